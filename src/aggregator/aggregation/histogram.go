@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+)
+
+// Histogram aggregates histograms reported with explicit bucket upper bounds.
+// Buckets are merged by upper bound so reporters are expected to share the
+// same bucket layout, buckets only reported by some reporters are added to
+// the aggregated histogram as they are received. Histogram APIs are not
+// thread-safe.
+type Histogram struct {
+	lastAt      time.Time
+	annotation  []byte
+	upperBounds []float64 // Bucket upper bounds in ascending order.
+	counts      []int64   // Number of values received in each bucket.
+	count       int64     // Number of values received.
+	sum         float64   // Sum of the values.
+}
+
+// NewHistogram creates a new histogram.
+func NewHistogram(_ Options) Histogram {
+	return Histogram{}
+}
+
+// Add adds a single value to the bucket it falls into, adding a +Inf bucket
+// if the value is larger than the upper bound of every bucket. NaN values are
+// ignored as they do not fall into any bucket.
+func (h *Histogram) Add(timestamp time.Time, value float64, annotation []byte) {
+	h.recordLastAt(timestamp)
+	if math.IsNaN(value) {
+		return
+	}
+	idx := sort.SearchFloat64s(h.upperBounds, value)
+	if idx == len(h.upperBounds) {
+		idx = h.bucketIndex(math.Inf(1))
+	}
+	h.counts[idx]++
+	h.count++
+	h.sum += value
+	h.annotation = maybeReplaceAnnotation(h.annotation, annotation)
+}
+
+// AddHistogram merges a histogram with the given bucket upper bounds, bucket
+// counts and sum of values into the aggregated histogram.
+func (h *Histogram) AddHistogram(
+	timestamp time.Time,
+	upperBounds []float64,
+	counts []int64,
+	sum float64,
+	annotation []byte,
+) {
+	h.recordLastAt(timestamp)
+	for i, upperBound := range upperBounds {
+		if i >= len(counts) {
+			break
+		}
+		h.counts[h.bucketIndex(upperBound)] += counts[i]
+		h.count += counts[i]
+	}
+	h.sum += sum
+	h.annotation = maybeReplaceAnnotation(h.annotation, annotation)
+}
+
+// bucketIndex returns the index of the bucket with the given upper bound,
+// inserting an empty bucket if there is none.
+func (h *Histogram) bucketIndex(upperBound float64) int {
+	idx := sort.SearchFloat64s(h.upperBounds, upperBound)
+	if idx < len(h.upperBounds) && h.upperBounds[idx] == upperBound {
+		return idx
+	}
+	h.upperBounds = append(h.upperBounds, 0)
+	copy(h.upperBounds[idx+1:], h.upperBounds[idx:])
+	h.upperBounds[idx] = upperBound
+	h.counts = append(h.counts, 0)
+	copy(h.counts[idx+1:], h.counts[idx:])
+	h.counts[idx] = 0
+	return idx
+}
+
+func (h *Histogram) recordLastAt(timestamp time.Time) {
+	if h.lastAt.IsZero() || timestamp.After(h.lastAt) {
+		h.lastAt = timestamp
+	}
+}
+
+// LastAt returns the time of the last value received.
+func (h *Histogram) LastAt() time.Time { return h.lastAt }
+
+// Count returns the number of values received.
+func (h *Histogram) Count() int64 { return h.count }
+
+// Sum returns the sum of the values received.
+func (h *Histogram) Sum() float64 { return h.sum }
+
+// Mean returns the mean of the values received.
+func (h *Histogram) Mean() float64 {
+	if h.count == 0 {
+		return 0.0
+	}
+	return h.sum / float64(h.count)
+}
+
+// Buckets returns the bucket upper bounds in ascending order and the number
+// of values received in each bucket.
+func (h *Histogram) Buckets() ([]float64, []int64) {
+	return h.upperBounds, h.counts
+}
+
+// Quantile estimates the value at the given quantile by linear interpolation
+// within the bucket the quantile falls into, assuming the lower bound of the
+// first bucket is zero if its upper bound is positive. Quantiles falling into
+// the +Inf bucket are estimated as the largest finite upper bound.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0.0
+	}
+	var (
+		rank       = q * float64(h.count)
+		cumulative int64
+	)
+	for i, upperBound := range h.upperBounds {
+		prevCumulative := cumulative
+		cumulative += h.counts[i]
+		if float64(cumulative) < rank || h.counts[i] == 0 {
+			continue
+		}
+		if math.IsInf(upperBound, 1) {
+			if i == 0 {
+				return 0.0
+			}
+			return h.upperBounds[i-1]
+		}
+		var lowerBound float64
+		if i > 0 {
+			lowerBound = h.upperBounds[i-1]
+		} else if upperBound <= 0 {
+			return upperBound
+		}
+		fraction := (rank - float64(prevCumulative)) / float64(h.counts[i])
+		return lowerBound + (upperBound-lowerBound)*fraction
+	}
+	return h.upperBounds[len(h.upperBounds)-1]
+}
+
+// ValueOf returns the value for the aggregation type.
+func (h *Histogram) ValueOf(aggType aggregation.Type) float64 {
+	switch aggType {
+	case aggregation.Count:
+		return float64(h.Count())
+	case aggregation.Sum:
+		return h.Sum()
+	case aggregation.Mean:
+		return h.Mean()
+	}
+	if q, ok := aggType.Quantile(); ok {
+		return h.Quantile(q)
+	}
+	return 0
+}
+
+// Annotation returns the annotation associated with the histogram.
+func (h *Histogram) Annotation() []byte {
+	return h.annotation
+}
+
+// Close closes the histogram.
+func (h *Histogram) Close() {
+	h.upperBounds = nil
+	h.counts = nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramAddHistogram(t *testing.T) {
+	h := NewHistogram(NewOptions(instrument.NewOptions()))
+	require.Equal(t, 0.0, h.ValueOf(aggregation.Count))
+	require.Equal(t, 0.0, h.ValueOf(aggregation.Mean))
+	require.Equal(t, 0.0, h.ValueOf(aggregation.P99))
+
+	now := time.Now()
+	h.AddHistogram(now, []float64{1, 2, 4, math.Inf(1)}, []int64{2, 2, 4, 0}, 20, nil)
+	h.AddHistogram(now.Add(time.Second), []float64{1, 2, 8}, []int64{0, 2, 0}, 3, []byte("foo"))
+
+	upperBounds, counts := h.Buckets()
+	require.Equal(t, []float64{1, 2, 4, 8, math.Inf(1)}, upperBounds)
+	require.Equal(t, []int64{2, 4, 4, 0, 0}, counts)
+	require.Equal(t, now.Add(time.Second), h.LastAt())
+	require.Equal(t, []byte("foo"), h.Annotation())
+	require.Equal(t, 10.0, h.ValueOf(aggregation.Count))
+	require.Equal(t, 23.0, h.ValueOf(aggregation.Sum))
+	require.Equal(t, 2.3, h.ValueOf(aggregation.Mean))
+	require.Equal(t, 1.75, h.ValueOf(aggregation.Median))
+	require.InDelta(t, 3.95, h.ValueOf(aggregation.P99), 1e-9)
+	require.Equal(t, 0.0, h.ValueOf(aggregation.Max))
+}
+
+func TestHistogramAdd(t *testing.T) {
+	h := NewHistogram(NewOptions(instrument.NewOptions()))
+	h.AddHistogram(time.Now(), []float64{1, 10}, []int64{0, 0}, 0, nil)
+	h.Add(time.Now(), 0.5, nil)
+	h.Add(time.Now(), 10, nil)
+	h.Add(time.Now(), 100, nil)
+	h.Add(time.Now(), math.NaN(), nil)
+
+	upperBounds, counts := h.Buckets()
+	require.Equal(t, []float64{1, 10, math.Inf(1)}, upperBounds)
+	require.Equal(t, []int64{1, 1, 1}, counts)
+	require.Equal(t, 3.0, h.ValueOf(aggregation.Count))
+	require.Equal(t, 110.5, h.ValueOf(aggregation.Sum))
+
+	// Quantiles falling into the +Inf bucket are capped to the largest
+	// finite upper bound.
+	require.Equal(t, 10.0, h.ValueOf(aggregation.P99))
+	require.Equal(t, 0.5, h.Quantile(1.0/6))
+
+	h.Close()
+	upperBounds, counts = h.Buckets()
+	require.Nil(t, upperBounds)
+	require.Nil(t, counts)
+}
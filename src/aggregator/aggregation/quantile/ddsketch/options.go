@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"errors"
+)
+
+const (
+	defaultRelativeAccuracy = 0.01
+	defaultMaxNumBins       = 2048
+)
+
+var (
+	errInvalidRelativeAccuracy = errors.New("relative accuracy must be between 0 and 1")
+	errInvalidMaxNumBins       = errors.New("max number of bins must be positive")
+)
+
+type options struct {
+	sketchPool       SketchPool
+	relativeAccuracy float64
+	maxNumBins       int
+}
+
+// NewOptions creates a new options.
+func NewOptions() Options {
+	o := &options{
+		relativeAccuracy: defaultRelativeAccuracy,
+		maxNumBins:       defaultMaxNumBins,
+	}
+	o.sketchPool = NewSketchPool(o)
+	return o
+}
+
+func (o *options) SetRelativeAccuracy(value float64) Options {
+	o.relativeAccuracy = value
+	return o
+}
+
+func (o *options) RelativeAccuracy() float64 {
+	return o.relativeAccuracy
+}
+
+func (o *options) SetMaxNumBins(value int) Options {
+	o.maxNumBins = value
+	return o
+}
+
+func (o *options) MaxNumBins() int {
+	return o.maxNumBins
+}
+
+func (o *options) SetSketchPool(value SketchPool) Options {
+	o.sketchPool = value
+	return o
+}
+
+func (o *options) SketchPool() SketchPool {
+	return o.sketchPool
+}
+
+func (o *options) Validate() error {
+	if o.relativeAccuracy <= 0 || o.relativeAccuracy >= 1 {
+		return errInvalidRelativeAccuracy
+	}
+	if o.maxNumBins <= 0 {
+		return errInvalidMaxNumBins
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"math"
+)
+
+const (
+	// minNormalFloat64 is the smallest positive normal float64 value.
+	minNormalFloat64 = 0x1p-1022
+)
+
+var (
+	nan = math.NaN()
+)
+
+// Sketch estimates quantiles of a stream of values within a relative
+// accuracy using a bounded number of logarithmically sized bins.
+type Sketch struct {
+	gamma             float64 // base of the logarithmic bin mapping
+	logGamma          float64 // natural logarithm of gamma
+	minIndexableValue float64 // values smaller in magnitude are counted as zero
+	positive          *store  // bins for positive values
+	negative          *store  // bins for the magnitude of negative values
+	zeroCount         int64   // number of values counted as zero
+	count             int64   // total number of values
+	min               float64 // exact minimum value
+	max               float64 // exact maximum value
+	sketchPool        SketchPool
+	closed            bool // whether the sketch is closed
+}
+
+// NewSketch creates a new sketch.
+func NewSketch(opts Options) *Sketch {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	var (
+		accuracy = opts.RelativeAccuracy()
+		gamma    = (1 + accuracy) / (1 - accuracy)
+	)
+	return &Sketch{
+		gamma:             gamma,
+		logGamma:          math.Log(gamma),
+		minIndexableValue: minNormalFloat64 * gamma,
+		positive:          newStore(opts.MaxNumBins()),
+		negative:          newStore(opts.MaxNumBins()),
+		min:               math.Inf(1),
+		max:               math.Inf(-1),
+		sketchPool:        opts.SketchPool(),
+	}
+}
+
+// AddBatch adds a batch of sample values.
+func (s *Sketch) AddBatch(values []float64) {
+	for _, v := range values {
+		s.Add(v)
+	}
+}
+
+// Add adds a sample value.
+func (s *Sketch) Add(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	switch {
+	case value > s.minIndexableValue:
+		s.positive.add(s.index(value))
+	case value < -s.minIndexableValue:
+		s.negative.add(s.index(-value))
+	default:
+		s.zeroCount++
+	}
+	s.count++
+	if value < s.min {
+		s.min = value
+	}
+	if value > s.max {
+		s.max = value
+	}
+}
+
+// Flush flushes the internal buffer. Values are binned as they are added
+// so this is a no-op, and exists to satisfy the same contract as streams.
+func (s *Sketch) Flush() {}
+
+// Min returns the minimum value.
+func (s *Sketch) Min() float64 {
+	return s.Quantile(0.0)
+}
+
+// Max returns the maximum value.
+func (s *Sketch) Max() float64 {
+	return s.Quantile(1.0)
+}
+
+// Quantile returns the quantile value.
+func (s *Sketch) Quantile(q float64) float64 {
+	if q < 0.0 || q > 1.0 {
+		return nan
+	}
+	if s.count == 0 {
+		return 0.0
+	}
+	if q == 0.0 {
+		return s.min
+	}
+	if q == 1.0 {
+		return s.max
+	}
+
+	var (
+		rank          = q * float64(s.count-1)
+		negativeCount = float64(s.negative.count)
+		value         float64
+	)
+	switch {
+	case rank < negativeCount:
+		value = -s.value(s.negative.keyAtRank(negativeCount - 1 - rank))
+	case rank < negativeCount+float64(s.zeroCount):
+		value = 0.0
+	default:
+		value = s.value(s.positive.keyAtRank(rank - negativeCount - float64(s.zeroCount)))
+	}
+
+	// Collapsed bins may map to values outside of the observed range.
+	return math.Max(s.min, math.Min(s.max, value))
+}
+
+// Count returns the number of values added to the sketch.
+func (s *Sketch) Count() int64 {
+	return s.count
+}
+
+// Reset resets the sketch so it can be reused.
+func (s *Sketch) Reset() {
+	s.positive.reset()
+	s.negative.reset()
+	s.zeroCount = 0
+	s.count = 0
+	s.min = math.Inf(1)
+	s.max = math.Inf(-1)
+	s.closed = false
+}
+
+// Close closes the sketch and returns it to the pool.
+func (s *Sketch) Close() {
+	if s.closed {
+		return
+	}
+	s.Reset()
+	s.closed = true
+	s.sketchPool.Put(s)
+}
+
+// index returns the index of the bin containing the given positive value.
+func (s *Sketch) index(value float64) int {
+	return int(math.Ceil(math.Log(value) / s.logGamma))
+}
+
+// value returns the representative value of the bin with the given index,
+// which is within the relative accuracy of every value in the bin.
+func (s *Sketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
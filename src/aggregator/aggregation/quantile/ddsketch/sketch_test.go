@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRelativeAccuracy = 0.01
+)
+
+var (
+	testQuantiles = []float64{0.5, 0.9, 0.99}
+)
+
+func testSketchOptions() Options {
+	return NewOptions().SetRelativeAccuracy(testRelativeAccuracy)
+}
+
+func TestEmptySketch(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	require.Equal(t, 0.0, s.Min())
+	require.Equal(t, 0.0, s.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, 0.0, s.Quantile(q))
+	}
+}
+
+func TestSketchInvalidQuantile(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	s.Add(1.0)
+	require.True(t, math.IsNaN(s.Quantile(-0.1)))
+	require.True(t, math.IsNaN(s.Quantile(1.1)))
+}
+
+func TestSketchWithOnePositiveSample(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	s.Add(100.0)
+	s.Flush()
+
+	require.Equal(t, 100.0, s.Min())
+	require.Equal(t, 100.0, s.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, 100.0, s.Quantile(q))
+	}
+}
+
+func TestSketchWithOneNegativeSample(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	s.Add(-100.0)
+	s.Flush()
+
+	require.Equal(t, -100.0, s.Min())
+	require.Equal(t, -100.0, s.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, -100.0, s.Quantile(q))
+	}
+}
+
+func TestSketchSkipsNaN(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	s.AddBatch([]float64{1.0, math.NaN(), 2.0})
+	require.Equal(t, int64(2), s.Count())
+	require.Equal(t, 1.0, s.Min())
+	require.Equal(t, 2.0, s.Max())
+}
+
+func TestSketchWithMixedSignSamples(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	for i := -500; i <= 500; i++ {
+		s.Add(float64(i))
+	}
+
+	require.Equal(t, -500.0, s.Min())
+	require.Equal(t, 500.0, s.Max())
+	require.Equal(t, 0.0, s.Quantile(0.5))
+	require.InDelta(t, -250.0, s.Quantile(0.25), 250*testRelativeAccuracy)
+	require.InDelta(t, 250.0, s.Quantile(0.75), 250*testRelativeAccuracy)
+}
+
+func TestSketchQuantilesWithinRelativeAccuracy(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	for i := 1; i <= 100000; i++ {
+		s.Add(float64(i))
+	}
+
+	for _, q := range testQuantiles {
+		expected := q * 99999
+		require.InEpsilon(t, expected, s.Quantile(q), testRelativeAccuracy)
+	}
+}
+
+func TestSketchCollapsesLowestBins(t *testing.T) {
+	opts := testSketchOptions().SetMaxNumBins(64)
+	s := NewSketch(opts)
+	for i := 1; i <= 100000; i++ {
+		s.Add(float64(i))
+	}
+
+	require.True(t, len(s.positive.bins) <= 64)
+	require.Equal(t, 1.0, s.Min())
+	require.Equal(t, 100000.0, s.Max())
+	require.InEpsilon(t, 0.99*99999, s.Quantile(0.99), testRelativeAccuracy)
+}
+
+func TestSketchReset(t *testing.T) {
+	s := NewSketch(testSketchOptions())
+	s.AddBatch([]float64{1.0, 2.0, 3.0})
+	s.Reset()
+
+	require.Equal(t, int64(0), s.Count())
+	require.Equal(t, 0.0, s.Quantile(0.5))
+	s.Add(5.0)
+	require.Equal(t, 5.0, s.Quantile(0.5))
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ddsketch
+
+// store is a dense store of bin counts indexed by bin index. Once the number
+// of bins exceeds the maximum, the lowest bins are collapsed into one so that
+// the estimation of higher quantiles stays accurate.
+type store struct {
+	bins       []int64
+	offset     int
+	count      int64
+	maxNumBins int
+}
+
+func newStore(maxNumBins int) *store {
+	return &store{maxNumBins: maxNumBins}
+}
+
+func (s *store) add(index int) {
+	if s.count == 0 {
+		s.bins = append(s.bins[:0], 0)
+		s.offset = index
+	}
+	if index < s.offset {
+		index = s.extendDown(index)
+	} else if index > s.maxIndex() {
+		s.extendUp(index)
+	}
+	s.bins[index-s.offset]++
+	s.count++
+}
+
+func (s *store) maxIndex() int {
+	return s.offset + len(s.bins) - 1
+}
+
+// keyAtRank returns the index of the bin containing the value at the given
+// rank, in ascending index order.
+func (s *store) keyAtRank(rank float64) int {
+	var n int64
+	for i, c := range s.bins {
+		n += c
+		if float64(n) > rank {
+			return s.offset + i
+		}
+	}
+	return s.maxIndex()
+}
+
+// extendDown makes room for an index lower than the current offset and
+// returns the index the value should be counted in.
+func (s *store) extendDown(index int) int {
+	newOffset := index
+	if maxIndex := s.maxIndex(); maxIndex-newOffset+1 > s.maxNumBins {
+		newOffset = maxIndex - s.maxNumBins + 1
+	}
+	if newOffset >= s.offset {
+		return s.offset
+	}
+	shift := s.offset - newOffset
+	s.bins = append(s.bins, make([]int64, shift)...)
+	copy(s.bins[shift:], s.bins[:len(s.bins)-shift])
+	for i := 0; i < shift; i++ {
+		s.bins[i] = 0
+	}
+	s.offset = newOffset
+	return newOffset
+}
+
+// extendUp makes room for an index higher than the current maximum index,
+// collapsing the lowest bins if needed.
+func (s *store) extendUp(index int) {
+	newOffset := s.offset
+	if index-newOffset+1 > s.maxNumBins {
+		newOffset = index - s.maxNumBins + 1
+	}
+	if shift := newOffset - s.offset; shift > 0 {
+		n := shift
+		if n > len(s.bins) {
+			n = len(s.bins)
+		}
+		var collapsed int64
+		for _, c := range s.bins[:n] {
+			collapsed += c
+		}
+		copy(s.bins, s.bins[n:])
+		s.bins = s.bins[:len(s.bins)-n]
+		if len(s.bins) == 0 {
+			s.bins = append(s.bins, collapsed)
+		} else {
+			s.bins[0] += collapsed
+		}
+		s.offset = newOffset
+	}
+	for s.maxIndex() < index {
+		s.bins = append(s.bins, 0)
+	}
+}
+
+func (s *store) reset() {
+	for i := range s.bins {
+		s.bins[i] = 0
+	}
+	s.bins = s.bins[:0]
+	s.offset = 0
+	s.count = 0
+}
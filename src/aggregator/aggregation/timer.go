@@ -24,13 +24,24 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/metrics/aggregation"
 )
 
+// quantileStream estimates quantiles over the timer values received.
+type quantileStream interface {
+	AddBatch(values []float64)
+	Flush()
+	Min() float64
+	Max() float64
+	Quantile(q float64) float64
+	Close()
+}
+
 // Timer aggregates timer values. Timer APIs are not thread-safe.
 type Timer struct {
 	lastAt                   time.Time
-	stream                   *cm.Stream // Stream of values received.
+	stream                   quantileStream // Stream of values received.
 	annotation               []byte
 	count                    int64   // Number of values received.
 	sum                      float64 // Sum of the values.
@@ -48,6 +59,17 @@ func NewTimer(quantiles []float64, streamOpts cm.Options, opts Options) Timer {
 	}
 }
 
+// NewSketchTimer creates a new timer that estimates arbitrary quantiles
+// within a relative accuracy using a sketch with bounded memory.
+func NewSketchTimer(sketchOpts ddsketch.Options, opts Options) Timer {
+	sketch := sketchOpts.SketchPool().Get()
+	sketch.Reset()
+	return Timer{
+		hasExpensiveAggregations: opts.HasExpensiveAggregations,
+		stream:                   sketch,
+	}
+}
+
 // Add adds a timer value.
 func (t *Timer) Add(timestamp time.Time, value float64, annotation []byte) {
 	t.AddBatch(timestamp, []float64{value}, annotation)
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
@@ -209,6 +210,42 @@ func TestTimerAggregationsNotExpensive(t *testing.T) {
 	timer.Close()
 }
 
+func TestSketchTimerAggregations(t *testing.T) {
+	opts := NewOptions(instrument.NewOptions())
+	opts.ResetSetData(testAggTypes)
+
+	sketchOpts := ddsketch.NewOptions().SetRelativeAccuracy(0.01)
+	timer := NewSketchTimer(sketchOpts, opts)
+
+	// Assert the state of an empty timer.
+	require.Equal(t, int64(0), timer.Count())
+	require.Equal(t, 0.0, timer.Min())
+	require.Equal(t, 0.0, timer.Max())
+	require.Equal(t, 0.0, timer.Quantile(0.5))
+
+	// Add values.
+	at := time.Now()
+	for i := 1; i <= 10000; i++ {
+		timer.Add(at, float64(i), nil)
+	}
+
+	// Min and max are exact while quantiles are within the relative accuracy.
+	require.Equal(t, int64(10000), timer.Count())
+	require.Equal(t, 1.0, timer.Min())
+	require.Equal(t, 10000.0, timer.Max())
+	require.InEpsilon(t, 5000.0, timer.Quantile(0.5), 0.01)
+	require.InEpsilon(t, 9500.0, timer.Quantile(0.95), 0.01)
+	require.InEpsilon(t, 9900.0, timer.Quantile(0.99), 0.01)
+	require.InEpsilon(t, 9990.0, timer.Quantile(0.999), 0.01)
+
+	// Closing the timer should return the underlying sketch to the pool.
+	timer.Close()
+	require.Equal(t, 0.0, timer.stream.Quantile(0.5))
+
+	// Closing the timer a second time should be a no op.
+	timer.Close()
+}
+
 func TestTimerReturnsLastNonEmptyAnnotation(t *testing.T) {
 	opts := NewOptions(instrument.NewOptions())
 	opts.ResetSetData(testAggTypes)
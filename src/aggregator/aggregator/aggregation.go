@@ -49,6 +49,10 @@ func (a *counterAggregation) AddUnion(t time.Time, mu unaggregated.MetricUnion)
 	a.Counter.Update(t, mu.CounterVal, mu.Annotation)
 }
 
+func (a *counterAggregation) Buckets() ([]float64, []int64) {
+	return nil, nil
+}
+
 // timerAggregation is a timer aggregation.
 type timerAggregation struct {
 	aggregation.Timer
@@ -70,6 +74,10 @@ func (a *timerAggregation) AddUnion(timestamp time.Time, mu unaggregated.MetricU
 	a.Timer.AddBatch(timestamp, mu.BatchTimerVal, mu.Annotation)
 }
 
+func (a *timerAggregation) Buckets() ([]float64, []int64) {
+	return nil, nil
+}
+
 // gaugeAggregation is a gauge aggregation.
 type gaugeAggregation struct {
 	aggregation.Gauge
@@ -91,3 +99,29 @@ func (a *gaugeAggregation) UpdateVal(t time.Time, value float64, prevValue float
 func (a *gaugeAggregation) AddUnion(t time.Time, mu unaggregated.MetricUnion) {
 	a.Gauge.Update(t, mu.GaugeVal, mu.Annotation)
 }
+
+func (a *gaugeAggregation) Buckets() ([]float64, []int64) {
+	return nil, nil
+}
+
+// histogramAggregation is a histogram aggregation.
+type histogramAggregation struct {
+	aggregation.Histogram
+}
+
+func newHistogramAggregation(h aggregation.Histogram) histogramAggregation {
+	return histogramAggregation{Histogram: h}
+}
+
+func (a *histogramAggregation) Add(t time.Time, value float64, annotation []byte) {
+	a.Histogram.Add(t, value, annotation)
+}
+
+func (a *histogramAggregation) UpdateVal(t time.Time, value float64, prevValue float64) error {
+	return errors.New("histograms do not support updating values")
+}
+
+func (a *histogramAggregation) AddUnion(t time.Time, mu unaggregated.MetricUnion) {
+	a.Histogram.AddHistogram(t, mu.HistogramBucketUpperBounds, mu.HistogramBucketCounts,
+		mu.HistogramSum, mu.Annotation)
+}
@@ -99,6 +99,34 @@ func TestGaugeAggregationAdd(t *testing.T) {
 	require.Equal(t, 799.2, g.Sum())
 }
 
+func TestHistogramAggregationAdd(t *testing.T) {
+	h := newHistogramAggregation(aggregation.NewHistogram(aggregation.NewOptions(instrument.NewOptions())))
+	for _, v := range testAggregationValues {
+		h.Add(time.Now(), v, nil)
+	}
+	require.Equal(t, int64(4), h.Count())
+	require.Equal(t, 799.2, h.Sum())
+	require.Error(t, h.UpdateVal(time.Now(), 1, 2))
+}
+
+func TestHistogramAggregationAddUnion(t *testing.T) {
+	h := newHistogramAggregation(aggregation.NewHistogram(aggregation.NewOptions(instrument.NewOptions())))
+	mu := unaggregated.MetricUnion{
+		Type:                       metric.HistogramType,
+		ID:                         testHistogramID,
+		HistogramBucketUpperBounds: []float64{1, 10},
+		HistogramBucketCounts:      []int64{3, 2},
+		HistogramSum:               12.5,
+	}
+	h.AddUnion(time.Now(), mu)
+	h.AddUnion(time.Now(), mu)
+	require.Equal(t, int64(10), h.Count())
+	require.Equal(t, 25.0, h.Sum())
+	upperBounds, counts := h.Buckets()
+	require.Equal(t, []float64{1, 10}, upperBounds)
+	require.Equal(t, []int64{6, 4}, counts)
+}
+
 func TestGaugeAggregationAddUnion(t *testing.T) {
 	g := newGaugeAggregation(aggregation.NewGauge(aggregation.NewOptions(instrument.NewOptions())))
 	for _, v := range testAggregationUnions {
@@ -33,6 +33,8 @@ import (
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3/src/aggregator/client"
+	"github.com/m3db/m3/src/aggregator/rate"
+	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/aggregator/sharding"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/shard"
@@ -45,6 +47,8 @@ import (
 	"github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/instrument"
+	xresource "github.com/m3db/m3/src/x/resource"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/uber-go/tally"
 	"go.uber.org/atomic"
@@ -55,6 +59,10 @@ const (
 	uninitializedCutoverNanos = math.MinInt64
 	uninitializedShardSetID   = 0
 	placementCheckInterval    = 10 * time.Second
+
+	// untimedToTimedErrorLogLimitPerSecond limits the rate of debug logs
+	// sampling untimed to timed conversion failures.
+	untimedToTimedErrorLogLimitPerSecond = 1
 )
 
 var (
@@ -62,6 +70,9 @@ var (
 	errAggregatorAlreadyOpenOrClosed = errors.New("aggregator is already open or closed")
 	errInvalidMetricType             = errors.New("invalid metric type")
 	errShardNotOwned                 = errors.New("aggregator shard is not owned")
+	errInvalidShardRedirectTTL       = errors.New("shard redirect ttl must be positive")
+	errShardRedirectToSelf           = errors.New("shard cannot be redirected to itself")
+	errInvalidShardIngestionPauseTTL = errors.New("shard ingestion pause ttl must be positive")
 )
 
 // Aggregator aggregates different types of metrics.
@@ -72,9 +83,25 @@ type Aggregator interface {
 	// AddUntimed adds an untimed metric with staged metadatas.
 	AddUntimed(metric unaggregated.MetricUnion, metas metadata.StagedMetadatas) error
 
+	// AddUntimedWithContext adds an untimed metric with staged metadatas,
+	// abandoning the write if the context is done before it completes.
+	AddUntimedWithContext(
+		ctx context.Context,
+		metric unaggregated.MetricUnion,
+		metas metadata.StagedMetadatas,
+	) error
+
 	// AddTimed adds a timed metric with metadata.
 	AddTimed(metric aggregated.Metric, metadata metadata.TimedMetadata) error
 
+	// AddTimedWithContext adds a timed metric with metadata, abandoning the
+	// write if the context is done before it completes.
+	AddTimedWithContext(
+		ctx context.Context,
+		metric aggregated.Metric,
+		metadata metadata.TimedMetadata,
+	) error
+
 	// AddTimedWithStagedMetadatas adds a timed metric with staged metadatas.
 	AddTimedWithStagedMetadatas(metric aggregated.Metric, metas metadata.StagedMetadatas) error
 
@@ -84,6 +111,10 @@ type Aggregator interface {
 	// AddPassthrough adds a passthrough metric with storage policy.
 	AddPassthrough(metric aggregated.Metric, storagePolicy policy.StoragePolicy) error
 
+	// AddPassthroughBatch adds a batch of passthrough metrics with storage policies,
+	// writing them to the passthrough writer in a single call.
+	AddPassthroughBatch(metrics []aggregated.PassthroughMetricWithMetadata) error
+
 	// Resign stops the aggregator from participating in leader election and resigns
 	// from ongoing campaign if any.
 	Resign() error
@@ -91,6 +122,57 @@ type Aggregator interface {
 	// Status returns the run-time status of the aggregator.
 	Status() RuntimeStatus
 
+	// CampaignDampingStatus returns the run-time status of the damping of
+	// leadership flaps.
+	CampaignDampingStatus() CampaignDampingStatus
+
+	// ClearCampaignDamping forgets the leadership flaps recorded so far and lets
+	// the aggregator campaign again if campaigning was suppressed.
+	ClearCampaignDamping()
+
+	// InProgressValues returns the values aggregated so far for the given metric ID
+	// that have not expired yet, or no values if the metric is not owned by the aggregator.
+	InProgressValues(metricID id.RawID) []InProgressDatapoint
+
+	// DebugMetric describes where the given metric ID is aggregated: the shard
+	// owning it, the status of the shard and the aggregations of the metric
+	// along with the last time they were flushed.
+	DebugMetric(metricID id.RawID) MetricDebugInfo
+
+	// SetShardRedirect redirects writes for a shard to another shard owned by the
+	// aggregator until the TTL expires, taking precedence over any redirect from
+	// the placement.
+	SetShardRedirect(shardID, redirectToShardID uint32, ttl time.Duration) error
+
+	// ClearShardRedirect clears a redirect set with SetShardRedirect.
+	ClearShardRedirect(shardID uint32) error
+
+	// ShardRedirects returns the redirects set with SetShardRedirect that have
+	// not expired yet.
+	ShardRedirects() []ShardRedirect
+
+	// PauseShardIngestion rejects writes for a shard with ErrShardIngestionPaused
+	// until the TTL expires, e.g. to shed load selectively during an incident
+	// while keeping the aggregator alive.
+	PauseShardIngestion(shardID uint32, ttl time.Duration) error
+
+	// ResumeShardIngestion resumes the ingestion of a shard paused with
+	// PauseShardIngestion.
+	ResumeShardIngestion(shardID uint32) error
+
+	// PausedShards returns the pauses set with PauseShardIngestion that have
+	// not expired yet.
+	PausedShards() []ShardIngestionPause
+
+	// ResolutionDowngrader returns the controller of the emergency resolution
+	// downgrade mode, or nil if the mode is not configured.
+	ResolutionDowngrader() *ResolutionDowngrader
+
+	// WindowDigests returns the digests of the aggregation windows of an owned
+	// shard with timestamps between start and end, computed when the
+	// verification mode is enabled.
+	WindowDigests(shardID uint32, start, end time.Time) ([]WindowDigest, error)
+
 	// Close closes the aggregator.
 	Close() error
 }
@@ -100,20 +182,37 @@ type Aggregator interface {
 type aggregator struct {
 	sync.RWMutex
 
-	opts                               Options
-	nowFn                              clock.NowFn
-	shardFn                            sharding.ShardFn
-	checkInterval                      time.Duration
-	placementManager                   PlacementManager
-	flushTimesManager                  FlushTimesManager
-	flushTimesChecker                  flushTimesChecker
-	electionManager                    ElectionManager
-	flushManager                       FlushManager
-	flushHandler                       handler.Handler
-	passthroughWriter                  writer.Writer
-	adminClient                        client.AdminClient
-	resignTimeout                      time.Duration
-	timedForResendEnabledRollupRegexps []*regexp.Regexp
+	opts                            Options
+	nowFn                           clock.NowFn
+	shardFn                         sharding.ShardFn
+	checkInterval                   time.Duration
+	placementManager                PlacementManager
+	flushTimesManager               FlushTimesManager
+	flushTimesChecker               flushTimesChecker
+	electionManager                 ElectionManager
+	flushManager                    FlushManager
+	flushHandler                    handler.Handler
+	passthroughWriter               writer.Writer
+	adminClient                     client.AdminClient
+	resignTimeout                   time.Duration
+	untimedToTimedErrLogRateLimiter *rate.Limiter
+	loadShedder                     *loadShedder
+	annotationSizeLimiter           *annotationSizeLimiter
+	flushOffsetTuner                *flushOffsetTuner
+
+	// runtimeCheckInterval, forwardingDelayScale, the timed for resend
+	// enabled rollup regexps and the shards ignoring cutoff/cutover times are
+	// updated from the runtime options so they can be tuned without restarting
+	// the process.
+	runtimeCheckInterval                      atomic.Duration
+	checkIntervalUpdatedCh                    chan struct{}
+	forwardingDelayScale                      atomic.Float64
+	timedForResendEnabledRollupRegexps        atomic.Value // []*regexp.Regexp
+	runtimeTimedForResendEnabledRollupRegexps []string
+	writesIgnoreCutoffCutover                 atomic.Value // writesIgnoreCutoffCutoverShards
+	runtimeWritesIgnoreCutoffCutover          bool
+	runtimeWritesIgnoreCutoffCutoverShards    []uint32
+	runtimeOptsCloser                         xresource.SimpleCloser
 
 	shardSetID         uint32
 	shardSetOpen       bool
@@ -137,26 +236,183 @@ func NewAggregator(opts Options) Aggregator {
 	timerOpts := iOpts.TimerOptions()
 	logger := iOpts.Logger()
 
-	return &aggregator{
-		opts:                               opts,
-		nowFn:                              opts.ClockOptions().NowFn(),
-		shardFn:                            opts.ShardFn(),
-		checkInterval:                      opts.EntryCheckInterval(),
-		placementManager:                   opts.PlacementManager(),
-		flushTimesManager:                  opts.FlushTimesManager(),
-		flushTimesChecker:                  newFlushTimesChecker(scope.SubScope("tick.shard-check")),
-		electionManager:                    opts.ElectionManager(),
-		flushManager:                       opts.FlushManager(),
-		flushHandler:                       opts.FlushHandler(),
-		passthroughWriter:                  opts.PassthroughWriter(),
-		adminClient:                        opts.AdminClient(),
-		resignTimeout:                      opts.ResignTimeout(),
-		timedForResendEnabledRollupRegexps: compileRegexps(logger, opts.TimedForResendEnabledRollupRegexps()),
-		doneCh:                             make(chan struct{}),
-		sleepFn:                            time.Sleep,
-		metrics:                            newAggregatorMetrics(scope, timerOpts, opts.MaxAllowedForwardingDelayFn()),
-		logger:                             logger,
+	agg := &aggregator{
+		opts:                            opts,
+		nowFn:                           opts.ClockOptions().NowFn(),
+		shardFn:                         opts.ShardFn(),
+		checkInterval:                   opts.EntryCheckInterval(),
+		placementManager:                opts.PlacementManager(),
+		flushTimesManager:               opts.FlushTimesManager(),
+		flushTimesChecker:               newFlushTimesChecker(scope.SubScope("tick.shard-check")),
+		electionManager:                 opts.ElectionManager(),
+		flushManager:                    opts.FlushManager(),
+		flushHandler:                    opts.FlushHandler(),
+		passthroughWriter:               opts.PassthroughWriter(),
+		adminClient:                     opts.AdminClient(),
+		resignTimeout:                   opts.ResignTimeout(),
+		untimedToTimedErrLogRateLimiter: rate.NewLimiter(untimedToTimedErrorLogLimitPerSecond),
+		doneCh:                          make(chan struct{}),
+		checkIntervalUpdatedCh:          make(chan struct{}, 1),
+		sleepFn:                         time.Sleep,
+		metrics:                         newAggregatorMetrics(scope, timerOpts, opts.MaxAllowedForwardingDelayFn()),
+		logger:                          logger,
+	}
+	agg.forwardingDelayScale.Store(1)
+	agg.timedForResendEnabledRollupRegexps.Store(
+		compileRegexps(logger, opts.TimedForResendEnabledRollupRegexps()))
+	agg.opts = opts.SetMaxAllowedForwardingDelayFn(
+		agg.scaledMaxAllowedForwardingDelayFn(opts.MaxAllowedForwardingDelayFn()))
+	if opts.PromotionReplayOptions() != nil {
+		agg.electionManager.SetBeforePromotionFn(agg.replayFromPeersBeforePromotion)
+	}
+	if sheddingOpts := opts.LoadSheddingOptions(); sheddingOpts != nil {
+		agg.loadShedder = newLoadShedder(*sheddingOpts, logger, scope.SubScope("load-shedding"))
+	}
+	if limitOpts := opts.AnnotationSizeLimitOptions(); limitOpts != nil {
+		agg.annotationSizeLimiter = newAnnotationSizeLimiter(*limitOpts, scope.SubScope("annotation-size-limit"))
+	}
+	if tuningOpts := opts.FlushOffsetTuningOptions(); tuningOpts != nil {
+		agg.flushOffsetTuner = newFlushOffsetTuner(*tuningOpts, opts.BufferForPastTimedMetricFn(),
+			agg.nowFn, scope.SubScope("flush-offset-tuning"))
+		agg.opts = agg.opts.SetBufferForPastTimedMetricFn(agg.flushOffsetTuner.BufferForPastTimedMetricFn())
+	}
+	return agg
+}
+
+// scaledMaxAllowedForwardingDelayFn scales the maximum allowed forwarding delay
+// by the factor set in the runtime options.
+func (agg *aggregator) scaledMaxAllowedForwardingDelayFn(
+	fn MaxAllowedForwardingDelayFn,
+) MaxAllowedForwardingDelayFn {
+	return func(resolution time.Duration, numForwardedTimes int) time.Duration {
+		delay := fn(resolution, numForwardedTimes)
+		scale := agg.forwardingDelayScale.Load()
+		if scale <= 0 || scale == 1 {
+			return delay
+		}
+		// NB: clamp to avoid overflowing delays that are effectively infinite.
+		scaled := float64(delay) * scale
+		if scaled >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(scaled)
+	}
+}
+
+// SetRuntimeOptions updates the aggregator options that can be changed at
+// runtime. Rate limits are applied by the metric maps and entries which watch
+// the runtime options themselves.
+func (agg *aggregator) SetRuntimeOptions(opts runtime.Options) {
+	if checkInterval := opts.EntryCheckInterval(); checkInterval != agg.runtimeCheckInterval.Load() {
+		agg.logger.Info("updating entry check interval",
+			zap.Duration("current", agg.currCheckInterval()),
+			zap.Duration("new", checkInterval))
+		agg.runtimeCheckInterval.Store(checkInterval)
+		select {
+		case agg.checkIntervalUpdatedCh <- struct{}{}:
+		default:
+		}
+	}
+	if scale := opts.MaxAllowedForwardingDelayScale(); scale != agg.forwardingDelayScale.Load() {
+		agg.logger.Info("updating max allowed forwarding delay scale",
+			zap.Float64("current", agg.forwardingDelayScale.Load()),
+			zap.Float64("new", scale))
+		agg.forwardingDelayScale.Store(scale)
+	}
+	// NB: runtime options are delivered sequentially so the previous runtime
+	// regexps can be compared without synchronization.
+	if regexps := opts.TimedForResendEnabledRollupRegexps(); !stringSlicesEqual(regexps,
+		agg.runtimeTimedForResendEnabledRollupRegexps) {
+		agg.logger.Info("updating timed for resend enabled rollup regexps",
+			zap.Strings("current", agg.runtimeTimedForResendEnabledRollupRegexps),
+			zap.Strings("new", regexps))
+		agg.runtimeTimedForResendEnabledRollupRegexps = regexps
+		if regexps == nil {
+			// Fall back to the statically configured regexps, an empty list
+			// disables converting rollups instead.
+			regexps = agg.opts.TimedForResendEnabledRollupRegexps()
+		}
+		agg.timedForResendEnabledRollupRegexps.Store(compileRegexps(agg.logger, regexps))
+	}
+	ignoreAll, ignoreShards := opts.WritesIgnoreCutoffCutover(), opts.WritesIgnoreCutoffCutoverShards()
+	if ignoreAll != agg.runtimeWritesIgnoreCutoffCutover ||
+		!uint32SlicesEqual(ignoreShards, agg.runtimeWritesIgnoreCutoffCutoverShards) {
+		agg.logger.Info("updating writes ignore cutoff cutover",
+			zap.Bool("currentAllShards", agg.runtimeWritesIgnoreCutoffCutover),
+			zap.Uint32s("currentShards", agg.runtimeWritesIgnoreCutoffCutoverShards),
+			zap.Bool("newAllShards", ignoreAll),
+			zap.Uint32s("newShards", ignoreShards))
+		agg.runtimeWritesIgnoreCutoffCutover = ignoreAll
+		agg.runtimeWritesIgnoreCutoffCutoverShards = ignoreShards
+		agg.writesIgnoreCutoffCutover.Store(
+			newWritesIgnoreCutoffCutoverShards(ignoreAll, ignoreShards))
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func uint32SlicesEqual(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writesIgnoreCutoffCutoverShards is the set of shards whose writes are accepted
+// regardless of the shard cutoff and cutover times as set in the runtime options.
+type writesIgnoreCutoffCutoverShards struct {
+	all    bool
+	shards map[uint32]struct{}
+}
+
+func newWritesIgnoreCutoffCutoverShards(all bool, shards []uint32) writesIgnoreCutoffCutoverShards {
+	s := writesIgnoreCutoffCutoverShards{
+		all:    all,
+		shards: make(map[uint32]struct{}, len(shards)),
+	}
+	for _, shard := range shards {
+		s.shards[shard] = struct{}{}
 	}
+	return s
+}
+
+// shardWritesIgnoreCutoffCutover returns whether writes to the shard are accepted
+// regardless of its cutoff and cutover times as set in the runtime options. This
+// allows recovering from incorrectly set cutoff times without a restart.
+func (agg *aggregator) shardWritesIgnoreCutoffCutover(shard uint32) bool {
+	ignore, ok := agg.writesIgnoreCutoffCutover.Load().(writesIgnoreCutoffCutoverShards)
+	if !ok {
+		return false
+	}
+	if ignore.all {
+		return true
+	}
+	_, ok = ignore.shards[shard]
+	return ok
+}
+
+// currCheckInterval returns the entry check interval set in the runtime
+// options, falling back to the statically configured interval.
+func (agg *aggregator) currCheckInterval() time.Duration {
+	if checkInterval := agg.runtimeCheckInterval.Load(); checkInterval > 0 {
+		return checkInterval
+	}
+	return agg.checkInterval
 }
 
 func compileRegexps(logger *zap.Logger, regexps []string) []*regexp.Regexp {
@@ -191,13 +447,46 @@ func (agg *aggregator) Open() error {
 	if err := agg.processPlacementWithLock(placement); err != nil {
 		return err
 	}
-	if agg.checkInterval > 0 {
-		agg.wg.Add(1)
-		go agg.tick()
+	if agg.opts.WAL() != nil {
+		agg.replayWALWithLock()
 	}
+	var heartbeat *heartbeatEmitter
+	if agg.opts.HeartbeatEnabled() {
+		scope := agg.opts.InstrumentOptions().MetricsScope().SubScope("heartbeat-writer")
+		heartbeatWriter, err := agg.flushHandler.NewWriter(scope)
+		if err != nil {
+			return err
+		}
+		heartbeat, err = newHeartbeatEmitter(agg.opts, heartbeatWriter)
+		if err != nil {
+			heartbeatWriter.Close() // nolint: errcheck
+			return err
+		}
+	}
+	agg.runtimeOptsCloser = agg.opts.RuntimeOptionsManager().RegisterWatcher(agg)
+	// NB: the ticking goroutine is started even without a configured entry
+	// check interval so that the interval can be set in the runtime options.
+	agg.wg.Add(1)
+	go agg.tick()
 
 	agg.wg.Add(1)
 	go agg.placementTick()
+	if heartbeat != nil {
+		agg.wg.Add(1)
+		go agg.heartbeatTick(heartbeat)
+	}
+	watchdogOpts := agg.opts.MemoryWatchdogOptions()
+	if downgrader := agg.opts.ResolutionDowngrader(); downgrader != nil && watchdogOpts.CheckInterval > 0 {
+		instrumentOpts := agg.opts.InstrumentOptions()
+		watchdog := newMemoryWatchdog(watchdogOpts, downgrader, readHeapInUseBytes,
+			instrumentOpts.Logger(), instrumentOpts.MetricsScope().SubScope("memory-watchdog"))
+		agg.wg.Add(1)
+		go agg.memoryWatchdogTick(watchdog, watchdogOpts.CheckInterval)
+	}
+	if verificationOpts := agg.opts.VerificationOptions(); verificationOpts != nil {
+		agg.wg.Add(1)
+		go agg.verificationTick(*verificationOpts)
+	}
 	agg.state = aggregatorOpen
 	return nil
 }
@@ -239,6 +528,45 @@ func (agg *aggregator) placementTick() {
 	}
 }
 
+func (agg *aggregator) heartbeatTick(heartbeat *heartbeatEmitter) {
+	defer agg.wg.Done()
+	defer heartbeat.Close() // nolint: errcheck
+
+	ticker := time.NewTicker(heartbeat.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-agg.doneCh:
+			return
+		}
+
+		agg.RLock()
+		shardIDs := make([]uint32, len(agg.shardIDs))
+		copy(shardIDs, agg.shardIDs)
+		agg.RUnlock()
+
+		heartbeat.Emit(shardIDs)
+	}
+}
+
+func (agg *aggregator) memoryWatchdogTick(watchdog *memoryWatchdog, interval time.Duration) {
+	defer agg.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-agg.doneCh:
+			return
+		}
+		watchdog.Check()
+	}
+}
+
 func (agg *aggregator) partitionResendEnabled(pipelines metadata.PipelineMetadatas) (
 	metadata.PipelineMetadatas,
 	metadata.PipelineMetadatas,
@@ -262,13 +590,43 @@ func (agg *aggregator) partitionResendEnabled(pipelines metadata.PipelineMetadat
 func (agg *aggregator) AddUntimed(
 	union unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
+) error {
+	return agg.addUntimed(context.Background(), union, metadatas)
+}
+
+func (agg *aggregator) AddUntimedWithContext(
+	ctx context.Context,
+	union unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
+) error {
+	return agg.addUntimed(ctx, union, metadatas)
+}
+
+func (agg *aggregator) addUntimed(
+	ctx context.Context,
+	union unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
 ) error {
 	sw := agg.metrics.addUntimed.SuccessLatencyStopwatch()
+	if err := ctx.Err(); err != nil {
+		agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
 	agg.updateStagedMetadatas(metadatas)
 	if err := agg.checkMetricType(union); err != nil {
 		agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
 		return err
 	}
+	if agg.loadShedder != nil && agg.loadShedder.ShedStaged(union.Type, metadatas) {
+		agg.metrics.addUntimed.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	annotation, err := agg.annotationSizeLimiter.Limit(union.Annotation)
+	if err != nil {
+		agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	union.Annotation = annotation
 	shard, err := agg.shardFor(union.ID)
 	if err != nil {
 		agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
@@ -280,7 +638,11 @@ func (agg *aggregator) AddUntimed(
 	if len(timedPipelines) > 0 {
 		metadatas[0].Pipelines = timedPipelines
 		if union.Type != metric.GaugeType {
-			return fmt.Errorf("cannot convert a %s to a timed metric", union.Type)
+			err = fmt.Errorf("cannot convert a %s to a timed metric", union.Type)
+			agg.reportUntimedToTimedError(untimedToTimedInvalidMetricType, union, timedPipelines, err)
+			agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
+			metadatas[0].Pipelines = prevPipelines
+			return err
 		}
 		timedMetric := aggregated.Metric{
 			Type:       metric.GaugeType,
@@ -291,11 +653,19 @@ func (agg *aggregator) AddUntimed(
 		}
 		agg.metrics.untimedToTimed.Inc(1)
 		if err = shard.AddTimedWithStagedMetadatas(timedMetric, metadatas); err != nil {
+			agg.reportUntimedToTimedError(untimedToTimedAddTimedError, union, timedPipelines, err)
 			agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
 			return err
 		}
 	}
 	if len(untimedPipelines) > 0 {
+		// The client may have gone away while the timed pipelines were being
+		// added, in which case skip the remaining write.
+		if err = ctx.Err(); err != nil {
+			agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
+			metadatas[0].Pipelines = prevPipelines
+			return err
+		}
 		metadatas[0].Pipelines = untimedPipelines
 		if err = shard.AddUntimed(union, metadatas); err != nil {
 			agg.metrics.addUntimed.ReportError(err, agg.electionManager.ElectionState())
@@ -313,9 +683,43 @@ func (agg *aggregator) AddUntimed(
 func (agg *aggregator) AddTimed(
 	metric aggregated.Metric,
 	metadata metadata.TimedMetadata,
+) error {
+	return agg.addTimed(context.Background(), metric, metadata)
+}
+
+func (agg *aggregator) AddTimedWithContext(
+	ctx context.Context,
+	metric aggregated.Metric,
+	metadata metadata.TimedMetadata,
+) error {
+	return agg.addTimed(ctx, metric, metadata)
+}
+
+func (agg *aggregator) addTimed(
+	ctx context.Context,
+	metric aggregated.Metric,
+	metadata metadata.TimedMetadata,
 ) error {
 	sw := agg.metrics.addTimed.SuccessLatencyStopwatch()
 	agg.metrics.timed.Inc(1)
+	if err := ctx.Err(); err != nil {
+		agg.metrics.addTimed.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	if agg.loadShedder != nil && agg.loadShedder.Shed(metric.Type, metadata.StoragePolicy) {
+		agg.metrics.addTimed.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	annotation, err := agg.annotationSizeLimiter.Limit(metric.Annotation)
+	if err != nil {
+		agg.metrics.addTimed.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	metric.Annotation = annotation
+	if agg.flushOffsetTuner != nil {
+		agg.flushOffsetTuner.Observe(metadata.StoragePolicy.Resolution().Window,
+			metric.TimeNanos, agg.nowFn().UnixNano())
+	}
 	shard, err := agg.shardFor(metric.ID)
 	if err != nil {
 		agg.metrics.addTimed.ReportError(err, agg.electionManager.ElectionState())
@@ -337,6 +741,19 @@ func (agg *aggregator) AddTimedWithStagedMetadatas(
 	sw := agg.metrics.addTimed.SuccessLatencyStopwatch()
 	agg.updateStagedMetadatas(metas)
 	agg.metrics.timed.Inc(1)
+	if agg.loadShedder != nil && agg.loadShedder.ShedStaged(metric.Type, metas) {
+		agg.metrics.addTimed.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	annotation, err := agg.annotationSizeLimiter.Limit(metric.Annotation)
+	if err != nil {
+		agg.metrics.addTimed.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	metric.Annotation = annotation
+	if agg.flushOffsetTuner != nil {
+		agg.flushOffsetTuner.ObserveStaged(metas, metric.TimeNanos, agg.nowFn().UnixNano())
+	}
 	shard, err := agg.shardFor(metric.ID)
 	if err != nil {
 		agg.metrics.addTimed.ReportError(err, agg.electionManager.ElectionState())
@@ -366,27 +783,67 @@ func (agg *aggregator) updateStagedMetadatas(sms metadata.StagedMetadatas) {
 }
 
 func (agg *aggregator) timedForResendEnabledOnPipeline(p metadata.PipelineMetadata) bool {
+	return agg.timedForResendEnabledRollupRegexp(p) != nil
+}
+
+// timedForResendEnabledRollupRegexp returns the regexp matching the rollup ID of
+// a resend enabled pipeline, or nil if the pipeline should not be converted to timed.
+func (agg *aggregator) timedForResendEnabledRollupRegexp(p metadata.PipelineMetadata) *regexp.Regexp {
 	if !p.ResendEnabled {
-		return false
+		return nil
 	}
-	if len(agg.timedForResendEnabledRollupRegexps) == 0 {
-		return false
+	regexps, _ := agg.timedForResendEnabledRollupRegexps.Load().([]*regexp.Regexp)
+	if len(regexps) == 0 {
+		return nil
 	}
 	for _, op := range p.Pipeline.Operations {
 		if op.Rollup.ID == nil {
 			continue
 		}
 
-		for _, r := range agg.timedForResendEnabledRollupRegexps {
+		for _, r := range regexps {
 			if r.Match(op.Rollup.ID) {
-				return true
+				return r
 			}
 		}
 
 		// Should only have one rollup op in a pipeline so can break after we found one.
 		break
 	}
-	return false
+	return nil
+}
+
+// reportUntimedToTimedError reports a failure to convert an untimed metric to a
+// timed metric for each of the pipelines by the rollup regexp that matched the
+// pipeline, so that the offending rules can be found.
+func (agg *aggregator) reportUntimedToTimedError(
+	reason untimedToTimedErrorReason,
+	union unaggregated.MetricUnion,
+	pipelines metadata.PipelineMetadatas,
+	err error,
+) {
+	patterns := make([]string, 0, len(pipelines))
+	for _, p := range pipelines {
+		var pattern string
+		if r := agg.timedForResendEnabledRollupRegexp(p); r != nil {
+			pattern = r.String()
+		}
+		agg.metrics.untimedToTimedErrors.ReportError(reason, union.Type, pattern)
+		patterns = append(patterns, pattern)
+	}
+
+	if !agg.untimedToTimedErrLogRateLimiter.IsAllowed(1, xtime.ToUnixNano(agg.nowFn())) {
+		return
+	}
+	if ce := agg.logger.Check(zap.DebugLevel, "failed to convert untimed metric to timed"); ce != nil {
+		ce.Write(
+			zap.String("reason", string(reason)),
+			zap.Stringer("metricType", union.Type),
+			zap.ByteString("id", union.ID),
+			zap.Strings("rollupPatterns", patterns),
+			zap.Error(err),
+		)
+	}
 }
 
 func (agg *aggregator) AddForwarded(
@@ -395,6 +852,16 @@ func (agg *aggregator) AddForwarded(
 ) error {
 	sw := agg.metrics.addForwarded.SuccessLatencyStopwatch()
 	agg.metrics.forwarded.Inc(1)
+	if agg.loadShedder != nil && agg.loadShedder.Shed(metric.Type, metadata.StoragePolicy) {
+		agg.metrics.addForwarded.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	annotation, err := agg.annotationSizeLimiter.Limit(metric.Annotation)
+	if err != nil {
+		agg.metrics.addForwarded.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	metric.Annotation = annotation
 	shard, err := agg.shardFor(metric.ID)
 	if err != nil {
 		agg.metrics.addForwarded.ReportError(err, agg.electionManager.ElectionState())
@@ -428,6 +895,16 @@ func (agg *aggregator) AddPassthrough(
 		return nil
 	}
 
+	if agg.loadShedder != nil && agg.loadShedder.Shed(metric.Type, storagePolicy) {
+		agg.metrics.addPassthrough.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	annotation, err := agg.annotationSizeLimiter.Limit(metric.Annotation)
+	if err != nil {
+		agg.metrics.addPassthrough.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+
 	mp := aggregated.ChunkedMetricWithStoragePolicy{
 		ChunkedMetric: aggregated.ChunkedMetric{
 			ChunkedID: id.ChunkedID{
@@ -435,7 +912,8 @@ func (agg *aggregator) AddPassthrough(
 			},
 			TimeNanos:  metric.TimeNanos,
 			Value:      metric.Value,
-			Annotation: metric.Annotation,
+			Annotation: annotation,
+			Exemplars:  metric.Exemplars,
 		},
 		StoragePolicy: storagePolicy,
 	}
@@ -456,6 +934,72 @@ func (agg *aggregator) AddPassthrough(
 	return nil
 }
 
+func (agg *aggregator) AddPassthroughBatch(
+	metrics []aggregated.PassthroughMetricWithMetadata,
+) error {
+	sw := agg.metrics.addPassthroughBatch.SuccessLatencyStopwatch()
+	agg.metrics.passthrough.Inc(int64(len(metrics)))
+
+	if agg.electionManager.ElectionState() == FollowerState {
+		agg.metrics.addPassthroughBatch.ReportFollowerNoop()
+		return nil
+	}
+
+	var (
+		mps      = make([]aggregated.ChunkedMetricWithStoragePolicy, 0, len(metrics))
+		shed     bool
+		rejected bool
+	)
+	for _, metric := range metrics {
+		if agg.loadShedder != nil && agg.loadShedder.Shed(metric.Type, metric.StoragePolicy) {
+			shed = true
+			continue
+		}
+		annotation, err := agg.annotationSizeLimiter.Limit(metric.Annotation)
+		if err != nil {
+			rejected = true
+			continue
+		}
+		mps = append(mps, aggregated.ChunkedMetricWithStoragePolicy{
+			ChunkedMetric: aggregated.ChunkedMetric{
+				ChunkedID: id.ChunkedID{
+					Data: []byte(metric.ID),
+				},
+				TimeNanos:  metric.TimeNanos,
+				Value:      metric.Value,
+				Annotation: annotation,
+				Exemplars:  metric.Exemplars,
+			},
+			StoragePolicy: metric.StoragePolicy,
+		})
+	}
+
+	agg.RLock()
+	defer agg.RUnlock()
+
+	if agg.state != aggregatorOpen {
+		return errAggregatorNotOpenOrClosed
+	}
+
+	if err := writer.WriteBatch(agg.passthroughWriter, mps); err != nil {
+		agg.metrics.addPassthroughBatch.ReportError(err, agg.electionManager.ElectionState())
+		return err
+	}
+	if shed {
+		// The metrics not shed were written, only report the batch as shed.
+		agg.metrics.addPassthroughBatch.ReportError(errWriteShed, agg.electionManager.ElectionState())
+		return errWriteShed
+	}
+	if rejected {
+		// The metrics with annotations within the max size were written.
+		agg.metrics.addPassthroughBatch.ReportError(errAnnotationTooLarge, agg.electionManager.ElectionState())
+		return errAnnotationTooLarge
+	}
+	agg.metrics.addPassthroughBatch.ReportSuccess()
+	sw.Stop()
+	return nil
+}
+
 func (agg *aggregator) Resign() error {
 	ctx, cancel := context.WithTimeout(context.Background(), agg.resignTimeout)
 	defer cancel()
@@ -463,9 +1007,250 @@ func (agg *aggregator) Resign() error {
 }
 
 func (agg *aggregator) Status() RuntimeStatus {
+	agg.RLock()
+	readyStatus := ReadyStatus{
+		PlacementProcessed: agg.currPlacement != nil,
+		ShardSetOpen:       agg.shardSetOpen,
+	}
+	var shardStatuses []ShardStatus
+	for _, shardID := range agg.shardIDs {
+		if shard, ok := agg.ownedShardWithLock(shardID); ok {
+			shardStatuses = append(shardStatuses, shard.Status())
+		}
+	}
+	agg.RUnlock()
+
+	// NB: the election manager is only opened once the shard set is open.
+	if readyStatus.ShardSetOpen {
+		readyStatus.ElectionStateDetermined = agg.electionManager.ElectionStateDetermined()
+	}
+	readyStatus.Ready = readyStatus.PlacementProcessed &&
+		readyStatus.ShardSetOpen &&
+		readyStatus.ElectionStateDetermined
+
 	return RuntimeStatus{
 		FlushStatus: agg.flushManager.Status(),
+		ReadyStatus: readyStatus,
+		Shards:      shardStatuses,
+	}
+}
+
+func (agg *aggregator) CampaignDampingStatus() CampaignDampingStatus {
+	return agg.electionManager.CampaignDampingStatus()
+}
+
+func (agg *aggregator) ClearCampaignDamping() {
+	agg.electionManager.ClearCampaignDamping()
+}
+
+func (agg *aggregator) InProgressValues(metricID id.RawID) []InProgressDatapoint {
+	shard, err := agg.shardFor(metricID)
+	if err != nil {
+		return nil
+	}
+	return shard.InProgressValues(metricID)
+}
+
+func (agg *aggregator) DebugMetric(metricID id.RawID) MetricDebugInfo {
+	var (
+		numShards = agg.currNumShards.Load()
+		info      MetricDebugInfo
+		shard     *aggregatorShard
+	)
+	if numShards > 0 {
+		info.ShardID = agg.shardFn(metricID, uint32(numShards))
+	}
+
+	agg.RLock()
+	if int(info.ShardID) < len(agg.shards) {
+		shard = agg.shards[info.ShardID]
+		if shard != nil {
+			if redirectToShardID := shard.RedirectTarget(); redirectToShardID != nil {
+				info.RedirectToShardID = redirectToShardID
+				shard = nil
+				if int(*redirectToShardID) < len(agg.shards) {
+					shard = agg.shards[*redirectToShardID]
+				}
+			}
+		}
+	}
+	agg.RUnlock()
+
+	if shard == nil {
+		return info
+	}
+	info.Owned = true
+	status := shard.Status()
+	info.ShardStatus = &status
+	info.Elements = shard.DebugElements(metricID)
+
+	// Flush times are only available once the shard set is open.
+	flushTimes, err := agg.flushTimesManager.Get()
+	if err != nil || flushTimes == nil {
+		return info
+	}
+	shardFlushTimes, ok := flushTimes.ByShard[shard.ID()]
+	if !ok || shardFlushTimes == nil {
+		return info
+	}
+	for i := range info.Elements {
+		elem := &info.Elements[i]
+		resolution := int64(elem.StoragePolicy.Resolution().Window)
+		var lastFlushedNanos int64
+		switch elem.Category {
+		case untimedMetric.String():
+			lastFlushedNanos = shardFlushTimes.StandardByResolution[resolution]
+		case timedMetric.String():
+			lastFlushedNanos = shardFlushTimes.TimedByResolution[resolution]
+		case forwardedMetric.String():
+			if forwarded := shardFlushTimes.ForwardedByResolution[resolution]; forwarded != nil {
+				lastFlushedNanos = forwarded.ByNumForwardedTimes[int32(elem.NumForwardedTimes)]
+			}
+		}
+		if lastFlushedNanos > 0 {
+			lastFlushed := time.Unix(0, lastFlushedNanos)
+			elem.LastFlushedTime = &lastFlushed
+		}
+	}
+	return info
+}
+
+func (agg *aggregator) SetShardRedirect(
+	shardID, redirectToShardID uint32,
+	ttl time.Duration,
+) error {
+	if ttl <= 0 {
+		return errInvalidShardRedirectTTL
+	}
+	if shardID == redirectToShardID {
+		return errShardRedirectToSelf
+	}
+
+	agg.Lock()
+	defer agg.Unlock()
+
+	if agg.state != aggregatorOpen {
+		return errAggregatorNotOpenOrClosed
+	}
+	shard, ok := agg.ownedShardWithLock(shardID)
+	if !ok {
+		return fmt.Errorf("%w: shard=%d", errShardNotOwned, shardID)
+	}
+	if _, ok := agg.ownedShardWithLock(redirectToShardID); !ok {
+		return fmt.Errorf("%w: shard=%d", errShardNotOwned, redirectToShardID)
+	}
+
+	expireAtNanos := agg.nowFn().Add(ttl).UnixNano()
+	shard.SetRuntimeRedirectToShardID(&redirectToShardID, expireAtNanos)
+	agg.metrics.shards.redirectSet.Inc(1)
+	agg.logger.Info("set shard redirect",
+		zap.Uint32("shard", shardID),
+		zap.Uint32("redirectToShard", redirectToShardID),
+		zap.Duration("ttl", ttl))
+	return nil
+}
+
+func (agg *aggregator) ClearShardRedirect(shardID uint32) error {
+	agg.Lock()
+	defer agg.Unlock()
+
+	if agg.state != aggregatorOpen {
+		return errAggregatorNotOpenOrClosed
 	}
+	shard, ok := agg.ownedShardWithLock(shardID)
+	if !ok {
+		return fmt.Errorf("%w: shard=%d", errShardNotOwned, shardID)
+	}
+
+	shard.SetRuntimeRedirectToShardID(nil, 0)
+	agg.metrics.shards.redirectCleared.Inc(1)
+	agg.logger.Info("cleared shard redirect", zap.Uint32("shard", shardID))
+	return nil
+}
+
+func (agg *aggregator) ShardRedirects() []ShardRedirect {
+	agg.RLock()
+	defer agg.RUnlock()
+
+	var redirects []ShardRedirect
+	for _, shard := range agg.shards {
+		if shard == nil {
+			continue
+		}
+		if redirect, ok := shard.RuntimeRedirect(); ok {
+			redirects = append(redirects, redirect)
+		}
+	}
+	return redirects
+}
+
+func (agg *aggregator) PauseShardIngestion(shardID uint32, ttl time.Duration) error {
+	if ttl <= 0 {
+		return errInvalidShardIngestionPauseTTL
+	}
+
+	agg.RLock()
+	defer agg.RUnlock()
+
+	if agg.state != aggregatorOpen {
+		return errAggregatorNotOpenOrClosed
+	}
+	shard, ok := agg.ownedShardWithLock(shardID)
+	if !ok {
+		return fmt.Errorf("%w: shard=%d", errShardNotOwned, shardID)
+	}
+
+	shard.SetIngestionPausedUntil(agg.nowFn().Add(ttl).UnixNano())
+	agg.metrics.shards.ingestionPaused.Inc(1)
+	agg.logger.Info("paused shard ingestion",
+		zap.Uint32("shard", shardID),
+		zap.Duration("ttl", ttl))
+	return nil
+}
+
+func (agg *aggregator) ResumeShardIngestion(shardID uint32) error {
+	agg.RLock()
+	defer agg.RUnlock()
+
+	if agg.state != aggregatorOpen {
+		return errAggregatorNotOpenOrClosed
+	}
+	shard, ok := agg.ownedShardWithLock(shardID)
+	if !ok {
+		return fmt.Errorf("%w: shard=%d", errShardNotOwned, shardID)
+	}
+
+	shard.SetIngestionPausedUntil(0)
+	agg.metrics.shards.ingestionResumed.Inc(1)
+	agg.logger.Info("resumed shard ingestion", zap.Uint32("shard", shardID))
+	return nil
+}
+
+func (agg *aggregator) PausedShards() []ShardIngestionPause {
+	agg.RLock()
+	defer agg.RUnlock()
+
+	var paused []ShardIngestionPause
+	for _, shard := range agg.shards {
+		if shard == nil {
+			continue
+		}
+		if pause, ok := shard.IngestionPause(); ok {
+			paused = append(paused, pause)
+		}
+	}
+	return paused
+}
+
+func (agg *aggregator) ResolutionDowngrader() *ResolutionDowngrader {
+	return agg.opts.ResolutionDowngrader()
+}
+
+func (agg *aggregator) ownedShardWithLock(shardID uint32) (*aggregatorShard, bool) {
+	if int(shardID) >= len(agg.shards) || agg.shards[shardID] == nil {
+		return nil, false
+	}
+	return agg.shards[shardID], true
 }
 
 func (agg *aggregator) Close() error {
@@ -478,6 +1263,9 @@ func (agg *aggregator) Close() error {
 	agg.state = aggregatorClosed
 
 	close(agg.doneCh)
+	if agg.runtimeOptsCloser != nil {
+		agg.runtimeOptsCloser.Close()
+	}
 
 	// Waiting for the ticking goroutines to return.
 	// Doing this outside of agg.Lock to avoid potential deadlocks.
@@ -493,12 +1281,50 @@ func (agg *aggregator) Close() error {
 	}
 	agg.flushHandler.Close()
 	agg.passthroughWriter.Close()
+	if w := agg.opts.WAL(); w != nil {
+		if err := w.Close(); err != nil {
+			agg.logger.Error("error closing write-ahead log", zap.Error(err))
+		}
+	}
 	if agg.adminClient != nil {
 		agg.adminClient.Close()
 	}
 	return nil
 }
 
+// replayWALWithLock replays the metrics journaled to the write-ahead log into
+// the owned shards so aggregations that were in flight before a restart are
+// not lost. Replay errors are logged rather than failing to open, and the
+// replay is skipped if the flush times are unavailable since every window
+// would be replayed into, aggregating the flushed windows twice.
+func (agg *aggregator) replayWALWithLock() {
+	var (
+		start            = agg.nowFn()
+		replayed, failed int
+	)
+	// NB: the flush times are loaded when the shard set is opened, the windows
+	// they cover have been flushed and must not be replayed into.
+	flushTimes, err := agg.flushTimesManager.Get()
+	if err != nil {
+		agg.metrics.walReplaySkipped.Inc(1)
+		agg.logger.Error("skipping write-ahead log replay without flush times", zap.Error(err))
+		return
+	}
+	for _, shardID := range agg.shardIDs {
+		shardReplayed, shardFailed, err := agg.shards[shardID].Replay(flushTimes.GetByShard()[shardID])
+		replayed += shardReplayed
+		failed += shardFailed
+		if err != nil {
+			agg.logger.Error("error replaying write-ahead log",
+				zap.Uint32("shard", shardID), zap.Error(err))
+		}
+	}
+	agg.logger.Info("replayed write-ahead log",
+		zap.Int("replayed", replayed),
+		zap.Int("failed", failed),
+		zap.Duration("took", agg.nowFn().Sub(start)))
+}
+
 func (agg *aggregator) shardFor(id id.RawID) (*aggregatorShard, error) {
 	var (
 		numShards = agg.currNumShards.Load()
@@ -514,11 +1340,12 @@ func (agg *aggregator) shardFor(id id.RawID) (*aggregatorShard, error) {
 	agg.RLock()
 	if int(shardID) < len(agg.shards) {
 		shard = agg.shards[shardID]
-		if shard != nil && shard.redirectToShardID != nil {
-			redirectToShardID := *shard.redirectToShardID
-			shard = nil
-			if int(redirectToShardID) < len(agg.shards) {
-				shard = agg.shards[redirectToShardID]
+		if shard != nil {
+			if redirectToShardID := shard.RedirectTarget(); redirectToShardID != nil {
+				shard = nil
+				if int(*redirectToShardID) < len(agg.shards) {
+					shard = agg.shards[*redirectToShardID]
+				}
 			}
 		}
 	}
@@ -707,6 +1534,7 @@ func (agg *aggregator) updateShardsWithLock(
 			incoming[shardID] = agg.shards[shardID]
 		} else {
 			incoming[shardID] = newAggregatorShard(shardID, agg.opts)
+			incoming[shardID].SetWritesIgnoreCutoffCutoverFn(agg.shardWritesIgnoreCutoffCutover)
 			agg.metrics.shards.add.Inc(1)
 		}
 
@@ -740,6 +1568,9 @@ func (agg *aggregator) checkMetricType(mu unaggregated.MetricUnion) error {
 	case metric.GaugeType:
 		agg.metrics.gauges.Inc(1)
 		return nil
+	case metric.HistogramType:
+		agg.metrics.histograms.Inc(1)
+		return mu.Histogram().Validate()
 	default:
 		return errInvalidMetricType
 	}
@@ -808,6 +1639,17 @@ func (agg *aggregator) tick() {
 	defer agg.wg.Done()
 
 	for {
+		if agg.currCheckInterval() <= 0 {
+			// Entries are not checked until an entry check interval is set in
+			// the runtime options.
+			select {
+			case <-agg.doneCh:
+				return
+			case <-agg.checkIntervalUpdatedCh:
+			}
+			continue
+		}
+
 		select {
 		case <-agg.doneCh:
 			return
@@ -824,13 +1666,14 @@ func (agg *aggregator) tickInternal() {
 	numShards := len(ownedShards)
 	agg.metrics.shards.owned.Update(float64(numShards))
 	agg.metrics.shards.pendingClose.Update(float64(agg.shardsPendingClose.Load()))
+	checkInterval := agg.currCheckInterval()
 	if numShards == 0 {
-		agg.sleepFn(agg.checkInterval)
+		agg.sleepFn(checkInterval)
 		return
 	}
 	var (
 		start                = agg.nowFn()
-		perShardTickDuration = agg.checkInterval / time.Duration(numShards)
+		perShardTickDuration = checkInterval / time.Duration(numShards)
 		tickResult           tickResult
 	)
 	for _, shard := range ownedShards {
@@ -839,8 +1682,17 @@ func (agg *aggregator) tickInternal() {
 	}
 	tickDuration := agg.nowFn().Sub(start)
 	agg.metrics.tick.Report(tickResult, tickDuration)
-	if tickDuration < agg.checkInterval {
-		agg.sleepFn(agg.checkInterval - tickDuration)
+	if agg.loadShedder != nil {
+		// NB: the ticks of the shards sleep to spread over the check interval,
+		// which would make every tick look as long as the interval.
+		agg.loadShedder.UpdateTick(tickDuration-tickResult.paced, checkInterval)
+	}
+	if agg.flushOffsetTuner != nil {
+		agg.flushOffsetTuner.Tune()
+	}
+	agg.opts.RollupCardinalityLimiter().Report()
+	if tickDuration < checkInterval {
+		agg.sleepFn(checkInterval - tickDuration)
 	}
 }
 
@@ -870,9 +1722,14 @@ func (m *aggregatorAddMetricSuccessMetrics) ReportSuccess() {
 type aggregatorAddMetricErrorMetrics struct {
 	shardNotOwned              tally.Counter
 	shardNotWriteable          tally.Counter
+	shardIngestionPaused       tally.Counter
 	valueRateLimitExceeded     tally.Counter
 	newMetricRateLimitExceeded tally.Counter
+	rollupCardinalityExceeded  tally.Counter
 	arrivedTooLate             tally.Counter
+	tooManyForwarded           tally.Counter
+	loadShed                   tally.Counter
+	canceled                   tally.Counter
 	uncategorizedErrors        tally.Counter
 }
 
@@ -886,15 +1743,30 @@ func newAggregatorAddMetricErrorMetrics(
 		shardNotWriteable: scope.Tagged(map[string]string{
 			"reason": "shard-not-writeable",
 		}).Counter("errors"),
+		shardIngestionPaused: scope.Tagged(map[string]string{
+			"reason": "shard-ingestion-paused",
+		}).Counter("errors"),
 		valueRateLimitExceeded: scope.Tagged(map[string]string{
 			"reason": "value-rate-limit-exceeded",
 		}).Counter("errors"),
 		newMetricRateLimitExceeded: scope.Tagged(map[string]string{
 			"reason": "new-metric-rate-limit-exceeded",
 		}).Counter("errors"),
+		rollupCardinalityExceeded: scope.Tagged(map[string]string{
+			"reason": "rollup-cardinality-limit-exceeded",
+		}).Counter("errors"),
 		arrivedTooLate: scope.Tagged(map[string]string{
 			"reason": "arrived-too-late",
 		}).Counter("errors"),
+		tooManyForwarded: scope.Tagged(map[string]string{
+			"reason": "too-many-forwarded",
+		}).Counter("errors"),
+		loadShed: scope.Tagged(map[string]string{
+			"reason": "load-shed",
+		}).Counter("errors"),
+		canceled: scope.Tagged(map[string]string{
+			"reason": "canceled",
+		}).Counter("errors"),
 		uncategorizedErrors: scope.Tagged(map[string]string{
 			"reason": "not-categorized",
 		}).Counter("errors"),
@@ -910,12 +1782,22 @@ func (m *aggregatorAddMetricErrorMetrics) ReportError(err error) {
 		m.shardNotOwned.Inc(1)
 	case xerrors.Is(err, errAggregatorShardNotWriteable):
 		m.shardNotWriteable.Inc(1)
+	case xerrors.Is(err, ErrShardIngestionPaused):
+		m.shardIngestionPaused.Inc(1)
 	case xerrors.Is(err, errWriteNewMetricRateLimitExceeded):
 		m.newMetricRateLimitExceeded.Inc(1)
+	case xerrors.Is(err, errRollupCardinalityLimitExceeded):
+		m.rollupCardinalityExceeded.Inc(1)
 	case xerrors.Is(err, errWriteValueRateLimitExceeded):
 		m.valueRateLimitExceeded.Inc(1)
 	case xerrors.Is(err, errArrivedTooLate):
 		m.arrivedTooLate.Inc(1)
+	case xerrors.Is(err, errTooManyForwarded):
+		m.tooManyForwarded.Inc(1)
+	case xerrors.Is(err, errWriteShed):
+		m.loadShed.Inc(1)
+	case xerrors.Is(err, context.Canceled), xerrors.Is(err, context.DeadlineExceeded):
+		m.canceled.Inc(1)
 	default:
 		m.uncategorizedErrors.Inc(1)
 	}
@@ -1193,18 +2075,26 @@ func (m aggregatorTickMetrics) Report(tickResult tickResult, duration time.Durat
 }
 
 type aggregatorShardsMetrics struct {
-	add          tally.Counter
-	close        tally.Counter
-	owned        tally.Gauge
-	pendingClose tally.Gauge
+	add              tally.Counter
+	close            tally.Counter
+	owned            tally.Gauge
+	pendingClose     tally.Gauge
+	redirectSet      tally.Counter
+	redirectCleared  tally.Counter
+	ingestionPaused  tally.Counter
+	ingestionResumed tally.Counter
 }
 
 func newAggregatorShardsMetrics(scope tally.Scope) aggregatorShardsMetrics {
 	return aggregatorShardsMetrics{
-		add:          scope.Counter("add"),
-		close:        scope.Counter("close"),
-		owned:        scope.Gauge("owned"),
-		pendingClose: scope.Gauge("pending-close"),
+		add:              scope.Counter("add"),
+		close:            scope.Counter("close"),
+		owned:            scope.Gauge("owned"),
+		pendingClose:     scope.Gauge("pending-close"),
+		redirectSet:      scope.Counter("redirect-set"),
+		redirectCleared:  scope.Counter("redirect-cleared"),
+		ingestionPaused:  scope.Counter("ingestion-paused"),
+		ingestionResumed: scope.Counter("ingestion-resumed"),
 	}
 }
 
@@ -1242,23 +2132,56 @@ func newAggregatorShardSetIDMetrics(scope tally.Scope) aggregatorShardSetIDMetri
 	}
 }
 
+type untimedToTimedErrorReason string
+
+const (
+	untimedToTimedInvalidMetricType untimedToTimedErrorReason = "invalid-metric-type"
+	untimedToTimedAddTimedError     untimedToTimedErrorReason = "add-timed"
+)
+
+type untimedToTimedErrorMetrics struct {
+	scope tally.Scope
+}
+
+func newUntimedToTimedErrorMetrics(scope tally.Scope) untimedToTimedErrorMetrics {
+	return untimedToTimedErrorMetrics{scope: scope}
+}
+
+// ReportError reports a conversion error tagged by the reason, metric type and
+// matched rollup regexp, which are all bounded by the aggregator configuration.
+func (m untimedToTimedErrorMetrics) ReportError(
+	reason untimedToTimedErrorReason,
+	metricType metric.Type,
+	rollupPattern string,
+) {
+	m.scope.Tagged(map[string]string{
+		"reason":         string(reason),
+		"metric_type":    metricType.String(),
+		"rollup_pattern": rollupPattern,
+	}).Counter("errors").Inc(1)
+}
+
 type aggregatorMetrics struct {
-	counters       tally.Counter
-	timers         tally.Counter
-	timerBatches   tally.Counter
-	gauges         tally.Counter
-	forwarded      tally.Counter
-	timed          tally.Counter
-	passthrough    tally.Counter
-	untimedToTimed tally.Counter
-	addUntimed     aggregatorAddUntimedMetrics
-	addTimed       aggregatorAddTimedMetrics
-	addForwarded   aggregatorAddForwardedMetrics
-	addPassthrough aggregatorAddPassthroughMetrics
-	placement      aggregatorPlacementMetrics
-	shards         aggregatorShardsMetrics
-	shardSetID     aggregatorShardSetIDMetrics
-	tick           aggregatorTickMetrics
+	counters             tally.Counter
+	timers               tally.Counter
+	timerBatches         tally.Counter
+	gauges               tally.Counter
+	histograms           tally.Counter
+	forwarded            tally.Counter
+	timed                tally.Counter
+	passthrough          tally.Counter
+	untimedToTimed       tally.Counter
+	addUntimed           aggregatorAddUntimedMetrics
+	untimedToTimedErrors untimedToTimedErrorMetrics
+	addTimed             aggregatorAddTimedMetrics
+	addForwarded         aggregatorAddForwardedMetrics
+	addPassthrough       aggregatorAddPassthroughMetrics
+	addPassthroughBatch  aggregatorAddPassthroughMetrics
+	placement            aggregatorPlacementMetrics
+	shards               aggregatorShardsMetrics
+	shardSetID           aggregatorShardSetIDMetrics
+	tick                 aggregatorTickMetrics
+	walReplaySkipped     tally.Counter
 }
 
 func newAggregatorMetrics(
@@ -1270,27 +2193,32 @@ func newAggregatorMetrics(
 	addTimedScope := scope.SubScope("addTimed")
 	addForwardedScope := scope.SubScope("addForwarded")
 	addPassthroughScope := scope.SubScope("addPassthrough")
+	addPassthroughBatchScope := scope.SubScope("addPassthroughBatch")
 	placementScope := scope.SubScope("placement")
 	shardsScope := scope.SubScope("shards")
 	shardSetIDScope := scope.SubScope("shard-set-id")
 	tickScope := scope.SubScope("tick")
 	return aggregatorMetrics{
-		counters:       scope.Counter("counters"),
-		timers:         scope.Counter("timers"),
-		timerBatches:   scope.Counter("timer-batches"),
-		gauges:         scope.Counter("gauges"),
-		forwarded:      scope.Counter("forwarded"),
-		timed:          scope.Counter("timed"),
-		passthrough:    scope.Counter("passthrough"),
-		untimedToTimed: scope.Counter("untimed-to-timed"),
-		addUntimed:     newAggregatorAddUntimedMetrics(addUntimedScope, opts),
-		addTimed:       newAggregatorAddTimedMetrics(addTimedScope, opts),
-		addForwarded:   newAggregatorAddForwardedMetrics(addForwardedScope, opts, maxAllowedForwardingDelayFn),
-		addPassthrough: newAggregatorAddPassthroughMetrics(addPassthroughScope, opts),
-		placement:      newAggregatorPlacementMetrics(placementScope),
-		shards:         newAggregatorShardsMetrics(shardsScope),
-		shardSetID:     newAggregatorShardSetIDMetrics(shardSetIDScope),
-		tick:           newAggregatorTickMetrics(tickScope),
+		counters:             scope.Counter("counters"),
+		timers:               scope.Counter("timers"),
+		timerBatches:         scope.Counter("timer-batches"),
+		gauges:               scope.Counter("gauges"),
+		histograms:           scope.Counter("histograms"),
+		forwarded:            scope.Counter("forwarded"),
+		timed:                scope.Counter("timed"),
+		passthrough:          scope.Counter("passthrough"),
+		untimedToTimed:       scope.Counter("untimed-to-timed"),
+		untimedToTimedErrors: newUntimedToTimedErrorMetrics(scope.SubScope("untimed-to-timed")),
+		addUntimed:           newAggregatorAddUntimedMetrics(addUntimedScope, opts),
+		addTimed:             newAggregatorAddTimedMetrics(addTimedScope, opts),
+		addForwarded:         newAggregatorAddForwardedMetrics(addForwardedScope, opts, maxAllowedForwardingDelayFn),
+		addPassthrough:       newAggregatorAddPassthroughMetrics(addPassthroughScope, opts),
+		addPassthroughBatch:  newAggregatorAddPassthroughMetrics(addPassthroughBatchScope, opts),
+		placement:            newAggregatorPlacementMetrics(placementScope),
+		shards:               newAggregatorShardsMetrics(shardsScope),
+		shardSetID:           newAggregatorShardSetIDMetrics(shardSetIDScope),
+		tick:                 newAggregatorTickMetrics(tickScope),
+		walReplaySkipped:     scope.Counter("wal-replay-skipped"),
 	}
 }
 
@@ -1302,7 +2230,87 @@ func withRole(role string, scope tally.Scope) tally.Scope {
 
 // RuntimeStatus contains run-time status of the aggregator.
 type RuntimeStatus struct {
-	FlushStatus FlushStatus `json:"flushStatus"`
+	FlushStatus FlushStatus   `json:"flushStatus"`
+	ReadyStatus ReadyStatus   `json:"readyStatus"`
+	Shards      []ShardStatus `json:"shards,omitempty"`
+}
+
+// ShardStatus contains run-time status of a shard owned by the aggregator.
+// The entry counts and tick duration are those of the last tick of the shard,
+// a shard whose last tick is long ago or took long is lagging.
+type ShardStatus struct {
+	ShardID               uint32        `json:"shardID"`
+	Writeable             bool          `json:"writeable"`
+	IngestionPaused       bool          `json:"ingestionPaused"`
+	CutoverTime           time.Time     `json:"cutoverTime"`
+	CutoffTime            time.Time     `json:"cutoffTime"`
+	EarliestWriteableTime time.Time     `json:"earliestWriteableTime"`
+	LatestWriteableTime   time.Time     `json:"latestWriteableTime"`
+	Entries               int           `json:"entries"`
+	ExpiredEntries        int           `json:"expiredEntries"`
+	LastTickAt            time.Time     `json:"lastTickAt"`
+	LastTickDuration      time.Duration `json:"lastTickDuration"`
+}
+
+// ReadyStatus contains the readiness of the aggregator to ingest metrics. Until
+// the aggregator is ready, metrics written to it may be dropped as not owned.
+type ReadyStatus struct {
+	Ready                   bool `json:"ready"`
+	PlacementProcessed      bool `json:"placementProcessed"`
+	ShardSetOpen            bool `json:"shardSetOpen"`
+	ElectionStateDetermined bool `json:"electionStateDetermined"`
+}
+
+// ShardRedirect is a redirect of writes from a shard to another shard set
+// at runtime rather than by the placement.
+type ShardRedirect struct {
+	ShardID           uint32    `json:"shardID"`
+	RedirectToShardID uint32    `json:"redirectToShardID"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+// ShardIngestionPause is a pause of the ingestion of a shard, writes to the
+// shard are rejected with ErrShardIngestionPaused until the pause expires.
+type ShardIngestionPause struct {
+	ShardID     uint32    `json:"shardID"`
+	PausedUntil time.Time `json:"pausedUntil"`
+}
+
+// InProgressDatapoint is a value aggregated so far for an aggregation window,
+// timestamped with the time it will be flushed at.
+type InProgressDatapoint struct {
+	TimeNanos     int64                `json:"timeNanos"`
+	Value         float64              `json:"value"`
+	StoragePolicy policy.StoragePolicy `json:"storagePolicy"`
+}
+
+// MetricDebugInfo describes where a metric ID is aggregated.
+type MetricDebugInfo struct {
+	// ShardID is the shard the metric ID belongs to.
+	ShardID uint32 `json:"shardID"`
+	// RedirectToShardID is the shard writes of the shard are redirected to.
+	RedirectToShardID *uint32 `json:"redirectToShardID,omitempty"`
+	// Owned is whether the shard the metric is written to is owned by the
+	// aggregator.
+	Owned bool `json:"owned"`
+	// ShardStatus is the status of the shard the metric is written to.
+	ShardStatus *ShardStatus         `json:"shardStatus,omitempty"`
+	Elements    []MetricDebugElement `json:"elements"`
+}
+
+// MetricDebugElement describes an aggregation of a metric ID.
+type MetricDebugElement struct {
+	Category            string               `json:"category"`
+	MetricType          string               `json:"metricType"`
+	StoragePolicy       policy.StoragePolicy `json:"storagePolicy"`
+	Resolution          string               `json:"resolution"`
+	AggregationID       string               `json:"aggregationID"`
+	Pipeline            string               `json:"pipeline"`
+	NumForwardedTimes   int                  `json:"numForwardedTimes"`
+	NumInProgressValues int                  `json:"numInProgressValues"`
+	// LastFlushedTime is the time the aggregations of the shard with the same
+	// category and resolution were last flushed up to, if any.
+	LastFlushedTime *time.Time `json:"lastFlushedTime,omitempty"`
 }
 
 type aggregatorState int
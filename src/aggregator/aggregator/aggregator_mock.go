@@ -27,12 +27,14 @@ package aggregator
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/watch"
@@ -91,6 +93,20 @@ func (mr *MockAggregatorMockRecorder) AddPassthrough(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPassthrough", reflect.TypeOf((*MockAggregator)(nil).AddPassthrough), arg0, arg1)
 }
 
+// AddPassthroughBatch mocks base method.
+func (m *MockAggregator) AddPassthroughBatch(arg0 []aggregated.PassthroughMetricWithMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPassthroughBatch", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPassthroughBatch indicates an expected call of AddPassthroughBatch.
+func (mr *MockAggregatorMockRecorder) AddPassthroughBatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPassthroughBatch", reflect.TypeOf((*MockAggregator)(nil).AddPassthroughBatch), arg0)
+}
+
 // AddTimed mocks base method.
 func (m *MockAggregator) AddTimed(arg0 aggregated.Metric, arg1 metadata.TimedMetadata) error {
 	m.ctrl.T.Helper()
@@ -105,6 +121,20 @@ func (mr *MockAggregatorMockRecorder) AddTimed(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTimed", reflect.TypeOf((*MockAggregator)(nil).AddTimed), arg0, arg1)
 }
 
+// AddTimedWithContext mocks base method.
+func (m *MockAggregator) AddTimedWithContext(arg0 context.Context, arg1 aggregated.Metric, arg2 metadata.TimedMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTimedWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTimedWithContext indicates an expected call of AddTimedWithContext.
+func (mr *MockAggregatorMockRecorder) AddTimedWithContext(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTimedWithContext", reflect.TypeOf((*MockAggregator)(nil).AddTimedWithContext), arg0, arg1, arg2)
+}
+
 // AddTimedWithStagedMetadatas mocks base method.
 func (m *MockAggregator) AddTimedWithStagedMetadatas(arg0 aggregated.Metric, arg1 metadata.StagedMetadatas) error {
 	m.ctrl.T.Helper()
@@ -133,6 +163,60 @@ func (mr *MockAggregatorMockRecorder) AddUntimed(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUntimed", reflect.TypeOf((*MockAggregator)(nil).AddUntimed), arg0, arg1)
 }
 
+// AddUntimedWithContext mocks base method.
+func (m *MockAggregator) AddUntimedWithContext(arg0 context.Context, arg1 unaggregated.MetricUnion, arg2 metadata.StagedMetadatas) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUntimedWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUntimedWithContext indicates an expected call of AddUntimedWithContext.
+func (mr *MockAggregatorMockRecorder) AddUntimedWithContext(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUntimedWithContext", reflect.TypeOf((*MockAggregator)(nil).AddUntimedWithContext), arg0, arg1, arg2)
+}
+
+// CampaignDampingStatus mocks base method.
+func (m *MockAggregator) CampaignDampingStatus() CampaignDampingStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CampaignDampingStatus")
+	ret0, _ := ret[0].(CampaignDampingStatus)
+	return ret0
+}
+
+// CampaignDampingStatus indicates an expected call of CampaignDampingStatus.
+func (mr *MockAggregatorMockRecorder) CampaignDampingStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CampaignDampingStatus", reflect.TypeOf((*MockAggregator)(nil).CampaignDampingStatus))
+}
+
+// ClearCampaignDamping mocks base method.
+func (m *MockAggregator) ClearCampaignDamping() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearCampaignDamping")
+}
+
+// ClearCampaignDamping indicates an expected call of ClearCampaignDamping.
+func (mr *MockAggregatorMockRecorder) ClearCampaignDamping() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCampaignDamping", reflect.TypeOf((*MockAggregator)(nil).ClearCampaignDamping))
+}
+
+// ClearShardRedirect mocks base method.
+func (m *MockAggregator) ClearShardRedirect(arg0 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearShardRedirect", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearShardRedirect indicates an expected call of ClearShardRedirect.
+func (mr *MockAggregatorMockRecorder) ClearShardRedirect(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearShardRedirect", reflect.TypeOf((*MockAggregator)(nil).ClearShardRedirect), arg0)
+}
+
 // Close mocks base method.
 func (m *MockAggregator) Close() error {
 	m.ctrl.T.Helper()
@@ -147,6 +231,34 @@ func (mr *MockAggregatorMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAggregator)(nil).Close))
 }
 
+// DebugMetric mocks base method.
+func (m *MockAggregator) DebugMetric(arg0 id.RawID) MetricDebugInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebugMetric", arg0)
+	ret0, _ := ret[0].(MetricDebugInfo)
+	return ret0
+}
+
+// DebugMetric indicates an expected call of DebugMetric.
+func (mr *MockAggregatorMockRecorder) DebugMetric(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugMetric", reflect.TypeOf((*MockAggregator)(nil).DebugMetric), arg0)
+}
+
+// InProgressValues mocks base method.
+func (m *MockAggregator) InProgressValues(arg0 id.RawID) []InProgressDatapoint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InProgressValues", arg0)
+	ret0, _ := ret[0].([]InProgressDatapoint)
+	return ret0
+}
+
+// InProgressValues indicates an expected call of InProgressValues.
+func (mr *MockAggregatorMockRecorder) InProgressValues(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InProgressValues", reflect.TypeOf((*MockAggregator)(nil).InProgressValues), arg0)
+}
+
 // Open mocks base method.
 func (m *MockAggregator) Open() error {
 	m.ctrl.T.Helper()
@@ -161,6 +273,34 @@ func (mr *MockAggregatorMockRecorder) Open() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAggregator)(nil).Open))
 }
 
+// PauseShardIngestion mocks base method.
+func (m *MockAggregator) PauseShardIngestion(arg0 uint32, arg1 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseShardIngestion", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseShardIngestion indicates an expected call of PauseShardIngestion.
+func (mr *MockAggregatorMockRecorder) PauseShardIngestion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseShardIngestion", reflect.TypeOf((*MockAggregator)(nil).PauseShardIngestion), arg0, arg1)
+}
+
+// PausedShards mocks base method.
+func (m *MockAggregator) PausedShards() []ShardIngestionPause {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PausedShards")
+	ret0, _ := ret[0].([]ShardIngestionPause)
+	return ret0
+}
+
+// PausedShards indicates an expected call of PausedShards.
+func (mr *MockAggregatorMockRecorder) PausedShards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PausedShards", reflect.TypeOf((*MockAggregator)(nil).PausedShards))
+}
+
 // Resign mocks base method.
 func (m *MockAggregator) Resign() error {
 	m.ctrl.T.Helper()
@@ -175,6 +315,62 @@ func (mr *MockAggregatorMockRecorder) Resign() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockAggregator)(nil).Resign))
 }
 
+// ResolutionDowngrader mocks base method.
+func (m *MockAggregator) ResolutionDowngrader() *ResolutionDowngrader {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolutionDowngrader")
+	ret0, _ := ret[0].(*ResolutionDowngrader)
+	return ret0
+}
+
+// ResolutionDowngrader indicates an expected call of ResolutionDowngrader.
+func (mr *MockAggregatorMockRecorder) ResolutionDowngrader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolutionDowngrader", reflect.TypeOf((*MockAggregator)(nil).ResolutionDowngrader))
+}
+
+// ResumeShardIngestion mocks base method.
+func (m *MockAggregator) ResumeShardIngestion(arg0 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeShardIngestion", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeShardIngestion indicates an expected call of ResumeShardIngestion.
+func (mr *MockAggregatorMockRecorder) ResumeShardIngestion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeShardIngestion", reflect.TypeOf((*MockAggregator)(nil).ResumeShardIngestion), arg0)
+}
+
+// SetShardRedirect mocks base method.
+func (m *MockAggregator) SetShardRedirect(arg0, arg1 uint32, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShardRedirect", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetShardRedirect indicates an expected call of SetShardRedirect.
+func (mr *MockAggregatorMockRecorder) SetShardRedirect(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShardRedirect", reflect.TypeOf((*MockAggregator)(nil).SetShardRedirect), arg0, arg1, arg2)
+}
+
+// ShardRedirects mocks base method.
+func (m *MockAggregator) ShardRedirects() []ShardRedirect {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShardRedirects")
+	ret0, _ := ret[0].([]ShardRedirect)
+	return ret0
+}
+
+// ShardRedirects indicates an expected call of ShardRedirects.
+func (mr *MockAggregatorMockRecorder) ShardRedirects() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardRedirects", reflect.TypeOf((*MockAggregator)(nil).ShardRedirects))
+}
+
 // Status mocks base method.
 func (m *MockAggregator) Status() RuntimeStatus {
 	m.ctrl.T.Helper()
@@ -189,6 +385,21 @@ func (mr *MockAggregatorMockRecorder) Status() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockAggregator)(nil).Status))
 }
 
+// WindowDigests mocks base method.
+func (m *MockAggregator) WindowDigests(arg0 uint32, arg1, arg2 time.Time) ([]WindowDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WindowDigests", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]WindowDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WindowDigests indicates an expected call of WindowDigests.
+func (mr *MockAggregatorMockRecorder) WindowDigests(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WindowDigests", reflect.TypeOf((*MockAggregator)(nil).WindowDigests), arg0, arg1, arg2)
+}
+
 // MockElectionManager is a mock of ElectionManager interface.
 type MockElectionManager struct {
 	ctrl     *gomock.Controller
@@ -212,6 +423,32 @@ func (m *MockElectionManager) EXPECT() *MockElectionManagerMockRecorder {
 	return m.recorder
 }
 
+// CampaignDampingStatus mocks base method.
+func (m *MockElectionManager) CampaignDampingStatus() CampaignDampingStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CampaignDampingStatus")
+	ret0, _ := ret[0].(CampaignDampingStatus)
+	return ret0
+}
+
+// CampaignDampingStatus indicates an expected call of CampaignDampingStatus.
+func (mr *MockElectionManagerMockRecorder) CampaignDampingStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CampaignDampingStatus", reflect.TypeOf((*MockElectionManager)(nil).CampaignDampingStatus))
+}
+
+// ClearCampaignDamping mocks base method.
+func (m *MockElectionManager) ClearCampaignDamping() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearCampaignDamping")
+}
+
+// ClearCampaignDamping indicates an expected call of ClearCampaignDamping.
+func (mr *MockElectionManagerMockRecorder) ClearCampaignDamping() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCampaignDamping", reflect.TypeOf((*MockElectionManager)(nil).ClearCampaignDamping))
+}
+
 // Close mocks base method.
 func (m *MockElectionManager) Close() error {
 	m.ctrl.T.Helper()
@@ -240,6 +477,20 @@ func (mr *MockElectionManagerMockRecorder) ElectionState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ElectionState", reflect.TypeOf((*MockElectionManager)(nil).ElectionState))
 }
 
+// ElectionStateDetermined mocks base method.
+func (m *MockElectionManager) ElectionStateDetermined() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ElectionStateDetermined")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ElectionStateDetermined indicates an expected call of ElectionStateDetermined.
+func (mr *MockElectionManagerMockRecorder) ElectionStateDetermined() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ElectionStateDetermined", reflect.TypeOf((*MockElectionManager)(nil).ElectionStateDetermined))
+}
+
 // IsCampaigning mocks base method.
 func (m *MockElectionManager) IsCampaigning() bool {
 	m.ctrl.T.Helper()
@@ -296,6 +547,18 @@ func (mr *MockElectionManagerMockRecorder) Resign(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockElectionManager)(nil).Resign), arg0)
 }
 
+// SetBeforePromotionFn mocks base method.
+func (m *MockElectionManager) SetBeforePromotionFn(arg0 BeforePromotionFn) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBeforePromotionFn", arg0)
+}
+
+// SetBeforePromotionFn indicates an expected call of SetBeforePromotionFn.
+func (mr *MockElectionManagerMockRecorder) SetBeforePromotionFn(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBeforePromotionFn", reflect.TypeOf((*MockElectionManager)(nil).SetBeforePromotionFn), arg0)
+}
+
 // MockFlushTimesManager is a mock of FlushTimesManager interface.
 type MockFlushTimesManager struct {
 	ctrl     *gomock.Controller
@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -32,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3/src/aggregator/client"
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cluster/kv/mem"
@@ -41,6 +43,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/metrics/pipeline"
 	"github.com/m3db/m3/src/metrics/pipeline/applied"
@@ -244,6 +247,110 @@ func TestAggregatorOpenSuccess(t *testing.T) {
 	require.Equal(t, int64(testPlacementCutover), agg.currPlacement.CutoverNanos())
 }
 
+func TestAggregatorSetRuntimeOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.checkInterval = time.Minute
+	maxAllowedForwardingDelayFn := agg.scaledMaxAllowedForwardingDelayFn(defaultMaxAllowedForwardingDelayFn)
+	delay := maxAllowedForwardingDelayFn(10*time.Second, 1)
+	require.Equal(t, time.Minute, agg.currCheckInterval())
+
+	agg.SetRuntimeOptions(runtime.NewOptions().
+		SetEntryCheckInterval(time.Second).
+		SetMaxAllowedForwardingDelayScale(2))
+	require.Equal(t, time.Second, agg.currCheckInterval())
+	require.Equal(t, 2*delay, maxAllowedForwardingDelayFn(10*time.Second, 1))
+	// Scaling an infinite delay does not overflow.
+	require.Equal(t, time.Duration(math.MaxInt64), agg.opts.MaxAllowedForwardingDelayFn()(10*time.Second, 1))
+
+	// Resetting the runtime options reverts to the configured options.
+	agg.SetRuntimeOptions(runtime.NewOptions())
+	require.Equal(t, time.Minute, agg.currCheckInterval())
+	require.Equal(t, delay, maxAllowedForwardingDelayFn(10*time.Second, 1))
+}
+
+func TestAggregatorRuntimeEntryCheckIntervalStartsTicking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flushTimesManager := NewMockFlushTimesManager(ctrl)
+	flushTimesManager.EXPECT().Reset().Return(nil).AnyTimes()
+	flushTimesManager.EXPECT().Open(gomock.Any()).Return(nil).AnyTimes()
+	flushTimesManager.EXPECT().Get().Return(nil, nil).AnyTimes()
+	flushTimesManager.EXPECT().Close().Return(nil).AnyTimes()
+
+	// No entry check interval is configured.
+	agg, _ := testAggregator(t, ctrl)
+	agg.flushTimesManager = flushTimesManager
+	require.Equal(t, time.Duration(0), agg.checkInterval)
+
+	sleptCh := make(chan time.Duration, 1)
+	agg.sleepFn = func(d time.Duration) {
+		select {
+		case sleptCh <- d:
+		default:
+		}
+		<-agg.doneCh
+	}
+	require.NoError(t, agg.Open())
+
+	select {
+	case <-sleptCh:
+		require.FailNow(t, "ticked without an entry check interval")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Setting the interval in the runtime options starts ticking.
+	agg.SetRuntimeOptions(runtime.NewOptions().SetEntryCheckInterval(time.Second))
+	select {
+	case d := <-sleptCh:
+		require.True(t, d <= time.Second)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "did not tick after setting the entry check interval")
+	}
+
+	require.NoError(t, agg.Close())
+}
+
+func TestAggregatorSetRuntimeOptionsTimedForResendEnabledRollupRegexps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.opts = agg.opts.SetTimedForResendEnabledRollupRegexps([]string{"^foo"})
+	rollup := func(id string) metadata.PipelineMetadata {
+		return metadata.PipelineMetadata{
+			Pipeline: applied.NewPipeline([]applied.OpUnion{
+				{
+					Type:   pipeline.RollupOpType,
+					Rollup: applied.RollupOp{ID: []byte(id)},
+				},
+			}),
+			ResendEnabled: true,
+		}
+	}
+
+	agg.SetRuntimeOptions(runtime.NewOptions().
+		SetTimedForResendEnabledRollupRegexps([]string{"^abc", "("}))
+	require.Nil(t, agg.timedForResendEnabledRollupRegexp(rollup("foo")))
+	r := agg.timedForResendEnabledRollupRegexp(rollup("abc"))
+	require.NotNil(t, r)
+	require.Equal(t, "^abc", r.String())
+
+	// An empty list of runtime regexps converts no rollups.
+	agg.SetRuntimeOptions(runtime.NewOptions().
+		SetTimedForResendEnabledRollupRegexps([]string{}))
+	require.Nil(t, agg.timedForResendEnabledRollupRegexp(rollup("abc")))
+	require.Nil(t, agg.timedForResendEnabledRollupRegexp(rollup("foo")))
+
+	// Removing the runtime regexps reverts to the configured regexps.
+	agg.SetRuntimeOptions(runtime.NewOptions())
+	require.Nil(t, agg.timedForResendEnabledRollupRegexp(rollup("abc")))
+	require.NotNil(t, agg.timedForResendEnabledRollupRegexp(rollup("foo")))
+}
+
 func TestAggregatorUpdateStagedMetadatas(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -419,6 +526,25 @@ func TestAggregatorAddUntimedSuccessNoPlacementUpdate(t *testing.T) {
 	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
 }
 
+func TestAggregatorAddUntimedWithContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := agg.AddUntimedWithContext(ctx, testUntimedMetric, testStagedMetadatas)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, 0, len(agg.shards[1].metricMap.entries))
+
+	err = agg.AddUntimedWithContext(context.Background(), testUntimedMetric, testStagedMetadatas)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
+}
+
 //nolint: dupl
 func TestAggregatorAddUntimedToTimed(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -426,7 +552,7 @@ func TestAggregatorAddUntimedToTimed(t *testing.T) {
 	logger := zap.NewNop()
 
 	agg, _ := testAggregator(t, ctrl)
-	agg.timedForResendEnabledRollupRegexps = compileRegexps(logger, []string{".*"})
+	agg.timedForResendEnabledRollupRegexps.Store(compileRegexps(logger, []string{".*"}))
 	metas := metadata.StagedMetadatas{testStagedMetadatas[0]}
 	// add another pipeline
 	metas[0].Pipelines = append(metas[0].Pipelines, metadata.PipelineMetadata{
@@ -467,13 +593,51 @@ func TestAggregatorAddUntimedToTimed(t *testing.T) {
 	}
 }
 
+func TestAggregatorAddUntimedToTimedInvalidMetricType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop()
+
+	s := tally.NewTestScope("testScope", nil)
+	agg, _ := testAggregator(t, ctrl)
+	agg.metrics.untimedToTimedErrors = newUntimedToTimedErrorMetrics(s)
+	agg.timedForResendEnabledRollupRegexps.Store(compileRegexps(logger, []string{"^foo", "^abc"}))
+	metas := metadata.StagedMetadatas{testStagedMetadatas[0]}
+	metas[0].Pipelines = append(metas[0].Pipelines, metadata.PipelineMetadata{
+		StoragePolicies: metas[0].Pipelines[0].StoragePolicies,
+		Pipeline: applied.NewPipeline([]applied.OpUnion{
+			{
+				Type: pipeline.RollupOpType,
+				Rollup: applied.RollupOp{
+					ID: []byte("abc"),
+				},
+			},
+		}),
+		ResendEnabled: true,
+	})
+	numPipelines := len(metas[0].Pipelines)
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+	err := agg.AddUntimed(testCounter, metas)
+	require.Error(t, err)
+	require.Equal(t, "cannot convert a counter to a timed metric", err.Error())
+	// The pipelines are restored so the metadatas can be reused.
+	require.Equal(t, numPipelines, len(metas[0].Pipelines))
+	require.Equal(t, 0, len(agg.shards[1].metricMap.entries))
+
+	counters := s.Snapshot().Counters()
+	c, ok := counters["testScope.errors+metric_type=counter,reason=invalid-metric-type,rollup_pattern=^abc"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), c.Value())
+}
+
 //nolint: dupl
 func TestAggregatorAddUntimedToTimedDisabled(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	agg, _ := testAggregator(t, ctrl)
-	agg.timedForResendEnabledRollupRegexps = nil
+	agg.timedForResendEnabledRollupRegexps.Store(compileRegexps(agg.logger, nil))
 	metas := metadata.StagedMetadatas{testStagedMetadatas[0]}
 	// add another pipeline
 	metas[0].Pipelines = append(metas[0].Pipelines, metadata.PipelineMetadata{
@@ -636,6 +800,190 @@ func TestAggregatorAddTimedSuccessNoPlacementUpdate(t *testing.T) {
 	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
 }
 
+func TestAggregatorAddTimedWithContextDeadlineExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	now := time.Unix(0, 12345)
+	agg.nowFn = func() time.Time { return now }
+
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+	err := agg.AddTimedWithContext(ctx, testTimedMetric, testTimedMetadata)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Equal(t, 0, len(agg.shards[1].metricMap.entries))
+
+	err = agg.AddTimedWithContext(context.Background(), testTimedMetric, testTimedMetadata)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
+}
+
+func TestAggregatorInProgressValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.shardFn = func([]byte, uint32) uint32 { return testNumShards }
+	require.Empty(t, agg.InProgressValues(testTimedMetric.ID))
+
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+
+	require.Equal(t, []InProgressDatapoint{
+		{
+			TimeNanos:     time.Minute.Nanoseconds(),
+			Value:         2 * testTimedMetric.Value,
+			StoragePolicy: testTimedMetadata.StoragePolicy,
+		},
+	}, agg.InProgressValues(testTimedMetric.ID))
+
+	// Untimed metrics are flushed with a prefix by default and as such are not
+	// written under the metric ID as is.
+	require.NoError(t, agg.AddUntimed(testUntimedMetric, testStagedMetadatas))
+	require.Empty(t, agg.InProgressValues(testUntimedMetric.ID))
+	require.Empty(t, agg.InProgressValues([]byte("unknown")))
+}
+
+func TestAggregatorDebugMetric(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	require.NoError(t, agg.Open())
+	agg.shardFn = func([]byte, uint32) uint32 { return 1 }
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+
+	flushTimesManager := NewMockFlushTimesManager(ctrl)
+	flushTimesManager.EXPECT().Get().Return(&schema.ShardSetFlushTimes{
+		ByShard: map[uint32]*schema.ShardFlushTimes{
+			1: {TimedByResolution: map[int64]int64{int64(time.Minute): 1234}},
+		},
+	}, nil)
+	agg.flushTimesManager = flushTimesManager
+
+	info := agg.DebugMetric(testTimedMetric.ID)
+	require.Equal(t, uint32(1), info.ShardID)
+	require.True(t, info.Owned)
+	require.Nil(t, info.RedirectToShardID)
+	require.NotNil(t, info.ShardStatus)
+	require.Equal(t, uint32(1), info.ShardStatus.ShardID)
+	require.Equal(t, 1, len(info.Elements))
+	elem := info.Elements[0]
+	require.Equal(t, "timed", elem.Category)
+	require.Equal(t, testTimedMetric.Type.String(), elem.MetricType)
+	require.Equal(t, testTimedMetadata.StoragePolicy, elem.StoragePolicy)
+	require.Equal(t, time.Minute.String(), elem.Resolution)
+	require.Equal(t, testTimedMetadata.AggregationID.String(), elem.AggregationID)
+	require.Equal(t, 1, elem.NumInProgressValues)
+	require.NotNil(t, elem.LastFlushedTime)
+	require.True(t, time.Unix(0, 1234).Equal(*elem.LastFlushedTime))
+
+	// Metrics of shards not owned by the aggregator have no aggregations.
+	agg.shardFn = func([]byte, uint32) uint32 { return testNumShards }
+	info = agg.DebugMetric(testTimedMetric.ID)
+	require.Equal(t, uint32(testNumShards), info.ShardID)
+	require.False(t, info.Owned)
+	require.Nil(t, info.ShardStatus)
+	require.Empty(t, info.Elements)
+}
+
+func TestAggregatorShardRedirect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	require.Equal(t, errAggregatorNotOpenOrClosed, agg.SetShardRedirect(3, 1, time.Minute))
+	require.NoError(t, agg.Open())
+
+	now := time.Unix(0, 0)
+	nowFn := func() time.Time { return now }
+	agg.nowFn = nowFn
+	for _, shardID := range agg.shardIDs {
+		agg.shards[shardID].nowFn = nowFn
+	}
+	agg.shardFn = func([]byte, uint32) uint32 { return 3 }
+
+	require.Equal(t, errInvalidShardRedirectTTL, agg.SetShardRedirect(3, 1, 0))
+	require.Equal(t, errShardRedirectToSelf, agg.SetShardRedirect(3, 3, time.Minute))
+	require.True(t, errors.Is(agg.SetShardRedirect(100, 1, time.Minute), errShardNotOwned))
+	require.True(t, errors.Is(agg.SetShardRedirect(3, 100, time.Minute), errShardNotOwned))
+	require.True(t, errors.Is(agg.ClearShardRedirect(100), errShardNotOwned))
+	require.Empty(t, agg.ShardRedirects())
+
+	require.NoError(t, agg.SetShardRedirect(3, 1, time.Minute))
+	require.Equal(t, []ShardRedirect{
+		{ShardID: 3, RedirectToShardID: 1, ExpiresAt: now.Add(time.Minute)},
+	}, agg.ShardRedirects())
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+	require.Equal(t, 1, len(agg.shards[1].metricMap.entries))
+	require.Equal(t, 0, len(agg.shards[3].metricMap.entries))
+
+	// Writes are no longer redirected once the redirect expires.
+	now = now.Add(time.Minute)
+	require.Empty(t, agg.ShardRedirects())
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+	require.Equal(t, 1, len(agg.shards[3].metricMap.entries))
+
+	require.NoError(t, agg.SetShardRedirect(3, 2, time.Minute))
+	require.NoError(t, agg.ClearShardRedirect(3))
+	require.Empty(t, agg.ShardRedirects())
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+	require.Equal(t, 0, len(agg.shards[2].metricMap.entries))
+}
+
+func TestAggregatorPauseShardIngestion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	require.Equal(t, errAggregatorNotOpenOrClosed, agg.PauseShardIngestion(3, time.Minute))
+	require.NoError(t, agg.Open())
+
+	now := time.Unix(0, 0)
+	nowFn := func() time.Time { return now }
+	agg.nowFn = nowFn
+	for _, shardID := range agg.shardIDs {
+		agg.shards[shardID].nowFn = nowFn
+	}
+	agg.shardFn = func([]byte, uint32) uint32 { return 3 }
+
+	require.Equal(t, errInvalidShardIngestionPauseTTL, agg.PauseShardIngestion(3, 0))
+	require.True(t, errors.Is(agg.PauseShardIngestion(100, time.Minute), errShardNotOwned))
+	require.True(t, errors.Is(agg.ResumeShardIngestion(100), errShardNotOwned))
+	require.Empty(t, agg.PausedShards())
+
+	require.NoError(t, agg.PauseShardIngestion(3, time.Minute))
+	require.Equal(t, []ShardIngestionPause{
+		{ShardID: 3, PausedUntil: now.Add(time.Minute)},
+	}, agg.PausedShards())
+	err := agg.AddTimed(testTimedMetric, testTimedMetadata)
+	require.True(t, errors.Is(err, ErrShardIngestionPaused))
+	var pausedErr ShardIngestionPausedError
+	require.True(t, errors.As(err, &pausedErr))
+	require.Equal(t, ShardIngestionPause{
+		ShardID:     3,
+		PausedUntil: now.Add(time.Minute),
+	}, pausedErr.ShardIngestionPause)
+	require.Equal(t, 0, len(agg.shards[3].metricMap.entries))
+
+	// Writes are accepted again once the pause expires.
+	now = now.Add(time.Minute)
+	require.Empty(t, agg.PausedShards())
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+	require.Equal(t, 1, len(agg.shards[3].metricMap.entries))
+
+	require.NoError(t, agg.PauseShardIngestion(3, time.Minute))
+	require.NoError(t, agg.ResumeShardIngestion(3))
+	require.Empty(t, agg.PausedShards())
+	require.NoError(t, agg.AddTimed(testTimedMetric, testTimedMetadata))
+}
+
 func TestAggregatorAddTimedSuccessWithPlacementUpdate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -890,6 +1238,42 @@ func TestAggregatorAddPassthroughSuccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAggregatorAddPassthroughBatchNotOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	err := agg.AddPassthroughBatch([]aggregated.PassthroughMetricWithMetadata{
+		{Metric: testPassthroughMetric, StoragePolicy: testPassthroughStroagePolicy},
+	})
+	require.Equal(t, errAggregatorNotOpenOrClosed, err)
+}
+
+func TestAggregatorAddPassthroughBatchSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metrics := []aggregated.PassthroughMetricWithMetadata{
+		{Metric: testPassthroughMetric, StoragePolicy: testPassthroughStroagePolicy},
+		{Metric: testPassthroughMetric, StoragePolicy: testPassthroughStroagePolicy},
+	}
+	expected := aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{Data: []byte(testPassthroughMetric.ID)},
+			TimeNanos: testPassthroughMetric.TimeNanos,
+			Value:     testPassthroughMetric.Value,
+		},
+		StoragePolicy: testPassthroughStroagePolicy,
+	}
+	w := writer.NewMockWriter(ctrl)
+	w.EXPECT().Write(expected).Return(nil).Times(2)
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.passthroughWriter = w
+	require.NoError(t, agg.Open())
+	require.NoError(t, agg.AddPassthroughBatch(metrics))
+}
+
 func TestAggregatorStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -905,6 +1289,39 @@ func TestAggregatorStatus(t *testing.T) {
 	require.Equal(t, RuntimeStatus{FlushStatus: flushStatus}, agg.Status())
 }
 
+func TestAggregatorStatusReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flushManager := NewMockFlushManager(ctrl)
+	flushManager.EXPECT().Status().Return(FlushStatus{}).AnyTimes()
+	flushManager.EXPECT().Open().Return(nil)
+	electionManager := NewMockElectionManager(ctrl)
+	electionManager.EXPECT().Open(testShardSetID).Return(nil)
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.flushManager = flushManager
+	agg.electionManager = electionManager
+	require.False(t, agg.Status().ReadyStatus.Ready)
+
+	require.NoError(t, agg.Open())
+
+	gomock.InOrder(
+		electionManager.EXPECT().ElectionStateDetermined().Return(false),
+		electionManager.EXPECT().ElectionStateDetermined().Return(true),
+	)
+	require.Equal(t, ReadyStatus{
+		PlacementProcessed: true,
+		ShardSetOpen:       true,
+	}, agg.Status().ReadyStatus)
+	require.Equal(t, ReadyStatus{
+		Ready:                   true,
+		PlacementProcessed:      true,
+		ShardSetOpen:            true,
+		ElectionStateDetermined: true,
+	}, agg.Status().ReadyStatus)
+}
+
 func TestAggregatorCloseAlreadyClosed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1154,7 +1571,12 @@ func TestAggregatorAddUntimedMetrics(t *testing.T) {
 		m.ReportError(errAggregatorShardNotWriteable, state)
 		m.ReportError(errWriteNewMetricRateLimitExceeded, state)
 		m.ReportError(errWriteValueRateLimitExceeded, state)
+		m.ReportError(errWriteShed, state)
+		m.ReportError(errRollupCardinalityLimitExceeded, state)
+		m.ReportError(ErrShardIngestionPaused, state)
 		m.ReportError(xerrors.NewRenamedError(errArrivedTooLate, errors.New("errorrr")), state)
+		m.ReportError(errTooManyForwarded, state)
+		m.ReportError(context.Canceled, state)
 		m.ReportError(errors.New("foo"), state)
 	}
 
@@ -1174,8 +1596,18 @@ func TestAggregatorAddUntimedMetrics(t *testing.T) {
 		"testScope.errors+reason=value-rate-limit-exceeded,role=non-leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=load-shed,role=leader",
+		"testScope.errors+reason=load-shed,role=non-leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=non-leader",
 		"testScope.errors+reason=arrived-too-late,role=leader",
 		"testScope.errors+reason=arrived-too-late,role=non-leader",
+		"testScope.errors+reason=too-many-forwarded,role=leader",
+		"testScope.errors+reason=too-many-forwarded,role=non-leader",
+		"testScope.errors+reason=canceled,role=leader",
+		"testScope.errors+reason=canceled,role=non-leader",
 		"testScope.errors+reason=not-categorized,role=leader",
 		"testScope.errors+reason=not-categorized,role=non-leader",
 	}
@@ -1210,9 +1642,14 @@ func TestAggregatorAddTimedMetrics(t *testing.T) {
 		m.ReportError(errAggregatorShardNotWriteable, state)
 		m.ReportError(errWriteNewMetricRateLimitExceeded, state)
 		m.ReportError(errWriteValueRateLimitExceeded, state)
+		m.ReportError(errWriteShed, state)
+		m.ReportError(errRollupCardinalityLimitExceeded, state)
+		m.ReportError(ErrShardIngestionPaused, state)
 		m.ReportError(errTooFarInTheFuture, state)
 		m.ReportError(errTooFarInThePast, state)
 		m.ReportError(xerrors.NewRenamedError(errArrivedTooLate, errors.New("errorrr")), state)
+		m.ReportError(errTooManyForwarded, state)
+		m.ReportError(context.Canceled, state)
 		m.ReportError(errors.New("foo"), state)
 	}
 
@@ -1230,12 +1667,22 @@ func TestAggregatorAddTimedMetrics(t *testing.T) {
 		"testScope.errors+reason=value-rate-limit-exceeded,role=non-leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=load-shed,role=leader",
+		"testScope.errors+reason=load-shed,role=non-leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=non-leader",
 		"testScope.errors+reason=too-far-in-the-future,role=leader",
 		"testScope.errors+reason=too-far-in-the-future,role=non-leader",
 		"testScope.errors+reason=too-far-in-the-past,role=leader",
 		"testScope.errors+reason=too-far-in-the-past,role=non-leader",
 		"testScope.errors+reason=arrived-too-late,role=leader",
 		"testScope.errors+reason=arrived-too-late,role=non-leader",
+		"testScope.errors+reason=too-many-forwarded,role=leader",
+		"testScope.errors+reason=too-many-forwarded,role=non-leader",
+		"testScope.errors+reason=canceled,role=leader",
+		"testScope.errors+reason=canceled,role=non-leader",
 		"testScope.errors+reason=not-categorized,role=leader",
 		"testScope.errors+reason=not-categorized,role=non-leader",
 	}
@@ -1271,7 +1718,12 @@ func TestAggregatorAddPassthroughMetrics(t *testing.T) {
 		m.ReportError(errAggregatorShardNotWriteable, state)
 		m.ReportError(errWriteNewMetricRateLimitExceeded, state)
 		m.ReportError(errWriteValueRateLimitExceeded, state)
+		m.ReportError(errWriteShed, state)
+		m.ReportError(errRollupCardinalityLimitExceeded, state)
+		m.ReportError(ErrShardIngestionPaused, state)
 		m.ReportError(xerrors.NewRenamedError(errArrivedTooLate, errors.New("errorrr")), state)
+		m.ReportError(errTooManyForwarded, state)
+		m.ReportError(context.Canceled, state)
 		m.ReportError(errors.New("foo"), state)
 	}
 
@@ -1290,8 +1742,18 @@ func TestAggregatorAddPassthroughMetrics(t *testing.T) {
 		"testScope.errors+reason=value-rate-limit-exceeded,role=non-leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=load-shed,role=leader",
+		"testScope.errors+reason=load-shed,role=non-leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=non-leader",
 		"testScope.errors+reason=arrived-too-late,role=leader",
 		"testScope.errors+reason=arrived-too-late,role=non-leader",
+		"testScope.errors+reason=too-many-forwarded,role=leader",
+		"testScope.errors+reason=too-many-forwarded,role=non-leader",
+		"testScope.errors+reason=canceled,role=leader",
+		"testScope.errors+reason=canceled,role=non-leader",
 		"testScope.errors+reason=not-categorized,role=leader",
 		"testScope.errors+reason=not-categorized,role=non-leader",
 	}
@@ -1331,7 +1793,12 @@ func TestAggregatorAddForwardedMetrics(t *testing.T) {
 		m.ReportError(errAggregatorShardNotWriteable, state)
 		m.ReportError(errWriteNewMetricRateLimitExceeded, state)
 		m.ReportError(errWriteValueRateLimitExceeded, state)
+		m.ReportError(errWriteShed, state)
+		m.ReportError(errRollupCardinalityLimitExceeded, state)
+		m.ReportError(ErrShardIngestionPaused, state)
 		m.ReportError(xerrors.NewRenamedError(errArrivedTooLate, errors.New("errorrr")), state)
+		m.ReportError(errTooManyForwarded, state)
+		m.ReportError(context.Canceled, state)
 		m.ReportError(errors.New("foo"), state)
 	}
 
@@ -1354,8 +1821,18 @@ func TestAggregatorAddForwardedMetrics(t *testing.T) {
 		"testScope.errors+reason=value-rate-limit-exceeded,role=non-leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=leader",
 		"testScope.errors+reason=new-metric-rate-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=load-shed,role=leader",
+		"testScope.errors+reason=load-shed,role=non-leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=leader",
+		"testScope.errors+reason=rollup-cardinality-limit-exceeded,role=non-leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=leader",
+		"testScope.errors+reason=shard-ingestion-paused,role=non-leader",
 		"testScope.errors+reason=arrived-too-late,role=leader",
 		"testScope.errors+reason=arrived-too-late,role=non-leader",
+		"testScope.errors+reason=too-many-forwarded,role=leader",
+		"testScope.errors+reason=too-many-forwarded,role=non-leader",
+		"testScope.errors+reason=canceled,role=leader",
+		"testScope.errors+reason=canceled,role=non-leader",
 		"testScope.errors+reason=not-categorized,role=leader",
 		"testScope.errors+reason=not-categorized,role=non-leader",
 	}
@@ -1700,6 +2177,35 @@ func testAggregatorUpdateShards(t *testing.T, ignoreCutoffCutover bool) {
 	assert.Equal(t, expectedLatest, aggShard.latestWriteableNanos)
 }
 
+func TestAggregatorSetRuntimeOptionsWritesIgnoreCutoffCutover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cutoff := time.Now().Add(-time.Hour)
+	shards := []shard.Shard{
+		shard.NewShard(0).SetState(shard.Leaving).SetCutoffNanos(cutoff.UnixNano()),
+		shard.NewShard(1).SetState(shard.Leaving).SetCutoffNanos(cutoff.UnixNano()),
+	}
+	placement, shardSet := testPlacementWithCustomShards(testInstanceID, 1, shards...)
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.updateShardsWithLock(placement, shardSet)
+	require.False(t, agg.shards[0].IsWritable())
+	require.False(t, agg.shards[1].IsWritable())
+
+	agg.SetRuntimeOptions(runtime.NewOptions().SetWritesIgnoreCutoffCutoverShards([]uint32{1}))
+	require.False(t, agg.shards[0].IsWritable())
+	require.True(t, agg.shards[1].IsWritable())
+
+	agg.SetRuntimeOptions(runtime.NewOptions().SetWritesIgnoreCutoffCutover(true))
+	require.True(t, agg.shards[0].IsWritable())
+	require.True(t, agg.shards[1].IsWritable())
+
+	agg.SetRuntimeOptions(runtime.NewOptions())
+	require.False(t, agg.shards[0].IsWritable())
+	require.False(t, agg.shards[1].IsWritable())
+}
+
 func testAddWithShardRedirect(t *testing.T, addFn func(*aggregator) error) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1836,6 +2342,7 @@ func testOptions(ctrl *gomock.Controller) Options {
 	electionMgr.EXPECT().Open(gomock.Any()).Return(nil).AnyTimes()
 	electionMgr.EXPECT().Close().Return(nil).AnyTimes()
 	electionMgr.EXPECT().ElectionState().Return(LeaderState).AnyTimes()
+	electionMgr.EXPECT().ElectionStateDetermined().Return(true).AnyTimes()
 
 	flushManager := NewMockFlushManager(ctrl)
 	flushManager.EXPECT().Reset().Return(nil).AnyTimes()
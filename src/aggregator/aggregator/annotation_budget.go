@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+)
+
+type annotationBudgetMetrics struct {
+	bytes        tally.Gauge
+	dropped      tally.Counter
+	droppedBytes tally.Counter
+}
+
+func newAnnotationBudgetMetrics(scope tally.Scope) annotationBudgetMetrics {
+	return annotationBudgetMetrics{
+		bytes:        scope.Gauge("bytes"),
+		dropped:      scope.Counter("dropped"),
+		droppedBytes: scope.Counter("dropped-bytes"),
+	}
+}
+
+// annotationBudget accounts for the annotation bytes retained by the open
+// aggregations of a shard, dropping annotations once the budget is exhausted
+// so that high cardinality series with large annotations cannot exhaust memory.
+type annotationBudget struct {
+	maxBytes int64
+	bytes    atomic.Int64
+	metrics  annotationBudgetMetrics
+}
+
+func newAnnotationBudget(maxBytes int64, scope tally.Scope) *annotationBudget {
+	return &annotationBudget{
+		maxBytes: maxBytes,
+		metrics:  newAnnotationBudgetMetrics(scope),
+	}
+}
+
+// Reserve accounts for replacing a retained annotation of the current length
+// with an annotation of the new length, returning false if the annotation
+// should be dropped instead.
+func (b *annotationBudget) Reserve(currLen, newLen int) bool {
+	if b == nil {
+		return true
+	}
+	delta := int64(newLen - currLen)
+	if delta <= 0 || b.maxBytes <= 0 {
+		b.bytes.Add(delta)
+		return true
+	}
+	if b.bytes.Add(delta) > b.maxBytes {
+		b.bytes.Sub(delta)
+		b.metrics.dropped.Inc(1)
+		b.metrics.droppedBytes.Inc(int64(newLen))
+		return false
+	}
+	return true
+}
+
+// Release releases an annotation of the given length once the aggregation
+// retaining it is closed.
+func (b *annotationBudget) Release(n int) {
+	if b == nil || n == 0 {
+		return
+	}
+	b.bytes.Sub(int64(n))
+}
+
+// Bytes returns the number of annotation bytes currently retained.
+func (b *annotationBudget) Bytes() int64 {
+	return b.bytes.Load()
+}
+
+// Report reports the number of annotation bytes currently retained.
+func (b *annotationBudget) Report() {
+	b.metrics.bytes.Update(float64(b.bytes.Load()))
+}
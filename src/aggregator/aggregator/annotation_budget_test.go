@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestAnnotationBudgetReserveAndRelease(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	b := newAnnotationBudget(10, scope)
+
+	require.True(t, b.Reserve(0, 6))
+	require.Equal(t, int64(6), b.Bytes())
+
+	// Exceeding the budget drops the annotation without accounting for it.
+	require.False(t, b.Reserve(0, 6))
+	require.Equal(t, int64(6), b.Bytes())
+
+	// Shrinking a retained annotation is always allowed.
+	require.True(t, b.Reserve(6, 2))
+	require.Equal(t, int64(2), b.Bytes())
+
+	require.True(t, b.Reserve(0, 8))
+	require.Equal(t, int64(10), b.Bytes())
+
+	b.Release(8)
+	b.Release(2)
+	require.Equal(t, int64(0), b.Bytes())
+
+	b.Report()
+	snapshot := scope.Snapshot()
+	require.Equal(t, int64(1), snapshot.Counters()["dropped+"].Value())
+	require.Equal(t, int64(6), snapshot.Counters()["dropped-bytes+"].Value())
+	require.Equal(t, float64(0), snapshot.Gauges()["bytes+"].Value())
+}
+
+func TestAnnotationBudgetUnlimited(t *testing.T) {
+	b := newAnnotationBudget(0, tally.NoopScope)
+	require.True(t, b.Reserve(0, 1<<20))
+	require.Equal(t, int64(1<<20), b.Bytes())
+
+	var nilBudget *annotationBudget
+	require.True(t, nilBudget.Reserve(0, 1<<20))
+	nilBudget.Release(1 << 20)
+}
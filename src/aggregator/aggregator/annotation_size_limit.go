@@ -0,0 +1,163 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	errAnnotationTooLarge                = errors.New("annotation exceeds the max annotation size")
+	errInvalidAnnotationSizeLimitMaxSize = errors.New("annotation size limit max bytes must be positive")
+)
+
+// AnnotationSizePolicy is how metrics whose annotation exceeds the max
+// annotation size are handled.
+type AnnotationSizePolicy int
+
+// List of supported annotation size policies.
+const (
+	// TruncateAnnotationSizePolicy truncates the annotation to the max size.
+	TruncateAnnotationSizePolicy AnnotationSizePolicy = iota
+	// DropAnnotationSizePolicy drops the annotation and keeps the metric.
+	DropAnnotationSizePolicy
+	// RejectAnnotationSizePolicy rejects the metric.
+	RejectAnnotationSizePolicy
+)
+
+var validAnnotationSizePolicies = []AnnotationSizePolicy{
+	TruncateAnnotationSizePolicy,
+	DropAnnotationSizePolicy,
+	RejectAnnotationSizePolicy,
+}
+
+func (p AnnotationSizePolicy) String() string {
+	switch p {
+	case TruncateAnnotationSizePolicy:
+		return "truncate"
+	case DropAnnotationSizePolicy:
+		return "drop"
+	case RejectAnnotationSizePolicy:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAnnotationSizePolicy parses an annotation size policy.
+func ParseAnnotationSizePolicy(str string) (AnnotationSizePolicy, error) {
+	for _, valid := range validAnnotationSizePolicies {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid annotation size policy '%s', valid policies are %v",
+		str, validAnnotationSizePolicies)
+}
+
+// UnmarshalYAML unmarshals an annotation size policy, defaulting to truncating
+// if not set.
+func (p *AnnotationSizePolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*p = TruncateAnnotationSizePolicy
+		return nil
+	}
+	parsed, err := ParseAnnotationSizePolicy(str)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// AnnotationSizeLimitOptions configure the max size of the annotations of the
+// metrics ingested, so oversized annotations do not bloat the memory of the
+// aggregations and the flushed payloads.
+type AnnotationSizeLimitOptions struct {
+	// MaxBytes is the max size in bytes of an annotation.
+	MaxBytes int
+	// Policy is how metrics whose annotation exceeds the max size are handled.
+	Policy AnnotationSizePolicy
+}
+
+// Validate validates the options.
+func (o AnnotationSizeLimitOptions) Validate() error {
+	if o.MaxBytes <= 0 {
+		return errInvalidAnnotationSizeLimitMaxSize
+	}
+	return nil
+}
+
+type annotationSizeLimiterMetrics struct {
+	truncated tally.Counter
+	dropped   tally.Counter
+	rejected  tally.Counter
+}
+
+func newAnnotationSizeLimiterMetrics(scope tally.Scope) annotationSizeLimiterMetrics {
+	return annotationSizeLimiterMetrics{
+		truncated: scope.Counter("truncated"),
+		dropped:   scope.Counter("dropped"),
+		rejected:  scope.Counter("rejected"),
+	}
+}
+
+// annotationSizeLimiter enforces the max size of the annotations ingested.
+type annotationSizeLimiter struct {
+	opts    AnnotationSizeLimitOptions
+	metrics annotationSizeLimiterMetrics
+}
+
+func newAnnotationSizeLimiter(
+	opts AnnotationSizeLimitOptions,
+	scope tally.Scope,
+) *annotationSizeLimiter {
+	return &annotationSizeLimiter{
+		opts:    opts,
+		metrics: newAnnotationSizeLimiterMetrics(scope),
+	}
+}
+
+// Limit returns the annotation to ingest in place of the given one, or
+// errAnnotationTooLarge if the metric should be rejected.
+func (l *annotationSizeLimiter) Limit(annotation []byte) ([]byte, error) {
+	if l == nil || len(annotation) <= l.opts.MaxBytes {
+		return annotation, nil
+	}
+	switch l.opts.Policy {
+	case DropAnnotationSizePolicy:
+		l.metrics.dropped.Inc(1)
+		return nil, nil
+	case RejectAnnotationSizePolicy:
+		l.metrics.rejected.Inc(1)
+		return nil, errAnnotationTooLarge
+	default:
+		l.metrics.truncated.Inc(1)
+		return annotation[:l.opts.MaxBytes], nil
+	}
+}
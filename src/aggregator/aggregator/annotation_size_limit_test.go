@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestAnnotationSizePolicyUnmarshalYAML(t *testing.T) {
+	for _, input := range []struct {
+		str      string
+		expected AnnotationSizePolicy
+	}{
+		{str: `""`, expected: TruncateAnnotationSizePolicy},
+		{str: "truncate", expected: TruncateAnnotationSizePolicy},
+		{str: "drop", expected: DropAnnotationSizePolicy},
+		{str: "reject", expected: RejectAnnotationSizePolicy},
+	} {
+		var policy AnnotationSizePolicy
+		require.NoError(t, yaml.Unmarshal([]byte(input.str), &policy))
+		require.Equal(t, input.expected, policy)
+	}
+
+	var policy AnnotationSizePolicy
+	require.Error(t, yaml.Unmarshal([]byte("shorten"), &policy))
+}
+
+func TestAnnotationSizeLimitOptionsValidate(t *testing.T) {
+	require.NoError(t, AnnotationSizeLimitOptions{MaxBytes: 1}.Validate())
+	require.Equal(t, errInvalidAnnotationSizeLimitMaxSize, AnnotationSizeLimitOptions{}.Validate())
+}
+
+func TestAnnotationSizeLimiterLimit(t *testing.T) {
+	var (
+		scope     = tally.NewTestScope("", nil)
+		small     = []byte("foo")
+		oversized = []byte("foobar")
+	)
+	for _, input := range []struct {
+		policy     AnnotationSizePolicy
+		annotation []byte
+		err        error
+		counter    string
+	}{
+		{policy: TruncateAnnotationSizePolicy, annotation: []byte("foob"), counter: "truncated"},
+		{policy: DropAnnotationSizePolicy, counter: "dropped"},
+		{policy: RejectAnnotationSizePolicy, err: errAnnotationTooLarge, counter: "rejected"},
+	} {
+		l := newAnnotationSizeLimiter(AnnotationSizeLimitOptions{
+			MaxBytes: 4,
+			Policy:   input.policy,
+		}, scope.Tagged(map[string]string{"policy": input.policy.String()}))
+
+		annotation, err := l.Limit(small)
+		require.NoError(t, err)
+		require.Equal(t, small, annotation)
+
+		annotation, err = l.Limit(oversized)
+		require.Equal(t, input.err, err)
+		require.Equal(t, input.annotation, annotation)
+
+		counters := scope.Snapshot().Counters()
+		c, ok := counters[input.counter+"+policy="+input.policy.String()]
+		require.True(t, ok)
+		require.Equal(t, int64(1), c.Value())
+	}
+
+	// A nil limiter does not limit annotations.
+	var l *annotationSizeLimiter
+	annotation, err := l.Limit(oversized)
+	require.NoError(t, err)
+	require.Equal(t, oversized, annotation)
+}
+
+func TestAggregatorAddPassthroughBatchAnnotationTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oversized := testPassthroughMetric
+	oversized.Annotation = []byte("foobar")
+	metrics := []aggregated.PassthroughMetricWithMetadata{
+		{Metric: testPassthroughMetric, StoragePolicy: testPassthroughStroagePolicy},
+		{Metric: oversized, StoragePolicy: testPassthroughStroagePolicy},
+	}
+	expected := aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{Data: []byte(testPassthroughMetric.ID)},
+			TimeNanos: testPassthroughMetric.TimeNanos,
+			Value:     testPassthroughMetric.Value,
+		},
+		StoragePolicy: testPassthroughStroagePolicy,
+	}
+	w := writer.NewMockWriter(ctrl)
+	w.EXPECT().Write(expected).Return(nil).Times(1)
+
+	agg, _ := testAggregator(t, ctrl)
+	agg.passthroughWriter = w
+	agg.annotationSizeLimiter = newAnnotationSizeLimiter(AnnotationSizeLimitOptions{
+		MaxBytes: 4,
+		Policy:   RejectAnnotationSizePolicy,
+	}, tally.NoopScope)
+	require.NoError(t, agg.Open())
+
+	// The metrics with annotations within the max size are still written.
+	require.Equal(t, errAnnotationTooLarge, agg.AddPassthroughBatch(metrics))
+}
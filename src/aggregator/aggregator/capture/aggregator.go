@@ -21,8 +21,10 @@
 package capture
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	aggr "github.com/m3db/m3/src/aggregator/aggregator"
 	"github.com/m3db/m3/src/metrics/metadata"
@@ -93,6 +95,17 @@ func (agg *aggregator) AddUntimed(
 	return nil
 }
 
+func (agg *aggregator) AddUntimedWithContext(
+	ctx context.Context,
+	mu unaggregated.MetricUnion,
+	sm metadata.StagedMetadatas,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return agg.AddUntimed(mu, sm)
+}
+
 func (agg *aggregator) AddTimed(
 	metric aggregated.Metric,
 	metadata metadata.TimedMetadata,
@@ -113,6 +126,17 @@ func (agg *aggregator) AddTimed(
 	return nil
 }
 
+func (agg *aggregator) AddTimedWithContext(
+	ctx context.Context,
+	metric aggregated.Metric,
+	metadata metadata.TimedMetadata,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return agg.AddTimed(metric, metadata)
+}
+
 func (agg *aggregator) AddTimedWithStagedMetadatas(
 	metric aggregated.Metric,
 	sm metadata.StagedMetadatas,
@@ -173,10 +197,50 @@ func (agg *aggregator) AddPassthrough(
 	return nil
 }
 
+func (agg *aggregator) AddPassthroughBatch(
+	metrics []aggregated.PassthroughMetricWithMetadata,
+) error {
+	agg.Lock()
+	defer agg.Unlock()
+
+	for _, pm := range metrics {
+		// Clone the metric and storage policy to ensure it cannot be mutated externally.
+		agg.passthroughMetricsWithMetadata = append(agg.passthroughMetricsWithMetadata,
+			aggregated.PassthroughMetricWithMetadata{
+				Metric:        cloneTimedMetric(pm.Metric),
+				StoragePolicy: cloneStoragePolicy(pm.StoragePolicy),
+			})
+		agg.numMetricsAdded++
+	}
+	return nil
+}
+
 func (agg *aggregator) Resign() error              { return nil }
 func (agg *aggregator) Status() aggr.RuntimeStatus { return aggr.RuntimeStatus{} }
 func (agg *aggregator) Close() error               { return nil }
 
+func (agg *aggregator) CampaignDampingStatus() aggr.CampaignDampingStatus {
+	return aggr.CampaignDampingStatus{}
+}
+func (agg *aggregator) ClearCampaignDamping() {}
+
+func (agg *aggregator) InProgressValues(id.RawID) []aggr.InProgressDatapoint { return nil }
+func (agg *aggregator) DebugMetric(id.RawID) aggr.MetricDebugInfo            { return aggr.MetricDebugInfo{} }
+
+func (agg *aggregator) SetShardRedirect(uint32, uint32, time.Duration) error { return nil }
+func (agg *aggregator) ClearShardRedirect(uint32) error                      { return nil }
+func (agg *aggregator) ShardRedirects() []aggr.ShardRedirect                 { return nil }
+
+func (agg *aggregator) PauseShardIngestion(uint32, time.Duration) error { return nil }
+func (agg *aggregator) ResumeShardIngestion(uint32) error               { return nil }
+func (agg *aggregator) PausedShards() []aggr.ShardIngestionPause        { return nil }
+
+func (agg *aggregator) ResolutionDowngrader() *aggr.ResolutionDowngrader { return nil }
+
+func (agg *aggregator) WindowDigests(uint32, time.Time, time.Time) ([]aggr.WindowDigest, error) {
+	return nil, nil
+}
+
 func (agg *aggregator) NumMetricsAdded() int {
 	agg.RLock()
 	numMetricsAdded := agg.numMetricsAdded
@@ -267,6 +331,12 @@ func cloneForwardedMetric(metric aggregated.ForwardedMetric) aggregated.Forwarde
 	copy(cloned.ID, metric.ID)
 	cloned.Values = make([]float64, len(metric.Values))
 	copy(cloned.Values, metric.Values)
+	if metric.HistogramBucketUpperBounds != nil {
+		cloned.HistogramBucketUpperBounds = make([]float64, len(metric.HistogramBucketUpperBounds))
+		copy(cloned.HistogramBucketUpperBounds, metric.HistogramBucketUpperBounds)
+		cloned.HistogramBucketCounts = make([]int64, len(metric.HistogramBucketCounts))
+		copy(cloned.HistogramBucketCounts, metric.HistogramBucketCounts)
+	}
 	return cloned
 }
 
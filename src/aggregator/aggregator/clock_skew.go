@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// ClockSkewWatermark is the most recent flush time persisted by the leader that a
+// follower observed to be ahead of its own clock by more than the clock skew threshold.
+// It is shared between the flush manager, which advances it, and the entries, which
+// clamp the time untimed metrics are aggregated at to it. This keeps a follower whose
+// clock lags behind the leader's from aggregating metrics into windows the leader has
+// already flushed, which it would flush a second time once promoted to leader.
+type ClockSkewWatermark struct {
+	nanos atomic.Int64
+}
+
+// NewClockSkewWatermark creates a new clock skew watermark.
+func NewClockSkewWatermark() *ClockSkewWatermark {
+	return &ClockSkewWatermark{}
+}
+
+// Update sets the watermark to the given time in nanoseconds, zero clears the watermark.
+func (w *ClockSkewWatermark) Update(nanos int64) {
+	w.nanos.Store(nanos)
+}
+
+// Nanos returns the watermark in nanoseconds, or zero if not set.
+func (w *ClockSkewWatermark) Nanos() int64 {
+	return w.nanos.Load()
+}
+
+// Clamp returns the later of the given time and the watermark.
+func (w *ClockSkewWatermark) Clamp(t time.Time) time.Time {
+	if nanos := w.nanos.Load(); nanos > t.UnixNano() {
+		return time.Unix(0, nanos)
+	}
+	return t
+}
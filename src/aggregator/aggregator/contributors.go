@@ -0,0 +1,231 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// contributorsSketchPrecision is the number of bits of the hash of a
+	// contributor selecting its register, the sketch has a standard error
+	// of 1.04/sqrt(2^precision), about 3%.
+	contributorsSketchPrecision = 10
+	contributorsSketchRegisters = 1 << contributorsSketchPrecision
+	contributorsSketchMaxRank   = 64 - contributorsSketchPrecision + 1
+
+	// contributorsSketchMaxSparse is the number of registers set above which
+	// the sparse representation is larger than the dense one.
+	contributorsSketchMaxSparse = contributorsSketchRegisters / 4
+
+	contributorsSketchSparseFormat byte = 1
+	contributorsSketchDenseFormat  byte = 2
+	contributorsSketchSparseBytes       = 3
+)
+
+var errInvalidContributorsSketch = errors.New("invalid contributors sketch")
+
+// contributorsSketch is a HyperLogLog sketch estimating the number of distinct
+// values of the contributors tag of the series aggregated into a rollup. The
+// sketch is kept sparse while few registers are set so forwarding the single
+// contributor of a series is cheap.
+type contributorsSketch struct {
+	sparse []uint32 // register index << 8 | rank, sorted by register index.
+	dense  []uint8  // set in place of sparse once too many registers are set.
+}
+
+// newContributorsSketch returns a sketch holding a single contributor.
+func newContributorsSketch(contributor []byte) *contributorsSketch {
+	s := &contributorsSketch{}
+	s.add(contributor)
+	return s
+}
+
+func (s *contributorsSketch) add(contributor []byte) {
+	hash := xxhash.Sum64(contributor)
+	idx := uint32(hash >> (64 - contributorsSketchPrecision))
+	rank := uint8(bits.LeadingZeros64(hash<<contributorsSketchPrecision|1<<(contributorsSketchPrecision-1)) + 1)
+	s.set(idx, rank)
+}
+
+func (s *contributorsSketch) set(idx uint32, rank uint8) {
+	if s.dense != nil {
+		if rank > s.dense[idx] {
+			s.dense[idx] = rank
+		}
+		return
+	}
+	i := sort.Search(len(s.sparse), func(i int) bool { return s.sparse[i]>>8 >= idx })
+	if i < len(s.sparse) && s.sparse[i]>>8 == idx {
+		if rank > uint8(s.sparse[i]) {
+			s.sparse[i] = idx<<8 | uint32(rank)
+		}
+		return
+	}
+	if len(s.sparse) == contributorsSketchMaxSparse {
+		s.densify()
+		s.dense[idx] = rank
+		return
+	}
+	s.sparse = append(s.sparse, 0)
+	copy(s.sparse[i+1:], s.sparse[i:])
+	s.sparse[i] = idx<<8 | uint32(rank)
+}
+
+func (s *contributorsSketch) densify() {
+	s.dense = make([]uint8, contributorsSketchRegisters)
+	for _, r := range s.sparse {
+		s.dense[r>>8] = uint8(r)
+	}
+	s.sparse = nil
+}
+
+// merge merges the contributors of another sketch into the sketch.
+func (s *contributorsSketch) merge(other *contributorsSketch) {
+	if other.dense != nil {
+		if s.dense == nil {
+			s.densify()
+		}
+		for idx, rank := range other.dense {
+			if rank > s.dense[idx] {
+				s.dense[idx] = rank
+			}
+		}
+		return
+	}
+	for _, r := range other.sparse {
+		s.set(r>>8, uint8(r))
+	}
+}
+
+// mergeBytes merges the contributors of an encoded sketch into the sketch,
+// leaving the sketch untouched if the encoded sketch is invalid.
+func (s *contributorsSketch) mergeBytes(data []byte) error {
+	if err := validateContributorsSketch(data); err != nil {
+		return err
+	}
+	format, data := data[0], data[1:]
+	if format == contributorsSketchSparseFormat {
+		for i := 0; i < len(data); i += contributorsSketchSparseBytes {
+			s.set(uint32(binary.BigEndian.Uint16(data[i:])), data[i+2])
+		}
+		return nil
+	}
+	for idx, rank := range data {
+		if rank > 0 {
+			s.set(uint32(idx), rank)
+		}
+	}
+	return nil
+}
+
+func validateContributorsSketch(data []byte) error {
+	if len(data) == 0 {
+		return errInvalidContributorsSketch
+	}
+	format, data := data[0], data[1:]
+	switch format {
+	case contributorsSketchSparseFormat:
+		if len(data)%contributorsSketchSparseBytes != 0 {
+			return errInvalidContributorsSketch
+		}
+		for i := 0; i < len(data); i += contributorsSketchSparseBytes {
+			idx, rank := binary.BigEndian.Uint16(data[i:]), data[i+2]
+			if idx >= contributorsSketchRegisters || rank == 0 || rank > contributorsSketchMaxRank {
+				return errInvalidContributorsSketch
+			}
+		}
+	case contributorsSketchDenseFormat:
+		if len(data) != contributorsSketchRegisters {
+			return errInvalidContributorsSketch
+		}
+		for _, rank := range data {
+			if rank > contributorsSketchMaxRank {
+				return errInvalidContributorsSketch
+			}
+		}
+	default:
+		return errInvalidContributorsSketch
+	}
+	return nil
+}
+
+// bytes returns the encoded sketch, or nil if the sketch is empty.
+func (s *contributorsSketch) bytes() []byte {
+	if s.dense != nil {
+		return append([]byte{contributorsSketchDenseFormat}, s.dense...)
+	}
+	if len(s.sparse) == 0 {
+		return nil
+	}
+	data := make([]byte, 1, 1+len(s.sparse)*contributorsSketchSparseBytes)
+	data[0] = contributorsSketchSparseFormat
+	for _, r := range s.sparse {
+		data = append(data, byte(r>>16), byte(r>>8), byte(r))
+	}
+	return data
+}
+
+func (s *contributorsSketch) empty() bool {
+	return s.dense == nil && len(s.sparse) == 0
+}
+
+func (s *contributorsSketch) reset() {
+	s.sparse = s.sparse[:0]
+	s.dense = nil
+}
+
+// estimate returns the estimated number of distinct contributors, relying on
+// linear counting while enough registers are unset for it to be more accurate.
+func (s *contributorsSketch) estimate() float64 {
+	var (
+		m     = float64(contributorsSketchRegisters)
+		zeros = contributorsSketchRegisters
+		sum   float64
+	)
+	if s.dense != nil {
+		zeros = 0
+		for _, rank := range s.dense {
+			if rank == 0 {
+				zeros++
+			}
+			sum += math.Ldexp(1, -int(rank))
+		}
+	} else {
+		zeros -= len(s.sparse)
+		sum = float64(zeros)
+		for _, r := range s.sparse {
+			sum += math.Ldexp(1, -int(uint8(r)))
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return estimate
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContributorsSketchEstimate(t *testing.T) {
+	for _, n := range []int{1, 10, 100, 1000, 100000} {
+		s := &contributorsSketch{}
+		for i := 0; i < n; i++ {
+			// Adding a contributor again does not change the estimate.
+			s.add([]byte(fmt.Sprintf("host%d", i)))
+			s.add([]byte(fmt.Sprintf("host%d", i)))
+		}
+		require.InEpsilon(t, float64(n), s.estimate(), 0.1, n)
+		require.Equal(t, n > contributorsSketchMaxSparse, s.dense != nil, n)
+	}
+	require.Equal(t, 0.0, (&contributorsSketch{}).estimate())
+}
+
+func TestContributorsSketchMerge(t *testing.T) {
+	var (
+		sparse1 = &contributorsSketch{}
+		sparse2 = &contributorsSketch{}
+		dense   = &contributorsSketch{}
+	)
+	for i := 0; i < 50; i++ {
+		sparse1.add([]byte(fmt.Sprintf("host%d", i)))
+		sparse2.add([]byte(fmt.Sprintf("host%d", i+25)))
+	}
+	for i := 0; i < 2000; i++ {
+		dense.add([]byte(fmt.Sprintf("host%d", i+1000)))
+	}
+
+	merged := &contributorsSketch{}
+	merged.merge(sparse1)
+	require.NoError(t, merged.mergeBytes(sparse2.bytes()))
+	require.Nil(t, merged.dense)
+	require.InEpsilon(t, 75.0, merged.estimate(), 0.1)
+
+	require.NoError(t, merged.mergeBytes(dense.bytes()))
+	require.NotNil(t, merged.dense)
+	require.InEpsilon(t, 2075.0, merged.estimate(), 0.1)
+
+	// Merging the sketches in another order yields the same sketch.
+	other := &contributorsSketch{}
+	other.merge(dense)
+	other.merge(sparse2)
+	other.merge(sparse1)
+	require.Equal(t, merged.bytes(), other.bytes())
+
+	merged.reset()
+	require.True(t, merged.empty())
+	require.Nil(t, merged.bytes())
+}
+
+func TestContributorsSketchMergeInvalidBytes(t *testing.T) {
+	s := newContributorsSketch([]byte("host1"))
+	expected := s.bytes()
+	for _, data := range [][]byte{
+		nil,
+		{3},
+		{contributorsSketchSparseFormat, 0, 1},
+		{contributorsSketchSparseFormat, 0, 1, 2, 0x04, 0, 1},
+		{contributorsSketchSparseFormat, 0, 1, 0},
+		{contributorsSketchSparseFormat, 0, 1, contributorsSketchMaxRank + 1},
+		append([]byte{contributorsSketchDenseFormat}, make([]byte, contributorsSketchRegisters-1)...),
+	} {
+		require.Equal(t, errInvalidContributorsSketch, s.mergeBytes(data), data)
+		require.Equal(t, expected, s.bytes(), data)
+	}
+}
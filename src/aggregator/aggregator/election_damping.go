@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+)
+
+var errCampaignDampingWindowAndBackoff = errors.New(
+	"campaign damping window and backoff must be positive when max flaps is set")
+
+// CampaignDampingOptions configures the damping of leadership flaps. Once the
+// election state of an instance has moved into or out of leadership MaxFlaps
+// times within the window, the instance stops campaigning for the backoff
+// duration as soon as it is not the leader, so an unstable etcd cluster cannot
+// keep moving the leadership between instances, each move causing a gap in
+// flushes. The leader is never made to resign by the damping since that would
+// be one more flap, and a suppressed instance still campaigns when the election
+// has no leader. Damping is disabled if MaxFlaps is zero.
+type CampaignDampingOptions struct {
+	Window   time.Duration
+	MaxFlaps int
+	Backoff  time.Duration
+}
+
+// Validate validates the campaign damping options.
+func (o CampaignDampingOptions) Validate() error {
+	if o.MaxFlaps > 0 && (o.Window <= 0 || o.Backoff <= 0) {
+		return errCampaignDampingWindowAndBackoff
+	}
+	return nil
+}
+
+// CampaignDampingStatus is the run-time status of the campaign damping.
+type CampaignDampingStatus struct {
+	Enabled         bool      `json:"enabled"`
+	RecentFlaps     int       `json:"recentFlaps"`
+	Suppressed      bool      `json:"suppressed"`
+	SuppressedUntil time.Time `json:"suppressedUntil"`
+}
+
+type campaignDampingMetrics struct {
+	flaps        tally.Counter
+	suppressions tally.Counter
+	clears       tally.Counter
+	suppressed   tally.Gauge
+}
+
+func newCampaignDampingMetrics(scope tally.Scope) campaignDampingMetrics {
+	return campaignDampingMetrics{
+		flaps:        scope.Counter("flaps"),
+		suppressions: scope.Counter("suppressions"),
+		clears:       scope.Counter("clears"),
+		suppressed:   scope.Gauge("suppressed"),
+	}
+}
+
+// campaignDamper tracks the leadership flaps of an instance and decides when
+// campaigning is suppressed.
+type campaignDamper struct {
+	sync.Mutex
+
+	opts    CampaignDampingOptions
+	nowFn   clock.NowFn
+	metrics campaignDampingMetrics
+
+	flaps           []time.Time
+	suppressedUntil time.Time
+}
+
+func newCampaignDamper(
+	opts CampaignDampingOptions,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *campaignDamper {
+	return &campaignDamper{
+		opts:    opts,
+		nowFn:   nowFn,
+		metrics: newCampaignDampingMetrics(scope),
+	}
+}
+
+func (d *campaignDamper) enabled() bool {
+	return d.opts.MaxFlaps > 0
+}
+
+// RecordFlap records a move into or out of leadership, and returns true if
+// campaigning has become suppressed as a result.
+func (d *campaignDamper) RecordFlap() bool {
+	d.metrics.flaps.Inc(1)
+	if !d.enabled() {
+		return false
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := d.nowFn()
+	d.pruneWithLock(now)
+	d.flaps = append(d.flaps, now)
+	if len(d.flaps) < d.opts.MaxFlaps {
+		return false
+	}
+	d.flaps = d.flaps[:0]
+	d.suppressedUntil = now.Add(d.opts.Backoff)
+	d.metrics.suppressions.Inc(1)
+	return true
+}
+
+// Suppressed returns true if campaigning is suppressed.
+func (d *campaignDamper) Suppressed() bool {
+	if !d.enabled() {
+		return false
+	}
+	d.Lock()
+	suppressed := d.nowFn().Before(d.suppressedUntil)
+	d.Unlock()
+	return suppressed
+}
+
+// Clear forgets the recorded flaps and lifts any suppression.
+func (d *campaignDamper) Clear() {
+	d.Lock()
+	d.flaps = d.flaps[:0]
+	d.suppressedUntil = time.Time{}
+	d.Unlock()
+	d.metrics.clears.Inc(1)
+}
+
+// Status returns the run-time status of the damping.
+func (d *campaignDamper) Status() CampaignDampingStatus {
+	status := CampaignDampingStatus{Enabled: d.enabled()}
+	if !status.Enabled {
+		return status
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := d.nowFn()
+	d.pruneWithLock(now)
+	status.RecentFlaps = len(d.flaps)
+	if now.Before(d.suppressedUntil) {
+		status.Suppressed = true
+		status.SuppressedUntil = d.suppressedUntil
+	}
+	return status
+}
+
+func (d *campaignDamper) reportMetrics() {
+	var suppressed float64
+	if d.Suppressed() {
+		suppressed = 1
+	}
+	d.metrics.suppressed.Update(suppressed)
+}
+
+func (d *campaignDamper) pruneWithLock(now time.Time) {
+	windowStart := now.Add(-d.opts.Window)
+	idx := 0
+	for idx < len(d.flaps) && !d.flaps[idx].After(windowStart) {
+		idx++
+	}
+	if idx == 0 {
+		return
+	}
+	n := copy(d.flaps, d.flaps[idx:])
+	d.flaps = d.flaps[:n]
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestCampaignDamperDisabled(t *testing.T) {
+	d := newCampaignDamper(CampaignDampingOptions{}, time.Now, tally.NoopScope)
+	for i := 0; i < 10; i++ {
+		require.False(t, d.RecordFlap())
+	}
+	require.False(t, d.Suppressed())
+	require.Equal(t, CampaignDampingStatus{}, d.Status())
+}
+
+func TestCampaignDamperSuppressesAfterMaxFlaps(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nowFn := func() time.Time { return now }
+	opts := CampaignDampingOptions{
+		Window:   time.Minute,
+		MaxFlaps: 3,
+		Backoff:  5 * time.Minute,
+	}
+	d := newCampaignDamper(opts, nowFn, tally.NoopScope)
+
+	// Flaps older than the window are forgotten.
+	require.False(t, d.RecordFlap())
+	now = now.Add(40 * time.Second)
+	require.False(t, d.RecordFlap())
+	now = now.Add(40 * time.Second)
+	require.False(t, d.RecordFlap())
+	require.Equal(t, 2, d.Status().RecentFlaps)
+	require.False(t, d.Suppressed())
+
+	now = now.Add(10 * time.Second)
+	require.True(t, d.RecordFlap())
+	require.True(t, d.Suppressed())
+	require.Equal(t, CampaignDampingStatus{
+		Enabled:         true,
+		Suppressed:      true,
+		SuppressedUntil: now.Add(opts.Backoff),
+	}, d.Status())
+
+	now = now.Add(opts.Backoff)
+	require.False(t, d.Suppressed())
+	require.False(t, d.Status().Suppressed)
+}
+
+func TestCampaignDamperClear(t *testing.T) {
+	opts := CampaignDampingOptions{
+		Window:   time.Minute,
+		MaxFlaps: 1,
+		Backoff:  time.Hour,
+	}
+	d := newCampaignDamper(opts, time.Now, tally.NoopScope)
+	require.True(t, d.RecordFlap())
+	require.True(t, d.Suppressed())
+
+	d.Clear()
+	require.False(t, d.Suppressed())
+	require.Equal(t, CampaignDampingStatus{Enabled: true}, d.Status())
+}
+
+func TestCampaignDampingOptionsValidate(t *testing.T) {
+	require.NoError(t, CampaignDampingOptions{}.Validate())
+	require.NoError(t, CampaignDampingOptions{
+		Window:   time.Minute,
+		MaxFlaps: 1,
+		Backoff:  time.Minute,
+	}.Validate())
+	require.Equal(t, errCampaignDampingWindowAndBackoff, CampaignDampingOptions{
+		MaxFlaps: 1,
+		Backoff:  time.Minute,
+	}.Validate())
+	require.Equal(t, errCampaignDampingWindowAndBackoff, CampaignDampingOptions{
+		Window:   time.Minute,
+		MaxFlaps: 1,
+	}.Validate())
+}
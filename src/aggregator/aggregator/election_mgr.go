@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader"
 	"github.com/m3db/m3/src/cluster/services/leader/campaign"
 	"github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -51,10 +52,29 @@ type ElectionManager interface {
 	// ElectionState returns the election state.
 	ElectionState() ElectionState
 
+	// ElectionStateDetermined returns true once the election state has been
+	// determined since the election manager was opened, either by the campaign
+	// or because campaigning is disabled.
+	ElectionStateDetermined() bool
+
 	// IsCampaigning returns true if the election manager is actively campaigning,
 	// and false otherwise.
 	IsCampaigning() bool
 
+	// CampaignDampingStatus returns the run-time status of the damping of
+	// leadership flaps.
+	CampaignDampingStatus() CampaignDampingStatus
+
+	// ClearCampaignDamping forgets the leadership flaps recorded so far and
+	// lets the instance campaign again if campaigning was suppressed.
+	ClearCampaignDamping()
+
+	// SetBeforePromotionFn sets the function called before the instance is
+	// promoted from follower to leader. It is called in the background and the
+	// promotion waits for it to return, unless the instance is no longer meant
+	// to lead by then.
+	SetBeforePromotionFn(fn BeforePromotionFn)
+
 	// Resign stops the election and resigns from the ongoing campaign if any, thereby
 	// forcing the current instance to become a follower. If the provided context
 	// expires before resignation is complete, the context error is returned, and the
@@ -69,6 +89,9 @@ const (
 	backOffOnResignOrElectionError = time.Second
 )
 
+// BeforePromotionFn is called before a follower is promoted to leader.
+type BeforePromotionFn func()
+
 var (
 	errElectionManagerAlreadyOpenOrClosed = errors.New("election manager is already open or closed")
 	errElectionManagerNotOpenOrClosed     = errors.New("election manager is not open or closed")
@@ -173,6 +196,8 @@ type electionManagerMetrics struct {
 	campaignCheckReplacementInstanceErrors tally.Counter
 	campaignCheckHasReplacementInstance    tally.Counter
 	campaignCheckUnexpectedShardTimes      tally.Counter
+	campaignCheckDamped                    tally.Counter
+	campaignCheckDampedNoLeader            tally.Counter
 	verifyLeaderErrors                     tally.Counter
 	verifyLeaderNotChanged                 tally.Counter
 	verifyCampaignDisabled                 tally.Counter
@@ -210,6 +235,8 @@ func newElectionManagerMetrics(scope tally.Scope) electionManagerMetrics {
 		campaignCheckReplacementInstanceErrors: campaignCheckScope.Counter("repl-instance-errors"),
 		campaignCheckHasReplacementInstance:    campaignCheckScope.Counter("has-repl-instance"),
 		campaignCheckUnexpectedShardTimes:      campaignCheckScope.Counter("unexpected-shard-times"),
+		campaignCheckDamped:                    campaignCheckScope.Counter("damped"),
+		campaignCheckDampedNoLeader:            campaignCheckScope.Counter("damped-no-leader"),
 		verifyLeaderErrors:                     verifyScope.Counter("leader-errors"),
 		verifyLeaderNotChanged:                 verifyScope.Counter("leader-not-changed"),
 		verifyCampaignDisabled:                 verifyScope.Counter("campaign-disabled"),
@@ -260,10 +287,12 @@ type electionManager struct {
 	flushTimesChecker          flushTimesChecker
 	campaignStateCheckInterval time.Duration
 	shardCutoffCheckOffset     time.Duration
+	damper                     *campaignDamper
 
 	state                  electionManagerState
 	doneCh                 chan struct{}
 	campaigning            int32
+	stateDetermined        int32
 	campaignStateWatchable watch.Watchable
 	electionKey            string
 	electionStateWatchable watch.Watchable
@@ -272,6 +301,10 @@ type electionManager struct {
 	goalStateWatchable     watch.Watchable
 	campaignIsEnabledFn    campaignIsEnabledFn
 	resignOnClose          int32
+	beforePromotionFn      BeforePromotionFn
+	promotionPreparedCh    chan struct{}
+	preparingPromotion     bool
+	promotionPrepared      bool
 	sleepFn                sleepFn
 	metrics                electionManagerMetrics
 }
@@ -284,6 +317,8 @@ func NewElectionManager(opts ElectionManagerOptions) ElectionManager {
 	campaignRetrier := retry.NewRetrier(opts.CampaignRetryOptions().SetForever(true))
 	changeRetrier := retry.NewRetrier(opts.ChangeRetryOptions().SetForever(true))
 	resignRetrier := retry.NewRetrier(opts.ResignRetryOptions().SetForever(true))
+	damper := newCampaignDamper(opts.CampaignDampingOptions(),
+		opts.ClockOptions().NowFn(), scope.SubScope("campaign-damping"))
 	mgr := &electionManager{
 		nowFn:                      opts.ClockOptions().NowFn(),
 		logger:                     instrumentOpts.Logger(),
@@ -301,6 +336,7 @@ func NewElectionManager(opts ElectionManagerOptions) ElectionManager {
 		flushTimesChecker:          newFlushTimesChecker(scope.SubScope("campaign-check")),
 		campaignStateCheckInterval: opts.CampaignStateCheckInterval(),
 		shardCutoffCheckOffset:     opts.ShardCutoffCheckOffset(),
+		damper:                     damper,
 		sleepFn:                    time.Sleep,
 		metrics:                    newElectionManagerMetrics(scope),
 	}
@@ -363,10 +399,29 @@ func (mgr *electionManager) ElectionState() ElectionState {
 	return mgr.electionStateWatchable.Get().(ElectionState)
 }
 
+func (mgr *electionManager) ElectionStateDetermined() bool {
+	return atomic.LoadInt32(&mgr.stateDetermined) == 1
+}
+
 func (mgr *electionManager) IsCampaigning() bool {
 	return mgr.campaignState() == campaignEnabled
 }
 
+func (mgr *electionManager) CampaignDampingStatus() CampaignDampingStatus {
+	return mgr.damper.Status()
+}
+
+func (mgr *electionManager) ClearCampaignDamping() {
+	mgr.damper.Clear()
+	mgr.logger.Info("campaign damping cleared")
+}
+
+func (mgr *electionManager) SetBeforePromotionFn(fn BeforePromotionFn) {
+	mgr.Lock()
+	mgr.beforePromotionFn = fn
+	mgr.Unlock()
+}
+
 func (mgr *electionManager) Resign(ctx context.Context) error {
 	mgr.RLock()
 	state := mgr.state
@@ -443,11 +498,15 @@ func (mgr *electionManager) watchGoalStateChanges(watch watch.Watch) {
 			return
 		case <-watch.C():
 			mgr.processGoalState(watch.Get().(goalState))
+		case <-mgr.promotionPreparedCh:
+			mgr.processPreparedPromotion(watch.Get().(goalState))
 		}
 	}
 }
 
 func (mgr *electionManager) processGoalState(goalState goalState) {
+	defer atomic.StoreInt32(&mgr.stateDetermined, 1)
+
 	currState := mgr.ElectionState()
 	newState := goalState.state
 	if currState == newState {
@@ -462,8 +521,64 @@ func (mgr *electionManager) processGoalState(goalState goalState) {
 		mgr.metrics.followerToPendingFollower.Inc(1)
 		return
 	}
+	if currState == FollowerState && newState == LeaderState && !mgr.preparePromotion() {
+		return
+	}
 	mgr.electionStateWatchable.Update(newState)
 	mgr.logger.Info(fmt.Sprintf("election state changed from %v to %v", currState, newState))
+
+	if currState != LeaderState && newState != LeaderState {
+		return
+	}
+	if mgr.damper.RecordFlap() {
+		opts := mgr.damper.opts
+		mgr.logger.Warn("leadership flapping, suppressing campaign once not leader",
+			zap.Int("maxFlaps", opts.MaxFlaps),
+			zap.Duration("window", opts.Window),
+			zap.Duration("backoff", opts.Backoff))
+	}
+}
+
+// preparePromotion returns whether a follower is prepared to be promoted to
+// leader. Otherwise the function called before the promotion is started in the
+// background, so the goal state changes are still processed meanwhile, and the
+// latest goal state is processed again once it returns.
+func (mgr *electionManager) preparePromotion() bool {
+	if mgr.promotionPrepared {
+		return true
+	}
+	mgr.RLock()
+	beforePromotionFn := mgr.beforePromotionFn
+	mgr.RUnlock()
+	if beforePromotionFn == nil {
+		return true
+	}
+	if mgr.preparingPromotion {
+		return false
+	}
+
+	mgr.preparingPromotion = true
+	mgr.Add(1)
+	go func(doneCh chan struct{}, preparedCh chan struct{}) {
+		defer mgr.Done()
+
+		beforePromotionFn()
+		select {
+		case preparedCh <- struct{}{}:
+		case <-doneCh:
+		}
+	}(mgr.doneCh, mgr.promotionPreparedCh)
+	return false
+}
+
+// processPreparedPromotion processes the latest goal state once the function
+// called before the promotion returned, which promotes the follower if it is
+// still meant to lead.
+func (mgr *electionManager) processPreparedPromotion(goalState goalState) {
+	mgr.preparingPromotion = false
+	mgr.promotionPrepared = true
+	mgr.processGoalState(goalState)
+	mgr.promotionPrepared = false
 }
 
 func (mgr *electionManager) verifyPendingFollower(watch watch.Watch) {
@@ -566,17 +681,21 @@ func (mgr *electionManager) campaignState() campaignState {
 }
 
 func (mgr *electionManager) checkCampaignState() {
-	enabled, err := mgr.campaignIsEnabledFn()
+	enabled, err := mgr.shouldCampaign()
 	if err != nil {
 		mgr.metrics.campaignCheckErrors.Inc(1)
 		return
 	}
 	newState := newCampaignState(enabled)
 	currState := mgr.campaignState()
-	if currState == newState {
-		return
+	if currState != newState {
+		mgr.processCampaignStateChange(newState)
+	}
+	// NB: the instance remains a follower while campaigning is disabled so
+	// there is no need to wait for the campaign to determine the election state.
+	if mgr.campaignState() == campaignDisabled {
+		atomic.StoreInt32(&mgr.stateDetermined, 1)
 	}
-	mgr.processCampaignStateChange(newState)
 }
 
 func (mgr *electionManager) checkCampaignStateLoop() {
@@ -609,7 +728,7 @@ func (mgr *electionManager) processCampaignStateChange(newState campaignState) {
 			campaignErr error
 		)
 		shouldResignFn := func(int) bool {
-			enabled, campaignErr = mgr.campaignIsEnabledFn()
+			enabled, campaignErr = mgr.shouldCampaign()
 			if campaignErr != nil {
 				mgr.metrics.campaignCheckErrors.Inc(1)
 				return false
@@ -624,6 +743,42 @@ func (mgr *electionManager) processCampaignStateChange(newState campaignState) {
 	}
 }
 
+// shouldCampaign returns true if campaigning is enabled and is not suppressed
+// by the damping of leadership flaps. The leader keeps campaigning while
+// suppressed so the damping never causes a flap by itself, and a suppressed
+// instance still campaigns when the election has no leader so the damping of
+// every replica never leaves the shard set without a leader.
+func (mgr *electionManager) shouldCampaign() (bool, error) {
+	enabled, err := mgr.campaignIsEnabledFn()
+	if err != nil || !enabled {
+		return enabled, err
+	}
+	if !mgr.damper.Suppressed() || mgr.ElectionState() == LeaderState {
+		return true, nil
+	}
+	if mgr.electionHasNoLeader() {
+		mgr.metrics.campaignCheckDampedNoLeader.Inc(1)
+		return true, nil
+	}
+	mgr.metrics.campaignCheckDamped.Inc(1)
+	return false, nil
+}
+
+// electionHasNoLeader returns true if the leader service reports that the
+// election has no leader. Errors determining the leader are treated as the
+// election having a leader, keeping the damping in effect.
+func (mgr *electionManager) electionHasNoLeader() bool {
+	ld, err := mgr.leaderService.Leader(mgr.electionKey)
+	if err == leader.ErrNoLeader {
+		return true
+	}
+	if err != nil {
+		mgr.logError("error determining the leader", err)
+		return false
+	}
+	return ld == ""
+}
+
 func (mgr *electionManager) campaignIsEnabled() (bool, error) {
 	// If the current instance is not found in the placement, campaigning is disabled.
 	shards, err := mgr.placementManager.Shards()
@@ -839,7 +994,11 @@ func (mgr *electionManager) setGoalStateWithLock(newState ElectionState) {
 func (mgr *electionManager) resetWithLock() {
 	mgr.state = electionManagerNotOpen
 	mgr.doneCh = make(chan struct{})
+	mgr.promotionPreparedCh = make(chan struct{})
+	mgr.preparingPromotion = false
+	mgr.promotionPrepared = false
 	mgr.campaigning = 0
+	mgr.stateDetermined = 0
 	mgr.campaignStateWatchable = watch.NewWatchable()
 	mgr.campaignStateWatchable.Update(campaignDisabled)
 	mgr.electionStateWatchable = watch.NewWatchable()
@@ -875,6 +1034,7 @@ func (mgr *electionManager) reportMetrics() {
 			mgr.metrics.campaignState.Update(float64(campaignState))
 			mgr.metrics.campaigning.Update(float64(campaigning))
 			mgr.metrics.resignOnClose.Update(float64(resignOnClose))
+			mgr.damper.reportMetrics()
 		case <-mgr.doneCh:
 			ticker.Stop()
 			return
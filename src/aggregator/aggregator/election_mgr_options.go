@@ -118,6 +118,12 @@ type ElectionManagerOptions interface {
 	// The cutoff time is applied in order to stop campaignining when necessary before all
 	// shards are cut off avoiding incomplete data to be flushed.
 	ShardCutoffCheckOffset() time.Duration
+
+	// SetCampaignDampingOptions sets the options damping leadership flaps.
+	SetCampaignDampingOptions(value CampaignDampingOptions) ElectionManagerOptions
+
+	// CampaignDampingOptions returns the options damping leadership flaps.
+	CampaignDampingOptions() CampaignDampingOptions
 }
 
 type electionManagerOptions struct {
@@ -134,6 +140,7 @@ type electionManagerOptions struct {
 	flushTimesManager          FlushTimesManager
 	campaignStateCheckInterval time.Duration
 	shardCutoffCheckOffset     time.Duration
+	campaignDampingOpts        CampaignDampingOptions
 }
 
 // NewElectionManagerOptions create a new set of options for the election manager.
@@ -280,3 +287,13 @@ func (o *electionManagerOptions) SetShardCutoffCheckOffset(value time.Duration)
 func (o *electionManagerOptions) ShardCutoffCheckOffset() time.Duration {
 	return o.shardCutoffCheckOffset
 }
+
+func (o *electionManagerOptions) SetCampaignDampingOptions(value CampaignDampingOptions) ElectionManagerOptions {
+	opts := *o
+	opts.campaignDampingOpts = value
+	return &opts
+}
+
+func (o *electionManagerOptions) CampaignDampingOptions() CampaignDampingOptions {
+	return o.campaignDampingOpts
+}
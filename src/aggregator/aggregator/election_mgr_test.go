@@ -32,6 +32,7 @@ import (
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader"
 	"github.com/m3db/m3/src/cluster/services/leader/campaign"
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/x/retry"
@@ -154,6 +155,90 @@ func TestElectionManagerElectionState(t *testing.T) {
 	require.Equal(t, LeaderState, mgr.ElectionState())
 }
 
+func TestElectionManagerElectionStateDetermined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testElectionManagerOptions(t, ctrl)
+	mgr := NewElectionManager(opts).(*electionManager)
+	require.False(t, mgr.ElectionStateDetermined())
+
+	// Campaigning is enabled so the state is determined by the campaign.
+	mgr.campaignIsEnabledFn = func() (bool, error) { return true, nil }
+	mgr.checkCampaignState()
+	require.False(t, mgr.ElectionStateDetermined())
+	mgr.processGoalState(goalState{state: LeaderState})
+	require.True(t, mgr.ElectionStateDetermined())
+
+	// Campaigning is disabled so the instance remains a follower.
+	mgr.Lock()
+	mgr.resetWithLock()
+	mgr.Unlock()
+	require.False(t, mgr.ElectionStateDetermined())
+	mgr.campaignIsEnabledFn = func() (bool, error) { return false, nil }
+	mgr.checkCampaignState()
+	require.True(t, mgr.ElectionStateDetermined())
+}
+
+func TestElectionManagerBeforePromotionFn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testElectionManagerOptions(t, ctrl)
+	mgr := NewElectionManager(opts).(*electionManager)
+	var statesBeforePromotion []ElectionState
+	mgr.SetBeforePromotionFn(func() {
+		statesBeforePromotion = append(statesBeforePromotion, mgr.ElectionState())
+	})
+
+	// The function is called in the background before a follower is promoted
+	// to leader.
+	require.Equal(t, FollowerState, mgr.ElectionState())
+	mgr.processGoalState(goalState{state: LeaderState})
+	<-mgr.promotionPreparedCh
+	require.Equal(t, FollowerState, mgr.ElectionState())
+	mgr.processPreparedPromotion(goalState{state: LeaderState})
+	require.Equal(t, LeaderState, mgr.ElectionState())
+	require.Equal(t, []ElectionState{FollowerState}, statesBeforePromotion)
+
+	// The function is not called when a pending follower becomes leader again.
+	mgr.processGoalState(goalState{state: PendingFollowerState})
+	mgr.processGoalState(goalState{state: LeaderState})
+	require.Equal(t, []ElectionState{FollowerState}, statesBeforePromotion)
+}
+
+func TestElectionManagerBeforePromotionFnGoalStateChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testElectionManagerOptions(t, ctrl)
+	mgr := NewElectionManager(opts).(*electionManager)
+	var (
+		calls   int
+		blockCh = make(chan struct{})
+	)
+	mgr.SetBeforePromotionFn(func() {
+		calls++
+		<-blockCh
+	})
+
+	// The goal state changes are processed while the function runs, and the
+	// function is only called once for successive promotions.
+	mgr.processGoalState(goalState{state: LeaderState})
+	mgr.processGoalState(goalState{state: FollowerState})
+	mgr.processGoalState(goalState{state: LeaderState})
+	require.Equal(t, FollowerState, mgr.ElectionState())
+	close(blockCh)
+	<-mgr.promotionPreparedCh
+	require.Equal(t, 1, calls)
+
+	// The follower is not promoted if it is no longer meant to lead.
+	mgr.processPreparedPromotion(goalState{state: FollowerState})
+	require.Equal(t, FollowerState, mgr.ElectionState())
+	require.False(t, mgr.promotionPrepared)
+	require.False(t, mgr.preparingPromotion)
+}
+
 func TestElectionManagerIsCampaigning(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -909,6 +994,63 @@ func TestElectionManagerCheckCampaignStateLoop(t *testing.T) {
 	require.NoError(t, mgr.Close())
 }
 
+func TestElectionManagerCheckCampaignStateDamped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testElectionManagerOptions(t, ctrl).
+		SetCampaignDampingOptions(CampaignDampingOptions{
+			Window:   time.Minute,
+			MaxFlaps: 1,
+			Backoff:  time.Hour,
+		})
+	leaderService := services.NewMockLeaderService(ctrl)
+	leaderService.EXPECT().
+		Campaign(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(string, services.CampaignOptions) (<-chan campaign.Status, error) {
+			return make(chan campaign.Status), nil
+		}).
+		AnyTimes()
+	leaderService.EXPECT().Resign(gomock.Any()).Return(nil).AnyTimes()
+	var (
+		currLeader = "other"
+		leaderErr  error
+	)
+	leaderService.EXPECT().
+		Leader(gomock.Any()).
+		DoAndReturn(func(string) (string, error) {
+			return currLeader, leaderErr
+		}).
+		AnyTimes()
+	opts = opts.SetLeaderService(leaderService)
+	mgr := NewElectionManager(opts).(*electionManager)
+	mgr.campaignIsEnabledFn = func() (bool, error) { return true, nil }
+
+	// The leader keeps campaigning while suppressed.
+	mgr.processGoalState(goalState{state: LeaderState})
+	require.True(t, mgr.CampaignDampingStatus().Suppressed)
+	mgr.checkCampaignState()
+	require.Equal(t, campaignEnabled, mgr.campaignState())
+
+	// Campaigning stops once leadership is lost to another instance.
+	mgr.processGoalState(goalState{state: PendingFollowerState})
+	mgr.checkCampaignState()
+	require.Equal(t, campaignDisabled, mgr.campaignState())
+
+	// Campaigning resumes while suppressed if the election has no leader.
+	currLeader, leaderErr = "", leader.ErrNoLeader
+	enabled, err := mgr.shouldCampaign()
+	require.NoError(t, err)
+	require.True(t, enabled)
+	require.True(t, mgr.CampaignDampingStatus().Suppressed)
+
+	// Clearing the damping lets the instance campaign again.
+	mgr.ClearCampaignDamping()
+	require.False(t, mgr.CampaignDampingStatus().Suppressed)
+	mgr.checkCampaignState()
+	require.Equal(t, campaignEnabled, mgr.campaignState())
+}
+
 func TestElectionManagerCampaignIsEnabledInstanceNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
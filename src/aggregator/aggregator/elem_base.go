@@ -43,6 +43,7 @@ import (
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/metrics/transformation"
 	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
 	xtime "github.com/m3db/m3/src/x/time"
 )
 
@@ -68,6 +69,10 @@ var (
 	errElemClosed                = errors.New("element is closed")
 	errAggregationClosed         = errors.New("aggregation is closed")
 	errDuplicateForwardingSource = errors.New("duplicate forwarding source")
+	errForwardedHistogramUpdate  = errors.New("forwarded histograms do not support updating values")
+
+	// Histograms emit their cumulative buckets alongside these by default.
+	defaultHistogramAggregationTypes = maggregation.Types{maggregation.Sum, maggregation.Count}
 )
 
 // isEarlierThanFn determines whether the timestamps of the metrics in a given
@@ -114,7 +119,7 @@ type metricElem interface {
 	AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) error
 
 	// AddMetric adds a metric value at a given timestamp.
-	AddValue(timestamp time.Time, value float64, annotation []byte) error
+	AddValue(timestamp time.Time, value float64, annotation []byte, exemplars []metric.Exemplar) error
 
 	// AddUnique adds a metric value from a given source at a given timestamp.
 	// If previous values from the same source/version have already been added to the
@@ -133,6 +138,9 @@ type metricElem interface {
 		onForwardedFlushedFn onForwardingElemFlushedFn,
 	) bool
 
+	// InProgressValues returns the values of the aggregations that have not expired yet.
+	InProgressValues() []transformation.Datapoint
+
 	// MarkAsTombstoned marks an element as tombstoned, which means this element
 	// will be deleted once its aggregated values have been flushed.
 	MarkAsTombstoned()
@@ -150,6 +158,9 @@ type ElemData struct {
 	NumForwardedTimes  int
 	IDPrefixSuffixType IDPrefixSuffixType
 	ResendEnabled      bool
+
+	// annotationBudget accounts for the annotations retained by the element.
+	annotationBudget *annotationBudget
 }
 
 // nolint: maligned
@@ -172,6 +183,9 @@ type elemBase struct {
 	metrics                         elemMetrics
 	resendEnabled                   bool
 	bufferForPastTimedMetricFn      BufferForPastTimedMetricFn
+	annotationBudget                *annotationBudget
+	transformationFlags             transformation.FeatureFlags
+	contributor                     *contributorsSketch // the contributor of the series forwarded to rollups.
 
 	// Mutable states.
 	tombstoned           bool
@@ -195,7 +209,8 @@ func (v valuesByTime) previousTimestamp(t xtime.UnixNano) xtime.UnixNano {
 }
 
 type elemMetrics struct {
-	updatedValues tally.Counter
+	updatedValues                 tally.Counter
+	counterResetDetectionDisabled tally.Counter
 }
 
 func newElemBase(opts Options) elemBase {
@@ -205,7 +220,8 @@ func newElemBase(opts Options) elemBase {
 		aggTypesOpts: opts.AggregationTypesOptions(),
 		aggOpts:      raggregation.NewOptions(opts.InstrumentOptions()),
 		metrics: elemMetrics{
-			updatedValues: scope.Counter("updated-values"),
+			updatedValues:                 scope.Counter("updated-values"),
+			counterResetDetectionDisabled: scope.Counter("counter-reset-detection-disabled"),
 		},
 		bufferForPastTimedMetricFn: opts.BufferForPastTimedMetricFn(),
 	}
@@ -230,9 +246,44 @@ func (e *elemBase) resetSetData(data ElemData, useDefaultAggregation bool) error
 	e.closed = false
 	e.idPrefixSuffixType = data.IDPrefixSuffixType
 	e.resendEnabled = data.ResendEnabled
+	e.annotationBudget = data.annotationBudget
+	e.transformationFlags = transformation.FeatureFlags{}
+	if parsed.HasDerivativeTransform {
+		e.transformationFlags = transformationFeatureFlags(e.opts.FeatureFlagBundlesParsed(), data.ID)
+	}
+	e.contributor = nil
+	if parsed.HasRollup && e.opts.EmitContributors() {
+		// NB: the contributor of the series is forwarded alongside its values so
+		// the rollup can count the distinct contributors of the series it rolls up.
+		value, ok, err := serialize.TagValueFromEncodedTagsFast(data.ID, e.opts.ContributorsTag())
+		if err == nil && ok {
+			e.contributor = newContributorsSketch(value)
+		}
+	}
+	return nil
+}
+
+// retainedAnnotation returns the annotation to add to an aggregation that
+// currently retains the given annotation, which is nil if retaining it would
+// exceed the annotation budget of the shard.
+func (e *elemBase) retainedAnnotation(curr, annotation []byte) []byte {
+	if len(annotation) == 0 || e.annotationBudget.Reserve(len(curr), len(annotation)) {
+		return annotation
+	}
 	return nil
 }
 
+// retainedExemplars returns the exemplars retained by an aggregation that
+// currently retains the given exemplars once the incoming ones are added.
+func (e *elemBase) retainedExemplars(curr, exemplars []metric.Exemplar) []metric.Exemplar {
+	return addExemplars(curr, exemplars, e.opts.MaxExemplarsPerAggregation())
+}
+
+// releaseAnnotation releases the annotation retained by a closed aggregation.
+func (e *elemBase) releaseAnnotation(annotation []byte) {
+	e.annotationBudget.Release(len(annotation))
+}
+
 func (e *elemBase) SetForwardedCallbacks(
 	writeFn writeForwardedMetricFn,
 	onDoneFn onForwardedAggregationDoneFn,
@@ -327,6 +378,9 @@ func (e timerElemBase) TypeStringFor(aggTypesOpts maggregation.TypesOptions, agg
 func (e timerElemBase) ElemPool(opts Options) TimerElemPool { return opts.TimerElemPool() }
 
 func (e timerElemBase) NewAggregation(opts Options, aggOpts raggregation.Options) timerAggregation {
+	if sketchOpts := opts.TimerSketchOptions(); sketchOpts != nil {
+		return newTimerAggregation(raggregation.NewSketchTimer(sketchOpts, aggOpts))
+	}
 	newTimer := raggregation.NewTimer(e.quantiles, opts.StreamOptions(), aggOpts)
 	return newTimerAggregation(newTimer)
 }
@@ -399,6 +453,40 @@ func (e *gaugeElemBase) ResetSetData(
 
 func (e *gaugeElemBase) Close() {}
 
+type histogramElemBase struct{}
+
+func (e histogramElemBase) Type() metric.Type { return metric.HistogramType }
+
+func (e histogramElemBase) FullPrefix(opts Options) []byte { return opts.FullHistogramPrefix() }
+
+func (e histogramElemBase) DefaultAggregationTypes(_ maggregation.TypesOptions) maggregation.Types {
+	return defaultHistogramAggregationTypes
+}
+
+func (e histogramElemBase) TypeStringFor(aggTypesOpts maggregation.TypesOptions, aggType maggregation.Type) []byte {
+	// Histograms share the type strings of timers as both support quantiles.
+	return aggTypesOpts.TypeStringForTimer(aggType)
+}
+
+func (e histogramElemBase) ElemPool(opts Options) HistogramElemPool { return opts.HistogramElemPool() }
+
+func (e histogramElemBase) NewAggregation(_ Options, aggOpts raggregation.Options) histogramAggregation {
+	return newHistogramAggregation(raggregation.NewHistogram(aggOpts))
+}
+
+func (e *histogramElemBase) ResetSetData(
+	_ maggregation.TypesOptions,
+	aggTypes maggregation.Types,
+	_ bool,
+) error {
+	if !aggTypes.IsValidForHistogram() {
+		return fmt.Errorf("invalid aggregation types %s for histogram", aggTypes.String())
+	}
+	return nil
+}
+
+func (e *histogramElemBase) Close() {}
+
 // nolint: maligned
 type parsedPipeline struct {
 	// Whether the source pipeline contains derivative transformations at its head.
@@ -44,7 +44,7 @@ func mustNewOp(t require.TestingT, ttype transformation.Type) transformation.Op
 }
 
 func TestElemBaseID(t *testing.T) {
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	require.NoError(t, e.resetSetData(testCounterElemData, false))
 	require.Equal(t, testCounterID, e.ID())
 }
@@ -86,7 +86,7 @@ func TestElemBaseResetSetData(t *testing.T) {
 			},
 		}),
 	}
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	elemData := testCounterElemData
 	elemData.AggTypes = testAggregationTypesExpensive
 	elemData.NumForwardedTimes = 3
@@ -112,7 +112,7 @@ func TestElemBaseResetSetDataNoRollup(t *testing.T) {
 			Transformation: pipeline.TransformationOp{Type: transformation.Absolute},
 		},
 	})
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	elemData := testCounterElemData
 	elemData.Pipeline = pipelineNoRollup
 	err := e.resetSetData(elemData, false)
@@ -126,7 +126,7 @@ func TestElemBaseForwardedIDWithDefaultPipeline(t *testing.T) {
 }
 
 func TestElemBaseForwardedIDWithCustomPipeline(t *testing.T) {
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	require.NoError(t, e.resetSetData(testCounterElemData, false))
 	fid, ok := e.ForwardedID()
 	require.True(t, ok)
@@ -140,7 +140,7 @@ func TestElemBaseForwardedAggregationKeyWithDefaultPipeline(t *testing.T) {
 }
 
 func TestElemBaseForwardedAggregationKeyWithCustomPipeline(t *testing.T) {
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	elemData := testCounterElemData
 	elemData.NumForwardedTimes = 3
 	require.NoError(t, e.resetSetData(elemData, false))
@@ -164,7 +164,7 @@ func TestElemBaseForwardedAggregationKeyWithCustomPipeline(t *testing.T) {
 }
 
 func TestElemBaseMarkAsTombStoned(t *testing.T) {
-	e := &elemBase{}
+	e := &elemBase{opts: newTestOptions()}
 	require.False(t, e.tombstoned)
 
 	// Marking a closed element tombstoned has no impact.
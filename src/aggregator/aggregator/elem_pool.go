@@ -69,6 +69,21 @@ type GaugeElemPool interface {
 	Put(value *GaugeElem)
 }
 
+// HistogramElemAlloc allocates a new histogram element.
+type HistogramElemAlloc func() *HistogramElem
+
+// HistogramElemPool provides a pool of histogram elements.
+type HistogramElemPool interface {
+	// Init initializes the histogram element pool.
+	Init(alloc HistogramElemAlloc)
+
+	// Get gets a histogram element from the pool.
+	Get() *HistogramElem
+
+	// Put returns a histogram element to the pool.
+	Put(value *HistogramElem)
+}
+
 type counterElemPool struct {
 	pool pool.ObjectPool
 }
@@ -137,3 +152,26 @@ func (p *gaugeElemPool) Get() *GaugeElem {
 func (p *gaugeElemPool) Put(value *GaugeElem) {
 	p.pool.Put(value)
 }
+
+type histogramElemPool struct {
+	pool pool.ObjectPool
+}
+
+// NewHistogramElemPool creates a new pool for histogram elements.
+func NewHistogramElemPool(opts pool.ObjectPoolOptions) HistogramElemPool {
+	return &histogramElemPool{pool: pool.NewObjectPool(opts)}
+}
+
+func (p *histogramElemPool) Init(alloc HistogramElemAlloc) {
+	p.pool.Init(func() interface{} {
+		return alloc()
+	})
+}
+
+func (p *histogramElemPool) Get() *HistogramElem {
+	return p.pool.Get().(*HistogramElem)
+}
+
+func (p *histogramElemPool) Put(value *HistogramElem) {
+	p.pool.Put(value)
+}
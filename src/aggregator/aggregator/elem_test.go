@@ -43,12 +43,14 @@ import (
 	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
 	testCounterID                 = id.RawID("testCounter")
 	testBatchTimerID              = id.RawID("testBatchTimer")
 	testGaugeID                   = id.RawID("testGauge")
+	testHistogramID               = id.RawID("testHistogram")
 	testAnnot                     = []byte("testAnnotation")
 	testStoragePolicy             = policy.NewStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour)
 	testAggregationTypes          = maggregation.Types{maggregation.Mean, maggregation.Sum}
@@ -87,6 +89,13 @@ var (
 		ID:       testGaugeID,
 		GaugeVal: 123.456,
 	}
+	testHistogram = unaggregated.MetricUnion{
+		Type:                       metric.HistogramType,
+		ID:                         testHistogramID,
+		HistogramBucketUpperBounds: []float64{1, 10, math.Inf(1)},
+		HistogramBucketCounts:      []int64{2, 3, 1},
+		HistogramSum:               30,
+	}
 	testPipeline = applied.NewPipeline([]applied.OpUnion{
 		{
 			Type:           pipeline.TransformationOpType,
@@ -207,6 +216,34 @@ func TestCounterResetSetDataNoRollup(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCounterElemAnnotationBudget(t *testing.T) {
+	budget := newAnnotationBudget(3, tally.NoopScope)
+	elemData := testCounterElemData
+	elemData.annotationBudget = budget
+	e, err := NewCounterElem(elemData, newTestOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, e.AddValue(testTimestamps[0], 1, []byte{1, 2}, nil))
+	require.Equal(t, int64(2), budget.Bytes())
+
+	// Replacing the annotation of an aggregation only accounts for the difference.
+	require.NoError(t, e.AddValue(testTimestamps[1], 1, []byte{3, 4}, nil))
+	require.Equal(t, int64(2), budget.Bytes())
+	require.Equal(t, []byte{3, 4}, e.values[0].lockedAgg.aggregation.Annotation())
+
+	// The annotation of the next aggregation exceeds the budget and is dropped,
+	// while the value is still aggregated.
+	require.NoError(t, e.AddValue(testTimestamps[2], 1, []byte{5, 6}, nil))
+	require.Equal(t, 2, len(e.values))
+	require.Equal(t, int64(2), budget.Bytes())
+	require.Empty(t, e.values[1].lockedAgg.aggregation.Annotation())
+	require.Equal(t, int64(1), e.values[1].lockedAgg.aggregation.Sum())
+
+	// Closing the element releases the retained annotations.
+	e.Close()
+	require.Equal(t, int64(0), budget.Bytes())
+}
+
 func TestCounterElemAddUnion(t *testing.T) {
 	e, err := NewCounterElem(testCounterElemData, newTestOptions())
 	require.NoError(t, err)
@@ -249,6 +286,55 @@ func TestCounterElemAddUnion(t *testing.T) {
 	require.Equal(t, errElemClosed, e.AddUnion(testTimestamps[2], testCounter))
 }
 
+func TestCounterElemExemplars(t *testing.T) {
+	elemData := testCounterElemData
+	elemData.Pipeline = applied.DefaultPipeline
+	opts := newTestOptions().SetMaxExemplarsPerAggregation(2)
+	e, err := NewCounterElem(elemData, opts)
+	require.NoError(t, err)
+
+	// Add a counter metric with more exemplars than retained.
+	counter := testCounter
+	counter.Exemplars = []metric.Exemplar{
+		{TraceID: []byte("trace1"), Value: 1, TimeNanos: 100},
+		{TraceID: []byte("trace2"), Value: 2, TimeNanos: 300},
+		{TraceID: []byte("trace3"), Value: 3, TimeNanos: 200},
+	}
+	require.NoError(t, e.AddUnion(testTimestamps[0], counter))
+	require.Equal(t, 1, len(e.values))
+	expected := []metric.Exemplar{
+		{TraceID: []byte("trace3"), Value: 3, TimeNanos: 200},
+		{TraceID: []byte("trace2"), Value: 2, TimeNanos: 300},
+	}
+	require.Equal(t, expected, e.values[0].lockedAgg.exemplars)
+
+	// Exemplars older than all retained ones are dropped.
+	require.NoError(t, e.AddValue(testTimestamps[1], 4, nil, []metric.Exemplar{
+		{TraceID: []byte("trace4"), Value: 4, TimeNanos: 150},
+	}))
+	require.Equal(t, expected, e.values[0].lockedAgg.exemplars)
+
+	// Retained exemplars do not alias the incoming ones.
+	newer := []metric.Exemplar{{TraceID: []byte("trace5"), Value: 5, TimeNanos: 400}}
+	require.NoError(t, e.AddValue(testTimestamps[1], 5, nil, newer))
+	newer[0].TraceID[0] = 'x'
+	expected = []metric.Exemplar{
+		{TraceID: []byte("trace5"), Value: 5, TimeNanos: 400},
+		{TraceID: []byte("trace2"), Value: 2, TimeNanos: 300},
+	}
+	require.Equal(t, expected, e.values[0].lockedAgg.exemplars)
+
+	// The retained exemplars are flushed with the aggregated values.
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, _ := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[1], isStandardMetricEarlierThan,
+		standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, 1, len(*localRes))
+	require.Equal(t, expected, (*localRes)[0].exemplars)
+	require.Equal(t, 0, len(e.values))
+}
+
 func TestCounterElemAddUnionWithCustomAggregation(t *testing.T) {
 	elemData := testCounterElemData
 	elemData.AggTypes = testAggregationTypesExpensive
@@ -429,6 +515,102 @@ func TestCounterElemAddUniqueWithCustomAggregation(t *testing.T) {
 		metadata.ForwardMetadata{SourceID: 1376}))
 }
 
+func TestCounterElemConsumeWithContributors(t *testing.T) {
+	elemData := testCounterElemData
+	elemData.Pipeline = applied.DefaultPipeline
+	opts := newTestOptions().SetEmitContributors(true)
+	e, err := NewCounterElem(elemData, opts)
+	require.NoError(t, err)
+
+	// Add values forwarded by two sources, whose series share a contributor.
+	contributors1 := newContributorsSketch([]byte("host1"))
+	contributors2 := newContributorsSketch([]byte("host2"))
+	contributors2.add([]byte("host1"))
+	require.NoError(t, e.AddUnique(testTimestamps[0],
+		aggregated.ForwardedMetric{Values: []float64{12, 14}, Contributors: contributors1.bytes()},
+		metadata.ForwardMetadata{SourceID: 1234}))
+	require.NoError(t, e.AddUnique(testTimestamps[1],
+		aggregated.ForwardedMetric{Values: []float64{20}, Contributors: contributors2.bytes()},
+		metadata.ForwardMetadata{SourceID: 5678}))
+	require.Equal(t, 2, e.values[0].lockedAgg.sources)
+
+	// Invalid contributors are rejected.
+	require.Equal(t, errInvalidContributorsSketch, e.AddUnique(testTimestamps[1],
+		aggregated.ForwardedMetric{Values: []float64{20}, Contributors: []byte{3}},
+		metadata.ForwardMetadata{SourceID: 9012}))
+
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[1], isStandardMetricEarlierThan,
+		standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, 0, len(*forwardRes))
+	require.Equal(t, []testLocalMetricWithMetadata{
+		{
+			idPrefix:  opts.FullCounterPrefix(),
+			id:        testCounterID,
+			timeNanos: testAlignedStarts[1],
+			value:     46,
+			sp:        testStoragePolicy,
+		},
+		{
+			idPrefix:  opts.FullCounterPrefix(),
+			id:        testCounterID,
+			idSuffix:  []byte("_contributors"),
+			timeNanos: testAlignedStarts[1],
+			value:     2,
+			sp:        testStoragePolicy,
+		},
+	}, *localRes)
+
+	// No contributors are emitted for windows whose values carry none.
+	*localRes = nil
+	require.NoError(t, e.AddUnique(testTimestamps[2],
+		aggregated.ForwardedMetric{Values: []float64{30}},
+		metadata.ForwardMetadata{SourceID: 1234}))
+	require.Len(t, e.values[0].lockedAgg.sourcesSeen, 2)
+	require.Equal(t, 1, e.values[0].lockedAgg.sources)
+	require.False(t, e.Consume(testAlignedStarts[2], isStandardMetricEarlierThan,
+		standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, 1, len(*localRes))
+}
+
+func TestCounterElemForwardsContributor(t *testing.T) {
+	elemData := testCounterElemData
+	elemData.ID = testEncodedTags(t, "__name__", "requests", "host", "host1")
+	elemData.Pipeline = applied.NewPipeline([]applied.OpUnion{
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+
+	for _, emitContributors := range []bool{false, true} {
+		opts := newTestOptions().SetEmitContributors(emitContributors)
+		e, err := NewCounterElem(elemData, opts)
+		require.NoError(t, err)
+		require.NoError(t, e.AddUnion(testTimestamps[0], testCounter))
+
+		localFn, _ := testFlushLocalMetricFn()
+		forwardFn, forwardRes := testFlushForwardedMetricFn()
+		onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+		require.False(t, e.Consume(testAlignedStarts[1], isStandardMetricEarlierThan,
+			standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+		require.Equal(t, 1, len(*forwardRes))
+
+		contributors := (*forwardRes)[0].contributors
+		if !emitContributors {
+			require.Nil(t, contributors)
+			continue
+		}
+		require.NotNil(t, contributors)
+		require.Equal(t, newContributorsSketch([]byte("host1")).bytes(), contributors.bytes())
+	}
+}
+
 func TestCounterElemConsumeDefaultAggregationDefaultPipeline(t *testing.T) {
 	isEarlierThanFn := isStandardMetricEarlierThan
 	timestampNanosFn := standardMetricTimestampNanos
@@ -686,6 +868,36 @@ func TestCounterElemClose(t *testing.T) {
 	require.NotNil(t, e.values)
 }
 
+func TestCounterElemInProgressValues(t *testing.T) {
+	alignedstartAtNanos := testAlignedStarts[:len(testAlignedStarts)-1]
+
+	// Values are flushed with a prefix and as such are not written under the element ID.
+	e := testCounterElem(alignedstartAtNanos, testCounterVals, maggregation.DefaultTypes,
+		applied.DefaultPipeline, newTestOptions())
+	require.Empty(t, e.InProgressValues())
+
+	e.idPrefixSuffixType = NoPrefixNoSuffix
+	require.Equal(t, []transformation.Datapoint{
+		{TimeNanos: alignedstartAtNanos[0], Value: float64(testCounter.CounterVal)},
+		{TimeNanos: alignedstartAtNanos[1], Value: float64(testCounter.CounterVal)},
+	}, e.InProgressValues())
+
+	// Closed aggregations have been flushed already.
+	e.values[0].lockedAgg.closed = true
+	require.Equal(t, []transformation.Datapoint{
+		{TimeNanos: alignedstartAtNanos[1], Value: float64(testCounter.CounterVal)},
+	}, e.InProgressValues())
+
+	// Rolled up values are written under a different ID.
+	e = testCounterElem(alignedstartAtNanos, testCounterVals, maggregation.DefaultTypes,
+		testPipeline, newTestOptions())
+	e.idPrefixSuffixType = NoPrefixNoSuffix
+	require.Empty(t, e.InProgressValues())
+
+	e.Close()
+	require.Empty(t, e.InProgressValues())
+}
+
 func TestCounterFindOrCreateNoSourceSet(t *testing.T) {
 	e, err := NewCounterElem(testCounterElemData, newTestOptions())
 	require.NoError(t, err)
@@ -1657,7 +1869,7 @@ func TestGaugeElemConsumeResendBuffer(t *testing.T) {
 
 	// Update the first value after flushing
 	updatedVal := testGaugeVals[0] - 1.0
-	require.NoError(t, e.AddValue(time.Unix(0, testAlignedStarts[0]), updatedVal, nil))
+	require.NoError(t, e.AddValue(time.Unix(0, testAlignedStarts[0]), updatedVal, nil, nil))
 	localFn, localRes = testFlushLocalMetricFn()
 	forwardFn, forwardRes = testFlushForwardedMetricFn()
 	onForwardedFlushedFn, onForwardedFlushedRes = testOnForwardedFlushedFn()
@@ -1876,6 +2088,89 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	require.Equal(t, 0, len(e.values))
 }
 
+func TestGaugeElemConsumeCounterResetDetection(t *testing.T) {
+	alignedstartAtNanos := []int64{
+		time.Unix(210, 0).UnixNano(),
+		time.Unix(220, 0).UnixNano(),
+		time.Unix(230, 0).UnixNano(),
+		time.Unix(240, 0).UnixNano(),
+	}
+	// The source counter is reset between the second and third windows.
+	gaugeVals := []float64{100.0, 300.0, 50.0}
+	data := testGaugeData
+	data.AggTypes = maggregation.Types{maggregation.Last}
+	data.Pipeline = applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Increase},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		flags    FeatureFlagConfigurations
+		sources  int
+		expected []float64
+	}{
+		{
+			name:     "disabled",
+			expected: []float64{100.0, 200.0},
+		},
+		{
+			name: "enabled",
+			flags: FeatureFlagConfigurations{
+				{Flags: FlagBundle{CounterResetDetection: true}},
+			},
+			expected: []float64{100.0, 200.0, 50.0},
+		},
+		{
+			name: "enabled with multiple sources",
+			flags: FeatureFlagConfigurations{
+				{Flags: FlagBundle{CounterResetDetection: true}},
+			},
+			sources:  2,
+			expected: []float64{100.0, 200.0},
+		},
+		{
+			name: "filter not matched",
+			flags: FeatureFlagConfigurations{
+				{
+					Flags:  FlagBundle{CounterResetDetection: true},
+					Filter: map[string]string{"service": "other"},
+				},
+			},
+			expected: []float64{100.0, 200.0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := newTestOptions().SetFeatureFlagBundlesParsed(test.flags.Parse())
+			e := testGaugeElemWithData(t, alignedstartAtNanos[:3], gaugeVals, data, opts)
+			e.values[0].lockedAgg.sources = test.sources
+
+			localFn, _ := testFlushLocalMetricFn()
+			forwardFn, forwardRes := testFlushForwardedMetricFn()
+			onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+			require.False(t, e.Consume(alignedstartAtNanos[3], isStandardMetricEarlierThan,
+				standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+
+			values := make([]float64, 0, len(*forwardRes))
+			for _, res := range *forwardRes {
+				values = append(values, res.value)
+			}
+			require.Equal(t, test.expected, values)
+		})
+	}
+}
+
 func TestGaugeElemResendSumReset(t *testing.T) {
 	alignedstartAtNanos := []int64{
 		time.Unix(210, 0).UnixNano(),
@@ -2069,7 +2364,7 @@ func TestGaugeElemResendBufferForwarding(t *testing.T) {
 	}, e.consumedValues[xtime.ToUnixNano(time.Unix(240, 0))][0])
 
 	// Update a previous value
-	require.NoError(t, e.AddValue(time.Unix(210, 0), 124.0, nil))
+	require.NoError(t, e.AddValue(time.Unix(210, 0), 124.0, nil, nil))
 	expectedForwardedRes = []testForwardedMetricWithMetadata{
 		{
 			aggregationKey: aggKey,
@@ -2281,6 +2576,7 @@ type testLocalMetricWithMetadata struct {
 	idSuffix  []byte
 	timeNanos int64
 	value     float64
+	exemplars []metric.Exemplar
 	sp        policy.StoragePolicy
 }
 
@@ -2288,12 +2584,170 @@ type testForwardedMetricWithMetadata struct {
 	aggregationKey aggregationKey
 	timeNanos      int64
 	value          float64
+	exemplars      []metric.Exemplar
+	histogram      forwardedHistogram
+	contributors   *contributorsSketch
 }
 
 type testOnForwardedFlushedData struct {
 	aggregationKey aggregationKey
 }
 
+func TestHistogramElemConsume(t *testing.T) {
+	elemData := ElemData{
+		ID:            testHistogramID,
+		StoragePolicy: testStoragePolicy,
+		Pipeline:      applied.DefaultPipeline,
+	}
+	opts := newTestOptions()
+	e, err := NewHistogramElem(elemData, opts)
+	require.NoError(t, err)
+
+	// Histograms reported by two series are merged into a single histogram.
+	require.NoError(t, e.AddUnion(testTimestamps[0], testHistogram))
+	require.NoError(t, e.AddUnion(testTimestamps[1], testHistogram))
+	require.Equal(t, 1, len(e.values))
+	require.Equal(t, int64(12), e.values[0].lockedAgg.aggregation.Count())
+	require.Equal(t, 60.0, e.values[0].lockedAgg.aggregation.Sum())
+
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[1], isStandardMetricEarlierThan,
+		standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, 0, len(*forwardRes))
+
+	expected := []testLocalMetricWithMetadata{
+		{
+			idSuffix: expectTimerSuffix(maggregation.Sum),
+			value:    60,
+		},
+		{
+			idSuffix: expectTimerSuffix(maggregation.Count),
+			value:    12,
+		},
+		{
+			idSuffix: []byte(".bucket.1"),
+			value:    4,
+		},
+		{
+			idSuffix: []byte(".bucket.10"),
+			value:    10,
+		},
+		{
+			idSuffix: []byte(".bucket.+Inf"),
+			value:    12,
+		},
+	}
+	for i := range expected {
+		expected[i].idPrefix = opts.FullHistogramPrefix()
+		expected[i].id = testHistogramID
+		expected[i].timeNanos = testAlignedStarts[1]
+		expected[i].sp = testStoragePolicy
+	}
+	require.Equal(t, expected, *localRes)
+}
+
+func TestHistogramElemConsumeRollupForwardsBuckets(t *testing.T) {
+	rollup := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	elemData := ElemData{
+		ID:            testHistogramID,
+		StoragePolicy: testStoragePolicy,
+		AggTypes:      maggregation.Types{maggregation.Sum, maggregation.Count},
+		Pipeline:      rollup,
+	}
+	e, err := NewHistogramElem(elemData, newTestOptions())
+	require.NoError(t, err)
+	require.NoError(t, e.AddUnion(testTimestamps[0], testHistogram))
+	require.NoError(t, e.AddUnion(testTimestamps[1], testHistogram))
+
+	// The buckets are forwarded once rather than a value per aggregation type.
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(testAlignedStarts[1], isStandardMetricEarlierThan,
+		standardMetricTimestampNanos, localFn, forwardFn, onForwardedFlushedFn))
+	require.Equal(t, 0, len(*localRes))
+
+	aggKey, ok := e.ForwardedAggregationKey()
+	require.True(t, ok)
+	verifyForwardedMetrics(t, []testForwardedMetricWithMetadata{
+		{
+			aggregationKey: aggKey,
+			timeNanos:      testAlignedStarts[1],
+			value:          nan,
+			histogram: forwardedHistogram{
+				upperBounds: []float64{1, 10, math.Inf(1)},
+				counts:      []int64{4, 6, 2},
+				sum:         60,
+			},
+		},
+	}, *forwardRes)
+}
+
+func TestHistogramElemAddUniqueHistogram(t *testing.T) {
+	elemData := ElemData{
+		ID:            testHistogramID,
+		StoragePolicy: testStoragePolicy,
+		Pipeline:      applied.DefaultPipeline,
+	}
+	e, err := NewHistogramElem(elemData, newTestOptions())
+	require.NoError(t, err)
+
+	// Histograms forwarded by different sources are merged by bucket.
+	require.NoError(t, e.AddUnique(testTimestamps[0], aggregated.ForwardedMetric{
+		Type:                       metric.HistogramType,
+		ID:                         testHistogramID,
+		HistogramBucketUpperBounds: []float64{1, 10, math.Inf(1)},
+		HistogramBucketCounts:      []int64{2, 3, 1},
+		HistogramSum:               30,
+	}, metadata.ForwardMetadata{SourceID: 1}))
+	require.NoError(t, e.AddUnique(testTimestamps[0], aggregated.ForwardedMetric{
+		Type:                       metric.HistogramType,
+		ID:                         testHistogramID,
+		HistogramBucketUpperBounds: []float64{5, 10},
+		HistogramBucketCounts:      []int64{4, 1},
+		HistogramSum:               25,
+	}, metadata.ForwardMetadata{SourceID: 2}))
+	require.Equal(t, 1, len(e.values))
+	lockedAgg := e.values[0].lockedAgg
+	require.Equal(t, int64(11), lockedAgg.aggregation.Count())
+	require.Equal(t, 55.0, lockedAgg.aggregation.Sum())
+	upperBounds, counts := lockedAgg.aggregation.Buckets()
+	require.Equal(t, []float64{1, 5, 10, math.Inf(1)}, upperBounds)
+	require.Equal(t, []int64{2, 4, 4, 1}, counts)
+	require.Equal(t, 2, lockedAgg.sources)
+
+	// Forwarded histograms cannot be updated by a resend.
+	require.Equal(t, errForwardedHistogramUpdate, e.AddUnique(testTimestamps[0], aggregated.ForwardedMetric{
+		Type:                       metric.HistogramType,
+		ID:                         testHistogramID,
+		HistogramBucketUpperBounds: []float64{1},
+		HistogramBucketCounts:      []int64{1},
+		Version:                    1,
+	}, metadata.ForwardMetadata{SourceID: 1}))
+}
+
+func TestHistogramElemResetSetDataInvalidAggregationType(t *testing.T) {
+	e, err := NewHistogramElem(ElemData{}, newTestOptions())
+	require.NoError(t, err)
+	err = e.ResetSetData(ElemData{
+		ID:            testHistogramID,
+		StoragePolicy: testStoragePolicy,
+		AggTypes:      maggregation.Types{maggregation.Max},
+		Pipeline:      applied.DefaultPipeline,
+	})
+	require.Error(t, err)
+}
+
 func testFlushLocalMetricFn() (
 	flushLocalMetricFn,
 	*[]testLocalMetricWithMetadata,
@@ -2306,6 +2760,8 @@ func testFlushLocalMetricFn() (
 		timeNanos int64,
 		value float64,
 		annotation []byte,
+		exemplars []metric.Exemplar,
+		_ maggregation.Type,
 		sp policy.StoragePolicy,
 	) {
 		result = append(result, testLocalMetricWithMetadata{
@@ -2314,6 +2770,7 @@ func testFlushLocalMetricFn() (
 			idSuffix:  idSuffix,
 			timeNanos: timeNanos,
 			value:     value,
+			exemplars: exemplars,
 			sp:        sp,
 		})
 	}, &result
@@ -2331,11 +2788,17 @@ func testFlushForwardedMetricFn() (
 		value float64,
 		prevValue float64,
 		annotation []byte,
+		exemplars []metric.Exemplar,
+		histogram forwardedHistogram,
+		contributors *contributorsSketch,
 	) {
 		result = append(result, testForwardedMetricWithMetadata{
 			aggregationKey: aggregationKey,
 			timeNanos:      timeNanos,
 			value:          value,
+			exemplars:      exemplars,
+			histogram:      histogram,
+			contributors:   contributors,
 		})
 	}, &result
 }
@@ -2598,6 +3061,7 @@ func verifyForwardedMetrics(t *testing.T, expected, actual []testForwardedMetric
 		} else {
 			require.Equal(t, expected[i].value, actual[i].value)
 		}
+		require.Equal(t, expected[i].histogram, actual[i].histogram)
 	}
 }
 
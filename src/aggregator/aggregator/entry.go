@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"bytes"
 	"container/list"
 	"errors"
 	"fmt"
@@ -68,6 +69,7 @@ var (
 	errTooFarInTheFuture = xerrors.NewInvalidParamsError(errors.New("too far in the future"))
 	errTooFarInThePast   = xerrors.NewInvalidParamsError(errors.New("too far in the past"))
 	errArrivedTooLate    = xerrors.NewInvalidParamsError(errors.New("arrived too late"))
+	errTooManyForwarded  = xerrors.NewInvalidParamsError(errors.New("forwarded too many times"))
 	errTimestampFormat   = time.RFC3339
 )
 
@@ -149,6 +151,7 @@ func newTimedEntryMetrics(scope tally.Scope) timedEntryMetrics {
 type forwardedEntryMetrics struct {
 	rateLimit        rateLimitEntryMetrics
 	arrivedTooLate   tally.Counter
+	tooManyForwarded tally.Counter
 	duplicateSources tally.Counter
 	metadataUpdates  tally.Counter
 }
@@ -157,6 +160,7 @@ func newForwardedEntryMetrics(scope tally.Scope) forwardedEntryMetrics {
 	return forwardedEntryMetrics{
 		rateLimit:        newRateLimitEntryMetrics(scope),
 		arrivedTooLate:   scope.Counter("arrived-too-late"),
+		tooManyForwarded: scope.Counter("too-many-forwarded"),
 		duplicateSources: scope.Counter("duplicate-sources"),
 		metadataUpdates:  scope.Counter("metadata-updates"),
 	}
@@ -264,6 +268,26 @@ func (e *Entry) SetRuntimeOptions(opts runtime.Options) {
 func (e *Entry) AddUntimed(
 	metricUnion unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
+) error {
+	return e.addUntimedMetric(metricUnion, metadatas, time.Time{})
+}
+
+// ReplayUntimed adds an untimed metric replayed from the write-ahead log along
+// with its metadatas, aggregating it at the time it was originally added.
+func (e *Entry) ReplayUntimed(
+	metricUnion unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
+	journaledAt time.Time,
+) error {
+	return e.addUntimedMetric(metricUnion, metadatas, journaledAt)
+}
+
+// addUntimedMetric adds an untimed metric at the given time, or at the current
+// time if the given time is zero.
+func (e *Entry) addUntimedMetric(
+	metricUnion unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
+	at time.Time,
 ) error {
 	switch metricUnion.Type {
 	case metric.TimerType:
@@ -272,7 +296,7 @@ func (e *Entry) AddUntimed(
 			int64(len(metricUnion.BatchTimerVal)),
 			e.metrics.untimed.rateLimit,
 		); err == nil {
-			err = e.writeBatchTimerWithMetadatas(metricUnion, metadatas)
+			err = e.writeBatchTimerWithMetadatas(metricUnion, metadatas, at)
 		}
 		if metricUnion.BatchTimerVal != nil && metricUnion.TimerValPool != nil {
 			metricUnion.TimerValPool.Put(metricUnion.BatchTimerVal)
@@ -283,7 +307,7 @@ func (e *Entry) AddUntimed(
 		if err := e.applyValueRateLimit(1, e.metrics.untimed.rateLimit); err != nil {
 			return err
 		}
-		return e.addUntimed(metricUnion, metadatas)
+		return e.addUntimed(metricUnion, metadatas, at)
 	}
 }
 
@@ -364,15 +388,81 @@ func (e *Entry) TryExpire(now time.Time) bool {
 	return true
 }
 
+// InProgressValues returns the values of the aggregations for the given metric ID
+// that have not expired yet, timestamped with the given timestamp function.
+func (e *Entry) InProgressValues(
+	metricID id.RawID,
+	timestampNanosFn timestampNanosFn,
+) []InProgressDatapoint {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	if e.closed {
+		return nil
+	}
+
+	var result []InProgressDatapoint
+	for _, agg := range e.aggregations {
+		elem := agg.elem.Value.(metricElem)
+		// The entry is keyed by the hash of the metric ID, guard against collisions.
+		if !bytes.Equal(elem.ID(), metricID) {
+			continue
+		}
+		resolution := agg.key.storagePolicy.Resolution().Window
+		for _, value := range elem.InProgressValues() {
+			result = append(result, InProgressDatapoint{
+				TimeNanos:     timestampNanosFn(value.TimeNanos, resolution),
+				Value:         value.Value,
+				StoragePolicy: agg.key.storagePolicy,
+			})
+		}
+	}
+	return result
+}
+
+// DebugElements describes the aggregations of the given metric ID held by the
+// entry, which is of the given category and metric type.
+func (e *Entry) DebugElements(
+	metricID id.RawID,
+	category metricCategory,
+	mtype metric.Type,
+) []MetricDebugElement {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	if e.closed {
+		return nil
+	}
+
+	var result []MetricDebugElement
+	for _, agg := range e.aggregations {
+		elem := agg.elem.Value.(metricElem)
+		// The entry is keyed by the hash of the metric ID, guard against collisions.
+		if !bytes.Equal(elem.ID(), metricID) {
+			continue
+		}
+		result = append(result, MetricDebugElement{
+			Category:            category.String(),
+			MetricType:          mtype.String(),
+			StoragePolicy:       agg.key.storagePolicy,
+			Resolution:          agg.key.storagePolicy.Resolution().Window.String(),
+			AggregationID:       agg.key.aggregationID.String(),
+			Pipeline:            agg.key.pipeline.String(),
+			NumForwardedTimes:   agg.key.numForwardedTimes,
+			NumInProgressValues: len(elem.InProgressValues()),
+		})
+	}
+	return result
+}
+
 func (e *Entry) writeBatchTimerWithMetadatas(
 	metric unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
+	at time.Time,
 ) error {
 	// If there is no limit on the maximum batch size per write, write
 	// all timers at once.
 	maxTimerBatchSizePerWrite := e.opts.MaxTimerBatchSizePerWrite()
 	if maxTimerBatchSizePerWrite == 0 {
-		return e.addUntimed(metric, metadatas)
+		return e.addUntimed(metric, metadatas, at)
 	}
 
 	// Otherwise, honor maximum timer batch size.
@@ -388,7 +478,7 @@ func (e *Entry) writeBatchTimerWithMetadatas(
 		}
 		splitTimer := metric
 		splitTimer.BatchTimerVal = timerValues[start:end]
-		if err := e.addUntimed(splitTimer, metadatas); err != nil {
+		if err := e.addUntimed(splitTimer, metadatas, at); err != nil {
 			return err
 		}
 	}
@@ -398,6 +488,7 @@ func (e *Entry) writeBatchTimerWithMetadatas(
 func (e *Entry) addUntimed(
 	metric unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
+	at time.Time,
 ) error {
 	e.timeLock.RLock()
 	defer e.timeLock.RUnlock()
@@ -410,6 +501,9 @@ func (e *Entry) addUntimed(
 	// for times that have already been flushed.
 	currTime := e.nowFn()
 	e.lastAccessNanos.Store(currTime.UnixNano())
+	if !at.IsZero() {
+		currTime = at
+	}
 
 	e.mtx.RLock()
 	if e.closed {
@@ -417,6 +511,12 @@ func (e *Entry) addUntimed(
 		return errEntryClosed
 	}
 
+	// If the local clock lags behind the clock of the leader, never aggregate into
+	// windows the leader has already flushed.
+	if watermark := e.opts.ClockSkewWatermark(); watermark != nil {
+		currTime = watermark.Clamp(currTime)
+	}
+
 	// Fast exit path for the common case where the metric has default metadatas for aggregation.
 	hasDefaultMetadatas := metadatas.IsDefault()
 	if e.hasDefaultMetadatas && hasDefaultMetadatas {
@@ -587,6 +687,8 @@ func (e *Entry) addNewAggregationKeyWithLock(
 		newElem = e.opts.TimerElemPool().Get()
 	case metric.GaugeType:
 		newElem = e.opts.GaugeElemPool().Get()
+	case metric.HistogramType:
+		newElem = e.opts.HistogramElemPool().Get()
 	default:
 		return nil, errInvalidMetricType
 	}
@@ -600,6 +702,7 @@ func (e *Entry) addNewAggregationKeyWithLock(
 		NumForwardedTimes:  key.numForwardedTimes,
 		IDPrefixSuffixType: key.idPrefixSuffixType,
 		ResendEnabled:      resendEnabled,
+		annotationBudget:   e.lists.annotationBudget,
 	}); err != nil {
 		return nil, err
 	}
@@ -898,7 +1001,7 @@ func (e *Entry) addTimedWithLock(
 	if err != nil {
 		return err
 	}
-	return value.elem.Value.(metricElem).AddValue(timestamp, metric.Value, metric.Annotation)
+	return value.elem.Value.(metricElem).AddValue(timestamp, metric.Value, metric.Annotation, metric.Exemplars)
 }
 
 func (e *Entry) addTimedWithStagedMetadatasAndLock(metric aggregated.Metric) error {
@@ -919,7 +1022,8 @@ func (e *Entry) addTimedWithStagedMetadatasAndLock(metric aggregated.Metric) err
 		}
 		multierr.AppendInto(
 			&err,
-			e.aggregations[i].elem.Value.(metricElem).AddValue(timestamp, metric.Value, metric.Annotation),
+			e.aggregations[i].elem.Value.(metricElem).AddValue(
+				timestamp, metric.Value, metric.Annotation, metric.Exemplars),
 		)
 	}
 	return err
@@ -948,6 +1052,12 @@ func (e *Entry) addForwarded(
 		return errEntryClosed
 	}
 
+	// Reject datapoints from pipelines that forward more times than allowed.
+	if err := e.checkNumForwardedTimes(metric, metadata); err != nil {
+		e.mtx.RUnlock()
+		return err
+	}
+
 	// Reject datapoints that arrive too late.
 	if err := e.checkLatenessForForwardedMetric(
 		metric,
@@ -998,6 +1108,28 @@ func (e *Entry) addForwarded(
 	return err
 }
 
+func (e *Entry) checkNumForwardedTimes(
+	metric aggregated.ForwardedMetric,
+	metadata metadata.ForwardMetadata,
+) error {
+	maxNumForwardedTimes := e.opts.MaxNumForwardedTimes()
+	if maxNumForwardedTimes <= 0 || metadata.NumForwardedTimes <= maxNumForwardedTimes {
+		return nil
+	}
+
+	e.metrics.forwarded.tooManyForwarded.Inc(1)
+
+	if !e.opts.VerboseErrors() {
+		// Don't return verbose errors if not enabled.
+		return errTooManyForwarded
+	}
+
+	err := fmt.Errorf("datapoint for aggregation forwarded too many times: "+
+		"id=%s, num_forwarded_times=%d, max_num_forwarded_times=%d, pipeline=%v",
+		metric.ID, metadata.NumForwardedTimes, maxNumForwardedTimes, metadata.Pipeline)
+	return xerrors.NewRenamedError(errTooManyForwarded, err)
+}
+
 func (e *Entry) checkLatenessForForwardedMetric(
 	metric aggregated.ForwardedMetric,
 	metadata metadata.ForwardMetadata,
@@ -1642,6 +1642,28 @@ func TestEntryAddForwardedMetricTooLate(t *testing.T) {
 	}
 }
 
+func TestEntryAddForwardedMetricTooManyForwardedTimes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e, _, _ := testEntry(ctrl, testEntryOptions{
+		options: testOptions(ctrl).
+			SetMaxNumForwardedTimes(testForwardMetadata.NumForwardedTimes - 1),
+	})
+
+	err := e.AddForwarded(testForwardedMetric, testForwardMetadata)
+	require.Equal(t, errTooManyForwarded, err)
+
+	e.opts = e.opts.SetVerboseErrors(true)
+	err = e.AddForwarded(testForwardedMetric, testForwardMetadata)
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.Equal(t, errTooManyForwarded, xerrors.InnerError(err))
+	require.True(t, strings.Contains(err.Error(), "datapoint for aggregation forwarded too many times"))
+	require.True(t, strings.Contains(err.Error(), "id="+string(testForwardedMetric.ID)))
+	require.True(t, strings.Contains(err.Error(), "num_forwarded_times=3"))
+	require.True(t, strings.Contains(err.Error(), "max_num_forwarded_times=2"))
+}
+
 func TestEntryAddForwarded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2131,3 +2153,35 @@ type testEntryData struct {
 	mu unaggregated.MetricUnion
 	fn testElemValidateFn
 }
+
+func TestEntryAddUntimedClockSkewWatermark(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	watermark := NewClockSkewWatermark()
+	opts := testOptions(ctrl).SetClockSkewWatermark(watermark)
+	e, _, now := testEntry(ctrl, testEntryOptions{options: opts})
+
+	// Metrics are aggregated at the watermark while it is ahead of the local clock.
+	watermarkNanos := now.Add(time.Hour).UnixNano()
+	watermark.Update(watermarkNanos)
+	require.NoError(t, e.AddUntimed(testCounter, testDefaultStagedMetadatas))
+	require.Equal(t, 2, len(e.aggregations))
+	for _, agg := range e.aggregations {
+		resolution := agg.key.storagePolicy.Resolution().Window
+		values := agg.elem.Value.(*CounterElem).values
+		require.Equal(t, 1, len(values))
+		require.Equal(t, time.Unix(0, watermarkNanos).Truncate(resolution).UnixNano(),
+			values[0].startAtNanos)
+	}
+
+	// Metrics are aggregated at the local time once the watermark is cleared.
+	watermark.Update(0)
+	require.NoError(t, e.AddUntimed(testCounter, testDefaultStagedMetadatas))
+	for _, agg := range e.aggregations {
+		resolution := agg.key.storagePolicy.Resolution().Window
+		values := agg.elem.Value.(*CounterElem).values
+		require.Equal(t, 2, len(values))
+		require.Equal(t, now.Truncate(resolution).UnixNano(), values[0].startAtNanos)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"github.com/m3db/m3/src/metrics/metric"
+)
+
+// addExemplars adds exemplars to the ones retained for an aggregated value,
+// keeping at most max exemplars. Once the limit is reached, an incoming
+// exemplar replaces the oldest retained one if it is more recent so the
+// retained exemplars track the latest traces. Incoming exemplars are cloned
+// since they may be backed by buffers reused once the metric is processed.
+func addExemplars(curr, exemplars []metric.Exemplar, max int) []metric.Exemplar {
+	for _, exemplar := range exemplars {
+		if len(curr) < max {
+			curr = append(curr, exemplar.Clone())
+			continue
+		}
+		oldest := -1
+		for i := range curr {
+			if curr[i].TimeNanos >= exemplar.TimeNanos {
+				continue
+			}
+			if oldest < 0 || curr[i].TimeNanos < curr[oldest].TimeNanos {
+				oldest = i
+			}
+		}
+		if oldest >= 0 {
+			curr[oldest] = exemplar.Clone()
+		}
+	}
+	return curr
+}
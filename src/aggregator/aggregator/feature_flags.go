@@ -23,6 +23,8 @@ package aggregator
 import (
 	"bytes"
 	"encoding/binary"
+
+	"github.com/m3db/m3/src/metrics/transformation"
 )
 
 // FeatureFlagConfigurations is a list of aggregator feature flags.
@@ -54,6 +56,11 @@ type FeatureFlagConfiguration struct {
 // FlagBundle contains all aggregator feature flags.
 // nolint:gofumpt
 type FlagBundle struct {
+	// CounterResetDetection makes the increase and perSecond transformations
+	// treat a decreasing value as the source counter having been reset, e.g. by
+	// a process restart, and use the value since the reset as the delta instead
+	// of dropping the datapoint. It only applies to series with a single source.
+	CounterResetDetection bool `yaml:"counterResetDetection"`
 }
 
 func (f FeatureFlagConfiguration) parse() FeatureFlagBundleParsed {
@@ -101,3 +108,22 @@ func (f FeatureFlagBundleParsed) Match(metricID []byte) (FlagBundle, bool) {
 	}
 	return f.flags, true
 }
+
+// transformationFeatureFlags returns the transformation feature flags enabled
+// by any of the bundles matching the given metric ID.
+func transformationFeatureFlags(
+	bundles []FeatureFlagBundleParsed,
+	metricID []byte,
+) transformation.FeatureFlags {
+	var flags transformation.FeatureFlags
+	for _, bundle := range bundles {
+		matched, ok := bundle.Match(metricID)
+		if !ok {
+			continue
+		}
+		if matched.CounterResetDetection {
+			flags.CounterResetDetection = true
+		}
+	}
+	return flags
+}
@@ -23,6 +23,8 @@ package aggregator
 import (
 	"time"
 
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 )
@@ -74,7 +76,7 @@ const (
 
 // A flushLocalMetricFn flushes an aggregated metric datapoint locally by either
 // consuming or discarding it. Processing of the datapoint is completed once it is
-// flushed.
+// flushed. The aggregation type is that of the value.
 type flushLocalMetricFn func(
 	idPrefix []byte,
 	id id.RawID,
@@ -82,6 +84,8 @@ type flushLocalMetricFn func(
 	timeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	aggType aggregation.Type,
 	sp policy.StoragePolicy,
 )
 
@@ -96,6 +100,9 @@ type flushForwardedMetricFn func(
 	value float64,
 	prevValue float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
 )
 
 // An onForwardingElemFlushedFn is a callback function that should be called
@@ -128,6 +128,42 @@ type FlushManagerOptions interface {
 
 	// BufferForPastTimedMetric returns the size of the buffer for timed metrics in the past.
 	BufferForPastTimedMetric() time.Duration
+
+	// SetClockSkewThreshold sets how far the clock of the leader, as estimated
+	// from the flush times it persisted, may be ahead of or behind the local clock
+	// before a follower reports clock skew, zero disables clock skew detection.
+	SetClockSkewThreshold(value time.Duration) FlushManagerOptions
+
+	// ClockSkewThreshold returns how far the clock of the leader, as estimated
+	// from the flush times it persisted, may be ahead of or behind the local clock
+	// before a follower reports clock skew, zero disables clock skew detection.
+	ClockSkewThreshold() time.Duration
+
+	// SetClockSkewWatermark sets the watermark a follower advances to the flush
+	// times persisted by the leader when the leader's clock is detected to be
+	// ahead, nil disables clamping.
+	SetClockSkewWatermark(value *ClockSkewWatermark) FlushManagerOptions
+
+	// ClockSkewWatermark returns the watermark a follower advances to the flush
+	// times persisted by the leader when the leader's clock is detected to be
+	// ahead, nil disables clamping.
+	ClockSkewWatermark() *ClockSkewWatermark
+
+	// SetMaxConcurrentShardFlushes sets the maximum number of shards the leader
+	// flushes concurrently, zero means no limit other than the worker pool size.
+	SetMaxConcurrentShardFlushes(value int) FlushManagerOptions
+
+	// MaxConcurrentShardFlushes returns the maximum number of shards the leader
+	// flushes concurrently, zero means no limit other than the worker pool size.
+	MaxConcurrentShardFlushes() int
+
+	// SetShardFlushPacing sets the fraction of the flush interval over which the
+	// leader spreads the start of the flushes of the shards, zero disables pacing.
+	SetShardFlushPacing(value float64) FlushManagerOptions
+
+	// ShardFlushPacing returns the fraction of the flush interval over which the
+	// leader spreads the start of the flushes of the shards, zero disables pacing.
+	ShardFlushPacing() float64
 }
 
 type flushManagerOptions struct {
@@ -145,6 +181,11 @@ type flushManagerOptions struct {
 	forcedFlushWindowSize  time.Duration
 
 	bufferForPastTimedMetric time.Duration
+	clockSkewThreshold       time.Duration
+	clockSkewWatermark       *ClockSkewWatermark
+
+	maxConcurrentShardFlushes int
+	shardFlushPacing          float64
 }
 
 // NewFlushManagerOptions create a new set of flush manager options.
@@ -294,3 +335,43 @@ func (o *flushManagerOptions) SetBufferForPastTimedMetric(value time.Duration) F
 func (o *flushManagerOptions) BufferForPastTimedMetric() time.Duration {
 	return o.bufferForPastTimedMetric
 }
+
+func (o *flushManagerOptions) SetClockSkewThreshold(value time.Duration) FlushManagerOptions {
+	opts := *o
+	opts.clockSkewThreshold = value
+	return &opts
+}
+
+func (o *flushManagerOptions) ClockSkewThreshold() time.Duration {
+	return o.clockSkewThreshold
+}
+
+func (o *flushManagerOptions) SetClockSkewWatermark(value *ClockSkewWatermark) FlushManagerOptions {
+	opts := *o
+	opts.clockSkewWatermark = value
+	return &opts
+}
+
+func (o *flushManagerOptions) ClockSkewWatermark() *ClockSkewWatermark {
+	return o.clockSkewWatermark
+}
+
+func (o *flushManagerOptions) SetMaxConcurrentShardFlushes(value int) FlushManagerOptions {
+	opts := *o
+	opts.maxConcurrentShardFlushes = value
+	return &opts
+}
+
+func (o *flushManagerOptions) MaxConcurrentShardFlushes() int {
+	return o.maxConcurrentShardFlushes
+}
+
+func (o *flushManagerOptions) SetShardFlushPacing(value float64) FlushManagerOptions {
+	opts := *o
+	opts.shardFlushPacing = value
+	return &opts
+}
+
+func (o *flushManagerOptions) ShardFlushPacing() float64 {
+	return o.shardFlushPacing
+}
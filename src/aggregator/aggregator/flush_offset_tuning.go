@@ -0,0 +1,306 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+)
+
+const (
+	// numFlushOffsetTuningBuckets is the number of buckets of the lateness
+	// histograms, which bounds the precision of the tuned buffers to the max
+	// buffer divided by the number of buckets.
+	numFlushOffsetTuningBuckets = 128
+
+	defaultFlushOffsetTuningPercentile = 99.0
+)
+
+var (
+	errInvalidFlushOffsetTuningMaxBuffer  = errors.New("flush offset tuning max buffer must be positive")
+	errInvalidFlushOffsetTuningMinBuffer  = errors.New("flush offset tuning min buffer must be between zero and the max buffer")
+	errInvalidFlushOffsetTuningPercentile = errors.New("flush offset tuning percentile must be between zero and 100")
+	errInvalidFlushOffsetTuningMinSamples = errors.New("flush offset tuning min samples must not be negative")
+)
+
+// FlushOffsetTuningOptions configures the auto-tuning of the buffer for past
+// timed metrics, which is how long after the end of a window timed metrics
+// are still accepted and the window is flushed. The lateness of timed metrics,
+// the time elapsed between the end of their window and their arrival, is
+// tracked per resolution and on every tick the buffer of each resolution is
+// set to the configured percentile of the lateness observed since it was last
+// tuned, within the configured bounds. A buffer decrease takes effect
+// immediately, while a buffer increase is phased in no faster than wall time
+// elapses so the oldest accepted timestamp never moves back to windows that
+// were already flushed under the smaller buffer.
+type FlushOffsetTuningOptions struct {
+	// MinBuffer is the smallest buffer the tuning can set.
+	MinBuffer time.Duration
+	// MaxBuffer is the largest buffer the tuning can set.
+	MaxBuffer time.Duration
+	// Percentile is the percentile of the observed lateness covered by the
+	// buffer, it defaults to 99 if zero.
+	Percentile float64
+	// MinSamples is the minimum number of timed metrics of a resolution
+	// observed since its buffer was last tuned for it to be tuned again.
+	MinSamples int
+}
+
+// Validate validates the options.
+func (o FlushOffsetTuningOptions) Validate() error {
+	if o.MaxBuffer <= 0 {
+		return errInvalidFlushOffsetTuningMaxBuffer
+	}
+	if o.MinBuffer < 0 || o.MinBuffer > o.MaxBuffer {
+		return errInvalidFlushOffsetTuningMinBuffer
+	}
+	if o.Percentile < 0 || o.Percentile > 100 {
+		return errInvalidFlushOffsetTuningPercentile
+	}
+	if o.MinSamples < 0 {
+		return errInvalidFlushOffsetTuningMinSamples
+	}
+	return nil
+}
+
+// latenessHistogram counts the lateness of timed metrics of a resolution in
+// linear buckets between zero and the max buffer, the last bucket counts the
+// timed metrics later than the max buffer.
+type latenessHistogram struct {
+	counts [numFlushOffsetTuningBuckets + 1]atomic.Int64
+
+	// ramp is the bufferRamp of the tuned buffer, it is nil until the buffer
+	// is first tuned.
+	ramp  atomic.Value
+	gauge tally.Gauge
+}
+
+// bufferRamp moves the buffer from one value to another no faster than wall
+// time elapses, so that the oldest accepted timestamp, now minus the buffer,
+// never decreases.
+type bufferRamp struct {
+	from      time.Duration
+	to        time.Duration
+	fromNanos int64
+}
+
+func (r bufferRamp) at(nowNanos int64) time.Duration {
+	if r.to <= r.from {
+		return r.to
+	}
+	elapsed := time.Duration(nowNanos - r.fromNanos)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if buffer := r.from + elapsed; buffer < r.to {
+		return buffer
+	}
+	return r.to
+}
+
+// flushOffsetTuner tunes the buffer for past timed metrics of each resolution
+// to the observed lateness of the timed metrics.
+type flushOffsetTuner struct {
+	sync.RWMutex
+
+	minBuffer  time.Duration
+	maxBuffer  time.Duration
+	percentile float64
+	minSamples int64
+	fallbackFn BufferForPastTimedMetricFn
+	nowFn      clock.NowFn
+	scope      tally.Scope
+
+	histograms map[time.Duration]*latenessHistogram
+}
+
+func newFlushOffsetTuner(
+	opts FlushOffsetTuningOptions,
+	fallbackFn BufferForPastTimedMetricFn,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *flushOffsetTuner {
+	percentile := opts.Percentile
+	if percentile == 0 {
+		percentile = defaultFlushOffsetTuningPercentile
+	}
+	return &flushOffsetTuner{
+		minBuffer:  opts.MinBuffer,
+		maxBuffer:  opts.MaxBuffer,
+		percentile: percentile,
+		minSamples: int64(opts.MinSamples),
+		fallbackFn: fallbackFn,
+		nowFn:      nowFn,
+		scope:      scope,
+		histograms: make(map[time.Duration]*latenessHistogram),
+	}
+}
+
+// Observe records the arrival of a timed metric of the given resolution.
+func (t *flushOffsetTuner) Observe(resolution time.Duration, timeNanos, nowNanos int64) {
+	if resolution <= 0 {
+		return
+	}
+	lateness := time.Duration(nowNanos-timeNanos) - resolution
+	idx := 0
+	if lateness > 0 {
+		idx = int(int64(lateness) * numFlushOffsetTuningBuckets / int64(t.maxBuffer))
+		if idx > numFlushOffsetTuningBuckets {
+			idx = numFlushOffsetTuningBuckets
+		}
+	}
+	t.histogramFor(resolution).counts[idx].Inc()
+}
+
+// ObserveStaged records the arrival of a timed metric with the given staged
+// metadatas for each resolution of its storage policies.
+func (t *flushOffsetTuner) ObserveStaged(
+	metas metadata.StagedMetadatas,
+	timeNanos, nowNanos int64,
+) {
+	if len(metas) == 0 {
+		return
+	}
+	var prevResolution time.Duration
+	for _, pipeline := range metas[0].Pipelines {
+		for _, sp := range pipeline.StoragePolicies {
+			resolution := sp.Resolution().Window
+			if resolution == prevResolution {
+				continue
+			}
+			t.Observe(resolution, timeNanos, nowNanos)
+			prevResolution = resolution
+		}
+	}
+}
+
+func (t *flushOffsetTuner) histogramFor(resolution time.Duration) *latenessHistogram {
+	t.RLock()
+	h, ok := t.histograms[resolution]
+	t.RUnlock()
+	if ok {
+		return h
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if h, ok := t.histograms[resolution]; ok {
+		return h
+	}
+	h = &latenessHistogram{
+		gauge: t.scope.Tagged(map[string]string{
+			"resolution": resolution.String(),
+		}).Gauge("buffer"),
+	}
+	t.histograms[resolution] = h
+	return h
+}
+
+// Tune sets the buffer of each resolution with enough observations to the
+// configured percentile of the observed lateness, and resets the observations
+// of the tuned resolutions.
+func (t *flushOffsetTuner) Tune() {
+	t.RLock()
+	defer t.RUnlock()
+
+	var (
+		nowNanos = t.nowFn().UnixNano()
+		counts   [numFlushOffsetTuningBuckets + 1]int64
+	)
+	for resolution, h := range t.histograms {
+		var total int64
+		for i := range h.counts {
+			total += h.counts[i].Load()
+		}
+		if total == 0 || total < t.minSamples {
+			// Keep accumulating observations until there are enough.
+			continue
+		}
+
+		total = 0
+		for i := range h.counts {
+			counts[i] = h.counts[i].Swap(0)
+			total += counts[i]
+		}
+
+		var (
+			target     = int64(math.Ceil(float64(total) * t.percentile / 100))
+			cumulative int64
+			idx        int
+		)
+		for idx = range counts {
+			cumulative += counts[idx]
+			if cumulative >= target {
+				break
+			}
+		}
+
+		// Use the upper bound of the bucket so the buffer covers all of the
+		// lateness counted in the bucket.
+		buffer := t.maxBuffer * time.Duration(idx+1) / numFlushOffsetTuningBuckets
+		if buffer > t.maxBuffer {
+			buffer = t.maxBuffer
+		}
+		if buffer < t.minBuffer {
+			buffer = t.minBuffer
+		}
+		h.ramp.Store(bufferRamp{
+			from:      t.bufferFor(resolution, h, nowNanos),
+			to:        buffer + resolution,
+			fromNanos: nowNanos,
+		})
+		h.gauge.Update(buffer.Seconds())
+	}
+}
+
+func (t *flushOffsetTuner) bufferFor(
+	resolution time.Duration,
+	h *latenessHistogram,
+	nowNanos int64,
+) time.Duration {
+	if ramp, ok := h.ramp.Load().(bufferRamp); ok {
+		return ramp.at(nowNanos)
+	}
+	return t.fallbackFn(resolution)
+}
+
+// BufferForPastTimedMetricFn returns the buffer for past timed metrics of the
+// tuned resolutions, and that of the fallback function for the other
+// resolutions until they are tuned.
+func (t *flushOffsetTuner) BufferForPastTimedMetricFn() BufferForPastTimedMetricFn {
+	return func(resolution time.Duration) time.Duration {
+		t.RLock()
+		h, ok := t.histograms[resolution]
+		t.RUnlock()
+		if !ok {
+			return t.fallbackFn(resolution)
+		}
+		return t.bufferFor(resolution, h, t.nowFn().UnixNano())
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/policy"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestFlushOffsetTuningOptionsValidate(t *testing.T) {
+	for _, test := range []struct {
+		opts     FlushOffsetTuningOptions
+		expected error
+	}{
+		{
+			opts:     FlushOffsetTuningOptions{MaxBuffer: time.Minute},
+			expected: nil,
+		},
+		{
+			opts:     FlushOffsetTuningOptions{},
+			expected: errInvalidFlushOffsetTuningMaxBuffer,
+		},
+		{
+			opts:     FlushOffsetTuningOptions{MinBuffer: 2 * time.Minute, MaxBuffer: time.Minute},
+			expected: errInvalidFlushOffsetTuningMinBuffer,
+		},
+		{
+			opts:     FlushOffsetTuningOptions{MaxBuffer: time.Minute, Percentile: 101},
+			expected: errInvalidFlushOffsetTuningPercentile,
+		},
+		{
+			opts:     FlushOffsetTuningOptions{MaxBuffer: time.Minute, MinSamples: -1},
+			expected: errInvalidFlushOffsetTuningMinSamples,
+		},
+	} {
+		require.Equal(t, test.expected, test.opts.Validate())
+	}
+}
+
+func TestFlushOffsetTunerTune(t *testing.T) {
+	var (
+		resolution = 10 * time.Second
+		now        = time.Unix(1000, 0)
+		nowFn      = func() time.Time { return now }
+		tuner      = newFlushOffsetTuner(FlushOffsetTuningOptions{
+			MinBuffer:  2 * time.Second,
+			MaxBuffer:  128 * time.Second,
+			Percentile: 90,
+			MinSamples: 10,
+		}, func(resolution time.Duration) time.Duration {
+			return resolution + time.Minute
+		}, nowFn, tally.NoopScope)
+		bufferFn = tuner.BufferForPastTimedMetricFn()
+		observe  = func(lateness time.Duration, n int) {
+			for i := 0; i < n; i++ {
+				tuner.Observe(resolution, now.UnixNano()-int64(resolution+lateness), now.UnixNano())
+			}
+		}
+	)
+
+	// The buffer of the given function is used until the resolution is tuned.
+	require.Equal(t, resolution+time.Minute, bufferFn(resolution))
+
+	// Not enough samples to tune.
+	observe(5*time.Second, 9)
+	tuner.Tune()
+	require.Equal(t, resolution+time.Minute, bufferFn(resolution))
+
+	// The buffer covers the 90th percentile of the lateness.
+	observe(5*time.Second, 90)
+	observe(100*time.Second, 10)
+	tuner.Tune()
+	require.Equal(t, resolution+6*time.Second, bufferFn(resolution))
+
+	// Observations are reset after tuning and the buffer is bounded.
+	observe(-5*time.Second, 100)
+	tuner.Tune()
+	require.Equal(t, resolution+2*time.Second, bufferFn(resolution))
+
+	// Increases are phased in no faster than wall time elapses.
+	observe(time.Hour, 100)
+	tuner.Tune()
+	require.Equal(t, resolution+2*time.Second, bufferFn(resolution))
+	now = now.Add(100 * time.Second)
+	require.Equal(t, resolution+102*time.Second, bufferFn(resolution))
+	now = now.Add(100 * time.Second)
+	require.Equal(t, resolution+128*time.Second, bufferFn(resolution))
+
+	// Other resolutions are not affected.
+	require.Equal(t, time.Minute+time.Minute, bufferFn(time.Minute))
+}
+
+func TestFlushOffsetTunerObserveStaged(t *testing.T) {
+	var (
+		now      = time.Unix(1000, 0)
+		nowNanos = now.UnixNano()
+		tuner    = newFlushOffsetTuner(FlushOffsetTuningOptions{
+			MaxBuffer: 128 * time.Second,
+		}, func(resolution time.Duration) time.Duration {
+			return resolution
+		}, func() time.Time { return now }, tally.NoopScope)
+		bufferFn = tuner.BufferForPastTimedMetricFn()
+		metas    = metadata.StagedMetadatas{{
+			Metadata: metadata.Metadata{
+				Pipelines: []metadata.PipelineMetadata{{
+					StoragePolicies: []policy.StoragePolicy{
+						policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*24*time.Hour),
+						policy.NewStoragePolicy(time.Minute, xtime.Minute, 40*24*time.Hour),
+					},
+				}},
+			},
+		}}
+	)
+
+	tuner.ObserveStaged(metas, nowNanos-int64(2*time.Minute), nowNanos)
+	tuner.Tune()
+	now = now.Add(time.Hour)
+	require.Equal(t, 10*time.Second+111*time.Second, bufferFn(10*time.Second))
+	require.Equal(t, time.Minute+61*time.Second, bufferFn(time.Minute))
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// changedShardFlushTimes returns the shards whose flush times in curr differ
+// from the flush times last persisted. It returns false if curr does not have
+// the same shards as the last compacted flush times base, since readers only
+// apply the deltas of the shards of base, or if the flush times of a shard are
+// nil, in which case the full flush times need to be persisted.
+func changedShardFlushTimes(
+	base, last, curr *schema.ShardSetFlushTimes,
+) ([]uint32, bool) {
+	if len(base.ByShard) != len(curr.ByShard) {
+		return nil, false
+	}
+	var changed []uint32
+	for shardID, currShard := range curr.ByShard {
+		if _, exists := base.ByShard[shardID]; !exists || currShard == nil {
+			return nil, false
+		}
+		if lastShard := last.ByShard[shardID]; lastShard != nil && proto.Equal(lastShard, currShard) {
+			continue
+		}
+		changed = append(changed, shardID)
+	}
+	return changed, true
+}
+
+// mergeShardFlushTimesDelta applies the delta of a shard on top of the base
+// flush times in place. The delta holds the full flush times of the shard
+// and replaces those of base, it is ignored if base does not have the shard.
+func mergeShardFlushTimesDelta(
+	base *schema.ShardSetFlushTimes,
+	shardID uint32,
+	delta *schema.ShardFlushTimes,
+) {
+	if _, exists := base.ByShard[shardID]; !exists {
+		return
+	}
+	base.ByShard[shardID] = delta
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedShardFlushTimes(t *testing.T) {
+	base := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+	last := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+	last.ByShard[0].StandardByResolution[int64(time.Second)] = 2000
+
+	// Only the shards changed since the flush times were last persisted are
+	// returned, regardless of the changes since they were compacted.
+	curr := proto.Clone(last).(*schema.ShardSetFlushTimes)
+	changed, ok := changedShardFlushTimes(base, last, curr)
+	require.True(t, ok)
+	require.Equal(t, 0, len(changed))
+
+	curr.ByShard[1].ForwardedByResolution[int64(time.Second)].ByNumForwardedTimes[3] = 4500
+	changed, ok = changedShardFlushTimes(base, last, curr)
+	require.True(t, ok)
+	require.Equal(t, []uint32{1}, changed)
+
+	// Round trip the delta through kv encoding before merging.
+	b, err := curr.ByShard[1].Marshal()
+	require.NoError(t, err)
+	var decoded schema.ShardFlushTimes
+	require.NoError(t, decoded.Unmarshal(b))
+	merged := proto.Clone(last).(*schema.ShardSetFlushTimes)
+	mergeShardFlushTimesDelta(merged, 1, &decoded)
+	require.True(t, proto.Equal(curr, merged))
+
+	// Deltas of shards that are not part of the flush times are ignored.
+	mergeShardFlushTimesDelta(merged, 2, &decoded)
+	require.True(t, proto.Equal(curr, merged))
+}
+
+func TestChangedShardFlushTimesRequiresCompaction(t *testing.T) {
+	inputs := []struct {
+		name   string
+		update func(*schema.ShardSetFlushTimes)
+	}{
+		{
+			name: "shard removed",
+			update: func(v *schema.ShardSetFlushTimes) {
+				delete(v.ByShard, 1)
+			},
+		},
+		{
+			name: "shard added",
+			update: func(v *schema.ShardSetFlushTimes) {
+				v.ByShard[2] = &schema.ShardFlushTimes{}
+			},
+		},
+		{
+			name: "shard replaced",
+			update: func(v *schema.ShardSetFlushTimes) {
+				v.ByShard[2] = v.ByShard[1]
+				delete(v.ByShard, 1)
+			},
+		},
+		{
+			name: "shard nil",
+			update: func(v *schema.ShardSetFlushTimes) {
+				v.ByShard[1] = nil
+			},
+		},
+	}
+	for _, input := range inputs {
+		t.Run(input.name, func(t *testing.T) {
+			curr := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+			input.update(curr)
+			_, ok := changedShardFlushTimes(testFlushTimesProto, testFlushTimesProto, curr)
+			require.False(t, ok)
+		})
+	}
+}
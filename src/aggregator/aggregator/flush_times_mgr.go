@@ -23,7 +23,9 @@ package aggregator
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/cluster/kv"
@@ -32,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/x/retry"
 	"github.com/m3db/m3/src/x/watch"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 )
@@ -80,15 +83,26 @@ const (
 	flushTimesManagerClosed
 )
 
+const (
+	// flushTimesDeltaKeySuffix is appended to the flush times key followed by
+	// a shard ID to form the key storing the flush times of the shard if they
+	// changed since the last compaction.
+	flushTimesDeltaKeySuffix = "/delta/"
+)
+
 var (
 	errFlushTimesManagerNotOpenOrClosed     = errors.New("flush times manager not open or closed")
 	errFlushTimesManagerOpen                = errors.New("flush times manager open")
 	errFlushTimesManagerAlreadyOpenOrClosed = errors.New("flush times manager already open or closed")
+	errFlushTimesNotLoaded                  = errors.New("flush times could not be loaded")
 )
 
 type flushTimesManagerMetrics struct {
 	flushTimesUnmarshalErrors tally.Counter
 	flushTimesPersist         instrument.MethodMetrics
+	flushTimesPersistSkipped  tally.Counter
+	flushTimesDeltaPersisted  tally.Counter
+	flushTimesCompacted       tally.Counter
 }
 
 func newFlushTimesManagerMetrics(
@@ -98,6 +112,9 @@ func newFlushTimesManagerMetrics(
 	return flushTimesManagerMetrics{
 		flushTimesUnmarshalErrors: scope.Counter("flush-times-unmarshal-errors"),
 		flushTimesPersist:         instrument.NewMethodMetrics(scope, "flush-times-persist", opts),
+		flushTimesPersistSkipped:  scope.Counter("flush-times-persist-skipped"),
+		flushTimesDeltaPersisted:  scope.Counter("flush-times-delta-persisted"),
+		flushTimesCompacted:       scope.Counter("flush-times-compacted"),
 	}
 }
 
@@ -110,11 +127,14 @@ type flushTimesManager struct {
 	flushTimesKeyFmt         string
 	flushTimesStore          kv.Store
 	flushTimesPersistRetrier retry.Retrier
+	minPersistInterval       time.Duration
+	compactEvery             int
 
 	state               flushTimesManagerState
 	doneCh              chan struct{}
 	flushTimesKey       string
 	proto               *schema.ShardSetFlushTimes
+	loaded              bool
 	flushTimesWatchable watch.Watchable
 	persistWatchable    watch.Watchable
 	metrics             flushTimesManagerMetrics
@@ -129,6 +149,8 @@ func NewFlushTimesManager(opts FlushTimesManagerOptions) FlushTimesManager {
 		flushTimesKeyFmt:         opts.FlushTimesKeyFmt(),
 		flushTimesStore:          opts.FlushTimesStore(),
 		flushTimesPersistRetrier: opts.FlushTimesPersistRetrier(),
+		minPersistInterval:       opts.FlushTimesMinPersistInterval(),
+		compactEvery:             opts.FlushTimesCompactEvery(),
 		metrics: newFlushTimesManagerMetrics(instrumentOpts.MetricsScope(),
 			instrumentOpts.TimerOptions()),
 	}
@@ -161,6 +183,20 @@ func (mgr *flushTimesManager) Open(shardSetID uint32) error {
 		return errFlushTimesManagerAlreadyOpenOrClosed
 	}
 	mgr.flushTimesKey = fmt.Sprintf(mgr.flushTimesKeyFmt, shardSetID)
+
+	// NB: the persisted flush times are loaded before the manager is open so
+	// that they are available as soon as it is, rather than once the watch has
+	// been notified, e.g. to not replay the write-ahead log into flushed windows.
+	flushTimes, err := mgr.loadFlushTimes()
+	if err != nil {
+		mgr.logger.Error("flush times load error",
+			zap.String("flushTimesKey", mgr.flushTimesKey),
+			zap.Error(err),
+		)
+	}
+	mgr.proto = flushTimes
+	mgr.loaded = err == nil
+
 	flushTimesWatch, err := mgr.flushTimesStore.Watch(mgr.flushTimesKey)
 	if err != nil {
 		return err
@@ -185,6 +221,11 @@ func (mgr *flushTimesManager) Get() (*schema.ShardSetFlushTimes, error) {
 	if mgr.state != flushTimesManagerOpen {
 		return nil, errFlushTimesManagerNotOpenOrClosed
 	}
+	// NB: flush times that failed to load are unknown rather than empty until
+	// the watch receives them.
+	if !mgr.loaded {
+		return nil, errFlushTimesNotLoaded
+	}
 	return mgr.proto, nil
 }
 
@@ -231,24 +272,56 @@ func (mgr *flushTimesManager) resetWithLock() {
 	mgr.doneCh = make(chan struct{})
 	mgr.flushTimesKey = ""
 	mgr.proto = nil
+	mgr.loaded = false
 	mgr.flushTimesWatchable = watch.NewWatchable()
 	mgr.persistWatchable = watch.NewWatchable()
 }
 
+func (mgr *flushTimesManager) flushTimesDeltaKey(shardID uint32) string {
+	return mgr.flushTimesKey + flushTimesDeltaKeySuffix + strconv.FormatUint(uint64(shardID), 10)
+}
+
+// shardFlushTimesDeltaWatch watches the flush times delta of a shard and
+// notifies the flush times watch loop of its updates.
+type shardFlushTimesDeltaWatch struct {
+	kv.ValueWatch
+
+	doneCh chan struct{}
+}
+
+// NB: the deltas are always watched, regardless of whether delta encoding is
+// enabled for this instance, since the compaction setting only determines
+// how the leader persists flush times and the leader may be configured
+// differently from the followers. The delta of every shard of the latest full
+// flush times is watched, since deltas are only persisted for those shards.
 func (mgr *flushTimesManager) watchFlushTimes(flushTimesWatch kv.ValueWatch) {
 	defer mgr.Done()
 
+	var (
+		deltaWatches = make(map[uint32]shardFlushTimesDeltaWatch)
+		deltaCh      = make(chan struct{}, 1)
+		deltaWg      sync.WaitGroup
+	)
+	defer func() {
+		for _, w := range deltaWatches {
+			close(w.doneCh)
+			w.Close()
+		}
+		deltaWg.Wait()
+	}()
 	for {
 		select {
 		case <-flushTimesWatch.C():
+		case <-deltaCh:
 		case <-mgr.doneCh:
 			return
 		}
 
-		var (
-			value = flushTimesWatch.Get()
-			proto schema.ShardSetFlushTimes
-		)
+		value := flushTimesWatch.Get()
+		if value == nil {
+			continue
+		}
+		var proto schema.ShardSetFlushTimes
 		if err := value.Unmarshal(&proto); err != nil {
 			mgr.metrics.flushTimesUnmarshalErrors.Inc(1)
 			mgr.logger.Error("flush times unmarshal error",
@@ -257,39 +330,258 @@ func (mgr *flushTimesManager) watchFlushTimes(flushTimesWatch kv.ValueWatch) {
 			)
 			continue
 		}
+
+		for shardID, w := range deltaWatches {
+			if _, exists := proto.ByShard[shardID]; !exists {
+				close(w.doneCh)
+				w.Close()
+				delete(deltaWatches, shardID)
+			}
+		}
+		watchErr := false
+		for shardID := range proto.ByShard {
+			if _, exists := deltaWatches[shardID]; exists {
+				continue
+			}
+			valueWatch, err := mgr.flushTimesStore.Watch(mgr.flushTimesDeltaKey(shardID))
+			if err != nil {
+				mgr.logger.Error("flush times delta watch error",
+					zap.String("flushTimesDeltaKey", mgr.flushTimesDeltaKey(shardID)),
+					zap.Error(err),
+				)
+				watchErr = true
+				continue
+			}
+			w := shardFlushTimesDeltaWatch{ValueWatch: valueWatch, doneCh: make(chan struct{})}
+			deltaWatches[shardID] = w
+			deltaWg.Add(1)
+			go func() {
+				defer deltaWg.Done()
+				for {
+					select {
+					case <-w.C():
+					case <-w.doneCh:
+						return
+					}
+					select {
+					case deltaCh <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		}
+		if watchErr {
+			// NB: the flush times are not updated without the deltas of every
+			// shard since they may be older than the current flush times.
+			continue
+		}
+
+		unmarshalErr := false
+		for shardID, w := range deltaWatches {
+			deltaValue := w.Get()
+			if deltaValue == nil {
+				continue
+			}
+			var delta schema.ShardFlushTimes
+			if err := deltaValue.Unmarshal(&delta); err != nil {
+				mgr.metrics.flushTimesUnmarshalErrors.Inc(1)
+				mgr.logger.Error("flush times delta unmarshal error",
+					zap.String("flushTimesDeltaKey", mgr.flushTimesDeltaKey(shardID)),
+					zap.Error(err),
+				)
+				unmarshalErr = true
+				break
+			}
+			mergeShardFlushTimesDelta(&proto, shardID, &delta)
+		}
+		if unmarshalErr {
+			continue
+		}
 		mgr.Lock()
 		mgr.proto = &proto
+		mgr.loaded = true
 		mgr.Unlock()
 		mgr.flushTimesWatchable.Update(&proto)
 	}
 }
 
+// loadFlushTimes loads the persisted flush times along with the deltas of their
+// shards persisted since they were last compacted, returning nil if none have
+// been persisted.
+func (mgr *flushTimesManager) loadFlushTimes() (*schema.ShardSetFlushTimes, error) {
+	value, err := mgr.flushTimesStore.Get(mgr.flushTimesKey)
+	if err == kv.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var flushTimes schema.ShardSetFlushTimes
+	if err := value.Unmarshal(&flushTimes); err != nil {
+		return nil, err
+	}
+	for shardID := range flushTimes.ByShard {
+		deltaValue, err := mgr.flushTimesStore.Get(mgr.flushTimesDeltaKey(shardID))
+		if err == kv.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var delta schema.ShardFlushTimes
+		if err := deltaValue.Unmarshal(&delta); err != nil {
+			return nil, err
+		}
+		mergeShardFlushTimesDelta(&flushTimes, shardID, &delta)
+	}
+	return &flushTimes, nil
+}
+
+// flushTimesPersistState tracks what has been persisted by the current
+// persist loop so that subsequent flush times can be persisted as deltas.
+type flushTimesPersistState struct {
+	// base is the flush times last persisted in full.
+	base *schema.ShardSetFlushTimes
+	// last is the flush times last persisted either in full or as deltas.
+	last *schema.ShardSetFlushTimes
+	// numDeltas is the number of times deltas were persisted since base.
+	numDeltas int
+	// deltaShards is the shards whose delta has been persisted since base.
+	deltaShards map[uint32]struct{}
+	// deltaMayExist is true if deltas may have been persisted by a previous
+	// leader since the last compaction.
+	deltaMayExist bool
+}
+
 func (mgr *flushTimesManager) persistFlushTimes(persistWatch watch.Watch) {
 	defer mgr.Done()
 
+	var (
+		state = flushTimesPersistState{
+			deltaShards:   make(map[uint32]struct{}),
+			deltaMayExist: true,
+		}
+		lastPersistAt time.Time
+	)
 	for {
 		select {
 		case <-mgr.doneCh:
 			return
 		case <-persistWatch.C():
-			flushTimes := persistWatch.Get().(*schema.ShardSetFlushTimes)
-			persistStart := mgr.nowFn()
-			persistErr := mgr.flushTimesPersistRetrier.Attempt(func() error {
-				_, err := mgr.flushTimesStore.Set(mgr.flushTimesKey, flushTimes)
-				return err
-			})
-			duration := mgr.nowFn().Sub(persistStart)
-			if persistErr == nil {
-				mgr.metrics.flushTimesPersist.ReportSuccess(duration)
+		}
+
+		// NB: wait out the remainder of the minimum persist interval so that
+		// updates received in the meantime are batched and only the latest
+		// flush times are persisted.
+		if mgr.minPersistInterval > 0 && !lastPersistAt.IsZero() {
+			if wait := lastPersistAt.Add(mgr.minPersistInterval).Sub(mgr.nowFn()); wait > 0 {
+				select {
+				case <-mgr.doneCh:
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		flushTimes := persistWatch.Get().(*schema.ShardSetFlushTimes)
+		if state.last != nil && proto.Equal(state.last, flushTimes) {
+			mgr.metrics.flushTimesPersistSkipped.Inc(1)
+			continue
+		}
+
+		var (
+			compacted    bool
+			persistStart = mgr.nowFn()
+		)
+		persistErr := mgr.flushTimesPersistRetrier.Attempt(func() error {
+			var err error
+			compacted, err = mgr.persistWithState(&state, flushTimes)
+			return err
+		})
+		lastPersistAt = mgr.nowFn()
+		duration := lastPersistAt.Sub(persistStart)
+		if persistErr == nil {
+			state.last = flushTimes
+			if compacted {
+				mgr.metrics.flushTimesCompacted.Inc(1)
 			} else {
-				mgr.metrics.flushTimesPersist.ReportError(duration)
-				mgr.logger.Error("flush times persist error",
-					zap.String("flushTimesKey", mgr.flushTimesKey),
-					zap.Error(persistErr),
-				)
+				mgr.metrics.flushTimesDeltaPersisted.Inc(1)
 			}
+			mgr.metrics.flushTimesPersist.ReportSuccess(duration)
+		} else {
+			mgr.metrics.flushTimesPersist.ReportError(duration)
+			mgr.logger.Error("flush times persist error",
+				zap.String("flushTimesKey", mgr.flushTimesKey),
+				zap.Error(persistErr),
+			)
+		}
+	}
+}
+
+// persistWithState persists the flush times of the shards that changed since
+// they were last persisted as deltas on top of the last compacted flush times
+// if possible, and otherwise compacts them by persisting the full flush times,
+// returning whether they were compacted.
+//
+// NB: the delta of a shard holds its full flush times and is persisted under
+// its own key, so that readers only ever need the base and the latest delta of
+// each shard, while the shards whose flush times did not change, e.g. those
+// that are not being flushed, are not persisted again.
+func (mgr *flushTimesManager) persistWithState(
+	state *flushTimesPersistState,
+	flushTimes *schema.ShardSetFlushTimes,
+) (bool, error) {
+	if mgr.compactEvery > 0 && state.base != nil && state.numDeltas < mgr.compactEvery {
+		last := state.last
+		if last == nil {
+			last = state.base
+		}
+		if changed, ok := changedShardFlushTimes(state.base, last, flushTimes); ok {
+			for _, shardID := range changed {
+				state.deltaShards[shardID] = struct{}{}
+				if _, err := mgr.flushTimesStore.Set(
+					mgr.flushTimesDeltaKey(shardID), flushTimes.ByShard[shardID],
+				); err != nil {
+					return false, err
+				}
+			}
+			state.numDeltas++
+			return false, nil
 		}
 	}
+
+	// NB: the deltas are removed before the full flush times are persisted so
+	// that readers only ever observe flush times that are stale rather than
+	// a delta applied on top of flush times it was not computed against.
+	if state.deltaMayExist {
+		// NB: deltas persisted by a previous leader can only exist for the
+		// shards of the flush times it last compacted.
+		persisted, err := mgr.loadFlushTimes()
+		if err != nil {
+			return false, err
+		}
+		if persisted != nil {
+			for shardID := range persisted.ByShard {
+				state.deltaShards[shardID] = struct{}{}
+			}
+		}
+		for shardID := range flushTimes.ByShard {
+			state.deltaShards[shardID] = struct{}{}
+		}
+		state.deltaMayExist = false
+	}
+	for shardID := range state.deltaShards {
+		if _, err := mgr.flushTimesStore.Delete(mgr.flushTimesDeltaKey(shardID)); err != nil && err != kv.ErrNotFound {
+			return false, err
+		}
+		delete(state.deltaShards, shardID)
+	}
+	if _, err := mgr.flushTimesStore.Set(mgr.flushTimesKey, flushTimes); err != nil {
+		return false, err
+	}
+	state.base = flushTimes
+	state.numDeltas = 0
+	return true, nil
 }
 
 type flushTimesCheckerMetrics struct {
@@ -21,6 +21,8 @@
 package aggregator
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
@@ -62,6 +64,28 @@ type FlushTimesManagerOptions interface {
 
 	// FlushTimesPersistRetrier returns the retrier for persisting flush times.
 	FlushTimesPersistRetrier() retry.Retrier
+
+	// SetFlushTimesMinPersistInterval sets the minimum interval between persisting
+	// flush times, with updates received in between batched so that only the
+	// latest flush times are persisted.
+	SetFlushTimesMinPersistInterval(value time.Duration) FlushTimesManagerOptions
+
+	// FlushTimesMinPersistInterval returns the minimum interval between persisting
+	// flush times.
+	FlushTimesMinPersistInterval() time.Duration
+
+	// SetFlushTimesCompactEvery sets the number of delta-encoded flush times
+	// persisted before the flush times are compacted into a full snapshot,
+	// with zero disabling delta encoding. This only affects how flush times are
+	// persisted by the leader, deltas are always applied when reading them.
+	// Deltas are persisted per shard and only for the shards whose flush times
+	// changed since they were last persisted, while adding or removing shards
+	// requires a compaction.
+	SetFlushTimesCompactEvery(value int) FlushTimesManagerOptions
+
+	// FlushTimesCompactEvery returns the number of delta-encoded flush times
+	// persisted before the flush times are compacted into a full snapshot.
+	FlushTimesCompactEvery() int
 }
 
 type flushTimesManagerOptions struct {
@@ -70,6 +94,8 @@ type flushTimesManagerOptions struct {
 	flushTimesKeyFmt         string
 	flushTimesStore          kv.Store
 	flushTimesPersistRetrier retry.Retrier
+	minPersistInterval       time.Duration
+	compactEvery             int
 }
 
 // NewFlushTimesManagerOptions create a new set of flush times manager options.
@@ -131,3 +157,23 @@ func (o *flushTimesManagerOptions) SetFlushTimesPersistRetrier(value retry.Retri
 func (o *flushTimesManagerOptions) FlushTimesPersistRetrier() retry.Retrier {
 	return o.flushTimesPersistRetrier
 }
+
+func (o *flushTimesManagerOptions) SetFlushTimesMinPersistInterval(value time.Duration) FlushTimesManagerOptions {
+	opts := *o
+	opts.minPersistInterval = value
+	return &opts
+}
+
+func (o *flushTimesManagerOptions) FlushTimesMinPersistInterval() time.Duration {
+	return o.minPersistInterval
+}
+
+func (o *flushTimesManagerOptions) SetFlushTimesCompactEvery(value int) FlushTimesManagerOptions {
+	opts := *o
+	opts.compactEvery = value
+	return &opts
+}
+
+func (o *flushTimesManagerOptions) FlushTimesCompactEvery() int {
+	return o.compactEvery
+}
@@ -26,9 +26,13 @@ import (
 	"time"
 
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
 )
@@ -133,6 +137,31 @@ func TestFlushTimesManagerGetSuccess(t *testing.T) {
 	require.Equal(t, res, testFlushTimesProto)
 }
 
+func TestFlushTimesManagerGetNotLoaded(t *testing.T) {
+	mgr, store := testFlushTimesManager()
+	_, err := store.Set(testFlushTimesKey, &commonpb.Int64Proto{Value: 1})
+	require.NoError(t, err)
+
+	// Flush times that fail to load are unavailable rather than empty.
+	require.NoError(t, mgr.Open(testShardSetID))
+	defer mgr.Close()
+	_, err = mgr.Get()
+	require.Equal(t, errFlushTimesNotLoaded, err)
+
+	// The flush times are available once the watch receives them.
+	_, err = store.Set(testFlushTimesKey, testFlushTimesProto)
+	require.NoError(t, err)
+	for {
+		if mgr.flushTimesWatchable.Get() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	res, err := mgr.Get()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(testFlushTimesProto, res))
+}
+
 func TestFlushTimesManagerWatchClosed(t *testing.T) {
 	mgr, _ := testFlushTimesManager()
 	_, err := mgr.Watch()
@@ -180,6 +209,188 @@ func TestFlushTimesManagerStoreAsyncSuccess(t *testing.T) {
 	}
 }
 
+func TestFlushTimesManagerStoreAsyncDeltaAndCompaction(t *testing.T) {
+	var (
+		scope = tally.NewTestScope("", nil)
+		store = mem.NewStore()
+		opts  = NewFlushTimesManagerOptions().
+			SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope)).
+			SetFlushTimesKeyFmt(testFlushTimesKeyFmt).
+			SetFlushTimesStore(store).
+			SetFlushTimesCompactEvery(2)
+		deltaKey0 = testFlushTimesKey + flushTimesDeltaKeySuffix + "0"
+		deltaKey1 = testFlushTimesKey + flushTimesDeltaKeySuffix + "1"
+	)
+	mgr := NewFlushTimesManager(opts).(*flushTimesManager)
+	require.NoError(t, mgr.Open(testShardSetID))
+	defer mgr.Close()
+
+	storeAndWait := func(value *schema.ShardSetFlushTimes) {
+		require.NoError(t, mgr.StoreAsync(value))
+		require.True(t, clock.WaitUntil(func() bool {
+			res, err := mgr.Get()
+			return err == nil && res != nil && proto.Equal(value, res)
+		}, 5*time.Second))
+	}
+	storedValue := func(key string) (*schema.ShardSetFlushTimes, int) {
+		value, err := store.Get(key)
+		if err == kv.ErrNotFound {
+			return nil, 0
+		}
+		require.NoError(t, err)
+		var res schema.ShardSetFlushTimes
+		require.NoError(t, value.Unmarshal(&res))
+		return &res, value.Version()
+	}
+	storedDelta := func(key string) (*schema.ShardFlushTimes, int) {
+		value, err := store.Get(key)
+		if err == kv.ErrNotFound {
+			return nil, 0
+		}
+		require.NoError(t, err)
+		var res schema.ShardFlushTimes
+		require.NoError(t, value.Unmarshal(&res))
+		return &res, value.Version()
+	}
+	withStandardFlushTime := func(nanos int64) *schema.ShardSetFlushTimes {
+		value := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+		value.ByShard[0].StandardByResolution[int64(time.Second)] = nanos
+		return value
+	}
+
+	// The first flush times are persisted in full.
+	storeAndWait(testFlushTimesProto)
+	base, version := storedValue(testFlushTimesKey)
+	require.True(t, proto.Equal(testFlushTimesProto, base))
+	require.Equal(t, 1, version)
+
+	// Subsequent flush times are persisted as deltas of the changed shards only.
+	storeAndWait(withStandardFlushTime(2000))
+	_, version = storedValue(testFlushTimesKey)
+	require.Equal(t, 1, version)
+	delta, _ := storedDelta(deltaKey0)
+	require.True(t, proto.Equal(withStandardFlushTime(2000).ByShard[0], delta))
+	delta, _ = storedDelta(deltaKey1)
+	require.Nil(t, delta)
+
+	// Unchanged flush times are not persisted.
+	require.NoError(t, mgr.StoreAsync(withStandardFlushTime(2000)))
+	require.True(t, clock.WaitUntil(func() bool {
+		c, ok := scope.Snapshot().Counters()["flush-times-persist-skipped+"]
+		return ok && c.Value() == 1
+	}, 5*time.Second))
+
+	storeAndWait(withStandardFlushTime(3000))
+	_, version = storedDelta(deltaKey0)
+	require.Equal(t, 2, version)
+
+	// The flush times are compacted once enough deltas have been persisted.
+	storeAndWait(withStandardFlushTime(4000))
+	base, version = storedValue(testFlushTimesKey)
+	require.True(t, proto.Equal(withStandardFlushTime(4000), base))
+	require.Equal(t, 2, version)
+	delta, _ = storedDelta(deltaKey0)
+	require.Nil(t, delta)
+
+	// Adding a shard requires the flush times to be compacted so that readers
+	// watch its delta.
+	storeAndWait(withStandardFlushTime(5000))
+	added := withStandardFlushTime(5000)
+	added.ByShard[2] = &schema.ShardFlushTimes{
+		StandardByResolution: map[int64]int64{int64(time.Second): 5000},
+	}
+	storeAndWait(added)
+	base, version = storedValue(testFlushTimesKey)
+	require.True(t, proto.Equal(added, base))
+	require.Equal(t, 3, version)
+	delta, _ = storedDelta(deltaKey0)
+	require.Nil(t, delta)
+}
+
+func TestFlushTimesManagerCompactionRemovesPreviousDeltas(t *testing.T) {
+	var (
+		store = mem.NewStore()
+		opts  = NewFlushTimesManagerOptions().
+			SetFlushTimesKeyFmt(testFlushTimesKeyFmt).
+			SetFlushTimesStore(store).
+			SetFlushTimesCompactEvery(2)
+		deltaKey = testFlushTimesKey + flushTimesDeltaKeySuffix + "3"
+	)
+
+	// A previous leader persisted a delta for a shard no longer owned.
+	previous := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+	previous.ByShard[3] = &schema.ShardFlushTimes{}
+	_, err := store.Set(testFlushTimesKey, previous)
+	require.NoError(t, err)
+	_, err = store.Set(deltaKey, &schema.ShardFlushTimes{
+		StandardByResolution: map[int64]int64{int64(time.Second): 2000},
+	})
+	require.NoError(t, err)
+
+	mgr := NewFlushTimesManager(opts).(*flushTimesManager)
+	require.NoError(t, mgr.Open(testShardSetID))
+	defer mgr.Close()
+
+	require.NoError(t, mgr.StoreAsync(testFlushTimesProto))
+	require.True(t, clock.WaitUntil(func() bool {
+		value, err := store.Get(testFlushTimesKey)
+		require.NoError(t, err)
+		var res schema.ShardSetFlushTimes
+		require.NoError(t, value.Unmarshal(&res))
+		return proto.Equal(testFlushTimesProto, &res)
+	}, 5*time.Second))
+	_, err = store.Get(deltaKey)
+	require.Equal(t, kv.ErrNotFound, err)
+}
+
+func TestFlushTimesManagerOpenLoadsPersistedFlushTimes(t *testing.T) {
+	mgr, store := testFlushTimesManager()
+	_, err := store.Set(testFlushTimesKey, testFlushTimesProto)
+	require.NoError(t, err)
+	delta := proto.Clone(testFlushTimesProto.ByShard[0]).(*schema.ShardFlushTimes)
+	delta.StandardByResolution[int64(time.Second)] = 2000
+	_, err = store.Set(testFlushTimesKey+flushTimesDeltaKeySuffix+"0", delta)
+	require.NoError(t, err)
+	// Deltas of shards that are not part of the flush times are ignored.
+	_, err = store.Set(testFlushTimesKey+flushTimesDeltaKeySuffix+"2", delta)
+	require.NoError(t, err)
+
+	// The flush times are available as soon as the manager is open.
+	require.NoError(t, mgr.Open(testShardSetID))
+	defer mgr.Close()
+	expected := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+	expected.ByShard[0].StandardByResolution[int64(time.Second)] = 2000
+	res, err := mgr.Get()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expected, res))
+}
+
+func TestFlushTimesManagerWatchesDeltaWithoutCompaction(t *testing.T) {
+	// NB: delta encoding is disabled for the manager, but deltas persisted by
+	// a leader with delta encoding enabled must still be applied.
+	mgr, store := testFlushTimesManager()
+	require.Equal(t, 0, mgr.compactEvery)
+	require.NoError(t, mgr.Open(testShardSetID))
+	defer mgr.Close()
+
+	_, err := store.Set(testFlushTimesKey, testFlushTimesProto)
+	require.NoError(t, err)
+	delta := proto.Clone(testFlushTimesProto.ByShard[0]).(*schema.ShardFlushTimes)
+	delta.StandardByResolution[int64(time.Second)] = 2000
+	_, err = store.Set(testFlushTimesKey+flushTimesDeltaKeySuffix+"0", delta)
+	require.NoError(t, err)
+	// Deltas of shards that are not part of the flush times are ignored.
+	_, err = store.Set(testFlushTimesKey+flushTimesDeltaKeySuffix+"2", delta)
+	require.NoError(t, err)
+
+	expected := proto.Clone(testFlushTimesProto).(*schema.ShardSetFlushTimes)
+	expected.ByShard[0].StandardByResolution[int64(time.Second)] = 2000
+	require.True(t, clock.WaitUntil(func() bool {
+		res, err := mgr.Get()
+		return err == nil && res != nil && proto.Equal(expected, res)
+	}, 5*time.Second))
+}
+
 func TestFlushTimesManagerCloseClosed(t *testing.T) {
 	mgr, _ := testFlushTimesManager()
 	require.Equal(t, errFlushTimesManagerNotOpenOrClosed, mgr.Close())
@@ -78,6 +78,9 @@ type followerFlushManagerMetrics struct {
 	kvUpdateFlush     tally.Counter
 	forcedFlush       tally.Counter
 	notCampaigning    tally.Counter
+	clockSkew         tally.Gauge
+	clockSkewAhead    tally.Counter
+	clockSkewBehind   tally.Counter
 	standard          standardFollowerFlusherMetrics
 	forwarded         forwardedFollowerFlusherMetrics
 	timed             standardFollowerFlusherMetrics
@@ -87,11 +90,16 @@ func newFollowerFlushManagerMetrics(scope tally.Scope) followerFlushManagerMetri
 	standardScope := scope.Tagged(map[string]string{"flusher-type": "standard"})
 	forwardedScope := scope.Tagged(map[string]string{"flusher-type": "forwarded"})
 	timedScope := scope.Tagged(map[string]string{"flusher-type": "timed"})
+	leaderAheadScope := scope.Tagged(map[string]string{"leader-clock": "ahead"})
+	leaderBehindScope := scope.Tagged(map[string]string{"leader-clock": "behind"})
 	return followerFlushManagerMetrics{
 		watchCreateErrors: scope.Counter("watch-create-errors"),
 		kvUpdateFlush:     scope.Counter("kv-update-flush"),
 		forcedFlush:       scope.Counter("forced-flush"),
 		notCampaigning:    scope.Counter("not-campaigning"),
+		clockSkew:         scope.Gauge("clock-skew-seconds"),
+		clockSkewAhead:    leaderAheadScope.Counter("clock-skew-detected"),
+		clockSkewBehind:   leaderBehindScope.Counter("clock-skew-detected"),
 		standard:          newStandardFlusherMetrics(standardScope),
 		forwarded:         newForwardedFlusherMetrics(forwardedScope),
 		timed:             newStandardFlusherMetrics(timedScope),
@@ -125,6 +133,9 @@ type followerFlushManager struct {
 	metrics         followerFlushManagerMetrics
 
 	bufferForPastTimedMetric time.Duration
+	clockSkewThreshold       time.Duration
+	clockSkewWatermark       *ClockSkewWatermark
+	flushTimesPersistEvery   time.Duration
 }
 
 func newFollowerFlushManager(
@@ -144,6 +155,9 @@ func newFollowerFlushManager(
 		maxBufferSize:            opts.MaxBufferSize(),
 		forcedFlushWindowSize:    opts.ForcedFlushWindowSize(),
 		bufferForPastTimedMetric: opts.BufferForPastTimedMetric(),
+		clockSkewThreshold:       opts.ClockSkewThreshold(),
+		clockSkewWatermark:       opts.ClockSkewWatermark(),
+		flushTimesPersistEvery:   opts.FlushTimesPersistEvery(),
 		logger:                   instrumentOpts.Logger(),
 		scope:                    scope,
 		doneCh:                   doneCh,
@@ -185,6 +199,7 @@ func (mgr *followerFlushManager) Prepare(buckets []*flushBucket) (flushTask, tim
 		mgr.flushTimesState = flushTimesProcessed
 		mgr.processed = mgr.received
 		mgr.flushMode = kvUpdateFollowerFlush
+		mgr.checkClockSkewWithLock(now)
 		flushersByInterval = mgr.flushersFromKVUpdateWithLock(buckets)
 		needsFlush = true
 		mgr.metrics.kvUpdateFlush.Inc(1)
@@ -386,6 +401,78 @@ func (mgr *followerFlushManager) canLeadNotFlushed(
 	return mgr.openedAt.Before(windowStartAt)
 }
 
+// checkClockSkewWithLock estimates how far the clock of the leader is skewed from the
+// local clock from the latest flush times persisted by the leader. The leader never
+// flushes data past its own clock, so a flush time ahead of the local clock is a lower
+// bound of how far the leader's clock is ahead. The leader also flushes each resolution
+// once per resolution and persists its flush times once per persist interval, so a
+// flush time further behind the local clock than both is a lower bound of how far the
+// leader's clock is behind.
+func (mgr *followerFlushManager) checkClockSkewWithLock(now time.Time) {
+	if mgr.clockSkewThreshold <= 0 || mgr.processed == nil {
+		return
+	}
+
+	var latestNanos, latestResolution int64
+	for _, shardFlushTimes := range mgr.processed.ByShard {
+		if shardFlushTimes.Tombstoned {
+			continue
+		}
+		for resolution, lastFlushedNanos := range shardFlushTimes.StandardByResolution {
+			if lastFlushedNanos > latestNanos ||
+				(lastFlushedNanos == latestNanos && resolution < latestResolution) {
+				latestNanos = lastFlushedNanos
+				latestResolution = resolution
+			}
+		}
+	}
+	if latestNanos == 0 {
+		return
+	}
+
+	leaderFlushedAt := time.Unix(0, latestNanos)
+	aheadSkew := leaderFlushedAt.Sub(now)
+	behindSkew := now.Sub(leaderFlushedAt) - time.Duration(latestResolution) - mgr.flushTimesPersistEvery
+	skew := aheadSkew
+	if behindSkew > 0 {
+		skew = -behindSkew
+	}
+	mgr.metrics.clockSkew.Update(skew.Seconds())
+
+	if behindSkew > mgr.clockSkewThreshold {
+		// Clamping only protects against flushing windows the leader has already
+		// flushed, which a leader whose clock is behind has not.
+		if mgr.clockSkewWatermark != nil {
+			mgr.clockSkewWatermark.Update(0)
+		}
+		mgr.metrics.clockSkewBehind.Inc(1)
+		mgr.logger.Warn("leader flush times are behind local clock, clocks may be skewed",
+			zap.Time("now", now),
+			zap.Time("leaderFlushedAt", leaderFlushedAt),
+			zap.Duration("skew", behindSkew),
+			zap.Duration("threshold", mgr.clockSkewThreshold))
+		return
+	}
+
+	if aheadSkew <= mgr.clockSkewThreshold {
+		if mgr.clockSkewWatermark != nil {
+			mgr.clockSkewWatermark.Update(0)
+		}
+		return
+	}
+
+	mgr.metrics.clockSkewAhead.Inc(1)
+	mgr.logger.Warn("leader flush times are ahead of local clock, clocks may be skewed",
+		zap.Time("now", now),
+		zap.Time("leaderFlushedAt", leaderFlushedAt),
+		zap.Duration("skew", aheadSkew),
+		zap.Duration("threshold", mgr.clockSkewThreshold),
+		zap.Bool("clamped", mgr.clockSkewWatermark != nil))
+	if mgr.clockSkewWatermark != nil {
+		mgr.clockSkewWatermark.Update(latestNanos)
+	}
+}
+
 func (mgr *followerFlushManager) Close() { mgr.Wait() }
 
 func (mgr *followerFlushManager) flushersFromKVUpdateWithLock(buckets []*flushBucket) []flushersGroup {
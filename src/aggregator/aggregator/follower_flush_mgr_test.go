@@ -27,6 +27,7 @@ import (
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/watch"
 
 	"github.com/golang/mock/gomock"
@@ -430,6 +431,52 @@ func TestFollowerFlushManagerPrepareFlushTimesUpdated(t *testing.T) {
 	require.Equal(t, now, mgr.lastFlushed)
 }
 
+func TestFollowerFlushManagerPrepareClockSkew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The latest standard flush time in testFlushTimes is 3663s.
+	now := time.Unix(3600, 0)
+	scope := tally.NewTestScope("", nil)
+	watermark := NewClockSkewWatermark()
+	opts := NewFlushManagerOptions().
+		SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope)).
+		SetClockSkewThreshold(time.Minute).
+		SetClockSkewWatermark(watermark)
+	mgr := newFollowerFlushManager(make(chan struct{}), opts).(*followerFlushManager)
+	mgr.nowFn = func() time.Time { return now }
+	buckets := testFlushBuckets(ctrl)
+
+	mgr.flushTimesState = flushTimesUpdated
+	mgr.received = testFlushTimes
+	mgr.Prepare(buckets)
+	require.Equal(t, int64(3663000000000), watermark.Nanos())
+	snapshot := scope.Snapshot()
+	require.Equal(t, int64(1), snapshot.Counters()["clock-skew-detected+leader-clock=ahead"].Value())
+	require.Equal(t, float64(63), snapshot.Gauges()["clock-skew-seconds+"].Value())
+
+	// The watermark is cleared once the skew is within the threshold.
+	now = time.Unix(3610, 0)
+	mgr.flushTimesState = flushTimesUpdated
+	mgr.Prepare(buckets)
+	require.Equal(t, int64(0), watermark.Nanos())
+	snapshot = scope.Snapshot()
+	require.Equal(t, int64(1), snapshot.Counters()["clock-skew-detected+leader-clock=ahead"].Value())
+	require.Equal(t, float64(53), snapshot.Gauges()["clock-skew-seconds+"].Value())
+
+	// Flush times lagging behind the local clock by more than the resolution
+	// and the persist interval are reported as the leader's clock being behind.
+	watermark.Update(3663000000000)
+	now = time.Unix(3800, 0)
+	mgr.flushTimesState = flushTimesUpdated
+	mgr.Prepare(buckets)
+	require.Equal(t, int64(0), watermark.Nanos())
+	snapshot = scope.Snapshot()
+	require.Equal(t, int64(1), snapshot.Counters()["clock-skew-detected+leader-clock=ahead"].Value())
+	require.Equal(t, int64(1), snapshot.Counters()["clock-skew-detected+leader-clock=behind"].Value())
+	require.Equal(t, float64(-126), snapshot.Gauges()["clock-skew-seconds+"].Value())
+}
+
 func TestFollowerFlushManagerPrepareMaxBufferSizeExceeded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -53,8 +53,53 @@ type writeForwardedMetricFn func(
 	value float64,
 	prevValue float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
 )
 
+// forwardedHistogram holds the buckets and the sum of the values of an
+// aggregated histogram, which are forwarded in place of its aggregated values
+// so the rollup aggregates the histogram itself.
+type forwardedHistogram struct {
+	upperBounds []float64
+	counts      []int64
+	sum         float64
+}
+
+// merge merges the buckets of two histograms by upper bound into a new
+// histogram, leaving both histograms untouched.
+func (h forwardedHistogram) merge(other forwardedHistogram) forwardedHistogram {
+	var (
+		numBuckets = len(h.upperBounds) + len(other.upperBounds)
+		merged     = forwardedHistogram{
+			upperBounds: make([]float64, 0, numBuckets),
+			counts:      make([]int64, 0, numBuckets),
+			sum:         h.sum + other.sum,
+		}
+		i, j int
+	)
+	for i < len(h.upperBounds) || j < len(other.upperBounds) {
+		switch {
+		case j == len(other.upperBounds) ||
+			(i < len(h.upperBounds) && h.upperBounds[i] < other.upperBounds[j]):
+			merged.upperBounds = append(merged.upperBounds, h.upperBounds[i])
+			merged.counts = append(merged.counts, h.counts[i])
+			i++
+		case i == len(h.upperBounds) || other.upperBounds[j] < h.upperBounds[i]:
+			merged.upperBounds = append(merged.upperBounds, other.upperBounds[j])
+			merged.counts = append(merged.counts, other.counts[j])
+			j++
+		default:
+			merged.upperBounds = append(merged.upperBounds, h.upperBounds[i])
+			merged.counts = append(merged.counts, h.counts[i]+other.counts[j])
+			i++
+			j++
+		}
+	}
+	return merged
+}
+
 type onForwardedAggregationDoneFn func(key aggregationKey) error
 
 // forwardededMetricWriter writes forwarded metrics.
@@ -162,6 +207,7 @@ type forwardedWriter struct {
 	aggregationMetrics         *forwardedAggregationMetrics
 	nowFn                      clock.NowFn
 	bufferForPastTimedMetricFn BufferForPastTimedMetricFn
+	maxExemplars               int
 }
 
 func newForwardedWriter(
@@ -178,6 +224,7 @@ func newForwardedWriter(
 		aggregationMetrics:         newForwardedAggregationMetrics(scope.SubScope("aggregations")),
 		bufferForPastTimedMetricFn: opts.BufferForPastTimedMetricFn(),
 		nowFn:                      opts.ClockOptions().NowFn(),
+		maxExemplars:               opts.MaxExemplarsPerAggregation(),
 	}
 }
 
@@ -279,11 +326,14 @@ func newIDKey(
 }
 
 type forwardedAggregationBucket struct {
-	timeNanos  int64
-	values     []float64
-	prevValues []float64
-	version    uint32
-	annotation []byte
+	timeNanos    int64
+	values       []float64
+	prevValues   []float64
+	version      uint32
+	annotation   []byte
+	exemplars    []metric.Exemplar
+	histogram    forwardedHistogram
+	contributors contributorsSketch
 }
 
 type forwardedAggregationWithKey struct {
@@ -305,6 +355,7 @@ type forwardedAggregationWithKey struct {
 	bufferForPastTimedMetric int64
 	nowFn                    clock.NowFn
 	resendEnabled            bool
+	maxExemplars             int
 }
 
 func (agg *forwardedAggregationWithKey) reset() {
@@ -322,6 +373,9 @@ func (agg *forwardedAggregationWithKey) reset() {
 		}
 		v.values = nil
 		v.prevValues = nil
+		v.exemplars = nil
+		v.histogram = forwardedHistogram{}
+		v.contributors.reset()
 		agg.buckets[k] = v
 		// keep buckets around for the buffer period.
 		if agg.resendEnabled {
@@ -333,13 +387,29 @@ func (agg *forwardedAggregationWithKey) reset() {
 	}
 }
 
-func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64, prevValue float64, annotation []byte) {
+func (agg *forwardedAggregationWithKey) add(
+	timeNanos int64,
+	value float64,
+	prevValue float64,
+	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
+) {
 	if b, ok := agg.buckets[timeNanos]; ok {
-		b.values = append(b.values, value)
-		b.prevValues = append(b.prevValues, prevValue)
+		if len(histogram.upperBounds) > 0 {
+			b.histogram = b.histogram.merge(histogram)
+		} else {
+			b.values = append(b.values, value)
+			b.prevValues = append(b.prevValues, prevValue)
+		}
 		if annotation != nil {
 			b.annotation = annotation
 		}
+		b.exemplars = addExemplars(b.exemplars, exemplars, agg.maxExemplars)
+		if contributors != nil {
+			b.contributors.merge(contributors)
+		}
 		agg.buckets[timeNanos] = b
 		return
 	}
@@ -357,14 +427,22 @@ func (agg *forwardedAggregationWithKey) add(timeNanos int64, value float64, prev
 		values = make([]float64, 0, initialValueArrayCapacity)
 		prevValues = make([]float64, 0, initialValueArrayCapacity)
 	}
-	values = append(values, value)
-	prevValues = append(prevValues, prevValue)
 	bucket := forwardedAggregationBucket{
 		timeNanos:  timeNanos,
-		values:     values,
-		prevValues: prevValues,
 		annotation: annotation,
+		exemplars:  addExemplars(nil, exemplars, agg.maxExemplars),
+	}
+	if len(histogram.upperBounds) > 0 {
+		bucket.histogram = bucket.histogram.merge(histogram)
+	} else {
+		values = append(values, value)
+		prevValues = append(prevValues, prevValue)
+	}
+	if contributors != nil {
+		bucket.contributors.merge(contributors)
 	}
+	bucket.values = values
+	bucket.prevValues = prevValues
 	agg.buckets[timeNanos] = bucket
 }
 
@@ -402,6 +480,7 @@ type forwardedAggregation struct {
 	onDoneFn                   onForwardedAggregationDoneFn
 	bufferForPastTimedMetricFn BufferForPastTimedMetricFn
 	nowFn                      clock.NowFn
+	maxExemplars               int
 }
 
 func (w *forwardedWriter) newForwardedAggregation(metricType metric.Type, metricID id.RawID) *forwardedAggregation {
@@ -414,6 +493,7 @@ func (w *forwardedWriter) newForwardedAggregation(metricType metric.Type, metric
 		metrics:                    w.aggregationMetrics,
 		bufferForPastTimedMetricFn: w.bufferForPastTimedMetricFn,
 		nowFn:                      w.nowFn,
+		maxExemplars:               w.maxExemplars,
 	}
 	agg.writeFn = agg.write
 	agg.onDoneFn = agg.onDone
@@ -456,6 +536,7 @@ func (agg *forwardedAggregation) add(metric Registerable) error {
 		bufferForPastTimedMetric: int64(agg.bufferForPastTimedMetricFn(key.storagePolicy.Resolution().Window)),
 		nowFn:                    agg.nowFn,
 		resendEnabled:            metric.ResendEnabled(),
+		maxExemplars:             agg.maxExemplars,
 	}
 	agg.byKey = append(agg.byKey, aggregation)
 	agg.metrics.added.Inc(1)
@@ -486,9 +567,12 @@ func (agg *forwardedAggregation) write(
 	value float64,
 	prevValue float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
 ) {
 	idx := agg.index(key)
-	agg.byKey[idx].add(timeNanos, value, prevValue, annotation)
+	agg.byKey[idx].add(timeNanos, value, prevValue, annotation, exemplars, histogram, contributors)
 	agg.metrics.write.Inc(1)
 }
 
@@ -512,17 +596,22 @@ func (agg *forwardedAggregation) onDone(key aggregationKey) error {
 			}
 		)
 		for t, b := range agg.byKey[idx].buckets {
-			if len(b.values) == 0 {
+			if len(b.values) == 0 && len(b.histogram.upperBounds) == 0 {
 				continue
 			}
 			metric := aggregated.ForwardedMetric{
-				Type:       agg.metricType,
-				ID:         agg.metricID,
-				TimeNanos:  b.timeNanos,
-				Values:     b.values,
-				PrevValues: b.prevValues,
-				Annotation: b.annotation,
-				Version:    b.version,
+				Type:                       agg.metricType,
+				ID:                         agg.metricID,
+				TimeNanos:                  b.timeNanos,
+				Values:                     b.values,
+				PrevValues:                 b.prevValues,
+				Annotation:                 b.annotation,
+				Exemplars:                  b.exemplars,
+				HistogramBucketUpperBounds: b.histogram.upperBounds,
+				HistogramBucketCounts:      b.histogram.counts,
+				HistogramSum:               b.histogram.sum,
+				Contributors:               b.contributors.bytes(),
+				Version:                    b.version,
 			}
 			b.version++
 			if err := agg.client.WriteForwarded(metric, meta); err != nil {
@@ -21,6 +21,7 @@
 package aggregator
 
 import (
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -59,7 +60,7 @@ func TestCachedValuesGrowth(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		for n := 0; n < 3; n++ {
 			timeNanos++
-			key.add(timeNanos, 1.0, 0.5, nil)
+			key.add(timeNanos, 1.0, 0.5, nil, nil, forwardedHistogram{}, nil)
 		}
 		key.reset()
 	}
@@ -165,7 +166,7 @@ func TestForwardedWriterRegisterNewAggregation(t *testing.T) {
 	require.Equal(t, 0, len(agg.byKey[0].buckets))
 
 	// Validate that writeFn can be used to write data to the aggregation.
-	writeFn(aggKey, 1234, 5.67, 5.0, nil)
+	writeFn(aggKey, 1234, 5.67, 5.0, nil, nil, forwardedHistogram{}, nil)
 	require.Equal(t, 1, len(agg.byKey[0].buckets))
 	require.Equal(t, int64(1234), agg.byKey[0].buckets[1234].timeNanos)
 	require.Equal(t, []float64{5.67}, agg.byKey[0].buckets[1234].values)
@@ -173,7 +174,7 @@ func TestForwardedWriterRegisterNewAggregation(t *testing.T) {
 	require.Equal(t, uint32(0), agg.byKey[0].buckets[1234].version)
 	require.Nil(t, agg.byKey[0].buckets[0].annotation)
 
-	writeFn(aggKey, 1234, 1.78, 1.0, testAnnot)
+	writeFn(aggKey, 1234, 1.78, 1.0, testAnnot, nil, forwardedHistogram{}, nil)
 	require.Equal(t, 1, len(agg.byKey[0].buckets))
 	require.Equal(t, int64(1234), agg.byKey[0].buckets[1234].timeNanos)
 	require.Equal(t, []float64{5.67, 1.78}, agg.byKey[0].buckets[1234].values)
@@ -181,7 +182,7 @@ func TestForwardedWriterRegisterNewAggregation(t *testing.T) {
 	require.Equal(t, uint32(0), agg.byKey[0].buckets[1234].version)
 	require.Equal(t, testAnnot, agg.byKey[0].buckets[1234].annotation)
 
-	writeFn(aggKey, 1240, -2.95, 0.0, nil)
+	writeFn(aggKey, 1240, -2.95, 0.0, nil, nil, forwardedHistogram{}, nil)
 	require.Equal(t, 2, len(agg.byKey[0].buckets))
 	require.Equal(t, int64(1240), agg.byKey[0].buckets[1240].timeNanos)
 	require.Equal(t, []float64{-2.95}, agg.byKey[0].buckets[1240].values)
@@ -217,6 +218,153 @@ func TestForwardedWriterRegisterNewAggregation(t *testing.T) {
 	require.Equal(t, 1, agg.byKey[0].currRefCnt)
 }
 
+func TestForwardedWriterExemplars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		c      = client.NewMockAdminClient(ctrl)
+		opts   = NewOptions(clock.NewOptions()).SetAdminClient(c).SetMaxExemplarsPerAggregation(2)
+		w      = newForwardedWriter(0, opts)
+		mt     = metric.GaugeType
+		mid    = id.RawID("foo")
+		aggKey = testForwardedWriterAggregationKey
+	)
+
+	writeFn, onDoneFn, err := w.Register(testRegisterable{
+		metricType: mt,
+		id:         mid,
+		key:        aggKey,
+	})
+	require.NoError(t, err)
+
+	// Validate that exemplars written for the same bucket are merged and bounded.
+	writeFn(aggKey, 1234, 5.67, 5.0, nil, []metric.Exemplar{
+		{TraceID: []byte("trace1"), Value: 5.67, TimeNanos: 100},
+		{TraceID: []byte("trace2"), Value: 5.0, TimeNanos: 200},
+	}, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1234, 1.78, 1.0, nil, []metric.Exemplar{
+		{TraceID: []byte("trace3"), Value: 1.78, TimeNanos: 300},
+	}, forwardedHistogram{}, nil)
+
+	expectedMetric := aggregated.ForwardedMetric{
+		Type:       mt,
+		ID:         mid,
+		TimeNanos:  1234,
+		Values:     []float64{5.67, 1.78},
+		PrevValues: []float64{5.0, 1.0},
+		Exemplars: []metric.Exemplar{
+			{TraceID: []byte("trace3"), Value: 1.78, TimeNanos: 300},
+			{TraceID: []byte("trace2"), Value: 5.0, TimeNanos: 200},
+		},
+	}
+	expectedMeta := metadata.ForwardMetadata{
+		AggregationID:     aggregation.MustCompressTypes(aggregation.Count),
+		StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+		SourceID:          0,
+		NumForwardedTimes: 1,
+	}
+	c.EXPECT().WriteForwarded(expectedMetric, expectedMeta).Return(nil)
+	require.NoError(t, onDoneFn(aggKey))
+}
+
+func TestForwardedWriterHistogram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		c      = client.NewMockAdminClient(ctrl)
+		opts   = NewOptions(clock.NewOptions()).SetAdminClient(c)
+		w      = newForwardedWriter(0, opts)
+		mt     = metric.HistogramType
+		mid    = id.RawID("foo")
+		aggKey = testForwardedWriterAggregationKey
+	)
+
+	writeFn, onDoneFn, err := w.Register(testRegisterable{
+		metricType: mt,
+		id:         mid,
+		key:        aggKey,
+	})
+	require.NoError(t, err)
+
+	// Validate that histograms written for the same bucket are merged by upper bound.
+	writeFn(aggKey, 1234, nan, nan, nil, nil, forwardedHistogram{
+		upperBounds: []float64{1, 10, math.Inf(1)},
+		counts:      []int64{2, 3, 1},
+		sum:         30,
+	}, nil)
+	writeFn(aggKey, 1234, nan, nan, nil, nil, forwardedHistogram{
+		upperBounds: []float64{5, 10},
+		counts:      []int64{4, 1},
+		sum:         25,
+	}, nil)
+
+	expectedMetric := aggregated.ForwardedMetric{
+		Type:                       mt,
+		ID:                         mid,
+		TimeNanos:                  1234,
+		Values:                     []float64{},
+		PrevValues:                 []float64{},
+		HistogramBucketUpperBounds: []float64{1, 5, 10, math.Inf(1)},
+		HistogramBucketCounts:      []int64{2, 4, 4, 1},
+		HistogramSum:               55,
+	}
+	expectedMeta := metadata.ForwardMetadata{
+		AggregationID:     aggregation.MustCompressTypes(aggregation.Count),
+		StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+		SourceID:          0,
+		NumForwardedTimes: 1,
+	}
+	c.EXPECT().WriteForwarded(expectedMetric, expectedMeta).Return(nil)
+	require.NoError(t, onDoneFn(aggKey))
+}
+
+func TestForwardedWriterContributors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		c      = client.NewMockAdminClient(ctrl)
+		opts   = NewOptions(clock.NewOptions()).SetAdminClient(c)
+		w      = newForwardedWriter(0, opts)
+		mt     = metric.CounterType
+		mid    = id.RawID("foo")
+		aggKey = testForwardedWriterAggregationKey
+	)
+
+	writeFn, onDoneFn, err := w.Register(testRegisterable{
+		metricType: mt,
+		id:         mid,
+		key:        aggKey,
+	})
+	require.NoError(t, err)
+
+	// Validate that the contributors written for the same bucket are merged.
+	writeFn(aggKey, 1234, 5.67, 5.0, nil, nil, forwardedHistogram{}, newContributorsSketch([]byte("host1")))
+	writeFn(aggKey, 1234, 1.78, 1.0, nil, nil, forwardedHistogram{}, newContributorsSketch([]byte("host2")))
+	writeFn(aggKey, 1234, 3.4, 3.0, nil, nil, forwardedHistogram{}, nil)
+
+	expectedContributors := newContributorsSketch([]byte("host1"))
+	expectedContributors.add([]byte("host2"))
+	expectedMetric := aggregated.ForwardedMetric{
+		Type:         mt,
+		ID:           mid,
+		TimeNanos:    1234,
+		Values:       []float64{5.67, 1.78, 3.4},
+		PrevValues:   []float64{5.0, 1.0, 3.0},
+		Contributors: expectedContributors.bytes(),
+	}
+	expectedMeta := metadata.ForwardMetadata{
+		AggregationID:     aggregation.MustCompressTypes(aggregation.Count),
+		StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+		SourceID:          0,
+		NumForwardedTimes: 1,
+	}
+	c.EXPECT().WriteForwarded(expectedMetric, expectedMeta).Return(nil)
+	require.NoError(t, onDoneFn(aggKey))
+}
+
 func TestForwardedWriterRegisterExistingAggregation(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -386,9 +534,9 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write some datapoints.
-	writeFn(aggKey, 1234, 3.4, 3.0, nil)
-	writeFn(aggKey, 1234, 3.5, 2.0, nil)
-	writeFn(aggKey, 1240, 98.2, 98.0, nil)
+	writeFn(aggKey, 1234, 3.4, 3.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1234, 3.5, 2.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1240, 98.2, 98.0, nil, nil, forwardedHistogram{}, nil)
 
 	// Register another aggregation.
 	writeFn2, onDoneFn2, err := w.Register(testRegisterable{
@@ -399,8 +547,8 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write some more datapoints.
-	writeFn2(aggKey, 1238, 3.4, 0.0, nil)
-	writeFn2(aggKey, 1239, 3.5, 0.0, nil)
+	writeFn2(aggKey, 1238, 3.4, 0.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1239, 3.5, 0.0, nil, nil, forwardedHistogram{}, nil)
 
 	expectedMetric1 := aggregated.ForwardedMetric{
 		Type:       mt,
@@ -478,11 +626,11 @@ func TestForwardedWriterPrepare(t *testing.T) {
 	require.Equal(t, 4, len(agg.byKey[0].cachedValueArrays))
 
 	// Write datapoints again.
-	writeFn(aggKey, 1234, 3.4, 3.0, nil)
-	writeFn(aggKey, 1234, 3.5, 2.0, nil)
-	writeFn(aggKey, 1240, 98.2, 98.0, nil)
-	writeFn2(aggKey, 1238, 3.4, 0.0, nil)
-	writeFn2(aggKey, 1239, 3.5, 0.0, nil)
+	writeFn(aggKey, 1234, 3.4, 3.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1234, 3.5, 2.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1240, 98.2, 98.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1238, 3.4, 0.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1239, 3.5, 0.0, nil, nil, forwardedHistogram{}, nil)
 	require.NoError(t, onDoneFn(aggKey))
 	require.NoError(t, onDoneFn2(aggKey))
 
@@ -531,9 +679,9 @@ func TestForwardedWriterResend(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write some datapoints.
-	writeFn(aggKey, 1234, 3.4, 3.0, nil)
-	writeFn(aggKey, 1234, 3.5, 2.0, nil)
-	writeFn(aggKey, 1240, 98.2, 98.0, nil)
+	writeFn(aggKey, 1234, 3.4, 3.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1234, 3.5, 2.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1240, 98.2, 98.0, nil, nil, forwardedHistogram{}, nil)
 
 	// Register another aggregation.
 	writeFn2, onDoneFn2, err := w.Register(testRegisterable{
@@ -545,8 +693,8 @@ func TestForwardedWriterResend(t *testing.T) {
 	require.NoError(t, err)
 
 	// Write some more datapoints.
-	writeFn2(aggKey, 1238, 3.4, 0.0, nil)
-	writeFn2(aggKey, 1239, 3.5, 0.0, nil)
+	writeFn2(aggKey, 1238, 3.4, 0.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1239, 3.5, 0.0, nil, nil, forwardedHistogram{}, nil)
 
 	expectedMetric1 := aggregated.ForwardedMetric{
 		Type:       mt,
@@ -626,11 +774,11 @@ func TestForwardedWriterResend(t *testing.T) {
 	require.Equal(t, 4, len(agg.byKey[0].cachedValueArrays))
 
 	// Write datapoints again.
-	writeFn(aggKey, 1234, 3.4, 3.0, nil)
-	writeFn(aggKey, 1234, 3.5, 2.0, nil)
-	writeFn(aggKey, 1240, 98.2, 98.0, nil)
-	writeFn2(aggKey, 1238, 3.4, 0.0, nil)
-	writeFn2(aggKey, 1239, 3.5, 0.0, nil)
+	writeFn(aggKey, 1234, 3.4, 3.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1234, 3.5, 2.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn(aggKey, 1240, 98.2, 98.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1238, 3.4, 0.0, nil, nil, forwardedHistogram{}, nil)
+	writeFn2(aggKey, 1239, 3.5, 0.0, nil, nil, forwardedHistogram{}, nil)
 
 	expectedMetric1.Version = 1
 	expectedMetric2.Version = 1
@@ -27,10 +27,13 @@ package aggregator
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
+	maggregation "github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/metrics/transformation"
@@ -42,12 +45,15 @@ import (
 type lockedGaugeAggregation struct {
 	sync.Mutex
 
-	dirty       bool
-	flushed     bool
-	closed      bool
-	sourcesSeen map[uint32]*bitset.BitSet
-	aggregation gaugeAggregation
-	prevValues  []float64 // the previously emitted values (one per aggregation type).
+	dirty        bool
+	flushed      bool
+	closed       bool
+	sourcesSeen  map[uint32]*bitset.BitSet
+	sources      int                // number of distinct sources that forwarded values.
+	contributors contributorsSketch // the distinct contributors of the forwarded values.
+	aggregation  gaugeAggregation
+	prevValues   []float64         // the previously emitted values (one per aggregation type).
+	exemplars    []metric.Exemplar // a bounded sample of the exemplars of the added values.
 }
 
 type timedGauge struct {
@@ -125,6 +131,8 @@ func (e *GaugeElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) e
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	mu.Annotation = e.retainedAnnotation(lockedAgg.aggregation.Annotation(), mu.Annotation)
+	lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, mu.Exemplars)
 	lockedAgg.aggregation.AddUnion(timestamp, mu)
 	lockedAgg.dirty = true
 	lockedAgg.Unlock()
@@ -132,7 +140,12 @@ func (e *GaugeElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion) e
 }
 
 // AddValue adds a metric value at a given timestamp.
-func (e *GaugeElem) AddValue(timestamp time.Time, value float64, annotation []byte) error {
+func (e *GaugeElem) AddValue(
+	timestamp time.Time,
+	value float64,
+	annotation []byte,
+	exemplars []metric.Exemplar,
+) error {
 	alignedStart := timestamp.Truncate(e.sp.Resolution().Window).UnixNano()
 	lockedAgg, err := e.findOrCreate(alignedStart, createAggregationOptions{})
 	if err != nil {
@@ -143,7 +156,9 @@ func (e *GaugeElem) AddValue(timestamp time.Time, value float64, annotation []by
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	annotation = e.retainedAnnotation(lockedAgg.aggregation.Annotation(), annotation)
 	lockedAgg.aggregation.Add(timestamp, value, annotation)
+	lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, exemplars)
 	lockedAgg.dirty = true
 	lockedAgg.Unlock()
 	return nil
@@ -168,6 +183,18 @@ func (e *GaugeElem) AddUnique(
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	isHistogram := len(metric.HistogramBucketUpperBounds) > 0
+	if isHistogram && metric.Version > 0 {
+		lockedAgg.Unlock()
+		return errForwardedHistogramUpdate
+	}
+	// NB: merging the contributors of a value forwarded again is a no-op.
+	if len(metric.Contributors) > 0 && e.opts.EmitContributors() {
+		if err := lockedAgg.contributors.mergeBytes(metric.Contributors); err != nil {
+			lockedAgg.Unlock()
+			return err
+		}
+	}
 	versionsSeen := lockedAgg.sourcesSeen[metadata.SourceID]
 	if versionsSeen == nil {
 		// N.B - these bitsets will be transitively cached through the cached sources seen.
@@ -179,9 +206,26 @@ func (e *GaugeElem) AddUnique(
 		lockedAgg.Unlock()
 		return errDuplicateForwardingSource
 	}
+	// NB: cached source sets are cleared rather than emptied, so a source is
+	// new to this window when none of its versions have been seen yet.
+	if versionsSeen.None() {
+		lockedAgg.sources++
+	}
 	versionsSeen.Set(version)
 
-	if metric.Version > 0 {
+	if isHistogram {
+		// NB: forwarded histograms carry their buckets rather than values so
+		// they are merged into the aggregated histogram.
+		lockedAgg.aggregation.AddUnion(timestamp, unaggregated.MetricUnion{
+			Type:                       metric.Type,
+			ID:                         metric.ID,
+			HistogramBucketUpperBounds: metric.HistogramBucketUpperBounds,
+			HistogramBucketCounts:      metric.HistogramBucketCounts,
+			HistogramSum:               metric.HistogramSum,
+			Annotation:                 e.retainedAnnotation(lockedAgg.aggregation.Annotation(), metric.Annotation),
+		})
+		lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, metric.Exemplars)
+	} else if metric.Version > 0 {
 		e.metrics.updatedValues.Inc(1)
 		for i := range metric.Values {
 			if err := lockedAgg.aggregation.UpdateVal(timestamp, metric.Values[i], metric.PrevValues[i]); err != nil {
@@ -189,9 +233,11 @@ func (e *GaugeElem) AddUnique(
 			}
 		}
 	} else {
+		annotation := e.retainedAnnotation(lockedAgg.aggregation.Annotation(), metric.Annotation)
 		for _, v := range metric.Values {
-			lockedAgg.aggregation.Add(timestamp, v, metric.Annotation)
+			lockedAgg.aggregation.Add(timestamp, v, annotation)
 		}
+		lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, metric.Exemplars)
 	}
 
 	lockedAgg.dirty = true
@@ -284,6 +330,8 @@ func (e *GaugeElem) Consume(
 		if expired {
 			// Cleanup expired item.
 			e.toConsume[i].lockedAgg.closed = true
+			e.releaseAnnotation(e.toConsume[i].lockedAgg.aggregation.Annotation())
+			e.toConsume[i].lockedAgg.exemplars = nil
 			e.toConsume[i].lockedAgg.aggregation.Close()
 			if e.toConsume[i].lockedAgg.sourcesSeen != nil {
 				e.cachedSourceSetsLock.Lock()
@@ -314,6 +362,47 @@ func (e *GaugeElem) Consume(
 	return canCollect
 }
 
+// InProgressValues returns the values of the aggregations that have not expired yet,
+// timestamped with the start of their aggregation windows. Only aggregations that
+// are flushed under the element ID as is are returned, since the values are looked
+// up by the ID they are written to storage with.
+func (e *GaugeElem) InProgressValues() []transformation.Datapoint {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed || e.parsedPipeline.HasRollup || len(e.parsedPipeline.Transformations) > 0 {
+		return nil
+	}
+
+	// If several aggregation types are flushed under the same ID, the one flushed
+	// last overwrites the others in storage.
+	aggTypeIdx := -1
+	for i, aggType := range e.aggTypes {
+		if e.idPrefixSuffixType == NoPrefixNoSuffix ||
+			(len(e.FullPrefix(e.opts)) == 0 && len(e.TypeStringFor(e.aggTypesOpts, aggType)) == 0) {
+			aggTypeIdx = i
+		}
+	}
+	if aggTypeIdx < 0 {
+		return nil
+	}
+
+	aggType := e.aggTypes[aggTypeIdx]
+	values := make([]transformation.Datapoint, 0, len(e.values))
+	for _, value := range e.values {
+		value.lockedAgg.Lock()
+		if !value.lockedAgg.closed {
+			if v := value.lockedAgg.aggregation.ValueOf(aggType); !math.IsNaN(v) {
+				values = append(values, transformation.Datapoint{
+					TimeNanos: value.startAtNanos,
+					Value:     v,
+				})
+			}
+		}
+		value.lockedAgg.Unlock()
+	}
+	return values
+}
+
 // Close closes the element.
 func (e *GaugeElem) Close() {
 	e.Lock()
@@ -333,6 +422,8 @@ func (e *GaugeElem) Close() {
 	for idx := range e.values {
 		// Close the underlying aggregation objects.
 		e.values[idx].lockedAgg.sourcesSeen = nil
+		e.releaseAnnotation(e.values[idx].lockedAgg.aggregation.Annotation())
+		e.values[idx].lockedAgg.exemplars = nil
 		e.values[idx].lockedAgg.aggregation.Close()
 		e.values[idx].Release()
 	}
@@ -458,6 +549,21 @@ func (e *GaugeElem) processValueWithAggregationLock(
 		discardNaNValues = e.opts.DiscardNaNAggregatedValues()
 		emitted          bool
 	)
+	upperBounds, counts := lockedAgg.aggregation.Buckets()
+	isForwardedHistogram := e.parsedPipeline.HasRollup && len(upperBounds) > 0
+	// NB: the contributors received by an intermediate rollup are forwarded in
+	// place of the contributor of the series.
+	contributors := e.contributor
+	if !lockedAgg.contributors.empty() {
+		contributors = &lockedAgg.contributors
+	}
+	// NB: the sum of the values forwarded by several sources decreases when any
+	// one of them resets, and the sum is not a counter that restarted from zero,
+	// so counter reset detection only applies to series with a single source.
+	if lockedAgg.sources > 1 && e.transformationFlags.CounterResetDetection {
+		e.transformationFlags.CounterResetDetection = false
+		e.metrics.counterResetDetectionDisabled.Inc(1)
+	}
 	for aggTypeIdx, aggType := range e.aggTypes {
 		var extraDp transformation.Datapoint
 		value := lockedAgg.aggregation.ValueOf(aggType)
@@ -491,7 +597,7 @@ func (e *GaugeElem) processValueWithAggregationLock(
 					TimeNanos: int64(timeNanos),
 					Value:     value,
 				}
-				res := binaryOp.Evaluate(prev, curr, transformation.FeatureFlags{})
+				res := binaryOp.Evaluate(prev, curr, e.transformationFlags)
 
 				// NB: we only need to record the value needed for derivative transformations.
 				// We currently only support first-order derivative transformations so we only
@@ -533,6 +639,10 @@ func (e *GaugeElem) processValueWithAggregationLock(
 		}
 		emitted = true
 
+		if isForwardedHistogram {
+			// The buckets are forwarded below once all the values are processed.
+			continue
+		}
 		if !e.parsedPipeline.HasRollup {
 			toFlush := make([]transformation.Datapoint, 0, 2)
 			toFlush = append(toFlush, transformation.Datapoint{
@@ -545,16 +655,60 @@ func (e *GaugeElem) processValueWithAggregationLock(
 			for _, point := range toFlush {
 				switch e.idPrefixSuffixType {
 				case NoPrefixNoSuffix:
-					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value,
+						lockedAgg.aggregation.Annotation(), lockedAgg.exemplars, aggType, e.sp)
 				case WithPrefixWithSuffix:
 					flushLocalFn(e.FullPrefix(e.opts), e.id, e.TypeStringFor(e.aggTypesOpts, aggType),
-						point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+						point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars, aggType, e.sp)
 				}
 			}
 		} else {
 			forwardedAggregationKey, _ := e.ForwardedAggregationKey()
 			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey,
-				int64(timeNanos), value, prevValue, lockedAgg.aggregation.Annotation())
+				int64(timeNanos), value, prevValue, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars,
+				forwardedHistogram{}, contributors)
+		}
+	}
+
+	if emitted && isForwardedHistogram {
+		// Forward the buckets of histograms rather than their aggregated values so
+		// the rollup aggregates the buckets received from every source.
+		forwardedAggregationKey, _ := e.ForwardedAggregationKey()
+		flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey,
+			int64(timeNanos), nan, nan, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars,
+			forwardedHistogram{
+				upperBounds: upperBounds,
+				counts:      counts,
+				sum:         lockedAgg.aggregation.ValueOf(maggregation.Sum),
+			}, contributors)
+	}
+
+	if emitted && !e.parsedPipeline.HasRollup && !lockedAgg.contributors.empty() && e.opts.EmitContributors() {
+		// Emit the estimated number of contributors to the rollup output alongside it
+		// so consumers can detect windows where only part of the fleet reported.
+		var prefix []byte
+		if e.idPrefixSuffixType == WithPrefixWithSuffix {
+			prefix = e.FullPrefix(e.opts)
+		}
+		flushLocalFn(prefix, e.id, e.opts.ContributorsSuffix(), int64(timeNanos),
+			math.Round(lockedAgg.contributors.estimate()), nil, nil, maggregation.Max, e.sp)
+	}
+
+	if emitted && !e.parsedPipeline.HasRollup {
+		// Emit the cumulative count of each bucket of aggregated histograms keyed by
+		// the bucket upper bound so the histogram can be reconstructed downstream.
+		var (
+			prefix     []byte
+			cumulative int64
+		)
+		if e.idPrefixSuffixType == WithPrefixWithSuffix {
+			prefix = e.FullPrefix(e.opts)
+		}
+		for i, upperBound := range upperBounds {
+			cumulative += counts[i]
+			suffix := append([]byte(nil), e.opts.HistogramBucketSuffix()...)
+			suffix = strconv.AppendFloat(suffix, upperBound, 'f', -1, 64)
+			flushLocalFn(prefix, e.id, suffix, int64(timeNanos), float64(cumulative), nil, nil, maggregation.Sum, e.sp)
 		}
 	}
 	return emitted
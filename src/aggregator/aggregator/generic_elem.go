@@ -23,6 +23,7 @@ package aggregator
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -60,6 +61,10 @@ type typeSpecificAggregation interface {
 	// LastAt returns the time for last received value.
 	LastAt() time.Time
 
+	// Buckets returns the bucket upper bounds and the number of values received
+	// in each bucket for histogram aggregations, and nil otherwise.
+	Buckets() ([]float64, []int64)
+
 	// Close closes the aggregation object.
 	Close()
 }
@@ -106,12 +111,15 @@ type typeSpecificElemBase interface {
 type lockedAggregation struct {
 	sync.Mutex
 
-	dirty       bool
-	flushed     bool
-	closed      bool
-	sourcesSeen map[uint32]*bitset.BitSet
-	aggregation typeSpecificAggregation
-	prevValues  []float64 // the previously emitted values (one per aggregation type).
+	dirty        bool
+	flushed      bool
+	closed       bool
+	sourcesSeen  map[uint32]*bitset.BitSet
+	sources      int                // number of distinct sources that forwarded values.
+	contributors contributorsSketch // the distinct contributors of the forwarded values.
+	aggregation  typeSpecificAggregation
+	prevValues   []float64         // the previously emitted values (one per aggregation type).
+	exemplars    []metric.Exemplar // a bounded sample of the exemplars of the added values.
 }
 
 type timedAggregation struct {
@@ -189,6 +197,8 @@ func (e *GenericElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion)
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	mu.Annotation = e.retainedAnnotation(lockedAgg.aggregation.Annotation(), mu.Annotation)
+	lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, mu.Exemplars)
 	lockedAgg.aggregation.AddUnion(timestamp, mu)
 	lockedAgg.dirty = true
 	lockedAgg.Unlock()
@@ -196,7 +206,12 @@ func (e *GenericElem) AddUnion(timestamp time.Time, mu unaggregated.MetricUnion)
 }
 
 // AddValue adds a metric value at a given timestamp.
-func (e *GenericElem) AddValue(timestamp time.Time, value float64, annotation []byte) error {
+func (e *GenericElem) AddValue(
+	timestamp time.Time,
+	value float64,
+	annotation []byte,
+	exemplars []metric.Exemplar,
+) error {
 	alignedStart := timestamp.Truncate(e.sp.Resolution().Window).UnixNano()
 	lockedAgg, err := e.findOrCreate(alignedStart, createAggregationOptions{})
 	if err != nil {
@@ -207,7 +222,9 @@ func (e *GenericElem) AddValue(timestamp time.Time, value float64, annotation []
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	annotation = e.retainedAnnotation(lockedAgg.aggregation.Annotation(), annotation)
 	lockedAgg.aggregation.Add(timestamp, value, annotation)
+	lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, exemplars)
 	lockedAgg.dirty = true
 	lockedAgg.Unlock()
 	return nil
@@ -232,6 +249,18 @@ func (e *GenericElem) AddUnique(
 		lockedAgg.Unlock()
 		return errAggregationClosed
 	}
+	isHistogram := len(metric.HistogramBucketUpperBounds) > 0
+	if isHistogram && metric.Version > 0 {
+		lockedAgg.Unlock()
+		return errForwardedHistogramUpdate
+	}
+	// NB: merging the contributors of a value forwarded again is a no-op.
+	if len(metric.Contributors) > 0 && e.opts.EmitContributors() {
+		if err := lockedAgg.contributors.mergeBytes(metric.Contributors); err != nil {
+			lockedAgg.Unlock()
+			return err
+		}
+	}
 	versionsSeen := lockedAgg.sourcesSeen[metadata.SourceID]
 	if versionsSeen == nil {
 		// N.B - these bitsets will be transitively cached through the cached sources seen.
@@ -243,9 +272,26 @@ func (e *GenericElem) AddUnique(
 		lockedAgg.Unlock()
 		return errDuplicateForwardingSource
 	}
+	// NB: cached source sets are cleared rather than emptied, so a source is
+	// new to this window when none of its versions have been seen yet.
+	if versionsSeen.None() {
+		lockedAgg.sources++
+	}
 	versionsSeen.Set(version)
 
-	if metric.Version > 0 {
+	if isHistogram {
+		// NB: forwarded histograms carry their buckets rather than values so
+		// they are merged into the aggregated histogram.
+		lockedAgg.aggregation.AddUnion(timestamp, unaggregated.MetricUnion{
+			Type:                       metric.Type,
+			ID:                         metric.ID,
+			HistogramBucketUpperBounds: metric.HistogramBucketUpperBounds,
+			HistogramBucketCounts:      metric.HistogramBucketCounts,
+			HistogramSum:               metric.HistogramSum,
+			Annotation:                 e.retainedAnnotation(lockedAgg.aggregation.Annotation(), metric.Annotation),
+		})
+		lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, metric.Exemplars)
+	} else if metric.Version > 0 {
 		e.metrics.updatedValues.Inc(1)
 		for i := range metric.Values {
 			if err := lockedAgg.aggregation.UpdateVal(timestamp, metric.Values[i], metric.PrevValues[i]); err != nil {
@@ -253,9 +299,11 @@ func (e *GenericElem) AddUnique(
 			}
 		}
 	} else {
+		annotation := e.retainedAnnotation(lockedAgg.aggregation.Annotation(), metric.Annotation)
 		for _, v := range metric.Values {
-			lockedAgg.aggregation.Add(timestamp, v, metric.Annotation)
+			lockedAgg.aggregation.Add(timestamp, v, annotation)
 		}
+		lockedAgg.exemplars = e.retainedExemplars(lockedAgg.exemplars, metric.Exemplars)
 	}
 
 	lockedAgg.dirty = true
@@ -348,6 +396,8 @@ func (e *GenericElem) Consume(
 		if expired {
 			// Cleanup expired item.
 			e.toConsume[i].lockedAgg.closed = true
+			e.releaseAnnotation(e.toConsume[i].lockedAgg.aggregation.Annotation())
+			e.toConsume[i].lockedAgg.exemplars = nil
 			e.toConsume[i].lockedAgg.aggregation.Close()
 			if e.toConsume[i].lockedAgg.sourcesSeen != nil {
 				e.cachedSourceSetsLock.Lock()
@@ -378,6 +428,47 @@ func (e *GenericElem) Consume(
 	return canCollect
 }
 
+// InProgressValues returns the values of the aggregations that have not expired yet,
+// timestamped with the start of their aggregation windows. Only aggregations that
+// are flushed under the element ID as is are returned, since the values are looked
+// up by the ID they are written to storage with.
+func (e *GenericElem) InProgressValues() []transformation.Datapoint {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed || e.parsedPipeline.HasRollup || len(e.parsedPipeline.Transformations) > 0 {
+		return nil
+	}
+
+	// If several aggregation types are flushed under the same ID, the one flushed
+	// last overwrites the others in storage.
+	aggTypeIdx := -1
+	for i, aggType := range e.aggTypes {
+		if e.idPrefixSuffixType == NoPrefixNoSuffix ||
+			(len(e.FullPrefix(e.opts)) == 0 && len(e.TypeStringFor(e.aggTypesOpts, aggType)) == 0) {
+			aggTypeIdx = i
+		}
+	}
+	if aggTypeIdx < 0 {
+		return nil
+	}
+
+	aggType := e.aggTypes[aggTypeIdx]
+	values := make([]transformation.Datapoint, 0, len(e.values))
+	for _, value := range e.values {
+		value.lockedAgg.Lock()
+		if !value.lockedAgg.closed {
+			if v := value.lockedAgg.aggregation.ValueOf(aggType); !math.IsNaN(v) {
+				values = append(values, transformation.Datapoint{
+					TimeNanos: value.startAtNanos,
+					Value:     v,
+				})
+			}
+		}
+		value.lockedAgg.Unlock()
+	}
+	return values
+}
+
 // Close closes the element.
 func (e *GenericElem) Close() {
 	e.Lock()
@@ -397,6 +488,8 @@ func (e *GenericElem) Close() {
 	for idx := range e.values {
 		// Close the underlying aggregation objects.
 		e.values[idx].lockedAgg.sourcesSeen = nil
+		e.releaseAnnotation(e.values[idx].lockedAgg.aggregation.Annotation())
+		e.values[idx].lockedAgg.exemplars = nil
 		e.values[idx].lockedAgg.aggregation.Close()
 		e.values[idx].Release()
 	}
@@ -522,6 +615,21 @@ func (e *GenericElem) processValueWithAggregationLock(
 		discardNaNValues = e.opts.DiscardNaNAggregatedValues()
 		emitted          bool
 	)
+	upperBounds, counts := lockedAgg.aggregation.Buckets()
+	isForwardedHistogram := e.parsedPipeline.HasRollup && len(upperBounds) > 0
+	// NB: the contributors received by an intermediate rollup are forwarded in
+	// place of the contributor of the series.
+	contributors := e.contributor
+	if !lockedAgg.contributors.empty() {
+		contributors = &lockedAgg.contributors
+	}
+	// NB: the sum of the values forwarded by several sources decreases when any
+	// one of them resets, and the sum is not a counter that restarted from zero,
+	// so counter reset detection only applies to series with a single source.
+	if lockedAgg.sources > 1 && e.transformationFlags.CounterResetDetection {
+		e.transformationFlags.CounterResetDetection = false
+		e.metrics.counterResetDetectionDisabled.Inc(1)
+	}
 	for aggTypeIdx, aggType := range e.aggTypes {
 		var extraDp transformation.Datapoint
 		value := lockedAgg.aggregation.ValueOf(aggType)
@@ -555,7 +663,7 @@ func (e *GenericElem) processValueWithAggregationLock(
 					TimeNanos: int64(timeNanos),
 					Value:     value,
 				}
-				res := binaryOp.Evaluate(prev, curr, transformation.FeatureFlags{})
+				res := binaryOp.Evaluate(prev, curr, e.transformationFlags)
 
 				// NB: we only need to record the value needed for derivative transformations.
 				// We currently only support first-order derivative transformations so we only
@@ -597,6 +705,10 @@ func (e *GenericElem) processValueWithAggregationLock(
 		}
 		emitted = true
 
+		if isForwardedHistogram {
+			// The buckets are forwarded below once all the values are processed.
+			continue
+		}
 		if !e.parsedPipeline.HasRollup {
 			toFlush := make([]transformation.Datapoint, 0, 2)
 			toFlush = append(toFlush, transformation.Datapoint{
@@ -609,16 +721,60 @@ func (e *GenericElem) processValueWithAggregationLock(
 			for _, point := range toFlush {
 				switch e.idPrefixSuffixType {
 				case NoPrefixNoSuffix:
-					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+					flushLocalFn(nil, e.id, nil, point.TimeNanos, point.Value,
+						lockedAgg.aggregation.Annotation(), lockedAgg.exemplars, aggType, e.sp)
 				case WithPrefixWithSuffix:
 					flushLocalFn(e.FullPrefix(e.opts), e.id, e.TypeStringFor(e.aggTypesOpts, aggType),
-						point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), e.sp)
+						point.TimeNanos, point.Value, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars, aggType, e.sp)
 				}
 			}
 		} else {
 			forwardedAggregationKey, _ := e.ForwardedAggregationKey()
 			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey,
-				int64(timeNanos), value, prevValue, lockedAgg.aggregation.Annotation())
+				int64(timeNanos), value, prevValue, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars,
+				forwardedHistogram{}, contributors)
+		}
+	}
+
+	if emitted && isForwardedHistogram {
+		// Forward the buckets of histograms rather than their aggregated values so
+		// the rollup aggregates the buckets received from every source.
+		forwardedAggregationKey, _ := e.ForwardedAggregationKey()
+		flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey,
+			int64(timeNanos), nan, nan, lockedAgg.aggregation.Annotation(), lockedAgg.exemplars,
+			forwardedHistogram{
+				upperBounds: upperBounds,
+				counts:      counts,
+				sum:         lockedAgg.aggregation.ValueOf(maggregation.Sum),
+			}, contributors)
+	}
+
+	if emitted && !e.parsedPipeline.HasRollup && !lockedAgg.contributors.empty() && e.opts.EmitContributors() {
+		// Emit the estimated number of contributors to the rollup output alongside it
+		// so consumers can detect windows where only part of the fleet reported.
+		var prefix []byte
+		if e.idPrefixSuffixType == WithPrefixWithSuffix {
+			prefix = e.FullPrefix(e.opts)
+		}
+		flushLocalFn(prefix, e.id, e.opts.ContributorsSuffix(), int64(timeNanos),
+			math.Round(lockedAgg.contributors.estimate()), nil, nil, maggregation.Max, e.sp)
+	}
+
+	if emitted && !e.parsedPipeline.HasRollup {
+		// Emit the cumulative count of each bucket of aggregated histograms keyed by
+		// the bucket upper bound so the histogram can be reconstructed downstream.
+		var (
+			prefix     []byte
+			cumulative int64
+		)
+		if e.idPrefixSuffixType == WithPrefixWithSuffix {
+			prefix = e.FullPrefix(e.opts)
+		}
+		for i, upperBound := range upperBounds {
+			cumulative += counts[i]
+			suffix := append([]byte(nil), e.opts.HistogramBucketSuffix()...)
+			suffix = strconv.AppendFloat(suffix, upperBound, 'f', -1, 64)
+			flushLocalFn(prefix, e.id, suffix, int64(timeNanos), float64(cumulative), nil, nil, maggregation.Sum, e.sp)
 		}
 	}
 	return emitted
@@ -21,8 +21,10 @@
 package handler
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/filter"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
@@ -31,18 +33,45 @@ import (
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/msg/producer/buffer"
 	"github.com/m3db/m3/src/msg/producer/config"
+	"github.com/m3db/m3/src/msg/protocol/proto"
 	"github.com/m3db/m3/src/x/instrument"
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/retry"
+	"github.com/m3db/m3/src/x/serialize"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// maxMessageMetadataOverhead is the room reserved for the m3msg message
+	// metadata wrapping each payload when deriving the max payload size from
+	// the encoder limit.
+	maxMessageMetadataOverhead = 64
+
+	// defaultKafkaMaxMessageSize is the default max size of the messages
+	// accepted by Kafka brokers.
+	defaultKafkaMaxMessageSize = 1000000
+
+	defaultOTLPExportTimeout = 10 * time.Second
+	defaultOTLPMaxBatchSize  = 1000
 )
 
 var (
 	errNoHandlerConfiguration                   = errors.New("no handler configuration")
-	errNoDynamicOrStaticBackendConfiguration    = errors.New("neither dynamic nor static backend was configured")
+	errNoDynamicOrStaticBackendConfiguration    = errors.New("neither dynamic, static, kafka, otlp nor file backend was configured")
 	errBothDynamicAndStaticBackendConfiguration = errors.New("both dynamic and static backend were configured")
+	errMultipleBackendConfiguration             = errors.New("multiple backends were configured")
+	errNoKafkaProducerFn                        = errors.New("kafka backend configured without a kafka producer fn")
+	errNoKafkaBrokers                           = errors.New("no kafka brokers configured")
+	errNoKafkaTopic                             = errors.New("no kafka topic configured")
+	errNoKafkaPartitions                        = errors.New("no kafka partitions configured")
+	errNoOTLPEndpoint                           = errors.New("no otlp endpoint configured")
+	errNoFilePath                               = errors.New("no file path configured")
 )
 
 // FlushHandlerConfiguration configures flush handlers.
@@ -55,6 +84,7 @@ func (c FlushHandlerConfiguration) NewHandler(
 	cs client.Client,
 	instrumentOpts instrument.Options,
 	rwOpts xio.Options,
+	kafkaProducerFn writer.KafkaProducerFn,
 ) (Handler, error) {
 	if len(c.Handlers) == 0 {
 		return nil, errNoHandlerConfiguration
@@ -63,7 +93,7 @@ func (c FlushHandlerConfiguration) NewHandler(
 		handlers = make([]Handler, 0, len(c.Handlers))
 	)
 	for _, hc := range c.Handlers {
-		handler, err := hc.newHandler(cs, instrumentOpts, rwOpts)
+		handler, err := hc.newHandler(cs, instrumentOpts, rwOpts, kafkaProducerFn)
 		if err != nil {
 			return nil, err
 		}
@@ -81,6 +111,16 @@ type writerConfiguration struct {
 
 	// How frequent is the encoding time sampled and included in the payload.
 	EncodingTimeSamplingRate float64 `yaml:"encodingTimeSamplingRate" validate:"min=0.0,max=1.0"`
+
+	// MaxMessageSize caps the size of each message produced, payloads larger
+	// than this are split across multiple messages if SplitMessages is set.
+	// Defaults to the largest size accepted by the producer.
+	MaxMessageSize *int `yaml:"maxMessageSize"`
+
+	// SplitMessages splits encoded metrics larger than the max message size
+	// across multiple messages rather than having them rejected by the
+	// producer. All consumers must be able to reassemble split metrics.
+	SplitMessages bool `yaml:"splitMessages"`
 }
 
 func (c writerConfiguration) NewWriterOptions(
@@ -107,16 +147,65 @@ type flushHandlerConfiguration struct {
 
 	// DynamicBackend configures the dynamic backend.
 	DynamicBackend *dynamicBackendConfiguration `yaml:"dynamicBackend"`
+
+	// Kafka configures the kafka backend.
+	Kafka *kafkaBackendConfiguration `yaml:"kafka"`
+
+	// OTLP configures the OpenTelemetry collector backend.
+	OTLP *otlpBackendConfiguration `yaml:"otlp"`
+
+	// File configures the file backend.
+	File *fileBackendConfiguration `yaml:"file"`
+
+	// Queue decouples flushes from the backend, queueing up flushed metrics
+	// and writing them to the backend in the background with retries.
+	Queue *queueConfiguration `yaml:"queue"`
+
+	// Shadow mirrors the metrics written to the backend to a shadow handler,
+	// e.g. to validate a new cluster before cutting over to it.
+	Shadow *shadowConfiguration `yaml:"shadow"`
 }
 
 func (c flushHandlerConfiguration) newHandler(
 	cs client.Client,
 	instrumentOpts instrument.Options,
 	rwOpts xio.Options,
+	kafkaProducerFn writer.KafkaProducerFn,
 ) (Handler, error) {
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
+	handler, err := c.newBackendHandler(cs, instrumentOpts, rwOpts, kafkaProducerFn)
+	if err != nil {
+		return nil, err
+	}
+	if c.Queue != nil {
+		handler, err = c.Queue.newQueuedHandler(handler, c.backendName(), instrumentOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.Shadow == nil {
+		return handler, nil
+	}
+	return c.Shadow.newShadowHandler(handler, cs, instrumentOpts, rwOpts, kafkaProducerFn)
+}
+
+func (c flushHandlerConfiguration) newBackendHandler(
+	cs client.Client,
+	instrumentOpts instrument.Options,
+	rwOpts xio.Options,
+	kafkaProducerFn writer.KafkaProducerFn,
+) (Handler, error) {
+	if c.File != nil {
+		return NewFileHandler(c.File.Path, instrumentOpts.Logger())
+	}
+	if c.Kafka != nil {
+		return c.Kafka.newKafkaHandler(kafkaProducerFn, instrumentOpts)
+	}
+	if c.OTLP != nil {
+		return c.OTLP.newOTLPHandler(instrumentOpts)
+	}
 	if c.DynamicBackend != nil {
 		return c.DynamicBackend.newProtobufHandler(
 			cs,
@@ -134,7 +223,52 @@ func (c flushHandlerConfiguration) newHandler(
 	}
 }
 
+// backendName returns the name of the configured backend.
+func (c flushHandlerConfiguration) backendName() string {
+	switch {
+	case c.File != nil:
+		return c.File.Name
+	case c.Kafka != nil:
+		return c.Kafka.Name
+	case c.OTLP != nil:
+		return c.OTLP.Name
+	case c.DynamicBackend != nil:
+		return c.DynamicBackend.Name
+	default:
+		return string(c.StaticBackend.Type)
+	}
+}
+
 func (c flushHandlerConfiguration) Validate() error {
+	if c.Queue != nil {
+		if err := c.Queue.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Shadow != nil {
+		if err := c.Shadow.Validate(); err != nil {
+			return err
+		}
+	}
+	var numOtherBackends int
+	for _, configured := range []bool{c.Kafka != nil, c.OTLP != nil, c.File != nil} {
+		if configured {
+			numOtherBackends++
+		}
+	}
+	if numOtherBackends > 0 {
+		if c.StaticBackend != nil || c.DynamicBackend != nil || numOtherBackends > 1 {
+			return errMultipleBackendConfiguration
+		}
+		switch {
+		case c.Kafka != nil:
+			return c.Kafka.Validate()
+		case c.OTLP != nil:
+			return c.OTLP.Validate()
+		default:
+			return c.File.Validate()
+		}
+	}
 	if c.StaticBackend == nil && c.DynamicBackend == nil {
 		return errNoDynamicOrStaticBackendConfiguration
 	}
@@ -144,6 +278,104 @@ func (c flushHandlerConfiguration) Validate() error {
 	return nil
 }
 
+type queueConfiguration struct {
+	// Size is the max number of metrics queued for each flushed shard.
+	Size int `yaml:"size"`
+
+	// DropPolicy is what happens to flushed metrics when the queue is full,
+	// defaults to dropping the oldest queued metrics.
+	DropPolicy DropPolicy `yaml:"dropPolicy"`
+
+	// Retrier configures the retries of failed flushes to the backend.
+	Retrier retry.Configuration `yaml:"retrier"`
+}
+
+func (c *queueConfiguration) Validate() error {
+	if c.Size <= 0 {
+		return errInvalidQueueSize
+	}
+	return nil
+}
+
+func (c *queueConfiguration) newQueuedHandler(
+	handler Handler,
+	backendName string,
+	instrumentOpts instrument.Options,
+) (Handler, error) {
+	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+		"backend":   backendName,
+		"component": "queue",
+	})
+	dropPolicy := c.DropPolicy
+	if dropPolicy == "" {
+		dropPolicy = DropOldest
+	}
+	return NewQueuedHandler(handler, QueueOptions{
+		Size:              c.Size,
+		DropPolicy:        dropPolicy,
+		RetryOptions:      c.Retrier.NewOptions(scope.SubScope("retrier")),
+		InstrumentOptions: instrumentOpts.SetMetricsScope(scope),
+	})
+}
+
+type shadowConfiguration struct {
+	// Handler is the shadow handler the metrics are mirrored to.
+	Handler flushHandlerConfiguration `yaml:"handler"`
+
+	// SampleRate is the fraction of series whose delivery is compared between
+	// the backend and the shadow handler.
+	SampleRate float64 `yaml:"sampleRate" validate:"min=0.0,max=1.0"`
+}
+
+func (c *shadowConfiguration) Validate() error {
+	if err := c.Handler.Validate(); err != nil {
+		return fmt.Errorf("invalid shadow handler: %w", err)
+	}
+	return ShadowOptions{SampleRate: c.SampleRate}.Validate()
+}
+
+func (c *shadowConfiguration) newShadowHandler(
+	primary Handler,
+	cs client.Client,
+	instrumentOpts instrument.Options,
+	rwOpts xio.Options,
+	kafkaProducerFn writer.KafkaProducerFn,
+) (Handler, error) {
+	shadow, err := c.Handler.newHandler(cs, instrumentOpts, rwOpts, kafkaProducerFn)
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+	h, err := NewShadowHandler(primary, shadow, ShadowOptions{
+		SampleRate:        c.SampleRate,
+		InstrumentOptions: instrumentOpts,
+	})
+	if err != nil {
+		primary.Close()
+		shadow.Close()
+		return nil, err
+	}
+	instrumentOpts.Logger().Info("mirroring flushed metrics to shadow handler",
+		zap.String("shadow", c.Handler.backendName()),
+		zap.Float64("sampleRate", c.SampleRate))
+	return h, nil
+}
+
+type fileBackendConfiguration struct {
+	// Name of the backend.
+	Name string `yaml:"name"`
+
+	// Path is the path of the file metrics are appended to.
+	Path string `yaml:"path"`
+}
+
+func (c *fileBackendConfiguration) Validate() error {
+	if c.Path == "" {
+		return errNoFilePath
+	}
+	return nil
+}
+
 type dynamicBackendConfiguration struct {
 	// Name of the backend.
 	Name string `yaml:"name"`
@@ -195,10 +427,201 @@ func (c *dynamicBackendConfiguration) newProtobufHandler(
 			zap.Stringer("service", sid))
 	}
 	wOpts := c.Writer.NewWriterOptions(instrumentOpts)
-	instrumentOpts.Logger().Info("created flush handler with protobuf encoding", zap.String("name", c.Name))
+	if c.Writer.SplitMessages {
+		wOpts = wOpts.SetMaxMessageSize(c.maxMessageSize())
+	}
+	instrumentOpts.Logger().Info("created flush handler with protobuf encoding",
+		zap.String("name", c.Name),
+		zap.Int("splitMessageSize", wOpts.MaxMessageSize()))
 	return NewProtobufHandler(p, c.HashType, wOpts), nil
 }
 
+// maxMessageSize negotiates the max size of the messages produced by the
+// writer, which is the smallest of the configured writer limit and the limits
+// enforced by the producer buffer and encoder.
+func (c *dynamicBackendConfiguration) maxMessageSize() int {
+	bufferMax := buffer.NewOptions().MaxMessageSize()
+	if c.Producer.Buffer.MaxMessageSize != nil {
+		bufferMax = *c.Producer.Buffer.MaxMessageSize
+	}
+	encoderMax := proto.NewOptions().MaxMessageSize()
+	if c.Producer.Writer.Encoder != nil && c.Producer.Writer.Encoder.MaxMessageSize != nil {
+		encoderMax = *c.Producer.Writer.Encoder.MaxMessageSize
+	}
+	encoderMax -= maxMessageMetadataOverhead
+
+	maxSize := bufferMax
+	if encoderMax < maxSize {
+		maxSize = encoderMax
+	}
+	if c.Writer.MaxMessageSize != nil && *c.Writer.MaxMessageSize > 0 &&
+		*c.Writer.MaxMessageSize < maxSize {
+		maxSize = *c.Writer.MaxMessageSize
+	}
+	if maxSize < 0 {
+		return 0
+	}
+	return maxSize
+}
+
+type kafkaBackendConfiguration struct {
+	// Name of the backend.
+	Name string `yaml:"name"`
+
+	// Brokers are the addresses of the kafka brokers.
+	Brokers []string `yaml:"brokers"`
+
+	// Topic is the kafka topic metrics are written to.
+	Topic string `yaml:"topic"`
+
+	// NumPartitions is the number of partitions of the topic, each metric
+	// is written to the partition of its shard.
+	NumPartitions uint32 `yaml:"numPartitions"`
+
+	// Hashing function type.
+	HashType sharding.HashType `yaml:"hashType"`
+
+	// Writer configs the writer options.
+	Writer writerConfiguration `yaml:"writer"`
+}
+
+func (c *kafkaBackendConfiguration) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errNoKafkaBrokers
+	}
+	if c.Topic == "" {
+		return errNoKafkaTopic
+	}
+	if c.NumPartitions == 0 {
+		return errNoKafkaPartitions
+	}
+	return nil
+}
+
+func (c *kafkaBackendConfiguration) newKafkaHandler(
+	kafkaProducerFn writer.KafkaProducerFn,
+	instrumentOpts instrument.Options,
+) (Handler, error) {
+	if kafkaProducerFn == nil {
+		return nil, errNoKafkaProducerFn
+	}
+	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+		"backend":   c.Name,
+		"component": "kafka-producer",
+	})
+	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
+	maxMessageSize := c.maxMessageSize()
+	p, err := kafkaProducerFn(writer.KafkaProducerOptions{
+		Brokers:           c.Brokers,
+		MaxMessageBytes:   maxMessageSize,
+		InstrumentOptions: instrumentOpts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	wOpts := c.Writer.NewWriterOptions(instrumentOpts)
+	instrumentOpts.Logger().Info("created kafka flush handler with protobuf encoding",
+		zap.String("name", c.Name),
+		zap.String("topic", c.Topic),
+		zap.Uint32("numPartitions", c.NumPartitions),
+		zap.Int("maxMessageSize", maxMessageSize))
+	return NewKafkaHandler(p, c.Topic, c.NumPartitions, c.HashType, wOpts), nil
+}
+
+// maxMessageSize returns the max size of the messages produced, which defaults
+// to the max size accepted by kafka brokers. Encoded metrics are never split
+// across kafka messages, so larger metrics are rejected by the producer.
+func (c *kafkaBackendConfiguration) maxMessageSize() int {
+	if c.Writer.MaxMessageSize != nil && *c.Writer.MaxMessageSize > 0 {
+		return *c.Writer.MaxMessageSize
+	}
+	return defaultKafkaMaxMessageSize
+}
+
+type otlpBackendConfiguration struct {
+	// Name of the backend.
+	Name string `yaml:"name"`
+
+	// Endpoint is the address of the OpenTelemetry collector.
+	Endpoint string `yaml:"endpoint"`
+
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool `yaml:"insecure"`
+
+	// ExportTimeout is the timeout of each export.
+	ExportTimeout *time.Duration `yaml:"exportTimeout"`
+
+	// MaxBatchSize is the max number of metrics exported per request.
+	MaxBatchSize *int `yaml:"maxBatchSize"`
+
+	// NameTag is the tag holding the metric name, defaults to __name__.
+	NameTag string `yaml:"nameTag"`
+
+	// ResourceAttributes are the attributes of the resource exporting the
+	// metrics.
+	ResourceAttributes map[string]string `yaml:"resourceAttributes"`
+
+	// TagDecoderPool configures the pool of decoders of the tags encoded in
+	// metric IDs.
+	TagDecoderPool pool.ObjectPoolConfiguration `yaml:"tagDecoderPool"`
+}
+
+func (c *otlpBackendConfiguration) Validate() error {
+	if c.Endpoint == "" {
+		return errNoOTLPEndpoint
+	}
+	return nil
+}
+
+func (c *otlpBackendConfiguration) newOTLPHandler(
+	instrumentOpts instrument.Options,
+) (Handler, error) {
+	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+		"backend":   c.Name,
+		"component": "otlp-exporter",
+	})
+	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})),
+	}
+	if c.Insecure {
+		dialOpts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	conn, err := grpc.Dial(c.Endpoint, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	iOpts := instrumentOpts.SetMetricsScope(scope.SubScope("tag-decoder-pool"))
+	tagDecoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(serialize.TagDecoderOptionsConfig{}),
+		c.TagDecoderPool.NewObjectPoolOptions(iOpts))
+	tagDecoderPool.Init()
+
+	exportTimeout := defaultOTLPExportTimeout
+	if c.ExportTimeout != nil {
+		exportTimeout = *c.ExportTimeout
+	}
+	maxBatchSize := defaultOTLPMaxBatchSize
+	if c.MaxBatchSize != nil {
+		maxBatchSize = *c.MaxBatchSize
+	}
+	instrumentOpts.Logger().Info("created otlp flush handler",
+		zap.String("name", c.Name),
+		zap.String("endpoint", c.Endpoint),
+		zap.Duration("exportTimeout", exportTimeout),
+		zap.Int("maxBatchSize", maxBatchSize))
+	return NewOTLPHandler(conn, writer.OTLPWriterOptions{
+		TagDecoderPool:     tagDecoderPool,
+		NameTag:            []byte(c.NameTag),
+		ResourceAttributes: c.ResourceAttributes,
+		ExportTimeout:      exportTimeout,
+		MaxBatchSize:       maxBatchSize,
+		InstrumentOptions:  instrumentOpts,
+	}), nil
+}
+
 type storagePolicyFilterConfiguration struct {
 	ServiceID       services.ServiceIDConfiguration `yaml:"serviceID" validate:"nonzero"`
 	StoragePolicies []policy.StoragePolicy          `yaml:"storagePolicies" validate:"nonzero"`
@@ -21,8 +21,12 @@
 package handler
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/x/instrument"
+
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -67,3 +71,213 @@ dynamicBackend:
 	require.Error(t, err)
 	require.Equal(t, errBothDynamicAndStaticBackendConfiguration, err)
 }
+
+func TestKafkaBackendConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	kafkaAndStatic := `
+staticBackend:
+  type: blackhole
+kafka:
+  name: test
+`
+	require.NoError(t, yaml.Unmarshal([]byte(kafkaAndStatic), &cfg))
+	require.Equal(t, errMultipleBackendConfiguration, cfg.Validate())
+
+	noTopic := `
+kafka:
+  name: test
+  brokers:
+    - localhost:9092
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(noTopic), &cfg))
+	require.Equal(t, errNoKafkaTopic, cfg.Validate())
+
+	str := `
+kafka:
+  name: test
+  brokers:
+    - localhost:9092
+  topic: aggregated
+  numPartitions: 64
+  hashType: murmur32
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, []string{"localhost:9092"}, cfg.Kafka.Brokers)
+	require.Equal(t, uint32(64), cfg.Kafka.NumPartitions)
+	require.Equal(t, defaultKafkaMaxMessageSize, cfg.Kafka.maxMessageSize())
+
+	_, err := cfg.newHandler(nil, instrument.NewOptions(), nil, nil)
+	require.Equal(t, errNoKafkaProducerFn, err)
+
+	var producerOpts writer.KafkaProducerOptions
+	h, err := cfg.newHandler(nil, instrument.NewOptions(), nil,
+		func(opts writer.KafkaProducerOptions) (writer.KafkaProducer, error) {
+			producerOpts = opts
+			return nil, nil
+		})
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	require.Equal(t, []string{"localhost:9092"}, producerOpts.Brokers)
+	require.Equal(t, defaultKafkaMaxMessageSize, producerOpts.MaxMessageBytes)
+}
+
+func TestOTLPBackendConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	kafkaAndOTLP := `
+kafka:
+  name: kafka
+otlp:
+  name: otlp
+`
+	require.NoError(t, yaml.Unmarshal([]byte(kafkaAndOTLP), &cfg))
+	require.Equal(t, errMultipleBackendConfiguration, cfg.Validate())
+
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(`otlp: {name: test}`), &cfg))
+	require.Equal(t, errNoOTLPEndpoint, cfg.Validate())
+
+	str := `
+otlp:
+  name: test
+  endpoint: localhost:4317
+  insecure: true
+  exportTimeout: 5s
+  maxBatchSize: 100
+  resourceAttributes:
+    service.name: m3aggregator
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	h, err := cfg.newHandler(nil, instrument.NewOptions(), nil, nil)
+	require.NoError(t, err)
+	h.Close()
+}
+
+func TestFileBackendConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	fileAndOTLP := `
+file:
+  name: file
+  path: /tmp/metrics
+otlp:
+  name: otlp
+`
+	require.NoError(t, yaml.Unmarshal([]byte(fileAndOTLP), &cfg))
+	require.Equal(t, errMultipleBackendConfiguration, cfg.Validate())
+
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(`file: {name: test}`), &cfg))
+	require.Equal(t, errNoFilePath, cfg.Validate())
+}
+
+func TestQueueConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	noSize := `
+staticBackend:
+  type: blackhole
+queue:
+  dropPolicy: block
+`
+	require.NoError(t, yaml.Unmarshal([]byte(noSize), &cfg))
+	require.Equal(t, errInvalidQueueSize, cfg.Validate())
+
+	str := `
+file:
+  name: test
+  path: ` + filepath.Join(t.TempDir(), "metrics") + `
+queue:
+  size: 1000
+  dropPolicy: dropNewest
+  retrier:
+    initialBackoff: 10ms
+    maxRetries: 3
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, DropNewest, cfg.Queue.DropPolicy)
+	require.Equal(t, "test", cfg.backendName())
+
+	h, err := cfg.newHandler(nil, instrument.NewOptions(), nil, nil)
+	require.NoError(t, err)
+	_, ok := h.(*queuedHandler)
+	require.True(t, ok)
+	h.Close()
+}
+
+func TestShadowConfiguration(t *testing.T) {
+	var cfg flushHandlerConfiguration
+
+	noShadowBackend := `
+staticBackend:
+  type: blackhole
+shadow:
+  sampleRate: 0.1
+`
+	require.NoError(t, yaml.Unmarshal([]byte(noShadowBackend), &cfg))
+	require.Error(t, cfg.Validate())
+
+	str := `
+staticBackend:
+  type: blackhole
+shadow:
+  handler:
+    file:
+      name: shadow
+      path: ` + filepath.Join(t.TempDir(), "metrics") + `
+    queue:
+      size: 1000
+  sampleRate: 0.1
+`
+	cfg = flushHandlerConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	h, err := cfg.newHandler(nil, instrument.NewOptions(), nil, nil)
+	require.NoError(t, err)
+	sh, ok := h.(*shadowHandler)
+	require.True(t, ok)
+	_, ok = sh.shadow.(*queuedHandler)
+	require.True(t, ok)
+	h.Close()
+}
+
+func TestDynamicBackendMaxMessageSize(t *testing.T) {
+	var cfg dynamicBackendConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(`name: test`), &cfg))
+	require.Equal(t, 1024*1024, cfg.maxMessageSize())
+
+	str := `
+name: test
+producer:
+  buffer:
+    maxMessageSize: 2000
+  writer:
+    encoder:
+      maxMessageSize: 1000
+`
+	cfg = dynamicBackendConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.Equal(t, 1000-maxMessageMetadataOverhead, cfg.maxMessageSize())
+
+	str = `
+name: test
+producer:
+  buffer:
+    maxMessageSize: 2000
+writer:
+  maxMessageSize: 500
+`
+	cfg = dynamicBackendConfiguration{}
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.Equal(t, 500, cfg.maxMessageSize())
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"os"
+	"sync"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+type fileHandler struct {
+	sync.Mutex
+
+	file   *os.File
+	logger *zap.Logger
+}
+
+// NewFileHandler creates a new handler appending metrics as JSON lines to the
+// file at the given path, creating the file if needed.
+func NewFileHandler(path string, logger *zap.Logger) (Handler, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileHandler{file: file, logger: logger}, nil
+}
+
+func (h *fileHandler) NewWriter(tally.Scope) (writer.Writer, error) {
+	return writer.NewFileWriter(h.file, h), nil
+}
+
+func (h *fileHandler) Close() {
+	if err := h.file.Close(); err != nil {
+		h.logger.Error("could not close file", zap.String("path", h.file.Name()), zap.Error(err))
+	}
+}
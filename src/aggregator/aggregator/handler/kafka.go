@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/aggregator/sharding"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+type kafkaHandler struct {
+	p             writer.KafkaProducer
+	topic         string
+	numPartitions uint32
+	hashType      sharding.HashType
+	opts          writer.Options
+}
+
+// NewKafkaHandler creates a new handler writing metrics encoded in protobuf
+// to a Kafka topic, with each metric written to the partition of its shard.
+func NewKafkaHandler(
+	p writer.KafkaProducer,
+	topic string,
+	numPartitions uint32,
+	hashType sharding.HashType,
+	opts writer.Options,
+) Handler {
+	return kafkaHandler{
+		p:             p,
+		topic:         topic,
+		numPartitions: numPartitions,
+		hashType:      hashType,
+		opts:          opts,
+	}
+}
+
+func (h kafkaHandler) NewWriter(scope tally.Scope) (writer.Writer, error) {
+	iOpts := h.opts.InstrumentOptions()
+	shardFn, err := h.hashType.ShardFn()
+	if err != nil {
+		return nil, err
+	}
+	return writer.NewKafkaWriter(
+		h.p,
+		h.topic,
+		h.numPartitions,
+		shardFn,
+		h.opts.SetInstrumentOptions(iOpts.SetMetricsScope(scope)),
+	), nil
+}
+
+func (h kafkaHandler) Close() {
+	if err := h.p.Close(); err != nil {
+		h.opts.InstrumentOptions().Logger().Error("could not close kafka producer",
+			zap.String("topic", h.topic), zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/aggregator/sharding"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type testKafkaProducer struct {
+	msgs   []writer.KafkaMessage
+	closed bool
+}
+
+func (p *testKafkaProducer) Produce(m writer.KafkaMessage) error {
+	p.msgs = append(p.msgs, m)
+	return nil
+}
+
+func (p *testKafkaProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestKafkaHandler(t *testing.T) {
+	p := &testKafkaProducer{}
+	h := NewKafkaHandler(p, "aggregated", 16, sharding.DefaultHash, writer.NewOptions())
+
+	w, err := h.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.False(t, p.closed)
+
+	h.Close()
+	require.True(t, p.closed)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+
+	"github.com/uber-go/tally"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type otlpHandler struct {
+	conn *grpc.ClientConn
+	opts writer.OTLPWriterOptions
+}
+
+// NewOTLPHandler creates a new handler exporting metrics in OTLP to the
+// OpenTelemetry collector at the other end of the connection.
+func NewOTLPHandler(
+	conn *grpc.ClientConn,
+	opts writer.OTLPWriterOptions,
+) Handler {
+	opts.Client = colmetricspb.NewMetricsServiceClient(conn)
+	return otlpHandler{
+		conn: conn,
+		opts: opts,
+	}
+}
+
+func (h otlpHandler) NewWriter(scope tally.Scope) (writer.Writer, error) {
+	opts := h.opts
+	opts.InstrumentOptions = opts.InstrumentOptions.SetMetricsScope(scope)
+	return writer.NewOTLPWriter(opts)
+}
+
+func (h otlpHandler) Close() {
+	if err := h.conn.Close(); err != nil {
+		h.opts.InstrumentOptions.Logger().Error("could not close otlp connection",
+			zap.String("target", h.conn.Target()), zap.Error(err))
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/retry"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// DropPolicy is what a queued handler does with a flushed batch of metrics
+// when its queue is full.
+type DropPolicy string
+
+// A list of supported drop policies.
+const (
+	// DropOldest drops the oldest queued batches to make room for the new batch.
+	DropOldest DropPolicy = "dropOldest"
+
+	// DropNewest drops the new batch.
+	DropNewest DropPolicy = "dropNewest"
+
+	// Block blocks the flush until there is room for the new batch.
+	Block DropPolicy = "block"
+)
+
+var (
+	validDropPolicies = []DropPolicy{
+		DropOldest,
+		DropNewest,
+		Block,
+	}
+
+	errInvalidQueueSize    = errors.New("queue size must be positive")
+	errQueuedHandlerClosed = errors.New("queued handler is closed")
+)
+
+// UnmarshalYAML unmarshals YAML into a drop policy.
+func (p *DropPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	validPolicies := make([]string, 0, len(validDropPolicies))
+	for _, valid := range validDropPolicies {
+		if str == string(valid) {
+			*p = valid
+			return nil
+		}
+		validPolicies = append(validPolicies, string(valid))
+	}
+	return fmt.Errorf("invalid drop policy '%s' valid policies are: %s",
+		str, strings.Join(validPolicies, ", "))
+}
+
+// QueueOptions configures the queues of a queued handler.
+type QueueOptions struct {
+	// Size is the max number of metrics queued for each writer of the
+	// handler. A batch larger than the size is still queued if the queue is
+	// empty.
+	Size int
+
+	// DropPolicy is what happens to a flushed batch when the queue is full.
+	DropPolicy DropPolicy
+
+	// RetryOptions configures the retries of failed flushes to the
+	// destination.
+	RetryOptions retry.Options
+
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+type queuedHandlerMetrics struct {
+	enqueued          tally.Counter
+	droppedQueueFull  tally.Counter
+	droppedWriteError tally.Counter
+	flushErrors       tally.Counter
+	queueSize         tally.Gauge
+	size              *atomic.Int64
+}
+
+func newQueuedHandlerMetrics(scope tally.Scope) queuedHandlerMetrics {
+	return queuedHandlerMetrics{
+		enqueued:          scope.Counter("enqueued"),
+		droppedQueueFull:  scope.Tagged(map[string]string{"reason": "queue-full"}).Counter("dropped"),
+		droppedWriteError: scope.Tagged(map[string]string{"reason": "write-error"}).Counter("dropped"),
+		flushErrors:       scope.Counter("flush-errors"),
+		queueSize:         scope.Gauge("queue-size"),
+		size:              atomic.NewInt64(0),
+	}
+}
+
+// updateQueueSize reports the number of metrics queued across the queues of
+// the handler.
+func (m queuedHandlerMetrics) updateQueueSize(delta int) {
+	m.queueSize.Update(float64(m.size.Add(int64(delta))))
+}
+
+// queuedHandler decouples flushes from a destination. Each writer it creates
+// holds on to the metrics written until flushed, and flushing queues them up
+// for a background goroutine writing them to a writer of the destination
+// dedicated to it, so a slow or failing destination neither blocks the flush
+// nor the other destinations of a broadcast handler, and the flushes of one
+// shard are not serialized with the flushes of the others.
+type queuedHandler struct {
+	sync.Mutex
+
+	handler Handler
+	opts    QueueOptions
+	retrier retry.Retrier
+	logger  *zap.Logger
+	metrics queuedHandlerMetrics
+	queues  []*writerQueue
+	closed  bool
+	closing atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// NewQueuedHandler creates a new handler queueing up flushed metrics and
+// writing them to the given handler in the background.
+func NewQueuedHandler(h Handler, opts QueueOptions) (Handler, error) {
+	if opts.Size <= 0 {
+		return nil, errInvalidQueueSize
+	}
+	return &queuedHandler{
+		handler: h,
+		opts:    opts,
+		retrier: retry.NewRetrier(opts.RetryOptions),
+		logger:  opts.InstrumentOptions.Logger(),
+		metrics: newQueuedHandlerMetrics(opts.InstrumentOptions.MetricsScope().SubScope("queue")),
+	}, nil
+}
+
+func (h *queuedHandler) NewWriter(scope tally.Scope) (writer.Writer, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.closed {
+		return nil, errQueuedHandlerClosed
+	}
+	w, err := h.handler.NewWriter(scope)
+	if err != nil {
+		return nil, err
+	}
+	q := &writerQueue{
+		handler: h,
+		writer:  w,
+		queue:   newBatchQueue(h.opts.Size, h.opts.DropPolicy, h.metrics),
+	}
+	h.queues = append(h.queues, q)
+	h.wg.Add(1)
+	go q.writeLoop()
+	return &queuedWriter{queue: q.queue}, nil
+}
+
+// Close stops retrying, writes out the metrics still queued and closes the
+// destination.
+func (h *queuedHandler) Close() {
+	h.Lock()
+	h.closed = true
+	queues := h.queues
+	h.Unlock()
+
+	h.closing.Store(true)
+	for _, q := range queues {
+		q.queue.Close()
+	}
+	h.wg.Wait()
+	for _, q := range queues {
+		if err := q.writer.Close(); err != nil {
+			h.logger.Error("error closing queued handler writer", zap.Error(err))
+		}
+	}
+	h.handler.Close()
+}
+
+// writerQueue is the queue of a writer of a queued handler, along with the
+// writer of the destination its batches are written to.
+type writerQueue struct {
+	handler *queuedHandler
+	writer  writer.Writer
+	queue   *batchQueue
+}
+
+func (q *writerQueue) writeLoop() {
+	defer q.handler.wg.Done()
+
+	for {
+		batch, ok := q.queue.Pop()
+		if !ok {
+			return
+		}
+		q.writeBatch(batch)
+	}
+}
+
+// writeBatch writes a batch to the destination. Writes are not retried since
+// a failed write may already have sent part of the encoded metric, instead
+// the flush is retried, which writers keep the data they failed to send
+// buffered for so that a retry sends whole encoded buffers.
+func (q *writerQueue) writeBatch(batch []aggregated.ChunkedMetricWithStoragePolicy) {
+	h := q.handler
+	for _, mp := range batch {
+		if err := q.writer.Write(mp); err != nil {
+			h.metrics.droppedWriteError.Inc(1)
+			h.logger.Error("dropping queued metric after write error", zap.Error(err))
+		}
+	}
+	// NB: once closing, the flush is attempted once so closing is not held
+	// up by a failing destination.
+	continueFn := func(attempt int) bool {
+		return attempt == 0 || !h.closing.Load()
+	}
+	if err := h.retrier.AttemptWhile(continueFn, q.writer.Flush); err != nil {
+		h.metrics.flushErrors.Inc(1)
+		h.logger.Error("error flushing queued metrics", zap.Error(err))
+	}
+}
+
+// queuedWriter is not thread safe, like every writer.
+type queuedWriter struct {
+	queue *batchQueue
+	batch []aggregated.ChunkedMetricWithStoragePolicy
+}
+
+func (w *queuedWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.batch = append(w.batch, cloneChunkedMetric(mp))
+	return nil
+}
+
+func (w *queuedWriter) Flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	w.queue.Push(w.batch)
+	w.batch = nil
+	return nil
+}
+
+func (w *queuedWriter) Close() error {
+	return w.Flush()
+}
+
+// cloneChunkedMetric copies the bytes of the metric since they may be reused
+// once written.
+func cloneChunkedMetric(
+	mp aggregated.ChunkedMetricWithStoragePolicy,
+) aggregated.ChunkedMetricWithStoragePolicy {
+	m := mp.ChunkedMetric
+	buf := make([]byte, 0, len(m.Prefix)+len(m.Data)+len(m.Suffix)+len(m.Annotation))
+	buf, mp.Prefix = appendClone(buf, m.Prefix)
+	buf, mp.Data = appendClone(buf, m.Data)
+	buf, mp.Suffix = appendClone(buf, m.Suffix)
+	_, mp.Annotation = appendClone(buf, m.Annotation)
+	if len(m.Exemplars) > 0 {
+		mp.Exemplars = make([]metric.Exemplar, 0, len(m.Exemplars))
+		for _, exemplar := range m.Exemplars {
+			mp.Exemplars = append(mp.Exemplars, exemplar.Clone())
+		}
+	}
+	return mp
+}
+
+func appendClone(buf, b []byte) ([]byte, []byte) {
+	if b == nil {
+		return buf, nil
+	}
+	start := len(buf)
+	buf = append(buf, b...)
+	return buf, buf[start:len(buf):len(buf)]
+}
+
+// batchQueue is a bounded queue of batches of metrics.
+type batchQueue struct {
+	sync.Mutex
+
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	batches  [][]aggregated.ChunkedMetricWithStoragePolicy
+	size     int
+	capacity int
+	policy   DropPolicy
+	closed   bool
+	metrics  queuedHandlerMetrics
+}
+
+func newBatchQueue(capacity int, policy DropPolicy, metrics queuedHandlerMetrics) *batchQueue {
+	q := &batchQueue{
+		capacity: capacity,
+		policy:   policy,
+		metrics:  metrics,
+	}
+	q.notEmpty = sync.NewCond(q)
+	q.notFull = sync.NewCond(q)
+	return q
+}
+
+// Push queues up a batch, applying the drop policy if the queue is full.
+// Batches pushed once the queue is closed are dropped.
+func (q *batchQueue) Push(batch []aggregated.ChunkedMetricWithStoragePolicy) {
+	q.Lock()
+	defer q.Unlock()
+
+	for !q.closed && q.size > 0 && q.size+len(batch) > q.capacity {
+		switch q.policy {
+		case DropNewest:
+			q.metrics.droppedQueueFull.Inc(int64(len(batch)))
+			return
+		case Block:
+			q.notFull.Wait()
+		default:
+			oldest := q.batches[0]
+			q.batches[0] = nil
+			q.batches = q.batches[1:]
+			q.size -= len(oldest)
+			q.metrics.droppedQueueFull.Inc(int64(len(oldest)))
+			q.metrics.updateQueueSize(-len(oldest))
+		}
+	}
+	if q.closed {
+		q.metrics.droppedQueueFull.Inc(int64(len(batch)))
+		return
+	}
+	q.batches = append(q.batches, batch)
+	q.size += len(batch)
+	q.metrics.enqueued.Inc(int64(len(batch)))
+	q.metrics.updateQueueSize(len(batch))
+	q.notEmpty.Signal()
+}
+
+// Pop dequeues the oldest batch, blocking until there is one. It returns false
+// once the queue is closed and empty.
+func (q *batchQueue) Pop() ([]aggregated.ChunkedMetricWithStoragePolicy, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	for len(q.batches) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if len(q.batches) == 0 {
+		return nil, false
+	}
+	batch := q.batches[0]
+	q.batches[0] = nil
+	q.batches = q.batches[1:]
+	q.size -= len(batch)
+	q.metrics.updateQueueSize(-len(batch))
+	q.notFull.Broadcast()
+	return batch, true
+}
+
+// Close closes the queue, the batches already queued can still be popped.
+func (q *batchQueue) Close() {
+	q.Lock()
+	q.closed = true
+	q.Unlock()
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package handler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/retry"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type testQueuedWriter struct {
+	sync.Mutex
+
+	writeErrs  []error
+	flushErrs  []error
+	flushCh    chan struct{}
+	written    []string
+	pending    []string
+	flushed    []string
+	numFlushes int
+	closed     bool
+}
+
+func (w *testQueuedWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.Lock()
+	defer w.Unlock()
+	if len(w.writeErrs) > 0 {
+		err := w.writeErrs[0]
+		w.writeErrs = w.writeErrs[1:]
+		return err
+	}
+	w.written = append(w.written, mp.ChunkedID.String())
+	w.pending = append(w.pending, mp.ChunkedID.String())
+	return nil
+}
+
+func (w *testQueuedWriter) Flush() error {
+	if w.flushCh != nil {
+		<-w.flushCh
+	}
+	w.Lock()
+	defer w.Unlock()
+	w.numFlushes++
+	if len(w.flushErrs) > 0 {
+		err := w.flushErrs[0]
+		w.flushErrs = w.flushErrs[1:]
+		return err
+	}
+	// The written metrics stay buffered until flushed successfully.
+	w.flushed = append(w.flushed, w.pending...)
+	w.pending = nil
+	return nil
+}
+
+func (w *testQueuedWriter) Close() error {
+	w.Lock()
+	w.closed = true
+	w.Unlock()
+	return nil
+}
+
+type testQueuedDestination struct {
+	// writer is the writer returned for every writer created if set,
+	// otherwise each writer created is the next one of writers.
+	writer  *testQueuedWriter
+	writers []*testQueuedWriter
+	closed  bool
+}
+
+func (h *testQueuedDestination) NewWriter(tally.Scope) (writer.Writer, error) {
+	if h.writer != nil {
+		return h.writer, nil
+	}
+	w := h.writers[0]
+	h.writers = h.writers[1:]
+	return w, nil
+}
+
+func (h *testQueuedDestination) Close() { h.closed = true }
+
+func TestQueuedHandler(t *testing.T) {
+	destWriter := &testQueuedWriter{
+		flushErrs: []error{errors.New("transient")},
+	}
+	dest := &testQueuedDestination{writer: destWriter}
+	h, err := NewQueuedHandler(dest, testQueueOptions())
+	require.NoError(t, err)
+
+	w, err := h.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+
+	// The metric is copied so its bytes can be reused once written.
+	mp := testQueuedMetric("foo")
+	require.NoError(t, w.Write(mp))
+	copy(mp.Data, "bar")
+	require.NoError(t, w.Write(testQueuedMetric("baz")))
+	require.NoError(t, w.Flush())
+
+	// Retries stop once closing, so wait for the retried flush.
+	require.True(t, clock.WaitUntil(func() bool {
+		destWriter.Lock()
+		defer destWriter.Unlock()
+		return destWriter.numFlushes == 2
+	}, time.Second))
+
+	require.NoError(t, w.Write(testQueuedMetric("qux")))
+	require.NoError(t, w.Close())
+
+	h.Close()
+	require.Equal(t, []string{"prefix.foo", "prefix.baz", "prefix.qux"}, destWriter.flushed)
+	require.Equal(t, 3, destWriter.numFlushes)
+	require.True(t, destWriter.closed)
+	require.True(t, dest.closed)
+}
+
+func TestQueuedHandlerWriteErrors(t *testing.T) {
+	writeErr := errors.New("write error")
+	destWriter := &testQueuedWriter{writeErrs: []error{writeErr}}
+	dest := &testQueuedDestination{writer: destWriter}
+	h, err := NewQueuedHandler(dest, testQueueOptions())
+	require.NoError(t, err)
+
+	w, err := h.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(testQueuedMetric("foo")))
+	require.NoError(t, w.Write(testQueuedMetric("bar")))
+	require.NoError(t, w.Flush())
+
+	// Failed writes are not retried since they may have been partially sent,
+	// only the metric that failed is dropped.
+	h.Close()
+	require.Equal(t, []string{"prefix.bar"}, destWriter.flushed)
+	require.Equal(t, 1, destWriter.numFlushes)
+}
+
+func TestQueuedHandlerQueuePerWriter(t *testing.T) {
+	var (
+		blocked = &testQueuedWriter{flushCh: make(chan struct{})}
+		other   = &testQueuedWriter{}
+		dest    = &testQueuedDestination{writers: []*testQueuedWriter{blocked, other}}
+	)
+	h, err := NewQueuedHandler(dest, testQueueOptions())
+	require.NoError(t, err)
+
+	w1, err := h.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+	w2, err := h.NewWriter(tally.NoopScope)
+	require.NoError(t, err)
+
+	require.NoError(t, w1.Write(testQueuedMetric("foo")))
+	require.NoError(t, w1.Flush())
+	require.NoError(t, w2.Write(testQueuedMetric("bar")))
+	require.NoError(t, w2.Flush())
+
+	// A writer whose flushes are stuck does not hold up the other writers.
+	require.True(t, clock.WaitUntil(func() bool {
+		other.Lock()
+		defer other.Unlock()
+		return other.numFlushes == 1
+	}, time.Second))
+
+	close(blocked.flushCh)
+	h.Close()
+	require.Equal(t, []string{"prefix.foo"}, blocked.flushed)
+	require.Equal(t, []string{"prefix.bar"}, other.flushed)
+	require.True(t, blocked.closed)
+	require.True(t, other.closed)
+
+	_, err = h.NewWriter(tally.NoopScope)
+	require.Equal(t, errQueuedHandlerClosed, err)
+}
+
+func TestNewQueuedHandlerInvalidSize(t *testing.T) {
+	opts := testQueueOptions()
+	opts.Size = 0
+	_, err := NewQueuedHandler(&testQueuedDestination{}, opts)
+	require.Equal(t, errInvalidQueueSize, err)
+}
+
+func TestBatchQueueDropPolicies(t *testing.T) {
+	for _, test := range []struct {
+		policy   DropPolicy
+		expected []string
+	}{
+		{policy: DropOldest, expected: []string{"prefix.b", "prefix.c"}},
+		{policy: DropNewest, expected: []string{"prefix.a", "prefix.b"}},
+	} {
+		t.Run(string(test.policy), func(t *testing.T) {
+			q := newBatchQueue(2, test.policy, newQueuedHandlerMetrics(tally.NoopScope))
+			for _, data := range []string{"a", "b", "c"} {
+				q.Push([]aggregated.ChunkedMetricWithStoragePolicy{testQueuedMetric(data)})
+			}
+			q.Close()
+			require.Equal(t, test.expected, popAll(q))
+		})
+	}
+}
+
+func TestBatchQueueBlock(t *testing.T) {
+	q := newBatchQueue(1, Block, newQueuedHandlerMetrics(tally.NoopScope))
+	q.Push([]aggregated.ChunkedMetricWithStoragePolicy{testQueuedMetric("a")})
+
+	pushed := make(chan struct{})
+	go func() {
+		q.Push([]aggregated.ChunkedMetricWithStoragePolicy{testQueuedMetric("b")})
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		require.FailNow(t, "push should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	batch, ok := q.Pop()
+	require.True(t, ok)
+	require.Equal(t, "prefix.a", batch[0].ChunkedID.String())
+	<-pushed
+
+	q.Close()
+	require.Equal(t, []string{"prefix.b"}, popAll(q))
+}
+
+func TestDropPolicyUnmarshalYAML(t *testing.T) {
+	var p DropPolicy
+	require.NoError(t, yaml.Unmarshal([]byte("dropNewest"), &p))
+	require.Equal(t, DropNewest, p)
+	require.Error(t, yaml.Unmarshal([]byte("dropAll"), &p))
+}
+
+func testQueueOptions() QueueOptions {
+	return QueueOptions{
+		Size:       10,
+		DropPolicy: DropOldest,
+		RetryOptions: retry.NewOptions().
+			SetInitialBackoff(time.Millisecond).
+			SetMaxRetries(1),
+		InstrumentOptions: instrument.NewOptions(),
+	}
+}
+
+func testQueuedMetric(data string) aggregated.ChunkedMetricWithStoragePolicy {
+	return aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{Prefix: []byte("prefix."), Data: []byte(data)},
+			Value:     1,
+		},
+	}
+}
+
+func popAll(q *batchQueue) []string {
+	var ids []string
+	for {
+		batch, ok := q.Pop()
+		if !ok {
+			return ids
+		}
+		for _, mp := range batch {
+			ids = append(ids, mp.ChunkedID.String())
+		}
+	}
+}
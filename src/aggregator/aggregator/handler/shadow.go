@@ -0,0 +1,216 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"errors"
+	"math"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var errInvalidShadowSampleRate = errors.New("invalid shadow sample rate, must be between 0 and 1")
+
+// ShadowOptions configures a shadow handler.
+type ShadowOptions struct {
+	// SampleRate is the fraction of series whose delivery is compared between
+	// the primary and the shadow handler. The same series are sampled on every
+	// flush. Zero disables the comparison.
+	SampleRate float64
+
+	// InstrumentOptions are the instrument options of the shadow handler.
+	InstrumentOptions instrument.Options
+}
+
+// Validate validates the shadow options.
+func (o ShadowOptions) Validate() error {
+	if o.SampleRate < 0 || o.SampleRate > 1 {
+		return errInvalidShadowSampleRate
+	}
+	return nil
+}
+
+// shadowHandler mirrors the metrics written to a primary handler to a shadow
+// handler, e.g. the topic of a new cluster being validated before a cutover.
+// Only the primary handler is served: errors of the shadow handler are counted
+// but never returned. A sample of the series is compared between the two
+// handlers to report the metrics delivered to only one of them.
+type shadowHandler struct {
+	primary   Handler
+	shadow    Handler
+	threshold uint32
+	logger    *zap.Logger
+}
+
+// NewShadowHandler creates a new handler writing to the primary handler and
+// mirroring the writes to the shadow handler.
+func NewShadowHandler(primary, shadow Handler, opts ShadowOptions) (Handler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &shadowHandler{
+		primary:   primary,
+		shadow:    shadow,
+		threshold: uint32(opts.SampleRate * math.MaxUint32),
+		logger:    opts.InstrumentOptions.Logger(),
+	}, nil
+}
+
+func (h *shadowHandler) NewWriter(scope tally.Scope) (writer.Writer, error) {
+	primary, err := h.primary.NewWriter(scope)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := h.shadow.NewWriter(scope.Tagged(map[string]string{"shadow": "true"}))
+	if err != nil {
+		_ = primary.Close()
+		return nil, err
+	}
+	return &shadowWriter{
+		primary:   primary,
+		shadow:    shadow,
+		threshold: h.threshold,
+		logger:    h.logger,
+		metrics:   newShadowWriterMetrics(scope.SubScope("shadow")),
+	}, nil
+}
+
+func (h *shadowHandler) Close() {
+	h.primary.Close()
+	h.shadow.Close()
+}
+
+type shadowWriterMetrics struct {
+	writeErrors tally.Counter
+	flushErrors tally.Counter
+	matched     tally.Counter
+	primaryOnly tally.Counter
+	shadowOnly  tally.Counter
+}
+
+func newShadowWriterMetrics(scope tally.Scope) shadowWriterMetrics {
+	compared := func(result string) tally.Counter {
+		return scope.Tagged(map[string]string{"result": result}).Counter("compared")
+	}
+	return shadowWriterMetrics{
+		writeErrors: scope.Counter("write-errors"),
+		flushErrors: scope.Counter("flush-errors"),
+		matched:     compared("matched"),
+		primaryOnly: compared("primary-only"),
+		shadowOnly:  compared("shadow-only"),
+	}
+}
+
+// shadowComparison counts the sampled metrics written since the last flush by
+// the handlers they were delivered to.
+type shadowComparison struct {
+	both        int64
+	primaryOnly int64
+	shadowOnly  int64
+}
+
+// shadowWriter is not thread safe, like every writer.
+type shadowWriter struct {
+	primary   writer.Writer
+	shadow    writer.Writer
+	threshold uint32
+	logger    *zap.Logger
+	metrics   shadowWriterMetrics
+
+	digest  xxhash.Digest
+	pending shadowComparison
+}
+
+func (w *shadowWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	err := w.primary.Write(mp)
+	shadowErr := w.shadow.Write(mp)
+	if shadowErr != nil {
+		w.metrics.writeErrors.Inc(1)
+	}
+	if w.sampled(mp) {
+		switch {
+		case err == nil && shadowErr == nil:
+			w.pending.both++
+		case err == nil:
+			w.pending.primaryOnly++
+		case shadowErr == nil:
+			w.pending.shadowOnly++
+		}
+	}
+	return err
+}
+
+// Flush flushes both writers and reports the comparison of the sampled
+// metrics written since the last flush, the metrics buffered by a writer that
+// fails to flush are considered not delivered.
+func (w *shadowWriter) Flush() error {
+	err := w.primary.Flush()
+	shadowErr := w.shadow.Flush()
+	if shadowErr != nil {
+		w.metrics.flushErrors.Inc(1)
+		w.logger.Error("error flushing shadow writer", zap.Error(shadowErr))
+	}
+
+	pending := w.pending
+	w.pending = shadowComparison{}
+	if err != nil {
+		pending.shadowOnly += pending.both
+		pending.primaryOnly = 0
+		pending.both = 0
+	}
+	if shadowErr != nil {
+		pending.primaryOnly += pending.both
+		pending.shadowOnly = 0
+		pending.both = 0
+	}
+	w.metrics.matched.Inc(pending.both)
+	w.metrics.primaryOnly.Inc(pending.primaryOnly)
+	w.metrics.shadowOnly.Inc(pending.shadowOnly)
+	return err
+}
+
+func (w *shadowWriter) Close() error {
+	err := w.primary.Close()
+	if shadowErr := w.shadow.Close(); shadowErr != nil {
+		w.logger.Error("error closing shadow writer", zap.Error(shadowErr))
+	}
+	return err
+}
+
+// sampled returns whether the series of the metric is compared, sampling the
+// series by the hash of their ID so the same series are compared every flush.
+func (w *shadowWriter) sampled(mp aggregated.ChunkedMetricWithStoragePolicy) bool {
+	if w.threshold == 0 {
+		return false
+	}
+	id := mp.ChunkedID
+	w.digest.Reset()
+	_, _ = w.digest.Write(id.Prefix)
+	_, _ = w.digest.Write(id.Data)
+	_, _ = w.digest.Write(id.Suffix)
+	return uint32(w.digest.Sum64()>>32) <= w.threshold
+}
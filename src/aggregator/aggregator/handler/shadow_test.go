@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestShadowHandler(t *testing.T) {
+	primary := &testQueuedDestination{writer: &testQueuedWriter{}}
+	shadow := &testQueuedDestination{writer: &testQueuedWriter{
+		writeErrs: []error{errors.New("shadow error")},
+	}}
+	h, err := NewShadowHandler(primary, shadow, ShadowOptions{
+		SampleRate:        1,
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+
+	scope := tally.NewTestScope("", nil)
+	w, err := h.NewWriter(scope)
+	require.NoError(t, err)
+
+	// Shadow errors are not returned.
+	require.NoError(t, w.Write(testQueuedMetric("foo")))
+	require.NoError(t, w.Write(testQueuedMetric("bar")))
+	require.NoError(t, w.Write(testQueuedMetric("baz")))
+	require.NoError(t, w.Flush())
+
+	require.Equal(t, []string{"prefix.foo", "prefix.bar", "prefix.baz"}, primary.writer.written)
+	require.Equal(t, []string{"prefix.bar", "prefix.baz"}, shadow.writer.written)
+	require.Equal(t, 1, primary.writer.numFlushes)
+	require.Equal(t, 1, shadow.writer.numFlushes)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["shadow.compared+result=matched"].Value())
+	require.Equal(t, int64(1), counters["shadow.compared+result=primary-only"].Value())
+	require.Equal(t, int64(0), counters["shadow.compared+result=shadow-only"].Value())
+	require.Equal(t, int64(1), counters["shadow.write-errors+"].Value())
+
+	require.NoError(t, w.Close())
+	h.Close()
+	require.True(t, primary.writer.closed)
+	require.True(t, shadow.writer.closed)
+	require.True(t, primary.closed)
+	require.True(t, shadow.closed)
+}
+
+func TestShadowHandlerPrimaryError(t *testing.T) {
+	writeErr := errors.New("primary error")
+	primary := &testQueuedDestination{writer: &testQueuedWriter{
+		writeErrs: []error{writeErr},
+	}}
+	shadow := &testQueuedDestination{writer: &testQueuedWriter{}}
+	h, err := NewShadowHandler(primary, shadow, ShadowOptions{
+		SampleRate:        1,
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+
+	scope := tally.NewTestScope("", nil)
+	w, err := h.NewWriter(scope)
+	require.NoError(t, err)
+
+	// Primary errors are returned while the shadow keeps receiving the metrics.
+	require.Equal(t, writeErr, w.Write(testQueuedMetric("foo")))
+	require.NoError(t, w.Flush())
+	require.Equal(t, []string{"prefix.foo"}, shadow.writer.written)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["shadow.compared+result=shadow-only"].Value())
+}
+
+func TestShadowHandlerNotSampled(t *testing.T) {
+	primary := &testQueuedDestination{writer: &testQueuedWriter{}}
+	shadow := &testQueuedDestination{writer: &testQueuedWriter{}}
+	h, err := NewShadowHandler(primary, shadow, ShadowOptions{
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+
+	scope := tally.NewTestScope("", nil)
+	w, err := h.NewWriter(scope)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(testQueuedMetric("foo")))
+	require.NoError(t, w.Flush())
+	require.Equal(t, []string{"prefix.foo"}, shadow.writer.written)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(0), counters["shadow.compared+result=matched"].Value())
+}
+
+func TestNewShadowHandlerInvalidSampleRate(t *testing.T) {
+	_, err := NewShadowHandler(NewBlackholeHandler(), NewBlackholeHandler(), ShadowOptions{
+		SampleRate:        1.5,
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.Equal(t, errInvalidShadowSampleRate, err)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+)
+
+// FileLine is a line written by the file writer for each metric.
+type FileLine struct {
+	ID                   string  `json:"id"`
+	TimeNanos            int64   `json:"timeNanos"`
+	Value                float64 `json:"value"`
+	StoragePolicy        string  `json:"storagePolicy"`
+	Annotation           []byte  `json:"annotation,omitempty"`
+	DowngradedResolution string  `json:"downgradedResolution,omitempty"`
+}
+
+type fileWriter struct {
+	out  io.Writer
+	lock sync.Locker
+	buf  bytes.Buffer
+	enc  *json.Encoder
+}
+
+// NewFileWriter creates a new writer appending metrics to out as JSON lines.
+// Lines are buffered until flushed, writers sharing out must share the lock
+// so the lines they flush are not interleaved.
+func NewFileWriter(out io.Writer, lock sync.Locker) Writer {
+	w := &fileWriter{out: out, lock: lock}
+	w.enc = json.NewEncoder(&w.buf)
+	return w
+}
+
+func (w *fileWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	line := FileLine{
+		ID:            mp.ChunkedID.String(),
+		TimeNanos:     mp.TimeNanos,
+		Value:         mp.Value,
+		StoragePolicy: mp.StoragePolicy.String(),
+		Annotation:    mp.Annotation,
+	}
+	if mp.DowngradedResolution > 0 {
+		line.DowngradedResolution = mp.DowngradedResolution.String()
+	}
+	return w.enc.Encode(line)
+}
+
+func (w *fileWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	w.lock.Lock()
+	n, err := w.out.Write(w.buf.Bytes())
+	w.lock.Unlock()
+	// Only the lines that were not written are kept, so they can be retried
+	// by flushing again.
+	w.buf.Next(n)
+	return err
+}
+
+func (w *fileWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWriter(t *testing.T) {
+	var (
+		out  bytes.Buffer
+		lock sync.Mutex
+		w1   = NewFileWriter(&out, &lock)
+		w2   = NewFileWriter(&out, &lock)
+	)
+	require.NoError(t, w1.Write(testChunkedMetricWithStoragePolicy))
+	require.NoError(t, w2.Write(testChunkedMetricWithStoragePolicy2))
+	require.Equal(t, 0, out.Len())
+
+	require.NoError(t, w2.Flush())
+	require.NoError(t, w1.Close())
+
+	var lines []FileLine
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line FileLine
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, []FileLine{
+		{
+			ID:            string(testRawID2),
+			TimeNanos:     testChunkedMetricWithStoragePolicy2.TimeNanos,
+			Value:         testChunkedMetricWithStoragePolicy2.Value,
+			StoragePolicy: "1m:1d",
+		},
+		{
+			ID:            string(testRawID),
+			TimeNanos:     testChunkedMetricWithStoragePolicy.TimeNanos,
+			Value:         testChunkedMetricWithStoragePolicy.Value,
+			StoragePolicy: "10s:6h",
+		},
+	}, lines)
+}
+
+type testPartialWriter struct {
+	out      bytes.Buffer
+	maxBytes int
+}
+
+func (w *testPartialWriter) Write(p []byte) (int, error) {
+	if len(p) > w.maxBytes {
+		n, _ := w.out.Write(p[:w.maxBytes])
+		return n, errors.New("short write")
+	}
+	return w.out.Write(p)
+}
+
+func TestFileWriterFlushError(t *testing.T) {
+	var (
+		out  = &testPartialWriter{maxBytes: 10}
+		lock sync.Mutex
+		w    = NewFileWriter(out, &lock)
+	)
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy))
+	require.Error(t, w.Flush())
+	require.Equal(t, 10, out.out.Len())
+
+	// The lines that were not written are written by the next flush.
+	out.maxBytes = 1 << 20
+	require.NoError(t, w.Flush())
+
+	var line FileLine
+	require.NoError(t, json.Unmarshal(out.out.Bytes(), &line))
+	require.Equal(t, string(testRawID), line.ID)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// KafkaMessage is a message written to a partition of a Kafka topic.
+type KafkaMessage struct {
+	Topic     string
+	Partition int32
+	Value     []byte
+}
+
+// KafkaProducer produces messages to Kafka. It is implemented by wrapping the
+// Kafka client of choice so the aggregator does not depend on a specific one.
+type KafkaProducer interface {
+	// Produce produces a message to Kafka, the message value is owned by
+	// the producer once produced.
+	Produce(m KafkaMessage) error
+
+	// Close closes the producer, flushing any pending messages.
+	Close() error
+}
+
+// KafkaProducerOptions configures a Kafka producer.
+type KafkaProducerOptions struct {
+	// Brokers are the addresses of the Kafka brokers.
+	Brokers []string
+
+	// MaxMessageBytes is the max size of the messages produced, larger
+	// messages are rejected. Zero means the default of the producer.
+	MaxMessageBytes int
+
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+// KafkaProducerFn creates a Kafka producer connected to the given brokers.
+type KafkaProducerFn func(opts KafkaProducerOptions) (KafkaProducer, error)
+
+// NewKafkaWriter creates a writer that encodes metrics in protobuf and
+// writes them to the partition of the topic matching their shard. Kafka
+// consumers do not reassemble split messages, so encoded metrics are never
+// split regardless of the max message size of the options.
+func NewKafkaWriter(
+	p KafkaProducer,
+	topic string,
+	numPartitions uint32,
+	shardFn sharding.ShardFn,
+	opts Options,
+) Writer {
+	return NewProtobufWriter(kafkaProducer{
+		p:             p,
+		topic:         topic,
+		numPartitions: numPartitions,
+	}, shardFn, opts.SetMaxMessageSize(0))
+}
+
+// kafkaProducer adapts a Kafka producer to the m3msg producer interface so
+// the protobuf writer can be reused for encoding and splitting metrics.
+type kafkaProducer struct {
+	p             KafkaProducer
+	topic         string
+	numPartitions uint32
+}
+
+func (p kafkaProducer) Produce(m producer.Message) error {
+	// Copy the bytes since the underlying buffer is returned to the pool
+	// once the message is finalized.
+	value := append([]byte(nil), m.Bytes()...)
+	err := p.p.Produce(KafkaMessage{
+		Topic:     p.topic,
+		Partition: int32(m.Shard()),
+		Value:     value,
+	})
+	if err != nil {
+		m.Finalize(producer.Dropped)
+		return err
+	}
+	m.Finalize(producer.Consumed)
+	return nil
+}
+
+func (p kafkaProducer) RegisterFilter(services.ServiceID, producer.FilterFunc) {}
+
+func (p kafkaProducer) UnregisterFilter(services.ServiceID) {}
+
+func (p kafkaProducer) NumShards() uint32 {
+	return p.numPartitions
+}
+
+func (p kafkaProducer) Init() error {
+	return nil
+}
+
+// Close is a noop, the Kafka producer is closed by its owner as it may be
+// shared by other writers.
+func (p kafkaProducer) Close(producer.CloseType) {}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var errKafkaProducerClosed = errors.New("kafka producer is closed")
+
+type saramaKafkaProducerMetrics struct {
+	produceErrors tally.Counter
+}
+
+func newSaramaKafkaProducerMetrics(scope tally.Scope) saramaKafkaProducerMetrics {
+	return saramaKafkaProducerMetrics{
+		produceErrors: scope.Counter("produce-errors"),
+	}
+}
+
+// saramaKafkaProducer produces messages asynchronously with a sarama producer,
+// messages that could not be delivered to the brokers are dropped and counted.
+type saramaKafkaProducer struct {
+	sync.RWMutex
+
+	p       sarama.AsyncProducer
+	logger  *zap.Logger
+	metrics saramaKafkaProducerMetrics
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewSaramaKafkaProducer creates a Kafka producer backed by a sarama async
+// producer, which writes each message to the partition set on it and waits
+// for all in-sync replicas to acknowledge it.
+func NewSaramaKafkaProducer(opts KafkaProducerOptions) (KafkaProducer, error) {
+	cfg := sarama.NewConfig()
+	cfg.Producer.Partitioner = sarama.NewManualPartitioner
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Return.Errors = true
+	if opts.MaxMessageBytes > 0 {
+		cfg.Producer.MaxMessageBytes = opts.MaxMessageBytes
+	}
+	p, err := sarama.NewAsyncProducer(opts.Brokers, cfg)
+	if err != nil {
+		return nil, err
+	}
+	producer := &saramaKafkaProducer{
+		p:       p,
+		logger:  opts.InstrumentOptions.Logger(),
+		metrics: newSaramaKafkaProducerMetrics(opts.InstrumentOptions.MetricsScope()),
+	}
+	producer.wg.Add(1)
+	go producer.consumeErrors()
+	return producer, nil
+}
+
+func (p *saramaKafkaProducer) Produce(m KafkaMessage) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.closed {
+		return errKafkaProducerClosed
+	}
+	p.p.Input() <- &sarama.ProducerMessage{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Value:     sarama.ByteEncoder(m.Value),
+	}
+	return nil
+}
+
+func (p *saramaKafkaProducer) consumeErrors() {
+	defer p.wg.Done()
+
+	var loggedErr bool
+	for err := range p.p.Errors() {
+		p.metrics.produceErrors.Inc(1)
+		// Only log the first error to not flood the logs while the brokers are
+		// unavailable, the errors are counted in any case.
+		if !loggedErr {
+			p.logger.Error("could not produce message to kafka", zap.Error(err))
+			loggedErr = true
+		}
+	}
+}
+
+func (p *saramaKafkaProducer) Close() error {
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.Unlock()
+
+	// Closing flushes the buffered messages, and closes the error channel once
+	// done so waiting for the errors to be consumed waits for the flush.
+	p.p.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
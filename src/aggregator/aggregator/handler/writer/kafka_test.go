@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKafkaProducer struct {
+	msgs   []KafkaMessage
+	err    error
+	closed bool
+}
+
+func (p *testKafkaProducer) Produce(m KafkaMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, m)
+	return nil
+}
+
+func (p *testKafkaProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestKafkaWriterWrite(t *testing.T) {
+	var (
+		p             = &testKafkaProducer{}
+		numPartitions = uint32(16)
+		shardFn       = sharding.Murmur32Hash.MustShardFn()
+		w             = NewKafkaWriter(p, "aggregated", numPartitions, shardFn, NewOptions())
+	)
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy))
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy2))
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Close())
+	require.False(t, p.closed)
+
+	expected := []struct {
+		id    []byte
+		value float64
+	}{
+		{id: testRawID, value: testChunkedMetricWithStoragePolicy.Value},
+		{id: testRawID2, value: testChunkedMetricWithStoragePolicy2.Value},
+	}
+	require.Equal(t, len(expected), len(p.msgs))
+	for i, m := range p.msgs {
+		require.Equal(t, "aggregated", m.Topic)
+		require.Equal(t, int32(shardFn(expected[i].id, numPartitions)), m.Partition)
+
+		d := protobuf.NewAggregatedDecoder(nil)
+		require.NoError(t, d.Decode(m.Value))
+		require.Equal(t, expected[i].id, d.ID())
+		require.Equal(t, expected[i].value, d.Value())
+	}
+}
+
+func TestKafkaWriterWriteNeverSplits(t *testing.T) {
+	var (
+		p    = &testKafkaProducer{}
+		opts = NewOptions().SetMaxMessageSize(10)
+		w    = NewKafkaWriter(p, "aggregated", 16, sharding.Murmur32Hash.MustShardFn(), opts)
+	)
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy))
+	require.Equal(t, 1, len(p.msgs))
+	require.True(t, len(p.msgs[0].Value) > 10)
+
+	d := protobuf.NewAggregatedDecoder(nil)
+	require.NoError(t, d.Decode(p.msgs[0].Value))
+	require.Equal(t, testRawID, d.ID())
+}
+
+func TestKafkaWriterWriteError(t *testing.T) {
+	errProduce := errors.New("produce error")
+	p := &testKafkaProducer{err: errProduce}
+	w := NewKafkaWriter(p, "aggregated", 16, sharding.Murmur32Hash.MustShardFn(), NewOptions())
+	require.Equal(t, errProduce, w.Write(testChunkedMetricWithStoragePolicy))
+}
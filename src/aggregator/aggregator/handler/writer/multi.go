@@ -44,6 +44,16 @@ func (w *multiWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error
 	return multiErr.FinalError()
 }
 
+func (w *multiWriter) WriteBatch(mps []aggregated.ChunkedMetricWithStoragePolicy) error {
+	multiErr := errors.NewMultiError()
+	for _, writer := range w.writers {
+		if err := WriteBatch(writer, mps); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (w *multiWriter) Flush() error {
 	multiErr := errors.NewMultiError()
 	for _, writer := range w.writers {
@@ -59,6 +59,16 @@ type Options interface {
 	// included in the encoded data. A value of 0 means the encoding time is never included,
 	// and a value of 1 means the encoding time is always included.
 	EncodingTimeSamplingRate() float64
+
+	// SetMaxMessageSize sets the max size of the messages produced, encoded
+	// metrics exceeding it are split across multiple messages. A value of 0
+	// means encoded metrics are never split.
+	SetMaxMessageSize(value int) Options
+
+	// MaxMessageSize returns the max size of the messages produced, encoded
+	// metrics exceeding it are split across multiple messages. A value of 0
+	// means encoded metrics are never split.
+	MaxMessageSize() int
 }
 
 type options struct {
@@ -66,6 +76,7 @@ type options struct {
 	instrumentOpts           instrument.Options
 	bytesPool                pool.BytesPool
 	encodingTimeSamplingRate float64
+	maxMessageSize           int
 }
 
 // NewOptions provide a set of writer options.
@@ -116,3 +127,13 @@ func (o *options) SetEncodingTimeSamplingRate(value float64) Options {
 func (o *options) EncodingTimeSamplingRate() float64 {
 	return o.encodingTimeSamplingRate
 }
+
+func (o *options) SetMaxMessageSize(value int) Options {
+	opts := *o
+	opts.maxMessageSize = value
+	return &opts
+}
+
+func (o *options) MaxMessageSize() int {
+	return o.maxMessageSize
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/uber-go/tally"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const (
+	// storagePolicyAttribute is the label holding the storage policy of
+	// the data points, since a metric is exported once per storage policy.
+	storagePolicyAttribute = "storage_policy"
+)
+
+var (
+	defaultOTLPNameTag = []byte("__name__")
+
+	errOTLPWriterNoClient         = errors.New("no otlp metrics client")
+	errOTLPWriterNoTagDecoderPool = errors.New("no tag decoder pool")
+)
+
+// OTLPWriterOptions configures an OTLP writer.
+type OTLPWriterOptions struct {
+	// Client exports the metrics to the OpenTelemetry collector.
+	Client colmetricspb.MetricsServiceClient
+
+	// TagDecoderPool pools the decoders of the tags encoded in metric IDs.
+	TagDecoderPool serialize.TagDecoderPool
+
+	// NameTag is the tag holding the metric name, defaults to __name__.
+	NameTag []byte
+
+	// ResourceAttributes are the attributes of the resource exporting the
+	// metrics.
+	ResourceAttributes map[string]string
+
+	// ExportTimeout is the timeout of each export, zero means no timeout.
+	ExportTimeout time.Duration
+
+	// MaxBatchSize is the max number of metrics exported per request, metrics
+	// are exported once the batch is full or when the writer is flushed. Zero
+	// means metrics are only exported when the writer is flushed.
+	MaxBatchSize int
+
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+type otlpWriterMetrics struct {
+	writerClosed  tally.Counter
+	exportSuccess tally.Counter
+	exportErrors  tally.Counter
+	exportMetrics tally.Counter
+}
+
+func newOTLPWriterMetrics(scope tally.Scope) otlpWriterMetrics {
+	exportScope := scope.SubScope("export")
+	return otlpWriterMetrics{
+		writerClosed:  scope.Counter("writer-closed"),
+		exportSuccess: exportScope.Counter("success"),
+		exportErrors:  exportScope.Counter("errors"),
+		exportMetrics: exportScope.Counter("metrics"),
+	}
+}
+
+// otlpWriter converts metrics to OTLP and exports them in batches to an
+// OpenTelemetry collector. otlpWriter is not thread safe.
+type otlpWriter struct {
+	client        colmetricspb.MetricsServiceClient
+	it            serialize.MetricTagsIterator
+	nameTag       []byte
+	resource      *resourcepb.Resource
+	exportTimeout time.Duration
+	maxBatchSize  int
+
+	closed  bool
+	id      []byte
+	batch   []*metricspb.Metric
+	metrics otlpWriterMetrics
+}
+
+// NewOTLPWriter creates a writer that converts metrics to OTLP and exports
+// them to an OpenTelemetry collector.
+func NewOTLPWriter(opts OTLPWriterOptions) (Writer, error) {
+	if opts.Client == nil {
+		return nil, errOTLPWriterNoClient
+	}
+	if opts.TagDecoderPool == nil {
+		return nil, errOTLPWriterNoTagDecoderPool
+	}
+	nameTag := opts.NameTag
+	if len(nameTag) == 0 {
+		nameTag = defaultOTLPNameTag
+	}
+	resource := &resourcepb.Resource{}
+	for k, v := range opts.ResourceAttributes {
+		resource.Attributes = append(resource.Attributes, newOTLPAttribute(k, v))
+	}
+	return &otlpWriter{
+		client:        opts.Client,
+		it:            serialize.NewMetricTagsIterator(opts.TagDecoderPool.Get(), nil),
+		nameTag:       nameTag,
+		resource:      resource,
+		exportTimeout: opts.ExportTimeout,
+		maxBatchSize:  opts.MaxBatchSize,
+		metrics:       newOTLPWriterMetrics(opts.InstrumentOptions.MetricsScope()),
+	}, nil
+}
+
+func (w *otlpWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	if w.closed {
+		w.metrics.writerClosed.Inc(1)
+		return errWriterClosed
+	}
+	w.id = w.id[:0]
+	w.id = append(w.id, mp.Prefix...)
+	w.id = append(w.id, mp.Data...)
+	w.id = append(w.id, mp.Suffix...)
+	w.batch = append(w.batch, w.convert(mp))
+	if w.maxBatchSize > 0 && len(w.batch) >= w.maxBatchSize {
+		if err := w.export(); err != nil {
+			// Drop the full batch rather than growing it further.
+			w.batch = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// convert converts a metric to OTLP. Sums and counts summarize the values
+// received over the resolution window of the storage policy, so they are
+// exported as delta sums over that window. The values of the other aggregation
+// types, such as the last value, min, max or quantiles, are not additive and are
+// exported as gauges, as are metrics that were not aggregated.
+func (w *otlpWriter) convert(mp aggregated.ChunkedMetricWithStoragePolicy) *metricspb.Metric {
+	name, labels := w.decodeID()
+	labels = append(labels, &commonpb.StringKeyValue{
+		Key:   storagePolicyAttribute,
+		Value: mp.StoragePolicy.String(),
+	})
+	dp := &metricspb.DoubleDataPoint{
+		Labels:       labels,
+		TimeUnixNano: uint64(mp.TimeNanos),
+		Value:        mp.Value,
+	}
+	m := &metricspb.Metric{Name: name}
+
+	window := mp.StoragePolicy.Resolution().Window
+	if mp.DowngradedResolution > 0 {
+		window = mp.DowngradedResolution
+	}
+	isSum := mp.AggregationType == aggregation.Sum || mp.AggregationType == aggregation.Count
+	if !isSum || window <= 0 {
+		m.Data = &metricspb.Metric_DoubleGauge{
+			DoubleGauge: &metricspb.DoubleGauge{
+				DataPoints: []*metricspb.DoubleDataPoint{dp},
+			},
+		}
+		return m
+	}
+	dp.StartTimeUnixNano = uint64(mp.TimeNanos - window.Nanoseconds())
+	m.Data = &metricspb.Metric_DoubleSum{
+		DoubleSum: &metricspb.DoubleSum{
+			DataPoints:             []*metricspb.DoubleDataPoint{dp},
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+		},
+	}
+	return m
+}
+
+// decodeID returns the name and labels of the metric from the tags encoded
+// in its ID, metric IDs not encoding tags, e.g. Graphite metric IDs, are used
+// as the name.
+func (w *otlpWriter) decodeID() (string, []*commonpb.StringKeyValue) {
+	var (
+		name   string
+		labels []*commonpb.StringKeyValue
+	)
+	w.it.Reset(w.id)
+	for w.it.Next() {
+		tagName, tagValue := w.it.Current()
+		if string(tagName) == string(w.nameTag) {
+			name = string(tagValue)
+			continue
+		}
+		labels = append(labels, &commonpb.StringKeyValue{
+			Key:   string(tagName),
+			Value: string(tagValue),
+		})
+	}
+	if w.it.Err() != nil {
+		return string(w.id), nil
+	}
+	return name, labels
+}
+
+func (w *otlpWriter) export() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	req := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: w.resource,
+				InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{
+					{Metrics: w.batch},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	if w.exportTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.exportTimeout)
+		defer cancel()
+	}
+	numMetrics := len(w.batch)
+	if _, err := w.client.Export(ctx, req); err != nil {
+		// The batch is kept so the export can be retried as a whole by
+		// flushing again.
+		w.metrics.exportErrors.Inc(1)
+		return err
+	}
+	// The batch is owned by the request, so start a new one.
+	w.batch = nil
+	w.metrics.exportSuccess.Inc(1)
+	w.metrics.exportMetrics.Inc(int64(numMetrics))
+	return nil
+}
+
+func (w *otlpWriter) Flush() error {
+	if w.closed {
+		w.metrics.writerClosed.Inc(1)
+		return errWriterClosed
+	}
+	return w.export()
+}
+
+func (w *otlpWriter) Close() error {
+	if w.closed {
+		w.metrics.writerClosed.Inc(1)
+		return errWriterClosed
+	}
+	// Don't close the client here, it may be shared by other writers.
+	w.closed = true
+	err := w.export()
+	w.batch = nil
+	w.it.Close()
+	return err
+}
+
+func newOTLPAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: value},
+		},
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+type testOTLPClient struct {
+	reqs []*colmetricspb.ExportMetricsServiceRequest
+	err  error
+}
+
+func (c *testOTLPClient) Export(
+	_ context.Context,
+	req *colmetricspb.ExportMetricsServiceRequest,
+	_ ...grpc.CallOption,
+) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.reqs = append(c.reqs, req)
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func TestOTLPWriterWrite(t *testing.T) {
+	client := &testOTLPClient{}
+	w := testOTLPWriter(t, client, 2)
+
+	tagged := aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{Data: testEncodedTags(t, "__name__", "requests", "service", "api")},
+			TimeNanos: 60 * int64(time.Second),
+			Value:     42,
+		},
+		StoragePolicy:   policy.NewStoragePolicy(10*time.Second, xtime.Second, 6*time.Hour),
+		AggregationType: aggregation.Sum,
+	}
+	require.NoError(t, w.Write(tagged))
+	require.Equal(t, 0, len(client.reqs))
+
+	// The batch is exported once full.
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy))
+	require.Equal(t, 1, len(client.reqs))
+
+	rm := client.reqs[0].ResourceMetrics
+	require.Equal(t, 1, len(rm))
+	require.Equal(t, "m3aggregator", rm[0].Resource.Attributes[0].Value.GetStringValue())
+	metrics := rm[0].InstrumentationLibraryMetrics[0].Metrics
+	require.Equal(t, 2, len(metrics))
+
+	require.Equal(t, "requests", metrics[0].Name)
+	sum := metrics[0].GetDoubleSum()
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, sum.AggregationTemporality)
+	dp := sum.DataPoints[0]
+	require.Equal(t, uint64(50*time.Second), dp.StartTimeUnixNano)
+	require.Equal(t, uint64(60*time.Second), dp.TimeUnixNano)
+	require.Equal(t, 42.0, dp.Value)
+	require.Equal(t, 2, len(dp.Labels))
+	require.Equal(t, "service", dp.Labels[0].Key)
+	require.Equal(t, "api", dp.Labels[0].Value)
+	require.Equal(t, storagePolicyAttribute, dp.Labels[1].Key)
+	require.Equal(t, "10s:6h", dp.Labels[1].Value)
+
+	// Metric IDs not encoding tags are used as the name, and metrics that were
+	// not aggregated are exported as gauges.
+	require.Equal(t, string(testRawID), metrics[1].Name)
+	require.NotNil(t, metrics[1].GetDoubleGauge())
+
+	// The remaining metrics are exported on flush.
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy2))
+	require.NoError(t, w.Flush())
+	require.Equal(t, 2, len(client.reqs))
+	require.NoError(t, w.Flush())
+	require.Equal(t, 2, len(client.reqs))
+
+	require.NoError(t, w.Close())
+	require.Equal(t, errWriterClosed, w.Write(testChunkedMetricWithStoragePolicy))
+}
+
+func TestOTLPWriterConvertAggregationTypes(t *testing.T) {
+	client := &testOTLPClient{}
+	w := testOTLPWriter(t, client, 0)
+
+	for _, aggType := range []aggregation.Type{
+		aggregation.Sum, aggregation.Count, aggregation.Last, aggregation.Max, aggregation.P99,
+	} {
+		mp := testChunkedMetricWithStoragePolicy
+		mp.AggregationType = aggType
+		require.NoError(t, w.Write(mp))
+	}
+	require.NoError(t, w.Flush())
+
+	metrics := client.reqs[0].ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics
+	require.Equal(t, 5, len(metrics))
+	for i, m := range metrics {
+		if i < 2 {
+			require.NotNil(t, m.GetDoubleSum(), i)
+			continue
+		}
+		require.NotNil(t, m.GetDoubleGauge(), i)
+	}
+}
+
+func TestOTLPWriterExportError(t *testing.T) {
+	errExport := errors.New("export error")
+	client := &testOTLPClient{err: errExport}
+	w := testOTLPWriter(t, client, 0)
+
+	require.NoError(t, w.Write(testChunkedMetricWithStoragePolicy))
+	require.Equal(t, errExport, w.Flush())
+
+	// The failed batch is exported as a whole by the next flush.
+	client.err = nil
+	require.NoError(t, w.Flush())
+	require.Equal(t, 1, len(client.reqs))
+	require.Equal(t, 1, len(client.reqs[0].ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics))
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, 1, len(client.reqs))
+}
+
+func testOTLPWriter(t *testing.T, client *testOTLPClient, maxBatchSize int) Writer {
+	decoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(serialize.TagDecoderOptionsConfig{}),
+		pool.NewObjectPoolOptions().SetSize(1))
+	decoderPool.Init()
+	w, err := NewOTLPWriter(OTLPWriterOptions{
+		Client:             client,
+		TagDecoderPool:     decoderPool,
+		ResourceAttributes: map[string]string{"service.name": "m3aggregator"},
+		MaxBatchSize:       maxBatchSize,
+		InstrumentOptions:  instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+	return w
+}
+
+func testEncodedTags(t *testing.T, tags ...string) []byte {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions().SetSize(1))
+	encoderPool.Init()
+	encoder := encoderPool.Get()
+	require.NoError(t, encoder.Encode(ident.MustNewTagStringsIterator(tags...)))
+	data, ok := encoder.Data()
+	require.True(t, ok)
+	return append([]byte(nil), data.Bytes()...)
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/watch"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var (
+	errPlacementShardedWriterClosed         = errors.New("placement sharded writer closed")
+	errPlacementShardedWriterNoPlacement    = errors.New("placement sharded writer has no placement")
+	errPlacementShardedWriterInvalidWriters = errors.New("number of writers per instance must be positive")
+)
+
+// NewWriterFn creates a new writer.
+type NewWriterFn func() (Writer, error)
+
+// PlacementShardedWriterOptions configures a placement sharded writer.
+type PlacementShardedWriterOptions struct {
+	// PlacementService is the placement service of the downstream consumers.
+	PlacementService placement.Service
+
+	// WritersPerInstance is the number of writers per downstream consumer
+	// instance.
+	WritersPerInstance int
+
+	// InitWatchTimeout is the timeout to wait for the initial placement.
+	InitWatchTimeout time.Duration
+
+	// NewWriterFn creates the backing writers.
+	NewWriterFn NewWriterFn
+
+	// ShardFn shards writes across the shards of the placement.
+	ShardFn sharding.AggregatedShardFn
+
+	// InstrumentOptions are the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+type placementShardedWriterMetrics struct {
+	rebalances      tally.Counter
+	rebalanceErrors tally.Counter
+	shardsMoved     tally.Counter
+	numWriters      tally.Gauge
+}
+
+func newPlacementShardedWriterMetrics(scope tally.Scope) placementShardedWriterMetrics {
+	return placementShardedWriterMetrics{
+		rebalances:      scope.Counter("rebalances"),
+		rebalanceErrors: scope.Counter("rebalance-errors"),
+		shardsMoved:     scope.Counter("shards-moved"),
+		numWriters:      scope.Gauge("num-writers"),
+	}
+}
+
+// placementShardedWriter assigns the shards of the placement of the downstream
+// consumers to the writers of the instances owning them.
+type placementShardedWriter struct {
+	sync.RWMutex
+
+	writersPerInstance int
+	newWriterFn        NewWriterFn
+	shardFn            sharding.AggregatedShardFn
+	logger             *zap.Logger
+	metrics            placementShardedWriterMetrics
+	value              watch.Value
+
+	closed          bool
+	shardWriters    []*threadsafeWriter
+	instanceWriters map[string][]*threadsafeWriter
+}
+
+var _ Writer = &placementShardedWriter{}
+
+// NewPlacementShardedWriter shards writes across the shards of the placement
+// of the downstream consumers, and writes each shard with a writer of the
+// instance owning it. On every placement update, only the shards whose owner
+// changed are moved to the writers of their new owner, after the writes the
+// previous writers buffered have been flushed.
+func NewPlacementShardedWriter(opts PlacementShardedWriterOptions) (Writer, error) {
+	w, err := newPlacementShardedWriter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := opts.PlacementService
+	vOpts := watch.NewOptions().
+		SetInitWatchTimeout(opts.InitWatchTimeout).
+		SetInstrumentOptions(opts.InstrumentOptions).
+		SetNewUpdatableFn(func() (watch.Updatable, error) {
+			return ps.Watch()
+		}).
+		SetGetUpdateFn(func(updatable watch.Updatable) (interface{}, error) {
+			return updatable.(placement.Watch).Get()
+		}).
+		SetProcessFn(w.process)
+	w.value = watch.NewValue(vOpts)
+	if err := w.value.Watch(); err != nil {
+		if _, ok := err.(watch.InitValueError); !ok {
+			w.Close() // nolint: errcheck
+			return nil, fmt.Errorf("placement sharded writer init error: %v", err)
+		}
+		w.logger.Warn("invalid placement update, continue to watch for placement updates",
+			zap.Error(err))
+	}
+	return w, nil
+}
+
+func newPlacementShardedWriter(opts PlacementShardedWriterOptions) (*placementShardedWriter, error) {
+	if opts.WritersPerInstance <= 0 {
+		return nil, errPlacementShardedWriterInvalidWriters
+	}
+
+	iOpts := opts.InstrumentOptions
+	return &placementShardedWriter{
+		writersPerInstance: opts.WritersPerInstance,
+		newWriterFn:        opts.NewWriterFn,
+		shardFn:            opts.ShardFn,
+		logger:             iOpts.Logger(),
+		metrics:            newPlacementShardedWriterMetrics(iOpts.MetricsScope()),
+		instanceWriters:    make(map[string][]*threadsafeWriter),
+	}, nil
+}
+
+func (w *placementShardedWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.RLock()
+	if w.closed {
+		w.RUnlock()
+		return errPlacementShardedWriterClosed
+	}
+	if len(w.shardWriters) == 0 {
+		w.RUnlock()
+		return errPlacementShardedWriterNoPlacement
+	}
+
+	shardID := w.shardFn(mp.ChunkedID, len(w.shardWriters))
+	err := w.shardWriters[shardID].Write(mp)
+	w.RUnlock()
+
+	return err
+}
+
+func (w *placementShardedWriter) Flush() error {
+	w.RLock()
+	defer w.RUnlock()
+
+	if w.closed {
+		return errPlacementShardedWriterClosed
+	}
+
+	var multiErr xerrors.MultiError
+	for _, writers := range w.instanceWriters {
+		for _, writer := range writers {
+			multiErr = multiErr.Add(writer.Flush())
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (w *placementShardedWriter) Close() error {
+	if w.value != nil {
+		w.value.Unwatch()
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return errPlacementShardedWriterClosed
+	}
+	w.closed = true
+
+	var multiErr xerrors.MultiError
+	for _, writers := range w.instanceWriters {
+		for _, writer := range writers {
+			multiErr = multiErr.Add(writer.Close())
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (w *placementShardedWriter) process(update interface{}) error {
+	p := update.(placement.Placement)
+	if p.NumInstances() == 0 || p.NumShards() == 0 {
+		// Keep the current writers until the placement has instances again.
+		return nil
+	}
+	if err := w.rebalance(p); err != nil {
+		w.metrics.rebalanceErrors.Inc(1)
+		w.logger.Error("could not rebalance placement sharded writer", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// rebalance assigns each shard of the placement to a writer of the instance
+// owning it. Writes are blocked while the shards are moved, and the writers
+// losing shards are flushed beforehand so that the writes they buffered for
+// the shards are handed off rather than dropped.
+func (w *placementShardedWriter) rebalance(p placement.Placement) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return errPlacementShardedWriterClosed
+	}
+
+	// Create the writers of the instances new to the placement first so that
+	// the rebalance can be aborted without changes.
+	instanceWriters := make(map[string][]*threadsafeWriter, p.NumInstances())
+	for _, instance := range p.Instances() {
+		id := instance.ID()
+		if writers, ok := w.instanceWriters[id]; ok {
+			instanceWriters[id] = writers
+			continue
+		}
+		writers, err := w.newInstanceWriters()
+		if err != nil {
+			for id, writers := range instanceWriters {
+				if _, ok := w.instanceWriters[id]; ok {
+					continue
+				}
+				for _, writer := range writers {
+					writer.Close() // nolint: errcheck
+				}
+			}
+			return err
+		}
+		instanceWriters[id] = writers
+	}
+
+	numShards := p.NumShards()
+	shardWriters := make([]*threadsafeWriter, numShards)
+	for shardID := range shardWriters {
+		owner, ok := shardOwner(p, uint32(shardID))
+		if !ok {
+			return fmt.Errorf("no instance owns shard %d", shardID)
+		}
+		shardWriters[shardID] = instanceWriters[owner][shardID%w.writersPerInstance]
+	}
+
+	// Hand off the writes buffered for the shards that move before the
+	// writes of the shards are reassigned.
+	var (
+		multiErr    xerrors.MultiError
+		flushed     = make(map[*threadsafeWriter]struct{})
+		shardsMoved int
+	)
+	for shardID, writer := range w.shardWriters {
+		if shardID < numShards && shardWriters[shardID] == writer {
+			continue
+		}
+		shardsMoved++
+		if _, ok := flushed[writer]; ok {
+			continue
+		}
+		flushed[writer] = struct{}{}
+		multiErr = multiErr.Add(writer.Flush())
+	}
+
+	// Close the writers of the instances removed from the placement.
+	var numWriters int
+	for id, writers := range w.instanceWriters {
+		if _, ok := instanceWriters[id]; ok {
+			continue
+		}
+		for _, writer := range writers {
+			if _, ok := flushed[writer]; !ok {
+				multiErr = multiErr.Add(writer.Flush())
+			}
+			multiErr = multiErr.Add(writer.Close())
+		}
+	}
+	for _, writers := range instanceWriters {
+		numWriters += len(writers)
+	}
+
+	w.shardWriters = shardWriters
+	w.instanceWriters = instanceWriters
+	w.metrics.rebalances.Inc(1)
+	w.metrics.shardsMoved.Inc(int64(shardsMoved))
+	w.metrics.numWriters.Update(float64(numWriters))
+	if err := multiErr.FinalError(); err != nil {
+		return fmt.Errorf("failed to hand off writes of placement sharded writer: %v", err)
+	}
+	return nil
+}
+
+func (w *placementShardedWriter) newInstanceWriters() ([]*threadsafeWriter, error) {
+	writers := make([]*threadsafeWriter, 0, w.writersPerInstance)
+	for i := 0; i < w.writersPerInstance; i++ {
+		writer, err := w.newWriterFn()
+		if err != nil {
+			for _, w := range writers {
+				w.Close() // nolint: errcheck
+			}
+			return nil, err
+		}
+		writers = append(writers, &threadsafeWriter{writer: writer})
+	}
+	return writers, nil
+}
+
+// shardOwner returns the instance a shard is written with, which is the
+// instance with the lowest ID among the ones owning the shard that are not
+// leaving it, so that the owner only changes when the instances owning the
+// shard do.
+func shardOwner(p placement.Placement, shardID uint32) (string, bool) {
+	var owners, leaving []string
+	for _, instance := range p.InstancesForShard(shardID) {
+		s, ok := instance.Shards().Shard(shardID)
+		if !ok {
+			continue
+		}
+		if s.State() == shard.Leaving {
+			leaving = append(leaving, instance.ID())
+			continue
+		}
+		owners = append(owners, instance.ID())
+	}
+	if len(owners) == 0 {
+		owners = leaving
+	}
+	if len(owners) == 0 {
+		return "", false
+	}
+	sort.Strings(owners)
+	return owners[0], true
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementShardedWriterRebalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w, err := newPlacementShardedWriter(PlacementShardedWriterOptions{
+		WritersPerInstance: 2,
+		NewWriterFn: func() (Writer, error) {
+			return NewMockWriter(ctrl), nil
+		},
+		ShardFn: func(chunkedID id.ChunkedID, _ int) uint32 {
+			shardID, err := strconv.Atoi(string(chunkedID.Data))
+			require.NoError(t, err)
+			return uint32(shardID)
+		},
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+
+	metric := aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{Data: []byte("2")},
+		},
+	}
+	require.Equal(t, errPlacementShardedWriterNoPlacement, w.Write(metric))
+
+	// Each shard is written with a writer of the instance owning it.
+	require.NoError(t, w.process(testShardedPlacement(map[string][]shard.Shard{
+		"i1": {shard.NewShard(0), shard.NewShard(1)},
+		"i2": {shard.NewShard(2), shard.NewShard(3)},
+	})))
+	i2 := testInstanceMockWriters(w, "i2")
+	i2[0].EXPECT().Write(metric).Return(nil)
+	require.NoError(t, w.Write(metric))
+
+	// Only the writer of the shard moving to the new instance is flushed.
+	i2[0].EXPECT().Flush().Return(nil)
+	require.NoError(t, w.process(testShardedPlacement(map[string][]shard.Shard{
+		"i1": {shard.NewShard(0), shard.NewShard(1)},
+		"i2": {shard.NewShard(2).SetState(shard.Leaving), shard.NewShard(3)},
+		"i3": {shard.NewShard(2).SetState(shard.Initializing)},
+	})))
+	i3 := testInstanceMockWriters(w, "i3")
+	i3[0].EXPECT().Write(metric).Return(nil)
+	require.NoError(t, w.Write(metric))
+
+	// The writers of the instances removed are flushed and closed.
+	for _, writer := range i2 {
+		writer.EXPECT().Flush().Return(nil)
+		writer.EXPECT().Close().Return(nil)
+	}
+	require.NoError(t, w.process(testShardedPlacement(map[string][]shard.Shard{
+		"i1": {shard.NewShard(0), shard.NewShard(1)},
+		"i3": {shard.NewShard(2), shard.NewShard(3)},
+	})))
+	require.Len(t, w.instanceWriters, 2)
+
+	// Placements without instances keep the current writers.
+	require.NoError(t, w.process(placement.NewPlacement()))
+	i3[0].EXPECT().Write(metric).Return(nil)
+	require.NoError(t, w.Write(metric))
+
+	for _, instance := range []string{"i1", "i3"} {
+		for _, writer := range testInstanceMockWriters(w, instance) {
+			writer.EXPECT().Close().Return(nil)
+		}
+	}
+	require.NoError(t, w.Close())
+	require.Equal(t, errPlacementShardedWriterClosed, w.Write(metric))
+}
+
+func testShardedPlacement(shards map[string][]shard.Shard) placement.Placement {
+	var (
+		instances []placement.Instance
+		shardIDs  = make(map[uint32]struct{})
+	)
+	for id, instanceShards := range shards {
+		for i, s := range instanceShards {
+			if s.State() == shard.Unknown {
+				instanceShards[i] = s.SetState(shard.Available)
+			}
+			shardIDs[s.ID()] = struct{}{}
+		}
+		instances = append(instances, placement.NewEmptyInstance(id, "", "", id, 1).
+			SetShards(shard.NewShards(instanceShards)))
+	}
+	ids := make([]uint32, 0, len(shardIDs))
+	for id := range shardIDs {
+		ids = append(ids, id)
+	}
+	return placement.NewPlacement().SetInstances(instances).SetShards(ids)
+}
+
+func testInstanceMockWriters(w *placementShardedWriter, instance string) []*MockWriter {
+	var writers []*MockWriter
+	for _, writer := range w.instanceWriters[instance] {
+		writers = append(writers, writer.writer.(*MockWriter))
+	}
+	return writers
+}
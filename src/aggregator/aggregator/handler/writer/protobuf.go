@@ -46,17 +46,24 @@ type protobufWriterMetrics struct {
 	encodeErrors  tally.Counter
 	routeSuccess  tally.Counter
 	routeErrors   tally.Counter
+	splitSuccess  tally.Counter
+	splitErrors   tally.Counter
+	splitMessages tally.Counter
 }
 
 func newProtobufWriterMetrics(scope tally.Scope) protobufWriterMetrics {
 	encodeScope := scope.SubScope("encode")
 	routeScope := scope.SubScope("route")
+	splitScope := scope.SubScope("split")
 	return protobufWriterMetrics{
 		writerClosed:  scope.Counter("writer-closed"),
 		encodeSuccess: encodeScope.Counter("success"),
 		encodeErrors:  encodeScope.Counter("errors"),
 		routeSuccess:  routeScope.Counter("success"),
 		routeErrors:   routeScope.Counter("errors"),
+		splitSuccess:  splitScope.Counter("success"),
+		splitErrors:   splitScope.Counter("errors"),
+		splitMessages: splitScope.Counter("messages"),
 	}
 }
 
@@ -67,6 +74,7 @@ type protobufWriter struct {
 	encoder                  protobuf.AggregatedEncoder
 	p                        producer.Producer
 	numShards                uint32
+	maxMessageSize           int
 
 	closed  bool
 	m       aggregated.MetricWithStoragePolicy
@@ -91,6 +99,7 @@ func NewProtobufWriter(
 		encoder:                  protobuf.NewAggregatedEncoder(opts.BytesPool()),
 		p:                        producer,
 		numShards:                producer.NumShards(),
+		maxMessageSize:           opts.MaxMessageSize(),
 		closed:                   false,
 		rand:                     rand.New(rand.NewSource(nowFn().UnixNano())),
 		metrics:                  newProtobufWriterMetrics(instrumentOpts.MetricsScope()),
@@ -117,7 +126,11 @@ func (w *protobufWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) err
 	}
 
 	w.metrics.encodeSuccess.Inc(1)
-	if err := w.p.Produce(newMessage(shard, mp.StoragePolicy, w.encoder.Buffer())); err != nil {
+	buf := w.encoder.Buffer()
+	if w.maxMessageSize > 0 && len(buf.Bytes()) > w.maxMessageSize {
+		return w.produceSplit(shard, mp.StoragePolicy, buf)
+	}
+	if err := w.p.Produce(newMessage(shard, mp.StoragePolicy, buf)); err != nil {
 		w.metrics.routeErrors.Inc(1)
 		return err
 	}
@@ -125,6 +138,34 @@ func (w *protobufWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) err
 	return nil
 }
 
+// produceSplit splits an encoded metric exceeding the max message size across
+// multiple messages routed to the same shard, which consumers reassemble,
+// rather than having the producer drop it.
+func (w *protobufWriter) produceSplit(
+	shard uint32,
+	sp policy.StoragePolicy,
+	buf protobuf.Buffer,
+) error {
+	// The fragments are copies so the encoded metric can be released once split.
+	defer buf.Close()
+
+	buffers, err := protobuf.SplitAggregated(buf.Bytes(), w.rand.Uint64(), w.maxMessageSize)
+	if err != nil {
+		w.metrics.splitErrors.Inc(1)
+		return err
+	}
+	w.metrics.splitSuccess.Inc(1)
+	w.metrics.splitMessages.Inc(int64(len(buffers)))
+	for _, b := range buffers {
+		if err := w.p.Produce(newMessage(shard, sp, b)); err != nil {
+			w.metrics.routeErrors.Inc(1)
+			return err
+		}
+	}
+	w.metrics.routeSuccess.Inc(1)
+	return nil
+}
+
 func (w *protobufWriter) prepare(mp aggregated.ChunkedMetricWithStoragePolicy) (aggregated.MetricWithStoragePolicy, uint32) {
 	// TODO(cw) Chunked metric has no 'type' field, consider adding one.
 	w.m.ID = w.m.ID[:0]
@@ -134,7 +175,9 @@ func (w *protobufWriter) prepare(mp aggregated.ChunkedMetricWithStoragePolicy) (
 	w.m.Metric.TimeNanos = mp.TimeNanos
 	w.m.Metric.Value = mp.Value
 	w.m.Annotation = mp.ChunkedMetric.Annotation
+	w.m.Exemplars = mp.ChunkedMetric.Exemplars
 	w.m.StoragePolicy = mp.StoragePolicy
+	w.m.DowngradedResolution = mp.DowngradedResolution
 	shard := w.shardFn(w.m.ID, w.numShards)
 	return w.m, shard
 }
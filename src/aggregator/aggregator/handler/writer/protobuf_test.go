@@ -197,6 +197,58 @@ func TestProtobufWriterWrite(t *testing.T) {
 
 }
 
+func TestProtobufWriterWriteSplit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const maxMessageSize = 64
+	writer := testProtobufWriter(t, ctrl, NewOptions().SetMaxMessageSize(maxMessageSize))
+
+	var (
+		shards      []uint32
+		splitCount  int
+		reassembled []byte
+	)
+	writer.p.(*producer.MockProducer).EXPECT().Produce(gomock.Any()).Do(func(m producer.Message) error {
+		require.True(t, m.Size() <= maxMessageSize)
+		d := protobuf.NewAggregatedDecoder(nil)
+		require.NoError(t, d.Decode(m.Bytes()))
+		split, ok := d.Split()
+		require.True(t, ok)
+		require.Equal(t, len(shards), split.Index)
+		splitCount = split.Count
+		shards = append(shards, m.Shard())
+		reassembled = append(reassembled, split.Payload...)
+		return nil
+	}).AnyTimes()
+
+	input := testChunkedMetricWithStoragePolicy
+	input.Annotation = bytes.Repeat([]byte("a"), 256)
+	require.NoError(t, writer.Write(input))
+	require.True(t, splitCount > 1)
+	require.Equal(t, splitCount, len(shards))
+	for _, s := range shards {
+		require.Equal(t, shards[0], s)
+	}
+
+	d := protobuf.NewAggregatedDecoder(nil)
+	require.NoError(t, d.Decode(reassembled))
+	require.Equal(t, testRawID, d.ID())
+	require.Equal(t, input.Annotation, d.Annotation())
+	require.Equal(t, input.StoragePolicy, d.StoragePolicy())
+
+	// Metrics within the max message size are not split.
+	writer = testProtobufWriter(t, ctrl, NewOptions().SetMaxMessageSize(4*maxMessageSize))
+	writer.p.(*producer.MockProducer).EXPECT().Produce(gomock.Any()).Do(func(m producer.Message) error {
+		d := protobuf.NewAggregatedDecoder(nil)
+		require.NoError(t, d.Decode(m.Bytes()))
+		_, ok := d.Split()
+		require.False(t, ok)
+		return nil
+	})
+	require.NoError(t, writer.Write(testChunkedMetricWithStoragePolicy))
+}
+
 func testProtobufWriter(t *testing.T, ctrl *gomock.Controller, opts Options) *protobufWriter {
 	p := producer.NewMockProducer(ctrl)
 	p.EXPECT().NumShards().Return(uint32(1024))
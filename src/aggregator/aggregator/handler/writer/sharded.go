@@ -37,14 +37,15 @@ var (
 )
 
 type shardedWriter struct {
-	mutex     sync.RWMutex
-	closed    bool
-	writers   []*threadsafeWriter
-	shardFn   sharding.AggregatedShardFn
-	numShards int
+	mutex      sync.RWMutex
+	closed     bool
+	writers    []*threadsafeWriter
+	shardFn    sharding.AggregatedShardFn
+	numShards  int
+	groupsPool sync.Pool
 }
 
-var _ Writer = &shardedWriter{}
+var _ BatchWriter = &shardedWriter{}
 
 // NewShardedWriter shards writes to the provided writers with the given sharding fn.
 func NewShardedWriter(
@@ -63,10 +64,15 @@ func NewShardedWriter(
 		})
 	}
 
+	numShards := len(writers)
 	return &shardedWriter{
-		numShards: len(writers),
+		numShards: numShards,
 		writers:   threadsafeWriters,
 		shardFn:   shardFn,
+		groupsPool: sync.Pool{New: func() interface{} {
+			groups := make([][]aggregated.ChunkedMetricWithStoragePolicy, numShards)
+			return &groups
+		}},
 	}, nil
 }
 
@@ -84,6 +90,43 @@ func (w *shardedWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) erro
 	return writerErr
 }
 
+func (w *shardedWriter) WriteBatch(mps []aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return errShardedWriterClosed
+	}
+
+	// Group the metrics by shard so that each backing writer is locked once
+	// per batch rather than once per metric.
+	groups := w.groupsPool.Get().(*[][]aggregated.ChunkedMetricWithStoragePolicy)
+	for _, mp := range mps {
+		shardID := w.shardFn(mp.ChunkedID, w.numShards)
+		(*groups)[shardID] = append((*groups)[shardID], mp)
+	}
+
+	var multiErr xerrors.MultiError
+	for shardID, group := range *groups {
+		if len(group) == 0 {
+			continue
+		}
+		multiErr = multiErr.Add(w.writers[shardID].WriteBatch(group))
+		// Release the references to the metrics before the group is reused.
+		for i := range group {
+			group[i] = aggregated.ChunkedMetricWithStoragePolicy{}
+		}
+		(*groups)[shardID] = group[:0]
+	}
+	w.groupsPool.Put(groups)
+
+	if multiErr.Empty() {
+		return nil
+	}
+
+	return errors.WithMessage(multiErr.FinalError(), "failed to write batch to sharded writer")
+}
+
 func (w *shardedWriter) Flush() error {
 	w.mutex.RLock()
 	defer w.mutex.RUnlock()
@@ -139,6 +182,14 @@ func (w *threadsafeWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) e
 	return err
 }
 
+// WriteBatch writes the metrics with the backing writer under a single lock.
+func (w *threadsafeWriter) WriteBatch(mps []aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.mutex.Lock()
+	err := WriteBatch(w.writer, mps)
+	w.mutex.Unlock()
+	return err
+}
+
 func (w *threadsafeWriter) Flush() error {
 	w.mutex.Lock()
 	err := w.writer.Flush()
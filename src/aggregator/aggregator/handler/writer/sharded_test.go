@@ -21,6 +21,7 @@
 package writer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
@@ -65,3 +66,81 @@ func TestNewShardedWriter(t *testing.T) {
 	w2.EXPECT().Close().Return(nil)
 	require.NoError(t, w.Close())
 }
+
+func TestShardedWriterWriteBatch(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	w1, w2 := NewMockWriter(ctrl), NewMockWriter(ctrl)
+	shardFn := func(chunkedID id.ChunkedID, _ int) uint32 {
+		return uint32(len(chunkedID.Data) % 2)
+	}
+
+	w, err := NewShardedWriter([]Writer{w1, w2}, shardFn, instrument.NewOptions())
+	require.NoError(t, err)
+
+	metrics := []aggregated.ChunkedMetricWithStoragePolicy{
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("ab")}}},
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("abc")}}},
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("abcd")}}},
+	}
+
+	w1.EXPECT().Write(metrics[0]).Return(nil)
+	w2.EXPECT().Write(metrics[1]).Return(nil)
+	w1.EXPECT().Write(metrics[2]).Return(errors.New("write error"))
+	err = WriteBatch(w, metrics)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "write error")
+
+	w1.EXPECT().Close().Return(nil)
+	w2.EXPECT().Close().Return(nil)
+	require.NoError(t, w.Close())
+	require.Equal(t, errShardedWriterClosed, WriteBatch(w, metrics))
+}
+
+func TestShardedWriterWriteBatchGroupsByShard(t *testing.T) {
+	w1, w2 := &testBatchWriter{}, &testBatchWriter{}
+	shardFn := func(chunkedID id.ChunkedID, _ int) uint32 {
+		return uint32(len(chunkedID.Data) % 2)
+	}
+
+	w, err := NewShardedWriter([]Writer{w1, w2}, shardFn, instrument.NewOptions())
+	require.NoError(t, err)
+
+	metrics := []aggregated.ChunkedMetricWithStoragePolicy{
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("ab")}}},
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("abc")}}},
+		{ChunkedMetric: aggregated.ChunkedMetric{ChunkedID: id.ChunkedID{Data: []byte("abcd")}}},
+	}
+
+	// Each backing writer is written the metrics of its shard in one batch.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, WriteBatch(w, metrics))
+	}
+	expected1 := [][]aggregated.ChunkedMetricWithStoragePolicy{
+		{metrics[0], metrics[2]},
+		{metrics[0], metrics[2]},
+	}
+	expected2 := [][]aggregated.ChunkedMetricWithStoragePolicy{
+		{metrics[1]},
+		{metrics[1]},
+	}
+	require.Equal(t, expected1, w1.batches)
+	require.Equal(t, expected2, w2.batches)
+}
+
+type testBatchWriter struct {
+	batches [][]aggregated.ChunkedMetricWithStoragePolicy
+}
+
+func (w *testBatchWriter) Write(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+	return w.WriteBatch([]aggregated.ChunkedMetricWithStoragePolicy{mp})
+}
+
+func (w *testBatchWriter) WriteBatch(mps []aggregated.ChunkedMetricWithStoragePolicy) error {
+	w.batches = append(w.batches, append([]aggregated.ChunkedMetricWithStoragePolicy(nil), mps...))
+	return nil
+}
+
+func (w *testBatchWriter) Flush() error { return nil }
+func (w *testBatchWriter) Close() error { return nil }
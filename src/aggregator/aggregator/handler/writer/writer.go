@@ -20,7 +20,10 @@
 
 package writer
 
-import "github.com/m3db/m3/src/metrics/metric/aggregated"
+import (
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/x/errors"
+)
 
 // Writer writes aggregated metrics alongside their policies.
 type Writer interface {
@@ -33,3 +36,28 @@ type Writer interface {
 	// Close closes the writer.
 	Close() error
 }
+
+// BatchWriter is a writer that can write a batch of aggregated metrics at once,
+// amortizing the per-write overhead across the batch.
+type BatchWriter interface {
+	Writer
+
+	// WriteBatch writes aggregated metrics alongside their storage policies.
+	WriteBatch(mps []aggregated.ChunkedMetricWithStoragePolicy) error
+}
+
+// WriteBatch writes a batch of aggregated metrics with the writer, in a single
+// call if the writer is a batch writer and one metric at a time otherwise.
+func WriteBatch(w Writer, mps []aggregated.ChunkedMetricWithStoragePolicy) error {
+	if bw, ok := w.(BatchWriter); ok {
+		return bw.WriteBatch(mps)
+	}
+
+	multiErr := errors.NewMultiError()
+	for _, mp := range mps {
+		if err := w.Write(mp); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	heartbeatLeaderSuffix      = "_leader"
+	heartbeatShardsOwnedSuffix = "_shards_owned"
+	heartbeatFlushLagSuffix    = "_flush_lag_seconds"
+)
+
+var (
+	heartbeatInstanceTagName = []byte("instance")
+	heartbeatShardTagName    = []byte("shard")
+
+	errHeartbeatNewIDFnNotSet = errors.New("heartbeat new id function not set")
+)
+
+type heartbeatMetrics struct {
+	emitted     tally.Counter
+	writeErrors tally.Counter
+	flushErrors tally.Counter
+}
+
+func newHeartbeatMetrics(scope tally.Scope) heartbeatMetrics {
+	return heartbeatMetrics{
+		emitted:     scope.Counter("emitted"),
+		writeErrors: scope.Counter("write-errors"),
+		flushErrors: scope.Counter("flush-errors"),
+	}
+}
+
+// heartbeatEmitter writes series describing the health of an aggregator instance
+// to the flush handler, so that the aggregator tier can be monitored through the
+// same pipeline it serves. For every heartbeat it emits:
+// * whether the instance is currently the leader of its shard set,
+// * the number of shards owned by the instance,
+// * per owned shard, the number of seconds since the oldest standard flush time.
+type heartbeatEmitter struct {
+	nowFn             clock.NowFn
+	instanceID        []byte
+	metricNamePrefix  string
+	newIDFn           id.NewIDFn
+	storagePolicy     policy.StoragePolicy
+	electionManager   ElectionManager
+	flushTimesManager FlushTimesManager
+	writer            writer.Writer
+	metrics           heartbeatMetrics
+}
+
+func newHeartbeatEmitter(opts Options, writer writer.Writer) (*heartbeatEmitter, error) {
+	newIDFn := opts.HeartbeatNewIDFn()
+	if newIDFn == nil {
+		return nil, errHeartbeatNewIDFnNotSet
+	}
+
+	scope := opts.InstrumentOptions().MetricsScope().SubScope("heartbeat")
+	return &heartbeatEmitter{
+		nowFn:             opts.ClockOptions().NowFn(),
+		instanceID:        []byte(opts.PlacementManager().InstanceID()),
+		metricNamePrefix:  opts.HeartbeatMetricNamePrefix(),
+		newIDFn:           newIDFn,
+		storagePolicy:     opts.HeartbeatStoragePolicy(),
+		electionManager:   opts.ElectionManager(),
+		flushTimesManager: opts.FlushTimesManager(),
+		writer:            writer,
+		metrics:           newHeartbeatMetrics(scope),
+	}, nil
+}
+
+// Interval returns the interval heartbeats should be emitted at.
+func (e *heartbeatEmitter) Interval() time.Duration {
+	return e.storagePolicy.Resolution().Window
+}
+
+// Emit writes a heartbeat for the given owned shards and flushes the writer.
+func (e *heartbeatEmitter) Emit(shardIDs []uint32) {
+	var (
+		now       = e.nowFn()
+		timeNanos = now.Truncate(e.Interval()).UnixNano()
+		leader    float64
+	)
+	if e.electionManager.ElectionState() == LeaderState {
+		leader = 1
+	}
+	e.write(heartbeatLeaderSuffix, nil, timeNanos, leader)
+	e.write(heartbeatShardsOwnedSuffix, nil, timeNanos, float64(len(shardIDs)))
+
+	// Flush times are only unavailable before they have been persisted for the first time,
+	// in which case there is no flush lag to report yet.
+	if flushTimes, err := e.flushTimesManager.Get(); err == nil && flushTimes != nil {
+		for _, shardID := range shardIDs {
+			shardFlushTimes, ok := flushTimes.ByShard[shardID]
+			if !ok || shardFlushTimes == nil || len(shardFlushTimes.StandardByResolution) == 0 {
+				continue
+			}
+			var oldestNanos int64
+			for _, lastFlushedNanos := range shardFlushTimes.StandardByResolution {
+				if oldestNanos == 0 || lastFlushedNanos < oldestNanos {
+					oldestNanos = lastFlushedNanos
+				}
+			}
+			lag := now.Sub(time.Unix(0, oldestNanos)).Seconds()
+			shardTag := id.TagPair{
+				Name:  heartbeatShardTagName,
+				Value: []byte(strconv.FormatUint(uint64(shardID), 10)),
+			}
+			e.write(heartbeatFlushLagSuffix, []id.TagPair{shardTag}, timeNanos, lag)
+		}
+	}
+
+	if err := e.writer.Flush(); err != nil {
+		e.metrics.flushErrors.Inc(1)
+	}
+}
+
+// Close closes the heartbeat writer.
+func (e *heartbeatEmitter) Close() error {
+	return e.writer.Close()
+}
+
+func (e *heartbeatEmitter) write(
+	nameSuffix string,
+	tags []id.TagPair,
+	timeNanos int64,
+	value float64,
+) {
+	tags = append(tags, id.TagPair{Name: heartbeatInstanceTagName, Value: e.instanceID})
+	metricID := e.newIDFn([]byte(e.metricNamePrefix+nameSuffix), tags)
+	if len(metricID) == 0 {
+		e.metrics.writeErrors.Inc(1)
+		return
+	}
+	metric := aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: id.ChunkedID{
+				Data: metricID,
+			},
+			TimeNanos: timeNanos,
+			Value:     value,
+		},
+		StoragePolicy: e.storagePolicy,
+	}
+	if err := e.writer.Write(metric); err != nil {
+		e.metrics.writeErrors.Inc(1)
+		return
+	}
+	e.metrics.emitted.Inc(1)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatEmitterEmit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now           = time.Unix(1000, 0)
+		storagePolicy = policy.NewStoragePolicy(10*time.Second, xtime.Second, time.Hour)
+		written       = make(map[string]aggregated.ChunkedMetricWithStoragePolicy)
+	)
+
+	placementManager := NewMockPlacementManager(ctrl)
+	placementManager.EXPECT().InstanceID().Return("instance1")
+
+	electionManager := NewMockElectionManager(ctrl)
+	electionManager.EXPECT().ElectionState().Return(LeaderState)
+
+	flushTimesManager := NewMockFlushTimesManager(ctrl)
+	flushTimesManager.EXPECT().Get().Return(&schema.ShardSetFlushTimes{
+		ByShard: map[uint32]*schema.ShardFlushTimes{
+			1: {
+				StandardByResolution: map[int64]int64{
+					int64(10 * time.Second): now.Add(-20 * time.Second).UnixNano(),
+					int64(time.Minute):      now.Add(-90 * time.Second).UnixNano(),
+				},
+			},
+		},
+	}, nil)
+
+	w := writer.NewMockWriter(ctrl)
+	w.EXPECT().Write(gomock.Any()).DoAndReturn(func(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+		written[string(mp.ChunkedID.Data)] = mp
+		return nil
+	}).Times(3)
+	w.EXPECT().Flush().Return(nil)
+
+	opts := newTestOptions().
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now })).
+		SetPlacementManager(placementManager).
+		SetElectionManager(electionManager).
+		SetFlushTimesManager(flushTimesManager).
+		SetHeartbeatStoragePolicy(storagePolicy).
+		SetHeartbeatNewIDFn(testHeartbeatNewIDFn)
+
+	emitter, err := newHeartbeatEmitter(opts, w)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, emitter.Interval())
+
+	// Shard 2 has no flush times yet and so has no flush lag.
+	emitter.Emit([]uint32{1, 2})
+
+	expected := map[string]float64{
+		"m3aggregator_heartbeat_leader{instance=instance1}":                    1,
+		"m3aggregator_heartbeat_shards_owned{instance=instance1}":              2,
+		"m3aggregator_heartbeat_flush_lag_seconds{shard=1,instance=instance1}": 90,
+	}
+	require.Equal(t, len(expected), len(written))
+	for id, value := range expected {
+		mp, ok := written[id]
+		require.True(t, ok, id)
+		require.Equal(t, value, mp.Value)
+		require.Equal(t, now.UnixNano(), mp.TimeNanos)
+		require.Equal(t, storagePolicy, mp.StoragePolicy)
+	}
+}
+
+func TestHeartbeatEmitterRequiresNewIDFn(t *testing.T) {
+	_, err := newHeartbeatEmitter(newTestOptions(), writer.NewBlackholeWriter())
+	require.Equal(t, errHeartbeatNewIDFnNotSet, err)
+}
+
+func testHeartbeatNewIDFn(name []byte, tags []id.TagPair) []byte {
+	var buf bytes.Buffer
+	buf.Write(name)
+	buf.WriteByte('{')
+	for i, tag := range tags {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(tag.Name)
+		buf.WriteByte('=')
+		buf.Write(tag.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
@@ -48,10 +48,12 @@ func newLeaderFlusherMetrics(scope tally.Scope) leaderFlusherMetrics {
 }
 
 type leaderFlushManagerMetrics struct {
-	queueSize tally.Gauge
-	standard  leaderFlusherMetrics
-	forwarded leaderFlusherMetrics
-	timed     leaderFlusherMetrics
+	queueSize           tally.Gauge
+	shardFlushThrottled tally.Counter
+	shardFlushPaced     tally.Counter
+	standard            leaderFlusherMetrics
+	forwarded           leaderFlusherMetrics
+	timed               leaderFlusherMetrics
 }
 
 func newLeaderFlushManagerMetrics(scope tally.Scope) leaderFlushManagerMetrics {
@@ -59,10 +61,12 @@ func newLeaderFlushManagerMetrics(scope tally.Scope) leaderFlushManagerMetrics {
 	forwardedScope := scope.Tagged(map[string]string{"flusher-type": "forwarded"})
 	timedScope := scope.Tagged(map[string]string{"flusher-type": "timed"})
 	return leaderFlushManagerMetrics{
-		queueSize: scope.Gauge("queue-size"),
-		standard:  newLeaderFlusherMetrics(standardScope),
-		forwarded: newLeaderFlusherMetrics(forwardedScope),
-		timed:     newLeaderFlusherMetrics(timedScope),
+		queueSize:           scope.Gauge("queue-size"),
+		shardFlushThrottled: scope.Counter("shard-flush-throttled"),
+		shardFlushPaced:     scope.Counter("shard-flush-paced"),
+		standard:            newLeaderFlusherMetrics(standardScope),
+		forwarded:           newLeaderFlusherMetrics(forwardedScope),
+		timed:               newLeaderFlusherMetrics(timedScope),
 	}
 }
 
@@ -78,8 +82,10 @@ type leaderFlushManager struct {
 	flushTimesManager      FlushTimesManager
 	flushTimesPersistEvery time.Duration
 	maxBufferSize          time.Duration
+	shardFlushPacing       float64
 	logger                 *zap.Logger
 	scope                  tally.Scope
+	sleepFn                sleepFn
 
 	doneCh              <-chan struct{}
 	flushTimes          flushMetadataHeap
@@ -87,7 +93,12 @@ type leaderFlushManager struct {
 	lastPersistAtNanos  int64
 	flushedSincePersist bool
 	flushTask           *leaderFlushTask
+	shardFlushSlots     chan struct{}
 	metrics             leaderFlushManagerMetrics
+
+	// NB: the paced flushes by bucket are only accessed by the flush loop.
+	pacedFlushes         sync.WaitGroup
+	pacedFlushesByBucket map[int]chan struct{}
 }
 
 func newLeaderFlushManager(
@@ -105,12 +116,18 @@ func newLeaderFlushManager(
 		flushTimesManager:      opts.FlushTimesManager(),
 		flushTimesPersistEvery: opts.FlushTimesPersistEvery(),
 		maxBufferSize:          opts.MaxBufferSize(),
+		shardFlushPacing:       opts.ShardFlushPacing(),
 		logger:                 instrumentOpts.Logger(),
 		scope:                  scope,
 		doneCh:                 doneCh,
 		flushedByShard:         make(map[uint32]*schema.ShardFlushTimes, defaultInitialFlushCapacity),
 		lastPersistAtNanos:     nowFn().UnixNano(),
 		metrics:                newLeaderFlushManagerMetrics(scope),
+		pacedFlushesByBucket:   make(map[int]chan struct{}),
+	}
+	mgr.sleepFn = mgr.sleepUntilDone
+	if n := opts.MaxConcurrentShardFlushes(); n > 0 {
+		mgr.shardFlushSlots = make(chan struct{}, n)
 	}
 	mgr.flushTask = &leaderFlushTask{
 		mgr:      mgr,
@@ -162,6 +179,8 @@ func (mgr *leaderFlushManager) Prepare(buckets []*flushBucket) (flushTask, time.
 			// inside the bucket may be modified during task execution when new
 			// flushers are registered or old flushers are unregistered.
 			mgr.flushTask.duration = buckets[bucketIdx].duration
+			mgr.flushTask.interval = buckets[bucketIdx].interval
+			mgr.flushTask.bucketIdx = bucketIdx
 			mgr.flushTask.flushers = append(mgr.flushTask.flushers[:0], buckets[bucketIdx].flushers...)
 			nextFlushMetadata := flushMetadata{
 				timeNanos: earliestFlush.timeNanos + int64(buckets[bucketIdx].interval),
@@ -233,7 +252,11 @@ func (mgr *leaderFlushManager) OnFlusherAdded(
 // NB(xichen): leader flush manager can always lead.
 func (mgr *leaderFlushManager) CanLead() bool { return true }
 
-func (mgr *leaderFlushManager) Close() {}
+// Close waits for the paced flushes in progress, which no longer wait between
+// shards once the manager is done.
+func (mgr *leaderFlushManager) Close() {
+	mgr.pacedFlushes.Wait()
+}
 
 func (mgr *leaderFlushManager) enqueueBucketWithLock(
 	bucketIdx int,
@@ -247,6 +270,17 @@ func (mgr *leaderFlushManager) enqueueBucketWithLock(
 	mgr.flushTimes.Push(newFlushMetadata)
 }
 
+// sleepUntilDone sleeps for the given duration, or until the manager is closed
+// so pacing does not hold up closing the flush manager.
+func (mgr *leaderFlushManager) sleepUntilDone(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-mgr.doneCh:
+	}
+}
+
 func (mgr *leaderFlushManager) computeNextFlushNanos(
 	flushInterval, flushOffset time.Duration,
 ) int64 {
@@ -467,12 +501,55 @@ func cloneForwardedFlushTimesForResolution(
 }
 
 type leaderFlushTask struct {
-	mgr      *leaderFlushManager
-	duration tally.Timer
-	flushers []flushingMetricList
+	mgr       *leaderFlushManager
+	duration  tally.Timer
+	interval  time.Duration
+	bucketIdx int
+	flushers  []flushingMetricList
 }
 
+// Run flushes the shards of the bucket. If pacing is enabled, the paced flushes
+// run in the background so the flush loop can start the flushes of other
+// buckets, and the flushes of a bucket start once the previous paced flushes
+// of the same bucket have completed.
 func (t *leaderFlushTask) Run() {
+	mgr := t.mgr
+	start := mgr.nowFn()
+	pacingStep := t.pacingStep()
+	if pacingStep <= 0 {
+		t.flush(t.flushers, start, 0)
+		return
+	}
+
+	// NB: the task and its flushers are reused by the next flush so the paced
+	// flush takes a copy of the flushers.
+	flushers := make([]flushingMetricList, len(t.flushers))
+	copy(flushers, t.flushers)
+	task := &leaderFlushTask{
+		mgr:       mgr,
+		duration:  t.duration,
+		interval:  t.interval,
+		bucketIdx: t.bucketIdx,
+	}
+	prevDone := mgr.pacedFlushesByBucket[t.bucketIdx]
+	done := make(chan struct{})
+	mgr.pacedFlushesByBucket[t.bucketIdx] = done
+	mgr.pacedFlushes.Add(1)
+	go func() {
+		defer mgr.pacedFlushes.Done()
+		defer close(done)
+		if prevDone != nil {
+			<-prevDone
+		}
+		task.flush(flushers, start, pacingStep)
+	}()
+}
+
+func (t *leaderFlushTask) flush(
+	flushers []flushingMetricList,
+	start time.Time,
+	pacingStep time.Duration,
+) {
 	mgr := t.mgr
 	shards, err := mgr.placementManager.Shards()
 	if err != nil {
@@ -482,9 +559,20 @@ func (t *leaderFlushTask) Run() {
 
 	var (
 		wgWorkers sync.WaitGroup
-		start     = mgr.nowFn()
+		slots     = mgr.shardFlushSlots
 	)
-	for _, flusher := range t.flushers {
+	for i, flusher := range flushers {
+		// Spread the start of the flushes of the shards over the pacing window
+		// to smooth out the writes downstream at flush boundaries. The start of
+		// each flush is relative to the start of the task so time spent waiting
+		// for a concurrent flush slot is not added on top of the pacing.
+		if pacingStep > 0 && i > 0 {
+			if wait := start.Add(time.Duration(i) * pacingStep).Sub(mgr.nowFn()); wait > 0 {
+				mgr.metrics.shardFlushPaced.Inc(1)
+				mgr.sleepFn(wait)
+			}
+		}
+
 		// By default traffic is cut off from a shard, unless the shard is in the list of
 		// shards owned by the instance, in which case the cutover time and the cutoff time
 		// are set to the corresponding cutover and cutoff times of the shard.
@@ -504,10 +592,21 @@ func (t *leaderFlushTask) Run() {
 			CutoffNanos:       cutoffNanos,
 			BufferAfterCutoff: mgr.maxBufferSize,
 		}
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			default:
+				mgr.metrics.shardFlushThrottled.Inc(1)
+				slots <- struct{}{}
+			}
+		}
 		flusher := flusher
 		wgWorkers.Add(1)
 		mgr.workers.Go(func() {
 			flusher.Flush(req)
+			if slots != nil {
+				<-slots
+			}
 			wgWorkers.Done()
 		})
 	}
@@ -515,6 +614,17 @@ func (t *leaderFlushTask) Run() {
 	t.duration.Record(mgr.nowFn().Sub(start))
 }
 
+// pacingStep returns the delay between the start of the flushes of two
+// consecutive shards, zero if pacing is disabled.
+func (t *leaderFlushTask) pacingStep() time.Duration {
+	pacing := t.mgr.shardFlushPacing
+	if pacing <= 0 || len(t.flushers) < 2 {
+		return 0
+	}
+	window := time.Duration(float64(t.interval) * pacing)
+	return window / time.Duration(len(t.flushers))
+}
+
 // flushMetadata contains metadata information for a flush.
 type flushMetadata struct {
 	timeNanos int64
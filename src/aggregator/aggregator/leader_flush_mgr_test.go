@@ -22,11 +22,13 @@ package aggregator
 
 import (
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
 	"github.com/m3db/m3/src/cluster/shard"
+	xsync "github.com/m3db/m3/src/x/sync"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
@@ -687,6 +689,102 @@ func TestLeaderFlushTaskRunWithFlushes(t *testing.T) {
 	require.Equal(t, expected, requests)
 }
 
+func TestLeaderFlushTaskRunWithPacing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var flushers []flushingMetricList
+	for i := 0; i < 4; i++ {
+		flusher := NewMockflushingMetricList(ctrl)
+		flusher.EXPECT().Shard().Return(uint32(i)).AnyTimes()
+		flusher.EXPECT().Flush(gomock.Any())
+		flushers = append(flushers, flusher)
+	}
+	placementManager := NewMockPlacementManager(ctrl)
+	placementManager.EXPECT().Shards().Return(shard.NewShards(nil), nil)
+
+	opts := NewFlushManagerOptions().SetShardFlushPacing(0.5)
+	mgr := newLeaderFlushManager(make(chan struct{}), opts).(*leaderFlushManager)
+	mgr.placementManager = placementManager
+	now := time.Unix(1000, 0)
+	mgr.nowFn = func() time.Time { return now }
+	var (
+		slept   []time.Duration
+		sleepCh = make(chan struct{})
+	)
+	mgr.sleepFn = func(d time.Duration) {
+		<-sleepCh
+		slept = append(slept, d)
+	}
+
+	flushTask := &leaderFlushTask{
+		mgr:      mgr,
+		duration: tally.NoopScope.Timer("foo"),
+		interval: 4 * time.Second,
+		flushers: flushers,
+	}
+
+	// The paced flushes run in the background without blocking the flush loop.
+	flushTask.Run()
+	close(sleepCh)
+	mgr.Close()
+
+	// The flushes are spread over half of the flush interval.
+	require.Equal(t, []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		1500 * time.Millisecond,
+	}, slept)
+}
+
+func TestLeaderFlushTaskRunWithMaxConcurrentShardFlushes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		flushing    int32
+		maxFlushing int32
+		flushers    []flushingMetricList
+	)
+	for i := 0; i < 8; i++ {
+		flusher := NewMockflushingMetricList(ctrl)
+		flusher.EXPECT().Shard().Return(uint32(i)).AnyTimes()
+		flusher.EXPECT().
+			Flush(gomock.Any()).
+			Do(func(flushRequest) {
+				n := atomic.AddInt32(&flushing, 1)
+				for {
+					prev := atomic.LoadInt32(&maxFlushing)
+					if n <= prev || atomic.CompareAndSwapInt32(&maxFlushing, prev, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&flushing, -1)
+			})
+		flushers = append(flushers, flusher)
+	}
+	placementManager := NewMockPlacementManager(ctrl)
+	placementManager.EXPECT().Shards().Return(shard.NewShards(nil), nil)
+
+	workers := xsync.NewWorkerPool(8)
+	workers.Init()
+	opts := NewFlushManagerOptions().
+		SetWorkerPool(workers).
+		SetMaxConcurrentShardFlushes(2)
+	mgr := newLeaderFlushManager(make(chan struct{}), opts).(*leaderFlushManager)
+	mgr.placementManager = placementManager
+	flushTask := &leaderFlushTask{
+		mgr:      mgr,
+		duration: tally.NoopScope.Timer("foo"),
+		flushers: flushers,
+	}
+	flushTask.Run()
+
+	require.Equal(t, int32(0), atomic.LoadInt32(&flushing))
+	require.True(t, atomic.LoadInt32(&maxFlushing) <= 2)
+}
+
 func validateShardSetFlushTimes(t *testing.T, expected, actual *schema.ShardSetFlushTimes) {
 	standardFlushTimesComparer := cmp.Comparer(func(a, b map[int64]int64) bool {
 		if len(a) != len(b) {
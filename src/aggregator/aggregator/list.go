@@ -24,12 +24,15 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	metricid "github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
@@ -54,21 +57,31 @@ type metricList interface {
 	// PushBack pushes a metric element to the back of the list.
 	PushBack(value metricElem) (*list.Element, error)
 
+	// MergeWindowDigests adds the digests of the windows flushed with timestamps
+	// in [startNanos, endNanos) to the merged digests.
+	MergeWindowDigests(startNanos, endNanos int64, merged map[windowDigestKey]windowDigest)
+
 	// Close closes the metric list.
 	Close()
 }
 
 type metricProcessingMetrics struct {
-	metricConsumeSuccess tally.Counter
-	metricConsumeErrors  tally.Counter
-	metricDiscarded      tally.Counter
+	metricConsumeSuccess    tally.Counter
+	metricConsumeErrors     tally.Counter
+	metricDiscarded         tally.Counter
+	metricDowngraded        tally.Counter
+	metricDowngradeMerged   tally.Counter
+	metricDowngradeOverflow tally.Counter
 }
 
 func newMetricProcessingMetrics(scope tally.Scope) metricProcessingMetrics {
 	return metricProcessingMetrics{
-		metricConsumeSuccess: scope.Counter("metric-consume-success"),
-		metricConsumeErrors:  scope.Counter("metric-consume-errors"),
-		metricDiscarded:      scope.Counter("metric-discarded"),
+		metricConsumeSuccess:    scope.Counter("metric-consume-success"),
+		metricConsumeErrors:     scope.Counter("metric-consume-errors"),
+		metricDiscarded:         scope.Counter("metric-discarded"),
+		metricDowngraded:        scope.Counter("metric-downgraded"),
+		metricDowngradeMerged:   scope.Counter("metric-downgrade-merged"),
+		metricDowngradeOverflow: scope.Counter("metric-downgrade-overflow"),
 	}
 }
 
@@ -159,6 +172,7 @@ type baseMetricList struct {
 	opts             Options
 	nowFn            clock.NowFn
 	timeLock         *sync.RWMutex
+	flushLock        sync.Mutex
 	flushHandler     handler.Handler
 	localWriter      writer.Writer
 	forwardedWriter  forwardedMetricWriter
@@ -166,11 +180,14 @@ type baseMetricList struct {
 	targetNanosFn    targetNanosFn
 	isEarlierThanFn  isEarlierThanFn
 	timestampNanosFn timestampNanosFn
+	downgrader       *ResolutionDowngrader
 
 	closed           bool
 	aggregations     *list.List
 	lastFlushedNanos int64
 	toCollect        []*list.Element
+	downgradeBuckets map[downgradeKey]*downgradeBucket
+	windowDigests    *windowDigests
 	metrics          baseMetricListMetrics
 
 	flushBeforeFn               flushBeforeFn
@@ -212,6 +229,7 @@ func newBaseMetricList(
 		targetNanosFn:    targetNanosFn,
 		isEarlierThanFn:  isEarlierThanFn,
 		timestampNanosFn: timestampNanosFn,
+		downgrader:       opts.ResolutionDowngrader(),
 		aggregations:     list.New(),
 		metrics:          newMetricListMetrics(scope),
 	}
@@ -222,6 +240,9 @@ func newBaseMetricList(
 	l.discardForwardedMetricFn = l.discardForwardedMetric
 	l.onForwardingElemConsumedFn = l.onForwardingElemConsumed
 	l.onForwardingElemDiscardedFn = l.onForwardingElemDiscarded
+	if verificationOpts := opts.VerificationOptions(); verificationOpts != nil {
+		l.windowDigests = newWindowDigests(verificationOpts.Retention)
+	}
 
 	return l, nil
 }
@@ -231,6 +252,17 @@ func (l *baseMetricList) Resolution() time.Duration    { return l.resolution }
 func (l *baseMetricList) FlushInterval() time.Duration { return l.resolution }
 func (l *baseMetricList) LastFlushedNanos() int64      { return atomic.LoadInt64(&l.lastFlushedNanos) }
 
+// MergeWindowDigests adds the digests of the windows flushed with timestamps
+// in [startNanos, endNanos) to the merged digests.
+func (l *baseMetricList) MergeWindowDigests(
+	startNanos, endNanos int64,
+	merged map[windowDigestKey]windowDigest,
+) {
+	if l.windowDigests != nil {
+		l.windowDigests.MergeTo(startNanos, endNanos, merged)
+	}
+}
+
 // Len returns the number of elements in the list.
 func (l *baseMetricList) Len() int {
 	l.RLock()
@@ -287,6 +319,12 @@ func (l *baseMetricList) Close() bool {
 }
 
 func (l *baseMetricList) Flush(req flushRequest) {
+	// NB: the leader flushes paced shards in the background, which can overlap
+	// with a follower flush after the leadership is lost, so flushes of the
+	// list are serialized.
+	l.flushLock.Lock()
+	defer l.flushLock.Unlock()
+
 	start := l.nowFn()
 
 	defer func() {
@@ -333,6 +371,9 @@ func (l *baseMetricList) Flush(req flushRequest) {
 }
 
 func (l *baseMetricList) DiscardBefore(beforeNanos int64) {
+	l.flushLock.Lock()
+	defer l.flushLock.Unlock()
+
 	l.flushBeforeFn(beforeNanos, discardType)
 	l.metrics.discardBefore.Inc(1)
 }
@@ -383,6 +424,10 @@ func (l *baseMetricList) flushBefore(beforeNanos int64, flushType flushType) {
 	}
 	l.RUnlock()
 
+	// Emit or drop the coarse windows re-bucketed by the resolution downgrade
+	// mode that cannot receive any more values.
+	l.flushDowngradeBuckets(beforeNanos, flushType)
+
 	if flushType == consumeType {
 		// Flush remaining bytes buffered in the local writer.
 		if err := l.localWriter.Flush(); err != nil {
@@ -418,6 +463,10 @@ func (l *baseMetricList) flushBefore(beforeNanos int64, flushType flushType) {
 	numCollected := len(l.toCollect)
 	l.Unlock()
 
+	if l.windowDigests != nil {
+		l.windowDigests.Expire(l.nowFn().UnixNano())
+	}
+
 	atomic.StoreInt64(&l.lastFlushedNanos, beforeNanos)
 	l.metrics.flushElemCollected.Inc(int64(numCollected))
 	flushBeforeDuration := l.nowFn().Sub(flushBeforeStart)
@@ -431,8 +480,20 @@ func (l *baseMetricList) consumeLocalMetric(
 	timeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	aggType aggregation.Type,
 	sp policy.StoragePolicy,
 ) {
+	if l.windowDigests != nil {
+		l.windowDigests.Add(idPrefix, id, idSuffix, timeNanos, value, sp)
+	}
+	if l.downgrader != nil {
+		if resolution, ok := l.downgrader.DowngradedResolution(sp); ok &&
+			l.downgradeLocalMetric(idPrefix, id, idSuffix, timeNanos, value, annotation,
+				exemplars, aggType, sp, resolution) {
+			return
+		}
+	}
 	chunkedID := metricid.ChunkedID{
 		Prefix: idPrefix,
 		Data:   []byte(id),
@@ -444,16 +505,116 @@ func (l *baseMetricList) consumeLocalMetric(
 			TimeNanos:  timeNanos,
 			Value:      value,
 			Annotation: annotation,
+			Exemplars:  exemplars,
 		},
-		StoragePolicy: sp,
+		StoragePolicy:   sp,
+		AggregationType: aggType,
 	}
-	if err := l.localWriter.Write(chunkedMetricWithPolicy); err != nil {
+	l.writeLocalMetric(chunkedMetricWithPolicy)
+}
+
+func (l *baseMetricList) writeLocalMetric(m aggregated.ChunkedMetricWithStoragePolicy) {
+	if err := l.localWriter.Write(m); err != nil {
 		l.metrics.flushLocal.metricConsumeErrors.Inc(1)
 	} else {
 		l.metrics.flushLocal.metricConsumeSuccess.Inc(1)
 	}
 }
 
+// downgradeLocalMetric re-aggregates a datapoint into the coarse window of the
+// downgraded resolution it falls into, emitting the window once the datapoint
+// closing it is flushed. It returns false without re-bucketing the datapoint if
+// the list already holds the maximum number of open coarse windows.
+func (l *baseMetricList) downgradeLocalMetric(
+	idPrefix []byte,
+	id metricid.RawID,
+	idSuffix []byte,
+	timeNanos int64,
+	value float64,
+	annotation []byte,
+	exemplars []metric.Exemplar,
+	aggType aggregation.Type,
+	sp policy.StoragePolicy,
+	resolution time.Duration,
+) bool {
+	var (
+		windowEndNanos = downgradedWindowEndNanos(timeNanos, resolution)
+		key            = downgradeKey{
+			id: string(idPrefix) + string(id) + string(idSuffix),
+			sp: sp,
+		}
+	)
+	if l.downgradeBuckets == nil {
+		l.downgradeBuckets = make(map[downgradeKey]*downgradeBucket)
+	}
+	bucket, exists := l.downgradeBuckets[key]
+	if !exists {
+		if len(l.downgradeBuckets) >= l.downgrader.MaxOpenWindows() {
+			l.metrics.flushLocal.metricDowngradeOverflow.Inc(1)
+			return false
+		}
+		bucket = &downgradeBucket{
+			idPrefix: append([]byte(nil), idPrefix...),
+			id:       append([]byte(nil), id...),
+			idSuffix: append([]byte(nil), idSuffix...),
+		}
+		l.downgradeBuckets[key] = bucket
+	} else if bucket.windowEndNanos != windowEndNanos {
+		// The datapoint closing the previous coarse window was never flushed.
+		l.writeDowngradeBucket(key.sp, bucket)
+		bucket.reset()
+	} else {
+		l.metrics.flushLocal.metricDowngradeMerged.Inc(1)
+	}
+	bucket.windowEndNanos = windowEndNanos
+	bucket.resolution = resolution
+	bucket.add(value, aggType)
+	bucket.annotation = append(bucket.annotation[:0], annotation...)
+	bucket.exemplars = addExemplars(bucket.exemplars, exemplars, l.opts.MaxExemplarsPerAggregation())
+
+	if timeNanos == windowEndNanos {
+		l.writeDowngradeBucket(key.sp, bucket)
+		delete(l.downgradeBuckets, key)
+	}
+	return true
+}
+
+func (l *baseMetricList) writeDowngradeBucket(sp policy.StoragePolicy, bucket *downgradeBucket) {
+	l.metrics.flushLocal.metricDowngraded.Inc(1)
+	l.writeLocalMetric(aggregated.ChunkedMetricWithStoragePolicy{
+		ChunkedMetric: aggregated.ChunkedMetric{
+			ChunkedID: metricid.ChunkedID{
+				Prefix: bucket.idPrefix,
+				Data:   bucket.id,
+				Suffix: bucket.idSuffix,
+			},
+			TimeNanos:  bucket.windowEndNanos,
+			Value:      bucket.value,
+			Annotation: bucket.annotation,
+			Exemplars:  bucket.exemplars,
+		},
+		StoragePolicy:        sp,
+		DowngradedResolution: bucket.resolution,
+		AggregationType:      bucket.aggType,
+	})
+}
+
+// flushDowngradeBuckets emits the coarse windows ending before the given time,
+// which no datapoint flushed later can fall into, and drops all windows when
+// the datapoints are discarded.
+func (l *baseMetricList) flushDowngradeBuckets(beforeNanos int64, flushType flushType) {
+	for key, bucket := range l.downgradeBuckets {
+		if flushType == discardType {
+			delete(l.downgradeBuckets, key)
+			continue
+		}
+		if bucket.windowEndNanos <= beforeNanos {
+			l.writeDowngradeBucket(key.sp, bucket)
+			delete(l.downgradeBuckets, key)
+		}
+	}
+}
+
 // nolint: unparam
 func (l *baseMetricList) discardLocalMetric(
 	idPrefix []byte,
@@ -462,8 +623,15 @@ func (l *baseMetricList) discardLocalMetric(
 	timeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	aggType aggregation.Type,
 	sp policy.StoragePolicy,
 ) {
+	// NB: followers digest the windows they discard so the leader can verify
+	// they would have flushed the same metrics.
+	if l.windowDigests != nil {
+		l.windowDigests.Add(idPrefix, id, idSuffix, timeNanos, value, sp)
+	}
 	l.metrics.flushLocal.metricDiscarded.Inc(1)
 }
 
@@ -474,8 +642,11 @@ func (l *baseMetricList) consumeForwardedMetric(
 	value float64,
 	prevValue float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
 ) {
-	writeFn(aggregationKey, timeNanos, value, prevValue, annotation)
+	writeFn(aggregationKey, timeNanos, value, prevValue, annotation, exemplars, histogram, contributors)
 	l.metrics.flushForwarded.metricConsumed.Inc(1)
 }
 
@@ -487,6 +658,9 @@ func (l *baseMetricList) discardForwardedMetric(
 	value float64,
 	prevValue float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
+	histogram forwardedHistogram,
+	contributors *contributorsSketch,
 ) {
 	l.metrics.flushForwarded.metricDiscarded.Inc(1)
 }
@@ -645,9 +819,10 @@ func newForwardedMetricList(
 		listScope            = iOpts.MetricsScope().Tagged(map[string]string{"list-type": "forwarded"})
 	)
 	// Forwarded metrics that have been kept for longer than the maximum lateness
-	// allowed will be flushed.
+	// allowed will be flushed. The maximum lateness allowed is evaluated on each
+	// flush as it may be scaled at runtime, the flush offset remains fixed.
 	targetNanosFn := func(nowNanos int64) int64 {
-		return nowNanos - maxLatenessAllowed.Nanoseconds()
+		return nowNanos - maxLatenessAllowedFn(resolution, numForwardedTimes).Nanoseconds()
 	}
 	l, err := newBaseMetricList(
 		shard,
@@ -722,9 +897,10 @@ func newTimedMetricList(
 		listScope                  = iOpts.MetricsScope().Tagged(map[string]string{"list-type": "timed"})
 	)
 	// Timed metrics that have been kept for longer than the maximum buffer
-	// will be flushed.
+	// will be flushed. The buffer is looked up on every flush since it may be
+	// tuned to the observed lateness of timed metrics.
 	targetNanosFn := func(nowNanos int64) int64 {
-		return nowNanos - timedAggregationBufferPast.Nanoseconds()
+		return nowNanos - fn(resolution).Nanoseconds()
 	}
 	l, err := newBaseMetricList(
 		shard,
@@ -823,17 +999,22 @@ type metricLists struct {
 	shard uint32
 	opts  Options
 
-	closed          bool
-	lists           map[metricListID]metricList
-	newMetricListFn newMetricListFn
+	closed           bool
+	lists            map[metricListID]metricList
+	newMetricListFn  newMetricListFn
+	annotationBudget *annotationBudget
 }
 
 func newMetricLists(shard uint32, opts Options) *metricLists {
+	scope := opts.InstrumentOptions().MetricsScope().SubScope("annotations").Tagged(
+		map[string]string{"shard": strconv.Itoa(int(shard))},
+	)
 	return &metricLists{
-		shard:           shard,
-		opts:            opts,
-		lists:           make(map[metricListID]metricList),
-		newMetricListFn: newMetricList,
+		shard:            shard,
+		opts:             opts,
+		lists:            make(map[metricListID]metricList),
+		newMetricListFn:  newMetricList,
+		annotationBudget: newAnnotationBudget(opts.MaxAnnotationBytesPerShard(), scope),
 	}
 }
 
@@ -884,6 +1065,8 @@ func (l *metricLists) Tick() listsTickResult {
 	l.RLock()
 	defer l.RUnlock()
 
+	l.annotationBudget.Report()
+
 	res := listsTickResult{
 		standard:  make(map[time.Duration]int, len(l.lists)),
 		forwarded: make(map[time.Duration]int, len(l.lists)),
@@ -904,6 +1087,18 @@ func (l *metricLists) Tick() listsTickResult {
 	return res
 }
 
+// WindowDigests returns the digests of the windows flushed by the lists with
+// timestamps in [startNanos, endNanos).
+func (l *metricLists) WindowDigests(startNanos, endNanos int64) []WindowDigest {
+	merged := make(map[windowDigestKey]windowDigest)
+	l.RLock()
+	for _, list := range l.lists {
+		list.MergeWindowDigests(startNanos, endNanos, merged)
+	}
+	l.RUnlock()
+	return windowDigestsFromMap(merged)
+}
+
 // Close closes the metric lists.
 func (l *metricLists) Close() {
 	l.Lock()
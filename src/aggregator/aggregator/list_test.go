@@ -23,6 +23,7 @@ package aggregator
 import (
 	"errors"
 	"math"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -39,7 +40,9 @@ import (
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/metrics/pipeline"
 	"github.com/m3db/m3/src/metrics/pipeline/applied"
+	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -241,6 +244,84 @@ func TestBaseMetricListFlushBeforeStale(t *testing.T) {
 	require.Equal(t, int64(1234), l.LastFlushedNanos())
 }
 
+func TestBaseMetricListConsumeLocalMetricDowngraded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var flushed []aggregated.ChunkedMetricWithStoragePolicy
+	w := writer.NewMockWriter(ctrl)
+	w.EXPECT().Write(gomock.Any()).DoAndReturn(func(mp aggregated.ChunkedMetricWithStoragePolicy) error {
+		flushed = append(flushed, mp)
+		return nil
+	}).AnyTimes()
+	handler := handler.NewMockHandler(ctrl)
+	handler.EXPECT().NewWriter(gomock.Any()).Return(w, nil).AnyTimes()
+
+	downgrader, err := NewResolutionDowngrader([]ResolutionDowngradePolicy{
+		{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+	}, 0)
+	require.NoError(t, err)
+	opts := testOptions(ctrl).
+		SetFlushHandler(handler).
+		SetResolutionDowngrader(downgrader)
+	l, err := newBaseMetricList(testShard, 10*time.Second, nil, nil, nil, opts)
+	require.NoError(t, err)
+
+	otherStoragePolicy := policy.NewStoragePolicy(time.Minute, xtime.Second, 48*time.Hour)
+	consume := func(metricID string, timeNanos int64, value float64, sp policy.StoragePolicy) {
+		l.consumeLocalMetric(nil, id.RawID(metricID), nil, timeNanos, value, nil, nil, aggregation.Sum, sp)
+	}
+
+	// Metrics are emitted as is while the mode is disabled.
+	consume("foo", int64(10*time.Second), 1, testStoragePolicy)
+	require.Len(t, flushed, 1)
+	require.Equal(t, time.Duration(0), flushed[0].DowngradedResolution)
+	flushed = flushed[:0]
+
+	downgrader.Enable(ResolutionDowngradeTriggerManual)
+	for i := 2; i <= 7; i++ {
+		consume("foo", int64(time.Duration(i)*10*time.Second), float64(i), testStoragePolicy)
+		consume("bar", int64(time.Duration(i)*10*time.Second), float64(i), otherStoragePolicy)
+	}
+	// Storage policies without a downgrade are not re-bucketed.
+	require.Len(t, flushed, 7)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, []byte("bar"), flushed[i].Data)
+	}
+	// The window closed by the datapoint at 60s is emitted with the sum of its values.
+	require.Equal(t, []byte("foo"), flushed[4].Data)
+	require.Equal(t, int64(time.Minute), flushed[4].TimeNanos)
+	require.Equal(t, float64(2+3+4+5+6), flushed[4].Value)
+	require.Equal(t, testStoragePolicy, flushed[4].StoragePolicy)
+	require.Equal(t, time.Minute, flushed[4].DowngradedResolution)
+	flushed = flushed[:0]
+
+	// The window still open is emitted once no more datapoints can fall into it.
+	l.flushDowngradeBuckets(int64(90*time.Second), consumeType)
+	require.Len(t, flushed, 0)
+	l.flushDowngradeBuckets(int64(2*time.Minute), consumeType)
+	require.Len(t, flushed, 1)
+	require.Equal(t, int64(2*time.Minute), flushed[0].TimeNanos)
+	require.Equal(t, float64(7), flushed[0].Value)
+	require.Equal(t, time.Minute, flushed[0].DowngradedResolution)
+	flushed = flushed[:0]
+
+	// Windows are dropped when datapoints are discarded.
+	consume("foo", int64(130*time.Second), 13, testStoragePolicy)
+	l.flushDowngradeBuckets(int64(3*time.Minute), discardType)
+	require.Len(t, flushed, 0)
+	require.Len(t, l.downgradeBuckets, 0)
+
+	// Series beyond the max open windows are emitted at their original resolution.
+	for i := 0; i < DefaultResolutionDowngradeMaxOpenWindows; i++ {
+		l.downgradeBuckets[downgradeKey{id: strconv.Itoa(i)}] = &downgradeBucket{}
+	}
+	consume("foo", int64(190*time.Second), 19, testStoragePolicy)
+	require.Len(t, flushed, 1)
+	require.Equal(t, int64(190*time.Second), flushed[0].TimeNanos)
+	require.Equal(t, time.Duration(0), flushed[0].DowngradedResolution)
+}
+
 func TestStandardMetricListID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -618,8 +699,8 @@ func TestTimedMetricListFlushConsumingAndCollectingTimedMetrics(t *testing.T) {
 	}
 
 	for _, ep := range elemPairs {
-		require.NoError(t, ep.elem.AddValue(time.Unix(0, ep.metric.TimeNanos), ep.metric.Value, nil))
-		require.NoError(t, ep.elem.AddValue(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Value, nil))
+		require.NoError(t, ep.elem.AddValue(time.Unix(0, ep.metric.TimeNanos), ep.metric.Value, nil, nil))
+		require.NoError(t, ep.elem.AddValue(time.Unix(0, ep.metric.TimeNanos).Add(l.resolution), ep.metric.Value, nil, nil))
 		_, err := l.PushBack(ep.elem)
 		require.NoError(t, err)
 	}
@@ -677,7 +758,8 @@ func TestTimedMetricListFlushConsumingAndCollectingTimedMetrics(t *testing.T) {
 					TimeNanos: alignedStart,
 					Value:     ep.metric.Value,
 				},
-				StoragePolicy: testStoragePolicy,
+				StoragePolicy:   testStoragePolicy,
+				AggregationType: testDefaultAggregationType(ep.metric.Type),
 			})
 		}
 
@@ -1157,7 +1239,8 @@ func TestForwardedMetricListLastStepLocalFlush(t *testing.T) {
 					TimeNanos: alignedStart,
 					Value:     ep.metric.Values[0],
 				},
-				StoragePolicy: testStoragePolicy,
+				StoragePolicy:   testStoragePolicy,
+				AggregationType: testDefaultAggregationType(ep.metric.Type),
 			})
 		}
 
@@ -1354,3 +1437,12 @@ type flushBeforeResult struct {
 	beforeNanos int64
 	flushType   flushType
 }
+
+// testDefaultAggregationType returns the aggregation type of the metrics flushed
+// for a metric type aggregated with the default aggregation types.
+func testDefaultAggregationType(metricType metric.Type) aggregation.Type {
+	if metricType == metric.CounterType {
+		return aggregation.Sum
+	}
+	return aggregation.Last
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/policy"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const defaultLoadSheddingLagRatio = 1.0
+
+var (
+	errWriteShed = errors.New("write shed because the aggregator ticks are lagging")
+
+	errInvalidLoadSheddingLaggingTicks = errors.New("load shedding lagging ticks must be positive")
+	errInvalidLoadSheddingLagRatio     = errors.New("load shedding lag ratio must not be negative")
+	errNoLoadSheddingTraffic           = errors.New(
+		"load shedding requires at least one metric type or storage policy to shed")
+)
+
+// LoadSheddingOptions configures shedding the lowest priority traffic when the
+// aggregator falls behind on expiring entries. A tick lags when it takes longer
+// than the lag ratio times the entry check interval. Once the configured number
+// of consecutive ticks lag, writes of the shed metric types and storage policies
+// are rejected until as many consecutive ticks complete in time again.
+type LoadSheddingOptions struct {
+	// LagRatio is the ratio of the time spent ticking, excluding the sleeps
+	// spreading the tick over the entry check interval, to the entry check
+	// interval above which a tick lags, it defaults to one if zero.
+	LagRatio float64
+	// LaggingTicks is the number of consecutive lagging ticks before shedding
+	// starts, and of consecutive ticks in time before shedding stops.
+	LaggingTicks int
+	// MetricTypes are the types of the metrics shed.
+	MetricTypes []metric.Type
+	// StoragePolicies are the storage policies of the metrics shed, metrics
+	// with staged metadatas are only shed if all of their storage policies are.
+	StoragePolicies []policy.StoragePolicy
+}
+
+// Validate validates the options.
+func (o LoadSheddingOptions) Validate() error {
+	if o.LaggingTicks <= 0 {
+		return errInvalidLoadSheddingLaggingTicks
+	}
+	if o.LagRatio < 0 {
+		return errInvalidLoadSheddingLagRatio
+	}
+	if len(o.MetricTypes) == 0 && len(o.StoragePolicies) == 0 {
+		return errNoLoadSheddingTraffic
+	}
+	return nil
+}
+
+type loadShedderMetrics struct {
+	shedding  tally.Gauge
+	tickLag   tally.Gauge
+	triggered tally.Counter
+	recovered tally.Counter
+}
+
+func newLoadShedderMetrics(scope tally.Scope) loadShedderMetrics {
+	return loadShedderMetrics{
+		shedding:  scope.Gauge("shedding"),
+		tickLag:   scope.Gauge("tick-lag-ratio"),
+		triggered: scope.Counter("triggered"),
+		recovered: scope.Counter("recovered"),
+	}
+}
+
+// loadShedder rejects writes of the lowest priority traffic while the ticks of
+// the aggregator are persistently lagging.
+type loadShedder struct {
+	lagRatio        float64
+	laggingTicks    int
+	metricTypes     map[metric.Type]struct{}
+	storagePolicies map[policy.StoragePolicy]struct{}
+	logger          *zap.Logger
+	metrics         loadShedderMetrics
+
+	shedding atomic.Bool
+	// consecutiveTicks is the number of consecutive ticks that would flip the
+	// shedding state, it is only accessed by the tick goroutine.
+	consecutiveTicks int
+}
+
+func newLoadShedder(
+	opts LoadSheddingOptions,
+	logger *zap.Logger,
+	scope tally.Scope,
+) *loadShedder {
+	lagRatio := opts.LagRatio
+	if lagRatio == 0 {
+		lagRatio = defaultLoadSheddingLagRatio
+	}
+	metricTypes := make(map[metric.Type]struct{}, len(opts.MetricTypes))
+	for _, t := range opts.MetricTypes {
+		metricTypes[t] = struct{}{}
+	}
+	storagePolicies := make(map[policy.StoragePolicy]struct{}, len(opts.StoragePolicies))
+	for _, sp := range opts.StoragePolicies {
+		storagePolicies[sp] = struct{}{}
+	}
+	return &loadShedder{
+		lagRatio:        lagRatio,
+		laggingTicks:    opts.LaggingTicks,
+		metricTypes:     metricTypes,
+		storagePolicies: storagePolicies,
+		logger:          logger,
+		metrics:         newLoadShedderMetrics(scope),
+	}
+}
+
+// UpdateTick records the time spent ticking, excluding the sleeps spreading the
+// tick over the entry check interval, against the entry check interval and
+// starts or stops shedding once enough consecutive ticks lag or complete in
+// time. It is not thread-safe.
+func (s *loadShedder) UpdateTick(tickDuration, checkInterval time.Duration) {
+	if checkInterval <= 0 {
+		return
+	}
+	ratio := float64(tickDuration) / float64(checkInterval)
+	s.metrics.tickLag.Update(ratio)
+
+	lagging := ratio > s.lagRatio
+	shedding := s.shedding.Load()
+	if lagging == shedding {
+		s.consecutiveTicks = 0
+	} else {
+		s.consecutiveTicks++
+	}
+
+	if s.consecutiveTicks >= s.laggingTicks {
+		s.consecutiveTicks = 0
+		s.shedding.Store(lagging)
+		if lagging {
+			s.metrics.triggered.Inc(1)
+			s.logger.Warn("aggregator ticks are lagging, shedding low priority traffic",
+				zap.Duration("tickDuration", tickDuration),
+				zap.Duration("checkInterval", checkInterval))
+		} else {
+			s.metrics.recovered.Inc(1)
+			s.logger.Info("aggregator ticks caught up, no longer shedding traffic",
+				zap.Duration("tickDuration", tickDuration),
+				zap.Duration("checkInterval", checkInterval))
+		}
+	}
+
+	if s.shedding.Load() {
+		s.metrics.shedding.Update(1)
+	} else {
+		s.metrics.shedding.Update(0)
+	}
+}
+
+// ShedStaged returns whether a metric with the given type and staged metadatas
+// should be shed.
+func (s *loadShedder) ShedStaged(metricType metric.Type, metas metadata.StagedMetadatas) bool {
+	if !s.shedding.Load() {
+		return false
+	}
+	if _, ok := s.metricTypes[metricType]; ok {
+		return true
+	}
+	if len(s.storagePolicies) == 0 || len(metas) == 0 {
+		return false
+	}
+	numStoragePolicies := 0
+	for _, pipeline := range metas[0].Pipelines {
+		for _, sp := range pipeline.StoragePolicies {
+			if _, ok := s.storagePolicies[sp]; !ok {
+				return false
+			}
+			numStoragePolicies++
+		}
+	}
+	return numStoragePolicies > 0
+}
+
+// Shed returns whether a metric with the given type and storage policy should
+// be shed.
+func (s *loadShedder) Shed(metricType metric.Type, sp policy.StoragePolicy) bool {
+	if !s.shedding.Load() {
+		return false
+	}
+	if _, ok := s.metricTypes[metricType]; ok {
+		return true
+	}
+	_, ok := s.storagePolicies[sp]
+	return ok
+}
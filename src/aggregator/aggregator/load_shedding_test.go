@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/policy"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+func TestLoadShedderUpdateTick(t *testing.T) {
+	opts := LoadSheddingOptions{
+		LaggingTicks: 2,
+		MetricTypes:  []metric.Type{metric.TimerType},
+	}
+	require.NoError(t, opts.Validate())
+	s := newLoadShedder(opts, zap.NewNop(), tally.NoopScope)
+
+	for i, input := range []struct {
+		tickDuration time.Duration
+		shedding     bool
+	}{
+		{tickDuration: 2 * time.Second, shedding: false},
+		// A tick in time resets the consecutive lagging ticks.
+		{tickDuration: time.Second, shedding: false},
+		{tickDuration: 2 * time.Second, shedding: false},
+		{tickDuration: 2 * time.Second, shedding: true},
+		{tickDuration: 500 * time.Millisecond, shedding: true},
+		// A lagging tick resets the consecutive ticks in time.
+		{tickDuration: 3 * time.Second, shedding: true},
+		{tickDuration: 500 * time.Millisecond, shedding: true},
+		{tickDuration: 500 * time.Millisecond, shedding: false},
+	} {
+		s.UpdateTick(input.tickDuration, time.Second)
+		require.Equal(t, input.shedding, s.shedding.Load(), i)
+	}
+}
+
+func TestLoadShedderShed(t *testing.T) {
+	var (
+		shedPolicy = policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*24*time.Hour)
+		keptPolicy = policy.NewStoragePolicy(time.Minute, xtime.Minute, 40*24*time.Hour)
+		opts       = LoadSheddingOptions{
+			LagRatio:        1.5,
+			LaggingTicks:    1,
+			MetricTypes:     []metric.Type{metric.TimerType},
+			StoragePolicies: []policy.StoragePolicy{shedPolicy},
+		}
+		stagedMetadatas = func(sps ...policy.StoragePolicy) metadata.StagedMetadatas {
+			return metadata.StagedMetadatas{{
+				Metadata: metadata.Metadata{
+					Pipelines: []metadata.PipelineMetadata{{StoragePolicies: sps}},
+				},
+			}}
+		}
+	)
+	s := newLoadShedder(opts, zap.NewNop(), tally.NoopScope)
+
+	// Nothing is shed until the ticks lag.
+	require.False(t, s.Shed(metric.TimerType, keptPolicy))
+	require.False(t, s.ShedStaged(metric.CounterType, stagedMetadatas(shedPolicy)))
+
+	s.UpdateTick(time.Second, time.Second)
+	require.False(t, s.Shed(metric.TimerType, keptPolicy))
+	s.UpdateTick(2*time.Second, time.Second)
+
+	require.True(t, s.Shed(metric.TimerType, keptPolicy))
+	require.True(t, s.Shed(metric.CounterType, shedPolicy))
+	require.False(t, s.Shed(metric.CounterType, keptPolicy))
+
+	require.True(t, s.ShedStaged(metric.TimerType, stagedMetadatas(keptPolicy)))
+	require.True(t, s.ShedStaged(metric.CounterType, stagedMetadatas(shedPolicy)))
+	require.False(t, s.ShedStaged(metric.CounterType, stagedMetadatas(shedPolicy, keptPolicy)))
+	require.False(t, s.ShedStaged(metric.CounterType, stagedMetadatas()))
+}
+
+func TestLoadSheddingOptionsValidate(t *testing.T) {
+	require.Equal(t, errInvalidLoadSheddingLaggingTicks, LoadSheddingOptions{
+		MetricTypes: []metric.Type{metric.TimerType},
+	}.Validate())
+	require.Equal(t, errInvalidLoadSheddingLagRatio, LoadSheddingOptions{
+		LagRatio:     -1,
+		LaggingTicks: 1,
+		MetricTypes:  []metric.Type{metric.TimerType},
+	}.Validate())
+	require.Equal(t, errNoLoadSheddingTraffic, LoadSheddingOptions{
+		LaggingTicks: 1,
+	}.Validate())
+}
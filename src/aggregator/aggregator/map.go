@@ -31,7 +31,9 @@ import (
 	"github.com/m3db/m3/src/aggregator/rate"
 	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3/src/x/clock"
 	xresource "github.com/m3db/m3/src/x/resource"
@@ -64,6 +66,19 @@ const (
 	timedMetric
 )
 
+func (c metricCategory) String() string {
+	switch c {
+	case untimedMetric:
+		return "untimed"
+	case forwardedMetric:
+		return "forwarded"
+	case timedMetric:
+		return "timed"
+	default:
+		return "unknown"
+	}
+}
+
 type entryKey struct {
 	idHash         hash.Hash128
 	metricType     metricType
@@ -73,6 +88,9 @@ type entryKey struct {
 type hashedEntry struct {
 	entry *Entry
 	key   entryKey
+
+	// The rollup rule the entry counts towards the cardinality limit of, if any.
+	rollupRule string
 }
 
 type metricMapMetrics struct {
@@ -110,6 +128,7 @@ type metricMap struct {
 	entryListDelLock  sync.Mutex // Must be held when deleting elements from the entry list
 	firstInsertAt     time.Time
 	rateLimiter       *rate.Limiter
+	rollupLimiter     *RollupCardinalityLimiter
 	runtimeOpts       runtime.Options
 	runtimeOptsCloser xresource.SimpleCloser
 	sleepFn           sleepFn
@@ -120,17 +139,18 @@ func newMetricMap(shard uint32, opts Options) *metricMap {
 	metricLists := newMetricLists(shard, opts)
 	scope := opts.InstrumentOptions().MetricsScope().SubScope("map")
 	m := &metricMap{
-		rateLimiter:  rate.NewLimiter(0),
-		shard:        shard,
-		opts:         opts,
-		nowFn:        opts.ClockOptions().NowFn(),
-		entryPool:    opts.EntryPool(),
-		batchPercent: opts.EntryCheckBatchPercent(),
-		metricLists:  metricLists,
-		entries:      make(map[entryKey]*list.Element),
-		entryList:    list.New(),
-		sleepFn:      time.Sleep,
-		metrics:      newMetricMapMetrics(scope),
+		rateLimiter:   rate.NewLimiter(0),
+		rollupLimiter: opts.RollupCardinalityLimiter(),
+		shard:         shard,
+		opts:          opts,
+		nowFn:         opts.ClockOptions().NowFn(),
+		entryPool:     opts.EntryPool(),
+		batchPercent:  opts.EntryCheckBatchPercent(),
+		metricLists:   metricLists,
+		entries:       make(map[entryKey]*list.Element),
+		entryList:     list.New(),
+		sleepFn:       time.Sleep,
+		metrics:       newMetricMapMetrics(scope),
 	}
 
 	runtimeOptsManager := opts.RuntimeOptionsManager()
@@ -155,7 +175,7 @@ func (m *metricMap) AddUntimed(
 		metricType:     metricType(metric.Type),
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, err := m.findOrCreate(key, nil)
 	if err != nil {
 		return err
 	}
@@ -164,6 +184,27 @@ func (m *metricMap) AddUntimed(
 	return err
 }
 
+// ReplayUntimed adds an untimed metric replayed from the write-ahead log at the
+// time it was journaled.
+func (m *metricMap) ReplayUntimed(
+	metric unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
+	journaledAt time.Time,
+) error {
+	key := entryKey{
+		metricCategory: untimedMetric,
+		metricType:     metricType(metric.Type),
+		idHash:         hash.Murmur3Hash128(metric.ID),
+	}
+	entry, err := m.findOrCreate(key, nil)
+	if err != nil {
+		return err
+	}
+	err = entry.ReplayUntimed(metric, metadatas, journaledAt)
+	entry.DecWriter()
+	return err
+}
+
 func (m *metricMap) AddTimed(
 	metric aggregated.Metric,
 	metadata metadata.TimedMetadata,
@@ -173,7 +214,7 @@ func (m *metricMap) AddTimed(
 		metricType:     metricType(metric.Type),
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, err := m.findOrCreate(key, nil)
 	if err != nil {
 		return err
 	}
@@ -191,7 +232,7 @@ func (m *metricMap) AddTimedWithStagedMetadatas(
 		metricType:     metricType(metric.Type),
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, err := m.findOrCreate(key, nil)
 	if err != nil {
 		return err
 	}
@@ -209,7 +250,7 @@ func (m *metricMap) AddForwarded(
 		metricType:     metricType(metric.Type),
 		idHash:         hash.Murmur3Hash128(metric.ID),
 	}
-	entry, err := m.findOrCreate(key)
+	entry, err := m.findOrCreate(key, metric.ID)
 	if err != nil {
 		return err
 	}
@@ -218,6 +259,74 @@ func (m *metricMap) AddForwarded(
 	return err
 }
 
+// InProgressValues returns the values of all aggregations for the given metric ID
+// that have not expired yet, across all metric categories and types.
+func (m *metricMap) InProgressValues(metricID id.RawID) []InProgressDatapoint {
+	idHash := hash.Murmur3Hash128(metricID)
+
+	m.RLock()
+	defer m.RUnlock()
+	if m.closed {
+		return nil
+	}
+
+	var result []InProgressDatapoint
+	for _, category := range []metricCategory{untimedMetric, timedMetric, forwardedMetric} {
+		// Forwarded metrics are flushed at the start of their aggregation windows
+		// while the others are flushed at the end.
+		timestampNanosFn := standardMetricTimestampNanos
+		if category == forwardedMetric {
+			timestampNanosFn = forwardedMetricTimestampNanos
+		}
+		for _, mtype := range []metric.Type{
+			metric.CounterType, metric.TimerType, metric.GaugeType, metric.HistogramType,
+		} {
+			key := entryKey{
+				metricCategory: category,
+				metricType:     metricType(mtype),
+				idHash:         idHash,
+			}
+			entry, found := m.lookupEntryWithLock(key)
+			if !found {
+				continue
+			}
+			result = append(result, entry.InProgressValues(metricID, timestampNanosFn)...)
+		}
+	}
+	return result
+}
+
+// DebugElements describes the aggregations of the given metric ID across all
+// metric categories and types.
+func (m *metricMap) DebugElements(metricID id.RawID) []MetricDebugElement {
+	idHash := hash.Murmur3Hash128(metricID)
+
+	m.RLock()
+	defer m.RUnlock()
+	if m.closed {
+		return nil
+	}
+
+	var result []MetricDebugElement
+	for _, category := range []metricCategory{untimedMetric, timedMetric, forwardedMetric} {
+		for _, mtype := range []metric.Type{
+			metric.CounterType, metric.TimerType, metric.GaugeType, metric.HistogramType,
+		} {
+			key := entryKey{
+				metricCategory: category,
+				metricType:     metricType(mtype),
+				idHash:         idHash,
+			}
+			entry, found := m.lookupEntryWithLock(key)
+			if !found {
+				continue
+			}
+			result = append(result, entry.DebugElements(metricID, category, mtype)...)
+		}
+	}
+	return result
+}
+
 func (m *metricMap) Tick(target time.Duration) tickResult {
 	mapTickRes := m.tick(target)
 	listsTickRes := m.metricLists.Tick()
@@ -227,6 +336,10 @@ func (m *metricMap) Tick(target time.Duration) tickResult {
 	return mapTickRes
 }
 
+func (m *metricMap) WindowDigests(startNanos, endNanos int64) []WindowDigest {
+	return m.metricLists.WindowDigests(startNanos, endNanos)
+}
+
 func (m *metricMap) SetRuntimeOptions(opts runtime.Options) {
 	m.Lock()
 	m.runtimeOpts = opts
@@ -256,10 +369,18 @@ func (m *metricMap) Close() {
 	}
 	m.runtimeOptsCloser.Close()
 	m.metricLists.Close()
+	// Release the rollup series of the shard so they no longer count towards
+	// the limits shared by the shards of the aggregator.
+	for elem := m.entryList.Front(); elem != nil; elem = elem.Next() {
+		m.rollupLimiter.Remove(elem.Value.(hashedEntry).rollupRule)
+	}
 	m.closed = true
 }
 
-func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
+// findOrCreate finds the entry for the given key, or creates one if it does not
+// exist. New entries for a non-nil rollup ID are subject to the cardinality limit
+// of the rollup rule producing them.
+func (m *metricMap) findOrCreate(key entryKey, rollupID id.RawID) (*Entry, error) {
 	m.RLock()
 	if m.closed {
 		m.RUnlock()
@@ -296,11 +417,22 @@ func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
 		m.Unlock()
 		return nil, err
 	}
+	var rollupRule string
+	if rollupID != nil {
+		rule, err := m.rollupLimiter.Add(rollupID)
+		if err != nil {
+			m.Unlock()
+			m.metrics.droppedNewMetrics.Inc(1)
+			return nil, err
+		}
+		rollupRule = rule
+	}
 	entry = m.entryPool.Get()
 	entry.ResetSetData(m.metricLists, m.runtimeOpts, m.opts)
 	m.entries[key] = m.entryList.PushBack(hashedEntry{
-		key:   key,
-		entry: entry,
+		key:        key,
+		entry:      entry,
+		rollupRule: rollupRule,
 	})
 	entry.IncWriter()
 	m.Unlock()
@@ -340,6 +472,7 @@ func (m *metricMap) tick(target time.Duration) tickResult {
 		numTimedActive       int
 		numTimedExpired      int
 		entryIdx             int
+		paced                time.Duration
 	)
 	m.forEachEntry(func(entry hashedEntry) {
 		now := m.nowFn()
@@ -347,6 +480,7 @@ func (m *metricMap) tick(target time.Duration) tickResult {
 			targetDeadline := start.Add(time.Duration(entryIdx) * perEntrySoftDeadline)
 			if now.Before(targetDeadline) {
 				m.sleepFn(targetDeadline.Sub(now))
+				paced += targetDeadline.Sub(now)
 			}
 		}
 		switch entry.key.metricCategory {
@@ -394,6 +528,7 @@ func (m *metricMap) tick(target time.Duration) tickResult {
 			activeEntries:  numTimedActive - numTimedExpired,
 			expiredEntries: numTimedExpired,
 		},
+		paced: paced,
 	}
 }
 
@@ -418,6 +553,7 @@ func (m *metricMap) purgeExpired(
 				numTimedExpired++
 			}
 			elem := m.entries[key]
+			m.rollupLimiter.Remove(elem.Value.(hashedEntry).rollupRule)
 			delete(m.entries, key)
 			elem.Value = nil
 			m.entryList.Remove(elem)
@@ -39,6 +39,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -463,6 +464,40 @@ func TestMetricMapAddForwardedNoRateLimit(t *testing.T) {
 	require.False(t, e1 == e4)
 }
 
+func TestMetricMapAddForwardedRollupCardinalityLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limiter, err := NewRollupCardinalityLimiter(RollupCardinalityLimitOptions{
+		DefaultLimit: 1,
+		Mode:         RollupCardinalityLimitReject,
+	}, tally.NoopScope)
+	require.NoError(t, err)
+	opts := testOptions(ctrl).SetRollupCardinalityLimiter(limiter)
+	m := newMetricMap(testShard, opts)
+
+	am := aggregated.ForwardedMetric{
+		Type:      metric.CounterType,
+		ID:        []byte("m3+requests+service=a"),
+		TimeNanos: 12345,
+		Values:    []float64{76109},
+	}
+	require.NoError(t, m.AddForwarded(am, testForwardMetadata))
+	require.Equal(t, 1, limiter.NumSeries("requests"))
+
+	// Writing to an existing series is not limited.
+	require.NoError(t, m.AddForwarded(am, testForwardMetadata))
+
+	// New series of the rollup beyond the limit are rejected.
+	am.ID = []byte("m3+requests+service=b")
+	require.Equal(t, errRollupCardinalityLimitExceeded, m.AddForwarded(am, testForwardMetadata))
+	require.Equal(t, 1, len(m.entries))
+
+	// Closing the map releases its series.
+	m.Close()
+	require.Equal(t, 0, limiter.NumSeries("requests"))
+}
+
 func TestMetricMapDeleteExpired(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -513,12 +548,17 @@ func TestMetricMapDeleteExpired(t *testing.T) {
 	}
 
 	// Delete expired entries.
-	m.tick(opts.EntryCheckInterval())
+	res := m.tick(opts.EntryCheckInterval())
 
 	// Assert there should be only half of the entries left.
 	require.Equal(t, numEntries/2, len(m.entries))
 	require.Equal(t, numEntries/2, m.entryList.Len())
 	require.Equal(t, len(sleepIntervals), numEntries/defaultSoftDeadlineCheckEvery)
+	var paced time.Duration
+	for _, d := range sleepIntervals {
+		paced += d
+	}
+	require.Equal(t, paced, res.paced)
 	for k, v := range m.entries {
 		e := v.Value.(hashedEntry)
 		require.Equal(t, k, e.key)
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var errMemoryWatchdogWatermarks = errors.New(
+	"memory watchdog low watermark must be smaller than the high watermark")
+
+// MemoryWatchdogOptions configures the memory watchdog, which enables the
+// resolution downgrade mode when the heap in use crosses the high watermark
+// and disables it once the heap in use drops back below the low watermark.
+// The watchdog is disabled if the check interval is zero.
+type MemoryWatchdogOptions struct {
+	CheckInterval      time.Duration
+	HighWatermarkBytes uint64
+	LowWatermarkBytes  uint64
+}
+
+// Validate validates the memory watchdog options.
+func (o MemoryWatchdogOptions) Validate() error {
+	if o.CheckInterval > 0 && o.LowWatermarkBytes >= o.HighWatermarkBytes {
+		return errMemoryWatchdogWatermarks
+	}
+	return nil
+}
+
+type heapInUseBytesFn func() uint64
+
+func readHeapInUseBytes() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+type memoryWatchdogMetrics struct {
+	heapInUse tally.Gauge
+	triggered tally.Counter
+	recovered tally.Counter
+}
+
+func newMemoryWatchdogMetrics(scope tally.Scope) memoryWatchdogMetrics {
+	return memoryWatchdogMetrics{
+		heapInUse: scope.Gauge("heap-in-use-bytes"),
+		triggered: scope.Counter("triggered"),
+		recovered: scope.Counter("recovered"),
+	}
+}
+
+type memoryWatchdog struct {
+	opts           MemoryWatchdogOptions
+	downgrader     *ResolutionDowngrader
+	heapInUseBytes heapInUseBytesFn
+	logger         *zap.Logger
+	metrics        memoryWatchdogMetrics
+
+	triggered bool
+}
+
+func newMemoryWatchdog(
+	opts MemoryWatchdogOptions,
+	downgrader *ResolutionDowngrader,
+	heapInUseBytes heapInUseBytesFn,
+	logger *zap.Logger,
+	scope tally.Scope,
+) *memoryWatchdog {
+	return &memoryWatchdog{
+		opts:           opts,
+		downgrader:     downgrader,
+		heapInUseBytes: heapInUseBytes,
+		logger:         logger,
+		metrics:        newMemoryWatchdogMetrics(scope),
+	}
+}
+
+// Check compares the heap in use against the watermarks and toggles the
+// resolution downgrade mode accordingly. It is not thread-safe.
+func (w *memoryWatchdog) Check() {
+	heapInUse := w.heapInUseBytes()
+	w.metrics.heapInUse.Update(float64(heapInUse))
+	switch {
+	case !w.triggered && heapInUse >= w.opts.HighWatermarkBytes:
+		w.triggered = true
+		w.downgrader.Enable(ResolutionDowngradeTriggerMemoryWatchdog)
+		w.metrics.triggered.Inc(1)
+		w.logger.Warn("heap in use above high watermark, enabling resolution downgrade",
+			zap.Uint64("heapInUseBytes", heapInUse),
+			zap.Uint64("highWatermarkBytes", w.opts.HighWatermarkBytes))
+	case w.triggered && heapInUse < w.opts.LowWatermarkBytes:
+		w.triggered = false
+		w.downgrader.Disable(ResolutionDowngradeTriggerMemoryWatchdog)
+		w.metrics.recovered.Inc(1)
+		w.logger.Info("heap in use below low watermark, disabling resolution downgrade",
+			zap.Uint64("heapInUseBytes", heapInUse),
+			zap.Uint64("lowWatermarkBytes", w.opts.LowWatermarkBytes))
+	}
+}
@@ -25,12 +25,15 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/aggregator/wal"
 	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
@@ -42,12 +45,16 @@ var (
 	defaultCounterPrefix              = []byte("counts.")
 	defaultTimerPrefix                = []byte("timers.")
 	defaultGaugePrefix                = []byte("gauges.")
+	defaultHistogramPrefix            = []byte("histograms.")
 	defaultEntryTTL                   = time.Hour
 	defaultEntryCheckInterval         = time.Hour
 	defaultEntryCheckBatchPercent     = 0.01
 	defaultMaxTimerBatchSizePerWrite  = 0
 	defaultMaxNumCachedSourceSets     = 2
 	defaultDiscardNaNAggregatedValues = true
+	defaultContributorsTag            = []byte("host")
+	defaultContributorsSuffix         = []byte("_contributors")
+	defaultHistogramBucketSuffix      = []byte(".bucket.")
 	defaultResignTimeout              = 5 * time.Minute
 	defaultDefaultStoragePolicies     = []policy.StoragePolicy{
 		policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*24*time.Hour),
@@ -55,6 +62,9 @@ var (
 	}
 	defaultVerboseErrors = false
 
+	defaultHeartbeatStoragePolicy    = policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*24*time.Hour)
+	defaultHeartbeatMetricNamePrefix = "m3aggregator_heartbeat"
+
 	defaultTimedMetricBuffer = time.Minute
 
 	// By default writes are buffered for 10 minutes before traffic is cut over to a shard
@@ -109,6 +119,12 @@ type Options interface {
 	// GaugePrefix returns the prefix for gauges.
 	GaugePrefix() []byte
 
+	// SetHistogramPrefix sets the prefix for histograms.
+	SetHistogramPrefix(value []byte) Options
+
+	// HistogramPrefix returns the prefix for histograms.
+	HistogramPrefix() []byte
+
 	// SetTimeLock sets the time lock.
 	SetTimeLock(value *sync.RWMutex) Options
 
@@ -139,6 +155,14 @@ type Options interface {
 	// StreamOptions returns the stream options.
 	StreamOptions() cm.Options
 
+	// SetTimerSketchOptions sets the sketch options used to compute timer
+	// quantiles, if nil timer quantiles are computed using a stream.
+	SetTimerSketchOptions(value ddsketch.Options) Options
+
+	// TimerSketchOptions returns the sketch options used to compute timer
+	// quantiles, if nil timer quantiles are computed using a stream.
+	TimerSketchOptions() ddsketch.Options
+
 	// SetAdminClient sets the administrative client.
 	SetAdminClient(value client.AdminClient) Options
 
@@ -211,7 +235,9 @@ type Options interface {
 	// EntryTTL returns the ttl for expiring stale entries.
 	EntryTTL() time.Duration
 
-	// SetEntryCheckInterval sets the interval for checking expired entries.
+	// SetEntryCheckInterval sets the interval for checking expired entries,
+	// entries are only checked once an interval is set in the runtime options
+	// if the interval is 0.
 	SetEntryCheckInterval(value time.Duration) Options
 
 	// EntryCheckInterval returns the interval for checking expired entries.
@@ -249,6 +275,14 @@ type Options interface {
 	// delay for given metric resolution and number of times the metric has been forwarded.
 	MaxAllowedForwardingDelayFn() MaxAllowedForwardingDelayFn
 
+	// SetMaxNumForwardedTimes sets the maximum number of times a metric may have been
+	// forwarded before it is rejected, with zero meaning no limit.
+	SetMaxNumForwardedTimes(value int) Options
+
+	// MaxNumForwardedTimes returns the maximum number of times a metric may have been
+	// forwarded before it is rejected, with zero meaning no limit.
+	MaxNumForwardedTimes() int
+
 	// SetBufferForPastTimedMetric sets the size of the buffer for timed metrics in the past.
 	SetBufferForPastTimedMetric(value time.Duration) Options
 
@@ -273,12 +307,62 @@ type Options interface {
 	// MaxNumCachedSourceSets returns the maximum number of cached source sets.
 	MaxNumCachedSourceSets() int
 
+	// SetMaxAnnotationBytesPerShard sets the maximum number of annotation bytes
+	// retained by the open aggregations of each shard, annotations that would
+	// exceed it are dropped. Zero means unlimited.
+	SetMaxAnnotationBytesPerShard(value int64) Options
+
+	// MaxAnnotationBytesPerShard returns the maximum number of annotation bytes
+	// retained by the open aggregations of each shard.
+	MaxAnnotationBytesPerShard() int64
+
+	// SetMaxExemplarsPerAggregation sets the maximum number of exemplars retained
+	// by each aggregated value and forwarded alongside it, the most recent ones
+	// being kept. Zero disables exemplar propagation.
+	SetMaxExemplarsPerAggregation(value int) Options
+
+	// MaxExemplarsPerAggregation returns the maximum number of exemplars retained
+	// by each aggregated value and forwarded alongside it.
+	MaxExemplarsPerAggregation() int
+
 	// SetDiscardNaNAggregatedValues determines whether NaN aggregated values are discarded.
 	SetDiscardNaNAggregatedValues(value bool) Options
 
 	// DiscardNaNAggregatedValues determines whether NaN aggregated values are discarded.
 	DiscardNaNAggregatedValues() bool
 
+	// SetEmitContributors sets whether rollup outputs are accompanied by a series
+	// estimating the number of distinct values of the contributors tag of the
+	// series rolled up into each window.
+	SetEmitContributors(value bool) Options
+
+	// EmitContributors returns whether rollup outputs are accompanied by a series
+	// estimating the number of distinct values of the contributors tag of the
+	// series rolled up into each window.
+	EmitContributors() bool
+
+	// SetContributorsTag sets the tag whose distinct values are counted by the
+	// contributors series.
+	SetContributorsTag(value []byte) Options
+
+	// ContributorsTag returns the tag whose distinct values are counted by the
+	// contributors series.
+	ContributorsTag() []byte
+
+	// SetContributorsSuffix sets the suffix of the contributors series.
+	SetContributorsSuffix(value []byte) Options
+
+	// ContributorsSuffix returns the suffix of the contributors series.
+	ContributorsSuffix() []byte
+
+	// SetHistogramBucketSuffix sets the suffix of the histogram bucket series,
+	// which is followed by the upper bound of each bucket.
+	SetHistogramBucketSuffix(value []byte) Options
+
+	// HistogramBucketSuffix returns the suffix of the histogram bucket series,
+	// which is followed by the upper bound of each bucket.
+	HistogramBucketSuffix() []byte
+
 	// SetEntryPool sets the entry pool.
 	SetEntryPool(value EntryPool) Options
 
@@ -303,6 +387,12 @@ type Options interface {
 	// GaugeElemPool returns the gauge element pool.
 	GaugeElemPool() GaugeElemPool
 
+	// SetHistogramElemPool sets the histogram element pool.
+	SetHistogramElemPool(value HistogramElemPool) Options
+
+	// HistogramElemPool returns the histogram element pool.
+	HistogramElemPool() HistogramElemPool
+
 	/// Read-only derived options.
 
 	// FullCounterPrefix returns the full prefix for counters.
@@ -314,6 +404,9 @@ type Options interface {
 	// FullGaugePrefix returns the full prefix for gauges.
 	FullGaugePrefix() []byte
 
+	// FullHistogramPrefix returns the full prefix for histograms.
+	FullHistogramPrefix() []byte
+
 	// SetVerboseErrors returns whether to return verbose errors or not.
 	SetVerboseErrors(value bool) Options
 
@@ -355,6 +448,116 @@ type Options interface {
 
 	// SetTimedForResendEnabledRollupRegexps sets TimedForResendEnabledRollupRegexps.
 	SetTimedForResendEnabledRollupRegexps([]string) Options
+
+	// SetHeartbeatEnabled sets whether the aggregator emits heartbeat series describing
+	// its own health to the flush handler.
+	SetHeartbeatEnabled(value bool) Options
+
+	// HeartbeatEnabled returns whether the aggregator emits heartbeat series describing
+	// its own health to the flush handler.
+	HeartbeatEnabled() bool
+
+	// SetHeartbeatStoragePolicy sets the storage policy of heartbeat series. Heartbeats
+	// are emitted once per resolution of the storage policy.
+	SetHeartbeatStoragePolicy(value policy.StoragePolicy) Options
+
+	// HeartbeatStoragePolicy returns the storage policy of heartbeat series. Heartbeats
+	// are emitted once per resolution of the storage policy.
+	HeartbeatStoragePolicy() policy.StoragePolicy
+
+	// SetHeartbeatMetricNamePrefix sets the prefix of heartbeat metric names.
+	SetHeartbeatMetricNamePrefix(value string) Options
+
+	// HeartbeatMetricNamePrefix returns the prefix of heartbeat metric names.
+	HeartbeatMetricNamePrefix() string
+
+	// SetHeartbeatNewIDFn sets the function used to generate heartbeat metric IDs.
+	SetHeartbeatNewIDFn(value id.NewIDFn) Options
+
+	// HeartbeatNewIDFn returns the function used to generate heartbeat metric IDs.
+	HeartbeatNewIDFn() id.NewIDFn
+
+	// SetClockSkewWatermark sets the watermark the time untimed metrics are aggregated
+	// at is clamped to, nil disables clamping.
+	SetClockSkewWatermark(value *ClockSkewWatermark) Options
+
+	// ClockSkewWatermark returns the watermark the time untimed metrics are aggregated
+	// at is clamped to, nil disables clamping.
+	ClockSkewWatermark() *ClockSkewWatermark
+
+	// SetResolutionDowngrader sets the controller of the emergency resolution
+	// downgrade mode, nil disables the mode.
+	SetResolutionDowngrader(value *ResolutionDowngrader) Options
+
+	// ResolutionDowngrader returns the controller of the emergency resolution
+	// downgrade mode, nil disables the mode.
+	ResolutionDowngrader() *ResolutionDowngrader
+
+	// SetMemoryWatchdogOptions sets the memory watchdog options.
+	SetMemoryWatchdogOptions(value MemoryWatchdogOptions) Options
+
+	// MemoryWatchdogOptions returns the memory watchdog options.
+	MemoryWatchdogOptions() MemoryWatchdogOptions
+
+	// SetWAL sets the write-ahead log the metrics accepted by each shard are
+	// journaled to and replayed from on startup, nil disables journaling.
+	SetWAL(value wal.WAL) Options
+
+	// WAL returns the write-ahead log the metrics accepted by each shard are
+	// journaled to and replayed from on startup, nil disables journaling.
+	WAL() wal.WAL
+
+	// SetAnnotationSizeLimitOptions sets the max size of the annotations of the
+	// metrics ingested and how oversized annotations are handled, nil disables
+	// the limit.
+	SetAnnotationSizeLimitOptions(value *AnnotationSizeLimitOptions) Options
+
+	// AnnotationSizeLimitOptions returns the max size of the annotations of the
+	// metrics ingested and how oversized annotations are handled, nil disables
+	// the limit.
+	AnnotationSizeLimitOptions() *AnnotationSizeLimitOptions
+
+	// SetPromotionReplayOptions sets the options for replaying the recent traffic
+	// of peers before a follower is promoted to leader, nil disables the replay.
+	SetPromotionReplayOptions(value *PromotionReplayOptions) Options
+
+	// PromotionReplayOptions returns the options for replaying the recent traffic
+	// of peers before a follower is promoted to leader, nil disables the replay.
+	PromotionReplayOptions() *PromotionReplayOptions
+
+	// SetVerificationOptions sets the options for comparing the aggregation
+	// windows of the leader with the ones of its followers, nil disables the
+	// verification mode.
+	SetVerificationOptions(value *VerificationOptions) Options
+
+	// VerificationOptions returns the options for comparing the aggregation
+	// windows of the leader with the ones of its followers, nil disables the
+	// verification mode.
+	VerificationOptions() *VerificationOptions
+
+	// SetRollupCardinalityLimiter sets the limiter of the number of distinct series
+	// produced by each rollup rule, nil disables the limits.
+	SetRollupCardinalityLimiter(value *RollupCardinalityLimiter) Options
+
+	// RollupCardinalityLimiter returns the limiter of the number of distinct series
+	// produced by each rollup rule, nil disables the limits.
+	RollupCardinalityLimiter() *RollupCardinalityLimiter
+
+	// SetLoadSheddingOptions sets the options for shedding the lowest priority
+	// traffic while the ticks are lagging, nil disables load shedding.
+	SetLoadSheddingOptions(value *LoadSheddingOptions) Options
+
+	// LoadSheddingOptions returns the options for shedding the lowest priority
+	// traffic while the ticks are lagging, nil disables load shedding.
+	LoadSheddingOptions() *LoadSheddingOptions
+
+	// SetFlushOffsetTuningOptions sets the options for tuning the buffer for
+	// past timed metrics to their observed lateness, nil disables tuning.
+	SetFlushOffsetTuningOptions(value *FlushOffsetTuningOptions) Options
+
+	// FlushOffsetTuningOptions returns the options for tuning the buffer for
+	// past timed metrics to their observed lateness, nil disables tuning.
+	FlushOffsetTuningOptions() *FlushOffsetTuningOptions
 }
 
 type options struct {
@@ -364,10 +567,12 @@ type options struct {
 	counterPrefix                      []byte
 	timerPrefix                        []byte
 	gaugePrefix                        []byte
+	histogramPrefix                    []byte
 	timeLock                           *sync.RWMutex
 	clockOpts                          clock.Options
 	instrumentOpts                     instrument.Options
 	streamOpts                         cm.Options
+	timerSketchOpts                    ddsketch.Options
 	adminClient                        client.AdminClient
 	runtimeOptsManager                 runtime.OptionsManager
 	placementManager                   PlacementManager
@@ -386,27 +591,50 @@ type options struct {
 	electionManager                    ElectionManager
 	resignTimeout                      time.Duration
 	maxAllowedForwardingDelayFn        MaxAllowedForwardingDelayFn
+	maxNumForwardedTimes               int
 	bufferForPastTimedMetric           time.Duration
 	bufferForPastTimedMetricFn         BufferForPastTimedMetricFn
 	bufferForFutureTimedMetric         time.Duration
 	maxNumCachedSourceSets             int
+	maxAnnotationBytesPerShard         int64
+	maxExemplarsPerAggregation         int
 	discardNaNAggregatedValues         bool
+	emitContributors                   bool
+	contributorsTag                    []byte
+	contributorsSuffix                 []byte
+	histogramBucketSuffix              []byte
 	entryPool                          EntryPool
 	counterElemPool                    CounterElemPool
 	timerElemPool                      TimerElemPool
 	gaugeElemPool                      GaugeElemPool
+	histogramElemPool                  HistogramElemPool
 	verboseErrors                      bool
 	addToReset                         bool
 	timedMetricsFlushOffsetEnabled     bool
 	featureFlagBundlesParsed           []FeatureFlagBundleParsed
 	writesIgnoreCutoffCutover          bool
 	timedForResendEnabledRollupRegexps []string
+	heartbeatEnabled                   bool
+	heartbeatStoragePolicy             policy.StoragePolicy
+	heartbeatMetricNamePrefix          string
+	heartbeatNewIDFn                   id.NewIDFn
+	clockSkewWatermark                 *ClockSkewWatermark
+	resolutionDowngrader               *ResolutionDowngrader
+	memoryWatchdogOpts                 MemoryWatchdogOptions
+	wal                                wal.WAL
+	promotionReplayOpts                *PromotionReplayOptions
+	annotationSizeLimitOpts            *AnnotationSizeLimitOptions
+	verificationOpts                   *VerificationOptions
+	rollupCardinalityLimiter           *RollupCardinalityLimiter
+	loadSheddingOpts                   *LoadSheddingOptions
+	flushOffsetTuningOpts              *FlushOffsetTuningOptions
 
 	// Derived options.
-	fullCounterPrefix []byte
-	fullTimerPrefix   []byte
-	fullGaugePrefix   []byte
-	timerQuantiles    []float64
+	fullCounterPrefix   []byte
+	fullTimerPrefix     []byte
+	fullGaugePrefix     []byte
+	fullHistogramPrefix []byte
+	timerQuantiles      []float64
 }
 
 // NewOptions create a new set of options.
@@ -421,6 +649,7 @@ func NewOptions(clockOpts clock.Options) Options {
 		counterPrefix:                    defaultCounterPrefix,
 		timerPrefix:                      defaultTimerPrefix,
 		gaugePrefix:                      defaultGaugePrefix,
+		histogramPrefix:                  defaultHistogramPrefix,
 		timeLock:                         &sync.RWMutex{},
 		clockOpts:                        clockOpts,
 		instrumentOpts:                   instrument.NewOptions(),
@@ -442,7 +671,12 @@ func NewOptions(clockOpts clock.Options) Options {
 		bufferForFutureTimedMetric:       defaultTimedMetricBuffer,
 		maxNumCachedSourceSets:           defaultMaxNumCachedSourceSets,
 		discardNaNAggregatedValues:       defaultDiscardNaNAggregatedValues,
+		contributorsTag:                  defaultContributorsTag,
+		contributorsSuffix:               defaultContributorsSuffix,
+		histogramBucketSuffix:            defaultHistogramBucketSuffix,
 		verboseErrors:                    defaultVerboseErrors,
+		heartbeatStoragePolicy:           defaultHeartbeatStoragePolicy,
+		heartbeatMetricNamePrefix:        defaultHeartbeatMetricNamePrefix,
 	}
 
 	// Initialize pools.
@@ -498,6 +732,17 @@ func (o *options) GaugePrefix() []byte {
 	return o.gaugePrefix
 }
 
+func (o *options) SetHistogramPrefix(value []byte) Options {
+	opts := *o
+	opts.histogramPrefix = value
+	opts.computeFullHistogramPrefix()
+	return &opts
+}
+
+func (o *options) HistogramPrefix() []byte {
+	return o.histogramPrefix
+}
+
 func (o *options) SetTimeLock(value *sync.RWMutex) Options {
 	opts := *o
 	opts.timeLock = value
@@ -548,6 +793,16 @@ func (o *options) StreamOptions() cm.Options {
 	return o.streamOpts
 }
 
+func (o *options) SetTimerSketchOptions(value ddsketch.Options) Options {
+	opts := *o
+	opts.timerSketchOpts = value
+	return &opts
+}
+
+func (o *options) TimerSketchOptions() ddsketch.Options {
+	return o.timerSketchOpts
+}
+
 func (o *options) SetAdminClient(value client.AdminClient) Options {
 	opts := *o
 	opts.adminClient = value
@@ -728,6 +983,16 @@ func (o *options) MaxAllowedForwardingDelayFn() MaxAllowedForwardingDelayFn {
 	return o.maxAllowedForwardingDelayFn
 }
 
+func (o *options) SetMaxNumForwardedTimes(value int) Options {
+	opts := *o
+	opts.maxNumForwardedTimes = value
+	return &opts
+}
+
+func (o *options) MaxNumForwardedTimes() int {
+	return o.maxNumForwardedTimes
+}
+
 func (o *options) SetBufferForPastTimedMetric(value time.Duration) Options {
 	opts := *o
 	opts.bufferForPastTimedMetric = value
@@ -768,6 +1033,26 @@ func (o *options) MaxNumCachedSourceSets() int {
 	return o.maxNumCachedSourceSets
 }
 
+func (o *options) SetMaxAnnotationBytesPerShard(value int64) Options {
+	opts := *o
+	opts.maxAnnotationBytesPerShard = value
+	return &opts
+}
+
+func (o *options) MaxAnnotationBytesPerShard() int64 {
+	return o.maxAnnotationBytesPerShard
+}
+
+func (o *options) SetMaxExemplarsPerAggregation(value int) Options {
+	opts := *o
+	opts.maxExemplarsPerAggregation = value
+	return &opts
+}
+
+func (o *options) MaxExemplarsPerAggregation() int {
+	return o.maxExemplarsPerAggregation
+}
+
 func (o *options) SetDiscardNaNAggregatedValues(value bool) Options {
 	opts := *o
 	opts.discardNaNAggregatedValues = value
@@ -778,6 +1063,46 @@ func (o *options) DiscardNaNAggregatedValues() bool {
 	return o.discardNaNAggregatedValues
 }
 
+func (o *options) SetEmitContributors(value bool) Options {
+	opts := *o
+	opts.emitContributors = value
+	return &opts
+}
+
+func (o *options) EmitContributors() bool {
+	return o.emitContributors
+}
+
+func (o *options) SetContributorsTag(value []byte) Options {
+	opts := *o
+	opts.contributorsTag = value
+	return &opts
+}
+
+func (o *options) ContributorsTag() []byte {
+	return o.contributorsTag
+}
+
+func (o *options) SetContributorsSuffix(value []byte) Options {
+	opts := *o
+	opts.contributorsSuffix = value
+	return &opts
+}
+
+func (o *options) ContributorsSuffix() []byte {
+	return o.contributorsSuffix
+}
+
+func (o *options) SetHistogramBucketSuffix(value []byte) Options {
+	opts := *o
+	opts.histogramBucketSuffix = value
+	return &opts
+}
+
+func (o *options) HistogramBucketSuffix() []byte {
+	return o.histogramBucketSuffix
+}
+
 func (o *options) SetEntryPool(value EntryPool) Options {
 	opts := *o
 	opts.entryPool = value
@@ -818,6 +1143,16 @@ func (o *options) GaugeElemPool() GaugeElemPool {
 	return o.gaugeElemPool
 }
 
+func (o *options) SetHistogramElemPool(value HistogramElemPool) Options {
+	opts := *o
+	opts.histogramElemPool = value
+	return &opts
+}
+
+func (o *options) HistogramElemPool() HistogramElemPool {
+	return o.histogramElemPool
+}
+
 func (o *options) SetVerboseErrors(value bool) Options {
 	opts := *o
 	opts.verboseErrors = value
@@ -840,6 +1175,10 @@ func (o *options) FullGaugePrefix() []byte {
 	return o.fullGaugePrefix
 }
 
+func (o *options) FullHistogramPrefix() []byte {
+	return o.fullHistogramPrefix
+}
+
 func (o *options) TimerQuantiles() []float64 {
 	return o.timerQuantiles
 }
@@ -866,6 +1205,11 @@ func (o *options) initPools() {
 	o.gaugeElemPool.Init(func() *GaugeElem {
 		return MustNewGaugeElem(ElemData{}, o)
 	})
+
+	o.histogramElemPool = NewHistogramElemPool(nil)
+	o.histogramElemPool.Init(func() *HistogramElem {
+		return MustNewHistogramElem(ElemData{}, o)
+	})
 }
 
 func (o *options) computeAllDerived() {
@@ -876,6 +1220,7 @@ func (o *options) computeFullPrefixes() {
 	o.computeFullCounterPrefix()
 	o.computeFullTimerPrefix()
 	o.computeFullGaugePrefix()
+	o.computeFullHistogramPrefix()
 }
 
 func (o *options) computeFullCounterPrefix() {
@@ -899,6 +1244,13 @@ func (o *options) computeFullGaugePrefix() {
 	o.fullGaugePrefix = fullGaugePrefix
 }
 
+func (o *options) computeFullHistogramPrefix() {
+	fullHistogramPrefix := make([]byte, len(o.metricPrefix)+len(o.histogramPrefix))
+	n := copy(fullHistogramPrefix, o.metricPrefix)
+	copy(fullHistogramPrefix[n:], o.histogramPrefix)
+	o.fullHistogramPrefix = fullHistogramPrefix
+}
+
 func (o *options) AddToReset() bool {
 	return o.addToReset
 }
@@ -950,6 +1302,146 @@ func (o *options) SetTimedForResendEnabledRollupRegexps(value []string) Options
 	return &opts
 }
 
+func (o *options) SetHeartbeatEnabled(value bool) Options {
+	opts := *o
+	opts.heartbeatEnabled = value
+	return &opts
+}
+
+func (o *options) HeartbeatEnabled() bool {
+	return o.heartbeatEnabled
+}
+
+func (o *options) SetHeartbeatStoragePolicy(value policy.StoragePolicy) Options {
+	opts := *o
+	opts.heartbeatStoragePolicy = value
+	return &opts
+}
+
+func (o *options) HeartbeatStoragePolicy() policy.StoragePolicy {
+	return o.heartbeatStoragePolicy
+}
+
+func (o *options) SetHeartbeatMetricNamePrefix(value string) Options {
+	opts := *o
+	opts.heartbeatMetricNamePrefix = value
+	return &opts
+}
+
+func (o *options) HeartbeatMetricNamePrefix() string {
+	return o.heartbeatMetricNamePrefix
+}
+
+func (o *options) SetHeartbeatNewIDFn(value id.NewIDFn) Options {
+	opts := *o
+	opts.heartbeatNewIDFn = value
+	return &opts
+}
+
+func (o *options) HeartbeatNewIDFn() id.NewIDFn {
+	return o.heartbeatNewIDFn
+}
+
+func (o *options) SetClockSkewWatermark(value *ClockSkewWatermark) Options {
+	opts := *o
+	opts.clockSkewWatermark = value
+	return &opts
+}
+
+func (o *options) ClockSkewWatermark() *ClockSkewWatermark {
+	return o.clockSkewWatermark
+}
+
+func (o *options) SetResolutionDowngrader(value *ResolutionDowngrader) Options {
+	opts := *o
+	opts.resolutionDowngrader = value
+	return &opts
+}
+
+func (o *options) ResolutionDowngrader() *ResolutionDowngrader {
+	return o.resolutionDowngrader
+}
+
+func (o *options) SetMemoryWatchdogOptions(value MemoryWatchdogOptions) Options {
+	opts := *o
+	opts.memoryWatchdogOpts = value
+	return &opts
+}
+
+func (o *options) MemoryWatchdogOptions() MemoryWatchdogOptions {
+	return o.memoryWatchdogOpts
+}
+
+func (o *options) SetWAL(value wal.WAL) Options {
+	opts := *o
+	opts.wal = value
+	return &opts
+}
+
+func (o *options) WAL() wal.WAL {
+	return o.wal
+}
+
+func (o *options) SetAnnotationSizeLimitOptions(value *AnnotationSizeLimitOptions) Options {
+	opts := *o
+	opts.annotationSizeLimitOpts = value
+	return &opts
+}
+
+func (o *options) AnnotationSizeLimitOptions() *AnnotationSizeLimitOptions {
+	return o.annotationSizeLimitOpts
+}
+
+func (o *options) SetPromotionReplayOptions(value *PromotionReplayOptions) Options {
+	opts := *o
+	opts.promotionReplayOpts = value
+	return &opts
+}
+
+func (o *options) PromotionReplayOptions() *PromotionReplayOptions {
+	return o.promotionReplayOpts
+}
+
+func (o *options) SetVerificationOptions(value *VerificationOptions) Options {
+	opts := *o
+	opts.verificationOpts = value
+	return &opts
+}
+
+func (o *options) VerificationOptions() *VerificationOptions {
+	return o.verificationOpts
+}
+
+func (o *options) SetRollupCardinalityLimiter(value *RollupCardinalityLimiter) Options {
+	opts := *o
+	opts.rollupCardinalityLimiter = value
+	return &opts
+}
+
+func (o *options) RollupCardinalityLimiter() *RollupCardinalityLimiter {
+	return o.rollupCardinalityLimiter
+}
+
+func (o *options) SetLoadSheddingOptions(value *LoadSheddingOptions) Options {
+	opts := *o
+	opts.loadSheddingOpts = value
+	return &opts
+}
+
+func (o *options) LoadSheddingOptions() *LoadSheddingOptions {
+	return o.loadSheddingOpts
+}
+
+func (o *options) SetFlushOffsetTuningOptions(value *FlushOffsetTuningOptions) Options {
+	opts := *o
+	opts.flushOffsetTuningOpts = value
+	return &opts
+}
+
+func (o *options) FlushOffsetTuningOptions() *FlushOffsetTuningOptions {
+	return o.flushOffsetTuningOpts
+}
+
 func defaultMaxAllowedForwardingDelayFn(
 	resolution time.Duration,
 	numForwardedTimes int,
@@ -26,12 +26,15 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	"github.com/m3db/m3/src/aggregator/client"
 	"github.com/m3db/m3/src/aggregator/runtime"
+	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -64,6 +67,7 @@ func TestOptionsValidateDefault(t *testing.T) {
 	require.NotNil(t, o.InstrumentOptions())
 	require.NotNil(t, o.TimeLock())
 	require.NotNil(t, o.StreamOptions())
+	require.Nil(t, o.TimerSketchOptions())
 	require.NotNil(t, o.EntryPool())
 	require.NotNil(t, o.CounterElemPool())
 	require.NotNil(t, o.TimerElemPool())
@@ -123,6 +127,12 @@ func TestSetStreamOptions(t *testing.T) {
 	require.Equal(t, value, o.StreamOptions())
 }
 
+func TestSetTimerSketchOptions(t *testing.T) {
+	value := ddsketch.NewOptions()
+	o := newTestOptions().SetTimerSketchOptions(value)
+	require.Equal(t, value, o.TimerSketchOptions())
+}
+
 func TestSetAdminClient(t *testing.T) {
 	var c client.AdminClient = &client.M3MsgClient{}
 	o := newTestOptions().SetAdminClient(c)
@@ -212,12 +222,64 @@ func TestSetMaxNumCachedSourceSets(t *testing.T) {
 	require.Equal(t, value, o.MaxNumCachedSourceSets())
 }
 
+func TestSetMaxAnnotationBytesPerShard(t *testing.T) {
+	value := int64(1 << 20)
+	o := newTestOptions().SetMaxAnnotationBytesPerShard(value)
+	require.Equal(t, value, o.MaxAnnotationBytesPerShard())
+}
+
+func TestSetMaxExemplarsPerAggregation(t *testing.T) {
+	value := 4
+	o := newTestOptions().SetMaxExemplarsPerAggregation(value)
+	require.Equal(t, value, o.MaxExemplarsPerAggregation())
+}
+
 func TestSetDiscardNaNAggregatedValues(t *testing.T) {
 	value := false
 	o := newTestOptions().SetDiscardNaNAggregatedValues(value)
 	require.Equal(t, value, o.DiscardNaNAggregatedValues())
 }
 
+func TestSetEmitContributors(t *testing.T) {
+	value := true
+	o := newTestOptions().SetEmitContributors(value)
+	require.Equal(t, value, o.EmitContributors())
+}
+
+func TestSetContributorsTag(t *testing.T) {
+	value := []byte("instance")
+	o := newTestOptions().SetContributorsTag(value)
+	require.Equal(t, value, o.ContributorsTag())
+}
+
+func TestSetContributorsSuffix(t *testing.T) {
+	value := []byte("_hosts")
+	o := newTestOptions().SetContributorsSuffix(value)
+	require.Equal(t, value, o.ContributorsSuffix())
+}
+
+func TestSetHeartbeatEnabled(t *testing.T) {
+	o := newTestOptions()
+	require.False(t, o.HeartbeatEnabled())
+	o = o.SetHeartbeatEnabled(true)
+	require.True(t, o.HeartbeatEnabled())
+}
+
+func TestSetHeartbeatStoragePolicy(t *testing.T) {
+	value := policy.NewStoragePolicy(time.Minute, xtime.Minute, 24*time.Hour)
+	o := newTestOptions()
+	require.Equal(t, defaultHeartbeatStoragePolicy, o.HeartbeatStoragePolicy())
+	o = o.SetHeartbeatStoragePolicy(value)
+	require.Equal(t, value, o.HeartbeatStoragePolicy())
+}
+
+func TestSetHeartbeatMetricNamePrefix(t *testing.T) {
+	o := newTestOptions()
+	require.Equal(t, defaultHeartbeatMetricNamePrefix, o.HeartbeatMetricNamePrefix())
+	o = o.SetHeartbeatMetricNamePrefix("foo")
+	require.Equal(t, "foo", o.HeartbeatMetricNamePrefix())
+}
+
 func TestSetCounterElemPool(t *testing.T) {
 	value := NewCounterElemPool(nil)
 	o := newTestOptions().SetCounterElemPool(value)
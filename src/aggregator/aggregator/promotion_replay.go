@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/wal"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errNoPeerReplayer                = errors.New("no peer replayer set")
+	errInvalidPromotionReplayWindow  = errors.New("promotion replay window must be positive")
+	errInvalidPromotionReplayTimeout = errors.New("promotion replay timeout must be positive")
+)
+
+// PeerReplayer replays the metrics journaled to the write-ahead logs of the
+// peers of an aggregator, the other instances of its shard set.
+type PeerReplayer interface {
+	// Replay calls fn with each metric a peer journaled for the shard between
+	// start and end in the order they were journaled.
+	Replay(
+		ctx context.Context,
+		shard uint32,
+		start, end time.Time,
+		fn wal.ReplayFn,
+	) error
+}
+
+// PromotionReplayOptions configure the replay of the recent traffic of the
+// peers of a follower before it is promoted to leader. A follower that has
+// only been receiving the traffic of its shards for a short while, for example
+// after a restart, would otherwise publish partial aggregates with its first
+// flushes as leader.
+type PromotionReplayOptions struct {
+	// Replayer replays the metrics journaled by the peers.
+	Replayer PeerReplayer
+	// Window is how far back from the time a shard was opened the traffic of
+	// the shard is replayed, it should cover the longest aggregation window.
+	Window time.Duration
+	// Timeout bounds how long the promotion is delayed by the replay.
+	Timeout time.Duration
+}
+
+// Validate validates the options.
+func (o PromotionReplayOptions) Validate() error {
+	if o.Replayer == nil {
+		return errNoPeerReplayer
+	}
+	if o.Window <= 0 {
+		return errInvalidPromotionReplayWindow
+	}
+	if o.Timeout <= 0 {
+		return errInvalidPromotionReplayTimeout
+	}
+	return nil
+}
+
+// replayFromPeersBeforePromotion replays the recent traffic of the owned shards
+// from the peers of the aggregator before it is promoted from follower to leader.
+// Nothing is replayed if the follower already has the data to lead, or if the
+// flush times are unavailable as the flushed windows would be replayed into.
+// Replay errors are logged rather than preventing the promotion.
+func (agg *aggregator) replayFromPeersBeforePromotion() {
+	opts := agg.opts.PromotionReplayOptions()
+	if opts == nil || agg.flushManager.Status().CanLead {
+		return
+	}
+
+	agg.RLock()
+	shards := make([]*aggregatorShard, 0, len(agg.shardIDs))
+	for _, shardID := range agg.shardIDs {
+		if shard, ok := agg.ownedShardWithLock(shardID); ok {
+			shards = append(shards, shard)
+		}
+	}
+	agg.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	var (
+		start            = agg.nowFn()
+		replayed, failed int
+	)
+	flushTimes, err := agg.flushTimesManager.Get()
+	if err != nil {
+		agg.logger.Error("skipping replay from peers without flush times", zap.Error(err))
+		return
+	}
+	for _, shard := range shards {
+		shardReplayed, shardFailed, err := shard.ReplayFromPeers(ctx, opts.Replayer, opts.Window,
+			flushTimes.GetByShard()[shard.ID()])
+		replayed += shardReplayed
+		failed += shardFailed
+		if err != nil {
+			agg.logger.Error("error replaying shard from peers before promotion",
+				zap.Uint32("shard", shard.ID()), zap.Error(err))
+		}
+	}
+	agg.logger.Info("replayed shards from peers before promotion",
+		zap.Int("replayed", replayed),
+		zap.Int("failed", failed),
+		zap.Duration("took", agg.nowFn().Sub(start)))
+}
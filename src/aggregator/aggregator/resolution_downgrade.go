@@ -0,0 +1,248 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/policy"
+
+	"go.uber.org/atomic"
+)
+
+// DefaultResolutionDowngradeMaxOpenWindows is the default maximum number of
+// coarse windows each metric list holds open while the mode is enabled.
+const DefaultResolutionDowngradeMaxOpenWindows = 100000
+
+// ResolutionDowngradeTrigger is what enabled the resolution downgrade mode.
+type ResolutionDowngradeTrigger string
+
+const (
+	// ResolutionDowngradeTriggerManual is an operator enabling the mode
+	// through the admin API.
+	ResolutionDowngradeTriggerManual ResolutionDowngradeTrigger = "manual"
+
+	// ResolutionDowngradeTriggerMemoryWatchdog is the memory watchdog enabling
+	// the mode when heap usage crosses the high watermark.
+	ResolutionDowngradeTriggerMemoryWatchdog ResolutionDowngradeTrigger = "memory_watchdog"
+)
+
+// ResolutionDowngradePolicy coarsens the output resolution of the metrics with
+// the given storage policy to the given resolution while the resolution downgrade
+// mode is enabled.
+type ResolutionDowngradePolicy struct {
+	StoragePolicy policy.StoragePolicy
+	Resolution    time.Duration
+}
+
+// ResolutionDowngradeStatus is the run-time status of the resolution downgrade mode.
+type ResolutionDowngradeStatus struct {
+	Enabled  bool
+	Triggers []ResolutionDowngradeTrigger
+	Policies []ResolutionDowngradePolicy
+}
+
+// ResolutionDowngrader controls the emergency resolution downgrade mode. While the
+// mode is enabled, metrics flushed with one of the configured storage policies are
+// re-aggregated into windows of the coarser resolution configured for the policy
+// according to their aggregation type, and each coarse window is emitted once,
+// timestamped at the end of the window and flagged with the resolution it was
+// downgraded to. This reduces the write volume the aggregator pushes downstream
+// when it or its consumers are under load. The mode is enabled as long as any
+// trigger is active.
+type ResolutionDowngrader struct {
+	sync.Mutex
+
+	policies       map[policy.StoragePolicy]time.Duration
+	maxOpenWindows int
+	triggers       map[ResolutionDowngradeTrigger]struct{}
+	enabled        atomic.Bool
+}
+
+// NewResolutionDowngrader creates a new resolution downgrader for the given
+// policies, holding at most maxOpenWindows coarse windows open per metric list
+// or DefaultResolutionDowngradeMaxOpenWindows if not positive.
+func NewResolutionDowngrader(
+	policies []ResolutionDowngradePolicy,
+	maxOpenWindows int,
+) (*ResolutionDowngrader, error) {
+	byStoragePolicy := make(map[policy.StoragePolicy]time.Duration, len(policies))
+	for _, p := range policies {
+		window := p.StoragePolicy.Resolution().Window
+		if p.Resolution <= window || p.Resolution%window != 0 {
+			return nil, fmt.Errorf(
+				"downgraded resolution %v for storage policy %s must be a multiple of and larger than %v",
+				p.Resolution, p.StoragePolicy.String(), window)
+		}
+		if _, exists := byStoragePolicy[p.StoragePolicy]; exists {
+			return nil, fmt.Errorf("duplicate resolution downgrade for storage policy %s",
+				p.StoragePolicy.String())
+		}
+		byStoragePolicy[p.StoragePolicy] = p.Resolution
+	}
+	if maxOpenWindows <= 0 {
+		maxOpenWindows = DefaultResolutionDowngradeMaxOpenWindows
+	}
+	return &ResolutionDowngrader{
+		policies:       byStoragePolicy,
+		maxOpenWindows: maxOpenWindows,
+		triggers:       make(map[ResolutionDowngradeTrigger]struct{}),
+	}, nil
+}
+
+// MaxOpenWindows returns the maximum number of coarse windows each metric list
+// holds open, datapoints of other series are emitted at their original
+// resolution.
+func (d *ResolutionDowngrader) MaxOpenWindows() int {
+	return d.maxOpenWindows
+}
+
+// Enable activates the given trigger, enabling the mode if it was disabled.
+func (d *ResolutionDowngrader) Enable(trigger ResolutionDowngradeTrigger) {
+	d.Lock()
+	d.triggers[trigger] = struct{}{}
+	d.enabled.Store(true)
+	d.Unlock()
+}
+
+// Disable deactivates the given trigger, disabling the mode if no other trigger
+// is active.
+func (d *ResolutionDowngrader) Disable(trigger ResolutionDowngradeTrigger) {
+	d.Lock()
+	delete(d.triggers, trigger)
+	d.enabled.Store(len(d.triggers) > 0)
+	d.Unlock()
+}
+
+// Enabled returns whether the mode is enabled.
+func (d *ResolutionDowngrader) Enabled() bool {
+	return d.enabled.Load()
+}
+
+// DowngradedResolution returns the resolution metrics with the given storage policy
+// are emitted at, and true if the mode is enabled and the storage policy is downgraded.
+func (d *ResolutionDowngrader) DowngradedResolution(sp policy.StoragePolicy) (time.Duration, bool) {
+	if !d.enabled.Load() {
+		return 0, false
+	}
+	resolution, ok := d.policies[sp]
+	return resolution, ok
+}
+
+// Status returns the run-time status of the mode.
+func (d *ResolutionDowngrader) Status() ResolutionDowngradeStatus {
+	d.Lock()
+	triggers := make([]ResolutionDowngradeTrigger, 0, len(d.triggers))
+	for trigger := range d.triggers {
+		triggers = append(triggers, trigger)
+	}
+	d.Unlock()
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i] < triggers[j] })
+
+	policies := make([]ResolutionDowngradePolicy, 0, len(d.policies))
+	for sp, resolution := range d.policies {
+		policies = append(policies, ResolutionDowngradePolicy{
+			StoragePolicy: sp,
+			Resolution:    resolution,
+		})
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].StoragePolicy.String() < policies[j].StoragePolicy.String()
+	})
+
+	return ResolutionDowngradeStatus{
+		Enabled:  len(triggers) > 0,
+		Triggers: triggers,
+		Policies: policies,
+	}
+}
+
+// downgradeKey identifies a series re-bucketed by the resolution downgrade mode.
+type downgradeKey struct {
+	id string
+	sp policy.StoragePolicy
+}
+
+// downgradeBucket holds the values flushed for a series within the coarse
+// window ending at windowEndNanos re-aggregated into a single value, alongside
+// the exemplars of all the values flushed within the window.
+type downgradeBucket struct {
+	idPrefix       []byte
+	id             []byte
+	idSuffix       []byte
+	annotation     []byte
+	exemplars      []metric.Exemplar
+	value          float64
+	numValues      int
+	windowEndNanos int64
+	resolution     time.Duration
+	aggType        aggregation.Type
+}
+
+// add re-aggregates the value of a fine window into the coarse window. Sums and
+// counts are added up, minimums and maximums are kept, and means are averaged
+// over the fine windows. The other aggregation types, such as quantiles, cannot
+// be combined from the values of the fine windows alone, so the last value is
+// kept.
+func (b *downgradeBucket) add(value float64, aggType aggregation.Type) {
+	b.aggType = aggType
+	b.numValues++
+	if b.numValues == 1 {
+		b.value = value
+		return
+	}
+	switch aggType {
+	case aggregation.Sum, aggregation.SumSq, aggregation.Count:
+		b.value += value
+	case aggregation.Min:
+		b.value = math.Min(b.value, value)
+	case aggregation.Max:
+		b.value = math.Max(b.value, value)
+	case aggregation.Mean:
+		b.value += (value - b.value) / float64(b.numValues)
+	default:
+		b.value = value
+	}
+}
+
+func (b *downgradeBucket) reset() {
+	b.value = 0
+	b.numValues = 0
+	b.exemplars = nil
+}
+
+// downgradedWindowEndNanos returns the end of the coarse window of the given
+// resolution that a value timestamped at timeNanos falls into, treating the
+// timestamp as the end of the window the value was aggregated in.
+func downgradedWindowEndNanos(timeNanos int64, resolution time.Duration) int64 {
+	res := int64(resolution)
+	windowEnd := timeNanos - timeNanos%res
+	if windowEnd < timeNanos {
+		windowEnd += res
+	}
+	return windowEnd
+}
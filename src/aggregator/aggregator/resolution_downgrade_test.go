@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/policy"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+func TestNewResolutionDowngraderInvalidPolicies(t *testing.T) {
+	for _, policies := range [][]ResolutionDowngradePolicy{
+		{{StoragePolicy: testStoragePolicy, Resolution: 10 * time.Second}},
+		{{StoragePolicy: testStoragePolicy, Resolution: 15 * time.Second}},
+		{
+			{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+			{StoragePolicy: testStoragePolicy, Resolution: 5 * time.Minute},
+		},
+	} {
+		_, err := NewResolutionDowngrader(policies, 0)
+		require.Error(t, err)
+	}
+}
+
+func TestResolutionDowngraderTriggers(t *testing.T) {
+	otherStoragePolicy := policy.NewStoragePolicy(time.Minute, xtime.Second, 48*time.Hour)
+	d, err := NewResolutionDowngrader([]ResolutionDowngradePolicy{
+		{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+	}, 0)
+	require.NoError(t, err)
+
+	_, ok := d.DowngradedResolution(testStoragePolicy)
+	require.False(t, ok)
+
+	d.Enable(ResolutionDowngradeTriggerManual)
+	d.Enable(ResolutionDowngradeTriggerMemoryWatchdog)
+	resolution, ok := d.DowngradedResolution(testStoragePolicy)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, resolution)
+	_, ok = d.DowngradedResolution(otherStoragePolicy)
+	require.False(t, ok)
+	require.Equal(t, ResolutionDowngradeStatus{
+		Enabled: true,
+		Triggers: []ResolutionDowngradeTrigger{
+			ResolutionDowngradeTriggerManual,
+			ResolutionDowngradeTriggerMemoryWatchdog,
+		},
+		Policies: []ResolutionDowngradePolicy{
+			{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+		},
+	}, d.Status())
+
+	// The mode stays enabled while any trigger is active.
+	d.Disable(ResolutionDowngradeTriggerManual)
+	require.True(t, d.Enabled())
+	d.Disable(ResolutionDowngradeTriggerMemoryWatchdog)
+	require.False(t, d.Enabled())
+	require.Equal(t, []ResolutionDowngradeTrigger{}, d.Status().Triggers)
+}
+
+func TestDowngradedWindowEndNanos(t *testing.T) {
+	require.Equal(t, int64(time.Minute), downgradedWindowEndNanos(int64(10*time.Second), time.Minute))
+	require.Equal(t, int64(time.Minute), downgradedWindowEndNanos(int64(time.Minute), time.Minute))
+	require.Equal(t, int64(2*time.Minute), downgradedWindowEndNanos(int64(70*time.Second), time.Minute))
+}
+
+func TestMemoryWatchdogCheck(t *testing.T) {
+	d, err := NewResolutionDowngrader([]ResolutionDowngradePolicy{
+		{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+	}, 0)
+	require.NoError(t, err)
+
+	var heapInUse uint64
+	opts := MemoryWatchdogOptions{
+		CheckInterval:      time.Second,
+		HighWatermarkBytes: 100,
+		LowWatermarkBytes:  50,
+	}
+	require.NoError(t, opts.Validate())
+	w := newMemoryWatchdog(opts, d, func() uint64 { return heapInUse },
+		zap.NewNop(), tally.NoopScope)
+
+	for _, input := range []struct {
+		heapInUse uint64
+		enabled   bool
+	}{
+		{heapInUse: 80, enabled: false},
+		{heapInUse: 100, enabled: true},
+		// Stays enabled between the watermarks.
+		{heapInUse: 60, enabled: true},
+		{heapInUse: 40, enabled: false},
+		{heapInUse: 60, enabled: false},
+	} {
+		heapInUse = input.heapInUse
+		w.Check()
+		require.Equal(t, input.enabled, d.Enabled(), input.heapInUse)
+	}
+
+	// The watchdog does not disable a manually enabled mode.
+	d.Enable(ResolutionDowngradeTriggerManual)
+	heapInUse = 200
+	w.Check()
+	heapInUse = 10
+	w.Check()
+	require.True(t, d.Enabled())
+	require.Equal(t, []ResolutionDowngradeTrigger{ResolutionDowngradeTriggerManual}, d.Status().Triggers)
+}
+
+func TestMemoryWatchdogOptionsValidate(t *testing.T) {
+	require.NoError(t, MemoryWatchdogOptions{}.Validate())
+	require.Equal(t, errMemoryWatchdogWatermarks, MemoryWatchdogOptions{
+		CheckInterval:      time.Second,
+		HighWatermarkBytes: 50,
+		LowWatermarkBytes:  50,
+	}.Validate())
+}
+
+func TestDowngradeBucketAdd(t *testing.T) {
+	for _, test := range []struct {
+		aggType  aggregation.Type
+		expected float64
+	}{
+		{aggType: aggregation.Sum, expected: 9},
+		{aggType: aggregation.Count, expected: 9},
+		{aggType: aggregation.Min, expected: 1},
+		{aggType: aggregation.Max, expected: 5},
+		{aggType: aggregation.Mean, expected: 3},
+		{aggType: aggregation.Last, expected: 5},
+		{aggType: aggregation.P99, expected: 5},
+	} {
+		var b downgradeBucket
+		for _, v := range []float64{3, 1, 5} {
+			b.add(v, test.aggType)
+		}
+		require.Equal(t, test.expected, b.value, test.aggType.String())
+
+		b.reset()
+		b.add(7, test.aggType)
+		require.Equal(t, float64(7), b.value, test.aggType.String())
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/x/serialize"
+
+	murmur3 "github.com/m3db/stackmurmur3/v2"
+	"github.com/uber-go/tally"
+)
+
+var (
+	defaultRollupCardinalityNameTag = []byte("__name__")
+
+	errRollupCardinalityLimitExceeded = errors.New("rollup cardinality limit is exceeded")
+)
+
+// RollupCardinalityLimitMode determines what happens to the new series of a
+// rollup rule once the rule has reached its cardinality limit.
+type RollupCardinalityLimitMode string
+
+const (
+	// RollupCardinalityLimitReject rejects all the new series beyond the limit.
+	RollupCardinalityLimitReject RollupCardinalityLimitMode = "reject"
+
+	// RollupCardinalityLimitSample accepts a sample of the new series beyond
+	// the limit. Series are sampled by the hash of their ID, so the same series
+	// are consistently accepted or rejected.
+	RollupCardinalityLimitSample RollupCardinalityLimitMode = "sample"
+)
+
+// RollupCardinalityLimitOptions configure the limits on the number of distinct
+// series each rollup rule produces in the aggregator.
+type RollupCardinalityLimitOptions struct {
+	// DefaultLimit is the maximum number of distinct series of the rollup rules
+	// without a limit of their own, zero means unlimited.
+	DefaultLimit int
+	// Limits are the maximum numbers of distinct series of rollup rules keyed
+	// by the name of the metric the rules roll up into.
+	Limits map[string]int
+	// Mode determines what happens to the new series beyond the limit.
+	Mode RollupCardinalityLimitMode
+	// SampleRate is the fraction of the new series beyond the limit accepted
+	// when sampling.
+	SampleRate float64
+	// NameTag is the tag the metric name is encoded as in rollup IDs with
+	// encoded tags, it defaults to __name__.
+	NameTag []byte
+}
+
+// Validate validates the options.
+func (o RollupCardinalityLimitOptions) Validate() error {
+	if o.DefaultLimit < 0 {
+		return fmt.Errorf("negative default rollup cardinality limit: %d", o.DefaultLimit)
+	}
+	for name, limit := range o.Limits {
+		if limit < 0 {
+			return fmt.Errorf("negative cardinality limit for rollup %s: %d", name, limit)
+		}
+	}
+	switch o.Mode {
+	case RollupCardinalityLimitReject:
+	case RollupCardinalityLimitSample:
+		if o.SampleRate < 0 || o.SampleRate > 1 {
+			return fmt.Errorf("rollup cardinality sample rate must be between 0 and 1: %v",
+				o.SampleRate)
+		}
+	default:
+		return fmt.Errorf("invalid rollup cardinality limit mode: %s", o.Mode)
+	}
+	return nil
+}
+
+type rollupCardinalityLimiterMetrics struct {
+	series   tally.Gauge
+	rejected tally.Counter
+	sampled  tally.Counter
+}
+
+func newRollupCardinalityLimiterMetrics(scope tally.Scope) rollupCardinalityLimiterMetrics {
+	return rollupCardinalityLimiterMetrics{
+		series: scope.Gauge("series"),
+		rejected: scope.Tagged(map[string]string{
+			"action": "rejected",
+		}).Counter("limit-exceeded"),
+		sampled: scope.Tagged(map[string]string{
+			"action": "sampled",
+		}).Counter("limit-exceeded"),
+	}
+}
+
+// RollupCardinalityLimiter tracks the number of distinct series each rollup
+// rule produces across the shards of the aggregator, and rejects or samples
+// the new series of the rules that reached their limit to protect against rules
+// whose cardinality explodes. Rollup rules are identified by the name of the
+// metric they roll up into, and a series is tracked until its entry expires.
+type RollupCardinalityLimiter struct {
+	sync.Mutex
+
+	opts      RollupCardinalityLimitOptions
+	nameTag   []byte
+	threshold uint32
+	series    map[string]int
+	numSeries int
+	metrics   rollupCardinalityLimiterMetrics
+}
+
+// NewRollupCardinalityLimiter creates a new rollup cardinality limiter.
+func NewRollupCardinalityLimiter(
+	opts RollupCardinalityLimitOptions,
+	scope tally.Scope,
+) (*RollupCardinalityLimiter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	nameTag := opts.NameTag
+	if len(nameTag) == 0 {
+		nameTag = defaultRollupCardinalityNameTag
+	}
+	var threshold uint32
+	if opts.Mode == RollupCardinalityLimitSample {
+		threshold = uint32(opts.SampleRate * math.MaxUint32)
+	}
+	return &RollupCardinalityLimiter{
+		opts:      opts,
+		nameTag:   nameTag,
+		threshold: threshold,
+		series:    make(map[string]int),
+		metrics:   newRollupCardinalityLimiterMetrics(scope),
+	}, nil
+}
+
+// Add accounts for a new series with the given rollup ID, returning
+// the name of the rollup rule the series is tracked under, or an error if the
+// series is rejected. Series whose rollup rule cannot be determined or that are
+// not limited are not tracked, and an empty name is returned.
+func (l *RollupCardinalityLimiter) Add(rollupID id.RawID) (string, error) {
+	if l == nil {
+		return "", nil
+	}
+	name, ok := l.rollupName(rollupID)
+	if !ok {
+		return "", nil
+	}
+	limit, ok := l.opts.Limits[string(name)]
+	if !ok {
+		limit = l.opts.DefaultLimit
+	}
+	if limit <= 0 {
+		return "", nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if l.series[string(name)] >= limit {
+		if l.opts.Mode != RollupCardinalityLimitSample || !l.sampled(rollupID) {
+			l.metrics.rejected.Inc(1)
+			return "", errRollupCardinalityLimitExceeded
+		}
+		l.metrics.sampled.Inc(1)
+	}
+	rule := string(name)
+	l.series[rule]++
+	l.numSeries++
+	return rule, nil
+}
+
+// Remove releases a series tracked under the given rollup rule once its entry
+// expires.
+func (l *RollupCardinalityLimiter) Remove(rule string) {
+	if l == nil || rule == "" {
+		return
+	}
+	l.Lock()
+	if n := l.series[rule]; n <= 1 {
+		delete(l.series, rule)
+	} else {
+		l.series[rule] = n - 1
+	}
+	l.numSeries--
+	l.Unlock()
+}
+
+// NumSeries returns the number of series tracked under the given rollup rule.
+func (l *RollupCardinalityLimiter) NumSeries(rule string) int {
+	l.Lock()
+	n := l.series[rule]
+	l.Unlock()
+	return n
+}
+
+// Report reports the number of series tracked across all the rollup rules.
+func (l *RollupCardinalityLimiter) Report() {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	numSeries := l.numSeries
+	l.Unlock()
+	l.metrics.series.Update(float64(numSeries))
+}
+
+func (l *RollupCardinalityLimiter) sampled(rollupID id.RawID) bool {
+	return l.threshold > 0 && murmur3.Sum32(rollupID) <= l.threshold
+}
+
+// rollupName returns the name of the metric a rollup ID rolls up into, which
+// identifies its rollup rule.
+func (l *RollupCardinalityLimiter) rollupName(rollupID id.RawID) ([]byte, bool) {
+	if name, ok, err := serialize.TagValueFromEncodedTagsFast(rollupID, l.nameTag); err == nil {
+		return name, ok
+	}
+	name, _, err := m3.NameAndTags(rollupID)
+	if err != nil {
+		return nil, false
+	}
+	return name, true
+}
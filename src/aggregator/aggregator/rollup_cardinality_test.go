@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestRollupCardinalityLimiterReject(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	l, err := NewRollupCardinalityLimiter(RollupCardinalityLimitOptions{
+		DefaultLimit: 2,
+		Limits:       map[string]int{"unlimited": 0, "small": 1},
+		Mode:         RollupCardinalityLimitReject,
+	}, scope)
+	require.NoError(t, err)
+
+	for _, id := range []string{"m3+requests+service=a", "m3+requests+service=b"} {
+		rule, err := l.Add([]byte(id))
+		require.NoError(t, err)
+		require.Equal(t, "requests", rule)
+	}
+	_, err = l.Add([]byte("m3+requests+service=c"))
+	require.Equal(t, errRollupCardinalityLimitExceeded, err)
+	require.Equal(t, 2, l.NumSeries("requests"))
+
+	// Rules with a limit of their own do not use the default limit.
+	rule, err := l.Add([]byte("m3+small+service=a"))
+	require.NoError(t, err)
+	require.Equal(t, "small", rule)
+	_, err = l.Add([]byte("m3+small+service=b"))
+	require.Equal(t, errRollupCardinalityLimitExceeded, err)
+	for i := 0; i < 10; i++ {
+		rule, err = l.Add([]byte(fmt.Sprintf("m3+unlimited+service=%d", i)))
+		require.NoError(t, err)
+		require.Equal(t, "", rule)
+	}
+
+	// IDs whose rollup rule cannot be determined are not limited.
+	rule, err = l.Add([]byte("not-a-rollup-id"))
+	require.NoError(t, err)
+	require.Equal(t, "", rule)
+
+	// Expired series make room for new ones.
+	l.Remove("requests")
+	require.Equal(t, 1, l.NumSeries("requests"))
+	_, err = l.Add([]byte("m3+requests+service=c"))
+	require.NoError(t, err)
+
+	l.Report()
+	snapshot := scope.Snapshot()
+	require.Equal(t, int64(2), snapshot.Counters()["limit-exceeded+action=rejected"].Value())
+	require.Equal(t, float64(3), snapshot.Gauges()["series+"].Value())
+}
+
+func TestRollupCardinalityLimiterSample(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	l, err := NewRollupCardinalityLimiter(RollupCardinalityLimitOptions{
+		DefaultLimit: 10,
+		Mode:         RollupCardinalityLimitSample,
+		SampleRate:   0.5,
+	}, scope)
+	require.NoError(t, err)
+
+	var accepted int
+	for i := 0; i < 1000; i++ {
+		if _, err := l.Add([]byte(fmt.Sprintf("m3+requests+service=%d", i))); err == nil {
+			accepted++
+		}
+	}
+	require.True(t, accepted > 400 && accepted < 600, "accepted %d", accepted)
+	require.Equal(t, accepted, l.NumSeries("requests"))
+	snapshot := scope.Snapshot()
+	require.Equal(t, int64(accepted-10),
+		snapshot.Counters()["limit-exceeded+action=sampled"].Value())
+
+	// The same series are consistently sampled.
+	l.Remove("requests")
+	_, err1 := l.Add([]byte("m3+requests+service=999"))
+	l.Remove("requests")
+	_, err2 := l.Add([]byte("m3+requests+service=999"))
+	require.Equal(t, err1, err2)
+}
+
+func TestRollupCardinalityLimiterEncodedTags(t *testing.T) {
+	l, err := NewRollupCardinalityLimiter(RollupCardinalityLimitOptions{
+		DefaultLimit: 1,
+		Mode:         RollupCardinalityLimitReject,
+	}, tally.NoopScope)
+	require.NoError(t, err)
+
+	rule, err := l.Add(testEncodedTags(t, "__name__", "requests", "service", "a"))
+	require.NoError(t, err)
+	require.Equal(t, "requests", rule)
+	_, err = l.Add(testEncodedTags(t, "__name__", "requests", "service", "b"))
+	require.Equal(t, errRollupCardinalityLimitExceeded, err)
+}
+
+func TestRollupCardinalityLimitOptionsValidate(t *testing.T) {
+	require.Error(t, RollupCardinalityLimitOptions{
+		DefaultLimit: -1, Mode: RollupCardinalityLimitReject,
+	}.Validate())
+	require.Error(t, RollupCardinalityLimitOptions{
+		Limits: map[string]int{"requests": -1}, Mode: RollupCardinalityLimitReject,
+	}.Validate())
+	require.Error(t, RollupCardinalityLimitOptions{Mode: "drop"}.Validate())
+	require.Error(t, RollupCardinalityLimitOptions{
+		Mode: RollupCardinalityLimitSample, SampleRate: 1.5,
+	}.Validate())
+	require.NoError(t, RollupCardinalityLimitOptions{
+		DefaultLimit: 10, Mode: RollupCardinalityLimitSample, SampleRate: 0.1,
+	}.Validate())
+
+	var nilLimiter *RollupCardinalityLimiter
+	rule, err := nilLimiter.Add([]byte("m3+requests+service=a"))
+	require.NoError(t, err)
+	require.Equal(t, "", rule)
+	nilLimiter.Remove(rule)
+	nilLimiter.Report()
+}
+
+func testEncodedTags(t *testing.T, tags ...string) []byte {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions().SetSize(1))
+	encoderPool.Init()
+	encoder := encoderPool.Get()
+	require.NoError(t, encoder.Encode(ident.MustNewTagStringsIterator(tags...)))
+	data, ok := encoder.Data()
+	require.True(t, ok)
+	return append([]byte(nil), data.Bytes()...)
+}
@@ -21,25 +21,52 @@
 package aggregator
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math"
 	"strconv"
 	"sync"
 	"time"
 
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/aggregator/wal"
+	"github.com/m3db/m3/src/metrics/encoding"
 	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
+	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
 
 	"github.com/uber-go/tally"
 )
 
 var (
+	// ErrShardIngestionPaused is returned when writing to a shard whose ingestion
+	// has been paused, writes rejected with it should be retried later.
+	ErrShardIngestionPaused = errors.New("aggregator shard ingestion is paused")
+
 	errAggregatorShardClosed       = errors.New("aggregator shard is closed")
 	errAggregatorShardNotWriteable = errors.New("aggregator shard is not writeable")
 )
 
+// ShardIngestionPausedError is returned by writes rejected because the
+// ingestion of their shard is paused, and matches ErrShardIngestionPaused.
+type ShardIngestionPausedError struct {
+	ShardIngestionPause
+}
+
+func (e ShardIngestionPausedError) Error() string {
+	return ErrShardIngestionPaused.Error()
+}
+
+// Is returns true if the target is ErrShardIngestionPaused.
+func (e ShardIngestionPausedError) Is(target error) bool {
+	return target == ErrShardIngestionPaused
+}
+
 type addUntimedFn func(
 	metric unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
@@ -61,14 +88,20 @@ type addForwardedFn func(
 ) error
 
 type aggregatorShardMetrics struct {
-	notWriteableErrors tally.Counter
-	writeSucccess      tally.Counter
+	notWriteableErrors    tally.Counter
+	ingestionPausedErrors tally.Counter
+	writeSucccess         tally.Counter
+	walWriteErrors        tally.Counter
+	replaySkippedFlushed  tally.Counter
 }
 
 func newAggregatorShardMetrics(scope tally.Scope) aggregatorShardMetrics {
 	return aggregatorShardMetrics{
-		notWriteableErrors: scope.Counter("not-writeable-errors"),
-		writeSucccess:      scope.Counter("write-success"),
+		notWriteableErrors:    scope.Counter("not-writeable-errors"),
+		ingestionPausedErrors: scope.Counter("ingestion-paused-errors"),
+		writeSucccess:         scope.Counter("write-success"),
+		walWriteErrors:        scope.Counter("wal-write-errors"),
+		replaySkippedFlushed:  scope.Counter("replay-skipped-flushed"),
 	}
 }
 
@@ -78,6 +111,8 @@ type aggregatorShard struct {
 
 	shard                            uint32
 	redirectToShardID                *uint32
+	runtimeRedirectToShardID         *uint32
+	runtimeRedirectExpireAtNanos     int64
 	nowFn                            clock.NowFn
 	bufferDurationBeforeShardCutover time.Duration
 	bufferDurationAfterShardCutoff   time.Duration
@@ -85,6 +120,14 @@ type aggregatorShard struct {
 	cutoffNanos                      int64
 	earliestWritableNanos            int64
 	latestWriteableNanos             int64
+	writesIgnoreCutoffCutoverFn      func(shard uint32) bool
+	ingestionPausedUntilNanos        int64
+	lastTickAtNanos                  int64
+	lastTickDuration                 time.Duration
+	lastTickEntries                  int
+	lastTickExpiredEntries           int
+	openedAtNanos                    int64
+	recoveredFromWAL                 bool
 
 	closed                        bool
 	metricMap                     *metricMap
@@ -93,6 +136,8 @@ type aggregatorShard struct {
 	addTimedFn                    addTimedFn
 	addTimedWithStagedMetadatasFn addTimedWithStagedMetadatasFn
 	addForwardedFn                addForwardedFn
+	wal                           wal.WAL
+	defaultStoragePolicies        policy.StoragePolicies
 }
 
 func newAggregatorShard(shard uint32, opts Options) *aggregatorShard {
@@ -111,11 +156,20 @@ func newAggregatorShard(shard uint32, opts Options) *aggregatorShard {
 		metricMap:                        newMetricMap(shard, opts),
 		metrics:                          newAggregatorShardMetrics(scope),
 		latestWriteableNanos:             int64(math.MaxInt64),
+		defaultStoragePolicies:           opts.DefaultStoragePolicies(),
 	}
+	s.openedAtNanos = s.nowFn().UnixNano()
 	s.addUntimedFn = s.metricMap.AddUntimed
 	s.addTimedFn = s.metricMap.AddTimed
 	s.addTimedWithStagedMetadatasFn = s.metricMap.AddTimedWithStagedMetadatas
 	s.addForwardedFn = s.metricMap.AddForwarded
+	if w := opts.WAL(); w != nil {
+		s.wal = w
+		s.addUntimedFn = s.journalUntimed
+		s.addTimedFn = s.journalTimed
+		s.addTimedWithStagedMetadatasFn = s.journalTimedWithStagedMetadatas
+		s.addForwardedFn = s.journalForwarded
+	}
 	return s
 }
 
@@ -147,6 +201,64 @@ func (s *aggregatorShard) SetRedirectToShardID(id *uint32) {
 	s.redirectToShardID = id
 }
 
+// SetRuntimeRedirectToShardID sets a redirect that takes precedence over the
+// redirect from the placement until it expires, or clears it if id is nil.
+func (s *aggregatorShard) SetRuntimeRedirectToShardID(id *uint32, expireAtNanos int64) {
+	s.runtimeRedirectToShardID = id
+	s.runtimeRedirectExpireAtNanos = expireAtNanos
+}
+
+// RuntimeRedirect returns the runtime redirect of the shard if one is set and
+// has not expired.
+func (s *aggregatorShard) RuntimeRedirect() (ShardRedirect, bool) {
+	if s.runtimeRedirectToShardID == nil ||
+		s.nowFn().UnixNano() >= s.runtimeRedirectExpireAtNanos {
+		return ShardRedirect{}, false
+	}
+	return ShardRedirect{
+		ShardID:           s.shard,
+		RedirectToShardID: *s.runtimeRedirectToShardID,
+		ExpiresAt:         time.Unix(0, s.runtimeRedirectExpireAtNanos),
+	}, true
+}
+
+// RedirectTarget returns the shard writes should be redirected to, if any.
+func (s *aggregatorShard) RedirectTarget() *uint32 {
+	if s.runtimeRedirectToShardID != nil &&
+		s.nowFn().UnixNano() < s.runtimeRedirectExpireAtNanos {
+		return s.runtimeRedirectToShardID
+	}
+	return s.redirectToShardID
+}
+
+// SetWritesIgnoreCutoffCutoverFn sets the function determining whether writes
+// to the shard are accepted regardless of its writeable range.
+func (s *aggregatorShard) SetWritesIgnoreCutoffCutoverFn(fn func(shard uint32) bool) {
+	s.Lock()
+	s.writesIgnoreCutoffCutoverFn = fn
+	s.Unlock()
+}
+
+// SetIngestionPausedUntil rejects writes to the shard with ErrShardIngestionPaused
+// until the given time, or resumes the ingestion if untilNanos is zero.
+func (s *aggregatorShard) SetIngestionPausedUntil(untilNanos int64) {
+	s.Lock()
+	s.ingestionPausedUntilNanos = untilNanos
+	s.Unlock()
+}
+
+// IngestionPause returns the pause of the ingestion of the shard if one is set
+// and has not expired.
+func (s *aggregatorShard) IngestionPause() (ShardIngestionPause, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if !s.isIngestionPausedWithLock() {
+		return ShardIngestionPause{}, false
+	}
+	return s.ingestionPauseWithLock(), true
+}
+
 func (s *aggregatorShard) SetWriteableRange(rng timeRange) {
 	var (
 		cutoverNanos  = rng.cutoverNanos
@@ -177,6 +289,12 @@ func (s *aggregatorShard) AddUntimed(
 		s.RUnlock()
 		return errAggregatorShardClosed
 	}
+	if s.isIngestionPausedWithLock() {
+		err := s.ingestionPausedErrorWithLock()
+		s.RUnlock()
+		s.metrics.ingestionPausedErrors.Inc(1)
+		return err
+	}
 	if !s.isWritableWithLock() {
 		s.RUnlock()
 		s.metrics.notWriteableErrors.Inc(1)
@@ -200,6 +318,12 @@ func (s *aggregatorShard) AddTimed(
 		s.RUnlock()
 		return errAggregatorShardClosed
 	}
+	if s.isIngestionPausedWithLock() {
+		err := s.ingestionPausedErrorWithLock()
+		s.RUnlock()
+		s.metrics.ingestionPausedErrors.Inc(1)
+		return err
+	}
 	if !s.isWritableWithLock() {
 		s.RUnlock()
 		s.metrics.notWriteableErrors.Inc(1)
@@ -223,6 +347,12 @@ func (s *aggregatorShard) AddTimedWithStagedMetadatas(
 		s.RUnlock()
 		return errAggregatorShardClosed
 	}
+	if s.isIngestionPausedWithLock() {
+		err := s.ingestionPausedErrorWithLock()
+		s.RUnlock()
+		s.metrics.ingestionPausedErrors.Inc(1)
+		return err
+	}
 	if !s.isWritableWithLock() {
 		s.RUnlock()
 		s.metrics.notWriteableErrors.Inc(1)
@@ -246,6 +376,12 @@ func (s *aggregatorShard) AddForwarded(
 		s.RUnlock()
 		return errAggregatorShardClosed
 	}
+	if s.isIngestionPausedWithLock() {
+		err := s.ingestionPausedErrorWithLock()
+		s.RUnlock()
+		s.metrics.ingestionPausedErrors.Inc(1)
+		return err
+	}
 	if !s.isWritableWithLock() {
 		s.RUnlock()
 		s.metrics.notWriteableErrors.Inc(1)
@@ -260,8 +396,398 @@ func (s *aggregatorShard) AddForwarded(
 	return nil
 }
 
+// Replay replays the metrics journaled by the shard into the metric map without
+// journaling them again, returning the number of metrics replayed and the number
+// that could not be added. Metrics are not replayed into the windows that have
+// already been flushed according to the persisted flush times of the shard, which
+// may be nil, so that the flushed windows are not aggregated a second time.
+func (s *aggregatorShard) Replay(
+	flushTimes *schema.ShardFlushTimes,
+) (replayed int, failed int, err error) {
+	if s.wal == nil {
+		return 0, 0, nil
+	}
+	err = s.wal.Replay(s.shard, s.replayFn(flushTimes, &replayed, &failed))
+	if replayed > 0 {
+		s.Lock()
+		s.recoveredFromWAL = true
+		s.Unlock()
+	}
+	return replayed, failed, err
+}
+
+// ReplayFromPeers replays the metrics journaled by the peers of the aggregator
+// for the shard before the shard was opened, which this instance has not
+// received itself, so that the aggregations it flushes once promoted to leader
+// are complete. Shards opened earlier than the window ago, or that have been
+// recovered from the write-ahead log, are not replayed as they are complete.
+// As with Replay, windows already flushed are not replayed into.
+func (s *aggregatorShard) ReplayFromPeers(
+	ctx context.Context,
+	replayer PeerReplayer,
+	window time.Duration,
+	flushTimes *schema.ShardFlushTimes,
+) (replayed int, failed int, err error) {
+	s.RLock()
+	openedAt := time.Unix(0, s.openedAtNanos)
+	recoveredFromWAL := s.recoveredFromWAL
+	s.RUnlock()
+
+	if recoveredFromWAL || !openedAt.After(s.nowFn().Add(-window)) {
+		return 0, 0, nil
+	}
+	err = replayer.Replay(ctx, s.shard, openedAt.Add(-window), openedAt,
+		s.replayFn(flushTimes, &replayed, &failed))
+	return replayed, failed, err
+}
+
+// replayFn returns the function replaying journaled metrics into the shard,
+// counting the metrics replayed and the metrics that could not be added.
+func (s *aggregatorShard) replayFn(
+	flushTimes *schema.ShardFlushTimes,
+	replayed *int,
+	failed *int,
+) wal.ReplayFn {
+	return func(msg encoding.UnaggregatedMessageUnion, journaledAt time.Time) error {
+		msg, ok := unflushedMessage(msg, journaledAt, flushTimes, s.defaultStoragePolicies)
+		if !ok {
+			s.metrics.replaySkippedFlushed.Inc(1)
+			return nil
+		}
+		// Metrics too late to be aggregated are expected to fail, which should
+		// not prevent the remaining metrics from being replayed.
+		if err := s.replayMessage(msg, journaledAt); err != nil {
+			*failed++
+			return nil
+		}
+		*replayed++
+		return nil
+	}
+}
+
+// replayMessage adds a replayed metric to the metric map without journaling it.
+func (s *aggregatorShard) replayMessage(
+	msg encoding.UnaggregatedMessageUnion,
+	journaledAt time.Time,
+) error {
+	switch msg.Type {
+	case encoding.CounterWithMetadatasType:
+		return s.metricMap.ReplayUntimed(msg.CounterWithMetadatas.Counter.ToUnion(),
+			msg.CounterWithMetadatas.StagedMetadatas, journaledAt)
+	case encoding.BatchTimerWithMetadatasType:
+		return s.metricMap.ReplayUntimed(msg.BatchTimerWithMetadatas.BatchTimer.ToUnion(),
+			msg.BatchTimerWithMetadatas.StagedMetadatas, journaledAt)
+	case encoding.GaugeWithMetadatasType:
+		return s.metricMap.ReplayUntimed(msg.GaugeWithMetadatas.Gauge.ToUnion(),
+			msg.GaugeWithMetadatas.StagedMetadatas, journaledAt)
+	case encoding.HistogramWithMetadatasType:
+		return s.metricMap.ReplayUntimed(msg.HistogramWithMetadatas.Histogram.ToUnion(),
+			msg.HistogramWithMetadatas.StagedMetadatas, journaledAt)
+	case encoding.TimedMetricWithMetadataType:
+		return s.metricMap.AddTimed(msg.TimedMetricWithMetadata.Metric,
+			msg.TimedMetricWithMetadata.TimedMetadata)
+	case encoding.TimedMetricWithMetadatasType:
+		return s.metricMap.AddTimedWithStagedMetadatas(msg.TimedMetricWithMetadatas.Metric,
+			msg.TimedMetricWithMetadatas.StagedMetadatas)
+	case encoding.ForwardedMetricWithMetadataType:
+		return s.metricMap.AddForwarded(msg.ForwardedMetricWithMetadata.ForwardedMetric,
+			msg.ForwardedMetricWithMetadata.ForwardMetadata)
+	default:
+		return fmt.Errorf("unexpected journaled message type: %v", msg.Type)
+	}
+}
+
+// unflushedMessage returns the journaled message without the storage policies
+// whose windows containing the metric have already been flushed according to
+// the flush times of the shard, and false if all of them have been flushed.
+func unflushedMessage(
+	msg encoding.UnaggregatedMessageUnion,
+	journaledAt time.Time,
+	flushTimes *schema.ShardFlushTimes,
+	defaultPolicies policy.StoragePolicies,
+) (encoding.UnaggregatedMessageUnion, bool) {
+	if flushTimes == nil {
+		return msg, true
+	}
+	var (
+		journaledAtNanos = journaledAt.UnixNano()
+		standard         = flushTimes.StandardByResolution
+		timed            = flushTimes.TimedByResolution
+		ok               = true
+	)
+	switch msg.Type {
+	case encoding.CounterWithMetadatasType:
+		msg.CounterWithMetadatas.StagedMetadatas, ok = unflushedStagedMetadatas(
+			msg.CounterWithMetadatas.StagedMetadatas, journaledAtNanos, standard, defaultPolicies)
+	case encoding.BatchTimerWithMetadatasType:
+		msg.BatchTimerWithMetadatas.StagedMetadatas, ok = unflushedStagedMetadatas(
+			msg.BatchTimerWithMetadatas.StagedMetadatas, journaledAtNanos, standard, defaultPolicies)
+	case encoding.GaugeWithMetadatasType:
+		msg.GaugeWithMetadatas.StagedMetadatas, ok = unflushedStagedMetadatas(
+			msg.GaugeWithMetadatas.StagedMetadatas, journaledAtNanos, standard, defaultPolicies)
+	case encoding.HistogramWithMetadatasType:
+		msg.HistogramWithMetadatas.StagedMetadatas, ok = unflushedStagedMetadatas(
+			msg.HistogramWithMetadatas.StagedMetadatas, journaledAtNanos, standard, defaultPolicies)
+	case encoding.TimedMetricWithMetadataType:
+		ok = !windowFlushed(msg.TimedMetricWithMetadata.StoragePolicy,
+			msg.TimedMetricWithMetadata.TimeNanos, timed)
+	case encoding.TimedMetricWithMetadatasType:
+		msg.TimedMetricWithMetadatas.StagedMetadatas, ok = unflushedStagedMetadatas(
+			msg.TimedMetricWithMetadatas.StagedMetadatas, msg.TimedMetricWithMetadatas.TimeNanos,
+			timed, defaultPolicies)
+	case encoding.ForwardedMetricWithMetadataType:
+		var (
+			meta       = msg.ForwardedMetricWithMetadata.ForwardMetadata
+			resolution = meta.StoragePolicy.Resolution().Window
+		)
+		if forwarded := flushTimes.ForwardedByResolution[int64(resolution)]; forwarded != nil {
+			if lastFlushedNanos, exists := forwarded.ByNumForwardedTimes[int32(meta.NumForwardedTimes)]; exists {
+				windowStartNanos := time.Unix(0, msg.ForwardedMetricWithMetadata.TimeNanos).
+					Truncate(resolution).UnixNano()
+				ok = !isForwardedMetricEarlierThan(windowStartNanos, resolution, lastFlushedNanos)
+			}
+		}
+	}
+	return msg, ok
+}
+
+// unflushedStagedMetadatas returns the metadata active at the given time without
+// the storage policies whose windows containing the time have been flushed, and
+// false if all of them have been flushed. The metadatas are returned as is if
+// none of the windows have been flushed.
+func unflushedStagedMetadatas(
+	metadatas metadata.StagedMetadatas,
+	timeNanos int64,
+	flushTimes map[int64]int64,
+	defaultPolicies policy.StoragePolicies,
+) (metadata.StagedMetadatas, bool) {
+	idx := len(metadatas) - 1
+	for idx >= 0 && metadatas[idx].CutoverNanos > timeNanos {
+		idx--
+	}
+	if idx < 0 || metadatas[idx].Tombstoned {
+		return metadatas, true
+	}
+
+	var (
+		active    = metadatas[idx]
+		pipelines = make(metadata.PipelineMetadatas, 0, len(active.Pipelines))
+		flushed   bool
+	)
+	for _, pipeline := range active.Pipelines {
+		if pipeline.DropPolicy != policy.DefaultDropPolicy {
+			pipelines = append(pipelines, pipeline)
+			continue
+		}
+		policies := pipeline.StoragePolicies
+		if policies.IsDefault() {
+			policies = defaultPolicies
+		}
+		unflushed := make(policy.StoragePolicies, 0, len(policies))
+		for _, sp := range policies {
+			if windowFlushed(sp, timeNanos, flushTimes) {
+				flushed = true
+				continue
+			}
+			unflushed = append(unflushed, sp)
+		}
+		// NB: pipelines without storage policies use the default ones, so the
+		// pipelines whose windows have all been flushed are removed instead.
+		if len(unflushed) > 0 {
+			pipeline.StoragePolicies = unflushed
+			pipelines = append(pipelines, pipeline)
+		}
+	}
+	if !flushed {
+		return metadatas, true
+	}
+	if len(pipelines) == 0 {
+		return nil, false
+	}
+	active.Pipelines = pipelines
+	return metadata.StagedMetadatas{active}, true
+}
+
+// windowFlushed returns true if the standard or timed window of the storage
+// policy containing the given time has been flushed according to the flush
+// times by resolution.
+func windowFlushed(
+	sp policy.StoragePolicy,
+	timeNanos int64,
+	flushTimes map[int64]int64,
+) bool {
+	resolution := sp.Resolution().Window
+	lastFlushedNanos, exists := flushTimes[int64(resolution)]
+	if !exists {
+		return false
+	}
+	windowStartNanos := time.Unix(0, timeNanos).Truncate(resolution).UnixNano()
+	return isStandardMetricEarlierThan(windowStartNanos, resolution, lastFlushedNanos)
+}
+
+// NB: metrics are journaled once they have been added so that rejected metrics
+// are not replayed. Failing to journal a metric does not fail the write, the
+// error is counted and the metric is only lost on a crash.
+func (s *aggregatorShard) journalUntimed(
+	mu unaggregated.MetricUnion,
+	metadatas metadata.StagedMetadatas,
+) error {
+	// NB: the timer values are returned to their pool once the metric has been
+	// added, so the return is deferred until the metric has been journaled.
+	timerValPool := mu.TimerValPool
+	mu.TimerValPool = nil
+	defer func() {
+		if mu.BatchTimerVal != nil && timerValPool != nil {
+			timerValPool.Put(mu.BatchTimerVal)
+		}
+	}()
+
+	if err := s.metricMap.AddUntimed(mu, metadatas); err != nil {
+		return err
+	}
+	msg := encoding.UnaggregatedMessageUnion{}
+	switch mu.Type {
+	case metric.CounterType:
+		msg.Type = encoding.CounterWithMetadatasType
+		msg.CounterWithMetadatas = unaggregated.CounterWithMetadatas{
+			Counter:         mu.Counter(),
+			StagedMetadatas: metadatas,
+		}
+	case metric.TimerType:
+		msg.Type = encoding.BatchTimerWithMetadatasType
+		msg.BatchTimerWithMetadatas = unaggregated.BatchTimerWithMetadatas{
+			BatchTimer:      mu.BatchTimer(),
+			StagedMetadatas: metadatas,
+		}
+	case metric.GaugeType:
+		msg.Type = encoding.GaugeWithMetadatasType
+		msg.GaugeWithMetadatas = unaggregated.GaugeWithMetadatas{
+			Gauge:           mu.Gauge(),
+			StagedMetadatas: metadatas,
+		}
+	case metric.HistogramType:
+		msg.Type = encoding.HistogramWithMetadatasType
+		msg.HistogramWithMetadatas = unaggregated.HistogramWithMetadatas{
+			Histogram:       mu.Histogram(),
+			StagedMetadatas: metadatas,
+		}
+	default:
+		return nil
+	}
+	s.journal(msg)
+	return nil
+}
+
+func (s *aggregatorShard) journalTimed(
+	metric aggregated.Metric,
+	metadata metadata.TimedMetadata,
+) error {
+	if err := s.metricMap.AddTimed(metric, metadata); err != nil {
+		return err
+	}
+	s.journal(encoding.UnaggregatedMessageUnion{
+		Type: encoding.TimedMetricWithMetadataType,
+		TimedMetricWithMetadata: aggregated.TimedMetricWithMetadata{
+			Metric:        metric,
+			TimedMetadata: metadata,
+		},
+	})
+	return nil
+}
+
+func (s *aggregatorShard) journalTimedWithStagedMetadatas(
+	metric aggregated.Metric,
+	metas metadata.StagedMetadatas,
+) error {
+	if err := s.metricMap.AddTimedWithStagedMetadatas(metric, metas); err != nil {
+		return err
+	}
+	s.journal(encoding.UnaggregatedMessageUnion{
+		Type: encoding.TimedMetricWithMetadatasType,
+		TimedMetricWithMetadatas: aggregated.TimedMetricWithMetadatas{
+			Metric:          metric,
+			StagedMetadatas: metas,
+		},
+	})
+	return nil
+}
+
+func (s *aggregatorShard) journalForwarded(
+	metric aggregated.ForwardedMetric,
+	metadata metadata.ForwardMetadata,
+) error {
+	if err := s.metricMap.AddForwarded(metric, metadata); err != nil {
+		return err
+	}
+	s.journal(encoding.UnaggregatedMessageUnion{
+		Type: encoding.ForwardedMetricWithMetadataType,
+		ForwardedMetricWithMetadata: aggregated.ForwardedMetricWithMetadata{
+			ForwardedMetric: metric,
+			ForwardMetadata: metadata,
+		},
+	})
+	return nil
+}
+
+func (s *aggregatorShard) journal(msg encoding.UnaggregatedMessageUnion) {
+	if err := s.wal.Write(s.shard, msg); err != nil {
+		s.metrics.walWriteErrors.Inc(1)
+	}
+}
+
+func (s *aggregatorShard) InProgressValues(metricID id.RawID) []InProgressDatapoint {
+	return s.metricMap.InProgressValues(metricID)
+}
+
+func (s *aggregatorShard) DebugElements(metricID id.RawID) []MetricDebugElement {
+	return s.metricMap.DebugElements(metricID)
+}
+
+// WindowDigests returns the digests of the windows of the shard flushed with
+// timestamps in [startNanos, endNanos).
+func (s *aggregatorShard) WindowDigests(startNanos, endNanos int64) []WindowDigest {
+	return s.metricMap.WindowDigests(startNanos, endNanos)
+}
+
 func (s *aggregatorShard) Tick(target time.Duration) tickResult {
-	return s.metricMap.Tick(target)
+	start := s.nowFn()
+	res := s.metricMap.Tick(target)
+	duration := s.nowFn().Sub(start)
+
+	s.Lock()
+	s.lastTickAtNanos = start.UnixNano()
+	s.lastTickDuration = duration
+	s.lastTickEntries = res.standard.activeEntries +
+		res.forwarded.activeEntries +
+		res.timed.activeEntries
+	s.lastTickExpiredEntries = res.standard.expiredEntries +
+		res.forwarded.expiredEntries +
+		res.timed.expiredEntries
+	s.Unlock()
+	return res
+}
+
+// Status returns the run-time status of the shard.
+func (s *aggregatorShard) Status() ShardStatus {
+	s.RLock()
+	defer s.RUnlock()
+
+	status := ShardStatus{
+		ShardID:               s.shard,
+		Writeable:             s.isWritableWithLock(),
+		IngestionPaused:       s.isIngestionPausedWithLock(),
+		CutoverTime:           time.Unix(0, s.cutoverNanos),
+		CutoffTime:            time.Unix(0, s.cutoffNanos),
+		EarliestWriteableTime: time.Unix(0, s.earliestWritableNanos),
+		LatestWriteableTime:   time.Unix(0, s.latestWriteableNanos),
+		Entries:               s.lastTickEntries,
+		ExpiredEntries:        s.lastTickExpiredEntries,
+		LastTickDuration:      s.lastTickDuration,
+	}
+	if s.lastTickAtNanos > 0 {
+		status.LastTickAt = time.Unix(0, s.lastTickAtNanos)
+	}
+	return status
 }
 
 func (s *aggregatorShard) Close() {
@@ -275,7 +801,25 @@ func (s *aggregatorShard) Close() {
 	s.metricMap.Close()
 }
 
+func (s *aggregatorShard) isIngestionPausedWithLock() bool {
+	return s.nowFn().UnixNano() < s.ingestionPausedUntilNanos
+}
+
+func (s *aggregatorShard) ingestionPauseWithLock() ShardIngestionPause {
+	return ShardIngestionPause{
+		ShardID:     s.shard,
+		PausedUntil: time.Unix(0, s.ingestionPausedUntilNanos),
+	}
+}
+
+func (s *aggregatorShard) ingestionPausedErrorWithLock() error {
+	return ShardIngestionPausedError{ShardIngestionPause: s.ingestionPauseWithLock()}
+}
+
 func (s *aggregatorShard) isWritableWithLock() bool {
+	if s.writesIgnoreCutoffCutoverFn != nil && s.writesIgnoreCutoffCutoverFn(s.shard) {
+		return true
+	}
 	nowNanos := s.nowFn().UnixNano()
 	return nowNanos >= s.earliestWritableNanos && nowNanos < s.latestWriteableNanos
 }
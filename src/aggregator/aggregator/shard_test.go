@@ -21,15 +21,26 @@
 package aggregator
 
 import (
+	"context"
+	"errors"
 	"math"
 	"testing"
 	"time"
 
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/aggregator/wal"
+	"github.com/m3db/m3/src/metrics/encoding"
 	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -265,6 +276,232 @@ func TestAggregatorShardAddForwardedSuccess(t *testing.T) {
 	require.Equal(t, testForwardMetadata, resultMetadata)
 }
 
+func TestAggregatorShardStatus(t *testing.T) {
+	opts := newTestOptions().
+		SetEntryCheckInterval(0).
+		SetBufferDurationBeforeShardCutover(time.Duration(500)).
+		SetBufferDurationAfterShardCutoff(time.Duration(1000))
+	shard := newAggregatorShard(testShard, opts)
+	now := time.Unix(0, 5000)
+	shard.nowFn = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	shard.SetWriteableRange(timeRange{cutoverNanos: 1000, cutoffNanos: 2000})
+
+	status := shard.Status()
+	require.Equal(t, ShardStatus{
+		ShardID:               testShard,
+		CutoverTime:           time.Unix(0, 1000),
+		CutoffTime:            time.Unix(0, 2000),
+		EarliestWriteableTime: time.Unix(0, 500),
+		LatestWriteableTime:   time.Unix(0, 3000),
+	}, status)
+
+	tickStart := now.Add(time.Second)
+	shard.Tick(0)
+	status = shard.Status()
+	require.Equal(t, tickStart, status.LastTickAt)
+	require.Equal(t, time.Second, status.LastTickDuration)
+	require.Equal(t, 0, status.Entries)
+	require.Equal(t, 0, status.ExpiredEntries)
+}
+
+type testPeerReplayer struct {
+	calls      int
+	shard      uint32
+	start, end time.Time
+	msgs       []encoding.UnaggregatedMessageUnion
+}
+
+func (r *testPeerReplayer) Replay(
+	_ context.Context,
+	shard uint32,
+	start, end time.Time,
+	fn wal.ReplayFn,
+) error {
+	r.calls++
+	r.shard, r.start, r.end = shard, start, end
+	for _, msg := range r.msgs {
+		if err := fn(msg, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAggregatorShardReplayFromPeers(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts := newTestOptions().
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now }))
+	shard := newAggregatorShard(testShard, opts)
+	replayer := &testPeerReplayer{
+		msgs: []encoding.UnaggregatedMessageUnion{{Type: encoding.UnknownMessageType}},
+	}
+
+	// The traffic before the shard was opened is replayed.
+	now = now.Add(time.Minute)
+	replayed, failed, err := shard.ReplayFromPeers(context.Background(), replayer, time.Hour, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, replayed)
+	require.Equal(t, 1, failed)
+	require.Equal(t, 1, replayer.calls)
+	require.Equal(t, testShard, replayer.shard)
+	require.Equal(t, time.Unix(1000, 0).Add(-time.Hour), replayer.start)
+	require.Equal(t, time.Unix(1000, 0), replayer.end)
+
+	// Shards opened earlier than the window ago are complete.
+	replayed, failed, err = shard.ReplayFromPeers(context.Background(), replayer, time.Minute, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, replayed+failed)
+	require.Equal(t, 1, replayer.calls)
+
+	// Shards recovered from the write-ahead log are complete.
+	shard.recoveredFromWAL = true
+	replayed, failed, err = shard.ReplayFromPeers(context.Background(), replayer, time.Hour, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, replayed+failed)
+	require.Equal(t, 1, replayer.calls)
+}
+
+type testWAL struct {
+	err  error
+	msgs []encoding.UnaggregatedMessageUnion
+}
+
+func (w *testWAL) Write(_ uint32, msg encoding.UnaggregatedMessageUnion) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msg)
+	return nil
+}
+
+func (w *testWAL) Replay(uint32, wal.ReplayFn) error { return nil }
+func (w *testWAL) Close() error                      { return nil }
+
+func TestAggregatorShardJournalsAddedMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now   = time.Unix(1000, 0)
+		w     = &testWAL{}
+		scope = tally.NewTestScope("", nil)
+		opts  = testOptions(ctrl).
+			SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now })).
+			SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope)).
+			SetWAL(w)
+		shard = newAggregatorShard(testShard, opts)
+	)
+	shard.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: math.MaxInt64})
+
+	counter := unaggregated.MetricUnion{
+		Type:       metric.CounterType,
+		ID:         []byte("foo"),
+		CounterVal: 1,
+		Annotation: []byte("annotation"),
+	}
+	require.NoError(t, shard.AddUntimed(counter, metadata.DefaultStagedMetadatas))
+	require.Len(t, w.msgs, 1)
+	require.Equal(t, encoding.CounterWithMetadatasType, w.msgs[0].Type)
+	require.Equal(t, []byte("annotation"), w.msgs[0].CounterWithMetadatas.Annotation)
+
+	// Metrics rejected by the metric map are not journaled.
+	require.Error(t, shard.AddUntimed(counter, metadata.StagedMetadatas{}))
+	require.Len(t, w.msgs, 1)
+
+	// Errors journaling a metric do not fail the write and are counted.
+	w.err = errors.New("write error")
+	require.NoError(t, shard.AddUntimed(counter, metadata.DefaultStagedMetadatas))
+	require.Len(t, w.msgs, 1)
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["shard.wal-write-errors+shard=0"].Value())
+}
+
+func TestUnflushedMessage(t *testing.T) {
+	var (
+		journaledAt = time.Unix(0, int64(90*time.Second))
+		tenSeconds  = policy.MustParseStoragePolicy("10s:2d")
+		oneMinute   = policy.MustParseStoragePolicy("1m:40d")
+		flushTimes  = &schema.ShardFlushTimes{
+			// The 10s window [90s, 100s) has been flushed, the 1m window
+			// [60s, 120s) has not.
+			StandardByResolution: map[int64]int64{
+				int64(10 * time.Second): int64(100 * time.Second),
+				int64(time.Minute):      int64(60 * time.Second),
+			},
+			TimedByResolution: map[int64]int64{
+				int64(10 * time.Second): int64(100 * time.Second),
+			},
+			ForwardedByResolution: map[int64]*schema.ForwardedFlushTimesForResolution{
+				int64(time.Minute): {ByNumForwardedTimes: map[int32]int64{1: int64(90 * time.Second)}},
+			},
+		}
+		counter = func(policies ...policy.StoragePolicy) encoding.UnaggregatedMessageUnion {
+			return encoding.UnaggregatedMessageUnion{
+				Type: encoding.CounterWithMetadatasType,
+				CounterWithMetadatas: unaggregated.CounterWithMetadatas{
+					Counter: unaggregated.Counter{ID: []byte("foo"), Value: 1},
+					StagedMetadatas: metadata.StagedMetadatas{{
+						Metadata: metadata.Metadata{Pipelines: metadata.PipelineMetadatas{{
+							StoragePolicies: policies,
+						}}},
+					}},
+				},
+			}
+		}
+	)
+
+	// Messages are replayed as is without flush times.
+	msg, ok := unflushedMessage(counter(tenSeconds), journaledAt, nil, nil)
+	require.True(t, ok)
+	require.Equal(t, counter(tenSeconds), msg)
+
+	// The storage policies whose windows have been flushed are removed.
+	msg, ok = unflushedMessage(counter(tenSeconds, oneMinute), journaledAt, flushTimes, nil)
+	require.True(t, ok)
+	require.Equal(t, counter(oneMinute), msg)
+
+	// The default storage policies are expanded to remove the flushed ones.
+	msg, ok = unflushedMessage(counter(), journaledAt, flushTimes,
+		policy.StoragePolicies{tenSeconds, oneMinute})
+	require.True(t, ok)
+	require.Equal(t, counter(oneMinute), msg)
+
+	// Messages are skipped if all of their windows have been flushed.
+	_, ok = unflushedMessage(counter(tenSeconds), journaledAt, flushTimes, nil)
+	require.False(t, ok)
+	_, ok = unflushedMessage(counter(oneMinute), journaledAt.Add(-time.Minute), flushTimes, nil)
+	require.False(t, ok)
+
+	timed := encoding.UnaggregatedMessageUnion{
+		Type: encoding.TimedMetricWithMetadataType,
+		TimedMetricWithMetadata: aggregated.TimedMetricWithMetadata{
+			Metric:        aggregated.Metric{ID: []byte("bar"), TimeNanos: int64(95 * time.Second)},
+			TimedMetadata: metadata.TimedMetadata{StoragePolicy: tenSeconds},
+		},
+	}
+	_, ok = unflushedMessage(timed, journaledAt, flushTimes, nil)
+	require.False(t, ok)
+	timed.TimedMetricWithMetadata.TimeNanos = int64(100 * time.Second)
+	_, ok = unflushedMessage(timed, journaledAt, flushTimes, nil)
+	require.True(t, ok)
+
+	forwarded := encoding.UnaggregatedMessageUnion{
+		Type: encoding.ForwardedMetricWithMetadataType,
+		ForwardedMetricWithMetadata: aggregated.ForwardedMetricWithMetadata{
+			ForwardedMetric: aggregated.ForwardedMetric{ID: []byte("baz"), TimeNanos: int64(60 * time.Second)},
+			ForwardMetadata: metadata.ForwardMetadata{StoragePolicy: oneMinute, NumForwardedTimes: 1},
+		},
+	}
+	_, ok = unflushedMessage(forwarded, journaledAt, flushTimes, nil)
+	require.False(t, ok)
+	forwarded.ForwardedMetricWithMetadata.NumForwardedTimes = 2
+	_, ok = unflushedMessage(forwarded, journaledAt, flushTimes, nil)
+	require.True(t, ok)
+}
+
 func TestAggregatorShardClose(t *testing.T) {
 	shard := newAggregatorShard(testShard, newTestOptions())
 
@@ -54,6 +54,9 @@ type tickResult struct {
 	standard  tickResultForMetricCategory
 	forwarded tickResultForMetricCategory
 	timed     tickResultForMetricCategory
+	// paced is the time spent sleeping to spread the tick over its target
+	// duration.
+	paced time.Duration
 }
 
 // merge merges two results. Both input results may become invalid after merge is called.
@@ -62,5 +65,6 @@ func (r *tickResult) merge(other tickResult) tickResult {
 		standard:  r.standard.merge(other.standard),
 		forwarded: r.forwarded.merge(other.forwarded),
 		timed:     r.timed.merge(other.timed),
+		paced:     r.paced + other.paced,
 	}
 }
@@ -0,0 +1,431 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/policy"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+var (
+	errNoPeerDigestFetcher               = errors.New("no peer digest fetcher set")
+	errInvalidVerificationInterval       = errors.New("verification interval must be positive")
+	errInvalidVerificationLag            = errors.New("verification lag must be positive")
+	errInvalidVerificationRetention      = errors.New("verification retention must be greater than the lag")
+	errInvalidVerificationTimeout        = errors.New("verification timeout must be positive")
+	errInvalidVerificationValueTolerance = errors.New("verification value tolerance must be in [0, 1)")
+	errVerificationNotEnabled            = errors.New("aggregator verification mode is not enabled")
+)
+
+// WindowDigest summarizes the aggregated metrics an aggregator flushed, or
+// discarded as a follower, for an aggregation window of a storage policy
+// without carrying the metrics themselves.
+type WindowDigest struct {
+	StoragePolicy policy.StoragePolicy
+	// TimeNanos is the timestamp of the metrics of the window.
+	TimeNanos int64
+	// Count is the number of metrics of the window.
+	Count int64
+	// Hash is the sum of the hashes of the IDs of the metrics of the window,
+	// and of their values that are not finite, so it does not depend on the
+	// order they were flushed in.
+	Hash uint64
+	// ValueSum is the sum of the finite values of the metrics of the window
+	// weighted by the hashes of their IDs, so that values swapped between
+	// metrics are told apart.
+	ValueSum float64
+	// AbsValueSum is the weighted sum of the absolute finite values, which
+	// the tolerance of the comparison of the value sums is relative to.
+	AbsValueSum float64
+}
+
+// PeerDigestFetcher fetches the window digests computed by the peers of an
+// aggregator, the other instances of its shard set.
+type PeerDigestFetcher interface {
+	// Fetch returns the digests of the windows of the shard with timestamps
+	// between start and end computed by each peer, keyed by peer. Digests of
+	// the peers that responded are returned along with any error.
+	Fetch(
+		ctx context.Context,
+		shard uint32,
+		start, end time.Time,
+	) (map[string][]WindowDigest, error)
+}
+
+// VerificationOptions configure the verification mode, in which every instance
+// computes digests of the aggregation windows it flushes, or discards as a
+// follower, and the leader periodically compares its digests with the ones of
+// its followers to validate that they would have produced the same output.
+type VerificationOptions struct {
+	// Fetcher fetches the digests computed by the followers.
+	Fetcher PeerDigestFetcher
+	// Interval is how often the leader compares digests.
+	Interval time.Duration
+	// Lag is how long the leader waits after a window before comparing its
+	// digest, which must leave the followers time to discard the window once
+	// the leader persisted its flush times.
+	Lag time.Duration
+	// Retention is how long digests are kept after their window.
+	Retention time.Duration
+	// Timeout bounds how long fetching the digests of a shard takes.
+	Timeout time.Duration
+	// ValueTolerance is the difference between the value sums of the digests
+	// of a window, relative to their absolute value sums, below which they
+	// match. Leader and followers receive the traffic in different orders, so
+	// sums, means and quantiles may differ in their low bits.
+	ValueTolerance float64
+}
+
+// Validate validates the options.
+func (o VerificationOptions) Validate() error {
+	if o.Fetcher == nil {
+		return errNoPeerDigestFetcher
+	}
+	if o.Interval <= 0 {
+		return errInvalidVerificationInterval
+	}
+	if o.Lag <= 0 {
+		return errInvalidVerificationLag
+	}
+	if o.Retention <= o.Lag {
+		return errInvalidVerificationRetention
+	}
+	if o.Timeout <= 0 {
+		return errInvalidVerificationTimeout
+	}
+	if o.ValueTolerance < 0 || o.ValueTolerance >= 1 {
+		return errInvalidVerificationValueTolerance
+	}
+	return nil
+}
+
+type windowDigestKey struct {
+	storagePolicy policy.StoragePolicy
+	timeNanos     int64
+}
+
+type windowDigest struct {
+	count       int64
+	hash        uint64
+	valueSum    float64
+	absValueSum float64
+}
+
+// windowDigests accumulates the digests of the windows flushed by a metric list.
+type windowDigests struct {
+	sync.Mutex
+
+	retention time.Duration
+	byKey     map[windowDigestKey]windowDigest
+	buf       []byte
+}
+
+func newWindowDigests(retention time.Duration) *windowDigests {
+	return &windowDigests{
+		retention: retention,
+		byKey:     make(map[windowDigestKey]windowDigest),
+	}
+}
+
+// Add adds a flushed metric to the digest of its window.
+func (d *windowDigests) Add(
+	idPrefix, id, idSuffix []byte,
+	timeNanos int64,
+	value float64,
+	sp policy.StoragePolicy,
+) {
+	d.Lock()
+	d.buf = append(d.buf[:0], idPrefix...)
+	d.buf = append(d.buf, id...)
+	d.buf = append(d.buf, idSuffix...)
+	idHash := xxhash.Sum64(d.buf)
+
+	key := windowDigestKey{storagePolicy: sp, timeNanos: timeNanos}
+	digest := d.byKey[key]
+	digest.count++
+	digest.hash += idHash
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		// Values that are not finite are not added up, they are equal
+		// regardless of the order of the traffic so are hashed instead.
+		var valueBytes [8]byte
+		binary.LittleEndian.PutUint64(valueBytes[:], math.Float64bits(value))
+		d.buf = append(d.buf, valueBytes[:]...)
+		digest.hash += xxhash.Sum64(d.buf)
+	} else {
+		weight := valueWeight(idHash)
+		digest.valueSum += weight * value
+		digest.absValueSum += weight * math.Abs(value)
+	}
+	d.byKey[key] = digest
+	d.Unlock()
+}
+
+// valueWeight derives the weight of the value of a metric in [1, 2) from the
+// hash of its ID.
+func valueWeight(idHash uint64) float64 {
+	return 1 + float64(idHash>>11)/(1<<53)
+}
+
+// Expire removes the digests of the windows past the retention.
+func (d *windowDigests) Expire(nowNanos int64) {
+	expireBeforeNanos := nowNanos - int64(d.retention)
+	d.Lock()
+	for key := range d.byKey {
+		if key.timeNanos < expireBeforeNanos {
+			delete(d.byKey, key)
+		}
+	}
+	d.Unlock()
+}
+
+// MergeTo adds the digests of the windows with timestamps in [startNanos,
+// endNanos) to the merged digests.
+func (d *windowDigests) MergeTo(
+	startNanos, endNanos int64,
+	merged map[windowDigestKey]windowDigest,
+) {
+	d.Lock()
+	for key, digest := range d.byKey {
+		if key.timeNanos < startNanos || key.timeNanos >= endNanos {
+			continue
+		}
+		mergedDigest := merged[key]
+		mergedDigest.count += digest.count
+		mergedDigest.hash += digest.hash
+		mergedDigest.valueSum += digest.valueSum
+		mergedDigest.absValueSum += digest.absValueSum
+		merged[key] = mergedDigest
+	}
+	d.Unlock()
+}
+
+func windowDigestsFromMap(digests map[windowDigestKey]windowDigest) []WindowDigest {
+	if len(digests) == 0 {
+		return nil
+	}
+	result := make([]WindowDigest, 0, len(digests))
+	for key, digest := range digests {
+		result = append(result, WindowDigest{
+			StoragePolicy: key.storagePolicy,
+			TimeNanos:     key.timeNanos,
+			Count:         digest.count,
+			Hash:          digest.hash,
+			ValueSum:      digest.valueSum,
+			AbsValueSum:   digest.absValueSum,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TimeNanos != result[j].TimeNanos {
+			return result[i].TimeNanos < result[j].TimeNanos
+		}
+		return result[i].StoragePolicy.String() < result[j].StoragePolicy.String()
+	})
+	return result
+}
+
+type windowVerificationResult int
+
+const (
+	windowMatched windowVerificationResult = iota
+	windowDiverged
+	windowMissingOnPeer
+	windowMissingOnLeader
+)
+
+// verificationResult is the outcome of comparing the digests of a shard with
+// the ones of a peer.
+type verificationResult struct {
+	matched, diverged, missingOnPeer, missingOnLeader int
+}
+
+// compareWindowDigests compares the digests of the leader with the ones of a
+// peer, the value sums of a window matching if they differ by less than the
+// tolerance relative to the absolute value sums.
+func compareWindowDigests(leader, peer []WindowDigest, valueTolerance float64) verificationResult {
+	var (
+		result verificationResult
+		byKey  = make(map[windowDigestKey]WindowDigest, len(peer))
+	)
+	for _, digest := range peer {
+		byKey[windowDigestKey{storagePolicy: digest.StoragePolicy, timeNanos: digest.TimeNanos}] = digest
+	}
+	for _, digest := range leader {
+		key := windowDigestKey{storagePolicy: digest.StoragePolicy, timeNanos: digest.TimeNanos}
+		peerDigest, ok := byKey[key]
+		if !ok {
+			result.missingOnPeer++
+			continue
+		}
+		delete(byKey, key)
+		if peerDigest.Count == digest.Count && peerDigest.Hash == digest.Hash &&
+			valuesMatch(digest, peerDigest, valueTolerance) {
+			result.matched++
+		} else {
+			result.diverged++
+		}
+	}
+	result.missingOnLeader = len(byKey)
+	return result
+}
+
+func valuesMatch(leader, peer WindowDigest, tolerance float64) bool {
+	maxAbsValueSum := math.Max(leader.AbsValueSum, peer.AbsValueSum)
+	return math.Abs(leader.ValueSum-peer.ValueSum) <= tolerance*maxAbsValueSum
+}
+
+type verificationMetrics struct {
+	matched         tally.Counter
+	diverged        tally.Counter
+	missingOnPeer   tally.Counter
+	missingOnLeader tally.Counter
+	fetchErrors     tally.Counter
+}
+
+func newVerificationMetrics(scope tally.Scope) verificationMetrics {
+	windowsFor := func(result string) tally.Counter {
+		return scope.Tagged(map[string]string{"result": result}).Counter("windows")
+	}
+	return verificationMetrics{
+		matched:         windowsFor("matched"),
+		diverged:        windowsFor("diverged"),
+		missingOnPeer:   windowsFor("missing-on-peer"),
+		missingOnLeader: windowsFor("missing-on-leader"),
+		fetchErrors:     scope.Counter("fetch-errors"),
+	}
+}
+
+func (m verificationMetrics) report(result verificationResult) {
+	m.matched.Inc(int64(result.matched))
+	m.diverged.Inc(int64(result.diverged))
+	m.missingOnPeer.Inc(int64(result.missingOnPeer))
+	m.missingOnLeader.Inc(int64(result.missingOnLeader))
+}
+
+// WindowDigests returns the digests of the windows of an owned shard with
+// timestamps between start and end.
+func (agg *aggregator) WindowDigests(shardID uint32, start, end time.Time) ([]WindowDigest, error) {
+	if agg.opts.VerificationOptions() == nil {
+		return nil, errVerificationNotEnabled
+	}
+
+	agg.RLock()
+	defer agg.RUnlock()
+
+	if agg.state != aggregatorOpen {
+		return nil, errAggregatorNotOpenOrClosed
+	}
+	shard, ok := agg.ownedShardWithLock(shardID)
+	if !ok {
+		return nil, fmt.Errorf("%w: shard=%d", errShardNotOwned, shardID)
+	}
+	return shard.WindowDigests(start.UnixNano(), end.UnixNano()), nil
+}
+
+func (agg *aggregator) verificationTick(opts VerificationOptions) {
+	defer agg.wg.Done()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var (
+		metrics       = newVerificationMetrics(agg.opts.InstrumentOptions().MetricsScope().SubScope("verification"))
+		verifiedUntil time.Time
+	)
+	for {
+		select {
+		case <-ticker.C:
+		case <-agg.doneCh:
+			return
+		}
+		verifiedUntil = agg.verify(opts, verifiedUntil, metrics)
+	}
+}
+
+// verify compares the digests of the windows of the owned shards since the
+// last verification with the ones of the followers if the aggregator is the
+// leader, and returns the time the windows were verified until.
+func (agg *aggregator) verify(
+	opts VerificationOptions,
+	verifiedUntil time.Time,
+	metrics verificationMetrics,
+) time.Time {
+	var (
+		now   = agg.nowFn()
+		start = verifiedUntil
+		end   = now.Add(-opts.Lag)
+	)
+	// Digests past the retention are no longer available to compare.
+	if earliest := now.Add(-opts.Retention); start.Before(earliest) {
+		start = earliest
+	}
+	if !end.After(start) || agg.electionManager.ElectionState() != LeaderState {
+		return end
+	}
+
+	agg.RLock()
+	shards := make([]*aggregatorShard, 0, len(agg.shardIDs))
+	for _, shardID := range agg.shardIDs {
+		if shard, ok := agg.ownedShardWithLock(shardID); ok {
+			shards = append(shards, shard)
+		}
+	}
+	agg.RUnlock()
+
+	for _, shard := range shards {
+		digests := shard.WindowDigests(start.UnixNano(), end.UnixNano())
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		peerDigests, err := opts.Fetcher.Fetch(ctx, shard.ID(), start, end)
+		cancel()
+		if err != nil {
+			metrics.fetchErrors.Inc(1)
+			agg.logger.Error("error fetching digests from peers",
+				zap.Uint32("shard", shard.ID()), zap.Error(err))
+		}
+		for peer, peerShardDigests := range peerDigests {
+			result := compareWindowDigests(digests, peerShardDigests, opts.ValueTolerance)
+			metrics.report(result)
+			if result.diverged > 0 {
+				agg.logger.Warn("aggregation windows diverged from peer",
+					zap.Uint32("shard", shard.ID()),
+					zap.String("peer", peer),
+					zap.Time("start", start),
+					zap.Time("end", end),
+					zap.Int("matched", result.matched),
+					zap.Int("diverged", result.diverged),
+					zap.Int("missingOnPeer", result.missingOnPeer),
+					zap.Int("missingOnLeader", result.missingOnLeader))
+			}
+		}
+	}
+	return end
+}
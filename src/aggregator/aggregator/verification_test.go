@@ -0,0 +1,288 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type peerDigestFetcherFn func(
+	ctx context.Context,
+	shard uint32,
+	start, end time.Time,
+) (map[string][]WindowDigest, error)
+
+func (fn peerDigestFetcherFn) Fetch(
+	ctx context.Context,
+	shard uint32,
+	start, end time.Time,
+) (map[string][]WindowDigest, error) {
+	return fn(ctx, shard, start, end)
+}
+
+func TestVerificationOptionsValidate(t *testing.T) {
+	fetcher := peerDigestFetcherFn(func(context.Context, uint32, time.Time, time.Time) (map[string][]WindowDigest, error) {
+		return nil, nil
+	})
+	valid := VerificationOptions{
+		Fetcher:   fetcher,
+		Interval:  time.Minute,
+		Lag:       time.Minute,
+		Retention: 10 * time.Minute,
+		Timeout:   10 * time.Second,
+	}
+	require.NoError(t, valid.Validate())
+
+	opts := valid
+	opts.Fetcher = nil
+	require.Equal(t, errNoPeerDigestFetcher, opts.Validate())
+
+	opts = valid
+	opts.Interval = 0
+	require.Equal(t, errInvalidVerificationInterval, opts.Validate())
+
+	opts = valid
+	opts.Lag = 0
+	require.Equal(t, errInvalidVerificationLag, opts.Validate())
+
+	opts = valid
+	opts.Retention = opts.Lag
+	require.Equal(t, errInvalidVerificationRetention, opts.Validate())
+
+	opts = valid
+	opts.Timeout = 0
+	require.Equal(t, errInvalidVerificationTimeout, opts.Validate())
+
+	opts = valid
+	opts.ValueTolerance = -0.1
+	require.Equal(t, errInvalidVerificationValueTolerance, opts.Validate())
+}
+
+func TestWindowDigestsOrderIndependent(t *testing.T) {
+	var (
+		sp    = policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+		first = newWindowDigests(time.Hour)
+		other = newWindowDigests(time.Hour)
+	)
+	first.Add(nil, []byte("foo"), nil, 10, 1.0, sp)
+	first.Add([]byte("pre."), []byte("bar"), nil, 10, 2.0, sp)
+	first.Add(nil, []byte("baz"), nil, 20, 3.0, sp)
+	other.Add(nil, []byte("baz"), nil, 20, 3.0, sp)
+	other.Add([]byte("pre."), []byte("bar"), nil, 10, 2.0, sp)
+	other.Add(nil, []byte("foo"), nil, 10, 1.0, sp)
+
+	merged := make(map[windowDigestKey]windowDigest)
+	first.MergeTo(0, 100, merged)
+	digests := windowDigestsFromMap(merged)
+	require.Equal(t, 2, len(digests))
+	require.Equal(t, int64(10), digests[0].TimeNanos)
+	require.Equal(t, int64(2), digests[0].Count)
+	require.Equal(t, int64(20), digests[1].TimeNanos)
+	require.Equal(t, int64(1), digests[1].Count)
+
+	merged = make(map[windowDigestKey]windowDigest)
+	other.MergeTo(0, 100, merged)
+	require.Equal(t, digests, windowDigestsFromMap(merged))
+
+	// A different value changes the value sums of the window.
+	other.Add(nil, []byte("qux"), nil, 30, 4.0, sp)
+	first.Add(nil, []byte("qux"), nil, 30, 5.0, sp)
+	require.Equal(t, verificationResult{matched: 2, diverged: 1}, compareWindowDigests(
+		first.windowDigestsBetween(0, 100), other.windowDigestsBetween(0, 100), 1e-6))
+
+	// Windows are only merged between the bounds and expired past the retention.
+	require.Equal(t, 1, len(first.windowDigestsBetween(20, 30)))
+	first.Expire(int64(time.Hour) + 15)
+	require.Equal(t, 2, len(first.windowDigestsBetween(0, 100)))
+}
+
+func (d *windowDigests) windowDigestsBetween(startNanos, endNanos int64) []WindowDigest {
+	merged := make(map[windowDigestKey]windowDigest)
+	d.MergeTo(startNanos, endNanos, merged)
+	return windowDigestsFromMap(merged)
+}
+
+func TestCompareWindowDigests(t *testing.T) {
+	var (
+		sp     = policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+		leader = []WindowDigest{
+			{StoragePolicy: sp, TimeNanos: 10, Count: 1, Hash: 1},
+			{StoragePolicy: sp, TimeNanos: 20, Count: 1, Hash: 2},
+			{StoragePolicy: sp, TimeNanos: 30, Count: 1, Hash: 3},
+		}
+		peer = []WindowDigest{
+			{StoragePolicy: sp, TimeNanos: 10, Count: 1, Hash: 1},
+			{StoragePolicy: sp, TimeNanos: 20, Count: 2, Hash: 2},
+			{StoragePolicy: sp, TimeNanos: 40, Count: 1, Hash: 4},
+		}
+	)
+	require.Equal(t, verificationResult{
+		matched:         1,
+		diverged:        1,
+		missingOnPeer:   1,
+		missingOnLeader: 1,
+	}, compareWindowDigests(leader, peer, 1e-6))
+}
+
+func TestWindowDigestsValueTolerance(t *testing.T) {
+	var (
+		sp     = policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+		first  = newWindowDigests(time.Hour)
+		other  = newWindowDigests(time.Hour)
+		values = []float64{0.1, 0.2, 0.3, 1e6, math.NaN()}
+	)
+	// Values differing in their low bits, as when aggregated in a different
+	// order, match within the tolerance.
+	for i, value := range values {
+		id := []byte(fmt.Sprintf("foo%d", i))
+		first.Add(nil, id, nil, 10, value, sp)
+		other.Add(nil, id, nil, 10, math.Nextafter(value, math.Inf(1)), sp)
+	}
+	require.Equal(t, verificationResult{matched: 1}, compareWindowDigests(
+		first.windowDigestsBetween(0, 100), other.windowDigestsBetween(0, 100), 1e-9))
+
+	// Values differing beyond the tolerance diverge.
+	first.Add(nil, []byte("foo"), nil, 40, 1.0, sp)
+	other.Add(nil, []byte("foo"), nil, 40, 1.01, sp)
+	require.Equal(t, verificationResult{diverged: 1}, compareWindowDigests(
+		first.windowDigestsBetween(40, 50), other.windowDigestsBetween(40, 50), 1e-3))
+
+	// Values swapped between metrics are told apart.
+	first.Add(nil, []byte("bar"), nil, 20, 1, sp)
+	first.Add(nil, []byte("baz"), nil, 20, 2, sp)
+	other.Add(nil, []byte("bar"), nil, 20, 2, sp)
+	other.Add(nil, []byte("baz"), nil, 20, 1, sp)
+	require.Equal(t, verificationResult{diverged: 1}, compareWindowDigests(
+		first.windowDigestsBetween(20, 30), other.windowDigestsBetween(20, 30), 1e-9))
+
+	// Values that are not finite must be the same.
+	first.Add(nil, []byte("qux"), nil, 30, math.Inf(1), sp)
+	other.Add(nil, []byte("qux"), nil, 30, math.MaxFloat64, sp)
+	require.Equal(t, verificationResult{diverged: 1}, compareWindowDigests(
+		first.windowDigestsBetween(30, 40), other.windowDigestsBetween(30, 40), 0.5))
+}
+
+func TestBaseMetricListWindowDigests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sp := policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+	opts := testOptions(ctrl).SetVerificationOptions(&VerificationOptions{Retention: time.Hour})
+	l, err := newBaseMetricList(testShard, 10*time.Second, nil, nil, nil, opts)
+	require.NoError(t, err)
+
+	// Followers digest the metrics they discard like the leader digests the
+	// metrics it flushes.
+	l.discardLocalMetric(nil, []byte("foo"), nil, 10, 1.0, nil, nil, aggregation.Sum, sp)
+	merged := make(map[windowDigestKey]windowDigest)
+	l.MergeWindowDigests(0, 100, merged)
+	require.Equal(t, 1, len(merged))
+
+	// No digests are computed when the verification mode is disabled.
+	l, err = newBaseMetricList(testShard, 10*time.Second, nil, nil, nil, testOptions(ctrl))
+	require.NoError(t, err)
+	l.discardLocalMetric(nil, []byte("foo"), nil, 10, 1.0, nil, nil, aggregation.Sum, sp)
+	merged = make(map[windowDigestKey]windowDigest)
+	l.MergeWindowDigests(0, 100, merged)
+	require.Equal(t, 0, len(merged))
+}
+
+func TestAggregatorVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now   = time.Unix(1000, 0)
+		sp    = policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour)
+		start time.Time
+		end   time.Time
+	)
+	fetcher := peerDigestFetcherFn(func(
+		_ context.Context,
+		shard uint32,
+		fetchStart, fetchEnd time.Time,
+	) (map[string][]WindowDigest, error) {
+		require.Equal(t, uint32(1), shard)
+		start, end = fetchStart, fetchEnd
+		return map[string][]WindowDigest{
+			"peer": {{StoragePolicy: sp, TimeNanos: now.Add(-2 * time.Minute).UnixNano(), Count: 2, Hash: 1}},
+		}, errors.New("unreachable peer")
+	})
+	verificationOpts := VerificationOptions{
+		Fetcher:   fetcher,
+		Interval:  time.Minute,
+		Lag:       time.Minute,
+		Retention: 10 * time.Minute,
+		Timeout:   time.Second,
+	}
+	opts := testOptions(ctrl).SetVerificationOptions(&verificationOpts)
+
+	shard := newAggregatorShard(1, opts)
+	list, err := shard.metricMap.metricLists.FindOrCreate(standardMetricListID{resolution: 10 * time.Second}.toMetricListID())
+	require.NoError(t, err)
+	list.(*standardMetricList).discardLocalMetric(nil, []byte("foo"), nil,
+		now.Add(-2*time.Minute).UnixNano(), 1.0, nil, nil, aggregation.Sum, sp)
+
+	electionManager := NewMockElectionManager(ctrl)
+	electionManager.EXPECT().ElectionState().Return(LeaderState)
+	agg := &aggregator{
+		opts:            opts,
+		nowFn:           func() time.Time { return now },
+		electionManager: electionManager,
+		shardIDs:        []uint32{1},
+		shards:          []*aggregatorShard{nil, shard},
+		logger:          instrument.NewOptions().Logger(),
+	}
+
+	scope := tally.NewTestScope("", nil)
+	verifiedUntil := agg.verify(verificationOpts, time.Time{}, newVerificationMetrics(scope))
+	require.Equal(t, now.Add(-time.Minute), verifiedUntil)
+	require.Equal(t, now.Add(-10*time.Minute), start)
+	require.Equal(t, now.Add(-time.Minute), end)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["windows+result=diverged"].Value())
+	require.Equal(t, int64(0), counters["windows+result=matched"].Value())
+	require.Equal(t, int64(1), counters["fetch-errors+"].Value())
+}
+
+func TestAggregatorWindowDigestsNotEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, _ := testAggregator(t, ctrl)
+	_, err := agg.WindowDigests(1, time.Unix(0, 0), time.Unix(60, 0))
+	require.Equal(t, errVerificationNotEnabled, err)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultCircuitBreakerFailureThreshold = 5
+	defaultCircuitBreakerCooldown         = 30 * time.Second
+)
+
+var (
+	errInvalidCircuitBreakerFailureThreshold = errors.New("circuit breaker failure threshold must be positive")
+	errInvalidCircuitBreakerCooldown         = errors.New("circuit breaker cooldown must be positive")
+)
+
+// InstanceWriteResultFn is called with the result of every write of queued
+// buffers to the connection of an instance.
+type InstanceWriteResultFn func(instance placement.Instance, err error)
+
+// CircuitBreakerOptions configure the ejection of consistently failing
+// instances from the write path.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed writes to an
+	// instance after which the instance is ejected.
+	FailureThreshold int
+	// Cooldown is how long an ejected instance is skipped for before writes
+	// are attempted against it again.
+	Cooldown time.Duration
+}
+
+// NewCircuitBreakerOptions returns the default circuit breaker options.
+func NewCircuitBreakerOptions() CircuitBreakerOptions {
+	return CircuitBreakerOptions{
+		FailureThreshold: defaultCircuitBreakerFailureThreshold,
+		Cooldown:         defaultCircuitBreakerCooldown,
+	}
+}
+
+// Validate validates the circuit breaker options.
+func (o CircuitBreakerOptions) Validate() error {
+	if o.FailureThreshold <= 0 {
+		return errInvalidCircuitBreakerFailureThreshold
+	}
+	if o.Cooldown <= 0 {
+		return errInvalidCircuitBreakerCooldown
+	}
+	return nil
+}
+
+type circuitBreakerMetrics struct {
+	ejections  tally.Counter
+	recoveries tally.Counter
+	ejected    tally.Gauge
+}
+
+func newCircuitBreakerMetrics(scope tally.Scope) circuitBreakerMetrics {
+	return circuitBreakerMetrics{
+		ejections:  scope.Counter("ejections"),
+		recoveries: scope.Counter("recoveries"),
+		ejected:    scope.Gauge("ejected"),
+	}
+}
+
+type instanceBreakerState struct {
+	consecutiveFailures atomic.Int64
+	// ejectedUntilNanos is zero unless the instance is ejected. It is kept once
+	// the cooldown expires so that the instance is ejected again right away if
+	// the next write fails, and is only reset by a successful write.
+	ejectedUntilNanos atomic.Int64
+}
+
+// instanceCircuitBreaker tracks the consecutive write failures of each
+// instance and ejects the instances that keep failing for a cooldown period.
+type instanceCircuitBreaker struct {
+	sync.RWMutex
+
+	failureThreshold int64
+	cooldown         time.Duration
+	nowFn            clock.NowFn
+	logger           *zap.Logger
+	states           map[string]*instanceBreakerState
+	numEjected       atomic.Int64
+	metrics          circuitBreakerMetrics
+}
+
+func newInstanceCircuitBreaker(
+	opts CircuitBreakerOptions,
+	clockOpts clock.Options,
+	instrumentOpts instrument.Options,
+) *instanceCircuitBreaker {
+	return &instanceCircuitBreaker{
+		failureThreshold: int64(opts.FailureThreshold),
+		cooldown:         opts.Cooldown,
+		nowFn:            clockOpts.NowFn(),
+		logger:           instrumentOpts.Logger(),
+		states:           make(map[string]*instanceBreakerState),
+		metrics:          newCircuitBreakerMetrics(instrumentOpts.MetricsScope()),
+	}
+}
+
+// Ejected returns whether the instance is ejected from the write path.
+func (b *instanceCircuitBreaker) Ejected(instanceID string, nowNanos int64) bool {
+	b.RLock()
+	state, exists := b.states[instanceID]
+	b.RUnlock()
+	if !exists {
+		return false
+	}
+	ejectedUntilNanos := state.ejectedUntilNanos.Load()
+	return ejectedUntilNanos != 0 && nowNanos < ejectedUntilNanos
+}
+
+// ReportWriteResult records the result of a write to an instance.
+func (b *instanceCircuitBreaker) ReportWriteResult(instance placement.Instance, err error) {
+	state := b.stateFor(instance.ID())
+	if err == nil {
+		state.consecutiveFailures.Store(0)
+		prev := state.ejectedUntilNanos.Load()
+		if prev != 0 && state.ejectedUntilNanos.CAS(prev, 0) {
+			b.metrics.recoveries.Inc(1)
+			b.metrics.ejected.Update(float64(b.numEjected.Dec()))
+			b.logger.Info("aggregator instance recovered",
+				zap.String("instance", instance.ID()))
+		}
+		return
+	}
+
+	if state.consecutiveFailures.Inc() < b.failureThreshold {
+		return
+	}
+	nowNanos := b.nowFn().UnixNano()
+	prev := state.ejectedUntilNanos.Load()
+	if prev != 0 && nowNanos < prev {
+		// Already ejected.
+		return
+	}
+	if !state.ejectedUntilNanos.CAS(prev, nowNanos+int64(b.cooldown)) {
+		return
+	}
+	b.metrics.ejections.Inc(1)
+	if prev == 0 {
+		b.metrics.ejected.Update(float64(b.numEjected.Inc()))
+	}
+	b.logger.Warn("ejecting failing aggregator instance",
+		zap.String("instance", instance.ID()),
+		zap.Int64("consecutiveFailures", state.consecutiveFailures.Load()),
+		zap.Duration("cooldown", b.cooldown),
+		zap.Error(err))
+}
+
+func (b *instanceCircuitBreaker) stateFor(instanceID string) *instanceBreakerState {
+	b.RLock()
+	state, exists := b.states[instanceID]
+	b.RUnlock()
+	if exists {
+		return state
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if state, exists = b.states[instanceID]; exists {
+		return state
+	}
+	state = &instanceBreakerState{}
+	b.states[instanceID] = state
+	return state
+}
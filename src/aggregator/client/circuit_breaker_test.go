@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+func TestCircuitBreakerOptionsValidate(t *testing.T) {
+	require.NoError(t, NewCircuitBreakerOptions().Validate())
+	require.Equal(t, errInvalidCircuitBreakerFailureThreshold,
+		CircuitBreakerOptions{Cooldown: time.Second}.Validate())
+	require.Equal(t, errInvalidCircuitBreakerCooldown,
+		CircuitBreakerOptions{FailureThreshold: 1}.Validate())
+}
+
+func TestInstanceCircuitBreakerEjectAndRecover(t *testing.T) {
+	var (
+		now      = time.Unix(0, testNowNanos)
+		scope    = tally.NewTestScope("", nil)
+		instance = placement.NewInstance().SetID("instance1")
+		errWrite = errors.New("write error")
+	)
+	b := newInstanceCircuitBreaker(
+		CircuitBreakerOptions{FailureThreshold: 3, Cooldown: time.Minute},
+		clock.NewOptions().SetNowFn(func() time.Time { return now }),
+		instrument.NewOptions().SetMetricsScope(scope),
+	)
+
+	// A success resets the consecutive failures.
+	b.ReportWriteResult(instance, errWrite)
+	b.ReportWriteResult(instance, errWrite)
+	b.ReportWriteResult(instance, nil)
+	b.ReportWriteResult(instance, errWrite)
+	b.ReportWriteResult(instance, errWrite)
+	require.False(t, b.Ejected("instance1", now.UnixNano()))
+
+	b.ReportWriteResult(instance, errWrite)
+	require.True(t, b.Ejected("instance1", now.UnixNano()))
+	require.False(t, b.Ejected("instance2", now.UnixNano()))
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["ejections+"].Value())
+	require.Equal(t, 1.0, scope.Snapshot().Gauges()["ejected+"].Value())
+
+	// Failures while ejected do not extend the cooldown.
+	b.ReportWriteResult(instance, errWrite)
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["ejections+"].Value())
+
+	// A failure once the cooldown expires ejects the instance again.
+	now = now.Add(time.Minute)
+	require.False(t, b.Ejected("instance1", now.UnixNano()))
+	b.ReportWriteResult(instance, errWrite)
+	require.True(t, b.Ejected("instance1", now.UnixNano()))
+	require.Equal(t, int64(2), scope.Snapshot().Counters()["ejections+"].Value())
+	require.Equal(t, 1.0, scope.Snapshot().Gauges()["ejected+"].Value())
+
+	// A success recovers the instance.
+	now = now.Add(time.Minute)
+	b.ReportWriteResult(instance, nil)
+	require.False(t, b.Ejected("instance1", now.UnixNano()))
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["recoveries+"].Value())
+	require.Equal(t, 0.0, scope.Snapshot().Gauges()["ejected+"].Value())
+
+	b.ReportWriteResult(instance, errWrite)
+	require.False(t, b.Ejected("instance1", now.UnixNano()))
+}
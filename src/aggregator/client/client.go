@@ -58,6 +58,12 @@ type Client interface {
 		metadatas metadata.StagedMetadatas,
 	) error
 
+	// WriteUntimedHistogram writes untimed histogram metrics.
+	WriteUntimedHistogram(
+		histogram unaggregated.Histogram,
+		metadatas metadata.StagedMetadatas,
+	) error
+
 	// WriteTimed writes timed metrics.
 	WriteTimed(
 		metric aggregated.Metric,
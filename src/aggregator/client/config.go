@@ -36,6 +36,8 @@ import (
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/retry"
+	"github.com/m3db/m3/src/x/sampler"
+	xtls "github.com/m3db/m3/src/x/tls"
 
 	"github.com/uber-go/tally"
 )
@@ -60,6 +62,7 @@ type Configuration struct {
 	QueueSize                  int                             `yaml:"queueSize"`
 	QueueDropType              *DropType                       `yaml:"queueDropType"`
 	Connection                 ConnectionConfiguration         `yaml:"connection"`
+	CircuitBreaker             *CircuitBreakerConfiguration    `yaml:"circuitBreaker"`
 }
 
 // NewAdminClient creates a new admin client.
@@ -138,7 +141,10 @@ func (c *Configuration) newClientOptions(
 		}
 
 		scope := instrumentOpts.MetricsScope()
-		connectionOpts := c.Connection.NewConnectionOptions(scope.SubScope("connection"))
+		connectionOpts, err := c.Connection.NewConnectionOptions(scope.SubScope("connection"))
+		if err != nil {
+			return nil, err
+		}
 		kvOpts, err := placementKV.NewOverrideOptions()
 		if err != nil {
 			return nil, err
@@ -194,6 +200,10 @@ func (c *Configuration) newClientOptions(
 		if c.QueueDropType != nil {
 			opts = opts.SetQueueDropType(*c.QueueDropType)
 		}
+		if c.CircuitBreaker != nil {
+			cbOpts := c.CircuitBreaker.NewCircuitBreakerOptions()
+			opts = opts.SetCircuitBreakerOptions(&cbOpts)
+		}
 	default:
 		return nil, fmt.Errorf("unknown client type: %v", c.Type)
 	}
@@ -215,10 +225,18 @@ type ConnectionConfiguration struct {
 	ReconnectThresholdMultiplier int                  `yaml:"reconnectThresholdMultiplier"`
 	MaxReconnectDuration         *time.Duration       `yaml:"maxReconnectDuration"`
 	WriteRetries                 *retry.Configuration `yaml:"writeRetries"`
+	ProtocolNegotiation          *bool                `yaml:"protocolNegotiation"`
+	HandshakeTimeout             time.Duration        `yaml:"handshakeTimeout"`
+
+	// TLS configures encryption and client certificates for connections to
+	// aggregator instances, connections are not encrypted when unset.
+	TLS *xtls.ClientConfiguration `yaml:"tls"`
 }
 
 // NewConnectionOptions creates new connection options.
-func (c *ConnectionConfiguration) NewConnectionOptions(scope tally.Scope) ConnectionOptions {
+func (c *ConnectionConfiguration) NewConnectionOptions(
+	scope tally.Scope,
+) (ConnectionOptions, error) {
 	opts := NewConnectionOptions()
 	if c.ConnectionTimeout != 0 {
 		opts = opts.SetConnectionTimeout(c.ConnectionTimeout)
@@ -245,6 +263,38 @@ func (c *ConnectionConfiguration) NewConnectionOptions(scope tally.Scope) Connec
 		retryOpts := c.WriteRetries.NewOptions(scope)
 		opts = opts.SetWriteRetryOptions(retryOpts)
 	}
+	if c.ProtocolNegotiation != nil {
+		opts = opts.SetProtocolNegotiation(*c.ProtocolNegotiation)
+	}
+	if c.HandshakeTimeout != 0 {
+		opts = opts.SetHandshakeTimeout(c.HandshakeTimeout)
+	}
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS config: %w", err)
+		}
+		opts = opts.SetTLSConfig(tlsConfig)
+	}
+	return opts, nil
+}
+
+// CircuitBreakerConfiguration configures the ejection of consistently failing
+// instances from the write path.
+type CircuitBreakerConfiguration struct {
+	FailureThreshold int           `yaml:"failureThreshold"`
+	Cooldown         time.Duration `yaml:"cooldown"`
+}
+
+// NewCircuitBreakerOptions creates new circuit breaker options.
+func (c *CircuitBreakerConfiguration) NewCircuitBreakerOptions() CircuitBreakerOptions {
+	opts := NewCircuitBreakerOptions()
+	if c.FailureThreshold != 0 {
+		opts.FailureThreshold = c.FailureThreshold
+	}
+	if c.Cooldown != 0 {
+		opts.Cooldown = c.Cooldown
+	}
 	return opts
 }
 
@@ -280,6 +330,10 @@ func (c *EncoderConfiguration) NewEncoderOptions(
 // if using M3Msg client type.
 type M3MsgConfiguration struct {
 	Producer producerconfig.ProducerConfiguration `yaml:"producer"`
+
+	// TraceSampleRate is the fraction of messages traced, whose trace context
+	// is propagated to the aggregator alongside the message.
+	TraceSampleRate sampler.Rate `yaml:"traceSampleRate"`
 }
 
 // NewM3MsgOptions returns new M3Msg options from configuration.
@@ -300,7 +354,8 @@ func (c *M3MsgConfiguration) NewM3MsgOptions(
 		return nil, err
 	}
 
-	opts = opts.SetProducer(producer)
+	opts = opts.SetProducer(producer).
+		SetTraceSampleRate(c.TraceSampleRate)
 
 	// Validate the options.
 	if err := opts.Validate(); err != nil {
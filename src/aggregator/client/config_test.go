@@ -80,6 +80,10 @@ connection:
     maxBackoff: 1s
     maxRetries: 2
     jitter: true
+  protocolNegotiation: true
+  handshakeTimeout: 2s
+circuitBreaker:
+  failureThreshold: 3
 `
 
 func TestConfigUnmarshal(t *testing.T) {
@@ -121,6 +125,10 @@ func TestConfigUnmarshal(t *testing.T) {
 	require.Equal(t, 2, cfg.Connection.WriteRetries.MaxRetries)
 	require.Equal(t, true, *cfg.Connection.WriteRetries.Jitter)
 	require.Nil(t, cfg.Connection.WriteRetries.Forever)
+	require.Equal(t, true, *cfg.Connection.ProtocolNegotiation)
+	require.Equal(t, 2*time.Second, cfg.Connection.HandshakeTimeout)
+	require.Equal(t, 3, cfg.CircuitBreaker.FailureThreshold)
+	require.Equal(t, time.Duration(0), cfg.CircuitBreaker.Cooldown)
 }
 
 func TestNewClientOptions(t *testing.T) {
@@ -171,4 +179,10 @@ func TestNewClientOptions(t *testing.T) {
 	require.Equal(t, 2, opts.ConnectionOptions().WriteRetryOptions().MaxRetries())
 	require.Equal(t, true, opts.ConnectionOptions().WriteRetryOptions().Jitter())
 	require.Equal(t, false, opts.ConnectionOptions().WriteRetryOptions().Forever())
+	require.Equal(t, true, opts.ConnectionOptions().ProtocolNegotiation())
+	require.Equal(t, 2*time.Second, opts.ConnectionOptions().HandshakeTimeout())
+	require.Equal(t, &CircuitBreakerOptions{
+		FailureThreshold: 3,
+		Cooldown:         defaultCircuitBreakerCooldown,
+	}, opts.CircuitBreakerOptions())
 }
@@ -21,17 +21,24 @@
 package client
 
 import (
+	"bufio"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"math/rand"
 	"net"
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 	"github.com/m3db/m3/src/x/clock"
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/retry"
+	xtls "github.com/m3db/m3/src/x/tls"
 
 	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -48,6 +55,7 @@ type (
 	sleepFn           func(time.Duration)
 	connectWithLockFn func() error
 	writeWithLockFn   func([]byte) error
+	handshakeFn       func(net.Conn) (encoding.Handshake, error)
 )
 
 // connection is a persistent connection that retries establishing
@@ -59,9 +67,11 @@ type connection struct {
 	connectWithLockFn       connectWithLockFn
 	sleepFn                 sleepFn
 	nowFn                   clock.NowFn
-	conn                    *net.TCPConn
+	conn                    net.Conn
+	tlsConfig               *tls.Config
 	rngFn                   retry.RngFn
 	writeWithLockFn         writeWithLockFn
+	handshakeFn             handshakeFn
 	addr                    string
 	maxDuration             time.Duration
 	maxThreshold            int
@@ -71,35 +81,51 @@ type connection struct {
 	lastConnectAttemptNanos int64
 	writeTimeout            time.Duration
 	connTimeout             time.Duration
+	handshakeTimeout        time.Duration
 	numFailures             int
+	capabilities            atomic.Uint64
 	mtx                     sync.Mutex
+	pausedShardsLock        sync.RWMutex
+	pausedShards            map[uint32]int64
 	keepAlive               bool
+	protocolNegotiation     bool
 }
 
 // newConnection creates a new connection.
 func newConnection(addr string, opts ConnectionOptions) *connection {
 	c := &connection{
-		addr:           addr,
-		connTimeout:    opts.ConnectionTimeout(),
-		writeTimeout:   opts.WriteTimeout(),
-		keepAlive:      opts.ConnectionKeepAlive(),
-		initThreshold:  opts.InitReconnectThreshold(),
-		multiplier:     opts.ReconnectThresholdMultiplier(),
-		maxThreshold:   opts.MaxReconnectThreshold(),
-		maxDuration:    opts.MaxReconnectDuration(),
-		writeRetryOpts: opts.WriteRetryOptions(),
-		rngFn:          rand.New(rand.NewSource(time.Now().UnixNano())).Int63n,
-		nowFn:          opts.ClockOptions().NowFn(),
-		sleepFn:        time.Sleep,
-		threshold:      opts.InitReconnectThreshold(),
+		addr:                addr,
+		connTimeout:         opts.ConnectionTimeout(),
+		writeTimeout:        opts.WriteTimeout(),
+		keepAlive:           opts.ConnectionKeepAlive(),
+		initThreshold:       opts.InitReconnectThreshold(),
+		multiplier:          opts.ReconnectThresholdMultiplier(),
+		maxThreshold:        opts.MaxReconnectThreshold(),
+		maxDuration:         opts.MaxReconnectDuration(),
+		writeRetryOpts:      opts.WriteRetryOptions(),
+		protocolNegotiation: opts.ProtocolNegotiation(),
+		handshakeTimeout:    opts.HandshakeTimeout(),
+		tlsConfig:           opts.TLSConfig(),
+		rngFn:               rand.New(rand.NewSource(time.Now().UnixNano())).Int63n,
+		nowFn:               opts.ClockOptions().NowFn(),
+		sleepFn:             time.Sleep,
+		threshold:           opts.InitReconnectThreshold(),
 		writer: opts.RWOptions().ResettableWriterFn()(
 			uninitWriter,
 			xio.ResettableWriterOptions{WriteBufferSize: 0},
 		),
-		metrics: newConnectionMetrics(opts.InstrumentOptions().MetricsScope()),
+		metrics:      newConnectionMetrics(opts.InstrumentOptions().MetricsScope()),
+		pausedShards: make(map[uint32]int64),
+	}
+	if c.handshakeTimeout <= 0 {
+		c.handshakeTimeout = c.connTimeout
 	}
 	c.connectWithLockFn = c.connectWithLock
 	c.writeWithLockFn = c.writeWithLock
+	c.handshakeFn = c.handshake
+	// Until a handshake says otherwise, assume the server supports the same
+	// features as the client.
+	c.capabilities.Store(uint64(encoding.SupportedCapabilities))
 
 	return c
 }
@@ -142,6 +168,22 @@ func (c *connection) Write(data []byte) error {
 	return err
 }
 
+// ShardIngestionPaused returns true if the server notified that the ingestion
+// of the shard is paused and the pause has not expired yet.
+func (c *connection) ShardIngestionPaused(shard uint32) bool {
+	c.pausedShardsLock.RLock()
+	untilNanos, ok := c.pausedShards[shard]
+	c.pausedShardsLock.RUnlock()
+	return ok && c.nowFn().UnixNano() < untilNanos
+}
+
+// Capabilities returns the capabilities negotiated with the server. If protocol
+// negotiation is disabled, or no connection was established yet, all the
+// capabilities supported by the client are returned.
+func (c *connection) Capabilities() encoding.Capabilities {
+	return encoding.Capabilities(c.capabilities.Load())
+}
+
 func (c *connection) Close() {
 	c.mtx.Lock()
 	c.closeWithLock()
@@ -167,24 +209,121 @@ func (c *connection) writeAttemptWithLock(data []byte) error {
 
 func (c *connection) connectWithLock() error {
 	c.lastConnectAttemptNanos = c.nowFn().UnixNano()
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	var readNotices bool
+	if c.protocolNegotiation {
+		negotiated, err := c.handshakeFn(conn)
+		if err != nil {
+			// Servers that predate the handshake close the connection when they
+			// receive it, so reconnect without negotiating any optional features.
+			c.metrics.handshakeError.Inc(1)
+			negotiated = encoding.Handshake{}
+			conn.Close() // nolint: errcheck
+			if conn, err = c.dial(); err != nil {
+				return err
+			}
+		}
+		c.metrics.protocolVersion.Update(float64(negotiated.ProtocolVersion))
+		c.capabilities.Store(uint64(negotiated.Capabilities))
+		readNotices = negotiated.Capabilities.Has(encoding.ShardIngestionPausedCapability)
+	}
+
+	if c.conn != nil {
+		c.conn.Close() // nolint: errcheck
+	}
+
+	c.conn = conn
+	c.writer.Reset(conn)
+	if readNotices {
+		go c.readNotices(conn)
+	}
+	return nil
+}
+
+// readNotices reads the notices the server sends on the connection until the
+// connection is closed.
+func (c *connection) readNotices(conn net.Conn) {
+	it := protobuf.NewUnaggregatedIterator(bufio.NewReader(conn), protobuf.NewUnaggregatedOptions())
+	defer it.Close()
+
+	for it.Next() {
+		current := it.Current()
+		if current.Type != encoding.ShardIngestionPausedType {
+			c.metrics.unexpectedNotice.Inc(1)
+			continue
+		}
+		c.metrics.shardIngestionPaused.Inc(1)
+		c.pausedShardsLock.Lock()
+		c.pausedShards[current.ShardIngestionPaused.Shard] = current.ShardIngestionPaused.UntilNanos
+		c.pausedShardsLock.Unlock()
+	}
+}
+
+func (c *connection) dial() (net.Conn, error) {
 	conn, err := net.DialTimeout(tcpProtocol, c.addr, c.connTimeout)
 	if err != nil {
 		c.metrics.connectError.Inc(1)
-		return err
+		return nil, err
 	}
 
 	tcpConn := conn.(*net.TCPConn)
 	if err := tcpConn.SetKeepAlive(c.keepAlive); err != nil {
 		c.metrics.setKeepAliveError.Inc(1)
 	}
+	if c.tlsConfig == nil {
+		return tcpConn, nil
+	}
 
-	if c.conn != nil {
-		c.conn.Close() // nolint: errcheck
+	tlsConn, err := xtls.Client(tcpConn, c.addr, c.tlsConfig, c.connTimeout)
+	if err != nil {
+		c.metrics.tlsHandshakeError.Inc(1)
+		tcpConn.Close() // nolint: errcheck
+		return nil, err
 	}
+	return tlsConn, nil
+}
 
-	c.conn = tcpConn
-	c.writer.Reset(tcpConn)
-	return nil
+// handshake advertises the protocol version and capabilities of the client to
+// the server, and returns the ones both the client and the server support.
+func (c *connection) handshake(conn net.Conn) (encoding.Handshake, error) {
+	if err := conn.SetDeadline(c.nowFn().Add(c.handshakeTimeout)); err != nil {
+		return encoding.Handshake{}, err
+	}
+
+	local := encoding.NewHandshake()
+	encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+	if err := encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:      encoding.HandshakeType,
+		Handshake: local,
+	}); err != nil {
+		return encoding.Handshake{}, err
+	}
+	buf := encoder.Relinquish()
+	_, err := conn.Write(buf.Bytes())
+	buf.Close()
+	if err != nil {
+		return encoding.Handshake{}, err
+	}
+
+	it := protobuf.NewUnaggregatedIterator(bufio.NewReader(conn), protobuf.NewUnaggregatedOptions())
+	defer it.Close()
+	if !it.Next() {
+		return encoding.Handshake{}, it.Err()
+	}
+	if msgType := it.Current().Type; msgType != encoding.HandshakeType {
+		return encoding.Handshake{}, fmt.Errorf("unexpected handshake response message type %v", msgType)
+	}
+	negotiated := local.Negotiate(it.Current().Handshake)
+
+	// Clear the deadline so it does not apply to subsequent writes.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return encoding.Handshake{}, err
+	}
+	return negotiated, nil
 }
 
 func (c *connection) checkReconnectWithLock() error {
@@ -249,15 +388,25 @@ const (
 )
 
 type connectionMetrics struct {
+	protocolVersion       tally.Gauge
+	handshakeError        tally.Counter
+	tlsHandshakeError     tally.Counter
 	connectError          tally.Counter
 	writeError            tally.Counter
 	writeRetries          tally.Counter
 	setKeepAliveError     tally.Counter
 	setWriteDeadlineError tally.Counter
+	shardIngestionPaused  tally.Counter
+	unexpectedNotice      tally.Counter
 }
 
 func newConnectionMetrics(scope tally.Scope) connectionMetrics {
 	return connectionMetrics{
+		protocolVersion: scope.Gauge("protocol-version"),
+		handshakeError: scope.Tagged(map[string]string{errorMetricType: "handshake"}).
+			Counter(errorMetric),
+		tlsHandshakeError: scope.Tagged(map[string]string{errorMetricType: "tls-handshake"}).
+			Counter(errorMetric),
 		connectError: scope.Tagged(map[string]string{errorMetricType: "connect"}).
 			Counter(errorMetric),
 		writeError: scope.Tagged(map[string]string{errorMetricType: "write"}).
@@ -267,6 +416,10 @@ func newConnectionMetrics(scope tally.Scope) connectionMetrics {
 			Counter(errorMetric),
 		setWriteDeadlineError: scope.Tagged(map[string]string{errorMetricType: "set-write-deadline"}).
 			Counter(errorMetric),
+		shardIngestionPaused: scope.Tagged(map[string]string{"type": "shard-ingestion-paused"}).
+			Counter("notices"),
+		unexpectedNotice: scope.Tagged(map[string]string{errorMetricType: "unexpected-notice"}).
+			Counter(errorMetric),
 	}
 }
 
@@ -21,6 +21,7 @@
 package client
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/m3db/m3/src/x/clock"
@@ -42,6 +43,11 @@ const (
 	defaultWriteRetryMaxBackoff         = time.Second
 	defaultWriteRetryMaxRetries         = 1
 	defaultWriteRetryJitterEnabled      = true
+	defaultProtocolNegotiation          = false
+
+	// defaultHandshakeTimeout is zero so that the handshake is bounded by the
+	// connection timeout, same as the TLS handshake, unless set explicitly.
+	defaultHandshakeTimeout = 0
 )
 
 // ConnectionOptions provides a set of options for tcp connections.
@@ -111,20 +117,47 @@ type ConnectionOptions interface {
 
 	// RWOptions returns the RW options.
 	RWOptions() xio.Options
+
+	// SetProtocolNegotiation sets whether a handshake is exchanged with the
+	// server when establishing connections to negotiate the protocol version
+	// and capabilities used on the connection.
+	SetProtocolNegotiation(value bool) ConnectionOptions
+
+	// ProtocolNegotiation returns whether a handshake is exchanged with the
+	// server when establishing connections to negotiate the protocol version
+	// and capabilities used on the connection.
+	ProtocolNegotiation() bool
+
+	// SetHandshakeTimeout sets the timeout for the server to respond to a handshake,
+	// with the connection timeout used if zero.
+	SetHandshakeTimeout(value time.Duration) ConnectionOptions
+
+	// HandshakeTimeout returns the timeout for the server to respond to a handshake.
+	HandshakeTimeout() time.Duration
+
+	// SetTLSConfig sets the TLS configuration used to encrypt connections,
+	// connections are not encrypted when nil.
+	SetTLSConfig(value *tls.Config) ConnectionOptions
+
+	// TLSConfig returns the TLS configuration used to encrypt connections.
+	TLSConfig() *tls.Config
 }
 
 type connectionOptions struct {
-	clockOpts      clock.Options
-	instrumentOpts instrument.Options
-	writeRetryOpts retry.Options
-	rwOpts         xio.Options
-	connTimeout    time.Duration
-	writeTimeout   time.Duration
-	maxDuration    time.Duration
-	initThreshold  int
-	maxThreshold   int
-	multiplier     int
-	connKeepAlive  bool
+	clockOpts           clock.Options
+	instrumentOpts      instrument.Options
+	writeRetryOpts      retry.Options
+	rwOpts              xio.Options
+	tlsConfig           *tls.Config
+	connTimeout         time.Duration
+	writeTimeout        time.Duration
+	maxDuration         time.Duration
+	handshakeTimeout    time.Duration
+	initThreshold       int
+	maxThreshold        int
+	multiplier          int
+	connKeepAlive       bool
+	protocolNegotiation bool
 }
 
 // NewConnectionOptions create a new set of connection options.
@@ -136,17 +169,19 @@ func NewConnectionOptions() ConnectionOptions {
 		SetMaxRetries(defaultWriteRetryMaxRetries).
 		SetJitter(defaultWriteRetryJitterEnabled)
 	return &connectionOptions{
-		clockOpts:      clock.NewOptions(),
-		instrumentOpts: instrument.NewOptions(),
-		connTimeout:    defaultConnectionTimeout,
-		connKeepAlive:  defaultConnectionKeepAlive,
-		writeTimeout:   defaultWriteTimeout,
-		initThreshold:  defaultInitReconnectThreshold,
-		maxThreshold:   defaultMaxReconnectThreshold,
-		multiplier:     defaultReconnectThresholdMultiplier,
-		maxDuration:    defaultMaxReconnectDuration,
-		writeRetryOpts: defaultWriteRetryOpts,
-		rwOpts:         xio.NewOptions(),
+		clockOpts:           clock.NewOptions(),
+		instrumentOpts:      instrument.NewOptions(),
+		connTimeout:         defaultConnectionTimeout,
+		connKeepAlive:       defaultConnectionKeepAlive,
+		writeTimeout:        defaultWriteTimeout,
+		initThreshold:       defaultInitReconnectThreshold,
+		maxThreshold:        defaultMaxReconnectThreshold,
+		multiplier:          defaultReconnectThresholdMultiplier,
+		maxDuration:         defaultMaxReconnectDuration,
+		writeRetryOpts:      defaultWriteRetryOpts,
+		rwOpts:              xio.NewOptions(),
+		protocolNegotiation: defaultProtocolNegotiation,
+		handshakeTimeout:    defaultHandshakeTimeout,
 	}
 }
 
@@ -259,3 +294,33 @@ func (o *connectionOptions) SetRWOptions(value xio.Options) ConnectionOptions {
 func (o *connectionOptions) RWOptions() xio.Options {
 	return o.rwOpts
 }
+
+func (o *connectionOptions) SetProtocolNegotiation(value bool) ConnectionOptions {
+	opts := *o
+	opts.protocolNegotiation = value
+	return &opts
+}
+
+func (o *connectionOptions) ProtocolNegotiation() bool {
+	return o.protocolNegotiation
+}
+
+func (o *connectionOptions) SetHandshakeTimeout(value time.Duration) ConnectionOptions {
+	opts := *o
+	opts.handshakeTimeout = value
+	return &opts
+}
+
+func (o *connectionOptions) HandshakeTimeout() time.Duration {
+	return o.handshakeTimeout
+}
+
+func (o *connectionOptions) SetTLSConfig(value *tls.Config) ConnectionOptions {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *connectionOptions) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
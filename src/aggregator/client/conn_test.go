@@ -21,15 +21,22 @@
 package client
 
 import (
+	"bufio"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 	"github.com/m3db/m3/src/x/clock"
+	xtls "github.com/m3db/m3/src/x/tls"
+	"github.com/m3db/m3/src/x/tls/tlstest"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -333,6 +340,275 @@ func TestConnectWriteToServer(t *testing.T) {
 	require.Nil(t, conn.conn)
 }
 
+func TestConnectionProtocolNegotiation(t *testing.T) {
+	data := []byte("foobar")
+
+	l, err := net.Listen(tcpProtocol, testLocalServerAddr)
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		conn, err := l.Accept()
+		require.NoError(t, err)
+		defer conn.Close() // nolint: errcheck
+
+		// Respond to the client handshake with a newer protocol version
+		// and an unknown capability.
+		reader := bufio.NewReader(conn)
+		it := protobuf.NewUnaggregatedIterator(reader, protobuf.NewUnaggregatedOptions())
+		require.True(t, it.Next())
+		require.Equal(t, encoding.HandshakeType, it.Current().Type)
+		require.Equal(t, encoding.NewHandshake(), it.Current().Handshake)
+
+		encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+		require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type: encoding.HandshakeType,
+			Handshake: encoding.Handshake{
+				ProtocolVersion: encoding.ProtocolVersion + 1,
+				Capabilities:    encoding.HistogramsCapability | 1<<63,
+			},
+		}))
+		_, err = conn.Write(encoder.Relinquish().Bytes())
+		require.NoError(t, err)
+
+		buf := make([]byte, len(data))
+		_, err = io.ReadFull(reader, buf)
+		require.NoError(t, err)
+		require.Equal(t, data, buf)
+	}()
+
+	opts := testConnectionOptions().
+		SetInitReconnectThreshold(0).
+		SetProtocolNegotiation(true).
+		SetHandshakeTimeout(5 * time.Second)
+	conn := newConnection(l.Addr().String(), opts)
+	require.NoError(t, conn.Write(data))
+	require.Equal(t, encoding.HistogramsCapability, conn.Capabilities())
+
+	wg.Wait()
+	conn.Close()
+}
+
+func TestConnectionShardIngestionPaused(t *testing.T) {
+	var (
+		data  = []byte("foobar")
+		until = time.Now().Add(time.Minute)
+	)
+
+	l, err := net.Listen(tcpProtocol, testLocalServerAddr)
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	serverErrCh := make(chan error, 1)
+	go func() {
+		serverErrCh <- func() error {
+			conn, err := l.Accept()
+			if err != nil {
+				return err
+			}
+			defer conn.Close() // nolint: errcheck
+
+			reader := bufio.NewReader(conn)
+			it := protobuf.NewUnaggregatedIterator(reader, protobuf.NewUnaggregatedOptions())
+			if !it.Next() {
+				return it.Err()
+			}
+			encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+			if err := encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+				Type:      encoding.HandshakeType,
+				Handshake: encoding.NewHandshake(),
+			}); err != nil {
+				return err
+			}
+			if _, err := conn.Write(encoder.Relinquish().Bytes()); err != nil {
+				return err
+			}
+
+			// Reject the write and notify the client the shard is paused.
+			if _, err := io.ReadFull(reader, make([]byte, len(data))); err != nil {
+				return err
+			}
+			if err := encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+				Type: encoding.ShardIngestionPausedType,
+				ShardIngestionPaused: encoding.ShardIngestionPaused{
+					Shard:      3,
+					UntilNanos: until.UnixNano(),
+				},
+			}); err != nil {
+				return err
+			}
+			_, err = conn.Write(encoder.Relinquish().Bytes())
+			return err
+		}()
+	}()
+
+	opts := testConnectionOptions().
+		SetInitReconnectThreshold(0).
+		SetProtocolNegotiation(true).
+		SetHandshakeTimeout(5 * time.Second)
+	conn := newConnection(l.Addr().String(), opts)
+	require.NoError(t, conn.Write(data))
+	require.NoError(t, <-serverErrCh)
+
+	for start := time.Now(); !conn.ShardIngestionPaused(3); {
+		require.True(t, time.Since(start) < 5*time.Second, "shard not paused")
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.False(t, conn.ShardIngestionPaused(4))
+
+	// The shard is written to again once the pause expires.
+	conn.nowFn = func() time.Time { return until }
+	require.False(t, conn.ShardIngestionPaused(3))
+
+	conn.Close()
+}
+
+func TestConnectionProtocolNegotiationUnsupported(t *testing.T) {
+	data := []byte("foobar")
+
+	l, err := net.Listen(tcpProtocol, testLocalServerAddr)
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// Servers without handshake support close the connection.
+		conn, err := l.Accept()
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		// The client reconnects without a handshake.
+		conn, err = l.Accept()
+		require.NoError(t, err)
+		defer conn.Close() // nolint: errcheck
+		buf := make([]byte, len(data))
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		require.Equal(t, data, buf)
+	}()
+
+	opts := testConnectionOptions().
+		SetInitReconnectThreshold(0).
+		SetProtocolNegotiation(true).
+		SetHandshakeTimeout(5 * time.Second)
+	conn := newConnection(l.Addr().String(), opts)
+	require.Equal(t, encoding.SupportedCapabilities, conn.Capabilities())
+	require.NoError(t, conn.Write(data))
+	require.Equal(t, encoding.Capabilities(0), conn.Capabilities())
+
+	wg.Wait()
+	conn.Close()
+}
+
+func TestConnectionTLS(t *testing.T) {
+	data := []byte("foobar")
+
+	var (
+		ca         = tlstest.NewCA(t, t.TempDir())
+		serverCert = ca.IssueServer("aggregator")
+		clientCert = ca.IssueClient("coordinator")
+	)
+	serverCfg, err := xtls.ServerConfiguration{
+		CertFile:          serverCert.CertFile,
+		KeyFile:           serverCert.KeyFile,
+		ClientCAFile:      ca.CertFile,
+		RequireClientCert: true,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := xtls.ClientConfiguration{
+		CertFile: clientCert.CertFile,
+		KeyFile:  clientCert.KeyFile,
+		CAFile:   ca.CertFile,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+
+	l, err := net.Listen(tcpProtocol, testLocalServerAddr)
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		conn, err := l.Accept()
+		require.NoError(t, err)
+		tlsConn := tls.Server(conn, serverCfg)
+		defer tlsConn.Close() // nolint: errcheck
+
+		buf := make([]byte, len(data))
+		_, err = io.ReadFull(tlsConn, buf)
+		require.NoError(t, err)
+		require.Equal(t, data, buf)
+
+		identity, ok := xtls.PeerIdentity(tlsConn.ConnectionState())
+		require.True(t, ok)
+		require.Equal(t, "coordinator", identity)
+	}()
+
+	opts := testConnectionOptions().
+		SetInitReconnectThreshold(0).
+		SetConnectionTimeout(5 * time.Second).
+		SetTLSConfig(clientCfg)
+	conn := newConnection(l.Addr().String(), opts)
+	require.NoError(t, conn.Write(data))
+
+	wg.Wait()
+	conn.Close()
+}
+
+func TestConnectionHandshakeTimeout(t *testing.T) {
+	data := []byte("foobar")
+
+	l, err := net.Listen(tcpProtocol, testLocalServerAddr)
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// The server never responds to the handshake.
+		unresponsive, err := l.Accept()
+		require.NoError(t, err)
+		defer unresponsive.Close() // nolint: errcheck
+
+		// The client reconnects without a handshake once it times out.
+		conn, err := l.Accept()
+		require.NoError(t, err)
+		defer conn.Close() // nolint: errcheck
+		buf := make([]byte, len(data))
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		require.Equal(t, data, buf)
+	}()
+
+	opts := testConnectionOptions().
+		SetInitReconnectThreshold(0).
+		SetProtocolNegotiation(true).
+		SetHandshakeTimeout(50 * time.Millisecond)
+	conn := newConnection(l.Addr().String(), opts)
+	require.Equal(t, 50*time.Millisecond, conn.handshakeTimeout)
+	require.NoError(t, conn.Write(data))
+	require.Equal(t, encoding.Capabilities(0), conn.Capabilities())
+
+	wg.Wait()
+	conn.Close()
+}
+
+func TestConnectionHandshakeTimeoutDefaultsToConnectionTimeout(t *testing.T) {
+	conn := newConnection(testLocalServerAddr, testConnectionOptions())
+	require.Equal(t, 100*time.Millisecond, conn.handshakeTimeout)
+}
+
 func testConnectionOptions() ConnectionOptions {
 	return NewConnectionOptions().
 		SetClockOptions(clock.NewOptions()).
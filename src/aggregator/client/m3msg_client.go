@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 
@@ -38,8 +40,12 @@ import (
 	"github.com/m3db/m3/src/msg/producer"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
+	xopentracing "github.com/m3db/m3/src/x/opentracing"
+	"github.com/m3db/m3/src/x/sampler"
 )
 
+const m3msgProduceSpanName = "m3msg.produce"
+
 var _ AdminClient = (*M3MsgClient)(nil)
 
 // M3MsgClient sends metrics to M3 Aggregator over m3msg.
@@ -51,9 +57,11 @@ type M3MsgClient struct {
 }
 
 type m3msgClient struct {
-	producer    producer.Producer
-	numShards   uint32
-	messagePool *messagePool
+	producer     producer.Producer
+	numShards    uint32
+	messagePool  *messagePool
+	tracer       opentracing.Tracer
+	traceSampler *sampler.Sampler
 }
 
 // NewM3MsgClient creates a new M3 Aggregator client that uses M3Msg.
@@ -72,10 +80,9 @@ func NewM3MsgClient(opts Options) (Client, error) {
 		return nil, err
 	}
 
-	msgClient := m3msgClient{
-		producer:    producer,
-		numShards:   producer.NumShards(),
-		messagePool: newMessagePool(),
+	traceSampler, err := sampler.NewSampler(m3msgOpts.TraceSampleRate())
+	if err != nil {
+		return nil, err
 	}
 
 	var (
@@ -83,6 +90,14 @@ func NewM3MsgClient(opts Options) (Client, error) {
 		logger = iOpts.Logger()
 	)
 
+	msgClient := m3msgClient{
+		producer:     producer,
+		numShards:    producer.NumShards(),
+		messagePool:  newMessagePool(),
+		tracer:       iOpts.Tracer(),
+		traceSampler: traceSampler,
+	}
+
 	logger.Info("creating M3MsgClient", zap.Uint32("numShards", msgClient.numShards))
 
 	return &M3MsgClient{
@@ -152,6 +167,24 @@ func (c *M3MsgClient) WriteUntimedGauge(
 	return err
 }
 
+// WriteUntimedHistogram writes untimed histogram metrics.
+func (c *M3MsgClient) WriteUntimedHistogram(
+	histogram unaggregated.Histogram,
+	metadatas metadata.StagedMetadatas,
+) error {
+	callStart := c.nowFn()
+	payload := payloadUnion{
+		payloadType: untimedType,
+		untimed: untimedPayload{
+			metric:    histogram.ToUnion(),
+			metadatas: metadatas,
+		},
+	}
+	err := c.write(histogram.ID, payload)
+	c.metrics.writeUntimedHistogram.ReportSuccessOrError(err, c.nowFn().Sub(callStart))
+	return err
+}
+
 // WriteTimed writes timed metrics.
 func (c *M3MsgClient) WriteTimed(
 	metric aggregated.Metric,
@@ -234,12 +267,26 @@ func (c *M3MsgClient) write(metricID id.RawID, payload payloadUnion) error {
 		return err
 	}
 
-	if err := c.m3msg.producer.Produce(msg); err != nil {
-		msg.Finalize(producer.Dropped)
-		return err
+	var span opentracing.Span
+	if c.m3msg.traceSampler.Sample() {
+		// NB: the trace context is propagated alongside the message so the
+		// aggregator can trace the consumption of the message as a child span.
+		span = c.m3msg.tracer.StartSpan(m3msgProduceSpanName)
+		span.SetTag("shard", shard)
+		msg.traceContext, _ = xopentracing.InjectBytes(c.m3msg.tracer, span.Context())
 	}
 
-	return nil
+	err := c.m3msg.producer.Produce(msg)
+	if err != nil {
+		msg.Finalize(producer.Dropped)
+	}
+	if span != nil {
+		if err != nil {
+			ext.LogError(span, err)
+		}
+		span.Finish()
+	}
+	return err
 }
 
 // Flush satisfies Client interface, as M3Msg client does not need explicit flushing.
@@ -257,6 +304,7 @@ type m3msgClientMetrics struct {
 	writeUntimedCounter    instrument.MethodMetrics
 	writeUntimedBatchTimer instrument.MethodMetrics
 	writeUntimedGauge      instrument.MethodMetrics
+	writeUntimedHistogram  instrument.MethodMetrics
 	writePassthrough       instrument.MethodMetrics
 	writeForwarded         instrument.MethodMetrics
 }
@@ -269,6 +317,7 @@ func newM3msgClientMetrics(
 		writeUntimedCounter:    instrument.NewMethodMetrics(scope, "writeUntimedCounter", opts),
 		writeUntimedBatchTimer: instrument.NewMethodMetrics(scope, "writeUntimedBatchTimer", opts),
 		writeUntimedGauge:      instrument.NewMethodMetrics(scope, "writeUntimedGauge", opts),
+		writeUntimedHistogram:  instrument.NewMethodMetrics(scope, "writeUntimedHistogram", opts),
 		writePassthrough:       instrument.NewMethodMetrics(scope, "writePassthrough", opts),
 		writeForwarded:         instrument.NewMethodMetrics(scope, "writeForwarded", opts),
 	}
@@ -295,16 +344,18 @@ func (m *messagePool) Put(msg *message) {
 }
 
 // Ensure message implements m3msg producer message interface.
-var _ producer.Message = (*message)(nil)
+var _ producer.TracedMessage = (*message)(nil)
 
 type message struct {
-	pool  *messagePool
-	shard uint32
+	pool         *messagePool
+	shard        uint32
+	traceContext []byte
 
 	metric metricpb.MetricWithMetadatas
 	cm     metricpb.CounterWithMetadatas
 	bm     metricpb.BatchTimerWithMetadatas
 	gm     metricpb.GaugeWithMetadatas
+	hm     metricpb.HistogramWithMetadatas
 	fm     metricpb.ForwardedMetricWithMetadata
 	tm     metricpb.TimedMetricWithMetadata
 	tms    metricpb.TimedMetricWithMetadatas
@@ -325,6 +376,7 @@ func (m *message) Encode(
 	payload payloadUnion,
 ) error {
 	m.shard = shard
+	m.traceContext = nil
 
 	switch payload.payloadType {
 	case untimedType:
@@ -368,6 +420,19 @@ func (m *message) Encode(
 				Type:               metricpb.MetricWithMetadatas_GAUGE_WITH_METADATAS,
 				GaugeWithMetadatas: &m.gm,
 			}
+		case metric.HistogramType:
+			value := unaggregated.HistogramWithMetadatas{
+				Histogram:       payload.untimed.metric.Histogram(),
+				StagedMetadatas: payload.untimed.metadatas,
+			}
+			if err := value.ToProto(&m.hm); err != nil {
+				return err
+			}
+
+			m.metric = metricpb.MetricWithMetadatas{
+				Type:                   metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS,
+				HistogramWithMetadatas: &m.hm,
+			}
 		default:
 			return fmt.Errorf("unrecognized metric type: %v",
 				payload.untimed.metric.Type)
@@ -441,6 +506,10 @@ func (m *message) Size() int {
 	return len(m.buf)
 }
 
+func (m *message) TraceContext() []byte {
+	return m.traceContext
+}
+
 func (m *message) Finalize(reason producer.FinalizeReason) {
 	// Return to pool.
 	m.pool.Put(m)
@@ -24,9 +24,14 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/x/instrument"
+	xopentracing "github.com/m3db/m3/src/x/opentracing"
+	"github.com/m3db/m3/src/x/sampler"
 )
 
 func TestNewM3MsgClient(t *testing.T) {
@@ -42,3 +47,38 @@ func TestNewM3MsgClient(t *testing.T) {
 	assert.NotNil(t, c)
 	assert.NoError(t, err)
 }
+
+func TestM3MsgClientPropagatesTraceContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := producer.NewMockProducer(ctrl)
+	p.EXPECT().Init().Times(2)
+	p.EXPECT().NumShards().Return(uint32(1)).Times(2)
+
+	var traceContexts [][]byte
+	p.EXPECT().Produce(gomock.Any()).DoAndReturn(func(m producer.Message) error {
+		traceContexts = append(traceContexts, m.(producer.TracedMessage).TraceContext())
+		return nil
+	}).Times(2)
+
+	tracer := mocktracer.New()
+	for _, rate := range []sampler.Rate{0, 1} {
+		opts := NewOptions().
+			SetInstrumentOptions(instrument.NewOptions().SetTracer(tracer)).
+			SetM3MsgOptions(NewM3MsgOptions().SetProducer(p).SetTraceSampleRate(rate))
+		c, err := NewM3MsgClient(opts)
+		require.NoError(t, err)
+		require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	}
+
+	// Only the sampled message carries the context of the span producing it.
+	require.Equal(t, 2, len(traceContexts))
+	require.Nil(t, traceContexts[0])
+	spans := tracer.FinishedSpans()
+	require.Equal(t, 1, len(spans))
+	require.Equal(t, m3msgProduceSpanName, spans[0].OperationName)
+	spanCtx, err := xopentracing.ExtractBytes(tracer, traceContexts[1])
+	require.NoError(t, err)
+	require.Equal(t, spans[0].SpanContext.SpanID, spanCtx.(mocktracer.MockSpanContext).SpanID)
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/msg/producer"
 	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/sampler"
 )
 
 var (
@@ -56,11 +57,20 @@ type M3MsgOptions interface {
 
 	// TimerOptions gets the instrument timer options.
 	TimerOptions() instrument.TimerOptions
+
+	// SetTraceSampleRate sets the fraction of messages traced, whose trace
+	// context is propagated to the aggregator alongside the message.
+	SetTraceSampleRate(value sampler.Rate) M3MsgOptions
+
+	// TraceSampleRate gets the fraction of messages traced, whose trace
+	// context is propagated to the aggregator alongside the message.
+	TraceSampleRate() sampler.Rate
 }
 
 type m3msgOptions struct {
-	producer     producer.Producer
-	timerOptions instrument.TimerOptions
+	producer        producer.Producer
+	timerOptions    instrument.TimerOptions
+	traceSampleRate sampler.Rate
 }
 
 // NewM3MsgOptions returns a new set of M3Msg options.
@@ -74,7 +84,7 @@ func (o *m3msgOptions) Validate() error {
 	if o.producer == nil {
 		return errM3MsgOptionsNoProducer
 	}
-	return nil
+	return o.traceSampleRate.Validate()
 }
 
 func (o *m3msgOptions) SetProducer(value producer.Producer) M3MsgOptions {
@@ -96,3 +106,13 @@ func (o *m3msgOptions) SetTimerOptions(value instrument.TimerOptions) M3MsgOptio
 func (o *m3msgOptions) TimerOptions() instrument.TimerOptions {
 	return o.timerOptions
 }
+
+func (o *m3msgOptions) SetTraceSampleRate(value sampler.Rate) M3MsgOptions {
+	opts := *o
+	opts.traceSampleRate = value
+	return &opts
+}
+
+func (o *m3msgOptions) TraceSampleRate() sampler.Rate {
+	return o.traceSampleRate
+}
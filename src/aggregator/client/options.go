@@ -226,6 +226,22 @@ type Options interface {
 
 	// RWOptions returns the RW options.
 	RWOptions() xio.Options
+
+	// SetCircuitBreakerOptions sets the options for ejecting consistently failing
+	// instances from the write path, nil disables ejecting instances.
+	SetCircuitBreakerOptions(value *CircuitBreakerOptions) Options
+
+	// CircuitBreakerOptions returns the options for ejecting consistently failing
+	// instances from the write path.
+	CircuitBreakerOptions() *CircuitBreakerOptions
+
+	// SetInstanceWriteResultFn sets the function called with the result of every
+	// write of queued buffers to an instance.
+	SetInstanceWriteResultFn(value InstanceWriteResultFn) Options
+
+	// InstanceWriteResultFn returns the function called with the result of every
+	// write of queued buffers to an instance.
+	InstanceWriteResultFn() InstanceWriteResultFn
 }
 
 type options struct {
@@ -236,6 +252,8 @@ type options struct {
 	m3msgOptions               M3MsgOptions
 	connOpts                   ConnectionOptions
 	rwOpts                     xio.Options
+	circuitBreakerOpts         *CircuitBreakerOptions
+	instanceWriteResultFn      InstanceWriteResultFn
 	shardFn                    sharding.ShardFn
 	shardCutoffLingerDuration  time.Duration
 	shardCutoverWarmupDuration time.Duration
@@ -285,6 +303,9 @@ func (o *options) Validate() error {
 		if o.watcherOpts == nil {
 			return errTCPClientNoWatcherOptions
 		}
+		if o.circuitBreakerOpts != nil {
+			return o.circuitBreakerOpts.Validate()
+		}
 		return nil
 	default:
 		return fmt.Errorf("unknown client type: %v", o.aggregatorClientType)
@@ -464,3 +485,23 @@ func (o *options) SetRWOptions(value xio.Options) Options {
 func (o *options) RWOptions() xio.Options {
 	return o.rwOpts
 }
+
+func (o *options) SetCircuitBreakerOptions(value *CircuitBreakerOptions) Options {
+	opts := *o
+	opts.circuitBreakerOpts = value
+	return &opts
+}
+
+func (o *options) CircuitBreakerOptions() *CircuitBreakerOptions {
+	return o.circuitBreakerOpts
+}
+
+func (o *options) SetInstanceWriteResultFn(value InstanceWriteResultFn) Options {
+	opts := *o
+	opts.instanceWriteResultFn = value
+	return &opts
+}
+
+func (o *options) InstanceWriteResultFn() InstanceWriteResultFn {
+	return o.instanceWriteResultFn
+}
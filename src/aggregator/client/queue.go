@@ -29,6 +29,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/metrics/encoding"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 
 	"github.com/uber-go/tally"
@@ -116,6 +117,13 @@ type instanceQueue interface {
 
 	// Flush flushes the queue, it blocks until the queue is drained.
 	Flush()
+
+	// Capabilities returns the capabilities negotiated with the instance.
+	Capabilities() encoding.Capabilities
+
+	// ShardIngestionPaused returns true if the instance notified that the
+	// ingestion of the shard is paused.
+	ShardIngestionPaused(shard uint32) bool
 }
 
 type writeFn func([]byte) error
@@ -126,6 +134,7 @@ type queue struct {
 	conn     *connection
 	log      *zap.Logger
 	writeFn  writeFn
+	resultFn InstanceWriteResultFn
 	buf      qbuf
 	dropType DropType
 	closed   atomic.Bool
@@ -156,6 +165,7 @@ func newInstanceQueue(instance placement.Instance, opts Options) instanceQueue {
 		metrics:  newQueueMetrics(iOpts.MetricsScope()),
 		instance: instance,
 		conn:     conn,
+		resultFn: opts.InstanceWriteResultFn(),
 		buf: qbuf{
 			b: make([]protobuf.Buffer, int(qsize)),
 		},
@@ -277,7 +287,11 @@ func (q *queue) flush(tmpWriteBuf *[]byte) (int, error) {
 		return n, io.EOF
 	}
 
-	if err := q.writeFn(*tmpWriteBuf); err != nil {
+	err := q.writeFn(*tmpWriteBuf)
+	if q.resultFn != nil {
+		q.resultFn(q.instance, err)
+	}
+	if err != nil {
 		q.metrics.connWriteErrors.Inc(1)
 		return n, err
 	}
@@ -287,6 +301,14 @@ func (q *queue) flush(tmpWriteBuf *[]byte) (int, error) {
 	return n, nil
 }
 
+func (q *queue) Capabilities() encoding.Capabilities {
+	return q.conn.Capabilities()
+}
+
+func (q *queue) ShardIngestionPaused(shard uint32) bool {
+	return q.conn.ShardIngestionPaused(shard)
+}
+
 func (q *queue) Size() int {
 	return int(q.buf.size())
 }
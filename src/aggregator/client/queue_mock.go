@@ -27,6 +27,7 @@ package client
 import (
 	"reflect"
 
+	"github.com/m3db/m3/src/metrics/encoding"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 
 	"github.com/golang/mock/gomock"
@@ -55,6 +56,20 @@ func (m *MockinstanceQueue) EXPECT() *MockinstanceQueueMockRecorder {
 	return m.recorder
 }
 
+// Capabilities mocks base method.
+func (m *MockinstanceQueue) Capabilities() encoding.Capabilities {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(encoding.Capabilities)
+	return ret0
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockinstanceQueueMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockinstanceQueue)(nil).Capabilities))
+}
+
 // Close mocks base method.
 func (m *MockinstanceQueue) Close() error {
 	m.ctrl.T.Helper()
@@ -95,6 +110,20 @@ func (mr *MockinstanceQueueMockRecorder) Flush() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockinstanceQueue)(nil).Flush))
 }
 
+// ShardIngestionPaused mocks base method.
+func (m *MockinstanceQueue) ShardIngestionPaused(arg0 uint32) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShardIngestionPaused", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShardIngestionPaused indicates an expected call of ShardIngestionPaused.
+func (mr *MockinstanceQueueMockRecorder) ShardIngestionPaused(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardIngestionPaused", reflect.TypeOf((*MockinstanceQueue)(nil).ShardIngestionPaused), arg0)
+}
+
 // Size mocks base method.
 func (m *MockinstanceQueue) Size() int {
 	m.ctrl.T.Helper()
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
+	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 )
 
@@ -151,7 +152,11 @@ func TestInstanceQueueEnqueueSuccessDrainSuccess(t *testing.T) {
 }
 
 func TestInstanceQueueEnqueueSuccessDrainError(t *testing.T) {
-	opts := testOptions()
+	var results []error
+	opts := testOptions().SetInstanceWriteResultFn(func(instance placement.Instance, err error) {
+		require.Equal(t, testPlacementInstance.ID(), instance.ID())
+		results = append(results, err)
+	})
 	queue := newInstanceQueue(testPlacementInstance, opts).(*queue)
 	drained := make(chan struct{}, 1)
 	queue.writeFn = func(data []byte) error {
@@ -165,6 +170,7 @@ func TestInstanceQueueEnqueueSuccessDrainError(t *testing.T) {
 	queue.Flush()
 	// Wait for the queue to be drained.
 	<-drained
+	require.Equal(t, []error{errTestWrite}, results)
 }
 
 func TestInstanceQueueEnqueueSuccessWriteError(t *testing.T) {
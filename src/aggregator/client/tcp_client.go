@@ -54,6 +54,7 @@ type TCPClient struct {
 	writerMgr                  instanceWriterManager
 	shardFn                    sharding.ShardFn
 	placementWatcher           placement.Watcher
+	circuitBreaker             *instanceCircuitBreaker
 	metrics                    tcpClientMetrics
 }
 
@@ -67,10 +68,24 @@ func NewTCPClient(opts Options) (*TCPClient, error) {
 		instrumentOpts   = opts.InstrumentOptions()
 		writerMgr        instanceWriterManager
 		placementWatcher placement.Watcher
+		circuitBreaker   *instanceCircuitBreaker
 	)
 
 	writerMgrScope := instrumentOpts.MetricsScope().SubScope("writer-manager")
 	writerMgrOpts := opts.SetInstrumentOptions(instrumentOpts.SetMetricsScope(writerMgrScope))
+	if cbOpts := opts.CircuitBreakerOptions(); cbOpts != nil {
+		cbScope := instrumentOpts.MetricsScope().SubScope("circuit-breaker")
+		circuitBreaker = newInstanceCircuitBreaker(*cbOpts, opts.ClockOptions(),
+			instrumentOpts.SetMetricsScope(cbScope))
+		writeResultFn := opts.InstanceWriteResultFn()
+		writerMgrOpts = writerMgrOpts.SetInstanceWriteResultFn(
+			func(instance placement.Instance, err error) {
+				circuitBreaker.ReportWriteResult(instance, err)
+				if writeResultFn != nil {
+					writeResultFn(instance, err)
+				}
+			})
+	}
 	writerMgr, err := newInstanceWriterManager(writerMgrOpts)
 	if err != nil {
 		return nil, err
@@ -95,6 +110,7 @@ func NewTCPClient(opts Options) (*TCPClient, error) {
 		writerMgr:                  writerMgr,
 		shardFn:                    opts.ShardFn(),
 		placementWatcher:           placementWatcher,
+		circuitBreaker:             circuitBreaker,
 		metrics:                    newTCPClientMetrics(instrumentOpts.MetricsScope()),
 	}, nil
 }
@@ -155,6 +171,23 @@ func (c *TCPClient) WriteUntimedGauge(
 	return c.write(gauge.ID, c.nowFn().UnixNano(), payload)
 }
 
+// WriteUntimedHistogram writes untimed histogram metrics.
+func (c *TCPClient) WriteUntimedHistogram(
+	histogram unaggregated.Histogram,
+	metadatas metadata.StagedMetadatas,
+) error {
+	payload := payloadUnion{
+		payloadType: untimedType,
+		untimed: untimedPayload{
+			metric:    histogram.ToUnion(),
+			metadatas: metadatas,
+		},
+	}
+
+	c.metrics.writeUntimedHistogram.Inc(1)
+	return c.write(histogram.ID, c.nowFn().UnixNano(), payload)
+}
+
 // WriteTimed writes timed metrics.
 func (c *TCPClient) WriteTimed(
 	metric aggregated.Metric,
@@ -280,9 +313,14 @@ func (c *TCPClient) write(
 	var (
 		shardID            = c.shardFn(metricID, uint32(placement.NumShards()))
 		instances          = placement.InstancesForShard(shardID)
+		nowNanos           int64
 		multiErr           = xerrors.NewMultiError()
 		oneOrMoreSucceeded = false
 	)
+	if c.circuitBreaker != nil {
+		nowNanos = c.nowFn().UnixNano()
+	}
+	skipMaintenance, skipEjected := c.writeableInstancesAvailable(timeNanos, nowNanos, shardID, instances)
 	for _, instance := range instances {
 		// NB(xichen): the shard should technically always be found because the instances
 		// are computed from the placement, but protect against errors here regardless.
@@ -297,6 +335,19 @@ func (c *TCPClient) write(
 			c.metrics.shardNotWriteable.Inc(1)
 			continue
 		}
+		// Instances in maintenance keep ownership of their shards but are only
+		// written to when no other instance can accept writes for the shard.
+		if skipMaintenance && instance.Maintenance() {
+			c.metrics.instanceInMaintenance.Inc(1)
+			continue
+		}
+		// Ejected instances are skipped for the cooldown period of the circuit
+		// breaker so that writes go only to the other instances owning the shard,
+		// unless every instance owning the shard is ejected.
+		if skipEjected && c.ejected(instance, nowNanos) {
+			c.metrics.instanceEjected.Inc(1)
+			continue
+		}
 		if err = c.writerMgr.Write(instance, shardID, payload); err != nil {
 			multiErr = multiErr.Add(err)
 			continue
@@ -313,6 +364,36 @@ func (c *TCPClient) write(
 	return multiErr.FinalError()
 }
 
+// writeableInstancesAvailable returns whether the shard can be written to an
+// instance that is neither in maintenance nor ejected, and whether it can be
+// written to an instance that is not ejected.
+func (c *TCPClient) writeableInstancesAvailable(
+	timeNanos int64,
+	nowNanos int64,
+	shardID uint32,
+	instances []placement.Instance,
+) (notInMaintenance bool, notEjected bool) {
+	for _, instance := range instances {
+		if c.ejected(instance, nowNanos) {
+			continue
+		}
+		shard, ok := instance.Shards().Shard(shardID)
+		if !ok || !c.shouldWriteForShard(timeNanos, shard) {
+			continue
+		}
+		notEjected = true
+		if !instance.Maintenance() {
+			notInMaintenance = true
+			break
+		}
+	}
+	return notInMaintenance, notEjected
+}
+
+func (c *TCPClient) ejected(instance placement.Instance, nowNanos int64) bool {
+	return c.circuitBreaker != nil && c.circuitBreaker.Ejected(instance.ID(), nowNanos)
+}
+
 func (c *TCPClient) shouldWriteForShard(nowNanos int64, shard shard.Shard) bool {
 	writeEarliestNanos, writeLatestNanos := c.writeTimeRangeFor(shard)
 	return nowNanos >= writeEarliestNanos && nowNanos <= writeLatestNanos
@@ -341,11 +422,14 @@ type tcpClientMetrics struct {
 	writeUntimedCounter    tally.Counter
 	writeUntimedBatchTimer tally.Counter
 	writeUntimedGauge      tally.Counter
+	writeUntimedHistogram  tally.Counter
 	writePassthrough       tally.Counter
 	writeForwarded         tally.Counter
 	flush                  tally.Counter
 	shardNotOwned          tally.Counter
 	shardNotWriteable      tally.Counter
+	instanceInMaintenance  tally.Counter
+	instanceEjected        tally.Counter
 	dropped                tally.Counter
 }
 
@@ -356,11 +440,14 @@ func newTCPClientMetrics(
 		writeUntimedCounter:    scope.Counter("writeUntimedCounter"),
 		writeUntimedBatchTimer: scope.Counter("writeUntimedBatchTimer"),
 		writeUntimedGauge:      scope.Counter("writeUntimedGauge"),
+		writeUntimedHistogram:  scope.Counter("writeUntimedHistogram"),
 		writePassthrough:       scope.Counter("writePassthrough"),
 		writeForwarded:         scope.Counter("writeForwarded"),
 		flush:                  scope.Counter("flush"),
 		shardNotOwned:          scope.Counter("shard-not-owned"),
 		shardNotWriteable:      scope.Counter("shard-not-writeable"),
+		instanceInMaintenance:  scope.Counter("instance-in-maintenance"),
+		instanceEjected:        scope.Counter("instance-ejected"),
 		dropped:                scope.Counter("dropped"),
 	}
 }
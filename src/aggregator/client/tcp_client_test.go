@@ -326,6 +326,105 @@ func TestTCPClientWriteUntimedMetricSuccess(t *testing.T) {
 	}
 }
 
+func TestTCPClientWriteUntimedMetricInstanceInMaintenance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var instancesRes []string
+	writerMgr := NewMockinstanceWriterManager(ctrl)
+	writerMgr.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			instance placement.Instance,
+			shardID uint32,
+			payload payloadUnion,
+		) error {
+			instancesRes = append(instancesRes, instance.ID())
+			return nil
+		}).
+		MinTimes(1)
+	watcher := placement.NewMockWatcher(ctrl)
+	c := mustNewTestTCPClient(t, testOptions())
+	c.nowFn = func() time.Time { return time.Unix(0, testNowNanos) }
+	c.writerMgr = writerMgr
+	c.placementWatcher = watcher
+
+	// Instances in maintenance are skipped while another instance owning
+	// the shard can accept writes.
+	p := testPlacement.Clone()
+	instance, ok := p.Instance("instance1")
+	require.True(t, ok)
+	instance.SetMaintenance(true)
+	watcher.EXPECT().Get().Return(p, nil)
+	require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	require.Equal(t, []string{"instance3"}, instancesRes)
+
+	// Instances in maintenance are still written to when every instance
+	// owning the shard is in maintenance.
+	instancesRes = instancesRes[:0]
+	instance, ok = p.Instance("instance3")
+	require.True(t, ok)
+	instance.SetMaintenance(true)
+	watcher.EXPECT().Get().Return(p, nil)
+	require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	require.Equal(t, []string{"instance1", "instance3"}, instancesRes)
+}
+
+func TestTCPClientWriteUntimedMetricInstanceEjected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var instancesRes []string
+	writerMgr := NewMockinstanceWriterManager(ctrl)
+	writerMgr.EXPECT().
+		Write(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			instance placement.Instance,
+			shardID uint32,
+			payload payloadUnion,
+		) error {
+			instancesRes = append(instancesRes, instance.ID())
+			return nil
+		}).
+		MinTimes(1)
+	watcher := placement.NewMockWatcher(ctrl)
+	opts := testOptions().SetCircuitBreakerOptions(&CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+	})
+	c := mustNewTestTCPClient(t, opts)
+	c.nowFn = func() time.Time { return time.Unix(0, testNowNanos) }
+	c.circuitBreaker.nowFn = c.nowFn
+	c.writerMgr = writerMgr
+	c.placementWatcher = watcher
+
+	// Ejected instances are skipped while another instance owning the shard
+	// can accept writes.
+	instance1, ok := testPlacement.Instance("instance1")
+	require.True(t, ok)
+	c.circuitBreaker.ReportWriteResult(instance1, errors.New("write error"))
+	watcher.EXPECT().Get().Return(testPlacement, nil)
+	require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	require.Equal(t, []string{"instance3"}, instancesRes)
+
+	// Ejected instances are still written to when every instance owning the
+	// shard is ejected.
+	instancesRes = instancesRes[:0]
+	instance3, ok := testPlacement.Instance("instance3")
+	require.True(t, ok)
+	c.circuitBreaker.ReportWriteResult(instance3, errors.New("write error"))
+	watcher.EXPECT().Get().Return(testPlacement, nil)
+	require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	require.Equal(t, []string{"instance1", "instance3"}, instancesRes)
+
+	// Ejected instances are written to again once they recover.
+	instancesRes = instancesRes[:0]
+	c.circuitBreaker.ReportWriteResult(instance3, nil)
+	watcher.EXPECT().Get().Return(testPlacement, nil)
+	require.NoError(t, c.WriteUntimedCounter(testCounter.Counter(), testStagedMetadatas))
+	require.Equal(t, []string{"instance3"}, instancesRes)
+}
+
 func TestTCPClientWriteUntimedMetricPartialError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
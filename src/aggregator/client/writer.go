@@ -40,9 +40,15 @@ import (
 )
 
 var (
+	// ErrShardIngestionPaused is returned when writing to a shard whose
+	// ingestion the instance paused, writes to the shard are rejected until
+	// the pause expires.
+	ErrShardIngestionPaused = errors.New("aggregator shard ingestion is paused")
+
 	errInstanceWriterClosed    = errors.New("instance writer is closed")
 	errUnrecognizedMetricType  = errors.New("unrecognized metric type")
 	errUnrecognizedPayloadType = errors.New("unrecognized payload type")
+	errHistogramsNotSupported  = errors.New("histograms are not supported by the instance")
 )
 
 type instanceWriter interface {
@@ -94,6 +100,13 @@ func newInstanceWriter(instance placement.Instance, opts Options) instanceWriter
 }
 
 func (w *writer) Write(shard uint32, payload payloadUnion) error {
+	// Back off from shards the instance paused the ingestion of rather than
+	// sending writes that will be rejected.
+	if w.queue.ShardIngestionPaused(shard) {
+		w.metrics.shardIngestionPaused.Inc(1)
+		return ErrShardIngestionPaused
+	}
+
 	w.RLock()
 	if w.closed {
 		w.RUnlock()
@@ -188,12 +201,18 @@ func (w *writer) encodeWithLock(
 	}
 
 	if err != nil {
-		w.metrics.encodeErrors.Inc(1)
-		w.log.Error("encode untimed metric error",
-			zap.Any("payload", payload),
-			zap.Int("payloadType", int(payload.payloadType)),
-			zap.Error(err),
-		)
+		if err == errHistogramsNotSupported {
+			// NB: this is expected while the instance runs a version without
+			// histogram support, so it is not logged to avoid flooding the logs.
+			w.metrics.unsupportedErrors.Inc(1)
+		} else {
+			w.metrics.encodeErrors.Inc(1)
+			w.log.Error("encode untimed metric error",
+				zap.Any("payload", payload),
+				zap.Int("payloadType", int(payload.payloadType)),
+				zap.Error(err),
+			)
+		}
 		// Rewind buffer and clear out the encoder error.
 		encoder.Truncate(sizeBefore) //nolint:errcheck
 		encoder.Unlock()
@@ -285,6 +304,17 @@ func (w *writer) encodeUntimedWithLock(
 				StagedMetadatas: metadatas,
 			}}
 		return encoder.EncodeMessage(msg)
+	case metric.HistogramType:
+		if !w.queue.Capabilities().Has(encoding.HistogramsCapability) {
+			return errHistogramsNotSupported
+		}
+		msg := encoding.UnaggregatedMessageUnion{
+			Type: encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: unaggregated.HistogramWithMetadatas{
+				Histogram:       metricUnion.Histogram(),
+				StagedMetadatas: metadatas,
+			}}
+		return encoder.EncodeMessage(msg)
 	default:
 	}
 
@@ -386,10 +416,12 @@ const (
 )
 
 type writerMetrics struct {
-	buffersEnqueued tally.Counter
-	encodeErrors    tally.Counter
-	enqueueErrors   tally.Counter
-	flushErrors     tally.Counter
+	buffersEnqueued      tally.Counter
+	encodeErrors         tally.Counter
+	enqueueErrors        tally.Counter
+	flushErrors          tally.Counter
+	unsupportedErrors    tally.Counter
+	shardIngestionPaused tally.Counter
 }
 
 func newWriterMetrics(s tally.Scope) writerMetrics {
@@ -398,6 +430,10 @@ func newWriterMetrics(s tally.Scope) writerMetrics {
 		encodeErrors:    s.Tagged(map[string]string{actionTag: "encode-error"}).Counter(buffersMetric),
 		enqueueErrors:   s.Tagged(map[string]string{actionTag: "enqueue-error"}).Counter(buffersMetric),
 		flushErrors:     s.Tagged(map[string]string{actionTag: "flush-error"}).Counter(buffersMetric),
+		unsupportedErrors: s.Tagged(map[string]string{actionTag: "unsupported-error"}).
+			Counter(buffersMetric),
+		shardIngestionPaused: s.Tagged(map[string]string{actionTag: "shard-ingestion-paused"}).
+			Counter(buffersMetric),
 	}
 }
 
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/encoding"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
@@ -146,10 +147,12 @@ func BenchmarkSerialWriter(b *testing.B) {
 
 type testNoOpQueue struct{}
 
-func (q testNoOpQueue) Enqueue(protobuf.Buffer) error { return nil }
-func (q testNoOpQueue) Close() error                  { return nil }
-func (q testNoOpQueue) Size() int                     { return 0 }
-func (q testNoOpQueue) Flush()                        {}
+func (q testNoOpQueue) Enqueue(protobuf.Buffer) error       { return nil }
+func (q testNoOpQueue) Close() error                        { return nil }
+func (q testNoOpQueue) Size() int                           { return 0 }
+func (q testNoOpQueue) Flush()                              {}
+func (q testNoOpQueue) Capabilities() encoding.Capabilities { return 0 }
+func (q testNoOpQueue) ShardIngestionPaused(uint32) bool    { return false }
 
 type testSerialWriter struct {
 	*writer
@@ -173,6 +173,7 @@ func TestWriterWriteUntimedCounterWithFlushingZeroSizeBefore(t *testing.T) {
 		encoder.EXPECT().Relinquish().Return(stream),
 	)
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -223,6 +224,7 @@ func TestWriterWriteUntimedCounterWithFlushingPositiveSizeBefore(t *testing.T) {
 		encoder.EXPECT().Relinquish().Return(stream),
 	)
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -498,12 +500,66 @@ func TestWriterWriteUntimedBatchTimerWriteError(t *testing.T) {
 	require.Equal(t, errTestWrite, w.Write(0, payload))
 }
 
+func TestWriterWriteUntimedHistogramNotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
+	queue.EXPECT().Capabilities().Return(encoding.Capabilities(0))
+	w := newInstanceWriter(testPlacementInstance, testOptions()).(*writer)
+	w.queue = queue
+	w.newLockedEncoderFn = func(protobuf.UnaggregatedOptions) *lockedEncoder {
+		encoder := protobuf.NewMockUnaggregatedEncoder(ctrl)
+		encoder.EXPECT().Len().Return(0)
+		encoder.EXPECT().Truncate(0).Return(nil)
+		return &lockedEncoder{UnaggregatedEncoder: encoder}
+	}
+
+	histogram := unaggregated.Histogram{
+		ID:                []byte("histogram"),
+		BucketUpperBounds: []float64{1, 10},
+		BucketCounts:      []int64{2, 3},
+		Sum:               12,
+	}
+	payload := payloadUnion{
+		payloadType: untimedType,
+		untimed: untimedPayload{
+			metric:    histogram.ToUnion(),
+			metadatas: testStagedMetadatas,
+		},
+	}
+	require.Equal(t, errHistogramsNotSupported, w.Write(0, payload))
+}
+
+func TestWriterWriteShardIngestionPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(uint32(3)).Return(true)
+	w := newInstanceWriter(testPlacementInstance, testOptions()).(*writer)
+	w.queue = queue
+
+	// Writes to a paused shard are neither encoded nor enqueued.
+	payload := payloadUnion{
+		payloadType: untimedType,
+		untimed: untimedPayload{
+			metric:    testCounter,
+			metadatas: testStagedMetadatas,
+		},
+	}
+	require.Equal(t, ErrShardIngestionPaused, w.Write(3, payload))
+	require.Empty(t, w.encodersByShard)
+}
+
 func TestWriterWriteUntimedBatchTimerEnqueueError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	errTestEnqueue := errors.New("test enqueue error")
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().Enqueue(gomock.Any()).Return(errTestEnqueue)
 	opts := testOptions().
 		SetMaxTimerBatchSize(1).
@@ -574,6 +630,7 @@ func TestWriterWriteForwardedWithFlushingZeroSizeBefore(t *testing.T) {
 		encoder.EXPECT().Relinquish().Return(stream),
 	)
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -624,6 +681,7 @@ func TestWriterWriteForwardedWithFlushingPositiveSizeBefore(t *testing.T) {
 		encoder.EXPECT().Relinquish().Return(stream),
 	)
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -688,6 +746,7 @@ func TestWriterWriteForwardedEnqueueError(t *testing.T) {
 
 	errTestEnqueue := errors.New("test enqueue error")
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().Enqueue(gomock.Any()).Return(errTestEnqueue)
 	opts := testOptions().
 		SetMaxTimerBatchSize(1).
@@ -721,6 +780,7 @@ func TestWriterFlushPartialError(t *testing.T) {
 		errTestFlush = errors.New("test flush error")
 	)
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -864,6 +924,7 @@ func testWriterConcurrentWriteStress(
 	}
 
 	queue := NewMockinstanceQueue(ctrl)
+	queue.EXPECT().ShardIngestionPaused(gomock.Any()).Return(false).AnyTimes()
 	queue.EXPECT().
 		Enqueue(gomock.Any()).
 		DoAndReturn(func(buf protobuf.Buffer) error {
@@ -31,6 +31,13 @@ const (
 
 	// A default warmup duration of 0 means there is no warmup.
 	defaultWriteNewMetricNoLimitWarmupDuration = 0
+
+	// A default entry check interval of 0 means the statically configured
+	// entry check interval is used.
+	defaultEntryCheckInterval = 0
+
+	// A default scale of 1 means the maximum allowed forwarding delay is unchanged.
+	defaultMaxAllowedForwardingDelayScale = 1.0
 )
 
 // Options provide a set of options that are configurable at runtime.
@@ -66,12 +73,63 @@ type Options interface {
 	// The warmup duration is in effect starting from the time when the first entry
 	// is insert into the shard.
 	WriteNewMetricNoLimitWarmupDuration() time.Duration
+
+	// SetEntryCheckInterval sets the interval for checking expired entries,
+	// overriding the statically configured interval if positive.
+	SetEntryCheckInterval(value time.Duration) Options
+
+	// EntryCheckInterval returns the interval for checking expired entries,
+	// overriding the statically configured interval if positive.
+	EntryCheckInterval() time.Duration
+
+	// SetMaxAllowedForwardingDelayScale sets the factor the maximum allowed
+	// forwarding delay is scaled by.
+	SetMaxAllowedForwardingDelayScale(value float64) Options
+
+	// MaxAllowedForwardingDelayScale returns the factor the maximum allowed
+	// forwarding delay is scaled by.
+	MaxAllowedForwardingDelayScale() float64
+
+	// SetTimedForResendEnabledRollupRegexps sets the regular expressions of the
+	// rollup IDs converted from untimed to timed metrics, overriding the
+	// statically configured regular expressions if not nil, an empty list
+	// converting no rollups.
+	SetTimedForResendEnabledRollupRegexps(value []string) Options
+
+	// TimedForResendEnabledRollupRegexps returns the regular expressions of the
+	// rollup IDs converted from untimed to timed metrics, overriding the
+	// statically configured regular expressions if not nil, an empty list
+	// converting no rollups.
+	TimedForResendEnabledRollupRegexps() []string
+
+	// SetWritesIgnoreCutoffCutover sets whether writes to all shards are
+	// accepted regardless of the shard cutoff and cutover times, in addition
+	// to the statically configured flag.
+	SetWritesIgnoreCutoffCutover(value bool) Options
+
+	// WritesIgnoreCutoffCutover returns whether writes to all shards are
+	// accepted regardless of the shard cutoff and cutover times, in addition
+	// to the statically configured flag.
+	WritesIgnoreCutoffCutover() bool
+
+	// SetWritesIgnoreCutoffCutoverShards sets the shards whose writes are
+	// accepted regardless of the shard cutoff and cutover times.
+	SetWritesIgnoreCutoffCutoverShards(value []uint32) Options
+
+	// WritesIgnoreCutoffCutoverShards returns the shards whose writes are
+	// accepted regardless of the shard cutoff and cutover times.
+	WritesIgnoreCutoffCutoverShards() []uint32
 }
 
 type options struct {
 	writeValuesPerMetricLimitPerSecond   int64
 	writeNewMetricLimitPerShardPerSecond int64
 	writeNewMetricNoLimitWarmupDuration  time.Duration
+	entryCheckInterval                   time.Duration
+	maxAllowedForwardingDelayScale       float64
+	timedForResendEnabledRollupRegexps   []string
+	writesIgnoreCutoffCutover            bool
+	writesIgnoreCutoffCutoverShards      []uint32
 }
 
 // NewOptions creates a new set of runtime options.
@@ -80,6 +138,8 @@ func NewOptions() Options {
 		writeValuesPerMetricLimitPerSecond:   defaultWriteValuesPerMetricLimitPerSecond,
 		writeNewMetricLimitPerShardPerSecond: defaultWriteNewMetricLimitPerShardPerSecond,
 		writeNewMetricNoLimitWarmupDuration:  defaultWriteNewMetricNoLimitWarmupDuration,
+		entryCheckInterval:                   defaultEntryCheckInterval,
+		maxAllowedForwardingDelayScale:       defaultMaxAllowedForwardingDelayScale,
 	}
 }
 
@@ -112,3 +172,53 @@ func (o *options) SetWriteNewMetricNoLimitWarmupDuration(value time.Duration) Op
 func (o *options) WriteNewMetricNoLimitWarmupDuration() time.Duration {
 	return o.writeNewMetricNoLimitWarmupDuration
 }
+
+func (o *options) SetEntryCheckInterval(value time.Duration) Options {
+	opts := *o
+	opts.entryCheckInterval = value
+	return &opts
+}
+
+func (o *options) EntryCheckInterval() time.Duration {
+	return o.entryCheckInterval
+}
+
+func (o *options) SetMaxAllowedForwardingDelayScale(value float64) Options {
+	opts := *o
+	opts.maxAllowedForwardingDelayScale = value
+	return &opts
+}
+
+func (o *options) MaxAllowedForwardingDelayScale() float64 {
+	return o.maxAllowedForwardingDelayScale
+}
+
+func (o *options) SetTimedForResendEnabledRollupRegexps(value []string) Options {
+	opts := *o
+	opts.timedForResendEnabledRollupRegexps = value
+	return &opts
+}
+
+func (o *options) TimedForResendEnabledRollupRegexps() []string {
+	return o.timedForResendEnabledRollupRegexps
+}
+
+func (o *options) SetWritesIgnoreCutoffCutover(value bool) Options {
+	opts := *o
+	opts.writesIgnoreCutoffCutover = value
+	return &opts
+}
+
+func (o *options) WritesIgnoreCutoffCutover() bool {
+	return o.writesIgnoreCutoffCutover
+}
+
+func (o *options) SetWritesIgnoreCutoffCutoverShards(value []uint32) Options {
+	opts := *o
+	opts.writesIgnoreCutoffCutoverShards = value
+	return &opts
+}
+
+func (o *options) WritesIgnoreCutoffCutoverShards() []uint32 {
+	return o.writesIgnoreCutoffCutoverShards
+}
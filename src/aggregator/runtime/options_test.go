@@ -31,9 +31,19 @@ func TestOptions(t *testing.T) {
 	opts := NewOptions().
 		SetWriteValuesPerMetricLimitPerSecond(20).
 		SetWriteNewMetricLimitPerShardPerSecond(10).
-		SetWriteNewMetricNoLimitWarmupDuration(time.Second)
+		SetWriteNewMetricNoLimitWarmupDuration(time.Second).
+		SetEntryCheckInterval(time.Minute).
+		SetMaxAllowedForwardingDelayScale(2).
+		SetTimedForResendEnabledRollupRegexps([]string{"^foo"}).
+		SetWritesIgnoreCutoffCutover(true).
+		SetWritesIgnoreCutoffCutoverShards([]uint32{1, 3})
 
 	require.Equal(t, int64(20), opts.WriteValuesPerMetricLimitPerSecond())
 	require.Equal(t, int64(10), opts.WriteNewMetricLimitPerShardPerSecond())
 	require.Equal(t, time.Second, opts.WriteNewMetricNoLimitWarmupDuration())
+	require.Equal(t, time.Minute, opts.EntryCheckInterval())
+	require.Equal(t, 2.0, opts.MaxAllowedForwardingDelayScale())
+	require.Equal(t, []string{"^foo"}, opts.TimedForResendEnabledRollupRegexps())
+	require.True(t, opts.WritesIgnoreCutoffCutover())
+	require.Equal(t, []uint32{1, 3}, opts.WritesIgnoreCutoffCutoverShards())
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	"github.com/m3db/m3/src/metrics/policy"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"google.golang.org/grpc"
+)
+
+// peerDigestFetcher fetches the window digests computed by the peers of an
+// aggregator through their admin services.
+type peerDigestFetcher struct {
+	peers    []string
+	dialOpts []grpc.DialOption
+}
+
+// NewPeerDigestFetcher creates a fetcher of the window digests computed by the
+// peers from the admin services at the given addresses.
+func NewPeerDigestFetcher(
+	peers []string,
+	dialOpts ...grpc.DialOption,
+) aggregator.PeerDigestFetcher {
+	return &peerDigestFetcher{
+		peers:    peers,
+		dialOpts: append([]grpc.DialOption{grpc.WithInsecure()}, dialOpts...),
+	}
+}
+
+func (f *peerDigestFetcher) Fetch(
+	ctx context.Context,
+	shard uint32,
+	start, end time.Time,
+) (map[string][]aggregator.WindowDigest, error) {
+	req := &adminpb.ShardDigestsRequest{
+		ShardId:    shard,
+		StartNanos: start.UnixNano(),
+		EndNanos:   end.UnixNano(),
+	}
+	var (
+		result   = make(map[string][]aggregator.WindowDigest, len(f.peers))
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, peer := range f.peers {
+		digests, err := f.fetchShardDigests(ctx, peer, req)
+		if err != nil {
+			multiErr = multiErr.Add(fmt.Errorf("could not fetch digests from peer %s: %w", peer, err))
+			continue
+		}
+		result[peer] = digests
+	}
+	return result, multiErr.FinalError()
+}
+
+func (f *peerDigestFetcher) fetchShardDigests(
+	ctx context.Context,
+	peer string,
+	req *adminpb.ShardDigestsRequest,
+) ([]aggregator.WindowDigest, error) {
+	conn, err := grpc.DialContext(ctx, peer, f.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() // nolint: errcheck
+
+	resp, err := adminpb.NewAdminClient(conn).ShardDigests(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]aggregator.WindowDigest, 0, len(resp.Digests))
+	for _, digest := range resp.Digests {
+		sp, err := policy.ParseStoragePolicy(digest.StoragePolicy)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, aggregator.WindowDigest{
+			StoragePolicy: sp,
+			TimeNanos:     digest.TimeNanos,
+			Count:         digest.Count,
+			Hash:          digest.Hash,
+			ValueSum:      digest.ValueSum,
+			AbsValueSum:   digest.AbsValueSum,
+		})
+	}
+	return digests, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/policy"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerDigestFetcherFetch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		start   = time.Unix(0, 0)
+		end     = time.Unix(60, 0)
+		digests = []aggregator.WindowDigest{
+			{
+				StoragePolicy: policy.NewStoragePolicy(10*time.Second, xtime.Second, 48*time.Hour),
+				TimeNanos:     int64(10 * time.Second),
+				Count:         3,
+				Hash:          12345,
+			},
+		}
+	)
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().WindowDigests(uint32(1), start, end).Return(digests, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewServer("", agg, NewOptions())
+	require.NoError(t, server.Serve(listener))
+	defer server.Close()
+
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachableAddr := unreachable.Addr().String()
+	require.NoError(t, unreachable.Close())
+
+	// The digests of the peers that responded are returned along with the error.
+	fetcher := NewPeerDigestFetcher([]string{unreachableAddr, listener.Addr().String()})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fetched, err := fetcher.Fetch(ctx, 1, start, end)
+	require.Error(t, err)
+	require.Equal(t, map[string][]aggregator.WindowDigest{
+		listener.Addr().String(): digests,
+	}, fetched)
+}
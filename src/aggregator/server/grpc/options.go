@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/wal"
+)
+
+// Options is a set of server options.
+type Options interface {
+	// SetPlacementManager sets the placement manager the placement is served from.
+	SetPlacementManager(value aggregator.PlacementManager) Options
+
+	// PlacementManager returns the placement manager the placement is served from.
+	PlacementManager() aggregator.PlacementManager
+
+	// SetFlushTimesManager sets the flush times manager the shard flush times
+	// are served from.
+	SetFlushTimesManager(value aggregator.FlushTimesManager) Options
+
+	// FlushTimesManager returns the flush times manager the shard flush times
+	// are served from.
+	FlushTimesManager() aggregator.FlushTimesManager
+
+	// SetWAL sets the write-ahead log the shards are replayed from.
+	SetWAL(value wal.WAL) Options
+
+	// WAL returns the write-ahead log the shards are replayed from.
+	WAL() wal.WAL
+}
+
+type options struct {
+	placementManager  aggregator.PlacementManager
+	flushTimesManager aggregator.FlushTimesManager
+	wal               wal.WAL
+}
+
+// NewOptions creates a new set of server options.
+func NewOptions() Options {
+	return &options{}
+}
+
+func (o *options) SetPlacementManager(value aggregator.PlacementManager) Options {
+	opts := *o
+	opts.placementManager = value
+	return &opts
+}
+
+func (o *options) PlacementManager() aggregator.PlacementManager {
+	return o.placementManager
+}
+
+func (o *options) SetFlushTimesManager(value aggregator.FlushTimesManager) Options {
+	opts := *o
+	opts.flushTimesManager = value
+	return &opts
+}
+
+func (o *options) FlushTimesManager() aggregator.FlushTimesManager {
+	return o.flushTimesManager
+}
+
+func (o *options) SetWAL(value wal.WAL) Options {
+	opts := *o
+	opts.wal = value
+	return &opts
+}
+
+func (o *options) WAL() wal.WAL {
+	return o.wal
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	"github.com/m3db/m3/src/aggregator/wal"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"google.golang.org/grpc"
+)
+
+var errNoPeers = errors.New("no peers to replay from")
+
+// peerReplayer replays the metrics journaled by the peers of an aggregator
+// through their admin services.
+type peerReplayer struct {
+	peers            []string
+	dialOpts         []grpc.DialOption
+	unaggregatedOpts protobuf.UnaggregatedOptions
+}
+
+// NewPeerReplayer creates a replayer fetching the metrics journaled by the
+// peers from the admin services at the given addresses, the metrics of a shard
+// are replayed from the first peer that serves them.
+func NewPeerReplayer(
+	peers []string,
+	dialOpts ...grpc.DialOption,
+) aggregator.PeerReplayer {
+	return &peerReplayer{
+		peers:            peers,
+		dialOpts:         append([]grpc.DialOption{grpc.WithInsecure()}, dialOpts...),
+		unaggregatedOpts: protobuf.NewUnaggregatedOptions(),
+	}
+}
+
+func (r *peerReplayer) Replay(
+	ctx context.Context,
+	shard uint32,
+	start, end time.Time,
+	fn wal.ReplayFn,
+) error {
+	if len(r.peers) == 0 {
+		return errNoPeers
+	}
+	req := &adminpb.ReplayShardRequest{
+		ShardId:    shard,
+		StartNanos: start.UnixNano(),
+		EndNanos:   end.UnixNano(),
+	}
+	multiErr := xerrors.NewMultiError()
+	for _, peer := range r.peers {
+		received, err := r.replayShard(ctx, peer, req, fn)
+		if err == nil {
+			return nil
+		}
+		err = fmt.Errorf("could not replay from peer %s: %w", peer, err)
+		if received {
+			// Replaying the rest of the shard from another peer would replay
+			// the metrics already received twice.
+			return err
+		}
+		multiErr = multiErr.Add(err)
+	}
+	return multiErr.FinalError()
+}
+
+// replayShard replays the metrics of a shard streamed by a peer, returning
+// whether any were received.
+func (r *peerReplayer) replayShard(
+	ctx context.Context,
+	peer string,
+	req *adminpb.ReplayShardRequest,
+	fn wal.ReplayFn,
+) (bool, error) {
+	conn, err := grpc.DialContext(ctx, peer, r.dialOpts...)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close() // nolint: errcheck
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := adminpb.NewAdminClient(conn).ReplayShard(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	received := false
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+		if err := wal.Decode(resp.Metrics, r.unaggregatedOpts, fn); err != nil {
+			return received, err
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	"github.com/m3db/m3/src/aggregator/wal"
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric/unaggregated"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCounter(id string) encoding.UnaggregatedMessageUnion {
+	return encoding.UnaggregatedMessageUnion{
+		Type: encoding.CounterWithMetadatasType,
+		CounterWithMetadatas: unaggregated.CounterWithMetadatas{
+			Counter: unaggregated.Counter{
+				ID:    []byte(id),
+				Value: 1,
+			},
+			StagedMetadatas: metadata.DefaultStagedMetadatas,
+		},
+	}
+}
+
+func TestPeerReplayerReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	w, err := wal.NewWAL(wal.NewOptions().
+		SetDir(dir).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now })))
+	require.NoError(t, err)
+	defer w.Close()
+
+	var (
+		start = now
+		end   = now.Add(2 * time.Second)
+	)
+	for _, id := range []string{"foo", "bar", "baz"} {
+		require.NoError(t, w.Write(1, testCounter(id)))
+		now = now.Add(time.Second)
+	}
+	require.NoError(t, w.Write(2, testCounter("qux")))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewServer("", aggregator.NewMockAggregator(ctrl), NewOptions().SetWAL(w))
+	require.NoError(t, server.Serve(listener))
+	defer server.Close()
+
+	// The metrics are replayed from the first peer that serves them.
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachableAddr := unreachable.Addr().String()
+	require.NoError(t, unreachable.Close())
+
+	replayer := NewPeerReplayer([]string{unreachableAddr, listener.Addr().String()})
+	var replayed []string
+	err = replayer.Replay(context.Background(), 1, start, end, func(
+		msg encoding.UnaggregatedMessageUnion,
+		_ time.Time,
+	) error {
+		replayed = append(replayed, string(msg.CounterWithMetadatas.ID))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "bar"}, replayed)
+}
+
+func TestPeerReplayerNoPeers(t *testing.T) {
+	replayer := NewPeerReplayer(nil)
+	err := replayer.Replay(context.Background(), 1, time.Now(), time.Now(), func(
+		encoding.UnaggregatedMessageUnion,
+		time.Time,
+	) error {
+		return nil
+	})
+	require.Equal(t, errNoPeers, err)
+}
+
+type testReplayShardServer struct {
+	adminpb.Admin_ReplayShardServer
+
+	responses []*adminpb.ReplayShardResponse
+}
+
+func (s *testReplayShardServer) Send(resp *adminpb.ReplayShardResponse) error {
+	metrics := append([]byte(nil), resp.Metrics...)
+	s.responses = append(s.responses, &adminpb.ReplayShardResponse{Metrics: metrics})
+	return nil
+}
+
+func TestAdminServiceReplayShardChunks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	w, err := wal.NewWAL(wal.NewOptions().
+		SetDir(dir).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now })))
+	require.NoError(t, err)
+	defer w.Close()
+
+	const numMetrics = 50000
+	for i := 0; i < numMetrics; i++ {
+		require.NoError(t, w.Write(1, testCounter(fmt.Sprintf("foo%d", i))))
+	}
+
+	service := newAdminService(aggregator.NewMockAggregator(ctrl), NewOptions().SetWAL(w))
+	stream := &testReplayShardServer{}
+	err = service.ReplayShard(&adminpb.ReplayShardRequest{
+		ShardId:    1,
+		StartNanos: now.UnixNano(),
+		EndNanos:   now.Add(time.Second).UnixNano(),
+	}, stream)
+	require.NoError(t, err)
+
+	// The metrics are streamed in chunks no larger than necessary.
+	require.True(t, len(stream.responses) > 1)
+	replayed := 0
+	for _, resp := range stream.responses {
+		require.True(t, len(resp.Metrics) < 2*replayShardChunkBytes)
+		err := wal.Decode(resp.Metrics, protobuf.NewUnaggregatedOptions(), func(
+			msg encoding.UnaggregatedMessageUnion,
+			_ time.Time,
+		) error {
+			require.Equal(t, fmt.Sprintf("foo%d", replayed), string(msg.CounterWithMetadatas.ID))
+			replayed++
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, numMetrics, replayed)
+}
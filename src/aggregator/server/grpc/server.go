@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"net"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	xserver "github.com/m3db/m3/src/x/server"
+
+	"google.golang.org/grpc"
+)
+
+// server is a gRPC server serving the aggregator admin service.
+type server struct {
+	opts       Options
+	address    string
+	aggregator aggregator.Aggregator
+	server     *grpc.Server
+	listener   net.Listener
+}
+
+// NewServer creates a new gRPC admin server.
+func NewServer(
+	address string,
+	aggregator aggregator.Aggregator,
+	opts Options,
+) xserver.Server {
+	return &server{
+		opts:       opts,
+		address:    address,
+		aggregator: aggregator,
+	}
+}
+
+func (s *server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+func (s *server) Serve(l net.Listener) error {
+	s.server = grpc.NewServer()
+	adminpb.RegisterAdminServer(s.server, newAdminService(s.aggregator, s.opts))
+
+	s.listener = l
+	s.address = l.Addr().String()
+
+	go func() {
+		s.server.Serve(l)
+	}()
+
+	return nil
+}
+
+func (s *server) Close() {
+	if s.server != nil {
+		s.server.Stop()
+	}
+	s.server = nil
+	s.listener = nil
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	"github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/aggregator/wal"
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// replayShardChunkBytes is the size the journaled metrics of a shard are
+	// streamed in, well below the default maximum gRPC message size.
+	replayShardChunkBytes = 1 << 20
+)
+
+var (
+	errPlacementManagerNotConfigured  = status.Error(codes.FailedPrecondition, "placement manager is not configured")
+	errFlushTimesManagerNotConfigured = status.Error(codes.FailedPrecondition, "flush times manager is not configured")
+	errWALNotConfigured               = status.Error(codes.FailedPrecondition, "write-ahead log is not configured")
+)
+
+// adminService serves the admin operations of an aggregator.
+type adminService struct {
+	aggregator        aggregator.Aggregator
+	placementManager  aggregator.PlacementManager
+	flushTimesManager aggregator.FlushTimesManager
+	wal               wal.WAL
+}
+
+func newAdminService(
+	aggregator aggregator.Aggregator,
+	opts Options,
+) adminpb.AdminServer {
+	return &adminService{
+		aggregator:        aggregator,
+		placementManager:  opts.PlacementManager(),
+		flushTimesManager: opts.FlushTimesManager(),
+		wal:               opts.WAL(),
+	}
+}
+
+func (s *adminService) Status(
+	_ context.Context,
+	_ *adminpb.StatusRequest,
+) (*adminpb.StatusResponse, error) {
+	runtimeStatus := s.aggregator.Status()
+	return &adminpb.StatusResponse{
+		ElectionState: electionStateProto(runtimeStatus.FlushStatus.ElectionState),
+		CanLead:       runtimeStatus.FlushStatus.CanLead,
+		ReadyStatus: &adminpb.ReadyStatus{
+			Ready:                   runtimeStatus.ReadyStatus.Ready,
+			PlacementProcessed:      runtimeStatus.ReadyStatus.PlacementProcessed,
+			ShardSetOpen:            runtimeStatus.ReadyStatus.ShardSetOpen,
+			ElectionStateDetermined: runtimeStatus.ReadyStatus.ElectionStateDetermined,
+		},
+		Shards: shardStatusesProto(runtimeStatus.Shards),
+	}, nil
+}
+
+func (s *adminService) Resign(
+	_ context.Context,
+	_ *adminpb.ResignRequest,
+) (*adminpb.ResignResponse, error) {
+	if err := s.aggregator.Resign(); err != nil {
+		return nil, err
+	}
+	return &adminpb.ResignResponse{}, nil
+}
+
+func (s *adminService) ShardFlushTimes(
+	_ context.Context,
+	req *adminpb.ShardFlushTimesRequest,
+) (*adminpb.ShardFlushTimesResponse, error) {
+	if s.flushTimesManager == nil {
+		return nil, errFlushTimesManagerNotConfigured
+	}
+	flushTimes, err := s.flushTimesManager.Get()
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Shards) == 0 {
+		return &adminpb.ShardFlushTimesResponse{FlushTimes: flushTimes}, nil
+	}
+
+	filtered := &flush.ShardSetFlushTimes{
+		ByShard: make(map[uint32]*flush.ShardFlushTimes, len(req.Shards)),
+	}
+	for _, shard := range req.Shards {
+		if shardFlushTimes, ok := flushTimes.ByShard[shard]; ok {
+			filtered.ByShard[shard] = shardFlushTimes
+		}
+	}
+	return &adminpb.ShardFlushTimesResponse{FlushTimes: filtered}, nil
+}
+
+func (s *adminService) ElectionState(
+	_ context.Context,
+	_ *adminpb.ElectionStateRequest,
+) (*adminpb.ElectionStateResponse, error) {
+	var (
+		runtimeStatus = s.aggregator.Status()
+		damping       = s.aggregator.CampaignDampingStatus()
+	)
+	resp := &adminpb.ElectionStateResponse{
+		State:           electionStateProto(runtimeStatus.FlushStatus.ElectionState),
+		StateDetermined: runtimeStatus.ReadyStatus.ElectionStateDetermined,
+		CampaignDamping: &adminpb.CampaignDampingStatus{
+			Enabled:     damping.Enabled,
+			RecentFlaps: int64(damping.RecentFlaps),
+			Suppressed:  damping.Suppressed,
+		},
+	}
+	if !damping.SuppressedUntil.IsZero() {
+		resp.CampaignDamping.SuppressedUntilNanos = damping.SuppressedUntil.UnixNano()
+	}
+	return resp, nil
+}
+
+func (s *adminService) Placement(
+	_ context.Context,
+	_ *adminpb.PlacementRequest,
+) (*adminpb.PlacementResponse, error) {
+	if s.placementManager == nil {
+		return nil, errPlacementManagerNotConfigured
+	}
+	placement, err := s.placementManager.Placement()
+	if err != nil {
+		return nil, err
+	}
+	placementProto, err := placement.Proto()
+	if err != nil {
+		return nil, err
+	}
+	return &adminpb.PlacementResponse{
+		InstanceId: s.placementManager.InstanceID(),
+		Placement:  placementProto,
+	}, nil
+}
+
+func (s *adminService) ReplayShard(
+	req *adminpb.ReplayShardRequest,
+	stream adminpb.Admin_ReplayShardServer,
+) error {
+	if s.wal == nil {
+		return errWALNotConfigured
+	}
+	var (
+		start   = time.Unix(0, req.StartNanos)
+		end     = time.Unix(0, req.EndNanos)
+		encoder = wal.NewEncoder(protobuf.NewUnaggregatedOptions())
+	)
+	send := func() error {
+		err := stream.Send(&adminpb.ReplayShardResponse{Metrics: encoder.Bytes()})
+		encoder.Reset()
+		return err
+	}
+	err := s.wal.Replay(req.ShardId, func(
+		msg encoding.UnaggregatedMessageUnion,
+		journaledAt time.Time,
+	) error {
+		if journaledAt.Before(start) || !journaledAt.Before(end) {
+			return nil
+		}
+		if err := encoder.Encode(msg, journaledAt); err != nil {
+			return err
+		}
+		if encoder.Len() < replayShardChunkBytes {
+			return nil
+		}
+		return send()
+	})
+	if err != nil {
+		return err
+	}
+	if encoder.Len() == 0 {
+		return nil
+	}
+	return send()
+}
+
+func (s *adminService) ShardDigests(
+	_ context.Context,
+	req *adminpb.ShardDigestsRequest,
+) (*adminpb.ShardDigestsResponse, error) {
+	digests, err := s.aggregator.WindowDigests(req.ShardId,
+		time.Unix(0, req.StartNanos), time.Unix(0, req.EndNanos))
+	if err != nil {
+		return nil, err
+	}
+	return &adminpb.ShardDigestsResponse{Digests: windowDigestsProto(digests)}, nil
+}
+
+func electionStateProto(state aggregator.ElectionState) adminpb.ElectionState {
+	switch state {
+	case aggregator.FollowerState:
+		return adminpb.ElectionState_FOLLOWER
+	case aggregator.PendingFollowerState:
+		return adminpb.ElectionState_PENDING_FOLLOWER
+	case aggregator.LeaderState:
+		return adminpb.ElectionState_LEADER
+	default:
+		return adminpb.ElectionState_UNKNOWN
+	}
+}
+
+func windowDigestsProto(digests []aggregator.WindowDigest) []*adminpb.WindowDigest {
+	if len(digests) == 0 {
+		return nil
+	}
+	result := make([]*adminpb.WindowDigest, 0, len(digests))
+	for _, digest := range digests {
+		result = append(result, &adminpb.WindowDigest{
+			StoragePolicy: digest.StoragePolicy.String(),
+			TimeNanos:     digest.TimeNanos,
+			Count:         digest.Count,
+			Hash:          digest.Hash,
+			ValueSum:      digest.ValueSum,
+			AbsValueSum:   digest.AbsValueSum,
+		})
+	}
+	return result
+}
+
+func shardStatusesProto(statuses []aggregator.ShardStatus) []*adminpb.ShardStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+	result := make([]*adminpb.ShardStatus, 0, len(statuses))
+	for _, status := range statuses {
+		var lastTickAtNanos int64
+		if !status.LastTickAt.IsZero() {
+			lastTickAtNanos = status.LastTickAt.UnixNano()
+		}
+		result = append(result, &adminpb.ShardStatus{
+			ShardId:                status.ShardID,
+			Writeable:              status.Writeable,
+			IngestionPaused:        status.IngestionPaused,
+			CutoverNanos:           status.CutoverTime.UnixNano(),
+			CutoffNanos:            status.CutoffTime.UnixNano(),
+			EarliestWriteableNanos: status.EarliestWriteableTime.UnixNano(),
+			LatestWriteableNanos:   status.LatestWriteableTime.UnixNano(),
+			Entries:                int64(status.Entries),
+			ExpiredEntries:         int64(status.ExpiredEntries),
+			LastTickAtNanos:        lastTickAtNanos,
+			LastTickDurationNanos:  int64(status.LastTickDuration),
+		})
+	}
+	return result
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/generated/proto/adminpb"
+	"github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/cluster/placement"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServiceStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().Status().Return(aggregator.RuntimeStatus{
+		FlushStatus: aggregator.FlushStatus{
+			ElectionState: aggregator.LeaderState,
+			CanLead:       true,
+		},
+		ReadyStatus: aggregator.ReadyStatus{
+			Ready:                   true,
+			PlacementProcessed:      true,
+			ShardSetOpen:            true,
+			ElectionStateDetermined: true,
+		},
+		Shards: []aggregator.ShardStatus{
+			{
+				ShardID:               3,
+				Writeable:             true,
+				CutoverTime:           time.Unix(0, 100),
+				CutoffTime:            time.Unix(0, 1000),
+				EarliestWriteableTime: time.Unix(0, 90),
+				LatestWriteableTime:   time.Unix(0, 1010),
+				Entries:               12,
+				ExpiredEntries:        2,
+				LastTickAt:            time.Unix(0, 500),
+				LastTickDuration:      time.Second,
+			},
+			{
+				ShardID:         4,
+				IngestionPaused: true,
+			},
+		},
+	})
+
+	service := newAdminService(agg, NewOptions())
+	resp, err := service.Status(context.Background(), &adminpb.StatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, &adminpb.StatusResponse{
+		ElectionState: adminpb.ElectionState_LEADER,
+		CanLead:       true,
+		ReadyStatus: &adminpb.ReadyStatus{
+			Ready:                   true,
+			PlacementProcessed:      true,
+			ShardSetOpen:            true,
+			ElectionStateDetermined: true,
+		},
+		Shards: []*adminpb.ShardStatus{
+			{
+				ShardId:                3,
+				Writeable:              true,
+				CutoverNanos:           100,
+				CutoffNanos:            1000,
+				EarliestWriteableNanos: 90,
+				LatestWriteableNanos:   1010,
+				Entries:                12,
+				ExpiredEntries:         2,
+				LastTickAtNanos:        500,
+				LastTickDurationNanos:  int64(time.Second),
+			},
+			{
+				ShardId:                4,
+				IngestionPaused:        true,
+				CutoverNanos:           time.Time{}.UnixNano(),
+				CutoffNanos:            time.Time{}.UnixNano(),
+				EarliestWriteableNanos: time.Time{}.UnixNano(),
+				LatestWriteableNanos:   time.Time{}.UnixNano(),
+			},
+		},
+	}, resp)
+}
+
+func TestAdminServiceResign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	errResign := errors.New("resign error")
+	agg := aggregator.NewMockAggregator(ctrl)
+	gomock.InOrder(
+		agg.EXPECT().Resign().Return(nil),
+		agg.EXPECT().Resign().Return(errResign),
+	)
+
+	service := newAdminService(agg, NewOptions())
+	_, err := service.Resign(context.Background(), &adminpb.ResignRequest{})
+	require.NoError(t, err)
+	_, err = service.Resign(context.Background(), &adminpb.ResignRequest{})
+	require.Equal(t, errResign, err)
+}
+
+func TestAdminServiceShardFlushTimes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flushTimes := &flush.ShardSetFlushTimes{
+		ByShard: map[uint32]*flush.ShardFlushTimes{
+			0: {StandardByResolution: map[int64]int64{int64(time.Second): 1000}},
+			1: {StandardByResolution: map[int64]int64{int64(time.Second): 2000}},
+			2: {Tombstoned: true},
+		},
+	}
+	flushTimesManager := aggregator.NewMockFlushTimesManager(ctrl)
+	flushTimesManager.EXPECT().Get().Return(flushTimes, nil).Times(2)
+
+	service := newAdminService(aggregator.NewMockAggregator(ctrl),
+		NewOptions().SetFlushTimesManager(flushTimesManager))
+
+	resp, err := service.ShardFlushTimes(context.Background(),
+		&adminpb.ShardFlushTimesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, flushTimes, resp.FlushTimes)
+
+	resp, err = service.ShardFlushTimes(context.Background(),
+		&adminpb.ShardFlushTimesRequest{Shards: []uint32{1, 2, 3}})
+	require.NoError(t, err)
+	require.Equal(t, &flush.ShardSetFlushTimes{
+		ByShard: map[uint32]*flush.ShardFlushTimes{
+			1: flushTimes.ByShard[1],
+			2: flushTimes.ByShard[2],
+		},
+	}, resp.FlushTimes)
+}
+
+func TestAdminServiceElectionState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	suppressedUntil := time.Unix(1234, 0)
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().Status().Return(aggregator.RuntimeStatus{
+		FlushStatus: aggregator.FlushStatus{ElectionState: aggregator.FollowerState},
+		ReadyStatus: aggregator.ReadyStatus{ElectionStateDetermined: true},
+	})
+	agg.EXPECT().CampaignDampingStatus().Return(aggregator.CampaignDampingStatus{
+		Enabled:         true,
+		RecentFlaps:     3,
+		Suppressed:      true,
+		SuppressedUntil: suppressedUntil,
+	})
+
+	service := newAdminService(agg, NewOptions())
+	resp, err := service.ElectionState(context.Background(), &adminpb.ElectionStateRequest{})
+	require.NoError(t, err)
+	require.Equal(t, &adminpb.ElectionStateResponse{
+		State:           adminpb.ElectionState_FOLLOWER,
+		StateDetermined: true,
+		CampaignDamping: &adminpb.CampaignDampingStatus{
+			Enabled:              true,
+			RecentFlaps:          3,
+			Suppressed:           true,
+			SuppressedUntilNanos: suppressedUntil.UnixNano(),
+		},
+	}, resp)
+}
+
+func TestAdminServicePlacement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testPlacement := placement.NewPlacement().
+		SetInstances([]placement.Instance{
+			placement.NewInstance().
+				SetID("instance1").
+				SetEndpoint("instance1:1234").
+				SetShardSetID(1),
+		}).
+		SetShards([]uint32{0}).
+		SetReplicaFactor(1).
+		SetCutoverNanos(5678)
+	placementManager := aggregator.NewMockPlacementManager(ctrl)
+	placementManager.EXPECT().Placement().Return(testPlacement, nil)
+	placementManager.EXPECT().InstanceID().Return("instance1")
+
+	service := newAdminService(aggregator.NewMockAggregator(ctrl),
+		NewOptions().SetPlacementManager(placementManager))
+	resp, err := service.Placement(context.Background(), &adminpb.PlacementRequest{})
+	require.NoError(t, err)
+
+	expected, err := testPlacement.Proto()
+	require.NoError(t, err)
+	require.Equal(t, "instance1", resp.InstanceId)
+	require.Equal(t, expected, resp.Placement)
+}
+
+func TestAdminServiceManagersNotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := newAdminService(aggregator.NewMockAggregator(ctrl), NewOptions())
+
+	_, err := service.ShardFlushTimes(context.Background(), &adminpb.ShardFlushTimesRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = service.Placement(context.Background(), &adminpb.PlacementRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	err = service.ReplayShard(&adminpb.ReplayShardRequest{}, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -25,28 +25,62 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/policy"
 	xerrors "github.com/m3db/m3/src/x/errors"
 )
 
 // A list of HTTP endpoints.
 const (
-	HealthPath = "/health"
-	ResignPath = "/resign"
-	StatusPath = "/status"
+	HealthPath        = "/health"
+	ReadyPath         = "/ready"
+	ResignPath        = "/resign"
+	StatusPath        = "/status"
+	InProgressPath    = "/inprogress"
+	ShardRedirectPath = "/shards/redirect"
+	ShardPausePath    = "/shards/pause"
+
+	ResolutionDowngradePath = "/resolution/downgrade"
+	CampaignDampingPath     = "/campaign/damping"
+	MetricDebugPath         = "/debug/metric"
+)
+
+const (
+	inProgressIDParam            = "id"
+	inProgressStoragePolicyParam = "storagePolicy"
+	shardRedirectShardParam      = "shard"
+	shardPauseShardParam         = "shard"
+	metricDebugIDParam           = "id"
 )
 
 var (
-	errRequestMustBeGet  = xerrors.NewInvalidParamsError(errors.New("request must be GET"))
-	errRequestMustBePost = xerrors.NewInvalidParamsError(errors.New("request must be POST"))
+	errRequestMustBeGet             = xerrors.NewInvalidParamsError(errors.New("request must be GET"))
+	errRequestMustBePost            = xerrors.NewInvalidParamsError(errors.New("request must be POST"))
+	errRequestMustBeGetOrPost       = xerrors.NewInvalidParamsError(errors.New("request must be GET or POST"))
+	errInProgressIDRequired         = errors.New("at least one id is required")
+	errRequestMustBeGetPostOrDelete = xerrors.NewInvalidParamsError(errors.New("request must be GET, POST or DELETE"))
+	errRequestMustBeGetOrDelete     = xerrors.NewInvalidParamsError(errors.New("request must be GET or DELETE"))
+	errShardRedirectShardRequired   = errors.New("shard is required")
+	errShardPauseShardRequired      = errors.New("shard is required")
+	errMetricDebugIDRequired        = errors.New("id is required")
+	errResolutionDowngradeNotSet    = xerrors.NewInvalidParamsError(errors.New("resolution downgrade is not configured"))
 )
 
 func registerHandlers(mux *http.ServeMux, aggregator aggregator.Aggregator) {
 	registerHealthHandler(mux)
+	registerReadyHandler(mux, aggregator)
 	registerResignHandler(mux, aggregator)
 	registerStatusHandler(mux, aggregator)
+	registerInProgressHandler(mux, aggregator)
+	registerShardRedirectHandler(mux, aggregator)
+	registerShardPauseHandler(mux, aggregator)
+	registerResolutionDowngradeHandler(mux, aggregator)
+	registerCampaignDampingHandler(mux, aggregator)
+	registerMetricDebugHandler(mux, aggregator)
 }
 
 func registerHealthHandler(mux *http.ServeMux) {
@@ -61,6 +95,25 @@ func registerHealthHandler(mux *http.ServeMux) {
 	})
 }
 
+// registerReadyHandler registers a handler reporting whether the aggregator is
+// ready to ingest metrics, i.e. the placement has been processed, the shard set
+// is open and the election state has been determined. Load balancers should use
+// it rather than the health handler so no traffic is sent to the aggregator
+// while writes would be dropped as not owned.
+func registerReadyHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(ReadyPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if httpMethod := strings.ToUpper(r.Method); httpMethod != http.MethodGet {
+			writeErrorResponse(w, errRequestMustBeGet)
+			return
+		}
+
+		status := aggregator.Status()
+		writeReadyResponse(w, status.ReadyStatus)
+	})
+}
+
 func registerResignHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
 	mux.HandleFunc(ResignPath, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -92,6 +145,253 @@ func registerStatusHandler(mux *http.ServeMux, aggregator aggregator.Aggregator)
 	})
 }
 
+func registerInProgressHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(InProgressPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var (
+			req InProgressRequest
+			err error
+		)
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+			req, err = parseInProgressQuery(r)
+		case http.MethodPost:
+			err = json.NewDecoder(r.Body).Decode(&req)
+		default:
+			writeErrorResponse(w, errRequestMustBeGetOrPost)
+			return
+		}
+		if err != nil {
+			writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+
+		series := make([]InProgressSeries, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			datapoints := aggregator.InProgressValues(id)
+			if req.StoragePolicy != nil {
+				filtered := datapoints[:0]
+				for _, dp := range datapoints {
+					if dp.StoragePolicy.Equivalent(*req.StoragePolicy) {
+						filtered = append(filtered, dp)
+					}
+				}
+				datapoints = filtered
+			}
+			if len(datapoints) == 0 {
+				continue
+			}
+			series = append(series, InProgressSeries{ID: id, Datapoints: datapoints})
+		}
+		writeInProgressResponse(w, series)
+	})
+}
+
+// registerShardRedirectHandler registers a handler to list, set and clear
+// shard redirects at runtime, e.g. to steer traffic away from a shard whose
+// metrics are overwhelming the instance. Redirects always expire after their
+// TTL so they cannot be forgotten about.
+func registerShardRedirectHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(ShardRedirectPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req ShardRedirectRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			ttl, err := time.ParseDuration(req.TTL)
+			if err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			err = aggregator.SetShardRedirect(req.ShardID, req.RedirectToShardID, ttl)
+			if err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+		case http.MethodDelete:
+			str := r.URL.Query().Get(shardRedirectShardParam)
+			if str == "" {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(errShardRedirectShardRequired))
+				return
+			}
+			shardID, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			if err := aggregator.ClearShardRedirect(uint32(shardID)); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+		default:
+			writeErrorResponse(w, errRequestMustBeGetPostOrDelete)
+			return
+		}
+
+		writeShardRedirectResponse(w, aggregator.ShardRedirects())
+	})
+}
+
+// registerShardPauseHandler registers a handler to list, pause and resume the
+// ingestion of shards at runtime, e.g. to shed the load of some shards during
+// an incident while keeping the aggregator alive. Pauses always expire after
+// their TTL so they cannot be forgotten about.
+func registerShardPauseHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(ShardPausePath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req ShardPauseRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			ttl, err := time.ParseDuration(req.TTL)
+			if err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			if err := aggregator.PauseShardIngestion(req.ShardID, ttl); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+		case http.MethodDelete:
+			str := r.URL.Query().Get(shardPauseShardParam)
+			if str == "" {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(errShardPauseShardRequired))
+				return
+			}
+			shardID, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+			if err := aggregator.ResumeShardIngestion(uint32(shardID)); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+		default:
+			writeErrorResponse(w, errRequestMustBeGetPostOrDelete)
+			return
+		}
+
+		writeShardPauseResponse(w, aggregator.PausedShards())
+	})
+}
+
+// registerResolutionDowngradeHandler registers the handler manually enabling (POST)
+// and disabling (DELETE) the emergency resolution downgrade mode. Disabling only
+// deactivates the manual trigger, the mode stays enabled while the memory watchdog
+// keeps it enabled.
+func registerResolutionDowngradeHandler(mux *http.ServeMux, agg aggregator.Aggregator) {
+	mux.HandleFunc(ResolutionDowngradePath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		downgrader := agg.ResolutionDowngrader()
+		if downgrader == nil {
+			writeErrorResponse(w, errResolutionDowngradeNotSet)
+			return
+		}
+
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+		case http.MethodPost:
+			downgrader.Enable(aggregator.ResolutionDowngradeTriggerManual)
+		case http.MethodDelete:
+			downgrader.Disable(aggregator.ResolutionDowngradeTriggerManual)
+		default:
+			writeErrorResponse(w, errRequestMustBeGetPostOrDelete)
+			return
+		}
+
+		writeResolutionDowngradeResponse(w, downgrader.Status())
+	})
+}
+
+// registerCampaignDampingHandler registers the handler reporting (GET) and
+// clearing (DELETE) the damping of leadership flaps, e.g. to let a suppressed
+// aggregator campaign again once the etcd cluster is known to be stable.
+func registerCampaignDampingHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(CampaignDampingPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+		case http.MethodDelete:
+			aggregator.ClearCampaignDamping()
+		default:
+			writeErrorResponse(w, errRequestMustBeGetOrDelete)
+			return
+		}
+
+		writeCampaignDampingResponse(w, aggregator.CampaignDampingStatus())
+	})
+}
+
+// registerMetricDebugHandler registers a handler describing where a metric ID
+// is aggregated, i.e. the shard owning it, whether the shard is writeable and
+// the aggregations of the metric along with the last time they were flushed,
+// to diagnose metrics missing from the aggregated output. The ID is given by
+// the id query parameter of a GET request, e.g. /debug/metric?id=foo, or in
+// the body of a POST request for IDs that are not printable.
+func registerMetricDebugHandler(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+	mux.HandleFunc(MetricDebugPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req MetricDebugRequest
+		switch strings.ToUpper(r.Method) {
+		case http.MethodGet:
+			req.ID = []byte(r.URL.Query().Get(metricDebugIDParam))
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+				return
+			}
+		default:
+			writeErrorResponse(w, errRequestMustBeGetOrPost)
+			return
+		}
+		if len(req.ID) == 0 {
+			writeErrorResponse(w, xerrors.NewInvalidParamsError(errMetricDebugIDRequired))
+			return
+		}
+
+		writeMetricDebugResponse(w, req.ID, aggregator.DebugMetric(req.ID))
+	})
+}
+
+// parseInProgressQuery parses an in-progress values request from the query
+// parameters of a request, which is convenient for ad-hoc debugging of metrics
+// whose IDs are printable, e.g. /inprogress?id=foo&storagePolicy=1m:2d.
+func parseInProgressQuery(r *http.Request) (InProgressRequest, error) {
+	values := r.URL.Query()
+	ids := values[inProgressIDParam]
+	if len(ids) == 0 {
+		return InProgressRequest{}, errInProgressIDRequired
+	}
+
+	req := InProgressRequest{IDs: make([][]byte, 0, len(ids))}
+	for _, id := range ids {
+		req.IDs = append(req.IDs, []byte(id))
+	}
+	if str := values.Get(inProgressStoragePolicyParam); str != "" {
+		storagePolicy, err := policy.ParseStoragePolicy(str)
+		if err != nil {
+			return InProgressRequest{}, err
+		}
+		req.StoragePolicy = &storagePolicy
+	}
+	return req, nil
+}
+
 // Response is an HTTP response.
 type Response struct {
 	State string `json:"state,omitempty"`
@@ -104,12 +404,128 @@ type StatusResponse struct {
 	Status aggregator.RuntimeStatus `json:"status,omitempty"`
 }
 
+// ReadyResponse is a readiness response, with state "OK" if the aggregator is
+// ready to ingest metrics and "NOT_READY" otherwise.
+type ReadyResponse struct {
+	Response
+	Status aggregator.ReadyStatus `json:"status"`
+}
+
+// InProgressRequest is a request for the values aggregated so far for a set of metrics,
+// optionally restricted to a single storage policy.
+type InProgressRequest struct {
+	IDs           [][]byte              `json:"ids"`
+	StoragePolicy *policy.StoragePolicy `json:"storagePolicy,omitempty"`
+}
+
+// InProgressSeries contains the values aggregated so far for a metric.
+type InProgressSeries struct {
+	ID         []byte                           `json:"id"`
+	Datapoints []aggregator.InProgressDatapoint `json:"datapoints"`
+}
+
+// InProgressResponse is an in-progress values response. Only the metrics owned by
+// the aggregator that have values aggregated so far are included.
+type InProgressResponse struct {
+	Response
+	Series []InProgressSeries `json:"series,omitempty"`
+}
+
+// ShardRedirectRequest is a request to redirect writes for a shard to another
+// shard owned by the aggregator for the duration of the TTL, e.g. "10m".
+type ShardRedirectRequest struct {
+	ShardID           uint32 `json:"shardID"`
+	RedirectToShardID uint32 `json:"redirectToShardID"`
+	TTL               string `json:"ttl"`
+}
+
+// ShardRedirectResponse is a shard redirects response, containing the
+// redirects set at runtime that have not expired yet.
+type ShardRedirectResponse struct {
+	Response
+	Redirects []aggregator.ShardRedirect `json:"redirects"`
+}
+
+// ShardPauseRequest is a request to pause the ingestion of a shard for the
+// duration of the TTL, e.g. "10m".
+type ShardPauseRequest struct {
+	ShardID uint32 `json:"shardID"`
+	TTL     string `json:"ttl"`
+}
+
+// ShardPauseResponse is a shard pauses response, containing the pauses of the
+// ingestion of shards that have not expired yet.
+type ShardPauseResponse struct {
+	Response
+	Paused []aggregator.ShardIngestionPause `json:"paused"`
+}
+
+// ResolutionDowngradePolicy is a storage policy whose output resolution is
+// coarsened to the given resolution while the resolution downgrade mode is enabled.
+type ResolutionDowngradePolicy struct {
+	StoragePolicy string `json:"storagePolicy"`
+	Resolution    string `json:"resolution"`
+}
+
+// ResolutionDowngradeResponse is a resolution downgrade mode response, containing
+// whether the mode is enabled and the triggers that enabled it.
+type ResolutionDowngradeResponse struct {
+	Response
+	Enabled  bool                        `json:"enabled"`
+	Triggers []string                    `json:"triggers"`
+	Policies []ResolutionDowngradePolicy `json:"policies"`
+}
+
+// CampaignDampingResponse is a campaign damping response, containing the
+// number of leadership flaps within the damping window and whether
+// campaigning is suppressed.
+type CampaignDampingResponse struct {
+	Response
+	Status aggregator.CampaignDampingStatus `json:"status"`
+}
+
+// MetricDebugRequest is a request describing where a metric is aggregated.
+type MetricDebugRequest struct {
+	ID []byte `json:"id"`
+}
+
+// MetricDebugResponse is a metric debug response, describing the shard a
+// metric belongs to and the aggregations of the metric.
+type MetricDebugResponse struct {
+	Response
+	ID     []byte                     `json:"id"`
+	Metric aggregator.MetricDebugInfo `json:"metric"`
+}
+
 // NewResponse creates a new empty response.
 func NewResponse() Response { return Response{} }
 
 // NewStatusResponse creates a new empty status response.
 func NewStatusResponse() StatusResponse { return StatusResponse{} }
 
+// NewReadyResponse creates a new empty readiness response.
+func NewReadyResponse() ReadyResponse { return ReadyResponse{} }
+
+// NewInProgressResponse creates a new empty in-progress values response.
+func NewInProgressResponse() InProgressResponse { return InProgressResponse{} }
+
+// NewShardRedirectResponse creates a new empty shard redirects response.
+func NewShardRedirectResponse() ShardRedirectResponse { return ShardRedirectResponse{} }
+
+// NewShardPauseResponse creates a new empty shard pauses response.
+func NewShardPauseResponse() ShardPauseResponse { return ShardPauseResponse{} }
+
+// NewResolutionDowngradeResponse creates a new empty resolution downgrade response.
+func NewResolutionDowngradeResponse() ResolutionDowngradeResponse {
+	return ResolutionDowngradeResponse{}
+}
+
+// NewCampaignDampingResponse creates a new empty campaign damping response.
+func NewCampaignDampingResponse() CampaignDampingResponse { return CampaignDampingResponse{} }
+
+// NewMetricDebugResponse creates a new empty metric debug response.
+func NewMetricDebugResponse() MetricDebugResponse { return MetricDebugResponse{} }
+
 func newSuccessResponse() Response {
 	return Response{State: "OK"}
 }
@@ -137,6 +553,86 @@ func writeStatusResponse(w http.ResponseWriter, status aggregator.RuntimeStatus)
 	writeResponse(w, response, nil)
 }
 
+func writeReadyResponse(w http.ResponseWriter, status aggregator.ReadyStatus) {
+	response := NewReadyResponse()
+	response.Status = status
+	if !status.Ready {
+		response.State = "NOT_READY"
+		buf := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buf).Encode(&response); err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(buf.Bytes())
+		return
+	}
+	response.State = "OK"
+	writeResponse(w, response, nil)
+}
+
+func writeInProgressResponse(w http.ResponseWriter, series []InProgressSeries) {
+	response := NewInProgressResponse()
+	response.Series = series
+	writeResponse(w, response, nil)
+}
+
+func writeShardRedirectResponse(w http.ResponseWriter, redirects []aggregator.ShardRedirect) {
+	response := NewShardRedirectResponse()
+	response.State = "OK"
+	response.Redirects = redirects
+	if response.Redirects == nil {
+		response.Redirects = []aggregator.ShardRedirect{}
+	}
+	writeResponse(w, response, nil)
+}
+
+func writeShardPauseResponse(w http.ResponseWriter, paused []aggregator.ShardIngestionPause) {
+	response := NewShardPauseResponse()
+	response.State = "OK"
+	response.Paused = paused
+	if response.Paused == nil {
+		response.Paused = []aggregator.ShardIngestionPause{}
+	}
+	writeResponse(w, response, nil)
+}
+
+func writeResolutionDowngradeResponse(w http.ResponseWriter, status aggregator.ResolutionDowngradeStatus) {
+	response := NewResolutionDowngradeResponse()
+	response.State = "OK"
+	response.Enabled = status.Enabled
+	response.Triggers = make([]string, 0, len(status.Triggers))
+	for _, trigger := range status.Triggers {
+		response.Triggers = append(response.Triggers, string(trigger))
+	}
+	response.Policies = make([]ResolutionDowngradePolicy, 0, len(status.Policies))
+	for _, p := range status.Policies {
+		response.Policies = append(response.Policies, ResolutionDowngradePolicy{
+			StoragePolicy: p.StoragePolicy.String(),
+			Resolution:    p.Resolution.String(),
+		})
+	}
+	writeResponse(w, response, nil)
+}
+
+func writeCampaignDampingResponse(w http.ResponseWriter, status aggregator.CampaignDampingStatus) {
+	response := NewCampaignDampingResponse()
+	response.State = "OK"
+	response.Status = status
+	writeResponse(w, response, nil)
+}
+
+func writeMetricDebugResponse(w http.ResponseWriter, id []byte, info aggregator.MetricDebugInfo) {
+	response := NewMetricDebugResponse()
+	response.State = "OK"
+	response.ID = id
+	response.Metric = info
+	if response.Metric.Elements == nil {
+		response.Metric.Elements = []aggregator.MetricDebugElement{}
+	}
+	writeResponse(w, response, nil)
+}
+
 func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
 	buf := bytes.NewBuffer(nil)
 	if encodeErr := json.NewEncoder(buf).Encode(&resp); encodeErr != nil {
@@ -0,0 +1,431 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testStoragePolicy       = policy.MustParseStoragePolicy("10s:2d")
+	testCoarseStoragePolicy = policy.MustParseStoragePolicy("1m:40d")
+	testInProgressValues    = []aggregator.InProgressDatapoint{
+		{TimeNanos: int64(10 * time.Second), Value: 1, StoragePolicy: testStoragePolicy},
+		{TimeNanos: int64(time.Minute), Value: 6, StoragePolicy: testCoarseStoragePolicy},
+	}
+)
+
+func TestReadyHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	notReady := aggregator.ReadyStatus{PlacementProcessed: true}
+	ready := aggregator.ReadyStatus{
+		Ready:                   true,
+		PlacementProcessed:      true,
+		ShardSetOpen:            true,
+		ElectionStateDetermined: true,
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	gomock.InOrder(
+		agg.EXPECT().Status().Return(aggregator.RuntimeStatus{ReadyStatus: notReady}),
+		agg.EXPECT().Status().Return(aggregator.RuntimeStatus{ReadyStatus: ready}),
+	)
+
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	decoded := decodeReadyResponse(t, resp)
+	require.Equal(t, "NOT_READY", decoded.State)
+	require.Equal(t, notReady, decoded.Status)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded = decodeReadyResponse(t, resp)
+	require.Equal(t, "OK", decoded.State)
+	require.Equal(t, ready, decoded.Status)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodPost, ReadyPath, nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestInProgressHandlerPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().InProgressValues(id.RawID("foo")).Return(testInProgressValues)
+	agg.EXPECT().InProgressValues(id.RawID("bar")).Return(nil)
+
+	body, err := json.Marshal(InProgressRequest{IDs: [][]byte{[]byte("foo"), []byte("bar")}})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, InProgressPath, bytes.NewReader(body))
+
+	resp := serveRequest(agg, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []InProgressSeries{
+		{ID: []byte("foo"), Datapoints: testInProgressValues},
+	}, decodeInProgressResponse(t, resp).Series)
+}
+
+func TestInProgressHandlerGetWithStoragePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().InProgressValues(id.RawID("foo")).Return(testInProgressValues)
+
+	req := httptest.NewRequest(http.MethodGet, InProgressPath+"?id=foo&storagePolicy=1m:40d", nil)
+
+	resp := serveRequest(agg, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []InProgressSeries{
+		{ID: []byte("foo"), Datapoints: testInProgressValues[1:]},
+	}, decodeInProgressResponse(t, resp).Series)
+}
+
+func TestInProgressHandlerInvalidRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, InProgressPath, nil),
+		httptest.NewRequest(http.MethodGet, InProgressPath+"?id=foo&storagePolicy=invalid", nil),
+		httptest.NewRequest(http.MethodPost, InProgressPath, bytes.NewReader([]byte("{"))),
+		httptest.NewRequest(http.MethodDelete, InProgressPath, nil),
+	} {
+		resp := serveRequest(agg, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code, req.URL.String())
+	}
+}
+
+func TestShardRedirectHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redirects := []aggregator.ShardRedirect{
+		{ShardID: 1, RedirectToShardID: 2, ExpiresAt: time.Unix(1000, 0).UTC()},
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	gomock.InOrder(
+		agg.EXPECT().SetShardRedirect(uint32(1), uint32(2), 10*time.Minute).Return(nil),
+		agg.EXPECT().ShardRedirects().Return(redirects),
+		agg.EXPECT().ShardRedirects().Return(redirects),
+		agg.EXPECT().ClearShardRedirect(uint32(1)).Return(nil),
+		agg.EXPECT().ShardRedirects().Return(nil),
+	)
+
+	body, err := json.Marshal(ShardRedirectRequest{ShardID: 1, RedirectToShardID: 2, TTL: "10m"})
+	require.NoError(t, err)
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodPost, ShardRedirectPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, redirects, decodeShardRedirectResponse(t, resp).Redirects)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodGet, ShardRedirectPath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, redirects, decodeShardRedirectResponse(t, resp).Redirects)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodDelete, ShardRedirectPath+"?shard=1", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []aggregator.ShardRedirect{}, decodeShardRedirectResponse(t, resp).Redirects)
+}
+
+func TestShardRedirectHandlerInvalidRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().SetShardRedirect(uint32(1), uint32(1), time.Minute).
+		Return(errors.New("shard cannot be redirected to itself"))
+
+	invalidTTL, err := json.Marshal(ShardRedirectRequest{ShardID: 1, RedirectToShardID: 2, TTL: "soon"})
+	require.NoError(t, err)
+	toSelf, err := json.Marshal(ShardRedirectRequest{ShardID: 1, RedirectToShardID: 1, TTL: "1m"})
+	require.NoError(t, err)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, ShardRedirectPath, bytes.NewReader([]byte("{"))),
+		httptest.NewRequest(http.MethodPost, ShardRedirectPath, bytes.NewReader(invalidTTL)),
+		httptest.NewRequest(http.MethodPost, ShardRedirectPath, bytes.NewReader(toSelf)),
+		httptest.NewRequest(http.MethodDelete, ShardRedirectPath, nil),
+		httptest.NewRequest(http.MethodDelete, ShardRedirectPath+"?shard=abc", nil),
+		httptest.NewRequest(http.MethodPut, ShardRedirectPath, nil),
+	} {
+		resp := serveRequest(agg, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code, req.URL.String())
+	}
+}
+
+func TestShardPauseHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	paused := []aggregator.ShardIngestionPause{
+		{ShardID: 1, PausedUntil: time.Unix(1000, 0).UTC()},
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	gomock.InOrder(
+		agg.EXPECT().PauseShardIngestion(uint32(1), 10*time.Minute).Return(nil),
+		agg.EXPECT().PausedShards().Return(paused),
+		agg.EXPECT().PausedShards().Return(paused),
+		agg.EXPECT().ResumeShardIngestion(uint32(1)).Return(nil),
+		agg.EXPECT().PausedShards().Return(nil),
+	)
+
+	body, err := json.Marshal(ShardPauseRequest{ShardID: 1, TTL: "10m"})
+	require.NoError(t, err)
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodPost, ShardPausePath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, paused, decodeShardPauseResponse(t, resp).Paused)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodGet, ShardPausePath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, paused, decodeShardPauseResponse(t, resp).Paused)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodDelete, ShardPausePath+"?shard=1", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []aggregator.ShardIngestionPause{}, decodeShardPauseResponse(t, resp).Paused)
+}
+
+func TestShardPauseHandlerInvalidRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().PauseShardIngestion(uint32(100), time.Minute).
+		Return(errors.New("aggregator shard is not owned"))
+
+	invalidTTL, err := json.Marshal(ShardPauseRequest{ShardID: 1, TTL: "soon"})
+	require.NoError(t, err)
+	notOwned, err := json.Marshal(ShardPauseRequest{ShardID: 100, TTL: "1m"})
+	require.NoError(t, err)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, ShardPausePath, bytes.NewReader([]byte("{"))),
+		httptest.NewRequest(http.MethodPost, ShardPausePath, bytes.NewReader(invalidTTL)),
+		httptest.NewRequest(http.MethodPost, ShardPausePath, bytes.NewReader(notOwned)),
+		httptest.NewRequest(http.MethodDelete, ShardPausePath, nil),
+		httptest.NewRequest(http.MethodDelete, ShardPausePath+"?shard=abc", nil),
+		httptest.NewRequest(http.MethodPut, ShardPausePath, nil),
+	} {
+		resp := serveRequest(agg, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code, req.URL.String())
+	}
+}
+
+func TestResolutionDowngradeHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	downgrader, err := aggregator.NewResolutionDowngrader([]aggregator.ResolutionDowngradePolicy{
+		{StoragePolicy: testStoragePolicy, Resolution: time.Minute},
+	}, 0)
+	require.NoError(t, err)
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().ResolutionDowngrader().Return(downgrader).Times(4)
+
+	expectedPolicies := []ResolutionDowngradePolicy{
+		{StoragePolicy: "10s:2d", Resolution: "1m0s"},
+	}
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodGet, ResolutionDowngradePath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded := decodeResolutionDowngradeResponse(t, resp)
+	require.False(t, decoded.Enabled)
+	require.Equal(t, []string{}, decoded.Triggers)
+	require.Equal(t, expectedPolicies, decoded.Policies)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodPost, ResolutionDowngradePath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded = decodeResolutionDowngradeResponse(t, resp)
+	require.True(t, decoded.Enabled)
+	require.Equal(t, []string{"manual"}, decoded.Triggers)
+	require.True(t, downgrader.Enabled())
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodDelete, ResolutionDowngradePath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.False(t, decodeResolutionDowngradeResponse(t, resp).Enabled)
+	require.False(t, downgrader.Enabled())
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodPut, ResolutionDowngradePath, nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestResolutionDowngradeHandlerNotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().ResolutionDowngrader().Return(nil)
+
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodPost, ResolutionDowngradePath, nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestCampaignDampingHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	suppressedUntil := time.Unix(1234, 0).UTC()
+	agg := aggregator.NewMockAggregator(ctrl)
+	gomock.InOrder(
+		agg.EXPECT().CampaignDampingStatus().Return(aggregator.CampaignDampingStatus{
+			Enabled:         true,
+			Suppressed:      true,
+			SuppressedUntil: suppressedUntil,
+		}),
+		agg.EXPECT().ClearCampaignDamping(),
+		agg.EXPECT().CampaignDampingStatus().Return(aggregator.CampaignDampingStatus{
+			Enabled: true,
+		}),
+	)
+
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodGet, CampaignDampingPath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded := decodeCampaignDampingResponse(t, resp)
+	require.True(t, decoded.Status.Suppressed)
+	require.True(t, suppressedUntil.Equal(decoded.Status.SuppressedUntil))
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodDelete, CampaignDampingPath, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.False(t, decodeCampaignDampingResponse(t, resp).Status.Suppressed)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodPost, CampaignDampingPath, nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestMetricDebugHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lastFlushed := time.Unix(1234, 0).UTC()
+	info := aggregator.MetricDebugInfo{
+		ShardID:     3,
+		Owned:       true,
+		ShardStatus: &aggregator.ShardStatus{ShardID: 3, Writeable: true},
+		Elements: []aggregator.MetricDebugElement{
+			{
+				Category:            "untimed",
+				MetricType:          "counter",
+				StoragePolicy:       testStoragePolicy,
+				Resolution:          "10s",
+				NumInProgressValues: 2,
+				LastFlushedTime:     &lastFlushed,
+			},
+		},
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().DebugMetric(id.RawID("foo")).Return(info).Times(2)
+	agg.EXPECT().DebugMetric(id.RawID("bar")).Return(aggregator.MetricDebugInfo{ShardID: 5})
+
+	resp := serveRequest(agg, httptest.NewRequest(http.MethodGet, MetricDebugPath+"?id=foo", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded := decodeMetricDebugResponse(t, resp)
+	require.Equal(t, []byte("foo"), decoded.ID)
+	require.Equal(t, uint32(3), decoded.Metric.ShardID)
+	require.True(t, decoded.Metric.Owned)
+	require.True(t, decoded.Metric.ShardStatus.Writeable)
+	require.Equal(t, 1, len(decoded.Metric.Elements))
+	require.Equal(t, testStoragePolicy, decoded.Metric.Elements[0].StoragePolicy)
+	require.True(t, lastFlushed.Equal(*decoded.Metric.Elements[0].LastFlushedTime))
+
+	body, err := json.Marshal(MetricDebugRequest{ID: []byte("foo")})
+	require.NoError(t, err)
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodPost, MetricDebugPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, uint32(3), decodeMetricDebugResponse(t, resp).Metric.ShardID)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodGet, MetricDebugPath+"?id=bar", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	decoded = decodeMetricDebugResponse(t, resp)
+	require.False(t, decoded.Metric.Owned)
+	require.Nil(t, decoded.Metric.ShardStatus)
+	require.Equal(t, []aggregator.MetricDebugElement{}, decoded.Metric.Elements)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodGet, MetricDebugPath, nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = serveRequest(agg, httptest.NewRequest(http.MethodDelete, MetricDebugPath+"?id=foo", nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func serveRequest(agg aggregator.Aggregator, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	registerHandlers(mux, agg)
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, req)
+	return resp
+}
+
+func decodeReadyResponse(t *testing.T, resp *httptest.ResponseRecorder) ReadyResponse {
+	var decoded ReadyResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeShardRedirectResponse(t *testing.T, resp *httptest.ResponseRecorder) ShardRedirectResponse {
+	var decoded ShardRedirectResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeShardPauseResponse(t *testing.T, resp *httptest.ResponseRecorder) ShardPauseResponse {
+	var decoded ShardPauseResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeResolutionDowngradeResponse(
+	t *testing.T,
+	resp *httptest.ResponseRecorder,
+) ResolutionDowngradeResponse {
+	var decoded ResolutionDowngradeResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeInProgressResponse(t *testing.T, resp *httptest.ResponseRecorder) InProgressResponse {
+	var decoded InProgressResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeCampaignDampingResponse(t *testing.T, resp *httptest.ResponseRecorder) CampaignDampingResponse {
+	var decoded CampaignDampingResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
+
+func decodeMetricDebugResponse(t *testing.T, resp *httptest.ResponseRecorder) MetricDebugResponse {
+	var decoded MetricDebugResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return decoded
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3/src/msg/consumer"
 	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/sampler"
 	xserver "github.com/m3db/m3/src/x/server"
 )
 
@@ -56,12 +57,22 @@ type Options interface {
 
 	// ConsumerOptions returns the consumer options.
 	ConsumerOptions() consumer.Options
+
+	// SetUnknownFieldsSampleRate sets the fraction of messages checked for
+	// fields unknown to this version of the messages.
+	SetUnknownFieldsSampleRate(value sampler.Rate) Options
+
+	// UnknownFieldsSampleRate returns the fraction of messages checked for
+	// fields unknown to this version of the messages.
+	UnknownFieldsSampleRate() sampler.Rate
 }
 
 type options struct {
 	instrumentOpts instrument.Options
 	serverOpts     xserver.Options
 	consumerOpts   consumer.Options
+
+	unknownFieldsSampleRate sampler.Rate
 }
 
 // NewOptions returns a set of M3Msg options.
@@ -79,7 +90,7 @@ func (o *options) Validate() error {
 	if o.consumerOpts == nil {
 		return errNoConsumerOptions
 	}
-	return nil
+	return o.unknownFieldsSampleRate.Validate()
 }
 
 func (o *options) SetInstrumentOptions(value instrument.Options) Options {
@@ -111,3 +122,13 @@ func (o *options) SetConsumerOptions(value consumer.Options) Options {
 func (o *options) ConsumerOptions() consumer.Options {
 	return o.consumerOpts
 }
+
+func (o *options) SetUnknownFieldsSampleRate(value sampler.Rate) Options {
+	opts := *o
+	opts.unknownFieldsSampleRate = value
+	return &opts
+}
+
+func (o *options) UnknownFieldsSampleRate() sampler.Rate {
+	return o.unknownFieldsSampleRate
+}
@@ -30,14 +30,54 @@ import (
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
 	"github.com/m3db/m3/src/msg/consumer"
+	xopentracing "github.com/m3db/m3/src/x/opentracing"
+	"github.com/m3db/m3/src/x/sampler"
 	xserver "github.com/m3db/m3/src/x/server"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 )
 
+const m3msgConsumeSpanName = "m3msg.consume"
+
+type serverMetrics struct {
+	unknownMessageType tally.Counter
+	unknownFieldsError tally.Counter
+	// ingestionPaused counts the messages left unacked for the producer to
+	// retry since the ingestion of their shard is paused.
+	ingestionPaused tally.Counter
+	// unknownFields counts the fields unknown to this version of the
+	// messages by message type, which are skipped when decoding.
+	unknownFields map[metricpb.MetricWithMetadatas_Type]tally.Counter
+}
+
+func newServerMetrics(scope tally.Scope) serverMetrics {
+	unknownFields := make(map[metricpb.MetricWithMetadatas_Type]tally.Counter,
+		len(metricpb.MetricWithMetadatas_Type_name))
+	for value, name := range metricpb.MetricWithMetadatas_Type_name {
+		unknownFields[metricpb.MetricWithMetadatas_Type(value)] = scope.Tagged(map[string]string{
+			"message_type": name,
+		}).Counter("unknown-fields")
+	}
+	return serverMetrics{
+		unknownMessageType: scope.Counter("unknown-message-type"),
+		unknownFieldsError: scope.Counter("unknown-fields-error"),
+		ingestionPaused:    scope.Counter("ingestion-paused"),
+		unknownFields:      unknownFields,
+	}
+}
+
 type server struct {
 	aggregator aggregator.Aggregator
 	logger     *zap.Logger
+	metrics    serverMetrics
+	tracer     opentracing.Tracer
+
+	// unknownFieldsSampler samples the messages checked for unknown fields,
+	// since checking re-parses the message.
+	unknownFieldsSampler *sampler.Sampler
 }
 
 // NewServer creates a new M3Msg server.
@@ -50,9 +90,16 @@ func NewServer(
 		return nil, err
 	}
 
+	unknownFieldsSampler, err := sampler.NewSampler(opts.UnknownFieldsSampleRate())
+	if err != nil {
+		return nil, err
+	}
 	s := &server{
-		aggregator: aggregator,
-		logger:     opts.InstrumentOptions().Logger(),
+		aggregator:           aggregator,
+		logger:               opts.InstrumentOptions().Logger(),
+		metrics:              newServerMetrics(opts.InstrumentOptions().MetricsScope()),
+		tracer:               opts.InstrumentOptions().Tracer(),
+		unknownFieldsSampler: unknownFieldsSampler,
 	}
 
 	handler := consumer.NewConsumerHandler(s.Consume, opts.ConsumerOptions())
@@ -90,12 +137,51 @@ func (s *server) handleMessage(
 	union *encoding.UnaggregatedMessageUnion,
 	msg consumer.Message,
 ) error {
-	defer msg.Ack()
+	// NB: messages traced by the producer are consumed in a child span of the
+	// span that produced them so a write can be followed to the aggregator.
+	if traceContext := msg.TraceContext(); len(traceContext) > 0 {
+		if spanCtx, err := xopentracing.ExtractBytes(s.tracer, traceContext); err == nil {
+			span := s.tracer.StartSpan(m3msgConsumeSpanName, opentracing.ChildOf(spanCtx))
+			span.SetTag("shard", msg.ShardID())
+			defer span.Finish()
+			err := s.addAndAckMessage(pb, union, msg)
+			if err != nil {
+				ext.LogError(span, err)
+			}
+			return err
+		}
+	}
+	return s.addAndAckMessage(pb, union, msg)
+}
 
+func (s *server) addAndAckMessage(
+	pb *metricpb.MetricWithMetadatas,
+	union *encoding.UnaggregatedMessageUnion,
+	msg consumer.Message,
+) error {
+	err := s.addMessage(pb, union, msg)
+	if errors.Is(err, aggregator.ErrShardIngestionPaused) {
+		// Leave the message unacked so the producer retries it later, by when
+		// the ingestion of the shard is hopefully resumed.
+		s.metrics.ingestionPaused.Inc(1)
+		return nil
+	}
+	msg.Ack()
+	return err
+}
+
+func (s *server) addMessage(
+	pb *metricpb.MetricWithMetadatas,
+	union *encoding.UnaggregatedMessageUnion,
+	msg consumer.Message,
+) error {
 	// Unmarshal the message.
 	if err := pb.Unmarshal(msg.Bytes()); err != nil {
 		return err
 	}
+	if s.unknownFieldsSampler.Sample() {
+		s.reportUnknownFields(pb, msg.Bytes())
+	}
 
 	switch pb.Type {
 	case metricpb.MetricWithMetadatas_COUNTER_WITH_METADATAS:
@@ -119,6 +205,13 @@ func (s *server) handleMessage(
 		}
 		u := union.GaugeWithMetadatas.ToUnion()
 		return s.aggregator.AddUntimed(u, union.GaugeWithMetadatas.StagedMetadatas)
+	case metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS:
+		err := union.HistogramWithMetadatas.FromProto(pb.HistogramWithMetadatas)
+		if err != nil {
+			return err
+		}
+		u := union.HistogramWithMetadatas.ToUnion()
+		return s.aggregator.AddUntimed(u, union.HistogramWithMetadatas.StagedMetadatas)
 	case metricpb.MetricWithMetadatas_FORWARDED_METRIC_WITH_METADATA:
 		err := union.ForwardedMetricWithMetadata.FromProto(pb.ForwardedMetricWithMetadata)
 		if err != nil {
@@ -144,6 +237,25 @@ func (s *server) handleMessage(
 			union.TimedMetricWithMetadatas.Metric,
 			union.TimedMetricWithMetadatas.StagedMetadatas)
 	default:
+		// Likely a message type added by a newer version of the clients.
+		s.metrics.unknownMessageType.Inc(1)
 		return fmt.Errorf("unrecognized message type: %v", pb.Type)
 	}
 }
+
+// reportUnknownFields reports the fields skipped when unmarshalling a sampled
+// message, likely fields added by a newer version of the clients, so that
+// mixed version fleets can be monitored during upgrades.
+func (s *server) reportUnknownFields(pb *metricpb.MetricWithMetadatas, b []byte) {
+	n, err := protobuf.UnknownFields(pb, b)
+	if err != nil {
+		s.metrics.unknownFieldsError.Inc(1)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	if counter, ok := s.metrics.unknownFields[pb.Type]; ok {
+		counter.Inc(int64(n))
+	}
+}
@@ -34,6 +34,9 @@ const (
 
 	// The default read buffer size for raw TCP connections.
 	defaultReadBufferSize = 65536
+
+	// The default maximum number of passthrough metrics added as a batch.
+	defaultPassthroughBatchSize = 256
 )
 
 // Options provide a set of server options.
@@ -68,6 +71,14 @@ type Options interface {
 	// ReadBufferSize returns the read buffer size.
 	ReadBufferSize() int
 
+	// SetPassthroughBatchSize sets the maximum number of consecutive passthrough
+	// metrics read from a connection that are added to the aggregator as a batch.
+	SetPassthroughBatchSize(value int) Options
+
+	// PassthroughBatchSize returns the maximum number of consecutive passthrough
+	// metrics read from a connection that are added to the aggregator as a batch.
+	PassthroughBatchSize() int
+
 	// SetErrorLogLimitPerSecond sets the error log limit per second.
 	SetErrorLogLimitPerSecond(value int64) Options
 
@@ -87,6 +98,7 @@ type options struct {
 	serverOpts           server.Options
 	protobufItOpts       protobuf.UnaggregatedOptions
 	readBufferSize       int
+	passthroughBatchSize int
 	errLogLimitPerSecond int64
 	rwOpts               xio.Options
 }
@@ -99,6 +111,7 @@ func NewOptions() Options {
 		serverOpts:           server.NewOptions(),
 		protobufItOpts:       protobuf.NewUnaggregatedOptions(),
 		readBufferSize:       defaultReadBufferSize,
+		passthroughBatchSize: defaultPassthroughBatchSize,
 		errLogLimitPerSecond: defaultErrorLogLimitPerSecond,
 		rwOpts:               xio.NewOptions(),
 	}
@@ -154,6 +167,16 @@ func (o *options) ReadBufferSize() int {
 	return o.readBufferSize
 }
 
+func (o *options) SetPassthroughBatchSize(value int) Options {
+	opts := *o
+	opts.passthroughBatchSize = value
+	return &opts
+}
+
+func (o *options) PassthroughBatchSize() int {
+	return o.passthroughBatchSize
+}
+
 func (o *options) SetErrorLogLimitPerSecond(value int64) Options {
 	opts := *o
 	opts.errLogLimitPerSecond = value
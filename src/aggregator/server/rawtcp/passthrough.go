@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rawtcp
+
+import (
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/policy"
+)
+
+// passthroughBatch accumulates consecutive passthrough metrics read from a
+// connection so they are added to the aggregator as a batch. The iterator
+// reuses the bytes of the metrics across messages, so the IDs and annotations
+// are copied into a buffer reused across batches since the aggregator does not
+// retain them once the batch is added.
+type passthroughBatch struct {
+	maxSize int
+	metrics []aggregated.PassthroughMetricWithMetadata
+	buf     []byte
+}
+
+func newPassthroughBatch(maxSize int) *passthroughBatch {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	return &passthroughBatch{maxSize: maxSize}
+}
+
+// Add adds a passthrough metric to the batch.
+func (b *passthroughBatch) Add(m aggregated.Metric, sp policy.StoragePolicy) {
+	m.ID = b.copyBytes(m.ID)
+	m.Annotation = b.copyBytes(m.Annotation)
+	if len(m.Exemplars) > 0 {
+		exemplars := make([]metric.Exemplar, 0, len(m.Exemplars))
+		for _, e := range m.Exemplars {
+			exemplars = append(exemplars, e.Clone())
+		}
+		m.Exemplars = exemplars
+	}
+	b.metrics = append(b.metrics, aggregated.PassthroughMetricWithMetadata{
+		Metric:        m,
+		StoragePolicy: sp,
+	})
+}
+
+// Len returns the number of metrics in the batch.
+func (b *passthroughBatch) Len() int { return len(b.metrics) }
+
+// Full returns true if the batch has reached its maximum size.
+func (b *passthroughBatch) Full() bool { return len(b.metrics) >= b.maxSize }
+
+// Metrics returns the metrics in the batch, valid until the batch is reset.
+func (b *passthroughBatch) Metrics() []aggregated.PassthroughMetricWithMetadata {
+	return b.metrics
+}
+
+// Reset empties the batch for reuse.
+func (b *passthroughBatch) Reset() {
+	for i := range b.metrics {
+		b.metrics[i] = aggregated.PassthroughMetricWithMetadata{}
+	}
+	b.metrics = b.metrics[:0]
+	b.buf = b.buf[:0]
+}
+
+func (b *passthroughBatch) copyBytes(src []byte) []byte {
+	if src == nil {
+		return nil
+	}
+	// NB: growing the buffer leaves the bytes copied so far in the previous
+	// buffer, which remains referenced by the metrics already in the batch.
+	start := len(b.buf)
+	b.buf = append(b.buf, src...)
+	end := len(b.buf)
+	return b.buf[start:end:end]
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rawtcp
+
+import (
+	"context"
+	"io"
+)
+
+// numReadAheadBuffers is the number of buffers read ahead of the metrics being
+// added, so that a dropped connection is noticed while a write is in flight.
+const numReadAheadBuffers = 4
+
+// connReader reads from a connection in a separate goroutine ahead of the
+// reader, and cancels the context of the connection as soon as reading fails
+// rather than once the reader gets to the failure. An io.EOF means the client
+// finished writing and closed its side of the connection, the data read
+// before it is still to be processed so the context is not cancelled.
+type connReader struct {
+	conn   io.Reader
+	cancel context.CancelFunc
+
+	filled chan []byte
+	free   chan []byte
+	doneCh chan struct{}
+	err    error
+
+	buf  []byte
+	curr []byte
+}
+
+func newConnReader(
+	conn io.Reader,
+	bufferSize int,
+	cancel context.CancelFunc,
+) *connReader {
+	r := &connReader{
+		conn:   conn,
+		cancel: cancel,
+		filled: make(chan []byte, numReadAheadBuffers),
+		free:   make(chan []byte, numReadAheadBuffers),
+		doneCh: make(chan struct{}),
+	}
+	for i := 0; i < numReadAheadBuffers; i++ {
+		r.free <- make([]byte, bufferSize)
+	}
+	go r.readLoop()
+	return r
+}
+
+func (r *connReader) readLoop() {
+	defer close(r.filled)
+
+	for {
+		var buf []byte
+		select {
+		case buf = <-r.free:
+		case <-r.doneCh:
+			return
+		}
+
+		n, err := r.conn.Read(buf[:cap(buf)])
+		if n > 0 {
+			select {
+			case r.filled <- buf[:n]:
+			case <-r.doneCh:
+				return
+			}
+		} else {
+			r.free <- buf
+		}
+		if err != nil {
+			// NB: err is read by Read once filled is closed.
+			r.err = err
+			if err != io.EOF {
+				r.cancel()
+			}
+			return
+		}
+	}
+}
+
+func (r *connReader) Read(p []byte) (int, error) {
+	if len(r.curr) == 0 {
+		if r.buf != nil {
+			r.free <- r.buf[:cap(r.buf)]
+			r.buf = nil
+		}
+		buf, ok := <-r.filled
+		if !ok {
+			return 0, r.err
+		}
+		r.buf, r.curr = buf, buf
+	}
+	n := copy(p, r.curr)
+	r.curr = r.curr[n:]
+	return n, nil
+}
+
+// Close stops reading ahead, the read loop returns once the pending read of
+// the connection returns, i.e. at the latest when the connection is closed.
+func (r *connReader) Close() {
+	close(r.doneCh)
+}
@@ -22,6 +22,8 @@ package rawtcp
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -35,7 +37,6 @@ import (
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
-	"github.com/m3db/m3/src/metrics/policy"
 	xio "github.com/m3db/m3/src/x/io"
 	xserver "github.com/m3db/m3/src/x/server"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -65,6 +66,11 @@ type handlerMetrics struct {
 	unknownErrorTypeErrors   tally.Counter
 	decodeErrors             tally.Counter
 	errLogRateLimited        tally.Counter
+	handshakes               tally.Counter
+	handshakeErrors          tally.Counter
+	ingestionPaused          tally.Counter
+	ingestionPausedNotices   tally.Counter
+	ingestionPausedErrors    tally.Counter
 }
 
 func newHandlerMetrics(scope tally.Scope) handlerMetrics {
@@ -77,20 +83,32 @@ func newHandlerMetrics(scope tally.Scope) handlerMetrics {
 		unknownErrorTypeErrors:   scope.Counter("unknown-error-type-errors"),
 		decodeErrors:             scope.Counter("decode-errors"),
 		errLogRateLimited:        scope.Counter("error-log-rate-limited"),
+		handshakes:               scope.Counter("handshakes"),
+		handshakeErrors:          scope.Counter("handshake-errors"),
+		ingestionPaused:          scope.Counter("ingestion-paused"),
+		ingestionPausedNotices:   scope.Counter("ingestion-paused-notices"),
+		ingestionPausedErrors:    scope.Counter("ingestion-paused-notice-errors"),
 	}
 }
 
 type handler struct {
 	sync.Mutex
 
-	aggregator     aggregator.Aggregator
-	log            *zap.Logger
-	readBufferSize int
-	protobufItOpts protobuf.UnaggregatedOptions
+	aggregator           aggregator.Aggregator
+	log                  *zap.Logger
+	readBufferSize       int
+	passthroughBatchSize int
+	protobufItOpts       protobuf.UnaggregatedOptions
 
 	errLogRateLimiter *rate.Limiter
 	metrics           handlerMetrics
 
+	// ctx is cancelled when the handler is closed, and is the parent of the
+	// context of each connection so that writes still in flight for open
+	// connections are abandoned rather than completed.
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	opts Options
 }
 
@@ -101,14 +119,18 @@ func NewHandler(aggregator aggregator.Aggregator, opts Options) xserver.Handler
 	if rateLimit := opts.ErrorLogLimitPerSecond(); rateLimit != 0 {
 		limiter = rate.NewLimiter(rateLimit)
 	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return &handler{
-		aggregator:        aggregator,
-		log:               iOpts.Logger(),
-		readBufferSize:    opts.ReadBufferSize(),
-		protobufItOpts:    opts.ProtobufUnaggregatedIteratorOptions(),
-		errLogRateLimiter: limiter,
-		metrics:           newHandlerMetrics(iOpts.MetricsScope()),
-		opts:              opts,
+		aggregator:           aggregator,
+		log:                  iOpts.Logger(),
+		readBufferSize:       opts.ReadBufferSize(),
+		passthroughBatchSize: opts.PassthroughBatchSize(),
+		protobufItOpts:       opts.ProtobufUnaggregatedIteratorOptions(),
+		errLogRateLimiter:    limiter,
+		metrics:              newHandlerMetrics(iOpts.MetricsScope()),
+		ctx:                  ctx,
+		cancel:               cancel,
+		opts:                 opts,
 	}
 }
 
@@ -118,43 +140,75 @@ func (s *handler) Handle(conn net.Conn) {
 		remoteAddress = remoteAddr.String()
 	}
 
+	// NB: each connection has its own context, cancelled as soon as reading
+	// from the connection fails, e.g. because it was dropped by the client or
+	// closed by the server, or when the handler is closed, so that metrics
+	// being added on behalf of the connection are abandoned. The connection
+	// is read from ahead of the metrics being added to notice failures while
+	// a metric is being added.
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	connReader := newConnReader(conn, s.readBufferSize, cancel)
+	defer connReader.Close()
+
 	nowFn := s.opts.ClockOptions().NowFn()
 	rOpts := xio.ResettableReaderOptions{ReadBufferSize: s.readBufferSize}
-	read := s.opts.RWOptions().ResettableReaderFn()(conn, rOpts)
+	read := s.opts.RWOptions().ResettableReaderFn()(connReader, rOpts)
 	reader := bufio.NewReaderSize(read, s.readBufferSize)
 	it := protobuf.NewUnaggregatedIterator(reader, s.protobufItOpts)
 	defer it.Close()
 
 	// Iterate over the incoming metrics stream and queue up metrics.
 	var (
-		untimedMetric       unaggregated.MetricUnion
-		stagedMetadatas     metadata.StagedMetadatas
-		forwardedMetric     aggregated.ForwardedMetric
-		forwardMetadata     metadata.ForwardMetadata
-		timedMetric         aggregated.Metric
-		timedMetadata       metadata.TimedMetadata
-		passthroughMetric   aggregated.Metric
-		passthroughMetadata policy.StoragePolicy
-		err                 error
+		untimedMetric    unaggregated.MetricUnion
+		stagedMetadatas  metadata.StagedMetadatas
+		forwardedMetric  aggregated.ForwardedMetric
+		forwardMetadata  metadata.ForwardMetadata
+		timedMetric      aggregated.Metric
+		timedMetadata    metadata.TimedMetadata
+		passthroughBatch = newPassthroughBatch(s.passthroughBatchSize)
+		capabilities     encoding.Capabilities
+		pausedNotices    *pausedNotices
+		pausedErr        aggregator.ShardIngestionPausedError
+		err              error
 	)
 	for it.Next() {
 		current := it.Current()
+		if current.Type == encoding.PassthroughMetricWithMetadataType {
+			// NB: consecutive passthrough metrics are added as a batch, which is
+			// added once full or once no more data is buffered so that metrics
+			// are not held back waiting on the connection.
+			passthroughBatch.Add(current.PassthroughMetricWithMetadata.Metric,
+				current.PassthroughMetricWithMetadata.StoragePolicy)
+			if passthroughBatch.Full() || reader.Buffered() == 0 {
+				s.addPassthroughBatch(passthroughBatch, remoteAddress)
+			}
+			continue
+		}
+		// Preserve the order of the metrics read from the connection.
+		s.addPassthroughBatch(passthroughBatch, remoteAddress)
+
 		switch current.Type {
 		case encoding.CounterWithMetadatasType:
 			untimedMetric = current.CounterWithMetadatas.Counter.ToUnion()
 			untimedMetric.Annotation = current.CounterWithMetadatas.Annotation
 			stagedMetadatas = current.CounterWithMetadatas.StagedMetadatas
-			err = s.aggregator.AddUntimed(untimedMetric, stagedMetadatas)
+			err = s.aggregator.AddUntimedWithContext(ctx, untimedMetric, stagedMetadatas)
 		case encoding.BatchTimerWithMetadatasType:
 			untimedMetric = current.BatchTimerWithMetadatas.BatchTimer.ToUnion()
 			untimedMetric.Annotation = current.BatchTimerWithMetadatas.Annotation
 			stagedMetadatas = current.BatchTimerWithMetadatas.StagedMetadatas
-			err = s.aggregator.AddUntimed(untimedMetric, stagedMetadatas)
+			err = s.aggregator.AddUntimedWithContext(ctx, untimedMetric, stagedMetadatas)
 		case encoding.GaugeWithMetadatasType:
 			untimedMetric = current.GaugeWithMetadatas.Gauge.ToUnion()
 			untimedMetric.Annotation = current.GaugeWithMetadatas.Annotation
 			stagedMetadatas = current.GaugeWithMetadatas.StagedMetadatas
-			err = s.aggregator.AddUntimed(untimedMetric, stagedMetadatas)
+			err = s.aggregator.AddUntimedWithContext(ctx, untimedMetric, stagedMetadatas)
+		case encoding.HistogramWithMetadatasType:
+			untimedMetric = current.HistogramWithMetadatas.Histogram.ToUnion()
+			untimedMetric.Annotation = current.HistogramWithMetadatas.Annotation
+			stagedMetadatas = current.HistogramWithMetadatas.StagedMetadatas
+			err = s.aggregator.AddUntimedWithContext(ctx, untimedMetric, stagedMetadatas)
 		case encoding.ForwardedMetricWithMetadataType:
 			forwardedMetric = current.ForwardedMetricWithMetadata.ForwardedMetric
 			untimedMetric.Annotation = current.ForwardedMetricWithMetadata.Annotation
@@ -164,17 +218,14 @@ func (s *handler) Handle(conn net.Conn) {
 			timedMetric = current.TimedMetricWithMetadata.Metric
 			timedMetric.Annotation = current.TimedMetricWithMetadata.Annotation
 			timedMetadata = current.TimedMetricWithMetadata.TimedMetadata
-			err = s.aggregator.AddTimed(timedMetric, timedMetadata)
+			err = s.aggregator.AddTimedWithContext(ctx, timedMetric, timedMetadata)
 		case encoding.TimedMetricWithMetadatasType:
 			timedMetric = current.TimedMetricWithMetadatas.Metric
 			timedMetric.Annotation = current.TimedMetricWithMetadatas.Annotation
 			stagedMetadatas = current.TimedMetricWithMetadatas.StagedMetadatas
 			err = s.aggregator.AddTimedWithStagedMetadatas(timedMetric, stagedMetadatas)
-		case encoding.PassthroughMetricWithMetadataType:
-			passthroughMetric = current.PassthroughMetricWithMetadata.Metric
-			passthroughMetric.Annotation = current.PassthroughMetricWithMetadata.Annotation
-			passthroughMetadata = current.PassthroughMetricWithMetadata.StoragePolicy
-			err = s.aggregator.AddPassthrough(passthroughMetric, passthroughMetadata)
+		case encoding.HandshakeType:
+			capabilities, err = s.handshake(conn, remoteAddress, current.Handshake)
 		default:
 			err = newUnknownMessageTypeError(current.Type)
 		}
@@ -183,6 +234,37 @@ func (s *handler) Handle(conn net.Conn) {
 			continue
 		}
 
+		// Writes rejected because the ingestion of their shard is paused are
+		// expected, clients that support it are notified so they back off from
+		// the shard until the pause expires.
+		if errors.As(err, &pausedErr) {
+			s.metrics.ingestionPaused.Inc(1)
+			if !capabilities.Has(encoding.ShardIngestionPausedCapability) {
+				continue
+			}
+			if pausedNotices == nil {
+				pausedNotices = newPausedNotices(s.protobufItOpts)
+			}
+			notified, err := pausedNotices.notify(conn, pausedErr.ShardIngestionPause)
+			if notified {
+				s.metrics.ingestionPausedNotices.Inc(1)
+			}
+			if err == nil {
+				continue
+			}
+			s.metrics.ingestionPausedErrors.Inc(1)
+			if s.errLogRateLimiter != nil && !s.errLogRateLimiter.IsAllowed(1, xtime.ToUnixNano(nowFn())) {
+				s.metrics.errLogRateLimited.Inc(1)
+				continue
+			}
+			s.log.Error("error notifying paused shard ingestion",
+				zap.String("remoteAddress", remoteAddress),
+				zap.Uint32("shard", pausedErr.ShardID),
+				zap.Error(err),
+			)
+			continue
+		}
+
 		// We rate limit the error log here because the error rate may scale with
 		// the metrics incoming rate and consume lots of cpu cycles.
 		if s.errLogRateLimiter != nil && !s.errLogRateLimiter.IsAllowed(1, xtime.ToUnixNano(nowFn())) {
@@ -203,6 +285,8 @@ func (s *handler) Handle(conn net.Conn) {
 			case encoding.BatchTimerWithMetadatasType:
 				fallthrough
 			case encoding.GaugeWithMetadatasType:
+				fallthrough
+			case encoding.HistogramWithMetadatasType:
 				s.metrics.addUntimedErrors.Inc(1)
 				s.log.Error("error adding untimed metric",
 					zap.String("remoteAddress", remoteAddress),
@@ -232,13 +316,10 @@ func (s *handler) Handle(conn net.Conn) {
 					zap.Any("metadatas", stagedMetadatas),
 					zap.Error(err),
 				)
-			case encoding.PassthroughMetricWithMetadataType:
-				s.metrics.addPassthroughErrors.Inc(1)
-				s.log.Error("error adding passthrough metric",
+			case encoding.HandshakeType:
+				s.metrics.handshakeErrors.Inc(1)
+				s.log.Error("error responding to handshake",
 					zap.String("remoteAddress", remoteAddress),
-					zap.Stringer("id", timedMetric.ID),
-					zap.Time("timestamp", time.Unix(0, timedMetric.TimeNanos)),
-					zap.Float64("value", timedMetric.Value),
 					zap.Error(err),
 				)
 			default:
@@ -247,6 +328,7 @@ func (s *handler) Handle(conn net.Conn) {
 			}
 		}
 	}
+	s.addPassthroughBatch(passthroughBatch, remoteAddress)
 
 	// If there is an error during decoding, it's likely due to a broken connection
 	// and therefore we ignore the EOF error.
@@ -259,10 +341,112 @@ func (s *handler) Handle(conn net.Conn) {
 	}
 }
 
+// addPassthroughBatch adds the passthrough metrics batched from a connection to
+// the aggregator and resets the batch.
+func (s *handler) addPassthroughBatch(batch *passthroughBatch, remoteAddress string) {
+	if batch.Len() == 0 {
+		return
+	}
+	err := s.aggregator.AddPassthroughBatch(batch.Metrics())
+	numMetrics := batch.Len()
+	batch.Reset()
+	if err == nil {
+		return
+	}
+	s.metrics.addPassthroughErrors.Inc(1)
+	nowFn := s.opts.ClockOptions().NowFn()
+	if s.errLogRateLimiter != nil && !s.errLogRateLimiter.IsAllowed(1, xtime.ToUnixNano(nowFn())) {
+		s.metrics.errLogRateLimited.Inc(1)
+		return
+	}
+	s.log.Error("error adding passthrough metrics",
+		zap.String("remoteAddress", remoteAddress),
+		zap.Int("numMetrics", numMetrics),
+		zap.Error(err),
+	)
+}
+
+// handshake responds to a client handshake with the protocol version and the
+// capabilities supported by the server, so that the client can negotiate the
+// features it may use on the connection, and returns the capabilities both
+// support.
+func (s *handler) handshake(
+	conn net.Conn,
+	remoteAddress string,
+	remote encoding.Handshake,
+) (encoding.Capabilities, error) {
+	s.metrics.handshakes.Inc(1)
+	s.log.Debug("received client handshake",
+		zap.String("remoteAddress", remoteAddress),
+		zap.Uint32("protocolVersion", remote.ProtocolVersion),
+		zap.Uint64("capabilities", uint64(remote.Capabilities)),
+	)
+
+	local := encoding.NewHandshake()
+	encoder := protobuf.NewUnaggregatedEncoder(s.protobufItOpts)
+	if err := encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:      encoding.HandshakeType,
+		Handshake: local,
+	}); err != nil {
+		return 0, err
+	}
+	buf := encoder.Relinquish()
+	defer buf.Close()
+
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return local.Negotiate(remote).Capabilities, nil
+}
+
+// pausedNotices notifies a client of the paused ingestion of shards it writes
+// to, once per pause of each shard.
+type pausedNotices struct {
+	encoder    protobuf.UnaggregatedEncoder
+	untilNanos map[uint32]int64
+}
+
+func newPausedNotices(opts protobuf.UnaggregatedOptions) *pausedNotices {
+	return &pausedNotices{
+		encoder:    protobuf.NewUnaggregatedEncoder(opts),
+		untilNanos: make(map[uint32]int64),
+	}
+}
+
+// notify writes a notice for the pause to the connection unless the client
+// was already notified of it, and returns whether a notice was written.
+func (n *pausedNotices) notify(
+	conn net.Conn,
+	pause aggregator.ShardIngestionPause,
+) (bool, error) {
+	untilNanos := pause.PausedUntil.UnixNano()
+	if n.untilNanos[pause.ShardID] == untilNanos {
+		return false, nil
+	}
+	if err := n.encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type: encoding.ShardIngestionPausedType,
+		ShardIngestionPaused: encoding.ShardIngestionPaused{
+			Shard:      pause.ShardID,
+			UntilNanos: untilNanos,
+		},
+	}); err != nil {
+		return false, err
+	}
+	buf := n.encoder.Relinquish()
+	defer buf.Close()
+
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return false, err
+	}
+	n.untilNanos[pause.ShardID] = untilNanos
+	return true, nil
+}
+
 func (s *handler) Close() {
 	// NB(cw) Do not close s.aggregator here because it's shared between
 	// the raw TCP server and the http server, and it will be closed on
 	// exit signal.
+	s.cancel()
 }
 
 type unknownMessageTypeError struct {
@@ -21,7 +21,10 @@
 package rawtcp
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -274,6 +277,238 @@ func TestRawTCPServerHandleUnaggregatedProtobufEncoding(t *testing.T) {
 	require.True(t, cmp.Equal(expectedResult, snapshot, testCmpOpts...), expectedResult, snapshot)
 }
 
+func TestRawTCPServerHandshake(t *testing.T) {
+	agg := capture.NewAggregator()
+	h := NewHandler(agg, testServerOptions())
+
+	listener, err := net.Listen("tcp", testListenAddress)
+	require.NoError(t, err)
+
+	s := xserver.NewServer(testListenAddress, h, xserver.NewOptions())
+	require.NoError(t, s.Serve(listener))
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+	require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:      encoding.HandshakeType,
+		Handshake: encoding.Handshake{ProtocolVersion: encoding.ProtocolVersion + 1},
+	}))
+	require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:                 encoding.CounterWithMetadatasType,
+		CounterWithMetadatas: testCounterWithMetadatas,
+	}))
+	_, err = conn.Write(encoder.Relinquish().Bytes())
+	require.NoError(t, err)
+
+	// The server responds with its own protocol version and capabilities.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	it := protobuf.NewUnaggregatedIterator(bufio.NewReader(conn), protobuf.NewUnaggregatedOptions())
+	defer it.Close()
+	require.True(t, it.Next())
+	require.Equal(t, encoding.HandshakeType, it.Current().Type)
+	require.Equal(t, encoding.NewHandshake(), it.Current().Handshake)
+
+	// Metrics following the handshake are processed as usual.
+	for agg.NumMetricsAdded() < 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	snapshot := agg.Snapshot()
+	require.True(t, cmp.Equal(
+		[]unaggregated.CounterWithMetadatas{testCounterWithMetadatas},
+		snapshot.CountersWithMetadatas,
+		testCmpOpts...,
+	))
+}
+
+func TestRawTCPServerNotifiesPausedShardIngestion(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		until  = time.Unix(0, 1000)
+		pauses = []aggregator.ShardIngestionPause{
+			{ShardID: 3, PausedUntil: until},
+			{ShardID: 3, PausedUntil: until},
+			{ShardID: 3, PausedUntil: until.Add(time.Minute)},
+		}
+		agg = aggregator.NewMockAggregator(ctrl)
+	)
+	for _, pause := range pauses {
+		agg.EXPECT().
+			AddUntimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(aggregator.ShardIngestionPausedError{ShardIngestionPause: pause})
+	}
+	h := NewHandler(agg, testServerOptions())
+
+	listener, err := net.Listen("tcp", testListenAddress)
+	require.NoError(t, err)
+
+	s := xserver.NewServer(testListenAddress, h, xserver.NewOptions())
+	require.NoError(t, s.Serve(listener))
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+	require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:      encoding.HandshakeType,
+		Handshake: encoding.NewHandshake(),
+	}))
+	for range pauses {
+		require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                 encoding.CounterWithMetadatasType,
+			CounterWithMetadatas: testCounterWithMetadatas,
+		}))
+	}
+	_, err = conn.Write(encoder.Relinquish().Bytes())
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	it := protobuf.NewUnaggregatedIterator(bufio.NewReader(conn), protobuf.NewUnaggregatedOptions())
+	defer it.Close()
+	require.True(t, it.Next())
+	require.Equal(t, encoding.HandshakeType, it.Current().Type)
+
+	// The client is notified once per pause of a shard.
+	for _, untilNanos := range []int64{until.UnixNano(), until.Add(time.Minute).UnixNano()} {
+		require.True(t, it.Next())
+		require.Equal(t, encoding.ShardIngestionPausedType, it.Current().Type)
+		require.Equal(t, encoding.ShardIngestionPaused{
+			Shard:      3,
+			UntilNanos: untilNanos,
+		}, it.Current().ShardIngestionPaused)
+	}
+}
+
+func TestHandleCancelsConnectionContext(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var connCtx context.Context
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().
+		AddUntimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ unaggregated.MetricUnion, _ metadata.StagedMetadatas) error {
+			require.NoError(t, ctx.Err())
+			connCtx = ctx
+			return nil
+		})
+
+	h := NewHandler(agg, testServerOptions()).(*handler)
+	serverConn, clientConn := net.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- writeAndClose(clientConn, encoding.UnaggregatedMessageUnion{
+			Type:                 encoding.CounterWithMetadatasType,
+			CounterWithMetadatas: testCounterWithMetadatas,
+		})
+	}()
+
+	// The connection context is cancelled once the connection is closed,
+	// while the handler remains usable for other connections.
+	h.Handle(serverConn)
+	require.NoError(t, <-errCh)
+	require.NotNil(t, connCtx)
+	require.Equal(t, context.Canceled, connCtx.Err())
+	require.NoError(t, h.ctx.Err())
+}
+
+func TestHandleCancelsInFlightAddOnDroppedConnection(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	addStarted := make(chan struct{})
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().
+		AddUntimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ unaggregated.MetricUnion, _ metadata.StagedMetadatas) error {
+			close(addStarted)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(10 * time.Second):
+				return errors.New("add was not cancelled")
+			}
+		})
+
+	h := NewHandler(agg, testServerOptions())
+	errCh := make(chan error, 1)
+	go func() {
+		encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+		if err := encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                 encoding.CounterWithMetadatasType,
+			CounterWithMetadatas: testCounterWithMetadatas,
+		}); err != nil {
+			errCh <- err
+			return
+		}
+		_, err := clientConn.Write(encoder.Relinquish().Bytes())
+		errCh <- err
+
+		// Drop the connection while the metric is being added.
+		<-addStarted
+		serverConn.Close()
+	}()
+
+	start := time.Now()
+	h.Handle(serverConn)
+	require.NoError(t, <-errCh)
+	require.True(t, time.Since(start) < 10*time.Second)
+}
+
+// writeAndClose writes the message to the connection and closes it.
+func writeAndClose(conn net.Conn, msg encoding.UnaggregatedMessageUnion) error {
+	encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+	if err := encoder.EncodeMessage(msg); err != nil {
+		return err
+	}
+	if _, err := conn.Write(encoder.Relinquish().Bytes()); err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestHandleHistogramAnnotation(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	histogram := unaggregated.Histogram{
+		ID:                []byte("testHistogram"),
+		BucketUpperBounds: []float64{1, 10},
+		BucketCounts:      []int64{3, 4},
+		Sum:               25,
+		Annotation:        []byte("annotation"),
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().
+		AddUntimedWithContext(gomock.Any(), histogram.ToUnion(), testDefaultMetadatas).
+		Return(nil)
+
+	h := NewHandler(agg, testServerOptions())
+	serverConn, clientConn := net.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- writeAndClose(clientConn, encoding.UnaggregatedMessageUnion{
+			Type: encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: unaggregated.HistogramWithMetadatas{
+				Histogram:       histogram,
+				StagedMetadatas: testDefaultMetadatas,
+			},
+		})
+	}()
+	h.Handle(serverConn)
+	require.NoError(t, <-errCh)
+}
+
 func TestHandle_Errors(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -334,7 +569,7 @@ func TestHandle_Errors(t *testing.T) {
 				Type:                          encoding.PassthroughMetricWithMetadataType,
 				PassthroughMetricWithMetadata: testPassthroughMetricWithMetadata,
 			},
-			logMsg: "error adding passthrough metric",
+			logMsg: "error adding passthrough metrics",
 		},
 	}
 
@@ -344,10 +579,10 @@ func TestHandle_Errors(t *testing.T) {
 
 	aggErr := errors.New("boom")
 	agg.EXPECT().AddTimedWithStagedMetadatas(gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
-	agg.EXPECT().AddUntimed(gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
+	agg.EXPECT().AddUntimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
 	agg.EXPECT().AddForwarded(gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
-	agg.EXPECT().AddTimed(gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
-	agg.EXPECT().AddPassthrough(gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
+	agg.EXPECT().AddTimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(aggErr).AnyTimes()
+	agg.EXPECT().AddPassthroughBatch(gomock.Any()).Return(aggErr).AnyTimes()
 
 	for _, tc := range cases {
 		tc := tc
@@ -382,6 +617,83 @@ func TestHandle_Errors(t *testing.T) {
 	}
 }
 
+func TestRawTCPServerHandlePassthroughBatch(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	agg := aggregator.NewMockAggregator(ctrl)
+
+	var (
+		mu      sync.Mutex
+		batches [][]aggregated.PassthroughMetricWithMetadata
+		done    = make(chan struct{})
+	)
+	agg.EXPECT().AddPassthroughBatch(gomock.Any()).DoAndReturn(
+		func(metrics []aggregated.PassthroughMetricWithMetadata) error {
+			mu.Lock()
+			defer mu.Unlock()
+			batch := make([]aggregated.PassthroughMetricWithMetadata, 0, len(metrics))
+			for _, m := range metrics {
+				m.ID = append([]byte(nil), m.ID...)
+				batch = append(batch, m)
+			}
+			batches = append(batches, batch)
+			return nil
+		}).MinTimes(3)
+	agg.EXPECT().AddUntimedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, unaggregated.MetricUnion, metadata.StagedMetadatas) error {
+			close(done)
+			return nil
+		})
+
+	listener, err := net.Listen("tcp", testListenAddress)
+	require.NoError(t, err)
+	h := NewHandler(agg, testServerOptions().SetPassthroughBatchSize(2))
+	s := xserver.NewServer(testListenAddress, h, xserver.NewOptions())
+	require.NoError(t, s.Serve(listener))
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	encoder := protobuf.NewUnaggregatedEncoder(protobuf.NewUnaggregatedOptions())
+	var expected []string
+	for i := 0; i < 5; i++ {
+		metric := testPassthroughMetricWithMetadata
+		metric.ID = []byte(fmt.Sprintf("passthrough%d", i))
+		expected = append(expected, string(metric.ID))
+		require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                          encoding.PassthroughMetricWithMetadataType,
+			PassthroughMetricWithMetadata: metric,
+		}))
+	}
+	require.NoError(t, encoder.EncodeMessage(encoding.UnaggregatedMessageUnion{
+		Type:                 encoding.CounterWithMetadatasType,
+		CounterWithMetadatas: testCounterWithMetadatas,
+	}))
+	_, err = conn.Write(encoder.Relinquish().Bytes())
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for metrics")
+	}
+
+	// The passthrough metrics are added in order, in batches of at most the
+	// batch size, before the metric that follows them.
+	mu.Lock()
+	defer mu.Unlock()
+	var actual []string
+	for _, batch := range batches {
+		require.True(t, len(batch) <= 2)
+		for _, m := range batch {
+			actual = append(actual, string(m.ID))
+		}
+	}
+	require.Equal(t, expected, actual)
+}
+
 func testServerOptions() Options {
 	opts := NewOptions()
 	instrumentOpts := opts.InstrumentOptions().SetReportInterval(time.Second)
@@ -29,6 +29,8 @@ import (
 	"time"
 
 	m3aggregator "github.com/m3db/m3/src/aggregator/aggregator"
+	grpcserver "github.com/m3db/m3/src/aggregator/server/grpc"
+	"github.com/m3db/m3/src/cluster/client"
 	"github.com/m3db/m3/src/cmd/services/m3aggregator/config"
 	"github.com/m3db/m3/src/cmd/services/m3aggregator/serve"
 	"github.com/m3db/m3/src/x/clock"
@@ -120,43 +122,143 @@ func Run(opts RunOptions) {
 
 	defer buildReporter.Stop()
 
+	continuousProfiler, err := cfg.Debug.NewContinuousProfiler(instrumentOpts)
+	if err != nil {
+		logger.Fatal("could not create continuous profiler", zap.Error(err))
+	}
+	if continuousProfiler != nil {
+		if err := continuousProfiler.Start(); err != nil {
+			logger.Fatal("could not start continuous profiler", zap.Error(err))
+		}
+		defer continuousProfiler.Stop()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		logger.Fatal("invalid config", zap.Error(err))
+	}
+
+	// Create the kv client.
+	client, err := cfg.KVClient.NewKVClient(instrumentOpts.
+		SetMetricsScope(scope.SubScope("kv-client")))
+	if err != nil {
+		logger.Fatal("error creating the kv client", zap.Error(err))
+	}
+
+	// The top level aggregator configuration is the first tier, any additional
+	// tiers participate in their own placements in the same process.
+	tiers := cfg.AggregationTiers()
+
+	doneCh := make(chan struct{})
+	closedChs := make([]chan struct{}, 0, len(tiers))
+	for i, tier := range tiers {
+		var (
+			tierLogger         = logger
+			tierInstrumentOpts = instrumentOpts
+			mux                = defaultServeMux
+			adminOptions       = opts.AdminOptions
+		)
+		if i > 0 {
+			tierLogger = logger.With(zap.String("tier", tier.Name))
+			tierInstrumentOpts = instrumentOpts.
+				SetLogger(tierLogger).
+				SetMetricsScope(scope.Tagged(map[string]string{"tier": tier.Name}))
+			// The metrics are only served by the first tier's HTTP server.
+			mux = http.NewServeMux()
+			adminOptions = nil
+		}
+
+		closedCh := runTier(tier, client, adminOptions, mux, doneCh, tierInstrumentOpts)
+		closedChs = append(closedChs, closedCh)
+	}
+
+	// Handle interrupts.
+	xos.WaitForInterrupt(logger, xos.InterruptOptions{
+		InterruptCh: opts.InterruptCh,
+	})
+
+	if s := cfg.Aggregator.ShutdownWaitTimeout; s != 0 {
+		sigC := make(chan os.Signal, 1)
+		signal.Notify(sigC, syscall.SIGINT, syscall.SIGTERM)
+
+		logger.Info("waiting intentional shutdown period", zap.Duration("waitTimeout", s))
+		select {
+		case sig := <-sigC:
+			logger.Info("second signal received, skipping shutdown wait", zap.String("signal", sig.String()))
+		case <-time.After(cfg.Aggregator.ShutdownWaitTimeout):
+			logger.Info("shutdown period elapsed")
+		}
+	}
+
+	close(doneCh)
+
+	timeout := time.After(gracefulShutdownTimeout)
+	for _, closedCh := range closedChs {
+		select {
+		case <-closedCh:
+		case <-timeout:
+			logger.Info("server closed due to timeout", zap.Duration("timeout", gracefulShutdownTimeout))
+			return
+		}
+	}
+	logger.Info("server closed clean")
+}
+
+// runTier opens an aggregator for an aggregation tier and starts serving
+// traffic for it, returning a channel closed once the tier's servers are closed.
+func runTier(
+	tier config.TierConfiguration,
+	kvClient client.Client,
+	adminOptions []AdminOption,
+	mux *http.ServeMux,
+	doneCh chan struct{},
+	instrumentOpts instrument.Options,
+) chan struct{} {
+	var (
+		logger = instrumentOpts.Logger()
+		scope  = instrumentOpts.MetricsScope()
+	)
+
 	serverOptions := serve.NewOptions(instrumentOpts)
-	if cfg.M3Msg != nil {
+	if tier.M3Msg != nil {
 		// Create the M3Msg server options.
 		m3msgInsrumentOpts := instrumentOpts.
 			SetMetricsScope(scope.
 				SubScope("m3msg-server").
 				Tagged(map[string]string{"server": "m3msg"}))
-		m3msgServerOpts, err := cfg.M3Msg.NewServerOptions(m3msgInsrumentOpts)
+		m3msgServerOpts, err := tier.M3Msg.NewServerOptions(m3msgInsrumentOpts)
 		if err != nil {
 			logger.Fatal("could not create m3msg server options", zap.Error(err))
 		}
 
 		serverOptions = serverOptions.
-			SetM3MsgAddr(cfg.M3Msg.Server.ListenAddress).
+			SetM3MsgAddr(tier.M3Msg.Server.ListenAddress).
 			SetM3MsgServerOpts(m3msgServerOpts)
 	}
 
-	if cfg.RawTCP != nil {
+	if tier.RawTCP != nil {
 		// Create the raw TCP server options.
 		rawTCPInstrumentOpts := instrumentOpts.
 			SetMetricsScope(scope.
 				SubScope("rawtcp-server").
 				Tagged(map[string]string{"server": "rawtcp"}))
+		rawTCPServerOpts, err := tier.RawTCP.NewServerOptions(rawTCPInstrumentOpts)
+		if err != nil {
+			logger.Fatal("could not create raw TCP server options", zap.Error(err))
+		}
 
 		serverOptions = serverOptions.
-			SetRawTCPAddr(cfg.RawTCP.ListenAddress).
-			SetRawTCPServerOpts(cfg.RawTCP.NewServerOptions(rawTCPInstrumentOpts))
+			SetRawTCPAddr(tier.RawTCP.ListenAddress).
+			SetRawTCPServerOpts(rawTCPServerOpts)
 	}
 
-	if cfg.HTTP != nil {
+	if tier.HTTP != nil {
 		// Create the http server options.
 		serverOptions = serverOptions.
-			SetHTTPAddr(cfg.HTTP.ListenAddress).
-			SetHTTPServerOpts(cfg.HTTP.NewServerOptions().SetMux(defaultServeMux))
+			SetHTTPAddr(tier.HTTP.ListenAddress).
+			SetHTTPServerOpts(tier.HTTP.NewServerOptions().SetMux(mux))
 	}
 
-	for i, transform := range opts.AdminOptions {
+	for i, transform := range adminOptions {
 		if opts, err := transform(serverOptions); err != nil {
 			logger.Fatal("could not apply transform",
 				zap.Int("index", i), zap.Error(err))
@@ -165,20 +267,13 @@ func Run(opts RunOptions) {
 		}
 	}
 
-	// Create the kv client.
-	client, err := cfg.KVClient.NewKVClient(instrumentOpts.
-		SetMetricsScope(scope.SubScope("kv-client")))
-	if err != nil {
-		logger.Fatal("error creating the kv client", zap.Error(err))
-	}
-
 	// Create the runtime options manager.
-	runtimeOptsManager := cfg.RuntimeOptions.NewRuntimeOptionsManager()
+	runtimeOptsManager := tier.RuntimeOptions.NewRuntimeOptionsManager()
 
 	// Create the aggregator.
-	aggregatorOpts, err := cfg.Aggregator.NewAggregatorOptions(
+	aggregatorOpts, err := tier.Aggregator.NewAggregatorOptions(
 		serverOptions.RawTCPAddr(),
-		client, serverOptions, runtimeOptsManager, clock.NewOptions(),
+		kvClient, serverOptions, runtimeOptsManager, clock.NewOptions(),
 		instrumentOpts.SetMetricsScope(scope.SubScope("aggregator")))
 	if err != nil {
 		logger.Fatal("error creating aggregator options", zap.Error(err))
@@ -188,11 +283,22 @@ func Run(opts RunOptions) {
 		logger.Fatal("error opening the aggregator", zap.Error(err))
 	}
 
+	if tier.GRPC != nil {
+		// Create the grpc server options, the admin service serves the placement,
+		// flush times and journaled metrics from the managers and write-ahead log
+		// the aggregator was created with.
+		serverOptions = serverOptions.
+			SetGRPCAddr(tier.GRPC.ListenAddress).
+			SetGRPCServerOpts(grpcserver.NewOptions().
+				SetPlacementManager(aggregatorOpts.PlacementManager()).
+				SetFlushTimesManager(aggregatorOpts.FlushTimesManager()).
+				SetWAL(aggregatorOpts.WAL()))
+	}
+
 	// Watch runtime option changes after aggregator is open.
 	placementManager := aggregatorOpts.PlacementManager()
-	cfg.RuntimeOptions.WatchRuntimeOptionChanges(client, runtimeOptsManager, placementManager, logger)
+	tier.RuntimeOptions.WatchRuntimeOptionChanges(kvClient, runtimeOptsManager, placementManager, logger)
 
-	doneCh := make(chan struct{})
 	closedCh := make(chan struct{})
 	go func() {
 		if err := serve.Serve(
@@ -205,31 +311,5 @@ func Run(opts RunOptions) {
 		logger.Debug("server closed")
 		close(closedCh)
 	}()
-
-	// Handle interrupts.
-	xos.WaitForInterrupt(logger, xos.InterruptOptions{
-		InterruptCh: opts.InterruptCh,
-	})
-
-	if s := cfg.Aggregator.ShutdownWaitTimeout; s != 0 {
-		sigC := make(chan os.Signal, 1)
-		signal.Notify(sigC, syscall.SIGINT, syscall.SIGTERM)
-
-		logger.Info("waiting intentional shutdown period", zap.Duration("waitTimeout", s))
-		select {
-		case sig := <-sigC:
-			logger.Info("second signal received, skipping shutdown wait", zap.String("signal", sig.String()))
-		case <-time.After(cfg.Aggregator.ShutdownWaitTimeout):
-			logger.Info("shutdown period elapsed")
-		}
-	}
-
-	close(doneCh)
-
-	select {
-	case <-closedCh:
-		logger.Info("server closed clean")
-	case <-time.After(gracefulShutdownTimeout):
-		logger.Info("server closed due to timeout", zap.Duration("timeout", gracefulShutdownTimeout))
-	}
+	return closedCh
 }
@@ -50,6 +50,15 @@ const (
 	// zeroHash always returns 0 as the hash. It is used when sharding is disabled.
 	zeroHash HashType = "zero"
 
+	// TagHashPrefix prefixes hash types that shard by the murmur3 hash of the
+	// value of the tag named after the prefix, e.g. "tag:service".
+	TagHashPrefix = "tag:"
+
+	// TenantHashPrefix prefixes hash types that confine the metrics of each
+	// value of a tenant tag to a bounded number of shards, e.g.
+	// "tenant:tenant:8" spreads the metrics of each tenant across 8 shards.
+	TenantHashPrefix = "tenant:"
+
 	DefaultHash = Murmur32Hash
 )
 
@@ -69,7 +78,22 @@ func (t *HashType) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		*t = DefaultHash
 		return nil
 	}
-	validTypes := make([]string, 0, len(validHashTypes))
+	if tagName, ok := HashType(str).tagName(); ok && tagName != "" {
+		*t = HashType(str)
+		return nil
+	}
+	if _, _, ok, err := HashType(str).tenantSharding(); ok {
+		if err != nil {
+			return err
+		}
+		*t = HashType(str)
+		return nil
+	}
+	if registeredShardFn(HashType(str)) != nil {
+		*t = HashType(str)
+		return nil
+	}
+	validTypes := make([]string, 0, len(validHashTypes)+2)
 	for _, valid := range validHashTypes {
 		if str == string(valid) {
 			*t = valid
@@ -77,10 +101,23 @@ func (t *HashType) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 		validTypes = append(validTypes, string(valid))
 	}
+	validTypes = append(validTypes, TagHashPrefix+"<tag name>",
+		TenantHashPrefix+"<tag name>:<shards per tenant>")
+	for _, registered := range registeredHashTypes() {
+		validTypes = append(validTypes, string(registered))
+	}
 	return fmt.Errorf("invalid hash type '%s' valid types are: %s",
 		str, strings.Join(validTypes, ", "))
 }
 
+// tagName returns the name of the tag to shard by for tag hash types.
+func (t HashType) tagName() (string, bool) {
+	if !strings.HasPrefix(string(t), TagHashPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(t), TagHashPrefix), true
+}
+
 // ShardFn returns the sharding function.
 func (t HashType) ShardFn() (ShardFn, error) {
 	switch t {
@@ -89,6 +126,18 @@ func (t HashType) ShardFn() (ShardFn, error) {
 			return murmur3.Sum32(id) % numShards
 		}, nil
 	default:
+		if tagName, ok := t.tagName(); ok && tagName != "" {
+			return NewTagShardFn([]byte(tagName)), nil
+		}
+		if tagName, shardsPerTenant, ok, err := t.tenantSharding(); ok {
+			if err != nil {
+				return nil, err
+			}
+			return NewTenantShardFn([]byte(tagName), shardsPerTenant), nil
+		}
+		if fn := registeredShardFn(t); fn != nil {
+			return fn, nil
+		}
 		return nil, fmt.Errorf("unrecognized hashing type %v", t)
 	}
 }
@@ -123,6 +172,47 @@ func (t HashType) AggregatedShardFn() (AggregatedShardFn, error) {
 			return 0
 		}, nil
 	default:
-		return nil, fmt.Errorf("unrecognized hashing type %v", t)
+		shardFn, err := t.ShardFn()
+		if err != nil {
+			return nil, err
+		}
+		if tagName, ok := t.tagName(); ok {
+			// NB: The prefix and suffix would corrupt the tag encoding header, so the
+			// tag value is looked up in the data of the aggregated metric instead.
+			tagNameBytes := []byte(tagName)
+			return func(chunkedID id.ChunkedID, numShards int) uint32 {
+				if value, ok := tagValue(chunkedID.Data, tagNameBytes); ok {
+					return murmur3.Sum32(value) % uint32(numShards)
+				}
+				var b [initialChunkedIDSize]byte
+				buf := b[:0]
+				buf = append(buf, chunkedID.Prefix...)
+				buf = append(buf, chunkedID.Data...)
+				buf = append(buf, chunkedID.Suffix...)
+				return murmur3.Sum32(buf) % uint32(numShards)
+			}, nil
+		}
+		if tagName, shardsPerTenant, ok, _ := t.tenantSharding(); ok {
+			tagNameBytes := []byte(tagName)
+			return func(chunkedID id.ChunkedID, numShards int) uint32 {
+				var b [initialChunkedIDSize]byte
+				buf := b[:0]
+				buf = append(buf, chunkedID.Prefix...)
+				buf = append(buf, chunkedID.Data...)
+				buf = append(buf, chunkedID.Suffix...)
+				if tenant, ok := tagValue(chunkedID.Data, tagNameBytes); ok {
+					return tenantShard(tenant, buf, shardsPerTenant, uint32(numShards))
+				}
+				return murmur3.Sum32(buf) % uint32(numShards)
+			}, nil
+		}
+		return func(chunkedID id.ChunkedID, numShards int) uint32 {
+			var b [initialChunkedIDSize]byte
+			buf := b[:0]
+			buf = append(buf, chunkedID.Prefix...)
+			buf = append(buf, chunkedID.Data...)
+			buf = append(buf, chunkedID.Suffix...)
+			return shardFn(buf, uint32(numShards))
+		}, nil
 	}
 }
@@ -53,7 +53,7 @@ func TestHashTypeUnmarshalYAMLErrors(t *testing.T) {
 		var hashType HashType
 		err := yaml.Unmarshal([]byte(input), &hashType)
 		require.Error(t, err)
-		require.Equal(t, "invalid hash type '"+input+"' valid types are: murmur32", err.Error())
+		require.Equal(t, "invalid hash type '"+input+"' valid types are: murmur32, tag:<tag name>, tenant:<tag name>:<shards per tenant>", err.Error())
 	}
 }
 
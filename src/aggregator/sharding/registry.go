@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/x/serialize"
+
+	murmur3 "github.com/m3db/stackmurmur3/v2"
+)
+
+var (
+	errEmptyHashType = errors.New("empty hash type")
+	errNilShardFn    = errors.New("nil shard fn")
+
+	registry = struct {
+		sync.RWMutex
+		shardFns map[HashType]ShardFn
+	}{
+		shardFns: make(map[HashType]ShardFn),
+	}
+)
+
+// RegisterShardFn registers a custom sharding function under the given hash
+// type so that it can be selected by name in configuration. Functions must be
+// registered before the configuration is loaded, and clients and aggregators
+// must register the same functions under the same names, since aggregators
+// reject metrics that clients routed to shards they do not own.
+func RegisterShardFn(t HashType, fn ShardFn) error {
+	if t == "" {
+		return errEmptyHashType
+	}
+	if fn == nil {
+		return errNilShardFn
+	}
+	if _, ok := t.tagName(); ok {
+		return fmt.Errorf("hash type %v uses the reserved prefix %s", t, TagHashPrefix)
+	}
+	if _, _, ok, _ := t.tenantSharding(); ok {
+		return fmt.Errorf("hash type %v uses the reserved prefix %s", t, TenantHashPrefix)
+	}
+	for _, builtin := range []HashType{Murmur32Hash, zeroHash} {
+		if t == builtin {
+			return fmt.Errorf("hash type %v is a builtin hash type", t)
+		}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.shardFns[t]; ok {
+		return fmt.Errorf("hash type %v is already registered", t)
+	}
+	registry.shardFns[t] = fn
+	return nil
+}
+
+// MustRegisterShardFn registers a custom sharding function, or panics if an
+// error is encountered.
+func MustRegisterShardFn(t HashType, fn ShardFn) {
+	if err := RegisterShardFn(t, fn); err != nil {
+		panic(fmt.Errorf("error registering shard fn: %v", err))
+	}
+}
+
+func registeredShardFn(t HashType) ShardFn {
+	registry.RLock()
+	fn := registry.shardFns[t]
+	registry.RUnlock()
+	return fn
+}
+
+func registeredHashTypes() []HashType {
+	registry.RLock()
+	hashTypes := make([]HashType, 0, len(registry.shardFns))
+	for t := range registry.shardFns {
+		hashTypes = append(hashTypes, t)
+	}
+	registry.RUnlock()
+
+	sort.Slice(hashTypes, func(i, j int) bool { return hashTypes[i] < hashTypes[j] })
+	return hashTypes
+}
+
+// NewTagShardFn returns a sharding function that shards metrics by the murmur3
+// hash of the value of the given tag, so that all the series with the same tag
+// value land on the same shard. Both tags encoded by m3coordinator and m3 metric
+// IDs are supported. Metrics without the tag are sharded by the murmur3 hash of
+// their full ID.
+func NewTagShardFn(tagName []byte) ShardFn {
+	return func(id []byte, numShards uint32) uint32 {
+		if value, ok := tagValue(id, tagName); ok {
+			return murmur3.Sum32(value) % numShards
+		}
+		return murmur3.Sum32(id) % numShards
+	}
+}
+
+func tagValue(id []byte, tagName []byte) ([]byte, bool) {
+	if value, ok, err := serialize.TagValueFromEncodedTagsFast(id, tagName); err == nil {
+		return value, ok
+	}
+
+	_, tags, err := m3.NameAndTags(id)
+	if err != nil {
+		return nil, false
+	}
+	it := m3.NewSortedTagIterator(tags)
+	defer it.Close()
+	for it.Next() {
+		name, value := it.Current()
+		if bytes.Equal(name, tagName) {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+
+	murmur3 "github.com/m3db/stackmurmur3/v2"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestRegisterShardFn(t *testing.T) {
+	hashType := HashType("test-registered")
+	shardFn := func(id []byte, numShards uint32) uint32 { return 7 % numShards }
+	require.NoError(t, RegisterShardFn(hashType, shardFn))
+	defer unregisterShardFn(hashType)
+
+	require.Error(t, RegisterShardFn(hashType, shardFn))
+	require.Error(t, RegisterShardFn("", shardFn))
+	require.Error(t, RegisterShardFn("test-nil", nil))
+	require.Error(t, RegisterShardFn(Murmur32Hash, shardFn))
+	require.Error(t, RegisterShardFn(zeroHash, shardFn))
+	require.Error(t, RegisterShardFn("tag:service", shardFn))
+	require.Error(t, RegisterShardFn("tenant:tenant:8", shardFn))
+
+	var parsed HashType
+	require.NoError(t, yaml.Unmarshal([]byte("test-registered"), &parsed))
+	require.Equal(t, hashType, parsed)
+
+	err := yaml.Unmarshal([]byte("huh"), &parsed)
+	require.Error(t, err)
+	require.Equal(t, "invalid hash type 'huh' valid types are: "+
+		"murmur32, tag:<tag name>, tenant:<tag name>:<shards per tenant>, test-registered", err.Error())
+
+	fn, err := parsed.ShardFn()
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), fn([]byte("foo"), 1024))
+
+	aggregatedFn, err := parsed.AggregatedShardFn()
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), aggregatedFn(id.ChunkedID{Data: []byte("foo")}, 1024))
+}
+
+func TestTagHashShardFn(t *testing.T) {
+	var hashType HashType
+	require.NoError(t, yaml.Unmarshal([]byte("tag:service"), &hashType))
+	require.Equal(t, HashType("tag:service"), hashType)
+	require.Error(t, yaml.Unmarshal([]byte(`"tag:"`), &hashType))
+
+	numShards := uint32(1024)
+	shardFn, err := hashType.ShardFn()
+	require.NoError(t, err)
+
+	expected := murmur3.Sum32([]byte("foo")) % numShards
+	inputs := [][]byte{
+		testEncodedTags(t, "__name__", "requests", "service", "foo"),
+		testEncodedTags(t, "__name__", "errors", "env", "prod", "service", "foo"),
+		[]byte("m3+requests+env=prod,service=foo"),
+		[]byte("m3+errors+service=foo"),
+	}
+	for _, input := range inputs {
+		require.Equal(t, expected, shardFn(input, numShards))
+	}
+
+	// Metrics without the tag fall back to the hash of the full ID.
+	noTag := []byte("m3+requests+env=prod")
+	require.Equal(t, murmur3.Sum32(noTag)%numShards, shardFn(noTag, numShards))
+
+	aggregatedShardFn, err := hashType.AggregatedShardFn()
+	require.NoError(t, err)
+	chunkedID := id.ChunkedID{
+		Prefix: []byte("stats.rollup."),
+		Data:   []byte("m3+requests+service=foo"),
+	}
+	require.Equal(t, expected, aggregatedShardFn(chunkedID, int(numShards)))
+
+	chunkedID.Data = testEncodedTags(t, "__name__", "requests", "service", "foo")
+	require.Equal(t, expected, aggregatedShardFn(chunkedID, int(numShards)))
+}
+
+func unregisterShardFn(t HashType) {
+	registry.Lock()
+	delete(registry.shardFns, t)
+	registry.Unlock()
+}
+
+func testEncodedTags(t *testing.T, tags ...string) []byte {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions().SetSize(1))
+	encoderPool.Init()
+	encoder := encoderPool.Get()
+	require.NoError(t, encoder.Encode(ident.MustNewTagStringsIterator(tags...)))
+	data, ok := encoder.Data()
+	require.True(t, ok)
+	return append([]byte(nil), data.Bytes()...)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	murmur3 "github.com/m3db/stackmurmur3/v2"
+)
+
+// tenantSharding returns the name of the tenant tag and the number of shards
+// per tenant for tenant hash types, which have the form
+// "tenant:<tag name>:<shards per tenant>".
+func (t HashType) tenantSharding() (string, uint32, bool, error) {
+	if !strings.HasPrefix(string(t), TenantHashPrefix) {
+		return "", 0, false, nil
+	}
+	str := strings.TrimPrefix(string(t), TenantHashPrefix)
+	idx := strings.LastIndex(str, ":")
+	if idx <= 0 {
+		return "", 0, true, fmt.Errorf(
+			"invalid hash type '%s' expected %s<tag name>:<shards per tenant>", t, TenantHashPrefix)
+	}
+	shardsPerTenant, err := strconv.ParseUint(str[idx+1:], 10, 32)
+	if err != nil || shardsPerTenant == 0 {
+		return "", 0, true, fmt.Errorf(
+			"invalid hash type '%s' the shards per tenant must be a positive integer", t)
+	}
+	return str[:idx], uint32(shardsPerTenant), true, nil
+}
+
+// NewTenantShardFn returns a sharding function that confines the metrics of
+// each value of the given tenant tag to at most shardsPerTenant consecutive
+// shards, limiting the shards affected when a tenant explodes in cardinality.
+// The first shard of a tenant is picked by the murmur3 hash of the tenant, and
+// metrics are spread across the shards of their tenant by the murmur3 hash of
+// the tenant concatenated with their ID. Metrics without the tag are sharded by
+// the murmur3 hash of their full ID.
+func NewTenantShardFn(tagName []byte, shardsPerTenant uint32) ShardFn {
+	return func(id []byte, numShards uint32) uint32 {
+		if tenant, ok := tagValue(id, tagName); ok {
+			return tenantShard(tenant, id, shardsPerTenant, numShards)
+		}
+		return murmur3.Sum32(id) % numShards
+	}
+}
+
+func tenantShard(tenant []byte, id []byte, shardsPerTenant uint32, numShards uint32) uint32 {
+	if shardsPerTenant > numShards {
+		shardsPerTenant = numShards
+	}
+	var b [initialChunkedIDSize]byte
+	buf := b[:0]
+	buf = append(buf, tenant...)
+	buf = append(buf, id...)
+	first := murmur3.Sum32(tenant) % numShards
+	return (first + murmur3.Sum32(buf)%shardsPerTenant) % numShards
+}
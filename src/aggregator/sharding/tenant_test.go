@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/metric/id"
+
+	murmur3 "github.com/m3db/stackmurmur3/v2"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestTenantHashTypeUnmarshalYAML(t *testing.T) {
+	var hashType HashType
+	require.NoError(t, yaml.Unmarshal([]byte("tenant:tenant:4"), &hashType))
+	require.Equal(t, HashType("tenant:tenant:4"), hashType)
+
+	require.NoError(t, yaml.Unmarshal([]byte("tenant:a:b:4"), &hashType))
+	tagName, shardsPerTenant, ok, err := hashType.tenantSharding()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "a:b", tagName)
+	require.Equal(t, uint32(4), shardsPerTenant)
+
+	for _, input := range []string{
+		"tenant:tenant",
+		"tenant::4",
+		"tenant:tenant:0",
+		"tenant:tenant:-1",
+		"tenant:tenant:x",
+	} {
+		require.Error(t, yaml.Unmarshal([]byte(input), &hashType), input)
+		_, err := HashType(input).ShardFn()
+		require.Error(t, err, input)
+	}
+}
+
+func TestTenantHashShardFn(t *testing.T) {
+	var (
+		hashType        = HashType("tenant:tenant:4")
+		numShards       = uint32(1024)
+		shardsPerTenant = uint32(4)
+		first           = murmur3.Sum32([]byte("foo")) % numShards
+	)
+	shardFn, err := hashType.ShardFn()
+	require.NoError(t, err)
+	aggregatedShardFn, err := hashType.AggregatedShardFn()
+	require.NoError(t, err)
+
+	inTenantShards := func(shard uint32) bool {
+		return (shard+numShards-first)%numShards < shardsPerTenant
+	}
+	shards := make(map[uint32]struct{})
+	for i := 0; i < 100; i++ {
+		service := fmt.Sprintf("service%d", i)
+		encoded := testEncodedTags(t, "__name__", "requests", "service", service, "tenant", "foo")
+		shard := shardFn(encoded, numShards)
+		require.True(t, inTenantShards(shard))
+		shards[shard] = struct{}{}
+
+		m3ID := []byte("m3+requests+service=" + service + ",tenant=foo")
+		require.True(t, inTenantShards(shardFn(m3ID, numShards)))
+
+		chunkedID := id.ChunkedID{Prefix: []byte("stats.rollup."), Data: m3ID}
+		require.True(t, inTenantShards(aggregatedShardFn(chunkedID, int(numShards))))
+	}
+	// The metrics of the tenant are spread across its shards.
+	require.True(t, len(shards) > 1)
+
+	// Metrics without the tag fall back to the hash of the full ID.
+	noTag := []byte("m3+requests+service=bar")
+	require.Equal(t, murmur3.Sum32(noTag)%numShards, shardFn(noTag, numShards))
+	chunkedID := id.ChunkedID{Prefix: []byte("stats.rollup."), Data: noTag}
+	require.Equal(t, murmur3.Sum32([]byte("stats.rollup.m3+requests+service=bar"))%numShards,
+		aggregatedShardFn(chunkedID, int(numShards)))
+
+	// Tenants are spread across all shards when there are fewer shards.
+	require.True(t, shardFn([]byte("m3+requests+tenant=foo"), 2) < 2)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+)
+
+// Encoder encodes metrics along with the time they were journaled at in the
+// format of the log segments, so they can be shipped to another aggregator.
+type Encoder struct {
+	encoder protobuf.UnaggregatedEncoder
+	header  []byte
+	buf     bytes.Buffer
+}
+
+// NewEncoder creates a new encoder.
+func NewEncoder(opts protobuf.UnaggregatedOptions) *Encoder {
+	return &Encoder{
+		encoder: protobuf.NewUnaggregatedEncoder(opts),
+		header:  make([]byte, binary.MaxVarintLen64),
+	}
+}
+
+// Encode encodes a metric along with the time it was journaled at.
+func (e *Encoder) Encode(msg encoding.UnaggregatedMessageUnion, journaledAt time.Time) error {
+	if err := e.encoder.EncodeMessage(msg); err != nil {
+		return err
+	}
+	buf := e.encoder.Relinquish()
+	defer buf.Close()
+
+	n := binary.PutVarint(e.header, journaledAt.UnixNano())
+	e.buf.Write(e.header[:n])
+	e.buf.Write(buf.Bytes())
+	return nil
+}
+
+// Bytes returns the metrics encoded so far.
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Len returns the number of bytes encoded so far.
+func (e *Encoder) Len() int {
+	return e.buf.Len()
+}
+
+// Reset discards the metrics encoded so far.
+func (e *Encoder) Reset() {
+	e.buf.Reset()
+}
+
+// Decode calls fn with each metric encoded in data by an Encoder, in the
+// order they were encoded.
+func Decode(data []byte, opts protobuf.UnaggregatedOptions, fn ReplayFn) error {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	it := protobuf.NewUnaggregatedIterator(reader, opts)
+	defer it.Close()
+
+	for {
+		journaledAtNanos, err := binary.ReadVarint(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !it.Next() {
+			if err := it.Err(); err != nil && err != io.EOF {
+				return err
+			}
+			return io.ErrUnexpectedEOF
+		}
+		if err := fn(*it.Current(), time.Unix(0, journaledAtNanos)); err != nil {
+			return err
+		}
+	}
+}
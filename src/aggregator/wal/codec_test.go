@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package wal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	opts := protobuf.NewUnaggregatedOptions()
+	encoder := NewEncoder(opts)
+	require.NoError(t, encoder.Encode(testCounter, time.Unix(0, 1000)))
+	require.NoError(t, encoder.Encode(testTimed, time.Unix(0, 2000)))
+
+	var decoded []replayedMetric
+	err := Decode(encoder.Bytes(), opts, func(
+		msg encoding.UnaggregatedMessageUnion,
+		journaledAt time.Time,
+	) error {
+		var id string
+		switch msg.Type {
+		case encoding.CounterWithMetadatasType:
+			id = string(msg.CounterWithMetadatas.ID)
+		case encoding.TimedMetricWithMetadataType:
+			id = string(msg.TimedMetricWithMetadata.ID)
+		}
+		decoded = append(decoded, replayedMetric{id: id, journaledAt: journaledAt})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []replayedMetric{
+		{id: "foo", journaledAt: time.Unix(0, 1000)},
+		{id: "bar", journaledAt: time.Unix(0, 2000)},
+	}, decoded)
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	opts := protobuf.NewUnaggregatedOptions()
+	encoder := NewEncoder(opts)
+	require.NoError(t, encoder.Encode(testCounter, time.Unix(0, 1000)))
+	data := encoder.Bytes()
+
+	err := Decode(data[:len(data)-1], opts, func(
+		encoding.UnaggregatedMessageUnion,
+		time.Time,
+	) error {
+		return nil
+	})
+	require.Error(t, err)
+}
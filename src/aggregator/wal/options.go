@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package wal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	defaultSegmentDuration = time.Minute
+	defaultRetention       = 10 * time.Minute
+	defaultFlushInterval   = time.Second
+)
+
+var (
+	errNoDir                  = errors.New("no write-ahead log directory set")
+	errInvalidSegmentDuration = errors.New("write-ahead log segment duration must be positive")
+	errInvalidRetention       = errors.New("write-ahead log retention must be positive")
+	errInvalidFlushInterval   = errors.New("write-ahead log flush interval must be positive")
+)
+
+// Options provide a set of write-ahead log options.
+type Options interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetDir sets the directory the log is written to.
+	SetDir(value string) Options
+
+	// Dir returns the directory the log is written to.
+	Dir() string
+
+	// SetSegmentDuration sets the duration each segment is written to before a
+	// new segment is started.
+	SetSegmentDuration(value time.Duration) Options
+
+	// SegmentDuration returns the duration each segment is written to before a
+	// new segment is started.
+	SegmentDuration() time.Duration
+
+	// SetRetention sets how long a segment is retained and replayed after it is
+	// last written to, which should cover the longest aggregation window and its
+	// flush delay.
+	SetRetention(value time.Duration) Options
+
+	// Retention returns how long a segment is retained and replayed after it is
+	// last written to.
+	Retention() time.Duration
+
+	// SetFlushInterval sets the interval the log is flushed and synced to disk at,
+	// metrics journaled since the last flush are lost on a crash.
+	SetFlushInterval(value time.Duration) Options
+
+	// FlushInterval returns the interval the log is flushed and synced to disk at.
+	FlushInterval() time.Duration
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetUnaggregatedOptions sets the options used to encode and decode journaled metrics.
+	SetUnaggregatedOptions(value protobuf.UnaggregatedOptions) Options
+
+	// UnaggregatedOptions returns the options used to encode and decode journaled metrics.
+	UnaggregatedOptions() protobuf.UnaggregatedOptions
+}
+
+type options struct {
+	dir              string
+	segmentDuration  time.Duration
+	retention        time.Duration
+	flushInterval    time.Duration
+	clockOpts        clock.Options
+	instrumentOpts   instrument.Options
+	unaggregatedOpts protobuf.UnaggregatedOptions
+}
+
+// NewOptions creates a new set of write-ahead log options.
+func NewOptions() Options {
+	return &options{
+		segmentDuration:  defaultSegmentDuration,
+		retention:        defaultRetention,
+		flushInterval:    defaultFlushInterval,
+		clockOpts:        clock.NewOptions(),
+		instrumentOpts:   instrument.NewOptions(),
+		unaggregatedOpts: protobuf.NewUnaggregatedOptions(),
+	}
+}
+
+func (o *options) Validate() error {
+	if o.dir == "" {
+		return errNoDir
+	}
+	if o.segmentDuration <= 0 {
+		return errInvalidSegmentDuration
+	}
+	if o.retention <= 0 {
+		return errInvalidRetention
+	}
+	if o.flushInterval <= 0 {
+		return errInvalidFlushInterval
+	}
+	return nil
+}
+
+func (o *options) SetDir(value string) Options {
+	opts := *o
+	opts.dir = value
+	return &opts
+}
+
+func (o *options) Dir() string {
+	return o.dir
+}
+
+func (o *options) SetSegmentDuration(value time.Duration) Options {
+	opts := *o
+	opts.segmentDuration = value
+	return &opts
+}
+
+func (o *options) SegmentDuration() time.Duration {
+	return o.segmentDuration
+}
+
+func (o *options) SetRetention(value time.Duration) Options {
+	opts := *o
+	opts.retention = value
+	return &opts
+}
+
+func (o *options) Retention() time.Duration {
+	return o.retention
+}
+
+func (o *options) SetFlushInterval(value time.Duration) Options {
+	opts := *o
+	opts.flushInterval = value
+	return &opts
+}
+
+func (o *options) FlushInterval() time.Duration {
+	return o.flushInterval
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *options) SetUnaggregatedOptions(value protobuf.UnaggregatedOptions) Options {
+	opts := *o
+	opts.unaggregatedOpts = value
+	return &opts
+}
+
+func (o *options) UnaggregatedOptions() protobuf.UnaggregatedOptions {
+	return o.unaggregatedOpts
+}
@@ -0,0 +1,471 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package wal provides a write-ahead log journaling the metrics accepted by
+// the shards of an aggregator, so that the aggregation windows open when the
+// process crashes can be recovered on startup.
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	segmentSuffix   = ".wal"
+	dirPermissions  = 0755
+	filePermissions = 0644
+)
+
+var errWALClosed = errors.New("write-ahead log is closed")
+
+// ReplayFn is called with each metric replayed from the log along with the
+// time it was journaled at. The metric is only valid until the function returns.
+type ReplayFn func(msg encoding.UnaggregatedMessageUnion, journaledAt time.Time) error
+
+// WAL is a write-ahead log journaling the metrics accepted by each shard of an
+// aggregator. The log of each shard is split into segments which are removed
+// once they are older than the retention.
+type WAL interface {
+	// Write journals a metric accepted by the given shard.
+	Write(shard uint32, msg encoding.UnaggregatedMessageUnion) error
+
+	// Replay replays the metrics journaled by the given shard that have not
+	// expired in the order they were written. A segment truncated by a crash
+	// is replayed up to the last complete metric.
+	Replay(shard uint32, fn ReplayFn) error
+
+	// Close flushes and closes the log.
+	Close() error
+}
+
+type walMetrics struct {
+	written         tally.Counter
+	writeErrors     tally.Counter
+	flushErrors     tally.Counter
+	replayed        tally.Counter
+	truncated       tally.Counter
+	segmentsRemoved tally.Counter
+}
+
+func newWALMetrics(scope tally.Scope) walMetrics {
+	return walMetrics{
+		written:         scope.Counter("written"),
+		writeErrors:     scope.Counter("write-errors"),
+		flushErrors:     scope.Counter("flush-errors"),
+		replayed:        scope.Counter("replayed"),
+		truncated:       scope.Counter("truncated-segments"),
+		segmentsRemoved: scope.Counter("segments-removed"),
+	}
+}
+
+type wal struct {
+	sync.RWMutex
+
+	opts     Options
+	nowFn    clock.NowFn
+	logger   *zap.Logger
+	metrics  walMetrics
+	journals map[uint32]*journal
+	closed   bool
+	doneCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewWAL creates a new write-ahead log, flushing it to disk in the background
+// until it is closed.
+func NewWAL(opts Options) (WAL, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.Dir(), dirPermissions); err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	w := &wal{
+		opts:     opts,
+		nowFn:    opts.ClockOptions().NowFn(),
+		logger:   iOpts.Logger(),
+		metrics:  newWALMetrics(iOpts.MetricsScope().SubScope("wal")),
+		journals: make(map[uint32]*journal),
+		doneCh:   make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.flushLoop()
+	return w, nil
+}
+
+func (w *wal) Write(shard uint32, msg encoding.UnaggregatedMessageUnion) error {
+	j, err := w.journal(shard)
+	if err != nil {
+		return err
+	}
+	if err := j.write(w.nowFn(), msg); err != nil {
+		w.metrics.writeErrors.Inc(1)
+		return err
+	}
+	w.metrics.written.Inc(1)
+	return nil
+}
+
+func (w *wal) Replay(shard uint32, fn ReplayFn) error {
+	w.RLock()
+	if w.closed {
+		w.RUnlock()
+		return errWALClosed
+	}
+	j, exists := w.journals[shard]
+	w.RUnlock()
+
+	// Make sure everything journaled so far is visible to the reader.
+	if exists {
+		if err := j.flush(false); err != nil {
+			return err
+		}
+	}
+
+	dir := w.shardDir(shard)
+	segments, err := w.segments(dir)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if err := w.replaySegment(filepath.Join(dir, segment.name), fn); err != nil {
+			return fmt.Errorf("could not replay segment %s: %w", segment.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *wal) replaySegment(path string, fn ReplayFn) error {
+	f, err := os.Open(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint: errcheck
+
+	reader := bufio.NewReader(f)
+	it := protobuf.NewUnaggregatedIterator(reader, w.opts.UnaggregatedOptions())
+	defer it.Close()
+
+	// Each metric is preceded by the time it was journaled at, the iterator
+	// only reads the bytes of a single metric at a time so both can be read
+	// from the same reader.
+	for {
+		journaledAtNanos, err := binary.ReadVarint(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return w.truncated(path, err)
+		}
+		if !it.Next() {
+			return w.truncated(path, it.Err())
+		}
+		if err := fn(*it.Current(), time.Unix(0, journaledAtNanos)); err != nil {
+			return err
+		}
+		w.metrics.replayed.Inc(1)
+	}
+}
+
+// truncated handles the end of a segment that could not be read, which is
+// expected for the last metric written before a crash.
+func (w *wal) truncated(path string, err error) error {
+	if err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	w.metrics.truncated.Inc(1)
+	w.logger.Warn("write-ahead log segment truncated", zap.String("path", path))
+	return nil
+}
+
+func (w *wal) Close() error {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return errWALClosed
+	}
+	w.closed = true
+	close(w.doneCh)
+	journals := w.journals
+	w.Unlock()
+
+	w.wg.Wait()
+
+	var firstErr error
+	for _, j := range journals {
+		if err := j.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (w *wal) journal(shard uint32) (*journal, error) {
+	w.RLock()
+	if w.closed {
+		w.RUnlock()
+		return nil, errWALClosed
+	}
+	j, exists := w.journals[shard]
+	w.RUnlock()
+	if exists {
+		return j, nil
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return nil, errWALClosed
+	}
+	if j, exists = w.journals[shard]; exists {
+		return j, nil
+	}
+	dir := w.shardDir(shard)
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return nil, err
+	}
+	j = newJournal(dir, w)
+	w.journals[shard] = j
+	return j, nil
+}
+
+func (w *wal) flushLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.FlushInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.doneCh:
+			return
+		}
+
+		w.RLock()
+		journals := make([]*journal, 0, len(w.journals))
+		for _, j := range w.journals {
+			journals = append(journals, j)
+		}
+		w.RUnlock()
+
+		for _, j := range journals {
+			if err := j.flush(true); err != nil {
+				w.metrics.flushErrors.Inc(1)
+				w.logger.Error("could not flush write-ahead log", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (w *wal) shardDir(shard uint32) string {
+	return filepath.Join(w.opts.Dir(), strconv.FormatUint(uint64(shard), 10))
+}
+
+type segment struct {
+	name  string
+	start time.Time
+}
+
+// segments returns the segments in the directory that have not expired in
+// the order they were started, removing any expired segments.
+func (w *wal) segments(dir string) ([]segment, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		now      = w.nowFn()
+		segments = make([]segment, 0, len(files))
+	)
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		startNanos, err := strconv.ParseInt(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		s := segment{name: name, start: time.Unix(0, startNanos)}
+		if w.expired(s, now) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			w.metrics.segmentsRemoved.Inc(1)
+			continue
+		}
+		segments = append(segments, s)
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].start.Before(segments[j].start)
+	})
+	return segments, nil
+}
+
+func (w *wal) expired(s segment, now time.Time) bool {
+	lastWrite := s.start.Add(w.opts.SegmentDuration())
+	return now.Sub(lastWrite) > w.opts.Retention()
+}
+
+// journal is the log of a single shard.
+type journal struct {
+	sync.Mutex
+
+	dir          string
+	wal          *wal
+	encoder      protobuf.UnaggregatedEncoder
+	header       []byte
+	file         *os.File
+	writer       *bufio.Writer
+	segmentStart time.Time
+	dirty        bool
+	closed       bool
+}
+
+func newJournal(dir string, w *wal) *journal {
+	return &journal{
+		dir:     dir,
+		wal:     w,
+		encoder: protobuf.NewUnaggregatedEncoder(w.opts.UnaggregatedOptions()),
+		header:  make([]byte, binary.MaxVarintLen64),
+	}
+}
+
+func (j *journal) write(now time.Time, msg encoding.UnaggregatedMessageUnion) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.closed {
+		return errWALClosed
+	}
+	if j.file == nil || !now.Before(j.segmentStart.Add(j.wal.opts.SegmentDuration())) {
+		if err := j.rotateWithLock(now); err != nil {
+			return err
+		}
+	}
+
+	if err := j.encoder.EncodeMessage(msg); err != nil {
+		return err
+	}
+	buf := j.encoder.Relinquish()
+	defer buf.Close()
+
+	n := binary.PutVarint(j.header, now.UnixNano())
+	if _, err := j.writer.Write(j.header[:n]); err != nil {
+		return err
+	}
+	if _, err := j.writer.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	j.dirty = true
+	return nil
+}
+
+// rotateWithLock closes the current segment and starts a new one, removing
+// the segments that have expired.
+func (j *journal) rotateWithLock(now time.Time) error {
+	if err := j.closeWithLock(); err != nil {
+		return err
+	}
+	if _, err := j.wal.segments(j.dir); err != nil {
+		return err
+	}
+
+	name := strconv.FormatInt(now.UnixNano(), 10) + segmentSuffix
+	f, err := os.OpenFile(filepath.Join(j.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return err
+	}
+	j.file = f
+	j.writer = bufio.NewWriter(f)
+	j.segmentStart = now
+	return nil
+}
+
+func (j *journal) flush(sync bool) error {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.flushWithLock(sync)
+}
+
+func (j *journal) flushWithLock(sync bool) error {
+	if j.file == nil || !j.dirty {
+		return nil
+	}
+	if err := j.writer.Flush(); err != nil {
+		return err
+	}
+	if sync {
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+		j.dirty = false
+	}
+	return nil
+}
+
+func (j *journal) close() error {
+	j.Lock()
+	defer j.Unlock()
+
+	j.closed = true
+	return j.closeWithLock()
+}
+
+func (j *journal) closeWithLock() error {
+	if j.file == nil {
+		return nil
+	}
+	err := j.flushWithLock(true)
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file = nil
+	j.writer = nil
+	return err
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package wal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding"
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/metric/unaggregated"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testCounter = encoding.UnaggregatedMessageUnion{
+		Type: encoding.CounterWithMetadatasType,
+		CounterWithMetadatas: unaggregated.CounterWithMetadatas{
+			Counter: unaggregated.Counter{
+				ID:    []byte("foo"),
+				Value: 123,
+			},
+			StagedMetadatas: metadata.DefaultStagedMetadatas,
+		},
+	}
+	testTimed = encoding.UnaggregatedMessageUnion{
+		Type: encoding.TimedMetricWithMetadataType,
+		TimedMetricWithMetadata: aggregated.TimedMetricWithMetadata{
+			Metric: aggregated.Metric{
+				ID:        []byte("bar"),
+				TimeNanos: 12345,
+				Value:     1.5,
+			},
+			TimedMetadata: metadata.TimedMetadata{
+				StoragePolicy: policy.MustParseStoragePolicy("10s:2d"),
+			},
+		},
+	}
+)
+
+type replayedMetric struct {
+	id          string
+	journaledAt time.Time
+}
+
+func testOptions(t *testing.T, now *time.Time) (Options, func()) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	opts := NewOptions().
+		SetDir(dir).
+		SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return *now }))
+	return opts, func() { os.RemoveAll(dir) }
+}
+
+func replay(t *testing.T, w WAL, shard uint32) []replayedMetric {
+	var replayed []replayedMetric
+	require.NoError(t, w.Replay(shard, func(msg encoding.UnaggregatedMessageUnion, journaledAt time.Time) error {
+		var id string
+		switch msg.Type {
+		case encoding.CounterWithMetadatasType:
+			id = string(msg.CounterWithMetadatas.ID)
+		case encoding.TimedMetricWithMetadataType:
+			id = string(msg.TimedMetricWithMetadata.ID)
+		}
+		replayed = append(replayed, replayedMetric{id: id, journaledAt: journaledAt})
+		return nil
+	}))
+	return replayed
+}
+
+func TestWALWriteReplay(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts, cleanup := testOptions(t, &now)
+	defer cleanup()
+
+	w, err := NewWAL(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(1, testCounter))
+	now = now.Add(time.Second)
+	require.NoError(t, w.Write(2, testTimed))
+
+	// Rotate to a new segment.
+	now = now.Add(opts.SegmentDuration())
+	require.NoError(t, w.Write(1, testTimed))
+	require.NoError(t, w.Close())
+
+	w, err = NewWAL(opts)
+	require.NoError(t, err)
+	defer w.Close() // nolint: errcheck
+
+	require.Equal(t, []replayedMetric{
+		{id: "foo", journaledAt: time.Unix(1000, 0)},
+		{id: "bar", journaledAt: now},
+	}, replay(t, w, 1))
+	require.Equal(t, []replayedMetric{
+		{id: "bar", journaledAt: time.Unix(1001, 0)},
+	}, replay(t, w, 2))
+	require.Empty(t, replay(t, w, 3))
+}
+
+func TestWALReplayUnflushed(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts, cleanup := testOptions(t, &now)
+	defer cleanup()
+
+	w, err := NewWAL(opts.SetFlushInterval(time.Hour))
+	require.NoError(t, err)
+	defer w.Close() // nolint: errcheck
+
+	require.NoError(t, w.Write(1, testCounter))
+	require.Equal(t, []replayedMetric{
+		{id: "foo", journaledAt: now},
+	}, replay(t, w, 1))
+}
+
+func TestWALReplayTruncatedSegment(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts, cleanup := testOptions(t, &now)
+	defer cleanup()
+
+	w, err := NewWAL(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(1, testCounter))
+	require.NoError(t, w.Write(1, testTimed))
+	require.NoError(t, w.Close())
+
+	// Simulate a crash part way through writing the last metric.
+	segments, err := filepath.Glob(filepath.Join(opts.Dir(), "1", "*"+segmentSuffix))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	info, err := os.Stat(segments[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(segments[0], info.Size()-2))
+
+	w, err = NewWAL(opts)
+	require.NoError(t, err)
+	defer w.Close() // nolint: errcheck
+
+	require.Equal(t, []replayedMetric{
+		{id: "foo", journaledAt: now},
+	}, replay(t, w, 1))
+}
+
+func TestWALReplaySkipsExpiredSegments(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts, cleanup := testOptions(t, &now)
+	defer cleanup()
+
+	w, err := NewWAL(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(1, testCounter))
+	now = now.Add(opts.SegmentDuration())
+	require.NoError(t, w.Write(1, testTimed))
+	require.NoError(t, w.Close())
+
+	now = now.Add(opts.Retention() + time.Second)
+	w, err = NewWAL(opts)
+	require.NoError(t, err)
+	defer w.Close() // nolint: errcheck
+
+	require.Equal(t, []replayedMetric{
+		{id: "bar", journaledAt: now.Add(-opts.Retention() - time.Second)},
+	}, replay(t, w, 1))
+
+	segments, err := filepath.Glob(filepath.Join(opts.Dir(), "1", "*"+segmentSuffix))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+}
+
+func TestWALClosed(t *testing.T) {
+	now := time.Unix(1000, 0)
+	opts, cleanup := testOptions(t, &now)
+	defer cleanup()
+
+	w, err := NewWAL(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.Equal(t, errWALClosed, w.Write(1, testCounter))
+	require.Equal(t, errWALClosed, w.Close())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.Equal(t, errNoDir, NewOptions().Validate())
+	require.NoError(t, NewOptions().SetDir("/tmp").Validate())
+	require.Equal(t, errInvalidRetention, NewOptions().SetDir("/tmp").SetRetention(0).Validate())
+}
@@ -180,6 +180,7 @@ type Instance struct {
 	Hostname       string            `protobuf:"bytes,8,opt,name=hostname,proto3" json:"hostname,omitempty"`
 	Port           uint32            `protobuf:"varint,9,opt,name=port,proto3" json:"port,omitempty"`
 	Metadata       *InstanceMetadata `protobuf:"bytes,10,opt,name=metadata" json:"metadata,omitempty"`
+	Maintenance    bool              `protobuf:"varint,11,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
 }
 
 func (m *Instance) Reset()                    { *m = Instance{} }
@@ -257,6 +258,13 @@ func (m *Instance) GetMetadata() *InstanceMetadata {
 	return nil
 }
 
+func (m *Instance) GetMaintenance() bool {
+	if m != nil {
+		return m.Maintenance
+	}
+	return false
+}
+
 type InstanceMetadata struct {
 	DebugPort uint32 `protobuf:"varint,1,opt,name=debug_port,json=debugPort,proto3" json:"debug_port,omitempty"`
 }
@@ -563,6 +571,16 @@ func (m *Instance) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if m.Maintenance {
+		dAtA[i] = 0x58
+		i++
+		if m.Maintenance {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -800,6 +818,9 @@ func (m *Instance) Size() (n int) {
 		l = m.Metadata.Size()
 		n += 1 + l + sovPlacement(uint64(l))
 	}
+	if m.Maintenance {
+		n += 2
+	}
 	return n
 }
 
@@ -1464,6 +1485,26 @@ func (m *Instance) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Maintenance", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlacement
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Maintenance = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPlacement(dAtA[iNdEx:])
@@ -2066,58 +2107,58 @@ func init() {
 }
 
 var fileDescriptorPlacement = []byte{
-	// 835 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xcd, 0x6e, 0x1b, 0x37,
-	0x10, 0xf6, 0x4a, 0xb6, 0xac, 0x1d, 0xfd, 0x74, 0xcb, 0xa6, 0xe9, 0xd6, 0x6d, 0x54, 0x55, 0x45,
-	0x50, 0xc1, 0x45, 0x25, 0x44, 0xbe, 0x24, 0x39, 0x14, 0x90, 0x13, 0x37, 0xd8, 0xc2, 0x56, 0x02,
-	0x4a, 0xf5, 0x21, 0x97, 0x05, 0xb5, 0xa4, 0x24, 0xa2, 0xda, 0xe5, 0x82, 0xe4, 0xe6, 0xa7, 0x4f,
-	0xd1, 0x57, 0x2a, 0xd0, 0x43, 0x8f, 0x7d, 0x84, 0xc2, 0x7d, 0x88, 0x02, 0x3d, 0x15, 0x4b, 0xee,
-	0xea, 0x27, 0xf6, 0x8d, 0xf3, 0xcd, 0x37, 0x9c, 0xe1, 0x37, 0x33, 0x84, 0x9f, 0x96, 0x5c, 0xaf,
-	0xb2, 0xf9, 0x20, 0x12, 0xf1, 0x30, 0x3e, 0xa3, 0xf3, 0x61, 0x7c, 0x36, 0x54, 0x32, 0x1a, 0x46,
-	0xeb, 0x4c, 0x69, 0x26, 0x87, 0x4b, 0x96, 0x30, 0x49, 0x34, 0xa3, 0xc3, 0x54, 0x0a, 0x2d, 0x86,
-	0xe9, 0x9a, 0x44, 0x2c, 0x66, 0x89, 0x4e, 0xe7, 0xdb, 0xf3, 0xc0, 0xf8, 0x50, 0x63, 0xc7, 0x79,
-	0xd2, 0x59, 0x0a, 0xb1, 0x5c, 0x33, 0x1b, 0x36, 0xcf, 0x16, 0xc3, 0xb7, 0x92, 0xa4, 0x29, 0x93,
-	0xca, 0x92, 0x7b, 0xff, 0x56, 0xc0, 0x7d, 0x55, 0xf2, 0xd1, 0x33, 0x70, 0x79, 0xa2, 0x34, 0x49,
-	0x22, 0xa6, 0x7c, 0xa7, 0x5b, 0xed, 0x37, 0x46, 0x0f, 0x07, 0x3b, 0xd7, 0x0d, 0x36, 0xd4, 0x41,
-	0x50, 0xf2, 0x2e, 0x12, 0x2d, 0xdf, 0xe3, 0x6d, 0x1c, 0x7a, 0x08, 0x6d, 0xc9, 0xd2, 0x35, 0x8f,
-	0x48, 0xb8, 0x20, 0x91, 0x16, 0xd2, 0xaf, 0x74, 0x9d, 0x7e, 0x0b, 0xb7, 0x0a, 0xf4, 0x47, 0x03,
-	0xa2, 0x07, 0x00, 0x49, 0x16, 0x87, 0x6a, 0x45, 0x24, 0x55, 0x7e, 0xd5, 0x50, 0xdc, 0x24, 0x8b,
-	0xa7, 0x06, 0xc8, 0xdd, 0x5c, 0x59, 0x2f, 0xa3, 0xfe, 0x61, 0xd7, 0xe9, 0xd7, 0xb1, 0xcb, 0xd5,
-	0xd4, 0x02, 0xe8, 0x6b, 0x68, 0x46, 0x99, 0x16, 0x6f, 0x98, 0x0c, 0x35, 0x8f, 0x99, 0x7f, 0xd4,
-	0x75, 0xfa, 0x55, 0xdc, 0x28, 0xb0, 0x19, 0x8f, 0x19, 0xfa, 0x0a, 0x1a, 0x5c, 0x85, 0x31, 0x97,
-	0x52, 0x48, 0x46, 0xfd, 0x9a, 0xb9, 0x02, 0xb8, 0xba, 0x2a, 0x10, 0xf4, 0x2d, 0x78, 0x31, 0x79,
-	0x67, 0x73, 0x84, 0x8a, 0xe9, 0x90, 0x53, 0xff, 0xd8, 0x96, 0x1a, 0x93, 0x77, 0x26, 0xd3, 0x94,
-	0xe9, 0x80, 0x9e, 0x4c, 0xa1, 0xbd, 0xff, 0x5c, 0xe4, 0x41, 0xf5, 0x17, 0xf6, 0xde, 0x77, 0xba,
-	0x4e, 0xdf, 0xc5, 0xf9, 0x11, 0x7d, 0x07, 0x47, 0x6f, 0xc8, 0x3a, 0x63, 0xe6, 0xb1, 0x8d, 0xd1,
-	0xa7, 0x7b, 0xb2, 0x95, 0xd1, 0xd8, 0x72, 0x9e, 0x56, 0x1e, 0x3b, 0xbd, 0x3f, 0x2a, 0x50, 0x2f,
-	0x71, 0xd4, 0x86, 0x0a, 0xa7, 0xc5, 0x75, 0x15, 0x9e, 0x97, 0xf6, 0x11, 0x57, 0x62, 0x4d, 0x34,
-	0x17, 0x49, 0xb8, 0x94, 0x22, 0x4b, 0xcd, 0xbd, 0x2e, 0x6e, 0x6f, 0xe0, 0x17, 0x39, 0x8a, 0x10,
-	0x1c, 0xfe, 0x2a, 0x12, 0x66, 0xf4, 0x73, 0xb1, 0x39, 0xa3, 0xfb, 0x50, 0x7b, 0xcb, 0xf8, 0x72,
-	0xa5, 0x8d, 0x6c, 0x2d, 0x5c, 0x58, 0xe8, 0x04, 0xea, 0x2c, 0xa1, 0xa9, 0xe0, 0x89, 0x36, 0x7a,
-	0xb9, 0x78, 0x63, 0xa3, 0x53, 0xa8, 0x15, 0x9d, 0xa8, 0x99, 0xb6, 0xa3, 0xbd, 0xfa, 0x8d, 0x16,
-	0xb8, 0x60, 0xa0, 0x2e, 0x34, 0xef, 0xd0, 0x0c, 0xd4, 0x46, 0xb0, 0x3c, 0xd3, 0x4a, 0x28, 0x9d,
-	0x90, 0x98, 0xf9, 0x75, 0x9b, 0xa9, 0xb4, 0xf3, 0x8a, 0x53, 0x21, 0xb5, 0xef, 0x9a, 0x28, 0x73,
-	0x46, 0x4f, 0xa0, 0x1e, 0x33, 0x4d, 0x28, 0xd1, 0xc4, 0x07, 0xa3, 0xdf, 0x83, 0x3b, 0xf5, 0xbb,
-	0x2a, 0x48, 0x78, 0x43, 0xef, 0x3d, 0x02, 0xef, 0x43, 0x6f, 0x3e, 0x3b, 0x94, 0xcd, 0xb3, 0x65,
-	0x68, 0x12, 0x39, 0x76, 0xb4, 0x0c, 0xf2, 0x4a, 0x48, 0xdd, 0xfb, 0xcf, 0x81, 0x23, 0xf3, 0xa2,
-	0x1d, 0xd9, 0x5b, 0x46, 0xf6, 0xef, 0xe1, 0x48, 0x69, 0xa2, 0x6d, 0x13, 0xdb, 0xa3, 0xcf, 0x6e,
-	0x8b, 0x30, 0xcd, 0xdd, 0xd8, 0xb2, 0xd0, 0x17, 0xe0, 0x2a, 0x91, 0xc9, 0x88, 0xe5, 0x2a, 0xd8,
-	0x0e, 0xd4, 0x2d, 0x10, 0x50, 0xf4, 0x0d, 0xb4, 0xca, 0x09, 0x4d, 0x48, 0x22, 0x94, 0x69, 0x46,
-	0x15, 0x97, 0x63, 0x3b, 0xc9, 0xb1, 0x72, 0x8c, 0x17, 0x8b, 0x82, 0xb3, 0x33, 0xc6, 0x8b, 0x85,
-	0xa5, 0x5c, 0xc1, 0x3d, 0xc9, 0x28, 0x97, 0x2c, 0xd2, 0xa1, 0x16, 0xc5, 0xb4, 0x72, 0x3b, 0xcf,
-	0x8d, 0xd1, 0x97, 0x03, 0xbb, 0xe0, 0x83, 0x72, 0xc1, 0x07, 0x3f, 0x07, 0x89, 0x3e, 0x1b, 0x5d,
-	0xe7, 0x73, 0x86, 0x3f, 0x2e, 0x23, 0x67, 0xc2, 0x54, 0x1f, 0xd0, 0xde, 0xef, 0x0e, 0xa0, 0xcd,
-	0x16, 0x4f, 0x13, 0x92, 0xaa, 0x95, 0xd0, 0x0a, 0x3d, 0x06, 0x57, 0x95, 0x46, 0xb1, 0xf9, 0xf7,
-	0xef, 0xde, 0xfc, 0xf3, 0x8a, 0xef, 0xe0, 0x2d, 0x19, 0xfd, 0x00, 0xad, 0x48, 0xc4, 0xa9, 0x64,
-	0x4a, 0x85, 0xb1, 0xa0, 0xa5, 0x76, 0x9f, 0xef, 0x45, 0x3f, 0x2b, 0x18, 0x57, 0x82, 0x32, 0xdc,
-	0x8c, 0x76, 0x2c, 0xf4, 0x08, 0xee, 0x95, 0x36, 0xa3, 0xe1, 0x26, 0xc8, 0xe8, 0xd9, 0xc4, 0x9f,
-	0x6c, 0x7d, 0x9b, 0x0a, 0x7a, 0xcf, 0xe1, 0xf8, 0x65, 0x9a, 0xef, 0x80, 0x42, 0x4f, 0xf6, 0xbe,
-	0x09, 0xc7, 0x68, 0x72, 0x72, 0x4b, 0x93, 0x73, 0x21, 0xd6, 0x56, 0x91, 0xed, 0x17, 0x72, 0xfa,
-	0x14, 0x60, 0xdb, 0x52, 0xe4, 0x41, 0x33, 0x98, 0x04, 0xb3, 0x60, 0x7c, 0x19, 0xbc, 0x0e, 0x26,
-	0x2f, 0xbc, 0x03, 0xd4, 0x02, 0x77, 0x7c, 0x3d, 0x0e, 0x2e, 0xc7, 0xe7, 0x97, 0x17, 0x9e, 0x83,
-	0x1a, 0x70, 0x7c, 0x79, 0x31, 0xbe, 0xce, 0x7d, 0x95, 0xd3, 0x1e, 0x34, 0x77, 0x9f, 0x84, 0xea,
-	0x70, 0x38, 0x79, 0x39, 0xb9, 0xf0, 0x0e, 0xf2, 0xd3, 0xeb, 0xe9, 0xec, 0xb9, 0xe7, 0x9c, 0x7b,
-	0x7f, 0xde, 0x74, 0x9c, 0xbf, 0x6e, 0x3a, 0xce, 0xdf, 0x37, 0x1d, 0xe7, 0xb7, 0x7f, 0x3a, 0x07,
-	0xf3, 0x9a, 0x29, 0xe8, 0xec, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x46, 0x70, 0xf8, 0xb6, 0xee,
-	0x05, 0x00, 0x00,
+	// 848 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xcf, 0x6e, 0x1b, 0xb7,
+	0x13, 0xf6, 0x4a, 0xb6, 0xac, 0x9d, 0x95, 0xf4, 0xdb, 0x1f, 0x9b, 0xa6, 0x5b, 0xb7, 0x51, 0x55,
+	0x15, 0x41, 0x05, 0x17, 0x95, 0x10, 0xf9, 0x92, 0xe4, 0x50, 0x40, 0x4e, 0xdc, 0x60, 0x0b, 0x5b,
+	0x09, 0x28, 0xd7, 0x87, 0x5c, 0x16, 0xd4, 0x2e, 0x25, 0x11, 0xd5, 0x92, 0x0b, 0x92, 0x9b, 0x3f,
+	0x7d, 0x8a, 0xbe, 0x52, 0x6f, 0x3d, 0xf6, 0x11, 0x0a, 0x17, 0xe8, 0x2b, 0x14, 0xe8, 0xa9, 0x58,
+	0x72, 0x57, 0x7f, 0x1a, 0xdf, 0x66, 0xbe, 0xf9, 0x86, 0x1c, 0x7e, 0x33, 0x1c, 0xf8, 0x61, 0xc9,
+	0xf4, 0x2a, 0x9f, 0x0f, 0x63, 0x91, 0x8e, 0xd2, 0xb3, 0x64, 0x3e, 0x4a, 0xcf, 0x46, 0x4a, 0xc6,
+	0xa3, 0x78, 0x9d, 0x2b, 0x4d, 0xe5, 0x68, 0x49, 0x39, 0x95, 0x44, 0xd3, 0x64, 0x94, 0x49, 0xa1,
+	0xc5, 0x28, 0x5b, 0x93, 0x98, 0xa6, 0x94, 0xeb, 0x6c, 0xbe, 0xb5, 0x87, 0x26, 0x86, 0xbc, 0x9d,
+	0xe0, 0x49, 0x77, 0x29, 0xc4, 0x72, 0x4d, 0x6d, 0xda, 0x3c, 0x5f, 0x8c, 0xde, 0x4a, 0x92, 0x65,
+	0x54, 0x2a, 0x4b, 0xee, 0xff, 0x5d, 0x03, 0xf7, 0x55, 0xc5, 0x47, 0xcf, 0xc0, 0x65, 0x5c, 0x69,
+	0xc2, 0x63, 0xaa, 0x02, 0xa7, 0x57, 0x1f, 0x78, 0xe3, 0x87, 0xc3, 0x9d, 0xe3, 0x86, 0x1b, 0xea,
+	0x30, 0xac, 0x78, 0x17, 0x5c, 0xcb, 0xf7, 0x78, 0x9b, 0x87, 0x1e, 0x42, 0x47, 0xd2, 0x6c, 0xcd,
+	0x62, 0x12, 0x2d, 0x48, 0xac, 0x85, 0x0c, 0x6a, 0x3d, 0x67, 0xd0, 0xc6, 0xed, 0x12, 0xfd, 0xde,
+	0x80, 0xe8, 0x01, 0x00, 0xcf, 0xd3, 0x48, 0xad, 0x88, 0x4c, 0x54, 0x50, 0x37, 0x14, 0x97, 0xe7,
+	0xe9, 0xcc, 0x00, 0x45, 0x98, 0x29, 0x1b, 0xa5, 0x49, 0x70, 0xd8, 0x73, 0x06, 0x4d, 0xec, 0x32,
+	0x35, 0xb3, 0x00, 0xfa, 0x12, 0x5a, 0x71, 0xae, 0xc5, 0x1b, 0x2a, 0x23, 0xcd, 0x52, 0x1a, 0x1c,
+	0xf5, 0x9c, 0x41, 0x1d, 0x7b, 0x25, 0x76, 0xcd, 0x52, 0x8a, 0xbe, 0x00, 0x8f, 0xa9, 0x28, 0x65,
+	0x52, 0x0a, 0x49, 0x93, 0xa0, 0x61, 0x8e, 0x00, 0xa6, 0xae, 0x4a, 0x04, 0x7d, 0x0d, 0x7e, 0x4a,
+	0xde, 0xd9, 0x3b, 0x22, 0x45, 0x75, 0xc4, 0x92, 0xe0, 0xd8, 0x96, 0x9a, 0x92, 0x77, 0xe6, 0xa6,
+	0x19, 0xd5, 0x61, 0x72, 0x32, 0x83, 0xce, 0xfe, 0x73, 0x91, 0x0f, 0xf5, 0x9f, 0xe8, 0xfb, 0xc0,
+	0xe9, 0x39, 0x03, 0x17, 0x17, 0x26, 0xfa, 0x06, 0x8e, 0xde, 0x90, 0x75, 0x4e, 0xcd, 0x63, 0xbd,
+	0xf1, 0xc7, 0x7b, 0xb2, 0x55, 0xd9, 0xd8, 0x72, 0x9e, 0xd6, 0x1e, 0x3b, 0xfd, 0xbf, 0x6a, 0xd0,
+	0xac, 0x70, 0xd4, 0x81, 0x1a, 0x4b, 0xca, 0xe3, 0x6a, 0xac, 0x28, 0xed, 0x7f, 0x4c, 0x89, 0x35,
+	0xd1, 0x4c, 0xf0, 0x68, 0x29, 0x45, 0x9e, 0x99, 0x73, 0x5d, 0xdc, 0xd9, 0xc0, 0x2f, 0x0a, 0x14,
+	0x21, 0x38, 0xfc, 0x59, 0x70, 0x6a, 0xf4, 0x73, 0xb1, 0xb1, 0xd1, 0x7d, 0x68, 0xbc, 0xa5, 0x6c,
+	0xb9, 0xd2, 0x46, 0xb6, 0x36, 0x2e, 0x3d, 0x74, 0x02, 0x4d, 0xca, 0x93, 0x4c, 0x30, 0xae, 0x8d,
+	0x5e, 0x2e, 0xde, 0xf8, 0xe8, 0x14, 0x1a, 0x65, 0x27, 0x1a, 0xa6, 0xed, 0x68, 0xaf, 0x7e, 0xa3,
+	0x05, 0x2e, 0x19, 0xa8, 0x07, 0xad, 0x3b, 0x34, 0x03, 0xb5, 0x11, 0xac, 0xb8, 0x69, 0x25, 0x94,
+	0xe6, 0x24, 0xa5, 0x41, 0xd3, 0xde, 0x54, 0xf9, 0x45, 0xc5, 0x99, 0x90, 0x3a, 0x70, 0x4d, 0x96,
+	0xb1, 0xd1, 0x13, 0x68, 0xa6, 0x54, 0x93, 0x84, 0x68, 0x12, 0x80, 0xd1, 0xef, 0xc1, 0x9d, 0xfa,
+	0x5d, 0x95, 0x24, 0xbc, 0xa1, 0xa3, 0x1e, 0x78, 0x29, 0x61, 0x5c, 0x53, 0x5e, 0x10, 0x02, 0xcf,
+	0x74, 0x79, 0x17, 0xea, 0x3f, 0x02, 0xff, 0xbf, 0xf9, 0xc5, 0x74, 0x25, 0x74, 0x9e, 0x2f, 0x23,
+	0x53, 0x8a, 0x63, 0x87, 0xcf, 0x20, 0xaf, 0x84, 0xd4, 0xfd, 0x7f, 0x1c, 0x38, 0x32, 0x6f, 0xde,
+	0x69, 0x4c, 0xdb, 0x34, 0xe6, 0x5b, 0x38, 0x52, 0x9a, 0x68, 0xdb, 0xe6, 0xce, 0xf8, 0x93, 0x0f,
+	0x65, 0x9a, 0x15, 0x61, 0x6c, 0x59, 0xe8, 0x33, 0x70, 0x95, 0xc8, 0x65, 0x4c, 0x0b, 0x9d, 0x6c,
+	0x8f, 0x9a, 0x16, 0x08, 0x13, 0xf4, 0x15, 0xb4, 0xab, 0x19, 0xe6, 0x84, 0x0b, 0x65, 0xda, 0x55,
+	0xc7, 0xd5, 0x60, 0x4f, 0x0b, 0xac, 0x1a, 0xf4, 0xc5, 0xa2, 0xe4, 0xec, 0x0c, 0xfa, 0x62, 0x61,
+	0x29, 0x57, 0x70, 0x4f, 0xd2, 0x84, 0x49, 0x1a, 0xeb, 0x48, 0x8b, 0x72, 0x9e, 0x99, 0x9d, 0x78,
+	0x6f, 0xfc, 0xf9, 0xd0, 0xae, 0x80, 0x61, 0xb5, 0x02, 0x86, 0x3f, 0x86, 0x5c, 0x9f, 0x8d, 0x6f,
+	0x8a, 0x49, 0xc4, 0xff, 0xaf, 0x32, 0xaf, 0x85, 0xa9, 0x3e, 0x4c, 0xfa, 0xbf, 0x3a, 0x80, 0x36,
+	0xff, 0x7c, 0xc6, 0x49, 0xa6, 0x56, 0x42, 0x2b, 0xf4, 0x18, 0x5c, 0x55, 0x39, 0xe5, 0x6e, 0xb8,
+	0x7f, 0xf7, 0x6e, 0x38, 0xaf, 0x05, 0x0e, 0xde, 0x92, 0xd1, 0x77, 0xd0, 0x8e, 0x45, 0x9a, 0x49,
+	0xaa, 0x54, 0x94, 0x8a, 0xa4, 0xd2, 0xee, 0xd3, 0xbd, 0xec, 0x67, 0x25, 0xe3, 0x4a, 0x24, 0x14,
+	0xb7, 0xe2, 0x1d, 0x0f, 0x3d, 0x82, 0x7b, 0x95, 0x4f, 0x93, 0x68, 0x93, 0x64, 0xf4, 0x6c, 0xe1,
+	0x8f, 0xb6, 0xb1, 0x4d, 0x05, 0xfd, 0xe7, 0x70, 0xfc, 0x32, 0x2b, 0x7e, 0x89, 0x42, 0x4f, 0xf6,
+	0x16, 0x89, 0x63, 0x34, 0x39, 0xf9, 0x40, 0x93, 0x73, 0x21, 0xd6, 0x56, 0x91, 0xed, 0x92, 0x39,
+	0x7d, 0x0a, 0xb0, 0x6d, 0x29, 0xf2, 0xa1, 0x15, 0x4e, 0xc3, 0xeb, 0x70, 0x72, 0x19, 0xbe, 0x0e,
+	0xa7, 0x2f, 0xfc, 0x03, 0xd4, 0x06, 0x77, 0x72, 0x33, 0x09, 0x2f, 0x27, 0xe7, 0x97, 0x17, 0xbe,
+	0x83, 0x3c, 0x38, 0xbe, 0xbc, 0x98, 0xdc, 0x14, 0xb1, 0xda, 0x69, 0x1f, 0x5a, 0xbb, 0x4f, 0x42,
+	0x4d, 0x38, 0x9c, 0xbe, 0x9c, 0x5e, 0xf8, 0x07, 0x85, 0xf5, 0x7a, 0x76, 0xfd, 0xdc, 0x77, 0xce,
+	0xfd, 0xdf, 0x6e, 0xbb, 0xce, 0xef, 0xb7, 0x5d, 0xe7, 0x8f, 0xdb, 0xae, 0xf3, 0xcb, 0x9f, 0xdd,
+	0x83, 0x79, 0xc3, 0x14, 0x74, 0xf6, 0xef, 0x00, 0x81, 0x20, 0x7e, 0x14, 0x10, 0x06, 0x00, 0x00,
 }
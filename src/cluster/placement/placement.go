@@ -450,7 +450,8 @@ func NewInstanceFromProto(instance *placementpb.Instance) (Instance, error) {
 		SetPort(instance.Port).
 		SetMetadata(InstanceMetadata{
 			DebugPort: debugPort,
-		}), nil
+		}).
+		SetMaintenance(instance.Maintenance), nil
 }
 
 type instance struct {
@@ -464,12 +465,13 @@ type instance struct {
 	weight         uint32
 	shardSetID     uint32
 	metadata       InstanceMetadata
+	maintenance    bool
 }
 
 func (i *instance) String() string {
 	return fmt.Sprintf(
-		"Instance[ID=%s, IsolationGroup=%s, Zone=%s, Weight=%d, Endpoint=%s, Hostname=%s, Port=%d, ShardSetID=%d, Shards=%s, Metadata=%+v]",
-		i.id, i.isolationGroup, i.zone, i.weight, i.endpoint, i.hostname, i.port, i.shardSetID, i.shards.String(), i.metadata,
+		"Instance[ID=%s, IsolationGroup=%s, Zone=%s, Weight=%d, Endpoint=%s, Hostname=%s, Port=%d, ShardSetID=%d, Shards=%s, Metadata=%+v, Maintenance=%t]",
+		i.id, i.isolationGroup, i.zone, i.weight, i.endpoint, i.hostname, i.port, i.shardSetID, i.shards.String(), i.metadata, i.maintenance,
 	)
 }
 
@@ -563,6 +565,15 @@ func (i *instance) SetMetadata(value InstanceMetadata) Instance {
 	return i
 }
 
+func (i *instance) Maintenance() bool {
+	return i.maintenance
+}
+
+func (i *instance) SetMaintenance(value bool) Instance {
+	i.maintenance = value
+	return i
+}
+
 func (i *instance) Proto() (*placementpb.Instance, error) {
 	ss, err := i.Shards().Proto()
 	if err != nil {
@@ -582,6 +593,7 @@ func (i *instance) Proto() (*placementpb.Instance, error) {
 		Metadata: &placementpb.InstanceMetadata{
 			DebugPort: i.Metadata().DebugPort,
 		},
+		Maintenance: i.Maintenance(),
 	}, nil
 }
 
@@ -617,7 +629,8 @@ func (i *instance) Clone() Instance {
 		SetPort(i.Port()).
 		SetShardSetID(i.ShardSetID()).
 		SetShards(i.Shards().Clone()).
-		SetMetadata(i.Metadata())
+		SetMetadata(i.Metadata()).
+		SetMaintenance(i.Maintenance())
 }
 
 // Instances is a slice of instances that can produce a debug string.
@@ -173,6 +173,20 @@ func (mr *MockInstanceMockRecorder) IsolationGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsolationGroup", reflect.TypeOf((*MockInstance)(nil).IsolationGroup))
 }
 
+// Maintenance mocks base method.
+func (m *MockInstance) Maintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Maintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Maintenance indicates an expected call of Maintenance.
+func (mr *MockInstanceMockRecorder) Maintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockInstance)(nil).Maintenance))
+}
+
 // Metadata mocks base method.
 func (m *MockInstance) Metadata() InstanceMetadata {
 	m.ctrl.T.Helper()
@@ -272,6 +286,20 @@ func (mr *MockInstanceMockRecorder) SetIsolationGroup(r interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsolationGroup", reflect.TypeOf((*MockInstance)(nil).SetIsolationGroup), r)
 }
 
+// SetMaintenance mocks base method.
+func (m *MockInstance) SetMaintenance(value bool) Instance {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenance", value)
+	ret0, _ := ret[0].(Instance)
+	return ret0
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance.
+func (mr *MockInstanceMockRecorder) SetMaintenance(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockInstance)(nil).SetMaintenance), value)
+}
+
 // SetMetadata mocks base method.
 func (m *MockInstance) SetMetadata(value InstanceMetadata) Instance {
 	m.ctrl.T.Helper()
@@ -480,7 +480,7 @@ func TestInstance(t *testing.T) {
 	})
 	i1.SetShards(s)
 	description := fmt.Sprintf(
-		"Instance[ID=id, IsolationGroup=isolationGroup, Zone=zone, Weight=1, Endpoint=endpoint, Hostname=host1, Port=123, ShardSetID=0, Shards=%s, Metadata={DebugPort:456}]",
+		"Instance[ID=id, IsolationGroup=isolationGroup, Zone=zone, Weight=1, Endpoint=endpoint, Hostname=host1, Port=123, ShardSetID=0, Shards=%s, Metadata={DebugPort:456}, Maintenance=false]",
 		s.String())
 	assert.Equal(t, description, i1.String())
 
@@ -756,6 +756,29 @@ func TestPlacementInstanceToProto(t *testing.T) {
 	assert.Equal(t, expInstance, instanceProto)
 }
 
+func TestPlacementInstanceMaintenance(t *testing.T) {
+	instance := NewEmptyInstance("i1", "r1", "z1", "e1", 1)
+	assert.False(t, instance.Maintenance())
+
+	instance.SetMaintenance(true)
+	assert.True(t, instance.Maintenance())
+	assert.True(t, instance.Clone().Maintenance())
+
+	instanceProto, err := instance.Proto()
+	assert.NoError(t, err)
+	assert.True(t, instanceProto.Maintenance)
+
+	b, err := instanceProto.Marshal()
+	assert.NoError(t, err)
+	var decoded placementpb.Instance
+	assert.NoError(t, decoded.Unmarshal(b))
+	assert.True(t, decoded.Maintenance)
+
+	instance, err = NewInstanceFromProto(&decoded)
+	assert.NoError(t, err)
+	assert.True(t, instance.Maintenance())
+}
+
 func getProtoShards(ids []uint32) []*placementpb.Shard {
 	r := make([]*placementpb.Shard, len(ids))
 	for i, id := range ids {
@@ -98,6 +98,15 @@ type Instance interface {
 	// SetMetadata sets the metadata of the instance.
 	SetMetadata(value InstanceMetadata) Instance
 
+	// Maintenance returns whether the instance is in maintenance. Writers
+	// deprioritize instances in maintenance, or skip them when the remaining
+	// replicas are enough to satisfy the write, while the instance keeps
+	// ownership of its shards.
+	Maintenance() bool
+
+	// SetMaintenance sets whether the instance is in maintenance.
+	SetMaintenance(value bool) Instance
+
 	// Proto returns the proto representation for the Instance.
 	Proto() (*placementpb.Instance, error)
 
@@ -78,7 +78,7 @@ func TestPlacementAddHandler_Force(t *testing.T) {
 		resp := w.Result()
 		body, _ := ioutil.ReadAll(resp.Body)
 		assert.JSONEq(t,
-			`{"status":"error","error":"no new instances found in the valid zone"}`,
+			`{"status":"error","error":"no new instances found in the valid zone","code":"internal","retryable":false}`,
 			string(body))
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 
@@ -139,7 +139,7 @@ func TestPlacementAddHandler_SafeErr_NoNewInstance(t *testing.T) {
 		body, _ := ioutil.ReadAll(resp.Body)
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 		assert.JSONEq(t,
-			`{"status":"error","error":"no new instances found in the valid zone"}`,
+			`{"status":"error","error":"no new instances found in the valid zone","code":"internal","retryable":false}`,
 			string(body))
 	})
 }
@@ -178,7 +178,7 @@ func TestPlacementAddHandler_SafeErr_NotAllAvailable(t *testing.T) {
 		body, _ := ioutil.ReadAll(resp.Body)
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 		assert.JSONEq(t,
-			`{"status":"error","error":"instances do not have all shards available: [A, B]"}`,
+			`{"status":"error","error":"instances do not have all shards available: [A, B]","code":"invalid_params","retryable":false}`,
 			string(body))
 	})
 }
@@ -246,7 +246,7 @@ func TestPlacementAddHandler_SafeOK(t *testing.T) {
 		resp := w.Result()
 		body, _ := ioutil.ReadAll(resp.Body)
 		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		require.JSONEq(t, `{"status":"error","error":"test err"}`, string(body))
+		require.JSONEq(t, `{"status":"error","error":"test err","code":"internal","retryable":false}`, string(body))
 
 		w = httptest.NewRecorder()
 		if serviceName == handleroptions.M3AggregatorServiceName {
@@ -285,7 +285,7 @@ func TestPlacementAddHandler_SafeOK(t *testing.T) {
 
 		switch serviceName {
 		case handleroptions.M3CoordinatorServiceName:
-			require.Equal(t, `{"placement":{"instances":{"host1":{"id":"host1","isolationGroup":"rack1","zone":"test","weight":1,"endpoint":"http://host1:1234","shards":[],"shardSetId":0,"hostname":"host1","port":1234,"metadata":{"debugPort":0}}},"replicaFactor":1,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":1}`, string(body))
+			require.Equal(t, `{"placement":{"instances":{"host1":{"id":"host1","isolationGroup":"rack1","zone":"test","weight":1,"endpoint":"http://host1:1234","shards":[],"shardSetId":0,"hostname":"host1","port":1234,"metadata":{"debugPort":0},"maintenance":false}},"replicaFactor":1,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":1}`, string(body))
 		case handleroptions.M3AggregatorServiceName:
 			require.Equal(t, `{"placement":{"instances":{},"replicaFactor":1,"numShards":0,"isSharded":true,"cutoverTime":"0","isMirrored":true,"maxShardSetId":0},"version":1}`, string(body))
 		default:
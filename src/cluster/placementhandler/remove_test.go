@@ -71,7 +71,7 @@ func TestPlacementRemoveHandler_Force(t *testing.T) {
 
 		body, _ := ioutil.ReadAll(resp.Body)
 		assert.JSONEq(t,
-			`{"status":"error","error":"no new instances found in the valid zone"}`,
+			`{"status":"error","error":"no new instances found in the valid zone","code":"internal","retryable":false}`,
 			string(body))
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 
@@ -150,7 +150,7 @@ func TestPlacementRemoveHandler_SafeOK(t *testing.T) {
 
 		body, _ := ioutil.ReadAll(resp.Body)
 		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		require.JSONEq(t, `{"status":"error","error":"test err"}`, string(body))
+		require.JSONEq(t, `{"status":"error","error":"test err","code":"internal","retryable":false}`, string(body))
 
 		w = httptest.NewRecorder()
 		if serviceName == handleroptions.M3AggregatorServiceName {
@@ -192,7 +192,7 @@ func TestPlacementRemoveHandler_SafeOK(t *testing.T) {
 		switch serviceName {
 		case handleroptions.M3CoordinatorServiceName:
 			//nolint: lll
-			require.Equal(t, `{"placement":{"instances":{"host1":{"id":"host1","isolationGroup":"rack1","zone":"test","weight":1,"endpoint":"http://host1:1234","shards":[],"shardSetId":0,"hostname":"host1","port":1234,"metadata":{"debugPort":0}}},"replicaFactor":1,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":1}`, string(body))
+			require.Equal(t, `{"placement":{"instances":{"host1":{"id":"host1","isolationGroup":"rack1","zone":"test","weight":1,"endpoint":"http://host1:1234","shards":[],"shardSetId":0,"hostname":"host1","port":1234,"metadata":{"debugPort":0},"maintenance":false}},"replicaFactor":1,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":1}`, string(body))
 		case handleroptions.M3AggregatorServiceName:
 			//nolint: lll
 			require.Equal(t, `{"placement":{"instances":{},"replicaFactor":1,"numShards":0,"isSharded":true,"cutoverTime":"0","isMirrored":true,"maxShardSetId":0},"version":1}`, string(body))
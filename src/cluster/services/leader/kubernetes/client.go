@@ -0,0 +1,449 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services/leader"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/x/clock"
+
+	"go.uber.org/zap"
+)
+
+// Used as the lease name suffix of elections with an empty election ID, the
+// same as the etcd backed leader service.
+const defaultElectionID = "default"
+
+// errLeaseLost is returned in the campaign status when the leader could not
+// renew its lease before the renew deadline, or another candidate took it over.
+var errLeaseLost = errors.New("kubernetes lease was lost")
+
+// client campaigns for a single election by holding the lease of the election.
+type client struct {
+	sync.Mutex
+
+	name          string
+	leases        *leaseClient
+	opts          Options
+	nowFn         clock.NowFn
+	logger        *zap.Logger
+	leaseDuration time.Duration
+	ctx           context.Context
+	cancel        context.CancelFunc
+
+	// observed is the latest version of the lease seen and observedAt is when
+	// it was first seen. A lease held by another candidate is considered expired
+	// once it has not changed for the lease duration measured with the local
+	// clock, so that clock skew between candidates does not matter.
+	observed   *lease
+	observedAt time.Time
+
+	campaignID       uint64
+	campaigning      bool
+	campaignCancelFn context.CancelFunc
+	identity         string
+	closed           bool
+}
+
+func newClient(leases *leaseClient, opts Options, name string) *client {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &client{
+		name:          name,
+		leases:        leases,
+		opts:          opts,
+		nowFn:         opts.ClockOptions().NowFn(),
+		logger:        opts.InstrumentOptions().Logger().With(zap.String("lease", name)),
+		leaseDuration: leaseDuration(opts.ElectionOpts()),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func (c *client) campaign(identity string) (<-chan campaign.Status, error) {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return nil, errServiceClosed
+	}
+	if c.campaigning {
+		c.Unlock()
+		return nil, leader.ErrCampaignInProgress
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.campaignID++
+	id := c.campaignID
+	c.campaigning = true
+	c.campaignCancelFn = cancel
+	c.identity = identity
+	c.Unlock()
+
+	// Buffer 1 to not block the initial follower update.
+	sc := make(chan campaign.Status, 1)
+	sc <- campaign.NewStatus(campaign.Follower)
+
+	go func() {
+		defer func() {
+			close(sc)
+			cancel()
+			c.stopCampaign(id)
+		}()
+
+		// Acquiring blocks until elected or the campaign is cancelled.
+		if !c.acquire(ctx, identity) {
+			return
+		}
+
+		sc <- campaign.NewStatus(campaign.Leader)
+		if err := c.renew(ctx, identity); err != nil {
+			sc <- campaign.NewErrorStatus(err)
+			return
+		}
+		sc <- campaign.NewStatus(campaign.Follower)
+	}()
+
+	return sc, nil
+}
+
+func (c *client) stopCampaign(id uint64) {
+	c.Lock()
+	if c.campaignID == id {
+		c.campaigning = false
+		c.campaignCancelFn = nil
+	}
+	c.Unlock()
+}
+
+func (c *client) resign() error {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return errServiceClosed
+	}
+	campaigning, cancel, identity := c.campaigning, c.campaignCancelFn, c.identity
+	c.campaigning = false
+	c.campaignCancelFn = nil
+	c.Unlock()
+
+	if !campaigning {
+		return nil
+	}
+	cancel()
+
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), c.opts.ElectionOpts().ResignTimeout())
+	defer cancelTimeout()
+	return c.release(ctx, identity)
+}
+
+func (c *client) leader() (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.opts.ElectionOpts().LeaderTimeout())
+	defer cancel()
+
+	l, err := c.leases.get(ctx, c.name)
+	if err == errLeaseNotFound {
+		return "", ErrNoLeader
+	}
+	if err != nil {
+		return "", err
+	}
+
+	now := c.nowFn()
+	c.record(l, now)
+	if l.Spec.HolderIdentity == "" || c.expired(now) {
+		return "", ErrNoLeader
+	}
+	return l.Spec.HolderIdentity, nil
+}
+
+// observe polls the lease every retry period and returns a channel the leader
+// is sent on whenever it changes, the channel is closed once the client is.
+func (c *client) observe() <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(c.opts.RetryPeriod())
+		defer ticker.Stop()
+
+		var last string
+		for {
+			if ld, err := c.leader(); err == nil && ld != last {
+				select {
+				case ch <- ld:
+					last = ld
+				case <-c.ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// close stops all campaigns and observers, and releases the lease if held.
+func (c *client) close() error {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return nil
+	}
+	c.closed = true
+	campaigning, identity := c.campaigning, c.identity
+	c.campaigning = false
+	c.campaignCancelFn = nil
+	c.Unlock()
+
+	c.cancel()
+	if !campaigning {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.ElectionOpts().ResignTimeout())
+	defer cancel()
+	return c.release(ctx, identity)
+}
+
+// acquire attempts to acquire the lease every retry period, returning true
+// once acquired or false if the context is done first.
+func (c *client) acquire(ctx context.Context, identity string) bool {
+	ticker := time.NewTicker(c.opts.RetryPeriod())
+	defer ticker.Stop()
+
+	for {
+		acquired, err := c.tryAcquireOrRenew(ctx, identity)
+		if acquired {
+			return true
+		}
+		if err != nil && err != errLeaseConflict && ctx.Err() == nil {
+			c.logger.Warn("could not acquire lease", zap.Error(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// renew renews the lease every retry period until the context is done, or
+// returns errLeaseLost once another candidate took over the lease or it could
+// not be renewed for the renew deadline.
+func (c *client) renew(ctx context.Context, identity string) error {
+	ticker := time.NewTicker(c.opts.RetryPeriod())
+	defer ticker.Stop()
+
+	lastRenewed := c.nowFn()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+
+		// Each attempt is bounded by the renew deadline so that a request that
+		// hangs cannot keep the leader from stepping down once other candidates
+		// may consider the lease expired.
+		deadline := lastRenewed.Add(c.opts.RenewDeadline())
+		attemptCtx, cancel := context.WithDeadline(ctx, deadline)
+		attemptStart := c.nowFn()
+		renewed, err := c.tryAcquireOrRenew(attemptCtx, identity)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if renewed {
+			// NB: other candidates may observe the renewal as soon as the
+			// request was sent, so the deadline is measured from then.
+			lastRenewed = attemptStart
+			continue
+		}
+		if err == nil {
+			c.logger.Warn("lease was taken over by another candidate")
+			return errLeaseLost
+		}
+		if attemptCtx.Err() != nil || !c.nowFn().Before(deadline) {
+			c.logger.Error("could not renew lease before the renew deadline",
+				zap.Duration("renewDeadline", c.opts.RenewDeadline()), zap.Error(err))
+			return errLeaseLost
+		}
+		c.logger.Warn("could not renew lease", zap.Error(err))
+	}
+}
+
+// tryAcquireOrRenew acquires the lease if it is not held or has expired, or
+// renews it if already held. It returns false without an error if the lease is
+// held by another candidate.
+func (c *client) tryAcquireOrRenew(ctx context.Context, identity string) (bool, error) {
+	now := c.nowFn()
+	leaseDurationSecs := int32(c.leaseDuration / time.Second)
+
+	current, err := c.leases.get(ctx, c.name)
+	if err == errLeaseNotFound {
+		created, err := c.leases.create(ctx, lease{
+			Metadata: leaseMetadata{Name: c.name},
+			Spec: leaseSpec{
+				HolderIdentity:       identity,
+				LeaseDurationSeconds: leaseDurationSecs,
+				AcquireTime:          newMicroTime(now),
+				RenewTime:            newMicroTime(now),
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		c.record(created, now)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c.record(current, now)
+	holder := current.Spec.HolderIdentity
+	if holder != "" && holder != identity && !c.expired(now) {
+		return false, nil
+	}
+
+	updated := current
+	updated.Spec.HolderIdentity = identity
+	updated.Spec.LeaseDurationSeconds = leaseDurationSecs
+	updated.Spec.RenewTime = newMicroTime(now)
+	if holder != identity {
+		updated.Spec.AcquireTime = newMicroTime(now)
+		updated.Spec.LeaseTransitions++
+	}
+	result, err := c.leases.update(ctx, updated)
+	if err != nil {
+		return false, err
+	}
+	c.record(result, now)
+	return true, nil
+}
+
+// release gives up the lease if it is held by the identity, retrying when the
+// lease is modified concurrently such as by an in-flight renewal.
+func (c *client) release(ctx context.Context, identity string) error {
+	for {
+		err := c.tryRelease(ctx, identity)
+		if err != errLeaseConflict {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *client) tryRelease(ctx context.Context, identity string) error {
+	current, err := c.leases.get(ctx, c.name)
+	if err == errLeaseNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.Spec.HolderIdentity != identity {
+		return nil
+	}
+
+	now := c.nowFn()
+	released := current
+	released.Spec.HolderIdentity = ""
+	released.Spec.AcquireTime = nil
+	released.Spec.RenewTime = newMicroTime(now)
+	result, err := c.leases.update(ctx, released)
+	if err != nil {
+		return err
+	}
+	c.record(result, now)
+	return nil
+}
+
+// record records the lease as observed at the given time if it changed since
+// it was last observed.
+func (c *client) record(l lease, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.observed != nil && c.observed.Metadata.ResourceVersion == l.Metadata.ResourceVersion {
+		return
+	}
+	c.observed = &l
+	c.observedAt = now
+}
+
+// expired returns whether the observed lease has not been renewed for its
+// lease duration.
+func (c *client) expired(now time.Time) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.observed == nil {
+		return true
+	}
+	leaseDuration := time.Duration(c.observed.Spec.LeaseDurationSeconds) * time.Second
+	if leaseDuration <= 0 {
+		leaseDuration = c.leaseDuration
+	}
+	return !now.Before(c.observedAt.Add(leaseDuration))
+}
+
+// leaseName returns the name of the lease of an election, which must be a
+// valid DNS subdomain name so election IDs are lowercased and all characters
+// other than alphanumerics, '-' and '.' are replaced with '-'. Since distinct
+// election IDs can map to the same name that way, a hash of the election ID is
+// appended to the name whenever it had to be changed.
+func leaseName(prefix, electionID string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, electionID)
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		name = defaultElectionID
+	}
+	if name != electionID && electionID != "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(electionID))
+		name = fmt.Sprintf("%s-%08x", name, h.Sum32())
+	}
+	return prefix + "-" + name
+}
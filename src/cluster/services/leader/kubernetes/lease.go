@@ -0,0 +1,188 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	leaseAPIVersion = "coordination.k8s.io/v1"
+	leaseKind       = "Lease"
+	microTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	maxErrorBody    = 4096
+)
+
+var (
+	errLeaseNotFound = errors.New("lease not found")
+	errLeaseConflict = errors.New("lease was modified concurrently")
+)
+
+// lease is the subset of a coordination/v1 Lease object used for elections.
+type lease struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   leaseMetadata `json:"metadata"`
+	Spec       leaseSpec     `json:"spec"`
+}
+
+type leaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       string     `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int32      `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          *microTime `json:"acquireTime,omitempty"`
+	RenewTime            *microTime `json:"renewTime,omitempty"`
+	LeaseTransitions     int32      `json:"leaseTransitions,omitempty"`
+}
+
+// microTime is a time serialized with microsecond precision the same as the
+// MicroTime type of the Kubernetes API.
+type microTime struct {
+	time.Time
+}
+
+func newMicroTime(t time.Time) *microTime {
+	return &microTime{Time: t.UTC().Truncate(time.Microsecond)}
+}
+
+func (t microTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(microTimeFormat))
+}
+
+func (t *microTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// leaseClient reads and writes the leases of a namespace through the API server.
+type leaseClient struct {
+	httpClient *http.Client
+	leasesURL  string
+	namespace  string
+	tokenFile  string
+}
+
+func newLeaseClient(opts Options) *leaseClient {
+	return &leaseClient{
+		httpClient: opts.HTTPClient(),
+		leasesURL: fmt.Sprintf("%s/apis/%s/namespaces/%s/leases",
+			strings.TrimSuffix(opts.APIServerURL(), "/"), leaseAPIVersion,
+			url.PathEscape(opts.Namespace())),
+		namespace: opts.Namespace(),
+		tokenFile: opts.TokenFile(),
+	}
+}
+
+func (c *leaseClient) get(ctx context.Context, name string) (lease, error) {
+	return c.do(ctx, http.MethodGet, c.leasesURL+"/"+url.PathEscape(name), nil)
+}
+
+func (c *leaseClient) create(ctx context.Context, l lease) (lease, error) {
+	return c.do(ctx, http.MethodPost, c.leasesURL, c.withTypeMeta(l))
+}
+
+// update replaces a lease, the update fails with errLeaseConflict if the lease
+// has been modified since it was read.
+func (c *leaseClient) update(ctx context.Context, l lease) (lease, error) {
+	return c.do(ctx, http.MethodPut, c.leasesURL+"/"+url.PathEscape(l.Metadata.Name), c.withTypeMeta(l))
+}
+
+func (c *leaseClient) withTypeMeta(l lease) *lease {
+	l.APIVersion = leaseAPIVersion
+	l.Kind = leaseKind
+	l.Metadata.Namespace = c.namespace
+	return &l
+}
+
+func (c *leaseClient) do(ctx context.Context, method, reqURL string, body *lease) (lease, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return lease{}, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, reqURL, reqBody)
+	if err != nil {
+		return lease{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tokenFile != "" {
+		token, err := ioutil.ReadFile(c.tokenFile)
+		if err != nil {
+			return lease{}, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return lease{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusNotFound:
+		return lease{}, errLeaseNotFound
+	case http.StatusConflict:
+		return lease{}, errLeaseConflict
+	default:
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return lease{}, fmt.Errorf("%s %s returned status %d: %s",
+			method, reqURL, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result lease
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return lease{}, err
+	}
+	return result, nil
+}
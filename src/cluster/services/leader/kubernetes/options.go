@@ -0,0 +1,331 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	serviceAccountDir    = "/var/run/secrets/kubernetes.io/serviceaccount"
+	defaultTokenFile     = serviceAccountDir + "/token"
+	defaultCAFile        = serviceAccountDir + "/ca.crt"
+	defaultNamespaceFile = serviceAccountDir + "/namespace"
+	defaultRetryPeriod   = 2 * time.Second
+	// defaultLeaseDuration is used when the election options do not specify a
+	// TTL, it matches the default TTL of etcd campaign sessions.
+	defaultLeaseDuration  = 60 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+)
+
+var (
+	errNotInCluster          = errors.New("not running in a kubernetes cluster, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+	errAPIServerURLNotSet    = errors.New("kubernetes leader options must specify the API server url")
+	errNamespaceNotSet       = errors.New("kubernetes leader options must specify the namespace")
+	errLeaseNamePrefixNotSet = errors.New("kubernetes leader options must specify the lease name prefix")
+	errHTTPClientNotSet      = errors.New("kubernetes leader options must specify the http client")
+	errElectionOptsNotSet    = errors.New("kubernetes leader options election opts cannot be nil")
+	errRetryPeriodInvalid    = errors.New("kubernetes leader options retry period must be positive")
+)
+
+// Options describe options for creating a Kubernetes lease backed leader service.
+type Options interface {
+	// SetAPIServerURL sets the url of the Kubernetes API server.
+	SetAPIServerURL(value string) Options
+
+	// APIServerURL returns the url of the Kubernetes API server.
+	APIServerURL() string
+
+	// SetNamespace sets the namespace the leases are created in.
+	SetNamespace(value string) Options
+
+	// Namespace returns the namespace the leases are created in.
+	Namespace() string
+
+	// SetLeaseNamePrefix sets the prefix of the lease names, the lease of an
+	// election is named after the prefix and the election ID.
+	SetLeaseNamePrefix(value string) Options
+
+	// LeaseNamePrefix returns the prefix of the lease names.
+	LeaseNamePrefix() string
+
+	// SetTokenFile sets the file the bearer token used to authenticate with the
+	// API server is read from, the file is read on every request so that
+	// rotated tokens are picked up. No token is sent when not set.
+	SetTokenFile(value string) Options
+
+	// TokenFile returns the file the bearer token is read from.
+	TokenFile() string
+
+	// SetHTTPClient sets the http client used to reach the API server.
+	SetHTTPClient(value *http.Client) Options
+
+	// HTTPClient returns the http client used to reach the API server.
+	HTTPClient() *http.Client
+
+	// SetElectionOpts sets the election options, the TTL is used as the
+	// lease duration and defaults to a minute when not set.
+	SetElectionOpts(value services.ElectionOptions) Options
+
+	// ElectionOpts returns the election options.
+	ElectionOpts() services.ElectionOptions
+
+	// SetRetryPeriod sets how often leases are renewed by the leader and
+	// acquiring them is attempted by the candidates.
+	SetRetryPeriod(value time.Duration) Options
+
+	// RetryPeriod returns how often leases are renewed or acquiring them
+	// is attempted.
+	RetryPeriod() time.Duration
+
+	// SetRenewDeadline sets how long the leader keeps trying to renew its
+	// lease before giving up leadership, defaults to two thirds of the lease
+	// duration when not set.
+	SetRenewDeadline(value time.Duration) Options
+
+	// RenewDeadline returns how long the leader keeps trying to renew its lease.
+	RenewDeadline() time.Duration
+
+	// SetClockOptions sets the clock options.
+	SetClockOptions(value clock.Options) Options
+
+	// ClockOptions returns the clock options.
+	ClockOptions() clock.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// Validate validates the options.
+	Validate() error
+}
+
+type options struct {
+	apiServerURL    string
+	namespace       string
+	leaseNamePrefix string
+	tokenFile       string
+	httpClient      *http.Client
+	electionOpts    services.ElectionOptions
+	retryPeriod     time.Duration
+	renewDeadline   time.Duration
+	clockOpts       clock.Options
+	instrumentOpts  instrument.Options
+}
+
+// NewOptions returns an instance of Kubernetes leader options.
+func NewOptions() Options {
+	return &options{
+		httpClient:     &http.Client{Timeout: defaultRequestTimeout},
+		electionOpts:   services.NewElectionOptions(),
+		retryPeriod:    defaultRetryPeriod,
+		clockOpts:      clock.NewOptions(),
+		instrumentOpts: instrument.NewOptions(),
+	}
+}
+
+// NewInClusterOptions returns Kubernetes leader options for a process running
+// in a pod, which reaches the API server with the service account of the pod
+// and creates leases in the namespace of the pod.
+func NewInClusterOptions() (Options, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errNotInCluster
+	}
+
+	ca, err := ioutil.ReadFile(defaultCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates found in %s", defaultCAFile)
+	}
+	httpClient := &http.Client{
+		Timeout: defaultRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+
+	opts := NewOptions().
+		SetAPIServerURL("https://" + net.JoinHostPort(host, port)).
+		SetTokenFile(defaultTokenFile).
+		SetHTTPClient(httpClient)
+	if namespace, err := ioutil.ReadFile(defaultNamespaceFile); err == nil {
+		opts = opts.SetNamespace(strings.TrimSpace(string(namespace)))
+	}
+	return opts, nil
+}
+
+func (o *options) SetAPIServerURL(value string) Options {
+	opts := *o
+	opts.apiServerURL = value
+	return &opts
+}
+
+func (o *options) APIServerURL() string {
+	return o.apiServerURL
+}
+
+func (o *options) SetNamespace(value string) Options {
+	opts := *o
+	opts.namespace = value
+	return &opts
+}
+
+func (o *options) Namespace() string {
+	return o.namespace
+}
+
+func (o *options) SetLeaseNamePrefix(value string) Options {
+	opts := *o
+	opts.leaseNamePrefix = value
+	return &opts
+}
+
+func (o *options) LeaseNamePrefix() string {
+	return o.leaseNamePrefix
+}
+
+func (o *options) SetTokenFile(value string) Options {
+	opts := *o
+	opts.tokenFile = value
+	return &opts
+}
+
+func (o *options) TokenFile() string {
+	return o.tokenFile
+}
+
+func (o *options) SetHTTPClient(value *http.Client) Options {
+	opts := *o
+	opts.httpClient = value
+	return &opts
+}
+
+func (o *options) HTTPClient() *http.Client {
+	return o.httpClient
+}
+
+func (o *options) SetElectionOpts(value services.ElectionOptions) Options {
+	opts := *o
+	opts.electionOpts = value
+	return &opts
+}
+
+func (o *options) ElectionOpts() services.ElectionOptions {
+	return o.electionOpts
+}
+
+func (o *options) SetRetryPeriod(value time.Duration) Options {
+	opts := *o
+	opts.retryPeriod = value
+	return &opts
+}
+
+func (o *options) RetryPeriod() time.Duration {
+	return o.retryPeriod
+}
+
+func (o *options) SetRenewDeadline(value time.Duration) Options {
+	opts := *o
+	opts.renewDeadline = value
+	return &opts
+}
+
+func (o *options) RenewDeadline() time.Duration {
+	if o.renewDeadline == 0 && o.electionOpts != nil {
+		return leaseDuration(o.electionOpts) * 2 / 3
+	}
+	return o.renewDeadline
+}
+
+func (o *options) SetClockOptions(value clock.Options) Options {
+	opts := *o
+	opts.clockOpts = value
+	return &opts
+}
+
+func (o *options) ClockOptions() clock.Options {
+	return o.clockOpts
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
+
+func (o *options) Validate() error {
+	if o.apiServerURL == "" {
+		return errAPIServerURLNotSet
+	}
+	if o.namespace == "" {
+		return errNamespaceNotSet
+	}
+	if o.leaseNamePrefix == "" {
+		return errLeaseNamePrefixNotSet
+	}
+	if o.httpClient == nil {
+		return errHTTPClientNotSet
+	}
+	if o.electionOpts == nil {
+		return errElectionOptsNotSet
+	}
+	if o.retryPeriod <= 0 {
+		return errRetryPeriodInvalid
+	}
+	leaseDuration := leaseDuration(o.electionOpts)
+	if renewDeadline := o.RenewDeadline(); renewDeadline <= o.retryPeriod || renewDeadline >= leaseDuration {
+		return fmt.Errorf(
+			"kubernetes leader options renew deadline %v must be longer than the retry period %v "+
+				"and shorter than the lease duration %v", renewDeadline, o.retryPeriod, leaseDuration)
+	}
+	return nil
+}
+
+func leaseDuration(electionOpts services.ElectionOptions) time.Duration {
+	if ttl := electionOpts.TTLSecs(); ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return defaultLeaseDuration
+}
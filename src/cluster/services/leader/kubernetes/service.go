@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+)
+
+var (
+	// ErrNoLeader is returned when the leader of an election is requested while
+	// no candidate holds its lease. It is the same error the etcd backed leader
+	// service returns so that callers can check for either.
+	ErrNoLeader = leader.ErrNoLeader
+
+	// errServiceClosed indicates the leader service has been closed and no more
+	// elections can be started.
+	errServiceClosed = errors.New("kubernetes leader service is closed")
+)
+
+type service struct {
+	sync.RWMutex
+
+	closed  bool
+	clients map[string]*client
+	opts    Options
+	leases  *leaseClient
+}
+
+// NewService creates a new leader service where the leader of each election
+// holds a Kubernetes lease named after the lease name prefix and the election
+// ID. Leases are renewed by the leader every retry period, and can be taken
+// over by another candidate once they have not been renewed for the lease
+// duration.
+func NewService(opts Options) (services.LeaderService, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &service{
+		clients: make(map[string]*client),
+		opts:    opts,
+		leases:  newLeaseClient(opts),
+	}, nil
+}
+
+// Close closes all election clients, releasing the leases held.
+func (s *service) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil
+	}
+	s.closed = true
+	clients := make([]*client, 0, len(s.clients))
+	for _, cl := range s.clients {
+		clients = append(clients, cl)
+	}
+	s.Unlock()
+
+	var (
+		wg       sync.WaitGroup
+		errLock  sync.Mutex
+		firstErr error
+	)
+	for _, cl := range clients {
+		cl := cl
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cl.close(); err != nil {
+				errLock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (s *service) Campaign(electionID string, opts services.CampaignOptions) (<-chan campaign.Status, error) {
+	if opts == nil {
+		return nil, errors.New("cannot pass nil campaign options")
+	}
+
+	cl, err := s.getOrCreateClient(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.campaign(opts.LeaderValue())
+}
+
+func (s *service) Resign(electionID string) error {
+	s.RLock()
+	closed := s.closed
+	cl, ok := s.clients[electionID]
+	s.RUnlock()
+
+	if closed {
+		return errServiceClosed
+	}
+	if !ok {
+		return fmt.Errorf("no election with ID '%s' to resign", electionID)
+	}
+
+	return cl.resign()
+}
+
+func (s *service) Leader(electionID string) (string, error) {
+	// Always create a client so that elections can be checked without
+	// campaigning.
+	cl, err := s.getOrCreateClient(electionID)
+	if err != nil {
+		return "", err
+	}
+
+	return cl.leader()
+}
+
+func (s *service) Observe(electionID string) (<-chan string, error) {
+	cl, err := s.getOrCreateClient(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.observe(), nil
+}
+
+func (s *service) getOrCreateClient(electionID string) (*client, error) {
+	s.RLock()
+	closed := s.closed
+	cl, ok := s.clients[electionID]
+	s.RUnlock()
+	if closed {
+		return nil, errServiceClosed
+	}
+	if ok {
+		return cl, nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil, errServiceClosed
+	}
+	if cl, ok := s.clients[electionID]; ok {
+		return cl, nil
+	}
+	cl = newClient(s.leases, s.opts, leaseName(s.opts.LeaseNamePrefix(), electionID))
+	s.clients[electionID] = cl
+	return cl, nil
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader"
+	"github.com/m3db/m3/src/cluster/services/leader/campaign"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testElectionID = "shardset/0/lock"
+
+// fakeLeaseServer serves the leases of a namespace the same as the API server,
+// rejecting updates of leases that have been modified since they were read.
+type fakeLeaseServer struct {
+	sync.Mutex
+
+	leases       map[string]lease
+	version      int
+	failUpdates  bool
+	hangUpdates  bool
+	namespaceURL string
+}
+
+func newFakeLeaseServer() *fakeLeaseServer {
+	return &fakeLeaseServer{
+		leases:       make(map[string]lease),
+		namespaceURL: "/apis/coordination.k8s.io/v1/namespaces/m3/leases",
+	}
+}
+
+func (s *fakeLeaseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && s.hangingUpdates() {
+		// Hang until the client gives up on the request, which is only noticed
+		// once the request body has been read.
+		_, _ = io.Copy(ioutil.Discard, r.Body)
+		<-r.Context().Done()
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	var l lease
+	if r.Method != http.MethodGet {
+		if err := json.NewDecoder(r.Body).Decode(&l); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch {
+	case r.Method == http.MethodGet:
+		var ok bool
+		if l, ok = s.leases[path.Base(r.URL.Path)]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	case r.Method == http.MethodPost && r.URL.Path == s.namespaceURL:
+		if _, ok := s.leases[l.Metadata.Name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.store(l)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && r.URL.Path == s.namespaceURL+"/"+l.Metadata.Name:
+		if s.failUpdates {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		existing, ok := s.leases[l.Metadata.Name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if existing.Metadata.ResourceVersion != l.Metadata.ResourceVersion {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		l = s.store(l)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.leases[l.Metadata.Name])
+}
+
+func (s *fakeLeaseServer) store(l lease) lease {
+	s.version++
+	l.Metadata.ResourceVersion = strconv.Itoa(s.version)
+	s.leases[l.Metadata.Name] = l
+	return l
+}
+
+func (s *fakeLeaseServer) lease(name string) (lease, bool) {
+	s.Lock()
+	defer s.Unlock()
+	l, ok := s.leases[name]
+	return l, ok
+}
+
+func (s *fakeLeaseServer) setFailUpdates(value bool) {
+	s.Lock()
+	s.failUpdates = value
+	s.Unlock()
+}
+
+func (s *fakeLeaseServer) setHangUpdates(value bool) {
+	s.Lock()
+	s.hangUpdates = value
+	s.Unlock()
+}
+
+func (s *fakeLeaseServer) hangingUpdates() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.hangUpdates
+}
+
+func newTestService(t *testing.T, url string, nowFn clock.NowFn) services.LeaderService {
+	opts := NewOptions().
+		SetAPIServerURL(url).
+		SetNamespace("m3").
+		SetLeaseNamePrefix("m3aggregator").
+		SetElectionOpts(services.NewElectionOptions().SetTTLSecs(1)).
+		SetRetryPeriod(10 * time.Millisecond)
+	if nowFn != nil {
+		opts = opts.SetClockOptions(clock.NewOptions().SetNowFn(nowFn))
+	}
+	svc, err := NewService(opts)
+	require.NoError(t, err)
+	return svc
+}
+
+func newTestCampaignOptions(t *testing.T, leaderValue string) services.CampaignOptions {
+	opts, err := services.NewCampaignOptions()
+	require.NoError(t, err)
+	return opts.SetLeaderValue(leaderValue)
+}
+
+func requireStatus(t *testing.T, ch <-chan campaign.Status, expected campaign.State) campaign.Status {
+	select {
+	case status, ok := <-ch:
+		require.True(t, ok)
+		require.Equal(t, expected, status.State)
+		return status
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for campaign status", expected.String())
+		return campaign.Status{}
+	}
+}
+
+func TestServiceCampaignAndResign(t *testing.T) {
+	server := newFakeLeaseServer()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	svc1 := newTestService(t, httpServer.URL, nil)
+	defer svc1.Close()
+	svc2 := newTestService(t, httpServer.URL, nil)
+	defer svc2.Close()
+
+	_, err := svc1.Leader(testElectionID)
+	require.Equal(t, ErrNoLeader, err)
+
+	ch1, err := svc1.Campaign(testElectionID, newTestCampaignOptions(t, "i1"))
+	require.NoError(t, err)
+	requireStatus(t, ch1, campaign.Follower)
+	requireStatus(t, ch1, campaign.Leader)
+
+	_, err = svc1.Campaign(testElectionID, newTestCampaignOptions(t, "i1"))
+	require.Equal(t, leader.ErrCampaignInProgress, err)
+
+	ld, err := svc2.Leader(testElectionID)
+	require.NoError(t, err)
+	require.Equal(t, "i1", ld)
+
+	ch2, err := svc2.Campaign(testElectionID, newTestCampaignOptions(t, "i2"))
+	require.NoError(t, err)
+	requireStatus(t, ch2, campaign.Follower)
+	select {
+	case status := <-ch2:
+		require.FailNow(t, "unexpected campaign status", status.State.String())
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, svc1.Resign(testElectionID))
+	requireStatus(t, ch1, campaign.Follower)
+	_, ok := <-ch1
+	require.False(t, ok)
+
+	requireStatus(t, ch2, campaign.Leader)
+	ld, err = svc1.Leader(testElectionID)
+	require.NoError(t, err)
+	require.Equal(t, "i2", ld)
+
+	l, ok := server.lease("m3aggregator-shardset-0-lock-ef3b8314")
+	require.True(t, ok)
+	require.Equal(t, "i2", l.Spec.HolderIdentity)
+	require.Equal(t, int32(1), l.Spec.LeaseDurationSeconds)
+	require.Equal(t, int32(1), l.Spec.LeaseTransitions)
+}
+
+func TestServiceTakesOverExpiredLease(t *testing.T) {
+	server := newFakeLeaseServer()
+	server.store(lease{
+		Metadata: leaseMetadata{Name: "m3aggregator-shardset-0-lock-ef3b8314"},
+		Spec: leaseSpec{
+			HolderIdentity:       "dead",
+			LeaseDurationSeconds: 1,
+		},
+	})
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	var (
+		nowLock sync.Mutex
+		now     = time.Now()
+	)
+	svc := newTestService(t, httpServer.URL, func() time.Time {
+		nowLock.Lock()
+		defer nowLock.Unlock()
+		return now
+	})
+	defer svc.Close()
+
+	ch, err := svc.Campaign(testElectionID, newTestCampaignOptions(t, "i1"))
+	require.NoError(t, err)
+	requireStatus(t, ch, campaign.Follower)
+	select {
+	case status := <-ch:
+		require.FailNow(t, "unexpected campaign status", status.State.String())
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The lease is taken over once it has not been renewed for its duration.
+	nowLock.Lock()
+	now = now.Add(time.Second)
+	nowLock.Unlock()
+	requireStatus(t, ch, campaign.Leader)
+}
+
+func TestServiceLosesLeaseWhenRenewalsFail(t *testing.T) {
+	server := newFakeLeaseServer()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	svc := newTestService(t, httpServer.URL, nil)
+	defer svc.Close()
+
+	ch, err := svc.Campaign(testElectionID, newTestCampaignOptions(t, "i1"))
+	require.NoError(t, err)
+	requireStatus(t, ch, campaign.Follower)
+	requireStatus(t, ch, campaign.Leader)
+
+	server.setFailUpdates(true)
+	status := requireStatus(t, ch, campaign.Error)
+	require.Equal(t, errLeaseLost, status.Err)
+	_, ok := <-ch
+	require.False(t, ok)
+}
+
+func TestServiceLosesLeaseWhenRenewalsHang(t *testing.T) {
+	server := newFakeLeaseServer()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	svc := newTestService(t, httpServer.URL, nil)
+	defer svc.Close()
+
+	ch, err := svc.Campaign(testElectionID, newTestCampaignOptions(t, "i1"))
+	require.NoError(t, err)
+	requireStatus(t, ch, campaign.Follower)
+	requireStatus(t, ch, campaign.Leader)
+
+	// The leader steps down at the renew deadline rather than waiting for the
+	// request timeout of the http client.
+	server.setHangUpdates(true)
+	start := time.Now()
+	status := requireStatus(t, ch, campaign.Error)
+	require.Equal(t, errLeaseLost, status.Err)
+	require.True(t, time.Since(start) < defaultRequestTimeout)
+	_, ok := <-ch
+	require.False(t, ok)
+}
+
+func TestLeaseName(t *testing.T) {
+	require.Equal(t, "m3aggregator-shardset-0-lock-ef3b8314", leaseName("m3aggregator", "shardset/0/lock"))
+	require.Equal(t, "m3aggregator-shardset-0-lock-4c7e7322", leaseName("m3aggregator", "/Shardset/0/lock/"))
+	require.Equal(t, "m3aggregator-a-b", leaseName("m3aggregator", "a-b"))
+	require.Equal(t, "m3aggregator-a-b-1ba46871", leaseName("m3aggregator", "a_b"))
+	require.Equal(t, "m3aggregator-default", leaseName("m3aggregator", ""))
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := NewOptions().
+		SetAPIServerURL("https://kubernetes.default.svc").
+		SetNamespace("m3").
+		SetLeaseNamePrefix("m3aggregator")
+	require.NoError(t, opts.Validate())
+	require.Equal(t, 40*time.Second, opts.RenewDeadline())
+
+	require.Equal(t, errNamespaceNotSet, opts.SetNamespace("").Validate())
+	require.Equal(t, errLeaseNamePrefixNotSet, opts.SetLeaseNamePrefix("").Validate())
+	require.Error(t, opts.SetRenewDeadline(time.Minute).Validate())
+	require.Error(t, opts.SetRenewDeadline(time.Second).SetRetryPeriod(time.Second).Validate())
+}
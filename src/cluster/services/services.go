@@ -777,7 +777,8 @@ func NewServiceInstanceFromProto(
 		SetServiceID(sid).
 		SetInstanceID(instance.Id).
 		SetEndpoint(instance.Endpoint).
-		SetShards(shards), nil
+		SetShards(shards).
+		SetMaintenance(instance.Maintenance), nil
 }
 
 // NewServiceInstanceFromPlacementInstance creates a new service instance from placement instance.
@@ -789,23 +790,27 @@ func NewServiceInstanceFromPlacementInstance(
 		SetServiceID(sid).
 		SetInstanceID(instance.ID()).
 		SetEndpoint(instance.Endpoint()).
-		SetShards(instance.Shards())
+		SetShards(instance.Shards()).
+		SetMaintenance(instance.Maintenance())
 }
 
 type serviceInstance struct {
-	service  ServiceID
-	id       string
-	endpoint string
-	shards   shard.Shards
-}
-
-func (i *serviceInstance) InstanceID() string                       { return i.id }
-func (i *serviceInstance) Endpoint() string                         { return i.endpoint }
-func (i *serviceInstance) Shards() shard.Shards                     { return i.shards }
-func (i *serviceInstance) ServiceID() ServiceID                     { return i.service }
-func (i *serviceInstance) SetInstanceID(id string) ServiceInstance  { i.id = id; return i }
-func (i *serviceInstance) SetEndpoint(e string) ServiceInstance     { i.endpoint = e; return i }
-func (i *serviceInstance) SetShards(s shard.Shards) ServiceInstance { i.shards = s; return i }
+	service     ServiceID
+	id          string
+	endpoint    string
+	shards      shard.Shards
+	maintenance bool
+}
+
+func (i *serviceInstance) InstanceID() string                        { return i.id }
+func (i *serviceInstance) Endpoint() string                          { return i.endpoint }
+func (i *serviceInstance) Shards() shard.Shards                      { return i.shards }
+func (i *serviceInstance) ServiceID() ServiceID                      { return i.service }
+func (i *serviceInstance) Maintenance() bool                         { return i.maintenance }
+func (i *serviceInstance) SetInstanceID(id string) ServiceInstance   { i.id = id; return i }
+func (i *serviceInstance) SetEndpoint(e string) ServiceInstance      { i.endpoint = e; return i }
+func (i *serviceInstance) SetShards(s shard.Shards) ServiceInstance  { i.shards = s; return i }
+func (i *serviceInstance) SetMaintenance(value bool) ServiceInstance { i.maintenance = value; return i }
 
 func (i *serviceInstance) SetServiceID(service ServiceID) ServiceInstance {
 	i.service = service
@@ -908,6 +908,20 @@ func (mr *MockServiceInstanceMockRecorder) InstanceID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceID", reflect.TypeOf((*MockServiceInstance)(nil).InstanceID))
 }
 
+// Maintenance mocks base method.
+func (m *MockServiceInstance) Maintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Maintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Maintenance indicates an expected call of Maintenance.
+func (mr *MockServiceInstanceMockRecorder) Maintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockServiceInstance)(nil).Maintenance))
+}
+
 // ServiceID mocks base method.
 func (m *MockServiceInstance) ServiceID() ServiceID {
 	m.ctrl.T.Helper()
@@ -950,6 +964,20 @@ func (mr *MockServiceInstanceMockRecorder) SetInstanceID(id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceID", reflect.TypeOf((*MockServiceInstance)(nil).SetInstanceID), id)
 }
 
+// SetMaintenance mocks base method.
+func (m *MockServiceInstance) SetMaintenance(value bool) ServiceInstance {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenance", value)
+	ret0, _ := ret[0].(ServiceInstance)
+	return ret0
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance.
+func (mr *MockServiceInstanceMockRecorder) SetMaintenance(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockServiceInstance)(nil).SetMaintenance), value)
+}
+
 // SetServiceID mocks base method.
 func (m *MockServiceInstance) SetServiceID(service ServiceID) ServiceInstance {
 	m.ctrl.T.Helper()
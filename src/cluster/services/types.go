@@ -229,6 +229,12 @@ type ServiceInstance interface {
 
 	// SetShards sets the shards of the instance.
 	SetShards(s shard.Shards) ServiceInstance
+
+	// Maintenance returns whether the instance is in maintenance.
+	Maintenance() bool
+
+	// SetMaintenance sets whether the instance is in maintenance.
+	SetMaintenance(value bool) ServiceInstance
 }
 
 // Advertisement advertises the availability of a given instance of a service.
@@ -21,6 +21,7 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -32,34 +33,63 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/ddsketch"
 	"github.com/m3db/m3/src/aggregator/aggregator"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
 	aggclient "github.com/m3db/m3/src/aggregator/client"
 	aggruntime "github.com/m3db/m3/src/aggregator/runtime"
+	grpcserver "github.com/m3db/m3/src/aggregator/server/grpc"
 	"github.com/m3db/m3/src/aggregator/sharding"
+	"github.com/m3db/m3/src/aggregator/wal"
 	"github.com/m3db/m3/src/cluster/client"
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/services/leader/kubernetes"
 	"github.com/m3db/m3/src/cmd/services/m3aggregator/serve"
 	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/config/hostid"
+	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/retry"
+	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3/src/x/sync"
+
+	"google.golang.org/grpc"
 )
 
 var (
 	errNoKVClientConfiguration = errors.New("no kv client configuration")
 	errEmptyJitterBucketList   = errors.New("empty jitter bucket list")
+
+	errClockSkewClampWithoutThreshold = errors.New(
+		"clock skew clamping requires a positive clock skew threshold")
+)
+
+var (
+	defaultNumPassthroughWriters         = 8
+	defaultPassthroughWritersPerInstance = 2
 )
 
-var defaultNumPassthroughWriters = 8
+const (
+	defaultHeartbeatNameTag            = "__name__"
+	defaultMemoryWatchdogCheckInterval = 10 * time.Second
+	defaultPromotionReplayWindow       = 10 * time.Minute
+	defaultPromotionReplayTimeout      = 30 * time.Second
+	defaultPromotionReplayMaxRecvBytes = 16 * 1024 * 1024
+	defaultVerificationInterval        = time.Minute
+	defaultVerificationLag             = time.Minute
+	defaultVerificationRetention       = 10 * time.Minute
+	defaultVerificationTimeout         = 10 * time.Second
+	defaultVerificationValueTolerance  = 1e-6
+)
 
 // AggregatorConfiguration contains aggregator configuration.
 type AggregatorConfiguration struct {
@@ -88,9 +118,16 @@ type AggregatorConfiguration struct {
 	// Gauge metric prefix.
 	GaugePrefix *string `yaml:"gaugePrefix"`
 
+	// Histogram metric prefix.
+	HistogramPrefix *string `yaml:"histogramPrefix"`
+
 	// Stream configuration for computing quantiles.
 	Stream streamConfiguration `yaml:"stream"`
 
+	// TimerSketch configures computing timer quantiles using a sketch with
+	// bounded memory instead of a stream, if set.
+	TimerSketch *timerSketchConfiguration `yaml:"timerSketch"`
+
 	// Client configuration.
 	Client aggclient.Configuration `yaml:"client"`
 
@@ -109,6 +146,10 @@ type AggregatorConfiguration struct {
 	// Amount of time we buffer timed metrics in the past.
 	BufferDurationForPastTimedMetric time.Duration `yaml:"bufferDurationForPastTimedMetric"`
 
+	// Amount of time we buffer timed metrics in the past for specific storage
+	// policies, overriding BufferDurationForPastTimedMetric.
+	BufferDurationForPastTimedMetricByStoragePolicy []pastTimedMetricBuffer `yaml:"bufferDurationForPastTimedMetricByStoragePolicy"`
+
 	// Amount of time we buffer timed metrics in the future.
 	BufferDurationForFutureTimedMetric time.Duration `yaml:"bufferDurationForFutureTimedMetric"`
 
@@ -159,6 +200,34 @@ type AggregatorConfiguration struct {
 	// Whether to discard NaN aggregated values.
 	DiscardNaNAggregatedValues *bool `yaml:"discardNaNAggregatedValues"`
 
+	// Maximum number of annotation bytes retained by the open aggregations of
+	// each shard, annotations beyond it are dropped. Unlimited if not set.
+	MaxAnnotationBytesPerShard int64 `yaml:"maxAnnotationBytesPerShard" validate:"min=0"`
+
+	// AnnotationSizeLimit configures the max size of the annotation of each
+	// metric ingested and how oversized annotations are handled.
+	AnnotationSizeLimit *annotationSizeLimitConfiguration `yaml:"annotationSizeLimit"`
+
+	// Maximum number of exemplars retained by each aggregated value and
+	// forwarded alongside it. Exemplars are dropped if not set.
+	MaxExemplarsPerAggregation int `yaml:"maxExemplarsPerAggregation" validate:"min=0"`
+
+	// Whether to emit a series alongside rollup outputs estimating the number of
+	// distinct values of the contributors tag of the series rolled up into each
+	// aggregation window.
+	EmitContributors bool `yaml:"emitContributors"`
+
+	// Tag whose distinct values are counted by the contributors series,
+	// defaults to "host".
+	ContributorsTag *string `yaml:"contributorsTag"`
+
+	// Suffix of the contributors series, defaults to "_contributors".
+	ContributorsSuffix *string `yaml:"contributorsSuffix"`
+
+	// Suffix of the histogram bucket series followed by the bucket upper bound,
+	// defaults to ".bucket.".
+	HistogramBucketSuffix *string `yaml:"histogramBucketSuffix"`
+
 	// Pool of counter elements.
 	CounterElemPool pool.ObjectPoolConfiguration `yaml:"counterElemPool"`
 
@@ -168,6 +237,9 @@ type AggregatorConfiguration struct {
 	// Pool of gauge elements.
 	GaugeElemPool pool.ObjectPoolConfiguration `yaml:"gaugeElemPool"`
 
+	// Pool of histogram elements.
+	HistogramElemPool pool.ObjectPoolConfiguration `yaml:"histogramElemPool"`
+
 	// Pool of entries.
 	EntryPool pool.ObjectPoolConfiguration `yaml:"entryPool"`
 
@@ -188,6 +260,42 @@ type AggregatorConfiguration struct {
 	// for pipelines that support resending aggregate values. The regexps are matched against the rollup IDs
 	// to allow for incremental transition of existing rules to this new behavior.
 	TimedForResendEnabledRollupRegexps []string `yaml:"timedForResendEnabledRollupRegexps"`
+
+	// Heartbeat configures the heartbeat series each aggregator instance emits
+	// about its own health into the flush pipeline.
+	Heartbeat *heartbeatConfiguration `yaml:"heartbeat"`
+
+	// ResolutionDowngrade configures the emergency mode coarsening the output
+	// resolution of selected storage policies when the aggregator is under load.
+	ResolutionDowngrade *resolutionDowngradeConfiguration `yaml:"resolutionDowngrade"`
+
+	// WAL configures the write-ahead log the incoming metrics are journaled to
+	// so that in flight aggregations are recovered after a restart.
+	WAL *walConfiguration `yaml:"wal"`
+
+	// PromotionReplay configures replaying the recent traffic of the shards
+	// from the write-ahead logs of the peers of a follower before it is promoted
+	// to leader, so that its first flushes as leader are not partial.
+	PromotionReplay *promotionReplayConfiguration `yaml:"promotionReplay"`
+
+	// Verification configures the verification mode, in which the leader
+	// periodically compares digests of its aggregation windows with the ones
+	// computed by its followers to detect them diverging from it.
+	Verification *verificationConfiguration `yaml:"verification"`
+
+	// RollupCardinalityLimits configures caps on the number of distinct series
+	// each rollup rule produces, to protect against rules whose cardinality explodes.
+	RollupCardinalityLimits *rollupCardinalityLimitsConfiguration `yaml:"rollupCardinalityLimits"`
+
+	// LoadShedding configures rejecting the writes of the lowest priority traffic
+	// while the ticks expiring entries persistently take longer than the entry
+	// check interval, instead of falling further behind.
+	LoadShedding *loadSheddingConfiguration `yaml:"loadShedding"`
+
+	// FlushOffsetTuning configures tuning the buffer for past timed metrics,
+	// and hence how long after the end of a window it is flushed, to the
+	// observed lateness of timed metrics.
+	FlushOffsetTuning *flushOffsetTuningConfiguration `yaml:"flushOffsetTuning"`
 }
 
 // InstanceIDType is the instance ID type that defines how the
@@ -294,19 +402,29 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	opts = setMetricPrefix(opts, c.CounterPrefix, opts.SetCounterPrefix)
 	opts = setMetricPrefix(opts, c.TimerPrefix, opts.SetTimerPrefix)
 	opts = setMetricPrefix(opts, c.GaugePrefix, opts.SetGaugePrefix)
+	opts = setMetricPrefix(opts, c.HistogramPrefix, opts.SetHistogramPrefix)
 
 	// Set stream options.
 	scope := instrumentOpts.MetricsScope()
-	iOpts := instrumentOpts.SetMetricsScope(scope.SubScope("stream"))
+	iOpts := componentInstrumentOptions(instrumentOpts, "stream")
 	streamOpts, err := c.Stream.NewStreamOptions(iOpts)
 	if err != nil {
 		return nil, err
 	}
 	opts = opts.SetStreamOptions(streamOpts)
 
+	// Set timer sketch options.
+	if c.TimerSketch != nil {
+		sketchOpts, err := c.TimerSketch.NewSketchOptions()
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetTimerSketchOptions(sketchOpts)
+	}
+
 	// Set administrative client.
 	// TODO(xichen): client retry threshold likely needs to be low for faster retries.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("client"))
+	iOpts = componentInstrumentOptions(instrumentOpts, "client")
 	adminClient, err := c.Client.NewAdminClient(
 		client, clock.NewOptions(), iOpts, rwOpts)
 	if err != nil {
@@ -324,7 +442,7 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	}
 
 	// Set placement manager.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("placement-manager"))
+	iOpts = componentInstrumentOptions(instrumentOpts, "placement-manager")
 	placementManager, err := c.PlacementManager.NewPlacementManager(client, instanceID, iOpts)
 	if err != nil {
 		return nil, err
@@ -353,6 +471,20 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		opts = opts.SetBufferForPastTimedMetric(c.BufferDurationForPastTimedMetric).
 			SetBufferForPastTimedMetricFn(bufferForPastTimedMetricFn(c.BufferDurationForPastTimedMetric))
 	}
+	maxBufferForPastTimedMetric := opts.BufferForPastTimedMetric()
+	if len(c.BufferDurationForPastTimedMetricByStoragePolicy) > 0 {
+		buffers := pastTimedMetricBuffers(c.BufferDurationForPastTimedMetricByStoragePolicy)
+		bufferFn, maxBuffer, err := buffers.NewBufferForPastTimedMetricFn(opts.BufferForPastTimedMetric())
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetBufferForPastTimedMetricFn(bufferFn)
+		maxBufferForPastTimedMetric = maxBuffer
+	}
+	if c.FlushOffsetTuning != nil && c.FlushOffsetTuning.MaxBuffer > maxBufferForPastTimedMetric {
+		// Followers wait for the largest buffer the tuning can set.
+		maxBufferForPastTimedMetric = c.FlushOffsetTuning.MaxBuffer
+	}
 	if c.BufferDurationForFutureTimedMetric != 0 {
 		opts = opts.SetBufferForFutureTimedMetric(c.BufferDurationForFutureTimedMetric)
 	}
@@ -363,7 +495,7 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	}
 
 	// Set flush times manager.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("flush-times-manager"))
+	iOpts = componentInstrumentOptions(instrumentOpts, "flush-times-manager")
 	flushTimesManager, err := c.FlushTimesManager.NewFlushTimesManager(client, iOpts)
 	if err != nil {
 		return nil, err
@@ -371,7 +503,7 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	opts = opts.SetFlushTimesManager(flushTimesManager)
 
 	// Set election manager.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("election-manager"))
+	iOpts = componentInstrumentOptions(instrumentOpts, "election-manager")
 	placementNamespace := c.PlacementManager.KVConfig.Namespace
 	electionManager, err := c.ElectionManager.NewElectionManager(
 		client,
@@ -388,23 +520,31 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	opts = opts.SetElectionManager(electionManager)
 
 	// Set flush manager.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("flush-manager"))
+	iOpts = componentInstrumentOptions(instrumentOpts, "flush-manager")
 	flushManagerOpts, err := c.FlushManager.NewFlushManagerOptions(
 		placementManager,
 		electionManager,
 		flushTimesManager,
 		iOpts,
-		opts.BufferForPastTimedMetric(),
+		maxBufferForPastTimedMetric,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if c.FlushManager.ClockSkewClampEnabled {
+		if c.FlushManager.ClockSkewThreshold <= 0 {
+			return nil, errClockSkewClampWithoutThreshold
+		}
+		watermark := aggregator.NewClockSkewWatermark()
+		flushManagerOpts = flushManagerOpts.SetClockSkewWatermark(watermark)
+		opts = opts.SetClockSkewWatermark(watermark)
+	}
 	flushManager := aggregator.NewFlushManager(flushManagerOpts)
 	opts = opts.SetFlushManager(flushManager)
 
 	// Set flushing handler.
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("flush-handler"))
-	flushHandler, err := c.Flush.NewHandler(client, iOpts, rwOpts)
+	iOpts = componentInstrumentOptions(instrumentOpts, "flush-handler")
+	flushHandler, err := c.Flush.NewHandler(client, iOpts, rwOpts, serveOpts.KafkaProducerFn())
 	if err != nil {
 		return nil, err
 	}
@@ -415,8 +555,8 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	if err != nil {
 		return nil, err
 	}
-	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("passthrough-writer"))
-	passthroughWriter, err := c.newPassthroughWriter(flushHandler, iOpts, aggShardFn)
+	iOpts = componentInstrumentOptions(instrumentOpts, "passthrough-writer")
+	passthroughWriter, err := c.newPassthroughWriter(client, flushHandler, iOpts, aggShardFn)
 	if err != nil {
 		return nil, err
 	}
@@ -426,7 +566,8 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 	jitterEnabled := flushManagerOpts.JitterEnabled()
 	maxJitterFn := flushManagerOpts.MaxJitterFn()
 	maxAllowedForwardingDelayFn := c.Forwarding.MaxAllowedForwardingDelayFn(jitterEnabled, maxJitterFn)
-	opts = opts.SetMaxAllowedForwardingDelayFn(maxAllowedForwardingDelayFn)
+	opts = opts.SetMaxAllowedForwardingDelayFn(maxAllowedForwardingDelayFn).
+		SetMaxNumForwardedTimes(c.Forwarding.MaxNumForwardedTimes)
 
 	// Set entry options.
 	if c.EntryTTL != 0 {
@@ -457,6 +598,30 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		opts = opts.SetDiscardNaNAggregatedValues(*c.DiscardNaNAggregatedValues)
 	}
 
+	// Set the annotation memory cap.
+	if c.MaxAnnotationBytesPerShard != 0 {
+		opts = opts.SetMaxAnnotationBytesPerShard(c.MaxAnnotationBytesPerShard)
+	}
+
+	// Set the exemplar limit, this must happen before the element pools are
+	// initialized since elements capture the options on creation.
+	opts = opts.SetMaxExemplarsPerAggregation(c.MaxExemplarsPerAggregation)
+
+	// Set contributors options, this must happen before the element pools
+	// are initialized since elements capture the options on creation.
+	opts = opts.SetEmitContributors(c.EmitContributors)
+	if c.ContributorsTag != nil {
+		opts = opts.SetContributorsTag([]byte(*c.ContributorsTag))
+	}
+	if c.ContributorsSuffix != nil {
+		opts = opts.SetContributorsSuffix([]byte(*c.ContributorsSuffix))
+	}
+
+	// Set the histogram bucket suffix, also captured by elements on creation.
+	if c.HistogramBucketSuffix != nil {
+		opts = opts.SetHistogramBucketSuffix([]byte(*c.HistogramBucketSuffix))
+	}
+
 	// Set counter elem pool.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("counter-elem-pool"))
 	counterElemPoolOpts := c.CounterElemPool.NewObjectPoolOptions(iOpts)
@@ -484,6 +649,15 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		return aggregator.MustNewGaugeElem(aggregator.ElemData{}, opts)
 	})
 
+	// Set histogram elem pool.
+	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("histogram-elem-pool"))
+	histogramElemPoolOpts := c.HistogramElemPool.NewObjectPoolOptions(iOpts)
+	histogramElemPool := aggregator.NewHistogramElemPool(histogramElemPoolOpts)
+	opts = opts.SetHistogramElemPool(histogramElemPool)
+	histogramElemPool.Init(func() *aggregator.HistogramElem {
+		return aggregator.MustNewHistogramElem(aggregator.ElemData{}, opts)
+	})
+
 	// Set entry pool.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("entry-pool"))
 	entryPoolOpts := c.EntryPool.NewObjectPoolOptions(iOpts)
@@ -500,6 +674,67 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		SetWritesIgnoreCutoffCutover(c.WritesIgnoreCutoffCutover).
 		SetTimedForResendEnabledRollupRegexps(c.TimedForResendEnabledRollupRegexps)
 
+	if c.Heartbeat != nil && c.Heartbeat.Enabled {
+		opts = c.Heartbeat.apply(opts)
+	}
+
+	if c.ResolutionDowngrade != nil {
+		opts, err = c.ResolutionDowngrade.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.WAL != nil {
+		w, err := c.WAL.newWAL(clockOpts, instrumentOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetWAL(w)
+	}
+
+	if c.PromotionReplay != nil {
+		opts, err = c.PromotionReplay.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Verification != nil {
+		opts, err = c.Verification.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.RollupCardinalityLimits != nil {
+		opts, err = c.RollupCardinalityLimits.apply(opts, instrumentOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.LoadShedding != nil {
+		opts, err = c.LoadShedding.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.AnnotationSizeLimit != nil {
+		opts, err = c.AnnotationSizeLimit.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.FlushOffsetTuning != nil {
+		opts, err = c.FlushOffsetTuning.apply(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return opts, nil
 }
 
@@ -538,6 +773,51 @@ func bufferForPastTimedMetricFn(buffer time.Duration) aggregator.BufferForPastTi
 	}
 }
 
+// pastTimedMetricBuffer determines the amount of time timed metrics in the
+// past are buffered for a storage policy.
+type pastTimedMetricBuffer struct {
+	StoragePolicy policy.StoragePolicy `yaml:"storagePolicy"`
+	Buffer        time.Duration        `yaml:"buffer" validate:"min=0"`
+}
+
+type pastTimedMetricBuffers []pastTimedMetricBuffer
+
+// NewBufferForPastTimedMetricFn returns a function that buffers timed metrics in
+// the past for the configured storage policies, or for the default buffer for
+// other storage policies, along with the largest of the buffers. Timed metrics
+// are aggregated by resolution, so the buffer of a storage policy applies to
+// all the storage policies with the same resolution.
+func (buffers pastTimedMetricBuffers) NewBufferForPastTimedMetricFn(
+	defaultBuffer time.Duration,
+) (aggregator.BufferForPastTimedMetricFn, time.Duration, error) {
+	var (
+		byResolution = make(map[time.Duration]time.Duration, len(buffers))
+		maxBuffer    = defaultBuffer
+	)
+	for _, b := range buffers {
+		resolution := b.StoragePolicy.Resolution().Window
+		if resolution <= 0 {
+			return nil, 0, fmt.Errorf(
+				"invalid storage policy %s for past timed metric buffer", b.StoragePolicy)
+		}
+		if existing, ok := byResolution[resolution]; ok && existing != b.Buffer {
+			return nil, 0, fmt.Errorf(
+				"conflicting past timed metric buffers %s and %s for resolution %s",
+				existing, b.Buffer, resolution)
+		}
+		byResolution[resolution] = b.Buffer
+		if b.Buffer > maxBuffer {
+			maxBuffer = b.Buffer
+		}
+	}
+	return func(resolution time.Duration) time.Duration {
+		if buffer, ok := byResolution[resolution]; ok {
+			return buffer + resolution
+		}
+		return defaultBuffer + resolution
+	}, maxBuffer, nil
+}
+
 // streamConfiguration contains configuration for quantile-related metric streams.
 type streamConfiguration struct {
 	// Error epsilon for quantile computation.
@@ -577,6 +857,31 @@ func (c *streamConfiguration) NewStreamOptions(_ instrument.Options) (cm.Options
 	return opts, nil
 }
 
+// timerSketchConfiguration contains configuration for sketches used to
+// compute timer quantiles.
+type timerSketchConfiguration struct {
+	// Relative accuracy of the estimated quantiles.
+	RelativeAccuracy float64 `yaml:"relativeAccuracy"`
+
+	// Maximum number of bins for positive and negative values each.
+	MaxNumBins int `yaml:"maxNumBins"`
+}
+
+func (c *timerSketchConfiguration) NewSketchOptions() (ddsketch.Options, error) {
+	opts := ddsketch.NewOptions()
+	if c.RelativeAccuracy != 0 {
+		opts = opts.SetRelativeAccuracy(c.RelativeAccuracy)
+	}
+	if c.MaxNumBins != 0 {
+		opts = opts.SetMaxNumBins(c.MaxNumBins)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
 type placementManagerConfiguration struct {
 	KVConfig kv.OverrideConfiguration       `yaml:"kvConfig"`
 	Watcher  placement.WatcherConfiguration `yaml:"placementWatcher"`
@@ -610,6 +915,9 @@ type forwardingConfiguration struct {
 	MaxSingleDelay time.Duration `yaml:"maxSingleDelay"`
 	// MaxConstDelay is the maximum delay for a forward step as a constant + resolution*numForwardedTimes.
 	MaxConstDelay time.Duration `yaml:"maxConstDelay"`
+	// MaxNumForwardedTimes is the maximum number of times a metric may have been
+	// forwarded before it is rejected, zero means no limit.
+	MaxNumForwardedTimes int `yaml:"maxNumForwardedTimes"`
 }
 
 func (c forwardingConfiguration) MaxAllowedForwardingDelayFn(
@@ -643,6 +951,15 @@ type flushTimesManagerConfiguration struct {
 
 	// Retrier for persisting flush times.
 	FlushTimesPersistRetrier retry.Configuration `yaml:"flushTimesPersistRetrier"`
+
+	// MinPersistInterval is the minimum interval between persisting flush times,
+	// with updates in between batched so only the latest flush times are persisted.
+	MinPersistInterval time.Duration `yaml:"minPersistInterval"`
+
+	// CompactEvery is the number of delta-encoded flush times persisted before
+	// the flush times are compacted, delta encoding is disabled if zero. This
+	// only affects persisting flush times, deltas are always applied on read.
+	CompactEvery int `yaml:"compactEvery" validate:"min=0"`
 }
 
 func (c flushTimesManagerConfiguration) NewFlushTimesManager(
@@ -663,10 +980,21 @@ func (c flushTimesManagerConfiguration) NewFlushTimesManager(
 		SetInstrumentOptions(instrumentOpts).
 		SetFlushTimesKeyFmt(c.FlushTimesKeyFmt).
 		SetFlushTimesStore(store).
-		SetFlushTimesPersistRetrier(retrier)
+		SetFlushTimesPersistRetrier(retrier).
+		SetFlushTimesMinPersistInterval(c.MinPersistInterval).
+		SetFlushTimesCompactEvery(c.CompactEvery)
 	return aggregator.NewFlushTimesManager(flushTimesManagerOpts), nil
 }
 
+// componentInstrumentOptions returns the instrument options of a component with
+// the metrics scope and the logger named after the component, so that the log
+// level of the component can be overridden.
+func componentInstrumentOptions(instrumentOpts instrument.Options, name string) instrument.Options {
+	return instrumentOpts.
+		SetMetricsScope(instrumentOpts.MetricsScope().SubScope(name)).
+		SetLogger(instrumentOpts.Logger().Named(name))
+}
+
 type electionManagerConfiguration struct {
 	Election                   electionConfiguration  `yaml:"election"`
 	ServiceID                  serviceIDConfiguration `yaml:"serviceID"`
@@ -677,6 +1005,69 @@ type electionManagerConfiguration struct {
 	ResignRetrier              retry.Configuration    `yaml:"resignRetrier"`
 	CampaignStateCheckInterval time.Duration          `yaml:"campaignStateCheckInterval"`
 	ShardCutoffCheckOffset     time.Duration          `yaml:"shardCutoffCheckOffset"`
+
+	// CampaignDamping stops the instance from campaigning for a while once its
+	// leadership has flapped too often.
+	CampaignDamping *campaignDampingConfiguration `yaml:"campaignDamping"`
+
+	// Kubernetes campaigns by holding Kubernetes leases instead of campaigning
+	// in etcd when set.
+	Kubernetes *kubernetesElectionConfiguration `yaml:"kubernetes"`
+}
+
+// kubernetesElectionConfiguration campaigns by holding a lease of the
+// coordination/v1 Lease API per shard set, reaching the API server with the
+// service account of the pod the aggregator runs in.
+type kubernetesElectionConfiguration struct {
+	// Namespace is the namespace the leases are created in, defaults to the
+	// namespace of the pod.
+	Namespace string `yaml:"namespace"`
+
+	// LeaseNamePrefix is the prefix of the lease names, which must be unique
+	// to the aggregator cluster within the namespace.
+	LeaseNamePrefix string `yaml:"leaseNamePrefix" validate:"nonzero"`
+
+	// RetryPeriod is how often the leader renews its leases and the other
+	// instances attempt to acquire them.
+	RetryPeriod time.Duration `yaml:"retryPeriod"`
+
+	// RenewDeadline is how long the leader keeps trying to renew its lease
+	// before giving up leadership.
+	RenewDeadline time.Duration `yaml:"renewDeadline"`
+}
+
+func (c kubernetesElectionConfiguration) NewLeaderService(
+	electionOpts services.ElectionOptions,
+	clockOpts clock.Options,
+	instrumentOpts instrument.Options,
+) (services.LeaderService, error) {
+	opts, err := kubernetes.NewInClusterOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.
+		SetLeaseNamePrefix(c.LeaseNamePrefix).
+		SetElectionOpts(electionOpts).
+		SetClockOptions(clockOpts).
+		SetInstrumentOptions(instrumentOpts)
+	if c.Namespace != "" {
+		opts = opts.SetNamespace(c.Namespace)
+	}
+	if c.RetryPeriod != 0 {
+		opts = opts.SetRetryPeriod(c.RetryPeriod)
+	}
+	if c.RenewDeadline != 0 {
+		opts = opts.SetRenewDeadline(c.RenewDeadline)
+	}
+	return kubernetes.NewService(opts)
+}
+
+// campaignDampingConfiguration suppresses campaigning for the backoff duration
+// once leadership has been gained or lost maxFlaps times within the window.
+type campaignDampingConfiguration struct {
+	Window   time.Duration `yaml:"window" validate:"nonzero"`
+	MaxFlaps int           `yaml:"maxFlaps" validate:"nonzero"`
+	Backoff  time.Duration `yaml:"backoff" validate:"nonzero"`
 }
 
 func (c electionManagerConfiguration) NewElectionManager(
@@ -692,14 +1083,8 @@ func (c electionManagerConfiguration) NewElectionManager(
 	if err != nil {
 		return nil, err
 	}
-	serviceID := c.ServiceID.NewServiceID()
-	namespaceOpts := services.NewNamespaceOptions().SetPlacementNamespace(placementNamespace)
-	serviceOpts := services.NewOverrideOptions().SetNamespaceOptions(namespaceOpts)
-	svcs, err := client.Services(serviceOpts)
-	if err != nil {
-		return nil, err
-	}
-	leaderService, err := svcs.LeaderService(serviceID, electionOpts)
+	leaderService, err := c.newLeaderService(client, placementNamespace, electionOpts,
+		clockOpts, instrumentOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -734,10 +1119,42 @@ func (c electionManagerConfiguration) NewElectionManager(
 	if c.ShardCutoffCheckOffset != 0 {
 		opts = opts.SetShardCutoffCheckOffset(c.ShardCutoffCheckOffset)
 	}
+	if c.CampaignDamping != nil {
+		dampingOpts := aggregator.CampaignDampingOptions{
+			Window:   c.CampaignDamping.Window,
+			MaxFlaps: c.CampaignDamping.MaxFlaps,
+			Backoff:  c.CampaignDamping.Backoff,
+		}
+		if err := dampingOpts.Validate(); err != nil {
+			return nil, err
+		}
+		opts = opts.SetCampaignDampingOptions(dampingOpts)
+	}
 	electionManager := aggregator.NewElectionManager(opts)
 	return electionManager, nil
 }
 
+func (c electionManagerConfiguration) newLeaderService(
+	client client.Client,
+	placementNamespace string,
+	electionOpts services.ElectionOptions,
+	clockOpts clock.Options,
+	instrumentOpts instrument.Options,
+) (services.LeaderService, error) {
+	if c.Kubernetes != nil {
+		return c.Kubernetes.NewLeaderService(electionOpts, clockOpts, instrumentOpts)
+	}
+
+	serviceID := c.ServiceID.NewServiceID()
+	namespaceOpts := services.NewNamespaceOptions().SetPlacementNamespace(placementNamespace)
+	serviceOpts := services.NewOverrideOptions().SetNamespaceOptions(namespaceOpts)
+	svcs, err := client.Services(serviceOpts)
+	if err != nil {
+		return nil, err
+	}
+	return svcs.LeaderService(serviceID, electionOpts)
+}
+
 type electionConfiguration struct {
 	LeaderTimeout time.Duration `yaml:"leaderTimeout"`
 	ResignTimeout time.Duration `yaml:"resignTimeout"`
@@ -800,6 +1217,23 @@ type flushManagerConfiguration struct {
 
 	// Window size for a forced flush.
 	ForcedFlushWindowSize time.Duration `yaml:"forcedFlushWindowSize"`
+
+	// How far the clock of the leader, as estimated from the flush times it
+	// persisted, may be ahead of or behind the local clock before a follower
+	// reports clock skew, zero disables the detection.
+	ClockSkewThreshold time.Duration `yaml:"clockSkewThreshold"`
+
+	// Whether followers clamp the aggregation windows of untimed metrics to the
+	// flush times persisted by the leader when its clock is detected to be ahead.
+	ClockSkewClampEnabled bool `yaml:"clockSkewClampEnabled"`
+
+	// Maximum number of shards flushed concurrently by the leader, zero means
+	// no limit other than the number of workers.
+	MaxConcurrentShardFlushes int `yaml:"maxConcurrentShardFlushes" validate:"min=0"`
+
+	// Fraction of the flush interval over which the leader spreads the start of
+	// the flushes of the shards, zero disables pacing.
+	ShardFlushPacing float64 `yaml:"shardFlushPacing" validate:"min=0.0,max=1.0"`
 }
 
 func (c flushManagerConfiguration) NewFlushManagerOptions(
@@ -848,6 +1282,15 @@ func (c flushManagerConfiguration) NewFlushManagerOptions(
 	if c.ForcedFlushWindowSize != 0 {
 		opts = opts.SetForcedFlushWindowSize(c.ForcedFlushWindowSize)
 	}
+	if c.ClockSkewThreshold != 0 {
+		opts = opts.SetClockSkewThreshold(c.ClockSkewThreshold)
+	}
+	if c.MaxConcurrentShardFlushes != 0 {
+		opts = opts.SetMaxConcurrentShardFlushes(c.MaxConcurrentShardFlushes)
+	}
+	if c.ShardFlushPacing != 0 {
+		opts = opts.SetShardFlushPacing(c.ShardFlushPacing)
+	}
 	return opts, nil
 }
 
@@ -909,9 +1352,63 @@ type passthroughConfiguration struct {
 
 	// NumWriters controls the number of passthrough writers used.
 	NumWriters int `yaml:"numWriters"`
+
+	// Rebalance assigns the shards of the placement of a downstream consumer
+	// service to passthrough writers of the instances owning them, and moves
+	// the shards whose owner changed on placement updates, NumWriters is
+	// ignored if set.
+	Rebalance *passthroughRebalanceConfiguration `yaml:"rebalance"`
+}
+
+// passthroughRebalanceConfiguration contains the knobs for rebalancing the
+// passthrough writers with the shards of a downstream consumer service.
+type passthroughRebalanceConfiguration struct {
+	// ConsumerService is the downstream consumer service whose placement is watched.
+	ConsumerService serviceIDConfiguration `yaml:"consumerService"`
+
+	// PlacementServiceOverride overrides the placement service of the consumer service.
+	PlacementServiceOverride services.OverrideConfiguration `yaml:"placementServiceOverride"`
+
+	// WritersPerInstance is the number of passthrough writers per consumer instance.
+	WritersPerInstance int `yaml:"writersPerInstance" validate:"min=0"`
+
+	// PlacementWatchInitTimeout is the timeout to wait for the initial placement.
+	PlacementWatchInitTimeout time.Duration `yaml:"placementWatchInitTimeout"`
+}
+
+func (c *passthroughRebalanceConfiguration) newWriter(
+	client client.Client,
+	flushHandler handler.Handler,
+	iOpts instrument.Options,
+	shardFn sharding.AggregatedShardFn,
+) (writer.Writer, error) {
+	svcs, err := client.Services(c.PlacementServiceOverride.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+	ps, err := svcs.PlacementService(c.ConsumerService.NewServiceID(), placement.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	writersPerInstance := defaultPassthroughWritersPerInstance
+	if c.WritersPerInstance != 0 {
+		writersPerInstance = c.WritersPerInstance
+	}
+	return writer.NewPlacementShardedWriter(writer.PlacementShardedWriterOptions{
+		PlacementService:   ps,
+		WritersPerInstance: writersPerInstance,
+		InitWatchTimeout:   c.PlacementWatchInitTimeout,
+		NewWriterFn: func() (writer.Writer, error) {
+			return flushHandler.NewWriter(iOpts.MetricsScope())
+		},
+		ShardFn:           shardFn,
+		InstrumentOptions: iOpts,
+	})
 }
 
 func (c *AggregatorConfiguration) newPassthroughWriter(
+	client client.Client,
 	flushHandler handler.Handler,
 	iOpts instrument.Options,
 	shardFn sharding.AggregatedShardFn,
@@ -922,6 +1419,10 @@ func (c *AggregatorConfiguration) newPassthroughWriter(
 		return writer.NewBlackholeWriter(), nil
 	}
 
+	if c.Passthrough.Rebalance != nil {
+		return c.Passthrough.Rebalance.newWriter(client, flushHandler, iOpts, shardFn)
+	}
+
 	count := defaultNumPassthroughWriters
 	if c.Passthrough.NumWriters != 0 {
 		count = c.Passthrough.NumWriters
@@ -938,3 +1439,394 @@ func (c *AggregatorConfiguration) newPassthroughWriter(
 
 	return writer.NewShardedWriter(writers, shardFn, iOpts)
 }
+
+// heartbeatConfiguration contains the knobs for the heartbeat series emitted by
+// each aggregator instance.
+type heartbeatConfiguration struct {
+	// Enabled controls whether heartbeat series are emitted.
+	Enabled bool `yaml:"enabled"`
+
+	// StoragePolicy is the storage policy of the heartbeat series, heartbeats are
+	// emitted once per resolution of the storage policy.
+	StoragePolicy *policy.StoragePolicy `yaml:"storagePolicy"`
+
+	// MetricNamePrefix is the prefix of the heartbeat metric names.
+	MetricNamePrefix string `yaml:"metricNamePrefix"`
+
+	// NameTag is the tag the metric name is encoded as in the heartbeat metric IDs.
+	NameTag string `yaml:"nameTag"`
+}
+
+func (c heartbeatConfiguration) apply(opts aggregator.Options) aggregator.Options {
+	opts = opts.SetHeartbeatEnabled(true)
+	if c.StoragePolicy != nil {
+		opts = opts.SetHeartbeatStoragePolicy(*c.StoragePolicy)
+	}
+	if c.MetricNamePrefix != "" {
+		opts = opts.SetHeartbeatMetricNamePrefix(c.MetricNamePrefix)
+	}
+
+	nameTag := defaultHeartbeatNameTag
+	if c.NameTag != "" {
+		nameTag = c.NameTag
+	}
+	return opts.SetHeartbeatNewIDFn(newHeartbeatIDFn([]byte(nameTag)))
+}
+
+// resolutionDowngradeConfiguration contains the knobs for the emergency resolution
+// downgrade mode, which is enabled manually through the admin API or by the memory
+// watchdog.
+type resolutionDowngradeConfiguration struct {
+	// Policies are the storage policies whose output resolution is coarsened
+	// while the mode is enabled.
+	Policies []resolutionDowngradePolicyConfiguration `yaml:"policies" validate:"nonzero"`
+
+	// MaxOpenWindows is the maximum number of coarse windows each shard holds
+	// open per resolution, datapoints of other series are emitted at their
+	// original resolution. Defaults to 100000.
+	MaxOpenWindows int `yaml:"maxOpenWindows"`
+
+	// MemoryWatchdog enables the mode when the heap in use crosses a high watermark.
+	MemoryWatchdog *memoryWatchdogConfiguration `yaml:"memoryWatchdog"`
+}
+
+// resolutionDowngradePolicyConfiguration coarsens the output resolution of a
+// storage policy, e.g. emitting metrics with a 10s:2d storage policy every minute.
+type resolutionDowngradePolicyConfiguration struct {
+	StoragePolicy policy.StoragePolicy `yaml:"storagePolicy" validate:"nonzero"`
+	Resolution    time.Duration        `yaml:"resolution" validate:"nonzero"`
+}
+
+// memoryWatchdogConfiguration contains the memory watchdog watermarks. The mode
+// is enabled once the heap in use reaches the high watermark and disabled once it
+// drops below the low watermark.
+type memoryWatchdogConfiguration struct {
+	CheckInterval      time.Duration `yaml:"checkInterval"`
+	HighWatermarkBytes uint64        `yaml:"highWatermarkBytes" validate:"nonzero"`
+	LowWatermarkBytes  uint64        `yaml:"lowWatermarkBytes" validate:"nonzero"`
+}
+
+func (c resolutionDowngradeConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	policies := make([]aggregator.ResolutionDowngradePolicy, 0, len(c.Policies))
+	for _, p := range c.Policies {
+		policies = append(policies, aggregator.ResolutionDowngradePolicy{
+			StoragePolicy: p.StoragePolicy,
+			Resolution:    p.Resolution,
+		})
+	}
+	downgrader, err := aggregator.NewResolutionDowngrader(policies, c.MaxOpenWindows)
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.SetResolutionDowngrader(downgrader)
+
+	if c.MemoryWatchdog == nil {
+		return opts, nil
+	}
+	checkInterval := defaultMemoryWatchdogCheckInterval
+	if c.MemoryWatchdog.CheckInterval > 0 {
+		checkInterval = c.MemoryWatchdog.CheckInterval
+	}
+	watchdogOpts := aggregator.MemoryWatchdogOptions{
+		CheckInterval:      checkInterval,
+		HighWatermarkBytes: c.MemoryWatchdog.HighWatermarkBytes,
+		LowWatermarkBytes:  c.MemoryWatchdog.LowWatermarkBytes,
+	}
+	if err := watchdogOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetMemoryWatchdogOptions(watchdogOpts), nil
+}
+
+// walConfiguration contains the knobs for the aggregator write-ahead log.
+type walConfiguration struct {
+	// Dir is the directory the write-ahead log segments are written to.
+	Dir string `yaml:"dir" validate:"nonzero"`
+
+	// SegmentDuration is the duration of metrics written to each segment.
+	SegmentDuration time.Duration `yaml:"segmentDuration"`
+
+	// Retention is how long segments are retained after they are rotated, which
+	// should cover the longest resolution plus buffer of the storage policies.
+	Retention time.Duration `yaml:"retention"`
+
+	// FlushInterval is how often buffered writes are flushed to the segments.
+	FlushInterval time.Duration `yaml:"flushInterval"`
+}
+
+func (c walConfiguration) newWAL(
+	clockOpts clock.Options,
+	instrumentOpts instrument.Options,
+) (wal.WAL, error) {
+	opts := wal.NewOptions().
+		SetDir(c.Dir).
+		SetClockOptions(clockOpts).
+		SetInstrumentOptions(instrumentOpts)
+	if c.SegmentDuration > 0 {
+		opts = opts.SetSegmentDuration(c.SegmentDuration)
+	}
+	if c.Retention > 0 {
+		opts = opts.SetRetention(c.Retention)
+	}
+	if c.FlushInterval > 0 {
+		opts = opts.SetFlushInterval(c.FlushInterval)
+	}
+	return wal.NewWAL(opts)
+}
+
+type promotionReplayConfiguration struct {
+	// Peers are the addresses of the admin gRPC services of the other instances
+	// of the shard set, which must journal their traffic to a write-ahead log.
+	Peers []string `yaml:"peers" validate:"nonzero"`
+
+	// Window is how far back from the time a shard was opened its traffic is
+	// replayed, which should cover the longest resolution of the storage policies.
+	Window time.Duration `yaml:"window"`
+
+	// Timeout bounds how long the promotion is delayed by the replay.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// MaxReceiveMessageSize is the max size in bytes of each chunk of the
+	// traffic of a shard streamed from a peer.
+	MaxReceiveMessageSize int `yaml:"maxReceiveMessageSize"`
+}
+
+func (c promotionReplayConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	var (
+		window         = defaultPromotionReplayWindow
+		timeout        = defaultPromotionReplayTimeout
+		maxRecvMsgSize = defaultPromotionReplayMaxRecvBytes
+	)
+	if c.Window > 0 {
+		window = c.Window
+	}
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+	if c.MaxReceiveMessageSize > 0 {
+		maxRecvMsgSize = c.MaxReceiveMessageSize
+	}
+	replayer := grpcserver.NewPeerReplayer(c.Peers,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRecvMsgSize)))
+	replayOpts := aggregator.PromotionReplayOptions{
+		Replayer: replayer,
+		Window:   window,
+		Timeout:  timeout,
+	}
+	if err := replayOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetPromotionReplayOptions(&replayOpts), nil
+}
+
+type verificationConfiguration struct {
+	// Peers are the addresses of the admin gRPC services of the other instances
+	// of the shard set, which must enable the verification mode as well.
+	Peers []string `yaml:"peers" validate:"nonzero"`
+
+	// Interval is how often the leader compares digests.
+	Interval time.Duration `yaml:"interval"`
+
+	// Lag is how long after a window its digests are compared, which must
+	// leave the followers time to discard the window.
+	Lag time.Duration `yaml:"lag"`
+
+	// Retention is how long digests are kept after their window.
+	Retention time.Duration `yaml:"retention"`
+
+	// Timeout bounds how long fetching the digests of a shard takes.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// ValueTolerance is the difference between the values of the windows of
+	// the leader and a follower, relative to their magnitude, below which
+	// they match.
+	ValueTolerance *float64 `yaml:"valueTolerance"`
+}
+
+func (c verificationConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	verificationOpts := aggregator.VerificationOptions{
+		Fetcher:        grpcserver.NewPeerDigestFetcher(c.Peers),
+		Interval:       defaultVerificationInterval,
+		Lag:            defaultVerificationLag,
+		Retention:      defaultVerificationRetention,
+		Timeout:        defaultVerificationTimeout,
+		ValueTolerance: defaultVerificationValueTolerance,
+	}
+	if c.Interval > 0 {
+		verificationOpts.Interval = c.Interval
+	}
+	if c.Lag > 0 {
+		verificationOpts.Lag = c.Lag
+	}
+	if c.Retention > 0 {
+		verificationOpts.Retention = c.Retention
+	}
+	if c.Timeout > 0 {
+		verificationOpts.Timeout = c.Timeout
+	}
+	if c.ValueTolerance != nil {
+		verificationOpts.ValueTolerance = *c.ValueTolerance
+	}
+	if err := verificationOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetVerificationOptions(&verificationOpts), nil
+}
+
+type loadSheddingConfiguration struct {
+	// LagRatio is the ratio of the time spent ticking, excluding the sleeps
+	// spreading the tick over the entry check interval, to the entry check
+	// interval above which a tick lags, it defaults to one.
+	LagRatio float64 `yaml:"lagRatio" validate:"min=0"`
+
+	// LaggingTicks is the number of consecutive lagging ticks before traffic is
+	// shed, and of consecutive ticks in time before it stops being shed.
+	LaggingTicks int `yaml:"laggingTicks" validate:"min=1"`
+
+	// MetricTypes are the types of the metrics shed.
+	MetricTypes []metric.Type `yaml:"metricTypes"`
+
+	// StoragePolicies are the storage policies of the metrics shed.
+	StoragePolicies []policy.StoragePolicy `yaml:"storagePolicies"`
+}
+
+func (c loadSheddingConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	sheddingOpts := aggregator.LoadSheddingOptions{
+		LagRatio:        c.LagRatio,
+		LaggingTicks:    c.LaggingTicks,
+		MetricTypes:     c.MetricTypes,
+		StoragePolicies: c.StoragePolicies,
+	}
+	if err := sheddingOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetLoadSheddingOptions(&sheddingOpts), nil
+}
+
+type annotationSizeLimitConfiguration struct {
+	// MaxBytes is the max size in bytes of the annotation of a metric.
+	MaxBytes int `yaml:"maxBytes" validate:"min=1"`
+
+	// Policy is how metrics whose annotation exceeds the max size are handled,
+	// one of truncate, drop or reject, it defaults to truncate.
+	Policy aggregator.AnnotationSizePolicy `yaml:"policy"`
+}
+
+func (c annotationSizeLimitConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	limitOpts := aggregator.AnnotationSizeLimitOptions{
+		MaxBytes: c.MaxBytes,
+		Policy:   c.Policy,
+	}
+	if err := limitOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetAnnotationSizeLimitOptions(&limitOpts), nil
+}
+
+type flushOffsetTuningConfiguration struct {
+	// MinBuffer is the smallest buffer for past timed metrics the tuning sets.
+	MinBuffer time.Duration `yaml:"minBuffer" validate:"min=0"`
+
+	// MaxBuffer is the largest buffer for past timed metrics the tuning sets.
+	MaxBuffer time.Duration `yaml:"maxBuffer" validate:"nonzero"`
+
+	// Percentile is the percentile of the observed lateness of timed metrics
+	// the buffer covers, it defaults to 99.
+	Percentile float64 `yaml:"percentile" validate:"min=0,max=100"`
+
+	// MinSamples is the minimum number of timed metrics of a resolution
+	// observed before its buffer is tuned.
+	MinSamples int `yaml:"minSamples" validate:"min=0"`
+}
+
+func (c flushOffsetTuningConfiguration) apply(opts aggregator.Options) (aggregator.Options, error) {
+	tuningOpts := aggregator.FlushOffsetTuningOptions{
+		MinBuffer:  c.MinBuffer,
+		MaxBuffer:  c.MaxBuffer,
+		Percentile: c.Percentile,
+		MinSamples: c.MinSamples,
+	}
+	if err := tuningOpts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts.SetFlushOffsetTuningOptions(&tuningOpts), nil
+}
+
+// rollupCardinalityLimitsConfiguration caps the number of distinct series each
+// rollup rule produces in the aggregator. Rollup rules are identified by the name
+// of the metric they roll up into.
+type rollupCardinalityLimitsConfiguration struct {
+	// DefaultLimit is the cap of the rollup rules without a limit of their own,
+	// zero means unlimited.
+	DefaultLimit int `yaml:"defaultLimit" validate:"min=0"`
+
+	// Limits are the caps of rollup rules keyed by the name of the metric they
+	// roll up into.
+	Limits map[string]int `yaml:"limits"`
+
+	// Mode is either reject, the default, to reject all the new series beyond
+	// the cap or sample to accept a sample of them.
+	Mode aggregator.RollupCardinalityLimitMode `yaml:"mode"`
+
+	// SampleRate is the fraction of the new series beyond the cap accepted in
+	// the sample mode.
+	SampleRate float64 `yaml:"sampleRate"`
+
+	// NameTag is the tag the metric name is encoded as in rollup IDs, it
+	// defaults to __name__.
+	NameTag string `yaml:"nameTag"`
+}
+
+func (c rollupCardinalityLimitsConfiguration) apply(
+	opts aggregator.Options,
+	instrumentOpts instrument.Options,
+) (aggregator.Options, error) {
+	mode := c.Mode
+	if mode == "" {
+		mode = aggregator.RollupCardinalityLimitReject
+	}
+	scope := instrumentOpts.MetricsScope().SubScope("rollup-cardinality")
+	limiter, err := aggregator.NewRollupCardinalityLimiter(aggregator.RollupCardinalityLimitOptions{
+		DefaultLimit: c.DefaultLimit,
+		Limits:       c.Limits,
+		Mode:         mode,
+		SampleRate:   c.SampleRate,
+		NameTag:      []byte(c.NameTag),
+	}, scope)
+	if err != nil {
+		return nil, err
+	}
+	return opts.SetRollupCardinalityLimiter(limiter), nil
+}
+
+// newHeartbeatIDFn returns a function that encodes heartbeat metric IDs as
+// serialized tags, the same encoding used by the coordinator for metric IDs sent
+// to the aggregator, so that heartbeats can be ingested alongside other metrics.
+func newHeartbeatIDFn(nameTag []byte) id.NewIDFn {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions().SetSize(1))
+	encoderPool.Init()
+
+	return func(name []byte, tagPairs []id.TagPair) []byte {
+		tags := make([]ident.Tag, 0, len(tagPairs)+1)
+		tags = append(tags, ident.Tag{Name: ident.BytesID(nameTag), Value: ident.BytesID(name)})
+		for _, pair := range tagPairs {
+			tags = append(tags, ident.Tag{Name: ident.BytesID(pair.Name), Value: ident.BytesID(pair.Value)})
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			return bytes.Compare(tags[i].Name.Bytes(), tags[j].Name.Bytes()) < 0
+		})
+
+		encoder := encoderPool.Get()
+		defer encoder.Finalize()
+
+		if err := encoder.Encode(ident.NewTagsIterator(ident.NewTags(tags...))); err != nil {
+			return nil
+		}
+		data, ok := encoder.Data()
+		if !ok {
+			return nil
+		}
+		return append([]byte(nil), data.Bytes()...)
+	}
+}
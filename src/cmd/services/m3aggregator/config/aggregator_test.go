@@ -24,6 +24,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/metric/id"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -118,3 +126,128 @@ func TestMaxAllowedForwardingDelayFnJitterDisabled(t *testing.T) {
 		require.Equal(t, input.expected, fn(input.resolution, input.numForwardedTimes))
 	}
 }
+
+func TestHeartbeatConfiguration(t *testing.T) {
+	config := `
+enabled: true
+storagePolicy: 1m:2d
+metricNamePrefix: agg_heartbeat`
+
+	var cfg heartbeatConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+
+	opts := cfg.apply(aggregator.NewOptions(clock.NewOptions()))
+	require.True(t, opts.HeartbeatEnabled())
+	require.Equal(t, policy.MustParseStoragePolicy("1m:2d"), opts.HeartbeatStoragePolicy())
+	require.Equal(t, "agg_heartbeat", opts.HeartbeatMetricNamePrefix())
+
+	metricID := opts.HeartbeatNewIDFn()([]byte("agg_heartbeat_leader"), []id.TagPair{
+		{Name: []byte("instance"), Value: []byte("instance1")},
+	})
+
+	decoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(serialize.TagDecoderOptionsConfig{}),
+		pool.NewObjectPoolOptions().SetSize(1))
+	decoderPool.Init()
+	it := serialize.NewMetricTagsIterator(decoderPool.Get(), nil)
+	it.Reset(metricID)
+	tags := make(map[string]string)
+	for it.Next() {
+		name, value := it.Current()
+		tags[string(name)] = string(value)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, map[string]string{
+		"__name__": "agg_heartbeat_leader",
+		"instance": "instance1",
+	}, tags)
+}
+
+func TestResolutionDowngradeConfiguration(t *testing.T) {
+	config := `
+policies:
+  - storagePolicy: 10s:2d
+    resolution: 1m
+memoryWatchdog:
+  highWatermarkBytes: 8000000000
+  lowWatermarkBytes: 6000000000`
+
+	var cfg resolutionDowngradeConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+
+	opts, err := cfg.apply(aggregator.NewOptions(clock.NewOptions()))
+	require.NoError(t, err)
+	downgrader := opts.ResolutionDowngrader()
+	require.NotNil(t, downgrader)
+	require.Equal(t, []aggregator.ResolutionDowngradePolicy{
+		{StoragePolicy: policy.MustParseStoragePolicy("10s:2d"), Resolution: time.Minute},
+	}, downgrader.Status().Policies)
+	require.Equal(t, aggregator.MemoryWatchdogOptions{
+		CheckInterval:      defaultMemoryWatchdogCheckInterval,
+		HighWatermarkBytes: 8000000000,
+		LowWatermarkBytes:  6000000000,
+	}, opts.MemoryWatchdogOptions())
+
+	cfg.Policies[0].Resolution = 15 * time.Second
+	_, err = cfg.apply(aggregator.NewOptions(clock.NewOptions()))
+	require.Error(t, err)
+}
+
+func TestPastTimedMetricBuffers(t *testing.T) {
+	config := `
+- storagePolicy: 1h:30d
+  buffer: 10m
+- storagePolicy: 10s:2d
+  buffer: 30s
+- storagePolicy: 10s:7d
+  buffer: 30s`
+
+	var buffers pastTimedMetricBuffers
+	require.NoError(t, yaml.Unmarshal([]byte(config), &buffers))
+
+	bufferFn, maxBuffer, err := buffers.NewBufferForPastTimedMetricFn(time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, maxBuffer)
+	require.Equal(t, time.Hour+10*time.Minute, bufferFn(time.Hour))
+	require.Equal(t, 40*time.Second, bufferFn(10*time.Second))
+	require.Equal(t, 2*time.Minute, bufferFn(time.Minute))
+
+	// The default buffer is the max buffer if it is the largest.
+	_, maxBuffer, err = buffers.NewBufferForPastTimedMetricFn(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, maxBuffer)
+
+	// Storage policies with the same resolution can not have different buffers.
+	buffers[2].Buffer = time.Minute
+	_, _, err = buffers.NewBufferForPastTimedMetricFn(time.Minute)
+	require.Error(t, err)
+}
+
+func TestFlushManagerConfigurationClockSkewThreshold(t *testing.T) {
+	config := `
+clockSkewThreshold: 30s
+clockSkewClampEnabled: true`
+
+	var cfg flushManagerConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+	require.True(t, cfg.ClockSkewClampEnabled)
+
+	opts, err := cfg.NewFlushManagerOptions(nil, nil, nil, instrument.NewOptions(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, opts.ClockSkewThreshold())
+	require.Nil(t, opts.ClockSkewWatermark())
+}
+
+func TestFlushManagerConfigurationShardFlushControls(t *testing.T) {
+	config := `
+maxConcurrentShardFlushes: 4
+shardFlushPacing: 0.25`
+
+	var cfg flushManagerConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+
+	opts, err := cfg.NewFlushManagerOptions(nil, nil, nil, instrument.NewOptions(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, opts.MaxConcurrentShardFlushes())
+	require.Equal(t, 0.25, opts.ShardFlushPacing())
+}
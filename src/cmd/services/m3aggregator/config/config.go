@@ -21,6 +21,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/m3db/m3/src/x/debug/config"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/log"
@@ -46,6 +49,10 @@ type Configuration struct {
 	// Optional.
 	HTTP *HTTPServerConfiguration `yaml:"http"`
 
+	// GRPC admin server configuration.
+	// Optional.
+	GRPC *GRPCServerConfiguration `yaml:"grpc"`
+
 	// Client configuration for key value store.
 	KVClient KVClientConfiguration `yaml:"kvClient" validate:"nonzero"`
 
@@ -55,6 +62,140 @@ type Configuration struct {
 	// Aggregator configuration.
 	Aggregator AggregatorConfiguration `yaml:"aggregator"`
 
+	// Tiers configures additional aggregation tiers served by the same process,
+	// e.g. a level-2 aggregation tier alongside the level-1 tier configured
+	// above, each with its own placement, shard set, election and flush manager.
+	// Optional.
+	Tiers []TierConfiguration `yaml:"tiers"`
+
 	// Debug configuration.
 	Debug config.DebugConfiguration `yaml:"debug"`
 }
+
+// TierConfiguration is the configuration of an additional aggregation tier
+// served by the same process. The tier must use its own placement, election
+// and flush times keys as well as its own server listen addresses.
+type TierConfiguration struct {
+	// Name is the name of the tier, used to tag the tier's metrics.
+	Name string `yaml:"name" validate:"nonzero"`
+
+	// M3Msg server configuration.
+	// Optional.
+	M3Msg *M3MsgServerConfiguration `yaml:"m3msg"`
+
+	// Raw TCP server configuration.
+	// Optional.
+	RawTCP *RawTCPServerConfiguration `yaml:"rawtcp"`
+
+	// HTTP server configuration.
+	// Optional.
+	HTTP *HTTPServerConfiguration `yaml:"http"`
+
+	// GRPC admin server configuration.
+	// Optional.
+	GRPC *GRPCServerConfiguration `yaml:"grpc"`
+
+	// Runtime options configuration.
+	RuntimeOptions RuntimeOptionsConfiguration `yaml:"runtimeOptions"`
+
+	// Aggregator configuration.
+	Aggregator AggregatorConfiguration `yaml:"aggregator"`
+}
+
+// AggregationTiers returns the aggregation tiers served by the process, the
+// first one being the tier configured at the top level of the configuration.
+func (c Configuration) AggregationTiers() []TierConfiguration {
+	return append([]TierConfiguration{
+		{
+			M3Msg:          c.M3Msg,
+			RawTCP:         c.RawTCP,
+			HTTP:           c.HTTP,
+			GRPC:           c.GRPC,
+			RuntimeOptions: c.RuntimeOptions,
+			Aggregator:     c.Aggregator,
+		},
+	}, c.Tiers...)
+}
+
+// Validate validates the configuration.
+func (c Configuration) Validate() error {
+	var (
+		names     = make(map[string]struct{}, len(c.Tiers))
+		addresses = make(map[string]string)
+		keys      = make(map[string]string)
+	)
+	for i, tier := range c.AggregationTiers() {
+		name := tier.Name
+		if i > 0 {
+			if err := tier.validate(); err != nil {
+				return err
+			}
+			if _, ok := names[name]; ok {
+				return fmt.Errorf("duplicate aggregation tier: %s", name)
+			}
+			names[name] = struct{}{}
+		}
+
+		// Tiers can neither share a listen address nor the keys of their
+		// placement, election and flush times.
+		for _, addr := range tier.listenAddresses() {
+			if other, ok := addresses[addr]; ok {
+				return fmt.Errorf("aggregation tiers %q and %q listen on the same address: %s",
+					other, name, addr)
+			}
+			addresses[addr] = name
+		}
+		for _, key := range tier.keys() {
+			if key == "" {
+				continue
+			}
+			if other, ok := keys[key]; ok {
+				return fmt.Errorf("aggregation tiers %q and %q use the same key: %s",
+					other, name, key)
+			}
+			keys[key] = name
+		}
+	}
+	return nil
+}
+
+func (c TierConfiguration) validate() error {
+	if c.Name == "" {
+		return errors.New("aggregation tier with no name")
+	}
+	if c.M3Msg == nil && c.RawTCP == nil {
+		return fmt.Errorf("aggregation tier %s has neither an m3msg nor a rawtcp server", c.Name)
+	}
+	for _, key := range c.keys() {
+		if key == "" {
+			return fmt.Errorf("aggregation tier %s must set its placement, election and flush times keys",
+				c.Name)
+		}
+	}
+	return nil
+}
+
+func (c TierConfiguration) listenAddresses() []string {
+	var addrs []string
+	if c.M3Msg != nil {
+		addrs = append(addrs, c.M3Msg.Server.ListenAddress)
+	}
+	if c.RawTCP != nil {
+		addrs = append(addrs, c.RawTCP.ListenAddress)
+	}
+	if c.HTTP != nil {
+		addrs = append(addrs, c.HTTP.ListenAddress)
+	}
+	if c.GRPC != nil {
+		addrs = append(addrs, c.GRPC.ListenAddress)
+	}
+	return addrs
+}
+
+func (c TierConfiguration) keys() []string {
+	return []string{
+		c.Aggregator.PlacementManager.Watcher.Key,
+		c.Aggregator.ElectionManager.ElectionKeyFmt,
+		c.Aggregator.FlushTimesManager.FlushTimesKeyFmt,
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestConfigurationValidateTiers(t *testing.T) {
+	str := `
+m3msg:
+  server:
+    listenAddress: 0.0.0.0:6000
+aggregator:
+  placementManager:
+    placementWatcher:
+      key: m3aggregator
+  electionManager:
+    electionKeyFmt: /shardset/%d/lock
+  flushTimesManager:
+    flushTimesKeyFmt: /shardset/%d/flush
+tiers:
+  - name: level-2
+    m3msg:
+      server:
+        listenAddress: 0.0.0.0:6010
+    http:
+      listenAddress: 0.0.0.0:6011
+    aggregator:
+      placementManager:
+        placementWatcher:
+          key: m3aggregator-level-2
+      electionManager:
+        electionKeyFmt: /level-2/shardset/%d/lock
+      flushTimesManager:
+        flushTimesKeyFmt: /level-2/shardset/%d/flush
+  - name: level-3
+    rawtcp:
+      listenAddress: 0.0.0.0:6020
+    aggregator:
+      placementManager:
+        placementWatcher:
+          key: m3aggregator-level-3
+      electionManager:
+        electionKeyFmt: /level-3/shardset/%d/lock
+      flushTimesManager:
+        flushTimesKeyFmt: /level-3/shardset/%d/flush
+`
+	newConfig := func() Configuration {
+		var cfg Configuration
+		require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+		return cfg
+	}
+
+	cfg := newConfig()
+	require.Len(t, cfg.Tiers, 2)
+	require.Len(t, cfg.AggregationTiers(), 3)
+	require.NoError(t, cfg.Validate())
+
+	for _, test := range []struct {
+		name   string
+		update func(cfg *Configuration)
+	}{
+		{
+			name:   "duplicate name",
+			update: func(cfg *Configuration) { cfg.Tiers[1].Name = "level-2" },
+		},
+		{
+			name:   "no name",
+			update: func(cfg *Configuration) { cfg.Tiers[1].Name = "" },
+		},
+		{
+			name:   "no ingest server",
+			update: func(cfg *Configuration) { cfg.Tiers[1].RawTCP = nil },
+		},
+		{
+			name: "shared listen address",
+			update: func(cfg *Configuration) {
+				cfg.Tiers[1].RawTCP.ListenAddress = cfg.M3Msg.Server.ListenAddress
+			},
+		},
+		{
+			name: "shared placement key",
+			update: func(cfg *Configuration) {
+				cfg.Tiers[0].Aggregator.PlacementManager.Watcher.Key = "m3aggregator"
+			},
+		},
+		{
+			name: "missing election key",
+			update: func(cfg *Configuration) {
+				cfg.Tiers[1].Aggregator.ElectionManager.ElectionKeyFmt = ""
+			},
+		},
+		{
+			name: "shared flush times key",
+			update: func(cfg *Configuration) {
+				cfg.Tiers[1].Aggregator.FlushTimesManager.FlushTimesKeyFmt =
+					cfg.Tiers[0].Aggregator.FlushTimesManager.FlushTimesKeyFmt
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newConfig()
+			test.update(&cfg)
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
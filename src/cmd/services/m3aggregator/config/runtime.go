@@ -21,7 +21,9 @@
 package config
 
 import (
+	"fmt"
 	"math"
+	"strconv"
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/aggregator"
@@ -33,6 +35,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// defaultMaxAllowedForwardingDelayScale leaves the maximum allowed forwarding
+// delay unscaled.
+const defaultMaxAllowedForwardingDelayScale = 1.0
+
 // RuntimeOptionsConfiguration configures runtime options.
 type RuntimeOptionsConfiguration struct {
 	KVConfig                               kv.OverrideConfiguration `yaml:"kvConfig"`
@@ -41,6 +47,28 @@ type RuntimeOptionsConfiguration struct {
 	WriteNewMetricLimitClusterPerSecondKey string                   `yaml:"writeNewMetricLimitClusterPerSecondKey" validate:"nonzero"`
 	WriteNewMetricLimitClusterPerSecond    int64                    `yaml:"writeNewMetricLimitClusterPerSecond"`
 	WriteNewMetricNoLimitWarmupDuration    time.Duration            `yaml:"writeNewMetricNoLimitWarmupDuration"`
+
+	// EntryCheckIntervalKey is the optional KV key of the entry check interval
+	// in nanoseconds, overriding the aggregator entry check interval if positive.
+	EntryCheckIntervalKey string `yaml:"entryCheckIntervalKey"`
+
+	// MaxAllowedForwardingDelayScaleKey is the optional KV key of the factor
+	// the maximum allowed forwarding delay is scaled by.
+	MaxAllowedForwardingDelayScaleKey string `yaml:"maxAllowedForwardingDelayScaleKey"`
+
+	// TimedForResendEnabledRollupRegexpsKey is the optional KV key of the
+	// regexps of the rollup IDs converted from untimed to timed metrics,
+	// overriding the aggregator regexps if not empty.
+	TimedForResendEnabledRollupRegexpsKey string `yaml:"timedForResendEnabledRollupRegexpsKey"`
+
+	// WritesIgnoreCutoffCutoverKey is the optional KV key of the flag accepting
+	// writes to all shards regardless of the shard cutoff and cutover times, in
+	// addition to the aggregator flag.
+	WritesIgnoreCutoffCutoverKey string `yaml:"writesIgnoreCutoffCutoverKey"`
+
+	// WritesIgnoreCutoffCutoverShardsKey is the optional KV key of the IDs of
+	// the shards accepting writes regardless of the shard cutoff and cutover times.
+	WritesIgnoreCutoffCutoverShardsKey string `yaml:"writesIgnoreCutoffCutoverShardsKey"`
 }
 
 // NewRuntimeOptionsManager creates a new runtime options manager.
@@ -79,6 +107,27 @@ func (c RuntimeOptionsConfiguration) WatchRuntimeOptionChanges(
 		newMetricClusterLimit        int64
 		newMetricPerShardLimit       int64
 		newMetricLimitCh             <-chan struct{}
+		checkIntervalKey             = c.EntryCheckIntervalKey
+		checkInterval                int64
+		checkIntervalWatch           kv.ValueWatch
+		checkIntervalCh              <-chan struct{}
+		forwardingDelayScaleKey      = c.MaxAllowedForwardingDelayScaleKey
+		forwardingDelayScale         = defaultMaxAllowedForwardingDelayScale
+		forwardingDelayScaleWatch    kv.ValueWatch
+		forwardingDelayScaleCh       <-chan struct{}
+		rollupRegexpsKey             = c.TimedForResendEnabledRollupRegexpsKey
+		rollupRegexps                []string
+		rollupRegexpsWatch           kv.ValueWatch
+		rollupRegexpsCh              <-chan struct{}
+		ignoreCutoffCutoverKey       = c.WritesIgnoreCutoffCutoverKey
+		ignoreCutoffCutover          bool
+		ignoreCutoffCutoverWatch     kv.ValueWatch
+		ignoreCutoffCutoverCh        <-chan struct{}
+		ignoreShardsKey              = c.WritesIgnoreCutoffCutoverShardsKey
+		ignoreShards                 []uint32
+		ignoreShardsWatch            kv.ValueWatch
+		ignoreShardsCh               <-chan struct{}
+		utilOpts                     = kvutil.NewOptions().SetLogger(logger)
 	)
 	valueLimit, err = retrieveLimit(valueLimitKey, store, defaultValueLimit)
 	if err != nil {
@@ -96,10 +145,55 @@ func (c RuntimeOptionsConfiguration) WatchRuntimeOptionChanges(
 	logger.Info("current write new metric limit per shard per second",
 		zap.Int64("limit", newMetricPerShardLimit))
 
+	if checkIntervalKey != "" {
+		checkInterval, err = retrieveLimit(checkIntervalKey, store, 0)
+		if err != nil {
+			logger.Error("unable to retrieve entry check interval from kv", zap.Error(err))
+		}
+		logger.Info("current entry check interval", zap.Duration("interval", time.Duration(checkInterval)))
+	}
+
+	if forwardingDelayScaleKey != "" {
+		forwardingDelayScale, err = retrieveScale(forwardingDelayScaleKey, store, defaultMaxAllowedForwardingDelayScale)
+		if err != nil {
+			logger.Error("unable to retrieve max allowed forwarding delay scale from kv", zap.Error(err))
+		}
+		logger.Info("current max allowed forwarding delay scale", zap.Float64("scale", forwardingDelayScale))
+	}
+
+	if rollupRegexpsKey != "" {
+		rollupRegexps, err = retrieveRollupRegexps(rollupRegexpsKey, store)
+		if err != nil {
+			logger.Error("unable to retrieve timed for resend enabled rollup regexps from kv", zap.Error(err))
+		}
+		logger.Info("current timed for resend enabled rollup regexps", zap.Strings("regexps", rollupRegexps))
+	}
+
+	if ignoreCutoffCutoverKey != "" {
+		ignoreCutoffCutover, err = retrieveBool(ignoreCutoffCutoverKey, store)
+		if err != nil {
+			logger.Error("unable to retrieve writes ignore cutoff cutover from kv", zap.Error(err))
+		}
+		logger.Info("current writes ignore cutoff cutover", zap.Bool("ignore", ignoreCutoffCutover))
+	}
+
+	if ignoreShardsKey != "" {
+		ignoreShards, err = retrieveShardIDs(ignoreShardsKey, store)
+		if err != nil {
+			logger.Error("unable to retrieve writes ignore cutoff cutover shards from kv", zap.Error(err))
+		}
+		logger.Info("current writes ignore cutoff cutover shards", zap.Uint32s("shards", ignoreShards))
+	}
+
 	runtimeOpts := runtime.NewOptions().
 		SetWriteNewMetricNoLimitWarmupDuration(c.WriteNewMetricNoLimitWarmupDuration).
 		SetWriteValuesPerMetricLimitPerSecond(valueLimit).
-		SetWriteNewMetricLimitPerShardPerSecond(newMetricPerShardLimit)
+		SetWriteNewMetricLimitPerShardPerSecond(newMetricPerShardLimit).
+		SetEntryCheckInterval(time.Duration(checkInterval)).
+		SetMaxAllowedForwardingDelayScale(forwardingDelayScale).
+		SetTimedForResendEnabledRollupRegexps(rollupRegexps).
+		SetWritesIgnoreCutoffCutover(ignoreCutoffCutover).
+		SetWritesIgnoreCutoffCutoverShards(ignoreShards)
 	runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
 
 	valueLimitWatch, err := store.Watch(valueLimitKey)
@@ -114,12 +208,53 @@ func (c RuntimeOptionsConfiguration) WatchRuntimeOptionChanges(
 	} else {
 		newMetricLimitCh = newMetricLimitWatch.C()
 	}
-	// If watch creation failed for both, we return immediately.
-	if valueLimitCh == nil && newMetricLimitCh == nil {
+	if checkIntervalKey != "" {
+		checkIntervalWatch, err = store.Watch(checkIntervalKey)
+		if err != nil {
+			logger.Error("unable to watch entry check interval", zap.Error(err))
+		} else {
+			checkIntervalCh = checkIntervalWatch.C()
+		}
+	}
+	if forwardingDelayScaleKey != "" {
+		forwardingDelayScaleWatch, err = store.Watch(forwardingDelayScaleKey)
+		if err != nil {
+			logger.Error("unable to watch max allowed forwarding delay scale", zap.Error(err))
+		} else {
+			forwardingDelayScaleCh = forwardingDelayScaleWatch.C()
+		}
+	}
+	if rollupRegexpsKey != "" {
+		rollupRegexpsWatch, err = store.Watch(rollupRegexpsKey)
+		if err != nil {
+			logger.Error("unable to watch timed for resend enabled rollup regexps", zap.Error(err))
+		} else {
+			rollupRegexpsCh = rollupRegexpsWatch.C()
+		}
+	}
+	if ignoreCutoffCutoverKey != "" {
+		ignoreCutoffCutoverWatch, err = store.Watch(ignoreCutoffCutoverKey)
+		if err != nil {
+			logger.Error("unable to watch writes ignore cutoff cutover", zap.Error(err))
+		} else {
+			ignoreCutoffCutoverCh = ignoreCutoffCutoverWatch.C()
+		}
+	}
+	if ignoreShardsKey != "" {
+		ignoreShardsWatch, err = store.Watch(ignoreShardsKey)
+		if err != nil {
+			logger.Error("unable to watch writes ignore cutoff cutover shards", zap.Error(err))
+		} else {
+			ignoreShardsCh = ignoreShardsWatch.C()
+		}
+	}
+	// If watch creation failed for all, we return immediately.
+	if valueLimitCh == nil && newMetricLimitCh == nil &&
+		checkIntervalCh == nil && forwardingDelayScaleCh == nil && rollupRegexpsCh == nil &&
+		ignoreCutoffCutoverCh == nil && ignoreShardsCh == nil {
 		return
 	}
 
-	utilOpts := kvutil.NewOptions().SetLogger(logger)
 	go func() {
 		for {
 			select {
@@ -162,6 +297,89 @@ func (c RuntimeOptionsConfiguration) WatchRuntimeOptionChanges(
 					zap.Int64("new", newNewMetricPerShardLimit))
 				runtimeOpts = runtimeOpts.SetWriteNewMetricLimitPerShardPerSecond(newNewMetricPerShardLimit)
 				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
+			case <-checkIntervalCh:
+				checkIntervalVal := checkIntervalWatch.Get()
+				newCheckInterval, err := kvutil.Int64FromValue(checkIntervalVal, checkIntervalKey, 0, utilOpts)
+				if err != nil {
+					logger.Error("unable to determine entry check interval", zap.Error(err))
+					continue
+				}
+				currCheckInterval := runtimeOpts.EntryCheckInterval()
+				if time.Duration(newCheckInterval) == currCheckInterval {
+					logger.Info("entry check interval is unchanged, skipping",
+						zap.Duration("interval", currCheckInterval))
+					continue
+				}
+				logger.Info("updating entry check interval",
+					zap.Duration("current", currCheckInterval),
+					zap.Duration("new", time.Duration(newCheckInterval)))
+				runtimeOpts = runtimeOpts.SetEntryCheckInterval(time.Duration(newCheckInterval))
+				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
+			case <-forwardingDelayScaleCh:
+				forwardingDelayScaleVal := forwardingDelayScaleWatch.Get()
+				newScale, err := kvutil.Float64FromValue(forwardingDelayScaleVal, forwardingDelayScaleKey,
+					defaultMaxAllowedForwardingDelayScale, utilOpts)
+				if err != nil {
+					logger.Error("unable to determine max allowed forwarding delay scale", zap.Error(err))
+					continue
+				}
+				currScale := runtimeOpts.MaxAllowedForwardingDelayScale()
+				if newScale == currScale {
+					logger.Info("max allowed forwarding delay scale is unchanged, skipping",
+						zap.Float64("scale", newScale))
+					continue
+				}
+				logger.Info("updating max allowed forwarding delay scale",
+					zap.Float64("current", currScale),
+					zap.Float64("new", newScale))
+				runtimeOpts = runtimeOpts.SetMaxAllowedForwardingDelayScale(newScale)
+				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
+			case <-rollupRegexpsCh:
+				rollupRegexpsVal := rollupRegexpsWatch.Get()
+				newRollupRegexps, err := rollupRegexpsFromValue(rollupRegexpsVal, rollupRegexpsKey, utilOpts)
+				if err != nil {
+					logger.Error("unable to determine timed for resend enabled rollup regexps", zap.Error(err))
+					continue
+				}
+				logger.Info("updating timed for resend enabled rollup regexps",
+					zap.Strings("current", runtimeOpts.TimedForResendEnabledRollupRegexps()),
+					zap.Strings("new", newRollupRegexps))
+				runtimeOpts = runtimeOpts.SetTimedForResendEnabledRollupRegexps(newRollupRegexps)
+				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
+			case <-ignoreCutoffCutoverCh:
+				ignoreCutoffCutoverVal := ignoreCutoffCutoverWatch.Get()
+				newIgnore, err := kvutil.BoolFromValue(ignoreCutoffCutoverVal, ignoreCutoffCutoverKey, false, utilOpts)
+				if err != nil {
+					logger.Error("unable to determine writes ignore cutoff cutover", zap.Error(err))
+					continue
+				}
+				currIgnore := runtimeOpts.WritesIgnoreCutoffCutover()
+				if newIgnore == currIgnore {
+					logger.Info("writes ignore cutoff cutover is unchanged, skipping",
+						zap.Bool("ignore", newIgnore))
+					continue
+				}
+				logger.Info("updating writes ignore cutoff cutover",
+					zap.Bool("current", currIgnore),
+					zap.Bool("new", newIgnore))
+				runtimeOpts = runtimeOpts.SetWritesIgnoreCutoffCutover(newIgnore)
+				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
+			case <-ignoreShardsCh:
+				ignoreShardsVal := ignoreShardsWatch.Get()
+				values, err := kvutil.StringArrayFromValue(ignoreShardsVal, ignoreShardsKey, nil, utilOpts)
+				var newIgnoreShards []uint32
+				if err == nil {
+					newIgnoreShards, err = parseShardIDs(values)
+				}
+				if err != nil {
+					logger.Error("unable to determine writes ignore cutoff cutover shards", zap.Error(err))
+					continue
+				}
+				logger.Info("updating writes ignore cutoff cutover shards",
+					zap.Uint32s("current", runtimeOpts.WritesIgnoreCutoffCutoverShards()),
+					zap.Uint32s("new", newIgnoreShards))
+				runtimeOpts = runtimeOpts.SetWritesIgnoreCutoffCutoverShards(newIgnoreShards)
+				runtimeOptsManager.SetRuntimeOptions(runtimeOpts)
 			}
 		}
 	}()
@@ -187,6 +405,76 @@ func clusterLimitToPerShardLimit(
 	return perShardLimit, nil
 }
 
+func retrieveScale(key string, store kv.Store, defaultScale float64) (float64, error) {
+	scale := defaultScale
+	value, err := store.Get(key)
+	if err == nil {
+		scale, err = kvutil.Float64FromValue(value, key, defaultScale, nil)
+	}
+	return scale, err
+}
+
+func retrieveStrings(key string, store kv.Store) ([]string, error) {
+	value, err := store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return kvutil.StringArrayFromValue(value, key, nil, nil)
+}
+
+func retrieveRollupRegexps(key string, store kv.Store) ([]string, error) {
+	value, err := store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return rollupRegexpsFromValue(value, key, nil)
+}
+
+// rollupRegexpsFromValue returns the rollup regexps of the value, which are
+// nil if the key is not set so that the statically configured regexps are used
+// and empty if the key is set to an empty list.
+func rollupRegexpsFromValue(value kv.Value, key string, opts kvutil.Options) ([]string, error) {
+	regexps, err := kvutil.StringArrayFromValue(value, key, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && regexps == nil {
+		regexps = []string{}
+	}
+	return regexps, nil
+}
+
+func retrieveBool(key string, store kv.Store) (bool, error) {
+	value, err := store.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return kvutil.BoolFromValue(value, key, false, nil)
+}
+
+func retrieveShardIDs(key string, store kv.Store) ([]uint32, error) {
+	values, err := retrieveStrings(key, store)
+	if err != nil {
+		return nil, err
+	}
+	return parseShardIDs(values)
+}
+
+func parseShardIDs(values []string) ([]uint32, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	shards := make([]uint32, 0, len(values))
+	for _, v := range values {
+		shard, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard ID %q: %w", v, err)
+		}
+		shards = append(shards, uint32(shard))
+	}
+	return shards, nil
+}
+
 func retrieveLimit(key string, store kv.Store, defaultLimit int64) (int64, error) {
 	limit := defaultLimit
 	value, err := store.Get(key)
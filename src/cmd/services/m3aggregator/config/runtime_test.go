@@ -158,8 +158,130 @@ writeNewMetricNoLimitWarmupDuration: 10m
 	}
 }
 
+func TestRuntimeOptionsConfigurationWatchAggregatorOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := `
+kvConfig:
+  zone: test
+  environment: production
+writeValuesPerMetricLimitPerSecondKey: rate-limit-key
+writeNewMetricLimitClusterPerSecondKey: new-metric-limit-key
+entryCheckIntervalKey: entry-check-interval-key
+maxAllowedForwardingDelayScaleKey: forwarding-delay-scale-key
+timedForResendEnabledRollupRegexpsKey: rollup-regexps-key
+writesIgnoreCutoffCutoverKey: ignore-cutoff-cutover-key
+writesIgnoreCutoffCutoverShardsKey: ignore-cutoff-cutover-shards-key
+`
+	var cfg RuntimeOptionsConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+	require.Equal(t, "entry-check-interval-key", cfg.EntryCheckIntervalKey)
+	require.Equal(t, "forwarding-delay-scale-key", cfg.MaxAllowedForwardingDelayScaleKey)
+
+	memStore := mem.NewStore()
+	_, err := memStore.Set("entry-check-interval-key",
+		&commonpb.Int64Proto{Value: int64(time.Minute)})
+	require.NoError(t, err)
+
+	runtimeOptsManager := cfg.NewRuntimeOptionsManager()
+	testPlacement := placement.NewPlacement().SetReplicaFactor(1).SetShards([]uint32{0})
+	testPlacementManager := aggregator.NewMockPlacementManager(ctrl)
+	testPlacementManager.EXPECT().Placement().Return(testPlacement, nil).AnyTimes()
+
+	mockClient := client.NewMockClient(ctrl)
+	mockClient.EXPECT().Store(gomock.Any()).Return(memStore, nil)
+	cfg.WatchRuntimeOptionChanges(mockClient, runtimeOptsManager, testPlacementManager, xtest.NewLogger(t))
+	runtimeOpts := runtimeOptsManager.RuntimeOptions()
+	require.Equal(t, time.Minute, runtimeOpts.EntryCheckInterval())
+	require.Equal(t, 1.0, runtimeOpts.MaxAllowedForwardingDelayScale())
+
+	// Scale the max allowed forwarding delay.
+	_, err = memStore.Set("forwarding-delay-scale-key", &commonpb.Float64Proto{Value: 2})
+	require.NoError(t, err)
+	expectedOpts := runtime.NewOptions().
+		SetEntryCheckInterval(time.Minute).
+		SetMaxAllowedForwardingDelayScale(2)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if compareRuntimeOptions(expectedOpts, runtimeOpts) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Reset the entry check interval.
+	_, err = memStore.Set("entry-check-interval-key", &commonpb.Int64Proto{Value: 0})
+	require.NoError(t, err)
+	expectedOpts = expectedOpts.SetEntryCheckInterval(0)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if compareRuntimeOptions(expectedOpts, runtimeOpts) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Set the timed for resend enabled rollup regexps.
+	_, err = memStore.Set("rollup-regexps-key",
+		&commonpb.StringArrayProto{Values: []string{"^foo", "^bar"}})
+	require.NoError(t, err)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if len(runtimeOpts.TimedForResendEnabledRollupRegexps()) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, []string{"^foo", "^bar"}, runtimeOpts.TimedForResendEnabledRollupRegexps())
+	require.True(t, compareRuntimeOptions(expectedOpts, runtimeOpts))
+
+	// An empty list of regexps is kept rather than treated as unset.
+	_, err = memStore.Set("rollup-regexps-key", &commonpb.StringArrayProto{})
+	require.NoError(t, err)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if len(runtimeOpts.TimedForResendEnabledRollupRegexps()) == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, []string{}, runtimeOpts.TimedForResendEnabledRollupRegexps())
+
+	// Ignore the cutoff and cutover times of all shards.
+	_, err = memStore.Set("ignore-cutoff-cutover-key", &commonpb.BoolProto{Value: true})
+	require.NoError(t, err)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if runtimeOpts.WritesIgnoreCutoffCutover() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Ignore the cutoff and cutover times of selected shards, invalid shard IDs
+	// are rejected.
+	_, err = memStore.Set("ignore-cutoff-cutover-shards-key",
+		&commonpb.StringArrayProto{Values: []string{"1", "foo"}})
+	require.NoError(t, err)
+	_, err = memStore.Set("ignore-cutoff-cutover-shards-key",
+		&commonpb.StringArrayProto{Values: []string{"3", "5"}})
+	require.NoError(t, err)
+	for {
+		runtimeOpts = runtimeOptsManager.RuntimeOptions()
+		if len(runtimeOpts.WritesIgnoreCutoffCutoverShards()) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, []uint32{3, 5}, runtimeOpts.WritesIgnoreCutoffCutoverShards())
+	require.True(t, runtimeOpts.WritesIgnoreCutoffCutover())
+}
+
 func compareRuntimeOptions(expected, actual runtime.Options) bool {
 	return expected.WriteNewMetricLimitPerShardPerSecond() == actual.WriteNewMetricLimitPerShardPerSecond() &&
 		expected.WriteNewMetricNoLimitWarmupDuration() == actual.WriteNewMetricNoLimitWarmupDuration() &&
-		expected.WriteValuesPerMetricLimitPerSecond() == actual.WriteValuesPerMetricLimitPerSecond()
+		expected.WriteValuesPerMetricLimitPerSecond() == actual.WriteValuesPerMetricLimitPerSecond() &&
+		expected.EntryCheckInterval() == actual.EntryCheckInterval() &&
+		expected.MaxAllowedForwardingDelayScale() == actual.MaxAllowedForwardingDelayScale()
 }
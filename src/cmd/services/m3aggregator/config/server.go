@@ -21,6 +21,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/m3db/m3/src/aggregator/server/http"
@@ -31,7 +32,9 @@ import (
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/retry"
+	"github.com/m3db/m3/src/x/sampler"
 	xserver "github.com/m3db/m3/src/x/server"
+	xtls "github.com/m3db/m3/src/x/tls"
 )
 
 // M3MsgServerConfiguration contains M3Msg server configuration.
@@ -41,16 +44,26 @@ type M3MsgServerConfiguration struct {
 
 	// Consumer is the M3Msg consumer configuration.
 	Consumer consumer.Configuration `yaml:"consumer"`
+
+	// UnknownFieldsSampleRate is the fraction of messages checked for fields
+	// unknown to this version of the messages, e.g. to monitor upgrades of the
+	// clients, defaults to checking none.
+	UnknownFieldsSampleRate sampler.Rate `yaml:"unknownFieldsSampleRate"`
 }
 
 // NewServerOptions creates a new set of M3Msg server options.
 func (c *M3MsgServerConfiguration) NewServerOptions(
 	instrumentOpts instrument.Options,
 ) (m3msg.Options, error) {
+	serverOpts, err := c.Server.NewOptions(instrumentOpts)
+	if err != nil {
+		return nil, err
+	}
 	opts := m3msg.NewOptions().
 		SetInstrumentOptions(instrumentOpts).
-		SetServerOptions(c.Server.NewOptions(instrumentOpts)).
-		SetConsumerOptions(c.Consumer.NewOptions(instrumentOpts))
+		SetServerOptions(serverOpts).
+		SetConsumerOptions(c.Consumer.NewOptions(instrumentOpts)).
+		SetUnknownFieldsSampleRate(c.UnknownFieldsSampleRate)
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
@@ -77,14 +90,21 @@ type RawTCPServerConfiguration struct {
 	// Read buffer size.
 	ReadBufferSize *int `yaml:"readBufferSize"`
 
+	// Maximum number of consecutive passthrough metrics read from a connection
+	// that are added as a batch.
+	PassthroughBatchSize *int `yaml:"passthroughBatchSize"`
+
 	// Protobuf iterator configuration.
 	ProtobufIterator protobufUnaggregatedIteratorConfiguration `yaml:"protobufIterator"`
+
+	// TLS configuration, connections are not encrypted if not set.
+	TLS *xtls.ServerConfiguration `yaml:"tls"`
 }
 
 // NewServerOptions create a new set of raw TCP server options.
 func (c *RawTCPServerConfiguration) NewServerOptions(
 	instrumentOpts instrument.Options,
-) rawtcp.Options {
+) (rawtcp.Options, error) {
 	opts := rawtcp.NewOptions().SetInstrumentOptions(instrumentOpts)
 
 	// Set server options.
@@ -97,6 +117,13 @@ func (c *RawTCPServerConfiguration) NewServerOptions(
 	if c.KeepAlivePeriod != nil {
 		serverOpts = serverOpts.SetTCPConnectionKeepAlivePeriod(*c.KeepAlivePeriod)
 	}
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS config: %w", err)
+		}
+		serverOpts = serverOpts.SetTLSConfig(tlsConfig)
+	}
 	opts = opts.SetServerOptions(serverOpts)
 
 	// Set protobuf iterator options.
@@ -106,10 +133,13 @@ func (c *RawTCPServerConfiguration) NewServerOptions(
 	if c.ReadBufferSize != nil {
 		opts = opts.SetReadBufferSize(*c.ReadBufferSize)
 	}
+	if c.PassthroughBatchSize != nil {
+		opts = opts.SetPassthroughBatchSize(*c.PassthroughBatchSize)
+	}
 	if c.ErrorLogLimitPerSecond != nil {
 		opts = opts.SetErrorLogLimitPerSecond(*c.ErrorLogLimitPerSecond)
 	}
-	return opts
+	return opts, nil
 }
 
 // protobufUnaggregatedIteratorConfiguration contains configuration for protobuf unaggregated iterator.
@@ -170,3 +200,9 @@ func (c *HTTPServerConfiguration) NewServerOptions() http.Options {
 	}
 	return opts
 }
+
+// GRPCServerConfiguration contains the gRPC admin server configuration.
+type GRPCServerConfiguration struct {
+	// GRPC server listening address.
+	ListenAddress string `yaml:"listenAddress" validate:"nonzero"`
+}
@@ -21,6 +21,8 @@
 package serve
 
 import (
+	"github.com/m3db/m3/src/aggregator/aggregator/handler/writer"
+	grpcserver "github.com/m3db/m3/src/aggregator/server/grpc"
 	httpserver "github.com/m3db/m3/src/aggregator/server/http"
 	m3msgserver "github.com/m3db/m3/src/aggregator/server/m3msg"
 	rawtcpserver "github.com/m3db/m3/src/aggregator/server/rawtcp"
@@ -66,6 +68,18 @@ type Options interface {
 	// HTTPServerOpts returns the HTTPServerOpts.
 	HTTPServerOpts() httpserver.Options
 
+	// SetGRPCAddr sets the gRPC admin address.
+	SetGRPCAddr(value string) Options
+
+	// GRPCAddr returns the gRPC admin address.
+	GRPCAddr() string
+
+	// SetGRPCServerOpts sets the GRPCServerOpts.
+	SetGRPCServerOpts(value grpcserver.Options) Options
+
+	// GRPCServerOpts returns the GRPCServerOpts.
+	GRPCServerOpts() grpcserver.Options
+
 	// SetInstrumentOpts sets the InstrumentOpts.
 	SetInstrumentOpts(value instrument.Options) Options
 
@@ -77,6 +91,14 @@ type Options interface {
 
 	// RWOptions returns the RW options.
 	RWOptions() xio.Options
+
+	// SetKafkaProducerFn sets the fn creating the Kafka producers used by
+	// Kafka flush handlers, which defaults to a sarama producer.
+	SetKafkaProducerFn(value writer.KafkaProducerFn) Options
+
+	// KafkaProducerFn returns the fn creating the Kafka producers used by
+	// Kafka flush handlers.
+	KafkaProducerFn() writer.KafkaProducerFn
 }
 
 type options struct {
@@ -86,15 +108,19 @@ type options struct {
 	rawTCPServerOpts rawtcpserver.Options
 	httpAddr         string
 	httpServerOpts   httpserver.Options
+	grpcAddr         string
+	grpcServerOpts   grpcserver.Options
 	iOpts            instrument.Options
 	rwOpts           xio.Options
+	kafkaProducerFn  writer.KafkaProducerFn
 }
 
 // NewOptions creates a new aggregator server options.
 func NewOptions(iOpts instrument.Options) Options {
 	return &options{
-		iOpts:  iOpts,
-		rwOpts: xio.NewOptions(),
+		iOpts:           iOpts,
+		rwOpts:          xio.NewOptions(),
+		kafkaProducerFn: writer.NewSaramaKafkaProducer,
 	}
 }
 
@@ -158,6 +184,26 @@ func (o *options) HTTPServerOpts() httpserver.Options {
 	return o.httpServerOpts
 }
 
+func (o *options) SetGRPCAddr(value string) Options {
+	opts := *o
+	opts.grpcAddr = value
+	return &opts
+}
+
+func (o *options) GRPCAddr() string {
+	return o.grpcAddr
+}
+
+func (o *options) SetGRPCServerOpts(value grpcserver.Options) Options {
+	opts := *o
+	opts.grpcServerOpts = value
+	return &opts
+}
+
+func (o *options) GRPCServerOpts() grpcserver.Options {
+	return o.grpcServerOpts
+}
+
 func (o *options) SetInstrumentOpts(value instrument.Options) Options {
 	opts := *o
 	opts.iOpts = value
@@ -177,3 +223,13 @@ func (o *options) SetRWOptions(value xio.Options) Options {
 func (o *options) RWOptions() xio.Options {
 	return o.rwOpts
 }
+
+func (o *options) SetKafkaProducerFn(value writer.KafkaProducerFn) Options {
+	opts := *o
+	opts.kafkaProducerFn = value
+	return &opts
+}
+
+func (o *options) KafkaProducerFn() writer.KafkaProducerFn {
+	return o.kafkaProducerFn
+}
@@ -24,10 +24,12 @@ import (
 	"fmt"
 
 	"github.com/m3db/m3/src/aggregator/aggregator"
+	grpcserver "github.com/m3db/m3/src/aggregator/server/grpc"
 	httpserver "github.com/m3db/m3/src/aggregator/server/http"
 	m3msgserver "github.com/m3db/m3/src/aggregator/server/m3msg"
 	rawtcpserver "github.com/m3db/m3/src/aggregator/server/rawtcp"
 	xdebug "github.com/m3db/m3/src/x/debug"
+	xlog "github.com/m3db/m3/src/x/log"
 
 	"go.uber.org/zap"
 )
@@ -68,6 +70,7 @@ func Serve(
 	if httpAddr := opts.HTTPAddr(); httpAddr != "" {
 		serverOpts := opts.HTTPServerOpts()
 		xdebug.RegisterPProfHandlers(serverOpts.Mux())
+		xlog.RegisterLevelHandler(serverOpts.Mux(), log)
 		httpServer := httpserver.NewServer(httpAddr, aggregator, serverOpts, iOpts)
 		if err := httpServer.ListenAndServe(); err != nil {
 			return fmt.Errorf("could not start http server at: addr=%s, err=%v", httpAddr, err)
@@ -76,6 +79,16 @@ func Serve(
 		log.Info("http server listening", zap.String("addr", httpAddr))
 	}
 
+	if grpcAddr := opts.GRPCAddr(); grpcAddr != "" {
+		serverOpts := opts.GRPCServerOpts()
+		grpcServer := grpcserver.NewServer(grpcAddr, aggregator, serverOpts)
+		if err := grpcServer.ListenAndServe(); err != nil {
+			return fmt.Errorf("could not start grpc server at: addr=%s, err=%v", grpcAddr, err)
+		}
+		defer grpcServer.Close()
+		log.Info("grpc server listening", zap.String("addr", grpcAddr))
+	}
+
 	// Wait for exit signal.
 	<-doneCh
 
@@ -101,3 +101,16 @@ func (d *asyncDownsampler) Enabled() bool {
 	}
 	return d.downsampler.Enabled()
 }
+
+func (d *asyncDownsampler) EvaluateTagsFilter(
+	filter string,
+	sampleSize int,
+	exampleLimit int,
+) (TagsFilterEvaluation, error) {
+	d.RLock()
+	defer d.RUnlock()
+	if d.err != nil {
+		return TagsFilterEvaluation{}, d.err
+	}
+	return d.downsampler.EvaluateTagsFilter(filter, sampleSize, exampleLimit)
+}
@@ -27,6 +27,7 @@ package downsample
 import (
 	"reflect"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
@@ -69,6 +70,21 @@ func (mr *MockDownsamplerMockRecorder) Enabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockDownsampler)(nil).Enabled))
 }
 
+// EvaluateTagsFilter mocks base method.
+func (m *MockDownsampler) EvaluateTagsFilter(arg0 string, arg1, arg2 int) (TagsFilterEvaluation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateTagsFilter", arg0, arg1, arg2)
+	ret0, _ := ret[0].(TagsFilterEvaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateTagsFilter indicates an expected call of EvaluateTagsFilter.
+func (mr *MockDownsamplerMockRecorder) EvaluateTagsFilter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateTagsFilter", reflect.TypeOf((*MockDownsampler)(nil).EvaluateTagsFilter), arg0, arg1, arg2)
+}
+
 // NewMetricsAppender mocks base method.
 func (m *MockDownsampler) NewMetricsAppender() (MetricsAppender, error) {
 	m.ctrl.T.Helper()
@@ -182,85 +198,85 @@ func (m *MockSamplesAppender) EXPECT() *MockSamplesAppenderMockRecorder {
 }
 
 // AppendCounterSample mocks base method.
-func (m *MockSamplesAppender) AppendCounterSample(arg0 time.UnixNano, arg1 int64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendCounterSample(arg0 time.UnixNano, arg1 int64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendCounterSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendCounterSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendCounterSample indicates an expected call of AppendCounterSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendCounterSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendCounterSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendCounterSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendCounterSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendCounterSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendCounterSample), arg0, arg1, arg2, arg3)
 }
 
 // AppendGaugeSample mocks base method.
-func (m *MockSamplesAppender) AppendGaugeSample(arg0 time.UnixNano, arg1 float64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendGaugeSample(arg0 time.UnixNano, arg1 float64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendGaugeSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendGaugeSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendGaugeSample indicates an expected call of AppendGaugeSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendGaugeSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendGaugeSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendGaugeSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendGaugeSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendGaugeSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendGaugeSample), arg0, arg1, arg2, arg3)
 }
 
 // AppendTimerSample mocks base method.
-func (m *MockSamplesAppender) AppendTimerSample(arg0 time.UnixNano, arg1 float64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendTimerSample(arg0 time.UnixNano, arg1 float64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendTimerSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendTimerSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendTimerSample indicates an expected call of AppendTimerSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendTimerSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendTimerSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendTimerSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendTimerSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendTimerSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendTimerSample), arg0, arg1, arg2, arg3)
 }
 
 // AppendUntimedCounterSample mocks base method.
-func (m *MockSamplesAppender) AppendUntimedCounterSample(arg0 time.UnixNano, arg1 int64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendUntimedCounterSample(arg0 time.UnixNano, arg1 int64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendUntimedCounterSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendUntimedCounterSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendUntimedCounterSample indicates an expected call of AppendUntimedCounterSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendUntimedCounterSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendUntimedCounterSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedCounterSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedCounterSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedCounterSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedCounterSample), arg0, arg1, arg2, arg3)
 }
 
 // AppendUntimedGaugeSample mocks base method.
-func (m *MockSamplesAppender) AppendUntimedGaugeSample(arg0 time.UnixNano, arg1 float64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendUntimedGaugeSample(arg0 time.UnixNano, arg1 float64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendUntimedGaugeSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendUntimedGaugeSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendUntimedGaugeSample indicates an expected call of AppendUntimedGaugeSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendUntimedGaugeSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendUntimedGaugeSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedGaugeSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedGaugeSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedGaugeSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedGaugeSample), arg0, arg1, arg2, arg3)
 }
 
 // AppendUntimedTimerSample mocks base method.
-func (m *MockSamplesAppender) AppendUntimedTimerSample(arg0 time.UnixNano, arg1 float64, arg2 []byte) error {
+func (m *MockSamplesAppender) AppendUntimedTimerSample(arg0 time.UnixNano, arg1 float64, arg2 []byte, arg3 []metric.Exemplar) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppendUntimedTimerSample", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AppendUntimedTimerSample", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AppendUntimedTimerSample indicates an expected call of AppendUntimedTimerSample.
-func (mr *MockSamplesAppenderMockRecorder) AppendUntimedTimerSample(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockSamplesAppenderMockRecorder) AppendUntimedTimerSample(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedTimerSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedTimerSample), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUntimedTimerSample", reflect.TypeOf((*MockSamplesAppender)(nil).AppendUntimedTimerSample), arg0, arg1, arg2, arg3)
 }
@@ -23,7 +23,10 @@ package downsample
 import (
 	"sync"
 
+	"github.com/m3db/m3/src/metrics/filters"
 	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
 	"github.com/m3db/m3/src/query/ts"
@@ -40,6 +43,25 @@ type Downsampler interface {
 	// downsampler is enabled if there are aggregated ClusterNamespaces
 	// that exist as downsampling only applies to aggregations.
 	Enabled() bool
+	// EvaluateTagsFilter evaluates a rule tags filter against up to sampleSize
+	// of the metric IDs most recently matched by the downsampler, returning
+	// at most exampleLimit of the matched metrics as examples.
+	EvaluateTagsFilter(
+		filter string,
+		sampleSize int,
+		exampleLimit int,
+	) (TagsFilterEvaluation, error)
+}
+
+// TagsFilterEvaluation is the result of evaluating a rule tags filter
+// against a sample of recently seen metric IDs.
+type TagsFilterEvaluation struct {
+	// Sampled is the number of metric IDs the filter was evaluated against.
+	Sampled int
+	// Matched is the number of sampled metric IDs that matched the filter.
+	Matched int
+	// Examples are the tags of a subset of the matched metric IDs.
+	Examples []models.Tags
 }
 
 // MetricsAppender is a metrics appender that can build a samples
@@ -82,13 +104,26 @@ type SamplesAppenderOverrideRules struct {
 // that can only be called by a single caller at a time.
 // The client timestamp provided to Untimed methods is only used to monitor ingestion latency on the server. It is
 // dropped and a server-side timestamp is used for the metric.
+// The exemplars, if any, are retained by the aggregations of the sample.
 type SamplesAppender interface {
-	AppendUntimedCounterSample(t xtime.UnixNano, value int64, annotation []byte) error
-	AppendUntimedGaugeSample(t xtime.UnixNano, value float64, annotation []byte) error
-	AppendUntimedTimerSample(t xtime.UnixNano, value float64, annotation []byte) error
-	AppendCounterSample(t xtime.UnixNano, value int64, annotation []byte) error
-	AppendGaugeSample(t xtime.UnixNano, value float64, annotation []byte) error
-	AppendTimerSample(t xtime.UnixNano, value float64, annotation []byte) error
+	AppendUntimedCounterSample(
+		t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
+	) error
+	AppendUntimedGaugeSample(
+		t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+	) error
+	AppendUntimedTimerSample(
+		t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+	) error
+	AppendCounterSample(
+		t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
+	) error
+	AppendGaugeSample(
+		t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+	) error
+	AppendTimerSample(
+		t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+	) error
 }
 
 type downsampler struct {
@@ -169,6 +204,57 @@ func (d *downsampler) Enabled() bool {
 	return d.enabled
 }
 
+func (d *downsampler) EvaluateTagsFilter(
+	filter string,
+	sampleSize int,
+	exampleLimit int,
+) (TagsFilterEvaluation, error) {
+	filterValues, err := filters.ValidateTagsFilter(filter)
+	if err != nil {
+		return TagsFilterEvaluation{}, err
+	}
+
+	tagsFilter, err := filters.NewTagsFilter(filterValues, filters.Conjunction,
+		d.agg.tagsFilterOpts)
+	if err != nil {
+		return TagsFilterEvaluation{}, err
+	}
+
+	ids := d.agg.matcher.RecentIDs(sampleSize)
+	result := TagsFilterEvaluation{Sampled: len(ids)}
+	for _, id := range ids {
+		if !tagsFilter.Matches(id) {
+			continue
+		}
+
+		result.Matched++
+		if len(result.Examples) >= exampleLimit {
+			continue
+		}
+
+		tags, err := d.decodeTags(id)
+		if err != nil {
+			return TagsFilterEvaluation{}, err
+		}
+		result.Examples = append(result.Examples, tags)
+	}
+
+	return result, nil
+}
+
+func (d *downsampler) decodeTags(id []byte) (models.Tags, error) {
+	iter := d.agg.pools.metricTagsIteratorPool.Get()
+	iter.Reset(id)
+	defer iter.Close()
+
+	tags := models.NewTags(iter.NumTags(), d.opts.TagOptions)
+	for iter.Next() {
+		name, value := iter.Current()
+		tags = tags.AddTag(models.Tag{Name: name, Value: value}.Clone())
+	}
+	return tags, iter.Err()
+}
+
 func (d *downsampler) OnUpdate(namespaces m3.ClusterNamespaces) {
 	logger := d.opts.InstrumentOptions.Logger()
 
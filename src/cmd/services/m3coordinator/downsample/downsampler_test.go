@@ -2760,6 +2760,66 @@ func TestDownsamplerAggregationWithRemoteAggregatorClient(t *testing.T) {
 	testDownsamplerRemoteAggregation(t, testDownsampler)
 }
 
+func TestDownsamplerEvaluateTagsFilter(t *testing.T) {
+	t.Parallel()
+
+	cacheCapacity := 10
+	testDownsampler := newTestDownsampler(t, testDownsamplerOptions{
+		matcherConfig: MatcherConfiguration{
+			Cache: MatcherCacheConfiguration{Capacity: &cacheCapacity},
+		},
+		rulesConfig: &RulesConfiguration{
+			MappingRules: []MappingRuleConfiguration{
+				{
+					Filter:       "app:nginx*",
+					Aggregations: []aggregation.Type{aggregation.Max},
+					StoragePolicies: []StoragePolicyConfiguration{
+						{
+							Resolution: 1 * time.Second,
+							Retention:  30 * 24 * time.Hour,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	now := time.Now().UnixNano()
+	for _, tags := range []map[string]string{
+		{nameTag: "foo_metric", "app": "nginx_edge"},
+		{nameTag: "bar_metric", "app": "nginx_origin"},
+		{nameTag: "baz_metric", "app": "envoy"},
+	} {
+		testDownsampler.matcher.ForwardMatch(newTestID(t, tags), now, now+1)
+	}
+
+	result, err := testDownsampler.downsampler.EvaluateTagsFilter("app:nginx*", 10, 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Sampled)
+	require.Equal(t, 2, result.Matched)
+	require.Equal(t, 1, len(result.Examples))
+
+	// Most recently matched IDs are evaluated first.
+	example := make(map[string]string)
+	for _, tag := range result.Examples[0].Tags {
+		example[string(tag.Name)] = string(tag.Value)
+	}
+	require.Equal(t, map[string]string{
+		nameTag: "bar_metric",
+		"app":   "nginx_origin",
+	}, example)
+
+	// Only the two most recently matched IDs are evaluated.
+	result, err = testDownsampler.downsampler.EvaluateTagsFilter("app:nginx*", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Sampled)
+	require.Equal(t, 1, result.Matched)
+	require.Equal(t, 0, len(result.Examples))
+
+	_, err = testDownsampler.downsampler.EvaluateTagsFilter("app:[nginx", 10, 1)
+	require.Error(t, err)
+}
+
 func TestDownsamplerWithOverrideNamespace(t *testing.T) {
 	overrideNamespaceTag := "override_namespace_tag"
 
@@ -3278,7 +3338,7 @@ func testDownsamplerAggregationIngest(
 
 		samplesAppender := samplesAppenderResult.SamplesAppender
 		for _, sample := range metric.samples {
-			err = samplesAppender.AppendUntimedCounterSample(xtime.Now(), sample, nil)
+			err = samplesAppender.AppendUntimedCounterSample(xtime.Now(), sample, nil, nil)
 			require.NoError(t, err)
 		}
 		for _, sample := range metric.timedSamples {
@@ -3292,9 +3352,9 @@ func testDownsamplerAggregationIngest(
 				time.Sleep(sample.offset)
 			}
 			if samplesAppenderResult.ShouldDropTimestamp {
-				err = samplesAppender.AppendUntimedCounterSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendUntimedCounterSample(sample.time, sample.value, nil, nil)
 			} else {
-				err = samplesAppender.AppendCounterSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendCounterSample(sample.time, sample.value, nil, nil)
 			}
 			require.NoError(t, err)
 		}
@@ -3315,7 +3375,7 @@ func testDownsamplerAggregationIngest(
 
 		samplesAppender := samplesAppenderResult.SamplesAppender
 		for _, sample := range metric.samples {
-			err = samplesAppender.AppendUntimedGaugeSample(xtime.Now(), sample, nil)
+			err = samplesAppender.AppendUntimedGaugeSample(xtime.Now(), sample, nil, nil)
 			require.NoError(t, err)
 		}
 		for _, sample := range metric.timedSamples {
@@ -3329,9 +3389,9 @@ func testDownsamplerAggregationIngest(
 				time.Sleep(sample.offset)
 			}
 			if samplesAppenderResult.ShouldDropTimestamp {
-				err = samplesAppender.AppendUntimedGaugeSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendUntimedGaugeSample(sample.time, sample.value, nil, nil)
 			} else {
-				err = samplesAppender.AppendGaugeSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendGaugeSample(sample.time, sample.value, nil, nil)
 			}
 			require.NoError(t, err)
 		}
@@ -3354,7 +3414,7 @@ func testDownsamplerAggregationIngest(
 
 		samplesAppender := samplesAppenderResult.SamplesAppender
 		for _, sample := range metric.samples {
-			err = samplesAppender.AppendUntimedTimerSample(xtime.Now(), sample, nil)
+			err = samplesAppender.AppendUntimedTimerSample(xtime.Now(), sample, nil, nil)
 			require.NoError(t, err)
 		}
 		for _, sample := range metric.timedSamples {
@@ -3368,9 +3428,9 @@ func testDownsamplerAggregationIngest(
 				time.Sleep(sample.offset)
 			}
 			if samplesAppenderResult.ShouldDropTimestamp {
-				err = samplesAppender.AppendUntimedTimerSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendUntimedTimerSample(sample.time, sample.value, nil, nil)
 			} else {
-				err = samplesAppender.AppendTimerSample(sample.time, sample.value, nil)
+				err = samplesAppender.AppendTimerSample(sample.time, sample.value, nil, nil)
 			}
 			require.NoError(t, err)
 		}
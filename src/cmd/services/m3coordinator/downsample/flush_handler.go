@@ -49,8 +49,37 @@ var (
 	// GraphiteIDSchemeTagValue specifies that the graphite ID
 	// scheme should be used for a metric.
 	GraphiteIDSchemeTagValue = []byte("graphite")
+	// GraphiteTaggedIDSchemeTagValue specifies that the tagged graphite ID
+	// scheme should be used for a metric.
+	GraphiteTaggedIDSchemeTagValue = []byte("graphite_tagged")
 )
 
+// IDSchemeTagValue returns the value of the ID scheme meta tag for metrics
+// using the given ID scheme, and false if the scheme does not need a tag.
+func IDSchemeTagValue(scheme models.IDSchemeType) ([]byte, bool) {
+	switch scheme {
+	case models.TypeGraphite:
+		return GraphiteIDSchemeTagValue, true
+	case models.TypeGraphiteTagged:
+		return GraphiteTaggedIDSchemeTagValue, true
+	default:
+		return nil, false
+	}
+}
+
+// IDSchemeFromTagValue returns the ID scheme for the value of the ID scheme
+// meta tag, and false if the value is not recognized.
+func IDSchemeFromTagValue(value []byte) (models.IDSchemeType, bool) {
+	switch {
+	case bytes.Equal(value, GraphiteIDSchemeTagValue):
+		return models.TypeGraphite, true
+	case bytes.Equal(value, GraphiteTaggedIDSchemeTagValue):
+		return models.TypeGraphiteTagged, true
+	default:
+		return models.TypeDefault, false
+	}
+}
+
 var (
 	aggregationSuffixTag = []byte("agg")
 )
@@ -146,10 +175,10 @@ func (w *downsamplerFlushHandlerWriter) Write(
 			// other path where flows back to the coordinator from the aggregator
 			// and this tag is interpreted, eventually need to handle more cleanly.
 			if bytes.Equal(name, MetricsOptionIDSchemeTagName) {
-				if bytes.Equal(value, GraphiteIDSchemeTagValue) &&
-					tags.Opts.IDSchemeType() != models.TypeGraphite {
+				if scheme, ok := IDSchemeFromTagValue(value); ok &&
+					tags.Opts.IDSchemeType() != scheme {
 					iter.Reset(mp.ChunkedID.Data)
-					tags.Opts = w.tagOptions.SetIDSchemeType(models.TypeGraphite)
+					tags.Opts = w.tagOptions.SetIDSchemeType(scheme)
 					tags.Tags = tags.Tags[:0]
 				}
 				// Continue, whether we updated and need to restart iteration,
@@ -180,6 +209,7 @@ func (w *downsamplerFlushHandlerWriter) Write(
 			}},
 			Unit:       convert.UnitForM3DB(mp.StoragePolicy.Resolution().Precision),
 			Annotation: mp.Annotation,
+			Exemplars:  mp.Exemplars,
 			Attributes: storagemetadata.Attributes{
 				MetricsType: storagemetadata.AggregatedMetricsType,
 				Retention:   mp.StoragePolicy.Retention().Duration(),
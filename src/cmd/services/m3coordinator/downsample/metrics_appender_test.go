@@ -138,7 +138,7 @@ func TestSamplesAppenderPoolResetsTagsAcrossSamples(t *testing.T) {
 			},
 		)
 
-		require.NoError(t, a.SamplesAppender.AppendUntimedCounterSample(xtime.Now(), int64(i), nil))
+		require.NoError(t, a.SamplesAppender.AppendUntimedCounterSample(xtime.Now(), int64(i), nil, nil))
 
 		assert.False(t, a.IsDropPolicyApplied)
 		appender.Finalize()
@@ -236,6 +236,7 @@ type agg struct {
 
 	clockOpts      clock.Options
 	matcher        matcher.Matcher
+	tagsFilterOpts filters.TagsFilterOptions
 	pools          aggPools
 	untimedRollups bool
 }
@@ -833,6 +834,7 @@ func (cfg Configuration) newAggregator(o DownsamplerOptions) (agg, error) {
 		return agg{
 			clientRemote:   client,
 			matcher:        matcher,
+			tagsFilterOpts: ruleSetOpts.TagsFilterOptions(),
 			pools:          pools,
 			untimedRollups: cfg.UntimedRollups,
 		}, nil
@@ -973,6 +975,7 @@ func (cfg Configuration) newAggregator(o DownsamplerOptions) (agg, error) {
 	return agg{
 		aggregator:     aggregatorInstance,
 		matcher:        matcher,
+		tagsFilterOpts: ruleSetOpts.TagsFilterOptions(),
 		pools:          pools,
 		untimedRollups: cfg.UntimedRollups,
 	}, nil
@@ -1220,6 +1223,14 @@ func (c *aggregatorLocalAdminClient) WriteUntimedGauge(
 	return c.agg.AddUntimed(gauge.ToUnion(), metadatas)
 }
 
+// WriteUntimedHistogram writes untimed histogram metrics.
+func (c *aggregatorLocalAdminClient) WriteUntimedHistogram(
+	histogram unaggregated.Histogram,
+	metadatas metadata.StagedMetadatas,
+) error {
+	return c.agg.AddUntimed(histogram.ToUnion(), metadatas)
+}
+
 // WriteTimed writes timed metrics.
 func (c *aggregatorLocalAdminClient) WriteTimed(
 	metric aggregated.Metric,
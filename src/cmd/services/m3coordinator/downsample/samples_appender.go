@@ -49,7 +49,9 @@ type samplesAppender struct {
 var _ SamplesAppender = (*samplesAppender)(nil)
 
 // nolint:dupl
-func (a samplesAppender) AppendUntimedCounterSample(t xtime.UnixNano, value int64, annotation []byte) error {
+func (a samplesAppender) AppendUntimedCounterSample(
+	t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	a.emitMetrics()
 	if a.clientRemote != nil {
 		// Remote client write instead of local aggregation.
@@ -57,6 +59,7 @@ func (a samplesAppender) AppendUntimedCounterSample(t xtime.UnixNano, value int6
 			ID:              a.unownedID,
 			Value:           value,
 			Annotation:      annotation,
+			Exemplars:       exemplars,
 			ClientTimeNanos: t,
 		}
 		return a.clientRemote.WriteUntimedCounter(sample, a.stagedMetadatas)
@@ -67,12 +70,15 @@ func (a samplesAppender) AppendUntimedCounterSample(t xtime.UnixNano, value int6
 		ID:         a.unownedID,
 		CounterVal: value,
 		Annotation: annotation,
+		Exemplars:  exemplars,
 	}
 	return a.agg.AddUntimed(sample, a.stagedMetadatas)
 }
 
 // nolint:dupl
-func (a samplesAppender) AppendUntimedGaugeSample(t xtime.UnixNano, value float64, annotation []byte) error {
+func (a samplesAppender) AppendUntimedGaugeSample(
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	a.emitMetrics()
 	if a.clientRemote != nil {
 		// Remote client write instead of local aggregation.
@@ -80,6 +86,7 @@ func (a samplesAppender) AppendUntimedGaugeSample(t xtime.UnixNano, value float6
 			ID:              a.unownedID,
 			Value:           value,
 			Annotation:      annotation,
+			Exemplars:       exemplars,
 			ClientTimeNanos: t,
 		}
 		return a.clientRemote.WriteUntimedGauge(sample, a.stagedMetadatas)
@@ -90,11 +97,14 @@ func (a samplesAppender) AppendUntimedGaugeSample(t xtime.UnixNano, value float6
 		ID:         a.unownedID,
 		GaugeVal:   value,
 		Annotation: annotation,
+		Exemplars:  exemplars,
 	}
 	return a.agg.AddUntimed(sample, a.stagedMetadatas)
 }
 
-func (a samplesAppender) AppendUntimedTimerSample(t xtime.UnixNano, value float64, annotation []byte) error {
+func (a samplesAppender) AppendUntimedTimerSample(
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	a.emitMetrics()
 	if a.clientRemote != nil {
 		// Remote client write instead of local aggregation.
@@ -102,6 +112,7 @@ func (a samplesAppender) AppendUntimedTimerSample(t xtime.UnixNano, value float6
 			ID:              a.unownedID,
 			Values:          []float64{value},
 			Annotation:      annotation,
+			Exemplars:       exemplars,
 			ClientTimeNanos: t,
 		}
 		return a.clientRemote.WriteUntimedBatchTimer(sample, a.stagedMetadatas)
@@ -112,32 +123,39 @@ func (a samplesAppender) AppendUntimedTimerSample(t xtime.UnixNano, value float6
 		ID:            a.unownedID,
 		BatchTimerVal: []float64{value},
 		Annotation:    annotation,
+		Exemplars:     exemplars,
 	}
 	return a.agg.AddUntimed(sample, a.stagedMetadatas)
 }
 
-func (a *samplesAppender) AppendCounterSample(t xtime.UnixNano, value int64, annotation []byte) error {
+func (a *samplesAppender) AppendCounterSample(
+	t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	return a.appendTimedSample(aggregated.Metric{
 		Type:       metric.CounterType,
 		ID:         a.unownedID,
 		TimeNanos:  int64(t),
 		Value:      float64(value),
 		Annotation: annotation,
+		Exemplars:  exemplars,
 	})
 }
 
-func (a *samplesAppender) AppendGaugeSample(t xtime.UnixNano, value float64, annotation []byte) error {
+func (a *samplesAppender) AppendGaugeSample(
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	return a.appendTimedSample(aggregated.Metric{
 		Type:       metric.GaugeType,
 		ID:         a.unownedID,
 		TimeNanos:  int64(t),
 		Value:      value,
 		Annotation: annotation,
+		Exemplars:  exemplars,
 	})
 }
 
 func (a *samplesAppender) AppendTimerSample(
-	t xtime.UnixNano, value float64, annotation []byte,
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
 ) error {
 	return a.appendTimedSample(aggregated.Metric{
 		Type:       metric.TimerType,
@@ -145,6 +163,7 @@ func (a *samplesAppender) AppendTimerSample(
 		TimeNanos:  int64(t),
 		Value:      value,
 		Annotation: annotation,
+		Exemplars:  exemplars,
 	})
 }
 
@@ -206,56 +225,62 @@ func (a *multiSamplesAppender) addSamplesAppender(v samplesAppender) {
 	a.appenders = append(a.appenders, v)
 }
 
-func (a *multiSamplesAppender) AppendUntimedCounterSample(t xtime.UnixNano, value int64, annotation []byte) error {
+func (a *multiSamplesAppender) AppendUntimedCounterSample(
+	t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendUntimedCounterSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendUntimedCounterSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
 
-func (a *multiSamplesAppender) AppendUntimedGaugeSample(t xtime.UnixNano, value float64, annotation []byte) error {
+func (a *multiSamplesAppender) AppendUntimedGaugeSample(
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendUntimedGaugeSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendUntimedGaugeSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
 
-func (a *multiSamplesAppender) AppendUntimedTimerSample(t xtime.UnixNano, value float64, annotation []byte) error {
+func (a *multiSamplesAppender) AppendUntimedTimerSample(
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
+) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendUntimedTimerSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendUntimedTimerSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
 
 func (a *multiSamplesAppender) AppendCounterSample(
-	t xtime.UnixNano, value int64, annotation []byte,
+	t xtime.UnixNano, value int64, annotation []byte, exemplars []metric.Exemplar,
 ) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendCounterSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendCounterSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
 
 func (a *multiSamplesAppender) AppendGaugeSample(
-	t xtime.UnixNano, value float64, annotation []byte,
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
 ) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendGaugeSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendGaugeSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
 
 func (a *multiSamplesAppender) AppendTimerSample(
-	t xtime.UnixNano, value float64, annotation []byte,
+	t xtime.UnixNano, value float64, annotation []byte, exemplars []metric.Exemplar,
 ) error {
 	var multiErr xerrors.MultiError
 	for _, appender := range a.appenders {
-		multiErr = multiErr.Add(appender.AppendTimerSample(t, value, annotation))
+		multiErr = multiErr.Add(appender.AppendTimerSample(t, value, annotation, exemplars))
 	}
 	return multiErr.LastError()
 }
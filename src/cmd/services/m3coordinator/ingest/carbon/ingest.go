@@ -64,8 +64,10 @@ var (
 	// Used for parsing carbon names into tags.
 	carbonSeparatorByte  = byte('.')
 	carbonSeparatorBytes = []byte{carbonSeparatorByte}
+	taggedSeparatorBytes = []byte{models.GraphiteTaggedSeparator}
 
 	errCannotGenerateTagsFromEmptyName = errors.New("cannot generate tags from empty name")
+	errCannotGenerateTagsFromEmptyPath = errors.New("cannot generate tags from tagged name with empty path")
 	errIOptsMustBeSet                  = errors.New("carbon ingester options: instrument options must be st")
 	errWorkerPoolMustBeSet             = errors.New("carbon ingester options: worker pool must be set")
 )
@@ -136,6 +138,7 @@ func NewIngester(
 		opts:                 opts,
 		logger:               opts.InstrumentOptions.Logger(),
 		tagOpts:              tagOpts,
+		taggedTagOpts:        tagOpts.SetIDSchemeType(models.TypeGraphiteTagged),
 		metrics:              metrics,
 		lineResourcesPool:    resourcePool,
 	}
@@ -152,6 +155,7 @@ type ingester struct {
 	logger               *zap.Logger
 	metrics              carbonIngesterMetrics
 	tagOpts              models.TagOptions
+	taggedTagOpts        models.TagOptions
 
 	lineResourcesPool pool.ObjectPool
 
@@ -423,7 +427,11 @@ func (i *ingester) writeWithOptions(
 	opts ingest.WriteOptions,
 ) error {
 	resources.datapoints[0] = ts.Datapoint{Timestamp: timestamp, Value: value}
-	tags, err := GenerateTagsFromNameIntoSlice(resources.name, i.tagOpts, resources.tags)
+	tagOpts := i.tagOpts
+	if bytes.IndexByte(resources.name, models.GraphiteTaggedSeparator) >= 0 {
+		tagOpts = i.taggedTagOpts
+	}
+	tags, err := GenerateTagsFromNameIntoSlice(resources.name, tagOpts, resources.tags)
 	if err != nil {
 		i.logger.Error("err generating tags from carbon",
 			zap.String("name", string(resources.name)), zap.Error(err))
@@ -476,6 +484,13 @@ func newCarbonIngesterMetrics(scope tally.Scope) (carbonIngesterMetrics, error)
 //      __g0__:foo
 //      __g1__:bar
 //      __g2__:baz
+// Tagged series names are also supported such that an input like:
+//      foo.bar;dc=east;env=prod
+// becomes
+//      __g0__:foo
+//      __g1__:bar
+//      dc:east
+//      env:prod
 func GenerateTagsFromName(
 	name []byte,
 	opts models.TagOptions,
@@ -502,7 +517,18 @@ func generateTagsFromName(
 		return models.EmptyTags(), errCannotGenerateTagsFromEmptyName
 	}
 
+	var taggedTags []byte
+	if idx := bytes.IndexByte(name, models.GraphiteTaggedSeparator); idx >= 0 {
+		if idx == 0 {
+			return models.EmptyTags(), errCannotGenerateTagsFromEmptyPath
+		}
+		name, taggedTags = name[:idx], name[idx+1:]
+	}
+
 	numTags := bytes.Count(name, carbonSeparatorBytes) + 1
+	if len(taggedTags) > 0 {
+		numTags += bytes.Count(taggedTags, taggedSeparatorBytes) + 1
+	}
 
 	if cap(tags) >= numTags {
 		tags = tags[:0]
@@ -544,7 +570,43 @@ func generateTagsFromName(
 		})
 	}
 
-	return models.Tags{Opts: opts, Tags: tags}, nil
+	if len(taggedTags) == 0 {
+		return models.Tags{Opts: opts, Tags: tags}, nil
+	}
+
+	for len(taggedTags) > 0 {
+		var tag []byte
+		if idx := bytes.IndexByte(taggedTags, models.GraphiteTaggedSeparator); idx >= 0 {
+			tag, taggedTags = taggedTags[:idx], taggedTags[idx+1:]
+		} else {
+			tag, taggedTags = taggedTags, nil
+		}
+
+		idx := bytes.IndexByte(tag, models.GraphiteTaggedValueSeparator)
+		if idx <= 0 || idx == len(tag)-1 {
+			return models.EmptyTags(),
+				fmt.Errorf("carbon metric: %s has invalid tag: %s", string(name), string(tag))
+		}
+
+		tagName := tag[:idx]
+		if models.IsGraphitePathTag(tagName) {
+			return models.EmptyTags(),
+				fmt.Errorf("carbon metric: %s has reserved tag name: %s", string(name), string(tagName))
+		}
+
+		tags = append(tags, models.Tag{
+			Name:  tagName,
+			Value: tag[idx+1:],
+		})
+	}
+
+	// NB: only tagged series use the tagged graphite scheme so that the IDs of
+	// series without tags are unchanged, and their tags need to be sorted
+	// amongst the path tags.
+	if opts.IDSchemeType() == models.TypeGraphite {
+		opts = opts.SetIDSchemeType(models.TypeGraphiteTagged)
+	}
+	return models.Tags{Opts: opts, Tags: tags}.Normalize(), nil
 }
 
 // Compile all the carbon ingestion rules into matcher so that we can
@@ -604,6 +604,31 @@ func TestGenerateTagsFromName(t *testing.T) {
 			expectedErr:  fmt.Errorf("carbon metric: foo.bar.baz.. has duplicate separator"),
 			expectedTags: []models.Tag{},
 		},
+		{
+			name: "foo.bar;env=prod;dc=east",
+			id:   "foo.bar;dc=east;env=prod",
+			expectedTags: []models.Tag{
+				{Name: []byte("dc"), Value: []byte("east")},
+				{Name: []byte("env"), Value: []byte("prod")},
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: graphite.TagName(1), Value: []byte("bar")},
+			},
+		},
+		{
+			name:         ";env=prod",
+			expectedErr:  errCannotGenerateTagsFromEmptyPath,
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         "foo.bar;env",
+			expectedErr:  fmt.Errorf("carbon metric: foo.bar has invalid tag: env"),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name:         "foo.bar;__g0__=baz",
+			expectedErr:  fmt.Errorf("carbon metric: foo.bar has reserved tag name: __g0__"),
+			expectedTags: []models.Tag{},
+		},
 	}
 
 	opts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
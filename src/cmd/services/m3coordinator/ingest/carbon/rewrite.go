@@ -21,7 +21,10 @@
 package ingestcarbon
 
 import (
+	"bytes"
+
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/models"
 )
 
 // nolint: gocyclo
@@ -34,6 +37,12 @@ func copyAndRewrite(
 		return append(dst[:0], src...)
 	}
 
+	// Only the path of a tagged series is rewritten, tags are kept as is.
+	var taggedTags []byte
+	if idx := bytes.IndexByte(src, models.GraphiteTaggedSeparator); idx >= 0 {
+		src, taggedTags = src[:idx], src[idx:]
+	}
+
 	// Copy into dst as we rewrite.
 	dst = dst[:0]
 	leadingDots := true
@@ -84,5 +93,5 @@ func copyAndRewrite(
 		// Remove trailing dot.
 		dst = dst[:i]
 	}
-	return dst
+	return append(dst, taggedTags...)
 }
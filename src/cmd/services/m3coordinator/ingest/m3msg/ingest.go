@@ -26,6 +26,7 @@ import (
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/id"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/models"
@@ -126,6 +127,7 @@ func (i *Ingester) Ingest(
 	metricNanos, encodeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
 	sp policy.StoragePolicy,
 	callback m3msg.Callbackable,
 ) {
@@ -135,6 +137,7 @@ func (i *Ingester) Ingest(
 	op.metricNanos = metricNanos
 	op.value = value
 	op.annotation = annotation
+	op.exemplars = exemplars
 	op.sp = sp
 	op.callback = callback
 	i.workers.Go(op.ingestFn)
@@ -157,6 +160,7 @@ type ingestOp struct {
 	metricNanos int64
 	value       float64
 	annotation  []byte
+	exemplars   []metric.Exemplar
 	sp          policy.StoragePolicy
 	callback    m3msg.Callbackable
 	tags        models.Tags
@@ -227,6 +231,7 @@ func (op *ingestOp) resetWriteQuery() error {
 			Retention:   op.sp.Retention().Duration(),
 		},
 		Annotation: op.annotation,
+		Exemplars:  op.exemplars,
 	})
 }
 
@@ -242,12 +247,12 @@ func (op *ingestOp) resetTags() error {
 		// other path where flows back to the coordinator from the aggregator
 		// and this tag is interpreted, eventually need to handle more cleanly.
 		if bytes.Equal(name, downsample.MetricsOptionIDSchemeTagName) {
-			if bytes.Equal(value, downsample.GraphiteIDSchemeTagValue) &&
-				op.tags.Opts.IDSchemeType() != models.TypeGraphite {
+			if scheme, ok := downsample.IDSchemeFromTagValue(value); ok &&
+				op.tags.Opts.IDSchemeType() != scheme {
 				// Restart iteration with graphite tag options parsing
 				op.it.Reset(op.id)
 				op.tags.Tags = op.tags.Tags[:0]
-				op.tags.Opts = op.tags.Opts.SetIDSchemeType(models.TypeGraphite)
+				op.tags.Opts = op.tags.Opts.SetIDSchemeType(scheme)
 			}
 			// Continue, whether we updated and need to restart iteration,
 			// or if passing for the second time
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
@@ -53,6 +54,7 @@ type IterValue struct {
 	Unit       xtime.Unit
 	Metadata   ts.Metadata
 	Annotation []byte
+	Exemplars  []metric.Exemplar
 }
 
 // DownsampleAndWriteIter is an interface that can be implemented to use
@@ -84,6 +86,8 @@ type DownsamplerAndWriter interface {
 	) BatchError
 
 	Storage() storage.Storage
+
+	Downsampler() downsample.Downsampler
 }
 
 // BatchError allows for access to individual errors.
@@ -244,7 +248,7 @@ func (d *downsamplerAndWriter) writeToDownsampler(
 		appender.AddTag(tag.Name, tag.Value)
 	}
 
-	if tags.Opts.IDSchemeType() == models.TypeGraphite {
+	if idSchemeTagValue, ok := downsample.IDSchemeTagValue(tags.Opts.IDSchemeType()); ok {
 		// NB(r): This is gross, but if this is a graphite metric then
 		// we are going to set a special tag that means the downsampler
 		// will write a graphite ID. This should really be plumbed
@@ -255,8 +259,7 @@ func (d *downsamplerAndWriter) writeToDownsampler(
 		// all places worth fixing this hack. There is at least one
 		// other path where flows back to the coordinator from the aggregator
 		// and this tag is interpreted, eventually need to handle more cleanly.
-		appender.AddTag(downsample.MetricsOptionIDSchemeTagName,
-			downsample.GraphiteIDSchemeTagValue)
+		appender.AddTag(downsample.MetricsOptionIDSchemeTagName, idSchemeTagValue)
 	}
 
 	// NB: we don't set series attributes on the sample appender options here.
@@ -278,10 +281,10 @@ func (d *downsamplerAndWriter) writeToDownsampler(
 
 	for _, dp := range datapoints {
 		if result.ShouldDropTimestamp {
-			err = result.SamplesAppender.AppendUntimedGaugeSample(dp.Timestamp, dp.Value, annotation)
+			err = result.SamplesAppender.AppendUntimedGaugeSample(dp.Timestamp, dp.Value, annotation, nil)
 		} else {
 			err = result.SamplesAppender.AppendGaugeSample(
-				dp.Timestamp, dp.Value, annotation,
+				dp.Timestamp, dp.Value, annotation, nil,
 			)
 		}
 		if err != nil {
@@ -419,6 +422,7 @@ func (d *downsamplerAndWriter) WriteBatch(
 						Datapoints: value.Datapoints,
 						Unit:       value.Unit,
 						Annotation: value.Annotation,
+						Exemplars:  value.Exemplars,
 						Attributes: storageAttributesFromPolicy(p),
 					})
 					if err == nil {
@@ -466,7 +470,7 @@ func (d *downsamplerAndWriter) writeAggregatedBatch(
 			appender.AddTag(tag.Name, tag.Value)
 		}
 
-		if value.Tags.Opts.IDSchemeType() == models.TypeGraphite {
+		if idSchemeTagValue, ok := downsample.IDSchemeTagValue(value.Tags.Opts.IDSchemeType()); ok {
 			// NB(r): This is gross, but if this is a graphite metric then
 			// we are going to set a special tag that means the downsampler
 			// will write a graphite ID. This should really be plumbed
@@ -477,8 +481,7 @@ func (d *downsamplerAndWriter) writeAggregatedBatch(
 			// all places worth fixing this hack. There is at least one
 			// other path where flows back to the coordinator from the aggregator
 			// and this tag is interpreted, eventually need to handle more cleanly.
-			appender.AddTag(downsample.MetricsOptionIDSchemeTagName,
-				downsample.GraphiteIDSchemeTagValue)
+			appender.AddTag(downsample.MetricsOptionIDSchemeTagName, idSchemeTagValue)
 		}
 
 		opts := downsample.SampleAppenderOptions{
@@ -503,34 +506,40 @@ func (d *downsamplerAndWriter) writeAggregatedBatch(
 			iter.SetCurrentMetadata(ts.Metadata{DropUnaggregated: true})
 		}
 
+		// The exemplars of the series are only attached to its first sample so
+		// they are not added to the aggregations once per sample.
+		exemplars := value.Exemplars
 		for _, dp := range value.Datapoints {
 			switch value.Attributes.M3Type {
 			case ts.M3MetricTypeGauge:
 				if result.ShouldDropTimestamp {
-					err = result.SamplesAppender.AppendUntimedGaugeSample(dp.Timestamp, dp.Value, value.Annotation)
+					err = result.SamplesAppender.AppendUntimedGaugeSample(
+						dp.Timestamp, dp.Value, value.Annotation, exemplars)
 				} else {
 					err = result.SamplesAppender.AppendGaugeSample(
-						dp.Timestamp, dp.Value, value.Annotation,
+						dp.Timestamp, dp.Value, value.Annotation, exemplars,
 					)
 				}
 			case ts.M3MetricTypeCounter:
 				if result.ShouldDropTimestamp {
 					err = result.SamplesAppender.AppendUntimedCounterSample(
-						dp.Timestamp, int64(dp.Value), value.Annotation)
+						dp.Timestamp, int64(dp.Value), value.Annotation, exemplars)
 				} else {
 					err = result.SamplesAppender.AppendCounterSample(
-						dp.Timestamp, int64(dp.Value), value.Annotation,
+						dp.Timestamp, int64(dp.Value), value.Annotation, exemplars,
 					)
 				}
 			case ts.M3MetricTypeTimer:
 				if result.ShouldDropTimestamp {
-					err = result.SamplesAppender.AppendUntimedTimerSample(dp.Timestamp, dp.Value, value.Annotation)
+					err = result.SamplesAppender.AppendUntimedTimerSample(
+						dp.Timestamp, dp.Value, value.Annotation, exemplars)
 				} else {
 					err = result.SamplesAppender.AppendTimerSample(
-						dp.Timestamp, dp.Value, value.Annotation,
+						dp.Timestamp, dp.Value, value.Annotation, exemplars,
 					)
 				}
 			}
+			exemplars = nil
 			if err != nil {
 				// If we see an error break out so we can try processing the
 				// next datapoint.
@@ -546,6 +555,10 @@ func (d *downsamplerAndWriter) Storage() storage.Storage {
 	return d.store
 }
 
+func (d *downsamplerAndWriter) Downsampler() downsample.Downsampler {
+	return d.downsampler
+}
+
 func storageAttributesFromPolicy(
 	p policy.StoragePolicy,
 ) storagemetadata.Attributes {
@@ -21,11 +21,14 @@
 package m3msg
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/msg/consumer"
 	"github.com/m3db/m3/src/x/instrument"
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/sampler"
 	"github.com/m3db/m3/src/x/server"
 )
 
@@ -62,13 +65,25 @@ func (c Configuration) NewServer(
 	return c.Server.NewServer(
 		h,
 		iOpts.SetMetricsScope(scope),
-	), nil
+	)
 }
 
 type handlerConfiguration struct {
 	// ProtobufDecoderPool configs the protobuf decoder pool.
 	ProtobufDecoderPool pool.ObjectPoolConfiguration `yaml:"protobufDecoderPool"`
 	BlackholePolicies   []policy.StoragePolicy       `yaml:"blackholePolicies"`
+
+	// SplitReassemblyTimeout configs how long fragments of aggregated
+	// metrics split across messages are held waiting for the rest.
+	SplitReassemblyTimeout time.Duration `yaml:"splitReassemblyTimeout"`
+
+	// SplitReassemblyMaxPendingBytes configs the max size of the fragments
+	// of aggregated metrics held waiting for the rest.
+	SplitReassemblyMaxPendingBytes int `yaml:"splitReassemblyMaxPendingBytes"`
+
+	// UnknownFieldsSampleRate configs the fraction of messages checked for
+	// fields unknown to this version of the aggregated metric message.
+	UnknownFieldsSampleRate sampler.Rate `yaml:"unknownFieldsSampleRate"`
 }
 
 func (c handlerConfiguration) newHandler(
@@ -83,8 +98,11 @@ func (c handlerConfiguration) newHandler(
 				"handler": "protobuf",
 			}),
 		),
-		ProtobufDecoderPoolOptions: c.ProtobufDecoderPool.NewObjectPoolOptions(iOpts),
-		BlockholePolicies:          c.BlackholePolicies,
+		ProtobufDecoderPoolOptions:     c.ProtobufDecoderPool.NewObjectPoolOptions(iOpts),
+		BlockholePolicies:              c.BlackholePolicies,
+		SplitReassemblyTimeout:         c.SplitReassemblyTimeout,
+		SplitReassemblyMaxPendingBytes: c.SplitReassemblyMaxPendingBytes,
+		UnknownFieldsSampleRate:        c.UnknownFieldsSampleRate,
 	})
 	return consumer.NewMessageHandler(p, cOpts), nil
 }
@@ -95,8 +113,11 @@ func (c handlerConfiguration) NewOptions(
 	iOpts instrument.Options,
 ) Options {
 	return Options{
-		WriteFn:                    writeFn,
-		InstrumentOptions:          iOpts,
-		ProtobufDecoderPoolOptions: c.ProtobufDecoderPool.NewObjectPoolOptions(iOpts),
+		WriteFn:                        writeFn,
+		InstrumentOptions:              iOpts,
+		ProtobufDecoderPoolOptions:     c.ProtobufDecoderPool.NewObjectPoolOptions(iOpts),
+		SplitReassemblyTimeout:         c.SplitReassemblyTimeout,
+		SplitReassemblyMaxPendingBytes: c.SplitReassemblyMaxPendingBytes,
+		UnknownFieldsSampleRate:        c.UnknownFieldsSampleRate,
 	}
 }
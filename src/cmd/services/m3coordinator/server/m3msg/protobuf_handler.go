@@ -23,12 +23,15 @@ package m3msg
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/msg/consumer"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/sampler"
 
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
@@ -40,13 +43,30 @@ type Options struct {
 	WriteFn                    WriteFn
 	ProtobufDecoderPoolOptions pool.ObjectPoolOptions
 	BlockholePolicies          []policy.StoragePolicy
+
+	// SplitReassemblyTimeout is how long the fragments of an aggregated metric
+	// split across multiple messages are held waiting for the remaining
+	// fragments, defaults to a minute if not set.
+	SplitReassemblyTimeout time.Duration
+
+	// SplitReassemblyMaxPendingBytes bounds the size of the fragments held
+	// waiting for the remaining fragments, defaults to 64MiB if not set.
+	SplitReassemblyMaxPendingBytes int
+
+	// UnknownFieldsSampleRate is the fraction of messages checked for fields
+	// unknown to this version of the aggregated metric message, none are
+	// checked if not set.
+	UnknownFieldsSampleRate sampler.Rate
 }
 
 type handlerMetrics struct {
 	messageReadError             tally.Counter
 	metricAccepted               tally.Counter
+	metricDowngradedResolution   tally.Counter
 	droppedMetricBlackholePolicy tally.Counter
 	droppedMetricDecodeError     tally.Counter
+	metricUnknownFields          tally.Counter
+	metricUnknownFieldsError     tally.Counter
 }
 
 func newHandlerMetrics(scope tally.Scope) handlerMetrics {
@@ -54,6 +74,14 @@ func newHandlerMetrics(scope tally.Scope) handlerMetrics {
 	return handlerMetrics{
 		messageReadError: scope.Counter("message-read-error"),
 		metricAccepted:   messageScope.Counter("accepted"),
+		// Counts metrics emitted by aggregators in the emergency resolution
+		// downgrade mode at a coarser resolution than their storage policy.
+		metricDowngradedResolution: messageScope.Counter("downgraded-resolution"),
+		// Counts fields unknown to this version of the aggregated metric
+		// message, such as fields added by newer aggregators during upgrades,
+		// which are skipped when decoding.
+		metricUnknownFields:      messageScope.Counter("unknown-fields"),
+		metricUnknownFieldsError: messageScope.Counter("unknown-fields-error"),
 		droppedMetricDecodeError: messageScope.Tagged(map[string]string{
 			"reason": "decode-error",
 		}).Counter("dropped"),
@@ -70,6 +98,11 @@ type pbHandler struct {
 	wg      *sync.WaitGroup
 	logger  *zap.Logger
 	m       handlerMetrics
+	splits  *splitReassembler
+
+	// unknownFieldsSampler samples the messages checked for unknown fields,
+	// since checking re-parses the message.
+	unknownFieldsSampler *sampler.Sampler
 
 	// Set of policies for which when we see a metric we drop it on the floor.
 	blackholePolicies []policy.StoragePolicy
@@ -79,16 +112,27 @@ func newProtobufProcessor(opts Options) consumer.MessageProcessor {
 	p := protobuf.NewAggregatedDecoderPool(opts.ProtobufDecoderPoolOptions)
 	p.Init()
 
+	scope := opts.InstrumentOptions.MetricsScope()
+	splits := newSplitReassembler(opts.SplitReassemblyTimeout,
+		opts.SplitReassemblyMaxPendingBytes, time.Now, scope.SubScope("split"))
 	h := &pbHandler{
 		ctx:               context.Background(),
 		writeFn:           opts.WriteFn,
 		pool:              p,
 		wg:                &sync.WaitGroup{},
 		logger:            opts.InstrumentOptions.Logger(),
-		m:                 newHandlerMetrics(opts.InstrumentOptions.MetricsScope()),
+		m:                 newHandlerMetrics(scope),
+		splits:            splits,
 		blackholePolicies: opts.BlockholePolicies,
 	}
 
+	unknownFieldsSampler, err := sampler.NewSampler(opts.UnknownFieldsSampleRate)
+	if err != nil {
+		h.logger.Warn("not checking messages for unknown fields", zap.Error(err))
+	} else {
+		h.unknownFieldsSampler = unknownFieldsSampler
+	}
+
 	if len(opts.BlockholePolicies) > 0 {
 		policyNames := make([]string, 0, len(opts.BlockholePolicies))
 		for _, sp := range h.blackholePolicies {
@@ -107,7 +151,29 @@ func (h *pbHandler) Process(msg consumer.Message) {
 		h.m.droppedMetricDecodeError.Inc(1)
 		return
 	}
+	if split, ok := dec.Split(); ok {
+		// The metric was split across messages, decode it once all of its
+		// fragments have been received.
+		reassembled, ok := h.splits.Add(msg, split)
+		dec.Close()
+		if !ok {
+			return
+		}
+		msg = reassembled
+		dec = h.pool.Get()
+		if err := dec.Decode(msg.Bytes()); err != nil {
+			h.logger.Error("could not decode reassembled metric from messages", zap.Error(err))
+			h.m.droppedMetricDecodeError.Inc(1)
+			return
+		}
+	}
 	h.m.metricAccepted.Inc(1)
+	if dec.DowngradedResolution() > 0 {
+		h.m.metricDowngradedResolution.Inc(1)
+	}
+	if h.unknownFieldsSampler != nil && h.unknownFieldsSampler.Sample() {
+		h.reportUnknownFields(msg.Bytes())
+	}
 
 	h.wg.Add(1)
 	r := NewProtobufCallback(msg, dec, h.wg)
@@ -122,7 +188,18 @@ func (h *pbHandler) Process(msg consumer.Message) {
 		}
 	}
 
-	h.writeFn(h.ctx, dec.ID(), dec.TimeNanos(), dec.EncodeNanos(), dec.Value(), dec.Annotation(), sp, r)
+	h.writeFn(h.ctx, dec.ID(), dec.TimeNanos(), dec.EncodeNanos(), dec.Value(), dec.Annotation(), dec.Exemplars(), sp, r)
+}
+
+func (h *pbHandler) reportUnknownFields(b []byte) {
+	n, err := protobuf.UnknownFields((*metricpb.AggregatedMetric)(nil), b)
+	if err != nil {
+		h.m.metricUnknownFieldsError.Inc(1)
+		return
+	}
+	if n > 0 {
+		h.m.metricUnknownFields.Inc(int64(n))
+	}
 }
 
 func (h *pbHandler) Close() { h.wg.Wait() }
@@ -36,10 +36,13 @@ import (
 	"github.com/m3db/m3/src/msg/generated/proto/msgpb"
 	"github.com/m3db/m3/src/msg/protocol/proto"
 	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/sampler"
 	"github.com/m3db/m3/src/x/server"
 	xtime "github.com/m3db/m3/src/x/time"
 
+	gogoproto "github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -236,6 +239,7 @@ func (m *mockWriter) write(
 	metricNanos, encodeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
 	sp policy.StoragePolicy,
 	callbackable Callbackable,
 ) {
@@ -271,3 +275,51 @@ type payload struct {
 	value       float64
 	sp          policy.StoragePolicy
 }
+
+func TestProtobufHandlerSamplesUnknownFields(t *testing.T) {
+	encoder := protobuf.NewAggregatedEncoder(nil)
+	require.NoError(t, encoder.Encode(aggregated.MetricWithStoragePolicy{
+		Metric: aggregated.Metric{
+			ID:        []byte(testID),
+			TimeNanos: 1000,
+			Value:     1,
+			Type:      metric.GaugeType,
+		},
+		StoragePolicy: precisionStoragePolicy,
+	}, 2000))
+	// Append a varint field unknown to the aggregated metric message.
+	b := append([]byte(nil), encoder.Buffer().Bytes()...)
+	b = append(b, gogoproto.EncodeVarint(1000<<3|gogoproto.WireVarint)...)
+	b = append(b, gogoproto.EncodeVarint(1)...)
+
+	for _, test := range []struct {
+		sampleRate sampler.Rate
+		expected   int64
+	}{
+		{sampleRate: 0, expected: 0},
+		{sampleRate: 0.5, expected: 2},
+		{sampleRate: 1, expected: 4},
+	} {
+		t.Run(fmt.Sprintf("%v", test.sampleRate), func(t *testing.T) {
+			scope := tally.NewTestScope("", nil)
+			w := &mockWriter{m: make(map[string]payload)}
+			h := newProtobufProcessor(Options{
+				WriteFn:                 w.write,
+				InstrumentOptions:       instrument.NewOptions().SetMetricsScope(scope),
+				UnknownFieldsSampleRate: test.sampleRate,
+			})
+			defer h.Close()
+
+			for i := 0; i < 4; i++ {
+				h.Process(&testMessage{bytes: b})
+			}
+			require.Equal(t, 4, w.ingested())
+
+			var unknown int64
+			if c, ok := scope.Snapshot().Counters()["metric.unknown-fields+"]; ok {
+				unknown = c.Value()
+			}
+			require.Equal(t, test.expected, unknown)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3msg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/msg/consumer"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	defaultSplitReassemblyTimeout         = time.Minute
+	defaultSplitReassemblyMaxPendingBytes = 64 * 1024 * 1024
+)
+
+type splitMetrics struct {
+	fragments   tally.Counter
+	reassembled tally.Counter
+	duplicate   tally.Counter
+	expired     tally.Counter
+	invalid     tally.Counter
+	dropped     tally.Counter
+}
+
+func newSplitMetrics(scope tally.Scope) splitMetrics {
+	return splitMetrics{
+		fragments:   scope.Counter("fragments"),
+		reassembled: scope.Counter("reassembled"),
+		duplicate:   scope.Counter("duplicate"),
+		expired:     scope.Counter("expired"),
+		invalid:     scope.Counter("invalid"),
+		dropped:     scope.Counter("dropped"),
+	}
+}
+
+// splitReassembler reassembles aggregated metrics that were split across
+// multiple messages by the aggregator because they exceeded the max message
+// size. Fragments are held until all fragments of a metric are received, the
+// fragments of metrics not completed within the timeout are dropped without
+// being acked so that the producer redelivers them. The payloads held are
+// bounded, the first fragment of a further metric is not acked either once
+// the bound is reached.
+type splitReassembler struct {
+	sync.Mutex
+
+	timeout         time.Duration
+	maxPendingBytes int
+	nowFn           clock.NowFn
+	pending         map[uint64]*pendingSplit
+	pendingBytes    int
+	// nextExpireAt is when the oldest pending metric expires.
+	nextExpireAt time.Time
+	m            splitMetrics
+}
+
+type pendingSplit struct {
+	payloads  [][]byte
+	msgs      []consumer.Message
+	received  int
+	bytes     int
+	createdAt time.Time
+}
+
+func newSplitReassembler(
+	timeout time.Duration,
+	maxPendingBytes int,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *splitReassembler {
+	if timeout <= 0 {
+		timeout = defaultSplitReassemblyTimeout
+	}
+	if maxPendingBytes <= 0 {
+		maxPendingBytes = defaultSplitReassemblyMaxPendingBytes
+	}
+	return &splitReassembler{
+		timeout:         timeout,
+		maxPendingBytes: maxPendingBytes,
+		nowFn:           nowFn,
+		pending:         make(map[uint64]*pendingSplit),
+		m:               newSplitMetrics(scope),
+	}
+}
+
+// Add adds a fragment received in the given message, returning the message
+// carrying the reassembled metric and true once all fragments of the metric
+// have been received. The payload of the fragment is copied.
+func (r *splitReassembler) Add(
+	msg consumer.Message,
+	split protobuf.AggregatedSplit,
+) (consumer.Message, bool) {
+	if err := split.Validate(); err != nil {
+		r.m.invalid.Inc(1)
+		return nil, false
+	}
+	r.m.fragments.Inc(1)
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.nowFn()
+	r.expireWithLock(now)
+
+	p, ok := r.pending[split.ID]
+	if ok && len(p.payloads) != split.Count {
+		// Split IDs are random so a mismatching count means the fragments
+		// cannot belong to the same metric.
+		r.m.invalid.Inc(1)
+		return nil, false
+	}
+	if !ok && r.pendingBytes+len(split.Payload) > r.maxPendingBytes {
+		// Fragments of metrics already pending are still accepted so that
+		// they can complete and release their payloads.
+		r.m.dropped.Inc(1)
+		return nil, false
+	}
+	if !ok {
+		if len(r.pending) == 0 || now.Add(r.timeout).Before(r.nextExpireAt) {
+			r.nextExpireAt = now.Add(r.timeout)
+		}
+		p = &pendingSplit{
+			payloads:  make([][]byte, split.Count),
+			msgs:      make([]consumer.Message, 0, split.Count),
+			createdAt: now,
+		}
+		r.pending[split.ID] = p
+	}
+	if p.payloads[split.Index] != nil {
+		// The fragment was redelivered, the payload already received is kept
+		// and acked once the metric is reassembled.
+		r.m.duplicate.Inc(1)
+		msg.Ack()
+		return nil, false
+	}
+	p.payloads[split.Index] = append(make([]byte, 0, len(split.Payload)), split.Payload...)
+	p.msgs = append(p.msgs, msg)
+	p.received++
+	p.bytes += len(split.Payload)
+	r.pendingBytes += len(split.Payload)
+	if p.received < split.Count {
+		return nil, false
+	}
+
+	r.removeWithLock(split.ID, p)
+	r.m.reassembled.Inc(1)
+	bytes := make([]byte, 0, p.bytes)
+	for _, b := range p.payloads {
+		bytes = append(bytes, b...)
+	}
+	return &splitMessage{bytes: bytes, msgs: p.msgs}, true
+}
+
+// expireWithLock drops the pending metrics not completed within the timeout,
+// only scanning the pending metrics once the oldest of them expires.
+func (r *splitReassembler) expireWithLock(now time.Time) {
+	if len(r.pending) == 0 || now.Before(r.nextExpireAt) {
+		return
+	}
+	var nextExpireAt time.Time
+	for id, p := range r.pending {
+		expireAt := p.createdAt.Add(r.timeout)
+		if !now.Before(expireAt) {
+			r.removeWithLock(id, p)
+			r.m.expired.Inc(1)
+			continue
+		}
+		if nextExpireAt.IsZero() || expireAt.Before(nextExpireAt) {
+			nextExpireAt = expireAt
+		}
+	}
+	r.nextExpireAt = nextExpireAt
+}
+
+func (r *splitReassembler) removeWithLock(id uint64, p *pendingSplit) {
+	delete(r.pending, id)
+	r.pendingBytes -= p.bytes
+}
+
+// splitMessage is a reassembled message that acks the messages of all the
+// fragments it was reassembled from.
+type splitMessage struct {
+	bytes []byte
+	msgs  []consumer.Message
+}
+
+func (m *splitMessage) Bytes() []byte { return m.bytes }
+
+func (m *splitMessage) Ack() {
+	for _, msg := range m.msgs {
+		msg.Ack()
+	}
+}
+
+func (m *splitMessage) ShardID() uint64 { return m.msgs[0].ShardID() }
+
+func (m *splitMessage) TraceContext() []byte { return m.msgs[0].TraceContext() }
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3msg
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type testMessage struct {
+	bytes []byte
+	acks  int
+}
+
+func (m *testMessage) Bytes() []byte        { return m.bytes }
+func (m *testMessage) Ack()                 { m.acks++ }
+func (m *testMessage) ShardID() uint64      { return 0 }
+func (m *testMessage) TraceContext() []byte { return nil }
+
+func testSplitMessages(t *testing.T, id string, maxSize int) []*testMessage {
+	encoder := protobuf.NewAggregatedEncoder(nil)
+	require.NoError(t, encoder.Encode(aggregated.MetricWithStoragePolicy{
+		Metric: aggregated.Metric{
+			ID:        []byte(id),
+			TimeNanos: 1000,
+			Value:     1,
+			Type:      metric.GaugeType,
+		},
+		StoragePolicy: precisionStoragePolicy,
+	}, 2000))
+
+	bufs, err := protobuf.SplitAggregated(encoder.Buffer().Bytes(), 42, maxSize)
+	require.NoError(t, err)
+	msgs := make([]*testMessage, 0, len(bufs))
+	for _, buf := range bufs {
+		msgs = append(msgs, &testMessage{bytes: buf.Bytes()})
+	}
+	return msgs
+}
+
+func TestProtobufHandlerReassemblesSplitMetric(t *testing.T) {
+	w := &mockWriter{m: make(map[string]payload)}
+	h := newProtobufProcessor(Options{
+		WriteFn:           w.write,
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	defer h.Close()
+
+	id := strings.Repeat("a", 200)
+	msgs := testSplitMessages(t, id, 64)
+	require.True(t, len(msgs) > 2)
+
+	// Fragments may be received out of order and redelivered.
+	h.Process(msgs[1])
+	h.Process(msgs[1])
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if i == 1 {
+			continue
+		}
+		require.Equal(t, 0, w.ingested())
+		h.Process(msgs[i])
+	}
+	require.Equal(t, 1, w.ingested())
+
+	p, ok := w.m[key(id, 2000)]
+	require.True(t, ok)
+	require.Equal(t, int64(1000), p.metricNanos)
+	require.Equal(t, float64(1), p.value)
+	require.Equal(t, precisionStoragePolicy, p.sp)
+	for i, msg := range msgs {
+		if i == 1 {
+			// Acked once as a duplicate and once when reassembled.
+			require.Equal(t, 2, msg.acks)
+			continue
+		}
+		require.Equal(t, 1, msg.acks)
+	}
+}
+
+func TestSplitReassemblerExpire(t *testing.T) {
+	now := time.Now()
+	r := newSplitReassembler(time.Minute, 0, func() time.Time { return now }, tally.NoopScope)
+
+	msgs := testSplitMessages(t, strings.Repeat("a", 200), 64)
+	add := func(msg *testMessage) bool {
+		dec := protobuf.NewAggregatedDecoder(nil)
+		require.NoError(t, dec.Decode(msg.bytes))
+		split, ok := dec.Split()
+		require.True(t, ok)
+		_, ok = r.Add(msg, split)
+		return ok
+	}
+
+	for _, msg := range msgs[1:] {
+		require.False(t, add(msg))
+	}
+	require.Equal(t, 1, len(r.pending))
+
+	// The pending fragments expire without being acked.
+	now = now.Add(time.Minute)
+	require.False(t, add(msgs[0]))
+	require.Equal(t, 1, len(r.pending))
+	for _, msg := range msgs {
+		require.Equal(t, 0, msg.acks)
+	}
+
+	// Redelivered fragments are reassembled.
+	for _, msg := range msgs[1 : len(msgs)-1] {
+		require.False(t, add(msg))
+	}
+	require.True(t, add(msgs[len(msgs)-1]))
+	require.Equal(t, 0, len(r.pending))
+}
+
+func TestSplitReassemblerMaxPendingBytes(t *testing.T) {
+	now := time.Now()
+	first := testSplitMessages(t, strings.Repeat("a", 200), 64)
+	second := testSplitMessages(t, strings.Repeat("b", 200), 64)
+	r := newSplitReassembler(time.Minute, len(first[0].bytes),
+		func() time.Time { return now }, tally.NoopScope)
+
+	add := func(msg *testMessage) bool {
+		dec := protobuf.NewAggregatedDecoder(nil)
+		require.NoError(t, dec.Decode(msg.bytes))
+		split, ok := dec.Split()
+		require.True(t, ok)
+		_, ok = r.Add(msg, split)
+		return ok
+	}
+
+	require.False(t, add(first[0]))
+	// Fragments of further metrics are dropped once the bound is reached.
+	require.False(t, add(second[0]))
+	require.Equal(t, 1, len(r.pending))
+
+	// Fragments of pending metrics are still accepted and release the bytes
+	// held once reassembled.
+	for _, msg := range first[1 : len(first)-1] {
+		require.False(t, add(msg))
+	}
+	require.True(t, add(first[len(first)-1]))
+	require.Equal(t, 0, len(r.pending))
+	require.Equal(t, 0, r.pendingBytes)
+	require.False(t, add(second[0]))
+	require.Equal(t, 1, len(r.pending))
+}
@@ -23,6 +23,7 @@ package m3msg
 import (
 	"context"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 )
 
@@ -33,6 +34,7 @@ type WriteFn func(
 	metricNanos, encodeNanos int64,
 	value float64,
 	annotation []byte,
+	exemplars []metric.Exemplar,
 	sp policy.StoragePolicy,
 	callback Callbackable,
 )
@@ -171,6 +171,11 @@ func (bsc BootstrapConfiguration) VerifyOrDefault() BootstrapVerifyConfiguration
 // during a bootstrap.
 type BootstrapVerifyConfiguration struct {
 	VerifyIndexSegments *bool `yaml:"verifyIndexSegments"`
+
+	// VerifySnapshots compares the blocks read from the snapshot files with
+	// the same blocks replayed from the commit log during the commitlog
+	// bootstrap and reports the discrepancies.
+	VerifySnapshots *bool `yaml:"verifySnapshots"`
 }
 
 // VerifyIndexSegmentsOrDefault returns whether to verify index segments
@@ -183,6 +188,16 @@ func (c BootstrapVerifyConfiguration) VerifyIndexSegmentsOrDefault() bool {
 	return *c.VerifyIndexSegments
 }
 
+// VerifySnapshotsOrDefault returns whether to verify snapshots
+// or use default value.
+func (c BootstrapVerifyConfiguration) VerifySnapshotsOrDefault() bool {
+	if c.VerifySnapshots == nil {
+		return false
+	}
+
+	return *c.VerifySnapshots
+}
+
 // BootstrapFilesystemConfiguration specifies config for the fs bootstrapper.
 type BootstrapFilesystemConfiguration struct {
 	// DeprecatedNumProcessorsPerCPU is the number of processors per CPU.
@@ -214,13 +229,19 @@ type BootstrapMigrationConfiguration struct {
 	// what’s expected of the specified version.
 	TargetMigrationVersion migration.MigrationVersion `yaml:"targetMigrationVersion"`
 
+	// MigrateBlockSize indicates that we should attempt to re-chunk filesets written with
+	// a block size other than their namespace's current block size to the current block size.
+	MigrateBlockSize bool `yaml:"migrateBlockSize"`
+
 	// Concurrency sets the number of concurrent workers performing migrations.
 	Concurrency int `yaml:"concurrency"`
 }
 
 // NewOptions generates migration.Options from the configuration.
 func (m BootstrapMigrationConfiguration) NewOptions() migration.Options {
-	opts := migration.NewOptions().SetTargetMigrationVersion(m.TargetMigrationVersion)
+	opts := migration.NewOptions().
+		SetTargetMigrationVersion(m.TargetMigrationVersion).
+		SetMigrateBlockSize(m.MigrateBlockSize)
 
 	if m.Concurrency > 0 {
 		opts = opts.SetConcurrency(m.Concurrency)
@@ -331,7 +352,8 @@ func (bsc BootstrapConfiguration) New(
 				SetResultOptions(rsOpts).
 				SetCommitLogOptions(opts.CommitLogOptions()).
 				SetRuntimeOptionsManager(opts.RuntimeOptionsManager()).
-				SetReturnUnfulfilledForCorruptCommitLogFiles(cCfg.ReturnUnfulfilledForCorruptCommitLogFiles)
+				SetReturnUnfulfilledForCorruptCommitLogFiles(cCfg.ReturnUnfulfilledForCorruptCommitLogFiles).
+				SetSnapshotsVerify(bsc.VerifyOrDefault().VerifySnapshotsOrDefault())
 			if err := cOpts.Validate(); err != nil {
 				return nil, err
 			}
@@ -89,6 +89,14 @@ type PostingsListCacheConfiguration struct {
 	CacheRegexp *bool `yaml:"cacheRegexp"`
 	CacheTerms  *bool `yaml:"cacheTerms"`
 	CacheSearch *bool `yaml:"cacheSearch"`
+
+	// MaxBytes if set bounds the estimated size in bytes of the cached
+	// postings lists in addition to the entry count.
+	MaxBytes *int64 `yaml:"maxBytes"`
+
+	// MatcherHashMetricsLimit if set enables search cache hit and miss metrics
+	// tagged by the hash of the query matchers for up to this many matchers.
+	MatcherHashMetricsLimit *int `yaml:"matcherHashMetricsLimit"`
 }
 
 // SizeOrDefault returns the provided size or the default value is none is
@@ -131,6 +139,26 @@ func (p PostingsListCacheConfiguration) CacheSearchOrDefault() bool {
 	return *p.CacheSearch
 }
 
+// MaxBytesOrDefault returns the provided max bytes or zero, which disables
+// size-aware eviction, if none is provided.
+func (p PostingsListCacheConfiguration) MaxBytesOrDefault() int64 {
+	if p.MaxBytes == nil {
+		return 0
+	}
+
+	return *p.MaxBytes
+}
+
+// MatcherHashMetricsLimitOrDefault returns the provided matcher hash metrics
+// limit or zero, which disables matcher hash metrics, if none is provided.
+func (p PostingsListCacheConfiguration) MatcherHashMetricsLimitOrDefault() int {
+	if p.MatcherHashMetricsLimit == nil {
+		return 0
+	}
+
+	return *p.MatcherHashMetricsLimit
+}
+
 // RegexpCacheConfiguration is a compiled regexp cache for query regexps.
 type RegexpCacheConfiguration struct {
 	Size *int `yaml:"size"`
@@ -184,6 +184,9 @@ type DBConfiguration struct {
 	// The repair policy for repairing data within a cluster.
 	Repair *RepairPolicy `yaml:"repair"`
 
+	// The divergence check policy for reporting divergence between replicas.
+	DivergenceCheck *DivergenceCheckPolicy `yaml:"divergenceCheck"`
+
 	// The replication policy for replicating data between clusters.
 	Replication *ReplicationPolicy `yaml:"replication"`
 
@@ -220,12 +223,26 @@ type DBConfiguration struct {
 	// TChannel exposes TChannel config options.
 	TChannel *TChannelConfiguration `yaml:"tchannel"`
 
+	// TLS enables TLS for the node service.
+	TLS *TLSConfiguration `yaml:"tls"`
+
+	// SlowQueryLog configures logging of slow queries.
+	SlowQueryLog *SlowQueryLogConfiguration `yaml:"slowQueryLog"`
+
+	// Ingest configures direct ingestion of aggregated metrics from m3msg.
+	Ingest *IngestConfiguration `yaml:"ingest"`
+
 	// Debug configuration.
 	Debug config.DebugConfiguration `yaml:"debug"`
 
 	// ForceColdWritesEnabled will force enable cold writes for all namespaces
 	// if set.
 	ForceColdWritesEnabled *bool `yaml:"forceColdWritesEnabled"`
+
+	// NewSeriesHooks are the names of the registered new series hooks to
+	// invoke before creating new series, for writes or when loading blocks,
+	// in order.
+	NewSeriesHooks []string `yaml:"newSeriesHooks"`
 }
 
 // LoggingOrDefault returns the logging configuration or defaults.
@@ -388,6 +405,12 @@ func (c *DBConfiguration) Validate() error {
 		}
 	}
 
+	if c.Ingest != nil {
+		if err := c.Ingest.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -429,6 +452,13 @@ type IndexConfiguration struct {
 	// block boundaries by eagerly writing the series to the next block
 	// preemptively.
 	ForwardIndexThreshold float64 `yaml:"forwardIndexThreshold" validate:"min=0.0,max=1.0"`
+
+	// SegmentsMemoryBudgetBytes is the maximum number of bytes of persisted
+	// index segments kept in memory across all namespaces. The most recently
+	// queried segments are kept in memory while they fit within the budget and
+	// the rest are served from their mmap'd files. If zero the memory of all
+	// persisted segments is released each tick.
+	SegmentsMemoryBudgetBytes int64 `yaml:"segmentsMemoryBudgetBytes" validate:"min=0"`
 }
 
 // RegexpDFALimitOrDefault returns the deterministic finite automaton states
@@ -575,6 +605,21 @@ type RepairPolicy struct {
 	// If enabled, what percentage of metadata should perform a detailed debug
 	// shadow comparison.
 	DebugShadowComparisonsPercentage float64 `yaml:"debugShadowComparisonsPercentage"`
+
+	// AggregatedFallbackEnabled synthesizes coarse datapoints from aggregated
+	// namespaces for blocks of unaggregated namespaces missing on all replicas.
+	AggregatedFallbackEnabled bool `yaml:"aggregatedFallbackEnabled"`
+}
+
+// DivergenceCheckPolicy is the policy for periodically comparing block
+// checksums with replicas and reporting divergence metrics, no data is repaired.
+type DivergenceCheckPolicy struct {
+	// Enabled or disabled.
+	Enabled bool `yaml:"enabled"`
+
+	// The interval between divergence checks, each check compares a single
+	// block of every namespace.
+	CheckInterval time.Duration `yaml:"checkInterval"`
 }
 
 // ReplicationPolicy is the replication policy.
@@ -343,12 +343,14 @@ func TestConfiguration(t *testing.T) {
     regexpFSALimit: null
     forwardIndexProbability: 0
     forwardIndexThreshold: 0
+    segmentsMemoryBudgetBytes: 0
   transforms:
     truncateBy: 0
     forceValue: null
   logging:
     file: /var/log/m3dbnode.log
     level: info
+    moduleLevels: {}
     fields: {}
   metrics:
     scope: null
@@ -410,6 +412,7 @@ func TestConfiguration(t *testing.T) {
     fetchSeriesBlocksBatchSize: null
     writeShardsInitializing: null
     shardsLeavingCountTowardsConsistency: null
+    tls: null
   gcPercentage: 100
   tick: null
   bootstrap:
@@ -431,6 +434,8 @@ func TestConfiguration(t *testing.T) {
       cacheRegexp: false
       cacheTerms: false
       cacheSearch: null
+      maxBytes: null
+      matcherHashMetricsLimit: null
     regexp: null
   filesystem:
     filePathPrefix: /var/lib/m3db
@@ -446,6 +451,8 @@ func TestConfiguration(t *testing.T) {
     force_index_summaries_mmap_memory: true
     force_bloom_filter_mmap_memory: true
     bloomFilterFalsePositivePercent: null
+    tieredStorage: null
+    diskUsageEviction: null
   commitlog:
     flushMaxBytes: 524288
     flushEvery: 1s
@@ -463,6 +470,8 @@ func TestConfiguration(t *testing.T) {
     concurrency: 0
     debugShadowComparisonsEnabled: false
     debugShadowComparisonsPercentage: 0
+    aggregatedFallbackEnabled: false
+  divergenceCheck: null
   replication: null
   pooling:
     blockAllocSize: 16
@@ -623,6 +632,7 @@ func TestConfiguration(t *testing.T) {
         clientOverrides:
           hostQueueFlushInterval: null
           targetHostQueueFlushSize: null
+          asyncWritePercent: null
         service:
           zone: embedded
           env: production
@@ -753,10 +763,15 @@ func TestConfiguration(t *testing.T) {
     writeNewSeriesPerSecond: 0
   wide: null
   tchannel: null
+  tls: null
+  slowQueryLog: null
+  ingest: null
   debug:
     mutexProfileFraction: 0
     blockProfileRate: 0
+    continuousProfiling: null
   forceColdWritesEnabled: null
+  newSeriesHooks: []
 coordinator: null
 `
 
@@ -21,8 +21,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 )
 
 const (
@@ -41,8 +45,13 @@ const (
 	defaultForceIndexSummariesMmapMemory   = false
 	defaultForceBloomFilterMmapMemory      = false
 	defaultBloomFilterFalsePositivePercent = 0.02
+	defaultTieredStorageCacheTTL           = time.Hour
+	defaultDiskUsageEvictionLowWatermark   = 0.8
+	defaultDiskUsageEvictionRetentionFloor = 24 * time.Hour
 )
 
+var errTieredStorageDirectoryNotSet = errors.New("fs tieredStorage directory must be set")
+
 // DefaultMmapConfiguration is the default mmap configuration.
 func DefaultMmapConfiguration() MmapConfiguration {
 	return MmapConfiguration{
@@ -98,6 +107,14 @@ type FilesystemConfiguration struct {
 	// BloomFilterFalsePositivePercent controls the target false positive percentage
 	// for the bloom filters for the fileset files.
 	BloomFilterFalsePositivePercent *float64 `yaml:"bloomFilterFalsePositivePercent"`
+
+	// TieredStorage configures tiering the data filesets of old blocks to
+	// object storage to reduce the local disk required for long retention.
+	TieredStorage *TieredStorageConfiguration `yaml:"tieredStorage"`
+
+	// DiskUsageEviction configures evicting the data filesets of the oldest
+	// blocks when the disk is close to full instead of running out of disk.
+	DiskUsageEviction *DiskUsageEvictionConfiguration `yaml:"diskUsageEviction"`
 }
 
 // Validate validates the Filesystem configuration. We use this method to validate
@@ -145,6 +162,23 @@ func (f FilesystemConfiguration) Validate() error {
 			*f.BloomFilterFalsePositivePercent)
 	}
 
+	if f.TieredStorage != nil {
+		if f.TieredStorage.Directory == "" {
+			return errTieredStorageDirectoryNotSet
+		}
+		if f.TieredStorage.EvictAfter <= 0 {
+			return fmt.Errorf(
+				"fs tieredStorage evictAfter is set to: %v, but must be positive",
+				f.TieredStorage.EvictAfter)
+		}
+	}
+
+	if f.DiskUsageEviction != nil {
+		if err := f.DiskUsageEviction.NewOptions().Validate(); err != nil {
+			return fmt.Errorf("fs diskUsageEviction is invalid: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -257,6 +291,90 @@ func (f FilesystemConfiguration) BloomFilterFalsePositivePercentOrDefault() floa
 	return defaultBloomFilterFalsePositivePercent
 }
 
+// TieredStorageConfiguration is the tiered storage configuration. Data filesets of
+// blocks that ended more than evictAfter ago are uploaded to object storage and
+// evicted locally, and restored on demand when they are read.
+type TieredStorageConfiguration struct {
+	// Directory is the directory objects are stored in, typically the mount
+	// point of an object storage bucket.
+	Directory string `yaml:"directory"`
+
+	// EvictAfter is how long after the end of a block its data fileset is
+	// tiered to object storage.
+	EvictAfter time.Duration `yaml:"evictAfter"`
+
+	// CacheTTL is how long files restored from object storage are kept locally
+	// before being evicted again.
+	CacheTTL *time.Duration `yaml:"cacheTTL"`
+}
+
+// CacheTTLOrDefault returns the configured cache TTL if configured, or a
+// default value otherwise.
+func (c TieredStorageConfiguration) CacheTTLOrDefault() time.Duration {
+	if c.CacheTTL != nil {
+		return *c.CacheTTL
+	}
+
+	return defaultTieredStorageCacheTTL
+}
+
+// NewOptions returns the tiered storage options for the configuration.
+func (c TieredStorageConfiguration) NewOptions(
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) fs.TieredStorageOptions {
+	return fs.TieredStorageOptions{
+		ObjectStore: fs.NewDirectoryObjectStore(c.Directory, newFileMode, newDirectoryMode),
+		EvictAfter:  c.EvictAfter,
+		CacheTTL:    c.CacheTTLOrDefault(),
+	}
+}
+
+// DiskUsageEvictionConfiguration is the disk usage eviction configuration. When
+// the fraction of the disk used is above the high watermark the data filesets
+// of the oldest blocks are deleted until it is below the low watermark, data of
+// blocks within the retention floor is never deleted.
+type DiskUsageEvictionConfiguration struct {
+	// HighWatermark is the fraction of the disk used above which data filesets
+	// are evicted.
+	HighWatermark float64 `yaml:"highWatermark"`
+
+	// LowWatermark is the fraction of the disk used that eviction stops at.
+	LowWatermark *float64 `yaml:"lowWatermark"`
+
+	// RetentionFloor is how much of the most recent data is never evicted.
+	RetentionFloor *time.Duration `yaml:"retentionFloor"`
+}
+
+// LowWatermarkOrDefault returns the configured low watermark if configured, or
+// a default value otherwise.
+func (c DiskUsageEvictionConfiguration) LowWatermarkOrDefault() float64 {
+	if c.LowWatermark != nil {
+		return *c.LowWatermark
+	}
+
+	return defaultDiskUsageEvictionLowWatermark
+}
+
+// RetentionFloorOrDefault returns the configured retention floor if configured,
+// or a default value otherwise.
+func (c DiskUsageEvictionConfiguration) RetentionFloorOrDefault() time.Duration {
+	if c.RetentionFloor != nil {
+		return *c.RetentionFloor
+	}
+
+	return defaultDiskUsageEvictionRetentionFloor
+}
+
+// NewOptions returns the disk usage eviction options for the configuration.
+func (c DiskUsageEvictionConfiguration) NewOptions() fs.DiskUsageEvictionOptions {
+	return fs.DiskUsageEvictionOptions{
+		HighWatermark:  c.HighWatermark,
+		LowWatermark:   c.LowWatermarkOrDefault(),
+		RetentionFloor: c.RetentionFloorOrDefault(),
+	}
+}
+
 // MmapConfiguration is the mmap configuration.
 type MmapConfiguration struct {
 	// HugeTLB is the huge pages configuration which will only take affect
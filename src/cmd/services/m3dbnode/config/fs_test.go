@@ -23,9 +23,11 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestFilesystemConfigurationParseNewFileMode(t *testing.T) {
@@ -47,3 +49,42 @@ func TestFilesystemConfigurationParseNewDirectoryMode(t *testing.T) {
 
 	assert.Equal(t, os.FileMode(0775)|os.ModeDir, v)
 }
+
+func TestFilesystemConfigurationTieredStorage(t *testing.T) {
+	var cfg FilesystemConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tieredStorage:
+  directory: /mnt/m3db-tiered
+  evictAfter: 168h
+`), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	opts := cfg.TieredStorage.NewOptions(DefaultNewFileMode, DefaultNewDirectoryMode)
+	assert.NotNil(t, opts.ObjectStore)
+	assert.Equal(t, 168*time.Hour, opts.EvictAfter)
+	assert.Equal(t, time.Hour, opts.CacheTTL)
+
+	cfg.TieredStorage.EvictAfter = 0
+	require.Error(t, cfg.Validate())
+
+	cfg.TieredStorage = &TieredStorageConfiguration{EvictAfter: time.Hour}
+	require.Equal(t, errTieredStorageDirectoryNotSet, cfg.Validate())
+}
+
+func TestFilesystemConfigurationDiskUsageEviction(t *testing.T) {
+	var cfg FilesystemConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(`
+diskUsageEviction:
+  highWatermark: 0.9
+`), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	opts := cfg.DiskUsageEviction.NewOptions()
+	assert.Equal(t, 0.9, opts.HighWatermark)
+	assert.Equal(t, 0.8, opts.LowWatermark)
+	assert.Equal(t, 24*time.Hour, opts.RetentionFloor)
+
+	lowWatermark := 0.95
+	cfg.DiskUsageEviction.LowWatermark = &lowWatermark
+	require.Error(t, cfg.Validate())
+}
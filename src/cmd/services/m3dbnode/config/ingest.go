@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/metrics/policy"
+)
+
+var errIngestNoNamespaces = errors.New("ingest must map at least one storage policy to a namespace")
+
+// IngestConfiguration configures direct ingestion of aggregated metrics
+// consumed from an m3msg topic, bypassing coordinators for the aggregator
+// to storage hop.
+type IngestConfiguration struct {
+	coordinatorcfg.IngestConfiguration `yaml:",inline"`
+
+	// Namespaces maps the storage policies of aggregated metrics to the
+	// namespaces they are written to.
+	Namespaces []IngestNamespaceConfiguration `yaml:"namespaces"`
+
+	// TagOptions are the tag options used to generate the IDs of the series
+	// written, which must match the tag options of the coordinators querying
+	// them.
+	TagOptions coordinatorcfg.TagOptionsConfiguration `yaml:"tagOptions"`
+}
+
+// IngestNamespaceConfiguration maps a storage policy to a namespace.
+type IngestNamespaceConfiguration struct {
+	// Namespace is the namespace metrics with the storage policy are written to.
+	Namespace string `yaml:"namespace" validate:"nonzero"`
+
+	// StoragePolicy is the storage policy of the aggregated metrics, e.g. 1m:40d.
+	StoragePolicy policy.StoragePolicy `yaml:"storagePolicy"`
+}
+
+// Validate validates the ingest configuration.
+func (c *IngestConfiguration) Validate() error {
+	if len(c.Namespaces) == 0 {
+		return errIngestNoNamespaces
+	}
+	seen := make(map[policy.StoragePolicy]struct{}, len(c.Namespaces))
+	for _, ns := range c.Namespaces {
+		if _, ok := seen[ns.StoragePolicy]; ok {
+			return fmt.Errorf("ingest storage policy %s mapped to more than one namespace",
+				ns.StoragePolicy.String())
+		}
+		seen[ns.StoragePolicy] = struct{}{}
+	}
+	return nil
+}
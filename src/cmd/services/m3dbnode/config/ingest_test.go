@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestIngestConfigurationParse(t *testing.T) {
+	str := `
+ingester:
+  workerPoolSize: 100
+m3msg:
+  server:
+    listenAddress: 0.0.0.0:7507
+namespaces:
+  - namespace: metrics_1m_40d
+    storagePolicy: 1m:40d
+  - namespace: metrics_10m_1y
+    storagePolicy: 10m:1y
+tagOptions:
+  idScheme: quoted
+  metricName: name
+`
+	var cfg IngestConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	assert.Equal(t, 100, cfg.Ingester.WorkerPoolSize)
+	assert.Equal(t, "0.0.0.0:7507", cfg.M3Msg.Server.ListenAddress)
+	require.Equal(t, 2, len(cfg.Namespaces))
+	assert.Equal(t, "metrics_1m_40d", cfg.Namespaces[0].Namespace)
+	assert.Equal(t, time.Minute, cfg.Namespaces[0].StoragePolicy.Resolution().Window)
+	assert.Equal(t, 40*24*time.Hour, cfg.Namespaces[0].StoragePolicy.Retention().Duration())
+	assert.Equal(t, models.TypeQuoted, cfg.TagOptions.Scheme)
+	assert.Equal(t, "name", cfg.TagOptions.MetricName)
+}
+
+func TestIngestConfigurationValidate(t *testing.T) {
+	var cfg IngestConfiguration
+	require.Error(t, cfg.Validate())
+
+	str := `
+namespaces:
+  - namespace: metrics_1m_40d
+    storagePolicy: 1m:40d
+  - namespace: metrics_1m_40d_other
+    storagePolicy: 1m:40d
+`
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.Error(t, cfg.Validate())
+}
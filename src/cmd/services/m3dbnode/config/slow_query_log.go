@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+)
+
+// SlowQueryLogConfiguration configures logging of queries that exceed any
+// of the configured thresholds, along with a breakdown of where the query
+// spent its time and how much data it touched. Fetch, FetchBatchRaw,
+// FetchTagged, Aggregate and AggregateTiles requests are observed.
+type SlowQueryLogConfiguration struct {
+	// Enabled enables the slow query log.
+	Enabled bool `yaml:"enabled"`
+
+	// DurationThreshold is the query duration above which a query is logged.
+	DurationThreshold time.Duration `yaml:"durationThreshold"`
+
+	// DocsThreshold is the number of index docs matched above which a query is logged.
+	DocsThreshold int `yaml:"docsThreshold" validate:"min=0"`
+
+	// BytesReadThreshold is the number of series bytes read above which a query is logged.
+	BytesReadThreshold int `yaml:"bytesReadThreshold" validate:"min=0"`
+}
+
+// SlowQueryLogOptions returns the slow query log options.
+func (c *SlowQueryLogConfiguration) SlowQueryLogOptions() tchannelthrift.SlowQueryLogOptions {
+	if c == nil {
+		return tchannelthrift.SlowQueryLogOptions{}
+	}
+	return tchannelthrift.SlowQueryLogOptions{
+		Enabled:            c.Enabled,
+		DurationThreshold:  c.DurationThreshold,
+		DocsThreshold:      c.DocsThreshold,
+		BytesReadThreshold: c.BytesReadThreshold,
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	xtls "github.com/m3db/m3/src/x/tls"
+)
+
+// TLSConfiguration configures TLS for the node service, the identity of
+// verified client certificates is attached to request metrics and logs and
+// can be limited by quotas.
+type TLSConfiguration struct {
+	xtls.ServerConfiguration `yaml:",inline"`
+
+	// HandshakeTimeout is the timeout for clients to complete the TLS handshake.
+	HandshakeTimeout time.Duration `yaml:"handshakeTimeout"`
+
+	// ClientIdentityQuota is the quota of each client identity.
+	ClientIdentityQuota ClientIdentityQuotaConfiguration `yaml:"clientIdentityQuota"`
+
+	// ClientIdentityQuotaOverrides are the quotas of specific client identities.
+	ClientIdentityQuotaOverrides map[string]ClientIdentityQuotaConfiguration `yaml:"clientIdentityQuotaOverrides"`
+}
+
+// ClientIdentityQuotaConfiguration limits the connections and requests of a
+// client identity, zero values are unlimited.
+type ClientIdentityQuotaConfiguration struct {
+	// MaxConnections is the maximum number of open connections.
+	MaxConnections int `yaml:"maxConnections" validate:"min=0"`
+
+	// MaxRequestsPerSecond is the maximum number of requests per second.
+	MaxRequestsPerSecond int `yaml:"maxRequestsPerSecond" validate:"min=0"`
+}
+
+func (c ClientIdentityQuotaConfiguration) quota() node.ClientIdentityQuota {
+	return node.ClientIdentityQuota{
+		MaxConnections:       c.MaxConnections,
+		MaxRequestsPerSecond: c.MaxRequestsPerSecond,
+	}
+}
+
+// ClientIdentityOptions returns the client identity options.
+func (c *TLSConfiguration) ClientIdentityOptions() node.ClientIdentityOptions {
+	opts := node.ClientIdentityOptions{
+		HandshakeTimeout: c.HandshakeTimeout,
+		DefaultQuota:     c.ClientIdentityQuota.quota(),
+	}
+	if len(c.ClientIdentityQuotaOverrides) > 0 {
+		opts.Overrides = make(map[string]node.ClientIdentityQuota,
+			len(c.ClientIdentityQuotaOverrides))
+		for identity, quota := range c.ClientIdentityQuotaOverrides {
+			opts.Overrides[identity] = quota.quota()
+		}
+	}
+	return opts
+}
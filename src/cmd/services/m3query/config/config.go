@@ -68,6 +68,10 @@ const (
 	defaultQueryTimeout = 30 * time.Second
 
 	defaultPrometheusMaxSamplesPerQuery = 100000000
+
+	defaultMatcherCacheSize = 1024
+
+	defaultSeenSeriesCacheSize = 1 << 20
 )
 
 var (
@@ -196,6 +200,14 @@ type Configuration struct {
 	// StoreMetricsType controls if metrics type is stored or not.
 	StoreMetricsType *bool `yaml:"storeMetricsType"`
 
+	// MetricMetadata configures storing the metric metadata sent with
+	// Prometheus remote write requests and serving it from /api/v1/metadata.
+	MetricMetadata *MetricMetadataConfiguration `yaml:"metricMetadata"`
+
+	// IngestAccounting configures accounting of the series and datapoints
+	// ingested through Prometheus remote write by source.
+	IngestAccounting *IngestAccountingConfiguration `yaml:"ingestAccounting"`
+
 	// MultiProcess is the multi-process configuration.
 	MultiProcess MultiProcessConfiguration `yaml:"multiProcess"`
 
@@ -291,6 +303,19 @@ type QueryConfiguration struct {
 	// RequireSeriesEndpointStartEndTime requires requests to /series endpoint
 	// to specify a start and end time to prevent unbounded queries.
 	RequireSeriesEndpointStartEndTime bool `yaml:"requireSeriesEndpointStartEndTime"`
+	// ReadYourWrites configures merging the values aggregators have aggregated
+	// so far into the results of queries for recent data.
+	ReadYourWrites *ReadYourWritesConfiguration `yaml:"readYourWrites"`
+	// RemoteRead configures merging the results of Prometheus remote read
+	// compatible stores into the results of queries.
+	RemoteRead *RemoteReadConfiguration `yaml:"remoteRead"`
+	// MatcherCache configures the caches of compiled regexp matchers and their
+	// index queries shared across queries.
+	MatcherCache MatcherCacheConfiguration `yaml:"matcherCache"`
+	// SnapshotCompletenessDelay is how long after a snapshot time queries are
+	// pinned to writes are still expected to arrive for data at or before it,
+	// it defaults to the default buffer past of M3DB namespaces.
+	SnapshotCompletenessDelay time.Duration `yaml:"snapshotCompletenessDelay" validate:"min=0"`
 }
 
 // TimeoutOrDefault returns the configured timeout or default value.
@@ -347,6 +372,80 @@ type ConsolidationConfiguration struct {
 	MatchType consolidators.MatchType `yaml:"matchType"`
 }
 
+// ReadYourWritesConfiguration configures querying aggregators for the values of
+// aggregation windows that have not been flushed to storage yet, so that queries
+// for recent data do not show a trailing gap equal to the aggregation window.
+type ReadYourWritesConfiguration struct {
+	// Enabled enables merging in-progress aggregator values into query results.
+	Enabled bool `yaml:"enabled"`
+	// Endpoints are the HTTP addresses of the aggregators to query,
+	// e.g. "http://m3aggregator01:6001".
+	Endpoints []string `yaml:"endpoints"`
+	// Window is how close to now a query must end for aggregators to be queried.
+	Window *time.Duration `yaml:"window"`
+	// RequestTimeout is the timeout of requests to aggregators.
+	RequestTimeout *time.Duration `yaml:"requestTimeout"`
+}
+
+// RemoteReadConfiguration configures querying external Prometheus remote read
+// compatible stores alongside the configured storage and merging their results,
+// for instance while migrating from such a store to M3.
+type RemoteReadConfiguration struct {
+	// Enabled enables merging remote read results into query results.
+	Enabled bool `yaml:"enabled"`
+	// Endpoints are the remote read addresses of the stores to query,
+	// e.g. "http://prometheus01:9090/api/v1/read".
+	Endpoints []string `yaml:"endpoints"`
+	// RequestTimeout is the timeout of remote read requests.
+	RequestTimeout *time.Duration `yaml:"requestTimeout"`
+}
+
+// MetricMetadataConfiguration configures storing the HELP, TYPE and UNIT
+// metadata of metric families that Prometheus sends with remote write requests.
+// The metadata is persisted in the cluster KV store so that every coordinator
+// can serve it regardless of which one received the write.
+type MetricMetadataConfiguration struct {
+	// Enabled enables storing and serving metric metadata.
+	Enabled bool `yaml:"enabled"`
+	// KVKey is the prefix of the KV keys the metadata is stored under, the
+	// metadata is sharded by metric family name across NumShards keys.
+	KVKey string `yaml:"kvKey"`
+	// NumShards is the number of KV keys the metadata is sharded across.
+	NumShards *int `yaml:"numShards"`
+	// MaxMetrics is the maximum number of metric families to store metadata for,
+	// metadata for any further metric families is dropped.
+	MaxMetrics *int `yaml:"maxMetrics"`
+	// PersistInterval is the interval at which metadata received since the
+	// last persist is written to the KV store in a batch per shard.
+	PersistInterval time.Duration `yaml:"persistInterval"`
+}
+
+// IngestAccountingConfiguration configures accounting of the traffic ingested
+// through Prometheus remote write by source, i.e. by remote address, user
+// agent and tenant, served from /api/v1/ingest/accounting.
+type IngestAccountingConfiguration struct {
+	// Enabled enables ingest accounting.
+	Enabled bool `yaml:"enabled"`
+	// SampleRate is the fraction of write requests accounted for, counts are
+	// scaled up accordingly. Defaults to accounting for every request.
+	SampleRate *float64 `yaml:"sampleRate"`
+	// Window is the period over which traffic is accounted for before the
+	// counts are reset.
+	Window *time.Duration `yaml:"window"`
+	// MaxSources is the maximum number of sources accounted for per window,
+	// traffic of any further sources is accounted for as a single "other" source.
+	MaxSources *int `yaml:"maxSources"`
+	// MaxTrackedSeries is the maximum number of series remembered to tell new
+	// series apart, new series are no longer counted once it is reached.
+	MaxTrackedSeries *int `yaml:"maxTrackedSeries"`
+	// TenantHeader is the request header holding the tenant.
+	TenantHeader string `yaml:"tenantHeader"`
+	// RemoteAddrHeader is the request header holding the remote address when
+	// the coordinator is behind a proxy, e.g. X-Forwarded-For, the first
+	// address listed is used.
+	RemoteAddrHeader string `yaml:"remoteAddrHeader"`
+}
+
 // PrometheusQueryConfiguration is the prometheus query engine configuration.
 type PrometheusQueryConfiguration struct {
 	// MaxSamplesPerQuery is the limit on fetched samples per query.
@@ -362,11 +461,61 @@ func (c PrometheusQueryConfiguration) MaxSamplesPerQueryOrDefault() int {
 	return defaultPrometheusMaxSamplesPerQuery
 }
 
+// MatcherCacheConfiguration configures the caches of compiled regexp matchers
+// and their index queries, so that queries repeatedly issuing the same patterns
+// such as dashboards skip recompiling them.
+type MatcherCacheConfiguration struct {
+	// Size is the maximum number of patterns cached, zero disables the caches.
+	Size *int `yaml:"size"`
+}
+
+// SizeOrDefault returns the configured size or default value.
+func (c MatcherCacheConfiguration) SizeOrDefault() int {
+	if v := c.Size; v != nil {
+		return *v
+	}
+
+	return defaultMatcherCacheSize
+}
+
 // LimitsConfiguration represents limitations on resource usage in the query
 // instance. Limits are split between per-query and global limits.
 type LimitsConfiguration struct {
 	// PerQuery configures limits which apply to each query individually.
 	PerQuery PerQueryLimitsConfiguration `yaml:"perQuery"`
+
+	// PerRemoteWrite configures limits which apply to each Prometheus remote
+	// write request individually.
+	PerRemoteWrite PerRemoteWriteLimitsConfiguration `yaml:"perRemoteWrite"`
+}
+
+// PerRemoteWriteLimitsConfiguration represents limits on the size of a single
+// Prometheus remote write request, requests exceeding a limit are rejected
+// with a 413 status code. Zero or negative values imply no limit.
+type PerRemoteWriteLimitsConfiguration struct {
+	// MaxUncompressedBodyBytes limits the size of the request body once
+	// decompressed, it is checked before the body is decompressed.
+	MaxUncompressedBodyBytes int `yaml:"maxUncompressedBodyBytes"`
+
+	// MaxSamples limits the number of samples across all series of a request.
+	MaxSamples int `yaml:"maxSamples"`
+
+	// MaxNewSeries limits the number of series of a request that were not
+	// written recently, as remembered by a cache of the series seen.
+	MaxNewSeries int `yaml:"maxNewSeries"`
+
+	// SeenSeriesCacheSize is the max number of series remembered to count
+	// the new series of a request.
+	SeenSeriesCacheSize int `yaml:"seenSeriesCacheSize"`
+}
+
+// SeenSeriesCacheSizeOrDefault returns the max number of series remembered
+// to count the new series of a request, or the default.
+func (c PerRemoteWriteLimitsConfiguration) SeenSeriesCacheSizeOrDefault() int {
+	if c.SeenSeriesCacheSize <= 0 {
+		return defaultSeenSeriesCacheSize
+	}
+	return c.SeenSeriesCacheSize
 }
 
 // PerQueryLimitsConfiguration represents limits on resource usage within a
@@ -438,6 +587,23 @@ type IngestConfiguration struct {
 	M3Msg m3msg.Configuration `yaml:"m3msg"`
 }
 
+// RequestLimitsConfiguration is the configuration for limiting the rate and
+// concurrency of requests served by an endpoint.
+type RequestLimitsConfiguration struct {
+	// MaxConcurrent is the max number of requests served concurrently,
+	// zero means there is no limit.
+	MaxConcurrent int `yaml:"maxConcurrent"`
+	// MaxWait is how long a request waits for a concurrency slot before
+	// it is rejected, zero means requests are rejected immediately.
+	MaxWait time.Duration `yaml:"maxWait"`
+	// RequestsPerSecond is the max rate of requests admitted,
+	// zero means there is no limit.
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	// Burst is the max number of requests admitted at once above the rate,
+	// defaults to the max of one and the requests per second.
+	Burst int `yaml:"burst"`
+}
+
 // CarbonConfiguration is the configuration for the carbon server.
 type CarbonConfiguration struct {
 	// Ingester if set defines an ingester to run for carbon.
@@ -446,6 +612,12 @@ type CarbonConfiguration struct {
 	LimitsFind *LimitsConfiguration `yaml:"limitsFind"`
 	// LimitsRender sets the limits configuration for render queries.
 	LimitsRender *LimitsConfiguration `yaml:"limitsRender"`
+	// RequestLimitsFind sets the rate and concurrency limits for find
+	// requests, these are independent of the limits of other endpoints.
+	RequestLimitsFind *RequestLimitsConfiguration `yaml:"requestLimitsFind"`
+	// RequestLimitsRender sets the rate and concurrency limits for render
+	// requests, these are independent of the limits of other endpoints.
+	RequestLimitsRender *RequestLimitsConfiguration `yaml:"requestLimitsRender"`
 	// AggregateNamespacesAllData configures whether all aggregate
 	// namespaces contain entire copies of the data set.
 	// This affects whether queries can be optimized or not, if false
@@ -505,6 +677,10 @@ type MiddlewareConfiguration struct {
 	Metrics MetricsMiddlewareConfiguration `yaml:"metrics"`
 	// Prometheus configures prometheus-related middleware.
 	Prometheus PrometheusMiddlewareConfiguration `yaml:"prometheus"`
+	// QueryFirewall configures the query firewall middleware.
+	QueryFirewall QueryFirewallConfiguration `yaml:"queryFirewall"`
+	// StickyQueryRouting configures the sticky query routing middleware.
+	StickyQueryRouting StickyQueryRoutingConfiguration `yaml:"stickyQueryRouting"`
 }
 
 // LoggingMiddlewareConfiguration configures the logging middleware.
@@ -563,6 +739,72 @@ type PrometheusMiddlewareConfiguration struct {
 	ResolutionMultiplier int `yaml:"resolutionMultiplier"`
 }
 
+// QueryFirewallConfiguration configures the query firewall middleware which
+// blocks or rewrites known expensive PromQL query patterns before they are
+// executed.
+type QueryFirewallConfiguration struct {
+	// Enabled enables the query firewall.
+	Enabled bool `yaml:"enabled"`
+	// Rules are the static query firewall rules.
+	Rules []QueryFirewallRuleConfiguration `yaml:"rules"`
+	// KVKey is an optional KV key to watch for query firewall rules, when a value
+	// is set for the key the rules it contains replace the static rules. The value
+	// is expected to be a string proto containing a YAML list of rules.
+	KVKey string `yaml:"kvKey"`
+}
+
+// QueryFirewallAction is the action taken when a query firewall rule matches.
+type QueryFirewallAction string
+
+const (
+	// QueryFirewallBlock rejects the query with the rule's message.
+	QueryFirewallBlock QueryFirewallAction = "block"
+	// QueryFirewallRewrite rewrites the query using the rule's rewrite template.
+	QueryFirewallRewrite QueryFirewallAction = "rewrite"
+)
+
+// QueryFirewallRuleConfiguration is a single query firewall rule, a query
+// matches the rule only if it matches all the conditions that are set.
+type QueryFirewallRuleConfiguration struct {
+	// Name is the name of the rule, used in metrics and errors.
+	Name string `yaml:"name"`
+	// Action is the action to take when the rule matches, either block or rewrite.
+	Action QueryFirewallAction `yaml:"action"`
+	// Message is an optional message returned to the user when a query is blocked.
+	Message string `yaml:"message"`
+	// QueryRegexp matches the raw query string.
+	QueryRegexp string `yaml:"queryRegexp"`
+	// Rewrite is the replacement template applied to the parts of the query matched
+	// by QueryRegexp when the action is rewrite, e.g. "${1}[5m]".
+	Rewrite string `yaml:"rewrite"`
+	// UnboundedSelector matches queries that contain a selector whose matchers all
+	// match any value, e.g. {__name__=~".+"}.
+	UnboundedSelector bool `yaml:"unboundedSelector"`
+	// MinRange matches queries whose time range is at least this long.
+	MinRange time.Duration `yaml:"minRange"`
+	// MaxStep matches range queries whose step is at most this long.
+	MaxStep time.Duration `yaml:"maxStep"`
+}
+
+// StickyQueryRoutingConfiguration configures the sticky query routing
+// middleware which forwards queries for the same tenant or query to the same
+// coordinator among a set of peers, so that per coordinator caches see the
+// same queries repeatedly.
+type StickyQueryRoutingConfiguration struct {
+	// Enabled enables sticky query routing.
+	Enabled bool `yaml:"enabled"`
+	// Self is the address of this coordinator as it appears in peers.
+	Self string `yaml:"self"`
+	// Peers are the addresses (host:port) of all coordinators that queries
+	// are routed between, including this coordinator. New peers should be
+	// appended to the end of the list to minimize the queries that move
+	// between coordinators.
+	Peers []string `yaml:"peers"`
+	// TenantHeader is an optional header whose value, when present on a
+	// request, is used as the routing key instead of the query.
+	TenantHeader string `yaml:"tenantHeader"`
+}
+
 // CarbonIngesterConfiguration is the configuration struct for carbon ingestion.
 type CarbonIngesterConfiguration struct {
 	ListenAddress  string                             `yaml:"listenAddress"`
@@ -771,6 +1013,9 @@ type PrometheusRemoteBackendConfiguration struct {
 type PrometheusRemoteBackendEndpointConfiguration struct {
 	Name    string `yaml:"name"`
 	Address string `yaml:"address"`
+	// ReadAddress is the optional Prometheus remote read address of the endpoint,
+	// queries are served by all endpoints that have one.
+	ReadAddress string `yaml:"readAddress"`
 	// When nil all unaggregated data will be sent to this endpoint.
 	StoragePolicy *PrometheusRemoteBackendStoragePolicyConfiguration `yaml:"storagePolicy"`
 }
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// integrity_check is a tool that writes a deterministic workload to a
+// coordinator and verifies it can be read back unchanged.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cenkalti/backoff/v3"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/integration/resources"
+	"github.com/m3db/m3/src/integration/resources/common"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/headers"
+)
+
+const (
+	modeWrite  = "write"
+	modeVerify = "verify"
+	modeAll    = "all"
+)
+
+// coordinator writes and queries a coordinator listening on the local host.
+type coordinator struct {
+	client common.CoordinatorClient
+}
+
+func (c *coordinator) WriteCarbon(port int, metric string, v float64, t time.Time) error {
+	return c.client.WriteCarbon(fmt.Sprintf("0.0.0.0:%d", port), metric, v, t)
+}
+
+func (c *coordinator) WriteProm(name string, tags map[string]string, samples []prompb.Sample) error {
+	return c.client.WriteProm(name, tags, samples)
+}
+
+func (c *coordinator) RunQuery(
+	verifier resources.ResponseVerifier, query string, hdrs map[string][]string,
+) error {
+	return c.client.RunQuery(verifier, query, hdrs)
+}
+
+func main() {
+	var (
+		mode          = flag.String("mode", modeAll, "One of write, verify or all")
+		httpPort      = flag.Int("httpPort", 7201, "Coordinator HTTP port")
+		carbonPort    = flag.Int("carbonPort", 7204, "Coordinator carbon ingestion port")
+		seed          = flag.Int64("seed", 42, "Seed of the generated workload")
+		numSeries     = flag.Int("series", 10, "Number of series per workload")
+		numDatapoints = flag.Int("datapoints", 12, "Number of datapoints per series")
+		interval      = flag.Duration("interval", 10*time.Second, "Interval between datapoints")
+		start         = flag.Int64("start", 0, "Unix time of the first datapoint, required to verify separately")
+		prefix        = flag.String("prefix", "integrity", "Prefix of the generated metric names")
+		aggregated    = flag.String("aggregatedPolicy", "", "Storage policy of an aggregated namespace to verify, e.g. 5s:10h")
+		timeout       = flag.Duration("timeout", time.Minute, "How long to retry each query before failing")
+	)
+	flag.Parse()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *mode != modeWrite && *mode != modeVerify && *mode != modeAll {
+		logger.Fatal("unknown mode", zap.String("mode", *mode))
+	}
+	if *mode == modeVerify && *start == 0 {
+		logger.Fatal("start must be set to verify a workload written separately")
+	}
+
+	opts := resources.IntegrityCheckOptions{
+		Seed:          *seed,
+		NumSeries:     *numSeries,
+		NumDatapoints: *numDatapoints,
+		Interval:      *interval,
+		CarbonPort:    *carbonPort,
+		MetricPrefix:  *prefix,
+	}
+	if *start != 0 {
+		opts.Start = time.Unix(*start, 0)
+	}
+	if *aggregated != "" {
+		sp, err := policy.ParseStoragePolicy(*aggregated)
+		if err != nil {
+			logger.Fatal("invalid aggregated storage policy", zap.Error(err))
+		}
+		opts.QueryPaths = []resources.IntegrityQueryPath{
+			{
+				Name: "prometheus",
+				Type: resources.IntegrityQueryTypePrometheus,
+				Headers: map[string][]string{
+					headers.MetricsTypeHeader: {"unaggregated"},
+				},
+			},
+			{
+				Name: "prometheus-aggregated",
+				Type: resources.IntegrityQueryTypePrometheus,
+				Headers: map[string][]string{
+					headers.MetricsTypeHeader:          {"aggregated"},
+					headers.MetricsStoragePolicyHeader: {sp.String()},
+				},
+				Resolution: sp.Resolution().Window,
+			},
+			{
+				Name:       "graphite",
+				Type:       resources.IntegrityQueryTypeGraphite,
+				Resolution: sp.Resolution().Window,
+			},
+		}
+	}
+
+	client := &coordinator{
+		client: common.NewCoordinatorClient(common.CoordinatorClientOptions{
+			Client:   &http.Client{},
+			HTTPPort: *httpPort,
+			Logger:   logger,
+			RetryFunc: func(op func() error) error {
+				bo := backoff.NewExponentialBackOff()
+				bo.MaxElapsedTime = *timeout
+				return backoff.Retry(op, bo)
+			},
+		}),
+	}
+	checker, err := resources.NewIntegrityChecker(client, opts)
+	if err != nil {
+		logger.Fatal("could not create integrity checker", zap.Error(err))
+	}
+
+	if *mode != modeVerify {
+		if err := checker.Write(); err != nil {
+			logger.Fatal("could not write workload", zap.Error(err))
+		}
+		first := checker.Workload().Prom
+		if len(first) > 0 && len(first[0].Datapoints) > 0 {
+			logger.Info("wrote workload",
+				zap.Int64("seed", *seed),
+				zap.Int64("start", first[0].Datapoints[0].Timestamp.Unix()))
+		}
+	}
+	if *mode != modeWrite {
+		if err := checker.Verify(); err != nil {
+			logger.Fatal("integrity check failed", zap.Error(err))
+		}
+		logger.Info("integrity check passed")
+	}
+}
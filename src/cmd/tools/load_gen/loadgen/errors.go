@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrorType categorizes the errors of a load test.
+type ErrorType string
+
+const (
+	// ErrorTypeTimeout is a request that timed out.
+	ErrorTypeTimeout ErrorType = "timeout"
+	// ErrorTypeConnection is a failure to connect or a dropped connection.
+	ErrorTypeConnection ErrorType = "connection"
+	// ErrorTypeRateLimited is a request rejected by a rate limit.
+	ErrorTypeRateLimited ErrorType = "rate-limited"
+	// ErrorTypeClient is a request rejected as invalid.
+	ErrorTypeClient ErrorType = "client-error"
+	// ErrorTypeServer is a request that failed on the server.
+	ErrorTypeServer ErrorType = "server-error"
+	// ErrorTypeCanceled is a request canceled before completing.
+	ErrorTypeCanceled ErrorType = "canceled"
+	// ErrorTypeDropped is a batch accepted by a client but dropped before
+	// being delivered.
+	ErrorTypeDropped ErrorType = "dropped"
+	// ErrorTypeOther is any other error.
+	ErrorTypeOther ErrorType = "other"
+)
+
+// StatusError is returned by targets for requests that completed with an
+// unsuccessful HTTP status code.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status code %d: %s", e.StatusCode, e.Message)
+}
+
+// FlushError is returned by targets for batches accepted by a client that
+// failed to be delivered when flushing the client.
+type FlushError struct {
+	Err error
+}
+
+func (e *FlushError) Error() string {
+	return fmt.Sprintf("flush failed: %v", e.Err)
+}
+
+// Unwrap returns the error of the flush.
+func (e *FlushError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns the type of an error returned by a target.
+func ClassifyError(err error) ErrorType {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorTypeRateLimited
+		case statusErr.StatusCode == http.StatusRequestTimeout ||
+			statusErr.StatusCode == http.StatusGatewayTimeout:
+			return ErrorTypeTimeout
+		case statusErr.StatusCode >= 400 && statusErr.StatusCode < 500:
+			return ErrorTypeClient
+		case statusErr.StatusCode >= 500:
+			return ErrorTypeServer
+		}
+		return ErrorTypeOther
+	}
+
+	var flushErr *FlushError
+	if errors.As(err, &flushErr) {
+		// NB: the aggregator client drops the buffered writes it fails to
+		// flush, connection errors are only logged by the client.
+		return ErrorTypeDropped
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorTypeCanceled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorTypeTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorTypeConnection
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorTypeConnection
+	}
+	return ErrorTypeOther
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/x/clock"
+)
+
+const (
+	defaultWorkers   = 10
+	defaultBatchSize = 100
+	defaultDuration  = time.Minute
+)
+
+var (
+	errInvalidWorkers    = errors.New("workers must be positive")
+	errInvalidBatchSize  = errors.New("batch size must be positive")
+	errInvalidTargetRate = errors.New("target rate must not be negative")
+	errInvalidDuration   = errors.New("duration must be positive")
+)
+
+// RunOptions configures how a workload is sent to a target.
+type RunOptions struct {
+	// Workers is the number of concurrent writers.
+	Workers int
+	// BatchSize is the number of samples in each write.
+	BatchSize int
+	// TargetRate is the number of samples per second to send across all
+	// workers, zero sends as fast as the target accepts them.
+	TargetRate int
+	// Duration is how long to run for.
+	Duration time.Duration
+	// NowFn returns the current time.
+	NowFn clock.NowFn
+}
+
+// NewRunOptions returns the default run options.
+func NewRunOptions() RunOptions {
+	return RunOptions{
+		Workers:   defaultWorkers,
+		BatchSize: defaultBatchSize,
+		Duration:  defaultDuration,
+		NowFn:     time.Now,
+	}
+}
+
+// Validate validates the run options.
+func (o RunOptions) Validate() error {
+	if o.Workers <= 0 {
+		return errInvalidWorkers
+	}
+	if o.BatchSize <= 0 {
+		return errInvalidBatchSize
+	}
+	if o.TargetRate < 0 {
+		return errInvalidTargetRate
+	}
+	if o.Duration <= 0 {
+		return errInvalidDuration
+	}
+	return nil
+}
+
+// Report summarizes a load test.
+type Report struct {
+	Duration       time.Duration
+	Writes         int64
+	FailedWrites   int64
+	Samples        int64
+	FailedSamples  int64
+	LateSamples    int64
+	ChurnedSeries  int64
+	Errors         map[ErrorType]int64
+	maxWriteTime   time.Duration
+	totalWriteTime time.Duration
+}
+
+// Throughput returns the number of samples successfully written per second.
+func (r Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Samples-r.FailedSamples) / r.Duration.Seconds()
+}
+
+// MeanWriteTime returns the mean time taken by a write.
+func (r Report) MeanWriteTime() time.Duration {
+	if r.Writes == 0 {
+		return 0
+	}
+	return r.totalWriteTime / time.Duration(r.Writes)
+}
+
+// MaxWriteTime returns the maximum time taken by a write.
+func (r Report) MaxWriteTime() time.Duration {
+	return r.maxWriteTime
+}
+
+// Print writes a human readable summary of the report.
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "duration:         %v\n", r.Duration)
+	fmt.Fprintf(w, "writes:           %d (%d failed)\n", r.Writes, r.FailedWrites)
+	fmt.Fprintf(w, "samples:          %d (%d failed, %d late)\n",
+		r.Samples, r.FailedSamples, r.LateSamples)
+	fmt.Fprintf(w, "churned series:   %d\n", r.ChurnedSeries)
+	fmt.Fprintf(w, "throughput:       %.2f samples/s\n", r.Throughput())
+	fmt.Fprintf(w, "write time:       mean %v, max %v\n", r.MeanWriteTime(), r.MaxWriteTime())
+
+	if len(r.Errors) == 0 {
+		return
+	}
+	types := make([]string, 0, len(r.Errors))
+	for t := range r.Errors {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	fmt.Fprintln(w, "errors:")
+	for _, t := range types {
+		fmt.Fprintf(w, "  %-16s%d\n", t+":", r.Errors[ErrorType(t)])
+	}
+}
+
+func (r *Report) add(other Report) {
+	r.Writes += other.Writes
+	r.FailedWrites += other.FailedWrites
+	r.Samples += other.Samples
+	r.FailedSamples += other.FailedSamples
+	r.LateSamples += other.LateSamples
+	r.totalWriteTime += other.totalWriteTime
+	if other.maxWriteTime > r.maxWriteTime {
+		r.maxWriteTime = other.maxWriteTime
+	}
+	for t, n := range other.Errors {
+		r.Errors[t] += n
+	}
+}
+
+// Run sends the workload to the target until the duration elapses or the
+// context is canceled, and reports what was achieved.
+func Run(
+	ctx context.Context,
+	workload *Workload,
+	target Target,
+	opts RunOptions,
+) (Report, error) {
+	if err := opts.Validate(); err != nil {
+		return Report{}, err
+	}
+	nowFn := opts.NowFn
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	// Each worker paces its writes so that the workers together send the
+	// target rate.
+	var interval time.Duration
+	if opts.TargetRate > 0 {
+		interval = time.Duration(float64(time.Second) *
+			float64(opts.BatchSize*opts.Workers) / float64(opts.TargetRate))
+	}
+
+	var (
+		start  = nowFn()
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		report = Report{Errors: make(map[ErrorType]int64)}
+	)
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerReport := runWorker(ctx, workload, target, opts.BatchSize, interval, nowFn)
+			lock.Lock()
+			report.add(workerReport)
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = nowFn().Sub(start)
+	report.ChurnedSeries = workload.NumChurned()
+	return report, nil
+}
+
+func runWorker(
+	ctx context.Context,
+	workload *Workload,
+	target Target,
+	batchSize int,
+	interval time.Duration,
+	nowFn clock.NowFn,
+) Report {
+	var (
+		report = Report{Errors: make(map[ErrorType]int64)}
+		ticker *time.Ticker
+	)
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+	}
+
+	for {
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+				return report
+			case <-ticker.C:
+			}
+		} else if ctx.Err() != nil {
+			return report
+		}
+
+		samples := workload.Next(nowFn(), batchSize)
+		start := nowFn()
+		err := target.Write(ctx, samples)
+		took := nowFn().Sub(start)
+		if err != nil && ctx.Err() != nil {
+			// Writes interrupted by the end of the run are not failures.
+			return report
+		}
+
+		report.Writes++
+		report.Samples += int64(len(samples))
+		report.totalWriteTime += took
+		if took > report.maxWriteTime {
+			report.maxWriteTime = took
+		}
+		for _, s := range samples {
+			if s.Late {
+				report.LateSamples++
+			}
+		}
+		if err != nil {
+			report.FailedWrites++
+			report.FailedSamples += int64(len(samples))
+			report.Errors[ClassifyError(err)]++
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	aggclient "github.com/m3db/m3/src/aggregator/client"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+func TestRunRemoteWrite(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		requests int
+		series   = make(map[string]struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		require.Equal(t, "bar", r.Header.Get("X-Foo"))
+		compressed, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, req.Unmarshal(data))
+
+		lock.Lock()
+		defer lock.Unlock()
+		requests++
+		for _, ts := range req.Timeseries {
+			require.Len(t, ts.Samples, 1)
+			var b bytes.Buffer
+			for _, l := range ts.Labels {
+				b.Write(l.Name)
+				b.Write(l.Value)
+			}
+			series[b.String()] = struct{}{}
+		}
+		// Reject every other request.
+		if requests%2 == 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	workloadOpts := NewWorkloadOptions()
+	workloadOpts.Cardinality = 50
+	workload, err := NewWorkload(workloadOpts)
+	require.NoError(t, err)
+
+	target := NewRemoteWriteTarget(server.URL, server.Client(), map[string]string{"X-Foo": "bar"})
+	defer target.Close()
+
+	runOpts := NewRunOptions()
+	runOpts.Workers = 2
+	runOpts.BatchSize = 10
+	runOpts.TargetRate = 1000
+	runOpts.Duration = 200 * time.Millisecond
+	report, err := Run(context.Background(), workload, target, runOpts)
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.True(t, report.Writes > 0)
+	require.Equal(t, int64(requests), report.Writes)
+	require.Equal(t, report.Writes*10, report.Samples)
+	require.Equal(t, int64(requests/2), report.FailedWrites)
+	require.Equal(t, report.FailedWrites*10, report.FailedSamples)
+	require.Equal(t, map[ErrorType]int64{ErrorTypeRateLimited: report.FailedWrites}, report.Errors)
+	require.Len(t, series, 50)
+	// Allow for the pacing of the workers being off by a write each.
+	require.True(t, report.Samples <= 2*10*int64(1+runOpts.Duration/(20*time.Millisecond)))
+}
+
+func TestAggregatorTargetFlushesBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	samples := []Sample{
+		{Labels: []Label{{Name: "__name__", Value: "foo"}}, TimeNanos: 1, Value: 1},
+		{Labels: []Label{{Name: "__name__", Value: "bar"}}, TimeNanos: 1, Value: 2},
+	}
+	flushErr := errors.New("writer queue is full")
+	client := aggclient.NewMockClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().WriteTimed(gomock.Any(), gomock.Any()).Return(nil).Times(2),
+		client.EXPECT().Flush().Return(nil),
+		client.EXPECT().WriteTimed(gomock.Any(), gomock.Any()).Return(nil).Times(2),
+		client.EXPECT().Flush().Return(flushErr),
+		client.EXPECT().Close().Return(nil),
+	)
+
+	target := NewAggregatorTarget(client, policy.MustParseStoragePolicy("10s:2d"))
+	require.NoError(t, target.Write(context.Background(), samples))
+
+	// Batches the client fails to flush are reported as dropped.
+	err := target.Write(context.Background(), samples)
+	require.True(t, errors.Is(err, flushErr))
+	require.Equal(t, ErrorTypeDropped, ClassifyError(err))
+	require.NoError(t, target.Close())
+}
+
+func TestClassifyError(t *testing.T) {
+	for _, test := range []struct {
+		err      error
+		expected ErrorType
+	}{
+		{&StatusError{StatusCode: http.StatusTooManyRequests}, ErrorTypeRateLimited},
+		{&StatusError{StatusCode: http.StatusBadRequest}, ErrorTypeClient},
+		{&StatusError{StatusCode: http.StatusGatewayTimeout}, ErrorTypeTimeout},
+		{&StatusError{StatusCode: http.StatusServiceUnavailable}, ErrorTypeServer},
+		{context.DeadlineExceeded, ErrorTypeTimeout},
+		{context.Canceled, ErrorTypeCanceled},
+		{syscall.ECONNREFUSED, ErrorTypeConnection},
+		{&FlushError{Err: errors.New("writer queue is full")}, ErrorTypeDropped},
+		{errors.New("foo"), ErrorTypeOther},
+	} {
+		require.Equal(t, test.expected, ClassifyError(test.err), test.err.Error())
+	}
+}
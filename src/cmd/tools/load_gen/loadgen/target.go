@@ -0,0 +1,208 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/snappy"
+
+	aggclient "github.com/m3db/m3/src/aggregator/client"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/metric/aggregated"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+)
+
+const maxErrorMessageBytes = 1024
+
+// Target is a destination of generated samples.
+type Target interface {
+	// Write writes a batch of samples.
+	Write(ctx context.Context, samples []Sample) error
+
+	// Close closes the target.
+	Close() error
+}
+
+type remoteWriteTarget struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+// NewRemoteWriteTarget returns a target writing samples to a Prometheus
+// remote write endpoint, such as the coordinator's /api/v1/prom/remote/write.
+func NewRemoteWriteTarget(
+	url string,
+	client *http.Client,
+	headers map[string]string,
+) Target {
+	return &remoteWriteTarget{
+		url:     url,
+		client:  client,
+		headers: headers,
+	}
+}
+
+func (t *remoteWriteTarget) Write(ctx context.Context, samples []Sample) error {
+	req := prompb.WriteRequest{
+		Timeseries: make([]prompb.TimeSeries, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		labels := make([]prompb.Label, 0, len(sample.Labels))
+		for _, l := range sample.Labels {
+			labels = append(labels, prompb.Label{
+				Name:  []byte(l.Name),
+				Value: []byte(l.Value),
+			})
+		}
+		req.Timeseries = append(req.Timeseries, prompb.TimeSeries{
+			Labels: labels,
+			Samples: []prompb.Sample{{
+				Value:     sample.Value,
+				Timestamp: sample.TimeNanos / int64(time.Millisecond),
+			}},
+		})
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url,
+		bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range t.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		// Drain the body so the connection can be reused.
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageBytes))
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Message:    string(bytes.TrimSpace(body)),
+	}
+}
+
+func (t *remoteWriteTarget) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+type aggregatorTarget struct {
+	client        aggclient.Client
+	storagePolicy policy.StoragePolicy
+	encoderPool   serialize.TagEncoderPool
+}
+
+// NewAggregatorTarget returns a target writing samples as timed gauges with
+// the aggregator client. The IDs of the samples are encoded the same way as
+// the coordinator encodes the IDs of the metrics it sends to aggregators.
+// The client is flushed after every batch so that a write completes once the
+// batch is sent to the aggregators rather than once it is buffered by the
+// client. The client must already be initialized.
+func NewAggregatorTarget(
+	client aggclient.Client,
+	storagePolicy policy.StoragePolicy,
+) Target {
+	encoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions())
+	encoderPool.Init()
+	return &aggregatorTarget{
+		client:        client,
+		storagePolicy: storagePolicy,
+		encoderPool:   encoderPool,
+	}
+}
+
+func (t *aggregatorTarget) Write(ctx context.Context, samples []Sample) error {
+	encoder := t.encoderPool.Get()
+	defer encoder.Finalize()
+
+	meta := metadata.TimedMetadata{
+		AggregationID: aggregation.DefaultID,
+		StoragePolicy: t.storagePolicy,
+	}
+	for _, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tags := make([]ident.Tag, 0, len(sample.Labels))
+		for _, l := range sample.Labels {
+			tags = append(tags, ident.StringTag(l.Name, l.Value))
+		}
+		encoder.Reset()
+		if err := encoder.Encode(ident.NewTagsIterator(ident.NewTags(tags...))); err != nil {
+			return err
+		}
+		data, ok := encoder.Data()
+		if !ok {
+			return fmt.Errorf("unable to encode tags of series")
+		}
+
+		err := t.client.WriteTimed(aggregated.Metric{
+			ID:        data.Bytes(),
+			Type:      metric.GaugeType,
+			TimeNanos: sample.TimeNanos,
+			Value:     sample.Value,
+		}, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := t.client.Flush(); err != nil {
+		return &FlushError{Err: err}
+	}
+	return nil
+}
+
+func (t *aggregatorTarget) Close() error {
+	return t.client.Close()
+}
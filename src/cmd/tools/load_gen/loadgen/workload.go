@@ -0,0 +1,228 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package loadgen generates configurable write workloads against the
+// coordinator remote write endpoint and the aggregator client protocol.
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetricName      = "loadgen_metric"
+	defaultCardinality     = 1000
+	defaultLabelsPerSeries = 5
+	defaultChurnInterval   = time.Minute
+
+	// seriesLabelName is the label holding the identity of a series, which
+	// changes each time the series churns.
+	seriesLabelName = "series"
+)
+
+var (
+	errInvalidCardinality        = errors.New("cardinality must be positive")
+	errInvalidLabelsPerSeries    = errors.New("labels per series must not be negative")
+	errInvalidChurnPercent       = errors.New("churn percent must be between 0 and 100")
+	errInvalidChurnInterval      = errors.New("churn interval must be positive when churning series")
+	errInvalidLateArrivalPercent = errors.New("late arrival percent must be between 0 and 100")
+	errInvalidMaxLateArrival     = errors.New("max late arrival must be positive when sending late samples")
+)
+
+// WorkloadOptions configures the shape of a generated workload.
+type WorkloadOptions struct {
+	// MetricName is the name of every generated series.
+	MetricName string
+	// Cardinality is the number of distinct series active at any time.
+	Cardinality int
+	// LabelsPerSeries is the number of labels, besides the name and series
+	// identity, of every generated series.
+	LabelsPerSeries int
+	// ChurnPercent is the percentage of the active series replaced by new
+	// series every churn interval.
+	ChurnPercent float64
+	// ChurnInterval is the interval at which series are churned.
+	ChurnInterval time.Duration
+	// LateArrivalPercent is the percentage of samples timestamped in the past.
+	LateArrivalPercent float64
+	// MaxLateArrival is the maximum age of late samples.
+	MaxLateArrival time.Duration
+	// Seed seeds the values and late arrivals of the workload.
+	Seed int64
+}
+
+// NewWorkloadOptions returns the default workload options.
+func NewWorkloadOptions() WorkloadOptions {
+	return WorkloadOptions{
+		MetricName:      defaultMetricName,
+		Cardinality:     defaultCardinality,
+		LabelsPerSeries: defaultLabelsPerSeries,
+		ChurnInterval:   defaultChurnInterval,
+		Seed:            time.Now().UnixNano(),
+	}
+}
+
+// Validate validates the workload options.
+func (o WorkloadOptions) Validate() error {
+	if o.Cardinality <= 0 {
+		return errInvalidCardinality
+	}
+	if o.LabelsPerSeries < 0 {
+		return errInvalidLabelsPerSeries
+	}
+	if o.ChurnPercent < 0 || o.ChurnPercent > 100 {
+		return errInvalidChurnPercent
+	}
+	if o.ChurnPercent > 0 && o.ChurnInterval <= 0 {
+		return errInvalidChurnInterval
+	}
+	if o.LateArrivalPercent < 0 || o.LateArrivalPercent > 100 {
+		return errInvalidLateArrivalPercent
+	}
+	if o.LateArrivalPercent > 0 && o.MaxLateArrival <= 0 {
+		return errInvalidMaxLateArrival
+	}
+	return nil
+}
+
+// Label is a name value pair of a series.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single generated datapoint.
+type Sample struct {
+	// Labels of the series, sorted by name and including the metric name.
+	Labels    []Label
+	TimeNanos int64
+	Value     float64
+	// Late is true if the sample was deliberately timestamped in the past.
+	Late bool
+}
+
+// Workload generates samples for a fixed number of active series, churning
+// a share of them every churn interval. It is safe for concurrent use.
+type Workload struct {
+	sync.Mutex
+
+	opts        WorkloadOptions
+	rng         *rand.Rand
+	generations []uint64
+	next        int
+	churnNext   int
+	lastChurn   time.Time
+	numChurned  int64
+}
+
+// NewWorkload returns a new workload.
+func NewWorkload(opts WorkloadOptions) (*Workload, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &Workload{
+		opts:        opts,
+		rng:         rand.New(rand.NewSource(opts.Seed)),
+		generations: make([]uint64, opts.Cardinality),
+	}, nil
+}
+
+// Next returns the next n samples of the workload at the given time, cycling
+// through the active series.
+func (w *Workload) Next(now time.Time, n int) []Sample {
+	w.Lock()
+	defer w.Unlock()
+
+	w.churnWithLock(now)
+
+	samples := make([]Sample, 0, n)
+	for i := 0; i < n; i++ {
+		slot := w.next
+		w.next = (w.next + 1) % len(w.generations)
+
+		sample := Sample{
+			Labels:    w.labels(slot),
+			TimeNanos: now.UnixNano(),
+			Value:     w.rng.Float64() * 100,
+		}
+		if w.opts.LateArrivalPercent > 0 && w.rng.Float64()*100 < w.opts.LateArrivalPercent {
+			late := time.Duration(w.rng.Int63n(int64(w.opts.MaxLateArrival))) + 1
+			sample.TimeNanos -= int64(late)
+			sample.Late = true
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// NumChurned returns the number of series replaced by new series so far.
+func (w *Workload) NumChurned() int64 {
+	w.Lock()
+	defer w.Unlock()
+	return w.numChurned
+}
+
+func (w *Workload) churnWithLock(now time.Time) {
+	if w.opts.ChurnPercent <= 0 {
+		return
+	}
+	if w.lastChurn.IsZero() {
+		w.lastChurn = now
+		return
+	}
+	numSeries := int(float64(len(w.generations)) * w.opts.ChurnPercent / 100)
+	for now.Sub(w.lastChurn) >= w.opts.ChurnInterval {
+		w.lastChurn = w.lastChurn.Add(w.opts.ChurnInterval)
+		// Replace series in a round robin fashion so that every series lives
+		// for the same number of churn intervals.
+		for i := 0; i < numSeries; i++ {
+			w.generations[w.churnNext]++
+			w.churnNext = (w.churnNext + 1) % len(w.generations)
+		}
+		w.numChurned += int64(numSeries)
+	}
+}
+
+func (w *Workload) labels(slot int) []Label {
+	var (
+		id     = w.generations[slot]*uint64(len(w.generations)) + uint64(slot)
+		labels = make([]Label, 0, w.opts.LabelsPerSeries+2)
+	)
+	labels = append(labels, Label{Name: "__name__", Value: w.opts.MetricName})
+	for i := 0; i < w.opts.LabelsPerSeries; i++ {
+		// Vary the label values at different rates so their cardinalities
+		// resemble those of real world labels.
+		labels = append(labels, Label{
+			Name:  fmt.Sprintf("label_%d", i),
+			Value: "value_" + strconv.FormatUint(id%uint64(10*(i+1)), 10),
+		})
+	}
+	labels = append(labels, Label{Name: seriesLabelName, Value: strconv.FormatUint(id, 10)})
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return labels
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSeriesIDs(samples []Sample) map[string]struct{} {
+	ids := make(map[string]struct{}, len(samples))
+	for _, s := range samples {
+		for _, l := range s.Labels {
+			if l.Name == seriesLabelName {
+				ids[l.Value] = struct{}{}
+			}
+		}
+	}
+	return ids
+}
+
+func TestWorkloadCardinality(t *testing.T) {
+	opts := NewWorkloadOptions()
+	opts.Cardinality = 10
+	opts.LabelsPerSeries = 12
+	w, err := NewWorkload(opts)
+	require.NoError(t, err)
+
+	now := time.Now()
+	samples := w.Next(now, 25)
+	require.Len(t, samples, 25)
+	require.Len(t, testSeriesIDs(samples), 10)
+	for _, s := range samples {
+		require.Equal(t, now.UnixNano(), s.TimeNanos)
+		require.False(t, s.Late)
+		require.Len(t, s.Labels, 14)
+		for i := 1; i < len(s.Labels); i++ {
+			require.True(t, s.Labels[i-1].Name < s.Labels[i].Name)
+		}
+	}
+}
+
+func TestWorkloadChurn(t *testing.T) {
+	opts := NewWorkloadOptions()
+	opts.Cardinality = 10
+	opts.ChurnPercent = 20
+	opts.ChurnInterval = time.Minute
+	w, err := NewWorkload(opts)
+	require.NoError(t, err)
+
+	now := time.Now()
+	before := testSeriesIDs(w.Next(now, 10))
+
+	// Not churned before the churn interval elapses.
+	require.Equal(t, before, testSeriesIDs(w.Next(now.Add(30*time.Second), 10)))
+	require.Equal(t, int64(0), w.NumChurned())
+
+	after := testSeriesIDs(w.Next(now.Add(2*time.Minute), 10))
+	require.Len(t, after, 10)
+	require.Equal(t, int64(4), w.NumChurned())
+	var numNew int
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			numNew++
+		}
+	}
+	require.Equal(t, 4, numNew)
+}
+
+func TestWorkloadLateArrivals(t *testing.T) {
+	opts := NewWorkloadOptions()
+	opts.LateArrivalPercent = 100
+	opts.MaxLateArrival = time.Minute
+	w, err := NewWorkload(opts)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, s := range w.Next(now, 100) {
+		require.True(t, s.Late)
+		require.True(t, s.TimeNanos < now.UnixNano())
+		require.True(t, s.TimeNanos >= now.Add(-time.Minute).UnixNano())
+	}
+}
+
+func TestWorkloadOptionsValidate(t *testing.T) {
+	opts := NewWorkloadOptions()
+	require.NoError(t, opts.Validate())
+
+	invalid := opts
+	invalid.Cardinality = 0
+	require.Equal(t, errInvalidCardinality, invalid.Validate())
+
+	invalid = opts
+	invalid.ChurnPercent = 10
+	invalid.ChurnInterval = 0
+	require.Equal(t, errInvalidChurnInterval, invalid.Validate())
+
+	invalid = opts
+	invalid.LateArrivalPercent = 10
+	require.Equal(t, errInvalidMaxLateArrival, invalid.Validate())
+}
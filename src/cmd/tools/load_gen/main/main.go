@@ -0,0 +1,168 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// load_gen is a tool for generating write load against coordinators and
+// aggregators.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	aggclient "github.com/m3db/m3/src/aggregator/client"
+	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	"github.com/m3db/m3/src/cmd/tools/load_gen/loadgen"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/x/clock"
+	xconfig "github.com/m3db/m3/src/x/config"
+	"github.com/m3db/m3/src/x/instrument"
+	xio "github.com/m3db/m3/src/x/io"
+)
+
+const (
+	targetRemoteWrite = "remote-write"
+	targetAggregator  = "aggregator"
+)
+
+// aggregatorConfiguration is the configuration of the aggregator target.
+type aggregatorConfiguration struct {
+	KV     etcdclient.Configuration `yaml:"kv"`
+	Client aggclient.Configuration  `yaml:"client"`
+}
+
+func main() {
+	var (
+		workloadOpts = loadgen.NewWorkloadOptions()
+		runOpts      = loadgen.NewRunOptions()
+
+		targetArg           = flag.String("target", targetRemoteWrite, "Target protocol, one of remote-write or aggregator")
+		urlArg              = flag.String("url", "http://0.0.0.0:7201/api/v1/prom/remote/write", "Remote write URL")
+		headersArg          = flag.String("headers", "", "Comma separated name=value headers sent with remote writes")
+		timeoutArg          = flag.Duration("timeout", 10*time.Second, "Remote write request timeout")
+		aggregatorConfigArg = flag.String("aggregatorConfig", "", "Aggregator client YAML configuration file, with kv and client sections")
+		storagePolicyArg    = flag.String("storagePolicy", "10s:2d", "Storage policy of samples written to aggregators")
+	)
+	flag.StringVar(&workloadOpts.MetricName, "name", workloadOpts.MetricName, "Metric name")
+	flag.IntVar(&workloadOpts.Cardinality, "cardinality", workloadOpts.Cardinality, "Number of active series")
+	flag.IntVar(&workloadOpts.LabelsPerSeries, "labels", workloadOpts.LabelsPerSeries, "Number of labels per series")
+	flag.Float64Var(&workloadOpts.ChurnPercent, "churnPercent", workloadOpts.ChurnPercent, "Percentage of series replaced every churn interval")
+	flag.DurationVar(&workloadOpts.ChurnInterval, "churnInterval", workloadOpts.ChurnInterval, "Interval at which series are churned")
+	flag.Float64Var(&workloadOpts.LateArrivalPercent, "latePercent", workloadOpts.LateArrivalPercent, "Percentage of samples timestamped in the past")
+	flag.DurationVar(&workloadOpts.MaxLateArrival, "maxLate", workloadOpts.MaxLateArrival, "Maximum age of late samples")
+	flag.Int64Var(&workloadOpts.Seed, "seed", workloadOpts.Seed, "Seed of the generated values and late arrivals")
+	flag.IntVar(&runOpts.Workers, "workers", runOpts.Workers, "Number of concurrent writers")
+	flag.IntVar(&runOpts.BatchSize, "batchSize", runOpts.BatchSize, "Number of samples per write")
+	flag.IntVar(&runOpts.TargetRate, "rate", runOpts.TargetRate, "Target samples per second, 0 for unlimited")
+	flag.DurationVar(&runOpts.Duration, "duration", runOpts.Duration, "Duration of test")
+	flag.Parse()
+
+	workload, err := loadgen.NewWorkload(workloadOpts)
+	if err != nil {
+		log.Fatalf("invalid workload: %v", err)
+	}
+
+	var target loadgen.Target
+	switch *targetArg {
+	case targetRemoteWrite:
+		headers, err := parseHeaders(*headersArg)
+		if err != nil {
+			log.Fatalf("invalid headers: %v", err)
+		}
+		target = loadgen.NewRemoteWriteTarget(*urlArg, &http.Client{Timeout: *timeoutArg}, headers)
+	case targetAggregator:
+		target, err = newAggregatorTarget(*aggregatorConfigArg, *storagePolicyArg)
+		if err != nil {
+			log.Fatalf("unable to create aggregator target: %v", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+		fmt.Println("beginning shutdown...")
+		cancel()
+	}()
+
+	report, err := loadgen.Run(ctx, workload, target, runOpts)
+	if err != nil {
+		log.Fatalf("unable to run: %v", err)
+	}
+	if err := target.Close(); err != nil {
+		log.Printf("unable to close target: %v", err)
+	}
+	report.Print(os.Stdout)
+}
+
+func parseHeaders(str string) (map[string]string, error) {
+	headers := make(map[string]string)
+	if str == "" {
+		return headers, nil
+	}
+	for _, header := range strings.Split(str, ",") {
+		parts := strings.SplitN(header, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("header %q is not of the form name=value", header)
+		}
+		headers[parts[0]] = parts[1]
+	}
+	return headers, nil
+}
+
+func newAggregatorTarget(configFile, storagePolicy string) (loadgen.Target, error) {
+	if configFile == "" {
+		return nil, fmt.Errorf("aggregator config file is required")
+	}
+	sp, err := policy.ParseStoragePolicy(storagePolicy)
+	if err != nil {
+		return nil, err
+	}
+	var cfg aggregatorConfiguration
+	if err := xconfig.LoadFile(&cfg, configFile, xconfig.Options{}); err != nil {
+		return nil, err
+	}
+
+	iOpts := instrument.NewOptions()
+	kvClient, err := cfg.KV.NewClient(iOpts)
+	if err != nil {
+		return nil, err
+	}
+	client, err := cfg.Client.NewClient(kvClient, clock.NewOptions(), iOpts, xio.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Init(); err != nil {
+		return nil, err
+	}
+	return loadgen.NewAggregatorTarget(client, sp), nil
+}
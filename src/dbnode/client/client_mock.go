@@ -1199,6 +1199,20 @@ func (mr *MockOptionsMockRecorder) AsyncWriteMaxConcurrency() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncWriteMaxConcurrency", reflect.TypeOf((*MockOptions)(nil).AsyncWriteMaxConcurrency))
 }
 
+// AsyncWritePercent mocks base method.
+func (m *MockOptions) AsyncWritePercent() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AsyncWritePercent")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// AsyncWritePercent indicates an expected call of AsyncWritePercent.
+func (mr *MockOptionsMockRecorder) AsyncWritePercent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncWritePercent", reflect.TypeOf((*MockOptions)(nil).AsyncWritePercent))
+}
+
 // AsyncWriteWorkerPool mocks base method.
 func (m *MockOptions) AsyncWriteWorkerPool() sync.PooledWorkerPool {
 	m.ctrl.T.Helper()
@@ -1773,6 +1787,20 @@ func (mr *MockOptionsMockRecorder) SetAsyncWriteMaxConcurrency(value interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAsyncWriteMaxConcurrency", reflect.TypeOf((*MockOptions)(nil).SetAsyncWriteMaxConcurrency), value)
 }
 
+// SetAsyncWritePercent mocks base method.
+func (m *MockOptions) SetAsyncWritePercent(value float64) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAsyncWritePercent", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetAsyncWritePercent indicates an expected call of SetAsyncWritePercent.
+func (mr *MockOptionsMockRecorder) SetAsyncWritePercent(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAsyncWritePercent", reflect.TypeOf((*MockOptions)(nil).SetAsyncWritePercent), value)
+}
+
 // SetAsyncWriteWorkerPool mocks base method.
 func (m *MockOptions) SetAsyncWriteWorkerPool(value sync.PooledWorkerPool) Options {
 	m.ctrl.T.Helper()
@@ -2874,6 +2902,20 @@ func (mr *MockAdminOptionsMockRecorder) AsyncWriteMaxConcurrency() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncWriteMaxConcurrency", reflect.TypeOf((*MockAdminOptions)(nil).AsyncWriteMaxConcurrency))
 }
 
+// AsyncWritePercent mocks base method.
+func (m *MockAdminOptions) AsyncWritePercent() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AsyncWritePercent")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// AsyncWritePercent indicates an expected call of AsyncWritePercent.
+func (mr *MockAdminOptionsMockRecorder) AsyncWritePercent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncWritePercent", reflect.TypeOf((*MockAdminOptions)(nil).AsyncWritePercent))
+}
+
 // AsyncWriteWorkerPool mocks base method.
 func (m *MockAdminOptions) AsyncWriteWorkerPool() sync.PooledWorkerPool {
 	m.ctrl.T.Helper()
@@ -3546,6 +3588,20 @@ func (mr *MockAdminOptionsMockRecorder) SetAsyncWriteMaxConcurrency(value interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAsyncWriteMaxConcurrency", reflect.TypeOf((*MockAdminOptions)(nil).SetAsyncWriteMaxConcurrency), value)
 }
 
+// SetAsyncWritePercent mocks base method.
+func (m *MockAdminOptions) SetAsyncWritePercent(value float64) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAsyncWritePercent", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetAsyncWritePercent indicates an expected call of SetAsyncWritePercent.
+func (mr *MockAdminOptionsMockRecorder) SetAsyncWritePercent(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAsyncWritePercent", reflect.TypeOf((*MockAdminOptions)(nil).SetAsyncWritePercent), value)
+}
+
 // SetAsyncWriteWorkerPool mocks base method.
 func (m *MockAdminOptions) SetAsyncWriteWorkerPool(value sync.PooledWorkerPool) Options {
 	m.ctrl.T.Helper()
@@ -21,8 +21,11 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
@@ -37,6 +40,7 @@ import (
 	"github.com/m3db/m3/src/x/retry"
 	"github.com/m3db/m3/src/x/sampler"
 	xsync "github.com/m3db/m3/src/x/sync"
+	xtls "github.com/m3db/m3/src/x/tls"
 )
 
 const (
@@ -117,6 +121,10 @@ type Configuration struct {
 	// ShardsLeavingCountTowardsConsistency sets whether or not writes to leaving shards
 	// count towards consistency, by default they do not.
 	ShardsLeavingCountTowardsConsistency *bool `yaml:"shardsLeavingCountTowardsConsistency"`
+
+	// TLS configures TLS for connections to nodes with TLS enabled, the client
+	// certificate identifies the client to the nodes.
+	TLS *xtls.ClientConfiguration `yaml:"tls"`
 }
 
 // ProtoConfiguration is the configuration for running with ProtoDataMode enabled.
@@ -437,6 +445,16 @@ func (c Configuration) NewAdminClient(
 	if c.ShardsLeavingCountTowardsConsistency != nil {
 		v = v.SetShardsLeavingCountTowardsConsistency(*c.ShardsLeavingCountTowardsConsistency)
 	}
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS config: %w", err)
+		}
+		// NB: Copy the channel options since they may be shared.
+		chanOpts := *v.ChannelOptions()
+		chanOpts.Dialer = newTLSDialer(tlsConfig)
+		v = v.SetChannelOptions(&chanOpts)
+	}
 
 	// Cast to admin options to apply admin config options.
 	opts := v.(AdminOptions)
@@ -460,3 +478,10 @@ func (c Configuration) NewAdminClient(
 	asyncClusterOpts := NewOptionsForAsyncClusters(opts, asyncTopoInits, asyncClientOverrides)
 	return NewAdminClient(opts, asyncClusterOpts...)
 }
+
+func newTLSDialer(
+	tlsConfig *tls.Config,
+) func(ctx context.Context, network, hostPort string) (net.Conn, error) {
+	dialer := &tls.Dialer{Config: tlsConfig}
+	return dialer.DialContext
+}
@@ -68,6 +68,7 @@ type fetchTaggedResultAccumulator struct {
 	exhaustive       bool
 	waitedIndex      int
 	waitedSeriesRead int
+	nextPageAfterID  []byte
 
 	startTime        xtime.UnixNano
 	endTime          xtime.UnixNano
@@ -107,6 +108,12 @@ func (accum *fetchTaggedResultAccumulator) AddFetchTaggedResponse(
 		if v := opts.response.WaitedSeriesRead; v != nil {
 			accum.waitedSeriesRead += int(*v)
 		}
+		// NB: each host returns a page of the series of its own shards, so the
+		// merged page can only be complete up to the smallest ID a host stopped at.
+		if v := opts.response.NextPageAfterID; v != nil &&
+			(accum.nextPageAfterID == nil || bytes.Compare(v, accum.nextPageAfterID) < 0) {
+			accum.nextPageAfterID = v
+		}
 		for _, elem := range opts.response.Elements {
 			accum.fetchResponses = append(accum.fetchResponses, elem)
 		}
@@ -258,6 +265,7 @@ func (accum *fetchTaggedResultAccumulator) Clear() {
 	accum.exhaustive = true
 	accum.waitedIndex = 0
 	accum.waitedSeriesRead = 0
+	accum.nextPageAfterID = nil
 	accum.calcTransport.Reset()
 }
 
@@ -271,6 +279,7 @@ func (accum *fetchTaggedResultAccumulator) Reset(
 	accum.exhaustive = true
 	accum.waitedIndex = 0
 	accum.waitedSeriesRead = 0
+	accum.nextPageAfterID = nil
 	accum.startTime = startTime
 	accum.endTime = endTime
 	accum.topoMap = topoMap
@@ -336,6 +345,12 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	return seriesIter
 }
 
+// inPage returns whether the series ID is part of the merged page of results,
+// series after the next page after ID are returned with the next page instead.
+func (accum *fetchTaggedResultAccumulator) inPage(id []byte) bool {
+	return accum.nextPageAfterID == nil || bytes.Compare(id, accum.nextPageAfterID) <= 0
+}
+
 func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 	limit int, pools fetchTaggedPools,
 	descr namespace.SchemaDescr, opts index.IterationOptions,
@@ -345,7 +360,10 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 	accum.fetchResponses = fetchTaggedIDResults(results)
 
 	numElements := 0
-	accum.fetchResponses.forEachID(func(_ fetchTaggedIDResults, _ bool) bool {
+	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, _ bool) bool {
+		if !accum.inPage(elems[0].ID) {
+			return false
+		}
 		numElements++
 		return numElements < limit
 	})
@@ -355,6 +373,11 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 	count := 0
 	moreElems := false
 	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, hasMore bool) bool {
+		if !accum.inPage(elems[0].ID) {
+			// NB: the remaining series are returned with the next page.
+			moreElems = false
+			return false
+		}
 		seriesIter := accum.sliceResponsesAsSeriesIter(pools, elems, descr, opts)
 		result.SetAt(count, seriesIter)
 		count++
@@ -369,6 +392,7 @@ func (accum *fetchTaggedResultAccumulator) AsEncodingSeriesIterators(
 		EstimateTotalBytes: accum.calcTransport.GetSize(),
 		WaitedIndex:        accum.waitedIndex,
 		WaitedSeriesRead:   accum.waitedSeriesRead,
+		NextPageAfterID:    accum.nextPageAfterID,
 	}, nil
 }
 
@@ -385,6 +409,11 @@ func (accum *fetchTaggedResultAccumulator) AsTaggedIDsIterator(
 	sort.Sort(results)
 	accum.fetchResponses = fetchTaggedIDResults(results)
 	accum.fetchResponses.forEachID(func(elems fetchTaggedIDResults, hasMore bool) bool {
+		if !accum.inPage(elems[0].ID) {
+			// NB: the remaining series are returned with the next page.
+			moreElems = false
+			return false
+		}
 		iter.addBacking(elems[0].NameSpace, elems[0].ID, elems[0].EncodedTags)
 		count++
 		moreElems = hasMore
@@ -398,6 +427,7 @@ func (accum *fetchTaggedResultAccumulator) AsTaggedIDsIterator(
 		EstimateTotalBytes: accum.calcTransport.GetSize(),
 		WaitedIndex:        accum.waitedIndex,
 		WaitedSeriesRead:   accum.waitedSeriesRead,
+		NextPageAfterID:    accum.nextPageAfterID,
 	}, nil
 }
 
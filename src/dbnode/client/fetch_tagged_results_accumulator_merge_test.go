@@ -184,6 +184,56 @@ func TestFetchTaggedResultsAccumulatorIdsMergeReportsExhaustiveCorrectly(t *test
 	newTestSerieses(1, 15).assertMatchesEncodingIters(t, iters)
 }
 
+func TestFetchTaggedResultsAccumulatorIdsMergePage(t *testing.T) {
+	// rf=1, each host returns a page of the series of its own shards
+	topoMap := testutil.MustNewTopologyMap(1, map[string][]shard.Shard{
+		"testhost0": testutil.ShardsRange(0, 14, shard.Available),
+		"testhost1": testutil.ShardsRange(15, 29, shard.Available),
+	})
+
+	th := newTestFetchTaggedHelper(t)
+	page0 := testSerieses{newTestSeries(1), newTestSeries(3), newTestSeries(5), newTestSeries(7)}.
+		toRPCResult(th, testStartTime, true)
+	page0.NextPageAfterID = newTestSeries(7).id.Bytes()
+	page1 := testSerieses{newTestSeries(2), newTestSeries(4), newTestSeries(6), newTestSeries(8)}.
+		toRPCResult(th, testStartTime, true)
+	page1.NextPageAfterID = newTestSeries(8).id.Bytes()
+	workflow := testFetchStateWorkflow{
+		t:         t,
+		topoMap:   topoMap,
+		level:     topology.ReadConsistencyLevelAll,
+		startTime: testStartTime,
+		endTime:   testEndTime,
+		steps: []testFetchStateWorklowStep{
+			{
+				hostname:          "testhost0",
+				fetchTaggedResult: page0,
+			},
+			{
+				hostname:          "testhost1",
+				fetchTaggedResult: page1,
+				expectedDone:      true,
+			},
+		},
+	}
+	accum := workflow.run()
+
+	// series after the smallest ID a host stopped at belong to the next page
+	resultsIter, resultsMetadata, err := accum.AsTaggedIDsIterator(100, th.pools)
+	require.NoError(t, err)
+	require.True(t, resultsMetadata.Exhaustive)
+	require.Equal(t, newTestSeries(7).id.Bytes(), resultsMetadata.NextPageAfterID)
+	matcher := newTestSerieses(1, 7).indexMatcher()
+	require.True(t, matcher.Matches(resultsIter))
+
+	iters, meta, err := accum.AsEncodingSeriesIterators(100, th.pools,
+		nil, index.IterationOptions{})
+	require.NoError(t, err)
+	require.True(t, meta.Exhaustive)
+	require.Equal(t, newTestSeries(7).id.Bytes(), meta.NextPageAfterID)
+	newTestSerieses(1, 7).assertMatchesEncodingIters(t, iters)
+}
+
 func TestFetchTaggedResultsAccumulatorSeriesItersDatapoints(t *testing.T) {
 	// rf=3, 3 identical hosts, with same shards
 	topoMap := testutil.MustNewTopologyMap(3, map[string][]shard.Shard{
@@ -168,6 +168,10 @@ const (
 	// defaultAsyncWriteMaxConcurrency is the default maximum concurrency for async writes.
 	defaultAsyncWriteMaxConcurrency = 4096
 
+	// defaultAsyncWritePercent is the default percentage of series whose
+	// writes are replicated to async clusters.
+	defaultAsyncWritePercent = 100.0
+
 	// defaultUseV2BatchAPIs is the default setting for whether the v2 version of the batch APIs should
 	// be used.
 	defaultUseV2BatchAPIs = false
@@ -229,6 +233,7 @@ var (
 
 	errNoTopologyInitializerSet    = errors.New("no topology initializer set")
 	errNoReaderIteratorAllocateSet = errors.New("no reader iterator allocator set, encoding not set")
+	errInvalidAsyncWritePercent    = errors.New("async write percent must be between 0 and 100")
 )
 
 type options struct {
@@ -293,6 +298,7 @@ type options struct {
 	asyncTopologyInitializers               []topology.Initializer
 	asyncWriteWorkerPool                    xsync.PooledWorkerPool
 	asyncWriteMaxConcurrency                int
+	asyncWritePercent                       float64
 	useV2BatchAPIs                          bool
 	iterationOptions                        index.IterationOptions
 	writeTimestampOffset                    time.Duration
@@ -322,6 +328,9 @@ func NewOptionsForAsyncClusters(opts Options, topoInits []topology.Initializer,
 		if overrides[i].TargetHostQueueFlushSize != nil {
 			options = options.SetHostQueueOpsFlushSize(*overrides[i].TargetHostQueueFlushSize)
 		}
+		if overrides[i].AsyncWritePercent != nil {
+			options = options.SetAsyncWritePercent(*overrides[i].AsyncWritePercent)
+		}
 		result = append(result, options)
 	}
 	return result
@@ -446,6 +455,7 @@ func newOptions() *options {
 		schemaRegistry:                          namespace.NewSchemaRegistry(false, nil),
 		asyncTopologyInitializers:               []topology.Initializer{},
 		asyncWriteMaxConcurrency:                defaultAsyncWriteMaxConcurrency,
+		asyncWritePercent:                       defaultAsyncWritePercent,
 		useV2BatchAPIs:                          defaultUseV2BatchAPIs,
 		thriftContextFn:                         defaultThriftContextFn,
 	}
@@ -479,6 +489,9 @@ func validate(opts *options) error {
 	); err != nil {
 		return err
 	}
+	if opts.asyncWritePercent < 0 || opts.asyncWritePercent > 100 {
+		return errInvalidAsyncWritePercent
+	}
 	return opts.logErrorSampleRate.Validate()
 }
 
@@ -1109,6 +1122,16 @@ func (o *options) AsyncWriteMaxConcurrency() int {
 	return o.asyncWriteMaxConcurrency
 }
 
+func (o *options) SetAsyncWritePercent(value float64) Options {
+	opts := *o
+	opts.asyncWritePercent = value
+	return &opts
+}
+
+func (o *options) AsyncWritePercent() float64 {
+	return o.asyncWritePercent
+}
+
 func (o *options) SetUseV2BatchAPIs(value bool) Options {
 	opts := *o
 	opts.useV2BatchAPIs = value
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 
@@ -42,11 +43,17 @@ import (
 
 type newSessionFn func(Options) (clientSession, error)
 
+// asyncWritePercentBuckets is the number of buckets series are hashed into to
+// sample writes replicated to async clusters, allowing percentages with two
+// decimal places.
+const asyncWritePercentBuckets = 10000
+
 // replicatedSession is an implementation of clientSession which replicates
 // session read/writes to a set of clusters asynchronously.
 type replicatedSession struct {
 	session              clientSession
 	asyncSessions        []clientSession
+	asyncReplications    []asyncReplication
 	newSessionFn         newSessionFn
 	identifierPool       ident.Pool
 	workerPool           m3sync.PooledWorkerPool
@@ -61,6 +68,7 @@ type replicatedSession struct {
 type replicatedSessionMetrics struct {
 	replicateExecuted    tally.Counter
 	replicateNotExecuted tally.Counter
+	replicateSkipped     tally.Counter
 	replicateError       tally.Counter
 	replicateSuccess     tally.Counter
 }
@@ -69,11 +77,32 @@ func newReplicatedSessionMetrics(scope tally.Scope) replicatedSessionMetrics {
 	return replicatedSessionMetrics{
 		replicateExecuted:    scope.Counter("replicate.executed"),
 		replicateNotExecuted: scope.Counter("replicate.not-executed"),
+		replicateSkipped:     scope.Counter("replicate.skipped"),
 		replicateError:       scope.Counter("replicate.error"),
 		replicateSuccess:     scope.Counter("replicate.success"),
 	}
 }
 
+// asyncReplication holds the per cluster state of replicating writes to an
+// async cluster, so that the writes to each cluster are accounted for
+// independently, e.g. when validating a migration to a new cluster.
+type asyncReplication struct {
+	writePercent float64
+	metrics      replicatedSessionMetrics
+}
+
+// shouldReplicate returns whether a write for the series should be replicated.
+// Series are sampled by ID rather than per write so that every write of a
+// sampled series is replicated, which allows the data of the series to be
+// compared between clusters.
+func (r asyncReplication) shouldReplicate(id ident.ID) bool {
+	if r.writePercent >= 100 {
+		return true
+	}
+	bucket := xxhash.Sum64(id.Bytes()) % asyncWritePercentBuckets
+	return float64(bucket) < r.writePercent*asyncWritePercentBuckets/100
+}
+
 // Ensure replicatedSession implements the clientSession interface.
 var _ clientSession = (*replicatedSession)(nil)
 
@@ -133,6 +162,7 @@ func (s *replicatedSession) setSession(opts Options) error {
 
 func (s *replicatedSession) setAsyncSessions(opts []Options) error {
 	sessions := make([]clientSession, 0, len(opts))
+	replications := make([]asyncReplication, 0, len(opts))
 	for i, oo := range opts {
 		subscope := oo.InstrumentOptions().MetricsScope().SubScope(fmt.Sprintf("async-%d", i))
 		oo = oo.SetInstrumentOptions(oo.InstrumentOptions().SetMetricsScope(subscope))
@@ -142,8 +172,13 @@ func (s *replicatedSession) setAsyncSessions(opts []Options) error {
 			return err
 		}
 		sessions = append(sessions, session)
+		replications = append(replications, asyncReplication{
+			writePercent: oo.AsyncWritePercent(),
+			metrics:      newReplicatedSessionMetrics(subscope),
+		})
 	}
 	s.asyncSessions = sessions
+	s.asyncReplications = replications
 	return nil
 }
 
@@ -161,8 +196,15 @@ type replicatedParams struct {
 // NB(srobb): it would be a nicer to accept a lambda which is the fn to
 // be performed on all sessions, however this causes an extra allocation.
 func (s replicatedSession) replicate(params replicatedParams) error {
-	for _, asyncSession := range s.asyncSessions {
+	for i, asyncSession := range s.asyncSessions {
 		asyncSession := asyncSession // capture var
+		clusterMetrics := s.asyncReplications[i].metrics
+
+		if !s.asyncReplications[i].shouldReplicate(params.id) {
+			s.metrics.replicateSkipped.Inc(1)
+			clusterMetrics.replicateSkipped.Inc(1)
+			continue
+		}
 
 		var (
 			clonedID   = s.identifierPool.Clone(params.id)
@@ -190,9 +232,11 @@ func (s replicatedSession) replicate(params replicatedParams) error {
 				}
 				if err != nil {
 					s.metrics.replicateError.Inc(1)
+					clusterMetrics.replicateError.Inc(1)
 					s.log.Error("could not replicate write", zap.Error(err))
 				} else {
 					s.metrics.replicateSuccess.Inc(1)
+					clusterMetrics.replicateSuccess.Inc(1)
 				}
 				if s.outCh != nil {
 					s.outCh <- err
@@ -200,8 +244,10 @@ func (s replicatedSession) replicate(params replicatedParams) error {
 				<-s.replicationSemaphore
 			})
 			s.metrics.replicateExecuted.Inc(1)
+			clusterMetrics.replicateExecuted.Inc(1)
 		default:
 			s.metrics.replicateNotExecuted.Inc(1)
+			clusterMetrics.replicateNotExecuted.Inc(1)
 		}
 	}
 
@@ -22,11 +22,14 @@ package client
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
 
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/topology"
@@ -163,6 +166,7 @@ func (s *replicatedSessionTestSuite) TestSetAsyncSessions() {
 		o := NewMockAdminOptions(s.mockCtrl)
 		o.EXPECT().InstrumentOptions().AnyTimes().Return(instrument.NewOptions())
 		o.EXPECT().SetInstrumentOptions(gomock.Any()).Return(o)
+		o.EXPECT().AsyncWritePercent().Return(100.0)
 		sessionOpts = append(sessionOpts, o)
 	}
 
@@ -236,6 +240,81 @@ func (s *replicatedSessionTestSuite) TestReplicateTagged() {
 	s.waitForAsyncSessions(asyncCount)
 }
 
+func (s *replicatedSessionTestSuite) TestReplicateWritePercent() {
+	var (
+		namespace  = ident.StringID("foo")
+		id         = ident.StringID("bar")
+		now        = xtime.Now()
+		value      = float64(123)
+		unit       = xtime.Nanosecond
+		annotation = []byte("annotation")
+		scope      = tally.NewTestScope("", nil)
+		sessions   []*MockclientSession
+	)
+
+	newSessionFunc := func(opts Options) (clientSession, error) {
+		s := NewMockclientSession(s.mockCtrl)
+		sessions = append(sessions, s)
+		return s, nil
+	}
+
+	opts := optionsWithAsyncSessions(true, 2).
+		SetInstrumentOptions(instrument.NewOptions().SetMetricsScope(scope))
+	topoInits := opts.AsyncTopologyInitializers()
+	overrides := make([]environment.ClientOverrides, len(topoInits))
+	noWrites := 0.0
+	overrides[1].AsyncWritePercent = &noWrites
+	session, err := newReplicatedSession(
+		opts,
+		NewOptionsForAsyncClusters(opts, topoInits, overrides),
+		withNewSessionFn(newSessionFunc),
+	)
+	s.NoError(err)
+	s.replicatedSession = session.(*replicatedSession)
+	s.replicatedSession.outCh = make(chan error)
+
+	// Only the sync cluster and the first async cluster receive the write.
+	s.Len(sessions, 3)
+	for _, session := range sessions[:2] {
+		session.EXPECT().Write(
+			ident.NewIDMatcher(namespace.String()),
+			ident.NewIDMatcher(id.String()),
+			now, value, unit, annotation,
+		).Return(nil)
+	}
+
+	err = s.replicatedSession.Write(namespace, id, now, value, unit, annotation)
+	s.NoError(err)
+	s.waitForAsyncSessions(1)
+
+	counters := scope.Snapshot().Counters()
+	s.Equal(int64(1), counters["async-0.replicate.executed+"].Value())
+	s.Equal(int64(1), counters["async-1.replicate.skipped+"].Value())
+	s.Equal(int64(1), counters["replicate.skipped+"].Value())
+}
+
+func TestAsyncReplicationShouldReplicate(t *testing.T) {
+	var (
+		replication = asyncReplication{writePercent: 25}
+		numSeries   = 10000
+		replicated  = 0
+	)
+	for i := 0; i < numSeries; i++ {
+		id := ident.StringID(fmt.Sprintf("series-%d", i))
+		should := replication.shouldReplicate(id)
+		// Sampling is by series so writes for a series are always replicated
+		// or always skipped.
+		require.Equal(t, should, replication.shouldReplicate(id))
+		if should {
+			replicated++
+		}
+	}
+	require.InDelta(t, numSeries/4, replicated, float64(numSeries)/20)
+
+	require.True(t, asyncReplication{writePercent: 100}.shouldReplicate(ident.StringID("foo")))
+	require.False(t, asyncReplication{writePercent: 0}.shouldReplicate(ident.StringID("foo")))
+}
+
 func (s *replicatedSessionTestSuite) TestOpenReplicatedSession() {
 	var newSessionFunc = func(opts Options) (clientSession, error) {
 		s := NewMockclientSession(s.mockCtrl)
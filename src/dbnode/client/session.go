@@ -134,6 +134,8 @@ type sessionState struct {
 	topoWatch      topology.MapWatch
 	replicas       int
 	majority       int
+
+	hostsInMaintenance bool
 }
 
 type session struct {
@@ -1006,6 +1008,14 @@ func (s *session) setTopologyWithLock(topoMap topology.Map, queues []hostQueue,
 	s.state.replicas = replicas
 	s.state.majority = majority
 
+	s.state.hostsInMaintenance = false
+	for _, host := range topoMap.Hosts() {
+		if host.Maintenance() {
+			s.state.hostsInMaintenance = true
+			break
+		}
+	}
+
 	// If the number of hostQueues has changed then we need to recreate the fetch
 	// batch op array pool as it must be the exact length of the queues as we index
 	// directly into the return array in fetch calls.
@@ -1390,6 +1400,8 @@ func (s *session) writeAttemptWithRLock(
 	state.nsID, state.tsID, state.tagEncoder, state.annotation = nsID, tsID, tagEncoder, clonedAnnotation
 	op.SetCompletionFn(state.completionFn)
 
+	skipHostsInMaintenance := s.state.hostsInMaintenance &&
+		s.canSkipHostsInMaintenanceWithRLock(tsID)
 	if err := s.state.topoMap.RouteForEach(tsID, func(
 		idx int,
 		hostShard shard.Shard,
@@ -1402,6 +1414,11 @@ func (s *session) writeAttemptWithRLock(
 			// towards quorum, current defaults, so this is ok consistency wise).
 			return
 		}
+		if skipHostsInMaintenance && host.Maintenance() {
+			// Do not write to hosts in maintenance when the other replicas
+			// are enough to satisfy the write consistency level.
+			return
+		}
 
 		// Count pending write requests before we enqueue the completion fns,
 		// which rely on the count when executing
@@ -1432,6 +1449,42 @@ func (s *session) writeAttemptWithRLock(
 	return state, majority, enqueued, nil
 }
 
+// canSkipHostsInMaintenanceWithRLock returns whether the replicas of a series
+// that are not in maintenance can satisfy the write consistency level alone.
+func (s *session) canSkipHostsInMaintenanceWithRLock(id ident.ID) bool {
+	var required int
+	switch s.state.writeLevel {
+	case topology.ConsistencyLevelOne:
+		required = 1
+	case topology.ConsistencyLevelMajority:
+		required = s.state.majority
+	default:
+		return false
+	}
+
+	var satisfying int
+	if err := s.state.topoMap.RouteForEach(id, func(
+		_ int,
+		hostShard shard.Shard,
+		host topology.Host,
+	) {
+		if host.Maintenance() {
+			return
+		}
+		switch hostShard.State() {
+		case shard.Available:
+			satisfying++
+		case shard.Leaving:
+			if s.shardsLeavingCountTowardsConsistency {
+				satisfying++
+			}
+		}
+	}); err != nil {
+		return false
+	}
+	return satisfying >= required
+}
+
 func (s *session) Fetch(
 	nsID ident.ID,
 	id ident.ID,
@@ -367,6 +367,81 @@ func TestSessionWriteConsistencyLevelOne(t *testing.T) {
 	testWriteConsistencyLevel(t, ctrl, level, 0, 3, outcomeFail)
 }
 
+type maintenanceTestHost struct {
+	topology.Host
+}
+
+func (h maintenanceTestHost) Maintenance() bool { return true }
+
+func TestSessionWriteSkipsHostsInMaintenance(t *testing.T) {
+	// Majority can be met by the two hosts not in maintenance.
+	testSessionWriteHostsInMaintenance(t, topology.ConsistencyLevelMajority, 2)
+	// All requires every replica so hosts in maintenance are still written to.
+	testSessionWriteHostsInMaintenance(t, topology.ConsistencyLevelAll, 3)
+}
+
+func testSessionWriteHostsInMaintenance(
+	t *testing.T,
+	level topology.ConsistencyLevel,
+	expectedEnqueued int,
+) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	shardSet := sessionTestShardSet()
+	hostShardSets := sessionTestHostAndShards(shardSet)
+	hostShardSets[0] = topology.NewHostShardSet(
+		maintenanceTestHost{Host: hostShardSets[0].Host()}, shardSet)
+
+	opts := newSessionTestOptions().
+		SetWriteConsistencyLevel(level).
+		SetTopologyInitializer(topology.NewStaticInitializer(
+			topology.NewStaticOptions().
+				SetReplicas(sessionTestReplicas).
+				SetShardSet(shardSet).
+				SetHostShardSets(hostShardSets)))
+	session := newTestSession(t, opts).(*session)
+
+	var (
+		enqueuedLock sync.Mutex
+		enqueued     []string
+	)
+	session.newHostQueueFn = func(
+		host topology.Host,
+		hostQueueOpts hostQueueOpts,
+	) (hostQueue, error) {
+		hostQueue := NewMockhostQueue(ctrl)
+		hostQueue.EXPECT().Open()
+		hostQueue.EXPECT().Host().Return(host).AnyTimes()
+		hostQueue.EXPECT().ConnectionCount().
+			Return(hostQueueOpts.opts.MinConnectionCount()).AnyTimes()
+		hostQueue.EXPECT().Enqueue(gomock.Any()).Do(func(op op) error {
+			enqueuedLock.Lock()
+			enqueued = append(enqueued, host.ID())
+			enqueuedLock.Unlock()
+			go op.CompletionFn()(host, nil)
+			return nil
+		}).Return(nil).AnyTimes()
+		hostQueue.EXPECT().Close()
+		return hostQueue, nil
+	}
+
+	require.NoError(t, session.Open())
+
+	w := newWriteStub()
+	require.NoError(t, session.Write(w.ns, w.id, w.t, w.value, w.unit, w.annotation))
+	require.NoError(t, session.Close())
+
+	enqueuedLock.Lock()
+	defer enqueuedLock.Unlock()
+	require.Equal(t, expectedEnqueued, len(enqueued))
+	if expectedEnqueued < sessionTestReplicas {
+		for _, id := range enqueued {
+			require.NotEqual(t, testHostName(0), id)
+		}
+	}
+}
+
 func testWriteConsistencyLevel(
 	t *testing.T,
 	ctrl *gomock.Controller,
@@ -160,6 +160,9 @@ type FetchResponseMetadata struct {
 	WaitedIndex int
 	// WaitedSeriesRead counts how many times series being read had to wait for permits.
 	WaitedSeriesRead int
+	// NextPageAfterID is the ID to resume a query paginated by series ID after,
+	// nil if no results remain.
+	NextPageAfterID []byte
 }
 
 // AggregatedTagsIterator iterates over a collection of tag names with optionally
@@ -689,6 +692,14 @@ type Options interface {
 	// AsyncWriteMaxConcurrency returns the async writes maximum concurrency.
 	AsyncWriteMaxConcurrency() int
 
+	// SetAsyncWritePercent sets the percentage of series whose writes are
+	// replicated to the cluster when it is used as an async cluster.
+	SetAsyncWritePercent(value float64) Options
+
+	// AsyncWritePercent returns the percentage of series whose writes are
+	// replicated to the cluster when it is used as an async cluster.
+	AsyncWritePercent() float64
+
 	// SetUseV2BatchAPIs sets whether the V2 batch APIs should be used.
 	SetUseV2BatchAPIs(value bool) Options
 
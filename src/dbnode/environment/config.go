@@ -99,6 +99,9 @@ type DynamicCluster struct {
 type ClientOverrides struct {
 	HostQueueFlushInterval   *time.Duration `yaml:"hostQueueFlushInterval"`
 	TargetHostQueueFlushSize *int           `yaml:"targetHostQueueFlushSize"`
+	// AsyncWritePercent is the percentage of series whose writes are replicated
+	// to an async cluster, e.g. to shadow a portion of writes to a new cluster.
+	AsyncWritePercent *float64 `yaml:"asyncWritePercent"`
 }
 
 // Validate validates the DynamicConfiguration.
@@ -115,6 +118,10 @@ func (c DynamicConfiguration) Validate() error {
 		if cfg.ClientOverrides.HostQueueFlushInterval != nil && *cfg.ClientOverrides.HostQueueFlushInterval <= 0 {
 			return fmt.Errorf("host queue flush interval must be larger than zero but was: %s", cfg.ClientOverrides.HostQueueFlushInterval.String())
 		}
+
+		if p := cfg.ClientOverrides.AsyncWritePercent; p != nil && (*p < 0 || *p > 100) {
+			return fmt.Errorf("async write percent must be between 0 and 100 but was: %v", *p)
+		}
 	}
 	if syncCount != 1 {
 		return errInvalidSyncCount
@@ -162,6 +169,10 @@ func (c StaticConfiguration) Validate() error {
 		if cfg.ClientOverrides.HostQueueFlushInterval != nil && *cfg.ClientOverrides.HostQueueFlushInterval <= 0 {
 			return fmt.Errorf("host queue flush interval must be larger than zero but was: %s", cfg.ClientOverrides.HostQueueFlushInterval.String())
 		}
+
+		if p := cfg.ClientOverrides.AsyncWritePercent; p != nil && (*p < 0 || *p > 100) {
+			return fmt.Errorf("async write percent must be between 0 and 100 but was: %v", *p)
+		}
 	}
 	if syncCount != 1 {
 		return errInvalidSyncCount
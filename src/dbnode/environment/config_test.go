@@ -21,6 +21,7 @@
 package environment
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -199,3 +200,39 @@ func TestConfigValidation(t *testing.T) {
 		assert.Equal(t, tt.expectErr, cfg.Validate())
 	}
 }
+
+func TestConfigValidationAsyncWritePercent(t *testing.T) {
+	in := `
+services:
+  - service:
+      zone: dca8
+      env: test
+  - service:
+      zone: phx3
+      env: test
+    async: true
+    clientOverrides:
+      asyncWritePercent: %v
+`
+
+	for _, tt := range []struct {
+		percent   float64
+		expectErr bool
+	}{
+		{percent: 0},
+		{percent: 12.5},
+		{percent: 100},
+		{percent: -1, expectErr: true},
+		{percent: 101, expectErr: true},
+	} {
+		var cfg Configuration
+		err := yaml.Unmarshal([]byte(fmt.Sprintf(in, tt.percent)), &cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.percent, *cfg.Services[1].ClientOverrides.AsyncWritePercent)
+		if tt.expectErr {
+			assert.Error(t, cfg.Validate())
+		} else {
+			assert.NoError(t, cfg.Validate())
+		}
+	}
+}
@@ -29,6 +29,7 @@
 
 	It has these top-level messages:
 		Payload
+		Exemplar
 */
 package annotation
 
@@ -36,6 +37,8 @@ import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
 
+import binary "encoding/binary"
+
 import io "io"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -91,6 +94,10 @@ func (MetricType) EnumDescriptor() ([]byte, []int) { return fileDescriptorAnnota
 type Payload struct {
 	MetricType        MetricType `protobuf:"varint,1,opt,name=metric_type,json=metricType,proto3,enum=annotation.MetricType" json:"metric_type,omitempty"`
 	HandleValueResets bool       `protobuf:"varint,2,opt,name=handle_value_resets,json=handleValueResets,proto3" json:"handle_value_resets,omitempty"`
+	Synthesized       bool       `protobuf:"varint,3,opt,name=synthesized,proto3" json:"synthesized,omitempty"`
+	// exemplars are the samples of the datapoint annotated with the trace
+	// they were recorded in.
+	Exemplars []*Exemplar `protobuf:"bytes,4,rep,name=exemplars" json:"exemplars,omitempty"`
 }
 
 func (m *Payload) Reset()                    { *m = Payload{} }
@@ -112,8 +119,63 @@ func (m *Payload) GetHandleValueResets() bool {
 	return false
 }
 
+func (m *Payload) GetSynthesized() bool {
+	if m != nil {
+		return m.Synthesized
+	}
+	return false
+}
+
+func (m *Payload) GetExemplars() []*Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
+type Exemplar struct {
+	TraceId   []byte  `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	SpanId    []byte  `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	Value     float64 `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	TimeNanos int64   `protobuf:"varint,4,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
+}
+
+func (m *Exemplar) Reset()                    { *m = Exemplar{} }
+func (m *Exemplar) String() string            { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()               {}
+func (*Exemplar) Descriptor() ([]byte, []int) { return fileDescriptorAnnotation, []int{1} }
+
+func (m *Exemplar) GetTraceId() []byte {
+	if m != nil {
+		return m.TraceId
+	}
+	return nil
+}
+
+func (m *Exemplar) GetSpanId() []byte {
+	if m != nil {
+		return m.SpanId
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimeNanos() int64 {
+	if m != nil {
+		return m.TimeNanos
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Payload)(nil), "annotation.Payload")
+	proto.RegisterType((*Exemplar)(nil), "annotation.Exemplar")
 	proto.RegisterEnum("annotation.MetricType", MetricType_name, MetricType_value)
 }
 func (m *Payload) Marshal() (dAtA []byte, err error) {
@@ -146,6 +208,69 @@ func (m *Payload) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if m.Synthesized {
+		dAtA[i] = 0x18
+		i++
+		if m.Synthesized {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintAnnotation(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TraceId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(len(m.TraceId)))
+		i += copy(dAtA[i:], m.TraceId)
+	}
+	if len(m.SpanId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(len(m.SpanId)))
+		i += copy(dAtA[i:], m.SpanId)
+	}
+	if m.Value != 0 {
+		dAtA[i] = 0x19
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if m.TimeNanos != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(m.TimeNanos))
+	}
 	return i, nil
 }
 
@@ -167,6 +292,35 @@ func (m *Payload) Size() (n int) {
 	if m.HandleValueResets {
 		n += 2
 	}
+	if m.Synthesized {
+		n += 2
+	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovAnnotation(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Exemplar) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 1 + l + sovAnnotation(uint64(l))
+	}
+	l = len(m.SpanId)
+	if l > 0 {
+		n += 1 + l + sovAnnotation(uint64(l))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.TimeNanos != 0 {
+		n += 1 + sovAnnotation(uint64(m.TimeNanos))
+	}
 	return n
 }
 
@@ -251,6 +405,199 @@ func (m *Payload) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.HandleValueResets = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Synthesized", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Synthesized = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, &Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAnnotation(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAnnotation
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = append(m.TraceId[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceId == nil {
+				m.TraceId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanId = append(m.SpanId[:0], dAtA[iNdEx:postIndex]...)
+			if m.SpanId == nil {
+				m.SpanId = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeNanos", wireType)
+			}
+			m.TimeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAnnotation(dAtA[iNdEx:])
@@ -382,24 +729,31 @@ func init() {
 }
 
 var fileDescriptorAnnotation = []byte{
-	// 297 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0xd0, 0x4d, 0x4b, 0xc3, 0x30,
-	0x1c, 0x06, 0xf0, 0x65, 0xee, 0xf5, 0x3f, 0x5f, 0x62, 0x06, 0xb2, 0x53, 0x19, 0x9e, 0x86, 0x87,
-	0x06, 0xdc, 0xc1, 0x73, 0x95, 0x3a, 0x87, 0xb4, 0x95, 0x36, 0x55, 0x3c, 0x95, 0x74, 0x09, 0xdb,
-	0x60, 0x4d, 0x46, 0x96, 0x09, 0xf3, 0x53, 0xf8, 0xb1, 0x3c, 0xfa, 0x11, 0x64, 0x7e, 0x11, 0x59,
-	0x05, 0xb7, 0xdb, 0x93, 0xe7, 0xc7, 0x93, 0xc3, 0x1f, 0xc6, 0xd3, 0xb9, 0x9d, 0xad, 0x73, 0x77,
-	0xa2, 0x0b, 0x5a, 0x0c, 0x45, 0x4e, 0x8b, 0x21, 0x5d, 0x99, 0x09, 0x15, 0xb9, 0xd2, 0x42, 0xd2,
-	0xa9, 0x54, 0xd2, 0x70, 0x2b, 0x05, 0x5d, 0x1a, 0x6d, 0x35, 0xe5, 0x4a, 0x69, 0xcb, 0xed, 0x5c,
-	0xab, 0x83, 0xe8, 0x96, 0x46, 0x60, 0xdf, 0x5c, 0x1a, 0x68, 0x3e, 0xf1, 0xcd, 0x42, 0x73, 0x41,
-	0x6e, 0xa0, 0x53, 0x48, 0x6b, 0xe6, 0x93, 0xcc, 0x6e, 0x96, 0xb2, 0x87, 0xfa, 0x68, 0x70, 0x7a,
-	0x7d, 0xe1, 0x1e, 0xcc, 0x83, 0x92, 0xd9, 0x66, 0x29, 0x63, 0x28, 0xfe, 0x33, 0x71, 0xa1, 0x3b,
-	0xe3, 0x4a, 0x2c, 0x64, 0xf6, 0xc6, 0x17, 0x6b, 0x99, 0x19, 0xb9, 0x92, 0x76, 0xd5, 0xab, 0xf6,
-	0xd1, 0xa0, 0x15, 0x9f, 0xff, 0xd1, 0xf3, 0x4e, 0xe2, 0x12, 0xae, 0xde, 0x01, 0xf6, 0x3f, 0x91,
-	0x0e, 0x34, 0xd3, 0xf0, 0x31, 0x8c, 0x5e, 0x42, 0x5c, 0xd9, 0x3d, 0xee, 0xa2, 0x34, 0x64, 0x7e,
-	0x8c, 0x11, 0x69, 0x43, 0x7d, 0xe4, 0xa5, 0x23, 0x1f, 0x57, 0xc9, 0x09, 0xb4, 0x1f, 0xc6, 0x09,
-	0x8b, 0x46, 0xb1, 0x17, 0xe0, 0x23, 0xd2, 0x85, 0xb3, 0x52, 0xb2, 0x7d, 0x59, 0xdb, 0x6d, 0x93,
-	0x34, 0x08, 0xbc, 0xf8, 0x15, 0xd7, 0x49, 0x0b, 0x6a, 0xe3, 0xf0, 0x3e, 0xc2, 0x0d, 0x72, 0x0c,
-	0xad, 0x84, 0x79, 0xcc, 0x4f, 0x7c, 0x86, 0x9b, 0xb7, 0xf8, 0x73, 0xeb, 0xa0, 0xaf, 0xad, 0x83,
-	0xbe, 0xb7, 0x0e, 0xfa, 0xf8, 0x71, 0x2a, 0x79, 0xa3, 0x3c, 0xca, 0xf0, 0x37, 0x00, 0x00, 0xff,
-	0xff, 0x25, 0xe6, 0x7a, 0x90, 0x61, 0x01, 0x00, 0x00,
+	// 404 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0xcf, 0x8e, 0xd3, 0x30,
+	0x10, 0xc6, 0xd7, 0xfd, 0x97, 0x74, 0xba, 0x40, 0xf0, 0xae, 0xa0, 0x1c, 0xa8, 0xaa, 0x3d, 0x55,
+	0x1c, 0x1a, 0xa9, 0x3d, 0x70, 0x2e, 0x28, 0x94, 0x08, 0x35, 0x45, 0x6e, 0x0a, 0xe2, 0x14, 0xb9,
+	0xf5, 0x68, 0x1b, 0xa9, 0xb1, 0xa3, 0xd8, 0x8b, 0xe8, 0x3e, 0x05, 0x8f, 0xb5, 0x47, 0x1e, 0x01,
+	0x95, 0x17, 0x41, 0x76, 0x61, 0x9b, 0xdb, 0x7c, 0xbf, 0xdf, 0x8c, 0xf4, 0x25, 0x86, 0xf8, 0x36,
+	0x37, 0xbb, 0xbb, 0xcd, 0x78, 0xab, 0x8a, 0xb0, 0x98, 0x8a, 0x4d, 0x58, 0x4c, 0x43, 0x5d, 0x6d,
+	0x43, 0xb1, 0x91, 0x4a, 0x60, 0x78, 0x8b, 0x12, 0x2b, 0x6e, 0x50, 0x84, 0x65, 0xa5, 0x8c, 0x0a,
+	0xb9, 0x94, 0xca, 0x70, 0x93, 0x2b, 0x59, 0x1b, 0xc7, 0xce, 0x51, 0x38, 0x93, 0x9b, 0x07, 0x02,
+	0xde, 0x67, 0x7e, 0xd8, 0x2b, 0x2e, 0xe8, 0x5b, 0xe8, 0x15, 0x68, 0xaa, 0x7c, 0x9b, 0x99, 0x43,
+	0x89, 0x7d, 0x32, 0x24, 0xa3, 0xa7, 0x93, 0x17, 0xe3, 0xda, 0xfd, 0xc2, 0xe9, 0xf4, 0x50, 0x22,
+	0x83, 0xe2, 0x71, 0xa6, 0x63, 0xb8, 0xda, 0x71, 0x29, 0xf6, 0x98, 0x7d, 0xe7, 0xfb, 0x3b, 0xcc,
+	0x2a, 0xd4, 0x68, 0x74, 0xbf, 0x31, 0x24, 0x23, 0x9f, 0x3d, 0x3f, 0xa9, 0x2f, 0xd6, 0x30, 0x27,
+	0xe8, 0x10, 0x7a, 0xfa, 0x20, 0xcd, 0x0e, 0x75, 0x7e, 0x8f, 0xa2, 0xdf, 0x74, 0x7b, 0x75, 0x44,
+	0x27, 0xd0, 0xc5, 0x1f, 0x58, 0x94, 0x7b, 0x5e, 0xe9, 0x7e, 0x6b, 0xd8, 0x1c, 0xf5, 0x26, 0xd7,
+	0xf5, 0x22, 0xd1, 0x3f, 0xc9, 0xce, 0x6b, 0x37, 0x1a, 0xfc, 0xff, 0x98, 0xbe, 0x02, 0xdf, 0x54,
+	0x7c, 0x8b, 0x59, 0x2e, 0xdc, 0x77, 0x5c, 0x32, 0xcf, 0xe5, 0x58, 0xd0, 0x97, 0xe0, 0xe9, 0x92,
+	0x4b, 0x6b, 0x1a, 0xce, 0x74, 0x6c, 0x8c, 0x05, 0xbd, 0x86, 0xb6, 0xab, 0xef, 0xfa, 0x10, 0x76,
+	0x0a, 0xf4, 0x35, 0x80, 0xc9, 0x0b, 0xcc, 0x24, 0x97, 0xca, 0x56, 0x21, 0xa3, 0x26, 0xeb, 0x5a,
+	0x92, 0x58, 0xf0, 0xe6, 0x1e, 0xe0, 0xfc, 0x53, 0x68, 0x0f, 0xbc, 0x75, 0xf2, 0x29, 0x59, 0x7e,
+	0x4d, 0x82, 0x0b, 0x1b, 0xde, 0x2f, 0xd7, 0x49, 0x1a, 0xb1, 0x80, 0xd0, 0x2e, 0xb4, 0xe7, 0xb3,
+	0xf5, 0x3c, 0x0a, 0x1a, 0xf4, 0x09, 0x74, 0x3f, 0xc6, 0xab, 0x74, 0x39, 0x67, 0xb3, 0x45, 0xd0,
+	0xa4, 0x57, 0xf0, 0xcc, 0x99, 0xec, 0x0c, 0x5b, 0xf6, 0x76, 0xb5, 0x5e, 0x2c, 0x66, 0xec, 0x5b,
+	0xd0, 0xa6, 0x3e, 0xb4, 0xe2, 0xe4, 0xc3, 0x32, 0xe8, 0xd0, 0x4b, 0xf0, 0x57, 0xe9, 0x2c, 0x8d,
+	0x56, 0x51, 0x1a, 0x78, 0xef, 0x82, 0x87, 0xe3, 0x80, 0xfc, 0x3a, 0x0e, 0xc8, 0xef, 0xe3, 0x80,
+	0xfc, 0xfc, 0x33, 0xb8, 0xd8, 0x74, 0xdc, 0x03, 0x4f, 0xff, 0x0e, 0x00, 0x39, 0x28, 0x20, 0x4e,
+	0x2d, 0x02, 0x00, 0x00,
 }
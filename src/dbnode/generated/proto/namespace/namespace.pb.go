@@ -22,27 +22,29 @@
 // THE SOFTWARE.
 
 /*
-	Package namespace is a generated protocol buffer package.
-
-	It is generated from these files:
-		github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto
-		github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto
-
-	It has these top-level messages:
-		RetentionOptions
-		IndexOptions
-		NamespaceOptions
-		AggregationOptions
-		Aggregation
-		AggregatedAttributes
-		DownsampleOptions
-		StagingState
-		Registry
-		NamespaceRuntimeOptions
-		ExtendedOptions
-		SchemaOptions
-		SchemaHistory
-		FileDescriptorSet
+Package namespace is a generated protocol buffer package.
+
+It is generated from these files:
+
+	github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto
+	github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto
+
+It has these top-level messages:
+
+	RetentionOptions
+	IndexOptions
+	NamespaceOptions
+	AggregationOptions
+	Aggregation
+	AggregatedAttributes
+	DownsampleOptions
+	StagingState
+	Registry
+	NamespaceRuntimeOptions
+	ExtendedOptions
+	SchemaOptions
+	SchemaHistory
+	FileDescriptorSet
 */
 package namespace
 
@@ -182,20 +184,21 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 }
 
 type NamespaceOptions struct {
-	BootstrapEnabled      bool                        `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
-	FlushEnabled          bool                        `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
-	WritesToCommitLog     bool                        `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
-	CleanupEnabled        bool                        `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
-	RepairEnabled         bool                        `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
-	RetentionOptions      *RetentionOptions           `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
-	SnapshotEnabled       bool                        `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
-	IndexOptions          *IndexOptions               `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
-	SchemaOptions         *SchemaOptions              `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
-	ColdWritesEnabled     bool                        `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
-	RuntimeOptions        *NamespaceRuntimeOptions    `protobuf:"bytes,11,opt,name=runtimeOptions" json:"runtimeOptions,omitempty"`
-	CacheBlocksOnRetrieve *google_protobuf1.BoolValue `protobuf:"bytes,12,opt,name=cacheBlocksOnRetrieve" json:"cacheBlocksOnRetrieve,omitempty"`
-	AggregationOptions    *AggregationOptions         `protobuf:"bytes,13,opt,name=aggregationOptions" json:"aggregationOptions,omitempty"`
-	StagingState          *StagingState               `protobuf:"bytes,14,opt,name=stagingState" json:"stagingState,omitempty"`
+	BootstrapEnabled               bool                         `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
+	FlushEnabled                   bool                         `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
+	WritesToCommitLog              bool                         `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
+	CleanupEnabled                 bool                         `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
+	RepairEnabled                  bool                         `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
+	RetentionOptions               *RetentionOptions            `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
+	SnapshotEnabled                bool                         `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
+	IndexOptions                   *IndexOptions                `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
+	SchemaOptions                  *SchemaOptions               `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
+	ColdWritesEnabled              bool                         `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	RuntimeOptions                 *NamespaceRuntimeOptions     `protobuf:"bytes,11,opt,name=runtimeOptions" json:"runtimeOptions,omitempty"`
+	CacheBlocksOnRetrieve          *google_protobuf1.BoolValue  `protobuf:"bytes,12,opt,name=cacheBlocksOnRetrieve" json:"cacheBlocksOnRetrieve,omitempty"`
+	AggregationOptions             *AggregationOptions          `protobuf:"bytes,13,opt,name=aggregationOptions" json:"aggregationOptions,omitempty"`
+	StagingState                   *StagingState                `protobuf:"bytes,14,opt,name=stagingState" json:"stagingState,omitempty"`
+	FutureWriteClampToleranceNanos *google_protobuf1.Int64Value `protobuf:"bytes,15,opt,name=futureWriteClampToleranceNanos" json:"futureWriteClampToleranceNanos,omitempty"`
 	// Use larger field ID to ensure new fields are always added before extended options.
 	ExtendedOptions *ExtendedOptions `protobuf:"bytes,1000,opt,name=extendedOptions" json:"extendedOptions,omitempty"`
 }
@@ -303,6 +306,13 @@ func (m *NamespaceOptions) GetStagingState() *StagingState {
 	return nil
 }
 
+func (m *NamespaceOptions) GetFutureWriteClampToleranceNanos() *google_protobuf1.Int64Value {
+	if m != nil {
+		return m.FutureWriteClampToleranceNanos
+	}
+	return nil
+}
+
 func (m *NamespaceOptions) GetExtendedOptions() *ExtendedOptions {
 	if m != nil {
 		return m.ExtendedOptions
@@ -365,6 +375,9 @@ type AggregatedAttributes struct {
 	// resolutionNanos is the time range to aggregate data across.
 	ResolutionNanos   int64              `protobuf:"varint,1,opt,name=resolutionNanos,proto3" json:"resolutionNanos,omitempty"`
 	DownsampleOptions *DownsampleOptions `protobuf:"bytes,2,opt,name=downsampleOptions" json:"downsampleOptions,omitempty"`
+	// fanoutDisabled excludes the namespace from automatic query fanout while
+	// keeping it registered, e.g. while it is being backfilled.
+	FanoutDisabled bool `protobuf:"varint,3,opt,name=fanoutDisabled,proto3" json:"fanoutDisabled,omitempty"`
 }
 
 func (m *AggregatedAttributes) Reset()                    { *m = AggregatedAttributes{} }
@@ -386,6 +399,13 @@ func (m *AggregatedAttributes) GetDownsampleOptions() *DownsampleOptions {
 	return nil
 }
 
+func (m *AggregatedAttributes) GetFanoutDisabled() bool {
+	if m != nil {
+		return m.FanoutDisabled
+	}
+	return false
+}
+
 // DownsampleOptions is a set of options related to downsampling data.
 type DownsampleOptions struct {
 	// all indicates whether to send data points to this namespace. If false,
@@ -444,10 +464,12 @@ type NamespaceRuntimeOptions struct {
 	FlushIndexingPerCPUConcurrency *google_protobuf1.DoubleValue `protobuf:"bytes,2,opt,name=flushIndexingPerCPUConcurrency" json:"flushIndexingPerCPUConcurrency,omitempty"`
 }
 
-func (m *NamespaceRuntimeOptions) Reset()                    { *m = NamespaceRuntimeOptions{} }
-func (m *NamespaceRuntimeOptions) String() string            { return proto.CompactTextString(m) }
-func (*NamespaceRuntimeOptions) ProtoMessage()               {}
-func (*NamespaceRuntimeOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{9} }
+func (m *NamespaceRuntimeOptions) Reset()         { *m = NamespaceRuntimeOptions{} }
+func (m *NamespaceRuntimeOptions) String() string { return proto.CompactTextString(m) }
+func (*NamespaceRuntimeOptions) ProtoMessage()    {}
+func (*NamespaceRuntimeOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptorNamespace, []int{9}
+}
 
 func (m *NamespaceRuntimeOptions) GetWriteIndexingPerCPUConcurrency() *google_protobuf1.DoubleValue {
 	if m != nil {
@@ -747,17 +769,27 @@ func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n7
 	}
+	if m.FutureWriteClampToleranceNanos != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.FutureWriteClampToleranceNanos.Size()))
+		n8, err := m.FutureWriteClampToleranceNanos.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
 	if m.ExtendedOptions != nil {
 		dAtA[i] = 0xc2
 		i++
 		dAtA[i] = 0x3e
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.ExtendedOptions.Size()))
-		n8, err := m.ExtendedOptions.MarshalTo(dAtA[i:])
+		n9, err := m.ExtendedOptions.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n8
+		i += n9
 	}
 	return i, nil
 }
@@ -821,11 +853,11 @@ func (m *Aggregation) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.Attributes.Size()))
-		n9, err := m.Attributes.MarshalTo(dAtA[i:])
+		n10, err := m.Attributes.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n10
 	}
 	return i, nil
 }
@@ -854,11 +886,21 @@ func (m *AggregatedAttributes) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.DownsampleOptions.Size()))
-		n10, err := m.DownsampleOptions.MarshalTo(dAtA[i:])
+		n11, err := m.DownsampleOptions.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n10
+		i += n11
+	}
+	if m.FanoutDisabled {
+		dAtA[i] = 0x18
+		i++
+		if m.FanoutDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
 	}
 	return i, nil
 }
@@ -949,11 +991,11 @@ func (m *Registry) MarshalTo(dAtA []byte) (int, error) {
 				dAtA[i] = 0x12
 				i++
 				i = encodeVarintNamespace(dAtA, i, uint64(v.Size()))
-				n11, err := v.MarshalTo(dAtA[i:])
+				n12, err := v.MarshalTo(dAtA[i:])
 				if err != nil {
 					return 0, err
 				}
-				i += n11
+				i += n12
 			}
 		}
 	}
@@ -979,21 +1021,21 @@ func (m *NamespaceRuntimeOptions) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.WriteIndexingPerCPUConcurrency.Size()))
-		n12, err := m.WriteIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
+		n13, err := m.WriteIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n13
 	}
 	if m.FlushIndexingPerCPUConcurrency != nil {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.FlushIndexingPerCPUConcurrency.Size()))
-		n13, err := m.FlushIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
+		n14, err := m.FlushIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n13
+		i += n14
 	}
 	return i, nil
 }
@@ -1023,11 +1065,11 @@ func (m *ExtendedOptions) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.Options.Size()))
-		n14, err := m.Options.MarshalTo(dAtA[i:])
+		n15, err := m.Options.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n14
+		i += n15
 	}
 	return i, nil
 }
@@ -1132,6 +1174,10 @@ func (m *NamespaceOptions) Size() (n int) {
 		l = m.StagingState.Size()
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	if m.FutureWriteClampToleranceNanos != nil {
+		l = m.FutureWriteClampToleranceNanos.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
 	if m.ExtendedOptions != nil {
 		l = m.ExtendedOptions.Size()
 		n += 2 + l + sovNamespace(uint64(l))
@@ -1174,6 +1220,9 @@ func (m *AggregatedAttributes) Size() (n int) {
 		l = m.DownsampleOptions.Size()
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	if m.FanoutDisabled {
+		n += 2
+	}
 	return n
 }
 
@@ -1928,6 +1977,39 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FutureWriteClampToleranceNanos", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FutureWriteClampToleranceNanos == nil {
+				m.FutureWriteClampToleranceNanos = &google_protobuf1.Int64Value{}
+			}
+			if err := m.FutureWriteClampToleranceNanos.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 1000:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field ExtendedOptions", wireType)
@@ -2247,6 +2329,26 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FanoutDisabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FanoutDisabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -2918,68 +3020,71 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 1001 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x96, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0x80, 0xbb, 0xf9, 0x73, 0x72, 0xec, 0x24, 0xce, 0xa8, 0x10, 0x2b, 0x2d, 0xa6, 0x5a, 0x7e,
-	0x14, 0x55, 0xc8, 0xa6, 0xc9, 0x0d, 0x14, 0xa9, 0xe0, 0x24, 0x26, 0x72, 0x29, 0x8e, 0x35, 0x69,
-	0x29, 0xe4, 0x6e, 0x76, 0xf7, 0x78, 0xb3, 0xea, 0x7a, 0x66, 0x35, 0x33, 0xdb, 0x24, 0x3c, 0x43,
-	0x2f, 0x78, 0x0f, 0x5e, 0x84, 0x4b, 0x1e, 0x01, 0x05, 0x21, 0x71, 0xc3, 0x3b, 0xa0, 0x9d, 0xf5,
-	0x3a, 0xfb, 0xe3, 0x96, 0x88, 0x9b, 0x68, 0x72, 0xce, 0x77, 0x7e, 0xe6, 0xfc, 0xcc, 0x1a, 0x8e,
-	0xfd, 0x40, 0x9f, 0xc7, 0x4e, 0xc7, 0x15, 0x93, 0xee, 0x64, 0xdf, 0x73, 0xba, 0x93, 0xfd, 0xae,
-	0x92, 0x6e, 0xd7, 0x73, 0xb8, 0xf0, 0xb0, 0xeb, 0x23, 0x47, 0xc9, 0x34, 0x7a, 0xdd, 0x48, 0x0a,
-	0x2d, 0xba, 0x9c, 0x4d, 0x50, 0x45, 0xcc, 0xc5, 0x9b, 0x53, 0xc7, 0x68, 0xc8, 0xda, 0x4c, 0xb0,
-	0x73, 0xdf, 0x17, 0xc2, 0x0f, 0x31, 0x35, 0x71, 0xe2, 0x71, 0x57, 0x69, 0x19, 0xbb, 0x3a, 0x05,
-	0x77, 0xda, 0x65, 0xed, 0x85, 0x64, 0x51, 0x84, 0x52, 0x4d, 0xf5, 0x47, 0xff, 0x37, 0x23, 0xe5,
-	0x9e, 0xe3, 0x84, 0xa5, 0x5e, 0xec, 0x37, 0x8b, 0xd0, 0xa4, 0xa8, 0x91, 0xeb, 0x40, 0xf0, 0x93,
-	0x28, 0xf9, 0xab, 0xc8, 0x1e, 0xdc, 0x95, 0x99, 0x6c, 0x84, 0x32, 0x10, 0xde, 0x90, 0x71, 0xa1,
-	0x5a, 0xd6, 0x03, 0x6b, 0x77, 0x91, 0xce, 0xd5, 0x91, 0x4f, 0x61, 0xc3, 0x09, 0x85, 0xfb, 0xea,
-	0x34, 0xf8, 0x19, 0x53, 0x7a, 0xc1, 0xd0, 0x25, 0x29, 0xf9, 0x0c, 0xb6, 0x9c, 0x78, 0x3c, 0x46,
-	0xf9, 0x6d, 0xac, 0x63, 0x39, 0x45, 0x17, 0x0d, 0x5a, 0x55, 0x90, 0x5d, 0xd8, 0x4c, 0x85, 0x23,
-	0xa6, 0x74, 0xca, 0x2e, 0x19, 0xb6, 0x2c, 0x36, 0x64, 0x12, 0xe9, 0x88, 0x69, 0xd6, 0xbf, 0x8c,
-	0x02, 0x79, 0xd5, 0x5a, 0x7e, 0x60, 0xed, 0xae, 0xd2, 0xb2, 0x98, 0x9c, 0xc1, 0x6e, 0x49, 0xd4,
-	0x1b, 0x6b, 0x94, 0x43, 0xa1, 0x7b, 0xae, 0x8b, 0x4a, 0xe5, 0x6f, 0xbc, 0x62, 0x82, 0xdd, 0x9a,
-	0x27, 0x4f, 0x60, 0x67, 0x6c, 0xd2, 0xa7, 0xf3, 0xea, 0x57, 0x33, 0xde, 0xde, 0x41, 0xd8, 0x23,
-	0x68, 0x0c, 0xb8, 0x87, 0x97, 0x59, 0x27, 0x5a, 0x50, 0x43, 0xce, 0x9c, 0x10, 0x3d, 0x53, 0xfc,
-	0x55, 0x9a, 0xfd, 0x7b, 0xdb, 0x7a, 0xdb, 0xff, 0xac, 0x40, 0x73, 0x98, 0xf5, 0x3e, 0x73, 0xfb,
-	0x10, 0x9a, 0x8e, 0x10, 0x5a, 0x69, 0xc9, 0xa2, 0x7e, 0xc1, 0x7f, 0x45, 0x4e, 0x6c, 0x68, 0x8c,
-	0xc3, 0x58, 0x9d, 0x67, 0xdc, 0x82, 0xe1, 0x0a, 0xb2, 0xa4, 0xa9, 0x17, 0x32, 0xd0, 0xa8, 0x9e,
-	0x8b, 0x43, 0x31, 0x99, 0x04, 0xfa, 0x99, 0xf0, 0x4d, 0x53, 0x57, 0x69, 0x55, 0x91, 0xa4, 0xee,
-	0x86, 0xc8, 0x78, 0x3c, 0x8b, 0xbd, 0x64, 0xd0, 0x92, 0x94, 0x7c, 0x0c, 0xeb, 0x12, 0x23, 0x16,
-	0xc8, 0x0c, 0x4b, 0x1b, 0x5a, 0x14, 0x92, 0x63, 0x68, 0xca, 0xd2, 0x00, 0x9b, 0xb6, 0xd5, 0xf7,
-	0xee, 0x75, 0x6e, 0x96, 0xaf, 0x3c, 0xe3, 0xb4, 0x62, 0x94, 0x4c, 0x90, 0xe2, 0x2c, 0x52, 0xe7,
-	0x42, 0x67, 0x01, 0x6b, 0xe9, 0x04, 0x95, 0xc4, 0xe4, 0x2b, 0x68, 0x04, 0xb9, 0x2e, 0xb5, 0x56,
-	0x4d, 0xb8, 0xed, 0x5c, 0xb8, 0x7c, 0x13, 0x69, 0x01, 0x26, 0x4f, 0x60, 0x3d, 0xdd, 0xc0, 0xcc,
-	0x7a, 0xcd, 0x58, 0xb7, 0x72, 0xd6, 0xa7, 0x79, 0x3d, 0x2d, 0xe2, 0x49, 0xad, 0x5d, 0x11, 0x7a,
-	0x2f, 0x4d, 0x59, 0xb3, 0x44, 0x21, 0xad, 0x75, 0x45, 0x41, 0x9e, 0xc2, 0x86, 0x8c, 0xb9, 0x0e,
-	0x26, 0x59, 0xef, 0x5b, 0x75, 0x13, 0xce, 0xce, 0x85, 0x9b, 0x8d, 0x07, 0x2d, 0x90, 0xb4, 0x64,
-	0x49, 0x46, 0xf0, 0x9e, 0xcb, 0xdc, 0x73, 0x3c, 0x48, 0x26, 0x4c, 0x9d, 0x70, 0x8a, 0x5a, 0x06,
-	0xf8, 0x1a, 0x5b, 0x0d, 0xe3, 0x72, 0xa7, 0x93, 0xbe, 0x58, 0x9d, 0xec, 0xc5, 0xea, 0x1c, 0x08,
-	0x11, 0xfe, 0xc0, 0xc2, 0x18, 0xe9, 0x7c, 0x43, 0xf2, 0x3d, 0x10, 0xe6, 0xfb, 0x12, 0x7d, 0x96,
-	0xef, 0xde, 0xba, 0x71, 0xf7, 0x41, 0x2e, 0xc3, 0x5e, 0x05, 0xa2, 0x73, 0x0c, 0x93, 0xbe, 0x28,
-	0xcd, 0xfc, 0x80, 0xfb, 0xa7, 0x9a, 0x69, 0x6c, 0x6d, 0x54, 0xfa, 0x72, 0x9a, 0x53, 0xd3, 0x02,
-	0x4c, 0xfa, 0xb0, 0x89, 0x97, 0x1a, 0xb9, 0x87, 0x5e, 0x96, 0xc8, 0xdf, 0xb5, 0xe9, 0xc5, 0x6e,
-	0x1c, 0xf4, 0x8b, 0x08, 0x2d, 0xdb, 0xd8, 0x23, 0x20, 0xd5, 0x6c, 0xc9, 0x63, 0x68, 0xe4, 0xf2,
-	0x4d, 0x5e, 0xd2, 0xc5, 0xdd, 0xfa, 0xde, 0xfb, 0xf3, 0xaf, 0x48, 0x0b, 0xac, 0xcd, 0xa1, 0x9e,
-	0x53, 0x92, 0x36, 0x40, 0xa6, 0x9e, 0x6d, 0x6d, 0x4e, 0x42, 0xbe, 0x06, 0x60, 0x5a, 0xcb, 0xc0,
-	0x89, 0x35, 0xa6, 0x8f, 0x42, 0x7d, 0xef, 0xc3, 0x39, 0x81, 0xd0, 0xeb, 0xcd, 0x30, 0x9a, 0x33,
-	0xb1, 0xdf, 0x58, 0x70, 0x77, 0x1e, 0x94, 0x2c, 0x88, 0x44, 0x25, 0xc2, 0x38, 0xc9, 0x23, 0xff,
-	0x45, 0x28, 0x8b, 0xc9, 0x53, 0xd8, 0xf2, 0xc4, 0x05, 0x57, 0x6c, 0x12, 0x85, 0xb3, 0xc1, 0x4b,
-	0x53, 0xb9, 0x9f, 0x4b, 0xe5, 0xa8, 0xcc, 0xd0, 0xaa, 0x99, 0xfd, 0x09, 0x6c, 0x55, 0x38, 0xd2,
-	0x84, 0x45, 0x16, 0x86, 0xd3, 0xdb, 0x27, 0x47, 0xfb, 0x1b, 0x68, 0xe4, 0x9b, 0x4b, 0x3e, 0x87,
-	0x15, 0xa5, 0x99, 0x8e, 0xd3, 0x1c, 0x37, 0x8a, 0xfb, 0x75, 0x03, 0xc6, 0x8a, 0x4e, 0x39, 0xfb,
-	0x57, 0x0b, 0x56, 0x29, 0xfa, 0x81, 0xd2, 0xf2, 0x8a, 0x1c, 0x02, 0xcc, 0xf8, 0xac, 0x5d, 0x1f,
-	0x15, 0xde, 0x93, 0x14, 0xbc, 0x59, 0x1e, 0xd5, 0xe7, 0x5a, 0x5e, 0xd1, 0x9c, 0xd9, 0xce, 0x19,
-	0x6c, 0x96, 0xd4, 0x49, 0xe2, 0xaf, 0xf0, 0xca, 0xe4, 0xb4, 0x46, 0x93, 0x23, 0x79, 0x04, 0xcb,
-	0xaf, 0x93, 0x1d, 0x99, 0xd6, 0xe7, 0xde, 0xbc, 0xc5, 0xcc, 0xca, 0x93, 0x92, 0x8f, 0x17, 0xbe,
-	0xb0, 0xec, 0xbf, 0x2c, 0xd8, 0x7e, 0xcb, 0xe2, 0x12, 0x0f, 0xda, 0xe6, 0xd5, 0x35, 0xaf, 0x50,
-	0xc0, 0xfd, 0x11, 0xca, 0xc3, 0xd1, 0x8b, 0x43, 0xc1, 0xdd, 0x58, 0x4a, 0xe4, 0x6e, 0x1a, 0x3f,
-	0xe9, 0x45, 0x79, 0x63, 0x8f, 0x44, 0xec, 0x84, 0x98, 0xee, 0xec, 0x7f, 0xf8, 0x48, 0xa2, 0x98,
-	0x8f, 0xc0, 0xdb, 0xa3, 0x2c, 0xdc, 0x26, 0xca, 0xbb, 0x7d, 0xd8, 0x3f, 0xc2, 0x66, 0x69, 0xe7,
-	0x08, 0x81, 0x25, 0x7d, 0x15, 0xe1, 0xb4, 0x88, 0xe6, 0x4c, 0x1e, 0x41, 0x4d, 0x14, 0xe6, 0x6c,
-	0xbb, 0x12, 0xf5, 0xd4, 0xfc, 0xba, 0xa2, 0x19, 0xf7, 0xf0, 0x4b, 0x58, 0x2f, 0x0c, 0x02, 0xa9,
-	0x43, 0xed, 0xc5, 0xf0, 0xbb, 0xe1, 0xc9, 0xcb, 0x61, 0xf3, 0x0e, 0x69, 0x42, 0x63, 0x30, 0x1c,
-	0x3c, 0x1f, 0xf4, 0x9e, 0x0d, 0xce, 0x06, 0xc3, 0xe3, 0xa6, 0x45, 0xd6, 0x60, 0x99, 0xf6, 0x7b,
-	0x47, 0x3f, 0x35, 0x17, 0x0e, 0x9a, 0xbf, 0x5d, 0xb7, 0xad, 0xdf, 0xaf, 0xdb, 0xd6, 0x1f, 0xd7,
-	0x6d, 0xeb, 0x97, 0x3f, 0xdb, 0x77, 0x9c, 0x15, 0x13, 0x66, 0xff, 0xdf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x48, 0x8b, 0xb8, 0x2d, 0x28, 0x0a, 0x00, 0x00,
+	// 1051 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xdd, 0x6e, 0x1b, 0xc5,
+	0x17, 0xef, 0x3a, 0x1f, 0x4e, 0x8e, 0x9d, 0xc4, 0x19, 0xf5, 0xff, 0x8f, 0x15, 0x8a, 0xa9, 0x96,
+	0x0f, 0x45, 0x15, 0xb2, 0x69, 0x82, 0x10, 0x14, 0xa9, 0xe0, 0xc4, 0x26, 0x72, 0x29, 0x8e, 0x35,
+	0x49, 0x29, 0xe4, 0x6e, 0x76, 0x77, 0xbc, 0x59, 0x75, 0x3d, 0xb3, 0x9a, 0x99, 0x6d, 0x12, 0x9e,
+	0x81, 0x0b, 0xde, 0x83, 0x5b, 0x1e, 0x82, 0x4b, 0xc4, 0x13, 0xa0, 0x20, 0x24, 0x1e, 0x03, 0xed,
+	0x8c, 0xd7, 0xde, 0x0f, 0x37, 0x44, 0xdc, 0x58, 0xe3, 0x73, 0x7e, 0xe7, 0xfc, 0xce, 0x9c, 0xaf,
+	0x59, 0x38, 0xf6, 0x03, 0x75, 0x11, 0x3b, 0x6d, 0x97, 0x4f, 0x3a, 0x93, 0x03, 0xcf, 0xe9, 0x4c,
+	0x0e, 0x3a, 0x52, 0xb8, 0x1d, 0xcf, 0x61, 0xdc, 0xa3, 0x1d, 0x9f, 0x32, 0x2a, 0x88, 0xa2, 0x5e,
+	0x27, 0x12, 0x5c, 0xf1, 0x0e, 0x23, 0x13, 0x2a, 0x23, 0xe2, 0xd2, 0xf9, 0xa9, 0xad, 0x35, 0x68,
+	0x7d, 0x26, 0xd8, 0x7d, 0xe0, 0x73, 0xee, 0x87, 0xd4, 0x98, 0x38, 0xf1, 0xb8, 0x23, 0x95, 0x88,
+	0x5d, 0x65, 0x80, 0xbb, 0xad, 0xa2, 0xf6, 0x52, 0x90, 0x28, 0xa2, 0x42, 0x4e, 0xf5, 0xbd, 0xff,
+	0x1a, 0x91, 0x74, 0x2f, 0xe8, 0x84, 0x18, 0x2f, 0xf6, 0x8f, 0x4b, 0xd0, 0xc0, 0x54, 0x51, 0xa6,
+	0x02, 0xce, 0x4e, 0xa2, 0xe4, 0x57, 0xa2, 0x7d, 0xb8, 0x2f, 0x52, 0xd9, 0x88, 0x8a, 0x80, 0x7b,
+	0x43, 0xc2, 0xb8, 0x6c, 0x5a, 0x0f, 0xad, 0xbd, 0x25, 0xbc, 0x50, 0x87, 0x3e, 0x80, 0x4d, 0x27,
+	0xe4, 0xee, 0xab, 0xd3, 0xe0, 0x07, 0x6a, 0xd0, 0x15, 0x8d, 0x2e, 0x48, 0xd1, 0x87, 0xb0, 0xed,
+	0xc4, 0xe3, 0x31, 0x15, 0x5f, 0xc5, 0x2a, 0x16, 0x53, 0xe8, 0x92, 0x86, 0x96, 0x15, 0x68, 0x0f,
+	0xb6, 0x8c, 0x70, 0x44, 0xa4, 0x32, 0xd8, 0x65, 0x8d, 0x2d, 0x8a, 0x35, 0x32, 0x61, 0xea, 0x11,
+	0x45, 0xfa, 0x57, 0x51, 0x20, 0xae, 0x9b, 0x2b, 0x0f, 0xad, 0xbd, 0x35, 0x5c, 0x14, 0xa3, 0x73,
+	0xd8, 0x2b, 0x88, 0xba, 0x63, 0x45, 0xc5, 0x90, 0xab, 0xae, 0xeb, 0x52, 0x29, 0xb3, 0x37, 0x5e,
+	0xd5, 0x64, 0x77, 0xc6, 0xa3, 0xa7, 0xb0, 0x3b, 0xd6, 0xe1, 0xe3, 0x45, 0xf9, 0xab, 0x6a, 0x6f,
+	0xb7, 0x20, 0xec, 0x11, 0xd4, 0x07, 0xcc, 0xa3, 0x57, 0x69, 0x25, 0x9a, 0x50, 0xa5, 0x8c, 0x38,
+	0x21, 0xf5, 0x74, 0xf2, 0xd7, 0x70, 0xfa, 0xf7, 0xae, 0xf9, 0xb6, 0x7f, 0xaf, 0x42, 0x63, 0x98,
+	0xd6, 0x3e, 0x75, 0xfb, 0x08, 0x1a, 0x0e, 0xe7, 0x4a, 0x2a, 0x41, 0xa2, 0x7e, 0xce, 0x7f, 0x49,
+	0x8e, 0x6c, 0xa8, 0x8f, 0xc3, 0x58, 0x5e, 0xa4, 0xb8, 0x8a, 0xc6, 0xe5, 0x64, 0x49, 0x51, 0x2f,
+	0x45, 0xa0, 0xa8, 0x3c, 0xe3, 0x47, 0x7c, 0x32, 0x09, 0xd4, 0x73, 0xee, 0xeb, 0xa2, 0xae, 0xe1,
+	0xb2, 0x22, 0x09, 0xdd, 0x0d, 0x29, 0x61, 0xf1, 0x8c, 0x7b, 0x59, 0x43, 0x0b, 0x52, 0xf4, 0x1e,
+	0x6c, 0x08, 0x1a, 0x91, 0x40, 0xa4, 0x30, 0x53, 0xd0, 0xbc, 0x10, 0x1d, 0x43, 0x43, 0x14, 0x1a,
+	0x58, 0x97, 0xad, 0xb6, 0xff, 0x56, 0x7b, 0x3e, 0x7c, 0xc5, 0x1e, 0xc7, 0x25, 0xa3, 0xa4, 0x83,
+	0x24, 0x23, 0x91, 0xbc, 0xe0, 0x2a, 0x25, 0xac, 0x9a, 0x0e, 0x2a, 0x88, 0xd1, 0xe7, 0x50, 0x0f,
+	0x32, 0x55, 0x6a, 0xae, 0x69, 0xba, 0x9d, 0x0c, 0x5d, 0xb6, 0x88, 0x38, 0x07, 0x46, 0x4f, 0x61,
+	0xc3, 0x4c, 0x60, 0x6a, 0xbd, 0xae, 0xad, 0x9b, 0x19, 0xeb, 0xd3, 0xac, 0x1e, 0xe7, 0xe1, 0x49,
+	0xae, 0x5d, 0x1e, 0x7a, 0x2f, 0x75, 0x5a, 0xd3, 0x40, 0xc1, 0xe4, 0xba, 0xa4, 0x40, 0xcf, 0x60,
+	0x53, 0xc4, 0x4c, 0x05, 0x93, 0xb4, 0xf6, 0xcd, 0x9a, 0xa6, 0xb3, 0x33, 0x74, 0xb3, 0xf6, 0xc0,
+	0x39, 0x24, 0x2e, 0x58, 0xa2, 0x11, 0xfc, 0xcf, 0x25, 0xee, 0x05, 0x3d, 0x4c, 0x3a, 0x4c, 0x9e,
+	0x30, 0x4c, 0x95, 0x08, 0xe8, 0x6b, 0xda, 0xac, 0x6b, 0x97, 0xbb, 0x6d, 0xb3, 0xb1, 0xda, 0xe9,
+	0xc6, 0x6a, 0x1f, 0x72, 0x1e, 0x7e, 0x4b, 0xc2, 0x98, 0xe2, 0xc5, 0x86, 0xe8, 0x1b, 0x40, 0xc4,
+	0xf7, 0x05, 0xf5, 0x49, 0xb6, 0x7a, 0x1b, 0xda, 0xdd, 0xdb, 0x99, 0x08, 0xbb, 0x25, 0x10, 0x5e,
+	0x60, 0x98, 0xd4, 0x45, 0x2a, 0xe2, 0x07, 0xcc, 0x3f, 0x55, 0x44, 0xd1, 0xe6, 0x66, 0xa9, 0x2e,
+	0xa7, 0x19, 0x35, 0xce, 0x81, 0x91, 0x0b, 0x2d, 0x33, 0x98, 0x3a, 0x81, 0x47, 0x21, 0x99, 0x44,
+	0x67, 0x3c, 0xa4, 0x82, 0x30, 0x77, 0x3a, 0x60, 0x5b, 0xd3, 0xae, 0x2a, 0x5e, 0x73, 0xc0, 0xd4,
+	0x27, 0x1f, 0x9b, 0x7b, 0xfe, 0x8b, 0x0b, 0xd4, 0x87, 0x2d, 0x7a, 0xa5, 0x28, 0xf3, 0xa8, 0x97,
+	0xde, 0xf6, 0xef, 0xea, 0x34, 0x7b, 0xf3, 0x28, 0xfb, 0x79, 0x08, 0x2e, 0xda, 0xd8, 0x23, 0x40,
+	0xe5, 0x94, 0xa0, 0x27, 0x50, 0xcf, 0x24, 0x25, 0x59, 0xd7, 0x4b, 0x7b, 0xb5, 0xfd, 0xff, 0x2f,
+	0xce, 0x23, 0xce, 0x61, 0x6d, 0x06, 0xb5, 0x8c, 0x12, 0xb5, 0x00, 0x52, 0xf5, 0x6c, 0x35, 0x64,
+	0x24, 0xe8, 0x0b, 0x00, 0xa2, 0x94, 0x08, 0x9c, 0x58, 0x51, 0xb3, 0x79, 0x6a, 0xfb, 0xef, 0x2c,
+	0x20, 0xa2, 0x5e, 0x77, 0x06, 0xc3, 0x19, 0x13, 0xfb, 0x17, 0x0b, 0xee, 0x2f, 0x02, 0x25, 0x53,
+	0x28, 0xa8, 0xe4, 0x61, 0x9c, 0xc4, 0x91, 0x7d, 0x76, 0x8a, 0x62, 0xf4, 0x0c, 0xb6, 0x3d, 0x7e,
+	0xc9, 0x24, 0x99, 0x44, 0xe1, 0xac, 0xbb, 0x4d, 0x28, 0x0f, 0x32, 0xa1, 0xf4, 0x8a, 0x18, 0x5c,
+	0x36, 0x4b, 0x56, 0xd2, 0x98, 0x30, 0x1e, 0xab, 0x5e, 0x20, 0xcd, 0x44, 0x99, 0xed, 0x55, 0x90,
+	0xda, 0xef, 0xc3, 0x76, 0xc9, 0x1f, 0x6a, 0xc0, 0x12, 0x09, 0xc3, 0x69, 0x96, 0x92, 0xa3, 0xfd,
+	0x25, 0xd4, 0xb3, 0x9d, 0x86, 0x3e, 0x82, 0x55, 0xa9, 0x88, 0x8a, 0xcd, 0x5d, 0x36, 0xf3, 0xc3,
+	0x3e, 0x07, 0xc6, 0x12, 0x4f, 0x71, 0xf6, 0xcf, 0x16, 0xac, 0x61, 0xea, 0x07, 0x52, 0x89, 0x6b,
+	0x74, 0x04, 0x30, 0xc3, 0xa7, 0x65, 0x7d, 0x37, 0xb7, 0xdc, 0x0c, 0x70, 0x3e, 0xc9, 0xb2, 0xcf,
+	0x94, 0xb8, 0xc6, 0x19, 0xb3, 0xdd, 0x73, 0xd8, 0x2a, 0xa8, 0x93, 0xc0, 0x5f, 0xd1, 0x6b, 0x1d,
+	0xd3, 0x3a, 0x4e, 0x8e, 0xe8, 0x31, 0xac, 0xbc, 0x4e, 0x1a, 0xb9, 0x59, 0x29, 0x6d, 0xd0, 0xe2,
+	0x23, 0x82, 0x0d, 0xf2, 0x49, 0xe5, 0x53, 0xcb, 0xfe, 0xcb, 0x82, 0x9d, 0x37, 0x6c, 0x11, 0xe4,
+	0x41, 0x4b, 0x3f, 0x01, 0x7a, 0x25, 0x06, 0xcc, 0x1f, 0x51, 0x71, 0x34, 0x7a, 0x71, 0xc4, 0x99,
+	0x1b, 0x0b, 0x41, 0x99, 0x6b, 0xf8, 0x93, 0x9a, 0x15, 0xe7, 0xaa, 0xc7, 0x63, 0x27, 0xa4, 0xd3,
+	0xc1, 0xba, 0xdd, 0x47, 0xc2, 0xa2, 0x5f, 0xa4, 0x37, 0xb3, 0x54, 0xee, 0xc2, 0x72, 0xbb, 0x0f,
+	0xfb, 0x3b, 0xd8, 0x2a, 0xcc, 0x26, 0x42, 0xb0, 0xac, 0xae, 0x23, 0x3a, 0x4d, 0xa2, 0x3e, 0xa3,
+	0xc7, 0x50, 0xe5, 0xb9, 0x7e, 0xdc, 0x29, 0xb1, 0x9e, 0xea, 0x4f, 0x3d, 0x9c, 0xe2, 0x1e, 0x7d,
+	0x06, 0x1b, 0xb9, 0x46, 0x40, 0x35, 0xa8, 0xbe, 0x18, 0x7e, 0x3d, 0x3c, 0x79, 0x39, 0x6c, 0xdc,
+	0x43, 0x0d, 0xa8, 0x0f, 0x86, 0x83, 0xb3, 0x41, 0xf7, 0xf9, 0xe0, 0x7c, 0x30, 0x3c, 0x6e, 0x58,
+	0x68, 0x1d, 0x56, 0x70, 0xbf, 0xdb, 0xfb, 0xbe, 0x51, 0x39, 0x6c, 0xfc, 0x7a, 0xd3, 0xb2, 0x7e,
+	0xbb, 0x69, 0x59, 0x7f, 0xdc, 0xb4, 0xac, 0x9f, 0xfe, 0x6c, 0xdd, 0x73, 0x56, 0x35, 0xcd, 0xc1,
+	0x3f, 0x03, 0x00, 0xf4, 0x51, 0xf9, 0x96, 0xb5, 0x0a, 0x00, 0x00,
 }
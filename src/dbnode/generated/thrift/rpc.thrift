@@ -185,6 +185,9 @@ struct FetchTaggedRequest {
 	9: optional i64 docsLimit
 	10: optional binary source
 	11: optional bool requireNoWait = false
+	12: optional bool sortByID = false
+	13: optional binary pageAfterID
+	14: optional i64 pageLimit
 }
 
 struct FetchTaggedResult {
@@ -192,6 +195,7 @@ struct FetchTaggedResult {
 	2: required bool exhaustive
 	3: optional i64 waitedIndex
 	4: optional i64 waitedSeriesRead
+	5: optional binary nextPageAfterID
 }
 
 struct FetchTaggedIDResult {
@@ -3429,6 +3429,9 @@ func (p *Segment) String() string {
 //  - DocsLimit
 //  - Source
 //  - RequireNoWait
+//  - SortByID
+//  - PageAfterID
+//  - PageLimit
 type FetchTaggedRequest struct {
 	NameSpace         []byte   `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
 	Query             []byte   `thrift:"query,2,required" db:"query" json:"query"`
@@ -3441,6 +3444,9 @@ type FetchTaggedRequest struct {
 	DocsLimit         *int64   `thrift:"docsLimit,9" db:"docsLimit" json:"docsLimit,omitempty"`
 	Source            []byte   `thrift:"source,10" db:"source" json:"source,omitempty"`
 	RequireNoWait     bool     `thrift:"requireNoWait,11" db:"requireNoWait" json:"requireNoWait,omitempty"`
+	SortByID          bool     `thrift:"sortByID,12" db:"sortByID" json:"sortByID,omitempty"`
+	PageAfterID       []byte   `thrift:"pageAfterID,13" db:"pageAfterID" json:"pageAfterID,omitempty"`
+	PageLimit         *int64   `thrift:"pageLimit,14" db:"pageLimit" json:"pageLimit,omitempty"`
 }
 
 func NewFetchTaggedRequest() *FetchTaggedRequest {
@@ -3512,6 +3518,27 @@ var FetchTaggedRequest_RequireNoWait_DEFAULT bool = false
 func (p *FetchTaggedRequest) GetRequireNoWait() bool {
 	return p.RequireNoWait
 }
+
+var FetchTaggedRequest_SortByID_DEFAULT bool = false
+
+func (p *FetchTaggedRequest) GetSortByID() bool {
+	return p.SortByID
+}
+
+var FetchTaggedRequest_PageAfterID_DEFAULT []byte
+
+func (p *FetchTaggedRequest) GetPageAfterID() []byte {
+	return p.PageAfterID
+}
+
+var FetchTaggedRequest_PageLimit_DEFAULT int64
+
+func (p *FetchTaggedRequest) GetPageLimit() int64 {
+	if !p.IsSetPageLimit() {
+		return FetchTaggedRequest_PageLimit_DEFAULT
+	}
+	return *p.PageLimit
+}
 func (p *FetchTaggedRequest) IsSetSeriesLimit() bool {
 	return p.SeriesLimit != nil
 }
@@ -3536,6 +3563,18 @@ func (p *FetchTaggedRequest) IsSetRequireNoWait() bool {
 	return p.RequireNoWait != FetchTaggedRequest_RequireNoWait_DEFAULT
 }
 
+func (p *FetchTaggedRequest) IsSetSortByID() bool {
+	return p.SortByID != FetchTaggedRequest_SortByID_DEFAULT
+}
+
+func (p *FetchTaggedRequest) IsSetPageAfterID() bool {
+	return p.PageAfterID != nil
+}
+
+func (p *FetchTaggedRequest) IsSetPageLimit() bool {
+	return p.PageLimit != nil
+}
+
 func (p *FetchTaggedRequest) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -3605,6 +3644,18 @@ func (p *FetchTaggedRequest) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField11(iprot); err != nil {
 				return err
 			}
+		case 12:
+			if err := p.ReadField12(iprot); err != nil {
+				return err
+			}
+		case 13:
+			if err := p.ReadField13(iprot); err != nil {
+				return err
+			}
+		case 14:
+			if err := p.ReadField14(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -3735,6 +3786,33 @@ func (p *FetchTaggedRequest) ReadField11(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchTaggedRequest) ReadField12(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 12: ", err)
+	} else {
+		p.SortByID = v
+	}
+	return nil
+}
+
+func (p *FetchTaggedRequest) ReadField13(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 13: ", err)
+	} else {
+		p.PageAfterID = v
+	}
+	return nil
+}
+
+func (p *FetchTaggedRequest) ReadField14(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 14: ", err)
+	} else {
+		p.PageLimit = &v
+	}
+	return nil
+}
+
 func (p *FetchTaggedRequest) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchTaggedRequest"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -3773,6 +3851,15 @@ func (p *FetchTaggedRequest) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField11(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField12(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField13(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField14(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -3938,6 +4025,51 @@ func (p *FetchTaggedRequest) writeField11(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchTaggedRequest) writeField12(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSortByID() {
+		if err := oprot.WriteFieldBegin("sortByID", thrift.BOOL, 12); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 12:sortByID: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(p.SortByID)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.sortByID (12) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 12:sortByID: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FetchTaggedRequest) writeField13(oprot thrift.TProtocol) (err error) {
+	if p.IsSetPageAfterID() {
+		if err := oprot.WriteFieldBegin("pageAfterID", thrift.STRING, 13); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 13:pageAfterID: ", p), err)
+		}
+		if err := oprot.WriteBinary(p.PageAfterID); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.pageAfterID (13) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 13:pageAfterID: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FetchTaggedRequest) writeField14(oprot thrift.TProtocol) (err error) {
+	if p.IsSetPageLimit() {
+		if err := oprot.WriteFieldBegin("pageLimit", thrift.I64, 14); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 14:pageLimit: ", p), err)
+		}
+		if err := oprot.WriteI64(int64(*p.PageLimit)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.pageLimit (14) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 14:pageLimit: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchTaggedRequest) String() string {
 	if p == nil {
 		return "<nil>"
@@ -3950,11 +4082,13 @@ func (p *FetchTaggedRequest) String() string {
 //  - Exhaustive
 //  - WaitedIndex
 //  - WaitedSeriesRead
+//  - NextPageAfterID
 type FetchTaggedResult_ struct {
 	Elements         []*FetchTaggedIDResult_ `thrift:"elements,1,required" db:"elements" json:"elements"`
 	Exhaustive       bool                    `thrift:"exhaustive,2,required" db:"exhaustive" json:"exhaustive"`
 	WaitedIndex      *int64                  `thrift:"waitedIndex,3" db:"waitedIndex" json:"waitedIndex,omitempty"`
 	WaitedSeriesRead *int64                  `thrift:"waitedSeriesRead,4" db:"waitedSeriesRead" json:"waitedSeriesRead,omitempty"`
+	NextPageAfterID  []byte                  `thrift:"nextPageAfterID,5" db:"nextPageAfterID" json:"nextPageAfterID,omitempty"`
 }
 
 func NewFetchTaggedResult_() *FetchTaggedResult_ {
@@ -3986,6 +4120,12 @@ func (p *FetchTaggedResult_) GetWaitedSeriesRead() int64 {
 	}
 	return *p.WaitedSeriesRead
 }
+
+var FetchTaggedResult__NextPageAfterID_DEFAULT []byte
+
+func (p *FetchTaggedResult_) GetNextPageAfterID() []byte {
+	return p.NextPageAfterID
+}
 func (p *FetchTaggedResult_) IsSetWaitedIndex() bool {
 	return p.WaitedIndex != nil
 }
@@ -3994,6 +4134,10 @@ func (p *FetchTaggedResult_) IsSetWaitedSeriesRead() bool {
 	return p.WaitedSeriesRead != nil
 }
 
+func (p *FetchTaggedResult_) IsSetNextPageAfterID() bool {
+	return p.NextPageAfterID != nil
+}
+
 func (p *FetchTaggedResult_) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -4029,6 +4173,10 @@ func (p *FetchTaggedResult_) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField4(iprot); err != nil {
 				return err
 			}
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -4097,6 +4245,15 @@ func (p *FetchTaggedResult_) ReadField4(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchTaggedResult_) ReadField5(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.NextPageAfterID = v
+	}
+	return nil
+}
+
 func (p *FetchTaggedResult_) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchTaggedResult"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -4114,6 +4271,9 @@ func (p *FetchTaggedResult_) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField4(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -4188,6 +4348,21 @@ func (p *FetchTaggedResult_) writeField4(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchTaggedResult_) writeField5(oprot thrift.TProtocol) (err error) {
+	if p.IsSetNextPageAfterID() {
+		if err := oprot.WriteFieldBegin("nextPageAfterID", thrift.STRING, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:nextPageAfterID: ", p), err)
+		}
+		if err := oprot.WriteBinary(p.NextPageAfterID); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.nextPageAfterID (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:nextPageAfterID: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchTaggedResult_) String() string {
 	if p == nil {
 		return "<nil>"
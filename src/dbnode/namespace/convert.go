@@ -216,6 +216,11 @@ func ToMetadata(
 		mOpts = mOpts.SetCacheBlocksOnRetrieve(opts.CacheBlocksOnRetrieve.Value)
 	}
 
+	if opts.FutureWriteClampToleranceNanos != nil {
+		mOpts = mOpts.SetFutureWriteClampTolerance(
+			FromNanos(opts.FutureWriteClampToleranceNanos.Value))
+	}
+
 	if err := mOpts.Validate(); err != nil {
 		return nil, err
 	}
@@ -256,6 +261,7 @@ func ToAggregationOptions(opts *nsproto.AggregationOptions) (AggregationOptions,
 			if err != nil {
 				return nil, err
 			}
+			attrs.FanoutDisabled = agg.Attributes.FanoutDisabled
 			aggregations = append(aggregations, NewAggregatedAggregation(attrs))
 		} else {
 			aggregations = append(aggregations, NewUnaggregatedAggregation())
@@ -340,6 +346,10 @@ func OptionsToProto(opts Options) (*nsproto.NamespaceOptions, error) {
 		ExtendedOptions:       extendedOpts,
 		AggregationOptions:    toProtoAggregationOptions(opts.AggregationOptions()),
 		StagingState:          stagingState,
+
+		FutureWriteClampToleranceNanos: &protobuftypes.Int64Value{
+			Value: opts.FutureWriteClampTolerance().Nanoseconds(),
+		},
 	}
 
 	return nsOpts, nil
@@ -372,6 +382,7 @@ func toProtoAggregationOptions(aggOpts AggregationOptions) *nsproto.AggregationO
 			protoAgg.Attributes = &nsproto.AggregatedAttributes{
 				ResolutionNanos:   agg.Attributes.Resolution.Nanoseconds(),
 				DownsampleOptions: &nsproto.DownsampleOptions{All: agg.Attributes.DownsampleOptions.All},
+				FanoutDisabled:    agg.Attributes.FanoutDisabled,
 			}
 		}
 		protoAggs = append(protoAggs, &protoAgg)
@@ -324,6 +324,23 @@ func TestFromProtoSnapshotEnabled(t *testing.T) {
 	require.Equal(t, !namespace.NewOptions().SnapshotEnabled(), md.Options().SnapshotEnabled())
 }
 
+func TestFutureWriteClampToleranceRoundTrip(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().SetFutureWriteClampTolerance(5*time.Second),
+	)
+	require.NoError(t, err)
+
+	nsOpts, err := namespace.OptionsToProto(md.Options())
+	require.NoError(t, err)
+	require.Equal(t, &protobuftypes.Int64Value{Value: (5 * time.Second).Nanoseconds()},
+		nsOpts.FutureWriteClampToleranceNanos)
+
+	md, err = namespace.ToMetadata("ns1", nsOpts)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, md.Options().FutureWriteClampTolerance())
+}
+
 func TestInvalidExtendedOptions(t *testing.T) {
 	invalidExtendedOptsNoConverterForType := &nsproto.ExtendedOptions{Type: "unknown"}
 	_, err := namespace.ToExtendedOptions(invalidExtendedOptsNoConverterForType)
@@ -385,6 +402,36 @@ func TestAggregationOptsToProto(t *testing.T) {
 	require.Equal(t, validAggregationOpts, *nsOpts.AggregationOptions)
 }
 
+func TestAggregationOptsFanoutDisabledRoundTrip(t *testing.T) {
+	protoOpts := nsproto.AggregationOptions{
+		Aggregations: []*nsproto.Aggregation{
+			{
+				Aggregated: true,
+				Attributes: &nsproto.AggregatedAttributes{
+					ResolutionNanos:   toNanos(1),
+					DownsampleOptions: &nsproto.DownsampleOptions{All: true},
+					FanoutDisabled:    true,
+				},
+			},
+		},
+	}
+
+	aggOpts, err := namespace.ToAggregationOptions(&protoOpts)
+	require.NoError(t, err)
+	require.Len(t, aggOpts.Aggregations(), 1)
+	require.True(t, aggOpts.Aggregations()[0].Attributes.FanoutDisabled)
+
+	md, err := namespace.NewMetadata(ident.StringID("ns1"),
+		namespace.NewOptions().SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg, err := namespace.ToProto(nsMap)
+	require.NoError(t, err)
+	require.Equal(t, protoOpts, *reg.Namespaces["ns1"].AggregationOptions)
+}
+
 func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOptions, observed namespace.Metadata) {
 	require.Equal(t, name, observed.ID().String())
 	opts := observed.Options()
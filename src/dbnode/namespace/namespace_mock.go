@@ -173,6 +173,20 @@ func (mr *MockOptionsMockRecorder) FlushEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushEnabled", reflect.TypeOf((*MockOptions)(nil).FlushEnabled))
 }
 
+// FutureWriteClampTolerance mocks base method.
+func (m *MockOptions) FutureWriteClampTolerance() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FutureWriteClampTolerance")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// FutureWriteClampTolerance indicates an expected call of FutureWriteClampTolerance.
+func (mr *MockOptionsMockRecorder) FutureWriteClampTolerance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FutureWriteClampTolerance", reflect.TypeOf((*MockOptions)(nil).FutureWriteClampTolerance))
+}
+
 // IndexOptions mocks base method.
 func (m *MockOptions) IndexOptions() IndexOptions {
 	m.ctrl.T.Helper()
@@ -341,6 +355,20 @@ func (mr *MockOptionsMockRecorder) SetFlushEnabled(value interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlushEnabled", reflect.TypeOf((*MockOptions)(nil).SetFlushEnabled), value)
 }
 
+// SetFutureWriteClampTolerance mocks base method.
+func (m *MockOptions) SetFutureWriteClampTolerance(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFutureWriteClampTolerance", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFutureWriteClampTolerance indicates an expected call of SetFutureWriteClampTolerance.
+func (mr *MockOptionsMockRecorder) SetFutureWriteClampTolerance(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFutureWriteClampTolerance", reflect.TypeOf((*MockOptions)(nil).SetFutureWriteClampTolerance), value)
+}
+
 // SetIndexOptions mocks base method.
 func (m *MockOptions) SetIndexOptions(value IndexOptions) Options {
 	m.ctrl.T.Helper()
@@ -22,6 +22,7 @@ package namespace
 
 import (
 	"errors"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/retention"
 )
@@ -59,6 +60,7 @@ var (
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
 	errNamespaceRuntimeOptionsNotSet                = errors.New("namespace runtime options is not set")
 	errAggregationOptionsNotSet                     = errors.New("aggregation options is not set")
+	errFutureWriteClampToleranceNegative            = errors.New("future write clamp tolerance must not be negative")
 )
 
 type options struct {
@@ -70,6 +72,7 @@ type options struct {
 	repairEnabled         bool
 	coldWritesEnabled     bool
 	cacheBlocksOnRetrieve bool
+	futureWriteClampTol   time.Duration
 	retentionOpts         retention.Options
 	indexOpts             IndexOptions
 	schemaHis             SchemaHistory
@@ -118,6 +121,10 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	if o.futureWriteClampTol < 0 {
+		return errFutureWriteClampToleranceNegative
+	}
+
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -155,6 +162,7 @@ func (o *options) Equal(value Options) bool {
 		o.repairEnabled == value.RepairEnabled() &&
 		o.coldWritesEnabled == value.ColdWritesEnabled() &&
 		o.cacheBlocksOnRetrieve == value.CacheBlocksOnRetrieve() &&
+		o.futureWriteClampTol == value.FutureWriteClampTolerance() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
 		o.indexOpts.Equal(value.IndexOptions()) &&
 		o.schemaHis.Equal(value.SchemaHistory()) &&
@@ -233,6 +241,16 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetFutureWriteClampTolerance(value time.Duration) Options {
+	opts := *o
+	opts.futureWriteClampTol = value
+	return &opts
+}
+
+func (o *options) FutureWriteClampTolerance() time.Duration {
+	return o.futureWriteClampTol
+}
+
 func (o *options) SetCacheBlocksOnRetrieve(value bool) Options {
 	opts := *o
 	opts.cacheBlocksOnRetrieve = value
@@ -217,3 +217,12 @@ func TestOptionsValidateStagingStatus(t *testing.T) {
 	o1 = o1.SetStagingState(StagingState{status: StagingStatus(12)})
 	require.Error(t, o1.Validate())
 }
+
+func TestOptionsValidateFutureWriteClampTolerance(t *testing.T) {
+	o1 := NewOptions().SetFutureWriteClampTolerance(5 * time.Second)
+	require.NoError(t, o1.Validate())
+	require.False(t, o1.Equal(NewOptions()))
+
+	o1 = o1.SetFutureWriteClampTolerance(-time.Second)
+	require.Equal(t, errFutureWriteClampToleranceNegative, o1.Validate())
+}
@@ -82,6 +82,16 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled for this namespace.
 	ColdWritesEnabled() bool
 
+	// SetFutureWriteClampTolerance sets how far past the buffer future limit
+	// the timestamp of a write may be for it to be clamped to the current time
+	// rather than rejected, zero disables clamping.
+	SetFutureWriteClampTolerance(value time.Duration) Options
+
+	// FutureWriteClampTolerance returns how far past the buffer future limit
+	// the timestamp of a write may be for it to be clamped to the current time
+	// rather than rejected, zero disables clamping.
+	FutureWriteClampTolerance() time.Duration
+
 	// SetCacheBlocksOnRetrieve sets whether to cache blocks from this namespace when retrieved.
 	// If global CacheBlocksOnRetrieve option in config.BlockRetrievePolicy is set to false,
 	// then that will override any namespace-specific CacheBlocksOnRetrieve options set to true.
@@ -359,6 +369,10 @@ type AggregatedAttributes struct {
 
 	// DownsampleOptions stores options around how data points are downsampled.
 	DownsampleOptions DownsampleOptions
+
+	// FanoutDisabled excludes the namespace from automatic query fanout
+	// without removing it, e.g. while the namespace is being backfilled.
+	FanoutDisabled bool
 }
 
 // DownsampleOptions is a set of options related to downsampling data.
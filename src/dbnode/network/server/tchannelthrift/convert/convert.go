@@ -263,6 +263,12 @@ func FromRPCFetchTaggedRequest(
 	if len(req.Source) > 0 {
 		opts.Source = req.Source
 	}
+	if len(req.PageAfterID) > 0 {
+		opts.PageAfterID = req.PageAfterID
+	}
+	if l := req.PageLimit; l != nil {
+		opts.PageLimit = int(*l)
+	}
 
 	q, err := idx.Unmarshal(req.Query)
 	if err != nil {
@@ -326,6 +332,17 @@ func ToRPCFetchTaggedRequest(
 		request.Source = opts.Source
 	}
 
+	if len(opts.PageAfterID) > 0 || opts.PageLimit > 0 {
+		// NB: pages are only deterministic across requests when sorted by ID.
+		request.SortByID = true
+		request.PageAfterID = opts.PageAfterID
+	}
+
+	if opts.PageLimit > 0 {
+		l := int64(opts.PageLimit)
+		request.PageLimit = &l
+	}
+
 	return request, nil
 }
 
@@ -180,6 +180,28 @@ func TestConvertFetchTaggedRequest(t *testing.T) {
 	}
 }
 
+func TestConvertFetchTaggedRequestPage(t *testing.T) {
+	ns := ident.StringID("abc")
+	q, _ := termQueryTestCase(t)
+	opts := index.QueryOptions{
+		StartInclusive: xtime.Now().Add(-time.Hour),
+		EndExclusive:   xtime.Now(),
+		PageAfterID:    []byte("foo"),
+		PageLimit:      10,
+	}
+
+	req, err := convert.ToRPCFetchTaggedRequest(ns, index.Query{Query: q}, opts, true)
+	require.NoError(t, err)
+	require.True(t, req.SortByID)
+	require.Equal(t, []byte("foo"), req.PageAfterID)
+	require.Equal(t, int64(10), req.GetPageLimit())
+
+	_, _, observedOpts, _, err := convert.FromRPCFetchTaggedRequest(&req, nil)
+	require.NoError(t, err)
+	require.Equal(t, opts.PageAfterID, observedOpts.PageAfterID)
+	require.Equal(t, opts.PageLimit, observedOpts.PageLimit)
+}
+
 func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	var (
 		seriesLimit       int64 = 10
@@ -61,6 +61,10 @@ func (e Endpoint) String() string {
 		return "FetchTagged"
 	case Query:
 		return "Query"
+	case Aggregate:
+		return "Aggregate"
+	case AggregateTiles:
+		return "AggregateTiles"
 	case Unknown:
 		fallthrough
 	default:
@@ -0,0 +1,345 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	stdctx "context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+	xtls "github.com/m3db/m3/src/x/tls"
+
+	apachethrift "github.com/apache/thrift/lib/go/thrift"
+	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go"
+	"github.com/uber/tchannel-go/thrift"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTLSHandshakeTimeout = 10 * time.Second
+
+	// unknownClientIdentity attributes connections whose client did not
+	// present a certificate with a usable identity.
+	unknownClientIdentity = "unknown"
+)
+
+type clientIdentityMetrics struct {
+	connectionsOpened   tally.Counter
+	connectionsRejected tally.Counter
+	connectionsOpen     tally.Gauge
+	requests            tally.Counter
+	requestsRejected    tally.Counter
+}
+
+func newClientIdentityMetrics(scope tally.Scope) clientIdentityMetrics {
+	return clientIdentityMetrics{
+		connectionsOpened:   scope.Counter("connections-opened"),
+		connectionsRejected: scope.Counter("connections-rejected"),
+		connectionsOpen:     scope.Gauge("connections-open"),
+		requests:            scope.Counter("requests"),
+		requestsRejected:    scope.Counter("requests-rejected"),
+	}
+}
+
+type clientIdentityState struct {
+	quota       ClientIdentityQuota
+	connections int
+	// requests is the number of requests in the second starting at window.
+	requests int
+	window   time.Time
+	metrics  clientIdentityMetrics
+}
+
+// clientIdentityTracker tracks the open connections and request rate of
+// each TLS client identity to attribute them and enforce their quotas.
+type clientIdentityTracker struct {
+	sync.Mutex
+
+	opts            ClientIdentityOptions
+	nowFn           clock.NowFn
+	scope           tally.Scope
+	logger          *zap.Logger
+	handshakeErrors tally.Counter
+	identities      map[string]*clientIdentityState
+}
+
+func newClientIdentityTracker(
+	opts ClientIdentityOptions,
+	iOpts instrument.Options,
+) *clientIdentityTracker {
+	scope := iOpts.MetricsScope().SubScope("client-identity")
+	return &clientIdentityTracker{
+		opts:            opts,
+		nowFn:           time.Now,
+		scope:           scope,
+		logger:          iOpts.Logger().With(zap.String("component", "client-identity")),
+		handshakeErrors: scope.Counter("handshake-errors"),
+		identities:      make(map[string]*clientIdentityState),
+	}
+}
+
+// stateWithLock returns the state of the identity, creating it if it does
+// not exist yet, the lock must be held.
+func (t *clientIdentityTracker) stateWithLock(identity string) *clientIdentityState {
+	state, ok := t.identities[identity]
+	if !ok {
+		state = &clientIdentityState{
+			quota: t.opts.Quota(identity),
+			metrics: newClientIdentityMetrics(t.scope.Tagged(map[string]string{
+				"identity": identity,
+			})),
+		}
+		t.identities[identity] = state
+	}
+	return state
+}
+
+// AcquireConnection returns whether a new connection from the identity is
+// within its quota, if so the connection must be released when closed.
+func (t *clientIdentityTracker) AcquireConnection(identity string) bool {
+	t.Lock()
+	state := t.stateWithLock(identity)
+	if limit := state.quota.MaxConnections; limit > 0 && state.connections >= limit {
+		t.Unlock()
+		state.metrics.connectionsRejected.Inc(1)
+		t.logger.Warn("rejected connection exceeding client identity quota",
+			zap.String("identity", identity),
+			zap.Int("maxConnections", limit))
+		return false
+	}
+	state.connections++
+	open := state.connections
+	t.Unlock()
+
+	state.metrics.connectionsOpened.Inc(1)
+	state.metrics.connectionsOpen.Update(float64(open))
+	t.logger.Debug("accepted connection", zap.String("identity", identity))
+	return true
+}
+
+// ReleaseConnection releases a connection acquired by the identity.
+func (t *clientIdentityTracker) ReleaseConnection(identity string) {
+	t.Lock()
+	state := t.stateWithLock(identity)
+	state.connections--
+	open := state.connections
+	t.Unlock()
+
+	state.metrics.connectionsOpen.Update(float64(open))
+}
+
+// AllowRequest returns whether a new request from the identity is within
+// its quota.
+func (t *clientIdentityTracker) AllowRequest(identity string) bool {
+	now := t.nowFn()
+	t.Lock()
+	state := t.stateWithLock(identity)
+	if window := now.Truncate(time.Second); !window.Equal(state.window) {
+		state.window = window
+		state.requests = 0
+	}
+	limit := state.quota.MaxRequestsPerSecond
+	allowed := limit <= 0 || state.requests < limit
+	if allowed {
+		state.requests++
+	}
+	t.Unlock()
+
+	if !allowed {
+		state.metrics.requestsRejected.Inc(1)
+		return false
+	}
+	state.metrics.requests.Inc(1)
+	return true
+}
+
+// clientIdentityListener is a listener that completes the TLS handshake of
+// accepted connections, identifies their client and only returns the
+// connections within the quota of their identity.
+type clientIdentityListener struct {
+	net.Listener
+
+	tlsConfig        *tls.Config
+	tracker          *clientIdentityTracker
+	handshakeTimeout time.Duration
+	connCh           chan net.Conn
+	errCh            chan error
+	closedCh         chan struct{}
+	closeOnce        sync.Once
+}
+
+func newClientIdentityListener(
+	listener net.Listener,
+	tlsConfig *tls.Config,
+	tracker *clientIdentityTracker,
+) *clientIdentityListener {
+	handshakeTimeout := tracker.opts.HandshakeTimeout
+	if handshakeTimeout <= 0 {
+		handshakeTimeout = defaultTLSHandshakeTimeout
+	}
+	l := &clientIdentityListener{
+		Listener:         listener,
+		tlsConfig:        tlsConfig,
+		tracker:          tracker,
+		handshakeTimeout: handshakeTimeout,
+		connCh:           make(chan net.Conn),
+		errCh:            make(chan error),
+		closedCh:         make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+func (l *clientIdentityListener) acceptLoop() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.errCh <- err:
+			case <-l.closedCh:
+				return
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		// Handshake asynchronously so slow clients do not block others.
+		go l.handshake(conn)
+	}
+}
+
+func (l *clientIdentityListener) handshake(conn net.Conn) {
+	tlsConn := tls.Server(conn, l.tlsConfig)
+	_ = tlsConn.SetDeadline(l.tracker.nowFn().Add(l.handshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		l.tracker.handshakeErrors.Inc(1)
+		l.tracker.logger.Debug("TLS handshake failed",
+			zap.Stringer("remoteAddr", conn.RemoteAddr()), zap.Error(err))
+		_ = conn.Close()
+		return
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	identity, ok := xtls.PeerIdentity(tlsConn.ConnectionState())
+	if !ok {
+		identity = unknownClientIdentity
+	}
+	if !l.tracker.AcquireConnection(identity) {
+		_ = tlsConn.Close()
+		return
+	}
+
+	identityConn := &clientIdentityConn{
+		Conn:     tlsConn,
+		identity: identity,
+		tracker:  l.tracker,
+	}
+	select {
+	case l.connCh <- identityConn:
+	case <-l.closedCh:
+		_ = identityConn.Close()
+	}
+}
+
+func (l *clientIdentityListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connCh:
+		return conn, nil
+	case err := <-l.errCh:
+		return nil, err
+	case <-l.closedCh:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *clientIdentityListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closedCh)
+	})
+	return l.Listener.Close()
+}
+
+// clientIdentityConn is a TLS connection of an identified client.
+type clientIdentityConn struct {
+	net.Conn
+
+	identity  string
+	tracker   *clientIdentityTracker
+	closeOnce sync.Once
+}
+
+func (c *clientIdentityConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.tracker.ReleaseConnection(c.identity)
+	})
+	return c.Conn.Close()
+}
+
+// clientIdentityConnContext sets the client identity of the connection as
+// the value of the base context of every call received on the connection.
+func clientIdentityConnContext(ctx stdctx.Context, conn net.Conn) stdctx.Context {
+	if identityConn, ok := conn.(*clientIdentityConn); ok {
+		return tchannelthrift.NewContextWithClientIdentity(ctx, identityConn.identity)
+	}
+	return ctx
+}
+
+// clientIdentityServer attributes requests to the client identity of their
+// connection and rejects the requests exceeding the identity quota.
+type clientIdentityServer struct {
+	thrift.TChanServer
+
+	tracker *clientIdentityTracker
+}
+
+func newClientIdentityServer(
+	server thrift.TChanServer,
+	tracker *clientIdentityTracker,
+) thrift.TChanServer {
+	return &clientIdentityServer{
+		TChanServer: server,
+		tracker:     tracker,
+	}
+}
+
+func (s *clientIdentityServer) Handle(
+	ctx thrift.Context,
+	methodName string,
+	protocol apachethrift.TProtocol,
+) (bool, apachethrift.TStruct, error) {
+	identity, ok := tchannelthrift.ClientIdentityFromContext(ctx)
+	if !ok {
+		identity = unknownClientIdentity
+	}
+	if !s.tracker.AllowRequest(identity) {
+		return false, nil, tchannel.NewSystemError(tchannel.ErrCodeBusy,
+			"client identity %s exceeded request quota", identity)
+	}
+	return s.TChanServer.Handle(ctx, methodName, protocol)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/x/instrument"
+	xtls "github.com/m3db/m3/src/x/tls"
+	"github.com/m3db/m3/src/x/tls/tlstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestClientIdentityTrackerQuotas(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	tracker := newClientIdentityTracker(ClientIdentityOptions{
+		DefaultQuota: ClientIdentityQuota{MaxConnections: 1, MaxRequestsPerSecond: 2},
+		Overrides: map[string]ClientIdentityQuota{
+			"team-b": {},
+		},
+	}, instrument.NewOptions().SetMetricsScope(scope))
+	now := time.Unix(100, 0)
+	tracker.nowFn = func() time.Time { return now }
+
+	require.True(t, tracker.AcquireConnection("team-a"))
+	require.False(t, tracker.AcquireConnection("team-a"))
+	tracker.ReleaseConnection("team-a")
+	require.True(t, tracker.AcquireConnection("team-a"))
+
+	require.True(t, tracker.AllowRequest("team-a"))
+	require.True(t, tracker.AllowRequest("team-a"))
+	require.False(t, tracker.AllowRequest("team-a"))
+	now = now.Add(time.Second)
+	require.True(t, tracker.AllowRequest("team-a"))
+
+	// Overrides without limits are unlimited.
+	for i := 0; i < 10; i++ {
+		require.True(t, tracker.AcquireConnection("team-b"))
+		require.True(t, tracker.AllowRequest("team-b"))
+	}
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1),
+		counters["client-identity.connections-rejected+identity=team-a"].Value())
+	require.Equal(t, int64(1),
+		counters["client-identity.requests-rejected+identity=team-a"].Value())
+	require.Equal(t, int64(3),
+		counters["client-identity.requests+identity=team-a"].Value())
+	require.Equal(t, int64(10),
+		counters["client-identity.requests+identity=team-b"].Value())
+}
+
+func TestClientIdentityListener(t *testing.T) {
+	var (
+		ca         = tlstest.NewCA(t, t.TempDir())
+		serverCert = ca.IssueServer("server")
+		clientCert = ca.IssueClient("team-a")
+	)
+	serverCfg, err := xtls.ServerConfiguration{
+		CertFile:     serverCert.CertFile,
+		KeyFile:      serverCert.KeyFile,
+		ClientCAFile: ca.CertFile,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := xtls.ClientConfiguration{
+		CertFile:   clientCert.CertFile,
+		KeyFile:    clientCert.KeyFile,
+		CAFile:     ca.CertFile,
+		ServerName: "localhost",
+	}.NewTLSConfig()
+	require.NoError(t, err)
+
+	tracker := newClientIdentityTracker(ClientIdentityOptions{
+		Overrides: map[string]ClientIdentityQuota{
+			"team-a": {MaxConnections: 1},
+		},
+	}, instrument.NewOptions())
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := newClientIdentityListener(tcpListener, serverCfg, tracker)
+	defer listener.Close()
+
+	dial := func(cfg *tls.Config) net.Conn {
+		conn, err := tls.Dial("tcp", tcpListener.Addr().String(), cfg)
+		require.NoError(t, err)
+		return conn
+	}
+
+	clientConn := dial(clientCfg)
+	defer clientConn.Close()
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	ctx := clientIdentityConnContext(context.Background(), conn)
+	identity, ok := tchannelthrift.ClientIdentityFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "team-a", identity)
+
+	// Clients without a certificate are attributed to the unknown identity.
+	anonymousCfg := clientCfg.Clone()
+	anonymousCfg.Certificates = nil
+	anonymousConn := dial(anonymousCfg)
+	defer anonymousConn.Close()
+	conn, err = listener.Accept()
+	require.NoError(t, err)
+	require.Equal(t, unknownClientIdentity, conn.(*clientIdentityConn).identity)
+
+	// Connections exceeding the quota of the identity are closed.
+	rejectedConn := dial(clientCfg)
+	defer rejectedConn.Close()
+	_ = rejectedConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = rejectedConn.Read(make([]byte, 1))
+	require.Error(t, err)
+	require.False(t, isTimeout(err))
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
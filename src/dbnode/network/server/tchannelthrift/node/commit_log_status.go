@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+
+	"github.com/uber/tchannel-go/thrift"
+)
+
+// CommitLogStatusResult describes the commit log queue of the node.
+type CommitLogStatusResult struct {
+	NumQueuedWrites int64  `json:"numQueuedWrites"`
+	QueueCapacity   int64  `json:"queueCapacity"`
+	LagNanos        int64  `json:"lagNanos"`
+	Backpressure    string `json:"backpressure"`
+}
+
+// CommitLogStatus returns the depth, lag and backpressure state of the commit
+// log queue. Writes are rejected once the queue is full, so clients can poll
+// it to slow down while the backpressure is elevated rather than waiting for
+// write errors. Unlike other RPCs it is served while the node is overloaded.
+// It is served by the node HTTP JSON interface.
+func (s *service) CommitLogStatus(tctx thrift.Context) (*CommitLogStatusResult, error) {
+	db, ok := s.state.DB()
+	if !ok {
+		return nil, convert.ToRPCError(errDatabaseIsNotInitializedYet)
+	}
+
+	callStart := s.nowFn()
+	status := db.CommitLogStatus()
+	s.metrics.commitLogStatus.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return &CommitLogStatusResult{
+		NumQueuedWrites: status.NumWrites,
+		QueueCapacity:   status.Capacity,
+		LagNanos:        int64(status.Lag),
+		Backpressure:    status.Backpressure.String(),
+	}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber/tchannel-go/thrift"
+)
+
+var (
+	errBootstrapFromDonorNoNamespace = errors.New("namespace is required")
+	errBootstrapFromDonorInvalidTime = errors.New("range end must be after range start")
+)
+
+// BootstrapShardsFromDonorRequest is a request to bootstrap shards of a
+// namespace from a designated donor host rather than quorum-selected peers.
+type BootstrapShardsFromDonorRequest struct {
+	NameSpace  string       `json:"nameSpace"`
+	Shards     []uint32     `json:"shards"`
+	Donor      string       `json:"donor"`
+	RangeStart int64        `json:"rangeStart"`
+	RangeEnd   int64        `json:"rangeEnd"`
+	RangeType  rpc.TimeType `json:"rangeType"`
+}
+
+// BootstrapShardsFromDonorResult acknowledges the shards being bootstrapped
+// from the donor.
+type BootstrapShardsFromDonorResult struct {
+	Shards []uint32 `json:"shards"`
+}
+
+// BootstrapShardsFromDonor starts replacing the flushed data of the requested
+// shards with the data of the donor host only, which is useful when a single
+// replica is known to have good data. The request returns once validated and
+// the replacement runs in the background. It is served by the node HTTP JSON
+// interface.
+func (s *service) BootstrapShardsFromDonor(
+	tctx thrift.Context,
+	req *BootstrapShardsFromDonorRequest,
+) (*BootstrapShardsFromDonorResult, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.NameSpace == "" {
+		return nil, tterrors.NewBadRequestError(errBootstrapFromDonorNoNamespace)
+	}
+
+	start, rangeStartErr := convert.ToTime(req.RangeStart, req.RangeType)
+	end, rangeEndErr := convert.ToTime(req.RangeEnd, req.RangeType)
+	if rangeStartErr != nil || rangeEndErr != nil {
+		return nil, tterrors.NewBadRequestError(xerrors.FirstError(rangeStartErr, rangeEndErr))
+	}
+	if !end.After(start) {
+		return nil, tterrors.NewBadRequestError(errBootstrapFromDonorInvalidTime)
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	err = db.BootstrapShardsFromDonor(s.newID(ctx, []byte(req.NameSpace)),
+		req.Shards, req.Donor, xtime.Range{Start: start, End: end})
+	if err != nil {
+		s.metrics.bootstrapFromDonor.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.bootstrapFromDonor.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return &BootstrapShardsFromDonorResult{Shards: req.Shards}, nil
+}
@@ -100,6 +100,52 @@ func TestFetchResultIterTestNoReleaseWithoutAcquire(t *testing.T) {
 	require.Equal(t, 1, blockPermits.closed)
 }
 
+func TestFetchResultIterSortedByIDPage(t *testing.T) {
+	fetchPage := func(afterID []byte, limit int64) ([]string, []byte) {
+		// NB: the index query excludes the series up to the page after ID.
+		res := index.NewQueryResults(ident.StringID("testNs"),
+			index.QueryResultsOptions{SearchAfterID: afterID}, testIndexOptions)
+		batch := make([]doc.Document, 0, 10)
+		for _, i := range []int{7, 2, 9, 0, 4, 1, 8, 3, 6, 5} {
+			batch = append(batch, doc.NewDocumentFromMetadata(doc.Metadata{
+				ID: []byte(fmt.Sprintf("seriesId_%d", i)),
+			}))
+		}
+		_, _, err := res.AddDocuments(batch)
+		require.NoError(t, err)
+
+		iter := newFetchTaggedResultsIter(fetchTaggedResultsIterOpts{
+			queryResult: index.QueryResult{
+				Results: res,
+			},
+			blockPermits:    &fakePermits{available: 10, quotaPerPermit: 1000},
+			instrumentClose: func(err error) {},
+			sortByID:        true,
+			pageLimit:       limit,
+		})
+		ctx := context.NewBackground()
+		var ids []string
+		for iter.Next(ctx) {
+			ids = append(ids, string(iter.Current().ID()))
+		}
+		require.NoError(t, iter.Err())
+		iter.Close(nil)
+		return ids, iter.NextPageAfterID()
+	}
+
+	ids, next := fetchPage(nil, 4)
+	require.Equal(t, []string{"seriesId_0", "seriesId_1", "seriesId_2", "seriesId_3"}, ids)
+	require.Equal(t, []byte("seriesId_3"), next)
+
+	ids, next = fetchPage(next, 4)
+	require.Equal(t, []string{"seriesId_4", "seriesId_5", "seriesId_6", "seriesId_7"}, ids)
+	require.Equal(t, []byte("seriesId_7"), next)
+
+	ids, next = fetchPage(next, 4)
+	require.Equal(t, []string{"seriesId_8", "seriesId_9"}, ids)
+	require.Nil(t, next)
+}
+
 func requireSeriesBlockMetric(t *testing.T, scope tally.TestScope) {
 	values, ok := scope.Snapshot().Histograms()["series-blocks+"]
 	require.True(t, ok)
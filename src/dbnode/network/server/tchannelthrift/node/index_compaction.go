@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+
+	"github.com/uber/tchannel-go/thrift"
+)
+
+var errIndexCompactionNoNamespace = errors.New("namespace is required")
+
+// IndexCompactionPlanRequest is a request for the index compaction plans of
+// a namespace.
+type IndexCompactionPlanRequest struct {
+	NameSpace string `json:"nameSpace"`
+}
+
+// IndexCompactionPlanResult describes the compaction planned for each index
+// block of a namespace.
+type IndexCompactionPlanResult struct {
+	Blocks []IndexBlockCompactionPlan `json:"blocks"`
+}
+
+// IndexBlockCompactionPlan describes the compaction planned for the in-memory
+// segments of an index block.
+type IndexBlockCompactionPlan struct {
+	BlockStart                  int64                     `json:"blockStart"`
+	NumForegroundSegments       int                       `json:"numForegroundSegments"`
+	NumBackgroundSegments       int                       `json:"numBackgroundSegments"`
+	BackgroundSegmentsSize      int64                     `json:"backgroundSegmentsSize"`
+	NumUnplannedSegments        int                       `json:"numUnplannedSegments"`
+	CompactingBackground        bool                      `json:"compactingBackground"`
+	GarbageCollectingBackground bool                      `json:"garbageCollectingBackground"`
+	Tasks                       []IndexCompactionTaskPlan `json:"tasks"`
+}
+
+// IndexCompactionTaskPlan describes the segments a planned compaction task
+// will merge into a single segment.
+type IndexCompactionTaskPlan struct {
+	NumMutableSegments     int   `json:"numMutableSegments"`
+	NumFSTSegments         int   `json:"numFSTSegments"`
+	CumulativeMutableAgeNS int64 `json:"cumulativeMutableAgeNanos"`
+	CumulativeSize         int64 `json:"cumulativeSize"`
+}
+
+// CompactIndexRequest is a request to compact the in-memory segments of the
+// index block of a namespace starting at the given block start.
+type CompactIndexRequest struct {
+	NameSpace  string       `json:"nameSpace"`
+	BlockStart int64        `json:"blockStart"`
+	RangeType  rpc.TimeType `json:"rangeType"`
+	Priority   string       `json:"priority"`
+}
+
+// CompactIndexResult is the result of a manually triggered index compaction.
+type CompactIndexResult struct{}
+
+// IndexCompactionPlan returns the background compaction planned for the
+// in-memory segments of each index block of a namespace, which helps explain
+// index memory growth and query latency caused by fragmented segments. It is
+// served by the node HTTP JSON interface.
+func (s *service) IndexCompactionPlan(
+	tctx thrift.Context,
+	req *IndexCompactionPlanRequest,
+) (*IndexCompactionPlanResult, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.NameSpace == "" {
+		return nil, tterrors.NewBadRequestError(errIndexCompactionNoNamespace)
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	plans, err := db.IndexCompactionPlans(s.newID(ctx, []byte(req.NameSpace)))
+	if err != nil {
+		s.metrics.indexCompactionPlan.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := &IndexCompactionPlanResult{
+		Blocks: make([]IndexBlockCompactionPlan, 0, len(plans)),
+	}
+	for _, p := range plans {
+		tasks := make([]IndexCompactionTaskPlan, 0, len(p.Tasks))
+		for _, t := range p.Tasks {
+			tasks = append(tasks, IndexCompactionTaskPlan{
+				NumMutableSegments:     t.NumMutable,
+				NumFSTSegments:         t.NumFST,
+				CumulativeMutableAgeNS: int64(t.CumulativeMutableAge),
+				CumulativeSize:         t.CumulativeSize,
+			})
+		}
+		res.Blocks = append(res.Blocks, IndexBlockCompactionPlan{
+			BlockStart:                  int64(p.BlockStart),
+			NumForegroundSegments:       p.NumForegroundSegments,
+			NumBackgroundSegments:       p.NumBackgroundSegments,
+			BackgroundSegmentsSize:      p.BackgroundSegmentsSize,
+			NumUnplannedSegments:        p.NumUnplannedSegments,
+			CompactingBackground:        p.CompactingBackground,
+			GarbageCollectingBackground: p.GarbageCollectingBackground,
+			Tasks:                       tasks,
+		})
+	}
+
+	s.metrics.indexCompactionPlan.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+// CompactIndex triggers a compaction of the in-memory segments of the index
+// block of a namespace starting at the requested block start. A normal
+// priority runs the tasks planned by the background compaction planner right
+// away while a high priority merges all the background segments of the block
+// into a single segment. It is served by the node HTTP JSON interface.
+func (s *service) CompactIndex(
+	tctx thrift.Context,
+	req *CompactIndexRequest,
+) (*CompactIndexResult, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.NameSpace == "" {
+		return nil, tterrors.NewBadRequestError(errIndexCompactionNoNamespace)
+	}
+
+	blockStart, err := convert.ToTime(req.BlockStart, req.RangeType)
+	if err != nil {
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	priority, err := index.ParseCompactionPriority(req.Priority)
+	if err != nil {
+		return nil, tterrors.NewBadRequestError(err)
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	err = db.CompactIndex(s.newID(ctx, []byte(req.NameSpace)), blockStart, priority)
+	if err != nil {
+		s.metrics.compactIndex.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.compactIndex.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return &CompactIndexResult{}, nil
+}
@@ -21,6 +21,9 @@
 package node
 
 import (
+	"crypto/tls"
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/x/instrument"
 
@@ -56,6 +59,38 @@ func defaultTChanNodeServerFn(
 	return rpc.NewTChanNodeServer(service)
 }
 
+// ClientIdentityQuota limits the connections and requests of a single TLS
+// client identity, a zero limit is unlimited.
+type ClientIdentityQuota struct {
+	// MaxConnections is the maximum number of open connections.
+	MaxConnections int
+
+	// MaxRequestsPerSecond is the maximum number of requests per second.
+	MaxRequestsPerSecond int
+}
+
+// ClientIdentityOptions configures how TLS client certificate identities
+// are attributed and limited.
+type ClientIdentityOptions struct {
+	// HandshakeTimeout is the timeout for a client to complete the TLS
+	// handshake, defaults to 10s if not set.
+	HandshakeTimeout time.Duration
+
+	// DefaultQuota is the quota of each identity without an override.
+	DefaultQuota ClientIdentityQuota
+
+	// Overrides are the quotas of specific identities.
+	Overrides map[string]ClientIdentityQuota
+}
+
+// Quota returns the quota of the identity.
+func (o ClientIdentityOptions) Quota(identity string) ClientIdentityQuota {
+	if quota, ok := o.Overrides[identity]; ok {
+		return quota
+	}
+	return o.DefaultQuota
+}
+
 // Options are thrift options.
 type Options interface {
 	// SetChannelOptions sets a tchan channel options.
@@ -81,6 +116,19 @@ type Options interface {
 
 	// InstrumentOptions returns the instrumentation options.
 	InstrumentOptions() instrument.Options
+
+	// SetTLSConfig sets the TLS config, if set the server only accepts
+	// TLS connections.
+	SetTLSConfig(value *tls.Config) Options
+
+	// TLSConfig returns the TLS config.
+	TLSConfig() *tls.Config
+
+	// SetClientIdentityOptions sets the TLS client identity options.
+	SetClientIdentityOptions(value ClientIdentityOptions) Options
+
+	// ClientIdentityOptions returns the TLS client identity options.
+	ClientIdentityOptions() ClientIdentityOptions
 }
 
 type options struct {
@@ -88,6 +136,8 @@ type options struct {
 	instrumentOpts    instrument.Options
 	tchanChannelFn    NewTChanChannelFn
 	tchanNodeServerFn NewTChanNodeServerFn
+	tlsConfig         *tls.Config
+	clientIdentity    ClientIdentityOptions
 }
 
 // NewOptions creates a new options.
@@ -137,3 +187,23 @@ func (o *options) SetInstrumentOptions(value instrument.Options) Options {
 func (o *options) InstrumentOptions() instrument.Options {
 	return o.instrumentOpts
 }
+
+func (o *options) SetTLSConfig(value *tls.Config) Options {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *options) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
+
+func (o *options) SetClientIdentityOptions(value ClientIdentityOptions) Options {
+	opts := *o
+	opts.clientIdentity = value
+	return &opts
+}
+
+func (o *options) ClientIdentityOptions() ClientIdentityOptions {
+	return o.clientIdentity
+}
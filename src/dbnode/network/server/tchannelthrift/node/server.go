@@ -21,6 +21,8 @@
 package node
 
 import (
+	"net"
+
 	ns "github.com/m3db/m3/src/dbnode/network/server"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node/channel"
@@ -59,6 +61,18 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 		immutableOpts := *chanOpts
 		opts = &immutableOpts
 	}
+
+	var tracker *clientIdentityTracker
+	tlsConfig := s.opts.TLSConfig()
+	if tlsConfig != nil {
+		tracker = newClientIdentityTracker(s.opts.ClientIdentityOptions(),
+			s.opts.InstrumentOptions())
+		if opts == nil {
+			opts = &tchannel.ChannelOptions{}
+		}
+		opts.ConnContext = clientIdentityConnContext
+	}
+
 	channel, err := s.opts.TChanChannelFn()(s.service, channel.ChannelName, opts)
 	if err != nil {
 		return nil, err
@@ -66,8 +80,26 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 
 	iOpts := s.opts.InstrumentOptions()
 	server := s.opts.TChanNodeServerFn()(s.service, iOpts)
+	if tracker != nil {
+		server = newClientIdentityServer(server, tracker)
+	}
 	tchannelthrift.RegisterServer(channel, server, s.contextPool)
-	channel.ListenAndServe(s.address)
+
+	if tlsConfig == nil {
+		channel.ListenAndServe(s.address)
+		return channel.Close, nil
+	}
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		channel.Close()
+		return nil, err
+	}
+	if err := channel.Serve(newClientIdentityListener(listener, tlsConfig, tracker)); err != nil {
+		channel.Close()
+		_ = listener.Close()
+		return nil, err
+	}
 
 	return channel.Close, nil
 }
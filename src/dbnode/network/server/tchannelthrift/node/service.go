@@ -21,6 +21,7 @@
 package node
 
 import (
+	"bytes"
 	goctx "context"
 	"errors"
 	"fmt"
@@ -102,6 +103,13 @@ var (
 
 	// errHealthNotSet is raised when server health data structure is not set.
 	errHealthNotSet = errors.New("server health not set")
+
+	// errPaginationRequiresSortByID is raised when a page of fetch tagged results
+	// is requested without sorting the results by ID.
+	errPaginationRequiresSortByID = errors.New("page after ID and page limit require sorting by ID")
+
+	// errInvalidPage is raised when a negative page limit is requested.
+	errInvalidPage = errors.New("page limit must not be negative")
 )
 
 type serviceMetrics struct {
@@ -114,6 +122,12 @@ type serviceMetrics struct {
 	fetchBlocksMetadata     instrument.MethodMetrics
 	repair                  instrument.MethodMetrics
 	truncate                instrument.MethodMetrics
+	snapshotConsistency     instrument.MethodMetrics
+	releaseConsistency      instrument.MethodMetrics
+	bootstrapFromDonor      instrument.MethodMetrics
+	indexCompactionPlan     instrument.MethodMetrics
+	compactIndex            instrument.MethodMetrics
+	commitLogStatus         instrument.MethodMetrics
 	fetchBatchRawRPCS       tally.Counter
 	fetchBatchRaw           instrument.BatchMethodMetrics
 	writeBatchRawRPCs       tally.Counter
@@ -138,6 +152,12 @@ func newServiceMetrics(scope tally.Scope, opts instrument.TimerOptions) serviceM
 		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", opts),
 		repair:                  instrument.NewMethodMetrics(scope, "repair", opts),
 		truncate:                instrument.NewMethodMetrics(scope, "truncate", opts),
+		snapshotConsistency:     instrument.NewMethodMetrics(scope, "snapshotConsistencyPoint", opts),
+		releaseConsistency:      instrument.NewMethodMetrics(scope, "releaseSnapshotConsistencyPoint", opts),
+		bootstrapFromDonor:      instrument.NewMethodMetrics(scope, "bootstrapShardsFromDonor", opts),
+		indexCompactionPlan:     instrument.NewMethodMetrics(scope, "indexCompactionPlan", opts),
+		compactIndex:            instrument.NewMethodMetrics(scope, "compactIndex", opts),
+		commitLogStatus:         instrument.NewMethodMetrics(scope, "commitLogStatus", opts),
 		fetchBatchRawRPCS:       scope.Counter("fetchBatchRaw-rpcs"),
 		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", opts),
 		writeBatchRawRPCs:       scope.Counter("writeBatchRaw-rpcs"),
@@ -167,6 +187,7 @@ type service struct {
 	metrics           serviceMetrics
 	queryLimits       limits.QueryLimits
 	seriesReadPermits permits.Manager
+	slowQueryLog      *slowQueryLogger
 }
 
 type serviceState struct {
@@ -279,6 +300,45 @@ type Service interface {
 	// Metadata returns the metadata for the given key and a bool indicating
 	// if it is present.
 	Metadata(key string) (string, bool)
+
+	// SnapshotConsistencyPoint forces a commit log rotation and snapshot and
+	// returns the volumes of a namespace that make up a consistent cut.
+	SnapshotConsistencyPoint(
+		ctx thrift.Context,
+		req *SnapshotConsistencyPointRequest,
+	) (*SnapshotConsistencyPointResult, error)
+
+	// ReleaseSnapshotConsistencyPoint releases the volumes pinned by a
+	// consistency point.
+	ReleaseSnapshotConsistencyPoint(
+		ctx thrift.Context,
+		req *ReleaseSnapshotConsistencyPointRequest,
+	) (*ReleaseSnapshotConsistencyPointResult, error)
+
+	// BootstrapShardsFromDonor starts replacing the flushed data of shards of a
+	// namespace with the data of a designated donor host only.
+	BootstrapShardsFromDonor(
+		ctx thrift.Context,
+		req *BootstrapShardsFromDonorRequest,
+	) (*BootstrapShardsFromDonorResult, error)
+
+	// IndexCompactionPlan returns the background compaction planned for the
+	// in-memory segments of each index block of a namespace.
+	IndexCompactionPlan(
+		ctx thrift.Context,
+		req *IndexCompactionPlanRequest,
+	) (*IndexCompactionPlanResult, error)
+
+	// CompactIndex triggers a compaction of the in-memory segments of an
+	// index block of a namespace.
+	CompactIndex(
+		ctx thrift.Context,
+		req *CompactIndexRequest,
+	) (*CompactIndexResult, error)
+
+	// CommitLogStatus returns the depth, lag and backpressure state of the
+	// commit log queue.
+	CommitLogStatus(ctx thrift.Context) (*CommitLogStatusResult, error)
 }
 
 // NewService creates a new node TChannel Thrift service
@@ -346,6 +406,7 @@ func NewService(db storage.Database, opts tchannelthrift.Options) Service {
 		},
 		queryLimits:       opts.QueryLimits(),
 		seriesReadPermits: opts.PermitsOptions().SeriesReadPermitsManager(),
+		slowQueryLog:      newSlowQueryLogger(opts.SlowQueryLogOptions(), iopts),
 	}
 }
 
@@ -440,9 +501,10 @@ func (s *service) Bootstrapped(ctx thrift.Context) (*rpc.NodeBootstrappedResult_
 // BootstrappedInPlacementOrNoPlacement is designed to be used with cluster
 // management tools like k8s that expected an endpoint that will return
 // success if the node either:
-// 1) Has no cluster placement set yet.
-// 2) Is bootstrapped and durable, meaning it is bootstrapped and is able
-//    to bootstrap the shards it owns from it's own local disk.
+//  1. Has no cluster placement set yet.
+//  2. Is bootstrapped and durable, meaning it is bootstrapped and is able
+//     to bootstrap the shards it owns from it's own local disk.
+//
 // This is useful in addition to the Bootstrapped RPC method as it helps
 // progress node addition/removal/modifications when no placement is set
 // at all and therefore the node has not been able to bootstrap yet.
@@ -622,6 +684,7 @@ func (s *service) aggregateTiles(
 		return 0, tterrors.NewBadRequestError(err)
 	}
 
+	callStart := s.nowFn()
 	sourceNsID := s.pools.id.GetStringID(ctx, req.SourceNamespace)
 	targetNsID := s.pools.id.GetStringID(ctx, req.TargetNamespace)
 
@@ -636,6 +699,14 @@ func (s *service) aggregateTiles(
 	}
 
 	processedTileCount, err := db.AggregateTiles(ctx, sourceNsID, targetNsID, opts)
+	s.observeSlowQuery(ctx, slowQueryStats{
+		endpoint:  tchannelthrift.AggregateTiles,
+		namespace: sourceNsID,
+		start:     start,
+		end:       end,
+		duration:  s.nowFn().Sub(callStart),
+		err:       err,
+	})
 	if err != nil {
 		return processedTileCount, convert.ToRPCError(err)
 	}
@@ -669,6 +740,15 @@ func (s *service) Fetch(tctx thrift.Context, req *rpc.FetchRequest) (*rpc.FetchR
 	// Make datapoints an initialized empty array for JSON serialization as empty array than null
 	datapoints, err := s.readDatapoints(ctx, db, nsID, tsID, start, end,
 		req.ResultTimeType)
+	s.observeSlowQuery(ctx, slowQueryStats{
+		endpoint:      tchannelthrift.Fetch,
+		namespace:     nsID,
+		start:         start,
+		end:           end,
+		duration:      s.nowFn().Sub(callStart),
+		seriesMatched: 1,
+		err:           err,
+	})
 	if err != nil {
 		s.metrics.fetch.ReportError(s.nowFn().Sub(callStart))
 		return nil, convert.ToRPCError(err)
@@ -784,6 +864,9 @@ func (s *service) fetchTaggedResult(ctx context.Context,
 	if v := int64(iter.WaitedSeriesRead()); v > 0 {
 		response.WaitedSeriesRead = &v
 	}
+	if v := iter.NextPageAfterID(); v != nil {
+		response.NextPageAfterID = v
+	}
 
 	return response, nil
 }
@@ -809,7 +892,7 @@ func (s *service) FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedReque
 
 		s.metrics.fetchTagged.ReportSuccessOrError(err, s.nowFn().Sub(callStart))
 	}
-	iter, err := s.fetchTaggedIter(ctx, req, instrumentClose)
+	iter, err := s.fetchTaggedIter(ctx, req, callStart, instrumentClose)
 	if err != nil {
 		instrumentClose(err)
 	}
@@ -819,6 +902,7 @@ func (s *service) FetchTaggedIter(ctx context.Context, req *rpc.FetchTaggedReque
 func (s *service) fetchTaggedIter(
 	ctx context.Context,
 	req *rpc.FetchTaggedRequest,
+	callStart time.Time,
 	instrumentClose func(error),
 ) (FetchTaggedResultsIter, error) {
 	db, err := s.startReadRPCWithDB()
@@ -833,11 +917,16 @@ func (s *service) fetchTaggedIter(
 	if err != nil {
 		return nil, tterrors.NewBadRequestError(err)
 	}
+	if err := validateFetchTaggedPage(req); err != nil {
+		return nil, tterrors.NewBadRequestError(err)
+	}
 
+	indexStart := s.nowFn()
 	queryResult, err := db.QueryIDs(ctx, ns, query, opts)
 	if err != nil {
 		return nil, convert.ToRPCError(err)
 	}
+	indexDuration := s.nowFn().Sub(indexStart)
 
 	permits, err := s.seriesReadPermits.NewPermits(ctx)
 	if err != nil {
@@ -847,6 +936,7 @@ func (s *service) fetchTaggedIter(
 	tagEncoder := s.pools.tagEncoder.Get()
 	ctx.RegisterFinalizer(tagEncoder)
 
+	clientIdentity, _ := tchannelthrift.ClientIdentityFromContext(ctx.GoContext())
 	return newFetchTaggedResultsIter(fetchTaggedResultsIterOpts{
 		queryResult:     queryResult,
 		queryOpts:       opts,
@@ -860,9 +950,27 @@ func (s *service) fetchTaggedIter(
 		blockPermits:    permits,
 		requireNoWait:   req.RequireNoWait,
 		indexWaited:     queryResult.Waited,
+		query:           query,
+		callStart:       callStart,
+		indexDuration:   indexDuration,
+		nowFn:           s.nowFn,
+		slowQueryLog:    s.slowQueryLog,
+		clientIdentity:  clientIdentity,
+		sortByID:        req.SortByID,
+		pageLimit:       req.GetPageLimit(),
 	}), nil
 }
 
+func validateFetchTaggedPage(req *rpc.FetchTaggedRequest) error {
+	if !req.SortByID && (req.IsSetPageAfterID() || req.IsSetPageLimit()) {
+		return errPaginationRequiresSortByID
+	}
+	if req.GetPageLimit() < 0 {
+		return errInvalidPage
+	}
+	return nil
+}
+
 // FetchTaggedResultsIter iterates over the results from FetchTagged
 // The iterator is not thread safe and must only be accessed from a single goroutine.
 type FetchTaggedResultsIter interface {
@@ -881,6 +989,10 @@ type FetchTaggedResultsIter interface {
 	// Namespace is the namespace.
 	Namespace() ident.ID
 
+	// NextPageAfterID returns the ID of the last series of the page of results
+	// sorted by ID to resume the query after, or nil if no results remain.
+	NextPageAfterID() []byte
+
 	// Next advances to the next element, returning if one exists.
 	//
 	// Iterators that embed this interface should expose a Current() function to return the element retrieved by Next.
@@ -900,6 +1012,8 @@ type FetchTaggedResultsIter interface {
 
 type fetchTaggedResultsIter struct {
 	fetchTaggedResultsIterOpts
+	entries          []index.ResultsMapEntry
+	nextPageAfterID  []byte
 	idResults        []idResult
 	idx              int
 	blockReadIdx     int
@@ -924,18 +1038,60 @@ type fetchTaggedResultsIterOpts struct {
 	blockPermits    permits.Permits
 	requireNoWait   bool
 	indexWaited     int
+	query           index.Query
+	callStart       time.Time
+	indexDuration   time.Duration
+	nowFn           clock.NowFn
+	slowQueryLog    *slowQueryLogger
+	clientIdentity  string
+	sortByID        bool
+	pageLimit       int64
 }
 
 func newFetchTaggedResultsIter(opts fetchTaggedResultsIterOpts) FetchTaggedResultsIter { //nolint: gocritic
+	entries, nextPageAfterID := fetchTaggedPage(opts.queryResult.Results.Map(),
+		opts.sortByID, opts.pageLimit)
 	return &fetchTaggedResultsIter{
 		fetchTaggedResultsIterOpts: opts,
-		idResults:                  make([]idResult, 0, opts.queryResult.Results.Map().Len()),
+		entries:                    entries,
+		nextPageAfterID:            nextPageAfterID,
+		idResults:                  make([]idResult, 0, len(entries)),
 		permits:                    make([]permits.Permit, 0),
 	}
 }
 
+// fetchTaggedPage returns the query result entries to iterate over. When sorting
+// by ID is requested the entries are sorted so that pages are deterministic and
+// restricted to the page limit, along with the ID of the last entry of the page
+// to resume after or nil if no results remain. The index query already excludes
+// series up to and including the requested page after ID.
+func fetchTaggedPage(
+	results *index.ResultsMap,
+	sortByID bool,
+	limit int64,
+) ([]index.ResultsMapEntry, []byte) {
+	entries := make([]index.ResultsMapEntry, 0, results.Len())
+	for _, entry := range results.Iter() { // nolint: gocritic
+		entries = append(entries, entry)
+	}
+	if !sortByID {
+		return entries, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Key(), entries[j].Key()) < 0
+	})
+	if limit == 0 || limit >= int64(len(entries)) {
+		return entries, nil
+	}
+	entries = entries[:limit]
+	// NB: copy the ID since the results map may be finalized before the
+	// response is written.
+	return entries, append([]byte(nil), entries[limit-1].Key()...)
+}
+
 func (i *fetchTaggedResultsIter) NumIDs() int {
-	return i.queryResult.Results.Map().Len()
+	return len(i.entries)
 }
 
 func (i *fetchTaggedResultsIter) Exhaustive() bool {
@@ -954,10 +1110,14 @@ func (i *fetchTaggedResultsIter) Namespace() ident.ID {
 	return i.nsID
 }
 
+func (i *fetchTaggedResultsIter) NextPageAfterID() []byte {
+	return i.nextPageAfterID
+}
+
 func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 	// initialize the iterator state on the first fetch.
 	if i.idx == 0 {
-		for _, entry := range i.queryResult.Results.Map().Iter() { // nolint: gocritic
+		for _, entry := range i.entries { // nolint: gocritic
 			result := idResult{
 				queryResult: entry,
 				docReader:   i.docReader,
@@ -986,7 +1146,7 @@ func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 		i.idResults[i.idx-1].blockReaders = nil
 	}
 
-	if i.idx == i.queryResult.Results.Map().Len() {
+	if i.idx == len(i.entries) {
 		return false
 	}
 
@@ -994,7 +1154,7 @@ func (i *fetchTaggedResultsIter) Next(ctx context.Context) bool {
 		// ensure the blockReaders exist for the current series ID. additionally try to prefetch additional blockReaders
 		// for future seriesID to pipeline the disk reads.
 	readBlocks:
-		for i.blockReadIdx < i.queryResult.Results.Map().Len() {
+		for i.blockReadIdx < len(i.entries) {
 			currResult := &i.idResults[i.blockReadIdx]
 			blockIter := currResult.blockReadersIter
 
@@ -1094,12 +1254,46 @@ func (i *fetchTaggedResultsIter) Current() IDResult {
 
 func (i *fetchTaggedResultsIter) Close(err error) {
 	i.instrumentClose(err)
+	if i.slowQueryLog != nil {
+		i.observeSlowQuery(err)
+	}
 	for _, p := range i.permits {
 		i.blockPermits.Release(p)
 	}
 	i.blockPermits.Close()
 }
 
+func (i *fetchTaggedResultsIter) observeSlowQuery(err error) {
+	var bytesRead int
+	for idx := range i.idResults {
+		bytesRead += i.idResults[idx].bytesRead
+	}
+	i.slowQueryLog.Observe(slowQueryStats{
+		endpoint:       tchannelthrift.FetchTagged,
+		namespace:      i.nsID,
+		query:          i.query,
+		start:          i.queryOpts.StartInclusive,
+		end:            i.queryOpts.EndExclusive,
+		indexDuration:  i.indexDuration,
+		duration:       i.nowFn().Sub(i.callStart),
+		seriesMatched:  i.NumIDs(),
+		docsMatched:    i.queryResult.Results.TotalDocsCount(),
+		bytesRead:      bytesRead,
+		shardsFn:       i.shardsTouched,
+		clientIdentity: i.clientIdentity,
+		err:            err,
+	})
+}
+
+func (i *fetchTaggedResultsIter) shardsTouched() int {
+	shardSet := i.db.ShardSet()
+	shards := make(map[uint32]struct{})
+	for _, entry := range i.queryResult.Results.Map().Iter() { // nolint: gocritic
+		shards[shardSet.Lookup(ident.BytesID(entry.Key()))] = struct{}{}
+	}
+	return len(shards)
+}
+
 // IDResult is the FetchTagged result for a series ID.
 type IDResult interface {
 	// ID returns the series ID.
@@ -1122,6 +1316,7 @@ type idResult struct {
 	blockReadersIter series.BlockReaderIter
 	blockReaders     [][]xio.BlockReader
 	quotaUsed        int64
+	bytesRead        int
 	iOpts            instrument.Options
 }
 
@@ -1152,12 +1347,71 @@ func (i *idResult) WriteSegments(ctx context.Context, dst []*rpc.Segments) ([]*r
 			return nil, err
 		}
 		if segments != nil {
+			i.bytesRead += segmentsLen(segments)
 			dst = append(dst, segments)
 		}
 	}
 	return dst, nil
 }
 
+func segmentsLen(segments *rpc.Segments) int {
+	var n int
+	if merged := segments.Merged; merged != nil {
+		n += len(merged.Head) + len(merged.Tail)
+	}
+	for _, unmerged := range segments.Unmerged {
+		n += len(unmerged.Head) + len(unmerged.Tail)
+	}
+	return n
+}
+
+func rawResultsLen(results []*rpc.FetchRawResult_) int {
+	var n int
+	for _, result := range results {
+		for _, segments := range result.Segments {
+			n += segmentsLen(segments)
+		}
+	}
+	return n
+}
+
+// observeSlowQuery logs the query if the slow query log is enabled and the
+// query exceeds any of its thresholds.
+func (s *service) observeSlowQuery(ctx context.Context, stats slowQueryStats) {
+	if s.slowQueryLog == nil {
+		return
+	}
+	stats.clientIdentity, _ = tchannelthrift.ClientIdentityFromContext(ctx.GoContext())
+	s.slowQueryLog.Observe(stats)
+}
+
+func (s *service) observeSlowAggregate(
+	ctx context.Context,
+	endpoint tchannelthrift.Endpoint,
+	ns ident.ID,
+	query index.Query,
+	opts index.AggregationOptions,
+	queryResult index.AggregateQueryResult,
+	callStart time.Time,
+	err error,
+) {
+	stats := slowQueryStats{
+		endpoint:  endpoint,
+		namespace: ns,
+		query:     query,
+		start:     opts.StartInclusive,
+		end:       opts.EndExclusive,
+		err:       err,
+	}
+	// NB: aggregate queries are served entirely by the index.
+	stats.duration = s.nowFn().Sub(callStart)
+	stats.indexDuration = stats.duration
+	if queryResult.Results != nil {
+		stats.docsMatched = queryResult.Results.TotalDocsCount()
+	}
+	s.observeSlowQuery(ctx, stats)
+}
+
 func (s *service) Aggregate(tctx thrift.Context, req *rpc.AggregateQueryRequest) (*rpc.AggregateQueryResult_, error) {
 	db, err := s.startReadRPCWithDB()
 	if err != nil {
@@ -1175,6 +1429,7 @@ func (s *service) Aggregate(tctx thrift.Context, req *rpc.AggregateQueryRequest)
 	}
 
 	queryResult, err := db.AggregateQuery(ctx, ns, query, opts)
+	s.observeSlowAggregate(ctx, tchannelthrift.Aggregate, ns, query, opts, queryResult, callStart, err)
 	if err != nil {
 		s.metrics.aggregate.ReportError(s.nowFn().Sub(callStart))
 		return nil, convert.ToRPCError(err)
@@ -1219,6 +1474,7 @@ func (s *service) AggregateRaw(tctx thrift.Context, req *rpc.AggregateQueryRawRe
 	}
 
 	queryResult, err := db.AggregateQuery(ctx, ns, query, opts)
+	s.observeSlowAggregate(ctx, tchannelthrift.AggregateRaw, ns, query, opts, queryResult, callStart, err)
 	if err != nil {
 		s.metrics.aggregate.ReportError(s.nowFn().Sub(callStart))
 		return nil, convert.ToRPCError(err)
@@ -1359,6 +1615,15 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 	s.metrics.fetchBatchRaw.ReportRetryableErrors(retryableErrors)
 	s.metrics.fetchBatchRaw.ReportNonRetryableErrors(nonRetryableErrors)
 	s.metrics.fetchBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+	s.observeSlowQuery(ctx, slowQueryStats{
+		endpoint:      tchannelthrift.FetchBatchRaw,
+		namespace:     nsID,
+		start:         start,
+		end:           end,
+		duration:      s.nowFn().Sub(callStart),
+		seriesMatched: len(req.Ids),
+		bytesRead:     rawResultsLen(result.Elements),
+	})
 
 	return result, nil
 }
@@ -1376,6 +1641,8 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 		ctx                = addRequestDataToContext(tctx, req.Source, tchannelthrift.FetchBatchRawV2)
 		nsIDs              = make([]ident.ID, 0, len(req.Elements))
 		result             = rpc.NewFetchBatchRawResult_()
+		queryStart         xtime.UnixNano
+		queryEnd           xtime.UnixNano
 		success            int
 		retryableErrors    int
 		nonRetryableErrors int
@@ -1401,6 +1668,13 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 			return nil, tterrors.NewBadRequestError(xerrors.FirstError(rangeStartErr, rangeEndErr))
 		}
 
+		if start.Before(queryStart) || queryStart.IsZero() {
+			queryStart = start
+		}
+		if end.After(queryEnd) {
+			queryEnd = end
+		}
+
 		rawResult := rpc.NewFetchRawResult_()
 		result.Elements = append(result.Elements, rawResult)
 		tsID := s.newID(ctx, elem.ID)
@@ -1446,6 +1720,19 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 	s.metrics.fetchBatchRaw.ReportRetryableErrors(retryableErrors)
 	s.metrics.fetchBatchRaw.ReportNonRetryableErrors(nonRetryableErrors)
 	s.metrics.fetchBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+	var queryNsID ident.ID
+	if len(nsIDs) == 1 {
+		queryNsID = nsIDs[0]
+	}
+	s.observeSlowQuery(ctx, slowQueryStats{
+		endpoint:      tchannelthrift.FetchBatchRawV2,
+		namespace:     queryNsID,
+		start:         queryStart,
+		end:           queryEnd,
+		duration:      s.nowFn().Sub(callStart),
+		seriesMatched: len(req.Elements),
+		bytesRead:     rawResultsLen(result.Elements),
+	})
 
 	return result, nil
 }
@@ -35,10 +35,13 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	conv "github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
@@ -59,6 +62,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber/tchannel-go/thrift"
@@ -2073,6 +2077,44 @@ func TestServiceFetchTaggedErrs(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestServiceFetchTaggedPageRequiresSortByID(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+
+	startNanos, err := convert.ToValue(start, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	endNanos, err := convert.ToValue(end, rpc.TimeType_UNIX_NANOSECONDS)
+	require.NoError(t, err)
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+
+	pageLimit := int64(10)
+	_, err = service.FetchTagged(tctx, &rpc.FetchTaggedRequest{
+		NameSpace:  []byte("metrics"),
+		Query:      data,
+		RangeStart: startNanos,
+		RangeEnd:   endNanos,
+		FetchData:  false,
+		PageLimit:  &pageLimit,
+	})
+	require.Equal(t, tterrors.NewBadRequestError(errPaginationRequiresSortByID), err)
+}
+
 func TestServiceFetchTaggedReturnOnFirstErr(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -3170,6 +3212,282 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceSnapshotConsistencyPoint(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID       = "metrics"
+		snapshotID = uuid.NewUUID()
+		blockStart = xtime.Now().Truncate(time.Hour)
+	)
+	mockDB.EXPECT().SnapshotConsistencyPoint(ident.NewIDMatcher(nsID)).
+		Return(storage.SnapshotConsistencyPoint{
+			SnapshotID:   snapshotID,
+			SnapshotTime: blockStart.Add(time.Minute),
+			CommitLog:    persist.CommitLogFile{FilePath: "commitlog-0-3.db", Index: 3},
+			Volumes: []storage.SnapshotConsistencyVolume{
+				{
+					FileSetType: persist.FileSetFlushType,
+					Shard:       1,
+					BlockStart:  blockStart.Add(-time.Hour),
+					VolumeIndex: 2,
+				},
+				{
+					FileSetType: persist.FileSetSnapshotType,
+					Shard:       1,
+					BlockStart:  blockStart,
+					VolumeIndex: 0,
+				},
+			},
+		}, nil)
+
+	r, err := service.SnapshotConsistencyPoint(tctx,
+		&SnapshotConsistencyPointRequest{NameSpace: nsID})
+	require.NoError(t, err)
+	assert.Equal(t, &SnapshotConsistencyPointResult{
+		SnapshotID:     snapshotID.String(),
+		SnapshotTime:   int64(blockStart.Add(time.Minute)),
+		CommitLogPath:  "commitlog-0-3.db",
+		CommitLogIndex: 3,
+		Volumes: []SnapshotConsistencyVolume{
+			{
+				FileSetType: "flush",
+				Shard:       1,
+				BlockStart:  int64(blockStart.Add(-time.Hour)),
+				VolumeIndex: 2,
+			},
+			{
+				FileSetType: "snapshot",
+				Shard:       1,
+				BlockStart:  int64(blockStart),
+				VolumeIndex: 0,
+			},
+		},
+	}, r)
+}
+
+func TestServiceReleaseSnapshotConsistencyPoint(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	snapshotID := uuid.NewUUID()
+	mockDB.EXPECT().ReleaseSnapshotConsistencyPoint(snapshotID).Return(nil)
+
+	r, err := service.ReleaseSnapshotConsistencyPoint(tctx,
+		&ReleaseSnapshotConsistencyPointRequest{SnapshotID: snapshotID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, &ReleaseSnapshotConsistencyPointResult{}, r)
+
+	_, err = service.ReleaseSnapshotConsistencyPoint(tctx,
+		&ReleaseSnapshotConsistencyPointRequest{SnapshotID: "not-a-uuid"})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+}
+
+func TestServiceBootstrapShardsFromDonor(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = xtime.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+		end   = start.Add(2 * time.Hour)
+	)
+	mockDB.EXPECT().BootstrapShardsFromDonor(ident.NewIDMatcher(nsID),
+		[]uint32{1, 3}, "donor", xtime.Range{Start: start, End: end}).
+		Return(nil)
+
+	r, err := service.BootstrapShardsFromDonor(tctx, &BootstrapShardsFromDonorRequest{
+		NameSpace:  nsID,
+		Shards:     []uint32{1, 3},
+		Donor:      "donor",
+		RangeStart: int64(start),
+		RangeEnd:   int64(end),
+		RangeType:  rpc.TimeType_UNIX_NANOSECONDS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &BootstrapShardsFromDonorResult{Shards: []uint32{1, 3}}, r)
+
+	_, err = service.BootstrapShardsFromDonor(tctx, &BootstrapShardsFromDonorRequest{
+		NameSpace:  nsID,
+		Shards:     []uint32{1},
+		Donor:      "donor",
+		RangeStart: int64(end),
+		RangeEnd:   int64(start),
+		RangeType:  rpc.TimeType_UNIX_NANOSECONDS,
+	})
+	require.Equal(t, tterrors.NewBadRequestError(errBootstrapFromDonorInvalidTime), err)
+}
+
+func TestServiceCommitLogStatus(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	// The status is served even while the node is overloaded.
+	mockDB.EXPECT().IsOverloaded().Return(true).AnyTimes()
+	mockDB.EXPECT().CommitLogStatus().Return(commitlog.QueueStatus{
+		NumWrites:    95,
+		Capacity:     100,
+		Lag:          time.Second,
+		Backpressure: commitlog.BackpressureSaturated,
+	})
+
+	r, err := service.CommitLogStatus(tctx)
+	require.NoError(t, err)
+	require.Equal(t, &CommitLogStatusResult{
+		NumQueuedWrites: 95,
+		QueueCapacity:   100,
+		LagNanos:        int64(time.Second),
+		Backpressure:    "saturated",
+	}, r)
+}
+
+func TestServiceIndexCompactionPlan(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID       = "metrics"
+		blockStart = xtime.Now().Truncate(2 * time.Hour)
+	)
+	mockDB.EXPECT().IndexCompactionPlans(ident.NewIDMatcher(nsID)).
+		Return([]index.CompactionPlan{
+			{
+				BlockStart:             blockStart,
+				NumForegroundSegments:  1,
+				NumBackgroundSegments:  3,
+				BackgroundSegmentsSize: 300,
+				CompactingBackground:   true,
+				Tasks: []compaction.TaskSummary{
+					{
+						NumMutable:           1,
+						NumFST:               1,
+						CumulativeMutableAge: time.Minute,
+						CumulativeSize:       200,
+					},
+				},
+			},
+		}, nil)
+
+	r, err := service.IndexCompactionPlan(tctx, &IndexCompactionPlanRequest{
+		NameSpace: nsID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &IndexCompactionPlanResult{
+		Blocks: []IndexBlockCompactionPlan{
+			{
+				BlockStart:             int64(blockStart),
+				NumForegroundSegments:  1,
+				NumBackgroundSegments:  3,
+				BackgroundSegmentsSize: 300,
+				CompactingBackground:   true,
+				Tasks: []IndexCompactionTaskPlan{
+					{
+						NumMutableSegments:     1,
+						NumFSTSegments:         1,
+						CumulativeMutableAgeNS: int64(time.Minute),
+						CumulativeSize:         200,
+					},
+				},
+			},
+		},
+	}, r)
+
+	_, err = service.IndexCompactionPlan(tctx, &IndexCompactionPlanRequest{})
+	require.Equal(t, tterrors.NewBadRequestError(errIndexCompactionNoNamespace), err)
+}
+
+func TestServiceCompactIndex(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID       = "metrics"
+		blockStart = xtime.Now().Truncate(2 * time.Hour)
+	)
+	mockDB.EXPECT().CompactIndex(ident.NewIDMatcher(nsID), blockStart,
+		index.HighCompactionPriority).Return(nil)
+
+	r, err := service.CompactIndex(tctx, &CompactIndexRequest{
+		NameSpace:  nsID,
+		BlockStart: int64(blockStart),
+		RangeType:  rpc.TimeType_UNIX_NANOSECONDS,
+		Priority:   "high",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CompactIndexResult{}, r)
+
+	_, err = service.CompactIndex(tctx, &CompactIndexRequest{
+		NameSpace:  nsID,
+		BlockStart: int64(blockStart),
+		RangeType:  rpc.TimeType_UNIX_NANOSECONDS,
+		Priority:   "urgent",
+	})
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsBadRequestError(rpcErr))
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
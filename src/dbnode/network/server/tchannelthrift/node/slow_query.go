@@ -0,0 +1,161 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+// slowQueryStats is the breakdown of a single query used to decide
+// whether it is slow and to describe it if so.
+type slowQueryStats struct {
+	endpoint tchannelthrift.Endpoint
+	// namespace is nil if the query spans several namespaces.
+	namespace ident.ID
+	// query is nil for endpoints that read series by ID.
+	query         fmt.Stringer
+	start         xtime.UnixNano
+	end           xtime.UnixNano
+	indexDuration time.Duration
+	duration      time.Duration
+	seriesMatched int
+	docsMatched   int
+	bytesRead     int
+	// shardsFn lazily resolves the shards touched by the query since it
+	// requires hashing every matched series ID.
+	shardsFn func() int
+	// clientIdentity is the TLS client certificate identity of the caller
+	// if identified.
+	clientIdentity string
+	err            error
+}
+
+type slowQueryMetrics struct {
+	duration  tally.Counter
+	docs      tally.Counter
+	bytesRead tally.Counter
+}
+
+func newSlowQueryMetrics(scope tally.Scope) slowQueryMetrics {
+	return slowQueryMetrics{
+		duration: scope.Tagged(map[string]string{
+			"threshold": "duration",
+		}).Counter("slow-query"),
+		docs: scope.Tagged(map[string]string{
+			"threshold": "docs",
+		}).Counter("slow-query"),
+		bytesRead: scope.Tagged(map[string]string{
+			"threshold": "bytes-read",
+		}).Counter("slow-query"),
+	}
+}
+
+// slowQueryLogger logs queries that exceed any of the configured thresholds.
+type slowQueryLogger struct {
+	opts    tchannelthrift.SlowQueryLogOptions
+	logger  *zap.Logger
+	metrics slowQueryMetrics
+}
+
+// newSlowQueryLogger returns a slow query logger, or nil if the slow query
+// log is disabled.
+func newSlowQueryLogger(
+	opts tchannelthrift.SlowQueryLogOptions,
+	iOpts instrument.Options,
+) *slowQueryLogger {
+	if !opts.Enabled {
+		return nil
+	}
+	return &slowQueryLogger{
+		opts:    opts,
+		logger:  iOpts.Logger().With(zap.String("component", "slow-query-log")),
+		metrics: newSlowQueryMetrics(iOpts.MetricsScope().SubScope("slow-query-log")),
+	}
+}
+
+// Observe records the query and logs it if it exceeds any threshold,
+// returning whether the query was considered slow.
+func (l *slowQueryLogger) Observe(stats slowQueryStats) bool {
+	var (
+		opts           = l.opts
+		slowDuration   = opts.DurationThreshold > 0 && stats.duration >= opts.DurationThreshold
+		slowDocs       = opts.DocsThreshold > 0 && stats.docsMatched >= opts.DocsThreshold
+		slowBytesRead  = opts.BytesReadThreshold > 0 && stats.bytesRead >= opts.BytesReadThreshold
+		isSlowQuery    = slowDuration || slowDocs || slowBytesRead
+		shardsTouched  int
+		blocksDuration = stats.duration - stats.indexDuration
+	)
+	if !isSlowQuery {
+		return false
+	}
+
+	if slowDuration {
+		l.metrics.duration.Inc(1)
+	}
+	if slowDocs {
+		l.metrics.docs.Inc(1)
+	}
+	if slowBytesRead {
+		l.metrics.bytesRead.Inc(1)
+	}
+
+	if stats.shardsFn != nil {
+		shardsTouched = stats.shardsFn()
+	}
+
+	fields := []zap.Field{
+		zap.Stringer("endpoint", stats.endpoint),
+	}
+	if stats.namespace != nil {
+		fields = append(fields, zap.Stringer("namespace", stats.namespace))
+	}
+	if stats.query != nil {
+		fields = append(fields, zap.Stringer("query", stats.query))
+	}
+	fields = append(fields,
+		zap.Time("start", stats.start.ToTime()),
+		zap.Time("end", stats.end.ToTime()),
+		zap.Duration("duration", stats.duration),
+		zap.Duration("indexDuration", stats.indexDuration),
+		zap.Duration("blocksDuration", blocksDuration),
+		zap.Int("shardsTouched", shardsTouched),
+		zap.Int("seriesMatched", stats.seriesMatched),
+		zap.Int("docsMatched", stats.docsMatched),
+		zap.Int("bytesRead", stats.bytesRead),
+	)
+	if stats.clientIdentity != "" {
+		fields = append(fields, zap.String("clientIdentity", stats.clientIdentity))
+	}
+	if stats.err != nil {
+		fields = append(fields, zap.Error(stats.err))
+	}
+	l.logger.Info("slow query", fields...)
+	return true
+}
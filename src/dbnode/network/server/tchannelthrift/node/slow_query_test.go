@@ -0,0 +1,181 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlowQueryLoggerDisabled(t *testing.T) {
+	require.Nil(t, newSlowQueryLogger(tchannelthrift.SlowQueryLogOptions{
+		DurationThreshold: time.Second,
+	}, instrument.NewOptions()))
+}
+
+func TestSlowQueryLoggerObserve(t *testing.T) {
+	core, recorded := observer.New(zapcore.InfoLevel)
+	scope := tally.NewTestScope("", nil)
+	iOpts := instrument.NewOptions().
+		SetLogger(zap.New(core)).
+		SetMetricsScope(scope)
+
+	l := newSlowQueryLogger(tchannelthrift.SlowQueryLogOptions{
+		Enabled:            true,
+		DurationThreshold:  time.Second,
+		DocsThreshold:      100,
+		BytesReadThreshold: 1024,
+	}, iOpts)
+	require.NotNil(t, l)
+
+	var shardsResolved int
+	stats := slowQueryStats{
+		endpoint:      tchannelthrift.FetchTagged,
+		namespace:     ident.StringID("testns"),
+		query:         index.Query{Query: idx.NewTermQuery([]byte("foo"), []byte("bar"))},
+		indexDuration: 100 * time.Millisecond,
+		duration:      200 * time.Millisecond,
+		seriesMatched: 5,
+		docsMatched:   10,
+		bytesRead:     512,
+		shardsFn: func() int {
+			shardsResolved++
+			return 3
+		},
+	}
+
+	// Below all thresholds.
+	require.False(t, l.Observe(stats))
+	require.Equal(t, 0, recorded.Len())
+	require.Equal(t, 0, shardsResolved)
+
+	// Exceeds the duration and docs thresholds.
+	stats.duration = 2 * time.Second
+	stats.docsMatched = 200
+	require.True(t, l.Observe(stats))
+	require.Equal(t, 1, shardsResolved)
+
+	entries := recorded.All()
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, "slow query", entries[0].Message)
+	fields := entries[0].ContextMap()
+	require.Equal(t, "FetchTagged", fields["endpoint"])
+	require.Equal(t, "testns", fields["namespace"])
+	require.Equal(t, "term(foo,bar)", fields["query"])
+	require.Equal(t, int64(3), fields["shardsTouched"])
+	require.Equal(t, int64(200), fields["docsMatched"])
+	require.Equal(t, int64(512), fields["bytesRead"])
+	require.Equal(t, 1900*time.Millisecond, fields["blocksDuration"])
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1),
+		counters["slow-query-log.slow-query+threshold=duration"].Value())
+	require.Equal(t, int64(1),
+		counters["slow-query-log.slow-query+threshold=docs"].Value())
+	require.Equal(t, int64(0),
+		counters["slow-query-log.slow-query+threshold=bytes-read"].Value())
+}
+
+func TestServiceSlowQueryLogReadEndpoints(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	iOpts := instrument.NewOptions().SetLogger(zap.New(core))
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+	service.slowQueryLog = newSlowQueryLogger(tchannelthrift.SlowQueryLogOptions{
+		Enabled:           true,
+		DurationThreshold: time.Second,
+	}, iOpts)
+	// Every query takes a second.
+	now := time.Now()
+	service.nowFn = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	mockDB.EXPECT().ReadEncoded(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("read error")).Times(2)
+	_, err := service.FetchBatchRaw(tctx, &rpc.FetchBatchRawRequest{
+		NameSpace:     []byte("testns"),
+		RangeTimeType: rpc.TimeType_UNIX_SECONDS,
+		Ids:           [][]byte{[]byte("foo"), []byte("bar")},
+	})
+	require.NoError(t, err)
+
+	q, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	data, err := idx.Marshal(q)
+	require.NoError(t, err)
+	results := index.NewAggregateResults(ident.StringID("testns"),
+		index.AggregateResultsOptions{}, testIndexOptions)
+	mockDB.EXPECT().AggregateQuery(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(index.AggregateQueryResult{Results: results}, nil)
+	_, err = service.AggregateRaw(tctx, &rpc.AggregateQueryRawRequest{
+		NameSpace:  []byte("testns"),
+		Query:      data,
+		RangeType:  rpc.TimeType_UNIX_SECONDS,
+		RangeStart: 0,
+		RangeEnd:   1,
+	})
+	require.NoError(t, err)
+
+	entries := recorded.All()
+	require.Equal(t, 2, len(entries))
+
+	fields := entries[0].ContextMap()
+	require.Equal(t, "FetchBatchRaw", fields["endpoint"])
+	require.Equal(t, "testns", fields["namespace"])
+	require.Equal(t, int64(2), fields["seriesMatched"])
+	require.NotContains(t, fields, "query")
+
+	fields = entries[1].ContextMap()
+	require.Equal(t, "AggregateRaw", fields["endpoint"])
+	require.Equal(t, "testns", fields["namespace"])
+	require.Equal(t, "regexp(foo,b.*)", fields["query"])
+	require.Equal(t, fields["duration"], fields["indexDuration"])
+}
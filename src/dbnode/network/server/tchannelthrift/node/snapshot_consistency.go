@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/pborman/uuid"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+var (
+	errSnapshotConsistencyPointNoNamespace  = errors.New("namespace is required")
+	errSnapshotConsistencyPointNoSnapshotID = errors.New("snapshot ID is required")
+)
+
+// SnapshotConsistencyPointRequest is a request to force a commit log rotation
+// and snapshot and return the consistent volumes of a namespace.
+type SnapshotConsistencyPointRequest struct {
+	NameSpace string `json:"nameSpace"`
+}
+
+// SnapshotConsistencyPointResult describes the volumes of a namespace that make
+// up a consistent cut along with the commit log they are consistent with.
+type SnapshotConsistencyPointResult struct {
+	SnapshotID     string                      `json:"snapshotID"`
+	SnapshotTime   int64                       `json:"snapshotTime"`
+	CommitLogPath  string                      `json:"commitLogPath"`
+	CommitLogIndex int64                       `json:"commitLogIndex"`
+	Volumes        []SnapshotConsistencyVolume `json:"volumes"`
+}
+
+// ReleaseSnapshotConsistencyPointRequest is a request to release the volumes
+// pinned by a consistency point.
+type ReleaseSnapshotConsistencyPointRequest struct {
+	SnapshotID string `json:"snapshotID"`
+}
+
+// ReleaseSnapshotConsistencyPointResult is the result of releasing a
+// consistency point.
+type ReleaseSnapshotConsistencyPointResult struct{}
+
+// SnapshotConsistencyVolume identifies a fileset volume of a consistency point.
+type SnapshotConsistencyVolume struct {
+	FileSetType string `json:"fileSetType"`
+	Shard       uint32 `json:"shard"`
+	BlockStart  int64  `json:"blockStart"`
+	VolumeIndex int    `json:"volumeIndex"`
+}
+
+// SnapshotConsistencyPoint forces a commit log rotation and snapshot and
+// returns the volumes of the requested namespace that backup tooling can
+// copy as a consistent cut. It is served by the node HTTP JSON interface.
+func (s *service) SnapshotConsistencyPoint(
+	tctx thrift.Context,
+	req *SnapshotConsistencyPointRequest,
+) (*SnapshotConsistencyPointResult, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.NameSpace == "" {
+		return nil, tterrors.NewBadRequestError(errSnapshotConsistencyPointNoNamespace)
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	point, err := db.SnapshotConsistencyPoint(s.newID(ctx, []byte(req.NameSpace)))
+	if err != nil {
+		s.metrics.snapshotConsistency.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := &SnapshotConsistencyPointResult{
+		SnapshotID:     point.SnapshotID.String(),
+		SnapshotTime:   int64(point.SnapshotTime),
+		CommitLogPath:  point.CommitLog.FilePath,
+		CommitLogIndex: point.CommitLog.Index,
+		Volumes:        make([]SnapshotConsistencyVolume, 0, len(point.Volumes)),
+	}
+	for _, v := range point.Volumes {
+		res.Volumes = append(res.Volumes, SnapshotConsistencyVolume{
+			FileSetType: v.FileSetType.String(),
+			Shard:       v.Shard,
+			BlockStart:  int64(v.BlockStart),
+			VolumeIndex: v.VolumeIndex,
+		})
+	}
+
+	s.metrics.snapshotConsistency.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+// ReleaseSnapshotConsistencyPoint releases the volumes pinned by a consistency
+// point once backup tooling has copied them. It is served by the node HTTP
+// JSON interface.
+func (s *service) ReleaseSnapshotConsistencyPoint(
+	tctx thrift.Context,
+	req *ReleaseSnapshotConsistencyPointRequest,
+) (*ReleaseSnapshotConsistencyPointResult, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SnapshotID == "" {
+		return nil, tterrors.NewBadRequestError(errSnapshotConsistencyPointNoSnapshotID)
+	}
+	snapshotID := uuid.Parse(req.SnapshotID)
+	if snapshotID == nil {
+		return nil, tterrors.NewBadRequestError(
+			fmt.Errorf("invalid snapshot ID: %s", req.SnapshotID))
+	}
+
+	callStart := s.nowFn()
+	if err := db.ReleaseSnapshotConsistencyPoint(snapshotID); err != nil {
+		s.metrics.releaseConsistency.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.releaseConsistency.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return &ReleaseSnapshotConsistencyPointResult{}, nil
+}
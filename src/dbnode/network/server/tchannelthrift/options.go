@@ -47,6 +47,7 @@ type options struct {
 	queryLimits                 limits.QueryLimits
 	permitsOptions              permits.Options
 	seriesBlocksPerBatch        int
+	slowQueryLogOptions         SlowQueryLogOptions
 }
 
 // NewOptions creates new options.
@@ -216,3 +217,13 @@ func (o *options) SetFetchTaggedSeriesBlocksPerBatch(value int) Options {
 func (o *options) FetchTaggedSeriesBlocksPerBatch() int {
 	return o.seriesBlocksPerBatch
 }
+
+func (o *options) SlowQueryLogOptions() SlowQueryLogOptions {
+	return o.slowQueryLogOptions
+}
+
+func (o *options) SetSlowQueryLogOptions(value SlowQueryLogOptions) Options {
+	opts := *o
+	opts.slowQueryLogOptions = value
+	return &opts
+}
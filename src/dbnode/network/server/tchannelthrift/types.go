@@ -23,6 +23,8 @@
 package tchannelthrift
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
 	"github.com/m3db/m3/src/dbnode/topology"
@@ -39,6 +41,10 @@ type Key string
 // EndpointContextKey is the key for setting and retrieving the endpoint from context.
 const EndpointContextKey Key = "endpoint"
 
+// ClientIdentityContextKey is the key for setting and retrieving the TLS
+// client certificate identity of the caller from context.
+const ClientIdentityContextKey Key = "clientIdentity"
+
 // Endpoint is a type representing an API endpoint
 type Endpoint int
 
@@ -57,6 +63,10 @@ const (
 	FetchTagged
 	// Query represents the Query endpoint.
 	Query
+	// Aggregate represents the Aggregate endpoint.
+	Aggregate
+	// AggregateTiles represents the AggregateTiles endpoint.
+	AggregateTiles
 )
 
 // Options controls server behavior
@@ -140,4 +150,23 @@ type Options interface {
 	// SetFetchTaggedSeriesBlocksPerBatch sets the series blocks allowed to be read
 	// per permit acquired.
 	SetFetchTaggedSeriesBlocksPerBatch(value int) Options
+
+	// SlowQueryLogOptions returns the slow query log options.
+	SlowQueryLogOptions() SlowQueryLogOptions
+
+	// SetSlowQueryLogOptions sets the slow query log options.
+	SetSlowQueryLogOptions(value SlowQueryLogOptions) Options
+}
+
+// SlowQueryLogOptions configures logging of queries that exceed any of the
+// configured thresholds, a zero threshold is ignored.
+type SlowQueryLogOptions struct {
+	// Enabled enables the slow query log.
+	Enabled bool
+	// DurationThreshold is the query duration above which a query is logged.
+	DurationThreshold time.Duration
+	// DocsThreshold is the number of index docs matched above which a query is logged.
+	DocsThreshold int
+	// BytesReadThreshold is the number of series bytes read above which a query is logged.
+	BytesReadThreshold int
 }
@@ -47,9 +47,19 @@ var (
 
 	errCommitLogClosed = errors.New("commit log is closed")
 
+	// The fractions of the queue capacity at which the backpressure state
+	// becomes elevated and saturated.
+	backpressureElevatedFactor  = 0.5
+	backpressureSaturatedFactor = 0.9
+
 	zeroFile = persist.CommitLogFile{}
 )
 
+// lagSampleInterval is how many writes are enqueued for each write whose lag
+// in the queue is measured, which keeps the clock reads and timer records off
+// the path of most writes through the single writer goroutine.
+const lagSampleInterval = 64
+
 type newCommitLogWriterFn func(
 	flushFn flushFn,
 	opts Options,
@@ -105,6 +115,8 @@ type commitLog struct {
 	metrics commitLogMetrics
 
 	numWritesInQueue int64
+	numEnqueued      uint64
+	lagNanos         int64
 }
 
 // Use the helper methods when interacting with this struct, the mutex
@@ -176,6 +188,11 @@ type commitLogMetrics struct {
 	numWritesInQueue tally.Gauge
 	queueLength      tally.Gauge
 	queueCapacity    tally.Gauge
+	backpressure     tally.Gauge
+	lag              tally.Timer
+	enqueueBlocked   tally.Counter
+	enqueueBlockTime tally.Timer
+	queueFull        tally.Counter
 	success          tally.Counter
 	errors           tally.Counter
 	openErrors       tally.Counter
@@ -243,6 +260,8 @@ type commitLogWrite struct {
 	eventType  eventType
 	write      writeOrWriteBatch
 	callbackFn callbackFn
+	// enqueuedAt is only set for the writes whose lag is sampled.
+	enqueuedAt time.Time
 }
 
 // NewCommitLog creates a new commit log
@@ -274,6 +293,11 @@ func NewCommitLog(opts Options) (CommitLog, error) {
 			numWritesInQueue: scope.Gauge("writes.queued"),
 			queueLength:      scope.Gauge("writes.queue-length"),
 			queueCapacity:    scope.Gauge("writes.queue-capacity"),
+			backpressure:     scope.Gauge("writes.backpressure-state"),
+			lag:              scope.Timer("writes.lag"),
+			enqueueBlocked:   scope.Counter("writes.enqueue-blocked"),
+			enqueueBlockTime: scope.Timer("writes.enqueue-block-time"),
+			queueFull:        scope.Counter("writes.queue-full"),
 			success:          scope.Counter("writes.success"),
 			errors:           scope.Counter("writes.errors"),
 			openErrors:       scope.Counter("writes.open-errors"),
@@ -405,6 +429,30 @@ func (l *commitLog) QueueLength() int64 {
 	return atomic.LoadInt64(&l.numWritesInQueue)
 }
 
+func (l *commitLog) QueueStatus() QueueStatus {
+	numWrites := atomic.LoadInt64(&l.numWritesInQueue)
+	return QueueStatus{
+		NumWrites:    numWrites,
+		Capacity:     l.maxQueueSize,
+		Lag:          time.Duration(atomic.LoadInt64(&l.lagNanos)),
+		Backpressure: backpressureState(numWrites, l.maxQueueSize),
+	}
+}
+
+// backpressureState returns the backpressure state of a queue holding the
+// given number of writes.
+func backpressureState(numWrites, capacity int64) BackpressureState {
+	usage := float64(numWrites)
+	switch {
+	case usage >= backpressureSaturatedFactor*float64(capacity):
+		return BackpressureSaturated
+	case usage >= backpressureElevatedFactor*float64(capacity):
+		return BackpressureElevated
+	default:
+		return BackpressureNone
+	}
+}
+
 func (l *commitLog) flushEvery(interval time.Duration) {
 	// Periodically flush the underlying commit log writer to cover
 	// the case when writes stall for a considerable time
@@ -417,6 +465,7 @@ func (l *commitLog) flushEvery(interval time.Duration) {
 		// item in the queue could (potentially) be a batch of many writes.
 		l.metrics.queueLength.Update(float64(len(l.writes)))
 		l.metrics.queueCapacity.Update(float64(cap(l.writes)))
+		l.metrics.backpressure.Update(float64(l.QueueStatus().Backpressure))
 
 		sleepFor := interval
 
@@ -500,6 +549,12 @@ func (l *commitLog) write() {
 			continue
 		}
 
+		if !write.enqueuedAt.IsZero() {
+			lag := l.nowFn().Sub(write.enqueuedAt)
+			atomic.StoreInt64(&l.lagNanos, int64(lag))
+			l.metrics.lag.Record(lag)
+		}
+
 		var (
 			numWritesSuccess int64
 			numDequeued      int
@@ -785,14 +840,15 @@ func (l *commitLog) writeWait(
 			write.writeBatch.Finalize()
 		}
 
+		l.metrics.queueFull.Inc(1)
 		return ErrCommitLogQueueFull
 	}
 
 	// Otherwise submit the write.
-	l.writes <- commitLogWrite{
+	l.enqueue(commitLogWrite{
 		write:      write,
 		callbackFn: completion,
-	}
+	})
 
 	l.closedState.RUnlock()
 
@@ -830,19 +886,38 @@ func (l *commitLog) writeBehind(
 			write.writeBatch.Finalize()
 		}
 
+		l.metrics.queueFull.Inc(1)
 		return ErrCommitLogQueueFull
 	}
 
 	// Otherwise submit the write.
-	l.writes <- commitLogWrite{
+	l.enqueue(commitLogWrite{
 		write: write,
-	}
+	})
 
 	l.closedState.RUnlock()
 
 	return nil
 }
 
+// enqueue submits a write to the writer goroutine, blocking while the queue
+// channel is full. The caller must hold the closedState read lock.
+func (l *commitLog) enqueue(write commitLogWrite) {
+	if atomic.AddUint64(&l.numEnqueued, 1)%lagSampleInterval == 0 {
+		write.enqueuedAt = l.nowFn()
+	}
+	select {
+	case l.writes <- write:
+		return
+	default:
+	}
+
+	blockedAt := l.nowFn()
+	l.writes <- write
+	l.metrics.enqueueBlocked.Inc(1)
+	l.metrics.enqueueBlockTime.Record(l.nowFn().Sub(blockedAt))
+}
+
 func (l *commitLog) Close() error {
 	l.closedState.Lock()
 	if l.closedState.closed {
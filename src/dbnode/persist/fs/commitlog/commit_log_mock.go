@@ -122,6 +122,20 @@ func (mr *MockCommitLogMockRecorder) QueueLength() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueLength", reflect.TypeOf((*MockCommitLog)(nil).QueueLength))
 }
 
+// QueueStatus mocks base method.
+func (m *MockCommitLog) QueueStatus() QueueStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueStatus")
+	ret0, _ := ret[0].(QueueStatus)
+	return ret0
+}
+
+// QueueStatus indicates an expected call of QueueStatus.
+func (mr *MockCommitLogMockRecorder) QueueStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueStatus", reflect.TypeOf((*MockCommitLog)(nil).QueueStatus))
+}
+
 // RotateLogs mocks base method.
 func (m *MockCommitLog) RotateLogs() (persist.CommitLogFile, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +79,83 @@ var testOpts = NewOptions().
 	SetFlushInterval(100 * time.Millisecond).
 	SetBacklogQueueSize(1024)
 
+func TestCommitLogQueueStatus(t *testing.T) {
+	// Set backlog of size ten and don't automatically flush.
+	backlogQueueSize := 10
+	flushInterval := time.Duration(0)
+	opts, scope := newTestOptions(t, overrides{
+		backlogQueueSize: &backlogQueueSize,
+		flushInterval:    &flushInterval,
+		strategy:         StrategyWriteBehind,
+	})
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+	defer commitLog.Close()
+
+	var (
+		series = testSeries(t, opts, 0, "foo.bar", testTags1, 127)
+		dp     = ts.Datapoint{TimestampNanos: xtime.Now(), Value: 123.456}
+		unit   = xtime.Millisecond
+		ctx    = context.NewBackground()
+	)
+	defer ctx.Close()
+
+	for i := 0; ; i++ {
+		status := commitLog.QueueStatus()
+		require.Equal(t, int64(i), status.NumWrites)
+		require.Equal(t, int64(backlogQueueSize), status.Capacity)
+		switch {
+		case i < 5:
+			require.Equal(t, BackpressureNone, status.Backpressure)
+		case i < 9:
+			require.Equal(t, BackpressureElevated, status.Backpressure)
+		default:
+			require.Equal(t, BackpressureSaturated, status.Backpressure)
+		}
+
+		if err := commitLog.Write(ctx, series, dp, unit, nil); err != nil {
+			require.Equal(t, ErrCommitLogQueueFull, err)
+			break
+		}
+
+		// Increment timestamp and value for next write.
+		dp.TimestampNanos = dp.TimestampNanos.Add(time.Second)
+		dp.Value += 1.0
+	}
+
+	queueFull, ok := scope.Snapshot().Counters()["commitlog.writes.queue-full+"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), queueFull.Value())
+}
+
+func TestCommitLogSamplesLag(t *testing.T) {
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteBehind,
+	})
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+
+	var (
+		series = testSeries(t, opts, 0, "foo.bar", testTags1, 127)
+		dp     = ts.Datapoint{TimestampNanos: xtime.Now(), Value: 123.456}
+		ctx    = context.NewBackground()
+	)
+	defer ctx.Close()
+
+	for i := 0; i < 2*lagSampleInterval; i++ {
+		require.NoError(t, commitLog.Write(ctx, series, dp, xtime.Millisecond, nil))
+		dp.TimestampNanos = dp.TimestampNanos.Add(time.Second)
+	}
+	require.NoError(t, commitLog.Close())
+
+	// The lag is only measured for one in every sampled interval of writes.
+	lag, ok := scope.Snapshot().Timers()["commitlog.writes.lag+"]
+	require.True(t, ok)
+	require.Equal(t, 2, len(lag.Values()))
+}
+
 func newTestOptions(
 	t *testing.T,
 	overrides overrides,
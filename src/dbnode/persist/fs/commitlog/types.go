@@ -50,6 +50,50 @@ const (
 	StrategyWriteBehind
 )
 
+// BackpressureState describes how close the commit log queue is to rejecting
+// writes.
+type BackpressureState int
+
+const (
+	// BackpressureNone means the commit log queue has plenty of room.
+	BackpressureNone BackpressureState = iota
+
+	// BackpressureElevated means the commit log queue is filling up and
+	// writers should slow down.
+	BackpressureElevated
+
+	// BackpressureSaturated means the commit log queue is close to or at its
+	// capacity, writes are about to be or are being rejected.
+	BackpressureSaturated
+)
+
+// String returns the string representation of the backpressure state.
+func (s BackpressureState) String() string {
+	switch s {
+	case BackpressureNone:
+		return "none"
+	case BackpressureElevated:
+		return "elevated"
+	case BackpressureSaturated:
+		return "saturated"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueStatus is the status of the commit log queue.
+type QueueStatus struct {
+	// NumWrites is the number of writes currently queued.
+	NumWrites int64
+	// Capacity is the max number of writes that can be queued.
+	Capacity int64
+	// Lag is how long the most recently dequeued write sampled waited in the
+	// queue, one in every 64 writes being sampled.
+	Lag time.Duration
+	// Backpressure is the backpressure state derived from the queue usage.
+	Backpressure BackpressureState
+}
+
 // CommitLog provides a synchronized commit log
 type CommitLog interface {
 	// Open the commit log
@@ -83,6 +127,10 @@ type CommitLog interface {
 	// QueueLength returns the number of writes that are currently in the commitlog
 	// queue.
 	QueueLength() int64
+
+	// QueueStatus returns the status of the commitlog queue, which writers can
+	// use to slow down before writes are rejected.
+	QueueStatus() QueueStatus
 }
 
 // LogEntry is a commit log entry being read.
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	errDiskUsageHighWatermarkInvalid = errors.New(
+		"disk usage eviction high watermark must be between 0 and 1")
+	errDiskUsageLowWatermarkInvalid = errors.New(
+		"disk usage eviction low watermark must be positive and not greater than the high watermark")
+	errDiskUsageRetentionFloorInvalid = errors.New(
+		"disk usage eviction retention floor must not be negative")
+)
+
+// DiskUsageEvictionOptions are the options for evicting the oldest data
+// filesets when the disk the filesets reside on is close to full, trading
+// retention for keeping the node up.
+type DiskUsageEvictionOptions struct {
+	// HighWatermark is the fraction of the disk used above which data filesets
+	// are evicted, eviction is disabled when not set.
+	HighWatermark float64
+
+	// LowWatermark is the fraction of the disk used that eviction stops at once
+	// reached.
+	LowWatermark float64
+
+	// RetentionFloor is how much of the most recent data is never evicted,
+	// regardless of disk usage.
+	RetentionFloor time.Duration
+}
+
+// Enabled returns whether disk usage based eviction is enabled.
+func (o DiskUsageEvictionOptions) Enabled() bool {
+	return o.HighWatermark > 0
+}
+
+// Validate validates the disk usage eviction options.
+func (o DiskUsageEvictionOptions) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+	if o.HighWatermark >= 1 {
+		return errDiskUsageHighWatermarkInvalid
+	}
+	if o.LowWatermark <= 0 || o.LowWatermark > o.HighWatermark {
+		return errDiskUsageLowWatermarkInvalid
+	}
+	if o.RetentionFloor < 0 {
+		return errDiskUsageRetentionFloorInvalid
+	}
+	return nil
+}
+
+// DiskUsageStats is the usage of a disk, space reserved for the superuser is
+// not counted as available the same as df.
+type DiskUsageStats struct {
+	UsedBytes  int64
+	TotalBytes int64
+}
+
+// Usage returns the fraction of the disk that is used.
+func (s DiskUsageStats) Usage() float64 {
+	if s.TotalBytes <= 0 {
+		return 0
+	}
+	return float64(s.UsedBytes) / float64(s.TotalBytes)
+}
+
+// DiskUsage returns the usage of the disk the path resides on.
+func DiskUsage(path string) (DiskUsageStats, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return DiskUsageStats{}, err
+	}
+
+	used := stat.Blocks - stat.Bfree
+	total := used + stat.Bavail
+	return DiskUsageStats{
+		UsedBytes:  int64(used) * int64(stat.Bsize),
+		TotalBytes: int64(total) * int64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsageEvictionOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DiskUsageEvictionOptions
+		err  error
+	}{
+		{
+			name: "disabled",
+			opts: DiskUsageEvictionOptions{},
+		},
+		{
+			name: "valid",
+			opts: DiskUsageEvictionOptions{
+				HighWatermark:  0.9,
+				LowWatermark:   0.8,
+				RetentionFloor: 24 * time.Hour,
+			},
+		},
+		{
+			name: "high watermark too large",
+			opts: DiskUsageEvictionOptions{HighWatermark: 1, LowWatermark: 0.8},
+			err:  errDiskUsageHighWatermarkInvalid,
+		},
+		{
+			name: "low watermark not set",
+			opts: DiskUsageEvictionOptions{HighWatermark: 0.9},
+			err:  errDiskUsageLowWatermarkInvalid,
+		},
+		{
+			name: "low watermark above high watermark",
+			opts: DiskUsageEvictionOptions{HighWatermark: 0.8, LowWatermark: 0.9},
+			err:  errDiskUsageLowWatermarkInvalid,
+		},
+		{
+			name: "negative retention floor",
+			opts: DiskUsageEvictionOptions{
+				HighWatermark:  0.9,
+				LowWatermark:   0.8,
+				RetentionFloor: -time.Hour,
+			},
+			err: errDiskUsageRetentionFloorInvalid,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.err, test.opts.Validate())
+		})
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	stats, err := DiskUsage(dir)
+	require.NoError(t, err)
+	require.True(t, stats.UsedBytes <= stats.TotalBytes)
+	usage := stats.Usage()
+	require.True(t, usage >= 0 && usage <= 1)
+	require.Equal(t, float64(0), DiskUsageStats{}.Usage())
+
+	_, err = DiskUsage("/does/not/exist")
+	require.Error(t, err)
+}
@@ -1131,6 +1131,18 @@ func (mr *MockDataFileSetSeekerManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDataFileSetSeekerManager)(nil).Close))
 }
 
+// EvictBefore mocks base method.
+func (m *MockDataFileSetSeekerManager) EvictBefore(arg0 uint32, arg1 time.UnixNano) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictBefore", arg0, arg1)
+}
+
+// EvictBefore indicates an expected call of EvictBefore.
+func (mr *MockDataFileSetSeekerManagerMockRecorder) EvictBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBefore", reflect.TypeOf((*MockDataFileSetSeekerManager)(nil).EvictBefore), arg0, arg1)
+}
+
 // Open mocks base method.
 func (m *MockDataFileSetSeekerManager) Open(arg0 namespace.Metadata, arg1 sharding.ShardSet) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,409 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// BlockSizeTask is implemented by tasks that re-chunk the data filesets of a single
+// shard that were written with a block size other than the namespace's current block size.
+type BlockSizeTask interface {
+	// Run re-chunks the filesets of the shard and returns the info files of the shard
+	// as they exist on disk once the migration has completed.
+	Run() ([]fs.ReadInfoFileResult, error)
+}
+
+// NewBlockSizeTaskFn is a function that can create a new block size migration task.
+type NewBlockSizeTaskFn func(opts TaskOptions) (BlockSizeTask, error)
+
+// toBlockSizeTask is an object responsible for re-chunking the filesets of a shard to
+// the current block size of the namespace.
+type toBlockSizeTask struct {
+	opts TaskOptions
+}
+
+// BlockSizeMigrationTask returns true if any of the filesets of a shard were written with a
+// block size other than the current block size of the namespace. If true, also returns a
+// function that can be used to create a new block size migration task.
+func BlockSizeMigrationTask(md namespace.Metadata, infoFiles []fs.ReadInfoFileResult) (NewBlockSizeTaskFn, bool) {
+	blockSize := int64(md.Options().RetentionOptions().BlockSize())
+	for _, info := range infoFiles {
+		if info.Err != nil && info.Err.Error() != nil {
+			continue
+		}
+		if info.Info.BlockSize != blockSize {
+			return NewToBlockSizeTask, true
+		}
+	}
+	return nil, false
+}
+
+// NewToBlockSizeTask creates a task for re-chunking the filesets of a shard to the
+// current block size of the namespace.
+func NewToBlockSizeTask(opts TaskOptions) (BlockSizeTask, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &toBlockSizeTask{
+		opts: opts,
+	}, nil
+}
+
+// blockSizeFileSetKey identifies every volume of a fileset written for a block start
+// with a given block size.
+type blockSizeFileSetKey struct {
+	blockStart xtime.UnixNano
+	blockSize  time.Duration
+}
+
+type blockSizeFileSet struct {
+	blockStart xtime.UnixNano
+	blockSize  time.Duration
+	volumes    []int
+}
+
+func (f *blockSizeFileSet) latestVolume() int {
+	latest := f.volumes[0]
+	for _, volume := range f.volumes[1:] {
+		if volume > latest {
+			latest = volume
+		}
+	}
+	return latest
+}
+
+func (f *blockSizeFileSet) overlaps(start, end xtime.UnixNano) bool {
+	return f.blockStart.Before(end) && start.Before(f.blockStart.Add(f.blockSize))
+}
+
+type blockSizeDatapoint struct {
+	dp         ts.Datapoint
+	unit       xtime.Unit
+	annotation ts.Annotation
+}
+
+type blockSizeSeries struct {
+	id         ident.ID
+	tags       ident.TagIterator
+	datapoints []blockSizeDatapoint
+}
+
+// Run re-chunks every fileset of the shard written with a stale block size. For each
+// block of the current block size that overlaps a stale fileset, the data of all
+// overlapping stale filesets plus any fileset already written with the current block
+// size for that block is read, and a new volume is written for the block. Datapoints
+// at the same timestamp are resolved in favor of the fileset written with the current
+// block size. The stale filesets are only removed once every block has been written,
+// so a failed migration leaves the existing data in place and can be retried.
+func (t *toBlockSizeTask) Run() ([]fs.ReadInfoFileResult, error) {
+	var (
+		fsOpts         = t.opts.FilesystemOptions()
+		nsMd           = t.opts.NamespaceMetadata()
+		shard          = t.opts.Shard()
+		infoFiles      = t.opts.InfoFileResults()
+		persistManager = t.opts.PersistManager()
+		blockSize      = nsMd.Options().RetentionOptions().BlockSize()
+
+		filesets      = make(map[blockSizeFileSetKey]*blockSizeFileSet)
+		maxVolumes    = make(map[xtime.UnixNano]int)
+		staleFileSets []*blockSizeFileSet
+	)
+	for _, info := range infoFiles {
+		if info.Err != nil && info.Err.Error() != nil {
+			continue
+		}
+		key := blockSizeFileSetKey{
+			blockStart: xtime.UnixNano(info.Info.BlockStart),
+			blockSize:  time.Duration(info.Info.BlockSize),
+		}
+		fileset, ok := filesets[key]
+		if !ok {
+			fileset = &blockSizeFileSet{
+				blockStart: key.blockStart,
+				blockSize:  key.blockSize,
+			}
+			filesets[key] = fileset
+			if key.blockSize != blockSize {
+				staleFileSets = append(staleFileSets, fileset)
+			}
+		}
+		fileset.volumes = append(fileset.volumes, info.Info.VolumeIndex)
+		if volume, ok := maxVolumes[key.blockStart]; !ok || info.Info.VolumeIndex > volume {
+			maxVolumes[key.blockStart] = info.Info.VolumeIndex
+		}
+	}
+	if len(staleFileSets) == 0 {
+		return infoFiles, nil
+	}
+
+	sort.Slice(staleFileSets, func(i, j int) bool {
+		return staleFileSets[i].blockStart.Before(staleFileSets[j].blockStart)
+	})
+
+	var (
+		blockStarts     []xtime.UnixNano
+		seenBlockStarts = make(map[xtime.UnixNano]struct{})
+	)
+	for _, fileset := range staleFileSets {
+		end := fileset.blockStart.Add(fileset.blockSize)
+		for blockStart := fileset.blockStart.Truncate(blockSize); blockStart.Before(end); blockStart = blockStart.Add(blockSize) {
+			if _, ok := seenBlockStarts[blockStart]; ok {
+				continue
+			}
+			seenBlockStarts[blockStart] = struct{}{}
+			blockStarts = append(blockStarts, blockStart)
+		}
+	}
+	sort.Slice(blockStarts, func(i, j int) bool {
+		return blockStarts[i].Before(blockStarts[j])
+	})
+
+	flushPersist, err := persistManager.StartFlushPersist()
+	if err != nil {
+		return infoFiles, err
+	}
+
+	var merged []*blockSizeFileSet
+	for _, blockStart := range blockStarts {
+		blockEnd := blockStart.Add(blockSize)
+
+		// Order the sources so that the fileset already written with the current
+		// block size, if any, is read last and takes precedence.
+		var sources []*blockSizeFileSet
+		for _, fileset := range staleFileSets {
+			if fileset.overlaps(blockStart, blockEnd) {
+				sources = append(sources, fileset)
+			}
+		}
+		current, ok := filesets[blockSizeFileSetKey{blockStart: blockStart, blockSize: blockSize}]
+		if ok {
+			sources = append(sources, current)
+			merged = append(merged, current)
+		}
+
+		volume := 0
+		if maxVolume, ok := maxVolumes[blockStart]; ok {
+			volume = maxVolume + 1
+		}
+
+		if err := t.rechunk(flushPersist, blockStart, blockEnd, volume, sources); err != nil {
+			// Release the persist manager so it can be reused by subsequent tasks.
+			_ = flushPersist.DoneFlush()
+			return infoFiles, err
+		}
+	}
+
+	if err := flushPersist.DoneFlush(); err != nil {
+		return infoFiles, err
+	}
+
+	for _, fileset := range append(staleFileSets, merged...) {
+		for _, volume := range fileset.volumes {
+			if err := fs.DeleteFileSetAt(fsOpts.FilePathPrefix(), nsMd.ID(), shard,
+				fileset.blockStart, volume); err != nil {
+				return infoFiles, err
+			}
+		}
+	}
+
+	return fs.ReadInfoFiles(fsOpts.FilePathPrefix(), nsMd.ID(), shard,
+		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions(), persist.FileSetFlushType), nil
+}
+
+// rechunk reads the datapoints within [blockStart, blockEnd) from the latest volume of each
+// source and writes them to a new volume for the block.
+func (t *toBlockSizeTask) rechunk(
+	flushPersist persist.FlushPreparer,
+	blockStart xtime.UnixNano,
+	blockEnd xtime.UnixNano,
+	volume int,
+	sources []*blockSizeFileSet,
+) error {
+	var (
+		sOpts       = t.opts.StorageOptions()
+		fsOpts      = t.opts.FilesystemOptions()
+		nsMd        = t.opts.NamespaceMetadata()
+		shard       = t.opts.Shard()
+		nsCtx       = namespace.NewContextFrom(nsMd)
+		seriesByID  = make(map[string]*blockSizeSeries)
+		seriesOrder []*blockSizeSeries
+	)
+	reader, err := fs.NewReader(sOpts.BytesPool(), fsOpts)
+	if err != nil {
+		return err
+	}
+
+	iter := sOpts.ReaderIteratorPool().Get()
+	defer iter.Close()
+
+	for _, source := range sources {
+		if err := reader.Open(fs.DataReaderOpenOptions{
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:   nsMd.ID(),
+				Shard:       shard,
+				BlockStart:  source.blockStart,
+				VolumeIndex: source.latestVolume(),
+			},
+			FileSetType: persist.FileSetFlushType,
+		}); err != nil {
+			return err
+		}
+
+		for {
+			id, tagsIter, data, _, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				reader.Close() // nolint
+				return err
+			}
+
+			series, ok := seriesByID[id.String()]
+			if !ok {
+				series = &blockSizeSeries{id: id, tags: tagsIter}
+			}
+
+			n := len(series.datapoints)
+			data.IncRef()
+			iter.Reset(xio.NewBytesReader64(data.Bytes()), nsCtx.Schema)
+			for iter.Next() {
+				dp, unit, annotation := iter.Current()
+				if dp.TimestampNanos.Before(blockStart) || !dp.TimestampNanos.Before(blockEnd) {
+					continue
+				}
+				var annotationCopy ts.Annotation
+				if len(annotation) > 0 {
+					annotationCopy = append(ts.Annotation(nil), annotation...)
+				}
+				series.datapoints = append(series.datapoints, blockSizeDatapoint{
+					dp:         dp,
+					unit:       unit,
+					annotation: annotationCopy,
+				})
+			}
+			iterErr := iter.Err()
+			data.DecRef()
+			data.Finalize()
+			if iterErr != nil {
+				reader.Close() // nolint
+				return iterErr
+			}
+
+			if ok {
+				// Already tracking this series from a previous source.
+				id.Finalize()
+				tagsIter.Close()
+				continue
+			}
+			if len(series.datapoints) == n {
+				// No data for this series within the block.
+				id.Finalize()
+				tagsIter.Close()
+				continue
+			}
+			seriesByID[id.String()] = series
+			seriesOrder = append(seriesOrder, series)
+		}
+
+		if err := reader.Close(); err != nil {
+			return err
+		}
+	}
+
+	if len(seriesOrder) == 0 {
+		return nil
+	}
+
+	prepared, err := flushPersist.PrepareData(persist.DataPrepareOptions{
+		NamespaceMetadata: nsMd,
+		Shard:             shard,
+		BlockStart:        blockStart,
+		VolumeIndex:       volume,
+		FileSetType:       persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		encoderPool    = sOpts.EncoderPool()
+		blockAllocSize = sOpts.DatabaseBlockOptions().DatabaseBlockAllocSize()
+	)
+	for _, series := range seriesOrder {
+		segment, err := encodeBlockSizeDatapoints(encoderPool, blockStart, blockAllocSize,
+			nsCtx.Schema, series.datapoints)
+		if err != nil {
+			return err
+		}
+
+		metadata := persist.NewMetadataFromIDAndTagIterator(series.id, series.tags,
+			persist.MetadataOptions{
+				FinalizeID:          true,
+				FinalizeTagIterator: true,
+			})
+		if err := prepared.Persist(metadata, segment, segment.CalculateChecksum()); err != nil {
+			return err
+		}
+	}
+
+	return prepared.Close()
+}
+
+// encodeBlockSizeDatapoints sorts the datapoints by timestamp, keeping only the last datapoint
+// read for any given timestamp, and encodes them into a single segment.
+func encodeBlockSizeDatapoints(
+	encoderPool encoding.EncoderPool,
+	blockStart xtime.UnixNano,
+	blockAllocSize int,
+	schema namespace.SchemaDescr,
+	datapoints []blockSizeDatapoint,
+) (ts.Segment, error) {
+	sort.SliceStable(datapoints, func(i, j int) bool {
+		return datapoints[i].dp.TimestampNanos.Before(datapoints[j].dp.TimestampNanos)
+	})
+
+	encoder := encoderPool.Get()
+	encoder.Reset(blockStart, blockAllocSize, schema)
+	for i, dp := range datapoints {
+		if i+1 < len(datapoints) && datapoints[i+1].dp.TimestampNanos.Equal(dp.dp.TimestampNanos) {
+			continue
+		}
+		if err := encoder.Encode(dp.dp, dp.unit, dp.annotation); err != nil {
+			encoder.Close()
+			return ts.Segment{}, err
+		}
+	}
+
+	return encoder.Discard(), nil
+}
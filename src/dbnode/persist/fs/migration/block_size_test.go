@@ -0,0 +1,260 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/persist/schema"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSizeMigrationTask(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("foo"), namespace.NewOptions().
+		SetRetentionOptions(retention.NewOptions().SetBlockSize(time.Hour)))
+	require.NoError(t, err)
+
+	newTaskFn, ok := BlockSizeMigrationTask(md, []fs.ReadInfoFileResult{
+		{Info: blockSizeInfo(0, time.Hour)},
+	})
+	require.False(t, ok)
+	require.Nil(t, newTaskFn)
+
+	newTaskFn, ok = BlockSizeMigrationTask(md, []fs.ReadInfoFileResult{
+		{Info: blockSizeInfo(0, time.Hour)},
+		{Info: blockSizeInfo(0, 2*time.Hour)},
+	})
+	require.True(t, ok)
+	require.NotNil(t, newTaskFn)
+}
+
+func TestToBlockSizeRun(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "")
+	defer os.RemoveAll(dir)
+
+	var (
+		shard      uint32 = 1
+		nsID              = ident.StringID("foo")
+		fsOpts            = fs.NewOptions().SetFilePathPrefix(filePathPrefix)
+		blockStart        = xtime.Now().Truncate(2 * time.Hour).Add(-4 * time.Hour)
+	)
+
+	// Write a fileset with the previous 2h block size along with a fileset written
+	// with the current 1h block size after the namespace was updated.
+	writeBlockSizeData(t, fsOpts, nsID, shard, blockStart, 2*time.Hour, map[string][]ts.Datapoint{
+		"foo": {
+			{TimestampNanos: blockStart.Add(10 * time.Minute), Value: 1},
+			{TimestampNanos: blockStart.Add(70 * time.Minute), Value: 2},
+		},
+		"bar": {
+			{TimestampNanos: blockStart.Add(20 * time.Minute), Value: 3},
+		},
+	})
+	writeBlockSizeData(t, fsOpts, nsID, shard, blockStart.Add(time.Hour), time.Hour, map[string][]ts.Datapoint{
+		"foo": {
+			{TimestampNanos: blockStart.Add(70 * time.Minute), Value: 4},
+		},
+		"baz": {
+			{TimestampNanos: blockStart.Add(80 * time.Minute), Value: 5},
+		},
+	})
+
+	results := fs.ReadInfoFiles(filePathPrefix, nsID, shard,
+		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions(), persist.FileSetFlushType)
+	require.Equal(t, 2, len(results))
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	require.NoError(t, err)
+	icm, err := fs.NewIndexClaimsManager(fsOpts)
+	require.NoError(t, err)
+	defer fs.ResetIndexClaimsManagersUnsafe()
+
+	md, err := namespace.NewMetadata(nsID, namespace.NewOptions().
+		SetRetentionOptions(retention.NewOptions().SetBlockSize(time.Hour)))
+	require.NoError(t, err)
+
+	plCache, err := index.NewPostingsListCache(1, index.PostingsListCacheOptions{
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+	defer plCache.Start()()
+
+	opts := NewTaskOptions().
+		SetPersistManager(pm).
+		SetNamespaceMetadata(md).
+		SetStorageOptions(storage.DefaultTestOptions().
+			SetPersistManager(pm).
+			SetIndexClaimsManager(icm).
+			SetNamespaceInitializer(namespace.NewStaticInitializer([]namespace.Metadata{md})).
+			SetRepairEnabled(false).
+			SetIndexOptions(index.NewOptions().
+				SetPostingsListCache(plCache)).
+			SetBlockLeaseManager(block.NewLeaseManager(nil))).
+		SetShard(shard).
+		SetInfoFileResults(results).
+		SetFilesystemOptions(fsOpts)
+
+	newTaskFn, ok := BlockSizeMigrationTask(md, results)
+	require.True(t, ok)
+	task, err := newTaskFn(opts)
+	require.NoError(t, err)
+
+	updated, err := task.Run()
+	require.NoError(t, err)
+
+	// The task returns exactly what is on disk after the migration.
+	require.Equal(t, fs.ReadInfoFiles(filePathPrefix, nsID, shard,
+		fsOpts.InfoReaderBufferSize(), fsOpts.DecodingOptions(), persist.FileSetFlushType), updated)
+	require.Equal(t, 2, len(updated))
+	for _, result := range updated {
+		require.NoError(t, result.Err.Error())
+		require.Equal(t, int64(time.Hour), result.Info.BlockSize)
+		require.Equal(t, 1, result.Info.VolumeIndex)
+	}
+
+	require.Equal(t, map[string][]ts.Datapoint{
+		"foo": {{TimestampNanos: blockStart.Add(10 * time.Minute), Value: 1}},
+		"bar": {{TimestampNanos: blockStart.Add(20 * time.Minute), Value: 3}},
+	}, readBlockSizeData(t, fsOpts, nsID, shard, blockStart, 1))
+
+	// Data from the fileset written with the current block size takes precedence.
+	require.Equal(t, map[string][]ts.Datapoint{
+		"foo": {{TimestampNanos: blockStart.Add(70 * time.Minute), Value: 4}},
+		"baz": {{TimestampNanos: blockStart.Add(80 * time.Minute), Value: 5}},
+	}, readBlockSizeData(t, fsOpts, nsID, shard, blockStart.Add(time.Hour), 1))
+
+	// Nothing left to migrate.
+	_, ok = BlockSizeMigrationTask(md, updated)
+	require.False(t, ok)
+}
+
+func blockSizeInfo(volume int, blockSize time.Duration) schema.IndexInfo {
+	return schema.IndexInfo{
+		VolumeIndex: volume,
+		BlockSize:   int64(blockSize),
+	}
+}
+
+func writeBlockSizeData(
+	t *testing.T,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	data map[string][]ts.Datapoint,
+) {
+	w, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+
+	err = w.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		BlockSize: blockSize,
+	})
+	require.NoError(t, err)
+
+	for id, datapoints := range data {
+		encoder := m3tsz.NewEncoder(blockStart, nil, m3tsz.DefaultIntOptimizationEnabled,
+			encoding.NewOptions())
+		for _, dp := range datapoints {
+			require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		}
+		segment := encoder.Discard()
+
+		metadata := persist.NewMetadataFromIDAndTags(ident.StringID(id),
+			ident.Tags{}, persist.MetadataOptions{})
+		err = w.WriteAll(metadata, []checked.Bytes{segment.Head, segment.Tail},
+			segment.CalculateChecksum())
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+}
+
+func readBlockSizeData(
+	t *testing.T,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+	volume int,
+) map[string][]ts.Datapoint {
+	r, err := fs.NewReader(nil, fsOpts)
+	require.NoError(t, err)
+
+	err = r.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   nsID,
+			Shard:       shard,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	require.NoError(t, err)
+	defer r.Close()
+
+	result := make(map[string][]ts.Datapoint)
+	for {
+		id, _, data, _, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data.IncRef()
+		iter := m3tsz.NewReaderIterator(xio.NewBytesReader64(data.Bytes()),
+			m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			result[id.String()] = append(result[id.String()], dp)
+		}
+		require.NoError(t, iter.Err())
+		data.DecRef()
+	}
+
+	return result
+}
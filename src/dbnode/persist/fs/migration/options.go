@@ -31,6 +31,7 @@ var defaultMigrationConcurrency = int(math.Ceil(float64(runtime.GOMAXPROCS(0)) /
 
 type options struct {
 	targetMigrationVersion MigrationVersion
+	migrateBlockSize       bool
 	concurrency            int
 }
 
@@ -61,6 +62,16 @@ func (o *options) TargetMigrationVersion() MigrationVersion {
 	return o.targetMigrationVersion
 }
 
+func (o *options) SetMigrateBlockSize(value bool) Options {
+	opts := *o
+	opts.migrateBlockSize = value
+	return &opts
+}
+
+func (o *options) MigrateBlockSize() bool {
+	return o.migrateBlockSize
+}
+
 func (o *options) SetConcurrency(value int) Options {
 	opts := *o
 	opts.concurrency = value
@@ -34,6 +34,14 @@ func TestOptionsTargetMigrationVersion(t *testing.T) {
 	require.Equal(t, MigrationVersion_1_1, opts.TargetMigrationVersion())
 }
 
+func TestOptionsMigrateBlockSize(t *testing.T) {
+	opts := NewOptions()
+	require.False(t, opts.MigrateBlockSize())
+
+	opts = opts.SetMigrateBlockSize(true)
+	require.True(t, opts.MigrateBlockSize())
+}
+
 func TestOptionsConcurrency(t *testing.T) {
 	opts := NewOptions()
 	require.Equal(t, defaultMigrationConcurrency, opts.Concurrency())
@@ -40,6 +40,7 @@ var (
 type taskOptions struct {
 	newMergerFn       fs.NewMergerFn
 	infoFileResult    fs.ReadInfoFileResult
+	infoFileResults   []fs.ReadInfoFileResult
 	shard             uint32
 	namespaceMetadata namespace.Metadata
 	persistManager    persist.Manager
@@ -103,6 +104,16 @@ func (t *taskOptions) InfoFileResult() fs.ReadInfoFileResult {
 	return t.infoFileResult
 }
 
+func (t *taskOptions) SetInfoFileResults(value []fs.ReadInfoFileResult) TaskOptions {
+	to := *t
+	to.infoFileResults = value
+	return &to
+}
+
+func (t *taskOptions) InfoFileResults() []fs.ReadInfoFileResult {
+	return t.infoFileResults
+}
+
 func (t *taskOptions) SetShard(value uint32) TaskOptions {
 	to := *t
 	to.shard = value
@@ -113,6 +113,12 @@ func TestInfoFileResult(t *testing.T) {
 	require.Equal(t, value, opts.SetInfoFileResult(value).InfoFileResult())
 }
 
+func TestInfoFileResults(t *testing.T) {
+	opts := NewTaskOptions()
+	value := []fs.ReadInfoFileResult{{}, {}}
+	require.Equal(t, value, opts.SetInfoFileResults(value).InfoFileResults())
+}
+
 func TestShard(t *testing.T) {
 	opts := NewTaskOptions()
 	value := uint32(1)
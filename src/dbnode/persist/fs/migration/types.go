@@ -38,6 +38,14 @@ type Options interface {
 	// TargetMigrationVersion is the target version for a migration.
 	TargetMigrationVersion() MigrationVersion
 
+	// SetMigrateBlockSize sets whether filesets written with a block size other than
+	// their namespace's current block size should be re-chunked to the current block size.
+	SetMigrateBlockSize(value bool) Options
+
+	// MigrateBlockSize returns whether filesets written with a block size other than
+	// their namespace's current block size should be re-chunked to the current block size.
+	MigrateBlockSize() bool
+
 	// SetConcurrency sets the number of concurrent workers performing migrations.
 	SetConcurrency(value int) Options
 
@@ -65,6 +73,12 @@ type TaskOptions interface {
 	// InfoFileResult gets the info file resulted associated with this task.
 	InfoFileResult() fs.ReadInfoFileResult
 
+	// SetInfoFileResults sets all the info file results of the shard associated with this task.
+	SetInfoFileResults(value []fs.ReadInfoFileResult) TaskOptions
+
+	// InfoFileResults gets all the info file results of the shard associated with this task.
+	InfoFileResults() []fs.ReadInfoFileResult
+
 	// SetShard sets the shard associated with this task.
 	SetShard(value uint32) TaskOptions
 
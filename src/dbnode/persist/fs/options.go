@@ -109,6 +109,8 @@ type options struct {
 	mmapReporter                         mmap.Reporter
 	indexReaderAutovalidateIndexSegments bool
 	encodingOptions                      msgpack.LegacyEncodingOptions
+	tieredStorageOptions                 TieredStorageOptions
+	diskUsageEvictionOptions             DiskUsageEvictionOptions
 }
 
 // NewOptions creates a new set of fs options
@@ -166,6 +168,12 @@ func (o *options) Validate() error {
 	if o.tagDecoderPool == nil {
 		return errTagDecoderPoolNotSet
 	}
+	if err := o.tieredStorageOptions.Validate(); err != nil {
+		return err
+	}
+	if err := o.diskUsageEvictionOptions.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -408,3 +416,23 @@ func (o *options) SetEncodingOptions(value msgpack.LegacyEncodingOptions) Option
 func (o *options) EncodingOptions() msgpack.LegacyEncodingOptions {
 	return o.encodingOptions
 }
+
+func (o *options) SetTieredStorageOptions(value TieredStorageOptions) Options {
+	opts := *o
+	opts.tieredStorageOptions = value
+	return &opts
+}
+
+func (o *options) TieredStorageOptions() TieredStorageOptions {
+	return o.tieredStorageOptions
+}
+
+func (o *options) SetDiskUsageEvictionOptions(value DiskUsageEvictionOptions) Options {
+	opts := *o
+	opts.diskUsageEvictionOptions = value
+	return &opts
+}
+
+func (o *options) DiskUsageEvictionOptions() DiskUsageEvictionOptions {
+	return o.diskUsageEvictionOptions
+}
@@ -200,6 +200,15 @@ func (r *reader) Open(opts DataReaderOpenOptions) error {
 	}
 	r.expectedDigestOfDigest = digest
 
+	// Data and index files of data filesets of old blocks may have been tiered
+	// to the object store and need to be restored before they can be opened,
+	// snapshot filesets are never tiered.
+	if opts.FileSetType == persist.FileSetFlushType {
+		if err := restoreTieredFiles(r.opts, indexFilepath, dataFilepath); err != nil {
+			return err
+		}
+	}
+
 	var infoFd, digestFd *os.File
 	err = openFiles(os.Open, map[string]**os.File{
 		infoFilepath:        &infoFd,
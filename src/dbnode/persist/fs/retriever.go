@@ -205,6 +205,15 @@ func (r *blockRetriever) AssignShardSet(shardSet sharding.ShardSet) {
 	r.seekerMgr.AssignShardSet(shardSet)
 }
 
+func (r *blockRetriever) EvictBefore(shard uint32, blockStart xtime.UnixNano) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.status != blockRetrieverOpen {
+		return
+	}
+	r.seekerMgr.EvictBefore(shard, blockStart)
+}
+
 func (r *blockRetriever) fetchLoop(seekerMgr DataFileSetSeekerManager) {
 	var (
 		seekerResources    = NewReusableSeekerResources(r.fsOpts)
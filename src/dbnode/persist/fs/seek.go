@@ -172,6 +172,15 @@ func (s *seeker) Open(
 		}
 	}
 
+	// Data and index files of old blocks may have been tiered to the object
+	// store and need to be restored before they can be opened.
+	if err := restoreTieredFiles(s.opts.opts,
+		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, indexFileSuffix, isLegacy),
+		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, dataFileSuffix, isLegacy),
+	); err != nil {
+		return err
+	}
+
 	// Open necessary files
 	if err := openFiles(os.Open, map[string]**os.File{
 		dataFilesetPathFromTimeAndIndex(shardDir, blockStart, volumeIndex, infoFileSuffix, isLegacy):        &infoFd,
@@ -56,6 +56,9 @@ var (
 	errSeekerManagerFileSetNotFound = errors.New(
 		"seeker manager lookup fileset not found",
 	)
+	errSeekerManagerBlockEvicted = errors.New(
+		"seeker manager lookup block evicted",
+	)
 	errNoAvailableSeekers = errors.New(
 		"no available seekers",
 	)
@@ -184,6 +187,9 @@ type seekersByTime struct {
 	shard    uint32
 	accessed bool
 	seekers  map[xtime.UnixNano]rotatableSeekers
+	// evictedBefore is the block start before which the filesets of the
+	// shard were evicted and no seekers are opened.
+	evictedBefore xtime.UnixNano
 }
 
 // rotatableSeekers is a wrapper around seekersAndBloom that allows for rotating
@@ -314,6 +320,21 @@ func (m *seekerManager) AssignShardSet(shardSet sharding.ShardSet) {
 	m.Unlock()
 }
 
+func (m *seekerManager) EvictBefore(shard uint32, blockStart xtime.UnixNano) {
+	byTime, ok := m.seekersByTime(shard)
+	if !ok {
+		return
+	}
+
+	// The seekers of the evicted blocks are closed by the open/close loop
+	// once they have all been returned.
+	byTime.Lock()
+	if blockStart.After(byTime.evictedBefore) {
+		byTime.evictedBefore = blockStart
+	}
+	byTime.Unlock()
+}
+
 func (m *seekerManager) Test(
 	id ident.ID,
 	shard uint32,
@@ -689,6 +710,10 @@ func (m *seekerManager) getOrOpenSeekersWithLock(
 	start xtime.UnixNano,
 	byTime *seekersByTime,
 ) (seekersAndBloom, error) {
+	if start.Before(byTime.evictedBefore) {
+		return seekersAndBloom{}, errSeekerManagerBlockEvicted
+	}
+
 	seekers, ok := byTime.seekers[start]
 	if ok && seekers.active.wg == nil {
 		// Seekers are already open
@@ -809,7 +834,7 @@ func (m *seekerManager) openAnyUnopenSeekers(byTime *seekersByTime) error {
 		byTime.Lock()
 		_, err := m.getOrOpenSeekersWithLock(t, byTime)
 		byTime.Unlock()
-		if err != nil && err != errSeekerManagerFileSetNotFound {
+		if err != nil && err != errSeekerManagerFileSetNotFound && err != errSeekerManagerBlockEvicted {
 			multiErr = multiErr.Add(err)
 		}
 	}
@@ -1024,6 +1049,9 @@ func (m *seekerManager) openCloseLoop() {
 			byTime.RLock()
 			for blockStart := range byTime.seekers {
 				if blockStart.Before(earliestSeekableBlockStart) ||
+					// Close seekers for blocks whose filesets were evicted so
+					// that the disk space of the deleted files is reclaimed.
+					blockStart.Before(byTime.evictedBefore) ||
 					// Close seekers for shards that are no longer available. This
 					// ensure that seekers are eventually consistent w/ shard state.
 					!m.shardExistsWithLock(uint32(shard)) {
@@ -733,3 +733,56 @@ func TestSeekerManagerDoNotOpenSeekersForOutOfRetentionBlocks(t *testing.T) {
 	require.NotContains(t, openSeekers, earliestBlockStart.Add(-blockSize))
 	require.NotContains(t, openSeekers, earliestBlockStart.Add(-2*blockSize))
 }
+
+func TestSeekerManagerEvictBefore(t *testing.T) {
+	defer leaktest.CheckTimeout(t, 1*time.Minute)()
+
+	ctrl := xtest.NewController(t)
+
+	shards := []uint32{2, 5}
+	m := NewSeekerManager(nil, testDefaultOpts, defaultTestBlockRetrieverOptions).(*seekerManager)
+	m.newOpenSeekerFn = func(
+		shard uint32,
+		blockStart xtime.UnixNano,
+		volume int,
+	) (DataFileSetSeeker, error) {
+		mock := NewMockDataFileSetSeeker(ctrl)
+		mock.EXPECT().Open(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mock.EXPECT().ConcurrentClone().Return(mock, nil).AnyTimes()
+		mock.EXPECT().Close().Return(nil).AnyTimes()
+		mock.EXPECT().ConcurrentIDBloomFilter().Return(nil).AnyTimes()
+		return mock, nil
+	}
+	m.sleepFn = func(_ time.Duration) {
+		time.Sleep(time.Millisecond)
+	}
+
+	metadata := testNs1Metadata(t)
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards(shards, shard.Available),
+		sharding.DefaultHashFn(1),
+	)
+	require.NoError(t, err)
+	require.NoError(t, m.Open(metadata, shardSet))
+
+	blockSize := metadata.Options().RetentionOptions().BlockSize()
+	evictBefore := xtime.UnixNano(0).Add(blockSize)
+	m.EvictBefore(shards[0], evictBefore)
+
+	// Blocks before the watermark are rejected for the evicted shard only.
+	_, err = m.Borrow(shards[0], 0)
+	require.Equal(t, errSeekerManagerBlockEvicted, err)
+	seeker, err := m.Borrow(shards[0], evictBefore)
+	require.NoError(t, err)
+	require.NoError(t, m.Return(shards[0], evictBefore, seeker))
+	seeker, err = m.Borrow(shards[1], 0)
+	require.NoError(t, err)
+	require.NoError(t, m.Return(shards[1], 0, seeker))
+
+	// The watermark never moves backwards.
+	m.EvictBefore(shards[0], 0)
+	_, err = m.Borrow(shards[0], 0)
+	require.Equal(t, errSeekerManagerBlockEvicted, err)
+
+	require.NoError(t, m.Close())
+}
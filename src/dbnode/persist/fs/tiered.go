@@ -0,0 +1,373 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+var (
+	// ErrObjectNotFound is returned by an ObjectStore when an object does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+
+	errTieredStorageEvictAfterNotSet = errors.New("tiered storage evict after must be positive")
+
+	// tieredFileSuffixes are the suffixes of the fileset files that are tiered
+	// to the object store, all other fileset files are small and are always kept
+	// locally so that filesets can be discovered and validated without reaching
+	// out to the object store.
+	tieredFileSuffixes = []string{dataFileSuffix, indexFileSuffix}
+)
+
+// ObjectStore is a store of immutable objects that data filesets of old blocks
+// can be tiered to. Keys are slash separated paths.
+type ObjectStore interface {
+	// Put stores an object with the contents of the reader.
+	Put(key string, r io.Reader) error
+
+	// Get returns a reader for the contents of an object, or ErrObjectNotFound
+	// if the object does not exist.
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete deletes an object, deleting an object that does not exist is a no-op.
+	Delete(key string) error
+
+	// List returns the keys of all objects below a key prefix directory.
+	List(dir string) ([]string, error)
+}
+
+// TieredStorageOptions are the options for tiering data filesets of old blocks
+// to an object store.
+type TieredStorageOptions struct {
+	// ObjectStore is the object store to tier filesets to, tiering is disabled
+	// when not set.
+	ObjectStore ObjectStore
+
+	// EvictAfter is how long after the end of a block its fileset is uploaded
+	// to the object store and evicted locally.
+	EvictAfter time.Duration
+
+	// CacheTTL is how long files restored from the object store to serve reads,
+	// or recently written, are kept locally before being evicted again.
+	CacheTTL time.Duration
+}
+
+// Validate validates the tiered storage options.
+func (o TieredStorageOptions) Validate() error {
+	if o.ObjectStore != nil && o.EvictAfter <= 0 {
+		return errTieredStorageEvictAfterNotSet
+	}
+	return nil
+}
+
+type directoryObjectStore struct {
+	dir              string
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+}
+
+// NewDirectoryObjectStore returns an object store that stores objects as files
+// below a directory, typically the mount point of an object storage bucket.
+func NewDirectoryObjectStore(
+	dir string,
+	newFileMode os.FileMode,
+	newDirectoryMode os.FileMode,
+) ObjectStore {
+	return &directoryObjectStore{
+		dir:              dir,
+		newFileMode:      newFileMode,
+		newDirectoryMode: newDirectoryMode,
+	}
+}
+
+func (s *directoryObjectStore) Put(key string, r io.Reader) error {
+	objectPath := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(objectPath), s.newDirectoryMode); err != nil {
+		return err
+	}
+	return writeFileAtomically(objectPath, r, s.newFileMode)
+}
+
+func (s *directoryObjectStore) Get(key string) (io.ReadCloser, error) {
+	fd, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
+
+func (s *directoryObjectStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *directoryObjectStore) List(dir string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.path(dir), func(objectPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		key, err := filepath.Rel(s.dir, objectPath)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(key))
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return keys, err
+}
+
+func (s *directoryObjectStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+// TierDataFiles uploads the data and index files of the complete data filesets
+// of a shard whose blocks ended before the given time to the object store and
+// evicts them locally. Files written or restored within the cache TTL are kept.
+// Objects of filesets that no longer exist locally, such as expired or compacted
+// filesets, are deleted from the object store.
+func TierDataFiles(
+	opts Options,
+	namespace ident.ID,
+	shard uint32,
+	blockSize time.Duration,
+	before xtime.UnixNano,
+) error {
+	tieredOpts := opts.TieredStorageOptions()
+	store := tieredOpts.ObjectStore
+	if store == nil {
+		return nil
+	}
+
+	var (
+		filePathPrefix = opts.FilePathPrefix()
+		shardDir       = ShardDataDirPath(filePathPrefix, namespace, shard)
+		cachedAfter    = opts.ClockOptions().NowFn()().Add(-tieredOpts.CacheTTL)
+	)
+	shardKey, err := tieredObjectKey(filePathPrefix, shardDir)
+	if err != nil {
+		return err
+	}
+	keys, err := store.List(shardKey)
+	if err != nil {
+		return err
+	}
+	uploaded := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		uploaded[key] = struct{}{}
+	}
+
+	filesets, err := DataFiles(filePathPrefix, namespace, shard)
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for i := range filesets {
+		fileset := &filesets[i]
+		if fileset.ID.BlockStart.Add(blockSize).After(before) {
+			continue
+		}
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		for _, filePath := range fileset.AbsoluteFilePaths {
+			if _, ok := tieredCheckpointFilePath(filePath); !ok {
+				continue
+			}
+			if err := tierFile(store, filePathPrefix, filePath, cachedAfter, uploaded); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		checkpointFilePath, ok := tieredCheckpointFilePath(
+			filepath.Join(filePathPrefix, filepath.FromSlash(key)))
+		if !ok {
+			continue
+		}
+		exists, err := CompleteCheckpointFileExists(checkpointFilePath)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		if exists {
+			continue
+		}
+		if err := store.Delete(key); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func tierFile(
+	store ObjectStore,
+	filePathPrefix string,
+	filePath string,
+	cachedAfter time.Time,
+	uploaded map[string]struct{},
+) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().After(cachedAfter) {
+		return nil
+	}
+
+	key, err := tieredObjectKey(filePathPrefix, filePath)
+	if err != nil {
+		return err
+	}
+	// Fileset volumes are immutable so files restored from the object store do
+	// not need to be uploaded again before they are evicted.
+	if _, ok := uploaded[key]; !ok {
+		fd, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		err = store.Put(key, fd)
+		if closeErr := fd.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to upload %s to object store: %w", filePath, err)
+		}
+	}
+
+	// NB: Seekers that already have the file open can continue reading it after
+	// it has been removed.
+	return os.Remove(filePath)
+}
+
+// restoreTieredFiles restores any of the given fileset files that have been
+// evicted to the object store, files that do not exist in the object store
+// are left for the caller to fail to open.
+func restoreTieredFiles(opts Options, filePaths ...string) error {
+	store := opts.TieredStorageOptions().ObjectStore
+	if store == nil {
+		return nil
+	}
+
+	for _, filePath := range filePaths {
+		_, err := os.Stat(filePath)
+		if err == nil {
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		key, err := tieredObjectKey(opts.FilePathPrefix(), filePath)
+		if err != nil {
+			return err
+		}
+		r, err := store.Get(key)
+		if err == ErrObjectNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to restore %s from object store: %w", filePath, err)
+		}
+		err = writeFileAtomically(filePath, r, opts.NewFileMode())
+		if closeErr := r.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to restore %s from object store: %w", filePath, err)
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomically writes the contents of the reader to a hidden temporary
+// file next to the destination and renames it into place so that concurrent
+// readers never observe a partially written file.
+func writeFileAtomically(filePath string, r io.Reader, mode os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close() // nolint: errcheck
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() // nolint: errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func tieredObjectKey(filePathPrefix, filePath string) (string, error) {
+	key, err := filepath.Rel(filePathPrefix, filePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(key), nil
+}
+
+// tieredCheckpointFilePath returns the path of the checkpoint file of the
+// fileset a tiered file belongs to, and false if the file is not tiered.
+func tieredCheckpointFilePath(filePath string) (string, bool) {
+	for _, suffix := range tieredFileSuffixes {
+		tieredSuffix := separator + suffix + fileSuffix
+		if strings.HasSuffix(filePath, tieredSuffix) {
+			return strings.TrimSuffix(filePath, tieredSuffix) +
+				separator + checkpointFileSuffix + fileSuffix, true
+		}
+	}
+	return "", false
+}
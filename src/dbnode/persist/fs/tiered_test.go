@@ -0,0 +1,186 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/x/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTierDataFilesEvictsAndRestores(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "data")
+	objectStoreDir := filepath.Join(dir, "objects")
+	defer os.RemoveAll(dir)
+
+	var (
+		shard   uint32 = 0
+		now            = testWriterStart.ToTime()
+		entries        = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", nil, []byte{4, 5, 6}},
+		}
+		store      = NewDirectoryObjectStore(objectStoreDir, defaultNewFileMode, defaultNewDirectoryMode)
+		tieredOpts = TieredStorageOptions{
+			ObjectStore: store,
+			EvictAfter:  time.Hour,
+			CacheTTL:    time.Hour,
+		}
+		opts = testDefaultOpts.
+			SetFilePathPrefix(filePathPrefix).
+			SetClockOptions(clock.NewOptions().SetNowFn(func() time.Time { return now })).
+			SetTieredStorageOptions(tieredOpts)
+		shardDir  = ShardDataDirPath(filePathPrefix, testNs1ID, shard)
+		dataFile  = filesetPathFromTimeAndIndex(shardDir, testWriterStart, 0, dataFileSuffix)
+		indexFile = filesetPathFromTimeAndIndex(shardDir, testWriterStart, 0, indexFileSuffix)
+		dataKey   = "data/testNs/0/" + filepath.Base(dataFile)
+		indexKey  = "data/testNs/0/" + filepath.Base(indexFile)
+	)
+	require.NoError(t, opts.Validate())
+
+	w := newTestWriter(t, filePathPrefix)
+	writeTestData(t, w, shard, testWriterStart, entries, persist.FileSetFlushType)
+
+	// Filesets of blocks that have not ended before the threshold are kept.
+	before := testWriterStart.Add(testBlockSize).Add(-time.Second)
+	require.NoError(t, TierDataFiles(opts, testNs1ID, shard, testBlockSize, before))
+	requireFilesExist(t, true, dataFile, indexFile)
+
+	// Recently written files are kept for the cache TTL.
+	before = testWriterStart.Add(testBlockSize)
+	require.NoError(t, TierDataFiles(opts, testNs1ID, shard, testBlockSize, before))
+	requireFilesExist(t, true, dataFile, indexFile)
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, TierDataFiles(opts, testNs1ID, shard, testBlockSize, before))
+	requireFilesExist(t, false, dataFile, indexFile)
+	keys, err := store.List("data")
+	require.NoError(t, err)
+	sort.Strings(keys)
+	require.Equal(t, []string{dataKey, indexKey}, keys)
+
+	// The fileset is still discoverable locally.
+	files, err := DataFiles(filePathPrefix, testNs1ID, shard)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	require.True(t, files[0].HasCompleteCheckpointFile())
+
+	// Reads transparently restore the evicted files.
+	r, err := NewReader(testBytesPool, opts.
+		SetInfoReaderBufferSize(testReaderBufferSize).
+		SetDataReaderBufferSize(testReaderBufferSize))
+	require.NoError(t, err)
+	readTestData(t, r, shard, testWriterStart, entries)
+	requireFilesExist(t, true, dataFile, indexFile)
+
+	require.NoError(t, os.Remove(dataFile))
+	require.NoError(t, os.Remove(indexFile))
+	s := NewSeeker(filePathPrefix, testReaderBufferSize, testReaderBufferSize,
+		testBytesPool, false, opts)
+	resources := NewReusableSeekerResources(opts)
+	require.NoError(t, s.Open(testNs1ID, shard, testWriterStart, 0, resources))
+	require.NoError(t, s.Close())
+	requireFilesExist(t, true, dataFile, indexFile)
+
+	// Restored files are evicted again once the cache TTL has passed.
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, TierDataFiles(opts, testNs1ID, shard, testBlockSize, before))
+	requireFilesExist(t, false, dataFile, indexFile)
+
+	// Objects of filesets removed locally are deleted from the object store.
+	require.NoError(t, DeleteFiles(files[0].AbsoluteFilePaths))
+	require.NoError(t, TierDataFiles(opts, testNs1ID, shard, testBlockSize, before))
+	keys, err = store.List("data")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestReaderDoesNotRestoreSnapshotFiles(t *testing.T) {
+	dir := createTempDir(t)
+	filePathPrefix := filepath.Join(dir, "data")
+	objectStoreDir := filepath.Join(dir, "objects")
+	defer os.RemoveAll(dir)
+
+	var (
+		shard uint32 = 0
+		store        = NewDirectoryObjectStore(objectStoreDir, defaultNewFileMode, defaultNewDirectoryMode)
+		opts         = testDefaultOpts.
+			SetFilePathPrefix(filePathPrefix).
+			SetTieredStorageOptions(TieredStorageOptions{
+				ObjectStore: store,
+				EvictAfter:  time.Hour,
+			})
+		shardDir = ShardSnapshotsDirPath(filePathPrefix, testNs1ID, shard)
+		dataFile = filesetPathFromTimeAndIndex(shardDir, testWriterStart, 0, dataFileSuffix)
+	)
+
+	w := newTestWriter(t, filePathPrefix)
+	writeTestData(t, w, shard, testWriterStart, nil, persist.FileSetSnapshotType)
+
+	// Snapshot filesets are never tiered, so a missing snapshot data file is
+	// not looked up in the object store even if an object exists for it.
+	f, err := os.Open(dataFile)
+	require.NoError(t, err)
+	key, err := tieredObjectKey(filePathPrefix, dataFile)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(key, f))
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Remove(dataFile))
+
+	r, err := NewReader(testBytesPool, opts)
+	require.NoError(t, err)
+	err = r.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  testNs1ID,
+			Shard:      shard,
+			BlockStart: testWriterStart,
+		},
+		FileSetType: persist.FileSetSnapshotType,
+	})
+	require.Error(t, err)
+	requireFilesExist(t, false, dataFile)
+}
+
+func TestTieredStorageOptionsValidate(t *testing.T) {
+	require.NoError(t, TieredStorageOptions{}.Validate())
+	require.Equal(t, errTieredStorageEvictAfterNotSet, TieredStorageOptions{
+		ObjectStore: NewDirectoryObjectStore("", defaultNewFileMode, defaultNewDirectoryMode),
+	}.Validate())
+}
+
+func requireFilesExist(t *testing.T, exist bool, filePaths ...string) {
+	for _, filePath := range filePaths {
+		_, err := os.Stat(filePath)
+		if exist {
+			require.NoError(t, err, filePath)
+		} else {
+			require.True(t, os.IsNotExist(err), filePath)
+		}
+	}
+}
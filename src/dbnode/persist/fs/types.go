@@ -140,7 +140,9 @@ type DataReaderOpenOptions struct {
 type DataFileSetReader interface {
 	io.Closer
 
-	// Open opens the files for the given shard and version for reading
+	// Open opens the files for the given shard and version for reading, data
+	// and index files of data filesets that have been tiered to the object
+	// store are restored first.
 	Open(opts DataReaderOpenOptions) error
 
 	// Status returns the status of the reader
@@ -301,6 +303,10 @@ type DataFileSetSeekerManager interface {
 	// AssignShardSet assigns current per ns shardset.
 	AssignShardSet(shardSet sharding.ShardSet)
 
+	// EvictBefore stops opening seekers for the blocks of a given shard before
+	// a given block start, and closes their open seekers once returned.
+	EvictBefore(shard uint32, blockStart xtime.UnixNano)
+
 	// Borrow returns an open seeker for a given shard, block start time, and
 	// volume.
 	Borrow(shard uint32, start xtime.UnixNano) (ConcurrentDataFileSetSeeker, error)
@@ -555,6 +561,22 @@ type Options interface {
 
 	// EncodingOptions returns the encoder options used by the encoder.
 	EncodingOptions() msgpack.LegacyEncodingOptions
+
+	// SetTieredStorageOptions sets the options for tiering data filesets
+	// of old blocks to an object store.
+	SetTieredStorageOptions(value TieredStorageOptions) Options
+
+	// TieredStorageOptions returns the options for tiering data filesets
+	// of old blocks to an object store.
+	TieredStorageOptions() TieredStorageOptions
+
+	// SetDiskUsageEvictionOptions sets the options for evicting the oldest
+	// data filesets when disk usage is high.
+	SetDiskUsageEvictionOptions(value DiskUsageEvictionOptions) Options
+
+	// DiskUsageEvictionOptions returns the options for evicting the oldest
+	// data filesets when disk usage is high.
+	DiskUsageEvictionOptions() DiskUsageEvictionOptions
 }
 
 // BlockRetrieverOptions represents the options for block retrieval.
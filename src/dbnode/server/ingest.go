@@ -0,0 +1,128 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3dbnode/config"
+	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xio "github.com/m3db/m3/src/x/io"
+	xserver "github.com/m3db/m3/src/x/server"
+)
+
+type ingestNamespaceKey struct {
+	resolution time.Duration
+	retention  time.Duration
+}
+
+// ingestAppender writes aggregated metrics consumed from m3msg directly
+// to the namespace their storage policy is mapped to, without going
+// through a coordinator.
+type ingestAppender struct {
+	client     client.Client
+	namespaces map[ingestNamespaceKey]ident.ID
+}
+
+func newIngestAppender(
+	cfg config.IngestConfiguration,
+	client client.Client,
+) *ingestAppender {
+	namespaces := make(map[ingestNamespaceKey]ident.ID, len(cfg.Namespaces))
+	for _, ns := range cfg.Namespaces {
+		key := ingestNamespaceKey{
+			resolution: ns.StoragePolicy.Resolution().Window,
+			retention:  ns.StoragePolicy.Retention().Duration(),
+		}
+		namespaces[key] = ident.StringID(ns.Namespace)
+	}
+	return &ingestAppender{
+		client:     client,
+		namespaces: namespaces,
+	}
+}
+
+func (a *ingestAppender) Write(_ context.Context, query *storage.WriteQuery) error {
+	attrs := query.Attributes()
+	if attrs.MetricsType != storagemetadata.AggregatedMetricsType {
+		return xerrors.NewNonRetryableError(fmt.Errorf(
+			"invalid ingest metrics type: %s", attrs.MetricsType.String()))
+	}
+	namespace, ok := a.namespaces[ingestNamespaceKey{
+		resolution: attrs.Resolution,
+		retention:  attrs.Retention,
+	}]
+	if !ok {
+		// NB: not retryable since the message would otherwise be redelivered
+		// until the configuration changes.
+		return xerrors.NewNonRetryableError(fmt.Errorf(
+			"no ingest namespace configured for: retention=%s, resolution=%s",
+			attrs.Retention.String(), attrs.Resolution.String()))
+	}
+
+	// NB: the default session is created once and reused by later writes.
+	session, err := a.client.DefaultSession()
+	if err != nil {
+		return err
+	}
+
+	var (
+		tags = query.Tags()
+		id   = ident.BytesID(tags.ID())
+	)
+	for _, dp := range query.Datapoints() {
+		err := session.WriteTagged(namespace, id, storage.TagsToIdentTagIterator(tags),
+			dp.Timestamp, dp.Value, query.Unit(), query.Annotation())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newIngestServer returns the m3msg server that consumes aggregated metrics
+// and writes them using the client.
+func newIngestServer(
+	cfg config.IngestConfiguration,
+	client client.Client,
+	iOpts instrument.Options,
+) (xserver.Server, error) {
+	tagOpts, err := coordinatorcfg.TagOptionsFromConfig(cfg.TagOptions)
+	if err != nil {
+		return nil, err
+	}
+	scope := iOpts.MetricsScope().SubScope("ingest-m3msg")
+	iOpts = iOpts.SetMetricsScope(scope)
+	ingester, err := cfg.Ingester.NewIngester(newIngestAppender(cfg, client),
+		tagOpts, iOpts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.M3Msg.NewServer(ingester.Ingest, xio.NewOptions(), iOpts)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3dbnode/config"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIngestWriteQuery(
+	t *testing.T,
+	resolution, retention time.Duration,
+) *storage.WriteQuery {
+	tags := models.NewTags(1, nil).AddTag(models.Tag{
+		Name:  []byte("__name__"),
+		Value: []byte("foo"),
+	})
+	q, err := storage.NewWriteQuery(storage.WriteQueryOptions{
+		Tags: tags,
+		Datapoints: ts.Datapoints{
+			{Timestamp: xtime.UnixNano(1000), Value: 42},
+		},
+		Unit: xtime.Second,
+		Attributes: storagemetadata.Attributes{
+			MetricsType: storagemetadata.AggregatedMetricsType,
+			Resolution:  resolution,
+			Retention:   retention,
+		},
+	})
+	require.NoError(t, err)
+	return q
+}
+
+func TestIngestAppenderWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sp, err := policy.ParseStoragePolicy("1m:40d")
+	require.NoError(t, err)
+	cfg := config.IngestConfiguration{
+		Namespaces: []config.IngestNamespaceConfiguration{
+			{Namespace: "metrics_1m_40d", StoragePolicy: sp},
+		},
+	}
+
+	session := client.NewMockSession(ctrl)
+	mockClient := client.NewMockClient(ctrl)
+	mockClient.EXPECT().DefaultSession().Return(session, nil).AnyTimes()
+	appender := newIngestAppender(cfg, mockClient)
+
+	q := newTestIngestWriteQuery(t, time.Minute, 40*24*time.Hour)
+	session.EXPECT().
+		WriteTagged(ident.NewIDMatcher("metrics_1m_40d"), gomock.Any(), gomock.Any(),
+			xtime.UnixNano(1000), 42.0, xtime.Second, gomock.Nil()).
+		DoAndReturn(func(_, id ident.ID, tags ident.TagIterator, _ xtime.UnixNano,
+			_ float64, _ xtime.Unit, _ []byte) error {
+			require.Equal(t, string(q.Tags().ID()), id.String())
+			require.Equal(t, 1, tags.Remaining())
+			return nil
+		})
+	require.NoError(t, appender.Write(context.Background(), q))
+
+	// Writes with an unmapped storage policy are not retried.
+	q = newTestIngestWriteQuery(t, 10*time.Minute, 365*24*time.Hour)
+	err = appender.Write(context.Background(), q)
+	require.Error(t, err)
+	require.True(t, xerrors.IsNonRetryableError(err))
+}
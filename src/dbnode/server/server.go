@@ -88,10 +88,12 @@ import (
 	xdocs "github.com/m3db/m3/src/x/docs"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
+	xlog "github.com/m3db/m3/src/x/log"
 	"github.com/m3db/m3/src/x/mmap"
 	xos "github.com/m3db/m3/src/x/os"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/serialize"
+	xserver "github.com/m3db/m3/src/x/server"
 
 	apachethrift "github.com/apache/thrift/lib/go/thrift"
 	"github.com/m3dbx/vellum/levenshtein"
@@ -419,6 +421,17 @@ func Run(runOpts RunOptions) {
 	}
 	defer buildReporter.Stop()
 
+	continuousProfiler, err := cfg.Debug.NewContinuousProfiler(iOpts)
+	if err != nil {
+		logger.Fatal("could not create continuous profiler", zap.Error(err))
+	}
+	if continuousProfiler != nil {
+		if err := continuousProfiler.Start(); err != nil {
+			logger.Fatal("could not start continuous profiler", zap.Error(err))
+		}
+		defer continuousProfiler.Stop()
+	}
+
 	mmapCfg := cfg.Filesystem.MmapConfigurationOrDefault()
 	shouldUseHugeTLB := mmapCfg.HugeTLB.Enabled
 	if shouldUseHugeTLB {
@@ -522,6 +535,8 @@ func Run(runOpts RunOptions) {
 		plCacheOptions = index.PostingsListCacheOptions{
 			InstrumentOptions: opts.InstrumentOptions().
 				SetMetricsScope(scope.SubScope("postings-list-cache")),
+			MaxBytes:                plCacheConfig.MaxBytesOrDefault(),
+			MatcherHashMetricsLimit: plCacheConfig.MatcherHashMetricsLimitOrDefault(),
 		}
 	)
 	segmentPostingsListCache, err := index.NewPostingsListCache(plCacheSize, plCacheOptions)
@@ -540,6 +555,18 @@ func Run(runOpts RunOptions) {
 	searchStopReporting := searchPostingsListCache.Start()
 	defer searchStopReporting()
 
+	// Setup index segments memory budget.
+	var segmentsMemoryBudget *index.SegmentsMemoryBudget
+	if maxBytes := cfg.Index.SegmentsMemoryBudgetBytes; maxBytes > 0 {
+		segmentsMemoryBudget, err = index.NewSegmentsMemoryBudget(index.SegmentsMemoryBudgetOptions{
+			MaxBytes:          maxBytes,
+			InstrumentOptions: opts.InstrumentOptions().SetMetricsScope(scope.SubScope("index")),
+		})
+		if err != nil {
+			logger.Fatal("could not construct index segments memory budget", zap.Error(err))
+		}
+	}
+
 	// Setup index regexp compilation cache.
 	m3ninxindex.SetRegexpCacheOptions(m3ninxindex.RegexpCacheOptions{
 		Size:  cfg.Cache.RegexpConfiguration().SizeOrDefault(),
@@ -570,6 +597,7 @@ func Run(runOpts RunOptions) {
 	indexOpts = indexOpts.SetInsertMode(insertMode).
 		SetPostingsListCache(segmentPostingsListCache).
 		SetSearchPostingsListCache(searchPostingsListCache).
+		SetSegmentsMemoryBudget(segmentsMemoryBudget).
 		SetReadThroughSegmentOptions(index.ReadThroughSegmentOptions{
 			CacheRegexp:   plCacheConfig.CacheRegexpOrDefault(),
 			CacheTerms:    plCacheConfig.CacheTermsOrDefault(),
@@ -622,7 +650,8 @@ func Run(runOpts RunOptions) {
 	fsopts := fs.NewOptions().
 		SetClockOptions(opts.ClockOptions()).
 		SetInstrumentOptions(opts.InstrumentOptions().
-			SetMetricsScope(scope.SubScope("database.fs"))).
+			SetMetricsScope(scope.SubScope("database.fs")).
+			SetLogger(logger.Named("fs"))).
 		SetFilePathPrefix(cfg.Filesystem.FilePathPrefixOrDefault()).
 		SetNewFileMode(newFileMode).
 		SetNewDirectoryMode(newDirectoryMode).
@@ -639,6 +668,14 @@ func Run(runOpts RunOptions) {
 		SetForceBloomFilterMmapMemory(cfg.Filesystem.ForceBloomFilterMmapMemoryOrDefault()).
 		SetIndexBloomFilterFalsePositivePercent(cfg.Filesystem.BloomFilterFalsePositivePercentOrDefault()).
 		SetMmapReporter(mmapReporter)
+	if cfg.Filesystem.TieredStorage != nil {
+		fsopts = fsopts.SetTieredStorageOptions(
+			cfg.Filesystem.TieredStorage.NewOptions(newFileMode, newDirectoryMode))
+	}
+	if cfg.Filesystem.DiskUsageEviction != nil {
+		fsopts = fsopts.SetDiskUsageEvictionOptions(
+			cfg.Filesystem.DiskUsageEviction.NewOptions())
+	}
 
 	var commitLogQueueSize int
 	cfgCommitLog := cfg.CommitLogOrDefault()
@@ -800,7 +837,8 @@ func Run(runOpts RunOptions) {
 		SetMaxOutstandingWriteRequests(cfg.Limits.MaxOutstandingWriteRequests).
 		SetMaxOutstandingReadRequests(cfg.Limits.MaxOutstandingReadRequests).
 		SetQueryLimits(queryLimits).
-		SetPermitsOptions(opts.PermitsOptions())
+		SetPermitsOptions(opts.PermitsOptions()).
+		SetSlowQueryLogOptions(cfg.SlowQueryLog.SlowQueryLogOptions())
 
 	// Start servers before constructing the DB so orchestration tools can check health endpoints
 	// before topology is set.
@@ -823,6 +861,15 @@ func Run(runOpts RunOptions) {
 	if fn := runOpts.StorageOptions.TChanNodeServerFn; fn != nil {
 		tchanOpts = tchanOpts.SetTChanNodeServerFn(fn)
 	}
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.NewTLSConfig()
+		if err != nil {
+			logger.Fatal("could not create node service TLS config", zap.Error(err))
+		}
+		tchanOpts = tchanOpts.
+			SetTLSConfig(tlsConfig).
+			SetClientIdentityOptions(cfg.TLS.ClientIdentityOptions())
+	}
 
 	listenAddress := cfg.ListenAddressOrDefault()
 	tchannelthriftNodeClose, err := ttnode.NewServer(service,
@@ -927,6 +974,15 @@ func Run(runOpts RunOptions) {
 		runOpts.ClientCh <- m3dbClient
 	}
 
+	var ingestServer xserver.Server
+	if cfg.Ingest != nil {
+		ingestServer, err = newIngestServer(*cfg.Ingest, m3dbClient, iOpts)
+		if err != nil {
+			logger.Fatal("could not create m3msg ingest server", zap.Error(err))
+		}
+		defer ingestServer.Close()
+	}
+
 	documentsBuilderAlloc := index.NewBootstrapResultDocumentsBuilderAllocator(
 		opts.IndexOptions())
 	rsOpts := result.NewOptions().
@@ -976,7 +1032,8 @@ func Run(runOpts RunOptions) {
 				SetStrategy(repairCfg.Strategy).
 				SetForce(repairCfg.Force).
 				SetResultOptions(rsOpts).
-				SetDebugShadowComparisonsEnabled(cfg.Repair.DebugShadowComparisonsEnabled)
+				SetDebugShadowComparisonsEnabled(cfg.Repair.DebugShadowComparisonsEnabled).
+				SetAggregatedFallbackEnabled(cfg.Repair.AggregatedFallbackEnabled)
 			if cfg.Repair.Throttle > 0 {
 				repairOpts = repairOpts.SetRepairThrottle(cfg.Repair.Throttle)
 			}
@@ -1046,9 +1103,23 @@ func Run(runOpts RunOptions) {
 
 	opts = opts.SetSchemaRegistry(schemaRegistry).
 		SetAdminClient(m3dbClient)
+	if divergenceCfg := cfg.DivergenceCheck; divergenceCfg != nil && divergenceCfg.Enabled {
+		opts = opts.SetDivergenceCheckEnabled(true)
+		if divergenceCfg.CheckInterval > 0 {
+			opts = opts.SetDivergenceCheckInterval(divergenceCfg.CheckInterval)
+		}
+	}
 	if cfg.WideConfig != nil && cfg.WideConfig.BatchSize > 0 {
 		opts = opts.SetWideBatchSize(cfg.WideConfig.BatchSize)
 	}
+	if len(cfg.NewSeriesHooks) > 0 {
+		hook, err := storage.NewRegisteredNewSeriesHook(cfg.NewSeriesHooks,
+			opts.InstrumentOptions())
+		if err != nil {
+			logger.Fatal("could not create new series hooks", zap.Error(err))
+		}
+		opts = opts.SetNewSeriesHook(hook)
+	}
 
 	db, err := cluster.NewDatabase(hostID, topo, clusterTopoWatch, opts)
 	if err != nil {
@@ -1081,6 +1152,16 @@ func Run(runOpts RunOptions) {
 		}
 		logger.Info("bootstrapped")
 
+		// Only consume aggregated metrics once bootstrapped so that writes
+		// are not rejected while the node is still loading data.
+		if ingestServer != nil {
+			if err := ingestServer.ListenAndServe(); err != nil {
+				logger.Fatal("could not start m3msg ingest server", zap.Error(err))
+			}
+			logger.Info("m3msg ingest server: listening",
+				zap.String("address", cfg.Ingest.M3Msg.Server.ListenAddress))
+		}
+
 		// Only set the write new series limit after bootstrapping
 		kvWatchNewSeriesLimitPerShard(syncCfg.KVStore, logger, topo,
 			runtimeOptsMgr, cfg.Limits.WriteNewSeriesPerSecond)
@@ -1134,6 +1215,7 @@ func startDebugServer(
 	mux *http.ServeMux,
 ) func() {
 	xdebug.RegisterPProfHandlers(mux)
+	xlog.RegisterLevelHandler(mux, logger)
 	server := http.Server{Addr: debugListenAddress, Handler: mux}
 
 	if debugWriter != nil {
@@ -1995,7 +2077,8 @@ func newAdminClient(
 		client.ConfigurationParameters{
 			ClockOptions: clockOpts,
 			InstrumentOptions: iOpts.
-				SetMetricsScope(iOpts.MetricsScope().SubScope("m3dbclient")),
+				SetMetricsScope(iOpts.MetricsScope().SubScope("m3dbclient")).
+				SetLogger(iOpts.Logger().Named("m3dbclient")),
 			TopologyInitializer: topologyInitializer,
 		},
 		options...,
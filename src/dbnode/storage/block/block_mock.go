@@ -946,6 +946,18 @@ func (mr *MockDatabaseBlockRetrieverMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDatabaseBlockRetriever)(nil).Close))
 }
 
+// EvictBefore mocks base method.
+func (m *MockDatabaseBlockRetriever) EvictBefore(shard uint32, blockStart time0.UnixNano) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictBefore", shard, blockStart)
+}
+
+// EvictBefore indicates an expected call of EvictBefore.
+func (mr *MockDatabaseBlockRetrieverMockRecorder) EvictBefore(shard, blockStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBefore", reflect.TypeOf((*MockDatabaseBlockRetriever)(nil).EvictBefore), shard, blockStart)
+}
+
 // Stream mocks base method.
 func (m *MockDatabaseBlockRetriever) Stream(ctx context.Context, shard uint32, id ident.ID, blockStart time0.UnixNano, onRetrieve OnRetrieveBlock, nsCtx namespace.Context) (xio.BlockReader, error) {
 	m.ctrl.T.Helper()
@@ -327,6 +327,10 @@ type DatabaseBlockRetriever interface {
 
 	// AssignShardSet assigns the given shard set to this retriever.
 	AssignShardSet(shardSet sharding.ShardSet)
+
+	// EvictBefore stops retrieving the blocks of a given shard before a given
+	// block start, releasing the resources held open for them.
+	EvictBefore(shard uint32, blockStart xtime.UnixNano)
 }
 
 // DatabaseShardBlockRetriever is a block retriever bound to a shard.
@@ -35,6 +35,10 @@ const (
 	// value for whether to return unfulfilled when encountering corrupt
 	// commit log files.
 	DefaultReturnUnfulfilledForCorruptCommitLogFiles = false
+
+	// defaultSnapshotsVerify defines the default for verifying snapshots
+	// against the commit log.
+	defaultSnapshotsVerify = false
 )
 
 var (
@@ -51,6 +55,7 @@ type options struct {
 	accumulateConcurrency                     int
 	runtimeOptsMgr                            runtime.OptionsManager
 	returnUnfulfilledForCorruptCommitLogFiles bool
+	snapshotsVerify                           bool
 }
 
 // NewOptions creates new bootstrap options
@@ -60,6 +65,7 @@ func NewOptions() Options {
 		commitLogOpts:         commitlog.NewOptions(),
 		accumulateConcurrency: defaultAccumulateConcurrency,
 		returnUnfulfilledForCorruptCommitLogFiles: DefaultReturnUnfulfilledForCorruptCommitLogFiles,
+		snapshotsVerify: defaultSnapshotsVerify,
 	}
 }
 
@@ -122,3 +128,13 @@ func (o *options) SetReturnUnfulfilledForCorruptCommitLogFiles(value bool) Optio
 func (o *options) ReturnUnfulfilledForCorruptCommitLogFiles() bool {
 	return o.returnUnfulfilledForCorruptCommitLogFiles
 }
+
+func (o *options) SetSnapshotsVerify(value bool) Options {
+	opts := *o
+	opts.snapshotsVerify = value
+	return &opts
+}
+
+func (o *options) SnapshotsVerify() bool {
+	return o.snapshotsVerify
+}
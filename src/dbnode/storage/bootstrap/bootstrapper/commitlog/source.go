@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
@@ -40,6 +41,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/x/checked"
 	xcontext "github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
@@ -119,6 +121,8 @@ type readSeriesBlocksWorker struct {
 	blockStart  xtime.UnixNano
 	blockSize   time.Duration
 	nsCtx       namespace.Context
+	iterPool    encoding.ReaderIteratorPool
+	verify      *snapshotVerificationBlock
 }
 
 func (w *readSeriesBlocksWorker) readSeriesBlocks(ctx context.Context) error {
@@ -149,6 +153,12 @@ func (w *readSeriesBlocksWorker) readSeriesBlocks(ctx context.Context) error {
 				id, checksum, expectedChecksum)
 		}
 
+		if w.verify != nil {
+			if err := w.addVerifiedSeries(id, data); err != nil {
+				return err
+			}
+		}
+
 		res, owned, err := w.accumulator.CheckoutSeriesWithoutLock(w.shard, id, tags)
 		if err != nil {
 			if !owned {
@@ -179,6 +189,21 @@ func (w *readSeriesBlocksWorker) readSeriesBlocks(ctx context.Context) error {
 	return nil
 }
 
+func (w *readSeriesBlocksWorker) addVerifiedSeries(id ident.ID, data checked.Bytes) error {
+	iter := w.iterPool.Get()
+	defer iter.Close()
+
+	data.IncRef()
+	defer data.DecRef()
+
+	iter.Reset(xio.NewBytesReader64(data.Bytes()), w.nsCtx.Schema)
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		w.verify.addSnapshotDatapoint(id.Bytes(), dp)
+	}
+	return iter.Err()
+}
+
 type readNamespaceResult struct {
 	namespace               bootstrap.Namespace
 	dataAndIndexShardRanges result.ShardTimeRanges
@@ -237,6 +262,7 @@ type commitLogSource struct {
 	newReaderFn     fs.NewReaderFn
 
 	metrics commitLogSourceMetrics
+	scope   tally.Scope
 	// Cache the results of reading the commit log between passes. The commit log is not sharded by time range, so the
 	// entire log needs to be read irrespective of the configured time ranges for the pass. The commit log only needs
 	// to be read once (during the first pass) and the results can be subsequently cached and returned on future passes.
@@ -274,6 +300,7 @@ func newCommitLogSource(
 		newReaderFn:     fs.NewReader,
 
 		metrics:         newCommitLogSourceMetrics(scope),
+		scope:           scope,
 		instrumentation: newInstrumentation(opts, scope, log),
 	}
 }
@@ -314,9 +341,16 @@ func (s *commitLogSource) Read(
 		fsOpts          = s.opts.CommitLogOptions().FilesystemOptions()
 		filePathPrefix  = fsOpts.FilePathPrefix()
 		namespaceIter   = namespaces.Namespaces.Iter()
+		verifier        *snapshotVerifier
 	)
 	defer doneReadingData()
 
+	// NB: The snapshots can only be verified against the commit log
+	// in the pass that reads it.
+	if s.opts.SnapshotsVerify() && !s.commitLogResult.read {
+		verifier = newSnapshotVerifier(s.scope, s.log)
+	}
+
 	instrCtx.bootstrapSnapshotsStarted()
 	for _, elem := range namespaceIter {
 		ns := elem.Value()
@@ -350,7 +384,7 @@ func (s *commitLogSource) Read(
 		for shard, tr := range shardTimeRanges.Iter() {
 			err := s.bootstrapShardSnapshots(
 				ns.Metadata, accumulator, shard, tr, blockSize,
-				mostRecentCompleteSnapshotByBlockShard, cache)
+				mostRecentCompleteSnapshotByBlockShard, cache, verifier)
 			if err != nil {
 				return bootstrap.NamespaceResults{}, err
 			}
@@ -361,10 +395,13 @@ func (s *commitLogSource) Read(
 	instrCtx.readCommitLogStarted()
 	if !s.commitLogResult.read {
 		var err error
-		s.commitLogResult, err = s.readCommitLog(namespaces, instrCtx.span)
+		s.commitLogResult, err = s.readCommitLog(namespaces, instrCtx.span, verifier)
 		if err != nil {
 			return bootstrap.NamespaceResults{}, err
 		}
+		if verifier != nil {
+			verifier.verify()
+		}
 	} else {
 		s.log.Debug("commit log already read in a previous pass, using previous result.")
 	}
@@ -399,7 +436,11 @@ func (s *commitLogSource) Read(
 	return bootstrapResult, nil
 }
 
-func (s *commitLogSource) readCommitLog(namespaces bootstrap.Namespaces, span opentracing.Span) (commitLogResult, error) {
+func (s *commitLogSource) readCommitLog(
+	namespaces bootstrap.Namespaces,
+	span opentracing.Span,
+	verifier *snapshotVerifier,
+) (commitLogResult, error) {
 	// Setup the series accumulator pipeline.
 	var (
 		numWorkers = s.opts.AccumulateConcurrency()
@@ -688,6 +729,11 @@ func (s *commitLogSource) readCommitLog(namespaces bootstrap.Namespaces, span op
 			}
 		}
 
+		if verifier != nil {
+			verifier.addCommitLogDatapoint(seriesEntry.namespace.namespaceID, shard,
+				seriesEntry.namespace.dataBlockSize, entry.Series.ID.Bytes(), entry.Datapoint)
+		}
+
 		// Distribute work.
 		// NB(r): In future we could batch a few points together before sending
 		// to a channel to alleviate lock contention/stress on the channels.
@@ -834,6 +880,7 @@ func (s *commitLogSource) bootstrapShardSnapshots(
 	blockSize time.Duration,
 	mostRecentCompleteSnapshotByBlockShard map[xtime.UnixNano]map[uint32]fs.FileSetFile,
 	cache bootstrap.Cache,
+	verifier *snapshotVerifier,
 ) error {
 	// NB(bodu): We use info files on disk to check if a snapshot should be loaded in as cold or warm.
 	// We do this instead of cross refing blockstarts and current time to handle the case of bootstrapping a
@@ -896,9 +943,14 @@ func (s *commitLogSource) bootstrapShardSnapshots(
 			if _, ok := shardBlockStartsOnDisk[blockStart]; ok {
 				writeType = series.ColdWrite
 			}
+			var verify *snapshotVerificationBlock
+			if verifier != nil {
+				verify = verifier.addSnapshotBlock(ns, shard, blockStart,
+					mostRecentCompleteSnapshotForShardBlock.CachedSnapshotTime)
+			}
 			if err := s.bootstrapShardBlockSnapshot(
 				ns, accumulator, shard, blockStart, blockSize,
-				mostRecentCompleteSnapshotForShardBlock, writeType); err != nil {
+				mostRecentCompleteSnapshotForShardBlock, writeType, verify); err != nil {
 				return err
 			}
 		}
@@ -915,6 +967,7 @@ func (s *commitLogSource) bootstrapShardBlockSnapshot(
 	blockSize time.Duration,
 	mostRecentCompleteSnapshot fs.FileSetFile,
 	writeType series.WriteType,
+	verify *snapshotVerificationBlock,
 ) error {
 	var (
 		bOpts      = s.opts.ResultOptions()
@@ -969,6 +1022,8 @@ func (s *commitLogSource) bootstrapShardBlockSnapshot(
 		blockStart:  blockStart,
 		blockSize:   blockSize,
 		nsCtx:       nsCtx,
+		iterPool:    blOpts.ReaderIteratorPool(),
+		verify:      verify,
 	}
 
 	errs, ctx := errgroup.WithContext(context.Background())
@@ -57,6 +57,14 @@ type Options interface {
 	// should return unfulfilled if it encounters corrupt commitlog files.
 	ReturnUnfulfilledForCorruptCommitLogFiles() bool
 
+	// SetSnapshotsVerify sets whether to verify the snapshots against the
+	// writes replayed from the commit log and report the discrepancies.
+	SetSnapshotsVerify(value bool) Options
+
+	// SnapshotsVerify returns whether to verify the snapshots against the
+	// writes replayed from the commit log and report the discrepancies.
+	SnapshotsVerify() bool
+
 	// SetRuntimeOptionsManagers sets the RuntimeOptionsManager.
 	SetRuntimeOptionsManager(value runtime.OptionsManager) Options
 
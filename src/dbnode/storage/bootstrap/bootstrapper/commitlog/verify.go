@@ -0,0 +1,333 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxLoggedSnapshotDiscrepancies is the max number of discrepancies
+	// between the snapshots and the commit log logged individually.
+	maxLoggedSnapshotDiscrepancies = 16
+
+	// maxSnapshotVerificationSeriesPerBlock is the max number of series of
+	// each shard block whose datapoints are verified, so that the datapoints
+	// held until the commit log is read are bounded.
+	maxSnapshotVerificationSeriesPerBlock = 1024
+
+	discrepancyMissingSeries    = "missing-series"
+	discrepancyMissingDatapoint = "missing-datapoint"
+	discrepancyValueMismatch    = "value-mismatch"
+)
+
+type snapshotVerificationBlockKey struct {
+	shard      uint32
+	blockStart xtime.UnixNano
+}
+
+type snapshotVerificationValues map[string]map[xtime.UnixNano]float64
+
+func (v snapshotVerificationValues) set(id string, dp ts.Datapoint) {
+	values, ok := v[id]
+	if !ok {
+		values = make(map[xtime.UnixNano]float64)
+		v[id] = values
+	}
+	values[dp.TimestampNanos] = dp.Value
+}
+
+// snapshotVerificationSample is a max heap of the hashes of the IDs of the
+// series sampled from a snapshot block, so the series with the largest hash
+// can be evicted when a series with a smaller hash is read.
+type snapshotVerificationSample []snapshotVerificationSeries
+
+type snapshotVerificationSeries struct {
+	hash uint64
+	id   string
+}
+
+func (s snapshotVerificationSample) Len() int           { return len(s) }
+func (s snapshotVerificationSample) Less(i, j int) bool { return s[i].hash > s[j].hash }
+func (s snapshotVerificationSample) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s *snapshotVerificationSample) Push(x interface{}) {
+	*s = append(*s, x.(snapshotVerificationSeries))
+}
+
+func (s *snapshotVerificationSample) Pop() interface{} {
+	old := *s
+	n := len(old)
+	series := old[n-1]
+	*s = old[:n-1]
+	return series
+}
+
+// snapshotVerificationBlock holds the datapoints of a sample of the series of
+// a shard block read from its most recent snapshot and replayed from the
+// commit log that were written before the snapshot was taken, so must be the
+// same in both. The series sampled are the ones with the smallest hashes of
+// their IDs, which bounds the datapoints held per block while sampling the
+// same series from the snapshot and the commit log.
+type snapshotVerificationBlock struct {
+	// cutoff is the time before which datapoints were written before the
+	// snapshot was taken, since writes of datapoints older than the buffer
+	// past at the time of the snapshot are rejected.
+	cutoff    xtime.UnixNano
+	maxSeries int
+	sample    snapshotVerificationSample
+	snapshot  snapshotVerificationValues
+	commitLog snapshotVerificationValues
+}
+
+func (b *snapshotVerificationBlock) addSnapshotDatapoint(id []byte, dp ts.Datapoint) {
+	if !dp.TimestampNanos.Before(b.cutoff) {
+		return
+	}
+	if values, ok := b.snapshot[string(id)]; ok {
+		values[dp.TimestampNanos] = dp.Value
+		return
+	}
+	hash := xxhash.Sum64(id)
+	if len(b.sample) >= b.maxSeries {
+		if hash >= b.sample[0].hash {
+			return
+		}
+		evicted := heap.Pop(&b.sample).(snapshotVerificationSeries)
+		delete(b.snapshot, evicted.id)
+	}
+	series := snapshotVerificationSeries{hash: hash, id: string(id)}
+	heap.Push(&b.sample, series)
+	b.snapshot.set(series.id, dp)
+}
+
+// sampled returns whether the series with the given ID hash is part of the
+// sample of the block.
+func (b *snapshotVerificationBlock) sampled(hash uint64) bool {
+	return len(b.sample) < b.maxSeries || hash <= b.sample[0].hash
+}
+
+type snapshotVerifierMetrics struct {
+	blocksVerified     tally.Counter
+	datapointsVerified tally.Counter
+	missingSeries      tally.Counter
+	missingDatapoints  tally.Counter
+	valueMismatches    tally.Counter
+}
+
+func newSnapshotVerifierMetrics(scope tally.Scope) snapshotVerifierMetrics {
+	scope = scope.SubScope("verify-snapshots")
+	discrepancies := func(discrepancyType string) tally.Counter {
+		return scope.Tagged(map[string]string{"type": discrepancyType}).Counter("discrepancies")
+	}
+	return snapshotVerifierMetrics{
+		blocksVerified:     scope.Counter("blocks-verified"),
+		datapointsVerified: scope.Counter("datapoints-verified"),
+		missingSeries:      discrepancies(discrepancyMissingSeries),
+		missingDatapoints:  discrepancies(discrepancyMissingDatapoint),
+		valueMismatches:    discrepancies(discrepancyValueMismatch),
+	}
+}
+
+// snapshotVerificationResult is the outcome of comparing the snapshots with
+// the commit log.
+type snapshotVerificationResult struct {
+	blocks            int
+	datapoints        int
+	missingSeries     int
+	missingDatapoints int
+	valueMismatches   int
+}
+
+func (r snapshotVerificationResult) discrepancies() int {
+	return r.missingSeries + r.missingDatapoints + r.valueMismatches
+}
+
+// snapshotVerifier compares the blocks read from the snapshot files with the
+// same blocks replayed from the commit log, which are independent sources of
+// the same writes, to detect snapshots missing writes or recording different
+// values after a crash. Only writes replayed from the commit log can be
+// verified since the commit logs covered by a snapshot may have been removed.
+type snapshotVerifier struct {
+	log               *zap.Logger
+	metrics           snapshotVerifierMetrics
+	maxSeriesPerBlock int
+	blocks            map[string]map[snapshotVerificationBlockKey]*snapshotVerificationBlock
+}
+
+func newSnapshotVerifier(scope tally.Scope, log *zap.Logger) *snapshotVerifier {
+	return &snapshotVerifier{
+		log:               log,
+		metrics:           newSnapshotVerifierMetrics(scope),
+		maxSeriesPerBlock: maxSnapshotVerificationSeriesPerBlock,
+		blocks:            make(map[string]map[snapshotVerificationBlockKey]*snapshotVerificationBlock),
+	}
+}
+
+// addSnapshotBlock registers a snapshot of a shard block to verify, it returns
+// nil if the snapshot can not be verified.
+func (v *snapshotVerifier) addSnapshotBlock(
+	ns namespace.Metadata,
+	shard uint32,
+	blockStart xtime.UnixNano,
+	snapshotTime xtime.UnixNano,
+) *snapshotVerificationBlock {
+	if ns.Options().ColdWritesEnabled() {
+		// Cold writes of datapoints of any age can be received after the
+		// snapshot was taken, so there is no cutoff to verify against.
+		return nil
+	}
+	nsID := ns.ID().String()
+	blocks, ok := v.blocks[nsID]
+	if !ok {
+		blocks = make(map[snapshotVerificationBlockKey]*snapshotVerificationBlock)
+		v.blocks[nsID] = blocks
+	}
+	block := &snapshotVerificationBlock{
+		cutoff:    snapshotTime.Add(-ns.Options().RetentionOptions().BufferPast()),
+		maxSeries: v.maxSeriesPerBlock,
+		snapshot:  make(snapshotVerificationValues),
+		commitLog: make(snapshotVerificationValues),
+	}
+	blocks[snapshotVerificationBlockKey{shard: shard, blockStart: blockStart}] = block
+	return block
+}
+
+// addCommitLogDatapoint records a datapoint replayed from the commit log if it
+// was written before the snapshot of its block was taken. Datapoints must be
+// added in the order they were written so the last write of a datapoint wins.
+func (v *snapshotVerifier) addCommitLogDatapoint(
+	nsID []byte,
+	shard uint32,
+	blockSize time.Duration,
+	id []byte,
+	dp ts.Datapoint,
+) {
+	blocks, ok := v.blocks[string(nsID)]
+	if !ok {
+		return
+	}
+	block, ok := blocks[snapshotVerificationBlockKey{
+		shard:      shard,
+		blockStart: dp.TimestampNanos.Truncate(blockSize),
+	}]
+	if !ok || !dp.TimestampNanos.Before(block.cutoff) {
+		return
+	}
+	if _, ok := block.commitLog[string(id)]; !ok && !block.sampled(xxhash.Sum64(id)) {
+		return
+	}
+	block.commitLog.set(string(id), dp)
+}
+
+// verify compares the datapoints replayed from the commit log with the
+// snapshots and reports the discrepancies.
+func (v *snapshotVerifier) verify() snapshotVerificationResult {
+	var (
+		result snapshotVerificationResult
+		logged int
+	)
+	logDiscrepancy := func(
+		discrepancyType string,
+		nsID string,
+		key snapshotVerificationBlockKey,
+		id string,
+		timestamp xtime.UnixNano,
+		fields ...zap.Field,
+	) {
+		if logged >= maxLoggedSnapshotDiscrepancies {
+			return
+		}
+		logged++
+		v.log.Error("snapshot verification discrepancy",
+			append([]zap.Field{
+				zap.String("type", discrepancyType),
+				zap.String("namespace", nsID),
+				zap.Uint32("shard", key.shard),
+				zap.Time("blockStart", key.blockStart.ToTime()),
+				zap.String("series", id),
+				zap.Time("timestamp", timestamp.ToTime()),
+			}, fields...)...)
+	}
+
+	for nsID, blocks := range v.blocks {
+		for key, block := range blocks {
+			result.blocks++
+			for id, commitLogValues := range block.commitLog {
+				result.datapoints += len(commitLogValues)
+				snapshotValues, ok := block.snapshot[id]
+				if !ok {
+					result.missingSeries++
+					for timestamp := range commitLogValues {
+						logDiscrepancy(discrepancyMissingSeries, nsID, key, id, timestamp)
+						break
+					}
+					continue
+				}
+				for timestamp, value := range commitLogValues {
+					snapshotValue, ok := snapshotValues[timestamp]
+					if !ok {
+						result.missingDatapoints++
+						logDiscrepancy(discrepancyMissingDatapoint, nsID, key, id, timestamp,
+							zap.Float64("commitLogValue", value))
+						continue
+					}
+					// NB: Compare the bits so that NaN values are equal.
+					if math.Float64bits(snapshotValue) != math.Float64bits(value) {
+						result.valueMismatches++
+						logDiscrepancy(discrepancyValueMismatch, nsID, key, id, timestamp,
+							zap.Float64("commitLogValue", value),
+							zap.Float64("snapshotValue", snapshotValue))
+					}
+				}
+			}
+		}
+	}
+
+	v.metrics.blocksVerified.Inc(int64(result.blocks))
+	v.metrics.datapointsVerified.Inc(int64(result.datapoints))
+	v.metrics.missingSeries.Inc(int64(result.missingSeries))
+	v.metrics.missingDatapoints.Inc(int64(result.missingDatapoints))
+	v.metrics.valueMismatches.Inc(int64(result.valueMismatches))
+
+	fields := []zap.Field{
+		zap.Int("blocks", result.blocks),
+		zap.Int("datapoints", result.datapoints),
+		zap.Int("missingSeries", result.missingSeries),
+		zap.Int("missingDatapoints", result.missingDatapoints),
+		zap.Int("valueMismatches", result.valueMismatches),
+	}
+	if result.discrepancies() > 0 {
+		v.log.Error("snapshot verification found discrepancies with the commit log", fields...)
+	} else {
+		v.log.Info("snapshot verification found no discrepancies with the commit log", fields...)
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commitlog
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+func TestSnapshotVerifierReportsDiscrepancies(t *testing.T) {
+	var (
+		scope        = tally.NewTestScope("", nil)
+		verifier     = newSnapshotVerifier(scope, zap.NewNop())
+		md           = testNsMetadata(t)
+		nsID         = md.ID().Bytes()
+		blockSize    = md.Options().RetentionOptions().BlockSize()
+		bufferPast   = md.Options().RetentionOptions().BufferPast()
+		blockStart   = xtime.Now().Truncate(blockSize).Add(-blockSize)
+		snapshotTime = blockStart.Add(blockSize / 2)
+		cutoff       = snapshotTime.Add(-bufferPast)
+		t1           = blockStart.Add(time.Second)
+		t2           = blockStart.Add(2 * time.Second)
+		t3           = blockStart.Add(3 * time.Second)
+	)
+
+	block := verifier.addSnapshotBlock(md, 0, blockStart, snapshotTime)
+	require.NotNil(t, block)
+	block.addSnapshotDatapoint([]byte("foo"), ts.Datapoint{TimestampNanos: t1, Value: 1})
+	block.addSnapshotDatapoint([]byte("foo"), ts.Datapoint{TimestampNanos: t2, Value: 2})
+	block.addSnapshotDatapoint([]byte("bar"), ts.Datapoint{TimestampNanos: t1, Value: 5})
+	// Datapoints that may have been written after the snapshot are not kept.
+	block.addSnapshotDatapoint([]byte("foo"), ts.Datapoint{TimestampNanos: cutoff, Value: 7})
+	require.Equal(t, 2, len(block.snapshot["foo"]))
+
+	addCommitLogDatapoint := func(nsID []byte, shard uint32, id string, timestamp xtime.UnixNano, value float64) {
+		verifier.addCommitLogDatapoint(nsID, shard, blockSize, []byte(id),
+			ts.Datapoint{TimestampNanos: timestamp, Value: value})
+	}
+	addCommitLogDatapoint(nsID, 0, "foo", t1, 1)
+	// The last write of a datapoint is compared.
+	addCommitLogDatapoint(nsID, 0, "foo", t2, 3)
+	addCommitLogDatapoint(nsID, 0, "foo", t2, 2)
+	addCommitLogDatapoint(nsID, 0, "foo", t3, 4)
+	addCommitLogDatapoint(nsID, 0, "bar", t1, 6)
+	addCommitLogDatapoint(nsID, 0, "baz", t1, 8)
+	// Datapoints that can not be verified are skipped.
+	addCommitLogDatapoint(nsID, 0, "foo", cutoff, 9)
+	addCommitLogDatapoint(nsID, 1, "foo", t1, 10)
+	addCommitLogDatapoint(nsID, 0, "foo", blockStart.Add(-blockSize), 11)
+	addCommitLogDatapoint([]byte("other"), 0, "foo", t1, 12)
+
+	require.Equal(t, snapshotVerificationResult{
+		blocks:            1,
+		datapoints:        5,
+		missingSeries:     1,
+		missingDatapoints: 1,
+		valueMismatches:   1,
+	}, verifier.verify())
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(5), counters["verify-snapshots.datapoints-verified+"].Value())
+	require.Equal(t, int64(1), counters["verify-snapshots.discrepancies+type=missing-series"].Value())
+	require.Equal(t, int64(1), counters["verify-snapshots.discrepancies+type=missing-datapoint"].Value())
+	require.Equal(t, int64(1), counters["verify-snapshots.discrepancies+type=value-mismatch"].Value())
+}
+
+func TestSnapshotVerifierSamplesSeries(t *testing.T) {
+	var (
+		verifier     = newSnapshotVerifier(tally.NoopScope, zap.NewNop())
+		md           = testNsMetadata(t)
+		nsID         = md.ID().Bytes()
+		blockSize    = md.Options().RetentionOptions().BlockSize()
+		blockStart   = xtime.Now().Truncate(blockSize).Add(-blockSize)
+		snapshotTime = blockStart.Add(blockSize / 2)
+		t1           = blockStart.Add(time.Second)
+		ids          = []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	)
+	verifier.maxSeriesPerBlock = 3
+
+	block := verifier.addSnapshotBlock(md, 0, blockStart, snapshotTime)
+	require.NotNil(t, block)
+	for _, id := range ids {
+		block.addSnapshotDatapoint([]byte(id), ts.Datapoint{TimestampNanos: t1, Value: 1})
+	}
+	// Only the series with the smallest ID hashes are kept.
+	require.Equal(t, 3, len(block.snapshot))
+	sort.Slice(ids, func(i, j int) bool {
+		return xxhash.Sum64String(ids[i]) < xxhash.Sum64String(ids[j])
+	})
+	for _, id := range ids[:3] {
+		require.Contains(t, block.snapshot, id)
+	}
+
+	// The same series are sampled from the commit log, so the series missing
+	// from the snapshots are still detected.
+	missing := "missing"
+	for i := 0; !block.sampled(xxhash.Sum64String(missing)); i++ {
+		missing = fmt.Sprintf("missing%d", i)
+	}
+	for _, id := range append(ids, missing) {
+		verifier.addCommitLogDatapoint(nsID, 0, blockSize, []byte(id),
+			ts.Datapoint{TimestampNanos: t1, Value: 1})
+	}
+	require.Equal(t, snapshotVerificationResult{
+		blocks:        1,
+		datapoints:    4,
+		missingSeries: 1,
+	}, verifier.verify())
+}
+
+func TestSnapshotVerifierSkipsColdWritesNamespaces(t *testing.T) {
+	md, err := namespace.NewMetadata(testNamespaceID,
+		namespace.NewOptions().SetColdWritesEnabled(true))
+	require.NoError(t, err)
+
+	verifier := newSnapshotVerifier(tally.NoopScope, zap.NewNop())
+	now := xtime.Now()
+	require.Nil(t, verifier.addSnapshotBlock(md, 0, now, now))
+	require.Equal(t, snapshotVerificationResult{}, verifier.verify())
+}
@@ -44,13 +44,14 @@ type worker struct {
 // Migrator is responsible for migrating data filesets based on version information in
 // the info files.
 type Migrator struct {
-	migrationTaskFn      MigrationTaskFn
-	infoFilesByNamespace bootstrap.InfoFilesByNamespace
-	migrationOpts        migration.Options
-	fsOpts               fs.Options
-	instrumentOpts       instrument.Options
-	storageOpts          storage.Options
-	log                  *zap.Logger
+	migrationTaskFn          MigrationTaskFn
+	blockSizeMigrationTaskFn BlockSizeMigrationTaskFn
+	infoFilesByNamespace     bootstrap.InfoFilesByNamespace
+	migrationOpts            migration.Options
+	fsOpts                   fs.Options
+	instrumentOpts           instrument.Options
+	storageOpts              storage.Options
+	log                      *zap.Logger
 }
 
 // NewMigrator creates a new Migrator.
@@ -59,13 +60,14 @@ func NewMigrator(opts Options) (Migrator, error) {
 		return Migrator{}, err
 	}
 	return Migrator{
-		migrationTaskFn:      opts.MigrationTaskFn(),
-		infoFilesByNamespace: opts.InfoFilesByNamespace(),
-		migrationOpts:        opts.MigrationOptions(),
-		fsOpts:               opts.FilesystemOptions(),
-		instrumentOpts:       opts.InstrumentOptions(),
-		storageOpts:          opts.StorageOptions(),
-		log:                  opts.InstrumentOptions().Logger(),
+		migrationTaskFn:          opts.MigrationTaskFn(),
+		blockSizeMigrationTaskFn: opts.BlockSizeMigrationTaskFn(),
+		infoFilesByNamespace:     opts.InfoFilesByNamespace(),
+		migrationOpts:            opts.MigrationOptions(),
+		fsOpts:                   opts.FilesystemOptions(),
+		instrumentOpts:           opts.InstrumentOptions(),
+		storageOpts:              opts.StorageOptions(),
+		log:                      opts.InstrumentOptions().Logger(),
 	}, nil
 }
 
@@ -78,6 +80,23 @@ type migrationCandidate struct {
 	shard          uint32
 }
 
+// blockSizeMigrationCandidate is the struct we generate when we find a shard with filesets
+// written with a block size other than the current block size of the namespace.
+type blockSizeMigrationCandidate struct {
+	newTaskFn       migration.NewBlockSizeTaskFn
+	infoFileResults []fs.ReadInfoFileResult
+	metadata        namespace.Metadata
+	shard           uint32
+}
+
+// completedBlockSizeMigration is the set of info files of a shard after its filesets
+// have been re-chunked.
+type completedBlockSizeMigration struct {
+	metadata               namespace.Metadata
+	shard                  uint32
+	updatedInfoFileResults []fs.ReadInfoFileResult
+}
+
 // mergeKey is the unique set of data that identifies an ReadInfoFileResult.
 type mergeKey struct {
 	metadata   namespace.Metadata
@@ -98,6 +117,14 @@ func (m *Migrator) Run(ctx context.Context) error {
 	ctx, span, _ := ctx.StartSampledTraceSpan(tracepoint.BootstrapperFilesystemSourceMigrator)
 	defer span.Finish()
 
+	// Re-chunk filesets to the current block size of their namespace first so that
+	// any subsequent migrations operate on filesets of the expected block size.
+	if m.migrationOpts.MigrateBlockSize() {
+		if err := m.runBlockSizeMigrations(); err != nil {
+			return err
+		}
+	}
+
 	// Find candidates
 	candidates := m.findMigrationCandidates()
 	if len(candidates) == 0 {
@@ -178,6 +205,111 @@ func (m *Migrator) Run(ctx context.Context) error {
 	return nil
 }
 
+func (m *Migrator) runBlockSizeMigrations() error {
+	candidates := m.findBlockSizeMigrationCandidates()
+	if len(candidates) == 0 {
+		m.log.Debug("no filesets to re-chunk to namespace block size.")
+		return nil
+	}
+
+	m.log.Info("starting fileset block size migration", zap.Int("shards", len(candidates)))
+
+	nowFn := m.fsOpts.ClockOptions().NowFn()
+	begin := nowFn()
+
+	numWorkers := m.migrationOpts.Concurrency()
+	if numWorkers > len(candidates) {
+		numWorkers = len(candidates)
+	}
+
+	baseOpts := migration.NewTaskOptions().
+		SetFilesystemOptions(m.fsOpts).
+		SetStorageOptions(m.storageOpts)
+
+	var (
+		wg           sync.WaitGroup
+		candidatesCh = make(chan blockSizeMigrationCandidate, len(candidates))
+
+		completedMigrationsLock sync.Mutex
+		completedMigrations     = make([]completedBlockSizeMigration, 0, len(candidates))
+	)
+	for _, candidate := range candidates {
+		candidatesCh <- candidate
+	}
+	close(candidatesCh)
+
+	for i := 0; i < numWorkers; i++ {
+		// Give each worker their own persist manager so that we can write files concurrently.
+		pm, err := fs.NewPersistManager(m.fsOpts)
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for candidate := range candidatesCh {
+				task, err := candidate.newTaskFn(baseOpts.
+					SetInfoFileResults(candidate.infoFileResults).
+					SetShard(candidate.shard).
+					SetNamespaceMetadata(candidate.metadata).
+					SetPersistManager(pm))
+				if err != nil {
+					m.log.Error("error creating block size migration task", zap.Error(err))
+					continue
+				}
+				// NB: Block size migration tasks only remove existing filesets once all re-chunked
+				// filesets have been written so a failure leaves the data of the shard readable.
+				updated, err := task.Run()
+				if err != nil {
+					m.log.Error("error running block size migration task",
+						zap.Stringer("namespace", candidate.metadata.ID()),
+						zap.Uint32("shard", candidate.shard),
+						zap.Error(err))
+					continue
+				}
+
+				completedMigrationsLock.Lock()
+				completedMigrations = append(completedMigrations, completedBlockSizeMigration{
+					metadata:               candidate.metadata,
+					shard:                  candidate.shard,
+					updatedInfoFileResults: updated,
+				})
+				completedMigrationsLock.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, completed := range completedMigrations {
+		m.infoFilesByNamespace[completed.metadata][completed.shard] = completed.updatedInfoFileResults
+	}
+
+	m.log.Info("fileset block size migration finished", zap.Duration("took", nowFn().Sub(begin)))
+
+	return nil
+}
+
+func (m *Migrator) findBlockSizeMigrationCandidates() []blockSizeMigrationCandidate {
+	var candidates []blockSizeMigrationCandidate
+	for md, resultsByShard := range m.infoFilesByNamespace {
+		for shard, results := range resultsByShard {
+			newTaskFn, shouldMigrate := m.blockSizeMigrationTaskFn(md, results)
+			if shouldMigrate {
+				candidates = append(candidates, blockSizeMigrationCandidate{
+					newTaskFn:       newTaskFn,
+					infoFileResults: results,
+					metadata:        md,
+					shard:           shard,
+				})
+			}
+		}
+	}
+
+	return candidates
+}
+
 func (m *Migrator) findMigrationCandidates() []migrationCandidate {
 	maxCapacity := 0
 	for _, resultsByShard := range m.infoFilesByNamespace {
@@ -85,6 +85,52 @@ func TestMigratorRun(t *testing.T) {
 	}
 }
 
+func TestMigratorRunBlockSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testMigratorOptions(ctrl)
+
+	md, err := namespace.NewMetadata(ident.StringID("foo"), namespace.NewOptions())
+	require.NoError(t, err)
+
+	// Shard 1 needs to be re-chunked while shard 2 does not.
+	infoFilesByNamespace := bootstrap.InfoFilesByNamespace{
+		md: {
+			1: {testInfoFileWithVolumeIndex(0), testInfoFileWithVolumeIndex(1)},
+			2: {testInfoFileWithVolumeIndex(1)},
+		},
+	}
+
+	opts = opts.
+		SetMigrationTaskFn(func(result fs.ReadInfoFileResult) (migration.NewTaskFn, bool) {
+			return nil, false
+		}).
+		SetBlockSizeMigrationTaskFn(func(
+			md namespace.Metadata,
+			results []fs.ReadInfoFileResult,
+		) (migration.NewBlockSizeTaskFn, bool) {
+			for _, result := range results {
+				if result.Info.VolumeIndex == 0 {
+					return newTestBlockSizeTask, true
+				}
+			}
+			return nil, false
+		}).
+		SetInfoFilesByNamespace(infoFilesByNamespace).
+		SetMigrationOptions(migration.NewOptions().SetMigrateBlockSize(true))
+
+	migrator, err := NewMigrator(opts)
+	require.NoError(t, err)
+
+	err = migrator.Run(context.NewBackground())
+	require.NoError(t, err)
+
+	// Re-chunked shards have their info files replaced.
+	require.Equal(t, []fs.ReadInfoFileResult{testInfoFileWithVolumeIndex(2)}, infoFilesByNamespace[md][1])
+	require.Equal(t, []fs.ReadInfoFileResult{testInfoFileWithVolumeIndex(1)}, infoFilesByNamespace[md][2])
+}
+
 func testMigratorOptions(ctrl *gomock.Controller) Options {
 	mockOpts := storage.NewMockOptions(ctrl)
 	mockOpts.EXPECT().Validate().AnyTimes()
@@ -110,6 +156,19 @@ func (t *testTask) Run() (fs.ReadInfoFileResult, error) {
 	return result, nil
 }
 
+type testBlockSizeTask struct {
+	opts migration.TaskOptions
+}
+
+func newTestBlockSizeTask(opts migration.TaskOptions) (migration.BlockSizeTask, error) {
+	return &testBlockSizeTask{opts: opts}, nil
+}
+
+func (t *testBlockSizeTask) Run() ([]fs.ReadInfoFileResult, error) {
+	results := t.opts.InfoFileResults()
+	return []fs.ReadInfoFileResult{testInfoFileWithVolumeIndex(len(results))}, nil
+}
+
 func testInfoFileWithVolumeIndex(volumeIndex int) fs.ReadInfoFileResult {
 	return fs.ReadInfoFileResult{Info: schema.IndexInfo{VolumeIndex: volumeIndex}}
 }
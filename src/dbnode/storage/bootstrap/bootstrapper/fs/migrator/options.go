@@ -31,28 +31,31 @@ import (
 )
 
 var (
-	errMigrationTaskFnNotSet      = errors.New("migrationTaskFn not set")
-	errInfoFilesByNamespaceNotSet = errors.New("infoFilesByNamespaces not set")
-	errMigrationOptsNotSet        = errors.New("migrationOpts not set")
-	errInstrumentOptsNotSet       = errors.New("instrumentOpts not set")
-	errStorageOptsNotSet          = errors.New("storageOpts not set")
-	errFilesystemOptsNotSet       = errors.New("filesystemOpts not set")
+	errMigrationTaskFnNotSet          = errors.New("migrationTaskFn not set")
+	errBlockSizeMigrationTaskFnNotSet = errors.New("blockSizeMigrationTaskFn not set")
+	errInfoFilesByNamespaceNotSet     = errors.New("infoFilesByNamespaces not set")
+	errMigrationOptsNotSet            = errors.New("migrationOpts not set")
+	errInstrumentOptsNotSet           = errors.New("instrumentOpts not set")
+	errStorageOptsNotSet              = errors.New("storageOpts not set")
+	errFilesystemOptsNotSet           = errors.New("filesystemOpts not set")
 )
 
 type options struct {
-	migrationTaskFn      MigrationTaskFn
-	infoFilesByNamespace bootstrap.InfoFilesByNamespace
-	migrationOpts        migration.Options
-	fsOpts               fs.Options
-	instrumentOpts       instrument.Options
-	storageOpts          storage.Options
+	migrationTaskFn          MigrationTaskFn
+	blockSizeMigrationTaskFn BlockSizeMigrationTaskFn
+	infoFilesByNamespace     bootstrap.InfoFilesByNamespace
+	migrationOpts            migration.Options
+	fsOpts                   fs.Options
+	instrumentOpts           instrument.Options
+	storageOpts              storage.Options
 }
 
 // NewOptions return new migration opts
 func NewOptions() Options {
 	return &options{
-		migrationOpts:  migration.NewOptions(),
-		instrumentOpts: instrument.NewOptions(),
+		blockSizeMigrationTaskFn: migration.BlockSizeMigrationTask,
+		migrationOpts:            migration.NewOptions(),
+		instrumentOpts:           instrument.NewOptions(),
 	}
 }
 
@@ -66,6 +69,9 @@ func (o *options) Validate() error {
 	if o.migrationTaskFn == nil {
 		return errMigrationTaskFnNotSet
 	}
+	if o.blockSizeMigrationTaskFn == nil {
+		return errBlockSizeMigrationTaskFnNotSet
+	}
 	if o.infoFilesByNamespace == nil {
 		return errInfoFilesByNamespaceNotSet
 	}
@@ -94,6 +100,16 @@ func (o *options) MigrationTaskFn() MigrationTaskFn {
 	return o.migrationTaskFn
 }
 
+func (o *options) SetBlockSizeMigrationTaskFn(value BlockSizeMigrationTaskFn) Options {
+	opts := *o
+	opts.blockSizeMigrationTaskFn = value
+	return &opts
+}
+
+func (o *options) BlockSizeMigrationTaskFn() BlockSizeMigrationTaskFn {
+	return o.blockSizeMigrationTaskFn
+}
+
 func (o *options) SetInfoFilesByNamespace(value bootstrap.InfoFilesByNamespace) Options {
 	opts := *o
 	opts.infoFilesByNamespace = value
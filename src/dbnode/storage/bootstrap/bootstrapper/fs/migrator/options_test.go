@@ -58,6 +58,17 @@ func TestOptionsValidateMigrationTaskFn(t *testing.T) {
 	require.Error(t, opts.Validate())
 }
 
+func TestOptionsValidateBlockSizeMigrationTaskFn(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newTestOptions(ctrl)
+	require.NoError(t, opts.Validate())
+
+	opts = opts.SetBlockSizeMigrationTaskFn(nil)
+	require.Error(t, opts.Validate())
+}
+
 func TestOptionsValidateInfoFilesByNamespace(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -21,6 +21,7 @@
 package migrator
 
 import (
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/migration"
 	"github.com/m3db/m3/src/dbnode/storage"
@@ -31,6 +32,13 @@ import (
 // MigrationTaskFn returns a fileset migration function and a boolean indicating if migration is necessary.
 type MigrationTaskFn func(result fs.ReadInfoFileResult) (migration.NewTaskFn, bool)
 
+// BlockSizeMigrationTaskFn returns a block size migration function for the filesets of a shard and a boolean
+// indicating if migration is necessary.
+type BlockSizeMigrationTaskFn func(
+	md namespace.Metadata,
+	results []fs.ReadInfoFileResult,
+) (migration.NewBlockSizeTaskFn, bool)
+
 // Options represents the options for the migrator.
 type Options interface {
 	// Validate checks that options are valid.
@@ -42,6 +50,14 @@ type Options interface {
 	// MigrationTaskFn gets the function for determining if the migrator should migrate a fileset.
 	MigrationTaskFn() MigrationTaskFn
 
+	// SetBlockSizeMigrationTaskFn sets the function for determining if the migrator should re-chunk
+	// the filesets of a shard to the current block size of the namespace.
+	SetBlockSizeMigrationTaskFn(value BlockSizeMigrationTaskFn) Options
+
+	// BlockSizeMigrationTaskFn gets the function for determining if the migrator should re-chunk
+	// the filesets of a shard to the current block size of the namespace.
+	BlockSizeMigrationTaskFn() BlockSizeMigrationTaskFn
+
 	// SetInfoFilesByNamespaces sets the info file results to operate on keyed by namespace.
 	SetInfoFilesByNamespace(value bootstrap.InfoFilesByNamespace) Options
 
@@ -210,15 +210,23 @@ func (s *fileSystemSource) Read(
 }
 
 func (s *fileSystemSource) runMigrations(ctx context.Context, infoFilesByNamespace bootstrap.InfoFilesByNamespace) {
-	// Only one migration for now, so just short circuit entirely if not enabled
-	if s.opts.MigrationOptions().TargetMigrationVersion() != migration.MigrationVersion_1_1 {
-		return
+	// Only one version migration for now, so just short circuit entirely if neither it nor
+	// the block size migration are enabled.
+	migrationOpts := s.opts.MigrationOptions()
+	migrationTaskFn := migration.MigrationTask
+	if migrationOpts.TargetMigrationVersion() != migration.MigrationVersion_1_1 {
+		if !migrationOpts.MigrateBlockSize() {
+			return
+		}
+		migrationTaskFn = func(fs.ReadInfoFileResult) (migration.NewTaskFn, bool) {
+			return nil, false
+		}
 	}
 
 	migrator, err := migrator.NewMigrator(migrator.NewOptions().
-		SetMigrationTaskFn(migration.MigrationTask).
+		SetMigrationTaskFn(migrationTaskFn).
 		SetInfoFilesByNamespace(infoFilesByNamespace).
-		SetMigrationOptions(s.opts.MigrationOptions()).
+		SetMigrationOptions(migrationOpts).
 		SetFilesystemOptions(s.fsopts).
 		SetInstrumentOptions(s.opts.InstrumentOptions()).
 		SetStorageOptions(s.opts.StorageOptions()))
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
@@ -54,8 +55,19 @@ type snapshotFilesFn func(
 
 type deleteFilesFn func(files []string) error
 
+type tierDataFilesFn func(
+	opts fs.Options, namespace ident.ID, shard uint32,
+	blockSize time.Duration, before xtime.UnixNano,
+) error
+
 type deleteInactiveDirectoriesFn func(parentDirPath string, activeDirNames []string) error
 
+type dataFilesFn func(
+	filePathPrefix string, namespace ident.ID, shard uint32,
+) (fs.FileSetFilesSlice, error)
+
+type diskUsageFn func(path string) (fs.DiskUsageStats, error)
+
 // Narrow interface so as not to expose all the functionality of the commitlog
 // to the cleanup manager.
 type activeCommitlogs interface {
@@ -78,6 +90,9 @@ type cleanupManager struct {
 
 	deleteFilesFn               deleteFilesFn
 	deleteInactiveDirectoriesFn deleteInactiveDirectoriesFn
+	tierDataFilesFn             tierDataFilesFn
+	dataFilesFn                 dataFilesFn
+	diskUsageFn                 diskUsageFn
 	warmFlushCleanupInProgress  bool
 	coldFlushCleanupInProgress  bool
 	metrics                     cleanupManagerMetrics
@@ -93,12 +108,17 @@ type cleanupManagerMetrics struct {
 	deletedCommitlogFile        tally.Counter
 	deletedSnapshotFile         tally.Counter
 	deletedSnapshotMetadataFile tally.Counter
+	diskUsage                   tally.Gauge
+	diskUsageEvictions          tally.Counter
+	diskUsageEvictedFileSets    tally.Counter
+	diskUsageFloorReached       tally.Counter
 }
 
 func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 	clScope := scope.SubScope("commitlog")
 	sScope := scope.SubScope("snapshot")
 	smScope := scope.SubScope("snapshot-metadata")
+	duScope := scope.SubScope("disk-usage-eviction")
 	return cleanupManagerMetrics{
 		warmFlushCleanupStatus:      scope.Gauge("warm-flush-cleanup"),
 		coldFlushCleanupStatus:      scope.Gauge("cold-flush-cleanup"),
@@ -108,6 +128,10 @@ func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 		deletedCommitlogFile:        clScope.Counter("deleted"),
 		deletedSnapshotFile:         sScope.Counter("deleted"),
 		deletedSnapshotMetadataFile: smScope.Counter("deleted"),
+		diskUsage:                   duScope.Gauge("disk-usage"),
+		diskUsageEvictions:          duScope.Counter("triggered"),
+		diskUsageEvictedFileSets:    duScope.Counter("evicted-filesets"),
+		diskUsageFloorReached:       duScope.Counter("floor-reached"),
 	}
 }
 
@@ -128,8 +152,11 @@ func newCleanupManager(
 		commitLogFilesFn:            commitlog.Files,
 		snapshotMetadataFilesFn:     fs.SortedSnapshotMetadataFiles,
 		snapshotFilesFn:             fs.SnapshotFiles,
-		deleteFilesFn:               fs.DeleteFiles,
+		deleteFilesFn:               opts.FileSetPins().DeleteFiles,
 		deleteInactiveDirectoriesFn: fs.DeleteInactiveDirectories,
+		tierDataFilesFn:             fs.TierDataFiles,
+		dataFilesFn:                 fs.DataFiles,
+		diskUsageFn:                 fs.DiskUsage,
 		metrics:                     newCleanupManagerMetrics(scope),
 		logger:                      opts.InstrumentOptions().Logger(),
 	}
@@ -212,6 +239,16 @@ func (m *cleanupManager) ColdFlushCleanup(t xtime.UnixNano) error {
 			"encountered errors when deleting inactive data files for %v: %v", t, err))
 	}
 
+	if err := m.tierDataFiles(t, namespaces); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when tiering data files for %v: %v", t, err))
+	}
+
+	if err := m.evictDataFilesForDiskUsage(t, namespaces); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when evicting data files for disk usage for %v: %v", t, err))
+	}
+
 	return multiErr.FinalError()
 }
 
@@ -292,6 +329,162 @@ func (m *cleanupManager) cleanupDataFiles(t xtime.UnixNano, namespaces []databas
 	return multiErr.FinalError()
 }
 
+// tierDataFiles evicts data filesets of blocks older than the tiered storage
+// threshold to the object store, it runs after expired and compacted filesets
+// have been cleaned up so that their objects are removed in the same pass.
+func (m *cleanupManager) tierDataFiles(t xtime.UnixNano, namespaces []databaseNamespace) error {
+	fsOpts := m.opts.CommitLogOptions().FilesystemOptions()
+	tieredOpts := fsOpts.TieredStorageOptions()
+	if tieredOpts.ObjectStore == nil {
+		return nil
+	}
+
+	before := t.Add(-tieredOpts.EvictAfter)
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		blockSize := n.Options().RetentionOptions().BlockSize()
+		for _, shard := range n.OwnedShards() {
+			if !shard.IsBootstrapped() {
+				continue
+			}
+			if err := m.tierDataFilesFn(fsOpts, n.ID(), shard.ID(), blockSize, before); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+	}
+	return multiErr.FinalError()
+}
+
+type evictableBlock struct {
+	blockStart xtime.UnixNano
+	blockEnd   xtime.UnixNano
+	namespace  databaseNamespace
+	shard      databaseShard
+}
+
+// evictDataFilesForDiskUsage evicts the data filesets of the oldest blocks when
+// disk usage is above the high watermark until it drops below the low watermark,
+// so that a node running out of disk loses its oldest data rather than crashing.
+// Blocks are evicted through their shards, which stop reading them and close the
+// seekers holding their files open, and the index blocks they cover are dropped.
+// Usage is tracked from the bytes freed since the disk usage reported by the
+// filesystem only drops once the seekers are closed. Data filesets of blocks
+// within the retention floor are never evicted. It runs last so that only disk
+// still used after regular cleanup and tiering counts.
+func (m *cleanupManager) evictDataFilesForDiskUsage(
+	t xtime.UnixNano, namespaces []databaseNamespace,
+) error {
+	evictionOpts := m.opts.CommitLogOptions().FilesystemOptions().DiskUsageEvictionOptions()
+	if !evictionOpts.Enabled() {
+		return nil
+	}
+
+	stats, err := m.diskUsageFn(m.filePathPrefix)
+	if err != nil {
+		return err
+	}
+	usage := stats.Usage()
+	m.metrics.diskUsage.Update(usage)
+	if usage < evictionOpts.HighWatermark {
+		return nil
+	}
+
+	m.metrics.diskUsageEvictions.Inc(1)
+	m.logger.Warn("disk usage above high watermark, evicting oldest data filesets",
+		zap.Float64("diskUsage", usage),
+		zap.Float64("highWatermark", evictionOpts.HighWatermark),
+		zap.Float64("lowWatermark", evictionOpts.LowWatermark),
+		zap.Duration("retentionFloor", evictionOpts.RetentionFloor))
+
+	var (
+		floor    = t.Add(-evictionOpts.RetentionFloor)
+		blocks   []evictableBlock
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, n := range namespaces {
+		if !n.Options().CleanupEnabled() {
+			continue
+		}
+		blockSize := n.Options().RetentionOptions().BlockSize()
+		for _, shard := range n.OwnedShards() {
+			if !shard.IsBootstrapped() {
+				continue
+			}
+			files, err := m.dataFilesFn(m.filePathPrefix, n.ID(), shard.ID())
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			for _, file := range files {
+				blockEnd := file.ID.BlockStart.Add(blockSize)
+				if blockEnd.After(floor) {
+					continue
+				}
+				blocks = append(blocks, evictableBlock{
+					blockStart: file.ID.BlockStart,
+					blockEnd:   blockEnd,
+					namespace:  n,
+					shard:      shard,
+				})
+			}
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].blockStart.Before(blocks[j].blockStart)
+	})
+
+	for len(blocks) > 0 && usage >= evictionOpts.LowWatermark {
+		// Evict the oldest block of all namespaces and shards together so
+		// that shards keep the same retention.
+		var (
+			blockStart = blocks[0].blockStart
+			evicted    int
+			freed      int64
+			i          int
+		)
+		for ; i < len(blocks) && blocks[i].blockStart.Equal(blockStart); i++ {
+			block := blocks[i]
+			// A namespace may have several volumes of the same block.
+			if i > 0 && blocks[i-1].blockStart.Equal(blockStart) &&
+				blocks[i-1].shard == block.shard {
+				continue
+			}
+			bytes, err := block.shard.EvictDataFileSetsBefore(block.blockEnd)
+			freed += bytes
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			evicted++
+			if index, err := block.namespace.Index(); err == nil {
+				index.EvictBlocksBefore(block.blockEnd)
+			}
+		}
+		blocks = blocks[i:]
+		m.metrics.diskUsageEvictedFileSets.Inc(int64(evicted))
+
+		stats.UsedBytes -= freed
+		usage = stats.Usage()
+		m.metrics.diskUsage.Update(usage)
+		m.logger.Warn("evicted data filesets for disk usage",
+			zap.Time("blockStart", blockStart.ToTime()),
+			zap.Int("evicted", evicted),
+			zap.Int64("freedBytes", freed),
+			zap.Float64("diskUsage", usage))
+	}
+
+	if usage >= evictionOpts.LowWatermark {
+		m.metrics.diskUsageFloorReached.Inc(1)
+		m.logger.Error("disk usage above low watermark with no data filesets "+
+			"left to evict outside the retention floor",
+			zap.Float64("diskUsage", usage),
+			zap.Float64("lowWatermark", evictionOpts.LowWatermark),
+			zap.Duration("retentionFloor", evictionOpts.RetentionFloor))
+	}
+
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(
 	t xtime.UnixNano, namespaces []databaseNamespace,
 ) error {
@@ -537,6 +537,182 @@ func TestDeleteInactiveDataAndSnapshotFileSetFiles(t *testing.T) {
 	}
 }
 
+type tierDataFilesCall struct {
+	namespace string
+	shard     uint32
+	blockSize time.Duration
+	before    xtime.UnixNano
+}
+
+func TestCleanupManagerTiersDataFiles(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor()
+
+	nsOpts := namespaceOptions.
+		SetCleanupEnabled(false)
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	shardNotBootstrapped := NewMockdatabaseShard(ctrl)
+	shardNotBootstrapped.EXPECT().ID().Return(uint32(1)).AnyTimes()
+	shardNotBootstrapped.EXPECT().IsBootstrapped().Return(false).AnyTimes()
+	ns.EXPECT().OwnedShards().Return([]databaseShard{shard, shardNotBootstrapped}).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	db.EXPECT().OwnedNamespaces().Return(namespaces, nil).AnyTimes()
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), tally.NoopScope).(*cleanupManager)
+
+	var calls []tierDataFilesCall
+	mgr.tierDataFilesFn = func(
+		opts fs.Options, namespace ident.ID, shard uint32,
+		blockSize time.Duration, before xtime.UnixNano,
+	) error {
+		calls = append(calls, tierDataFilesCall{
+			namespace: namespace.String(),
+			shard:     shard,
+			blockSize: blockSize,
+			before:    before,
+		})
+		return nil
+	}
+
+	// Tiering is disabled without an object store.
+	require.NoError(t, cleanup(mgr, ts))
+	require.Empty(t, calls)
+
+	fsOpts := mgr.opts.CommitLogOptions().FilesystemOptions().
+		SetTieredStorageOptions(fs.TieredStorageOptions{
+			ObjectStore: fs.NewDirectoryObjectStore("objects", 0666, 0755),
+			EvictAfter:  24 * time.Hour,
+		})
+	mgr.opts = mgr.opts.SetCommitLogOptions(
+		mgr.opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	require.NoError(t, cleanup(mgr, ts))
+	require.Equal(t, []tierDataFilesCall{
+		{
+			namespace: "nsID",
+			shard:     0,
+			blockSize: nsOpts.RetentionOptions().BlockSize(),
+			before:    ts.Add(-24 * time.Hour),
+		},
+	}, calls)
+}
+
+func TestCleanupManagerEvictsDataFilesForDiskUsage(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor()
+
+	nsOpts := namespaceOptions
+	blockSize := nsOpts.RetentionOptions().BlockSize()
+	blockEnd := func(blocksAgo int) xtime.UnixNano {
+		return ts.Truncate(blockSize).Add(-time.Duration(blocksAgo-1) * blockSize)
+	}
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+	idx := NewMockNamespaceIndex(ctrl)
+	ns.EXPECT().Index().Return(idx, nil).AnyTimes()
+
+	var (
+		shards        = make([]databaseShard, 0, 2)
+		evicted       []string
+		freedPerShard int64 = 100
+	)
+	for i := 0; i < 2; i++ {
+		i := i
+		shard := NewMockdatabaseShard(ctrl)
+		shard.EXPECT().ID().Return(uint32(i)).AnyTimes()
+		shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+		shard.EXPECT().EvictDataFileSetsBefore(gomock.Any()).DoAndReturn(
+			func(blockStart xtime.UnixNano) (int64, error) {
+				evicted = append(evicted, fmt.Sprintf("%d-%d", i, blockStart))
+				return freedPerShard, nil
+			}).AnyTimes()
+		shards = append(shards, shard)
+	}
+	ns.EXPECT().OwnedShards().Return(shards).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	scope := tally.NewTestScope("", nil)
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), scope).(*cleanupManager)
+
+	fsOpts := mgr.opts.CommitLogOptions().FilesystemOptions().
+		SetDiskUsageEvictionOptions(fs.DiskUsageEvictionOptions{
+			HighWatermark:  0.9,
+			LowWatermark:   0.8,
+			RetentionFloor: 2 * blockSize,
+		})
+	mgr.opts = mgr.opts.SetCommitLogOptions(
+		mgr.opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+
+	// Each shard has filesets for the four most recent blocks, the two most
+	// recent of which are within the retention floor.
+	mgr.dataFilesFn = func(
+		filePathPrefix string, namespace ident.ID, shard uint32,
+	) (fs.FileSetFilesSlice, error) {
+		var files fs.FileSetFilesSlice
+		for i := 4; i > 0; i-- {
+			blockStart := ts.Truncate(blockSize).Add(-time.Duration(i) * blockSize)
+			files = append(files, fs.FileSetFile{
+				ID: fs.FileSetFileIdentifier{
+					Namespace:  namespace,
+					BlockStart: blockStart,
+					Shard:      shard,
+				},
+			})
+		}
+		return files, nil
+	}
+	evictedBefore := func(shard uint32, blocksAgo int) string {
+		return fmt.Sprintf("%d-%d", shard, blockEnd(blocksAgo))
+	}
+
+	// Nothing is evicted below the high watermark.
+	mgr.diskUsageFn = func(string) (fs.DiskUsageStats, error) {
+		return fs.DiskUsageStats{UsedBytes: 850, TotalBytes: 1000}, nil
+	}
+	require.NoError(t, mgr.evictDataFilesForDiskUsage(ts, namespaces))
+	require.Empty(t, evicted)
+
+	// The oldest block is evicted from all shards and the index until the
+	// bytes freed bring usage below the low watermark.
+	mgr.diskUsageFn = func(string) (fs.DiskUsageStats, error) {
+		return fs.DiskUsageStats{UsedBytes: 950, TotalBytes: 1000}, nil
+	}
+	idx.EXPECT().EvictBlocksBefore(blockEnd(4)).Times(2)
+	require.NoError(t, mgr.evictDataFilesForDiskUsage(ts, namespaces))
+	require.Equal(t, []string{evictedBefore(0, 4), evictedBefore(1, 4)}, evicted)
+
+	// Data within the retention floor is never evicted.
+	evicted = nil
+	freedPerShard = 50
+	mgr.diskUsageFn = func(string) (fs.DiskUsageStats, error) {
+		return fs.DiskUsageStats{UsedBytes: 1000, TotalBytes: 1000}, nil
+	}
+	idx.EXPECT().EvictBlocksBefore(blockEnd(4)).Times(2)
+	idx.EXPECT().EvictBlocksBefore(blockEnd(3)).Times(2)
+	require.NoError(t, mgr.evictDataFilesForDiskUsage(ts, namespaces))
+	require.Equal(t, []string{
+		evictedBefore(0, 4), evictedBefore(1, 4),
+		evictedBefore(0, 3), evictedBefore(1, 3),
+	}, evicted)
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(2), counters["disk-usage-eviction.triggered+"].Value())
+	require.Equal(t, int64(6), counters["disk-usage-eviction.evicted-filesets+"].Value())
+	require.Equal(t, int64(1), counters["disk-usage-eviction.floor-reached+"].Value())
+}
+
 func TestCleanupManagerPropagatesOwnedNamespacesError(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -110,15 +110,19 @@ type db struct {
 	mediator databaseMediator
 	repairer databaseRepairer
 
+	donorBootstrapping int32
+
 	created    uint64
 	bootstraps int
 
 	shardSet              sharding.ShardSet
 	lastReceivedNewShards time.Time
 
-	scope   tally.Scope
-	metrics databaseMetrics
-	log     *zap.Logger
+	scope        tally.Scope
+	metrics      databaseMetrics
+	writeLatency *namespacesWriteLatencyMetrics
+	futureWrites *namespacesFutureWriteMetrics
+	log          *zap.Logger
 
 	writeBatchPool *writes.WriteBatchPool
 
@@ -193,6 +197,8 @@ func NewDatabase(
 		commitLog:              commitLog,
 		scope:                  scope,
 		metrics:                newDatabaseMetrics(scope),
+		writeLatency:           newNamespacesWriteLatencyMetrics(iopts),
+		futureWrites:           newNamespacesFutureWriteMetrics(scope),
 		log:                    logger,
 		writeBatchPool:         opts.WriteBatchPool(),
 		queryLimits:            opts.IndexOptions().QueryLimits(),
@@ -253,6 +259,17 @@ func NewDatabase(
 		}
 	}
 
+	if opts.DivergenceCheckEnabled() {
+		divergenceChecker, err := newDatabaseDivergenceChecker(d, opts)
+		if err != nil {
+			return nil, err
+		}
+		err = d.mediator.RegisterBackgroundProcess(divergenceChecker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, fn := range opts.BackgroundProcessFns() {
 		process, err := fn(d, opts)
 		if err != nil {
@@ -677,6 +694,7 @@ func (d *db) Write(
 		return err
 	}
 
+	timestamp = d.clampFutureWrite(n, timestamp)
 	seriesWrite, err := n.Write(ctx, id, timestamp, value, unit, annotation)
 	if err != nil {
 		return err
@@ -691,7 +709,10 @@ func (d *db) Write(
 		Value:          value,
 	}
 
-	return d.commitLog.Write(ctx, seriesWrite.Series, dp, unit, annotation)
+	commitLogStart := d.nowFn()
+	err = d.commitLog.Write(ctx, seriesWrite.Series, dp, unit, annotation)
+	d.writeLatency.forNamespace(n.ID()).commitLog.Record(d.nowFn().Sub(commitLogStart))
+	return err
 }
 
 func (d *db) WriteTagged(
@@ -710,6 +731,7 @@ func (d *db) WriteTagged(
 		return err
 	}
 
+	timestamp = d.clampFutureWrite(n, timestamp)
 	seriesWrite, err := n.WriteTagged(ctx, id, tagResolver, timestamp, value, unit, annotation)
 	if err != nil {
 		return err
@@ -724,7 +746,10 @@ func (d *db) WriteTagged(
 		Value:          value,
 	}
 
-	return d.commitLog.Write(ctx, seriesWrite.Series, dp, unit, annotation)
+	commitLogStart := d.nowFn()
+	err = d.commitLog.Write(ctx, seriesWrite.Series, dp, unit, annotation)
+	d.writeLatency.forNamespace(n.ID()).commitLog.Record(d.nowFn().Sub(commitLogStart))
+	return err
 }
 
 func (d *db) BatchWriter(namespace ident.ID, batchSize int) (writes.BatchWriter, error) {
@@ -791,13 +816,23 @@ func (d *db) writeBatch(
 		return errWriterDoesNotImplementWriteBatch
 	}
 
-	iter := writes.Iter()
+	var (
+		writeLatency = d.writeLatency.forNamespace(n.ID())
+		seriesStart  = d.nowFn()
+		iter         = writes.Iter()
+	)
 	for i, write := range iter {
 		var (
 			seriesWrite SeriesWrite
 			err         error
 		)
 
+		// NB: update the timestamp of the write in the batch as well so the
+		// commit log gets the clamped timestamp.
+		write.Write.Datapoint.TimestampNanos = d.clampFutureWrite(
+			n, write.Write.Datapoint.TimestampNanos)
+		iter[i].Write.Datapoint.TimestampNanos = write.Write.Datapoint.TimestampNanos
+
 		if tagged {
 			seriesWrite, err = n.WriteTagged(
 				ctx,
@@ -846,7 +881,9 @@ func (d *db) writeBatch(
 
 	// Now insert all pending index inserts together in one go
 	// to limit lock contention.
-	if pending := writes.PendingIndex(); len(pending) > 0 {
+	indexStart := d.nowFn()
+	pending := writes.PendingIndex()
+	if len(pending) > 0 {
 		err := n.WritePendingIndexInserts(pending)
 		if err != nil {
 			// Mark those as pending index with an error.
@@ -862,14 +899,37 @@ func (d *db) writeBatch(
 		}
 	}
 
+	commitLogStart := d.nowFn()
+	if len(pending) > 0 {
+		writeLatency.index.Record(commitLogStart.Sub(indexStart))
+	}
+
 	if !n.Options().WritesToCommitLog() {
+		if sampled {
+			sp.LogFields(
+				xopentracing.Duration("seriesDuration", indexStart.Sub(seriesStart)),
+				xopentracing.Duration("indexDuration", commitLogStart.Sub(indexStart)),
+			)
+		}
 		// Finalize here because we can't rely on the commitlog to do it since
 		// we're not using it.
 		writes.Finalize()
 		return nil
 	}
 
-	return d.commitLog.WriteBatch(ctx, writes)
+	err = d.commitLog.WriteBatch(ctx, writes)
+	commitLogDuration := d.nowFn().Sub(commitLogStart)
+	writeLatency.commitLog.Record(commitLogDuration)
+	if sampled {
+		// Annotate the span with the time spent in each segment of the write
+		// path to tell whether the batch was series, index or commit log bound.
+		sp.LogFields(
+			xopentracing.Duration("seriesDuration", indexStart.Sub(seriesStart)),
+			xopentracing.Duration("indexDuration", commitLogStart.Sub(indexStart)),
+			xopentracing.Duration("commitLogDuration", commitLogDuration),
+		)
+	}
+	return err
 }
 
 func (d *db) QueryIDs(
@@ -1200,12 +1260,44 @@ func (d *db) Truncate(namespace ident.ID) (int64, error) {
 	return n.Truncate()
 }
 
+func (d *db) IndexCompactionPlans(namespace ident.ID) ([]index.CompactionPlan, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := n.Index()
+	if err != nil {
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+	return idx.CompactionPlans()
+}
+
+func (d *db) CompactIndex(
+	namespace ident.ID,
+	blockStart xtime.UnixNano,
+	priority index.CompactionPriority,
+) error {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return err
+	}
+	idx, err := n.Index()
+	if err != nil {
+		return xerrors.NewInvalidParamsError(err)
+	}
+	return idx.Compact(blockStart, priority)
+}
+
 func (d *db) IsOverloaded() bool {
 	queueSize := float64(d.commitLog.QueueLength())
 	queueCapacity := float64(d.opts.CommitLogOptions().BacklogQueueSize())
 	return queueSize >= commitLogQueueCapacityOverloadedFactor*queueCapacity
 }
 
+func (d *db) CommitLogStatus() commitlog.QueueStatus {
+	return d.commitLog.QueueStatus()
+}
+
 func (d *db) BootstrapState() DatabaseBootstrapState {
 	nsBootstrapStates := NamespaceBootstrapStates{}
 
@@ -0,0 +1,459 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	defaultDivergenceCheckInterval = 10 * time.Minute
+
+	// divergenceCheckConsistencyLevel is the consistency level used to fetch
+	// block metadata from peers.
+	divergenceCheckConsistencyLevel = topology.ReadConsistencyLevelUnstrictMajority
+)
+
+var (
+	errDivergenceCheckNoAdminClient   = errors.New("divergence check enabled but no admin client set")
+	errInvalidDivergenceCheckInterval = errors.New("invalid divergence check interval")
+	errDivergenceCheckInProgress      = errors.New("divergence check already in progress")
+)
+
+// seriesBlockChecksum is the size and checksum of a single series block.
+type seriesBlockChecksum struct {
+	size        int64
+	checksum    uint32
+	hasChecksum bool
+}
+
+func (c seriesBlockChecksum) matches(other seriesBlockChecksum) bool {
+	if c.hasChecksum && other.hasChecksum {
+		return c.checksum == other.checksum
+	}
+	// NB: fall back to the sizes when a replica has not computed the
+	// checksum of the block yet.
+	return c.hasChecksum == other.hasChecksum && c.size == other.size
+}
+
+// blockChecksums are the checksums of the series of a shard by block start
+// and series ID.
+type blockChecksums map[xtime.UnixNano]map[string]seriesBlockChecksum
+
+func (c blockChecksums) add(start xtime.UnixNano, id []byte, size int64, checksum *uint32) {
+	series, ok := c[start]
+	if !ok {
+		series = make(map[string]seriesBlockChecksum)
+		c[start] = series
+	}
+
+	value := seriesBlockChecksum{size: size}
+	if checksum != nil {
+		value.checksum = *checksum
+		value.hasChecksum = true
+	}
+	series[string(id)] = value
+}
+
+type blockChecksumsComparison struct {
+	blocksCompared   int64
+	blocksMismatched int64
+	seriesMismatched int64
+	bytesDiffering   int64
+}
+
+func (r *blockChecksumsComparison) compareBlock(local, peer map[string]seriesBlockChecksum) {
+	var seriesMismatched, bytesDiffering int64
+	for id, localSeries := range local {
+		peerSeries, ok := peer[id]
+		if !ok {
+			seriesMismatched++
+			bytesDiffering += localSeries.size
+			continue
+		}
+		if localSeries.matches(peerSeries) {
+			continue
+		}
+		// A series that differs has to be replaced entirely on one of the
+		// replicas, so count the larger of the two blocks.
+		seriesMismatched++
+		if localSeries.size > peerSeries.size {
+			bytesDiffering += localSeries.size
+		} else {
+			bytesDiffering += peerSeries.size
+		}
+	}
+	for id, peerSeries := range peer {
+		if _, ok := local[id]; ok {
+			continue
+		}
+		seriesMismatched++
+		bytesDiffering += peerSeries.size
+	}
+
+	r.blocksCompared++
+	if seriesMismatched > 0 {
+		r.blocksMismatched++
+	}
+	r.seriesMismatched += seriesMismatched
+	r.bytesDiffering += bytesDiffering
+}
+
+func compareBlockChecksums(local, peer blockChecksums) blockChecksumsComparison {
+	var result blockChecksumsComparison
+	for start, localSeries := range local {
+		result.compareBlock(localSeries, peer[start])
+	}
+	for start, peerSeries := range peer {
+		if _, ok := local[start]; ok {
+			continue
+		}
+		result.compareBlock(nil, peerSeries)
+	}
+	return result
+}
+
+type divergenceCheckMetrics struct {
+	runs             tally.Counter
+	errors           tally.Counter
+	blocksCompared   tally.Counter
+	blocksMismatched tally.Counter
+	seriesMismatched tally.Counter
+	bytesDiffering   tally.Counter
+}
+
+func newDivergenceCheckMetrics(scope tally.Scope) divergenceCheckMetrics {
+	return divergenceCheckMetrics{
+		runs:             scope.Counter("runs"),
+		errors:           scope.Counter("errors"),
+		blocksCompared:   scope.Counter("blocks-compared"),
+		blocksMismatched: scope.Counter("blocks-mismatched"),
+		seriesMismatched: scope.Counter("series-mismatched"),
+		bytesDiffering:   scope.Counter("bytes-differing"),
+	}
+}
+
+// dbDivergenceChecker periodically compares the checksums of the series blocks
+// of each owned shard with the replicas of the shard and reports how much they
+// diverge, without repairing any of the differences.
+type dbDivergenceChecker struct {
+	database database
+	opts     Options
+	session  client.AdminSession
+
+	checkFn       func() error
+	sleepFn       sleepFn
+	nowFn         clock.NowFn
+	logger        *zap.Logger
+	checkInterval time.Duration
+	scope         tally.Scope
+	status        tally.Gauge
+	metrics       divergenceCheckMetrics
+
+	// cursors are the next block start to check by namespace.
+	cursors map[string]xtime.UnixNano
+
+	closedLock sync.Mutex
+	running    int32
+	closed     bool
+}
+
+func newDatabaseDivergenceChecker(database database, opts Options) (*dbDivergenceChecker, error) {
+	if opts.AdminClient() == nil {
+		return nil, errDivergenceCheckNoAdminClient
+	}
+	if opts.DivergenceCheckInterval() <= 0 {
+		return nil, errInvalidDivergenceCheckInterval
+	}
+
+	scope := opts.InstrumentOptions().MetricsScope().SubScope("replica-divergence")
+	c := &dbDivergenceChecker{
+		database:      database,
+		opts:          opts,
+		sleepFn:       time.Sleep,
+		nowFn:         opts.ClockOptions().NowFn(),
+		logger:        opts.InstrumentOptions().Logger(),
+		checkInterval: opts.DivergenceCheckInterval(),
+		scope:         scope,
+		status:        scope.Gauge("running"),
+		metrics:       newDivergenceCheckMetrics(scope),
+		cursors:       make(map[string]xtime.UnixNano),
+	}
+	c.checkFn = c.Check
+	return c, nil
+}
+
+func (c *dbDivergenceChecker) run() {
+	for {
+		c.closedLock.Lock()
+		closed := c.closed
+		c.closedLock.Unlock()
+
+		if closed {
+			break
+		}
+
+		c.sleepFn(c.checkInterval)
+
+		if err := c.checkFn(); err != nil {
+			c.metrics.errors.Inc(1)
+			c.logger.Error("error checking replica divergence", zap.Error(err))
+		}
+	}
+}
+
+func (c *dbDivergenceChecker) Start() {
+	go c.run()
+}
+
+func (c *dbDivergenceChecker) Stop() {
+	c.closedLock.Lock()
+	c.closed = true
+	c.closedLock.Unlock()
+}
+
+func (c *dbDivergenceChecker) Report() {
+	if atomic.LoadInt32(&c.running) == 1 {
+		c.status.Update(1)
+	} else {
+		c.status.Update(0)
+	}
+}
+
+// Check compares one block of every owned namespace with the replicas of
+// each owned shard. Each call checks the block before the one checked by the
+// previous call, starting over from the most recent flushable block once the
+// start of retention is reached.
+func (c *dbDivergenceChecker) Check() error {
+	// Don't attempt a check if the database is not bootstrapped yet.
+	if !c.database.IsBootstrapped() {
+		return nil
+	}
+
+	if !atomic.CompareAndSwapInt32(&c.running, 0, 1) {
+		return errDivergenceCheckInProgress
+	}
+	defer atomic.StoreInt32(&c.running, 0)
+
+	if c.session == nil {
+		session, err := c.opts.AdminClient().DefaultAdminSession()
+		if err != nil {
+			return err
+		}
+		c.session = session
+	}
+
+	namespaces, err := c.database.OwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		if err := c.checkNamespaceBlockStart(n, c.nextBlockStart(n)); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	c.metrics.runs.Inc(1)
+	return multiErr.FinalError()
+}
+
+func (c *dbDivergenceChecker) nextBlockStart(n databaseNamespace) xtime.UnixNano {
+	var (
+		now        = xtime.ToUnixNano(c.nowFn())
+		rOpts      = n.Options().RetentionOptions()
+		first      = retention.FlushTimeStart(rOpts, now)
+		last       = retention.FlushTimeEnd(rOpts, now)
+		key        = n.ID().String()
+		blockStart xtime.UnixNano
+		ok         bool
+	)
+	blockStart, ok = c.cursors[key]
+	if !ok || blockStart.Before(first) || blockStart.After(last) {
+		blockStart = last
+	}
+	c.cursors[key] = blockStart.Add(-rOpts.BlockSize())
+	return blockStart
+}
+
+func (c *dbDivergenceChecker) checkNamespaceBlockStart(
+	n databaseNamespace,
+	blockStart xtime.UnixNano,
+) error {
+	var (
+		start    = blockStart
+		end      = blockStart.Add(n.Options().RetentionOptions().BlockSize())
+		nsScope  = c.scope.Tagged(map[string]string{"namespace": n.ID().String()})
+		metrics  = newDivergenceCheckMetrics(nsScope)
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, s := range n.OwnedShards() {
+		if !s.IsBootstrapped() {
+			continue
+		}
+
+		local, err := c.localBlockChecksums(s, start, end)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		peers, err := c.peerBlockChecksums(n, s.ID(), start, end)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		for peer, checksums := range peers {
+			result := compareBlockChecksums(local, checksums)
+			metrics.blocksCompared.Inc(result.blocksCompared)
+			metrics.blocksMismatched.Inc(result.blocksMismatched)
+			metrics.seriesMismatched.Inc(result.seriesMismatched)
+			metrics.bytesDiffering.Inc(result.bytesDiffering)
+			if result.blocksMismatched == 0 {
+				continue
+			}
+
+			c.logger.Info("replica diverges from local shard",
+				zap.Stringer("namespace", n.ID()),
+				zap.Uint32("shard", s.ID()),
+				zap.String("peer", peer),
+				zap.Time("blockStart", blockStart.ToTime()),
+				zap.Int64("blocksMismatched", result.blocksMismatched),
+				zap.Int64("seriesMismatched", result.seriesMismatched),
+				zap.Int64("bytesDiffering", result.bytesDiffering))
+		}
+	}
+
+	return multiErr.FinalError()
+}
+
+func (c *dbDivergenceChecker) localBlockChecksums(
+	s databaseShard,
+	start, end xtime.UnixNano,
+) (blockChecksums, error) {
+	ctx := c.opts.ContextPool().Get()
+	defer ctx.Close()
+
+	var (
+		checksums = make(blockChecksums)
+		opts      = block.FetchBlocksMetadataOptions{IncludeSizes: true, IncludeChecksums: true}
+		pageToken PageToken
+	)
+	for {
+		// NB: a nil page token is only returned once all phases of the
+		// metadata have been fetched, even with an unbounded limit.
+		results, nextPageToken, err := s.FetchBlocksMetadataV2(ctx, start, end,
+			math.MaxInt64, pageToken, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if results != nil {
+			for _, series := range results.Results() {
+				for _, b := range series.Blocks.Results() {
+					if b.Err != nil {
+						continue
+					}
+					checksums.add(b.Start, series.ID.Bytes(), b.Size, b.Checksum)
+				}
+			}
+			results.Close()
+		}
+
+		if nextPageToken == nil {
+			return checksums, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// peerBlockChecksums returns the block checksums of each available replica of
+// the shard by host ID, replicas without any data for the shard have empty
+// checksums.
+func (c *dbDivergenceChecker) peerBlockChecksums(
+	n databaseNamespace,
+	shardID uint32,
+	start, end xtime.UnixNano,
+) (map[string]blockChecksums, error) {
+	topoMap, err := c.session.TopologyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		origin    = c.session.Origin().ID()
+		checksums = make(map[string]blockChecksums)
+	)
+	if err := topoMap.RouteShardForEach(shardID, func(
+		_ int,
+		hostShard shard.Shard,
+		host topology.Host,
+	) {
+		if host.ID() == origin || hostShard.State() != shard.Available {
+			return
+		}
+		checksums[host.ID()] = make(blockChecksums)
+	}); err != nil {
+		return nil, err
+	}
+
+	rsOpts := result.NewOptions()
+	if rOpts := c.opts.RepairOptions(); rOpts != nil {
+		rsOpts = rOpts.ResultOptions()
+	}
+	iter, err := c.session.FetchBlocksMetadataFromPeers(n.ID(), shardID, start, end,
+		divergenceCheckConsistencyLevel, rsOpts)
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		host, metadata := iter.Current()
+		peer, ok := checksums[host.ID()]
+		if !ok {
+			continue
+		}
+		peer.add(metadata.Start, metadata.ID.Bytes(), metadata.Size, metadata.Checksum)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return checksums, nil
+}
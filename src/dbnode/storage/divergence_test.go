@@ -0,0 +1,244 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func TestDivergenceCheckerCheck(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now         = xtime.Now()
+		scope       = tally.NewTestScope("", nil)
+		namespaceID = ident.StringID("testNamespace")
+		nsOpts      = namespace.NewOptions()
+		rOpts       = nsOpts.RetentionOptions()
+		start       = retention.FlushTimeEnd(rOpts, now)
+		end         = start.Add(rOpts.BlockSize())
+		shardID     = uint32(0)
+		checksums   = []uint32{4, 5}
+		origin      = topology.NewHost("0", "addr0")
+		peer1       = topology.NewHost("1", "addr1")
+		peer2       = topology.NewHost("2", "addr2")
+		peer3       = topology.NewHost("3", "addr3")
+	)
+
+	topoMap := topology.NewMockMap(ctrl)
+	topoMap.EXPECT().RouteShardForEach(shardID, gomock.Any()).DoAndReturn(
+		func(_ uint32, fn topology.RouteForEachFn) error {
+			fn(0, shard.NewShard(shardID).SetState(shard.Available), origin)
+			fn(1, shard.NewShard(shardID).SetState(shard.Available), peer1)
+			fn(2, shard.NewShard(shardID).SetState(shard.Available), peer2)
+			// Replicas that are still initializing the shard are not compared.
+			fn(3, shard.NewShard(shardID).SetState(shard.Initializing), peer3)
+			return nil
+		})
+
+	peerIter := client.NewMockPeerBlockMetadataIter(ctrl)
+	peerBlocks := []block.ReplicaMetadata{
+		{
+			Host:     peer1,
+			Metadata: block.NewMetadata(ident.StringID("bar"), ident.Tags{}, start, 3, &checksums[1], 0),
+		},
+		{
+			Host:     peer1,
+			Metadata: block.NewMetadata(ident.StringID("foo"), ident.Tags{}, start, 1, &checksums[0], 0),
+		},
+		{
+			// Peer missing the bar series.
+			Host:     peer2,
+			Metadata: block.NewMetadata(ident.StringID("foo"), ident.Tags{}, start, 1, &checksums[0], 0),
+		},
+		{
+			Host:     peer3,
+			Metadata: block.NewMetadata(ident.StringID("foo"), ident.Tags{}, start, 1, &checksums[0], 0),
+		},
+	}
+	var calls []*gomock.Call
+	for _, b := range peerBlocks {
+		calls = append(calls,
+			peerIter.EXPECT().Next().Return(true),
+			peerIter.EXPECT().Current().Return(b.Host, b.Metadata))
+	}
+	calls = append(calls,
+		peerIter.EXPECT().Next().Return(false),
+		peerIter.EXPECT().Err().Return(nil))
+	gomock.InOrder(calls...)
+
+	session := client.NewMockAdminSession(ctrl)
+	session.EXPECT().Origin().Return(origin).AnyTimes()
+	session.EXPECT().TopologyMap().Return(topoMap, nil)
+	session.EXPECT().
+		FetchBlocksMetadataFromPeers(namespaceID, shardID, start, end,
+			divergenceCheckConsistencyLevel, gomock.Any()).
+		Return(peerIter, nil)
+
+	mockClient := client.NewMockAdminClient(ctrl)
+	mockClient.EXPECT().DefaultAdminSession().Return(session, nil)
+
+	localResults := block.NewFetchBlocksMetadataResults()
+	for i, id := range []string{"foo", "bar"} {
+		results := block.NewFetchBlockMetadataResults()
+		results.Add(block.NewFetchBlockMetadataResult(start, int64(1+2*i), &checksums[i], 0, nil))
+		localResults.Add(block.NewFetchBlocksMetadataResult(ident.StringID(id), nil, results))
+	}
+	fetchOpts := block.FetchBlocksMetadataOptions{IncludeSizes: true, IncludeChecksums: true}
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().IsBootstrapped().Return(true)
+	mockShard.EXPECT().ID().Return(shardID).AnyTimes()
+	mockShard.EXPECT().
+		FetchBlocksMetadataV2(gomock.Any(), start, end, gomock.Any(), nil, fetchOpts).
+		Return(localResults, nil, nil)
+
+	mockNamespace := NewMockdatabaseNamespace(ctrl)
+	mockNamespace.EXPECT().ID().Return(namespaceID).AnyTimes()
+	mockNamespace.EXPECT().Options().Return(nsOpts).AnyTimes()
+	mockNamespace.EXPECT().OwnedShards().Return([]databaseShard{mockShard})
+
+	mockDatabase := NewMockdatabase(ctrl)
+	mockDatabase.EXPECT().IsBootstrapped().Return(true)
+	mockDatabase.EXPECT().OwnedNamespaces().Return([]databaseNamespace{mockNamespace}, nil)
+
+	opts := DefaultTestOptions().
+		SetAdminClient(mockClient).
+		SetDivergenceCheckEnabled(true).
+		SetClockOptions(DefaultTestOptions().ClockOptions().
+			SetNowFn(func() time.Time { return now.ToTime() })).
+		SetInstrumentOptions(DefaultTestOptions().InstrumentOptions().
+			SetMetricsScope(scope))
+	checker, err := newDatabaseDivergenceChecker(mockDatabase, opts)
+	require.NoError(t, err)
+	require.NoError(t, checker.Check())
+
+	counters := scope.Snapshot().Counters()
+	counter := func(name string) int64 {
+		c, ok := counters["replica-divergence."+name+"+namespace=testNamespace"]
+		require.True(t, ok, name)
+		return c.Value()
+	}
+	require.Equal(t, int64(2), counter("blocks-compared"))
+	require.Equal(t, int64(1), counter("blocks-mismatched"))
+	require.Equal(t, int64(1), counter("series-mismatched"))
+	require.Equal(t, int64(3), counter("bytes-differing"))
+}
+
+func TestCompareBlockChecksums(t *testing.T) {
+	var (
+		blockSize = time.Hour
+		start     = xtime.Now().Truncate(blockSize)
+		checksums = []uint32{1, 2, 3}
+		local     = make(blockChecksums)
+		peer      = make(blockChecksums)
+	)
+
+	// Same size but different checksums.
+	local.add(start, []byte("foo"), 10, &checksums[0])
+	peer.add(start, []byte("foo"), 10, &checksums[1])
+	// Identical series.
+	local.add(start, []byte("bar"), 20, &checksums[2])
+	peer.add(start, []byte("bar"), 20, &checksums[2])
+	// Different checksums and sizes, the larger block is counted.
+	local.add(start, []byte("baz"), 5, &checksums[0])
+	peer.add(start, []byte("baz"), 7, &checksums[2])
+	// Series only present on one of the replicas.
+	local.add(start, []byte("qux"), 3, &checksums[0])
+	peer.add(start, []byte("quux"), 4, &checksums[0])
+	// Blocks only present on one of the replicas.
+	local.add(start.Add(blockSize), []byte("foo"), 11, &checksums[0])
+	peer.add(start.Add(-blockSize), []byte("foo"), 12, nil)
+	// Blocks without checksums are compared by size.
+	local.add(start.Add(2*blockSize), []byte("foo"), 13, nil)
+	peer.add(start.Add(2*blockSize), []byte("foo"), 13, nil)
+
+	require.Equal(t, blockChecksumsComparison{
+		blocksCompared:   4,
+		blocksMismatched: 3,
+		seriesMismatched: 6,
+		bytesDiffering:   10 + 7 + 3 + 4 + 11 + 12,
+	}, compareBlockChecksums(local, peer))
+}
+
+func TestDivergenceCheckerNextBlockStart(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now    = xtime.Now()
+		nsOpts = namespace.NewOptions()
+		rOpts  = nsOpts.RetentionOptions()
+		first  = retention.FlushTimeStart(rOpts, now)
+		last   = retention.FlushTimeEnd(rOpts, now)
+	)
+
+	mockNamespace := NewMockdatabaseNamespace(ctrl)
+	mockNamespace.EXPECT().ID().Return(ident.StringID("testNamespace")).AnyTimes()
+	mockNamespace.EXPECT().Options().Return(nsOpts).AnyTimes()
+
+	opts := DefaultTestOptions().
+		SetAdminClient(client.NewMockAdminClient(ctrl)).
+		SetClockOptions(DefaultTestOptions().ClockOptions().
+			SetNowFn(func() time.Time { return now.ToTime() }))
+	checker, err := newDatabaseDivergenceChecker(NewMockdatabase(ctrl), opts)
+	require.NoError(t, err)
+
+	// Walks backwards through the flushable range and starts over once the
+	// start of retention is passed.
+	var checked []xtime.UnixNano
+	for blockStart := last; !blockStart.Before(first); blockStart = blockStart.Add(-rOpts.BlockSize()) {
+		checked = append(checked, blockStart)
+		require.Equal(t, blockStart, checker.nextBlockStart(mockNamespace))
+	}
+	require.True(t, len(checked) > 1)
+	require.Equal(t, last, checker.nextBlockStart(mockNamespace))
+}
+
+func TestNewDatabaseDivergenceCheckerErrors(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := newDatabaseDivergenceChecker(NewMockdatabase(ctrl), DefaultTestOptions())
+	require.Equal(t, errDivergenceCheckNoAdminClient, err)
+
+	opts := DefaultTestOptions().
+		SetAdminClient(client.NewMockAdminClient(ctrl)).
+		SetDivergenceCheckInterval(0)
+	_, err = newDatabaseDivergenceChecker(NewMockdatabase(ctrl), opts)
+	require.Equal(t, errInvalidDivergenceCheckInterval, err)
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// donorBootstrapConsistencyLevel is the consistency level used to fetch
+	// block metadata and blocks, only the donor's replies are used so a
+	// single successful replica suffices.
+	donorBootstrapConsistencyLevel = topology.ReadConsistencyLevelOne
+)
+
+var (
+	errDonorBootstrapNoAdminClient = errors.New("donor bootstrap requires an admin client")
+	errDonorBootstrapInProgress    = errors.New("donor bootstrap already in progress")
+	errDonorBootstrapNoShards      = errors.New("no shards to bootstrap from donor")
+	errDonorBootstrapNoDonor       = errors.New("donor is required")
+	errDonorBootstrapSelfDonor     = errors.New("donor cannot be the local host")
+)
+
+// BootstrapShardsFromDonor validates the request and starts replacing the
+// flushed data of the given shards of a namespace with the data of the
+// designated donor host only, rather than that of a quorum of peers, in the
+// background. The outcome is reported by logs and metrics.
+func (d *db) BootstrapShardsFromDonor(
+	namespace ident.ID,
+	shards []uint32,
+	donor string,
+	tr xtime.Range,
+) error {
+	if len(shards) == 0 {
+		return xerrors.NewInvalidParamsError(errDonorBootstrapNoShards)
+	}
+	if donor == "" {
+		return xerrors.NewInvalidParamsError(errDonorBootstrapNoDonor)
+	}
+
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return err
+	}
+
+	adminClient := d.opts.AdminClient()
+	if adminClient == nil {
+		return errDonorBootstrapNoAdminClient
+	}
+
+	if !atomic.CompareAndSwapInt32(&d.donorBootstrapping, 0, 1) {
+		return errDonorBootstrapInProgress
+	}
+	started := false
+	defer func() {
+		// The background bootstrap releases the flag once done.
+		if !started {
+			atomic.StoreInt32(&d.donorBootstrapping, 0)
+		}
+	}()
+
+	session, err := adminClient.DefaultAdminSession()
+	if err != nil {
+		return fmt.Errorf("error obtaining default admin session: %v", err)
+	}
+	if session.Origin().ID() == donor {
+		return xerrors.NewInvalidParamsError(errDonorBootstrapSelfDonor)
+	}
+
+	topoMap, err := session.TopologyMap()
+	if err != nil {
+		return fmt.Errorf("error obtaining topology map: %v", err)
+	}
+	donorShardSet, ok := topoMap.LookupHostShardSet(donor)
+	if !ok {
+		return xerrors.NewInvalidParamsError(
+			fmt.Errorf("donor %s is not part of the topology", donor))
+	}
+
+	// Validate all shards up front so that either all or none of the shards
+	// are bootstrapped from the donor due to a bad request.
+	for _, shardID := range shards {
+		state, err := donorShardSet.ShardSet().LookupStateByID(shardID)
+		if err != nil || state != shard.Available {
+			return xerrors.NewInvalidParamsError(
+				fmt.Errorf("donor %s does not have shard %d available", donor, shardID))
+		}
+		if _, _, err := n.ReadableShardAt(shardID); err != nil {
+			return xerrors.NewInvalidParamsError(err)
+		}
+	}
+
+	started = true
+	go func() {
+		defer atomic.StoreInt32(&d.donorBootstrapping, 0)
+		d.bootstrapShardsFromDonor(session, n, shards, donor, tr)
+	}()
+	return nil
+}
+
+func (d *db) bootstrapShardsFromDonor(
+	session client.AdminSession,
+	n databaseNamespace,
+	shards []uint32,
+	donor string,
+	tr xtime.Range,
+) {
+	var (
+		scope      = d.scope.SubScope("donor-bootstrap")
+		succeeded  = scope.Counter("shards-succeeded")
+		failed     = scope.Counter("shards-failed")
+		inProgress = scope.Gauge("in-progress")
+		nsMeta     = n.Metadata()
+		blockSize  = nsMeta.Options().RetentionOptions().BlockSize()
+		rsOpts     = result.NewOptions()
+		logger     = d.log.With(zap.Stringer("namespace", n.ID()), zap.String("donor", donor))
+	)
+	if rOpts := d.opts.RepairOptions(); rOpts != nil {
+		rsOpts = rOpts.ResultOptions()
+	}
+
+	inProgress.Update(1)
+	defer inProgress.Update(0)
+
+	// Whole blocks are replaced, so widen the range to the blocks it overlaps.
+	tr.Start = tr.Start.Truncate(blockSize)
+	if end := tr.End.Truncate(blockSize); end.Before(tr.End) {
+		tr.End = end.Add(blockSize)
+	}
+
+	pm, err := fs.NewPersistManager(d.opts.CommitLogOptions().FilesystemOptions())
+	if err != nil {
+		logger.Error("donor bootstrap failed to create persist manager", zap.Error(err))
+		failed.Inc(int64(len(shards)))
+		return
+	}
+	defer pm.Close()
+
+	for _, shardID := range shards {
+		shardResult, err := d.bootstrapShardFromDonor(session, n, nsMeta, shardID,
+			donor, tr, rsOpts, pm)
+		if err != nil {
+			logger.Error("donor bootstrap of shard failed",
+				zap.Uint32("shard", shardID),
+				zap.Error(err))
+			failed.Inc(1)
+			continue
+		}
+
+		logger.Info("donor bootstrap of shard complete",
+			zap.Uint32("shard", shardID),
+			zap.Int64("series", shardResult.numSeries),
+			zap.Int64("blocks", shardResult.numBlocks),
+			zap.Int("replacedBlocks", shardResult.numReplacedBlocks),
+			zap.Int("skippedBlocks", shardResult.numSkippedBlocks))
+		succeeded.Inc(1)
+	}
+}
+
+type donorBootstrapShardResult struct {
+	numSeries         int64
+	numBlocks         int64
+	numReplacedBlocks int
+	numSkippedBlocks  int
+}
+
+func (d *db) bootstrapShardFromDonor(
+	session client.AdminSession,
+	n databaseNamespace,
+	nsMeta namespace.Metadata,
+	shardID uint32,
+	donor string,
+	tr xtime.Range,
+	rsOpts result.Options,
+	pm persist.Manager,
+) (donorBootstrapShardResult, error) {
+	s, nsCtx, err := n.ReadableShardAt(shardID)
+	if err != nil {
+		return donorBootstrapShardResult{}, err
+	}
+
+	peerIter, err := session.FetchBlocksMetadataFromPeers(nsMeta.ID(), s.ID(),
+		tr.Start, tr.End, donorBootstrapConsistencyLevel, rsOpts)
+	if err != nil {
+		return donorBootstrapShardResult{}, err
+	}
+
+	var metadatas []block.ReplicaMetadata
+	for peerIter.Next() {
+		host, metadata := peerIter.Current()
+		if host.ID() != donor {
+			continue
+		}
+		metadatas = append(metadatas, block.ReplicaMetadata{
+			Host:     host,
+			Metadata: metadata,
+		})
+	}
+	if err := peerIter.Err(); err != nil {
+		return donorBootstrapShardResult{}, err
+	}
+
+	var (
+		shardResult donorBootstrapShardResult
+		data        = result.NewShardResult(rsOpts)
+	)
+	defer data.Close()
+
+	// Without metadata the donor holds no data in the range, which still
+	// replaces the data of the local blocks.
+	if len(metadatas) > 0 {
+		blocksIter, err := session.FetchBlocksFromPeers(nsMeta, s.ID(),
+			donorBootstrapConsistencyLevel, metadatas, rsOpts)
+		if err != nil {
+			return donorBootstrapShardResult{}, err
+		}
+
+		for blocksIter.Next() {
+			_, id, tags, dbBlock := blocksIter.Current()
+			shardResult.numBlocks++
+			if existing, ok := data.BlockAt(id, dbBlock.StartTime()); ok {
+				if err := existing.Merge(dbBlock); err != nil {
+					return donorBootstrapShardResult{}, err
+				}
+				continue
+			}
+			data.AddBlock(id, tags, dbBlock)
+		}
+		if err := blocksIter.Err(); err != nil {
+			return donorBootstrapShardResult{}, err
+		}
+		shardResult.numSeries = data.NumSeries()
+	}
+
+	// Flushes of the shard would otherwise write the next volumes of the
+	// blocks concurrently.
+	d.mediator.DisableFileOpsAndWait()
+	defer d.mediator.EnableFileOps()
+
+	flushPersist, err := pm.StartFlushPersist()
+	if err != nil {
+		return donorBootstrapShardResult{}, err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	blockSize := nsMeta.Options().RetentionOptions().BlockSize()
+	for blockStart := tr.Start; blockStart.Before(tr.End); blockStart = blockStart.Add(blockSize) {
+		replaced, err := s.ReplaceFlushedBlock(blockStart, data.AllSeries(), flushPersist, nsCtx)
+		if err != nil {
+			multiErr = multiErr.Add(fmt.Errorf("block %s: %v", blockStart, err))
+			continue
+		}
+		if !replaced {
+			shardResult.numSkippedBlocks++
+			continue
+		}
+		shardResult.numReplacedBlocks++
+	}
+	multiErr = multiErr.Add(flushPersist.DoneFlush())
+
+	return shardResult, multiErr.FinalError()
+}
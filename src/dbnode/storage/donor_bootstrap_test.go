@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	xclock "github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func TestDatabaseBootstrapShardsFromDonor(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		nsID     = ident.StringID("testns1")
+		shardID  = uint32(0)
+		start    = xtime.Now().Truncate(defaultTestRetentionOpts.BlockSize())
+		end      = start.Add(defaultTestRetentionOpts.BlockSize())
+		origin   = topology.NewHost("0", "addr0")
+		donor    = topology.NewHost("1", "addr1")
+		peer     = topology.NewHost("2", "addr2")
+		checksum = uint32(4)
+		fooMeta  = block.NewMetadata(ident.StringID("foo"), ident.Tags{}, start, 1, &checksum, 0)
+		barMeta  = block.NewMetadata(ident.StringID("bar"), ident.Tags{}, start, 1, &checksum, 0)
+	)
+
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{shardID}, shard.Available), nil)
+	require.NoError(t, err)
+
+	topoMap := topology.NewMockMap(ctrl)
+	topoMap.EXPECT().LookupHostShardSet(donor.ID()).
+		Return(topology.NewHostShardSet(donor, shardSet), true)
+
+	peerIter := client.NewMockPeerBlockMetadataIter(ctrl)
+	gomock.InOrder(
+		peerIter.EXPECT().Next().Return(true),
+		peerIter.EXPECT().Current().Return(donor, fooMeta),
+		// Metadata from replicas other than the donor is ignored.
+		peerIter.EXPECT().Next().Return(true),
+		peerIter.EXPECT().Current().Return(peer, barMeta),
+		peerIter.EXPECT().Next().Return(false),
+		peerIter.EXPECT().Err().Return(nil),
+	)
+
+	dbBlock := block.NewMockDatabaseBlock(ctrl)
+	dbBlock.EXPECT().StartTime().Return(start).AnyTimes()
+	dbBlock.EXPECT().Close()
+	blocksIter := client.NewMockPeerBlocksIter(ctrl)
+	gomock.InOrder(
+		blocksIter.EXPECT().Next().Return(true),
+		blocksIter.EXPECT().Current().Return(donor, fooMeta.ID, fooMeta.Tags, dbBlock),
+		blocksIter.EXPECT().Next().Return(false),
+		blocksIter.EXPECT().Err().Return(nil),
+	)
+
+	nsMeta, err := namespace.NewMetadata(nsID, namespace.NewOptions())
+	require.NoError(t, err)
+
+	session := client.NewMockAdminSession(ctrl)
+	session.EXPECT().Origin().Return(origin).AnyTimes()
+	session.EXPECT().TopologyMap().Return(topoMap, nil)
+	session.EXPECT().
+		FetchBlocksMetadataFromPeers(ident.NewIDMatcher(nsID.String()), shardID, start, end,
+			donorBootstrapConsistencyLevel, gomock.Any()).
+		Return(peerIter, nil)
+	session.EXPECT().
+		FetchBlocksFromPeers(nsMeta, shardID, donorBootstrapConsistencyLevel,
+			[]block.ReplicaMetadata{{Host: donor, Metadata: fooMeta}}, gomock.Any()).
+		Return(blocksIter, nil)
+
+	mockClient := client.NewMockAdminClient(ctrl)
+	mockClient.EXPECT().DefaultAdminSession().Return(session, nil)
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, newTestDatabaseOpt{
+		bs:    Bootstrapped,
+		nsMap: testNamespaceMap(t),
+		dbOpt: DefaultTestOptions().SetAdminClient(mockClient),
+	})
+	defer close(mapCh)
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(shardID).AnyTimes()
+	mockShard.EXPECT().
+		ReplaceFlushedBlock(start, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ xtime.UnixNano,
+			series *result.Map,
+			_ persist.FlushPreparer,
+			_ namespace.Context,
+		) (bool, error) {
+			require.Equal(t, 1, series.Len())
+			_, ok := series.Get(fooMeta.ID)
+			require.True(t, ok)
+			return true, nil
+		})
+
+	ns := dbAddNewMockNamespace(ctrl, d, nsID.String())
+	ns.EXPECT().Metadata().Return(nsMeta)
+	ns.EXPECT().ReadableShardAt(shardID).Return(mockShard, namespace.Context{}, nil).Times(2)
+
+	err = d.BootstrapShardsFromDonor(nsID, []uint32{shardID}, donor.ID(),
+		xtime.Range{Start: start, End: end})
+	require.NoError(t, err)
+
+	// The blocks are replaced in the background.
+	require.True(t, xclock.WaitUntil(func() bool {
+		return atomic.LoadInt32(&d.donorBootstrapping) == 0
+	}, time.Minute))
+}
+
+func TestDatabaseBootstrapShardsFromDonorInvalidParams(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	origin := topology.NewHost("0", "addr0")
+	session := client.NewMockAdminSession(ctrl)
+	session.EXPECT().Origin().Return(origin).AnyTimes()
+	mockClient := client.NewMockAdminClient(ctrl)
+	mockClient.EXPECT().DefaultAdminSession().Return(session, nil).AnyTimes()
+
+	d, mapCh, _ := newTestDatabase(t, ctrl, newTestDatabaseOpt{
+		bs:    Bootstrapped,
+		nsMap: testNamespaceMap(t),
+		dbOpt: DefaultTestOptions().SetAdminClient(mockClient),
+	})
+	defer close(mapCh)
+
+	nsID := ident.StringID("testns1")
+	dbAddNewMockNamespace(ctrl, d, nsID.String())
+
+	err := d.BootstrapShardsFromDonor(nsID, nil, "1", xtime.Range{})
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	err = d.BootstrapShardsFromDonor(nsID, []uint32{0}, "", xtime.Range{})
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	err = d.BootstrapShardsFromDonor(nsID, []uint32{0}, origin.ID(), xtime.Range{})
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	// A rejected request does not block the next one.
+	require.Equal(t, int32(0), atomic.LoadInt32(&d.donorBootstrapping))
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+)
+
+type fileSetPins struct {
+	sync.Mutex
+
+	byOwner map[string][]string
+	pinned  map[string]int
+}
+
+// NewFileSetPins creates a new FileSetPins.
+func NewFileSetPins() FileSetPins {
+	return &fileSetPins{
+		byOwner: make(map[string][]string),
+		pinned:  make(map[string]int),
+	}
+}
+
+func (p *fileSetPins) Pin(owner string, listFn func() ([]string, error)) error {
+	p.Lock()
+	defer p.Unlock()
+
+	// List the files while holding the lock so that none of them can be
+	// deleted before they are pinned.
+	filePaths, err := listFn()
+	if err != nil {
+		return err
+	}
+	for _, filePath := range filePaths {
+		p.pinned[filePath]++
+	}
+	p.byOwner[owner] = append(p.byOwner[owner], filePaths...)
+	return nil
+}
+
+func (p *fileSetPins) Release(owner string) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	filePaths, ok := p.byOwner[owner]
+	if !ok {
+		return false
+	}
+	for _, filePath := range filePaths {
+		if p.pinned[filePath] <= 1 {
+			delete(p.pinned, filePath)
+			continue
+		}
+		p.pinned[filePath]--
+	}
+	delete(p.byOwner, owner)
+	return true
+}
+
+func (p *fileSetPins) DeleteFiles(filePaths []string) error {
+	p.Lock()
+	defer p.Unlock()
+
+	unpinned := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if _, ok := p.pinned[filePath]; ok {
+			continue
+		}
+		unpinned = append(unpinned, filePath)
+	}
+	return fs.DeleteFiles(unpinned)
+}
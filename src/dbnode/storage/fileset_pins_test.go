@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSetPinsDeleteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileset-pins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var filePaths []string
+	for _, name := range []string{"a", "b", "c"} {
+		filePath := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(filePath, nil, 0600))
+		filePaths = append(filePaths, filePath)
+	}
+	exists := func(filePath string) bool {
+		_, err := os.Stat(filePath)
+		return err == nil
+	}
+
+	pins := NewFileSetPins()
+	require.NoError(t, pins.Pin("first", func() ([]string, error) {
+		return filePaths[:2], nil
+	}))
+	require.NoError(t, pins.Pin("second", func() ([]string, error) {
+		return filePaths[1:2], nil
+	}))
+
+	// Pinned files are skipped.
+	require.NoError(t, pins.DeleteFiles(filePaths))
+	require.True(t, exists(filePaths[0]))
+	require.True(t, exists(filePaths[1]))
+	require.False(t, exists(filePaths[2]))
+
+	// Files stay pinned until every owner has released them.
+	require.True(t, pins.Release("first"))
+	require.False(t, pins.Release("first"))
+	require.NoError(t, pins.DeleteFiles(filePaths[:2]))
+	require.False(t, exists(filePaths[0]))
+	require.True(t, exists(filePaths[1]))
+
+	require.True(t, pins.Release("second"))
+	require.NoError(t, pins.DeleteFiles(filePaths[1:2]))
+	require.False(t, exists(filePaths[1]))
+}
+
+func TestFileSetPinsPinError(t *testing.T) {
+	expectedErr := errors.New("list error")
+	pins := NewFileSetPins()
+	err := pins.Pin("owner", func() ([]string, error) {
+		return nil, expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+	require.False(t, pins.Release("owner"))
+}
@@ -152,7 +152,8 @@ func (m *flushManager) Flush(startTime xtime.UnixNano) error {
 	rotatedCommitlogID, err := m.commitlog.RotateLogs()
 	m.metrics.commitLogRotationDuration.Record(m.nowFn().Sub(start))
 	if err == nil {
-		if err = m.dataSnapshot(namespaces, startTime, rotatedCommitlogID); err != nil {
+		snapshotID := uuid.NewUUID()
+		if err = m.dataSnapshot(namespaces, startTime, snapshotID, rotatedCommitlogID); err != nil {
 			multiErr = multiErr.Add(err)
 		}
 	} else {
@@ -166,6 +167,44 @@ func (m *flushManager) Flush(startTime xtime.UnixNano) error {
 	return multiErr.FinalError()
 }
 
+func (m *flushManager) Snapshot(
+	startTime xtime.UnixNano,
+) (uuid.UUID, persist.CommitLogFile, error) {
+	// ensure a forced snapshot never runs concurrently with a flush
+	m.Lock()
+	if m.state != flushManagerIdle {
+		m.Unlock()
+		return nil, persist.CommitLogFile{}, errFlushOperationsInProgress
+	}
+	m.state = flushManagerNotIdle
+	m.Unlock()
+
+	defer m.setState(flushManagerIdle)
+
+	// NB: all owned namespaces are snapshotted, rather than just the namespace
+	// that requested the snapshot, since completing a snapshot marks every
+	// commit log prior to the rotated one as eligible for cleanup.
+	namespaces, err := m.database.OwnedNamespaces()
+	if err != nil {
+		return nil, persist.CommitLogFile{}, err
+	}
+
+	start := m.nowFn()
+	rotatedCommitlogID, err := m.commitlog.RotateLogs()
+	m.metrics.commitLogRotationDuration.Record(m.nowFn().Sub(start))
+	if err != nil {
+		return nil, persist.CommitLogFile{}, fmt.Errorf(
+			"error rotating commitlog for snapshot: %v", err)
+	}
+
+	snapshotID := uuid.NewUUID()
+	if err := m.dataSnapshot(namespaces, startTime, snapshotID, rotatedCommitlogID); err != nil {
+		return nil, persist.CommitLogFile{}, err
+	}
+
+	return snapshotID, rotatedCommitlogID, nil
+}
+
 func (m *flushManager) dataWarmFlush(
 	namespaces []databaseNamespace,
 	startTime xtime.UnixNano,
@@ -204,10 +243,9 @@ func (m *flushManager) dataWarmFlush(
 func (m *flushManager) dataSnapshot(
 	namespaces []databaseNamespace,
 	startTime xtime.UnixNano,
+	snapshotID uuid.UUID,
 	rotatedCommitlogID persist.CommitLogFile,
 ) error {
-	snapshotID := uuid.NewUUID()
-
 	snapshotPersist, err := m.pm.StartSnapshotPersist(snapshotID)
 	if err != nil {
 		return err
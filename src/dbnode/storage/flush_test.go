@@ -588,6 +588,48 @@ func TestFlushManagerFlushSnapshot(t *testing.T) {
 	require.Equal(t, now, lastSuccessfulSnapshot)
 }
 
+func TestFlushManagerSnapshot(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	fm, ns1, ns2, _ := newMultipleFlushManagerNeedsFlush(t, ctrl)
+	now := xtime.Now()
+
+	for _, ns := range []*MockdatabaseNamespace{ns1, ns2} {
+		rOpts := ns.Options().RetentionOptions()
+		blockSize := rOpts.BlockSize()
+		bufferFuture := rOpts.BufferFuture()
+
+		start := retention.FlushTimeStart(ns.Options().RetentionOptions(), now)
+		snapshotEnd := now.Add(bufferFuture).Truncate(blockSize)
+		num := numIntervals(start, snapshotEnd, blockSize)
+		for i := 0; i < num; i++ {
+			st := start.Add(time.Duration(i) * blockSize)
+			ns.EXPECT().Snapshot(st, now, gomock.Any())
+		}
+	}
+
+	snapshotID, commitLog, err := fm.Snapshot(now)
+	require.NoError(t, err)
+	require.NotNil(t, snapshotID)
+	require.Equal(t, testCommitlogFile, commitLog)
+
+	lastSuccessfulSnapshot, ok := fm.LastSuccessfulSnapshotStartTime()
+	require.True(t, ok)
+	require.Equal(t, now, lastSuccessfulSnapshot)
+}
+
+func TestFlushManagerSnapshotFlushInProgress(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	fm, _, _, _ := newMultipleFlushManagerNeedsFlush(t, ctrl)
+	fm.setState(flushManagerFlushInProgress)
+
+	_, _, err := fm.Snapshot(xtime.Now())
+	require.Equal(t, errFlushOperationsInProgress, err)
+}
+
 type timesInOrder []xtime.UnixNano
 
 func (a timesInOrder) Len() int           { return len(a) }
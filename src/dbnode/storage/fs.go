@@ -21,15 +21,20 @@
 package storage
 
 import (
+	"errors"
 	"sync"
 
+	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/x/instrument"
 	xtime "github.com/m3db/m3/src/x/time"
 
+	"github.com/pborman/uuid"
 	"go.uber.org/zap"
 )
 
+var errFileOpsUnavailable = errors.New("file operations are disabled or in progress")
+
 type fileOpStatus int
 
 const (
@@ -176,6 +181,33 @@ func (m *fileSystemManager) Run(t xtime.UnixNano) bool {
 	return true
 }
 
+func (m *fileSystemManager) Snapshot(
+	t xtime.UnixNano,
+) (uuid.UUID, persist.CommitLogFile, error) {
+	m.Lock()
+	if !m.shouldRunWithLock() {
+		m.Unlock()
+		return nil, persist.CommitLogFile{}, errFileOpsUnavailable
+	}
+	m.status = fileOpInProgress
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.status = fileOpNotStarted
+		m.Unlock()
+	}()
+
+	m.log.Debug("starting forced snapshot", zap.Time("time", t.ToTime()))
+	snapshotID, commitLog, err := m.databaseFlushManager.Snapshot(t)
+	if err != nil {
+		return nil, persist.CommitLogFile{}, err
+	}
+	m.log.Debug("completed forced snapshot", zap.Time("time", t.ToTime()))
+
+	return snapshotID, commitLog, nil
+}
+
 func (m *fileSystemManager) Report() {
 	m.databaseCleanupManager.Report()
 	m.databaseFlushManager.Report()
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+)
+
+// futureWriteMetrics counts the writes of a namespace too far in the future,
+// either clamped to the current time or beyond the clamp tolerance and so
+// rejected.
+type futureWriteMetrics struct {
+	clamped         tally.Counter
+	beyondTolerance tally.Counter
+}
+
+func newFutureWriteMetrics(scope tally.Scope) futureWriteMetrics {
+	scope = scope.SubScope("future-write")
+	return futureWriteMetrics{
+		clamped:         scope.Counter("clamped"),
+		beyondTolerance: scope.Counter("beyond-tolerance"),
+	}
+}
+
+// namespacesFutureWriteMetrics holds the future write metrics of each namespace.
+type namespacesFutureWriteMetrics struct {
+	sync.RWMutex
+
+	scope   tally.Scope
+	metrics map[string]futureWriteMetrics
+}
+
+func newNamespacesFutureWriteMetrics(scope tally.Scope) *namespacesFutureWriteMetrics {
+	return &namespacesFutureWriteMetrics{
+		scope:   scope,
+		metrics: make(map[string]futureWriteMetrics),
+	}
+}
+
+func (m *namespacesFutureWriteMetrics) forNamespace(id ident.ID) futureWriteMetrics {
+	m.RLock()
+	metrics, ok := m.metrics[string(id.Bytes())]
+	m.RUnlock()
+	if ok {
+		return metrics
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	name := id.String()
+	if metrics, ok := m.metrics[name]; ok {
+		return metrics
+	}
+	metrics = newFutureWriteMetrics(m.scope.Tagged(map[string]string{
+		"namespace": name,
+	}))
+	m.metrics[name] = metrics
+	return metrics
+}
+
+type futureWriteOutcome int
+
+const (
+	futureWriteWithinLimit futureWriteOutcome = iota
+	futureWriteClamped
+	futureWriteBeyondTolerance
+)
+
+// clampFutureWrite returns the timestamp to write a datapoint at. Timestamps
+// past the buffer future limit of the namespace by no more than its future
+// write clamp tolerance are clamped to now, so that small clock skews between
+// clients and the database do not fail writes. The clamped timestamp must
+// also be used for the commit log so that the write is bootstrapped as it was
+// accepted.
+func clampFutureWrite(
+	nsOpts namespace.Options,
+	now xtime.UnixNano,
+	timestamp xtime.UnixNano,
+) (xtime.UnixNano, futureWriteOutcome) {
+	tolerance := nsOpts.FutureWriteClampTolerance()
+	if tolerance <= 0 || nsOpts.ColdWritesEnabled() {
+		// NB: with cold writes enabled, writes past the buffer future limit
+		// are accepted as cold writes and so never need clamping.
+		return timestamp, futureWriteWithinLimit
+	}
+	futureLimit := now.Add(nsOpts.RetentionOptions().BufferFuture()).Truncate(time.Second)
+	if futureLimit.After(timestamp) {
+		return timestamp, futureWriteWithinLimit
+	}
+	if !futureLimit.Add(tolerance).After(timestamp) {
+		return timestamp, futureWriteBeyondTolerance
+	}
+	return now, futureWriteClamped
+}
+
+func (d *db) clampFutureWrite(
+	n databaseNamespace,
+	timestamp xtime.UnixNano,
+) xtime.UnixNano {
+	nsOpts := n.Options()
+	if nsOpts.FutureWriteClampTolerance() <= 0 {
+		return timestamp
+	}
+	timestamp, outcome := clampFutureWrite(nsOpts, xtime.ToUnixNano(d.nowFn()), timestamp)
+	switch outcome {
+	case futureWriteClamped:
+		d.futureWrites.forNamespace(n.ID()).clamped.Inc(1)
+	case futureWriteBeyondTolerance:
+		d.futureWrites.forNamespace(n.ID()).beyondTolerance.Inc(1)
+	}
+	return timestamp
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestClampFutureWrite(t *testing.T) {
+	var (
+		now    = xtime.UnixNano(100 * time.Hour)
+		nsOpts = namespace.NewOptions().
+			SetRetentionOptions(retention.NewOptions().SetBufferFuture(time.Minute)).
+			SetFutureWriteClampTolerance(10 * time.Second)
+	)
+
+	for _, test := range []struct {
+		name      string
+		nsOpts    namespace.Options
+		timestamp xtime.UnixNano
+		expected  xtime.UnixNano
+		outcome   futureWriteOutcome
+	}{
+		{
+			name:      "within buffer future",
+			nsOpts:    nsOpts,
+			timestamp: now.Add(30 * time.Second),
+			expected:  now.Add(30 * time.Second),
+			outcome:   futureWriteWithinLimit,
+		},
+		{
+			name:      "within tolerance",
+			nsOpts:    nsOpts,
+			timestamp: now.Add(65 * time.Second),
+			expected:  now,
+			outcome:   futureWriteClamped,
+		},
+		{
+			name:      "beyond tolerance",
+			nsOpts:    nsOpts,
+			timestamp: now.Add(70 * time.Second),
+			expected:  now.Add(70 * time.Second),
+			outcome:   futureWriteBeyondTolerance,
+		},
+		{
+			name:      "clamping disabled",
+			nsOpts:    nsOpts.SetFutureWriteClampTolerance(0),
+			timestamp: now.Add(65 * time.Second),
+			expected:  now.Add(65 * time.Second),
+			outcome:   futureWriteWithinLimit,
+		},
+		{
+			name:      "cold writes enabled",
+			nsOpts:    nsOpts.SetColdWritesEnabled(true),
+			timestamp: now.Add(65 * time.Second),
+			expected:  now.Add(65 * time.Second),
+			outcome:   futureWriteWithinLimit,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			timestamp, outcome := clampFutureWrite(test.nsOpts, now, test.timestamp)
+			require.Equal(t, test.expected, timestamp)
+			require.Equal(t, test.outcome, outcome)
+		})
+	}
+}
+
+func TestDatabaseClampFutureWriteMetrics(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		scope = tally.NewTestScope("", nil)
+		now   = time.Unix(0, int64(100*time.Hour))
+		d     = &db{
+			nowFn:        func() time.Time { return now },
+			futureWrites: newNamespacesFutureWriteMetrics(scope),
+		}
+		nsOpts = namespace.NewOptions().
+			SetRetentionOptions(retention.NewOptions().SetBufferFuture(time.Minute)).
+			SetFutureWriteClampTolerance(10 * time.Second)
+	)
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("foo")).AnyTimes()
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+
+	nowNanos := xtime.ToUnixNano(now)
+	require.Equal(t, nowNanos, d.clampFutureWrite(ns, nowNanos.Add(65*time.Second)))
+	require.Equal(t, nowNanos, d.clampFutureWrite(ns, nowNanos.Add(61*time.Second)))
+	require.Equal(t, nowNanos.Add(2*time.Minute),
+		d.clampFutureWrite(ns, nowNanos.Add(2*time.Minute)))
+
+	counters := scope.Snapshot().Counters()
+	counter, ok := counters["future-write.clamped+namespace=foo"]
+	require.True(t, ok)
+	require.Equal(t, int64(2), counter.Value())
+	counter, ok = counters["future-write.beyond-tolerance+namespace=foo"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), counter.Value())
+}
@@ -159,6 +159,10 @@ type nsIndexState struct {
 	blocksByTime map[xtime.UnixNano]index.Block
 	latestBlock  index.Block
 
+	// evictedBefore is the time before which blocks are dropped ahead of the
+	// retention period to reclaim disk space.
+	evictedBefore xtime.UnixNano
+
 	// NB: `blockStartsDescOrder` contains the keys from the map `blocksByTime` in reverse
 	// chronological order. This is used at query time to enforce determinism about results
 	// returned.
@@ -972,6 +976,7 @@ func (i *nsIndex) tickingBlocks(
 		i.retentionPeriod, i.blockSize, startTime)
 
 	i.state.Lock()
+	earliestBlockStartToRetain = i.earliestNotEvictedWithRLock(earliestBlockStartToRetain)
 	activeBlock := i.activeBlock
 	tickingBlocks := make([]index.Block, 0, len(i.state.blocksByTime))
 	defer func() {
@@ -1135,6 +1140,59 @@ func (i *nsIndex) BackgroundCompact() {
 	}
 }
 
+// CompactionPlans returns the background compaction planned for the in-memory
+// segments of each index block, ordered by block start.
+func (i *nsIndex) CompactionPlans() ([]index.CompactionPlan, error) {
+	i.state.RLock()
+	defer i.state.RUnlock()
+
+	plans := make([]index.CompactionPlan, 0, len(i.state.blocksDescOrderImmutable)+1)
+	for _, b := range i.state.blocksDescOrderImmutable {
+		plan, err := b.block.CompactionPlan()
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	if i.activeBlock != nil {
+		plan, err := i.activeBlock.CompactionPlan()
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+
+	sort.Slice(plans, func(a, b int) bool {
+		return plans[a].BlockStart.Before(plans[b].BlockStart)
+	})
+	return plans, nil
+}
+
+// Compact triggers a compaction of the in-memory segments of the index blocks
+// starting at the given block start with the given priority.
+func (i *nsIndex) Compact(blockStart xtime.UnixNano, priority index.CompactionPriority) error {
+	i.state.RLock()
+	defer i.state.RUnlock()
+
+	var blocks []index.Block
+	if i.activeBlock != nil && i.activeBlock.StartTime() == blockStart {
+		blocks = append(blocks, i.activeBlock)
+	}
+	if b, ok := i.state.blocksByTime[blockStart]; ok {
+		blocks = append(blocks, b)
+	}
+	if len(blocks) == 0 {
+		return xerrors.NewInvalidParamsError(
+			fmt.Errorf("no index block starting at %s", blockStart.String()))
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, b := range blocks {
+		multiErr = multiErr.Add(b.Compact(priority))
+	}
+	return multiErr.FinalError()
+}
+
 func (i *nsIndex) readInfoFilesAsMap() map[xtime.UnixNano][]fs.ReadIndexInfoFileResult {
 	fsOpts := i.opts.CommitLogOptions().FilesystemOptions()
 	infoFiles := i.readIndexInfoFilesFn(fs.ReadIndexInfoFilesOptions{
@@ -1504,8 +1562,9 @@ func (i *nsIndex) Query(
 	// Get results and set the namespace ID and size limit.
 	results := i.resultsPool.Get()
 	results.Reset(i.nsMetadata.ID(), index.QueryResultsOptions{
-		SizeLimit: opts.SeriesLimit,
-		FilterID:  i.shardsFilterID(),
+		SizeLimit:     opts.SeriesLimit,
+		FilterID:      i.shardsFilterID(),
+		SearchAfterID: opts.PageAfterID,
 	})
 	ctx.RegisterFinalizer(results)
 	queryRes, err := i.query(ctx, query, results, opts, i.execBlockQueryFn,
@@ -2209,8 +2268,9 @@ func (i *nsIndex) CleanupExpiredFileSets(t xtime.UnixNano) error {
 		return errDbIndexUnableToCleanupClosed
 	}
 
-	// earliest block to retain based on retention period
-	earliestBlockStartToRetain := retention.FlushTimeStartForRetentionPeriod(i.retentionPeriod, i.blockSize, t)
+	// earliest block to retain based on retention period and evictions
+	earliestBlockStartToRetain := i.earliestNotEvictedWithRLock(
+		retention.FlushTimeStartForRetentionPeriod(i.retentionPeriod, i.blockSize, t))
 
 	// now we loop through the blocks we hold, to ensure we don't delete any data for them.
 	for t := range i.state.blocksByTime {
@@ -2233,6 +2293,23 @@ func (i *nsIndex) CleanupExpiredFileSets(t xtime.UnixNano) error {
 	return i.deleteFilesFn(filesets)
 }
 
+func (i *nsIndex) EvictBlocksBefore(t xtime.UnixNano) {
+	i.state.Lock()
+	if t.After(i.state.evictedBefore) {
+		i.state.evictedBefore = t
+	}
+	i.state.Unlock()
+}
+
+// earliestNotEvictedWithRLock returns the start of the earliest block that
+// ends after the eviction time, or the given block start if later.
+func (i *nsIndex) earliestNotEvictedWithRLock(blockStart xtime.UnixNano) xtime.UnixNano {
+	if evicted := i.state.evictedBefore.Truncate(i.blockSize); evicted.After(blockStart) {
+		return evicted
+	}
+	return blockStart
+}
+
 func (i *nsIndex) CleanupCorruptedFileSets() error {
 	/*
 	   Corrupted index filesets can be safely cleaned up if its not
@@ -166,6 +166,7 @@ type blockMetrics struct {
 	segmentFreeMmapSuccess          tally.Counter
 	segmentFreeMmapError            tally.Counter
 	segmentFreeMmapSkipNotImmutable tally.Counter
+	segmentFreeMmapSkipPinned       tally.Counter
 	querySeriesMatched              tally.Histogram
 	queryDocsMatched                tally.Histogram
 	aggregateSeriesMatched          tally.Histogram
@@ -191,6 +192,10 @@ func newBlockMetrics(s tally.Scope) blockMetrics {
 			"result":    "skip",
 			"skip_type": "not-immutable",
 		}).Counter(segmentFreeMmap),
+		segmentFreeMmapSkipPinned: s.Tagged(map[string]string{
+			"result":    "skip",
+			"skip_type": "pinned",
+		}).Counter(segmentFreeMmap),
 
 		querySeriesMatched:     s.Histogram("query-series-matched", buckets),
 		queryDocsMatched:       s.Histogram("query-docs-matched", buckets),
@@ -305,6 +310,17 @@ func (b *block) BackgroundCompact() {
 	b.mutableSegments.BackgroundCompact()
 }
 
+// CompactionPlan returns the background compaction currently planned for
+// the in-memory segments of the block.
+func (b *block) CompactionPlan() (CompactionPlan, error) {
+	return b.mutableSegments.CompactionPlan()
+}
+
+// Compact triggers a compaction of the in-memory segments of the block.
+func (b *block) Compact(priority CompactionPriority) error {
+	return b.mutableSegments.Compact(priority)
+}
+
 func (b *block) WriteBatch(inserts *WriteBatch) (WriteBatchResult, error) {
 	b.RLock()
 	if !b.writesAcceptedWithRLock() {
@@ -1008,7 +1024,13 @@ func (b *block) addResults(
 		elem := seg.Segment()
 		if immSeg, ok := elem.(segment.ImmutableSegment); ok {
 			// only wrap the immutable segments with a read through cache.
-			elem = NewReadThroughSegment(immSeg, plCaches, readThroughOpts)
+			readThroughSeg := NewReadThroughSegment(immSeg, plCaches, readThroughOpts)
+			if budget := b.opts.SegmentsMemoryBudget(); budget != nil {
+				if err := readThroughSeg.trackMemoryBudget(budget); err != nil {
+					b.logger.Error("could not track segment memory budget", zap.Error(err))
+				}
+			}
+			elem = readThroughSeg
 		}
 		readThroughSegments = append(readThroughSegments, elem)
 	}
@@ -1082,7 +1104,13 @@ func (b *block) Tick(c context.Cancellable) (BlockTickResult, error) {
 			return nil
 		}
 
-		// TODO(bodu): Revist this and implement a more sophisticated free strategy.
+		// Keep the most recently queried segments within the memory
+		// budget in memory, if any.
+		if readThroughSeg, ok := seg.(*ReadThroughSegment); ok && readThroughSeg.pinned() {
+			b.metrics.segmentFreeMmapSkipPinned.Inc(1)
+			return nil
+		}
+
 		if err := immSeg.FreeMmap(); err != nil {
 			multiErr = multiErr.Add(err)
 			b.metrics.segmentFreeMmapError.Inc(1)
@@ -882,6 +882,35 @@ func (mr *MockBlockMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBlock)(nil).Close))
 }
 
+// Compact mocks base method.
+func (m *MockBlock) Compact(priority CompactionPriority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockBlockMockRecorder) Compact(priority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockBlock)(nil).Compact), priority)
+}
+
+// CompactionPlan mocks base method.
+func (m *MockBlock) CompactionPlan() (CompactionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactionPlan")
+	ret0, _ := ret[0].(CompactionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactionPlan indicates an expected call of CompactionPlan.
+func (mr *MockBlockMockRecorder) CompactionPlan() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactionPlan", reflect.TypeOf((*MockBlock)(nil).CompactionPlan))
+}
+
 // EndTime mocks base method.
 func (m *MockBlock) EndTime() time0.UnixNano {
 	m.ctrl.T.Helper()
@@ -1979,6 +2008,20 @@ func (mr *MockOptionsMockRecorder) SegmentBuilderOptions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentBuilderOptions", reflect.TypeOf((*MockOptions)(nil).SegmentBuilderOptions))
 }
 
+// SegmentsMemoryBudget mocks base method.
+func (m *MockOptions) SegmentsMemoryBudget() *SegmentsMemoryBudget {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentsMemoryBudget")
+	ret0, _ := ret[0].(*SegmentsMemoryBudget)
+	return ret0
+}
+
+// SegmentsMemoryBudget indicates an expected call of SegmentsMemoryBudget.
+func (mr *MockOptionsMockRecorder) SegmentsMemoryBudget() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentsMemoryBudget", reflect.TypeOf((*MockOptions)(nil).SegmentsMemoryBudget))
+}
+
 // SetAggregateResultsEntryArrayPool mocks base method.
 func (m *MockOptions) SetAggregateResultsEntryArrayPool(value AggregateResultsEntryArrayPool) Options {
 	m.ctrl.T.Helper()
@@ -2301,6 +2344,20 @@ func (mr *MockOptionsMockRecorder) SetSegmentBuilderOptions(value interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSegmentBuilderOptions", reflect.TypeOf((*MockOptions)(nil).SetSegmentBuilderOptions), value)
 }
 
+// SetSegmentsMemoryBudget mocks base method.
+func (m *MockOptions) SetSegmentsMemoryBudget(value *SegmentsMemoryBudget) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSegmentsMemoryBudget", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetSegmentsMemoryBudget indicates an expected call of SetSegmentsMemoryBudget.
+func (mr *MockOptionsMockRecorder) SetSegmentsMemoryBudget(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSegmentsMemoryBudget", reflect.TypeOf((*MockOptions)(nil).SetSegmentsMemoryBudget), value)
+}
+
 // Validate mocks base method.
 func (m *MockOptions) Validate() error {
 	m.ctrl.T.Helper()
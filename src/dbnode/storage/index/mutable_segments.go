@@ -64,6 +64,7 @@ var (
 	errForegroundCompactorNoPlan               = errors.New("index foreground compactor failed to generate a plan")
 	errForegroundCompactorBadPlanFirstTask     = errors.New("index foreground compactor generated plan without mutable segment in first task")
 	errForegroundCompactorBadPlanSecondaryTask = errors.New("index foreground compactor generated plan with mutable segment a secondary task")
+	errBackgroundCompactionInProgress          = errors.New("index background compaction already in progress")
 
 	numBackgroundCompactorsStandard       = 1
 	numBackgroundCompactorsGarbageCollect = 1
@@ -451,8 +452,72 @@ func (m *mutableSegments) BackgroundCompact() {
 	m.backgroundCompactWithLock(true)
 }
 
-func (m *mutableSegments) backgroundCompactWithLock(force bool) {
-	// Create a logical plan.
+// CompactionPlan returns the background compaction currently planned for
+// the segments.
+func (m *mutableSegments) CompactionPlan() (CompactionPlan, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	segs := m.backgroundCompactableSegmentsWithLock()
+	plan, err := compaction.NewPlan(segs, m.opts.BackgroundCompactionPlannerOptions())
+	if err != nil {
+		return CompactionPlan{}, err
+	}
+
+	result := CompactionPlan{
+		BlockStart:                  m.blockStart,
+		NumForegroundSegments:       len(m.foregroundSegments),
+		NumBackgroundSegments:       len(m.backgroundSegments),
+		CompactingBackground:        m.compact.compactingBackgroundStandard,
+		GarbageCollectingBackground: m.compact.compactingBackgroundGarbageCollect,
+		Tasks:                       make([]compaction.TaskSummary, 0, len(plan.Tasks)),
+		NumUnplannedSegments:        len(plan.UnusedSegments),
+	}
+	for _, seg := range segs {
+		result.BackgroundSegmentsSize += seg.Size
+	}
+	for _, task := range plan.Tasks {
+		result.Tasks = append(result.Tasks, task.Summary())
+	}
+	return result, nil
+}
+
+// Compact triggers a compaction of the background segments with the given
+// priority, it returns an error if a background compaction is in progress.
+func (m *mutableSegments) Compact(priority CompactionPriority) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.state != mutableSegmentsStateOpen {
+		return errMutableSegmentsAlreadyClosed
+	}
+	if m.compact.compactingBackgroundStandard {
+		return errBackgroundCompactionInProgress
+	}
+
+	switch priority {
+	case NormalCompactionPriority:
+		m.backgroundCompactWithLock(true)
+		return nil
+	case HighCompactionPriority:
+		segs := m.backgroundCompactableSegmentsWithLock()
+		if len(segs) < 2 || m.compact.backgroundCompactors == nil {
+			// Nothing to consolidate.
+			return nil
+		}
+		// Consolidate all the segments into a single segment regardless of
+		// the compaction levels of the planner.
+		plan := &compaction.Plan{Tasks: []compaction.Task{{Segments: segs}}}
+		m.startBackgroundCompactWithLock(plan, true)
+		return nil
+	default:
+		return fmt.Errorf("unknown compaction priority: %d", priority)
+	}
+}
+
+// backgroundCompactableSegmentsWithLock returns the background segments that
+// can be compacted.
+func (m *mutableSegments) backgroundCompactableSegmentsWithLock() []compaction.Segment {
 	segs := make([]compaction.Segment, 0, len(m.backgroundSegments))
 	for _, seg := range m.backgroundSegments {
 		if seg.garbageCollecting {
@@ -467,7 +532,26 @@ func (m *mutableSegments) backgroundCompactWithLock(force bool) {
 			Segment: seg.Segment(),
 		})
 	}
+	return segs
+}
 
+// startBackgroundCompactWithLock kicks off the standard background
+// compaction of the plan.
+func (m *mutableSegments) startBackgroundCompactWithLock(plan *compaction.Plan, gcRequired bool) {
+	m.compact.compactingBackgroundStandard = true
+	go func() {
+		m.backgroundCompactWithPlan(plan, m.compact.backgroundCompactors, gcRequired)
+
+		m.Lock()
+		m.compact.compactingBackgroundStandard = false
+		m.cleanupBackgroundCompactWithLock()
+		m.Unlock()
+	}()
+}
+
+func (m *mutableSegments) backgroundCompactWithLock(force bool) {
+	// Create a logical plan.
+	segs := m.backgroundCompactableSegmentsWithLock()
 	plan, err := compaction.NewPlan(segs, m.opts.BackgroundCompactionPlannerOptions())
 	if err != nil {
 		instrument.EmitAndLogInvariantViolation(m.iopts, func(l *zap.Logger) {
@@ -550,15 +634,7 @@ func (m *mutableSegments) backgroundCompactWithLock(force bool) {
 
 	if len(plan.Tasks) != 0 {
 		// Kick off compaction.
-		m.compact.compactingBackgroundStandard = true
-		go func() {
-			m.backgroundCompactWithPlan(plan, m.compact.backgroundCompactors, gcRequired)
-
-			m.Lock()
-			m.compact.compactingBackgroundStandard = false
-			m.cleanupBackgroundCompactWithLock()
-			m.Unlock()
-		}()
+		m.startBackgroundCompactWithLock(plan, gcRequired)
 	}
 
 	if len(gcPlan.Tasks) != 0 {
@@ -196,6 +196,94 @@ func TestMutableSegmentsBackgroundCompactGCReconstructCachedSearches(t *testing.
 	}
 }
 
+func TestMutableSegmentsCompactHighPriority(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	blockSize := time.Hour
+	testMD := newTestNSMetadata(t)
+	blockStart := xtime.Now().Truncate(blockSize)
+
+	segs, _ := newTestMutableSegments(t, testMD, blockStart)
+	segs.backgroundCompactDisable = true // Disable to explicitly test.
+	defer segs.Close()
+
+	// Insert until we have a few background segments.
+	inserted := 0
+	for {
+		segs.Lock()
+		curr := len(segs.backgroundSegments)
+		segs.Unlock()
+		if curr >= 3 {
+			break
+		}
+
+		batch := NewWriteBatch(WriteBatchOptions{
+			IndexBlockSize: blockSize,
+		})
+		for i := 0; i < 128; i++ {
+			onIndexSeries := doc.NewMockOnIndexSeries(ctrl)
+			onIndexSeries.EXPECT().TryMarkIndexGarbageCollected().Return(false).AnyTimes()
+			onIndexSeries.EXPECT().NeedsIndexGarbageCollected().Return(false).AnyTimes()
+
+			batch.Append(WriteBatchEntry{
+				Timestamp:     blockStart.Add(time.Minute),
+				OnIndexSeries: onIndexSeries,
+			}, testDocN(inserted))
+			inserted++
+		}
+
+		_, err := segs.WriteBatch(batch)
+		require.NoError(t, err)
+	}
+
+	plan, err := segs.CompactionPlan()
+	require.NoError(t, err)
+	require.Equal(t, blockStart, plan.BlockStart)
+	require.True(t, plan.NumBackgroundSegments >= 3)
+	require.True(t, plan.BackgroundSegmentsSize > 0)
+	require.False(t, plan.CompactingBackground)
+
+	require.NoError(t, segs.Compact(HighCompactionPriority))
+
+	// Wait for the compaction to consolidate the background segments.
+	for {
+		segs.Lock()
+		compacting := segs.compact.compactingBackgroundStandard
+		segs.Unlock()
+		if !compacting {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	plan, err = segs.CompactionPlan()
+	require.NoError(t, err)
+	require.Equal(t, 1, plan.NumBackgroundSegments)
+	require.Equal(t, 0, len(plan.Tasks))
+
+	// All documents remain searchable after the compaction.
+	testDocSearches(t, segs)
+}
+
+func TestParseCompactionPriority(t *testing.T) {
+	for _, test := range []struct {
+		str      string
+		expected CompactionPriority
+	}{
+		{str: "", expected: NormalCompactionPriority},
+		{str: "normal", expected: NormalCompactionPriority},
+		{str: "high", expected: HighCompactionPriority},
+	} {
+		p, err := ParseCompactionPriority(test.str)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, p)
+	}
+
+	_, err := ParseCompactionPriority("urgent")
+	require.Error(t, err)
+}
+
 func testDocSearches(
 	t *testing.T,
 	segs *mutableSegments,
@@ -133,6 +133,7 @@ type options struct {
 	backgroundCompactionPlannerOpts compaction.PlannerOptions
 	postingsListCache               *PostingsListCache
 	searchPostingsListCache         *PostingsListCache
+	segmentsMemoryBudget            *SegmentsMemoryBudget
 	readThroughSegmentOptions       ReadThroughSegmentOptions
 	mmapReporter                    mmap.Reporter
 	queryLimits                     limits.QueryLimits
@@ -422,6 +423,16 @@ func (o *options) SearchPostingsListCache() *PostingsListCache {
 	return o.searchPostingsListCache
 }
 
+func (o *options) SetSegmentsMemoryBudget(value *SegmentsMemoryBudget) Options {
+	opts := *o
+	opts.segmentsMemoryBudget = value
+	return &opts
+}
+
+func (o *options) SegmentsMemoryBudget() *SegmentsMemoryBudget {
+	return o.segmentsMemoryBudget
+}
+
 func (o *options) SetReadThroughSegmentOptions(value ReadThroughSegmentOptions) Options {
 	opts := *o
 	opts.readThroughSegmentOptions = value
@@ -22,7 +22,9 @@ package index
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
@@ -30,12 +32,17 @@ import (
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/x/instrument"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 )
 
-var errInstrumentOptions = errors.New("no instrument options set")
+var (
+	errInstrumentOptions          = errors.New("no instrument options set")
+	errMaxBytesNegative           = errors.New("max bytes must not be negative")
+	errMatcherHashMetricsLimitNeg = errors.New("matcher hash metrics limit must not be negative")
+)
 
 // PatternType is an enum for the various pattern types. It allows us
 // separate them logically within the cache.
@@ -56,11 +63,31 @@ const (
 
 	reportLoopInterval = 10 * time.Second
 	emptyPattern       = ""
+
+	// cachedPostingsOverheadBytes is the estimated fixed overhead of a cached
+	// postings list entry, including the LRU element and map entries.
+	cachedPostingsOverheadBytes = 256
+	// postingsIDEstimatedBytes is the estimated upper bound of bytes used per
+	// postings ID, which is the size of an entry in a roaring array container.
+	postingsIDEstimatedBytes = 2
+
+	otherMatcherHash = "other"
 )
 
 // PostingsListCacheOptions is the options struct for the query cache.
 type PostingsListCacheOptions struct {
 	InstrumentOptions instrument.Options
+
+	// MaxBytes if positive bounds the estimated size in bytes of the
+	// cached postings lists, evicting least recently used entries once
+	// exceeded in addition to the entry count based eviction.
+	MaxBytes int64
+
+	// MatcherHashMetricsLimit if positive enables search hit and miss
+	// metrics tagged by the hash of the search query matchers, for up to
+	// this many distinct matchers, any further matchers are reported
+	// under a single "other" matcher hash.
+	MatcherHashMetricsLimit int
 }
 
 // Validate will return an error if the options are not valid.
@@ -68,6 +95,12 @@ func (o PostingsListCacheOptions) Validate() error {
 	if o.InstrumentOptions == nil {
 		return errInstrumentOptions
 	}
+	if o.MaxBytes < 0 {
+		return errMaxBytesNegative
+	}
+	if o.MatcherHashMetricsLimit < 0 {
+		return errMatcherHashMetricsLimitNeg
+	}
 	return nil
 }
 
@@ -78,6 +111,8 @@ type PostingsListCache struct {
 	size    int
 	opts    PostingsListCacheOptions
 	metrics *postingsListCacheMetrics
+	// matcherHashMetrics is only set if matcher hash metrics are enabled.
+	matcherHashMetrics *matcherHashMetrics
 
 	logger *zap.Logger
 }
@@ -95,19 +130,25 @@ func NewPostingsListCache(
 	lru, err := newPostingsListLRU(postingsListLRUOptions{
 		size: size,
 		// Use ~1000 items per shard.
-		shards: int(math.Ceil(float64(size) / 1000)),
+		shards:   int(math.Ceil(float64(size) / 1000)),
+		maxBytes: opts.MaxBytes,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	scope := opts.InstrumentOptions.MetricsScope()
 	plc := &PostingsListCache{
 		lru:     lru,
 		size:    size,
 		opts:    opts,
-		metrics: newPostingsListCacheMetrics(opts.InstrumentOptions.MetricsScope()),
+		metrics: newPostingsListCacheMetrics(scope),
 		logger:  opts.InstrumentOptions.Logger(),
 	}
+	if opts.MatcherHashMetricsLimit > 0 {
+		plc.matcherHashMetrics = newMatcherHashMetrics(scope,
+			opts.MatcherHashMetricsLimit)
+	}
 
 	return plc, nil
 }
@@ -159,6 +200,9 @@ func (q *PostingsListCache) get(
 ) (postings.List, bool) {
 	entry, ok := q.lru.Get(segmentUUID, field, pattern, patternType)
 	q.emitCacheGetMetrics(patternType, ok)
+	if patternType == PatternTypeSearch && q.matcherHashMetrics != nil {
+		q.matcherHashMetrics.emitGet(field, ok)
+	}
 	if !ok {
 		return nil, false
 	}
@@ -177,6 +221,17 @@ type cachedPostings struct {
 	postings postings.List
 	// searchQuery is only set for search queries.
 	searchQuery *querypb.Query
+	// sizeBytes is the estimated size in bytes of the entry.
+	sizeBytes int64
+}
+
+func estimateCachedPostingsBytes(
+	field string,
+	pattern string,
+	pl postings.List,
+) int64 {
+	return int64(cachedPostingsOverheadBytes + len(field) + len(pattern) +
+		postingsIDEstimatedBytes*pl.Len())
 }
 
 // PutRegexp updates the LRU with the result of the regexp query.
@@ -238,8 +293,11 @@ func (q *PostingsListCache) put(
 		patternType: patternType,
 		searchQuery: searchQueryProto,
 		postings:    pl,
+		sizeBytes:   estimateCachedPostingsBytes(field, pattern, pl),
+	}
+	if q.lru.Add(segmentUUID, field, pattern, patternType, value) {
+		q.metrics.evictions.Inc(1)
 	}
-	q.lru.Add(segmentUUID, field, pattern, patternType, value)
 
 	q.emitCachePutMetrics(patternType)
 }
@@ -343,7 +401,10 @@ func shardCachedPatternsWithRLock(
 
 // Report will emit metrics about the status of the cache.
 func (q *PostingsListCache) Report() {
+	q.metrics.size.Update(float64(q.lru.Len()))
+	q.metrics.sizeBytes.Update(float64(q.lru.Bytes()))
 	q.metrics.capacity.Update(float64(q.size))
+	q.metrics.capacityBytes.Update(float64(q.opts.MaxBytes))
 }
 
 func (q *PostingsListCache) emitCacheGetMetrics(patternType PatternType, hit bool) {
@@ -389,8 +450,11 @@ type postingsListCacheMetrics struct {
 	search  *postingsListCacheMethodMetrics
 	unknown *postingsListCacheMethodMetrics
 
-	size     tally.Gauge
-	capacity tally.Gauge
+	size          tally.Gauge
+	sizeBytes     tally.Gauge
+	capacity      tally.Gauge
+	capacityBytes tally.Gauge
+	evictions     tally.Counter
 
 	pooledGet              tally.Counter
 	pooledGetErrAddIter    tally.Counter
@@ -415,9 +479,12 @@ func newPostingsListCacheMetrics(scope tally.Scope) *postingsListCacheMetrics {
 		unknown: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
 			"query_type": "unknown",
 		})),
-		size:      scope.Gauge("size"),
-		capacity:  scope.Gauge("capacity"),
-		pooledGet: scope.Counter("pooled_get"),
+		size:          scope.Gauge("size"),
+		sizeBytes:     scope.Gauge("size_bytes"),
+		capacity:      scope.Gauge("capacity"),
+		capacityBytes: scope.Gauge("capacity_bytes"),
+		evictions:     scope.Counter("evictions"),
+		pooledGet:     scope.Counter("pooled_get"),
 		pooledGetErrAddIter: scope.Tagged(map[string]string{
 			"error_type": "add_iter",
 		}).Counter("pooled_get_error"),
@@ -441,3 +508,66 @@ func newPostingsListCacheMethodMetrics(scope tally.Scope) *postingsListCacheMeth
 		puts:   scope.Counter("puts"),
 	}
 }
+
+// matcherHashMetrics tracks search hits and misses by the hash of the search
+// query, which is derived solely from the query matchers and not the query
+// time range, so repeated queries over sliding time windows are attributed
+// to the same matcher. Hits and misses are counted per segment lookup.
+type matcherHashMetrics struct {
+	sync.Mutex
+
+	scope  tally.Scope
+	limit  int
+	byHash map[uint64]matcherHashMethodMetrics
+	other  matcherHashMethodMetrics
+}
+
+type matcherHashMethodMetrics struct {
+	hits   tally.Counter
+	misses tally.Counter
+}
+
+func newMatcherHashMethodMetrics(scope tally.Scope, hash string) matcherHashMethodMetrics {
+	scope = scope.Tagged(map[string]string{"matcher_hash": hash})
+	return matcherHashMethodMetrics{
+		hits:   scope.Counter("matcher_hits"),
+		misses: scope.Counter("matcher_misses"),
+	}
+}
+
+func newMatcherHashMetrics(scope tally.Scope, limit int) *matcherHashMetrics {
+	scope = scope.Tagged(map[string]string{"query_type": "search"})
+	return &matcherHashMetrics{
+		scope:  scope,
+		limit:  limit,
+		byHash: make(map[uint64]matcherHashMethodMetrics, limit),
+		other:  newMatcherHashMethodMetrics(scope, otherMatcherHash),
+	}
+}
+
+func (m *matcherHashMetrics) emitGet(query string, hit bool) {
+	method := m.metricsForQuery(query)
+	if hit {
+		method.hits.Inc(1)
+	} else {
+		method.misses.Inc(1)
+	}
+}
+
+func (m *matcherHashMetrics) metricsForQuery(query string) matcherHashMethodMetrics {
+	hash := xxhash.Sum64String(query)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if method, ok := m.byHash[hash]; ok {
+		return method
+	}
+	if len(m.byHash) >= m.limit {
+		return m.other
+	}
+
+	method := newMatcherHashMethodMetrics(m.scope, fmt.Sprintf("%016x", hash))
+	m.byHash[hash] = method
+	return method
+}
@@ -34,12 +34,12 @@ import (
 // that were resolved by running a given query against a particular segment for a given
 // field and pattern type (term vs regexp). Normally a key in the LRU would look like:
 //
-// type key struct {
-//    segmentUUID uuid.UUID
-//    field       string
-//    pattern     string
-//    patternType PatternType
-// }
+//	type key struct {
+//	   segmentUUID uuid.UUID
+//	   field       string
+//	   pattern     string
+//	   patternType PatternType
+//	}
 //
 // However, some of the postings lists that we will store in the LRU have a fixed lifecycle
 // because they reference mmap'd byte slices which will eventually be unmap'd. To prevent
@@ -67,6 +67,8 @@ type postingsListLRU struct {
 type postingsListLRUShard struct {
 	sync.RWMutex
 	size      int
+	maxBytes  int64
+	bytes     int64
 	evictList *list.List
 	items     map[uuid.Array]map[PostingsListCacheKey]*list.Element
 }
@@ -88,6 +90,10 @@ type PostingsListCacheKey struct {
 type postingsListLRUOptions struct {
 	size   int
 	shards int
+	// maxBytes if positive bounds the estimated size in bytes of the
+	// postings lists held by the LRU, evicting the least recently used
+	// entries once exceeded.
+	maxBytes int64
 }
 
 // newPostingsListLRU constructs an LRU of the given size.
@@ -99,10 +105,19 @@ func newPostingsListLRU(opts postingsListLRUOptions) (*postingsListLRU, error) {
 	if shards <= 0 {
 		return nil, errors.New("must provide a positive shards")
 	}
+	if opts.maxBytes < 0 {
+		return nil, errors.New("must provide a non-negative max bytes")
+	}
+
+	var shardMaxBytes int64
+	if opts.maxBytes > 0 {
+		shardMaxBytes = int64(math.Ceil(float64(opts.maxBytes) / float64(shards)))
+	}
 
 	lruShards := make([]*postingsListLRUShard, 0, shards)
 	for i := 0; i < shards; i++ {
-		lruShard := newPostingsListLRUShard(int(math.Ceil(float64(size) / float64(shards))))
+		lruShard := newPostingsListLRUShard(int(math.Ceil(float64(size)/float64(shards))),
+			shardMaxBytes)
 		lruShards = append(lruShards, lruShard)
 	}
 
@@ -112,10 +127,12 @@ func newPostingsListLRU(opts postingsListLRUOptions) (*postingsListLRU, error) {
 	}, nil
 }
 
-// newPostingsListLRU constructs an LRU of the given size.
-func newPostingsListLRUShard(size int) *postingsListLRUShard {
+// newPostingsListLRUShard constructs an LRU shard of the given size and
+// max bytes, a zero max bytes disables size-aware eviction.
+func newPostingsListLRUShard(size int, maxBytes int64) *postingsListLRUShard {
 	return &postingsListLRUShard{
 		size:      size,
+		maxBytes:  maxBytes,
 		evictList: list.New(),
 		items:     make(map[uuid.Array]map[PostingsListCacheKey]*list.Element),
 	}
@@ -175,6 +192,15 @@ func (c *postingsListLRU) Len() int {
 	return n
 }
 
+// Bytes returns the estimated size in bytes of the cached postings lists.
+func (c *postingsListLRU) Bytes() int64 {
+	var n int64
+	for _, shard := range c.shards {
+		n += shard.Bytes()
+	}
+	return n
+}
+
 // Add adds a value to the cache. Returns true if an eviction occurred.
 func (c *postingsListLRUShard) Add(
 	segmentUUID uuid.UUID,
@@ -196,8 +222,10 @@ func (c *postingsListLRUShard) Add(
 			// can only point to one entry at a time and we use them for purges. Also,
 			// it saves space by avoiding storing duplicate values.
 			c.evictList.MoveToFront(ent)
-			ent.Value.(*entry).cachedPostings = cachedPostings
-			return false
+			existing := ent.Value.(*entry)
+			c.bytes += cachedPostings.sizeBytes - existing.cachedPostings.sizeBytes
+			existing.cachedPostings = cachedPostings
+			return c.evictOverBudget()
 		}
 	}
 
@@ -218,12 +246,23 @@ func (c *postingsListLRUShard) Add(
 		}
 	}
 
-	evict := c.evictList.Len() > c.size
+	c.bytes += cachedPostings.sizeBytes
+
 	// Verify size not exceeded.
-	if evict {
+	return c.evictOverBudget()
+}
+
+// evictOverBudget removes the oldest items until both the number of items
+// and the estimated bytes are within budget. Returns true if an eviction
+// occurred.
+func (c *postingsListLRUShard) evictOverBudget() bool {
+	evicted := false
+	for c.evictList.Len() > c.size ||
+		(c.maxBytes > 0 && c.bytes > c.maxBytes && c.evictList.Len() > 0) {
 		c.removeOldest()
+		evicted = true
 	}
-	return evict
+	return evicted
 }
 
 // Get looks up a key's value from the cache.
@@ -294,6 +333,13 @@ func (c *postingsListLRUShard) Len() int {
 	return c.evictList.Len()
 }
 
+// Bytes returns the estimated size in bytes of the items in the cache.
+func (c *postingsListLRUShard) Bytes() int64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.bytes
+}
+
 // removeOldest removes the oldest item from the cache.
 func (c *postingsListLRUShard) removeOldest() {
 	ent := c.evictList.Back()
@@ -306,6 +352,7 @@ func (c *postingsListLRUShard) removeOldest() {
 func (c *postingsListLRUShard) removeElement(e *list.Element) {
 	c.evictList.Remove(e)
 	entry := e.Value.(*entry)
+	c.bytes -= entry.cachedPostings.sizeBytes
 
 	if patterns, ok := c.items[entry.uuid.Array()]; ok {
 		delete(patterns, entry.key)
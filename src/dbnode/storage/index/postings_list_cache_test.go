@@ -31,8 +31,10 @@ import (
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
 	"github.com/m3db/m3/src/x/instrument"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 const (
@@ -186,6 +188,80 @@ func TestPurgeSegment(t *testing.T) {
 	}
 }
 
+func TestSizeAwareEviction(t *testing.T) {
+	var (
+		e0      = testPlEntries[0]
+		e1      = testPlEntries[1]
+		e2      = testPlEntries[2]
+		e0Bytes = estimateCachedPostingsBytes(e0.key.Field, e0.key.Pattern, e0.postingsList)
+		e1Bytes = estimateCachedPostingsBytes(e1.key.Field, e1.key.Pattern, e1.postingsList)
+		e2Bytes = estimateCachedPostingsBytes(e2.key.Field, e2.key.Pattern, e2.postingsList)
+	)
+
+	opts := testPostingListCacheOptions
+	opts.MaxBytes = e0Bytes + e1Bytes + e2Bytes - 1
+	plCache, err := NewPostingsListCache(10, opts)
+	require.NoError(t, err)
+
+	putEntry(t, plCache, 0)
+	putEntry(t, plCache, 1)
+	requireExpectedOrder(t, plCache, []testEntry{e0, e1})
+	require.Equal(t, e0Bytes+e1Bytes, plCache.lru.Bytes())
+
+	// Exceeding the max bytes evicts the least recently used entry even
+	// though the entry count is within the size.
+	putEntry(t, plCache, 2)
+	requireExpectedOrder(t, plCache, []testEntry{e1, e2})
+	require.Equal(t, e1Bytes+e2Bytes, plCache.lru.Bytes())
+
+	plCache.PurgeSegment(e1.segmentUUID)
+	plCache.PurgeSegment(e2.segmentUUID)
+	require.Equal(t, int64(0), plCache.lru.Bytes())
+
+	opts.MaxBytes = -1
+	_, err = NewPostingsListCache(10, opts)
+	require.Error(t, err)
+}
+
+func TestMatcherHashMetrics(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	opts := testPostingListCacheOptions
+	opts.InstrumentOptions = instrument.NewOptions().SetMetricsScope(scope)
+	opts.MatcherHashMetricsLimit = 1
+	plCache, err := NewPostingsListCache(10, opts)
+	require.NoError(t, err)
+
+	var (
+		segmentUUID = uuid.NewUUID()
+		query       = "conjunction(term(__name__, foo))"
+		otherQuery  = "conjunction(term(__name__, bar))"
+		pl          = roaring.NewPostingsList()
+	)
+	_, ok := plCache.GetSearch(segmentUUID, query)
+	require.False(t, ok)
+	plCache.PutSearch(segmentUUID, query, nil, pl)
+	_, ok = plCache.GetSearch(segmentUUID, query)
+	require.True(t, ok)
+
+	// Exceeds the limit of tracked matchers.
+	_, ok = plCache.GetSearch(segmentUUID, otherQuery)
+	require.False(t, ok)
+
+	counters := make(map[string]int64)
+	for _, c := range scope.Snapshot().Counters() {
+		if hash, ok := c.Tags()["matcher_hash"]; ok {
+			counters[c.Name()+"_"+hash] = c.Value()
+		}
+	}
+	queryHash := fmt.Sprintf("%016x", xxhash.Sum64String(query))
+	require.Equal(t, map[string]int64{
+		"matcher_hits_" + queryHash:   1,
+		"matcher_misses_" + queryHash: 1,
+		"matcher_hits_other":          0,
+		"matcher_misses_other":        1,
+	}, counters)
+}
+
 func TestEverthingInsertedCanBeRetrieved(t *testing.T) {
 	plCache, err := NewPostingsListCache(len(testPlEntries), testPostingListCacheOptions)
 	require.NoError(t, err)
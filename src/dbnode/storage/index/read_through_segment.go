@@ -27,6 +27,7 @@ import (
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/search"
 
@@ -59,6 +60,9 @@ type ReadThroughSegment struct {
 
 	opts ReadThroughSegmentOptions
 
+	// memoryBudget is only set if the segment's mmap'd memory is budgeted.
+	memoryBudget *SegmentsMemoryBudget
+
 	closed bool
 }
 
@@ -109,6 +113,9 @@ func (r *ReadThroughSegment) Reader() (segment.Reader, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.memoryBudget != nil {
+		r.memoryBudget.Touch(r)
+	}
 	return newReadThroughSegmentReader(r, reader, r.uuid, r.caches, r.opts), nil
 }
 
@@ -123,6 +130,9 @@ func (r *ReadThroughSegment) Close() error {
 
 	r.closed = true
 
+	if r.memoryBudget != nil {
+		r.memoryBudget.Remove(r)
+	}
 	if cache := r.caches.SegmentPostingsListCache; cache != nil {
 		// Purge segments from the cache before closing the segment to avoid
 		// temporarily having postings lists in the cache whose underlying
@@ -163,6 +173,31 @@ func (r *ReadThroughSegment) ContainsField(field []byte) (bool, error) {
 	return r.segment.ContainsField(field)
 }
 
+// trackMemoryBudget tracks the mmap'd memory of the segment against the budget,
+// this must be called before the segment is queried.
+func (r *ReadThroughSegment) trackMemoryBudget(budget *SegmentsMemoryBudget) error {
+	fstSegment, ok := r.segment.(fst.Segment)
+	if !ok {
+		return nil
+	}
+	sizeBytes, err := segmentSizeBytes(fstSegment)
+	if err != nil {
+		return err
+	}
+	r.memoryBudget = budget
+	budget.Track(r, sizeBytes)
+	return nil
+}
+
+// pinned returns whether the segment is within the memory budget and so its
+// mmap'd data should be kept in memory.
+func (r *ReadThroughSegment) pinned() bool {
+	if r.memoryBudget == nil {
+		return false
+	}
+	return r.memoryBudget.Pinned(r)
+}
+
 // FreeMmap frees the mmapped data if any.
 func (r *ReadThroughSegment) FreeMmap() error {
 	return r.segment.FreeMmap()
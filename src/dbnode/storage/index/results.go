@@ -21,6 +21,7 @@
 package index
 
 import (
+	"bytes"
 	"errors"
 	"sync"
 
@@ -136,6 +137,9 @@ func (r *results) addDocumentWithLock(w doc.Document) (bool, int, error) {
 	if r.opts.FilterID != nil && !r.opts.FilterID(r.reusableID) {
 		return false, r.resultsMap.Len(), nil
 	}
+	if len(r.opts.SearchAfterID) > 0 && bytes.Compare(id, r.opts.SearchAfterID) <= 0 {
+		return false, r.resultsMap.Len(), nil
+	}
 
 	// check if it already exists in the map.
 	if r.resultsMap.Contains(id) {
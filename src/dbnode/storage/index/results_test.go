@@ -113,6 +113,23 @@ func TestResultsInsertBatchOfTwo(t *testing.T) {
 	require.Equal(t, 2, res.TotalDocsCount())
 }
 
+func TestResultsInsertSearchAfterID(t *testing.T) {
+	res := NewQueryResults(nil, QueryResultsOptions{
+		SearchAfterID: []byte("d2"),
+	}, testOpts)
+	size, docsCount, err := res.AddDocuments([]doc.Document{
+		doc.NewDocumentFromMetadata(doc.Metadata{ID: []byte("d1")}),
+		doc.NewDocumentFromMetadata(doc.Metadata{ID: []byte("d2")}),
+		doc.NewDocumentFromMetadata(doc.Metadata{ID: []byte("d3")}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, size)
+	require.Equal(t, 3, docsCount)
+
+	_, ok := res.Map().Get([]byte("d3"))
+	require.True(t, ok)
+}
+
 func TestResultsFirstInsertWins(t *testing.T) {
 	res := NewQueryResults(nil, QueryResultsOptions{}, testOpts)
 	d1 := doc.Metadata{ID: []byte("abc")}
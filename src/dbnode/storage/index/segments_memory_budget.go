@@ -0,0 +1,220 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+var errSegmentsMemoryBudgetNegative = errors.New("segments memory budget must not be negative")
+
+// SegmentsMemoryBudgetOptions is the options struct for the segments memory budget.
+type SegmentsMemoryBudgetOptions struct {
+	// MaxBytes is the maximum number of bytes of the mmap'd index segments
+	// that are kept resident in memory.
+	MaxBytes int64
+	// InstrumentOptions is the instrument options for the budget.
+	InstrumentOptions instrument.Options
+}
+
+// Validate will return an error if the options are not valid.
+func (o SegmentsMemoryBudgetOptions) Validate() error {
+	if o.MaxBytes < 0 {
+		return errSegmentsMemoryBudgetNegative
+	}
+	return nil
+}
+
+// SegmentsMemoryBudget bounds the memory used by the persisted index segments
+// across all namespaces and blocks. The most recently queried segments are
+// pinned in memory while they fit within the budget, any other segments have
+// their mmap'd pages released when the index is ticked and are served from
+// the mmap'd files, so that index memory usage remains predictable with long
+// retention.
+type SegmentsMemoryBudget struct {
+	sync.Mutex
+
+	maxBytes    int64
+	lru         *list.List
+	elems       map[*ReadThroughSegment]*list.Element
+	pinnedBytes int64
+	dirty       bool
+
+	metrics segmentsMemoryBudgetMetrics
+}
+
+type segmentsMemoryBudgetEntry struct {
+	segment   *ReadThroughSegment
+	sizeBytes int64
+	pinned    bool
+}
+
+// NewSegmentsMemoryBudget returns a new segments memory budget.
+func NewSegmentsMemoryBudget(
+	opts SegmentsMemoryBudgetOptions,
+) (*SegmentsMemoryBudget, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	iOpts := opts.InstrumentOptions
+	if iOpts == nil {
+		iOpts = instrument.NewOptions()
+	}
+	b := &SegmentsMemoryBudget{
+		maxBytes: opts.MaxBytes,
+		lru:      list.New(),
+		elems:    make(map[*ReadThroughSegment]*list.Element),
+		metrics:  newSegmentsMemoryBudgetMetrics(iOpts.MetricsScope()),
+	}
+	b.metrics.budgetBytes.Update(float64(opts.MaxBytes))
+	return b, nil
+}
+
+// Track starts tracking a segment of the given size against the budget.
+func (b *SegmentsMemoryBudget) Track(seg *ReadThroughSegment, sizeBytes int64) {
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.elems[seg]; ok {
+		return
+	}
+	// New segments are considered cold until they are queried.
+	b.elems[seg] = b.lru.PushBack(&segmentsMemoryBudgetEntry{
+		segment:   seg,
+		sizeBytes: sizeBytes,
+	})
+	b.dirty = true
+}
+
+// Touch marks a segment as recently queried.
+func (b *SegmentsMemoryBudget) Touch(seg *ReadThroughSegment) {
+	b.Lock()
+	defer b.Unlock()
+	elem, ok := b.elems[seg]
+	if !ok {
+		return
+	}
+	if b.lru.Front() != elem {
+		b.lru.MoveToFront(elem)
+		b.dirty = true
+	}
+}
+
+// Remove stops tracking a segment, usually once it is closed.
+func (b *SegmentsMemoryBudget) Remove(seg *ReadThroughSegment) {
+	b.Lock()
+	defer b.Unlock()
+	elem, ok := b.elems[seg]
+	if !ok {
+		return
+	}
+	b.lru.Remove(elem)
+	delete(b.elems, seg)
+	b.dirty = true
+}
+
+// Pinned returns whether a segment is within the budget and should be kept
+// in memory rather than having its mmap'd pages released.
+func (b *SegmentsMemoryBudget) Pinned(seg *ReadThroughSegment) bool {
+	b.Lock()
+	defer b.Unlock()
+	elem, ok := b.elems[seg]
+	if !ok {
+		return false
+	}
+	b.updateWithLock()
+	return elem.Value.(*segmentsMemoryBudgetEntry).pinned
+}
+
+// PinnedBytes returns the number of bytes of the segments pinned in memory.
+func (b *SegmentsMemoryBudget) PinnedBytes() int64 {
+	b.Lock()
+	defer b.Unlock()
+	b.updateWithLock()
+	return b.pinnedBytes
+}
+
+func (b *SegmentsMemoryBudget) updateWithLock() {
+	if !b.dirty {
+		return
+	}
+	b.dirty = false
+
+	var (
+		pinnedBytes    int64
+		pinnedSegments int
+		full           bool
+	)
+	for elem := b.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*segmentsMemoryBudgetEntry)
+		// Once a segment does not fit all the less recently queried
+		// segments are released too so that only the hottest are pinned.
+		full = full || pinnedBytes+entry.sizeBytes > b.maxBytes
+		entry.pinned = !full
+		if entry.pinned {
+			pinnedBytes += entry.sizeBytes
+			pinnedSegments++
+		}
+	}
+	b.pinnedBytes = pinnedBytes
+
+	b.metrics.pinnedBytes.Update(float64(pinnedBytes))
+	b.metrics.pinnedSegments.Update(float64(pinnedSegments))
+	b.metrics.unpinnedSegments.Update(float64(b.lru.Len() - pinnedSegments))
+}
+
+type segmentsMemoryBudgetMetrics struct {
+	budgetBytes      tally.Gauge
+	pinnedBytes      tally.Gauge
+	pinnedSegments   tally.Gauge
+	unpinnedSegments tally.Gauge
+}
+
+func newSegmentsMemoryBudgetMetrics(scope tally.Scope) segmentsMemoryBudgetMetrics {
+	scope = scope.SubScope("segments-memory-budget")
+	return segmentsMemoryBudgetMetrics{
+		budgetBytes:      scope.Gauge("budget-bytes"),
+		pinnedBytes:      scope.Gauge("pinned-bytes"),
+		pinnedSegments:   scope.Gauge("pinned-segments"),
+		unpinnedSegments: scope.Gauge("unpinned-segments"),
+	}
+}
+
+// segmentSizeBytes returns the size of the mmap'd data of a segment.
+func segmentSizeBytes(seg fst.Segment) (int64, error) {
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	data, err := seg.SegmentData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(data.DocsData.Bytes) + len(data.DocsIdxData.Bytes) +
+		len(data.PostingsData.Bytes) + len(data.FSTTermsData.Bytes) +
+		len(data.FSTFieldsData.Bytes)), nil
+}
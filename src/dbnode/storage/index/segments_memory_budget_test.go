@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	"github.com/m3db/m3/src/x/mmap"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBudgetedSegment(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	budget *SegmentsMemoryBudget,
+	sizeBytes int,
+) (*ReadThroughSegment, *fst.MockSegment) {
+	seg := fst.NewMockSegment(ctrl)
+	seg.EXPECT().SegmentData(gomock.Any()).Return(fst.SegmentData{
+		PostingsData: mmap.Descriptor{Bytes: make([]byte, sizeBytes)},
+	}, nil)
+
+	readThroughSeg := NewReadThroughSegment(seg, ReadThroughSegmentCaches{},
+		defaultReadThroughSegmentOptions)
+	require.NoError(t, readThroughSeg.trackMemoryBudget(budget))
+	return readThroughSeg, seg
+}
+
+func TestSegmentsMemoryBudgetPinsMostRecentlyQueried(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	budget, err := NewSegmentsMemoryBudget(SegmentsMemoryBudgetOptions{MaxBytes: 100})
+	require.NoError(t, err)
+
+	seg1, _ := newTestBudgetedSegment(t, ctrl, budget, 60)
+	seg2, _ := newTestBudgetedSegment(t, ctrl, budget, 30)
+	seg3, mockSeg3 := newTestBudgetedSegment(t, ctrl, budget, 50)
+
+	// Segments that have not been queried are pinned while they fit.
+	require.True(t, seg1.pinned())
+	require.True(t, seg2.pinned())
+	require.False(t, seg3.pinned())
+	require.Equal(t, int64(90), budget.PinnedBytes())
+
+	// Querying a segment makes it the hottest, releasing the segments that
+	// no longer fit.
+	mockSeg3.EXPECT().Reader().Return(segment.NewMockReader(ctrl), nil)
+	_, err = seg3.Reader()
+	require.NoError(t, err)
+	require.True(t, seg3.pinned())
+	require.False(t, seg1.pinned())
+	require.False(t, seg2.pinned())
+	require.Equal(t, int64(50), budget.PinnedBytes())
+
+	// Closing a segment returns its memory to the budget.
+	mockSeg3.EXPECT().Close().Return(nil)
+	require.NoError(t, seg3.Close())
+	require.False(t, seg3.pinned())
+	require.True(t, seg1.pinned())
+	require.True(t, seg2.pinned())
+	require.Equal(t, int64(90), budget.PinnedBytes())
+}
+
+func TestSegmentsMemoryBudgetNegative(t *testing.T) {
+	_, err := NewSegmentsMemoryBudget(SegmentsMemoryBudgetOptions{MaxBytes: -1})
+	require.Error(t, err)
+}
@@ -96,6 +96,12 @@ type QueryOptions struct {
 	IterationOptions IterationOptions
 	// Source is an optional query source.
 	Source []byte
+	// PageAfterID, if set, restricts results to series with IDs that sort
+	// strictly after it, resuming a query paginated by series ID.
+	PageAfterID []byte
+	// PageLimit is an optional limit for the number of series returned per
+	// page when paginating by series ID.
+	PageLimit int
 }
 
 // WideQueryOptions enables users to specify constraints and
@@ -237,6 +243,9 @@ type QueryResultsOptions struct {
 	// NB(r): This is used to filter out results from shards the DB node
 	// node no longer owns but is still included in index segments.
 	FilterID func(id ident.ID) bool
+	// SearchAfterID, if provided, filters out IDs that do not sort strictly
+	// after it so that paginated queries only collect the remaining series.
+	SearchAfterID []byte
 	// IndexBatchCollector collects ID batches in an asynchronous fashion.
 	IndexBatchCollector chan<- ident.IDBatch
 }
@@ -454,10 +463,56 @@ type Block interface {
 	// BackgroundCompact background compacts eligible segments.
 	BackgroundCompact()
 
+	// CompactionPlan returns the background compaction currently planned for
+	// the in-memory segments of the block.
+	CompactionPlan() (CompactionPlan, error)
+
+	// Compact triggers a compaction of the in-memory segments of the block
+	// with the given priority.
+	Compact(priority CompactionPriority) error
+
 	// Close will release any held resources and close the Block.
 	Close() error
 }
 
+// CompactionPriority is the priority of a manually triggered compaction.
+type CompactionPriority uint
+
+const (
+	// NormalCompactionPriority compacts the segments selected by the
+	// background compaction planner right away.
+	NormalCompactionPriority CompactionPriority = iota
+	// HighCompactionPriority compacts all the background segments into a
+	// single segment regardless of the compaction levels of the planner,
+	// consolidating fragmented segments.
+	HighCompactionPriority
+)
+
+// ParseCompactionPriority parses a compaction priority from its string form.
+func ParseCompactionPriority(str string) (CompactionPriority, error) {
+	switch str {
+	case "", "normal":
+		return NormalCompactionPriority, nil
+	case "high":
+		return HighCompactionPriority, nil
+	default:
+		return 0, fmt.Errorf("invalid compaction priority %q, must be normal or high", str)
+	}
+}
+
+// CompactionPlan describes the background compaction planned for the in-memory
+// segments of a block.
+type CompactionPlan struct {
+	BlockStart                  xtime.UnixNano
+	NumForegroundSegments       int
+	NumBackgroundSegments       int
+	BackgroundSegmentsSize      int64
+	NumUnplannedSegments        int
+	CompactingBackground        bool
+	GarbageCollectingBackground bool
+	Tasks                       []compaction.TaskSummary
+}
+
 // EvictMutableSegmentResults returns statistics about the EvictMutableSegments execution.
 type EvictMutableSegmentResults struct {
 	NumMutableSegments int64
@@ -1096,6 +1151,14 @@ type Options interface {
 	// SearchPostingsListCache returns the postings list cache.
 	SearchPostingsListCache() *PostingsListCache
 
+	// SetSegmentsMemoryBudget sets the memory budget of the persisted index
+	// segments, nil if the memory of the segments is not budgeted.
+	SetSegmentsMemoryBudget(value *SegmentsMemoryBudget) Options
+
+	// SegmentsMemoryBudget returns the memory budget of the persisted index
+	// segments, nil if the memory of the segments is not budgeted.
+	SegmentsMemoryBudget() *SegmentsMemoryBudget
+
 	// SetReadThroughSegmentOptions sets the read through segment cache options.
 	SetReadThroughSegmentOptions(value ReadThroughSegmentOptions) Options
 
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var (
+	errNewSeriesHookNameEmpty = errors.New("new series hook name must not be empty")
+	errNewSeriesHookFnNil     = errors.New("new series hook constructor must not be nil")
+
+	newSeriesHookFnsLock sync.RWMutex
+	newSeriesHookFns     = make(map[string]NewSeriesHookFn)
+)
+
+// RegisterNewSeriesHook registers a new series hook so that it can be enabled
+// by name in the config. Hooks are compiled in by importing the package that
+// registers them, typically from its init function.
+func RegisterNewSeriesHook(name string, fn NewSeriesHookFn) error {
+	if name == "" {
+		return errNewSeriesHookNameEmpty
+	}
+	if fn == nil {
+		return errNewSeriesHookFnNil
+	}
+
+	newSeriesHookFnsLock.Lock()
+	defer newSeriesHookFnsLock.Unlock()
+
+	if _, ok := newSeriesHookFns[name]; ok {
+		return fmt.Errorf("new series hook %s is already registered", name)
+	}
+	newSeriesHookFns[name] = fn
+	return nil
+}
+
+// NewRegisteredNewSeriesHook creates the registered new series hooks with the
+// given names as a single hook that rejects a series if any of them reject it,
+// invoking the hooks in order.
+func NewRegisteredNewSeriesHook(
+	names []string,
+	iOpts instrument.Options,
+) (NewSeriesHook, error) {
+	newSeriesHookFnsLock.RLock()
+	defer newSeriesHookFnsLock.RUnlock()
+
+	hooks := make(newSeriesHooks, 0, len(names))
+	for _, name := range names {
+		fn, ok := newSeriesHookFns[name]
+		if !ok {
+			return nil, fmt.Errorf("new series hook %s is not registered", name)
+		}
+		hook, err := fn(iOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not create new series hook %s: %w", name, err)
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+type newSeriesHooks []NewSeriesHook
+
+func (h newSeriesHooks) OnNewSeries(namespace ident.ID, metadata doc.Metadata) error {
+	for _, hook := range h {
+		if err := hook.OnNewSeries(namespace, metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
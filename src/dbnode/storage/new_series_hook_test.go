@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testNewSeriesHook struct {
+	rejectPrefix []byte
+	calls        int
+}
+
+func (h *testNewSeriesHook) OnNewSeries(_ ident.ID, metadata doc.Metadata) error {
+	h.calls++
+	if bytes.HasPrefix(metadata.ID, h.rejectPrefix) {
+		return errors.New("series rejected")
+	}
+	return nil
+}
+
+func TestRegisteredNewSeriesHook(t *testing.T) {
+	first := &testNewSeriesHook{rejectPrefix: []byte("foo")}
+	second := &testNewSeriesHook{rejectPrefix: []byte("bar")}
+	require.NoError(t, RegisterNewSeriesHook("test-first", func(instrument.Options) (NewSeriesHook, error) {
+		return first, nil
+	}))
+	require.NoError(t, RegisterNewSeriesHook("test-second", func(instrument.Options) (NewSeriesHook, error) {
+		return second, nil
+	}))
+	require.Error(t, RegisterNewSeriesHook("test-first", func(instrument.Options) (NewSeriesHook, error) {
+		return first, nil
+	}))
+	require.Error(t, RegisterNewSeriesHook("", func(instrument.Options) (NewSeriesHook, error) {
+		return first, nil
+	}))
+	require.Error(t, RegisterNewSeriesHook("test-nil", nil))
+
+	_, err := NewRegisteredNewSeriesHook([]string{"test-first", "test-unknown"},
+		instrument.NewOptions())
+	require.Error(t, err)
+
+	hook, err := NewRegisteredNewSeriesHook([]string{"test-first", "test-second"},
+		instrument.NewOptions())
+	require.NoError(t, err)
+
+	nsID := ident.StringID("testns")
+	require.NoError(t, hook.OnNewSeries(nsID, doc.Metadata{ID: []byte("baz")}))
+	require.Error(t, hook.OnNewSeries(nsID, doc.Metadata{ID: []byte("bar")}))
+	require.Error(t, hook.OnNewSeries(nsID, doc.Metadata{ID: []byte("foo")}))
+	require.Equal(t, 3, first.calls)
+	// The second hook is not invoked once the first rejects the series.
+	require.Equal(t, 2, second.calls)
+}
+
+func TestShardWriteNewSeriesHook(t *testing.T) {
+	hook := &testNewSeriesHook{rejectPrefix: []byte("blocked")}
+	opts := DefaultTestOptions().SetNewSeriesHook(hook)
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	now := xtime.ToUnixNano(opts.ClockOptions().NowFn()())
+	_, err := shard.Write(ctx, ident.StringID("blocked.series"), now,
+		1.0, xtime.Second, nil, series.WriteOptions{})
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+
+	_, err = shard.Write(ctx, ident.StringID("allowed.series"), now,
+		1.0, xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+
+	// Writes to existing series do not invoke the hook.
+	_, err = shard.Write(ctx, ident.StringID("allowed.series"), now.Add(time.Second),
+		2.0, xtime.Second, nil, series.WriteOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, hook.calls)
+
+	shard.RLock()
+	require.Equal(t, 1, shard.lookup.Len())
+	shard.RUnlock()
+}
+
+func TestShardLoadBlocksNewSeriesHook(t *testing.T) {
+	var (
+		hook          = &testNewSeriesHook{rejectPrefix: []byte("blocked")}
+		opts          = DefaultTestOptions().SetNewSeriesHook(hook)
+		shard         = testDatabaseShard(t, opts)
+		blOpts        = opts.DatabaseBlockOptions()
+		testBlockSize = 2 * time.Hour
+		start         = xtime.Now().Truncate(testBlockSize)
+		sr            = result.NewShardResult(result.NewOptions())
+	)
+	defer shard.Close()
+
+	for _, id := range []string{"blocked.series", "allowed.series"} {
+		data := checked.NewBytes([]byte("123"), nil)
+		data.IncRef()
+		sr.AddBlock(ident.StringID(id), ident.NewTags(ident.StringTag("foo", "bar")),
+			block.NewDatabaseBlock(start, testBlockSize, ts.Segment{Head: data},
+				blOpts, namespace.Context{}))
+	}
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+	require.NoError(t, shard.Bootstrap(ctx, namespace.Context{ID: ident.StringID("foo")}))
+
+	// Series rejected by the hook are skipped rather than failing the load.
+	require.NoError(t, shard.LoadBlocks(sr.AllSeries()))
+	require.Equal(t, 2, hook.calls)
+
+	shard.RLock()
+	defer shard.RUnlock()
+	require.Equal(t, 1, shard.lookup.Len())
+	_, err := shard.lookupEntryWithLock(ident.StringID("allowed.series"))
+	require.NoError(t, err)
+}
@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/ts/writes"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
@@ -136,6 +137,8 @@ type options struct {
 	transformOptions                series.WriteTransformOptions
 	indexOpts                       index.Options
 	repairOpts                      repair.Options
+	divergenceCheckEnabled          bool
+	divergenceCheckInterval         time.Duration
 	newEncoderFn                    encoding.NewEncoderFn
 	newDecoderFn                    encoding.NewDecoderFn
 	bootstrapProcessProvider        bootstrap.ProcessProvider
@@ -167,6 +170,7 @@ type options struct {
 	sourceLoggerBuilder             limits.SourceLoggerBuilder
 	iterationOptions                index.IterationOptions
 	memoryTracker                   MemoryTracker
+	fileSetPins                     FileSetPins
 	mmapReporter                    mmap.Reporter
 	doNotIndexWithFieldsMap         map[string]string
 	namespaceRuntimeOptsMgrRegistry namespace.RuntimeOptionsManagerRegistry
@@ -176,6 +180,7 @@ type options struct {
 	newBackgroundProcessFns         []NewBackgroundProcessFn
 	namespaceHooks                  NamespaceHooks
 	tileAggregator                  TileAggregator
+	newSeriesHook                   NewSeriesHook
 	permitsOptions                  permits.Options
 	limitsOptions                   limits.Options
 }
@@ -218,6 +223,7 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		indexOpts:                index.NewOptions(),
 		repairEnabled:            defaultRepairEnabled,
 		repairOpts:               repair.NewOptions(),
+		divergenceCheckInterval:  defaultDivergenceCheckInterval,
 		bootstrapProcessProvider: defaultBootstrapProcessProvider,
 		poolOpts:                 poolOpts,
 		contextPool: context.NewPool(context.NewOptions().
@@ -246,11 +252,13 @@ func newOptions(poolOpts pool.ObjectPoolOptions) Options {
 		schemaReg:                       namespace.NewSchemaRegistry(false, nil),
 		onColdFlush:                     &noOpColdFlush{},
 		memoryTracker:                   NewMemoryTracker(NewMemoryTrackerOptions(defaultNumLoadedBytesLimit)),
+		fileSetPins:                     NewFileSetPins(),
 		namespaceRuntimeOptsMgrRegistry: namespace.NewRuntimeOptionsManagerRegistry(),
 		mediatorTickInterval:            defaultMediatorTickInterval,
 		wideBatchSize:                   defaultWideBatchSize,
 		namespaceHooks:                  &noopNamespaceHooks{},
 		tileAggregator:                  &noopTileAggregator{},
+		newSeriesHook:                   &noopNewSeriesHook{},
 		permitsOptions:                  permits.NewOptions(),
 		limitsOptions:                   limits.DefaultLimitsOptions(iOpts),
 	}
@@ -281,6 +289,16 @@ func (o *options) Validate() error {
 		}
 	}
 
+	// validate divergence check options
+	if o.DivergenceCheckEnabled() {
+		if o.AdminClient() == nil {
+			return errDivergenceCheckNoAdminClient
+		}
+		if o.DivergenceCheckInterval() <= 0 {
+			return errInvalidDivergenceCheckInterval
+		}
+	}
+
 	// validate indexing options
 	iOpts := o.IndexOptions()
 	if iOpts == nil {
@@ -462,6 +480,26 @@ func (o *options) RepairOptions() repair.Options {
 	return o.repairOpts
 }
 
+func (o *options) SetDivergenceCheckEnabled(value bool) Options {
+	opts := *o
+	opts.divergenceCheckEnabled = value
+	return &opts
+}
+
+func (o *options) DivergenceCheckEnabled() bool {
+	return o.divergenceCheckEnabled
+}
+
+func (o *options) SetDivergenceCheckInterval(value time.Duration) Options {
+	opts := *o
+	opts.divergenceCheckInterval = value
+	return &opts
+}
+
+func (o *options) DivergenceCheckInterval() time.Duration {
+	return o.divergenceCheckInterval
+}
+
 func (o *options) SetEncodingM3TSZPooled() Options {
 	opts := *o
 
@@ -822,6 +860,16 @@ func (o *options) MemoryTracker() MemoryTracker {
 	return o.memoryTracker
 }
 
+func (o *options) SetFileSetPins(value FileSetPins) Options {
+	opts := *o
+	opts.fileSetPins = value
+	return &opts
+}
+
+func (o *options) FileSetPins() FileSetPins {
+	return o.fileSetPins
+}
+
 func (o *options) SetMmapReporter(mmapReporter mmap.Reporter) Options {
 	opts := *o
 	opts.mmapReporter = mmapReporter
@@ -936,6 +984,16 @@ func (o *options) TileAggregator() TileAggregator {
 	return o.tileAggregator
 }
 
+func (o *options) SetNewSeriesHook(value NewSeriesHook) Options {
+	opts := *o
+	opts.newSeriesHook = value
+	return &opts
+}
+
+func (o *options) NewSeriesHook() NewSeriesHook {
+	return o.newSeriesHook
+}
+
 type noOpColdFlush struct{}
 
 func (n *noOpColdFlush) ColdFlushNamespace(Namespace, ColdFlushNsOpts) (OnColdFlushNamespace, error) {
@@ -948,6 +1006,12 @@ func (h *noopNamespaceHooks) OnCreatedNamespace(Namespace, GetNamespaceFn) error
 	return nil
 }
 
+type noopNewSeriesHook struct{}
+
+func (h *noopNewSeriesHook) OnNewSeries(ident.ID, doc.Metadata) error {
+	return nil
+}
+
 type noopTileAggregator struct{}
 
 func (a *noopTileAggregator) AggregateTiles(
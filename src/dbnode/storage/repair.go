@@ -346,16 +346,28 @@ func (r shardRepairer) Repair(
 // into smaller batches (less than one complete block). This would improve the granularity of throttling
 // for clusters where the number of shards is low.
 func (r shardRepairer) loadDataIntoShard(shard databaseShard, data result.ShardResult) error {
+	return loadDataIntoShard(shard, data, r.opts, r.scope)
+}
+
+// loadDataIntoShard loads data fetched from peers into the shard, waiting for
+// outstanding data to be flushed whenever the memory load limit is hit.
+func loadDataIntoShard(
+	shard databaseShard,
+	data result.ShardResult,
+	opts Options,
+	scope tally.Scope,
+) error {
 	var (
-		waitingGauge  = r.scope.Gauge("waiting-for-limit")
-		waitedCounter = r.scope.Counter("waited-for-limit")
+		logger        = opts.InstrumentOptions().Logger()
+		waitingGauge  = scope.Gauge("waiting-for-limit")
+		waitedCounter = scope.Counter("waited-for-limit")
 		doneCh        = make(chan struct{})
 		waiting       bool
 		waitingLock   sync.Mutex
 	)
 	defer close(doneCh)
 
-	// Emit a gauge constantly that indicates whether or not the repair process is blocked waiting.
+	// Emit a gauge constantly that indicates whether or not the load is blocked waiting.
 	go func() {
 		for {
 			select {
@@ -384,8 +396,9 @@ func (r shardRepairer) loadDataIntoShard(shard databaseShard, data result.ShardR
 			waiting = true
 			waitingLock.Unlock()
 			// Wait for some of the outstanding data to be flushed before trying again.
-			r.logger.Info("repair throttled due to memory load limits, waiting for data to be flushed before continuing")
-			r.opts.MemoryTracker().WaitForDec()
+			logger.Info("load throttled due to memory load limits, waiting for data to be flushed before continuing",
+				zap.Uint32("shard", shard.ID()))
+			opts.MemoryTracker().WaitForDec()
 			continue
 		}
 		if err != nil {
@@ -578,6 +591,9 @@ func newDatabaseRepairer(database database, opts Options) (databaseRepairer, err
 	}
 
 	shardRepairer := newShardRepairer(opts, ropts)
+	if ropts.AggregatedFallbackEnabled() {
+		shardRepairer = newAggregatedFallbackShardRepairer(shardRepairer, database, opts)
+	}
 
 	r := &dbRepairer{
 		database:            database,
@@ -65,6 +65,7 @@ type options struct {
 	resultOptions                    result.Options
 	debugShadowComparisonsEnabled    bool
 	debugShadowComparisonsPercentage float64
+	aggregatedFallbackEnabled        bool
 }
 
 // NewOptions creates new bootstrap options
@@ -203,6 +204,16 @@ func (o *options) DebugShadowComparisonsPercentage() float64 {
 	return o.debugShadowComparisonsPercentage
 }
 
+func (o *options) SetAggregatedFallbackEnabled(value bool) Options {
+	opts := *o
+	opts.aggregatedFallbackEnabled = value
+	return &opts
+}
+
+func (o *options) AggregatedFallbackEnabled() bool {
+	return o.aggregatedFallbackEnabled
+}
+
 func (o *options) Validate() error {
 	if len(o.adminClients) == 0 {
 		return errNoAdminClient
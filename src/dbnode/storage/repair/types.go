@@ -400,6 +400,14 @@ type Options interface {
 	// DebugShadowComparisonsPercentage returns the debug shadow comparisons percentage.
 	DebugShadowComparisonsPercentage() float64
 
+	// SetAggregatedFallbackEnabled sets whether blocks of unaggregated namespaces
+	// missing on all replicas are synthesized from aggregated namespaces.
+	SetAggregatedFallbackEnabled(value bool) Options
+
+	// AggregatedFallbackEnabled returns whether blocks of unaggregated namespaces
+	// missing on all replicas are synthesized from aggregated namespaces.
+	AggregatedFallbackEnabled() bool
+
 	// Validate checks if the options are valid.
 	Validate() error
 }
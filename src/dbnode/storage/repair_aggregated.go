@@ -0,0 +1,425 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/storage/repair"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+// aggregatedFallbackFetchBatchSize is the number of series whose block metadata
+// is fetched at a time when looking for missing blocks.
+const aggregatedFallbackFetchBatchSize = 4096
+
+type aggregatedFallbackMetrics struct {
+	missingBlocks         tally.Counter
+	skippedBlocks         tally.Counter
+	synthesizedBlocks     tally.Counter
+	synthesizedDatapoints tally.Counter
+}
+
+func newAggregatedFallbackMetrics(scope tally.Scope) aggregatedFallbackMetrics {
+	return aggregatedFallbackMetrics{
+		missingBlocks:         scope.Counter("missing-blocks"),
+		skippedBlocks:         scope.Counter("skipped-blocks"),
+		synthesizedBlocks:     scope.Counter("synthesized-blocks"),
+		synthesizedDatapoints: scope.Counter("synthesized-datapoints"),
+	}
+}
+
+// aggregatedFallbackShardRepairer repairs shards from their peers, then
+// synthesizes the blocks of unaggregated namespaces that are missing on all
+// replicas from the finest aggregated namespace retaining them, so that the
+// series show coarse datapoints rather than a gap. Synthesized datapoints are
+// marked as such in their annotation.
+//
+// A block is considered missing when a series that is still active in the
+// shard has a flushed block in the previous block start but none in the
+// repaired one once the shard has been repaired from its peers, and only
+// synthesized if the aggregated namespace has datapoints for it that are
+// either unannotated or annotated with annotation payloads.
+type aggregatedFallbackShardRepairer struct {
+	databaseShardRepairer
+
+	database database
+	opts     Options
+	nowFn    clock.NowFn
+	logger   *zap.Logger
+	scope    tally.Scope
+	metrics  aggregatedFallbackMetrics
+}
+
+func newAggregatedFallbackShardRepairer(
+	shardRepairer databaseShardRepairer,
+	database database,
+	opts Options,
+) databaseShardRepairer {
+	iopts := opts.InstrumentOptions()
+	scope := iopts.MetricsScope().SubScope("repair").SubScope("aggregated-fallback")
+	return aggregatedFallbackShardRepairer{
+		databaseShardRepairer: shardRepairer,
+		database:              database,
+		opts:                  opts,
+		nowFn:                 opts.ClockOptions().NowFn(),
+		logger:                iopts.Logger(),
+		scope:                 scope,
+		metrics:               newAggregatedFallbackMetrics(scope),
+	}
+}
+
+func (r aggregatedFallbackShardRepairer) Repair(
+	ctx context.Context,
+	nsCtx namespace.Context,
+	nsMeta namespace.Metadata,
+	tr xtime.Range,
+	shard databaseShard,
+) (repair.MetadataComparisonResult, error) {
+	res, err := r.databaseShardRepairer.Repair(ctx, nsCtx, nsMeta, tr, shard)
+	if err != nil || r.Options().Type() != repair.DefaultRepair {
+		return res, err
+	}
+	if _, aggregated := namespaceResolution(nsMeta); aggregated || nsCtx.Schema != nil {
+		// Only unaggregated namespaces without a schema, whose annotations
+		// can mark datapoints as synthesized, fall back to aggregated ones.
+		return res, nil
+	}
+	if err := r.repairFromAggregated(ctx, nsCtx, nsMeta, tr, shard); err != nil {
+		return res, fmt.Errorf("error repairing from aggregated namespace: %v", err)
+	}
+	return res, nil
+}
+
+func (r aggregatedFallbackShardRepairer) repairFromAggregated(
+	ctx context.Context,
+	nsCtx namespace.Context,
+	nsMeta namespace.Metadata,
+	tr xtime.Range,
+	shard databaseShard,
+) error {
+	source, ok, err := r.fallbackNamespace(tr.Start)
+	if err != nil || !ok {
+		return err
+	}
+
+	var (
+		blockSize = nsMeta.Options().RetentionOptions().BlockSize()
+		results   = result.NewShardResult(r.opts.RepairOptions().ResultOptions())
+	)
+	tr.IterateForward(blockSize, func(blockStart xtime.UnixNano) bool {
+		err = r.synthesizeMissingBlocks(ctx, source, nsCtx, shard, blockStart, blockSize, results)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if results.NumSeries() == 0 {
+		return nil
+	}
+
+	r.logger.Info("synthesized blocks missing on all replicas from aggregated namespace",
+		zap.String("namespace", nsMeta.ID().String()),
+		zap.String("source", source.String()),
+		zap.Uint32("shard", shard.ID()),
+		zap.Int64("numSeries", results.NumSeries()))
+	return loadDataIntoShard(shard, results, r.opts, r.scope)
+}
+
+// fallbackNamespace returns the aggregated namespace with the finest
+// resolution retaining data from the block start.
+func (r aggregatedFallbackShardRepairer) fallbackNamespace(
+	blockStart xtime.UnixNano,
+) (ident.ID, bool, error) {
+	namespaces, err := r.database.OwnedNamespaces()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		now        = xtime.ToUnixNano(r.nowFn())
+		source     ident.ID
+		resolution time.Duration
+	)
+	for _, n := range namespaces {
+		md := n.Metadata()
+		res, aggregated := namespaceResolution(md)
+		if !aggregated {
+			continue
+		}
+		retentionStart := now.Add(-md.Options().RetentionOptions().RetentionPeriod())
+		if blockStart.Before(retentionStart) {
+			continue
+		}
+		if source == nil || res < resolution {
+			source = md.ID()
+			resolution = res
+		}
+	}
+	return source, source != nil, nil
+}
+
+// synthesizeMissingBlocks adds to the results the synthesized block of every
+// active series of the shard with a flushed block in the previous block start
+// but no block in the block start.
+func (r aggregatedFallbackShardRepairer) synthesizeMissingBlocks(
+	ctx context.Context,
+	source ident.ID,
+	nsCtx namespace.Context,
+	shard databaseShard,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	results result.ShardResult,
+) error {
+	present, err := r.seriesWithBlocks(ctx, shard, blockStart, blockSize)
+	if err != nil {
+		return err
+	}
+
+	var (
+		previousStart = blockStart.Add(-blockSize)
+		// NB: only the flushed blocks of the previous block start are read so
+		// that series both in memory and on disk are visited once.
+		opts      = block.FetchBlocksMetadataOptions{OnlyDisk: true}
+		pageToken PageToken
+	)
+	for {
+		page, nextPageToken, err := shard.FetchBlocksMetadataV2(ctx, previousStart,
+			blockStart, aggregatedFallbackFetchBatchSize, pageToken, opts)
+		if err != nil {
+			return err
+		}
+		if page != nil {
+			err = r.synthesizePageBlocks(ctx, source, nsCtx, shard, page, present,
+				blockStart, blockSize, results)
+			page.Close()
+			if err != nil {
+				return err
+			}
+		}
+		if nextPageToken == nil {
+			return nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+func (r aggregatedFallbackShardRepairer) synthesizePageBlocks(
+	ctx context.Context,
+	source ident.ID,
+	nsCtx namespace.Context,
+	shard databaseShard,
+	page block.FetchBlocksMetadataResults,
+	present map[string]struct{},
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	results result.ShardResult,
+) error {
+	for _, res := range page.Results() {
+		if _, ok := present[res.ID.String()]; ok {
+			continue
+		}
+		// Series that are no longer written to are expired from the shard,
+		// their missing blocks are the end of the series rather than a gap.
+		_, active, err := shard.DocRef(res.ID)
+		if err != nil {
+			return err
+		}
+		if !active {
+			continue
+		}
+		r.metrics.missingBlocks.Inc(1)
+
+		// Copy the ID and tags since the page is closed once visited.
+		id := ident.BytesID(append([]byte(nil), res.ID.Bytes()...))
+		var tags ident.Tags
+		if res.Tags != nil {
+			tags, err = convert.TagsFromTagsIter(id, res.Tags, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		bl, exists, err := r.synthesizeBlock(ctx, source, id, blockStart, blockSize, nsCtx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			results.AddBlock(id, tags, bl)
+		}
+	}
+	return nil
+}
+
+// seriesWithBlocks returns the IDs of the series of the shard with a block,
+// in memory or flushed, in the block start.
+func (r aggregatedFallbackShardRepairer) seriesWithBlocks(
+	ctx context.Context,
+	shard databaseShard,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+) (map[string]struct{}, error) {
+	var (
+		series    = make(map[string]struct{})
+		pageToken PageToken
+	)
+	for {
+		page, nextPageToken, err := shard.FetchBlocksMetadataV2(ctx, blockStart,
+			blockStart.Add(blockSize), aggregatedFallbackFetchBatchSize, pageToken,
+			block.FetchBlocksMetadataOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if page != nil {
+			for _, res := range page.Results() {
+				series[res.ID.String()] = struct{}{}
+			}
+			page.Close()
+		}
+		if nextPageToken == nil {
+			return series, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// synthesizeBlock encodes the datapoints of the series within the block from
+// the aggregated namespace, marking them as synthesized.
+func (r aggregatedFallbackShardRepairer) synthesizeBlock(
+	ctx context.Context,
+	source ident.ID,
+	id ident.ID,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+	nsCtx namespace.Context,
+) (block.DatabaseBlock, bool, error) {
+	readCtx := r.opts.ContextPool().Get()
+	defer readCtx.BlockingClose()
+
+	readerIter, err := r.database.ReadEncoded(readCtx, source, id, blockStart, blockStart.Add(blockSize))
+	if err != nil {
+		return nil, false, err
+	}
+	unfiltered, err := readerIter.ToSlices(readCtx)
+	if err != nil {
+		return nil, false, err
+	}
+	readers, err := xio.FilterEmptyBlockReadersSliceOfSlicesInPlace(unfiltered)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(readers) == 0 {
+		return nil, false, nil
+	}
+
+	iter := r.opts.MultiReaderIteratorPool().Get()
+	iter.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(readers), nil)
+	defer iter.Close()
+
+	bopts := r.opts.DatabaseBlockOptions()
+	encoder := bopts.EncoderPool().Get()
+	encoder.Reset(blockStart, bopts.DatabaseBlockAllocSize(), nil)
+
+	var numDatapoints int64
+	for iter.Next() {
+		dp, unit, annot := iter.Current()
+		if dp.TimestampNanos.Before(blockStart) || !dp.TimestampNanos.Before(blockStart.Add(blockSize)) {
+			continue
+		}
+		synthesized, ok, err := synthesizedAnnotation(annot)
+		if err != nil {
+			encoder.Close()
+			return nil, false, err
+		}
+		if !ok {
+			// NB: marking the datapoint as synthesized would replace an
+			// annotation that is not an annotation payload.
+			encoder.Close()
+			r.metrics.skippedBlocks.Inc(1)
+			return nil, false, nil
+		}
+		if err := encoder.Encode(dp, unit, synthesized); err != nil {
+			encoder.Close()
+			return nil, false, err
+		}
+		numDatapoints++
+	}
+	if err := iter.Err(); err != nil {
+		encoder.Close()
+		return nil, false, err
+	}
+	if numDatapoints == 0 {
+		encoder.Close()
+		return nil, false, nil
+	}
+
+	r.metrics.synthesizedBlocks.Inc(1)
+	r.metrics.synthesizedDatapoints.Inc(numDatapoints)
+	bl := bopts.DatabaseBlockPool().Get()
+	bl.Reset(blockStart, blockSize, encoder.Discard(), nsCtx)
+	return bl, true, nil
+}
+
+// synthesizedAnnotation returns the annotation of a datapoint marked as
+// synthesized, preserving the metric type of the original annotation, and
+// false if the original annotation is not an annotation payload.
+func synthesizedAnnotation(annot []byte) ([]byte, bool, error) {
+	var payload annotation.Payload
+	if len(annot) > 0 {
+		if err := payload.Unmarshal(annot); err != nil {
+			return nil, false, nil
+		}
+	}
+	payload.Synthesized = true
+	synthesized, err := payload.Marshal()
+	if err != nil {
+		return nil, false, err
+	}
+	return synthesized, true, nil
+}
+
+// namespaceResolution returns the resolution of the namespace and whether it
+// is aggregated.
+func namespaceResolution(md namespace.Metadata) (time.Duration, bool) {
+	aggOpts := md.Options().AggregationOptions()
+	if aggOpts == nil {
+		return 0, false
+	}
+	for _, agg := range aggOpts.Aggregations() {
+		if agg.Aggregated {
+			return agg.Attributes.Resolution, true
+		}
+	}
+	return 0, false
+}
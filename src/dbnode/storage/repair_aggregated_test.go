@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestSynthesizedAnnotation(t *testing.T) {
+	original, err := (&annotation.Payload{
+		MetricType:        annotation.MetricType_COUNTER,
+		HandleValueResets: true,
+	}).Marshal()
+	require.NoError(t, err)
+
+	for _, input := range [][]byte{nil, original} {
+		annot, ok, err := synthesizedAnnotation(input)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		var payload annotation.Payload
+		require.NoError(t, payload.Unmarshal(annot))
+		require.True(t, payload.Synthesized)
+		if input != nil {
+			require.Equal(t, annotation.MetricType_COUNTER, payload.MetricType)
+			require.True(t, payload.HandleValueResets)
+		}
+	}
+
+	// Annotations that are not annotation payloads are not replaced.
+	_, ok, err := synthesizedAnnotation([]byte{0xff})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestAggregatedFallbackSynthesizesActiveSeriesOnly(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		blockSize  = 2 * time.Hour
+		blockStart = xtime.Now().Truncate(blockSize).Add(-4 * blockSize)
+		source     = ident.StringID("agg")
+		scope      = tally.NewTestScope("", nil)
+		opts       = DefaultTestOptions()
+	)
+	metadataResults := func(ids ...string) block.FetchBlocksMetadataResults {
+		results := block.NewFetchBlocksMetadataResults()
+		for _, id := range ids {
+			results.Add(block.NewFetchBlocksMetadataResult(ident.StringID(id), nil,
+				block.NewFetchBlockMetadataResults()))
+		}
+		return results
+	}
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().
+		FetchBlocksMetadataV2(gomock.Any(), blockStart, blockStart.Add(blockSize),
+			int64(aggregatedFallbackFetchBatchSize), nil, block.FetchBlocksMetadataOptions{}).
+		Return(metadataResults("foo"), nil, nil)
+	// The previous block start is fetched in pages of flushed blocks.
+	gomock.InOrder(
+		shard.EXPECT().
+			FetchBlocksMetadataV2(gomock.Any(), blockStart.Add(-blockSize), blockStart,
+				int64(aggregatedFallbackFetchBatchSize), nil,
+				block.FetchBlocksMetadataOptions{OnlyDisk: true}).
+			Return(metadataResults("foo", "bar"), PageToken("next"), nil),
+		shard.EXPECT().
+			FetchBlocksMetadataV2(gomock.Any(), blockStart.Add(-blockSize), blockStart,
+				int64(aggregatedFallbackFetchBatchSize), PageToken("next"),
+				block.FetchBlocksMetadataOptions{OnlyDisk: true}).
+			Return(metadataResults("baz"), nil, nil),
+	)
+	// Only bar is still active, baz is no longer written to.
+	shard.EXPECT().DocRef(ident.NewIDMatcher("bar")).Return(doc.Metadata{}, true, nil)
+	shard.EXPECT().DocRef(ident.NewIDMatcher("baz")).Return(doc.Metadata{}, false, nil)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().
+		ReadEncoded(gomock.Any(), source, ident.NewIDMatcher("bar"), blockStart, blockStart.Add(blockSize)).
+		Return(&series.FakeBlockReaderIter{}, nil)
+
+	r := aggregatedFallbackShardRepairer{
+		database: db,
+		opts:     opts,
+		metrics:  newAggregatedFallbackMetrics(scope),
+	}
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	results := result.NewShardResult(opts.RepairOptions().ResultOptions())
+	require.NoError(t, r.synthesizeMissingBlocks(ctx, source, namespace.Context{}, shard,
+		blockStart, blockSize, results))
+	// The aggregated namespace has no datapoints for bar.
+	require.Equal(t, int64(0), results.NumSeries())
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["missing-blocks+"].Value())
+}
+
+func TestNamespaceResolution(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("raw"), namespace.NewOptions())
+	require.NoError(t, err)
+	_, aggregated := namespaceResolution(md)
+	require.False(t, aggregated)
+
+	attrs, err := namespace.NewAggregatedAttributes(5*time.Minute, namespace.NewDownsampleOptions(true))
+	require.NoError(t, err)
+	aggOpts := namespace.NewAggregationOptions().
+		SetAggregations([]namespace.Aggregation{namespace.NewAggregatedAggregation(attrs)})
+	md, err = namespace.NewMetadata(ident.StringID("agg"),
+		namespace.NewOptions().SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	resolution, aggregated := namespaceResolution(md)
+	require.True(t, aggregated)
+	require.Equal(t, 5*time.Minute, resolution)
+}
@@ -614,6 +614,24 @@ func (s *dbSeries) OnEvictedFromWiredList(id ident.ID, blockStart xtime.UnixNano
 	}
 }
 
+func (s *dbSeries) EvictCachedBlock(blockStart xtime.UnixNano) {
+	s.Lock()
+	defer s.Unlock()
+
+	block, ok := s.cachedBlocks.BlockAt(blockStart)
+	if !ok {
+		return
+	}
+
+	s.cachedBlocks.RemoveBlockAt(blockStart)
+	// Blocks retrieved from disk are owned by the WiredList when using the
+	// LRU policy, see updateBlocksWithLock.
+	if s.opts.CachePolicy() == CacheLRU && block.WasRetrievedFromDisk() {
+		return
+	}
+	block.Close()
+}
+
 func (s *dbSeries) WarmFlush(
 	ctx context.Context,
 	blockStart xtime.UnixNano,
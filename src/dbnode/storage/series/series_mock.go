@@ -104,6 +104,18 @@ func (mr *MockDatabaseSeriesMockRecorder) ColdFlushBlockStarts(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlushBlockStarts", reflect.TypeOf((*MockDatabaseSeries)(nil).ColdFlushBlockStarts), arg0)
 }
 
+// EvictCachedBlock mocks base method.
+func (m *MockDatabaseSeries) EvictCachedBlock(arg0 time.UnixNano) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictCachedBlock", arg0)
+}
+
+// EvictCachedBlock indicates an expected call of EvictCachedBlock.
+func (mr *MockDatabaseSeriesMockRecorder) EvictCachedBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictCachedBlock", reflect.TypeOf((*MockDatabaseSeries)(nil).EvictCachedBlock), arg0)
+}
+
 // FetchBlocks mocks base method.
 func (m *MockDatabaseSeries) FetchBlocks(arg0 context.Context, arg1 []time.UnixNano, arg2 namespace.Context) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -1337,3 +1337,38 @@ func TestSeriesCloseCacheLRUPolicy(t *testing.T) {
 	series.cachedBlocks = blocks
 	series.Close()
 }
+
+func TestSeriesEvictCachedBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSeriesTestOptions().
+		SetCachePolicy(CacheLRU)
+	series := NewDatabaseSeries(DatabaseSeriesOptions{
+		ID:      ident.StringID("foo"),
+		Options: opts,
+	}).(*dbSeries)
+
+	var (
+		blockSize = opts.RetentionOptions().BlockSize()
+		start     = xtime.Now().Truncate(blockSize)
+	)
+	// Blocks retrieved from disk are closed by the WiredList.
+	diskBlock := block.NewMockDatabaseBlock(ctrl)
+	diskBlock.EXPECT().StartTime().Return(start).AnyTimes()
+	diskBlock.EXPECT().WasRetrievedFromDisk().Return(true)
+	series.cachedBlocks.AddBlock(diskBlock)
+
+	nonDiskBlock := block.NewMockDatabaseBlock(ctrl)
+	nonDiskBlock.EXPECT().StartTime().Return(start.Add(blockSize)).AnyTimes()
+	nonDiskBlock.EXPECT().WasRetrievedFromDisk().Return(false)
+	nonDiskBlock.EXPECT().Close()
+	series.cachedBlocks.AddBlock(nonDiskBlock)
+
+	series.EvictCachedBlock(start.Add(-blockSize))
+	require.Equal(t, 2, series.cachedBlocks.Len())
+
+	series.EvictCachedBlock(start)
+	series.EvictCachedBlock(start.Add(blockSize))
+	require.Equal(t, 0, series.cachedBlocks.Len())
+}
@@ -125,6 +125,10 @@ type DatabaseSeries interface {
 	// (only checks for in-mem buffer data).
 	IsBufferEmptyAtBlockStart(xtime.UnixNano) bool
 
+	// EvictCachedBlock removes the block cached for the block start, e.g. once
+	// the volume it was retrieved from has been replaced.
+	EvictCachedBlock(blockStart xtime.UnixNano)
+
 	// NumActiveBlocks returns the number of active blocks the series currently holds.
 	NumActiveBlocks() int
 
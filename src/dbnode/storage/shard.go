@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"os"
 	"sync"
 	"time"
 
@@ -147,6 +148,7 @@ type dbShard struct {
 	currRuntimeOptions       dbShardRuntimeOptions
 	logger                   *zap.Logger
 	metrics                  dbShardMetrics
+	writeLatency             writeLatencyMetrics
 	tileAggregator           TileAggregator
 	ticking                  bool
 	shard                    uint32
@@ -175,6 +177,7 @@ type dbShardMetrics struct {
 	insertAsyncWriteInternalErrors      tally.Counter
 	insertAsyncWriteInvalidParamsErrors tally.Counter
 	insertAsyncIndexErrors              tally.Counter
+	insertNewSeriesRejected             tally.Counter
 	snapshotTotalLatency                tally.Timer
 	snapshotCheckNeedsSnapshotLatency   tally.Timer
 	snapshotPrepareLatency              tally.Timer
@@ -212,6 +215,10 @@ func newDatabaseShardMetrics(shardID uint32, scope tally.Scope) dbShardMetrics {
 			"error_type":    "reverse-index",
 			"suberror_type": "write-batch-error",
 		}).Counter(insertErrorName),
+		insertNewSeriesRejected: scope.Tagged(map[string]string{
+			"error_type":    "insert-series",
+			"suberror_type": "new-series-rejected",
+		}).Counter(insertErrorName),
 		snapshotTotalLatency:              snapshotScope.Timer("total-latency"),
 		snapshotCheckNeedsSnapshotLatency: snapshotScope.Timer("check-needs-snapshot-latency"),
 		snapshotPrepareLatency:            snapshotScope.Timer("prepare-latency"),
@@ -233,6 +240,9 @@ type shardFlushState struct {
 	sync.RWMutex
 	statesByTime map[xtime.UnixNano]fileOpState
 	initialized  bool
+	// evictedBefore is the block start before which the data filesets were
+	// evicted to reclaim disk space, those blocks are no longer retrievable.
+	evictedBefore xtime.UnixNano
 }
 
 func newShardFlushState() shardFlushState {
@@ -272,7 +282,7 @@ func newDatabaseShard(
 		newFSMergeWithMemFn:  newFSMergeWithMem,
 		filesetsFn:           fs.DataFiles,
 		filesetPathsBeforeFn: fs.DataFileSetsBefore,
-		deleteFilesFn:        fs.DeleteFiles,
+		deleteFilesFn:        opts.FileSetPins().DeleteFiles,
 		snapshotFilesFn:      fs.SnapshotFiles,
 		sleepFn:              time.Sleep,
 		newReaderFn:          fs.NewReader,
@@ -284,6 +294,7 @@ func newDatabaseShard(
 		indexEnabled:         namespaceMetadata.Options().IndexOptions().Enabled(),
 		logger:               opts.InstrumentOptions().Logger(),
 		metrics:              newDatabaseShardMetrics(shard, scope),
+		writeLatency:         newWriteLatencyMetrics(opts.InstrumentOptions()),
 		tileAggregator:       opts.TileAggregator(),
 	}
 	s.insertQueue = newDatabaseShardInsertQueue(s.insertSeriesBatch,
@@ -377,9 +388,19 @@ func (s *dbShard) hasWarmFlushed(blockStart xtime.UnixNano) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if s.isEvicted(blockStart) {
+		return false, nil
+	}
 	return s.warmStatusIsRetrievable(flushState.WarmStatus), nil
 }
 
+func (s *dbShard) isEvicted(blockStart xtime.UnixNano) bool {
+	s.flushState.RLock()
+	evicted := blockStart.Before(s.flushState.evictedBefore)
+	s.flushState.RUnlock()
+	return evicted
+}
+
 func (s *dbShard) warmStatusIsRetrievable(status warmStatus) bool {
 	if !statusIsRetrievable(status.DataFlushed) {
 		return false
@@ -903,11 +924,14 @@ func (s *dbShard) writeAndIndex(
 	shouldReverseIndex bool,
 ) (SeriesWrite, error) {
 	// Prepare write
+	start := s.nowFn()
 	entry, opts, err := s.TryRetrieveSeriesAndIncrementReaderWriterCount(id)
 	if err != nil {
 		return SeriesWrite{}, err
 	}
 
+	segmentStart := s.nowFn()
+	s.writeLatency.seriesLookup.Record(segmentStart.Sub(start))
 	writable := entry != nil
 
 	// If no entry and we are not writing new series asynchronously.
@@ -934,6 +958,10 @@ func (s *dbShard) writeAndIndex(
 		}
 		writable = true
 
+		now := s.nowFn()
+		s.writeLatency.seriesInsertWait.Record(now.Sub(segmentStart))
+		segmentStart = now
+
 		// NB(r): We just indexed this series if shouldReverseIndex was true
 		shouldReverseIndex = false
 	}
@@ -953,6 +981,7 @@ func (s *dbShard) writeAndIndex(
 		// synchronously and all downstream code will copy anthing they need to maintain
 		// a reference to.
 		wasWritten, _, err = entry.Series.Write(ctx, timestamp, value, unit, annotation, wOpts)
+		s.writeLatency.buffer.Record(s.nowFn().Sub(segmentStart))
 		// Load series metadata before decrementing the writer count
 		// to ensure this metadata is snapshotted at a consistent state
 		// NB(r): We explicitly do not place the series ID back into a
@@ -1325,6 +1354,11 @@ func (s *dbShard) insertSeriesAsyncBatched(
 		return insertAsyncResult{}, err
 	}
 
+	if err := s.onNewSeries(entry); err != nil {
+		entry.Series.Close()
+		return insertAsyncResult{}, err
+	}
+
 	wg, err := s.insertQueue.Insert(dbShardInsert{
 		entry: entry,
 		opts:  opts,
@@ -1337,6 +1371,20 @@ func (s *dbShard) insertSeriesAsyncBatched(
 	}, err
 }
 
+// onNewSeries invokes the new series hook for an entry about to be inserted,
+// returning an invalid params error if the hook rejects the series.
+func (s *dbShard) onNewSeries(entry *Entry) error {
+	err := s.opts.NewSeriesHook().OnNewSeries(s.namespace.ID(), entry.Series.Metadata())
+	if err == nil {
+		return nil
+	}
+	s.metrics.insertNewSeriesRejected.Inc(1)
+	if !xerrors.IsInvalidParams(err) {
+		err = xerrors.NewInvalidParamsError(err)
+	}
+	return err
+}
+
 type insertSyncType uint8
 
 // nolint: varcheck, unused
@@ -1370,6 +1418,11 @@ func (s *dbShard) insertSeriesSync(
 		return nil, err
 	}
 
+	if err := s.onNewSeries(newEntry); err != nil {
+		newEntry.Series.Close()
+		return nil, err
+	}
+
 	s.Lock()
 	unlocked := false
 	defer func() {
@@ -2146,6 +2199,13 @@ func (s *dbShard) loadBlock(
 					enqueuedAt: s.nowFn(),
 				},
 			})
+		if err != nil && xerrors.IsInvalidParams(err) {
+			// The series was rejected by the new series hook, skip loading
+			// its block rather than failing the load of every other series.
+			block.Close()
+			result.canFinalizeTags = true
+			return result, nil
+		}
 		if err != nil {
 			return result, err
 		}
@@ -2632,6 +2692,48 @@ func (s *dbShard) CleanupExpiredFileSets(earliestToRetain xtime.UnixNano) error
 	return s.deleteFilesFn(expired)
 }
 
+func (s *dbShard) EvictDataFileSetsBefore(blockStart xtime.UnixNano) (int64, error) {
+	// Mark the blocks as evicted first so that reads stop retrieving them and
+	// the seekers holding their files open are closed.
+	s.flushState.Lock()
+	if blockStart.After(s.flushState.evictedBefore) {
+		s.flushState.evictedBefore = blockStart
+	}
+	s.flushState.Unlock()
+	if s.DatabaseBlockRetriever != nil {
+		s.DatabaseBlockRetriever.EvictBefore(s.ID(), blockStart)
+	}
+
+	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
+	if err != nil {
+		return 0, fmt.Errorf("encountered errors when getting fileset files for prefix %s namespace %s shard %d: %v",
+			filePathPrefix, s.namespace.ID(), s.ID(), err)
+	}
+
+	var (
+		freed    int64
+		multiErr = xerrors.NewMultiError()
+	)
+	for _, fileset := range filesets {
+		if !fileset.ID.BlockStart.Before(blockStart) {
+			continue
+		}
+		for _, filePath := range fileset.AbsoluteFilePaths {
+			var size int64
+			if info, err := os.Stat(filePath); err == nil {
+				size = info.Size()
+			}
+			if err := s.deleteFilesFn([]string{filePath}); err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+			freed += size
+		}
+	}
+	return freed, multiErr.FinalError()
+}
+
 func (s *dbShard) CleanupCompactedFileSets() error {
 	filePathPrefix := s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
 	filesets, err := s.filesetsFn(filePathPrefix, s.namespace.ID(), s.ID())
@@ -2708,6 +2810,173 @@ func (s *dbShard) AggregateTiles(
 	return processedTileCount, nil
 }
 
+func (s *dbShard) ReplaceFlushedBlock(
+	blockStart xtime.UnixNano,
+	seriesToWrite *result.Map,
+	flushPreparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+) (bool, error) {
+	// We don't replace data when the shard is still bootstrapping.
+	s.RLock()
+	if s.bootstrapState != Bootstrapped {
+		s.RUnlock()
+		return false, errShardNotBootstrappedToFlush
+	}
+	s.RUnlock()
+
+	// Only blocks that have been warm flushed can be replaced, the warm writes
+	// of any other block are yet to be flushed to its first volume.
+	hasWarmFlushed, err := s.hasWarmFlushed(blockStart)
+	if err != nil || !hasWarmFlushed {
+		return false, err
+	}
+
+	coldVersion, err := s.RetrievableBlockColdVersion(blockStart)
+	if err != nil {
+		return false, err
+	}
+	nextVersion := coldVersion + 1
+	prepareOpts := persist.DataPrepareOptions{
+		NamespaceMetadata: s.namespace,
+		Shard:             s.ID(),
+		BlockStart:        blockStart,
+		VolumeIndex:       nextVersion,
+		DeleteIfExists:    false,
+		FileSetType:       persist.FileSetFlushType,
+	}
+	prepared, err := flushPreparer.PrepareData(prepareOpts)
+	if err != nil {
+		return false, err
+	}
+
+	var multiErr xerrors.MultiError
+	ctx := s.contextPool.Get()
+	for _, elem := range seriesToWrite.Iter() {
+		dbBlocks := elem.Value()
+		dbBlock, ok := dbBlocks.Blocks.BlockAt(blockStart)
+		if !ok {
+			continue
+		}
+		ctx.Reset()
+		err := s.persistReplacementBlock(ctx, dbBlocks.ID, dbBlocks.Tags, dbBlock, prepared.Persist)
+		ctx.BlockingCloseReset()
+		if err != nil {
+			// If we encounter an error when persisting a series, don't continue as
+			// the volume is discarded anyway.
+			multiErr = multiErr.Add(err)
+			break
+		}
+	}
+	ctx.BlockingClose()
+
+	if err := prepared.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	if err := multiErr.FinalError(); err != nil {
+		// Remove the incomplete volume so that it is not picked up when the
+		// flush states are next read from disk.
+		fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+		if err := fs.DeleteFileSetAt(fsOpts.FilePathPrefix(), s.namespace.ID(),
+			s.ID(), blockStart, nextVersion); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+		return false, multiErr.FinalError()
+	}
+
+	// Make sure the series written are in the shard and the reverse index
+	// before making the volume readable.
+	for _, elem := range seriesToWrite.Iter() {
+		dbBlocks := elem.Value()
+		if _, ok := dbBlocks.Blocks.BlockAt(blockStart); !ok {
+			continue
+		}
+		if err := s.ensureReplacedSeries(dbBlocks.ID, dbBlocks.Tags, blockStart); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	// Switch the readers over to the new volume, the writes of the block that
+	// are still buffered are merged into it by the next cold flush.
+	if err := s.finishWriting(blockStart, nextVersion, false); err != nil {
+		return false, err
+	}
+
+	// Blocks cached from the previous volumes no longer reflect what is on disk.
+	s.forEachShardEntry(func(entry *Entry) bool {
+		entry.Series.EvictCachedBlock(blockStart)
+		return true
+	})
+
+	return true, multiErr.FinalError()
+}
+
+func (s *dbShard) persistReplacementBlock(
+	ctx context.Context,
+	id ident.ID,
+	tags ident.Tags,
+	dbBlock block.DatabaseBlock,
+	persistFn persist.DataFn,
+) error {
+	reader, err := dbBlock.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	segment, err := reader.Segment()
+	if err != nil {
+		return err
+	}
+	if segment.Len() == 0 {
+		return nil
+	}
+	checksum, err := dbBlock.Checksum()
+	if err != nil {
+		return err
+	}
+
+	metadata := persist.NewMetadataFromIDAndTags(id, tags, persist.MetadataOptions{})
+	return persistFn(metadata, segment, checksum)
+}
+
+func (s *dbShard) ensureReplacedSeries(
+	id ident.ID,
+	tags ident.Tags,
+	blockStart xtime.UnixNano,
+) error {
+	entry, shardOpts, err := s.TryRetrieveSeriesAndIncrementReaderWriterCount(id)
+	if err != nil && err != errShardEntryNotFound {
+		return err
+	}
+	if entry == nil {
+		entry, err = s.insertSeriesSync(id, convert.NewTagsMetadataResolver(tags),
+			insertSyncOptions{
+				insertType:      insertSyncIncReaderWriterCount,
+				hasPendingIndex: s.reverseIndex != nil,
+				pendingIndex: dbShardPendingIndex{
+					timestamp:  blockStart,
+					enqueuedAt: s.nowFn(),
+				},
+			})
+		if err != nil && xerrors.IsInvalidParams(err) {
+			// The series was rejected by the new series hook, its data is still
+			// readable by ID.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry.DecrementReaderWriterCount()
+		return nil
+	}
+	defer entry.DecrementReaderWriterCount()
+
+	if s.reverseIndex != nil &&
+		entry.NeedsIndexUpdate(s.reverseIndex.BlockStartForWriteTime(blockStart)) {
+		return s.insertSeriesForIndexingAsyncBatched(entry, blockStart,
+			shardOpts.WriteNewSeriesAsync)
+	}
+	return nil
+}
+
 func (s *dbShard) BootstrapState() BootstrapState {
 	s.RLock()
 	bs := s.bootstrapState
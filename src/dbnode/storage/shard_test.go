@@ -750,6 +750,102 @@ func TestShardColdFlushNoMergeIfNothingDirty(t *testing.T) {
 	}
 }
 
+func TestShardReplaceFlushedBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	now := xtime.Now()
+	nowFn := func() time.Time {
+		return now.ToTime()
+	}
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir)
+	opts = opts.
+		SetClockOptions(opts.ClockOptions().SetNowFn(nowFn)).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts))
+
+	blockSize := opts.SeriesOptions().RetentionOptions().BlockSize()
+	shard := testDatabaseShard(t, opts)
+	defer shard.Close()
+	// Rate limits are read from the runtime options by the persist manager.
+	fsOpts = fsOpts.SetRuntimeOptionsManager(runtime.NewOptionsManager())
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	nsCtx := namespace.Context{ID: shard.namespace.ID()}
+	require.NoError(t, shard.Bootstrap(ctx, nsCtx))
+
+	t0 := now.Truncate(blockSize).Add(-10 * blockSize)
+	t1 := t0.Add(blockSize)
+	// Only t0 has been warm flushed, so only t0 can be replaced.
+	shard.markWarmDataFlushStateSuccess(t0)
+
+	// Blocks cached by the series of the shard are evicted once replaced.
+	local := series.NewMockDatabaseSeries(ctrl)
+	local.EXPECT().ID().Return(ident.StringID("bar")).AnyTimes()
+	local.EXPECT().EvictCachedBlock(t0)
+	shard.list.PushBack(NewEntry(NewEntryOptions{
+		Series: local,
+	}))
+
+	data := result.NewShardResult(result.NewOptions())
+	defer data.Close()
+	segment := ts.NewSegment(checked.NewBytes([]byte{1, 2, 3}, nil), nil, 0, ts.FinalizeNone)
+	data.AddBlock(ident.StringID("foo"), ident.NewTags(ident.StringTag("a", "b")),
+		block.NewDatabaseBlock(t0, blockSize, segment, opts.DatabaseBlockOptions(), nsCtx))
+
+	pm, err := fs.NewPersistManager(fsOpts)
+	require.NoError(t, err)
+	defer pm.Close()
+	flushPersist, err := pm.StartFlushPersist()
+	require.NoError(t, err)
+
+	replaced, err := shard.ReplaceFlushedBlock(t1, data.AllSeries(), flushPersist, nsCtx)
+	require.NoError(t, err)
+	require.False(t, replaced)
+
+	replaced, err = shard.ReplaceFlushedBlock(t0, data.AllSeries(), flushPersist, nsCtx)
+	require.NoError(t, err)
+	require.True(t, replaced)
+	require.NoError(t, flushPersist.DoneFlush())
+
+	// The new volume is readable and holds only the replacement data.
+	coldVersion, err := shard.RetrievableBlockColdVersion(t0)
+	require.NoError(t, err)
+	require.Equal(t, 1, coldVersion)
+	coldVersion, err = shard.RetrievableBlockColdVersion(t1)
+	require.NoError(t, err)
+	require.Equal(t, 0, coldVersion)
+
+	reader, err := fs.NewReader(opts.BytesPool(), fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   shard.namespace.ID(),
+			Shard:       shard.ID(),
+			BlockStart:  t0,
+			VolumeIndex: 1,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+	require.Equal(t, 1, reader.Entries())
+	id, _, _, _, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, "foo", id.String())
+
+	// The replaced series is now part of the shard.
+	_, exists, err := shard.DocRef(ident.StringID("foo"))
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
 func newMergerTestFn(
 	_ fs.DataFileSetReader,
 	_ int,
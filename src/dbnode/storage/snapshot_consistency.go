@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
+	"go.uber.org/zap"
+)
+
+type filesetFilesFn func(
+	filePathPrefix string, namespace ident.ID, shard uint32,
+) (fs.FileSetFilesSlice, error)
+
+func (d *db) SnapshotConsistencyPoint(
+	namespace ident.ID,
+) (SnapshotConsistencyPoint, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		return SnapshotConsistencyPoint{}, err
+	}
+
+	snapshotTime := xtime.ToUnixNano(d.nowFn())
+	snapshotID, commitLog, err := d.mediator.Snapshot(snapshotTime)
+	if err != nil {
+		return SnapshotConsistencyPoint{}, err
+	}
+
+	var (
+		filePathPrefix = d.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix()
+		ownedShards    = n.OwnedShards()
+		shards         = make([]uint32, 0, len(ownedShards))
+	)
+	for _, s := range ownedShards {
+		shards = append(shards, s.ID())
+	}
+
+	// Pin the volumes as they are read so that cleanups do not delete them
+	// before backup tooling has copied them.
+	var volumes []SnapshotConsistencyVolume
+	err = d.opts.FileSetPins().Pin(snapshotID.String(), func() ([]string, error) {
+		var (
+			filePaths []string
+			err       error
+		)
+		volumes, filePaths, err = snapshotConsistencyVolumes(filePathPrefix, n.ID(),
+			shards, snapshotID, fs.DataFiles, fs.SnapshotFiles)
+		return filePaths, err
+	})
+	if err != nil {
+		return SnapshotConsistencyPoint{}, err
+	}
+
+	d.log.Info("snapshot consistency point created",
+		zap.Stringer("namespace", n.ID()),
+		zap.String("snapshotID", snapshotID.String()),
+		zap.Int64("commitLogIndex", commitLog.Index),
+		zap.Int("volumes", len(volumes)))
+
+	return SnapshotConsistencyPoint{
+		SnapshotID:   snapshotID,
+		SnapshotTime: snapshotTime,
+		CommitLog:    commitLog,
+		Volumes:      volumes,
+	}, nil
+}
+
+func (d *db) ReleaseSnapshotConsistencyPoint(snapshotID uuid.UUID) error {
+	if !d.opts.FileSetPins().Release(snapshotID.String()) {
+		return xerrors.NewInvalidParamsError(
+			fmt.Errorf("snapshot consistency point %s not found", snapshotID))
+	}
+	return nil
+}
+
+// snapshotConsistencyVolumes returns the latest complete flushed volume of
+// each block and the complete snapshot volumes with the given snapshot ID,
+// along with the paths of their files.
+func snapshotConsistencyVolumes(
+	filePathPrefix string,
+	namespace ident.ID,
+	shards []uint32,
+	snapshotID uuid.UUID,
+	dataFilesFn filesetFilesFn,
+	snapshotFilesFn filesetFilesFn,
+) ([]SnapshotConsistencyVolume, []string, error) {
+	var (
+		volumes   []SnapshotConsistencyVolume
+		filePaths []string
+	)
+	for _, shard := range shards {
+		dataFiles, err := dataFilesFn(filePathPrefix, namespace, shard)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"err reading data files for ns: %s and shard: %d, err: %w",
+				namespace, shard, err)
+		}
+
+		// NB: collect block starts up front since looking up the latest
+		// volume of a block sorts the data files in place.
+		var (
+			blockStarts = make([]xtime.UnixNano, 0, len(dataFiles))
+			seen        = make(map[xtime.UnixNano]struct{}, len(dataFiles))
+		)
+		for _, f := range dataFiles {
+			if _, ok := seen[f.ID.BlockStart]; ok {
+				continue
+			}
+			seen[f.ID.BlockStart] = struct{}{}
+			blockStarts = append(blockStarts, f.ID.BlockStart)
+		}
+
+		for _, blockStart := range blockStarts {
+			latest, ok := dataFiles.LatestVolumeForBlock(blockStart)
+			if !ok {
+				continue
+			}
+			volumes = append(volumes, SnapshotConsistencyVolume{
+				FileSetType: persist.FileSetFlushType,
+				Shard:       shard,
+				BlockStart:  blockStart,
+				VolumeIndex: latest.ID.VolumeIndex,
+			})
+			filePaths = append(filePaths, latest.AbsoluteFilePaths...)
+		}
+
+		snapshotFiles, err := snapshotFilesFn(filePathPrefix, namespace, shard)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"err reading snapshot files for ns: %s and shard: %d, err: %w",
+				namespace, shard, err)
+		}
+
+		for _, f := range snapshotFiles {
+			_, id, err := f.SnapshotTimeAndID()
+			if err != nil || !uuid.Equal(id, snapshotID) {
+				// Snapshot files of other snapshots are not part of the
+				// consistency point and are cleaned up independently.
+				continue
+			}
+			if !f.HasCompleteCheckpointFile() {
+				continue
+			}
+			volumes = append(volumes, SnapshotConsistencyVolume{
+				FileSetType: persist.FileSetSnapshotType,
+				Shard:       shard,
+				BlockStart:  f.ID.BlockStart,
+				VolumeIndex: f.ID.VolumeIndex,
+			})
+			filePaths = append(filePaths, f.AbsoluteFilePaths...)
+		}
+	}
+
+	return volumes, filePaths, nil
+}
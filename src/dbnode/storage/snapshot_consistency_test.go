@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotConsistencyVolumes(t *testing.T) {
+	var (
+		nsID       = ident.StringID("metrics")
+		snapshotID = uuid.NewUUID()
+		otherID    = uuid.NewUUID()
+		blockStart = xtime.Now().Truncate(time.Hour)
+		prevBlock  = blockStart.Add(-time.Hour)
+	)
+
+	dataFile := func(blockStart xtime.UnixNano, volume int, complete bool) fs.FileSetFile {
+		f := fs.FileSetFile{
+			ID: fs.FileSetFileIdentifier{
+				Namespace:   nsID,
+				BlockStart:  blockStart,
+				VolumeIndex: volume,
+			},
+			AbsoluteFilePaths:               []string{"data/fileset-data.db"},
+			CachedHasCompleteCheckpointFile: fs.EvalFalse,
+		}
+		if complete {
+			f.CachedHasCompleteCheckpointFile = fs.EvalTrue
+		}
+		return f
+	}
+	snapshotFile := func(blockStart xtime.UnixNano, volume int, id uuid.UUID) fs.FileSetFile {
+		return fs.FileSetFile{
+			ID: fs.FileSetFileIdentifier{
+				Namespace:   nsID,
+				BlockStart:  blockStart,
+				VolumeIndex: volume,
+			},
+			AbsoluteFilePaths:               []string{"snapshots/fileset-data.db"},
+			CachedSnapshotTime:              blockStart,
+			CachedSnapshotID:                id,
+			CachedHasCompleteCheckpointFile: fs.EvalTrue,
+		}
+	}
+
+	dataFilesFn := func(_ string, _ ident.ID, shard uint32) (fs.FileSetFilesSlice, error) {
+		if shard != 1 {
+			return nil, nil
+		}
+		// Latest volume of the previous block is incomplete.
+		return fs.FileSetFilesSlice{
+			dataFile(prevBlock, 2, false),
+			dataFile(blockStart, 0, true),
+			dataFile(prevBlock, 0, true),
+			dataFile(prevBlock, 1, true),
+		}, nil
+	}
+	snapshotFilesFn := func(_ string, _ ident.ID, shard uint32) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{
+			snapshotFile(blockStart, 0, otherID),
+			snapshotFile(blockStart, 1, snapshotID),
+		}, nil
+	}
+
+	volumes, filePaths, err := snapshotConsistencyVolumes("", nsID, []uint32{1, 2},
+		snapshotID, dataFilesFn, snapshotFilesFn)
+	require.NoError(t, err)
+	require.Equal(t, []SnapshotConsistencyVolume{
+		{
+			FileSetType: persist.FileSetFlushType,
+			Shard:       1,
+			BlockStart:  prevBlock,
+			VolumeIndex: 1,
+		},
+		{
+			FileSetType: persist.FileSetFlushType,
+			Shard:       1,
+			BlockStart:  blockStart,
+			VolumeIndex: 0,
+		},
+		{
+			FileSetType: persist.FileSetSnapshotType,
+			Shard:       1,
+			BlockStart:  blockStart,
+			VolumeIndex: 1,
+		},
+		{
+			FileSetType: persist.FileSetSnapshotType,
+			Shard:       2,
+			BlockStart:  blockStart,
+			VolumeIndex: 1,
+		},
+	}, volumes)
+	require.Equal(t, []string{
+		"data/fileset-data.db",
+		"data/fileset-data.db",
+		"snapshots/fileset-data.db",
+		"snapshots/fileset-data.db",
+	}, filePaths)
+}
+
+func TestSnapshotConsistencyVolumesError(t *testing.T) {
+	expectedErr := errors.New("read error")
+	dataFilesFn := func(_ string, _ ident.ID, _ uint32) (fs.FileSetFilesSlice, error) {
+		return nil, expectedErr
+	}
+
+	_, _, err := snapshotConsistencyVolumes("", ident.StringID("metrics"), []uint32{1},
+		uuid.NewUUID(), dataFilesFn, dataFilesFn)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, expectedErr))
+}
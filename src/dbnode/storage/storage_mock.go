@@ -60,6 +60,7 @@ import (
 	time0 "github.com/m3db/m3/src/x/time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
 )
 
 // MockIndexedErrorHandler is a mock of IndexedErrorHandler interface.
@@ -205,6 +206,20 @@ func (mr *MockDatabaseMockRecorder) Bootstrap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bootstrap", reflect.TypeOf((*MockDatabase)(nil).Bootstrap))
 }
 
+// BootstrapShardsFromDonor mocks base method.
+func (m *MockDatabase) BootstrapShardsFromDonor(namespace ident.ID, shards []uint32, donor string, tr time0.Range) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapShardsFromDonor", namespace, shards, donor, tr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BootstrapShardsFromDonor indicates an expected call of BootstrapShardsFromDonor.
+func (mr *MockDatabaseMockRecorder) BootstrapShardsFromDonor(namespace, shards, donor, tr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapShardsFromDonor", reflect.TypeOf((*MockDatabase)(nil).BootstrapShardsFromDonor), namespace, shards, donor, tr)
+}
+
 // BootstrapState mocks base method.
 func (m *MockDatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -233,6 +248,34 @@ func (mr *MockDatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDatabase)(nil).Close))
 }
 
+// CommitLogStatus mocks base method.
+func (m *MockDatabase) CommitLogStatus() commitlog.QueueStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitLogStatus")
+	ret0, _ := ret[0].(commitlog.QueueStatus)
+	return ret0
+}
+
+// CommitLogStatus indicates an expected call of CommitLogStatus.
+func (mr *MockDatabaseMockRecorder) CommitLogStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitLogStatus", reflect.TypeOf((*MockDatabase)(nil).CommitLogStatus))
+}
+
+// CompactIndex mocks base method.
+func (m *MockDatabase) CompactIndex(namespace ident.ID, blockStart time0.UnixNano, priority index.CompactionPriority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactIndex", namespace, blockStart, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactIndex indicates an expected call of CompactIndex.
+func (mr *MockDatabaseMockRecorder) CompactIndex(namespace, blockStart, priority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactIndex", reflect.TypeOf((*MockDatabase)(nil).CompactIndex), namespace, blockStart, priority)
+}
+
 // FetchBlocks mocks base method.
 func (m *MockDatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -279,6 +322,21 @@ func (mr *MockDatabaseMockRecorder) FlushState(namespace, shardID, blockStart in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushState", reflect.TypeOf((*MockDatabase)(nil).FlushState), namespace, shardID, blockStart)
 }
 
+// IndexCompactionPlans mocks base method.
+func (m *MockDatabase) IndexCompactionPlans(namespace ident.ID) ([]index.CompactionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCompactionPlans", namespace)
+	ret0, _ := ret[0].([]index.CompactionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCompactionPlans indicates an expected call of IndexCompactionPlans.
+func (mr *MockDatabaseMockRecorder) IndexCompactionPlans(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCompactionPlans", reflect.TypeOf((*MockDatabase)(nil).IndexCompactionPlans), namespace)
+}
+
 // IsBootstrapped mocks base method.
 func (m *MockDatabase) IsBootstrapped() bool {
 	m.ctrl.T.Helper()
@@ -408,6 +466,20 @@ func (mr *MockDatabaseMockRecorder) ReadEncoded(ctx, namespace, id, start, end i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadEncoded", reflect.TypeOf((*MockDatabase)(nil).ReadEncoded), ctx, namespace, id, start, end)
 }
 
+// ReleaseSnapshotConsistencyPoint mocks base method.
+func (m *MockDatabase) ReleaseSnapshotConsistencyPoint(snapshotID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSnapshotConsistencyPoint", snapshotID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSnapshotConsistencyPoint indicates an expected call of ReleaseSnapshotConsistencyPoint.
+func (mr *MockDatabaseMockRecorder) ReleaseSnapshotConsistencyPoint(snapshotID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSnapshotConsistencyPoint", reflect.TypeOf((*MockDatabase)(nil).ReleaseSnapshotConsistencyPoint), snapshotID)
+}
+
 // Repair mocks base method.
 func (m *MockDatabase) Repair() error {
 	m.ctrl.T.Helper()
@@ -436,6 +508,21 @@ func (mr *MockDatabaseMockRecorder) ShardSet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardSet", reflect.TypeOf((*MockDatabase)(nil).ShardSet))
 }
 
+// SnapshotConsistencyPoint mocks base method.
+func (m *MockDatabase) SnapshotConsistencyPoint(namespace ident.ID) (SnapshotConsistencyPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotConsistencyPoint", namespace)
+	ret0, _ := ret[0].(SnapshotConsistencyPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotConsistencyPoint indicates an expected call of SnapshotConsistencyPoint.
+func (mr *MockDatabaseMockRecorder) SnapshotConsistencyPoint(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotConsistencyPoint", reflect.TypeOf((*MockDatabase)(nil).SnapshotConsistencyPoint), namespace)
+}
+
 // Terminate mocks base method.
 func (m *MockDatabase) Terminate() error {
 	m.ctrl.T.Helper()
@@ -644,6 +731,20 @@ func (mr *MockdatabaseMockRecorder) Bootstrap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bootstrap", reflect.TypeOf((*Mockdatabase)(nil).Bootstrap))
 }
 
+// BootstrapShardsFromDonor mocks base method.
+func (m *Mockdatabase) BootstrapShardsFromDonor(namespace ident.ID, shards []uint32, donor string, tr time0.Range) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapShardsFromDonor", namespace, shards, donor, tr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BootstrapShardsFromDonor indicates an expected call of BootstrapShardsFromDonor.
+func (mr *MockdatabaseMockRecorder) BootstrapShardsFromDonor(namespace, shards, donor, tr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapShardsFromDonor", reflect.TypeOf((*Mockdatabase)(nil).BootstrapShardsFromDonor), namespace, shards, donor, tr)
+}
+
 // BootstrapState mocks base method.
 func (m *Mockdatabase) BootstrapState() DatabaseBootstrapState {
 	m.ctrl.T.Helper()
@@ -672,6 +773,34 @@ func (mr *MockdatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*Mockdatabase)(nil).Close))
 }
 
+// CommitLogStatus mocks base method.
+func (m *Mockdatabase) CommitLogStatus() commitlog.QueueStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitLogStatus")
+	ret0, _ := ret[0].(commitlog.QueueStatus)
+	return ret0
+}
+
+// CommitLogStatus indicates an expected call of CommitLogStatus.
+func (mr *MockdatabaseMockRecorder) CommitLogStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitLogStatus", reflect.TypeOf((*Mockdatabase)(nil).CommitLogStatus))
+}
+
+// CompactIndex mocks base method.
+func (m *Mockdatabase) CompactIndex(namespace ident.ID, blockStart time0.UnixNano, priority index.CompactionPriority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactIndex", namespace, blockStart, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactIndex indicates an expected call of CompactIndex.
+func (mr *MockdatabaseMockRecorder) CompactIndex(namespace, blockStart, priority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactIndex", reflect.TypeOf((*Mockdatabase)(nil).CompactIndex), namespace, blockStart, priority)
+}
+
 // FetchBlocks mocks base method.
 func (m *Mockdatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -718,6 +847,21 @@ func (mr *MockdatabaseMockRecorder) FlushState(namespace, shardID, blockStart in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushState", reflect.TypeOf((*Mockdatabase)(nil).FlushState), namespace, shardID, blockStart)
 }
 
+// IndexCompactionPlans mocks base method.
+func (m *Mockdatabase) IndexCompactionPlans(namespace ident.ID) ([]index.CompactionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexCompactionPlans", namespace)
+	ret0, _ := ret[0].([]index.CompactionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IndexCompactionPlans indicates an expected call of IndexCompactionPlans.
+func (mr *MockdatabaseMockRecorder) IndexCompactionPlans(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexCompactionPlans", reflect.TypeOf((*Mockdatabase)(nil).IndexCompactionPlans), namespace)
+}
+
 // IsBootstrapped mocks base method.
 func (m *Mockdatabase) IsBootstrapped() bool {
 	m.ctrl.T.Helper()
@@ -862,6 +1006,20 @@ func (mr *MockdatabaseMockRecorder) ReadEncoded(ctx, namespace, id, start, end i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadEncoded", reflect.TypeOf((*Mockdatabase)(nil).ReadEncoded), ctx, namespace, id, start, end)
 }
 
+// ReleaseSnapshotConsistencyPoint mocks base method.
+func (m *Mockdatabase) ReleaseSnapshotConsistencyPoint(snapshotID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSnapshotConsistencyPoint", snapshotID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSnapshotConsistencyPoint indicates an expected call of ReleaseSnapshotConsistencyPoint.
+func (mr *MockdatabaseMockRecorder) ReleaseSnapshotConsistencyPoint(snapshotID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSnapshotConsistencyPoint", reflect.TypeOf((*Mockdatabase)(nil).ReleaseSnapshotConsistencyPoint), snapshotID)
+}
+
 // Repair mocks base method.
 func (m *Mockdatabase) Repair() error {
 	m.ctrl.T.Helper()
@@ -890,6 +1048,21 @@ func (mr *MockdatabaseMockRecorder) ShardSet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardSet", reflect.TypeOf((*Mockdatabase)(nil).ShardSet))
 }
 
+// SnapshotConsistencyPoint mocks base method.
+func (m *Mockdatabase) SnapshotConsistencyPoint(namespace ident.ID) (SnapshotConsistencyPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotConsistencyPoint", namespace)
+	ret0, _ := ret[0].(SnapshotConsistencyPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotConsistencyPoint indicates an expected call of SnapshotConsistencyPoint.
+func (mr *MockdatabaseMockRecorder) SnapshotConsistencyPoint(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotConsistencyPoint", reflect.TypeOf((*Mockdatabase)(nil).SnapshotConsistencyPoint), namespace)
+}
+
 // Terminate mocks base method.
 func (m *Mockdatabase) Terminate() error {
 	m.ctrl.T.Helper()
@@ -2104,6 +2277,21 @@ func (mr *MockdatabaseShardMockRecorder) DocRef(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DocRef", reflect.TypeOf((*MockdatabaseShard)(nil).DocRef), id)
 }
 
+// EvictDataFileSetsBefore mocks base method.
+func (m *MockdatabaseShard) EvictDataFileSetsBefore(blockStart time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictDataFileSetsBefore", blockStart)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvictDataFileSetsBefore indicates an expected call of EvictDataFileSetsBefore.
+func (mr *MockdatabaseShardMockRecorder) EvictDataFileSetsBefore(blockStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictDataFileSetsBefore", reflect.TypeOf((*MockdatabaseShard)(nil).EvictDataFileSetsBefore), blockStart)
+}
+
 // FetchBlocks mocks base method.
 func (m *MockdatabaseShard) FetchBlocks(ctx context.Context, id ident.ID, starts []time0.UnixNano, nsCtx namespace.Context) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -2319,6 +2507,21 @@ func (mr *MockdatabaseShardMockRecorder) ReadEncoded(ctx, id, start, end, nsCtx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadEncoded", reflect.TypeOf((*MockdatabaseShard)(nil).ReadEncoded), ctx, id, start, end, nsCtx)
 }
 
+// ReplaceFlushedBlock mocks base method.
+func (m *MockdatabaseShard) ReplaceFlushedBlock(blockStart time0.UnixNano, series *result.Map, flush persist.FlushPreparer, nsCtx namespace.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceFlushedBlock", blockStart, series, flush, nsCtx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceFlushedBlock indicates an expected call of ReplaceFlushedBlock.
+func (mr *MockdatabaseShardMockRecorder) ReplaceFlushedBlock(blockStart, series, flush, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFlushedBlock", reflect.TypeOf((*MockdatabaseShard)(nil).ReplaceFlushedBlock), blockStart, series, flush, nsCtx)
+}
+
 // Repair mocks base method.
 func (m *MockdatabaseShard) Repair(ctx context.Context, nsCtx namespace.Context, nsMeta namespace.Metadata, tr time0.Range, repairer databaseShardRepairer) (repair.MetadataComparisonResult, error) {
 	m.ctrl.T.Helper()
@@ -2678,6 +2881,35 @@ func (mr *MockNamespaceIndexMockRecorder) ColdFlush(shards interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockNamespaceIndex)(nil).ColdFlush), shards)
 }
 
+// Compact mocks base method.
+func (m *MockNamespaceIndex) Compact(blockStart time0.UnixNano, priority index.CompactionPriority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", blockStart, priority)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockNamespaceIndexMockRecorder) Compact(blockStart, priority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockNamespaceIndex)(nil).Compact), blockStart, priority)
+}
+
+// CompactionPlans mocks base method.
+func (m *MockNamespaceIndex) CompactionPlans() ([]index.CompactionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactionPlans")
+	ret0, _ := ret[0].([]index.CompactionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactionPlans indicates an expected call of CompactionPlans.
+func (mr *MockNamespaceIndexMockRecorder) CompactionPlans() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactionPlans", reflect.TypeOf((*MockNamespaceIndex)(nil).CompactionPlans))
+}
+
 // DebugMemorySegments mocks base method.
 func (m *MockNamespaceIndex) DebugMemorySegments(opts DebugMemorySegmentsOptions) error {
 	m.ctrl.T.Helper()
@@ -2692,6 +2924,18 @@ func (mr *MockNamespaceIndexMockRecorder) DebugMemorySegments(opts interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugMemorySegments", reflect.TypeOf((*MockNamespaceIndex)(nil).DebugMemorySegments), opts)
 }
 
+// EvictBlocksBefore mocks base method.
+func (m *MockNamespaceIndex) EvictBlocksBefore(t time0.UnixNano) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictBlocksBefore", t)
+}
+
+// EvictBlocksBefore indicates an expected call of EvictBlocksBefore.
+func (mr *MockNamespaceIndexMockRecorder) EvictBlocksBefore(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBlocksBefore", reflect.TypeOf((*MockNamespaceIndex)(nil).EvictBlocksBefore), t)
+}
+
 // Query mocks base method.
 func (m *MockNamespaceIndex) Query(ctx context.Context, query index.Query, opts index.QueryOptions) (index.QueryResult, error) {
 	m.ctrl.T.Helper()
@@ -3030,6 +3274,22 @@ func (mr *MockdatabaseFlushManagerMockRecorder) Report() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseFlushManager)(nil).Report))
 }
 
+// Snapshot mocks base method.
+func (m *MockdatabaseFlushManager) Snapshot(startTime time0.UnixNano) (uuid.UUID, persist.CommitLogFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", startTime)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(persist.CommitLogFile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockdatabaseFlushManagerMockRecorder) Snapshot(startTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseFlushManager)(nil).Snapshot), startTime)
+}
+
 // MockdatabaseCleanupManager is a mock of databaseCleanupManager interface.
 type MockdatabaseCleanupManager struct {
 	ctrl     *gomock.Controller
@@ -3199,6 +3459,22 @@ func (mr *MockdatabaseFileSystemManagerMockRecorder) Run(t interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockdatabaseFileSystemManager)(nil).Run), t)
 }
 
+// Snapshot mocks base method.
+func (m *MockdatabaseFileSystemManager) Snapshot(t time0.UnixNano) (uuid.UUID, persist.CommitLogFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", t)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(persist.CommitLogFile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockdatabaseFileSystemManagerMockRecorder) Snapshot(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseFileSystemManager)(nil).Snapshot), t)
+}
+
 // Status mocks base method.
 func (m *MockdatabaseFileSystemManager) Status() fileOpStatus {
 	m.ctrl.T.Helper()
@@ -3790,6 +4066,22 @@ func (mr *MockdatabaseMediatorMockRecorder) Report() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseMediator)(nil).Report))
 }
 
+// Snapshot mocks base method.
+func (m *MockdatabaseMediator) Snapshot(t time0.UnixNano) (uuid.UUID, persist.CommitLogFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", t)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(persist.CommitLogFile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockdatabaseMediatorMockRecorder) Snapshot(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockdatabaseMediator)(nil).Snapshot), t)
+}
+
 // Tick mocks base method.
 func (m *MockdatabaseMediator) Tick(forceType forceType, startTime time0.UnixNano) error {
 	m.ctrl.T.Helper()
@@ -4177,6 +4469,34 @@ func (mr *MockOptionsMockRecorder) DatabaseSeriesPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DatabaseSeriesPool", reflect.TypeOf((*MockOptions)(nil).DatabaseSeriesPool))
 }
 
+// DivergenceCheckEnabled mocks base method.
+func (m *MockOptions) DivergenceCheckEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DivergenceCheckEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DivergenceCheckEnabled indicates an expected call of DivergenceCheckEnabled.
+func (mr *MockOptionsMockRecorder) DivergenceCheckEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DivergenceCheckEnabled", reflect.TypeOf((*MockOptions)(nil).DivergenceCheckEnabled))
+}
+
+// DivergenceCheckInterval mocks base method.
+func (m *MockOptions) DivergenceCheckInterval() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DivergenceCheckInterval")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// DivergenceCheckInterval indicates an expected call of DivergenceCheckInterval.
+func (mr *MockOptionsMockRecorder) DivergenceCheckInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DivergenceCheckInterval", reflect.TypeOf((*MockOptions)(nil).DivergenceCheckInterval))
+}
+
 // DoNotIndexWithFieldsMap mocks base method.
 func (m *MockOptions) DoNotIndexWithFieldsMap() map[string]string {
 	m.ctrl.T.Helper()
@@ -4261,6 +4581,20 @@ func (mr *MockOptionsMockRecorder) FetchBlocksMetadataResultsPool() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataResultsPool", reflect.TypeOf((*MockOptions)(nil).FetchBlocksMetadataResultsPool))
 }
 
+// FileSetPins mocks base method.
+func (m *MockOptions) FileSetPins() FileSetPins {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FileSetPins")
+	ret0, _ := ret[0].(FileSetPins)
+	return ret0
+}
+
+// FileSetPins indicates an expected call of FileSetPins.
+func (mr *MockOptionsMockRecorder) FileSetPins() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileSetPins", reflect.TypeOf((*MockOptions)(nil).FileSetPins))
+}
+
 // ForceColdWritesEnabled mocks base method.
 func (m *MockOptions) ForceColdWritesEnabled() bool {
 	m.ctrl.T.Helper()
@@ -4457,6 +4791,20 @@ func (mr *MockOptionsMockRecorder) NamespaceRuntimeOptionsManagerRegistry() *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceRuntimeOptionsManagerRegistry", reflect.TypeOf((*MockOptions)(nil).NamespaceRuntimeOptionsManagerRegistry))
 }
 
+// NewSeriesHook mocks base method.
+func (m *MockOptions) NewSeriesHook() NewSeriesHook {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSeriesHook")
+	ret0, _ := ret[0].(NewSeriesHook)
+	return ret0
+}
+
+// NewSeriesHook indicates an expected call of NewSeriesHook.
+func (mr *MockOptionsMockRecorder) NewSeriesHook() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSeriesHook", reflect.TypeOf((*MockOptions)(nil).NewSeriesHook))
+}
+
 // OnColdFlush mocks base method.
 func (m *MockOptions) OnColdFlush() OnColdFlush {
 	m.ctrl.T.Helper()
@@ -4821,6 +5169,34 @@ func (mr *MockOptionsMockRecorder) SetDatabaseSeriesPool(value interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDatabaseSeriesPool", reflect.TypeOf((*MockOptions)(nil).SetDatabaseSeriesPool), value)
 }
 
+// SetDivergenceCheckEnabled mocks base method.
+func (m *MockOptions) SetDivergenceCheckEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDivergenceCheckEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDivergenceCheckEnabled indicates an expected call of SetDivergenceCheckEnabled.
+func (mr *MockOptionsMockRecorder) SetDivergenceCheckEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDivergenceCheckEnabled", reflect.TypeOf((*MockOptions)(nil).SetDivergenceCheckEnabled), value)
+}
+
+// SetDivergenceCheckInterval mocks base method.
+func (m *MockOptions) SetDivergenceCheckInterval(value time.Duration) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDivergenceCheckInterval", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetDivergenceCheckInterval indicates an expected call of SetDivergenceCheckInterval.
+func (mr *MockOptionsMockRecorder) SetDivergenceCheckInterval(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDivergenceCheckInterval", reflect.TypeOf((*MockOptions)(nil).SetDivergenceCheckInterval), value)
+}
+
 // SetDoNotIndexWithFieldsMap mocks base method.
 func (m *MockOptions) SetDoNotIndexWithFieldsMap(value map[string]string) Options {
 	m.ctrl.T.Helper()
@@ -4919,6 +5295,20 @@ func (mr *MockOptionsMockRecorder) SetFetchBlocksMetadataResultsPool(value inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFetchBlocksMetadataResultsPool", reflect.TypeOf((*MockOptions)(nil).SetFetchBlocksMetadataResultsPool), value)
 }
 
+// SetFileSetPins mocks base method.
+func (m *MockOptions) SetFileSetPins(value FileSetPins) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFileSetPins", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFileSetPins indicates an expected call of SetFileSetPins.
+func (mr *MockOptionsMockRecorder) SetFileSetPins(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFileSetPins", reflect.TypeOf((*MockOptions)(nil).SetFileSetPins), value)
+}
+
 // SetForceColdWritesEnabled mocks base method.
 func (m *MockOptions) SetForceColdWritesEnabled(value bool) Options {
 	m.ctrl.T.Helper()
@@ -5115,6 +5505,20 @@ func (mr *MockOptionsMockRecorder) SetNamespaceRuntimeOptionsManagerRegistry(val
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNamespaceRuntimeOptionsManagerRegistry", reflect.TypeOf((*MockOptions)(nil).SetNamespaceRuntimeOptionsManagerRegistry), value)
 }
 
+// SetNewSeriesHook mocks base method.
+func (m *MockOptions) SetNewSeriesHook(value NewSeriesHook) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNewSeriesHook", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetNewSeriesHook indicates an expected call of SetNewSeriesHook.
+func (mr *MockOptionsMockRecorder) SetNewSeriesHook(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNewSeriesHook", reflect.TypeOf((*MockOptions)(nil).SetNewSeriesHook), value)
+}
+
 // SetOnColdFlush mocks base method.
 func (m *MockOptions) SetOnColdFlush(value OnColdFlush) Options {
 	m.ctrl.T.Helper()
@@ -5552,6 +5956,71 @@ func (mr *MockMemoryTrackerMockRecorder) WaitForDec() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForDec", reflect.TypeOf((*MockMemoryTracker)(nil).WaitForDec))
 }
 
+// MockFileSetPins is a mock of FileSetPins interface.
+type MockFileSetPins struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileSetPinsMockRecorder
+}
+
+// MockFileSetPinsMockRecorder is the mock recorder for MockFileSetPins.
+type MockFileSetPinsMockRecorder struct {
+	mock *MockFileSetPins
+}
+
+// NewMockFileSetPins creates a new mock instance.
+func NewMockFileSetPins(ctrl *gomock.Controller) *MockFileSetPins {
+	mock := &MockFileSetPins{ctrl: ctrl}
+	mock.recorder = &MockFileSetPinsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileSetPins) EXPECT() *MockFileSetPinsMockRecorder {
+	return m.recorder
+}
+
+// DeleteFiles mocks base method.
+func (m *MockFileSetPins) DeleteFiles(filePaths []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFiles", filePaths)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFiles indicates an expected call of DeleteFiles.
+func (mr *MockFileSetPinsMockRecorder) DeleteFiles(filePaths interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFiles", reflect.TypeOf((*MockFileSetPins)(nil).DeleteFiles), filePaths)
+}
+
+// Pin mocks base method.
+func (m *MockFileSetPins) Pin(owner string, listFn func() ([]string, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pin", owner, listFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pin indicates an expected call of Pin.
+func (mr *MockFileSetPinsMockRecorder) Pin(owner, listFn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockFileSetPins)(nil).Pin), owner, listFn)
+}
+
+// Release mocks base method.
+func (m *MockFileSetPins) Release(owner string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", owner)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockFileSetPinsMockRecorder) Release(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockFileSetPins)(nil).Release), owner)
+}
+
 // MockTileAggregator is a mock of TileAggregator interface.
 type MockTileAggregator struct {
 	ctrl     *gomock.Controller
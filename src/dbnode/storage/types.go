@@ -57,6 +57,8 @@ import (
 	"github.com/m3db/m3/src/x/mmap"
 	"github.com/m3db/m3/src/x/pool"
 	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
 )
 
 // PageToken is an opaque paging token.
@@ -226,6 +228,17 @@ type Database interface {
 	// Bootstrap bootstraps the database.
 	Bootstrap() error
 
+	// BootstrapShardsFromDonor validates the request and starts replacing the
+	// flushed data of the given shards of a namespace within the time range
+	// with the data of the designated donor host only, rather than that of a
+	// quorum of peers, in the background.
+	BootstrapShardsFromDonor(
+		namespace ident.ID,
+		shards []uint32,
+		donor string,
+		tr xtime.Range,
+	) error
+
 	// IsBootstrapped determines whether the database is bootstrapped.
 	IsBootstrapped() bool
 
@@ -237,6 +250,9 @@ type Database interface {
 	// IsOverloaded determines whether the database is overloaded.
 	IsOverloaded() bool
 
+	// CommitLogStatus returns the status of the commit log queue.
+	CommitLogStatus() commitlog.QueueStatus
+
 	// Repair will issue a repair and return nil on success or error on error.
 	Repair() error
 
@@ -252,6 +268,27 @@ type Database interface {
 
 	// AggregateTiles does large tile aggregation from source namespace to target namespace.
 	AggregateTiles(ctx context.Context, sourceNsID, targetNsID ident.ID, opts AggregateTilesOptions) (int64, error)
+
+	// SnapshotConsistencyPoint forces a commit log rotation and snapshot and
+	// returns the volumes of the given namespace that make up a consistent cut,
+	// which are pinned until the consistency point is released.
+	SnapshotConsistencyPoint(namespace ident.ID) (SnapshotConsistencyPoint, error)
+
+	// ReleaseSnapshotConsistencyPoint releases the volumes pinned by the
+	// consistency point with the given snapshot ID.
+	ReleaseSnapshotConsistencyPoint(snapshotID uuid.UUID) error
+
+	// IndexCompactionPlans returns the background compaction planned for the
+	// in-memory segments of each index block of the given namespace.
+	IndexCompactionPlans(namespace ident.ID) ([]index.CompactionPlan, error)
+
+	// CompactIndex triggers a compaction of the in-memory segments of the
+	// index block of the given namespace starting at the given block start.
+	CompactIndex(
+		namespace ident.ID,
+		blockStart xtime.UnixNano,
+		priority index.CompactionPriority,
+	) error
 }
 
 // database is the internal database interface.
@@ -644,6 +681,11 @@ type databaseShard interface {
 	// CleanupExpiredFileSets removes expired fileset files.
 	CleanupExpiredFileSets(earliestToRetain xtime.UnixNano) error
 
+	// EvictDataFileSetsBefore deletes the data filesets of the blocks before
+	// the given block start to reclaim disk space, the blocks are no longer
+	// read once evicted. It returns the number of bytes freed.
+	EvictDataFileSetsBefore(blockStart xtime.UnixNano) (int64, error)
+
 	// CleanupCompactedFileSets removes fileset files that have been compacted,
 	// meaning that there exists a more recent, superset, fully persisted
 	// fileset for that block.
@@ -681,6 +723,17 @@ type databaseShard interface {
 
 	// LatestVolume returns the latest volume for the combination of shard+blockStart.
 	LatestVolume(blockStart xtime.UnixNano) (int, error)
+
+	// ReplaceFlushedBlock replaces the flushed data of the block with the
+	// blocks of the given series by writing them to a new volume, which is
+	// made readable atomically. Blocks that have not been warm flushed yet
+	// are not replaced, which is reported by returning false.
+	ReplaceFlushedBlock(
+		blockStart xtime.UnixNano,
+		series *result.Map,
+		flush persist.FlushPreparer,
+		nsCtx namespace.Context,
+	) (bool, error)
 }
 
 // ShardSnapshotResult is a result from a shard snapshot.
@@ -754,6 +807,11 @@ type NamespaceIndex interface {
 	// using the provided `t` as the frame of reference.
 	CleanupExpiredFileSets(t xtime.UnixNano) error
 
+	// EvictBlocksBefore drops the blocks that end before the given time on
+	// the next tick, and their filesets on the next cleanup, to reclaim disk
+	// space ahead of the retention period.
+	EvictBlocksBefore(t xtime.UnixNano)
+
 	// CleanupCorruptedFileSets removes corrupted fileset files.
 	CleanupCorruptedFileSets() error
 
@@ -785,6 +843,14 @@ type NamespaceIndex interface {
 	// BackgroundCompact background compacts eligible segments.
 	BackgroundCompact()
 
+	// CompactionPlans returns the background compaction planned for the
+	// in-memory segments of each index block.
+	CompactionPlans() ([]index.CompactionPlan, error)
+
+	// Compact triggers a compaction of the in-memory segments of the index
+	// blocks starting at the given block start with the given priority.
+	Compact(blockStart xtime.UnixNano, priority index.CompactionPriority) error
+
 	// Close will release the index resources and close the index.
 	Close() error
 }
@@ -901,6 +967,10 @@ type databaseFlushManager interface {
 	// successful snapshot, if any.
 	LastSuccessfulSnapshotStartTime() (xtime.UnixNano, bool)
 
+	// Snapshot rotates the commit log and snapshots in-memory data, returning
+	// the snapshot ID and the commit log that became active for the snapshot.
+	Snapshot(startTime xtime.UnixNano) (uuid.UUID, persist.CommitLogFile, error)
+
 	// Report reports runtime information.
 	Report()
 }
@@ -938,6 +1008,11 @@ type databaseFileSystemManager interface {
 	// returning true if those operations are performed, and false otherwise.
 	Run(t xtime.UnixNano) bool
 
+	// Snapshot rotates the commit log and snapshots in-memory data if no other
+	// file operations are in progress, returning the snapshot ID and the
+	// commit log that became active for the snapshot.
+	Snapshot(t xtime.UnixNano) (uuid.UUID, persist.CommitLogFile, error)
+
 	// Report reports runtime information.
 	Report()
 
@@ -1066,6 +1141,11 @@ type databaseMediator interface {
 	// successful snapshot, if any.
 	LastSuccessfulSnapshotStartTime() (xtime.UnixNano, bool)
 
+	// Snapshot rotates the commit log and snapshots in-memory data if no other
+	// file operations are in progress, returning the snapshot ID and the
+	// commit log that became active for the snapshot.
+	Snapshot(t xtime.UnixNano) (uuid.UUID, persist.CommitLogFile, error)
+
 	// EnqueueMutuallyExclusiveFn enqueues function to be executed mutually exclusively,
 	// when file operations are idle.
 	EnqueueMutuallyExclusiveFn(fn func()) error
@@ -1180,6 +1260,20 @@ type Options interface {
 	// RepairOptions returns the repair options.
 	RepairOptions() repair.Options
 
+	// SetDivergenceCheckEnabled sets whether or not to periodically compare
+	// block checksums with replicas and report divergence metrics.
+	SetDivergenceCheckEnabled(value bool) Options
+
+	// DivergenceCheckEnabled returns whether or not to periodically compare
+	// block checksums with replicas and report divergence metrics.
+	DivergenceCheckEnabled() bool
+
+	// SetDivergenceCheckInterval sets the interval between divergence checks.
+	SetDivergenceCheckInterval(value time.Duration) Options
+
+	// DivergenceCheckInterval returns the interval between divergence checks.
+	DivergenceCheckInterval() time.Duration
+
 	// SetBootstrapProcessProvider sets the bootstrap process provider for the database.
 	SetBootstrapProcessProvider(value bootstrap.ProcessProvider) Options
 
@@ -1359,6 +1453,12 @@ type Options interface {
 	// MemoryTracker returns the MemoryTracker.
 	MemoryTracker() MemoryTracker
 
+	// SetFileSetPins sets the FileSetPins.
+	SetFileSetPins(value FileSetPins) Options
+
+	// FileSetPins returns the FileSetPins.
+	FileSetPins() FileSetPins
+
 	// SetMmapReporter sets the mmap reporter.
 	SetMmapReporter(mmapReporter mmap.Reporter) Options
 
@@ -1415,6 +1515,14 @@ type Options interface {
 	// TileAggregator returns the TileAggregator.
 	TileAggregator() TileAggregator
 
+	// SetNewSeriesHook sets the hook invoked before a new series is created,
+	// either for a write or when loading its blocks.
+	SetNewSeriesHook(value NewSeriesHook) Options
+
+	// NewSeriesHook returns the hook invoked before a new series is created,
+	// either for a write or when loading its blocks.
+	NewSeriesHook() NewSeriesHook
+
 	// PermitsOptions returns the permits options.
 	PermitsOptions() permits.Options
 
@@ -1452,6 +1560,44 @@ type MemoryTracker interface {
 	WaitForDec()
 }
 
+// FileSetPins pins the files of fileset volumes so that cleanups do not delete
+// them, e.g. while they are copied by backup tooling.
+type FileSetPins interface {
+	// Pin pins the files returned by the list function for the owner, none of
+	// the files can be deleted between being listed and pinned.
+	Pin(owner string, listFn func() ([]string, error)) error
+
+	// Release releases the files pinned for the owner, returning false if the
+	// owner has not pinned any.
+	Release(owner string) bool
+
+	// DeleteFiles deletes the files that are not pinned.
+	DeleteFiles(filePaths []string) error
+}
+
+// SnapshotConsistencyPoint describes a consistent cut of the persisted data of
+// a namespace, made up of the latest flushed volumes and the volumes of a
+// forced snapshot, which external backup tooling can copy as a whole.
+type SnapshotConsistencyPoint struct {
+	// SnapshotID is the ID of the forced snapshot.
+	SnapshotID uuid.UUID
+	// SnapshotTime is the time the forced snapshot was taken at.
+	SnapshotTime xtime.UnixNano
+	// CommitLog is the commit log that became active when the snapshot was
+	// taken, writes in prior commit logs are captured by the volumes.
+	CommitLog persist.CommitLogFile
+	// Volumes are the fileset volumes that make up the consistent cut.
+	Volumes []SnapshotConsistencyVolume
+}
+
+// SnapshotConsistencyVolume identifies a fileset volume of a consistency point.
+type SnapshotConsistencyVolume struct {
+	FileSetType persist.FileSetType
+	Shard       uint32
+	BlockStart  xtime.UnixNano
+	VolumeIndex int
+}
+
 // DatabaseBootstrapState stores a snapshot of the bootstrap state for all shards across all
 // namespaces at a given moment in time.
 type DatabaseBootstrapState struct {
@@ -1554,5 +1700,20 @@ type NamespaceHooks interface {
 	OnCreatedNamespace(Namespace, GetNamespaceFn) error
 }
 
+// NewSeriesHook allows plugins to veto the creation of new series at write
+// time, e.g. to protect against cardinality explosions or to block series with
+// tag values matching PII patterns.
+type NewSeriesHook interface {
+	// OnNewSeries gets invoked with the ID and tags of a series before it is
+	// created in the given namespace, either for a write or when loading its
+	// blocks, e.g. while bootstrapping or repairing. Returning an error rejects
+	// the series, failing the write as an invalid params error or skipping
+	// the blocks being loaded.
+	OnNewSeries(namespace ident.ID, metadata doc.Metadata) error
+}
+
+// NewSeriesHookFn creates a new NewSeriesHook.
+type NewSeriesHookFn func(iOpts instrument.Options) (NewSeriesHook, error)
+
 // GetNamespaceFn will return a namespace for a given ID if present.
 type GetNamespaceFn func(id ident.ID) (Namespace, bool)
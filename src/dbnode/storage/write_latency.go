@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"sync"
+
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+const (
+	writeLatencySegmentSeriesLookup     = "series-lookup"
+	writeLatencySegmentSeriesInsertWait = "series-insert-wait"
+	writeLatencySegmentBuffer           = "buffer"
+	writeLatencySegmentIndex            = "index"
+	writeLatencySegmentCommitLog        = "commitlog"
+)
+
+// writeLatencyMetrics breaks down the latency of the write path of a namespace
+// by segment so slow writes can be attributed to lock contention on the shard
+// (series lookup and the wait for new series inserts), the series buffers,
+// the index or the commit log.
+type writeLatencyMetrics struct {
+	seriesLookup     tally.Timer
+	seriesInsertWait tally.Timer
+	buffer           tally.Timer
+	index            tally.Timer
+	commitLog        tally.Timer
+}
+
+func newWriteLatencyMetrics(iopts instrument.Options) writeLatencyMetrics {
+	var (
+		scope     = iopts.MetricsScope()
+		timerOpts = iopts.TimerOptions()
+	)
+	timer := func(segment string) tally.Timer {
+		return instrument.NewTimer(scope.Tagged(map[string]string{
+			"segment": segment,
+		}), "write-latency", timerOpts)
+	}
+	return writeLatencyMetrics{
+		seriesLookup:     timer(writeLatencySegmentSeriesLookup),
+		seriesInsertWait: timer(writeLatencySegmentSeriesInsertWait),
+		buffer:           timer(writeLatencySegmentBuffer),
+		index:            timer(writeLatencySegmentIndex),
+		commitLog:        timer(writeLatencySegmentCommitLog),
+	}
+}
+
+// namespacesWriteLatencyMetrics holds the write latency metrics of each
+// namespace for the segments of the write path measured by the database.
+type namespacesWriteLatencyMetrics struct {
+	sync.RWMutex
+
+	iopts   instrument.Options
+	metrics map[string]writeLatencyMetrics
+}
+
+func newNamespacesWriteLatencyMetrics(
+	iopts instrument.Options,
+) *namespacesWriteLatencyMetrics {
+	return &namespacesWriteLatencyMetrics{
+		iopts:   iopts,
+		metrics: make(map[string]writeLatencyMetrics),
+	}
+}
+
+func (m *namespacesWriteLatencyMetrics) forNamespace(id ident.ID) writeLatencyMetrics {
+	m.RLock()
+	metrics, ok := m.metrics[string(id.Bytes())]
+	m.RUnlock()
+	if ok {
+		return metrics
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	name := id.String()
+	if metrics, ok := m.metrics[name]; ok {
+		return metrics
+	}
+	metrics = newWriteLatencyMetrics(m.iopts.SetMetricsScope(
+		m.iopts.MetricsScope().Tagged(map[string]string{
+			"namespace": name,
+		})))
+	m.metrics[name] = metrics
+	return metrics
+}
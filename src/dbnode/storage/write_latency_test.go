@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestShardWriteLatencyMetrics(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	opts := DefaultTestOptions()
+	opts = opts.SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(scope))
+
+	shard := testDatabaseShard(t, opts)
+	shard.SetRuntimeOptions(runtime.NewOptions().
+		SetWriteNewSeriesAsync(false))
+	defer shard.Close()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	now := xtime.Now()
+	for i := 0; i < 2; i++ {
+		_, err := shard.Write(ctx, ident.StringID("foo"), now, float64(i),
+			xtime.Second, nil, series.WriteOptions{})
+		require.NoError(t, err)
+	}
+
+	timers := scope.Snapshot().Timers()
+	for segment, count := range map[string]int{
+		writeLatencySegmentSeriesLookup:     2,
+		writeLatencySegmentSeriesInsertWait: 1,
+		writeLatencySegmentBuffer:           2,
+	} {
+		timer, ok := timers["write-latency+segment="+segment]
+		require.True(t, ok, segment)
+		require.Len(t, timer.Values(), count, segment)
+	}
+}
+
+func TestNamespacesWriteLatencyMetrics(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	opts := DefaultTestOptions()
+	metrics := newNamespacesWriteLatencyMetrics(
+		opts.InstrumentOptions().SetMetricsScope(scope))
+
+	metrics.forNamespace(ident.StringID("foo")).commitLog.Record(1)
+	metrics.forNamespace(ident.StringID("foo")).commitLog.Record(1)
+	metrics.forNamespace(ident.StringID("bar")).index.Record(1)
+	require.Len(t, metrics.metrics, 2)
+
+	timers := scope.Snapshot().Timers()
+	timer, ok := timers["write-latency+namespace=foo,segment=commitlog"]
+	require.True(t, ok)
+	require.Len(t, timer.Values(), 2)
+	timer, ok = timers["write-latency+namespace=bar,segment=index"]
+	require.True(t, ok)
+	require.Len(t, timer.Values(), 1)
+}
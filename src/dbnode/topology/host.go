@@ -39,8 +39,9 @@ func Majority(replicas int) int {
 }
 
 type host struct {
-	id      string
-	address string
+	id          string
+	address     string
+	maintenance bool
 }
 
 func (h *host) ID() string {
@@ -51,6 +52,10 @@ func (h *host) Address() string {
 	return h.address
 }
 
+func (h *host) Maintenance() bool {
+	return h.maintenance
+}
+
 func (h *host) String() string {
 	return fmt.Sprintf("Host<ID=%s, Address=%s>", h.id, h.address)
 }
@@ -86,7 +91,12 @@ func NewHostShardSetFromServiceInstance(
 	if err != nil {
 		return nil, err
 	}
-	return NewHostShardSet(NewHost(si.InstanceID(), si.Endpoint()), shardSet), nil
+	host := &host{
+		id:          si.InstanceID(),
+		address:     si.Endpoint(),
+		maintenance: si.Maintenance(),
+	}
+	return NewHostShardSet(host, shardSet), nil
 }
 
 func (h *hostShardSet) Host() Host {
@@ -87,6 +87,20 @@ func (mr *MockHostMockRecorder) ID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockHost)(nil).ID))
 }
 
+// Maintenance mocks base method.
+func (m *MockHost) Maintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Maintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Maintenance indicates an expected call of Maintenance.
+func (mr *MockHostMockRecorder) Maintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockHost)(nil).Maintenance))
+}
+
 // String mocks base method.
 func (m *MockHost) String() string {
 	m.ctrl.T.Helper()
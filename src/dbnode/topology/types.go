@@ -37,6 +37,10 @@ type Host interface {
 	// Address returns the address of the host
 	Address() string
 
+	// Maintenance returns whether the host is in maintenance, writes skip
+	// hosts in maintenance when the other replicas satisfy the consistency level
+	Maintenance() bool
+
 	// String returns a string representation of the host
 	String() string
 }
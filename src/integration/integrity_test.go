@@ -0,0 +1,73 @@
+// +build dtest
+//
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/integration/resources"
+	"github.com/m3db/m3/src/x/headers"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataIntegrity(t *testing.T) {
+	var (
+		resolution = 5 * time.Second
+		policy     = []string{"5s:10h"}
+	)
+	checker, err := resources.NewIntegrityChecker(
+		singleDBNodeDockerResources.Coordinator(),
+		resources.IntegrityCheckOptions{
+			MetricPrefix: "integrity_test",
+			QueryPaths: []resources.IntegrityQueryPath{
+				{
+					Name: "prometheus-unaggregated",
+					Type: resources.IntegrityQueryTypePrometheus,
+					Headers: map[string][]string{
+						headers.MetricsTypeHeader: {"unaggregated"},
+					},
+				},
+				{
+					Name: "prometheus-aggregated",
+					Type: resources.IntegrityQueryTypePrometheus,
+					Headers: map[string][]string{
+						headers.MetricsTypeHeader:          {"aggregated"},
+						headers.MetricsStoragePolicyHeader: policy,
+					},
+					Resolution: resolution,
+				},
+				{
+					// Carbon is only ingested to the aggregated namespace.
+					Name:       "graphite",
+					Type:       resources.IntegrityQueryTypeGraphite,
+					Resolution: resolution,
+				},
+			},
+		})
+	require.NoError(t, err)
+	require.NoError(t, checker.Write())
+	require.NoError(t, checker.Verify())
+}
@@ -0,0 +1,439 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/errors"
+)
+
+const (
+	defaultIntegritySeed          = 42
+	defaultIntegrityNumSeries     = 10
+	defaultIntegrityNumDatapoints = 12
+	defaultIntegrityInterval      = 10 * time.Second
+	defaultIntegrityCarbonPort    = 7204
+	defaultIntegrityMetricPrefix  = "integrity"
+
+	// integrityMaxValue bounds the generated values so that they are exactly
+	// representable by both the prometheus and graphite response formats.
+	integrityMaxValue = 1 << 20
+)
+
+// IntegrityQueryType is the type of query used to read back datapoints.
+type IntegrityQueryType int
+
+const (
+	// IntegrityQueryTypePrometheus reads back the prometheus workload using
+	// a range selector through the prometheus query API.
+	IntegrityQueryTypePrometheus IntegrityQueryType = iota
+	// IntegrityQueryTypeGraphite reads back the carbon workload through the
+	// graphite render API.
+	IntegrityQueryTypeGraphite
+)
+
+// IntegrityQueryPath is a path the written workload is read back through.
+type IntegrityQueryPath struct {
+	// Name identifies the path in verification errors.
+	Name string
+	// Type is the type of query used to read back datapoints.
+	Type IntegrityQueryType
+	// Headers are the headers sent with each query, e.g. to restrict reads
+	// to an aggregated namespace.
+	Headers map[string][]string
+	// Resolution is the resolution of the namespace the path reads from,
+	// zero if datapoints are read back unaggregated. Aggregated datapoints
+	// are expected to hold the last value written in each resolution window.
+	Resolution time.Duration
+}
+
+// IntegrityCoordinator is the subset of the coordinator APIs used to write
+// and read back an integrity workload.
+type IntegrityCoordinator interface {
+	// WriteCarbon writes a carbon metric datapoint at a given time.
+	WriteCarbon(port int, metric string, v float64, t time.Time) error
+	// WriteProm writes a prometheus metric.
+	WriteProm(name string, tags map[string]string, samples []prompb.Sample) error
+	// RunQuery runs the given query with a given verification function.
+	RunQuery(verifier ResponseVerifier, query string, headers map[string][]string) error
+}
+
+// IntegrityCheckOptions are the options for an integrity check.
+type IntegrityCheckOptions struct {
+	// Seed seeds the pseudo-random workload so that the same workload can
+	// be regenerated, e.g. to verify data written before an upgrade.
+	Seed int64
+	// NumSeries is the number of series written for each of the prometheus
+	// and carbon workloads.
+	NumSeries int
+	// NumDatapoints is the number of datapoints written for each series.
+	NumDatapoints int
+	// Interval is the interval between the datapoints of a series.
+	Interval time.Duration
+	// Start is the time of the first datapoint, truncated to Interval.
+	// Defaults to NumDatapoints intervals before now.
+	Start time.Time
+	// CarbonPort is the port carbon datapoints are written to.
+	CarbonPort int
+	// MetricPrefix prefixes the name of every series written.
+	MetricPrefix string
+	// QueryPaths are the paths the workload is read back through, defaulting
+	// to the unaggregated prometheus and graphite paths.
+	QueryPaths []IntegrityQueryPath
+}
+
+// IntegrityDatapoint is a datapoint of an integrity workload.
+type IntegrityDatapoint struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// IntegritySeries is a series of an integrity workload.
+type IntegritySeries struct {
+	Name       string
+	Tags       map[string]string
+	Datapoints []IntegrityDatapoint
+}
+
+// IntegrityWorkload is a deterministic workload written to and read back from
+// a cluster to check that no datapoints are lost or altered.
+type IntegrityWorkload struct {
+	Prom   []IntegritySeries
+	Carbon []IntegritySeries
+}
+
+// IntegrityChecker writes a deterministic workload and verifies it can be read
+// back unchanged through each configured query path.
+type IntegrityChecker struct {
+	coordinator IntegrityCoordinator
+	opts        IntegrityCheckOptions
+	workload    IntegrityWorkload
+}
+
+// NewIntegrityChecker creates a new integrity checker.
+func NewIntegrityChecker(
+	coordinator IntegrityCoordinator,
+	opts IntegrityCheckOptions,
+) (*IntegrityChecker, error) {
+	opts = opts.withDefaults()
+	if opts.NumSeries < 0 || opts.NumDatapoints < 0 {
+		return nil, fmt.Errorf("invalid integrity workload size: series=%d, datapoints=%d",
+			opts.NumSeries, opts.NumDatapoints)
+	}
+	if opts.Interval%time.Second != 0 {
+		return nil, fmt.Errorf("integrity interval must be a whole number of seconds: %v",
+			opts.Interval)
+	}
+	return &IntegrityChecker{
+		coordinator: coordinator,
+		opts:        opts,
+		workload:    NewIntegrityWorkload(opts),
+	}, nil
+}
+
+func (o IntegrityCheckOptions) withDefaults() IntegrityCheckOptions {
+	if o.Seed == 0 {
+		o.Seed = defaultIntegritySeed
+	}
+	if o.NumSeries == 0 {
+		o.NumSeries = defaultIntegrityNumSeries
+	}
+	if o.NumDatapoints == 0 {
+		o.NumDatapoints = defaultIntegrityNumDatapoints
+	}
+	if o.Interval == 0 {
+		o.Interval = defaultIntegrityInterval
+	}
+	if o.Start.IsZero() {
+		o.Start = time.Now().Add(-time.Duration(o.NumDatapoints) * o.Interval)
+	}
+	o.Start = o.Start.Truncate(o.Interval)
+	if o.CarbonPort == 0 {
+		o.CarbonPort = defaultIntegrityCarbonPort
+	}
+	if o.MetricPrefix == "" {
+		o.MetricPrefix = defaultIntegrityMetricPrefix
+	}
+	if len(o.QueryPaths) == 0 {
+		o.QueryPaths = []IntegrityQueryPath{
+			{Name: "prometheus", Type: IntegrityQueryTypePrometheus},
+			{Name: "graphite", Type: IntegrityQueryTypeGraphite},
+		}
+	}
+	return o
+}
+
+// NewIntegrityWorkload generates the workload for the given options, which is
+// the same for the same seed, size, interval and start.
+func NewIntegrityWorkload(opts IntegrityCheckOptions) IntegrityWorkload {
+	opts = opts.withDefaults()
+	rng := rand.New(rand.NewSource(opts.Seed)) // nolint: gosec
+	series := func(name func(i int) string, tags func(i int) map[string]string) []IntegritySeries {
+		result := make([]IntegritySeries, 0, opts.NumSeries)
+		for i := 0; i < opts.NumSeries; i++ {
+			s := IntegritySeries{
+				Name:       name(i),
+				Datapoints: make([]IntegrityDatapoint, 0, opts.NumDatapoints),
+			}
+			if tags != nil {
+				s.Tags = tags(i)
+			}
+			for j := 0; j < opts.NumDatapoints; j++ {
+				s.Datapoints = append(s.Datapoints, IntegrityDatapoint{
+					Timestamp: opts.Start.Add(time.Duration(j) * opts.Interval),
+					Value:     float64(rng.Intn(integrityMaxValue)),
+				})
+			}
+			result = append(result, s)
+		}
+		return result
+	}
+
+	return IntegrityWorkload{
+		Prom: series(func(i int) string {
+			return fmt.Sprintf("%s_prom_%d", opts.MetricPrefix, i)
+		}, func(i int) map[string]string {
+			return map[string]string{"seed": strconv.FormatInt(opts.Seed, 10)}
+		}),
+		Carbon: series(func(i int) string {
+			return fmt.Sprintf("%s.carbon.%d", opts.MetricPrefix, i)
+		}, nil),
+	}
+}
+
+// Workload returns the workload written and verified by the checker.
+func (c *IntegrityChecker) Workload() IntegrityWorkload {
+	return c.workload
+}
+
+// Write writes the workload.
+func (c *IntegrityChecker) Write() error {
+	for _, s := range c.workload.Prom {
+		samples := make([]prompb.Sample, 0, len(s.Datapoints))
+		for _, dp := range s.Datapoints {
+			samples = append(samples, prompb.Sample{
+				Value:     dp.Value,
+				Timestamp: dp.Timestamp.UnixNano() / int64(time.Millisecond),
+			})
+		}
+		if err := c.coordinator.WriteProm(s.Name, s.Tags, samples); err != nil {
+			return fmt.Errorf("could not write series %s: %w", s.Name, err)
+		}
+	}
+	for _, s := range c.workload.Carbon {
+		for _, dp := range s.Datapoints {
+			if err := c.coordinator.WriteCarbon(c.opts.CarbonPort, s.Name, dp.Value, dp.Timestamp); err != nil {
+				return fmt.Errorf("could not write series %s: %w", s.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Verify reads back the workload through each query path, returning an error
+// for every series that does not match the datapoints written.
+func (c *IntegrityChecker) Verify() error {
+	var multiErr errors.MultiError
+	for _, path := range c.opts.QueryPaths {
+		series := c.workload.Prom
+		if path.Type == IntegrityQueryTypeGraphite {
+			series = c.workload.Carbon
+		}
+		for _, s := range series {
+			expected := expectedIntegrityDatapoints(s.Datapoints, path.Resolution)
+			verifier := integrityVerifier(path, expected)
+			if err := c.coordinator.RunQuery(verifier, c.query(path, s), path.Headers); err != nil {
+				multiErr = multiErr.Add(fmt.Errorf("query path %s, series %s: %w",
+					path.Name, s.Name, err))
+			}
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (c *IntegrityChecker) query(path IntegrityQueryPath, s IntegritySeries) string {
+	var (
+		start = c.opts.Start
+		end   = start.Add(time.Duration(c.opts.NumDatapoints) * c.opts.Interval)
+	)
+	if path.Type == IntegrityQueryTypeGraphite {
+		return "api/v1/graphite/render?" + url.Values{
+			"target": []string{s.Name},
+			"from":   []string{strconv.FormatInt(start.Add(-c.opts.Interval).Unix(), 10)},
+			"until":  []string{strconv.FormatInt(end.Unix(), 10)},
+			"format": []string{"json"},
+		}.Encode()
+	}
+
+	// A range selector returns the raw datapoints of the series rather than
+	// the values at each step of a range query. The selector excludes its
+	// start so it begins one interval before the first datapoint and ends at
+	// the last datapoint.
+	var matchers []string
+	for k, v := range s.Tags {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(matchers)
+	selector := fmt.Sprintf("%s{%s}[%ds]", s.Name, strings.Join(matchers, ","),
+		int64(end.Sub(start)/time.Second))
+	return "api/v1/query?" + url.Values{
+		"query": []string{selector},
+		"time":  []string{strconv.FormatInt(end.Add(-c.opts.Interval).Unix(), 10)},
+	}.Encode()
+}
+
+// expectedIntegrityDatapoints returns the datapoints expected to be read back
+// at the given resolution, keeping the last datapoint of each resolution
+// window when aggregated.
+func expectedIntegrityDatapoints(
+	datapoints []IntegrityDatapoint,
+	resolution time.Duration,
+) []IntegrityDatapoint {
+	if resolution <= 0 {
+		return datapoints
+	}
+	expected := make([]IntegrityDatapoint, 0, len(datapoints))
+	for _, dp := range datapoints {
+		window := dp.Timestamp.Truncate(resolution)
+		if n := len(expected); n > 0 && expected[n-1].Timestamp.Equal(window) {
+			expected[n-1].Value = dp.Value
+			continue
+		}
+		expected = append(expected, IntegrityDatapoint{Timestamp: window, Value: dp.Value})
+	}
+	return expected
+}
+
+// verifyIntegrityDatapoints checks the datapoints read back match those
+// expected. Aggregated datapoints are timestamped by the aggregator so they
+// need only fall within the resolution window they were expected in.
+func verifyIntegrityDatapoints(
+	expected, actual []IntegrityDatapoint,
+	resolution time.Duration,
+) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d datapoints, got %d: %v",
+			len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		var (
+			e, a     = expected[i], actual[i]
+			inWindow = a.Timestamp.Equal(e.Timestamp)
+		)
+		if resolution > 0 {
+			inWindow = !a.Timestamp.Before(e.Timestamp) &&
+				!a.Timestamp.After(e.Timestamp.Add(resolution))
+		}
+		if !inWindow || a.Value != e.Value {
+			return fmt.Errorf("datapoint %d: expected %v at %v, got %v at %v",
+				i, e.Value, e.Timestamp, a.Value, a.Timestamp)
+		}
+	}
+	return nil
+}
+
+func integrityVerifier(path IntegrityQueryPath, expected []IntegrityDatapoint) ResponseVerifier {
+	return func(status int, _ map[string][]string, resp string, err error) error {
+		if err != nil {
+			return err
+		}
+		if status/100 != 2 {
+			return fmt.Errorf("expected 200 status code, got %v: %s", status, resp)
+		}
+
+		var actual []IntegrityDatapoint
+		if path.Type == IntegrityQueryTypeGraphite {
+			actual, err = parseGraphiteIntegrityDatapoints(resp)
+		} else {
+			actual, err = parsePromIntegrityDatapoints(resp)
+		}
+		if err != nil {
+			return err
+		}
+		return verifyIntegrityDatapoints(expected, actual, path.Resolution)
+	}
+}
+
+func parsePromIntegrityDatapoints(resp string) ([]IntegrityDatapoint, error) {
+	var parsed struct {
+		Data struct {
+			Result []struct {
+				Values []model.SamplePair `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp), &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Data.Result) != 1 {
+		return nil, fmt.Errorf("expected one series, got %d", len(parsed.Data.Result))
+	}
+
+	values := parsed.Data.Result[0].Values
+	datapoints := make([]IntegrityDatapoint, 0, len(values))
+	for _, v := range values {
+		datapoints = append(datapoints, IntegrityDatapoint{
+			Timestamp: v.Timestamp.Time(),
+			Value:     float64(v.Value),
+		})
+	}
+	return datapoints, nil
+}
+
+func parseGraphiteIntegrityDatapoints(resp string) ([]IntegrityDatapoint, error) {
+	var parsed []struct {
+		// NB: graphite presents datapoints as an array [value, timestamp]
+		// with a null value for steps without a datapoint.
+		Datapoints [][]*float64 `json:"datapoints"`
+	}
+	if err := json.Unmarshal([]byte(resp), &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed) != 1 {
+		return nil, fmt.Errorf("expected one series, got %d", len(parsed))
+	}
+
+	datapoints := make([]IntegrityDatapoint, 0, len(parsed[0].Datapoints))
+	for _, dp := range parsed[0].Datapoints {
+		if len(dp) != 2 || dp[1] == nil {
+			return nil, fmt.Errorf("unexpected graphite datapoint: %v", dp)
+		}
+		if dp[0] == nil {
+			continue
+		}
+		datapoints = append(datapoints, IntegrityDatapoint{
+			Timestamp: time.Unix(int64(*dp[1]), 0),
+			Value:     *dp[0],
+		})
+	}
+	return datapoints, nil
+}
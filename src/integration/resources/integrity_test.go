@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+func TestNewIntegrityWorkloadDeterministic(t *testing.T) {
+	opts := IntegrityCheckOptions{
+		Seed:          7,
+		NumSeries:     3,
+		NumDatapoints: 4,
+		Interval:      10 * time.Second,
+		Start:         time.Unix(1000, 0),
+	}
+	workload := NewIntegrityWorkload(opts)
+	require.Equal(t, workload, NewIntegrityWorkload(opts))
+	require.Len(t, workload.Prom, 3)
+	require.Len(t, workload.Carbon, 3)
+	require.Equal(t, "integrity_prom_0", workload.Prom[0].Name)
+	require.Equal(t, "integrity.carbon.2", workload.Carbon[2].Name)
+	require.Equal(t, time.Unix(1030, 0), workload.Prom[1].Datapoints[3].Timestamp)
+
+	opts.Seed = 8
+	require.NotEqual(t, workload, NewIntegrityWorkload(opts))
+}
+
+func TestExpectedIntegrityDatapointsAggregated(t *testing.T) {
+	datapoints := []IntegrityDatapoint{
+		{Timestamp: time.Unix(10, 0), Value: 1},
+		{Timestamp: time.Unix(15, 0), Value: 2},
+		{Timestamp: time.Unix(20, 0), Value: 3},
+	}
+	require.Equal(t, datapoints, expectedIntegrityDatapoints(datapoints, 0))
+	require.Equal(t, []IntegrityDatapoint{
+		{Timestamp: time.Unix(10, 0), Value: 2},
+		{Timestamp: time.Unix(20, 0), Value: 3},
+	}, expectedIntegrityDatapoints(datapoints, 10*time.Second))
+}
+
+func TestIntegrityVerifier(t *testing.T) {
+	expected := []IntegrityDatapoint{
+		{Timestamp: time.Unix(10, 0), Value: 1},
+		{Timestamp: time.Unix(20, 0), Value: 2},
+	}
+
+	prom := integrityVerifier(IntegrityQueryPath{Type: IntegrityQueryTypePrometheus}, expected)
+	require.NoError(t, prom(200, nil,
+		`{"data":{"result":[{"values":[[10,"1"],[20,"2"]]}]}}`, nil))
+	require.Error(t, prom(200, nil,
+		`{"data":{"result":[{"values":[[10,"1"],[20,"3"]]}]}}`, nil))
+	require.Error(t, prom(200, nil,
+		`{"data":{"result":[{"values":[[10,"1"]]}]}}`, nil))
+	require.Error(t, prom(500, nil, "", nil))
+
+	graphite := integrityVerifier(IntegrityQueryPath{Type: IntegrityQueryTypeGraphite}, expected)
+	require.NoError(t, graphite(200, nil,
+		`[{"datapoints":[[null,0],[1,10],[2,20],[null,30]]}]`, nil))
+	require.Error(t, graphite(200, nil,
+		`[{"datapoints":[[1,10],[2,25]]}]`, nil))
+
+	aggregated := integrityVerifier(IntegrityQueryPath{
+		Type:       IntegrityQueryTypeGraphite,
+		Resolution: 10 * time.Second,
+	}, expected)
+	require.NoError(t, aggregated(200, nil,
+		`[{"datapoints":[[1,20],[2,30]]}]`, nil))
+	require.Error(t, aggregated(200, nil,
+		`[{"datapoints":[[1,20],[2,40]]}]`, nil))
+}
+
+type testIntegrityCoordinator struct {
+	written map[string][]IntegrityDatapoint
+	queries []string
+}
+
+func (c *testIntegrityCoordinator) WriteCarbon(_ int, metric string, v float64, t time.Time) error {
+	c.written[metric] = append(c.written[metric], IntegrityDatapoint{Timestamp: t, Value: v})
+	return nil
+}
+
+func (c *testIntegrityCoordinator) WriteProm(
+	name string, _ map[string]string, samples []prompb.Sample,
+) error {
+	for _, s := range samples {
+		c.written[name] = append(c.written[name], IntegrityDatapoint{
+			Timestamp: time.Unix(0, s.Timestamp*int64(time.Millisecond)),
+			Value:     s.Value,
+		})
+	}
+	return nil
+}
+
+func (c *testIntegrityCoordinator) RunQuery(
+	verifier ResponseVerifier, query string, _ map[string][]string,
+) error {
+	c.queries = append(c.queries, query)
+	return verifier(200, nil, "[]", nil)
+}
+
+func TestIntegrityCheckerWriteVerify(t *testing.T) {
+	coordinator := &testIntegrityCoordinator{written: make(map[string][]IntegrityDatapoint)}
+	checker, err := NewIntegrityChecker(coordinator, IntegrityCheckOptions{
+		NumSeries:     2,
+		NumDatapoints: 3,
+		Start:         time.Unix(1005, 0),
+	})
+	require.NoError(t, err)
+	require.NoError(t, checker.Write())
+
+	workload := checker.Workload()
+	for _, s := range append(workload.Prom, workload.Carbon...) {
+		require.Equal(t, s.Datapoints, coordinator.written[s.Name])
+		require.Equal(t, time.Unix(1000, 0), s.Datapoints[0].Timestamp)
+	}
+
+	// Every series of every default query path is read back.
+	require.Error(t, checker.Verify())
+	require.Len(t, coordinator.queries, 4)
+	require.Equal(t,
+		"api/v1/query?query=integrity_prom_0%7Bseed%3D%2242%22%7D%5B30s%5D&time=1020",
+		coordinator.queries[0])
+	require.Equal(t,
+		"api/v1/graphite/render?format=json&from=990&target=integrity.carbon.0&until=1030",
+		coordinator.queries[2])
+}
+
+func TestNewIntegrityCheckerInvalidInterval(t *testing.T) {
+	_, err := NewIntegrityChecker(nil, IntegrityCheckOptions{
+		Interval: 1500 * time.Millisecond,
+	})
+	require.Error(t, err)
+}
@@ -214,6 +214,20 @@ func (mr *MockServiceNodeMockRecorder) IsolationGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsolationGroup", reflect.TypeOf((*MockServiceNode)(nil).IsolationGroup))
 }
 
+// Maintenance mocks base method.
+func (m *MockServiceNode) Maintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Maintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Maintenance indicates an expected call of Maintenance.
+func (mr *MockServiceNodeMockRecorder) Maintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockServiceNode)(nil).Maintenance))
+}
+
 // Metadata mocks base method.
 func (m *MockServiceNode) Metadata() placement.InstanceMetadata {
 	m.ctrl.T.Helper()
@@ -327,6 +341,20 @@ func (mr *MockServiceNodeMockRecorder) SetIsolationGroup(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsolationGroup", reflect.TypeOf((*MockServiceNode)(nil).SetIsolationGroup), arg0)
 }
 
+// SetMaintenance mocks base method.
+func (m *MockServiceNode) SetMaintenance(arg0 bool) placement.Instance {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenance", arg0)
+	ret0, _ := ret[0].(placement.Instance)
+	return ret0
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance.
+func (mr *MockServiceNodeMockRecorder) SetMaintenance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockServiceNode)(nil).SetMaintenance), arg0)
+}
+
 // SetMetadata mocks base method.
 func (m *MockServiceNode) SetMetadata(arg0 placement.InstanceMetadata) placement.Instance {
 	m.ctrl.T.Helper()
@@ -37,6 +37,17 @@ func TestTypeIsValid(t *testing.T) {
 	require.False(t, Type(int(P9999)+1).IsValid())
 }
 
+func TestTypeIsValidForHistogram(t *testing.T) {
+	for _, aggType := range []Type{Sum, Count, Mean, Median, P50, P99} {
+		require.True(t, aggType.IsValidForHistogram())
+	}
+	for _, aggType := range []Type{Last, Min, Max, SumSq, Stdev} {
+		require.False(t, aggType.IsValidForHistogram())
+	}
+	require.True(t, Types{Sum, Count, P99}.IsValidForHistogram())
+	require.False(t, Types{Sum, Max}.IsValidForHistogram())
+}
+
 func TestTypeMaxID(t *testing.T) {
 	require.Equal(t, maxTypeID, P9999.ID())
 	require.Equal(t, P9999, Type(maxTypeID))
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encoding
+
+import (
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+)
+
+// ProtocolVersion is the version of the unaggregated wire protocol spoken by
+// this build. Peers that do not exchange a handshake speak version zero.
+const ProtocolVersion uint32 = 1
+
+// Capabilities is a bit set of optional features of the unaggregated wire
+// protocol that a peer may or may not support.
+type Capabilities uint64
+
+// A list of supported capabilities.
+const (
+	// HistogramsCapability indicates support for histogram metrics.
+	HistogramsCapability Capabilities = 1 << iota
+	// ShardIngestionPausedCapability indicates support for the server notifying
+	// the client of writes rejected because the ingestion of a shard is paused.
+	ShardIngestionPausedCapability
+)
+
+// SupportedCapabilities are the capabilities supported by this build.
+const SupportedCapabilities = HistogramsCapability | ShardIngestionPausedCapability
+
+// Has returns true if all of the given capabilities are set.
+func (c Capabilities) Has(other Capabilities) bool {
+	return c&other == other
+}
+
+// Handshake advertises the protocol version and capabilities of a peer.
+type Handshake struct {
+	ProtocolVersion uint32
+	Capabilities    Capabilities
+}
+
+// NewHandshake returns the handshake advertised by this build.
+func NewHandshake() Handshake {
+	return Handshake{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    SupportedCapabilities,
+	}
+}
+
+// Negotiate returns the protocol version and capabilities both the local
+// and the remote peer support.
+func (h Handshake) Negotiate(remote Handshake) Handshake {
+	version := h.ProtocolVersion
+	if remote.ProtocolVersion < version {
+		version = remote.ProtocolVersion
+	}
+	return Handshake{
+		ProtocolVersion: version,
+		Capabilities:    h.Capabilities & remote.Capabilities,
+	}
+}
+
+// ToProto converts the handshake to a protobuf message in place.
+func (h Handshake) ToProto(pb *metricpb.Handshake) {
+	pb.ProtocolVersion = h.ProtocolVersion
+	pb.Capabilities = uint64(h.Capabilities)
+}
+
+// FromProto converts the protobuf message to a handshake in place.
+func (h *Handshake) FromProto(pb *metricpb.Handshake) {
+	if pb == nil {
+		*h = Handshake{}
+		return
+	}
+	h.ProtocolVersion = pb.ProtocolVersion
+	h.Capabilities = Capabilities(pb.Capabilities)
+}
+
+// ShardIngestionPaused notifies a client that writes to a shard are rejected
+// until the pause of the ingestion of the shard expires.
+type ShardIngestionPaused struct {
+	Shard      uint32
+	UntilNanos int64
+}
+
+// ToProto converts the notice to a protobuf message in place.
+func (p ShardIngestionPaused) ToProto(pb *metricpb.ShardIngestionPaused) {
+	pb.Shard = p.Shard
+	pb.UntilNanos = p.UntilNanos
+}
+
+// FromProto converts the protobuf message to a notice in place.
+func (p *ShardIngestionPaused) FromProto(pb *metricpb.ShardIngestionPaused) {
+	if pb == nil {
+		*p = ShardIngestionPaused{}
+		return
+	}
+	p.Shard = pb.Shard
+	p.UntilNanos = pb.UntilNanos
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesHas(t *testing.T) {
+	require.True(t, SupportedCapabilities.Has(HistogramsCapability))
+	require.True(t, SupportedCapabilities.Has(ShardIngestionPausedCapability))
+	require.True(t, HistogramsCapability.Has(0))
+	require.False(t, Capabilities(0).Has(HistogramsCapability))
+}
+
+func TestHandshakeNegotiate(t *testing.T) {
+	local := Handshake{ProtocolVersion: 2, Capabilities: 0x3}
+
+	negotiated := local.Negotiate(Handshake{ProtocolVersion: 1, Capabilities: 0x6})
+	require.Equal(t, Handshake{ProtocolVersion: 1, Capabilities: 0x2}, negotiated)
+
+	negotiated = local.Negotiate(Handshake{ProtocolVersion: 5, Capabilities: 0x1})
+	require.Equal(t, Handshake{ProtocolVersion: 2, Capabilities: 0x1}, negotiated)
+
+	// A peer that does not speak the handshake supports nothing optional.
+	require.Equal(t, Handshake{}, local.Negotiate(Handshake{}))
+}
+
+func TestHandshakeProtoRoundTrip(t *testing.T) {
+	var (
+		pb  metricpb.Handshake
+		res Handshake
+	)
+	NewHandshake().ToProto(&pb)
+	res.FromProto(&pb)
+	require.Equal(t, NewHandshake(), res)
+
+	res.FromProto(nil)
+	require.Equal(t, Handshake{}, res)
+}
+
+func TestShardIngestionPausedProtoRoundTrip(t *testing.T) {
+	var (
+		pb     metricpb.ShardIngestionPaused
+		res    ShardIngestionPaused
+		paused = ShardIngestionPaused{Shard: 12, UntilNanos: 123456}
+	)
+	paused.ToProto(&pb)
+	res.FromProto(&pb)
+	require.Equal(t, paused, res)
+
+	res.FromProto(nil)
+	require.Equal(t, ShardIngestionPaused{}, res)
+}
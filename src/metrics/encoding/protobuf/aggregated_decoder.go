@@ -21,7 +21,10 @@
 package protobuf
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 )
 
@@ -44,6 +47,10 @@ func (d *AggregatedDecoder) Decode(b []byte) error {
 	if err := d.pb.Unmarshal(b); err != nil {
 		return err
 	}
+	if d.pb.SplitCount > 0 {
+		// Fragments of a split metric only carry the split fields.
+		return nil
+	}
 	return d.sp.FromProto(d.pb.Metric.StoragePolicy)
 }
 
@@ -67,6 +74,11 @@ func (d *AggregatedDecoder) Annotation() []byte {
 	return d.pb.Metric.TimedMetric.Annotation
 }
 
+// Exemplars returns the decoded exemplars, if any.
+func (d *AggregatedDecoder) Exemplars() []metric.Exemplar {
+	return metric.ExemplarsFromProto(d.pb.Metric.TimedMetric.Exemplars, nil)
+}
+
 // StoragePolicy returns the decoded storage policy.
 func (d AggregatedDecoder) StoragePolicy() policy.StoragePolicy {
 	return d.sp
@@ -77,6 +89,29 @@ func (d AggregatedDecoder) EncodeNanos() int64 {
 	return d.pb.EncodeNanos
 }
 
+// DowngradedResolution returns the decoded resolution the metric was
+// downgraded to, or zero if the metric was emitted at its storage policy
+// resolution.
+func (d AggregatedDecoder) DowngradedResolution() time.Duration {
+	return time.Duration(d.pb.DowngradedResolutionNanos)
+}
+
+// Split returns the fragment carried by the decoded message and true if the
+// message is one of the fragments of a metric that was split across messages,
+// in which case the other fields are not set. The payload remains valid until
+// the decoder is closed.
+func (d *AggregatedDecoder) Split() (AggregatedSplit, bool) {
+	if d.pb.SplitCount <= 0 {
+		return AggregatedSplit{}, false
+	}
+	return AggregatedSplit{
+		ID:      d.pb.SplitId,
+		Index:   int(d.pb.SplitIndex),
+		Count:   int(d.pb.SplitCount),
+		Payload: d.pb.SplitPayload,
+	}, true
+}
+
 // Close closes the decoder.
 func (d *AggregatedDecoder) Close() {
 	d.sp = policy.StoragePolicy{}
@@ -59,6 +59,7 @@ func (enc *aggregatedEncoder) Encode(
 		return err
 	}
 	enc.pb.EncodeNanos = encodedAtNanos
+	enc.pb.DowngradedResolutionNanos = int64(m.DowngradedResolution)
 	// Always allocate a new byte slice to avoid modifying the existing one which may still being used.
 	enc.buf = allocate(enc.pool, enc.pb.Size())
 	n, err := enc.pb.MarshalTo(enc.buf)
@@ -22,6 +22,7 @@ package protobuf
 
 import (
 	"testing"
+	"time"
 
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
@@ -108,3 +109,44 @@ func TestAggregatedEncoderDecoder_ResetProtobuf(t *testing.T) {
 	require.Equal(t, testAggregatedMetric2.TimeNanos, dec.TimeNanos())
 	require.Equal(t, testAggregatedMetric2.Value, dec.Value())
 }
+
+func TestAggregatedEncoderDecoder_DowngradedResolution(t *testing.T) {
+	enc := NewAggregatedEncoder(nil)
+	dec := NewAggregatedDecoder(nil)
+
+	downgraded := testAggregatedMetric1
+	downgraded.DowngradedResolution = time.Minute
+	require.NoError(t, enc.Encode(downgraded, 2000))
+	require.NoError(t, dec.Decode(enc.Buffer().Bytes()))
+	require.Equal(t, time.Minute, dec.DowngradedResolution())
+	require.Equal(t, downgraded.StoragePolicy, dec.StoragePolicy())
+	require.Equal(t, downgraded.TimeNanos, dec.TimeNanos())
+	require.Equal(t, downgraded.Value, dec.Value())
+
+	dec.Close()
+
+	require.NoError(t, enc.Encode(testAggregatedMetric2, 3000))
+	require.NoError(t, dec.Decode(enc.Buffer().Bytes()))
+	require.Equal(t, time.Duration(0), dec.DowngradedResolution())
+}
+
+func TestAggregatedEncoderDecoder_Exemplars(t *testing.T) {
+	enc := NewAggregatedEncoder(nil)
+	dec := NewAggregatedDecoder(nil)
+
+	withExemplars := testAggregatedMetric1
+	withExemplars.Exemplars = []metric.Exemplar{
+		{TraceID: []byte("trace1"), SpanID: []byte("span1"), Value: 12, TimeNanos: 1000},
+		{TraceID: []byte("trace2"), Value: 34, TimeNanos: 1100},
+	}
+	require.NoError(t, enc.Encode(withExemplars, 2000))
+	require.NoError(t, dec.Decode(enc.Buffer().Bytes()))
+	require.Equal(t, withExemplars.Value, dec.Value())
+	require.Equal(t, withExemplars.Exemplars, dec.Exemplars())
+
+	// Exemplars must not leak into the next decoded metric.
+	dec.Close()
+	require.NoError(t, enc.Encode(testAggregatedMetric2, 3000))
+	require.NoError(t, dec.Decode(enc.Buffer().Bytes()))
+	require.Nil(t, dec.Exemplars())
+}
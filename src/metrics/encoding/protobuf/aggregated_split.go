@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+)
+
+const (
+	// maxAggregatedSplitOverhead is the upper bound of the bytes needed to
+	// encode the split fields of a fragment other than the payload itself.
+	maxAggregatedSplitOverhead = 32
+)
+
+var (
+	errSplitMaxSizeTooSmall = errors.New("max size too small to split aggregated metric")
+)
+
+// AggregatedSplit is a fragment of an encoded aggregated metric that was split
+// across multiple messages because it exceeded the max message size.
+type AggregatedSplit struct {
+	// ID is shared by all fragments of the same metric.
+	ID uint64
+	// Index is the position of the fragment in the encoded metric.
+	Index int
+	// Count is the total number of fragments of the metric.
+	Count int
+	// Payload is the fragment of the encoded metric.
+	Payload []byte
+}
+
+// Validate validates the split fragment.
+func (s AggregatedSplit) Validate() error {
+	if s.Count <= 0 {
+		return fmt.Errorf("invalid split count %d", s.Count)
+	}
+	if s.Index < 0 || s.Index >= s.Count {
+		return fmt.Errorf("invalid split index %d for split count %d", s.Index, s.Count)
+	}
+	return nil
+}
+
+// SplitAggregated splits an encoded aggregated metric into encoded messages
+// of at most maxSize bytes that each carry a fragment of the metric, the
+// fragments are concatenated in index order to reassemble the metric. The
+// buffers are not allocated from a pool since the capacity of pooled buffers
+// may exceed the max size.
+func SplitAggregated(encoded []byte, splitID uint64, maxSize int) ([]Buffer, error) {
+	fragmentSize := maxSize - maxAggregatedSplitOverhead
+	if fragmentSize <= 0 {
+		return nil, errSplitMaxSizeTooSmall
+	}
+
+	var (
+		count   = (len(encoded) + fragmentSize - 1) / fragmentSize
+		buffers = make([]Buffer, 0, count)
+		pb      = metricpb.AggregatedMetric{
+			SplitId:    splitID,
+			SplitCount: int32(count),
+		}
+	)
+	for i := 0; i < count; i++ {
+		start := i * fragmentSize
+		end := start + fragmentSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		pb.SplitIndex = int32(i)
+		pb.SplitPayload = encoded[start:end]
+
+		buf := make([]byte, pb.Size())
+		n, err := pb.MarshalTo(buf)
+		if err != nil {
+			return nil, err
+		}
+		buffers = append(buffers, NewBuffer(buf[:n], nil))
+	}
+	return buffers, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAggregatedRoundTrip(t *testing.T) {
+	large := testAggregatedMetric1
+	large.Annotation = bytes.Repeat([]byte("a"), 1000)
+
+	enc := NewAggregatedEncoder(nil)
+	require.NoError(t, enc.Encode(large, 2000))
+	encoded := enc.Buffer().Bytes()
+
+	const maxSize = 128
+	buffers, err := SplitAggregated(encoded, 42, maxSize)
+	require.NoError(t, err)
+	require.True(t, len(buffers) > 1)
+
+	var reassembled []byte
+	for i, buf := range buffers {
+		require.True(t, len(buf.Bytes()) <= maxSize)
+
+		dec := NewAggregatedDecoder(nil)
+		require.NoError(t, dec.Decode(buf.Bytes()))
+		split, ok := dec.Split()
+		require.True(t, ok)
+		require.NoError(t, split.Validate())
+		require.Equal(t, uint64(42), split.ID)
+		require.Equal(t, i, split.Index)
+		require.Equal(t, len(buffers), split.Count)
+		reassembled = append(reassembled, split.Payload...)
+		dec.Close()
+	}
+	require.Equal(t, encoded, reassembled)
+
+	dec := NewAggregatedDecoder(nil)
+	require.NoError(t, dec.Decode(reassembled))
+	_, ok := dec.Split()
+	require.False(t, ok)
+	require.Equal(t, string(large.ID), string(dec.ID()))
+	require.Equal(t, large.Annotation, dec.Annotation())
+	require.Equal(t, large.StoragePolicy, dec.StoragePolicy())
+	require.Equal(t, int64(2000), dec.EncodeNanos())
+}
+
+func TestSplitAggregatedMaxSizeTooSmall(t *testing.T) {
+	_, err := SplitAggregated([]byte("foo"), 1, maxAggregatedSplitOverhead)
+	require.Equal(t, errSplitMaxSizeTooSmall, err)
+}
+
+func TestAggregatedSplitValidate(t *testing.T) {
+	require.NoError(t, AggregatedSplit{Index: 1, Count: 2}.Validate())
+	require.Error(t, AggregatedSplit{Index: 0, Count: 0}.Validate())
+	require.Error(t, AggregatedSplit{Index: 2, Count: 2}.Validate())
+	require.Error(t, AggregatedSplit{Index: -1, Count: 2}.Validate())
+}
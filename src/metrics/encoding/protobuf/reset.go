@@ -50,6 +50,9 @@ func ReuseMetricWithMetadatasProto(pb *metricpb.MetricWithMetadatas) {
 	resetTimedMetricWithMetadataProto(pb.TimedMetricWithMetadata)
 	resetTimedMetricWithMetadatasProto(pb.TimedMetricWithMetadatas)
 	resetTimedMetricWithStoragePolicyProto(pb.TimedMetricWithStoragePolicy)
+	resetHistogramWithMetadatasProto(pb.HistogramWithMetadatas)
+	resetHandshakeProto(pb.Handshake)
+	resetShardIngestionPausedProto(pb.ShardIngestionPaused)
 }
 
 // ReuseAggregatedMetricProto allows for zero-alloc reuse of
@@ -60,6 +63,11 @@ func ReuseAggregatedMetricProto(pb *metricpb.AggregatedMetric) {
 	}
 	resetTimedMetricWithStoragePolicyProto(&pb.Metric)
 	pb.EncodeNanos = 0
+	pb.DowngradedResolutionNanos = 0
+	pb.SplitId = 0
+	pb.SplitIndex = 0
+	pb.SplitCount = 0
+	pb.SplitPayload = pb.SplitPayload[:0]
 }
 
 func resetCounterWithMetadatasProto(pb *metricpb.CounterWithMetadatas) {
@@ -86,6 +94,28 @@ func resetGaugeWithMetadatasProto(pb *metricpb.GaugeWithMetadatas) {
 	resetMetadatas(&pb.Metadatas)
 }
 
+func resetHistogramWithMetadatasProto(pb *metricpb.HistogramWithMetadatas) {
+	if pb == nil {
+		return
+	}
+	resetHistogram(&pb.Histogram)
+	resetMetadatas(&pb.Metadatas)
+}
+
+func resetHandshakeProto(pb *metricpb.Handshake) {
+	if pb == nil {
+		return
+	}
+	pb.Reset()
+}
+
+func resetShardIngestionPausedProto(pb *metricpb.ShardIngestionPaused) {
+	if pb == nil {
+		return
+	}
+	pb.Reset()
+}
+
 func resetForwardedMetricWithMetadataProto(pb *metricpb.ForwardedMetricWithMetadata) {
 	if pb == nil {
 		return
@@ -126,6 +156,7 @@ func resetCounter(pb *metricpb.Counter) {
 	pb.Value = 0
 	pb.Annotation = pb.Annotation[:0]
 	pb.ClientTimeNanos = 0
+	pb.Exemplars = pb.Exemplars[:0]
 }
 
 func resetBatchTimer(pb *metricpb.BatchTimer) {
@@ -136,6 +167,7 @@ func resetBatchTimer(pb *metricpb.BatchTimer) {
 	pb.Values = pb.Values[:0]
 	pb.Annotation = pb.Annotation[:0]
 	pb.ClientTimeNanos = 0
+	pb.Exemplars = pb.Exemplars[:0]
 }
 
 func resetGauge(pb *metricpb.Gauge) {
@@ -146,6 +178,19 @@ func resetGauge(pb *metricpb.Gauge) {
 	pb.Value = 0.0
 	pb.Annotation = pb.Annotation[:0]
 	pb.ClientTimeNanos = 0
+	pb.Exemplars = pb.Exemplars[:0]
+}
+
+func resetHistogram(pb *metricpb.Histogram) {
+	if pb == nil {
+		return
+	}
+	pb.Id = pb.Id[:0]
+	pb.BucketUpperBounds = pb.BucketUpperBounds[:0]
+	pb.BucketCounts = pb.BucketCounts[:0]
+	pb.Sum = 0.0
+	pb.Annotation = pb.Annotation[:0]
+	pb.ClientTimeNanos = 0
 }
 
 func resetForwardedMetric(pb *metricpb.ForwardedMetric) {
@@ -158,6 +203,10 @@ func resetForwardedMetric(pb *metricpb.ForwardedMetric) {
 	pb.Values = pb.Values[:0]
 	pb.PrevValues = pb.PrevValues[:0]
 	pb.Annotation = pb.Annotation[:0]
+	pb.Exemplars = pb.Exemplars[:0]
+	pb.HistogramBucketUpperBounds = pb.HistogramBucketUpperBounds[:0]
+	pb.HistogramBucketCounts = pb.HistogramBucketCounts[:0]
+	pb.HistogramSum = 0.0
 	pb.Version = 0
 }
 
@@ -170,6 +219,7 @@ func resetTimedMetric(pb *metricpb.TimedMetric) {
 	pb.TimeNanos = 0
 	pb.Value = 0
 	pb.Annotation = pb.Annotation[:0]
+	pb.Exemplars = pb.Exemplars[:0]
 }
 
 func resetMetadatas(pb *metricpb.StagedMetadatas) {
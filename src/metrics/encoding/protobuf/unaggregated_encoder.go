@@ -66,6 +66,9 @@ type unaggregatedEncoder struct {
 	tms                 metricpb.TimedMetricWithMetadatas
 	cm                  metricpb.CounterWithMetadatas
 	gm                  metricpb.GaugeWithMetadatas
+	hm                  metricpb.HistogramWithMetadatas
+	hs                  metricpb.Handshake
+	sp                  metricpb.ShardIngestionPaused
 	buf                 []byte
 	fm                  metricpb.ForwardedMetricWithMetadata
 	pm                  metricpb.TimedMetricWithStoragePolicy
@@ -131,6 +134,12 @@ func (enc *unaggregatedEncoder) EncodeMessage(msg encoding.UnaggregatedMessageUn
 		return enc.encodeTimedMetricWithMetadatas(msg.TimedMetricWithMetadatas)
 	case encoding.PassthroughMetricWithMetadataType:
 		return enc.encodePassthroughMetricWithMetadata(msg.PassthroughMetricWithMetadata)
+	case encoding.HistogramWithMetadatasType:
+		return enc.encodeHistogramWithMetadatas(msg.HistogramWithMetadatas)
+	case encoding.HandshakeType:
+		return enc.encodeHandshake(msg.Handshake)
+	case encoding.ShardIngestionPausedType:
+		return enc.encodeShardIngestionPaused(msg.ShardIngestionPaused)
 	default:
 		return fmt.Errorf("unknown message type: %v", msg.Type)
 	}
@@ -213,6 +222,35 @@ func (enc *unaggregatedEncoder) encodePassthroughMetricWithMetadata(pm aggregate
 	return enc.encodeMetricWithMetadatas(mm)
 }
 
+func (enc *unaggregatedEncoder) encodeHistogramWithMetadatas(hm unaggregated.HistogramWithMetadatas) error {
+	if err := hm.ToProto(&enc.hm); err != nil {
+		return fmt.Errorf("histogram with metadatas proto conversion failed: %v", err)
+	}
+	mm := metricpb.MetricWithMetadatas{
+		Type:                   metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS,
+		HistogramWithMetadatas: &enc.hm,
+	}
+	return enc.encodeMetricWithMetadatas(mm)
+}
+
+func (enc *unaggregatedEncoder) encodeHandshake(hs encoding.Handshake) error {
+	hs.ToProto(&enc.hs)
+	mm := metricpb.MetricWithMetadatas{
+		Type:      metricpb.MetricWithMetadatas_HANDSHAKE,
+		Handshake: &enc.hs,
+	}
+	return enc.encodeMetricWithMetadatas(mm)
+}
+
+func (enc *unaggregatedEncoder) encodeShardIngestionPaused(sp encoding.ShardIngestionPaused) error {
+	sp.ToProto(&enc.sp)
+	mm := metricpb.MetricWithMetadatas{
+		Type:                 metricpb.MetricWithMetadatas_SHARD_INGESTION_PAUSED,
+		ShardIngestionPaused: &enc.sp,
+	}
+	return enc.encodeMetricWithMetadatas(mm)
+}
+
 func (enc *unaggregatedEncoder) encodeMetricWithMetadatas(pb metricpb.MetricWithMetadatas) error {
 	msgSize := pb.Size()
 	if msgSize > enc.maxMessageSize {
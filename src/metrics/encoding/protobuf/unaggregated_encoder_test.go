@@ -21,6 +21,7 @@
 package protobuf
 
 import (
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -72,6 +73,18 @@ var (
 		ID:    []byte("testGauge2"),
 		Value: 234231.345,
 	}
+	testHistogram1 = unaggregated.Histogram{
+		ID:                []byte("testHistogram1"),
+		BucketUpperBounds: []float64{0.1, 1, math.Inf(1)},
+		BucketCounts:      []int64{3, 5, 1},
+		Sum:               12.5,
+	}
+	testHistogram2 = unaggregated.Histogram{
+		ID:                []byte("testHistogram2"),
+		BucketUpperBounds: []float64{10, 100},
+		BucketCounts:      []int64{0, 7},
+		Sum:               483.2,
+	}
 	testForwardedMetric1 = aggregated.ForwardedMetric{
 		Type:      metric.CounterType,
 		ID:        []byte("testForwardedMetric1"),
@@ -277,6 +290,18 @@ var (
 		Id:    []byte("testGauge2"),
 		Value: 234231.345,
 	}
+	testHistogram1Proto = metricpb.Histogram{
+		Id:                []byte("testHistogram1"),
+		BucketUpperBounds: []float64{0.1, 1, math.Inf(1)},
+		BucketCounts:      []int64{3, 5, 1},
+		Sum:               12.5,
+	}
+	testHistogram2Proto = metricpb.Histogram{
+		Id:                []byte("testHistogram2"),
+		BucketUpperBounds: []float64{10, 100},
+		BucketCounts:      []int64{0, 7},
+		Sum:               483.2,
+	}
 	testForwardedMetric1Proto = metricpb.ForwardedMetric{
 		Type:      metricpb.MetricType_COUNTER,
 		Id:        []byte("testForwardedMetric1"),
@@ -746,6 +771,50 @@ func TestUnaggregatedEncoderEncodeGaugeWithMetadatas(t *testing.T) {
 	}
 }
 
+func TestUnaggregatedEncoderEncodeHistogramWithMetadatas(t *testing.T) {
+	inputs := []unaggregated.HistogramWithMetadatas{
+		{
+			Histogram:       testHistogram1,
+			StagedMetadatas: testStagedMetadatas1,
+		},
+		{
+			Histogram:       testHistogram2,
+			StagedMetadatas: testStagedMetadatas2,
+		},
+	}
+	expected := []metricpb.HistogramWithMetadatas{
+		{
+			Histogram: testHistogram1Proto,
+			Metadatas: testStagedMetadatas1Proto,
+		},
+		{
+			Histogram: testHistogram2Proto,
+			Metadatas: testStagedMetadatas2Proto,
+		},
+	}
+
+	var (
+		sizeRes int
+		pbRes   metricpb.MetricWithMetadatas
+	)
+	enc := NewUnaggregatedEncoder(NewUnaggregatedOptions())
+	enc.(*unaggregatedEncoder).encodeMessageSizeFn = func(size int) { sizeRes = size }
+	enc.(*unaggregatedEncoder).encodeMessageFn = func(pb metricpb.MetricWithMetadatas) error { pbRes = pb; return nil }
+	for i, input := range inputs {
+		require.NoError(t, enc.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                   encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: input,
+		}))
+		expectedProto := metricpb.MetricWithMetadatas{
+			Type:                   metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS,
+			HistogramWithMetadatas: &expected[i],
+		}
+		expectedMsgSize := expectedProto.Size()
+		require.Equal(t, expectedMsgSize, sizeRes)
+		require.Equal(t, expectedProto, pbRes)
+	}
+}
+
 func TestUnaggregatedEncoderEncodeForwardedMetricWithMetadata(t *testing.T) {
 	inputs := []aggregated.ForwardedMetricWithMetadata{
 		{
@@ -150,6 +150,15 @@ func (it *UnaggregatedIterator) decodeMessage(size int) error {
 	case metricpb.MetricWithMetadatas_TIMED_METRIC_WITH_STORAGE_POLICY:
 		it.msg.Type = encoding.PassthroughMetricWithMetadataType
 		it.err = it.msg.PassthroughMetricWithMetadata.FromProto(it.pb.TimedMetricWithStoragePolicy)
+	case metricpb.MetricWithMetadatas_HISTOGRAM_WITH_METADATAS:
+		it.msg.Type = encoding.HistogramWithMetadatasType
+		it.err = it.msg.HistogramWithMetadatas.FromProto(it.pb.HistogramWithMetadatas)
+	case metricpb.MetricWithMetadatas_HANDSHAKE:
+		it.msg.Type = encoding.HandshakeType
+		it.msg.Handshake.FromProto(it.pb.Handshake)
+	case metricpb.MetricWithMetadatas_SHARD_INGESTION_PAUSED:
+		it.msg.Type = encoding.ShardIngestionPausedType
+		it.msg.ShardIngestionPaused.FromProto(it.pb.ShardIngestionPaused)
 	default:
 		it.err = fmt.Errorf("unrecognized message type: %v", it.pb.Type)
 	}
@@ -173,6 +173,76 @@ func TestUnaggregatedIteratorDecodeGaugeWithMetadatas(t *testing.T) {
 	require.Equal(t, len(inputs), i)
 }
 
+func TestUnaggregatedIteratorDecodeHistogramWithMetadatas(t *testing.T) {
+	inputs := []unaggregated.HistogramWithMetadatas{
+		{
+			Histogram:       testHistogram1,
+			StagedMetadatas: testStagedMetadatas1,
+		},
+		{
+			Histogram:       testHistogram2,
+			StagedMetadatas: testStagedMetadatas2,
+		},
+	}
+
+	enc := NewUnaggregatedEncoder(NewUnaggregatedOptions())
+	for _, input := range inputs {
+		require.NoError(t, enc.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                   encoding.HistogramWithMetadatasType,
+			HistogramWithMetadatas: input,
+		}))
+	}
+	dataBuf := enc.Relinquish()
+	defer dataBuf.Close()
+
+	var (
+		i      int
+		stream = bytes.NewReader(dataBuf.Bytes())
+	)
+	it := NewUnaggregatedIterator(stream, NewUnaggregatedOptions())
+	defer it.Close()
+	for it.Next() {
+		res := it.Current()
+		require.Equal(t, encoding.HistogramWithMetadatasType, res.Type)
+		require.Equal(t, inputs[i], res.HistogramWithMetadatas)
+		i++
+	}
+	require.Equal(t, io.EOF, it.Err())
+	require.Equal(t, len(inputs), i)
+}
+
+func TestUnaggregatedIteratorDecodeHandshake(t *testing.T) {
+	inputs := []encoding.Handshake{
+		encoding.NewHandshake(),
+		{ProtocolVersion: 2},
+	}
+
+	enc := NewUnaggregatedEncoder(NewUnaggregatedOptions())
+	for _, input := range inputs {
+		require.NoError(t, enc.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:      encoding.HandshakeType,
+			Handshake: input,
+		}))
+	}
+	dataBuf := enc.Relinquish()
+	defer dataBuf.Close()
+
+	var (
+		i      int
+		stream = bytes.NewReader(dataBuf.Bytes())
+	)
+	it := NewUnaggregatedIterator(stream, NewUnaggregatedOptions())
+	defer it.Close()
+	for it.Next() {
+		res := it.Current()
+		require.Equal(t, encoding.HandshakeType, res.Type)
+		require.Equal(t, inputs[i], res.Handshake)
+		i++
+	}
+	require.Equal(t, io.EOF, it.Err())
+	require.Equal(t, len(inputs), i)
+}
+
 func TestUnaggregatedIteratorDecodeForwardedMetricWithMetadata(t *testing.T) {
 	inputs := []aggregated.ForwardedMetricWithMetadata{
 		{
@@ -571,3 +641,35 @@ func TestUnaggregatedIteratorNextOnInvalid(t *testing.T) {
 	require.False(t, it.Next())
 	require.False(t, it.Next())
 }
+
+func TestUnaggregatedIteratorDecodeShardIngestionPaused(t *testing.T) {
+	inputs := []encoding.ShardIngestionPaused{
+		{Shard: 3, UntilNanos: 1000},
+		{Shard: 0, UntilNanos: 2000},
+	}
+
+	enc := NewUnaggregatedEncoder(NewUnaggregatedOptions())
+	for _, input := range inputs {
+		require.NoError(t, enc.EncodeMessage(encoding.UnaggregatedMessageUnion{
+			Type:                 encoding.ShardIngestionPausedType,
+			ShardIngestionPaused: input,
+		}))
+	}
+	dataBuf := enc.Relinquish()
+	defer dataBuf.Close()
+
+	var (
+		i      int
+		stream = bytes.NewReader(dataBuf.Bytes())
+	)
+	it := NewUnaggregatedIterator(stream, NewUnaggregatedOptions())
+	defer it.Close()
+	for it.Next() {
+		res := it.Current()
+		require.Equal(t, encoding.ShardIngestionPausedType, res.Type)
+		require.Equal(t, inputs[i], res.ShardIngestionPaused)
+		i++
+	}
+	require.Equal(t, io.EOF, it.Err())
+	require.Equal(t, len(inputs), i)
+}
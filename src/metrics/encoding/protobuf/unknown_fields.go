@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+var (
+	errUnsupportedWireType = errors.New("unsupported protobuf wire type")
+
+	protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+	// messageFieldsByType caches the fields of each message type.
+	messageFieldsByType sync.Map
+)
+
+// messageFields maps the field numbers of a message type to the type of the
+// field if it is a nested message, or nil otherwise.
+type messageFields map[uint64]reflect.Type
+
+// UnknownFields returns the number of fields in the encoded message, including
+// the fields of nested messages, that are not fields of the message type, such
+// as fields added by a newer version of the message which are skipped when
+// unmarshalling. Only the type of msg is used so it may be a nil pointer.
+// NB: oneof fields are not supported.
+func UnknownFields(msg proto.Message, b []byte) (int, error) {
+	t := reflect.TypeOf(msg)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return unknownFields(t, b)
+}
+
+func unknownFields(t reflect.Type, b []byte) (int, error) {
+	var (
+		fields  = messageFieldsOf(t)
+		unknown int
+	)
+	for len(b) > 0 {
+		key, n := proto.DecodeVarint(b)
+		if n == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		b = b[n:]
+
+		var (
+			fieldNum = key >> 3
+			wireType = key & 0x7
+			value    []byte
+		)
+		switch wireType {
+		case proto.WireVarint:
+			if _, n = proto.DecodeVarint(b); n == 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+		case proto.WireFixed64:
+			n = 8
+		case proto.WireFixed32:
+			n = 4
+		case proto.WireBytes:
+			l, ln := proto.DecodeVarint(b)
+			if ln == 0 || l > uint64(len(b)-ln) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			n = ln + int(l)
+			value = b[ln:n]
+		default:
+			return 0, fmt.Errorf("%w: %d", errUnsupportedWireType, wireType)
+		}
+		if n > len(b) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		b = b[n:]
+
+		fieldType, ok := fields[fieldNum]
+		if !ok {
+			unknown++
+			continue
+		}
+		if fieldType != nil && wireType == proto.WireBytes {
+			nested, err := unknownFields(fieldType, value)
+			if err != nil {
+				return 0, err
+			}
+			unknown += nested
+		}
+	}
+	return unknown, nil
+}
+
+func messageFieldsOf(t reflect.Type) messageFields {
+	if fields, ok := messageFieldsByType.Load(t); ok {
+		return fields.(messageFields)
+	}
+
+	fields := make(messageFields, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// The tag is of the form "bytes,1,opt,name=metric".
+		parts := strings.Split(f.Tag.Get("protobuf"), ",")
+		if len(parts) < 2 {
+			continue
+		}
+		num, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[num] = nestedMessageType(f.Type)
+	}
+	messageFieldsByType.Store(t, fields)
+	return fields
+}
+
+func nestedMessageType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || !reflect.PtrTo(t).Implements(protoMessageType) {
+		return nil
+	}
+	return t
+}
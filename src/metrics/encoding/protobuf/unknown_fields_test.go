@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFields(t *testing.T) {
+	timedMetric := metricpb.TimedMetric{
+		Type:      metricpb.MetricType_GAUGE,
+		Id:        []byte("foo"),
+		TimeNanos: 1234,
+		Value:     1.5,
+	}
+	timedMetricBytes, err := timedMetric.Marshal()
+	require.NoError(t, err)
+
+	// Add an unknown field to the nested timed metric.
+	nested := proto.NewBuffer(append([]byte(nil), timedMetricBytes...))
+	require.NoError(t, nested.EncodeVarint(uint64(99<<3|proto.WireBytes)))
+	require.NoError(t, nested.EncodeRawBytes([]byte("new")))
+
+	withPolicy := proto.NewBuffer(nil)
+	require.NoError(t, withPolicy.EncodeVarint(uint64(1<<3|proto.WireBytes)))
+	require.NoError(t, withPolicy.EncodeRawBytes(nested.Bytes()))
+
+	// Add an unknown field to the top level aggregated metric.
+	buf := proto.NewBuffer(nil)
+	require.NoError(t, buf.EncodeVarint(uint64(1<<3|proto.WireBytes)))
+	require.NoError(t, buf.EncodeRawBytes(withPolicy.Bytes()))
+	require.NoError(t, buf.EncodeVarint(uint64(2<<3|proto.WireVarint)))
+	require.NoError(t, buf.EncodeVarint(5678))
+	require.NoError(t, buf.EncodeVarint(uint64(100<<3|proto.WireFixed64)))
+	require.NoError(t, buf.EncodeFixed64(42))
+
+	unknown, err := UnknownFields((*metricpb.AggregatedMetric)(nil), buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 2, unknown)
+
+	// The unknown fields are skipped when unmarshalling.
+	var pb metricpb.AggregatedMetric
+	require.NoError(t, pb.Unmarshal(buf.Bytes()))
+	require.Equal(t, timedMetric, pb.Metric.TimedMetric)
+	require.Equal(t, int64(5678), pb.EncodeNanos)
+
+	unknown, err = UnknownFields((*metricpb.TimedMetric)(nil), timedMetricBytes)
+	require.NoError(t, err)
+	require.Equal(t, 0, unknown)
+}
+
+func TestUnknownFieldsTruncated(t *testing.T) {
+	b, err := (&metricpb.TimedMetric{Id: []byte("foo")}).Marshal()
+	require.NoError(t, err)
+
+	_, err = UnknownFields((*metricpb.TimedMetric)(nil), b[:len(b)-1])
+	require.Error(t, err)
+}
@@ -40,6 +40,9 @@ const (
 	TimedMetricWithMetadataType
 	TimedMetricWithMetadatasType
 	PassthroughMetricWithMetadataType
+	HistogramWithMetadatasType
+	HandshakeType
+	ShardIngestionPausedType
 )
 
 // UnaggregatedMessageUnion is a union of different types of unaggregated messages.
@@ -55,6 +58,9 @@ type UnaggregatedMessageUnion struct {
 	TimedMetricWithMetadata       aggregated.TimedMetricWithMetadata
 	TimedMetricWithMetadatas      aggregated.TimedMetricWithMetadatas
 	PassthroughMetricWithMetadata aggregated.PassthroughMetricWithMetadata
+	HistogramWithMetadatas        unaggregated.HistogramWithMetadatas
+	Handshake                     Handshake
+	ShardIngestionPaused          ShardIngestionPaused
 }
 
 // ByteReadScanner is capable of reading and scanning bytes.
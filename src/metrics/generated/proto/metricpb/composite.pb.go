@@ -39,6 +39,8 @@
 		TimedMetricWithStoragePolicy
 		AggregatedMetric
 		MetricWithMetadatas
+		HistogramWithMetadatas
+		Handshake
 		PipelineMetadata
 		Metadata
 		StagedMetadata
@@ -51,6 +53,8 @@
 		TimedMetric
 		ForwardedMetric
 		Tag
+		Histogram
+		Exemplar
 */
 package metricpb
 
@@ -84,17 +88,23 @@ const (
 	MetricWithMetadatas_TIMED_METRIC_WITH_METADATA       MetricWithMetadatas_Type = 5
 	MetricWithMetadatas_TIMED_METRIC_WITH_METADATAS      MetricWithMetadatas_Type = 6
 	MetricWithMetadatas_TIMED_METRIC_WITH_STORAGE_POLICY MetricWithMetadatas_Type = 7
+	MetricWithMetadatas_HISTOGRAM_WITH_METADATAS         MetricWithMetadatas_Type = 8
+	MetricWithMetadatas_HANDSHAKE                        MetricWithMetadatas_Type = 9
+	MetricWithMetadatas_SHARD_INGESTION_PAUSED           MetricWithMetadatas_Type = 10
 )
 
 var MetricWithMetadatas_Type_name = map[int32]string{
-	0: "UNKNOWN",
-	1: "COUNTER_WITH_METADATAS",
-	2: "BATCH_TIMER_WITH_METADATAS",
-	3: "GAUGE_WITH_METADATAS",
-	4: "FORWARDED_METRIC_WITH_METADATA",
-	5: "TIMED_METRIC_WITH_METADATA",
-	6: "TIMED_METRIC_WITH_METADATAS",
-	7: "TIMED_METRIC_WITH_STORAGE_POLICY",
+	0:  "UNKNOWN",
+	1:  "COUNTER_WITH_METADATAS",
+	2:  "BATCH_TIMER_WITH_METADATAS",
+	3:  "GAUGE_WITH_METADATAS",
+	4:  "FORWARDED_METRIC_WITH_METADATA",
+	5:  "TIMED_METRIC_WITH_METADATA",
+	6:  "TIMED_METRIC_WITH_METADATAS",
+	7:  "TIMED_METRIC_WITH_STORAGE_POLICY",
+	8:  "HISTOGRAM_WITH_METADATAS",
+	9:  "HANDSHAKE",
+	10: "SHARD_INGESTION_PAUSED",
 }
 var MetricWithMetadatas_Type_value = map[string]int32{
 	"UNKNOWN":                          0,
@@ -105,6 +115,9 @@ var MetricWithMetadatas_Type_value = map[string]int32{
 	"TIMED_METRIC_WITH_METADATA":       5,
 	"TIMED_METRIC_WITH_METADATAS":      6,
 	"TIMED_METRIC_WITH_STORAGE_POLICY": 7,
+	"HISTOGRAM_WITH_METADATAS":         8,
+	"HANDSHAKE":                        9,
+	"SHARD_INGESTION_PAUSED":           10,
 }
 
 func (x MetricWithMetadatas_Type) String() string {
@@ -291,6 +304,18 @@ func (m *TimedMetricWithStoragePolicy) GetStoragePolicy() policypb.StoragePolicy
 type AggregatedMetric struct {
 	Metric      TimedMetricWithStoragePolicy `protobuf:"bytes,1,opt,name=metric" json:"metric"`
 	EncodeNanos int64                        `protobuf:"varint,2,opt,name=encode_nanos,json=encodeNanos,proto3" json:"encode_nanos,omitempty"`
+	// downgraded_resolution_nanos is non-zero when the aggregator emitted the
+	// metric at a coarser resolution than its storage policy while in the
+	// emergency resolution downgrade mode, and holds the effective resolution.
+	DowngradedResolutionNanos int64 `protobuf:"varint,3,opt,name=downgraded_resolution_nanos,json=downgradedResolutionNanos,proto3" json:"downgraded_resolution_nanos,omitempty"`
+	// split_count is non-zero when the encoded metric exceeded the max message
+	// size and was split across split_count messages, each of which carries the
+	// split_index fragment of the encoded metric in split_payload. Fragments of
+	// the same metric share the split_id.
+	SplitId      uint64 `protobuf:"varint,4,opt,name=split_id,json=splitId,proto3" json:"split_id,omitempty"`
+	SplitIndex   int32  `protobuf:"varint,5,opt,name=split_index,json=splitIndex,proto3" json:"split_index,omitempty"`
+	SplitCount   int32  `protobuf:"varint,6,opt,name=split_count,json=splitCount,proto3" json:"split_count,omitempty"`
+	SplitPayload []byte `protobuf:"bytes,7,opt,name=split_payload,json=splitPayload,proto3" json:"split_payload,omitempty"`
 }
 
 func (m *AggregatedMetric) Reset()                    { *m = AggregatedMetric{} }
@@ -312,6 +337,41 @@ func (m *AggregatedMetric) GetEncodeNanos() int64 {
 	return 0
 }
 
+func (m *AggregatedMetric) GetDowngradedResolutionNanos() int64 {
+	if m != nil {
+		return m.DowngradedResolutionNanos
+	}
+	return 0
+}
+
+func (m *AggregatedMetric) GetSplitId() uint64 {
+	if m != nil {
+		return m.SplitId
+	}
+	return 0
+}
+
+func (m *AggregatedMetric) GetSplitIndex() int32 {
+	if m != nil {
+		return m.SplitIndex
+	}
+	return 0
+}
+
+func (m *AggregatedMetric) GetSplitCount() int32 {
+	if m != nil {
+		return m.SplitCount
+	}
+	return 0
+}
+
+func (m *AggregatedMetric) GetSplitPayload() []byte {
+	if m != nil {
+		return m.SplitPayload
+	}
+	return nil
+}
+
 // NB: we intentionally choose to explicitly define the message type as well
 // as the corresponding payload as opposed to use `oneof` protobuf type here.
 // This is because the generated `Unmarshal` method of `oneof` types doesn't
@@ -327,6 +387,9 @@ type MetricWithMetadatas struct {
 	TimedMetricWithMetadata      *TimedMetricWithMetadata      `protobuf:"bytes,6,opt,name=timed_metric_with_metadata,json=timedMetricWithMetadata" json:"timed_metric_with_metadata,omitempty"`
 	TimedMetricWithMetadatas     *TimedMetricWithMetadatas     `protobuf:"bytes,7,opt,name=timed_metric_with_metadatas,json=timedMetricWithMetadatas" json:"timed_metric_with_metadatas,omitempty"`
 	TimedMetricWithStoragePolicy *TimedMetricWithStoragePolicy `protobuf:"bytes,8,opt,name=timed_metric_with_storage_policy,json=timedMetricWithStoragePolicy" json:"timed_metric_with_storage_policy,omitempty"`
+	HistogramWithMetadatas       *HistogramWithMetadatas       `protobuf:"bytes,9,opt,name=histogram_with_metadatas,json=histogramWithMetadatas" json:"histogram_with_metadatas,omitempty"`
+	Handshake                    *Handshake                    `protobuf:"bytes,10,opt,name=handshake" json:"handshake,omitempty"`
+	ShardIngestionPaused         *ShardIngestionPaused         `protobuf:"bytes,11,opt,name=shard_ingestion_paused,json=shardIngestionPaused" json:"shard_ingestion_paused,omitempty"`
 }
 
 func (m *MetricWithMetadatas) Reset()                    { *m = MetricWithMetadatas{} }
@@ -390,6 +453,111 @@ func (m *MetricWithMetadatas) GetTimedMetricWithStoragePolicy() *TimedMetricWith
 	return nil
 }
 
+func (m *MetricWithMetadatas) GetHistogramWithMetadatas() *HistogramWithMetadatas {
+	if m != nil {
+		return m.HistogramWithMetadatas
+	}
+	return nil
+}
+
+func (m *MetricWithMetadatas) GetHandshake() *Handshake {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+func (m *MetricWithMetadatas) GetShardIngestionPaused() *ShardIngestionPaused {
+	if m != nil {
+		return m.ShardIngestionPaused
+	}
+	return nil
+}
+
+type HistogramWithMetadatas struct {
+	Histogram Histogram       `protobuf:"bytes,1,opt,name=histogram" json:"histogram"`
+	Metadatas StagedMetadatas `protobuf:"bytes,2,opt,name=metadatas" json:"metadatas"`
+}
+
+func (m *HistogramWithMetadatas) Reset()         { *m = HistogramWithMetadatas{} }
+func (m *HistogramWithMetadatas) String() string { return proto.CompactTextString(m) }
+func (*HistogramWithMetadatas) ProtoMessage()    {}
+func (*HistogramWithMetadatas) Descriptor() ([]byte, []int) {
+	return fileDescriptorComposite, []int{9}
+}
+
+func (m *HistogramWithMetadatas) GetHistogram() Histogram {
+	if m != nil {
+		return m.Histogram
+	}
+	return Histogram{}
+}
+
+func (m *HistogramWithMetadatas) GetMetadatas() StagedMetadatas {
+	if m != nil {
+		return m.Metadatas
+	}
+	return StagedMetadatas{}
+}
+
+// Handshake is exchanged by aggregator clients and servers right after a
+// connection is established so both sides can agree on the protocol version
+// and the optional features they may use. Servers that predate the handshake
+// reject it as an unrecognized message type and close the connection.
+type Handshake struct {
+	// protocol_version is the highest protocol version the sender speaks.
+	ProtocolVersion uint32 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// capabilities is a bit set of the optional features the sender supports.
+	Capabilities uint64 `protobuf:"varint,2,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+}
+
+func (m *Handshake) Reset()                    { *m = Handshake{} }
+func (m *Handshake) String() string            { return proto.CompactTextString(m) }
+func (*Handshake) ProtoMessage()               {}
+func (*Handshake) Descriptor() ([]byte, []int) { return fileDescriptorComposite, []int{10} }
+
+func (m *Handshake) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *Handshake) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
+// ShardIngestionPaused is sent by aggregator servers to clients that negotiated
+// support for it when a write was rejected because the ingestion of the shard
+// it belongs to is paused, so that clients can back off from the shard.
+type ShardIngestionPaused struct {
+	Shard uint32 `protobuf:"varint,1,opt,name=shard,proto3" json:"shard,omitempty"`
+	// until_nanos is when the pause expires.
+	UntilNanos int64 `protobuf:"varint,2,opt,name=until_nanos,json=untilNanos,proto3" json:"until_nanos,omitempty"`
+}
+
+func (m *ShardIngestionPaused) Reset()                    { *m = ShardIngestionPaused{} }
+func (m *ShardIngestionPaused) String() string            { return proto.CompactTextString(m) }
+func (*ShardIngestionPaused) ProtoMessage()               {}
+func (*ShardIngestionPaused) Descriptor() ([]byte, []int) { return fileDescriptorComposite, []int{11} }
+
+func (m *ShardIngestionPaused) GetShard() uint32 {
+	if m != nil {
+		return m.Shard
+	}
+	return 0
+}
+
+func (m *ShardIngestionPaused) GetUntilNanos() int64 {
+	if m != nil {
+		return m.UntilNanos
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*CounterWithMetadatas)(nil), "metricpb.CounterWithMetadatas")
 	proto.RegisterType((*BatchTimerWithMetadatas)(nil), "metricpb.BatchTimerWithMetadatas")
@@ -400,6 +568,9 @@ func init() {
 	proto.RegisterType((*TimedMetricWithStoragePolicy)(nil), "metricpb.TimedMetricWithStoragePolicy")
 	proto.RegisterType((*AggregatedMetric)(nil), "metricpb.AggregatedMetric")
 	proto.RegisterType((*MetricWithMetadatas)(nil), "metricpb.MetricWithMetadatas")
+	proto.RegisterType((*HistogramWithMetadatas)(nil), "metricpb.HistogramWithMetadatas")
+	proto.RegisterType((*Handshake)(nil), "metricpb.Handshake")
+	proto.RegisterType((*ShardIngestionPaused)(nil), "metricpb.ShardIngestionPaused")
 	proto.RegisterEnum("metricpb.MetricWithMetadatas_Type", MetricWithMetadatas_Type_name, MetricWithMetadatas_Type_value)
 }
 func (m *CounterWithMetadatas) Marshal() (dAtA []byte, err error) {
@@ -668,6 +839,32 @@ func (m *AggregatedMetric) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintComposite(dAtA, i, uint64(m.EncodeNanos))
 	}
+	if m.DowngradedResolutionNanos != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.DowngradedResolutionNanos))
+	}
+	if m.SplitId != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.SplitId))
+	}
+	if m.SplitIndex != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.SplitIndex))
+	}
+	if m.SplitCount != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.SplitCount))
+	}
+	if len(m.SplitPayload) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(len(m.SplitPayload)))
+		i += copy(dAtA[i:], m.SplitPayload)
+	}
 	return i, nil
 }
 
@@ -761,6 +958,126 @@ func (m *MetricWithMetadatas) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n22
 	}
+	if m.HistogramWithMetadatas != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.HistogramWithMetadatas.Size()))
+		n23, err := m.HistogramWithMetadatas.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if m.Handshake != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.Handshake.Size()))
+		n26, err := m.Handshake.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	if m.ShardIngestionPaused != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.ShardIngestionPaused.Size()))
+		n27, err := m.ShardIngestionPaused.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	return i, nil
+}
+
+func (m *HistogramWithMetadatas) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HistogramWithMetadatas) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintComposite(dAtA, i, uint64(m.Histogram.Size()))
+	n24, err := m.Histogram.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n24
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintComposite(dAtA, i, uint64(m.Metadatas.Size()))
+	n25, err := m.Metadatas.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n25
+	return i, nil
+}
+
+func (m *Handshake) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Handshake) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ProtocolVersion != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.ProtocolVersion))
+	}
+	if m.Capabilities != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.Capabilities))
+	}
+	return i, nil
+}
+
+func (m *ShardIngestionPaused) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShardIngestionPaused) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Shard != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.Shard))
+	}
+	if m.UntilNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintComposite(dAtA, i, uint64(m.UntilNanos))
+	}
 	return i, nil
 }
 
@@ -851,6 +1168,22 @@ func (m *AggregatedMetric) Size() (n int) {
 	if m.EncodeNanos != 0 {
 		n += 1 + sovComposite(uint64(m.EncodeNanos))
 	}
+	if m.DowngradedResolutionNanos != 0 {
+		n += 1 + sovComposite(uint64(m.DowngradedResolutionNanos))
+	}
+	if m.SplitId != 0 {
+		n += 1 + sovComposite(uint64(m.SplitId))
+	}
+	if m.SplitIndex != 0 {
+		n += 1 + sovComposite(uint64(m.SplitIndex))
+	}
+	if m.SplitCount != 0 {
+		n += 1 + sovComposite(uint64(m.SplitCount))
+	}
+	l = len(m.SplitPayload)
+	if l > 0 {
+		n += 1 + l + sovComposite(uint64(l))
+	}
 	return n
 }
 
@@ -888,6 +1221,52 @@ func (m *MetricWithMetadatas) Size() (n int) {
 		l = m.TimedMetricWithStoragePolicy.Size()
 		n += 1 + l + sovComposite(uint64(l))
 	}
+	if m.HistogramWithMetadatas != nil {
+		l = m.HistogramWithMetadatas.Size()
+		n += 1 + l + sovComposite(uint64(l))
+	}
+	if m.Handshake != nil {
+		l = m.Handshake.Size()
+		n += 1 + l + sovComposite(uint64(l))
+	}
+	if m.ShardIngestionPaused != nil {
+		l = m.ShardIngestionPaused.Size()
+		n += 1 + l + sovComposite(uint64(l))
+	}
+	return n
+}
+
+func (m *HistogramWithMetadatas) Size() (n int) {
+	var l int
+	_ = l
+	l = m.Histogram.Size()
+	n += 1 + l + sovComposite(uint64(l))
+	l = m.Metadatas.Size()
+	n += 1 + l + sovComposite(uint64(l))
+	return n
+}
+
+func (m *Handshake) Size() (n int) {
+	var l int
+	_ = l
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovComposite(uint64(m.ProtocolVersion))
+	}
+	if m.Capabilities != 0 {
+		n += 1 + sovComposite(uint64(m.Capabilities))
+	}
+	return n
+}
+
+func (m *ShardIngestionPaused) Size() (n int) {
+	var l int
+	_ = l
+	if m.Shard != 0 {
+		n += 1 + sovComposite(uint64(m.Shard))
+	}
+	if m.UntilNanos != 0 {
+		n += 1 + sovComposite(uint64(m.UntilNanos))
+	}
 	return n
 }
 
@@ -1752,36 +2131,143 @@ func (m *AggregatedMetric) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipComposite(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthComposite
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DowngradedResolutionNanos", wireType)
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			m.DowngradedResolutionNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DowngradedResolutionNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *MetricWithMetadatas) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowComposite
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitId", wireType)
+			}
+			m.SplitId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitIndex", wireType)
+			}
+			m.SplitIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitIndex |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitCount", wireType)
+			}
+			m.SplitCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitPayload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SplitPayload = append(m.SplitPayload[:0], dAtA[iNdEx:postIndex]...)
+			if m.SplitPayload == nil {
+				m.SplitPayload = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipComposite(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthComposite
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MetricWithMetadatas) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowComposite
 			}
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
@@ -2052,6 +2538,391 @@ func (m *MetricWithMetadatas) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramWithMetadatas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HistogramWithMetadatas == nil {
+				m.HistogramWithMetadatas = &HistogramWithMetadatas{}
+			}
+			if err := m.HistogramWithMetadatas.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Handshake", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Handshake == nil {
+				m.Handshake = &Handshake{}
+			}
+			if err := m.Handshake.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardIngestionPaused", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ShardIngestionPaused == nil {
+				m.ShardIngestionPaused = &ShardIngestionPaused{}
+			}
+			if err := m.ShardIngestionPaused.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipComposite(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthComposite
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HistogramWithMetadatas) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowComposite
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HistogramWithMetadatas: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HistogramWithMetadatas: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Histogram", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Histogram.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadatas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthComposite
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Metadatas.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipComposite(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthComposite
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Handshake) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowComposite
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Handshake: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Handshake: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			m.Capabilities = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Capabilities |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipComposite(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthComposite
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShardIngestionPaused) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowComposite
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShardIngestionPaused: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShardIngestionPaused: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shard", wireType)
+			}
+			m.Shard = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Shard |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UntilNanos", wireType)
+			}
+			m.UntilNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowComposite
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UntilNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipComposite(dAtA[iNdEx:])
@@ -2183,56 +3054,71 @@ func init() {
 }
 
 var fileDescriptorComposite = []byte{
-	// 808 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x96, 0xcd, 0x6e, 0xd3, 0x58,
-	0x14, 0xc7, 0xeb, 0x36, 0x4d, 0x3a, 0x27, 0x9d, 0x4e, 0xe6, 0x4e, 0xa6, 0xc9, 0x24, 0x95, 0xdb,
-	0x5a, 0x33, 0x23, 0x24, 0x44, 0x22, 0x1a, 0x89, 0x0a, 0x55, 0x20, 0x39, 0x1f, 0x4d, 0x23, 0x68,
-	0x52, 0x39, 0xae, 0x22, 0x58, 0xd4, 0xb2, 0x1d, 0xd7, 0x31, 0x22, 0x71, 0x64, 0xdf, 0xa8, 0xaa,
-	0xd8, 0xb0, 0x84, 0x0d, 0x42, 0x42, 0xbc, 0x01, 0x0f, 0xd3, 0x25, 0x4f, 0x80, 0x50, 0x59, 0xb1,
-	0xe7, 0x01, 0x90, 0xed, 0xeb, 0xf8, 0x2b, 0x2e, 0x90, 0xec, 0xec, 0xf3, 0xf1, 0x3b, 0xff, 0x5c,
-	0xdf, 0xf3, 0x57, 0xa0, 0xa9, 0x6a, 0x78, 0x30, 0x91, 0x4a, 0xb2, 0x3e, 0x2c, 0x0f, 0x2b, 0x7d,
-	0xa9, 0x3c, 0xac, 0x94, 0x4d, 0x43, 0x2e, 0x0f, 0x15, 0x6c, 0x68, 0xb2, 0x59, 0x56, 0x95, 0x91,
-	0x62, 0x88, 0x58, 0xe9, 0x97, 0xc7, 0x86, 0x8e, 0x75, 0x12, 0x1f, 0x4b, 0x65, 0x59, 0x1f, 0x8e,
-	0x75, 0x53, 0xc3, 0x4a, 0xc9, 0x4e, 0xa0, 0x35, 0x37, 0x53, 0xb8, 0xe3, 0x43, 0xaa, 0xba, 0xaa,
-	0x3b, 0x9d, 0xd2, 0xe4, 0xdc, 0x7e, 0x73, 0x30, 0xd6, 0x93, 0xd3, 0x58, 0xa8, 0xcf, 0xab, 0xc0,
-	0x79, 0x20, 0x94, 0xc3, 0x05, 0x28, 0x62, 0x5f, 0xc4, 0xe2, 0x9c, 0x6a, 0xc6, 0xfa, 0x73, 0x4d,
-	0xbe, 0x1c, 0x4b, 0xe4, 0xc1, 0xa1, 0x30, 0xaf, 0x28, 0xc8, 0xd6, 0xf4, 0xc9, 0x08, 0x2b, 0x46,
-	0x4f, 0xc3, 0x83, 0x63, 0x32, 0xc3, 0x44, 0x77, 0x21, 0x25, 0x3b, 0xf1, 0x3c, 0xb5, 0x43, 0xdd,
-	0x4a, 0xef, 0xfd, 0x59, 0x72, 0x95, 0x94, 0x48, 0x43, 0x35, 0x71, 0xf5, 0x69, 0x7b, 0x89, 0x73,
-	0xeb, 0xd0, 0x03, 0xf8, 0xcd, 0xd5, 0x68, 0xe6, 0x97, 0xed, 0xa6, 0x7f, 0xbc, 0xa6, 0x2e, 0x16,
-	0x55, 0xa5, 0x3f, 0x1d, 0x40, 0x9a, 0xbd, 0x0e, 0xe6, 0x3d, 0x05, 0xb9, 0xaa, 0x88, 0xe5, 0x01,
-	0xaf, 0x0d, 0xc3, 0x6a, 0x0e, 0x20, 0x2d, 0x59, 0x29, 0x01, 0x5b, 0x39, 0xa2, 0x28, 0xeb, 0xc1,
-	0xbd, 0x3e, 0xc2, 0x05, 0x69, 0x1a, 0x59, 0x54, 0xd7, 0x4b, 0x0a, 0x50, 0x53, 0x9c, 0xa8, 0x4a,
-	0x50, 0xd2, 0x6d, 0x58, 0x55, 0xad, 0x28, 0x11, 0xf3, 0x87, 0x47, 0xb4, 0x8b, 0x09, 0xc7, 0xa9,
-	0x59, 0x54, 0xc2, 0x3b, 0x0a, 0x8a, 0x87, 0xba, 0x71, 0x21, 0x1a, 0x7d, 0xbb, 0xce, 0xd0, 0x64,
-	0xbf, 0x18, 0xb4, 0x0f, 0x49, 0x07, 0x46, 0xc4, 0xf8, 0xd8, 0xa1, 0x36, 0xc2, 0x26, 0xe5, 0xe8,
-	0x00, 0xd6, 0xdc, 0x29, 0x51, 0x59, 0xa4, 0xd5, 0x9d, 0x42, 0x5a, 0xa7, 0x0d, 0xcc, 0x6b, 0x0a,
-	0x72, 0xd6, 0x09, 0xcf, 0x52, 0x54, 0x09, 0x29, 0xfa, 0xdb, 0xc3, 0xfa, 0x5a, 0x42, 0x6a, 0xee,
-	0x47, 0xd4, 0xe4, 0xa2, 0x6d, 0xb3, 0xb5, 0xbc, 0xa1, 0x20, 0x1f, 0xa3, 0xc5, 0x9c, 0x4f, 0xcc,
-	0x82, 0x9f, 0xec, 0x03, 0x05, 0x5b, 0x21, 0x41, 0x5d, 0xac, 0x1b, 0xa2, 0xaa, 0x9c, 0xd8, 0xfb,
-	0x87, 0x1e, 0xc2, 0xba, 0x75, 0x99, 0xfb, 0xc2, 0xcf, 0x4b, 0x4b, 0x63, 0x2f, 0x84, 0xea, 0xb0,
-	0x61, 0x3a, 0x40, 0xc1, 0xd9, 0xe8, 0xe9, 0x91, 0xb9, 0x9b, 0x5e, 0x0a, 0x0c, 0x24, 0x8c, 0xdf,
-	0x4d, 0x7f, 0x90, 0x79, 0x01, 0x19, 0x56, 0x55, 0x0d, 0x45, 0xb5, 0x9c, 0x62, 0x4a, 0x0e, 0x1e,
-	0xd7, 0xff, 0x33, 0x35, 0x45, 0x7e, 0x51, 0xe8, 0xfc, 0x76, 0x61, 0x5d, 0x19, 0xc9, 0x7a, 0x5f,
-	0x11, 0x46, 0xe2, 0x48, 0x77, 0x8e, 0x70, 0x85, 0x4b, 0x3b, 0xb1, 0xb6, 0x15, 0x62, 0xbe, 0xa6,
-	0xe0, 0xaf, 0x59, 0xdf, 0xeb, 0x1e, 0x24, 0xf0, 0xe5, 0xd8, 0xd9, 0xac, 0x8d, 0x3d, 0xc6, 0x1b,
-	0x3f, 0xa3, 0xb8, 0xc4, 0x5f, 0x8e, 0x15, 0xce, 0xae, 0x47, 0x3c, 0x6c, 0x12, 0x2f, 0x12, 0x2e,
-	0x34, 0x3c, 0x10, 0xc2, 0xdf, 0x8f, 0x8e, 0x58, 0x58, 0x00, 0xc5, 0x65, 0xe5, 0x59, 0x4e, 0x78,
-	0x06, 0x05, 0x9f, 0xf7, 0x84, 0xc9, 0x2b, 0x36, 0x79, 0x77, 0x96, 0x15, 0x05, 0xe1, 0x39, 0x29,
-	0xc6, 0xdb, 0xda, 0x90, 0xb5, 0x4d, 0x22, 0x4c, 0x4e, 0xd8, 0xe4, 0xad, 0x90, 0xaf, 0x04, 0xa1,
-	0x48, 0x8d, 0x1a, 0xd3, 0x33, 0xa0, 0xcf, 0xdd, 0xa5, 0x27, 0x97, 0x2b, 0x88, 0xce, 0xaf, 0xda,
-	0xe4, 0xff, 0x62, 0x4d, 0xc2, 0xcf, 0xe3, 0x8a, 0xe7, 0x37, 0x18, 0xcf, 0x19, 0x14, 0xfc, 0x97,
-	0x38, 0x34, 0x27, 0x19, 0x3e, 0x9b, 0x98, 0x0d, 0xe5, 0x72, 0x38, 0xc6, 0x46, 0x44, 0x28, 0xc6,
-	0xf3, 0xcd, 0x7c, 0xca, 0x1e, 0xc0, 0xfc, 0x70, 0x80, 0xc9, 0xe5, 0x71, 0x9c, 0x39, 0x8c, 0x60,
-	0x27, 0x3a, 0x22, 0xb4, 0x59, 0x6b, 0xbf, 0xb2, 0x07, 0xdc, 0x16, 0xbe, 0x21, 0xcb, 0x7c, 0xa3,
-	0x20, 0x61, 0xdd, 0x59, 0x94, 0x86, 0xd4, 0x69, 0xfb, 0x51, 0xbb, 0xd3, 0x6b, 0x67, 0x96, 0x50,
-	0x01, 0x36, 0x6b, 0x9d, 0xd3, 0x36, 0xdf, 0xe0, 0x84, 0x5e, 0x8b, 0x3f, 0x12, 0x8e, 0x1b, 0x3c,
-	0x5b, 0x67, 0x79, 0xb6, 0x9b, 0xa1, 0x10, 0x0d, 0x85, 0x2a, 0xcb, 0xd7, 0x8e, 0x04, 0xbe, 0x75,
-	0x1c, 0xcd, 0x2f, 0xa3, 0x3c, 0x64, 0x9b, 0xec, 0x69, 0xb3, 0x11, 0xce, 0xac, 0x20, 0x06, 0xe8,
-	0xc3, 0x0e, 0xd7, 0x63, 0xb9, 0x7a, 0xa3, 0x6e, 0x25, 0xb8, 0x56, 0x2d, 0x58, 0x94, 0x49, 0x58,
-	0x74, 0x8b, 0x1b, 0x93, 0x5f, 0x45, 0xdb, 0x50, 0x8c, 0xcf, 0x77, 0x33, 0x49, 0xf4, 0x2f, 0xec,
-	0x44, 0x0b, 0xba, 0x7c, 0x87, 0x63, 0x9b, 0x0d, 0xe1, 0xa4, 0xf3, 0xb8, 0x55, 0x7b, 0x92, 0x49,
-	0x55, 0x5b, 0x57, 0xd7, 0x34, 0xf5, 0xf1, 0x9a, 0xa6, 0x3e, 0x5f, 0xd3, 0xd4, 0xdb, 0x2f, 0xf4,
-	0xd2, 0xd3, 0xfd, 0x39, 0xff, 0x08, 0x49, 0x49, 0xfb, 0xbd, 0xf2, 0x3d, 0x00, 0x00, 0xff, 0xff,
-	0x34, 0x3f, 0x09, 0x4f, 0x12, 0x0a, 0x00, 0x00,
+	// 1044 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa5, 0x56, 0x5d, 0x6f, 0xe3, 0x44,
+	0x14, 0xad, 0xdb, 0xb4, 0x49, 0x6e, 0xd2, 0xdd, 0xe0, 0x86, 0xd6, 0x4d, 0xaa, 0x6c, 0x37, 0x7c,
+	0x08, 0x84, 0x48, 0x44, 0x2b, 0xb1, 0x42, 0x08, 0x24, 0x37, 0x49, 0x93, 0x68, 0x69, 0x52, 0x4d,
+	0x5c, 0x2a, 0xfa, 0x80, 0xe5, 0xd8, 0xae, 0x63, 0x48, 0xec, 0xc8, 0x76, 0x28, 0x7d, 0xe3, 0x11,
+	0x5e, 0xd0, 0x4a, 0x88, 0x1f, 0x80, 0x04, 0x3f, 0x83, 0xf7, 0x7d, 0xe4, 0x17, 0x20, 0x04, 0x7f,
+	0x84, 0xf1, 0x8c, 0x1d, 0x7f, 0xc4, 0x5e, 0x41, 0xf3, 0x90, 0xc8, 0xbe, 0xf7, 0xdc, 0x73, 0x8f,
+	0xef, 0xcc, 0x1c, 0x1b, 0xba, 0x9a, 0xee, 0x4c, 0x16, 0xe3, 0x86, 0x6c, 0xce, 0x9a, 0xb3, 0x53,
+	0x65, 0x8c, 0xff, 0x9a, 0xb6, 0x25, 0x37, 0x67, 0xaa, 0x63, 0xe9, 0xb2, 0xdd, 0xd4, 0x54, 0x43,
+	0xb5, 0x24, 0x47, 0x55, 0x9a, 0x73, 0xcb, 0x74, 0x4c, 0x2f, 0x3e, 0x1f, 0x37, 0x71, 0xc1, 0xdc,
+	0xb4, 0x75, 0x47, 0x6d, 0x90, 0x04, 0x9b, 0xf3, 0x33, 0x95, 0xf7, 0x43, 0x94, 0x9a, 0xa9, 0x99,
+	0xb4, 0x72, 0xbc, 0xb8, 0x25, 0x77, 0x94, 0xc6, 0xbd, 0xa2, 0x85, 0x95, 0xf6, 0x43, 0x15, 0xd0,
+	0x0b, 0x8f, 0xe5, 0x7c, 0x0d, 0x16, 0x49, 0x91, 0x1c, 0xe9, 0x81, 0x6a, 0xe6, 0xe6, 0x54, 0x97,
+	0xef, 0x31, 0x0f, 0xbd, 0xa0, 0x2c, 0xf5, 0xef, 0x19, 0x28, 0xb7, 0xcc, 0x85, 0xe1, 0xa8, 0xd6,
+	0x35, 0xe6, 0xbb, 0xf0, 0x7a, 0xd8, 0xec, 0x07, 0x90, 0x95, 0x69, 0x9c, 0x63, 0x8e, 0x99, 0x77,
+	0x0a, 0x27, 0xaf, 0x35, 0x7c, 0x25, 0x0d, 0xaf, 0xe0, 0x2c, 0xf3, 0xf2, 0xcf, 0x27, 0x1b, 0xc8,
+	0xc7, 0xb1, 0x9f, 0x40, 0xde, 0xd7, 0x68, 0x73, 0x9b, 0xa4, 0xe8, 0x30, 0x28, 0x1a, 0x39, 0x92,
+	0xa6, 0x2a, 0xcb, 0x06, 0x5e, 0x71, 0x50, 0x51, 0xff, 0x99, 0x81, 0x83, 0x33, 0xc9, 0x91, 0x27,
+	0x82, 0x3e, 0x8b, 0xab, 0xf9, 0x18, 0x0a, 0x63, 0x37, 0x25, 0x3a, 0x6e, 0xce, 0x53, 0x54, 0x0e,
+	0xc8, 0x83, 0x3a, 0x8f, 0x17, 0xc6, 0xcb, 0xc8, 0xba, 0xba, 0xbe, 0x63, 0x80, 0xed, 0x4a, 0x0b,
+	0x4d, 0x8d, 0x4a, 0x7a, 0x0f, 0xb6, 0x35, 0x37, 0xea, 0x89, 0x79, 0x1c, 0x30, 0x12, 0xb0, 0xc7,
+	0x43, 0x31, 0xeb, 0x4a, 0xf8, 0x89, 0x81, 0xea, 0xb9, 0x69, 0xdd, 0x49, 0x96, 0x42, 0x70, 0xb8,
+	0x2c, 0x2c, 0x86, 0x7d, 0x06, 0x3b, 0x94, 0xcc, 0x13, 0x13, 0xe2, 0x8e, 0x95, 0x79, 0xdc, 0x1e,
+	0x1c, 0xcf, 0x35, 0xe7, 0x77, 0x59, 0x95, 0xe5, 0x95, 0xfa, 0x5d, 0xbc, 0xd2, 0x65, 0x41, 0xfd,
+	0x07, 0xbc, 0x60, 0xee, 0x84, 0x93, 0x14, 0x9d, 0xc6, 0x14, 0xbd, 0x1e, 0xd0, 0x86, 0x4a, 0x62,
+	0x6a, 0x3e, 0x5a, 0x51, 0x73, 0xb0, 0x5a, 0x96, 0xac, 0xe5, 0x47, 0x06, 0xb8, 0x14, 0x2d, 0xf6,
+	0xc3, 0xc4, 0xac, 0xb9, 0x64, 0xbf, 0x32, 0x70, 0x14, 0x13, 0x34, 0x72, 0x4c, 0x0b, 0x57, 0x5d,
+	0x92, 0xf3, 0xc7, 0x7e, 0x0a, 0x45, 0x77, 0x33, 0x2b, 0xe2, 0x7f, 0x97, 0x56, 0x70, 0x82, 0x10,
+	0xdb, 0x86, 0x47, 0x36, 0x25, 0x14, 0xe9, 0x89, 0x5e, 0x8e, 0xcc, 0x3f, 0xe9, 0x8d, 0x48, 0x43,
+	0x8f, 0x63, 0xd7, 0x0e, 0x07, 0xeb, 0xbf, 0x6f, 0x42, 0x89, 0xd7, 0x34, 0x4b, 0xd5, 0x5c, 0xab,
+	0x58, 0x52, 0x47, 0xe7, 0xf5, 0x76, 0xa2, 0xa8, 0x95, 0x47, 0x8a, 0x0d, 0xf0, 0x29, 0x14, 0x55,
+	0x43, 0x36, 0x15, 0x55, 0x34, 0x24, 0xc3, 0xa4, 0x33, 0xdc, 0x42, 0x05, 0x1a, 0x1b, 0xb8, 0x21,
+	0x3c, 0x83, 0xaa, 0x62, 0xde, 0x19, 0x9a, 0x25, 0xe1, 0x0d, 0x2a, 0x5a, 0xaa, 0x6d, 0x4e, 0x17,
+	0x8e, 0x6e, 0x1a, 0x5e, 0xc5, 0x16, 0xa9, 0x38, 0x0c, 0x20, 0x68, 0x89, 0xa0, 0xf5, 0x87, 0x90,
+	0xb3, 0xe7, 0x53, 0xdd, 0x11, 0x75, 0x85, 0xcb, 0x60, 0x70, 0x06, 0x65, 0xc9, 0x7d, 0x5f, 0x61,
+	0x9f, 0x40, 0xc1, 0x4b, 0x19, 0x8a, 0xfa, 0x2d, 0xb7, 0x8d, 0xb3, 0xdb, 0x08, 0x68, 0xd6, 0x8d,
+	0x04, 0x00, 0x62, 0x5f, 0xdc, 0x4e, 0x08, 0x40, 0xfc, 0x8d, 0x7d, 0x03, 0x76, 0x29, 0x60, 0x2e,
+	0xdd, 0x4f, 0x4d, 0x49, 0xe1, 0xb2, 0x18, 0x52, 0x44, 0x45, 0x12, 0xbc, 0xa4, 0xb1, 0xfa, 0x6f,
+	0x79, 0xd8, 0x4b, 0xda, 0x72, 0x1f, 0x42, 0xc6, 0xb9, 0x9f, 0x53, 0x73, 0x78, 0x74, 0x52, 0x0f,
+	0x06, 0x98, 0x00, 0x6e, 0x08, 0x18, 0x89, 0x08, 0x9e, 0x15, 0x60, 0xdf, 0xb3, 0x53, 0xf1, 0x0e,
+	0x63, 0xc4, 0xf8, 0x16, 0xac, 0xad, 0xb8, 0x70, 0x84, 0x0a, 0x95, 0xe5, 0x24, 0x33, 0xff, 0x12,
+	0x2a, 0x21, 0xfb, 0x8c, 0x33, 0x6f, 0x11, 0xe6, 0xa7, 0x49, 0x6e, 0x1a, 0x25, 0x3f, 0x18, 0xa7,
+	0xd8, 0xf3, 0x00, 0xca, 0xc4, 0xe7, 0xe2, 0xcc, 0x19, 0xc2, 0x7c, 0x14, 0xb3, 0xc6, 0x28, 0x29,
+	0xab, 0xad, 0x7a, 0xeb, 0x57, 0x50, 0xbb, 0xf5, 0x7d, 0xcb, 0x3b, 0x1f, 0x51, 0x6a, 0xb2, 0x9e,
+	0x85, 0x93, 0xb7, 0x52, 0x7d, 0x2e, 0xcc, 0x87, 0xaa, 0xb7, 0xaf, 0xf0, 0x4e, 0x3c, 0x9b, 0xf0,
+	0x39, 0x8c, 0xf5, 0xd9, 0x89, 0xcf, 0x26, 0xc5, 0x64, 0xd0, 0x81, 0x93, 0xe2, 0x84, 0x12, 0x54,
+	0xd3, 0xf9, 0x6d, 0xb2, 0xa9, 0x0a, 0xe1, 0x0d, 0x92, 0xe6, 0x62, 0x88, 0x73, 0xd2, 0xfc, 0xcd,
+	0x80, 0xe3, 0xd5, 0x16, 0x31, 0x73, 0xc8, 0xfd, 0x9f, 0x93, 0x8c, 0x8e, 0x9c, 0x57, 0x59, 0xd7,
+	0x0d, 0x70, 0x13, 0x1d, 0xf3, 0xe3, 0x43, 0x39, 0x8b, 0x3f, 0x4f, 0x9e, 0xf4, 0x39, 0x0e, 0xfa,
+	0xf4, 0x7c, 0x64, 0xf4, 0x69, 0xf6, 0x27, 0x89, 0x71, 0xfc, 0xdd, 0x91, 0x9f, 0x48, 0x86, 0x62,
+	0x4f, 0xa4, 0xaf, 0x55, 0x0e, 0x08, 0xd9, 0x5e, 0x88, 0xcc, 0x4f, 0xa1, 0x00, 0x55, 0xff, 0x65,
+	0x13, 0x32, 0xee, 0x11, 0x62, 0x0b, 0x90, 0xbd, 0x1a, 0x3c, 0x1f, 0x0c, 0xaf, 0x07, 0xa5, 0x0d,
+	0xb6, 0x02, 0xfb, 0xad, 0xe1, 0xd5, 0x40, 0xe8, 0x20, 0xf1, 0xba, 0x2f, 0xf4, 0xc4, 0x8b, 0x8e,
+	0xc0, 0xb7, 0x79, 0x81, 0x1f, 0x95, 0x18, 0xb6, 0x06, 0x95, 0x33, 0x5e, 0x68, 0xf5, 0x44, 0xa1,
+	0x7f, 0xb1, 0x9a, 0xdf, 0x64, 0x39, 0x28, 0x77, 0xf9, 0xab, 0x6e, 0x27, 0x9e, 0xd9, 0x62, 0xeb,
+	0x50, 0x3b, 0x1f, 0xa2, 0x6b, 0x1e, 0xb5, 0x3b, 0x6d, 0x37, 0x81, 0xfa, 0xad, 0x28, 0xa8, 0x94,
+	0x71, 0xd9, 0x5d, 0xde, 0x94, 0xfc, 0x36, 0x76, 0x9e, 0x6a, 0x7a, 0x7e, 0x54, 0xda, 0x61, 0xdf,
+	0x84, 0xe3, 0x55, 0xc0, 0x48, 0x18, 0x22, 0x1e, 0x4b, 0xba, 0x1c, 0x7e, 0xd6, 0x6f, 0x7d, 0x51,
+	0xca, 0xb2, 0x47, 0xc0, 0xf5, 0xfa, 0x38, 0xda, 0x45, 0xfc, 0x45, 0x9c, 0x23, 0xc7, 0xee, 0x42,
+	0xbe, 0xc7, 0x0f, 0xda, 0xa3, 0x1e, 0xff, 0xbc, 0x53, 0xca, 0xd7, 0x5f, 0x30, 0xb0, 0x9f, 0xbc,
+	0x12, 0xf8, 0xe3, 0x21, 0xbf, 0x5c, 0x0b, 0xcf, 0xf0, 0xf7, 0x12, 0x96, 0xcf, 0x7f, 0xc5, 0x2d,
+	0xb1, 0xeb, 0xbe, 0x21, 0x6f, 0xb0, 0x42, 0x7f, 0x0d, 0xd9, 0x77, 0xa1, 0x44, 0x3e, 0x48, 0x65,
+	0x73, 0x2a, 0x7e, 0xa3, 0x5a, 0x36, 0xb6, 0x78, 0xa2, 0x65, 0x17, 0x3d, 0xf6, 0xe3, 0x9f, 0xd3,
+	0x30, 0x5e, 0x82, 0xa2, 0x2c, 0xcd, 0xa5, 0xb1, 0x8e, 0x6d, 0x58, 0x57, 0x69, 0xe7, 0x0c, 0x8a,
+	0xc4, 0xce, 0xfa, 0x2f, 0xff, 0xae, 0x31, 0x7f, 0xe0, 0xdf, 0x5f, 0xf8, 0xf7, 0xe2, 0x9f, 0xda,
+	0xc6, 0xcd, 0xb3, 0x07, 0x7e, 0x76, 0x8f, 0x77, 0xc8, 0xfd, 0xe9, 0xbf, 0xfa, 0x0d, 0xa0, 0x12,
+	0x80, 0x0c, 0x00, 0x00,
 }
@@ -26,6 +26,7 @@ package metricpb
 import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import _ "github.com/gogo/protobuf/gogoproto"
 
 import binary "encoding/binary"
 
@@ -39,10 +40,11 @@ var _ = math.Inf
 type MetricType int32
 
 const (
-	MetricType_UNKNOWN MetricType = 0
-	MetricType_COUNTER MetricType = 1
-	MetricType_TIMER   MetricType = 2
-	MetricType_GAUGE   MetricType = 3
+	MetricType_UNKNOWN   MetricType = 0
+	MetricType_COUNTER   MetricType = 1
+	MetricType_TIMER     MetricType = 2
+	MetricType_GAUGE     MetricType = 3
+	MetricType_HISTOGRAM MetricType = 4
 )
 
 var MetricType_name = map[int32]string{
@@ -50,12 +52,14 @@ var MetricType_name = map[int32]string{
 	1: "COUNTER",
 	2: "TIMER",
 	3: "GAUGE",
+	4: "HISTOGRAM",
 }
 var MetricType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"COUNTER": 1,
-	"TIMER":   2,
-	"GAUGE":   3,
+	"UNKNOWN":   0,
+	"COUNTER":   1,
+	"TIMER":     2,
+	"GAUGE":     3,
+	"HISTOGRAM": 4,
 }
 
 func (x MetricType) String() string {
@@ -64,10 +68,11 @@ func (x MetricType) String() string {
 func (MetricType) EnumDescriptor() ([]byte, []int) { return fileDescriptorMetric, []int{0} }
 
 type Counter struct {
-	Id              []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Value           int64  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
-	Annotation      []byte `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
-	ClientTimeNanos int64  `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Id              []byte     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value           int64      `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Annotation      []byte     `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	ClientTimeNanos int64      `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Exemplars       []Exemplar `protobuf:"bytes,5,rep,name=exemplars" json:"exemplars"`
 }
 
 func (m *Counter) Reset()                    { *m = Counter{} }
@@ -103,11 +108,19 @@ func (m *Counter) GetClientTimeNanos() int64 {
 	return 0
 }
 
+func (m *Counter) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
 type BatchTimer struct {
-	Id              []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Values          []float64 `protobuf:"fixed64,2,rep,packed,name=values" json:"values,omitempty"`
-	Annotation      []byte    `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
-	ClientTimeNanos int64     `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Id              []byte     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Values          []float64  `protobuf:"fixed64,2,rep,packed,name=values" json:"values,omitempty"`
+	Annotation      []byte     `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	ClientTimeNanos int64      `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Exemplars       []Exemplar `protobuf:"bytes,5,rep,name=exemplars" json:"exemplars"`
 }
 
 func (m *BatchTimer) Reset()                    { *m = BatchTimer{} }
@@ -143,11 +156,19 @@ func (m *BatchTimer) GetClientTimeNanos() int64 {
 	return 0
 }
 
+func (m *BatchTimer) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
 type Gauge struct {
-	Id              []byte  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Value           float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
-	Annotation      []byte  `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
-	ClientTimeNanos int64   `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Id              []byte     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value           float64    `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Annotation      []byte     `protobuf:"bytes,3,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	ClientTimeNanos int64      `protobuf:"varint,4,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+	Exemplars       []Exemplar `protobuf:"bytes,5,rep,name=exemplars" json:"exemplars"`
 }
 
 func (m *Gauge) Reset()                    { *m = Gauge{} }
@@ -183,12 +204,20 @@ func (m *Gauge) GetClientTimeNanos() int64 {
 	return 0
 }
 
+func (m *Gauge) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
 type TimedMetric struct {
 	Type       MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=metricpb.MetricType" json:"type,omitempty"`
 	Id         []byte     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	TimeNanos  int64      `protobuf:"varint,3,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
 	Value      float64    `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
 	Annotation []byte     `protobuf:"bytes,5,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	Exemplars  []Exemplar `protobuf:"bytes,6,rep,name=exemplars" json:"exemplars"`
 }
 
 func (m *TimedMetric) Reset()                    { *m = TimedMetric{} }
@@ -231,16 +260,32 @@ func (m *TimedMetric) GetAnnotation() []byte {
 	return nil
 }
 
+func (m *TimedMetric) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
 type ForwardedMetric struct {
 	Type      MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=metricpb.MetricType" json:"type,omitempty"`
 	Id        []byte     `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	TimeNanos int64      `protobuf:"varint,3,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
 	// values and prev_values are the same length. a given index to the arrays
 	// gives the tuple (value, prev_value) for a given forwarded value.
-	Values     []float64 `protobuf:"fixed64,4,rep,packed,name=values" json:"values,omitempty"`
-	PrevValues []float64 `protobuf:"fixed64,6,rep,packed,name=prev_values,json=prevValues" json:"prev_values,omitempty"`
-	Annotation []byte    `protobuf:"bytes,5,opt,name=annotation,proto3" json:"annotation,omitempty"`
-	Version    uint32    `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	Values     []float64  `protobuf:"fixed64,4,rep,packed,name=values" json:"values,omitempty"`
+	PrevValues []float64  `protobuf:"fixed64,6,rep,packed,name=prev_values,json=prevValues" json:"prev_values,omitempty"`
+	Annotation []byte     `protobuf:"bytes,5,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	Version    uint32     `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	Exemplars  []Exemplar `protobuf:"bytes,8,rep,name=exemplars" json:"exemplars"`
+	// histogram_bucket_upper_bounds, histogram_bucket_counts and histogram_sum
+	// are set instead of values when forwarding an aggregated histogram.
+	HistogramBucketUpperBounds []float64 `protobuf:"fixed64,9,rep,packed,name=histogram_bucket_upper_bounds,json=histogramBucketUpperBounds" json:"histogram_bucket_upper_bounds,omitempty"`
+	HistogramBucketCounts      []int64   `protobuf:"varint,10,rep,packed,name=histogram_bucket_counts,json=histogramBucketCounts" json:"histogram_bucket_counts,omitempty"`
+	HistogramSum               float64   `protobuf:"fixed64,11,opt,name=histogram_sum,json=histogramSum,proto3" json:"histogram_sum,omitempty"`
+	// contributors is the encoded sketch of the distinct contributors of the
+	// series forwarded, set when the contributors of rollups are counted.
+	Contributors []byte `protobuf:"bytes,12,opt,name=contributors,proto3" json:"contributors,omitempty"`
 }
 
 func (m *ForwardedMetric) Reset()                    { *m = ForwardedMetric{} }
@@ -297,6 +342,41 @@ func (m *ForwardedMetric) GetVersion() uint32 {
 	return 0
 }
 
+func (m *ForwardedMetric) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
+func (m *ForwardedMetric) GetHistogramBucketUpperBounds() []float64 {
+	if m != nil {
+		return m.HistogramBucketUpperBounds
+	}
+	return nil
+}
+
+func (m *ForwardedMetric) GetHistogramBucketCounts() []int64 {
+	if m != nil {
+		return m.HistogramBucketCounts
+	}
+	return nil
+}
+
+func (m *ForwardedMetric) GetHistogramSum() float64 {
+	if m != nil {
+		return m.HistogramSum
+	}
+	return 0
+}
+
+func (m *ForwardedMetric) GetContributors() []byte {
+	if m != nil {
+		return m.Contributors
+	}
+	return nil
+}
+
 type Tag struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -321,6 +401,107 @@ func (m *Tag) GetValue() []byte {
 	return nil
 }
 
+// Histogram is a histogram of the values observed by a reporter, where
+// bucket_counts[i] is the number of values in the bucket with the inclusive
+// upper bound bucket_upper_bounds[i] and the bounds are in ascending order.
+type Histogram struct {
+	Id                []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BucketUpperBounds []float64 `protobuf:"fixed64,2,rep,packed,name=bucket_upper_bounds,json=bucketUpperBounds" json:"bucket_upper_bounds,omitempty"`
+	BucketCounts      []int64   `protobuf:"varint,3,rep,packed,name=bucket_counts,json=bucketCounts" json:"bucket_counts,omitempty"`
+	Sum               float64   `protobuf:"fixed64,4,opt,name=sum,proto3" json:"sum,omitempty"`
+	Annotation        []byte    `protobuf:"bytes,5,opt,name=annotation,proto3" json:"annotation,omitempty"`
+	ClientTimeNanos   int64     `protobuf:"varint,6,opt,name=client_time_nanos,json=clientTimeNanos,proto3" json:"client_time_nanos,omitempty"`
+}
+
+func (m *Histogram) Reset()                    { *m = Histogram{} }
+func (m *Histogram) String() string            { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()               {}
+func (*Histogram) Descriptor() ([]byte, []int) { return fileDescriptorMetric, []int{6} }
+
+func (m *Histogram) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Histogram) GetBucketUpperBounds() []float64 {
+	if m != nil {
+		return m.BucketUpperBounds
+	}
+	return nil
+}
+
+func (m *Histogram) GetBucketCounts() []int64 {
+	if m != nil {
+		return m.BucketCounts
+	}
+	return nil
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Histogram) GetAnnotation() []byte {
+	if m != nil {
+		return m.Annotation
+	}
+	return nil
+}
+
+func (m *Histogram) GetClientTimeNanos() int64 {
+	if m != nil {
+		return m.ClientTimeNanos
+	}
+	return 0
+}
+
+// Exemplar is a sample of an incoming value annotated with the trace it was
+// recorded in, retained through aggregation to link metrics to traces.
+type Exemplar struct {
+	TraceId   []byte  `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	SpanId    []byte  `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	Value     float64 `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	TimeNanos int64   `protobuf:"varint,4,opt,name=time_nanos,json=timeNanos,proto3" json:"time_nanos,omitempty"`
+}
+
+func (m *Exemplar) Reset()                    { *m = Exemplar{} }
+func (m *Exemplar) String() string            { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()               {}
+func (*Exemplar) Descriptor() ([]byte, []int) { return fileDescriptorMetric, []int{7} }
+
+func (m *Exemplar) GetTraceId() []byte {
+	if m != nil {
+		return m.TraceId
+	}
+	return nil
+}
+
+func (m *Exemplar) GetSpanId() []byte {
+	if m != nil {
+		return m.SpanId
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimeNanos() int64 {
+	if m != nil {
+		return m.TimeNanos
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Counter)(nil), "metricpb.Counter")
 	proto.RegisterType((*BatchTimer)(nil), "metricpb.BatchTimer")
@@ -328,6 +509,8 @@ func init() {
 	proto.RegisterType((*TimedMetric)(nil), "metricpb.TimedMetric")
 	proto.RegisterType((*ForwardedMetric)(nil), "metricpb.ForwardedMetric")
 	proto.RegisterType((*Tag)(nil), "metricpb.Tag")
+	proto.RegisterType((*Histogram)(nil), "metricpb.Histogram")
+	proto.RegisterType((*Exemplar)(nil), "metricpb.Exemplar")
 	proto.RegisterEnum("metricpb.MetricType", MetricType_name, MetricType_value)
 }
 func (m *Counter) Marshal() (dAtA []byte, err error) {
@@ -367,6 +550,18 @@ func (m *Counter) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMetric(dAtA, i, uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintMetric(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -412,6 +607,18 @@ func (m *BatchTimer) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMetric(dAtA, i, uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintMetric(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -453,6 +660,18 @@ func (m *Gauge) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMetric(dAtA, i, uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintMetric(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -499,6 +718,18 @@ func (m *TimedMetric) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMetric(dAtA, i, uint64(len(m.Annotation)))
 		i += copy(dAtA[i:], m.Annotation)
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintMetric(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -564,6 +795,58 @@ func (m *ForwardedMetric) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMetric(dAtA, i, uint64(m.Version))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0x42
+			i++
+			i = encodeVarintMetric(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.HistogramBucketUpperBounds) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.HistogramBucketUpperBounds)*8))
+		for _, num := range m.HistogramBucketUpperBounds {
+			f4 := math.Float64bits(float64(num))
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(f4))
+			i += 8
+		}
+	}
+	if len(m.HistogramBucketCounts) > 0 {
+		dAtA6 := make([]byte, len(m.HistogramBucketCounts)*10)
+		var j5 int
+		for _, num1 := range m.HistogramBucketCounts {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA6[j5] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j5++
+			}
+			dAtA6[j5] = uint8(num)
+			j5++
+		}
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(j5))
+		i += copy(dAtA[i:], dAtA6[:j5])
+	}
+	if m.HistogramSum != 0 {
+		dAtA[i] = 0x59
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.HistogramSum))))
+		i += 8
+	}
+	if len(m.Contributors) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.Contributors)))
+		i += copy(dAtA[i:], m.Contributors)
+	}
 	return i, nil
 }
 
@@ -597,6 +880,116 @@ func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Histogram) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Histogram) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.BucketUpperBounds) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.BucketUpperBounds)*8))
+		for _, num := range m.BucketUpperBounds {
+			f1 := math.Float64bits(float64(num))
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(f1))
+			i += 8
+		}
+	}
+	if len(m.BucketCounts) > 0 {
+		dAtA3 := make([]byte, len(m.BucketCounts)*10)
+		var j2 int
+		for _, num1 := range m.BucketCounts {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA3[j2] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j2++
+			}
+			dAtA3[j2] = uint8(num)
+			j2++
+		}
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(j2))
+		i += copy(dAtA[i:], dAtA3[:j2])
+	}
+	if m.Sum != 0 {
+		dAtA[i] = 0x21
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	if len(m.Annotation) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.Annotation)))
+		i += copy(dAtA[i:], m.Annotation)
+	}
+	if m.ClientTimeNanos != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(m.ClientTimeNanos))
+	}
+	return i, nil
+}
+
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TraceId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.TraceId)))
+		i += copy(dAtA[i:], m.TraceId)
+	}
+	if len(m.SpanId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(len(m.SpanId)))
+		i += copy(dAtA[i:], m.SpanId)
+	}
+	if m.Value != 0 {
+		dAtA[i] = 0x19
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if m.TimeNanos != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintMetric(dAtA, i, uint64(m.TimeNanos))
+	}
+	return i, nil
+}
+
 func encodeVarintMetric(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -623,6 +1016,12 @@ func (m *Counter) Size() (n int) {
 	if m.ClientTimeNanos != 0 {
 		n += 1 + sovMetric(uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovMetric(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -643,6 +1042,12 @@ func (m *BatchTimer) Size() (n int) {
 	if m.ClientTimeNanos != 0 {
 		n += 1 + sovMetric(uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovMetric(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -663,6 +1068,12 @@ func (m *Gauge) Size() (n int) {
 	if m.ClientTimeNanos != 0 {
 		n += 1 + sovMetric(uint64(m.ClientTimeNanos))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovMetric(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -686,6 +1097,12 @@ func (m *TimedMetric) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMetric(uint64(l))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovMetric(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -715,6 +1132,29 @@ func (m *ForwardedMetric) Size() (n int) {
 	if m.Version != 0 {
 		n += 1 + sovMetric(uint64(m.Version))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovMetric(uint64(l))
+		}
+	}
+	if len(m.HistogramBucketUpperBounds) > 0 {
+		n += 1 + sovMetric(uint64(len(m.HistogramBucketUpperBounds)*8)) + len(m.HistogramBucketUpperBounds)*8
+	}
+	if len(m.HistogramBucketCounts) > 0 {
+		l = 0
+		for _, e := range m.HistogramBucketCounts {
+			l += sovMetric(uint64(e))
+		}
+		n += 1 + sovMetric(uint64(l)) + l
+	}
+	if m.HistogramSum != 0 {
+		n += 9
+	}
+	l = len(m.Contributors)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
 	return n
 }
 
@@ -732,21 +1172,71 @@ func (m *Tag) Size() (n int) {
 	return n
 }
 
-func sovMetric(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *Histogram) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
+	if len(m.BucketUpperBounds) > 0 {
+		n += 1 + sovMetric(uint64(len(m.BucketUpperBounds)*8)) + len(m.BucketUpperBounds)*8
+	}
+	if len(m.BucketCounts) > 0 {
+		l = 0
+		for _, e := range m.BucketCounts {
+			l += sovMetric(uint64(e))
 		}
+		n += 1 + sovMetric(uint64(l)) + l
 	}
-	return n
-}
-func sozMetric(x uint64) (n int) {
-	return sovMetric(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (m *Counter) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
+	if m.Sum != 0 {
+		n += 9
+	}
+	l = len(m.Annotation)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
+	if m.ClientTimeNanos != 0 {
+		n += 1 + sovMetric(uint64(m.ClientTimeNanos))
+	}
+	return n
+}
+
+func (m *Exemplar) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
+	l = len(m.SpanId)
+	if l > 0 {
+		n += 1 + l + sovMetric(uint64(l))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.TimeNanos != 0 {
+		n += 1 + sovMetric(uint64(m.TimeNanos))
+	}
+	return n
+}
+
+func sovMetric(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozMetric(x uint64) (n int) {
+	return sovMetric(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Counter) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -874,6 +1364,37 @@ func (m *Counter) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetric(dAtA[iNdEx:])
@@ -1051,6 +1572,37 @@ func (m *BatchTimer) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetric(dAtA[iNdEx:])
@@ -1193,6 +1745,37 @@ func (m *Gauge) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetric(dAtA[iNdEx:])
@@ -1354,6 +1937,37 @@ func (m *TimedMetric) Unmarshal(dAtA []byte) error {
 				m.Annotation = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetric(dAtA[iNdEx:])
@@ -1615,61 +2229,11 @@ func (m *ForwardedMetric) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipMetric(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthMetric
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Tag) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowMetric
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Tag: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Tag: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMetric
@@ -1679,47 +2243,278 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMetric
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = append(m.Name[:0], dAtA[iNdEx:postIndex]...)
-			if m.Name == nil {
-				m.Name = []byte{}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMetric
+		case 9:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
 				}
-				if iNdEx >= l {
+				v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.HistogramBucketUpperBounds = append(m.HistogramBucketUpperBounds, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.HistogramBucketUpperBounds = append(m.HistogramBucketUpperBounds, v2)
 				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramBucketUpperBounds", wireType)
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthMetric
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+		case 10:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (int64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.HistogramBucketCounts = append(m.HistogramBucketCounts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMetric
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (int64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.HistogramBucketCounts = append(m.HistogramBucketCounts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramBucketCounts", wireType)
+			}
+		case 11:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HistogramSum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.HistogramSum = float64(math.Float64frombits(v))
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Contributors", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Contributors = append(m.Contributors[:0], dAtA[iNdEx:postIndex]...)
+			if m.Contributors == nil {
+				m.Contributors = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetric(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetric
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Tag) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetric
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tag: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tag: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = append(m.Name[:0], dAtA[iNdEx:postIndex]...)
+			if m.Name == nil {
+				m.Name = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
@@ -1748,6 +2543,398 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Histogram) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetric
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Histogram: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Histogram: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.BucketUpperBounds = append(m.BucketUpperBounds, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.BucketUpperBounds = append(m.BucketUpperBounds, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field BucketUpperBounds", wireType)
+			}
+		case 3:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (int64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.BucketCounts = append(m.BucketCounts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMetric
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMetric
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMetric
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (int64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.BucketCounts = append(m.BucketCounts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field BucketCounts", wireType)
+			}
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Annotation", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Annotation = append(m.Annotation[:0], dAtA[iNdEx:postIndex]...)
+			if m.Annotation == nil {
+				m.Annotation = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientTimeNanos", wireType)
+			}
+			m.ClientTimeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClientTimeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetric(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetric
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetric
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = append(m.TraceId[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceId == nil {
+				m.TraceId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMetric
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanId = append(m.SpanId[:0], dAtA[iNdEx:postIndex]...)
+			if m.SpanId == nil {
+				m.SpanId = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeNanos", wireType)
+			}
+			m.TimeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetric
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetric(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetric
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipMetric(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1858,33 +3045,50 @@ func init() {
 }
 
 var fileDescriptorMetric = []byte{
-	// 444 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
-	0x14, 0xec, 0xda, 0x4e, 0x42, 0x5f, 0x4a, 0x1b, 0x56, 0x15, 0xf2, 0x05, 0x13, 0xe5, 0x64, 0xf5,
-	0x60, 0x4b, 0xe4, 0xc0, 0x85, 0x0b, 0x2d, 0x21, 0xaa, 0x50, 0x5d, 0xc9, 0x72, 0x40, 0xe2, 0x12,
-	0xad, 0xed, 0x95, 0xbb, 0x52, 0xbd, 0x6b, 0xad, 0xd7, 0x41, 0x11, 0x17, 0x3e, 0x81, 0x0f, 0xe0,
-	0x83, 0x38, 0xf2, 0x03, 0x48, 0x28, 0xfc, 0x08, 0xf2, 0xda, 0x26, 0x85, 0x8a, 0x1e, 0x50, 0x73,
-	0x7b, 0x33, 0xbb, 0x6f, 0x67, 0xc6, 0x23, 0xc3, 0xab, 0x8c, 0xa9, 0xab, 0x2a, 0xf6, 0x12, 0x91,
-	0xfb, 0xf9, 0x34, 0x8d, 0xfd, 0x7c, 0xea, 0x97, 0x32, 0xf1, 0x73, 0xaa, 0x24, 0x4b, 0x4a, 0x3f,
-	0xa3, 0x9c, 0x4a, 0xa2, 0x68, 0xea, 0x17, 0x52, 0x28, 0xd1, 0xf2, 0x45, 0xdc, 0x0e, 0x9e, 0x66,
-	0xf1, 0x83, 0x8e, 0x9e, 0x7c, 0x84, 0xc1, 0x99, 0xa8, 0xb8, 0xa2, 0x12, 0x1f, 0x82, 0xc1, 0x52,
-	0x1b, 0x8d, 0x91, 0x7b, 0x10, 0x1a, 0x2c, 0xc5, 0xc7, 0xd0, 0x5b, 0x91, 0xeb, 0x8a, 0xda, 0xc6,
-	0x18, 0xb9, 0x66, 0xd8, 0x00, 0xec, 0x00, 0x10, 0xce, 0x85, 0x22, 0x8a, 0x09, 0x6e, 0x9b, 0xfa,
-	0xf6, 0x0d, 0x06, 0x9f, 0xc0, 0xa3, 0xe4, 0x9a, 0x51, 0xae, 0x96, 0x8a, 0xe5, 0x74, 0xc9, 0x09,
-	0x17, 0xa5, 0x6d, 0xe9, 0x17, 0x8e, 0x9a, 0x83, 0x88, 0xe5, 0x34, 0xa8, 0xe9, 0xc9, 0x27, 0x04,
-	0x70, 0x4a, 0x54, 0x72, 0x55, 0x53, 0xb7, 0x0d, 0x3c, 0x86, 0xbe, 0xd6, 0x2c, 0x6d, 0x63, 0x6c,
-	0xba, 0x28, 0x6c, 0xd1, 0xbd, 0x5a, 0x58, 0x43, 0x6f, 0x4e, 0xaa, 0x8c, 0xde, 0x9d, 0x1e, 0xed,
-	0x22, 0xfd, 0x17, 0x04, 0xc3, 0x1a, 0xa5, 0x17, 0xba, 0x0c, 0xec, 0x82, 0xa5, 0xd6, 0x05, 0xd5,
-	0x1e, 0x0e, 0x9f, 0x1d, 0x7b, 0x5d, 0x47, 0x5e, 0x73, 0x1e, 0xad, 0x0b, 0x1a, 0xea, 0x1b, 0xad,
-	0x57, 0xe3, 0xb7, 0xd7, 0x27, 0x00, 0x37, 0xe4, 0x4c, 0x2d, 0xb7, 0xaf, 0x3a, 0xa1, 0x6d, 0x14,
-	0xeb, 0xdf, 0x51, 0x7a, 0x7f, 0x47, 0x99, 0x7c, 0x47, 0x70, 0xf4, 0x5a, 0xc8, 0x0f, 0x44, 0xa6,
-	0xbb, 0xb7, 0xb8, 0xad, 0xda, 0xba, 0xa3, 0xea, 0x5b, 0x26, 0xf1, 0x53, 0x18, 0x16, 0x92, 0xae,
-	0x96, 0xed, 0x72, 0x5f, 0x2f, 0x43, 0x4d, 0xbd, 0x6d, 0x1e, 0xb0, 0x61, 0xb0, 0xa2, 0xb2, 0xac,
-	0xb7, 0x07, 0x63, 0xe4, 0x3e, 0x0c, 0x3b, 0x38, 0xf1, 0xc1, 0x8c, 0x48, 0x86, 0x31, 0x58, 0x9c,
-	0xe4, 0xb4, 0x6d, 0x5e, 0xcf, 0x7f, 0x76, 0x7f, 0xd0, 0x7e, 0xb0, 0x93, 0x17, 0x00, 0xdb, 0x98,
-	0x78, 0x08, 0x83, 0x45, 0xf0, 0x26, 0xb8, 0x7c, 0x17, 0x8c, 0xf6, 0x6a, 0x70, 0x76, 0xb9, 0x08,
-	0xa2, 0x59, 0x38, 0x42, 0x78, 0x1f, 0x7a, 0xd1, 0xf9, 0xc5, 0x2c, 0x1c, 0x19, 0xf5, 0x38, 0x7f,
-	0xb9, 0x98, 0xcf, 0x46, 0xe6, 0xe9, 0xf9, 0xd7, 0x8d, 0x83, 0xbe, 0x6d, 0x1c, 0xf4, 0x63, 0xe3,
-	0xa0, 0xcf, 0x3f, 0x9d, 0xbd, 0xf7, 0xcf, 0xff, 0xf3, 0x57, 0x8e, 0xfb, 0x1a, 0x4f, 0x7f, 0x05,
-	0x00, 0x00, 0xff, 0xff, 0x42, 0xaf, 0x37, 0x39, 0x0c, 0x04, 0x00, 0x00,
+	// 712 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0x4f, 0x4f, 0xdb, 0x4a,
+	0x10, 0x67, 0x63, 0xe7, 0xdf, 0x24, 0x81, 0xb0, 0x8f, 0xf7, 0xf0, 0x43, 0x22, 0x44, 0xe1, 0x12,
+	0x21, 0xbd, 0x44, 0x02, 0x89, 0x77, 0x26, 0x34, 0x0d, 0x11, 0x22, 0x48, 0x26, 0x69, 0xa5, 0x5e,
+	0x2c, 0xff, 0xd9, 0x06, 0xab, 0xd8, 0x6b, 0xed, 0xae, 0x69, 0xf9, 0x16, 0xfd, 0x2a, 0x55, 0x2f,
+	0xfd, 0x08, 0x9c, 0xaa, 0x1e, 0x7a, 0xe9, 0xa5, 0xaa, 0xe8, 0x17, 0xa9, 0xbc, 0x76, 0x9c, 0x84,
+	0x50, 0x8a, 0x2a, 0x55, 0xe2, 0x36, 0x33, 0x3b, 0x33, 0x99, 0xdf, 0x2f, 0xbf, 0x19, 0xc3, 0x93,
+	0xb1, 0x2b, 0xce, 0x43, 0xab, 0x65, 0x53, 0xaf, 0xed, 0xed, 0x39, 0x56, 0xdb, 0xdb, 0x6b, 0x73,
+	0x66, 0xb7, 0x3d, 0x22, 0x98, 0x6b, 0xf3, 0xf6, 0x98, 0xf8, 0x84, 0x99, 0x82, 0x38, 0xed, 0x80,
+	0x51, 0x41, 0x93, 0x78, 0x60, 0x25, 0x46, 0x4b, 0x46, 0x71, 0x61, 0x12, 0xde, 0xf8, 0x6f, 0xa6,
+	0xdf, 0x98, 0x8e, 0x69, 0x5c, 0x66, 0x85, 0x2f, 0xa5, 0x17, 0xf7, 0x88, 0xac, 0xb8, 0xb0, 0xf1,
+	0x1e, 0x41, 0xfe, 0x90, 0x86, 0xbe, 0x20, 0x0c, 0x2f, 0x43, 0xc6, 0x75, 0x34, 0x54, 0x47, 0xcd,
+	0xb2, 0x9e, 0x71, 0x1d, 0xbc, 0x06, 0xd9, 0x4b, 0xf3, 0x22, 0x24, 0x5a, 0xa6, 0x8e, 0x9a, 0x8a,
+	0x1e, 0x3b, 0xb8, 0x06, 0x60, 0xfa, 0x3e, 0x15, 0xa6, 0x70, 0xa9, 0xaf, 0x29, 0x32, 0x7b, 0x26,
+	0x82, 0x77, 0x60, 0xd5, 0xbe, 0x70, 0x89, 0x2f, 0x0c, 0xe1, 0x7a, 0xc4, 0xf0, 0x4d, 0x9f, 0x72,
+	0x4d, 0x95, 0x1d, 0x56, 0xe2, 0x87, 0xa1, 0xeb, 0x91, 0x41, 0x14, 0xc6, 0xfb, 0x50, 0x24, 0x6f,
+	0x88, 0x17, 0x5c, 0x98, 0x8c, 0x6b, 0xd9, 0xba, 0xd2, 0x2c, 0xed, 0xe2, 0xd6, 0x04, 0x4a, 0xab,
+	0x9b, 0x3c, 0x75, 0xd4, 0xeb, 0xaf, 0x5b, 0x4b, 0xfa, 0x34, 0xb5, 0xf1, 0x01, 0x01, 0x74, 0x4c,
+	0x61, 0x9f, 0x47, 0xad, 0x16, 0x07, 0xff, 0x07, 0x72, 0x72, 0x56, 0xae, 0x65, 0xea, 0x4a, 0x13,
+	0xe9, 0x89, 0xf7, 0x28, 0x46, 0x7f, 0x87, 0x20, 0xdb, 0x33, 0xc3, 0x31, 0xb9, 0x9f, 0x6e, 0xf4,
+	0x98, 0xe8, 0xfe, 0x8c, 0xa0, 0x14, 0x75, 0x71, 0x4e, 0x64, 0x2e, 0x6e, 0x82, 0x2a, 0xae, 0x02,
+	0x22, 0x67, 0x5f, 0xde, 0x5d, 0x9b, 0xb6, 0x88, 0xdf, 0x87, 0x57, 0x01, 0xd1, 0x65, 0x46, 0x82,
+	0x31, 0x93, 0x62, 0xdc, 0x04, 0x98, 0x19, 0x53, 0x91, 0x63, 0x16, 0x45, 0x3a, 0x60, 0x4a, 0x81,
+	0xfa, 0x73, 0x0a, 0xb2, 0x0b, 0x14, 0xcc, 0xc1, 0xca, 0x3d, 0x1c, 0xd6, 0x17, 0x05, 0x56, 0x9e,
+	0x52, 0xf6, 0xda, 0x64, 0xce, 0x9f, 0x87, 0x36, 0xd5, 0xa4, 0x7a, 0x8f, 0x26, 0x17, 0xc1, 0x6d,
+	0x41, 0x29, 0x60, 0xe4, 0xd2, 0x48, 0x8a, 0x73, 0xb2, 0x18, 0xa2, 0xd0, 0xb3, 0xb8, 0x81, 0x06,
+	0xf9, 0x4b, 0xc2, 0x78, 0x54, 0x9d, 0xaf, 0xa3, 0x66, 0x45, 0x9f, 0xb8, 0xf3, 0xbc, 0x14, 0x1e,
+	0xcc, 0x0b, 0x3e, 0x80, 0xcd, 0x73, 0x97, 0x0b, 0x3a, 0x66, 0xa6, 0x67, 0x58, 0xa1, 0xfd, 0x8a,
+	0x08, 0x23, 0x0c, 0x02, 0xc2, 0x0c, 0x8b, 0x86, 0xbe, 0xc3, 0xb5, 0xa2, 0x1c, 0x62, 0x23, 0x4d,
+	0xea, 0xc8, 0x9c, 0x51, 0x94, 0xd2, 0x91, 0x19, 0x78, 0x1f, 0xd6, 0x17, 0x5a, 0xd8, 0xd1, 0x99,
+	0xe1, 0x1a, 0xd4, 0x95, 0xa6, 0xa2, 0xff, 0x7d, 0xab, 0x58, 0xde, 0x20, 0x8e, 0xb7, 0xa1, 0x32,
+	0xad, 0xe3, 0xa1, 0xa7, 0x95, 0xa4, 0x10, 0xca, 0x69, 0xf0, 0x2c, 0xf4, 0x70, 0x03, 0xca, 0x36,
+	0xf5, 0x05, 0x73, 0xad, 0x50, 0x50, 0xc6, 0xb5, 0xb2, 0x24, 0x6d, 0x2e, 0xd6, 0x68, 0x83, 0x32,
+	0x34, 0xc7, 0x18, 0x83, 0xea, 0x9b, 0x1e, 0x49, 0xb6, 0x4c, 0xda, 0xf3, 0x7b, 0x56, 0x4e, 0x44,
+	0xd6, 0xf8, 0x88, 0xa0, 0x78, 0x34, 0xf9, 0x95, 0x85, 0xdd, 0x6c, 0xc1, 0x5f, 0x77, 0x11, 0x11,
+	0x9f, 0x97, 0x55, 0x6b, 0x01, 0xff, 0x36, 0x54, 0xe6, 0x51, 0x2b, 0x12, 0x75, 0xd9, 0x9a, 0x05,
+	0x5b, 0x05, 0x25, 0x82, 0x18, 0x6b, 0x3d, 0x32, 0x7f, 0x29, 0x86, 0x3b, 0x97, 0x3d, 0x77, 0xe7,
+	0xb2, 0x37, 0x38, 0x14, 0x26, 0x7f, 0x31, 0xfe, 0x17, 0x0a, 0x82, 0x99, 0x36, 0x31, 0x52, 0x50,
+	0x79, 0xe9, 0xf7, 0x1d, 0xbc, 0x0e, 0x79, 0x1e, 0x98, 0xbe, 0x91, 0x6a, 0x39, 0x17, 0xb9, 0xfd,
+	0x99, 0x73, 0xa4, 0xcc, 0xee, 0xe2, 0xbc, 0xca, 0xd5, 0x5b, 0x2a, 0xdf, 0x39, 0x06, 0x98, 0x2e,
+	0x0a, 0x2e, 0x41, 0x7e, 0x34, 0x38, 0x1e, 0x9c, 0x3e, 0x1f, 0x54, 0x97, 0x22, 0xe7, 0xf0, 0x74,
+	0x34, 0x18, 0x76, 0xf5, 0x2a, 0xc2, 0x45, 0xc8, 0x0e, 0xfb, 0x27, 0x5d, 0xbd, 0x9a, 0x89, 0xcc,
+	0xde, 0xc1, 0xa8, 0xd7, 0xad, 0x2a, 0xb8, 0x02, 0xc5, 0xa3, 0xfe, 0xd9, 0xf0, 0xb4, 0xa7, 0x1f,
+	0x9c, 0x54, 0xd5, 0x4e, 0xff, 0xfa, 0xa6, 0x86, 0x3e, 0xdd, 0xd4, 0xd0, 0xb7, 0x9b, 0x1a, 0x7a,
+	0xfb, 0xbd, 0xb6, 0xf4, 0xe2, 0xff, 0xdf, 0xfc, 0x58, 0x5a, 0x39, 0xe9, 0xef, 0xfd, 0x18, 0x00,
+	0x2c, 0x2e, 0x0f, 0xd3, 0x6e, 0x07, 0x00, 0x00,
 }
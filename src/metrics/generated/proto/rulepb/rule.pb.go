@@ -235,9 +235,10 @@ type RollupRuleSnapshot struct {
 	LastUpdatedAtNanos int64           `protobuf:"varint,6,opt,name=last_updated_at_nanos,json=lastUpdatedAtNanos,proto3" json:"last_updated_at_nanos,omitempty"`
 	LastUpdatedBy      string          `protobuf:"bytes,7,opt,name=last_updated_by,json=lastUpdatedBy,proto3" json:"last_updated_by,omitempty"`
 	// TODO(xichen): rename this once all rules are updated in KV.
-	TargetsV2    []*RollupTargetV2 `protobuf:"bytes,8,rep,name=targets_v2,json=targetsV2" json:"targets_v2,omitempty"`
-	KeepOriginal bool              `protobuf:"varint,9,opt,name=keep_original,json=keepOriginal,proto3" json:"keep_original,omitempty"`
-	Tags         []*metricpb.Tag   `protobuf:"bytes,10,rep,name=tags" json:"tags,omitempty"`
+	TargetsV2          []*RollupTargetV2 `protobuf:"bytes,8,rep,name=targets_v2,json=targetsV2" json:"targets_v2,omitempty"`
+	KeepOriginal       bool              `protobuf:"varint,9,opt,name=keep_original,json=keepOriginal,proto3" json:"keep_original,omitempty"`
+	Tags               []*metricpb.Tag   `protobuf:"bytes,10,rep,name=tags" json:"tags,omitempty"`
+	DualEmitUntilNanos int64             `protobuf:"varint,11,opt,name=dual_emit_until_nanos,json=dualEmitUntilNanos,proto3" json:"dual_emit_until_nanos,omitempty"`
 }
 
 func (m *RollupRuleSnapshot) Reset()                    { *m = RollupRuleSnapshot{} }
@@ -315,6 +316,13 @@ func (m *RollupRuleSnapshot) GetTags() []*metricpb.Tag {
 	return nil
 }
 
+func (m *RollupRuleSnapshot) GetDualEmitUntilNanos() int64 {
+	if m != nil {
+		return m.DualEmitUntilNanos
+	}
+	return 0
+}
+
 type RollupRule struct {
 	Uuid      string                `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	Snapshots []*RollupRuleSnapshot `protobuf:"bytes,2,rep,name=snapshots" json:"snapshots,omitempty"`
@@ -778,6 +786,11 @@ func (m *RollupRuleSnapshot) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.DualEmitUntilNanos != 0 {
+		dAtA[i] = 0x58
+		i++
+		i = encodeVarintRule(dAtA, i, uint64(m.DualEmitUntilNanos))
+	}
 	return i, nil
 }
 
@@ -1068,6 +1081,9 @@ func (m *RollupRuleSnapshot) Size() (n int) {
 			n += 1 + l + sovRule(uint64(l))
 		}
 	}
+	if m.DualEmitUntilNanos != 0 {
+		n += 1 + sovRule(uint64(m.DualEmitUntilNanos))
+	}
 	return n
 }
 
@@ -2181,6 +2197,25 @@ func (m *RollupRuleSnapshot) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DualEmitUntilNanos", wireType)
+			}
+			m.DualEmitUntilNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRule
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DualEmitUntilNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRule(dAtA[iNdEx:])
@@ -2698,54 +2733,55 @@ func init() {
 }
 
 var fileDescriptorRule = []byte{
-	// 775 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xcb, 0x6e, 0xf3, 0x44,
-	0x14, 0xc6, 0x49, 0x9a, 0xc4, 0x27, 0x97, 0x86, 0x69, 0x29, 0x56, 0x41, 0x51, 0x08, 0x02, 0x65,
-	0x81, 0x1c, 0x70, 0x55, 0xa9, 0xec, 0x68, 0x55, 0x04, 0x12, 0xa2, 0x54, 0xd3, 0xd2, 0x45, 0x85,
-	0x64, 0x8d, 0xe3, 0xc1, 0xb5, 0xf0, 0x65, 0x34, 0x33, 0xae, 0x94, 0xa7, 0x80, 0x17, 0xe1, 0x3d,
-	0x58, 0xf2, 0x08, 0xa8, 0xbc, 0x03, 0x0b, 0x56, 0xbf, 0x3c, 0x63, 0x27, 0x8e, 0xea, 0xa8, 0x4a,
-	0xa5, 0x5f, 0xff, 0x2a, 0x67, 0xce, 0x9c, 0xf9, 0xce, 0xe5, 0xfb, 0x8e, 0x15, 0xf8, 0x26, 0x08,
-	0xe5, 0x43, 0xe6, 0xd9, 0x8b, 0x34, 0x9e, 0xc7, 0x27, 0xbe, 0x37, 0x8f, 0x4f, 0xe6, 0x82, 0x2f,
-	0xe6, 0x31, 0x95, 0x3c, 0x5c, 0x88, 0x79, 0x40, 0x13, 0xca, 0x89, 0xa4, 0xfe, 0x9c, 0xf1, 0x54,
-	0xa6, 0x73, 0x9e, 0x45, 0x94, 0x79, 0xea, 0xc7, 0x56, 0x1e, 0xd4, 0xd6, 0xae, 0xe3, 0xab, 0x1d,
-	0x91, 0x48, 0x10, 0x70, 0x1a, 0x10, 0x19, 0xa6, 0x09, 0xf3, 0xaa, 0x27, 0x8d, 0x7b, 0xfc, 0xfd,
-	0x8e, 0x78, 0x2c, 0x64, 0x34, 0x0a, 0x93, 0xbc, 0xba, 0xd2, 0x2c, 0x90, 0x2e, 0x77, 0x45, 0x4a,
-	0xa3, 0x70, 0xb1, 0xcc, 0x71, 0x94, 0xf1, 0x4a, 0x14, 0xed, 0x67, 0x5e, 0x61, 0x68, 0x94, 0xe9,
-	0xff, 0x4d, 0x38, 0xf8, 0x91, 0x30, 0x16, 0x26, 0x01, 0xce, 0x22, 0x7a, 0x93, 0x10, 0x26, 0x1e,
-	0x52, 0x89, 0x10, 0xb4, 0x12, 0x12, 0x53, 0xcb, 0x98, 0x18, 0x33, 0x13, 0x2b, 0x1b, 0x8d, 0x01,
-	0x64, 0x1a, 0x7b, 0x42, 0xa6, 0x09, 0xf5, 0xad, 0xc6, 0xc4, 0x98, 0x75, 0x71, 0xc5, 0x83, 0x3e,
-	0x85, 0xc1, 0x22, 0x93, 0xe9, 0x23, 0xe5, 0x6e, 0x42, 0x92, 0x54, 0x58, 0xcd, 0x89, 0x31, 0x6b,
-	0xe2, 0x7e, 0xe1, 0xbc, 0xca, 0x7d, 0xe8, 0x08, 0xda, 0xbf, 0x86, 0x91, 0xa4, 0xdc, 0x6a, 0x29,
-	0xe8, 0xe2, 0x84, 0xbe, 0x80, 0xae, 0x6a, 0x2f, 0xa4, 0xc2, 0xda, 0x9b, 0x34, 0x67, 0x3d, 0x67,
-	0x64, 0x97, 0x8d, 0xdb, 0xd7, 0xca, 0xc0, 0xab, 0x08, 0xf4, 0x15, 0x7c, 0x10, 0x11, 0x21, 0xdd,
-	0x8c, 0xf9, 0x79, 0x8b, 0x2e, 0x91, 0x45, 0xca, 0xb6, 0x4a, 0x89, 0xf2, 0xcb, 0x9f, 0xf5, 0xdd,
-	0xb9, 0xd4, 0x89, 0x3f, 0x87, 0xfd, 0x8d, 0x27, 0xde, 0xd2, 0xea, 0xa8, 0x0a, 0x06, 0x95, 0xe0,
-	0x8b, 0x25, 0xfa, 0x01, 0xde, 0xaf, 0x90, 0xef, 0xca, 0x25, 0xa3, 0xc2, 0xea, 0x4e, 0x9a, 0xb3,
-	0xa1, 0x33, 0xb6, 0x37, 0x44, 0x62, 0x9f, 0xaf, 0x4f, 0xb7, 0x4b, 0x46, 0xf1, 0x88, 0x6c, 0x3a,
-	0x04, 0xba, 0x80, 0x91, 0x90, 0x29, 0x27, 0x01, 0x75, 0x57, 0xdd, 0x99, 0xaa, 0xbb, 0x0f, 0xd7,
-	0xdd, 0xdd, 0xe8, 0x88, 0xa2, 0xc9, 0x7d, 0x51, 0x39, 0xe6, 0xbd, 0x9e, 0x42, 0xcf, 0xe7, 0x29,
-	0xd3, 0x00, 0x4b, 0x0b, 0x26, 0xc6, 0x6c, 0xe8, 0x1c, 0xae, 0x9f, 0x5f, 0xf2, 0x94, 0x15, 0x6f,
-	0xc1, 0x5f, 0xd9, 0xe8, 0x13, 0x68, 0x49, 0x12, 0x08, 0xab, 0xa7, 0xd2, 0x0d, 0xec, 0x92, 0x7f,
-	0xfb, 0x96, 0x04, 0x58, 0x5d, 0x4d, 0x7f, 0x81, 0x5e, 0x85, 0xfb, 0x9c, 0xf3, 0x2c, 0x0b, 0xfd,
-	0x92, 0xf3, 0xdc, 0x46, 0x5f, 0x83, 0x29, 0x0a, 0x4d, 0x08, 0xab, 0xa1, 0xa0, 0x3e, 0xb2, 0xf5,
-	0x86, 0xd9, 0x35, 0xba, 0xc1, 0xeb, 0xe8, 0xa9, 0x0f, 0x7d, 0x9c, 0x46, 0x51, 0xc6, 0x6e, 0x09,
-	0x0f, 0x68, 0xbd, 0xa4, 0x50, 0x51, 0x64, 0x8e, 0x6c, 0xea, 0xaa, 0x36, 0x94, 0xd0, 0x7c, 0x49,
-	0x09, 0xd3, 0x3f, 0x0d, 0x18, 0x56, 0xd3, 0xdc, 0x39, 0xe8, 0x4b, 0xe8, 0x96, 0x1b, 0xa7, 0x92,
-	0xf5, 0xf2, 0x69, 0xad, 0xb6, 0xd1, 0xbe, 0x2e, 0x4c, 0xbc, 0x8a, 0xaa, 0xa5, 0xa9, 0xb1, 0x23,
-	0x4d, 0x9f, 0xc1, 0x90, 0x53, 0x41, 0x13, 0xdf, 0xa5, 0x09, 0xf1, 0x22, 0xea, 0x2b, 0xf9, 0x77,
-	0xf1, 0x40, 0x7b, 0xbf, 0xd5, 0xce, 0xe9, 0xef, 0x4d, 0x40, 0xba, 0xde, 0x77, 0xbb, 0x6f, 0x36,
-	0x74, 0xa4, 0x1a, 0x58, 0xb9, 0x6e, 0x87, 0x25, 0xad, 0xd5, 0x69, 0xe2, 0x32, 0xe8, 0x6d, 0x6e,
-	0xdc, 0x29, 0x40, 0x91, 0xc5, 0x7d, 0x74, 0xd4, 0xaa, 0xf5, 0x9c, 0xa3, 0xba, 0x6a, 0xee, 0x1c,
-	0x6c, 0x16, 0x91, 0x77, 0x4e, 0xde, 0xfe, 0x6f, 0x94, 0x32, 0x37, 0xe5, 0x61, 0x10, 0x26, 0x24,
-	0xb2, 0x4c, 0x35, 0xa1, 0x7e, 0xee, 0xfc, 0xa9, 0xf0, 0xad, 0xb6, 0x00, 0xb6, 0x6f, 0xc1, 0x3d,
-	0xc0, 0x9a, 0x90, 0xda, 0x25, 0x38, 0x7b, 0xbe, 0x04, 0xc7, 0x9b, 0xf5, 0x6d, 0xdb, 0x81, 0xff,
-	0x1a, 0xd0, 0x51, 0x77, 0x5a, 0xff, 0xcf, 0x90, 0x3f, 0x06, 0x33, 0xa7, 0x5a, 0x30, 0xb2, 0xa0,
-	0x8a, 0x61, 0x13, 0xaf, 0x1d, 0x68, 0x06, 0xa3, 0x05, 0xa7, 0x9b, 0xe3, 0xd6, 0x1c, 0x0f, 0x0b,
-	0x7f, 0x39, 0xea, 0xad, 0xec, 0xb4, 0xb6, 0xb2, 0xb3, 0xa9, 0xae, 0xbd, 0x97, 0xd5, 0xd5, 0xae,
-	0x51, 0xd7, 0x19, 0x0c, 0x62, 0xfd, 0x15, 0x70, 0xf3, 0x79, 0x08, 0xab, 0xa3, 0xa6, 0x73, 0x50,
-	0xf3, 0x89, 0xc0, 0xfd, 0x78, 0x7d, 0xc8, 0xbf, 0x6a, 0x7d, 0xae, 0x46, 0x57, 0x3c, 0xd4, 0xb4,
-	0xa3, 0xe7, 0x63, 0xc5, 0x3d, 0xbe, 0xb2, 0x6b, 0x35, 0x65, 0xd6, 0x68, 0xea, 0xe2, 0xbb, 0xbf,
-	0x9e, 0xc6, 0xc6, 0xdf, 0x4f, 0x63, 0xe3, 0x9f, 0xa7, 0xb1, 0xf1, 0xc7, 0xbf, 0xe3, 0xf7, 0xee,
-	0x4f, 0x5f, 0xf5, 0xcf, 0xc2, 0x6b, 0xab, 0xd3, 0xc9, 0x9b, 0x00, 0x00, 0x00, 0xff, 0xff, 0x66,
-	0x1c, 0x70, 0x44, 0x99, 0x08, 0x00, 0x00,
+	// 793 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc5, 0x56, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x25, 0x4d, 0x9a, 0xd6, 0xe3, 0x24, 0x0d, 0xd3, 0x52, 0xac, 0x82, 0xa2, 0x12, 0x04, 0xea,
+	0x02, 0x39, 0x90, 0xaa, 0x52, 0xd9, 0xd1, 0xaa, 0x15, 0x48, 0x88, 0x52, 0x4d, 0x1f, 0x8b, 0x0a,
+	0xc9, 0x9a, 0xc4, 0x83, 0x6b, 0xe1, 0x97, 0xc6, 0xe3, 0x4a, 0xf9, 0x0b, 0x7e, 0x84, 0x9f, 0x60,
+	0xc5, 0x92, 0x4f, 0x40, 0xf0, 0x0f, 0x2c, 0x58, 0x31, 0x2f, 0x27, 0x8e, 0xea, 0xa8, 0x4a, 0x25,
+	0xc4, 0x22, 0xc9, 0x9d, 0x7b, 0xaf, 0xcf, 0xdc, 0xc7, 0x39, 0x56, 0xc0, 0x2b, 0xcf, 0x67, 0x97,
+	0xd9, 0xc0, 0x1e, 0xc6, 0x61, 0x2f, 0xdc, 0x76, 0x07, 0xfc, 0xab, 0x97, 0xd2, 0x61, 0x2f, 0x24,
+	0x8c, 0xfa, 0xc3, 0xb4, 0xe7, 0x91, 0x88, 0x50, 0xcc, 0x88, 0xdb, 0x4b, 0x68, 0xcc, 0xe2, 0x1e,
+	0xcd, 0x02, 0x92, 0x0c, 0xe4, 0x8f, 0x2d, 0x3d, 0xb0, 0xae, 0x5c, 0x1b, 0x47, 0x73, 0x22, 0x61,
+	0xcf, 0xa3, 0xc4, 0xc3, 0xcc, 0x8f, 0x23, 0x0e, 0x58, 0x38, 0x29, 0xdc, 0x8d, 0x37, 0x73, 0xe2,
+	0x25, 0x7e, 0x42, 0x02, 0x3f, 0x12, 0xd5, 0xe5, 0xa6, 0x46, 0x3a, 0x98, 0x17, 0x29, 0x0e, 0xfc,
+	0xe1, 0x48, 0xe0, 0x48, 0xe3, 0x96, 0x28, 0xca, 0xcf, 0x51, 0x94, 0xa1, 0x50, 0xba, 0x7f, 0xaa,
+	0x60, 0xf5, 0x1d, 0x4e, 0x12, 0x3f, 0xf2, 0x10, 0x9f, 0xdb, 0x49, 0x84, 0x93, 0xf4, 0x32, 0x66,
+	0x10, 0x82, 0x5a, 0x84, 0x43, 0x62, 0x55, 0x36, 0x2b, 0x5b, 0x06, 0x92, 0x36, 0xec, 0x00, 0xc0,
+	0xe2, 0x70, 0x90, 0xb2, 0x38, 0x22, 0xae, 0xb5, 0xc0, 0x23, 0xcb, 0xa8, 0xe0, 0x81, 0x8f, 0x41,
+	0x73, 0x98, 0xb1, 0xf8, 0x8a, 0x50, 0x27, 0xc2, 0x51, 0x9c, 0x5a, 0x55, 0x9e, 0x52, 0x45, 0x0d,
+	0xed, 0x3c, 0x12, 0x3e, 0xb8, 0x0e, 0xea, 0x1f, 0xfd, 0x80, 0x11, 0x6a, 0xd5, 0x24, 0xb4, 0x3e,
+	0xc1, 0x67, 0x60, 0x59, 0xb6, 0xe7, 0x93, 0xd4, 0x5a, 0xdc, 0xac, 0x6e, 0x99, 0xfd, 0xb6, 0x9d,
+	0x37, 0x6e, 0x1f, 0x4b, 0x03, 0x8d, 0x33, 0xe0, 0x0b, 0x70, 0x2f, 0xc0, 0x29, 0x73, 0xb2, 0xc4,
+	0x15, 0x2d, 0x3a, 0x98, 0xe9, 0x2b, 0xeb, 0xf2, 0x4a, 0x28, 0x82, 0x67, 0x2a, 0xb6, 0xc7, 0xd4,
+	0xc5, 0x4f, 0xc1, 0xca, 0xd4, 0x23, 0x83, 0x91, 0xb5, 0x24, 0x2b, 0x68, 0x16, 0x92, 0xf7, 0x47,
+	0xf0, 0x2d, 0xb8, 0x5b, 0x58, 0xbe, 0xc3, 0x46, 0x09, 0xaf, 0x68, 0x99, 0x57, 0xd4, 0xea, 0x77,
+	0xec, 0x29, 0x92, 0xd8, 0x7b, 0x93, 0xd3, 0x29, 0x4f, 0x43, 0x6d, 0x3c, 0xed, 0x48, 0xe1, 0x3e,
+	0x68, 0xf3, 0xe1, 0x50, 0xec, 0x11, 0x67, 0xdc, 0x9d, 0x21, 0xbb, 0xbb, 0x3f, 0xe9, 0xee, 0x44,
+	0x65, 0xe8, 0x26, 0x57, 0xd2, 0xc2, 0x51, 0xf4, 0xba, 0x03, 0x4c, 0x97, 0xc6, 0x89, 0x02, 0x18,
+	0x59, 0x80, 0x17, 0xdd, 0xea, 0xaf, 0x4d, 0x1e, 0x3f, 0xe0, 0x41, 0xfd, 0x2c, 0x70, 0xc7, 0x36,
+	0x7c, 0x04, 0x6a, 0x0c, 0x7b, 0xa9, 0x65, 0xca, 0xeb, 0x9a, 0x76, 0xbe, 0x7f, 0xfb, 0x14, 0x7b,
+	0x48, 0x86, 0xba, 0x1f, 0x80, 0x59, 0xd8, 0xbd, 0xd8, 0x79, 0x96, 0xf9, 0x6e, 0xbe, 0x73, 0x61,
+	0xc3, 0x97, 0xc0, 0x48, 0x35, 0x27, 0x52, 0xbe, 0x72, 0x01, 0xf5, 0xc0, 0x56, 0x0a, 0xb3, 0x4b,
+	0x78, 0x83, 0x26, 0xd9, 0x5d, 0x17, 0x34, 0x50, 0x1c, 0x04, 0x59, 0x72, 0x8a, 0xa9, 0x47, 0xca,
+	0x29, 0x05, 0x75, 0x91, 0x02, 0xd9, 0x50, 0x55, 0x4d, 0x31, 0xa1, 0x7a, 0x13, 0x13, 0xba, 0x5f,
+	0x2a, 0xa0, 0x55, 0xbc, 0xe6, 0xbc, 0x0f, 0x9f, 0x73, 0x00, 0xad, 0x38, 0x79, 0x99, 0x29, 0xa6,
+	0x35, 0x56, 0xa3, 0x7d, 0xac, 0x4d, 0x34, 0xce, 0x2a, 0x5d, 0xd3, 0xc2, 0x9c, 0x6b, 0x7a, 0x02,
+	0x5a, 0x94, 0xa4, 0x24, 0x72, 0x1d, 0x12, 0xe1, 0x41, 0xc0, 0x15, 0x52, 0x95, 0x0a, 0x69, 0x2a,
+	0xef, 0xa1, 0x72, 0x76, 0xbf, 0x56, 0x01, 0x54, 0xf5, 0xfe, 0x5f, 0xbd, 0xd9, 0x60, 0x89, 0xc9,
+	0x81, 0xe5, 0x72, 0x5b, 0xcb, 0xd7, 0x5a, 0x9c, 0x26, 0xca, 0x93, 0xfe, 0xa5, 0xe2, 0x76, 0x78,
+	0x9f, 0xea, 0x16, 0xe7, 0xaa, 0x2f, 0xa5, 0x66, 0xf6, 0xd7, 0xcb, 0xaa, 0x39, 0xef, 0x23, 0x43,
+	0x67, 0xf2, 0x35, 0xf3, 0xf6, 0x3f, 0x11, 0x92, 0x38, 0x31, 0xf5, 0x3d, 0x3f, 0xc2, 0x01, 0x17,
+	0x96, 0x98, 0x50, 0x43, 0x38, 0xdf, 0x6b, 0xdf, 0x58, 0x05, 0x60, 0xa6, 0x0a, 0x44, 0x67, 0x6e,
+	0x86, 0x03, 0x87, 0x84, 0x3e, 0xaf, 0x35, 0x62, 0x7e, 0xa0, 0x3b, 0x33, 0x55, 0x67, 0x22, 0x78,
+	0xc8, 0x63, 0x67, 0x22, 0x24, 0x3b, 0xeb, 0x5e, 0x00, 0x30, 0xd9, 0x61, 0xa9, 0x6e, 0x76, 0xaf,
+	0xeb, 0x66, 0x63, 0xba, 0xa5, 0x59, 0xb2, 0xf9, 0xbd, 0x00, 0x96, 0x64, 0x4c, 0x49, 0xe6, 0x1a,
+	0xf2, 0x43, 0x60, 0x08, 0x76, 0xa4, 0x09, 0x1e, 0x12, 0x49, 0x0a, 0x03, 0x4d, 0x1c, 0x70, 0x0b,
+	0xb4, 0x87, 0x94, 0x4c, 0x6f, 0x48, 0xd1, 0xa2, 0xa5, 0xfd, 0xf9, 0x76, 0x66, 0x2e, 0xb4, 0x36,
+	0x73, 0xa1, 0xd3, 0x84, 0x5c, 0xbc, 0x99, 0x90, 0xf5, 0x12, 0x42, 0xee, 0x82, 0x66, 0xa8, 0x5e,
+	0x1c, 0x8e, 0x98, 0x47, 0xca, 0x39, 0x21, 0xa6, 0xb3, 0x5a, 0xf2, 0x56, 0x41, 0x8d, 0x70, 0x72,
+	0x10, 0x2f, 0xc2, 0x06, 0x95, 0xa3, 0xd3, 0x0f, 0x2a, 0xa6, 0xc0, 0xeb, 0x63, 0x45, 0x26, 0x1d,
+	0xdb, 0xa5, 0x34, 0x34, 0x4a, 0x68, 0xb8, 0xff, 0xfa, 0xdb, 0xcf, 0x4e, 0xe5, 0x3b, 0xff, 0xfc,
+	0xe0, 0x9f, 0xcf, 0xbf, 0x3a, 0x77, 0x2e, 0x76, 0x6e, 0xf5, 0x67, 0x64, 0x50, 0x97, 0xa7, 0xed,
+	0xbf, 0x17, 0xbd, 0x9c, 0x40, 0xcc, 0x08, 0x00, 0x00,
 }
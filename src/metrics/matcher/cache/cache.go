@@ -63,6 +63,10 @@ type Cache interface {
 	// Unregister deletes the cached results for a given namespace.
 	Unregister(namespace []byte)
 
+	// RecentIDs returns up to limit of the most recently matched metric ids
+	// across all namespaces, ordered from most to least recently matched.
+	RecentIDs(limit int) [][]byte
+
 	// Close closes the cache.
 	Close() error
 }
@@ -242,6 +246,24 @@ func (c *cache) Unregister(namespace []byte) {
 	c.metrics.unregisters.Inc(1)
 }
 
+func (c *cache) RecentIDs(limit int) [][]byte {
+	if limit <= 0 {
+		return nil
+	}
+
+	c.list.Lock()
+	defer c.list.Unlock()
+
+	if n := c.list.Len(); n < limit {
+		limit = n
+	}
+	ids := make([][]byte, 0, limit)
+	for elem := c.list.Front(); elem != nil && len(ids) < limit; elem = elem.next {
+		ids = append(ids, append([]byte(nil), elem.id...))
+	}
+	return ids
+}
+
 func (c *cache) Close() error {
 	c.Lock()
 	if c.closed {
@@ -594,6 +594,24 @@ func TestCacheDeleteBatching(t *testing.T) {
 	}
 }
 
+func TestCacheRecentIDs(t *testing.T) {
+	opts := testCacheOptions()
+	c := NewCache(opts).(*cache)
+	now := time.Now()
+	c.nowFn = func() time.Time { return now }
+	source := newMockSource()
+	populateCache(c, testValues, now, source, populateBoth)
+
+	require.Nil(t, c.RecentIDs(0))
+	require.Equal(t, [][]byte{testValues[0].id}, c.RecentIDs(1))
+	require.Equal(t, [][]byte{testValues[0].id, testValues[1].id}, c.RecentIDs(10))
+
+	// Promote the second id and assert it is now the most recent.
+	now = now.Add(time.Minute)
+	c.ForwardMatch(testValues[1].namespace, testValues[1].id, now.UnixNano(), now.UnixNano())
+	require.Equal(t, [][]byte{testValues[1].id, testValues[0].id}, c.RecentIDs(10))
+}
+
 func TestCacheClose(t *testing.T) {
 	opts := testCacheOptions()
 	c := NewCache(opts).(*cache)
@@ -36,6 +36,10 @@ type Matcher interface {
 	// and returns the match result.
 	ForwardMatch(id id.ID, fromNanos, toNanos int64) rules.MatchResult
 
+	// RecentIDs returns up to limit of the most recently matched metric IDs,
+	// or nil if the matcher does not cache match results.
+	RecentIDs(limit int) [][]byte
+
 	// Close closes the matcher.
 	Close() error
 }
@@ -115,6 +119,10 @@ func (m *matcher) ForwardMatch(
 	return m.cache.ForwardMatch(m.namespaceResolver.Resolve(id), id.Bytes(), fromNanos, toNanos)
 }
 
+func (m *matcher) RecentIDs(limit int) [][]byte {
+	return m.cache.RecentIDs(limit)
+}
+
 func (m *matcher) Close() error {
 	m.namespaces.Close()
 	return m.cache.Close()
@@ -151,6 +159,10 @@ func (m *noCacheMatcher) ForwardMatch(
 	return m.namespaces.ForwardMatch(m.namespaceResolver.Resolve(id), id.Bytes(), fromNanos, toNanos)
 }
 
+func (m *noCacheMatcher) RecentIDs(limit int) [][]byte {
+	return nil
+}
+
 func (m *noCacheMatcher) Close() error {
 	m.namespaces.Close()
 	return nil
@@ -83,3 +83,17 @@ func (mr *MockMatcherMockRecorder) ForwardMatch(arg0, arg1, arg2 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardMatch", reflect.TypeOf((*MockMatcher)(nil).ForwardMatch), arg0, arg1, arg2)
 }
+
+// RecentIDs mocks base method.
+func (m *MockMatcher) RecentIDs(arg0 int) [][]byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentIDs", arg0)
+	ret0, _ := ret[0].([][]byte)
+	return ret0
+}
+
+// RecentIDs indicates an expected call of RecentIDs.
+func (mr *MockMatcherMockRecorder) RecentIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentIDs", reflect.TypeOf((*MockMatcher)(nil).RecentIDs), arg0)
+}
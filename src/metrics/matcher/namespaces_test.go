@@ -374,6 +374,10 @@ func (c *memCache) Unregister(namespace []byte) {
 	delete(c.namespaces, string(namespace))
 }
 
+func (c *memCache) RecentIDs(limit int) [][]byte {
+	return nil
+}
+
 func (c *memCache) Close() error { return nil }
 
 type mockRuntimeValue struct {
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/aggregation"
 	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric"
@@ -42,6 +43,7 @@ var (
 type Metric struct {
 	ID         id.RawID
 	Annotation []byte
+	Exemplars  []metric.Exemplar
 	Type       metric.Type
 	TimeNanos  int64
 	Value      float64
@@ -56,6 +58,7 @@ func (m Metric) ToProto(pb *metricpb.TimedMetric) error {
 	pb.TimeNanos = m.TimeNanos
 	pb.Value = m.Value
 	pb.Annotation = m.Annotation
+	pb.Exemplars = metric.ExemplarsToProto(m.Exemplars, pb.Exemplars[:0])
 	return nil
 }
 
@@ -68,6 +71,7 @@ func (m *Metric) FromProto(pb metricpb.TimedMetric) error {
 	m.TimeNanos = pb.TimeNanos
 	m.Value = pb.Value
 	m.Annotation = pb.Annotation
+	m.Exemplars = metric.ExemplarsFromProto(pb.Exemplars, nil)
 	return nil
 }
 
@@ -85,6 +89,7 @@ func (m Metric) String() string {
 type ChunkedMetric struct {
 	id.ChunkedID
 	Annotation []byte
+	Exemplars  []metric.Exemplar
 	TimeNanos  int64
 	Value      float64
 }
@@ -115,6 +120,14 @@ type RawMetric interface {
 type MetricWithStoragePolicy struct {
 	Metric
 	policy.StoragePolicy
+
+	// DowngradedResolution is non-zero when the metric was emitted at a
+	// coarser resolution than its storage policy resolution.
+	DowngradedResolution time.Duration
+
+	// AggregationType is the type of the aggregation that produced the value,
+	// or aggregation.UnknownType if the metric was not aggregated.
+	AggregationType aggregation.Type
 }
 
 // ToProto converts the chunked metric with storage policy to a protobuf message in place.
@@ -143,6 +156,14 @@ func (m MetricWithStoragePolicy) String() string {
 type ChunkedMetricWithStoragePolicy struct {
 	ChunkedMetric
 	policy.StoragePolicy
+
+	// DowngradedResolution is non-zero when the metric was emitted at a
+	// coarser resolution than its storage policy resolution.
+	DowngradedResolution time.Duration
+
+	// AggregationType is the type of the aggregation that produced the value,
+	// or aggregation.UnknownType if the metric was not aggregated.
+	AggregationType aggregation.Type
 }
 
 // ForwardedMetric is a forwarded metric.
@@ -151,9 +172,18 @@ type ForwardedMetric struct {
 	Values     []float64
 	PrevValues []float64
 	Annotation []byte
-	Type       metric.Type
-	TimeNanos  int64
-	Version    uint32
+	Exemplars  []metric.Exemplar
+	// HistogramBucketUpperBounds, HistogramBucketCounts and HistogramSum are
+	// set instead of the values when forwarding an aggregated histogram.
+	HistogramBucketUpperBounds []float64
+	HistogramBucketCounts      []int64
+	HistogramSum               float64
+	// Contributors is the encoded sketch of the distinct contributors of the
+	// forwarded series, set when the contributors of rollups are counted.
+	Contributors []byte
+	Type         metric.Type
+	TimeNanos    int64
+	Version      uint32
 }
 
 // ToProto converts the forwarded metric to a protobuf message in place.
@@ -166,6 +196,11 @@ func (m ForwardedMetric) ToProto(pb *metricpb.ForwardedMetric) error {
 	pb.Values = m.Values
 	pb.PrevValues = m.PrevValues
 	pb.Annotation = m.Annotation
+	pb.Exemplars = metric.ExemplarsToProto(m.Exemplars, pb.Exemplars[:0])
+	pb.HistogramBucketUpperBounds = m.HistogramBucketUpperBounds
+	pb.HistogramBucketCounts = m.HistogramBucketCounts
+	pb.HistogramSum = m.HistogramSum
+	pb.Contributors = m.Contributors
 	pb.Version = m.Version
 	return nil
 }
@@ -180,6 +215,11 @@ func (m *ForwardedMetric) FromProto(pb metricpb.ForwardedMetric) error {
 	m.Values = pb.Values
 	m.PrevValues = pb.PrevValues
 	m.Annotation = pb.Annotation
+	m.Exemplars = metric.ExemplarsFromProto(pb.Exemplars, nil)
+	m.HistogramBucketUpperBounds = pb.HistogramBucketUpperBounds
+	m.HistogramBucketCounts = pb.HistogramBucketCounts
+	m.HistogramSum = pb.HistogramSum
+	m.Contributors = pb.Contributors
 	m.Version = pb.Version
 	return nil
 }
@@ -21,6 +21,7 @@
 package aggregated
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -74,6 +75,15 @@ var (
 		TimeNanos: 67890,
 		Values:    []float64{1.34, -26.57},
 	}
+	testForwardedMetric3 = ForwardedMetric{
+		Type:                       metric.HistogramType,
+		ID:                         []byte("testForwardedMetric3"),
+		TimeNanos:                  67890,
+		HistogramBucketUpperBounds: []float64{0.5, 1, math.Inf(1)},
+		HistogramBucketCounts:      []int64{3, 0, 1},
+		HistogramSum:               12.5,
+		Contributors:               []byte{1, 0, 1, 2},
+	}
 	testBadForwardedMetric = ForwardedMetric{
 		Type: 999,
 	}
@@ -140,6 +150,15 @@ var (
 		TimeNanos: 67890,
 		Values:    []float64{1.34, -26.57},
 	}
+	testForwardedMetric3Proto = metricpb.ForwardedMetric{
+		Type:                       metricpb.MetricType_HISTOGRAM,
+		Id:                         []byte("testForwardedMetric3"),
+		TimeNanos:                  67890,
+		HistogramBucketUpperBounds: []float64{0.5, 1, math.Inf(1)},
+		HistogramBucketCounts:      []int64{3, 0, 1},
+		HistogramSum:               12.5,
+		Contributors:               []byte{1, 0, 1, 2},
+	}
 	testForwardMetadata1Proto = metricpb.ForwardMetadata{
 		AggregationId: aggregationpb.AggregationID{Id: 0},
 		StoragePolicy: policypb.StoragePolicy{
@@ -363,6 +382,14 @@ func TestForwardedMetricWithMetadataFromProto(t *testing.T) {
 			expectedMetric:   testForwardedMetric2,
 			expectedMetadata: testForwardMetadata2,
 		},
+		{
+			data: metricpb.ForwardedMetricWithMetadata{
+				Metric:   testForwardedMetric3Proto,
+				Metadata: testForwardMetadata1Proto,
+			},
+			expectedMetric:   testForwardedMetric3,
+			expectedMetadata: testForwardMetadata1,
+		},
 	}
 
 	var res ForwardedMetricWithMetadata
@@ -416,6 +443,10 @@ func TestForwardedMetricWithMetadataRoundtrip(t *testing.T) {
 			metric:   testForwardedMetric2,
 			metadata: testForwardMetadata2,
 		},
+		{
+			metric:   testForwardedMetric3,
+			metadata: testForwardMetadata1,
+		},
 	}
 
 	var (
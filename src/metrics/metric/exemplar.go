@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metric
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+)
+
+// Exemplar is a sample of an incoming value annotated with the trace it was
+// recorded in. A bounded set of exemplars is retained through aggregation so
+// aggregated datapoints can be linked back to the traces that produced them.
+type Exemplar struct {
+	TraceID   []byte
+	SpanID    []byte
+	Value     float64
+	TimeNanos int64
+}
+
+// ToProto converts the exemplar to a protobuf message in place.
+func (e Exemplar) ToProto(pb *metricpb.Exemplar) {
+	pb.TraceId = e.TraceID
+	pb.SpanId = e.SpanID
+	pb.Value = e.Value
+	pb.TimeNanos = e.TimeNanos
+}
+
+// FromProto converts the protobuf message to an exemplar in place.
+func (e *Exemplar) FromProto(pb metricpb.Exemplar) {
+	e.TraceID = pb.TraceId
+	e.SpanID = pb.SpanId
+	e.Value = pb.Value
+	e.TimeNanos = pb.TimeNanos
+}
+
+// Clone returns a copy of the exemplar that does not share any byte slices
+// with the original.
+func (e Exemplar) Clone() Exemplar {
+	return Exemplar{
+		TraceID:   append([]byte(nil), e.TraceID...),
+		SpanID:    append([]byte(nil), e.SpanID...),
+		Value:     e.Value,
+		TimeNanos: e.TimeNanos,
+	}
+}
+
+// String is the string representation of an exemplar.
+func (e Exemplar) String() string {
+	return fmt.Sprintf(
+		"{trace_id:%s,span_id:%s,value:%f,timestamp:%s}",
+		e.TraceID,
+		e.SpanID,
+		e.Value,
+		time.Unix(0, e.TimeNanos).String(),
+	)
+}
+
+// ExemplarsToProto converts the exemplars to protobuf messages, appending
+// them to the given slice.
+func ExemplarsToProto(exemplars []Exemplar, pb []metricpb.Exemplar) []metricpb.Exemplar {
+	for _, e := range exemplars {
+		var pbExemplar metricpb.Exemplar
+		e.ToProto(&pbExemplar)
+		pb = append(pb, pbExemplar)
+	}
+	return pb
+}
+
+// ExemplarsFromProto converts the protobuf messages to exemplars, appending
+// them to the given slice.
+func ExemplarsFromProto(pb []metricpb.Exemplar, exemplars []Exemplar) []Exemplar {
+	for _, pbExemplar := range pb {
+		var e Exemplar
+		e.FromProto(pbExemplar)
+		exemplars = append(exemplars, e)
+	}
+	return exemplars
+}
@@ -37,12 +37,17 @@ var (
 	errNilCounterWithMetadatasProto    = errors.New("nil counter with metadatas proto message")
 	errNilBatchTimerWithMetadatasProto = errors.New("nil batch timer with metadatas proto message")
 	errNilGaugeWithMetadatasProto      = errors.New("nil gauge with metadatas proto message")
+	errNilHistogramWithMetadatasProto  = errors.New("nil histogram with metadatas proto message")
+	errHistogramBucketsMismatch        = errors.New("histogram bucket upper bounds and counts differ in length")
+	errHistogramBoundsNotAscending     = errors.New("histogram bucket upper bounds are not in ascending order")
+	errHistogramNegativeBucketCount    = errors.New("histogram bucket count is negative")
 )
 
 // Counter is a counter containing the counter ID and the counter value.
 type Counter struct {
 	ID              id.RawID
 	Annotation      []byte
+	Exemplars       []metric.Exemplar
 	Value           int64
 	ClientTimeNanos xtime.UnixNano
 }
@@ -54,6 +59,7 @@ func (c Counter) ToUnion() MetricUnion {
 		ID:              c.ID,
 		CounterVal:      c.Value,
 		Annotation:      c.Annotation,
+		Exemplars:       c.Exemplars,
 		ClientTimeNanos: c.ClientTimeNanos,
 	}
 }
@@ -64,6 +70,7 @@ func (c Counter) ToProto(pb *metricpb.Counter) {
 	pb.Value = c.Value
 	pb.Annotation = c.Annotation
 	pb.ClientTimeNanos = int64(c.ClientTimeNanos)
+	pb.Exemplars = metric.ExemplarsToProto(c.Exemplars, pb.Exemplars[:0])
 }
 
 // FromProto converts the protobuf message to a counter in place.
@@ -72,6 +79,7 @@ func (c *Counter) FromProto(pb metricpb.Counter) {
 	c.Value = pb.Value
 	c.Annotation = pb.Annotation
 	c.ClientTimeNanos = xtime.UnixNano(pb.ClientTimeNanos)
+	c.Exemplars = metric.ExemplarsFromProto(pb.Exemplars, nil)
 }
 
 // BatchTimer is a timer containing the timer ID and a list of timer values.
@@ -79,6 +87,7 @@ type BatchTimer struct {
 	ID              id.RawID
 	Values          []float64
 	Annotation      []byte
+	Exemplars       []metric.Exemplar
 	ClientTimeNanos xtime.UnixNano
 }
 
@@ -89,6 +98,7 @@ func (t BatchTimer) ToUnion() MetricUnion {
 		ID:              t.ID,
 		BatchTimerVal:   t.Values,
 		Annotation:      t.Annotation,
+		Exemplars:       t.Exemplars,
 		ClientTimeNanos: t.ClientTimeNanos,
 	}
 }
@@ -99,6 +109,7 @@ func (t BatchTimer) ToProto(pb *metricpb.BatchTimer) {
 	pb.Values = t.Values
 	pb.Annotation = t.Annotation
 	pb.ClientTimeNanos = int64(t.ClientTimeNanos)
+	pb.Exemplars = metric.ExemplarsToProto(t.Exemplars, pb.Exemplars[:0])
 }
 
 // FromProto converts the protobuf message to a batch timer in place.
@@ -106,12 +117,14 @@ func (t *BatchTimer) FromProto(pb metricpb.BatchTimer) {
 	t.ID = pb.Id
 	t.Values = pb.Values
 	t.Annotation = pb.Annotation
+	t.Exemplars = metric.ExemplarsFromProto(pb.Exemplars, nil)
 }
 
 // Gauge is a gauge containing the gauge ID and the value at certain time.
 type Gauge struct {
 	ID              id.RawID
 	Annotation      []byte
+	Exemplars       []metric.Exemplar
 	Value           float64
 	ClientTimeNanos xtime.UnixNano
 }
@@ -123,6 +136,7 @@ func (g Gauge) ToUnion() MetricUnion {
 		ID:              g.ID,
 		GaugeVal:        g.Value,
 		Annotation:      g.Annotation,
+		Exemplars:       g.Exemplars,
 		ClientTimeNanos: g.ClientTimeNanos,
 	}
 }
@@ -133,6 +147,7 @@ func (g Gauge) ToProto(pb *metricpb.Gauge) {
 	pb.Value = g.Value
 	pb.Annotation = g.Annotation
 	pb.ClientTimeNanos = int64(g.ClientTimeNanos)
+	pb.Exemplars = metric.ExemplarsToProto(g.Exemplars, pb.Exemplars[:0])
 }
 
 // FromProto converts the protobuf message to a gauge in place.
@@ -141,6 +156,70 @@ func (g *Gauge) FromProto(pb metricpb.Gauge) {
 	g.Value = pb.Value
 	g.Annotation = pb.Annotation
 	g.ClientTimeNanos = xtime.UnixNano(pb.ClientTimeNanos)
+	g.Exemplars = metric.ExemplarsFromProto(pb.Exemplars, nil)
+}
+
+// Histogram is a histogram containing the histogram ID, the number of values
+// observed in each bucket and the sum of the values.
+type Histogram struct {
+	ID id.RawID
+	// BucketUpperBounds are the inclusive upper bounds of the buckets in
+	// ascending order, the last of which may be +Inf.
+	BucketUpperBounds []float64
+	// BucketCounts are the number of values observed in each bucket.
+	BucketCounts    []int64
+	Sum             float64
+	Annotation      []byte
+	ClientTimeNanos xtime.UnixNano
+}
+
+// ToUnion converts the histogram to a metric union.
+func (h Histogram) ToUnion() MetricUnion {
+	return MetricUnion{
+		Type:                       metric.HistogramType,
+		ID:                         h.ID,
+		HistogramBucketUpperBounds: h.BucketUpperBounds,
+		HistogramBucketCounts:      h.BucketCounts,
+		HistogramSum:               h.Sum,
+		Annotation:                 h.Annotation,
+		ClientTimeNanos:            h.ClientTimeNanos,
+	}
+}
+
+// Validate validates the histogram buckets.
+func (h Histogram) Validate() error {
+	if len(h.BucketUpperBounds) != len(h.BucketCounts) {
+		return errHistogramBucketsMismatch
+	}
+	for i, count := range h.BucketCounts {
+		if count < 0 {
+			return errHistogramNegativeBucketCount
+		}
+		if i > 0 && h.BucketUpperBounds[i] <= h.BucketUpperBounds[i-1] {
+			return errHistogramBoundsNotAscending
+		}
+	}
+	return nil
+}
+
+// ToProto converts the histogram to a protobuf message in place.
+func (h Histogram) ToProto(pb *metricpb.Histogram) {
+	pb.Id = h.ID
+	pb.BucketUpperBounds = h.BucketUpperBounds
+	pb.BucketCounts = h.BucketCounts
+	pb.Sum = h.Sum
+	pb.Annotation = h.Annotation
+	pb.ClientTimeNanos = int64(h.ClientTimeNanos)
+}
+
+// FromProto converts the protobuf message to a histogram in place.
+func (h *Histogram) FromProto(pb metricpb.Histogram) {
+	h.ID = pb.Id
+	h.BucketUpperBounds = pb.BucketUpperBounds
+	h.BucketCounts = pb.BucketCounts
+	h.Sum = pb.Sum
+	h.Annotation = pb.Annotation
+	h.ClientTimeNanos = xtime.UnixNano(pb.ClientTimeNanos)
 }
 
 // CounterWithPoliciesList is a counter with applicable policies list.
@@ -242,20 +321,51 @@ func (gm *GaugeWithMetadatas) FromProto(pb *metricpb.GaugeWithMetadatas) error {
 	return nil
 }
 
+// HistogramWithMetadatas is a histogram with applicable metadatas.
+type HistogramWithMetadatas struct {
+	metadata.StagedMetadatas
+	Histogram
+}
+
+// ToProto converts the histogram with metadatas to a protobuf message in place.
+func (hm HistogramWithMetadatas) ToProto(pb *metricpb.HistogramWithMetadatas) error {
+	if err := hm.StagedMetadatas.ToProto(&pb.Metadatas); err != nil {
+		return err
+	}
+	hm.Histogram.ToProto(&pb.Histogram)
+	return nil
+}
+
+// FromProto converts the protobuf message to a histogram with metadatas in place.
+func (hm *HistogramWithMetadatas) FromProto(pb *metricpb.HistogramWithMetadatas) error {
+	if pb == nil {
+		return errNilHistogramWithMetadatasProto
+	}
+	if err := hm.StagedMetadatas.FromProto(pb.Metadatas); err != nil {
+		return err
+	}
+	hm.Histogram.FromProto(pb.Histogram)
+	return nil
+}
+
 // MetricUnion is a union of different types of metrics, only one of which is valid
 // at any given time. The actual type of the metric depends on the type field,
 // which determines which value field is valid. Note that if the timer values are
 // allocated from a pool, the TimerValPool should be set to the originating pool,
 // and the caller is responsible for returning the timer values to the pool.
 type MetricUnion struct {
-	TimerValPool    pool.FloatsPool
-	Annotation      []byte
-	ID              id.RawID
-	BatchTimerVal   []float64
-	CounterVal      int64
-	GaugeVal        float64
-	Type            metric.Type
-	ClientTimeNanos xtime.UnixNano
+	TimerValPool               pool.FloatsPool
+	Annotation                 []byte
+	Exemplars                  []metric.Exemplar
+	ID                         id.RawID
+	BatchTimerVal              []float64
+	HistogramBucketUpperBounds []float64
+	HistogramBucketCounts      []int64
+	HistogramSum               float64
+	CounterVal                 int64
+	GaugeVal                   float64
+	Type                       metric.Type
+	ClientTimeNanos            xtime.UnixNano
 }
 
 var emptyMetricUnion MetricUnion
@@ -269,6 +379,9 @@ func (m *MetricUnion) String() string {
 		return fmt.Sprintf("{type:%s,id:%s,value:%v}", m.Type, m.ID.String(), m.BatchTimerVal)
 	case metric.GaugeType:
 		return fmt.Sprintf("{type:%s,id:%s,value:%f}", m.Type, m.ID.String(), m.GaugeVal)
+	case metric.HistogramType:
+		return fmt.Sprintf("{type:%s,id:%s,bounds:%v,counts:%v,sum:%f}", m.Type, m.ID.String(),
+			m.HistogramBucketUpperBounds, m.HistogramBucketCounts, m.HistogramSum)
 	default:
 		return fmt.Sprintf(
 			"{type:%d,id:%s,counterVal:%d,batchTimerVal:%v,gaugeVal:%f}",
@@ -282,15 +395,45 @@ func (m *MetricUnion) Reset() { *m = emptyMetricUnion }
 
 // Counter returns the counter metric.
 func (m *MetricUnion) Counter() Counter {
-	return Counter{ID: m.ID, Value: m.CounterVal, Annotation: m.Annotation, ClientTimeNanos: m.ClientTimeNanos}
+	return Counter{
+		ID:              m.ID,
+		Value:           m.CounterVal,
+		Annotation:      m.Annotation,
+		Exemplars:       m.Exemplars,
+		ClientTimeNanos: m.ClientTimeNanos,
+	}
 }
 
 // BatchTimer returns the batch timer metric.
 func (m *MetricUnion) BatchTimer() BatchTimer {
-	return BatchTimer{ID: m.ID, Values: m.BatchTimerVal, Annotation: m.Annotation, ClientTimeNanos: m.ClientTimeNanos}
+	return BatchTimer{
+		ID:              m.ID,
+		Values:          m.BatchTimerVal,
+		Annotation:      m.Annotation,
+		Exemplars:       m.Exemplars,
+		ClientTimeNanos: m.ClientTimeNanos,
+	}
 }
 
 // Gauge returns the gauge metric.
 func (m *MetricUnion) Gauge() Gauge {
-	return Gauge{ID: m.ID, Value: m.GaugeVal, Annotation: m.Annotation, ClientTimeNanos: m.ClientTimeNanos}
+	return Gauge{
+		ID:              m.ID,
+		Value:           m.GaugeVal,
+		Annotation:      m.Annotation,
+		Exemplars:       m.Exemplars,
+		ClientTimeNanos: m.ClientTimeNanos,
+	}
+}
+
+// Histogram returns the histogram metric.
+func (m *MetricUnion) Histogram() Histogram {
+	return Histogram{
+		ID:                m.ID,
+		BucketUpperBounds: m.HistogramBucketUpperBounds,
+		BucketCounts:      m.HistogramBucketCounts,
+		Sum:               m.HistogramSum,
+		Annotation:        m.Annotation,
+		ClientTimeNanos:   m.ClientTimeNanos,
+	}
 }
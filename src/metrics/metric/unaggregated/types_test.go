@@ -69,6 +69,19 @@ var (
 		ID:       []byte("testGauge"),
 		GaugeVal: 45.28,
 	}
+	testHistogram = Histogram{
+		ID:                []byte("testHistogram"),
+		BucketUpperBounds: []float64{0.1, 1, 10},
+		BucketCounts:      []int64{3, 0, 7},
+		Sum:               58.2,
+	}
+	testHistogramUnion = MetricUnion{
+		Type:                       metric.HistogramType,
+		ID:                         []byte("testHistogram"),
+		HistogramBucketUpperBounds: []float64{0.1, 1, 10},
+		HistogramBucketCounts:      []int64{3, 0, 7},
+		HistogramSum:               58.2,
+	}
 	testMetadatas = metadata.StagedMetadatas{
 		{
 			CutoverNanos: 1234,
@@ -163,6 +176,10 @@ var (
 		Gauge:           testGauge,
 		StagedMetadatas: testMetadatas,
 	}
+	testHistogramWithMetadatas = HistogramWithMetadatas{
+		Histogram:       testHistogram,
+		StagedMetadatas: testMetadatas,
+	}
 	testCounterProto = metricpb.Counter{
 		Id:    []byte("testCounter"),
 		Value: 1234,
@@ -175,6 +192,12 @@ var (
 		Id:    []byte("testGauge"),
 		Value: 45.28,
 	}
+	testHistogramProto = metricpb.Histogram{
+		Id:                []byte("testHistogram"),
+		BucketUpperBounds: []float64{0.1, 1, 10},
+		BucketCounts:      []int64{3, 0, 7},
+		Sum:               58.2,
+	}
 	testMetadatasProto = metricpb.StagedMetadatas{
 		Metadatas: []metricpb.StagedMetadata{
 			{
@@ -300,6 +323,10 @@ var (
 		Gauge:     testGaugeProto,
 		Metadatas: testMetadatasProto,
 	}
+	testHistogramWithMetadatasProto = metricpb.HistogramWithMetadatas{
+		Histogram: testHistogramProto,
+		Metadatas: testMetadatasProto,
+	}
 )
 
 func TestCounterToUnion(t *testing.T) {
@@ -380,6 +407,38 @@ func TestGaugeRoundTrip(t *testing.T) {
 	require.Equal(t, testGauge, c)
 }
 
+func TestHistogramToUnion(t *testing.T) {
+	require.Equal(t, testHistogramUnion, testHistogram.ToUnion())
+	require.Equal(t, testHistogram, testHistogramUnion.Histogram())
+}
+
+func TestHistogramValidate(t *testing.T) {
+	require.NoError(t, testHistogram.Validate())
+
+	h := testHistogram
+	h.BucketCounts = []int64{1}
+	require.Equal(t, errHistogramBucketsMismatch, h.Validate())
+
+	h = testHistogram
+	h.BucketUpperBounds = []float64{1, 1, 10}
+	require.Equal(t, errHistogramBoundsNotAscending, h.Validate())
+
+	h = testHistogram
+	h.BucketCounts = []int64{1, -1, 1}
+	require.Equal(t, errHistogramNegativeBucketCount, h.Validate())
+}
+
+func TestHistogramRoundTrip(t *testing.T) {
+	var (
+		pb metricpb.Histogram
+		h  Histogram
+	)
+	testHistogram.ToProto(&pb)
+	require.Equal(t, testHistogramProto, pb)
+	h.FromProto(pb)
+	require.Equal(t, testHistogram, h)
+}
+
 func TestCounterWithMetadatasToProto(t *testing.T) {
 	var pb metricpb.CounterWithMetadatas
 	require.NoError(t, testCounterWithMetadatas.ToProto(&pb))
@@ -514,3 +573,19 @@ func TestGaugeWithMetadatasRoundTrip(t *testing.T) {
 	require.NoError(t, g.FromProto(&pb))
 	require.Equal(t, testGaugeWithMetadatas, g)
 }
+
+func TestHistogramWithMetadatasFromProtoNilProto(t *testing.T) {
+	var h HistogramWithMetadatas
+	require.Equal(t, errNilHistogramWithMetadatasProto, h.FromProto(nil))
+}
+
+func TestHistogramWithMetadatasRoundTrip(t *testing.T) {
+	var (
+		pb metricpb.HistogramWithMetadatas
+		h  HistogramWithMetadatas
+	)
+	require.NoError(t, testHistogramWithMetadatas.ToProto(&pb))
+	require.Equal(t, testHistogramWithMetadatasProto, pb)
+	require.NoError(t, h.FromProto(&pb))
+	require.Equal(t, testHistogramWithMetadatas, h)
+}
@@ -80,6 +80,11 @@ func newActiveRuleSet(
 	for _, rollupRule := range rollupRules {
 		for _, snapshot := range rollupRule.snapshots {
 			uniqueCutoverTimes[snapshot.cutoverNanos] = struct{}{}
+			// The end of a dual emit period is also a rule change since the
+			// targets of the previous snapshot then stop being emitted.
+			if snapshot.dualEmitUntilNanos > snapshot.cutoverNanos {
+				uniqueCutoverTimes[snapshot.dualEmitUntilNanos] = struct{}{}
+			}
 		}
 	}
 
@@ -318,8 +323,13 @@ func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResu
 
 		// Make sure the cutover time tracks the latest cutover time among all matching
 		// rollup rules to represent the correct time of rule change.
-		if cutoverNanos < snapshot.cutoverNanos {
-			cutoverNanos = snapshot.cutoverNanos
+		snapshotCutoverNanos := snapshot.cutoverNanos
+		if snapshot.dualEmitUntilNanos > snapshotCutoverNanos &&
+			snapshot.dualEmitUntilNanos <= timeNanos {
+			snapshotCutoverNanos = snapshot.dualEmitUntilNanos
+		}
+		if cutoverNanos < snapshotCutoverNanos {
+			cutoverNanos = snapshotCutoverNanos
 		}
 
 		if snapshot.keepOriginal {
@@ -336,6 +346,21 @@ func (as *activeRuleSet) rollupResultsFor(id []byte, timeNanos int64) rollupResu
 			rollupTargets = append(rollupTargets, target.clone())
 			tags = append(tags, snapshot.tags)
 		}
+
+		// While a rule is being migrated, the targets of the previous snapshot
+		// keep being emitted so consumers can move over to the new outputs.
+		prev := rollupRule.dualEmitSnapshot(timeNanos)
+		if prev == nil || !prev.filter.Matches(id) {
+			continue
+		}
+		for _, target := range prev.targets {
+			if snapshot.hasTarget(target, prev.tags) {
+				// Avoid emitting the same output twice.
+				continue
+			}
+			rollupTargets = append(rollupTargets, target.clone())
+			tags = append(tags, prev.tags)
+		}
 	}
 	// NB: could log the matching error here if needed.
 	res, _ := as.toRollupResults(id, cutoverNanos, rollupTargets, keepOriginal, tags)
@@ -676,7 +701,13 @@ func (as *activeRuleSet) reverseMappingsForRollupID(
 			continue
 		}
 
-		for _, target := range snapshot.targets {
+		targets := snapshot.targets
+		if prev := rollupRule.dualEmitSnapshot(timeNanos); prev != nil {
+			// Rollup IDs produced by the previous snapshot are still emitted.
+			targets = append(targets[:len(targets):len(targets)], prev.targets...)
+		}
+
+		for _, target := range targets {
 			for i := 0; i < target.Pipeline.Len(); i++ {
 				pipelineOp := target.Pipeline.At(i)
 				if pipelineOp.Type != mpipeline.RollupOpType {
@@ -3408,6 +3408,107 @@ func TestMatchedKeepOriginal(t *testing.T) {
 	}
 }
 
+func TestActiveRuleSetForwardMatchWithDualEmitRollupRule(t *testing.T) {
+	filter, err := filters.NewTagsFilter(
+		filters.TagFilterValueMap{
+			"foo": filters.FilterValue{Pattern: "bar"},
+		},
+		filters.Conjunction,
+		testTagsFilterOptions(),
+	)
+	require.NoError(t, err)
+
+	newTarget := func(name string) rollupTarget {
+		rr, err := pipeline.NewRollupOp(
+			pipeline.GroupByRollupType,
+			name,
+			[]string{"foo"},
+			aggregation.DefaultID,
+		)
+		require.NoError(t, err)
+		return rollupTarget{
+			Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+				{
+					Type:   pipeline.RollupOpType,
+					Rollup: rr,
+				},
+			}),
+			StoragePolicies: policy.StoragePolicies{
+				policy.NewStoragePolicy(10*time.Second, xtime.Second, 24*time.Hour),
+			},
+		}
+	}
+
+	rollups := []*rollupRule{
+		{
+			uuid: "rollup",
+			snapshots: []*rollupRuleSnapshot{
+				{
+					name:         "rollup.old",
+					cutoverNanos: 0,
+					filter:       filter,
+					targets:      []rollupTarget{newTarget("rollup.old")},
+				},
+				{
+					name:               "rollup.new",
+					cutoverNanos:       10000,
+					filter:             filter,
+					targets:            []rollupTarget{newTarget("rollup.new")},
+					dualEmitUntilNanos: 20000,
+				},
+			},
+		},
+	}
+	as := newActiveRuleSet(
+		0,
+		nil,
+		rollups,
+		testTagsFilterOptions(),
+		mockNewID,
+		func([]byte, []byte) bool { return true },
+	)
+	require.Equal(t, []int64{0, 10000, 20000}, as.cutoverTimesAsc)
+
+	cases := []struct {
+		name          string
+		timeNanos     int64
+		expectedIDs   []string
+		expireAtNanos int64
+	}{
+		{
+			name:          "before-migration",
+			timeNanos:     5000,
+			expectedIDs:   []string{"rollup.old|foo=bar"},
+			expireAtNanos: 10000,
+		},
+		{
+			name:          "during-migration",
+			timeNanos:     15000,
+			expectedIDs:   []string{"rollup.new|foo=bar", "rollup.old|foo=bar"},
+			expireAtNanos: 20000,
+		},
+		{
+			name:          "after-migration",
+			timeNanos:     25000,
+			expectedIDs:   []string{"rollup.new|foo=bar"},
+			expireAtNanos: timeNanosMax,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			res := as.ForwardMatch(b("foo=bar"), tt.timeNanos, tt.timeNanos+1)
+			require.Equal(t, tt.expireAtNanos, res.ExpireAtNanos())
+
+			ids := make([]string, 0, res.NumNewRollupIDs())
+			for i := 0; i < res.NumNewRollupIDs(); i++ {
+				ids = append(ids, string(res.ForNewRollupIDsAt(i, tt.timeNanos).ID))
+			}
+			require.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
 func testMappingRules(t *testing.T) []*mappingRule {
 	filter1, err := filters.NewTagsFilter(
 		filters.TagFilterValueMap{"mtagName1": filters.FilterValue{Pattern: "mtagValue1"}},
@@ -39,6 +39,8 @@ var (
 	errRollupRuleSnapshotIndexOutOfRange   = errors.New("rollup rule snapshot index out of range")
 	errNilRollupRuleSnapshotProto          = errors.New("nil rollup rule snapshot proto")
 	errNilRollupRuleProto                  = errors.New("nil rollup rule proto")
+	errDualEmitWithoutPreviousTargets      = errors.New("dual emit requires a previous non-tombstoned rollup rule snapshot")
+	errDualEmitBeforeCutover               = errors.New("dual emit until time must be after the cutover time")
 )
 
 // rollupRuleSnapshot defines a rule snapshot such that if a metric matches the
@@ -54,6 +56,7 @@ type rollupRuleSnapshot struct {
 	lastUpdatedBy      string
 	keepOriginal       bool
 	tags               []models.Tag
+	dualEmitUntilNanos int64
 }
 
 func newRollupRuleSnapshotFromProto(
@@ -108,6 +111,7 @@ func newRollupRuleSnapshotFromProto(
 		r.LastUpdatedBy,
 		r.KeepOriginal,
 		models.TagsFromProto(r.Tags),
+		r.DualEmitUntilNanos,
 	), nil
 }
 
@@ -136,6 +140,7 @@ func newRollupRuleSnapshotFromFields(
 		lastUpdatedBy,
 		keepOriginal,
 		tags,
+		0,
 	), nil
 }
 
@@ -152,6 +157,7 @@ func newRollupRuleSnapshotFromFieldsInternal(
 	lastUpdatedBy string,
 	keepOriginal bool,
 	tags []models.Tag,
+	dualEmitUntilNanos int64,
 ) *rollupRuleSnapshot {
 	return &rollupRuleSnapshot{
 		name:               name,
@@ -164,6 +170,7 @@ func newRollupRuleSnapshotFromFieldsInternal(
 		lastUpdatedBy:      lastUpdatedBy,
 		keepOriginal:       keepOriginal,
 		tags:               tags,
+		dualEmitUntilNanos: dualEmitUntilNanos,
 	}
 }
 
@@ -189,9 +196,29 @@ func (rrs *rollupRuleSnapshot) clone() rollupRuleSnapshot {
 		lastUpdatedBy:      rrs.lastUpdatedBy,
 		keepOriginal:       rrs.keepOriginal,
 		tags:               tags,
+		dualEmitUntilNanos: rrs.dualEmitUntilNanos,
 	}
 }
 
+// hasTarget returns whether the snapshot produces the same output as the
+// given rollup target with the given rollup tags.
+func (rrs *rollupRuleSnapshot) hasTarget(target rollupTarget, tags []models.Tag) bool {
+	if len(rrs.tags) != len(tags) {
+		return false
+	}
+	for i := range tags {
+		if !rrs.tags[i].Equal(tags[i]) {
+			return false
+		}
+	}
+	for _, t := range rrs.targets {
+		if t.Pipeline.Equal(target.Pipeline) && t.StoragePolicies.Equal(target.StoragePolicies) {
+			return true
+		}
+	}
+	return false
+}
+
 // proto returns the given MappingRuleSnapshot in protobuf form.
 func (rrs *rollupRuleSnapshot) proto() (*rulepb.RollupRuleSnapshot, error) {
 	tags := make([]*metricpb.Tag, 0, len(rrs.tags))
@@ -207,6 +234,7 @@ func (rrs *rollupRuleSnapshot) proto() (*rulepb.RollupRuleSnapshot, error) {
 		LastUpdatedBy:      rrs.lastUpdatedBy,
 		KeepOriginal:       rrs.keepOriginal,
 		Tags:               tags,
+		DualEmitUntilNanos: rrs.dualEmitUntilNanos,
 	}
 
 	targets := make([]*rulepb.RollupTargetV2, len(rrs.targets))
@@ -299,11 +327,37 @@ func (rc *rollupRule) activeRule(timeNanos int64) *rollupRule {
 	if idx < 0 {
 		return rc
 	}
+	if dualEmitIdx := rc.dualEmitIndex(timeNanos); dualEmitIdx >= 0 {
+		// Retain the previous snapshot while its targets are still emitted.
+		idx = dualEmitIdx
+	}
 	return &rollupRule{
 		uuid:      rc.uuid,
 		snapshots: rc.snapshots[idx:]}
 }
 
+// dualEmitSnapshot returns the snapshot preceding the active snapshot at time
+// timeNanos if its rollup targets are still being emitted alongside those of
+// the active snapshot as part of a rule migration, or nil otherwise.
+func (rc *rollupRule) dualEmitSnapshot(timeNanos int64) *rollupRuleSnapshot {
+	idx := rc.dualEmitIndex(timeNanos)
+	if idx < 0 {
+		return nil
+	}
+	return rc.snapshots[idx]
+}
+
+func (rc *rollupRule) dualEmitIndex(timeNanos int64) int {
+	idx := rc.activeIndex(timeNanos)
+	if idx <= 0 || rc.snapshots[idx].dualEmitUntilNanos <= timeNanos {
+		return -1
+	}
+	if prev := rc.snapshots[idx-1]; prev.tombstoned {
+		return -1
+	}
+	return idx - 1
+}
+
 func (rc *rollupRule) activeIndex(timeNanos int64) int {
 	idx := len(rc.snapshots) - 1
 	for idx >= 0 && rc.snapshots[idx].cutoverNanos > timeNanos {
@@ -336,7 +390,16 @@ func (rc *rollupRule) addSnapshot(
 	meta UpdateMetadata,
 	keepOriginal bool,
 	tags []models.Tag,
+	dualEmitUntilNanos int64,
 ) error {
+	if dualEmitUntilNanos != 0 {
+		if dualEmitUntilNanos <= meta.cutoverNanos {
+			return merrors.NewInvalidInputError(errDualEmitBeforeCutover.Error())
+		}
+		if len(rc.snapshots) == 0 || rc.tombstoned() {
+			return merrors.NewInvalidInputError(errDualEmitWithoutPreviousTargets.Error())
+		}
+	}
 	snapshot, err := newRollupRuleSnapshotFromFields(
 		name,
 		meta.cutoverNanos,
@@ -351,6 +414,7 @@ func (rc *rollupRule) addSnapshot(
 	if err != nil {
 		return err
 	}
+	snapshot.dualEmitUntilNanos = dualEmitUntilNanos
 	rc.snapshots = append(rc.snapshots, snapshot)
 	return nil
 }
@@ -376,6 +440,7 @@ func (rc *rollupRule) markTombstoned(meta UpdateMetadata) error {
 	snapshot.lastUpdatedBy = meta.updatedBy
 	snapshot.targets = nil
 	snapshot.keepOriginal = false
+	snapshot.dualEmitUntilNanos = 0
 	rc.snapshots = append(rc.snapshots, &snapshot)
 	return nil
 }
@@ -387,6 +452,7 @@ func (rc *rollupRule) revive(
 	meta UpdateMetadata,
 	keepOriginal bool,
 	tags []models.Tag,
+	dualEmitUntilNanos int64,
 ) error {
 	n, err := rc.name()
 	if err != nil {
@@ -395,7 +461,7 @@ func (rc *rollupRule) revive(
 	if !rc.tombstoned() {
 		return merrors.NewInvalidInputError(fmt.Sprintf("%s is not tombstoned", n))
 	}
-	return rc.addSnapshot(name, rawFilter, targets, meta, keepOriginal, tags)
+	return rc.addSnapshot(name, rawFilter, targets, meta, keepOriginal, tags, dualEmitUntilNanos)
 }
 
 func (rc *rollupRule) history() ([]view.RollupRule, error) {
@@ -434,5 +500,6 @@ func (rc *rollupRule) rollupRuleView(snapshotIdx int) (view.RollupRule, error) {
 		LastUpdatedAtMillis: rrs.lastUpdatedAtNanos / nanosPerMilli,
 		KeepOriginal:        rrs.keepOriginal,
 		Tags:                rrs.tags,
+		DualEmitUntilMillis: rrs.dualEmitUntilNanos / nanosPerMilli,
 	}, nil
 }
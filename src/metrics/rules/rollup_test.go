@@ -934,3 +934,69 @@ func TestNewRollupRuleHistory(t *testing.T) {
 	}
 	require.Equal(t, expected, history)
 }
+
+func TestRollupRuleDualEmit(t *testing.T) {
+	proto := &rulepb.RollupRule{
+		Uuid: "12669817-13ae-40e6-ba2f-33087b262c68",
+		Snapshots: []*rulepb.RollupRuleSnapshot{
+			testRollupRuleSnapshot3V2Proto,
+		},
+	}
+	rr, err := newRollupRuleFromProto(proto, testTagsFilterOptions())
+	require.NoError(t, err)
+
+	var (
+		cutoverNanos       = int64(20000000000)
+		dualEmitUntilNanos = int64(30000000000)
+		meta               = UpdateMetadata{
+			cutoverNanos:   cutoverNanos,
+			updatedAtNanos: 10000,
+			updatedBy:      "john",
+		}
+		newTargets = testRollupRuleSnapshot4.clone().targets
+	)
+	require.NoError(t, rr.addSnapshot("bar", "tag1:value1", newTargets, meta,
+		false, nil, dualEmitUntilNanos))
+	require.Equal(t, 2, len(rr.snapshots))
+	require.Equal(t, dualEmitUntilNanos, rr.snapshots[1].dualEmitUntilNanos)
+
+	require.Nil(t, rr.dualEmitSnapshot(cutoverNanos-1))
+	require.Equal(t, rr.snapshots[0], rr.dualEmitSnapshot(cutoverNanos))
+	require.Nil(t, rr.dualEmitSnapshot(dualEmitUntilNanos))
+
+	// The previous snapshot is retained by the active rule while dual emitting.
+	require.Equal(t, rr.snapshots, rr.activeRule(cutoverNanos).snapshots)
+	require.Equal(t, rr.snapshots[1:], rr.activeRule(dualEmitUntilNanos).snapshots)
+
+	rrv, err := rr.rollupRuleView(1)
+	require.NoError(t, err)
+	require.Equal(t, dualEmitUntilNanos/nanosPerMilli, rrv.DualEmitUntilMillis)
+
+	pb, err := rr.proto()
+	require.NoError(t, err)
+	data, err := pb.Marshal()
+	require.NoError(t, err)
+	var decoded rulepb.RollupRule
+	require.NoError(t, decoded.Unmarshal(data))
+	require.Equal(t, dualEmitUntilNanos, decoded.Snapshots[1].DualEmitUntilNanos)
+
+	require.NoError(t, rr.markTombstoned(UpdateMetadata{cutoverNanos: dualEmitUntilNanos}))
+	require.Equal(t, int64(0), rr.snapshots[2].dualEmitUntilNanos)
+}
+
+func TestRollupRuleAddSnapshotDualEmitInvalid(t *testing.T) {
+	meta := UpdateMetadata{cutoverNanos: 20000000000}
+	targets := testRollupRuleSnapshot3.clone().targets
+
+	rr := newEmptyRollupRule()
+	err := rr.addSnapshot("foo", "tag1:value1", targets, meta, false, nil, 30000000000)
+	require.Error(t, err)
+	_, ok := err.(errors.InvalidInputError)
+	require.True(t, ok)
+
+	require.NoError(t, rr.addSnapshot("foo", "tag1:value1", targets, meta, false, nil, 0))
+	err = rr.addSnapshot("foo", "tag1:value1", targets, meta, false, nil, meta.cutoverNanos)
+	require.Error(t, err)
+	_, ok = err.(errors.InvalidInputError)
+	require.True(t, ok)
+}
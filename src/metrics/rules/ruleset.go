@@ -425,6 +425,7 @@ func (rs *ruleSet) AddRollupRule(rrv view.RollupRule, meta UpdateMetadata) (stri
 			meta,
 			rrv.KeepOriginal,
 			rrv.Tags,
+			rrv.DualEmitUntilMillis*nanosPerMilli,
 		); err != nil {
 			return "", xerrors.Wrap(err, fmt.Sprintf(ruleActionErrorFmt, "add", rrv.Name))
 		}
@@ -437,6 +438,7 @@ func (rs *ruleSet) AddRollupRule(rrv view.RollupRule, meta UpdateMetadata) (stri
 			meta,
 			rrv.KeepOriginal,
 			rrv.Tags,
+			rrv.DualEmitUntilMillis*nanosPerMilli,
 		); err != nil {
 			return "", xerrors.Wrap(err, fmt.Sprintf(ruleActionErrorFmt, "revive", rrv.Name))
 		}
@@ -458,6 +460,7 @@ func (rs *ruleSet) UpdateRollupRule(rrv view.RollupRule, meta UpdateMetadata) er
 		meta,
 		rrv.KeepOriginal,
 		rrv.Tags,
+		rrv.DualEmitUntilMillis*nanosPerMilli,
 	); err != nil {
 		return xerrors.Wrap(err, fmt.Sprintf(ruleActionErrorFmt, "update", rrv.Name))
 	}
@@ -45,6 +45,7 @@ type Configuration struct {
 	RequiredRollupTags               []string                           `yaml:"requiredRollupTags"`
 	MaxTransformationDerivativeOrder *int                               `yaml:"maxTransformationDerivativeOrder"`
 	MaxRollupLevels                  *int                               `yaml:"maxRollupLevels"`
+	MaxForwardingLevels              int                                `yaml:"maxForwardingLevels"`
 	MetricTypes                      metricTypesValidationConfiguration `yaml:"metricTypes"`
 	Policies                         policiesValidationConfiguration    `yaml:"policies"`
 	TagNameInvalidChars              string                             `yaml:"tagNameInvalidChars"`
@@ -73,7 +74,8 @@ func (c Configuration) newValidatorOptions(
 		SetMetricTypesFn(c.MetricTypes.NewMetricTypesFn()).
 		SetTagNameInvalidChars(toRunes(c.TagNameInvalidChars)).
 		SetMetricNameInvalidChars(toRunes(c.MetricNameInvalidChars)).
-		SetFilterInvalidTagNames(c.FilterInvalidTagNames)
+		SetFilterInvalidTagNames(c.FilterInvalidTagNames).
+		SetMaxForwardingLevels(c.MaxForwardingLevels)
 	if c.MetricTypes.MultiAggregationTypesEnabledFor != nil {
 		opts = opts.SetMultiAggregationTypesEnabledFor(*c.MetricTypes.MultiAggregationTypesEnabledFor)
 	}
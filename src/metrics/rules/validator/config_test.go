@@ -94,6 +94,7 @@ requiredRollupTags:
   - tag2
 maxTransformationDerivativeOrder: 2
 maxRollupLevels: 1
+maxForwardingLevels: 2
 filterInvalidTagNames:
 - foobar
 metricTypes:
@@ -240,6 +241,7 @@ policies:
 	}
 
 	require.Error(t, opts.CheckFilterTagNameValid("foobar"))
+	require.Equal(t, 2, opts.MaxForwardingLevels())
 }
 
 func TestNamespaceValidatorConfigurationStatic(t *testing.T) {
@@ -105,6 +105,14 @@ type Options interface {
 	// MaxRollupLevels returns the maximum number of rollup operations supported in pipelines.
 	MaxRollupLevels() int
 
+	// SetMaxForwardingLevels sets the maximum number of times a metric may be forwarded
+	// between aggregators by a pipeline, with zero meaning no limit.
+	SetMaxForwardingLevels(value int) Options
+
+	// MaxForwardingLevels returns the maximum number of times a metric may be forwarded
+	// between aggregators by a pipeline, with zero meaning no limit.
+	MaxForwardingLevels() int
+
 	// SetTagNameInvalidChars sets the list of invalid chars for a tag name.
 	SetTagNameInvalidChars(value []rune) Options
 
@@ -159,6 +167,7 @@ type options struct {
 	requiredRollupTags                          []string
 	maxTransformationDerivativeOrder            int
 	maxRollupLevels                             int
+	maxForwardingLevels                         int
 	metricNameInvalidChars                      map[rune]struct{}
 	tagNameInvalidChars                         map[rune]struct{}
 	tagNameInvalidNames                         map[string]struct{}
@@ -264,6 +273,15 @@ func (o *options) MaxRollupLevels() int {
 	return o.maxRollupLevels
 }
 
+func (o *options) SetMaxForwardingLevels(value int) Options {
+	o.maxForwardingLevels = value
+	return o
+}
+
+func (o *options) MaxForwardingLevels() int {
+	return o.maxForwardingLevels
+}
+
 func (o *options) SetTagNameInvalidChars(values []rune) Options {
 	tagNameInvalidChars := make(map[rune]struct{}, len(values))
 	for _, v := range values {
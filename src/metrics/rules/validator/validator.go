@@ -285,6 +285,10 @@ func (v *validator) validateStoragePolicies(
 //   be no more than the maximum transformation derivative order that is supported.
 // * The pipeline must contain at least one rollup operation and at most `n` rollup operations,
 //   where `n` is the maximum supported number of rollup levels.
+// * The pipeline must forward metrics between aggregators at most `m` times, where `m` is
+//   the maximum supported number of forwarding levels if set. Every rollup operation forwards
+//   the metric to the aggregator owning the rollup ID except when it is the first operation,
+//   in which case the rollup ID is computed before the metric is first sent to an aggregator.
 func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric.Type) error {
 	if pipeline.IsEmpty() {
 		return errEmptyPipeline
@@ -344,6 +348,17 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 	if numRollupOps == 0 {
 		return errNoRollupOpInPipeline
 	}
+	if maxForwardingLevels := v.opts.MaxForwardingLevels(); maxForwardingLevels > 0 {
+		numForwardingLevels := numRollupOps
+		if pipeline.At(0).Type == mpipeline.RollupOpType {
+			numForwardingLevels--
+		}
+		if numForwardingLevels > maxForwardingLevels {
+			return fmt.Errorf("number of forwarding levels is %d higher than supported %d, "+
+				"reduce the number of rollup operations in the pipeline",
+				numForwardingLevels, maxForwardingLevels)
+		}
+	}
 	return nil
 }
 
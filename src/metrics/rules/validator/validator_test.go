@@ -959,6 +959,77 @@ func TestValidatorValidateRollupRulePipelineMultiLevelRollup(t *testing.T) {
 	require.NoError(t, validator.ValidateSnapshot(view))
 }
 
+func TestValidatorValidateRollupRulePipelineMaxForwardingLevels(t *testing.T) {
+	rr1, err := pipeline.NewRollupOp(
+		pipeline.GroupByRollupType,
+		"rName1",
+		[]string{"rtagName1", "rtagName2", "rtagName3"},
+		aggregation.DefaultID,
+	)
+	require.NoError(t, err)
+	rr2, err := pipeline.NewRollupOp(
+		pipeline.GroupByRollupType,
+		"rName2",
+		[]string{"rtagName1", "rtagName2"},
+		aggregation.DefaultID,
+	)
+	require.NoError(t, err)
+
+	newView := func(ops []pipeline.OpUnion) view.RuleSet {
+		return view.RuleSet{
+			RollupRules: []view.RollupRule{
+				{
+					Name:   "snapshot1",
+					Filter: testTypeTag + ":" + testCounterType,
+					Targets: []view.RollupTarget{
+						{
+							Pipeline:        pipeline.NewPipeline(ops),
+							StoragePolicies: testStoragePolicies(),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// The first rollup operation is applied before the metric is sent to an
+	// aggregator so it does not forward the metric.
+	rollupFirst := newView([]pipeline.OpUnion{
+		{
+			Type:   pipeline.RollupOpType,
+			Rollup: rr1,
+		},
+		{
+			Type:   pipeline.RollupOpType,
+			Rollup: rr2,
+		},
+	})
+	transformationFirst := newView([]pipeline.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.PerSecond},
+		},
+		{
+			Type:   pipeline.RollupOpType,
+			Rollup: rr1,
+		},
+		{
+			Type:   pipeline.RollupOpType,
+			Rollup: rr2,
+		},
+	})
+
+	opts := testValidatorOptions().SetMaxRollupLevels(2)
+	require.NoError(t, NewValidator(opts).ValidateSnapshot(rollupFirst))
+	require.NoError(t, NewValidator(opts).ValidateSnapshot(transformationFirst))
+
+	opts = opts.SetMaxForwardingLevels(1)
+	require.NoError(t, NewValidator(opts).ValidateSnapshot(rollupFirst))
+	err = NewValidator(opts).ValidateSnapshot(transformationFirst)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "number of forwarding levels is 2 higher than supported 1"))
+}
+
 func TestValidatorValidateRollupRuleRollupOpDuplicateRollupTag(t *testing.T) {
 	rr1, err := pipeline.NewRollupOp(
 		pipeline.GroupByRollupType,
@@ -55,6 +55,10 @@ type RollupRule struct {
 	LastUpdatedAtMillis int64          `json:"lastUpdatedAtMillis"`
 	KeepOriginal        bool           `json:"keepOriginal"`
 	Tags                []models.Tag   `json:"tags"`
+	// DualEmitUntilMillis is the time until which the targets of the previous
+	// version of the rule keep being emitted alongside the updated targets,
+	// allowing consumers to migrate to a renamed or retagged rollup output.
+	DualEmitUntilMillis int64 `json:"dualEmitUntilMillis,omitempty"`
 }
 
 // Equal determines whether two rollup rules are equal.
@@ -77,6 +81,7 @@ func (r *RollupRule) Equal(other *RollupRule) bool {
 		r.Name == other.Name &&
 		r.Filter == other.Filter &&
 		r.KeepOriginal == other.KeepOriginal &&
+		r.DualEmitUntilMillis == other.DualEmitUntilMillis &&
 		rollupTargets(r.Targets).Equal(other.Targets)
 }
 
@@ -46,12 +46,14 @@ func transformPerSecond() BinaryTransform {
 // * It skips NaN values.
 // * It assumes the timestamps are monotonically increasing, and values are non-decreasing.
 //   If either of the two conditions is not met, an empty datapoint is returned.
+// * If counter reset detection is enabled, a decreasing value is treated as the counter
+//   having restarted from zero and the current value is used as the difference.
 func perSecond(prev, curr Datapoint, flags FeatureFlags) Datapoint {
 	if prev.TimeNanos >= curr.TimeNanos || math.IsNaN(prev.Value) || math.IsNaN(curr.Value) {
 		return emptyDatapoint
 	}
-	diff := curr.Value - prev.Value
-	if diff < 0 {
+	diff, ok := counterDiff(prev.Value, curr.Value, flags)
+	if !ok {
 		return emptyDatapoint
 	}
 	rate := diff * float64(nanosPerSecond) / float64(curr.TimeNanos-prev.TimeNanos)
@@ -68,7 +70,9 @@ func transformIncrease() BinaryTransform {
 // * It skips NaN values. If the previous value is a NaN value, it uses a previous value of 0.
 // * It assumes the timestamps are monotonically increasing, and values are non-decreasing.
 //   If either of the two conditions is not met, an empty datapoint is returned.
-func increase(prev, curr Datapoint, _ FeatureFlags) Datapoint {
+// * If counter reset detection is enabled, a decreasing value is treated as the counter
+//   having restarted from zero and the current value is used as the difference.
+func increase(prev, curr Datapoint, flags FeatureFlags) Datapoint {
 	if prev.TimeNanos >= curr.TimeNanos {
 		return emptyDatapoint
 	}
@@ -80,9 +84,24 @@ func increase(prev, curr Datapoint, _ FeatureFlags) Datapoint {
 		prev.Value = 0
 	}
 
-	diff := curr.Value - prev.Value
-	if diff < 0 {
+	diff, ok := counterDiff(prev.Value, curr.Value, flags)
+	if !ok {
 		return emptyDatapoint
 	}
 	return Datapoint{TimeNanos: curr.TimeNanos, Value: diff}
 }
+
+// counterDiff returns the difference between two consecutive counter values and
+// whether it is valid. A negative difference is only valid if counter reset
+// detection is enabled, in which case the counter is assumed to have restarted
+// from zero and the current value is the difference.
+func counterDiff(prev, curr float64, flags FeatureFlags) (float64, bool) {
+	diff := curr - prev
+	if diff >= 0 {
+		return diff, true
+	}
+	if !flags.CounterResetDetection || curr < 0 {
+		return 0, false
+	}
+	return curr, true
+}
@@ -79,3 +79,46 @@ func TestPerSecond(t *testing.T) {
 		}
 	}
 }
+
+func TestPerSecondWithCounterResetDetection(t *testing.T) {
+	flags := FeatureFlags{CounterResetDetection: true}
+
+	// A reset uses the value since the restart as the difference.
+	prev := Datapoint{TimeNanos: time.Unix(1230, 0).UnixNano(), Value: 30}
+	curr := Datapoint{TimeNanos: time.Unix(1240, 0).UnixNano(), Value: 5}
+	require.Equal(t, Datapoint{TimeNanos: curr.TimeNanos, Value: 0.5}, perSecond(prev, curr, flags))
+
+	// Non decreasing values are unaffected.
+	curr.Value = 40
+	require.Equal(t, Datapoint{TimeNanos: curr.TimeNanos, Value: 1}, perSecond(prev, curr, flags))
+
+	// Out of order datapoints are still dropped.
+	curr.TimeNanos = prev.TimeNanos
+	require.True(t, perSecond(prev, curr, flags).IsEmpty())
+}
+
+func TestIncrease(t *testing.T) {
+	prev := Datapoint{TimeNanos: time.Unix(1230, 0).UnixNano(), Value: 25}
+	curr := Datapoint{TimeNanos: time.Unix(1240, 0).UnixNano(), Value: 30}
+	require.Equal(t, Datapoint{TimeNanos: curr.TimeNanos, Value: 5}, increase(prev, curr, FeatureFlags{}))
+
+	// A NaN previous value is treated as zero.
+	prev.Value = math.NaN()
+	require.Equal(t, Datapoint{TimeNanos: curr.TimeNanos, Value: 30}, increase(prev, curr, FeatureFlags{}))
+
+	// A decreasing value is dropped without counter reset detection.
+	prev.Value = 40
+	require.True(t, increase(prev, curr, FeatureFlags{}).IsEmpty())
+}
+
+func TestIncreaseWithCounterResetDetection(t *testing.T) {
+	flags := FeatureFlags{CounterResetDetection: true}
+
+	prev := Datapoint{TimeNanos: time.Unix(1230, 0).UnixNano(), Value: 1000}
+	curr := Datapoint{TimeNanos: time.Unix(1240, 0).UnixNano(), Value: 7}
+	require.Equal(t, Datapoint{TimeNanos: curr.TimeNanos, Value: 7}, increase(prev, curr, flags))
+
+	// Negative values are not counters and are still dropped.
+	curr.Value = -1
+	require.True(t, increase(prev, curr, flags).IsEmpty())
+}
@@ -58,6 +58,10 @@ func (fn UnaryTransformFn) Evaluate(dp Datapoint) Datapoint {
 // the aggregator configuration file.
 // nolint:gofumpt
 type FeatureFlags struct {
+	// CounterResetDetection makes transformations computing the difference
+	// between consecutive datapoints treat a decreasing value as a counter
+	// reset, i.e. the counter restarted from zero, rather than dropping it.
+	CounterResetDetection bool
 }
 
 // BinaryTransform is a binary transformation that takes the
@@ -263,10 +263,15 @@ func (m *message) ShardID() uint64 {
 	return m.Metadata.Shard
 }
 
+func (m *message) TraceContext() []byte {
+	return m.Message.TraceContext
+}
+
 func resetProto(m *msgpb.Message) {
 	m.Metadata.Id = 0
 	m.Metadata.Shard = 0
 	m.Value = m.Value[:0]
+	m.TraceContext = m.TraceContext[:0]
 }
 
 type connWithTimeout struct {
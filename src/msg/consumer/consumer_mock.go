@@ -93,6 +93,20 @@ func (mr *MockMessageMockRecorder) ShardID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShardID", reflect.TypeOf((*MockMessage)(nil).ShardID))
 }
 
+// TraceContext mocks base method.
+func (m *MockMessage) TraceContext() []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceContext")
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// TraceContext indicates an expected call of TraceContext.
+func (mr *MockMessageMockRecorder) TraceContext() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceContext", reflect.TypeOf((*MockMessage)(nil).TraceContext))
+}
+
 // MockMessageProcessor is a mock of MessageProcessor interface.
 type MockMessageProcessor struct {
 	ctrl     *gomock.Controller
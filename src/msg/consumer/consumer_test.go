@@ -100,6 +100,44 @@ func TestConsumerWithMessagePool(t *testing.T) {
 	require.NotEqual(t, m2, m3)
 }
 
+func TestConsumerMessageTraceContext(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	opts := testOptions()
+	l, err := NewListener("127.0.0.1:0", opts)
+	require.NoError(t, err)
+	defer l.Close()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+
+	c, err := l.Accept()
+	require.NoError(t, err)
+	defer c.Close()
+
+	tracedMsg := testMsg1
+	tracedMsg.TraceContext = []byte("traceid=123")
+	err = produce(conn, &tracedMsg)
+	require.NoError(t, err)
+
+	m1, err := c.Message()
+	require.NoError(t, err)
+	require.Equal(t, tracedMsg.Value, m1.Bytes())
+	require.Equal(t, tracedMsg.TraceContext, m1.TraceContext())
+
+	// Acking m1 making it available for reuse, the trace context must not
+	// leak into the next message.
+	m1.Ack()
+
+	err = produce(conn, &testMsg2)
+	require.NoError(t, err)
+
+	m2, err := c.Message()
+	require.NoError(t, err)
+	require.Equal(t, testMsg2.Value, m2.Bytes())
+	require.Empty(t, m2.TraceContext())
+}
+
 func TestConsumerAckReusedMessage(t *testing.T) {
 	defer leaktest.Check(t)()
 
@@ -38,6 +38,10 @@ type Message interface {
 
 	// ShardID returns shard ID of the Message.
 	ShardID() uint64
+
+	// TraceContext returns the serialized trace context propagated by the
+	// producer of the Message, or nil if the Message is not traced.
+	TraceContext() []byte
 }
 
 // Consumer receives messages from a connection.
@@ -77,8 +77,9 @@ func (m *Metadata) GetId() uint64 {
 }
 
 type Message struct {
-	Metadata Metadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata"`
-	Value    []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Metadata     Metadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata"`
+	Value        []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	TraceContext []byte   `protobuf:"bytes,3,opt,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty"`
 }
 
 func (m *Message) Reset()                    { *m = Message{} }
@@ -100,6 +101,13 @@ func (m *Message) GetValue() []byte {
 	return nil
 }
 
+func (m *Message) GetTraceContext() []byte {
+	if m != nil {
+		return m.TraceContext
+	}
+	return nil
+}
+
 type Ack struct {
 	Metadata []Metadata `protobuf:"bytes,1,rep,name=metadata" json:"metadata"`
 }
@@ -178,6 +186,12 @@ func (m *Message) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMsg(dAtA, i, uint64(len(m.Value)))
 		i += copy(dAtA[i:], m.Value)
 	}
+	if len(m.TraceContext) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.TraceContext)))
+		i += copy(dAtA[i:], m.TraceContext)
+	}
 	return i, nil
 }
 
@@ -241,6 +255,10 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMsg(uint64(l))
 	}
+	l = len(m.TraceContext)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
 	return n
 }
 
@@ -447,6 +465,37 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				m.Value = []byte{}
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceContext", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceContext = append(m.TraceContext[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceContext == nil {
+				m.TraceContext = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMsg(dAtA[iNdEx:])
@@ -659,7 +708,7 @@ func init() {
 }
 
 var fileDescriptorMsg = []byte{
-	// 232 bytes of a gzipped FileDescriptorProto
+	// 256 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4a, 0xcf, 0x2c, 0xc9,
 	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
 	0x2e, 0x4a, 0xd6, 0xcf, 0x2d, 0x4e, 0xd7, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d, 0x4a, 0x2c, 0x49, 0x4d,
@@ -668,11 +717,12 @@ var fileDescriptorMsg = []byte{
 	0x98, 0x07, 0xd1, 0x0a, 0x62, 0x41, 0x74, 0x29, 0x19, 0x70, 0x71, 0xf8, 0xa6, 0x96, 0x24, 0xa6,
 	0x24, 0x96, 0x24, 0x0a, 0x89, 0x70, 0xb1, 0x16, 0x67, 0x24, 0x16, 0xa5, 0x48, 0x30, 0x2a, 0x30,
 	0x6a, 0xb0, 0x04, 0x41, 0x38, 0x42, 0x7c, 0x5c, 0x4c, 0x99, 0x29, 0x12, 0x4c, 0x60, 0x21, 0xa6,
-	0xcc, 0x14, 0xa5, 0x20, 0x2e, 0x76, 0xdf, 0xd4, 0xe2, 0xe2, 0xc4, 0xf4, 0x54, 0x21, 0x43, 0x2e,
+	0xcc, 0x14, 0xa5, 0x72, 0x2e, 0x76, 0xdf, 0xd4, 0xe2, 0xe2, 0xc4, 0xf4, 0x54, 0x21, 0x43, 0x2e,
 	0x8e, 0x5c, 0xa8, 0x66, 0xb0, 0x1e, 0x6e, 0x23, 0x7e, 0x3d, 0xb0, 0x2b, 0xf4, 0x60, 0x66, 0x3a,
-	0xb1, 0x9c, 0xb8, 0x27, 0xcf, 0x10, 0x04, 0x57, 0x06, 0xb2, 0xa3, 0x2c, 0x31, 0xa7, 0x34, 0x15,
-	0x6c, 0x20, 0x4f, 0x10, 0x84, 0xa3, 0x64, 0xc1, 0xc5, 0xec, 0x98, 0x9c, 0x8d, 0x66, 0x1e, 0x33,
-	0x11, 0xe6, 0x39, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72,
-	0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0x3d, 0x66, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff,
-	0x31, 0xd7, 0x86, 0x7e, 0x4c, 0x01, 0x00, 0x00,
+	0xb1, 0x9c, 0xb8, 0x27, 0xcf, 0x10, 0xc4, 0x91, 0x8b, 0x64, 0x47, 0x59, 0x62, 0x4e, 0x69, 0x2a,
+	0xd8, 0x40, 0x9e, 0x20, 0x08, 0x47, 0x48, 0x99, 0x8b, 0xb7, 0xa4, 0x28, 0x31, 0x39, 0x35, 0x3e,
+	0x39, 0x3f, 0xaf, 0x24, 0xb5, 0xa2, 0x44, 0x82, 0x19, 0x2c, 0xcb, 0x03, 0x16, 0x74, 0x86, 0x88,
+	0x29, 0x59, 0x70, 0x31, 0x3b, 0x26, 0x67, 0xa3, 0x59, 0xca, 0x4c, 0x84, 0xa5, 0x4e, 0x02, 0x27,
+	0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c,
+	0x49, 0x6c, 0x60, 0xdf, 0x1b, 0x03, 0x06, 0x00, 0xa8, 0x21, 0x96, 0x44, 0x71, 0x01, 0x00, 0x00,
 }
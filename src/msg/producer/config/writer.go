@@ -21,6 +21,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/m3db/m3/src/cluster/client"
@@ -34,6 +35,7 @@ import (
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/retry"
+	xtls "github.com/m3db/m3/src/x/tls"
 
 	"github.com/uber-go/tally"
 )
@@ -49,10 +51,16 @@ type ConnectionConfiguration struct {
 	FlushInterval   *time.Duration       `yaml:"flushInterval"`
 	WriteBufferSize *int                 `yaml:"writeBufferSize"`
 	ReadBufferSize  *int                 `yaml:"readBufferSize"`
+
+	// TLS configures encryption and client certificates for connections to
+	// consumers, connections are not encrypted when unset.
+	TLS *xtls.ClientConfiguration `yaml:"tls"`
 }
 
 // NewOptions creates connection options.
-func (c *ConnectionConfiguration) NewOptions(iOpts instrument.Options) writer.ConnectionOptions {
+func (c *ConnectionConfiguration) NewOptions(
+	iOpts instrument.Options,
+) (writer.ConnectionOptions, error) {
 	opts := writer.NewConnectionOptions()
 	if c.NumConnections != nil {
 		opts = opts.SetNumConnections(*c.NumConnections)
@@ -81,7 +89,14 @@ func (c *ConnectionConfiguration) NewOptions(iOpts instrument.Options) writer.Co
 	if c.ReadBufferSize != nil {
 		opts = opts.SetReadBufferSize(*c.ReadBufferSize)
 	}
-	return opts.SetInstrumentOptions(iOpts)
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS config: %w", err)
+		}
+		opts = opts.SetTLSConfig(tlsConfig)
+	}
+	return opts.SetInstrumentOptions(iOpts), nil
 }
 
 // WriterConfiguration configs the writer options.
@@ -179,7 +194,11 @@ func (c *WriterConfiguration) NewOptions(
 		opts = opts.SetDecoderOptions(c.Decoder.NewOptions(iOpts))
 	}
 	if c.Connection != nil {
-		opts = opts.SetConnectionOptions(c.Connection.NewOptions(iOpts))
+		connOpts, err := c.Connection.NewOptions(iOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetConnectionOptions(connOpts)
 	}
 
 	opts = opts.SetIgnoreCutoffCutover(c.IgnoreCutoffCutover)
@@ -29,6 +29,8 @@ import (
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/x/instrument"
 	xio "github.com/m3db/m3/src/x/io"
+	xtls "github.com/m3db/m3/src/x/tls"
+	"github.com/m3db/m3/src/x/tls/tlstest"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -52,7 +54,8 @@ readBufferSize: 200
 	var cfg ConnectionConfiguration
 	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
 
-	cOpts := cfg.NewOptions(instrument.NewOptions())
+	cOpts, err := cfg.NewOptions(instrument.NewOptions())
+	require.NoError(t, err)
 	require.Equal(t, 3*time.Second, cOpts.DialTimeout())
 	require.Equal(t, 2*time.Second, cOpts.WriteTimeout())
 	require.Equal(t, 20*time.Second, cOpts.KeepAlivePeriod())
@@ -62,6 +65,30 @@ readBufferSize: 200
 	require.Equal(t, 2*time.Second, cOpts.FlushInterval())
 	require.Equal(t, 100, cOpts.WriteBufferSize())
 	require.Equal(t, 200, cOpts.ReadBufferSize())
+	require.Nil(t, cOpts.TLSConfig())
+}
+
+func TestConnectionConfigurationTLS(t *testing.T) {
+	var (
+		ca         = tlstest.NewCA(t, t.TempDir())
+		clientCert = ca.IssueClient("coordinator")
+	)
+
+	cfg := ConnectionConfiguration{
+		TLS: &xtls.ClientConfiguration{
+			CertFile: clientCert.CertFile,
+			KeyFile:  clientCert.KeyFile,
+			CAFile:   ca.CertFile,
+		},
+	}
+	cOpts, err := cfg.NewOptions(instrument.NewOptions())
+	require.NoError(t, err)
+	require.NotNil(t, cOpts.TLSConfig())
+	require.Len(t, cOpts.TLSConfig().Certificates, 1)
+
+	cfg.TLS.CAFile = "non-existent.pem"
+	_, err = cfg.NewOptions(instrument.NewOptions())
+	require.Error(t, err)
 }
 
 func TestWriterConfiguration(t *testing.T) {
@@ -51,6 +51,18 @@ type Message interface {
 	Finalize(FinalizeReason)
 }
 
+// TracedMessage is a message that carries a serialized trace context, which
+// the producer propagates to consumers alongside the message so that the
+// message can be traced across services. Messages may optionally implement
+// this interface.
+type TracedMessage interface {
+	Message
+
+	// TraceContext returns the serialized trace context of the message,
+	// or nil if the message is not traced.
+	TraceContext() []byte
+}
+
 // CloseType decides how the producer should be closed.
 type CloseType int
 
@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/x/clock"
 	xio "github.com/m3db/m3/src/x/io"
 	"github.com/m3db/m3/src/x/retry"
+	xtls "github.com/m3db/m3/src/x/tls"
 
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
@@ -73,6 +74,7 @@ type consumerWriterMetrics struct {
 	connectError            tally.Counter
 	setKeepAliveError       tally.Counter
 	setKeepAlivePeriodError tally.Counter
+	tlsHandshakeError       tally.Counter
 }
 
 func newConsumerWriterMetrics(scope tally.Scope) consumerWriterMetrics {
@@ -88,6 +90,7 @@ func newConsumerWriterMetrics(scope tally.Scope) consumerWriterMetrics {
 		connectError:            scope.Counter("connect-error"),
 		setKeepAliveError:       scope.Counter("set-keep-alive-error"),
 		setKeepAlivePeriodError: scope.Counter("set-keep-alive-period-error"),
+		tlsHandshakeError:       scope.Counter("tls-handshake-error"),
 	}
 }
 
@@ -457,6 +460,15 @@ func (w *consumerWriterImpl) connectNoRetry(addr string) (io.ReadWriteCloser, er
 	if err = tcpConn.SetKeepAlive(true); err != nil {
 		w.m.setKeepAliveError.Inc(1)
 	}
+	if tlsConfig := w.connOpts.TLSConfig(); tlsConfig != nil {
+		tlsConn, err := xtls.Client(tcpConn, addr, tlsConfig, w.connOpts.DialTimeout())
+		if err != nil {
+			w.m.tlsHandshakeError.Inc(1)
+			tcpConn.Close() // nolint: errcheck
+			return nil, err
+		}
+		conn = tlsConn
+	}
 	keepAlivePeriod := w.connOpts.KeepAlivePeriod()
 	if keepAlivePeriod <= 0 {
 		return conn, nil
@@ -118,8 +118,12 @@ func (m *message) Marshaler() (proto.Marshaler, bool) {
 func (m *message) ToProto(pb *msgpb.Message) {
 	m.meta.ToProto(&pb.Metadata)
 	pb.Value = m.RefCountedMessage.Bytes()
+	if tm, ok := m.RefCountedMessage.Message.(producer.TracedMessage); ok {
+		pb.TraceContext = tm.TraceContext()
+	}
 }
 
 func (m *message) ResetProto(pb *msgpb.Message) {
 	pb.Value = nil
+	pb.TraceContext = nil
 }
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/msg/producer"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testTracedMessage struct {
+	producer.Message
+
+	traceContext []byte
+}
+
+func (m testTracedMessage) TraceContext() []byte {
+	return m.traceContext
+}
+
+func TestMessageTraceContext(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mm := producer.NewMockMessage(ctrl)
+	mm.EXPECT().Size().Return(3).AnyTimes()
+	mm.EXPECT().Bytes().Return([]byte("foo")).AnyTimes()
+
+	traced := testTracedMessage{Message: mm, traceContext: []byte("traceid=123")}
+	m := newMessage()
+	m.Set(metadata{shard: 1, id: 2},
+		producer.NewRefCountedMessage(traced, nil), 0)
+	require.Equal(t, []byte("foo"), m.pb.Value)
+	require.Equal(t, []byte("traceid=123"), m.pb.TraceContext)
+
+	m.Close()
+	require.Nil(t, m.pb.TraceContext)
+
+	// Messages that are not traced do not carry a trace context.
+	m.Set(metadata{shard: 1, id: 3},
+		producer.NewRefCountedMessage(mm, nil), 0)
+	require.Equal(t, []byte("foo"), m.pb.Value)
+	require.Nil(t, m.pb.TraceContext)
+}
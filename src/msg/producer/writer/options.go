@@ -21,6 +21,7 @@
 package writer
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/m3db/m3/src/cluster/placement"
@@ -108,6 +109,13 @@ type ConnectionOptions interface {
 	// SetReadBufferSize sets the buffer size for read.
 	SetReadBufferSize(value int) ConnectionOptions
 
+	// TLSConfig returns the TLS configuration, connections are not
+	// encrypted when nil.
+	TLSConfig() *tls.Config
+
+	// SetTLSConfig sets the TLS configuration.
+	SetTLSConfig(value *tls.Config) ConnectionOptions
+
 	// InstrumentOptions returns the instrument options.
 	InstrumentOptions() instrument.Options
 
@@ -125,6 +133,7 @@ type connectionOptions struct {
 	flushInterval   time.Duration
 	writeBufferSize int
 	readBufferSize  int
+	tlsConfig       *tls.Config
 	iOpts           instrument.Options
 }
 
@@ -234,6 +243,16 @@ func (opts *connectionOptions) SetReadBufferSize(value int) ConnectionOptions {
 	return &o
 }
 
+func (opts *connectionOptions) TLSConfig() *tls.Config {
+	return opts.tlsConfig
+}
+
+func (opts *connectionOptions) SetTLSConfig(value *tls.Config) ConnectionOptions {
+	o := *opts
+	o.tlsConfig = value
+	return &o
+}
+
 func (opts *connectionOptions) InstrumentOptions() instrument.Options {
 	return opts.iOpts
 }
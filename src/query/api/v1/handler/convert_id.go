@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/util/idconvert"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ConvertIDURL is the url to convert a series ID between Graphite paths,
+	// Prometheus label sets and the M3 ID schemes.
+	ConvertIDURL = "/api/v1/convert/id"
+
+	// ConvertIDHTTPMethod is the HTTP method used with this resource.
+	ConvertIDHTTPMethod = http.MethodGet
+
+	convertIDParam     = "id"
+	convertIDFromParam = "from"
+	convertIDToParam   = "to"
+)
+
+var (
+	errConvertIDRequired     = errors.New("id is required")
+	errConvertIDFromRequired = errors.New("from is required")
+)
+
+// ConvertIDHandler converts a series ID from one format to the others, to aid
+// migrating dashboards between query languages on top of the same stored data.
+type ConvertIDHandler struct {
+	tagOpts        models.TagOptions
+	instrumentOpts instrument.Options
+}
+
+// NewConvertIDHandler returns a new instance of handler.
+func NewConvertIDHandler(opts options.HandlerOptions) http.Handler {
+	return &ConvertIDHandler{
+		tagOpts:        opts.TagOptions(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+type convertIDResult struct {
+	From idconvert.Format  `json:"from"`
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+	// IDs are the series IDs keyed by format.
+	IDs map[idconvert.Format]string `json:"ids"`
+	// Errors explain why the series cannot be represented in a format,
+	// keyed by format.
+	Errors map[idconvert.Format]string `json:"errors,omitempty"`
+}
+
+func (h *ConvertIDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	id, from, to, err := h.parseRequest(r)
+	if err != nil {
+		logger.Error("unable to parse request", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	tags, err := idconvert.ToTags([]byte(id), from, h.tagOpts)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	result := convertIDResult{
+		From: from,
+		ID:   id,
+		Tags: make(map[string]string, tags.Len()),
+		IDs:  make(map[idconvert.Format]string, len(to)),
+	}
+	for _, tag := range tags.Tags {
+		result.Tags[string(tag.Name)] = string(tag.Value)
+	}
+	for _, format := range to {
+		converted, err := idconvert.FromTags(tags, format)
+		if err != nil {
+			if result.Errors == nil {
+				result.Errors = make(map[idconvert.Format]string)
+			}
+			result.Errors[format] = err.Error()
+			continue
+		}
+		result.IDs[format] = string(converted)
+	}
+
+	xhttp.WriteJSONResponse(w, result, logger)
+}
+
+func (h *ConvertIDHandler) parseRequest(
+	r *http.Request,
+) (string, idconvert.Format, []idconvert.Format, error) {
+	values := r.URL.Query()
+	id := values.Get(convertIDParam)
+	if id == "" {
+		return "", "", nil, xerrors.NewInvalidParamsError(errConvertIDRequired)
+	}
+
+	str := values.Get(convertIDFromParam)
+	if str == "" {
+		return "", "", nil, xerrors.NewInvalidParamsError(errConvertIDFromRequired)
+	}
+	from, err := idconvert.ParseFormat(str)
+	if err != nil {
+		return "", "", nil, xerrors.NewInvalidParamsError(err)
+	}
+
+	// Convert to every format unless specific formats are requested.
+	toValues := values[convertIDToParam]
+	if len(toValues) == 0 {
+		return id, from, idconvert.Formats, nil
+	}
+	to := make([]idconvert.Format, 0, len(toValues))
+	for _, str := range toValues {
+		format, err := idconvert.ParseFormat(str)
+		if err != nil {
+			return "", "", nil, xerrors.NewInvalidParamsError(err)
+		}
+		to = append(to, format)
+	}
+	return id, from, to, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/util/idconvert"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConvertIDHandler() http.Handler {
+	opts := options.EmptyHandlerOptions().
+		SetTagOptions(models.NewTagOptions())
+	return NewConvertIDHandler(opts)
+}
+
+func TestConvertIDHandler(t *testing.T) {
+	h := newTestConvertIDHandler()
+
+	query := url.Values{
+		convertIDParam:     []string{`http_requests{job="api"}`},
+		convertIDFromParam: []string{string(idconvert.Prometheus)},
+	}
+	req := httptest.NewRequest(ConvertIDHTTPMethod, ConvertIDURL+"?"+query.Encode(), nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result convertIDResult
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, convertIDResult{
+		From: idconvert.Prometheus,
+		ID:   `http_requests{job="api"}`,
+		Tags: map[string]string{
+			"__name__": "http_requests",
+			"job":      "api",
+		},
+		IDs: map[idconvert.Format]string{
+			idconvert.Prometheus:  `http_requests{job="api"}`,
+			idconvert.Quoted:      `{__name__="http_requests",job="api"}`,
+			idconvert.PrependMeta: "8,13,3,3!__name__http_requestsjobapi",
+		},
+		Errors: map[idconvert.Format]string{
+			idconvert.Graphite: "series has no graphite path tags and cannot be represented as a graphite path",
+		},
+	}, result)
+}
+
+func TestConvertIDHandlerTo(t *testing.T) {
+	h := newTestConvertIDHandler()
+
+	query := url.Values{
+		convertIDParam:     []string{"foo.bar"},
+		convertIDFromParam: []string{string(idconvert.Graphite)},
+		convertIDToParam:   []string{string(idconvert.Prometheus)},
+	}
+	req := httptest.NewRequest(ConvertIDHTTPMethod, ConvertIDURL+"?"+query.Encode(), nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result convertIDResult
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, map[idconvert.Format]string{
+		idconvert.Prometheus: `{__g0__="foo",__g1__="bar"}`,
+	}, result.IDs)
+	require.Empty(t, result.Errors)
+}
+
+func TestConvertIDHandlerInvalidRequests(t *testing.T) {
+	h := newTestConvertIDHandler()
+
+	for _, query := range []url.Values{
+		{convertIDFromParam: []string{string(idconvert.Graphite)}},
+		{convertIDParam: []string{"foo.bar"}},
+		{convertIDParam: []string{"foo.bar"}, convertIDFromParam: []string{"invalid"}},
+		{
+			convertIDParam:     []string{"foo.bar"},
+			convertIDFromParam: []string{string(idconvert.Graphite)},
+			convertIDToParam:   []string{"invalid"},
+		},
+		{convertIDParam: []string{"foo..bar"}, convertIDFromParam: []string{string(idconvert.Graphite)}},
+	} {
+		req := httptest.NewRequest(ConvertIDHTTPMethod, ConvertIDURL+"?"+query.Encode(), nil)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, query.Encode())
+	}
+}
@@ -185,6 +185,7 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -208,7 +209,8 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -361,6 +363,7 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -384,7 +387,8 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -490,6 +494,7 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -513,7 +518,8 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -624,6 +630,7 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -647,7 +654,8 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -888,6 +896,7 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -911,7 +920,8 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					},
 					"host2": {
 						"id": "host2",
@@ -925,7 +935,8 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -1045,6 +1056,7 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1068,7 +1080,8 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					},
 					"host2": {
 						"id": "host2",
@@ -1082,7 +1095,8 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
@@ -1132,8 +1146,10 @@ func TestClusterTypeMissingHostnames(t *testing.T) {
 	assert.Equal(t,
 		xtest.MustPrettyJSONMap(t,
 			xjson.Map{
-				"status": "error",
-				"error":  "missing required field",
+				"status":    "error",
+				"error":     "missing required field",
+				"code":      "invalid_params",
+				"retryable": false,
 			},
 		),
 		xtest.MustPrettyJSONString(t, string(body)))
@@ -1168,8 +1184,10 @@ func TestBadType(t *testing.T) {
 	assert.Equal(t,
 		xtest.MustPrettyJSONMap(t,
 			xjson.Map{
-				"status": "error",
-				"error":  "invalid database type",
+				"status":    "error",
+				"error":     "invalid database type",
+				"code":      "invalid_params",
+				"retryable": false,
 			},
 		),
 		xtest.MustPrettyJSONString(t, string(body)))
@@ -1264,6 +1282,7 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1278,7 +1297,8 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 										"resolutionNanos": "300000000000",
 										"downsampleOptions": {
 											"all": true
-										}
+										},
+										"fanoutDisabled": false
 									}
 								}
 							]
@@ -1306,6 +1326,7 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"futureWriteClampToleranceNanos": "0",
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1329,7 +1350,8 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"port": 9000,
 						"metadata": {
 							"debugPort": 0
-						}
+						},
+						"maintenance": false
 					}
 				},
 				"replicaFactor": 0,
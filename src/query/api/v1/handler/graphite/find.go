@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/api/v1/route"
@@ -58,12 +59,18 @@ type grahiteFindHandler struct {
 func NewFindHandler(opts options.HandlerOptions) http.Handler {
 	wrappedStore := graphitestorage.NewM3WrappedStorage(opts.Storage(),
 		opts.M3DBOptions(), opts.InstrumentOpts(), opts.GraphiteStorageOptions())
-	return &grahiteFindHandler{
+	handler := &grahiteFindHandler{
 		storage:             wrappedStore,
 		graphiteStorageOpts: opts.GraphiteStorageOptions(),
 		fetchOptionsBuilder: opts.GraphiteFindFetchOptionsBuilder(),
 		instrumentOpts:      opts.InstrumentOpts(),
 	}
+	var limits *config.RequestLimitsConfiguration
+	if carbon := opts.Config().Carbon; carbon != nil {
+		limits = carbon.RequestLimitsFind
+	}
+	return newRequestLimitedHandler("find", handler, limits,
+		opts.InstrumentOpts())
 }
 
 type nodeDescriptor struct {
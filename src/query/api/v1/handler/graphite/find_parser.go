@@ -62,7 +62,7 @@ func parseFindParamsToQueries(r *http.Request) (
 	query := r.FormValue("query")
 	if query == "" {
 		return nil, nil, "",
-			xerrors.NewInvalidParamsError(errors.ErrNoQueryFound)
+			xerrors.NewInvalidParamError("query", errors.ErrNoQueryFound)
 	}
 
 	now := time.Now()
@@ -83,7 +83,7 @@ func parseFindParamsToQueries(r *http.Request) (
 
 	if err != nil {
 		return nil, nil, "",
-			xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'from': %s", fromString))
+			xerrors.NewInvalidParamError("from", fmt.Errorf("invalid 'from': %s", fromString))
 	}
 
 	until, err := graphite.ParseTime(
@@ -94,13 +94,13 @@ func parseFindParamsToQueries(r *http.Request) (
 
 	if err != nil {
 		return nil, nil, "",
-			xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'until': %s", untilString))
+			xerrors.NewInvalidParamError("until", fmt.Errorf("invalid 'until': %s", untilString))
 	}
 
 	matchers, queryType, err := graphitestorage.TranslateQueryToMatchersWithTerminator(query)
 	if err != nil {
 		return nil, nil, "",
-			xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'query': %s", query))
+			xerrors.NewInvalidParamError("query", fmt.Errorf("invalid 'query': %s", query))
 	}
 
 	switch queryType {
@@ -142,7 +142,7 @@ func parseFindParamsToQueries(r *http.Request) (
 	// so this is a sanity check and unexpected in actual execution.
 	if len(matchers) < 2 {
 		return nil, nil, "",
-			xerrors.NewInvalidParamsError(fmt.Errorf("unable to parse 'query': %s", query))
+			xerrors.NewInvalidParamError("query", fmt.Errorf("unable to parse 'query': %s", query))
 	}
 
 	filter := [][]byte{matchers[len(matchers)-2].Name}
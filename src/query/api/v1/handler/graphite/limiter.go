@@ -0,0 +1,207 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+var (
+	errRequestRateLimited        = errors.New("request rate limit exceeded")
+	errRequestConcurrencyLimited = errors.New("request concurrency limit exceeded")
+)
+
+// requestLimitedHandler limits the rate and concurrency of requests served
+// by a Graphite endpoint. Each endpoint has its own pool so that storms of
+// requests from Graphite dashboards cannot starve the query capacity used
+// by other endpoints of the coordinator.
+type requestLimitedHandler struct {
+	handler http.Handler
+	slots   chan struct{}
+	maxWait time.Duration
+	bucket  *tokenBucket
+	nowFn   func() time.Time
+	metrics requestLimitedHandlerMetrics
+}
+
+type requestLimitedHandlerMetrics struct {
+	admitted           tally.Counter
+	rateLimited        tally.Counter
+	concurrencyLimited tally.Counter
+	inflight           tally.Gauge
+	wait               tally.Timer
+}
+
+func newRequestLimitedHandlerMetrics(
+	scope tally.Scope,
+) requestLimitedHandlerMetrics {
+	return requestLimitedHandlerMetrics{
+		admitted: scope.Counter("admitted"),
+		rateLimited: scope.Tagged(map[string]string{
+			"reason": "rate",
+		}).Counter("rejected"),
+		concurrencyLimited: scope.Tagged(map[string]string{
+			"reason": "concurrency",
+		}).Counter("rejected"),
+		inflight: scope.Gauge("inflight"),
+		wait:     scope.Timer("wait"),
+	}
+}
+
+// newRequestLimitedHandler wraps the handler with the request limits, if no
+// limits are configured the handler is returned as is.
+func newRequestLimitedHandler(
+	endpoint string,
+	handler http.Handler,
+	cfg *config.RequestLimitsConfiguration,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	if cfg == nil || (cfg.MaxConcurrent <= 0 && cfg.RequestsPerSecond <= 0) {
+		return handler
+	}
+
+	scope := instrumentOpts.MetricsScope().
+		SubScope("graphite-request-limits").
+		Tagged(map[string]string{"endpoint": endpoint})
+	h := &requestLimitedHandler{
+		handler: handler,
+		maxWait: cfg.MaxWait,
+		nowFn:   time.Now,
+		metrics: newRequestLimitedHandlerMetrics(scope),
+	}
+	if cfg.MaxConcurrent > 0 {
+		h.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	if cfg.RequestsPerSecond > 0 {
+		burst := float64(cfg.Burst)
+		if burst <= 0 {
+			burst = math.Max(1, cfg.RequestsPerSecond)
+		}
+		h.bucket = newTokenBucket(cfg.RequestsPerSecond, burst)
+	}
+	return h
+}
+
+func (h *requestLimitedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.bucket != nil && !h.bucket.take(h.nowFn()) {
+		h.metrics.rateLimited.Inc(1)
+		xhttp.WriteError(w, xhttp.NewError(errRequestRateLimited,
+			http.StatusTooManyRequests))
+		return
+	}
+
+	if h.slots != nil {
+		if !h.acquire(r.Context()) {
+			h.metrics.concurrencyLimited.Inc(1)
+			xhttp.WriteError(w, xhttp.NewError(errRequestConcurrencyLimited,
+				http.StatusTooManyRequests))
+			return
+		}
+		h.metrics.inflight.Update(float64(len(h.slots)))
+		defer func() {
+			<-h.slots
+			h.metrics.inflight.Update(float64(len(h.slots)))
+		}()
+	}
+
+	h.metrics.admitted.Inc(1)
+	h.handler.ServeHTTP(w, r)
+}
+
+// acquire takes a concurrency slot, waiting up to the max wait for one to
+// become available.
+func (h *requestLimitedHandler) acquire(ctx context.Context) bool {
+	select {
+	case h.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if h.maxWait <= 0 {
+		return false
+	}
+
+	start := h.nowFn()
+	defer func() {
+		h.metrics.wait.Record(h.nowFn().Sub(start))
+	}()
+
+	timer := time.NewTimer(h.maxWait)
+	defer timer.Stop()
+
+	select {
+	case h.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// tokenBucket is a token bucket refilled at a constant rate up to a burst.
+type tokenBucket struct {
+	sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+	}
+}
+
+// take takes a token from the bucket, returning false if none are left.
+func (b *tokenBucket) take(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.last.IsZero() && now.After(b.last) {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+func TestRequestLimitedHandlerNoLimits(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	iOpts := instrument.NewOptions()
+
+	h := newRequestLimitedHandler("find", handler, nil, iOpts)
+	_, ok := h.(*requestLimitedHandler)
+	assert.False(t, ok)
+
+	h = newRequestLimitedHandler("find", handler,
+		&config.RequestLimitsConfiguration{}, iOpts)
+	_, ok = h.(*requestLimitedHandler)
+	assert.False(t, ok)
+}
+
+func TestRequestLimitedHandlerRateLimit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := newRequestLimitedHandler("render", handler,
+		&config.RequestLimitsConfiguration{
+			RequestsPerSecond: 1,
+			Burst:             2,
+		}, instrument.NewOptions())
+	limited, ok := h.(*requestLimitedHandler)
+	require.True(t, ok)
+
+	now := time.Now()
+	limited.nowFn = func() time.Time { return now }
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReadURL, nil))
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, http.StatusTooManyRequests, serve())
+
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, http.StatusTooManyRequests, serve())
+}
+
+func TestRequestLimitedHandlerConcurrencyLimit(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+	h := newRequestLimitedHandler("find", handler,
+		&config.RequestLimitsConfiguration{
+			MaxConcurrent: 1,
+			MaxWait:       10 * time.Millisecond,
+		}, instrument.NewOptions())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, FindURL, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}()
+	<-started
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, FindURL, nil))
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	close(release)
+	wg.Wait()
+
+	// The slot is released once the first request completes.
+	go func() { <-started }()
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, FindURL, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
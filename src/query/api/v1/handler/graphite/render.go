@@ -29,6 +29,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/api/v1/route"
@@ -73,7 +74,7 @@ type respError struct {
 func NewRenderHandler(opts options.HandlerOptions) http.Handler {
 	wrappedStore := graphite.NewM3WrappedStorage(opts.Storage(),
 		opts.M3DBOptions(), opts.InstrumentOpts(), opts.GraphiteStorageOptions())
-	return &renderHandler{
+	handler := &renderHandler{
 		opts: opts,
 		engine: native.NewEngine(wrappedStore, native.CompileOptions{
 			EscapeAllNotOnlyQuotes: opts.GraphiteStorageOptions().CompileEscapeAllNotOnlyQuotes,
@@ -83,6 +84,12 @@ func NewRenderHandler(opts options.HandlerOptions) http.Handler {
 		graphiteOpts:        opts.GraphiteStorageOptions(),
 		instrumentOpts:      opts.InstrumentOpts(),
 	}
+	var limits *config.RequestLimitsConfiguration
+	if carbon := opts.Config().Carbon; carbon != nil {
+		limits = carbon.RequestLimitsRender
+	}
+	return newRequestLimitedHandler("render", handler, limits,
+		opts.InstrumentOpts())
 }
 
 func sendError(errorCh chan error, err error) {
@@ -48,7 +48,7 @@ const (
 )
 
 var (
-	errNoTarget           = xerrors.NewInvalidParamsError(errors.New("no 'target' specified"))
+	errNoTarget           = xerrors.NewInvalidParamError("target", errors.New("no 'target' specified"))
 	errFromNotBeforeUntil = xerrors.NewInvalidParamsError(errors.New("'from' must come before 'until'"))
 )
 
@@ -125,7 +125,7 @@ func ParseRenderRequest(
 		now,
 		tzOffsetForAbsoluteTime,
 	); err != nil {
-		return nil, p, nil, xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'from': %s", fromString))
+		return nil, p, nil, xerrors.NewInvalidParamError("from", fmt.Errorf("invalid 'from': %s", fromString))
 	}
 
 	if p.Until, err = graphite.ParseTime(
@@ -133,7 +133,7 @@ func ParseRenderRequest(
 		now,
 		tzOffsetForAbsoluteTime,
 	); err != nil {
-		return nil, p, nil, xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'until': %s", untilString))
+		return nil, p, nil, xerrors.NewInvalidParamError("until", fmt.Errorf("invalid 'until': %s", untilString))
 	}
 
 	if !p.From.Before(p.Until) {
@@ -170,7 +170,7 @@ func ParseRenderRequest(
 		p.MaxDataPoints, err = strconv.ParseInt(maxDataPointsString, 10, 64)
 
 		if err != nil || p.MaxDataPoints < 1 {
-			return nil, p, nil, xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'maxDataPoints': %s", maxDataPointsString))
+			return nil, p, nil, xerrors.NewInvalidParamError("maxDataPoints", fmt.Errorf("invalid 'maxDataPoints': %s", maxDataPointsString))
 		}
 	} else {
 		p.MaxDataPoints = math.MaxInt64
@@ -183,9 +183,9 @@ func ParseRenderRequest(
 		p.From,
 		tzOffsetForAbsoluteTime,
 	); err != nil && len(compareString) != 0 {
-		return nil, p, nil, xerrors.NewInvalidParamsError(fmt.Errorf("invalid 'compare': %s", compareString))
+		return nil, p, nil, xerrors.NewInvalidParamError("compare", fmt.Errorf("invalid 'compare': %s", compareString))
 	} else if p.From.Before(compareFrom) {
-		return nil, p, nil, xerrors.NewInvalidParamsError(fmt.Errorf("'compare' must be in the past"))
+		return nil, p, nil, xerrors.NewInvalidParamError("compare", fmt.Errorf("'compare' must be in the past"))
 	} else {
 		p.Compare = compareFrom.Sub(p.From)
 	}
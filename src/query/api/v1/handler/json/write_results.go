@@ -0,0 +1,295 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// WriteResultsJSONURL is the url for the write query results json handler.
+	WriteResultsJSONURL = route.Prefix + "/json/write_results"
+
+	// WriteResultsJSONHTTPMethod is the HTTP method used with this resource.
+	WriteResultsJSONHTTPMethod = http.MethodPost
+
+	defaultResultsAggregation = aggregation.Last
+)
+
+var (
+	errNoDownsampler         = errors.New("downsampler is not enabled")
+	errNoResultsSeries       = errors.New("no series to write")
+	errNoResultsPolicies     = errors.New("at least one storage policy must be set")
+	errNoResultsSeriesTags   = errors.New("series must have at least one tag")
+	errNoResultsSeriesPoints = errors.New("series must have at least one datapoint")
+)
+
+// WriteResultsJSONHandler writes the results of scheduled queries, such as
+// recording rules evaluated by an external scheduler, back through the
+// downsampler with explicit storage policies. The results are aggregated as
+// rollup rule outputs are so that the derived series are written to, and age
+// out of, the same aggregated namespaces as other aggregated data.
+type WriteResultsJSONHandler struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	tagOpts              models.TagOptions
+	instrumentOpts       instrument.Options
+}
+
+// NewWriteResultsJSONHandler returns a new instance of handler.
+func NewWriteResultsJSONHandler(opts options.HandlerOptions) http.Handler {
+	return &WriteResultsJSONHandler{
+		downsamplerAndWriter: opts.DownsamplerAndWriter(),
+		tagOpts:              opts.TagOptions(),
+		instrumentOpts:       opts.InstrumentOpts(),
+	}
+}
+
+// WriteResultsRequest is a request to write query results.
+type WriteResultsRequest struct {
+	// StoragePolicies are the storage policies to write the results with,
+	// e.g. "1m:40d".
+	StoragePolicies []string `json:"storagePolicies"`
+	// Aggregation is the aggregation applied to the results within each
+	// resolution window, defaults to Last.
+	Aggregation string `json:"aggregation"`
+	// Series are the results to write.
+	Series []WriteResultsSeries `json:"series"`
+}
+
+// WriteResultsSeries is a single series of query results.
+type WriteResultsSeries struct {
+	Tags       map[string]string       `json:"tags"`
+	Datapoints []WriteResultsDatapoint `json:"datapoints"`
+}
+
+// WriteResultsDatapoint is a single query result datapoint.
+type WriteResultsDatapoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+func (h *WriteResultsJSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	if h.downsamplerAndWriter == nil || h.downsamplerAndWriter.Downsampler() == nil ||
+		!h.downsamplerAndWriter.Downsampler().Enabled() {
+		xhttp.WriteError(w, errNoDownsampler)
+		return
+	}
+
+	req, err := parseWriteResultsRequest(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	writeOpts, err := newWriteResultsOptions(req)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	iter, err := newWriteResultsIter(req.Series, h.tagOpts)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	batchErr := h.downsamplerAndWriter.WriteBatch(r.Context(), iter, writeOpts)
+	if batchErr == nil {
+		return
+	}
+
+	err = batchErr
+	logger.Error("write results error",
+		zap.String("remoteAddr", r.RemoteAddr),
+		zap.Int("numErrors", len(batchErr.Errors())),
+		zap.Error(err))
+	for _, e := range batchErr.Errors() {
+		if xerrors.IsInvalidParams(e) {
+			err = xerrors.NewInvalidParamsError(err)
+			break
+		}
+	}
+	xhttp.WriteError(w, err)
+}
+
+// newWriteResultsOptions overrides the mapping rules with a single rule for
+// the requested storage policies, which results in the same staged metadata
+// as a rollup rule targeting those storage policies, and disables writing the
+// results unaggregated.
+func newWriteResultsOptions(req *WriteResultsRequest) (ingest.WriteOptions, error) {
+	if len(req.StoragePolicies) == 0 {
+		return ingest.WriteOptions{}, errNoResultsPolicies
+	}
+
+	policies := make(policy.StoragePolicies, 0, len(req.StoragePolicies))
+	for _, str := range req.StoragePolicies {
+		p, err := policy.ParseStoragePolicy(str)
+		if err != nil {
+			return ingest.WriteOptions{}, fmt.Errorf("could not parse storage policy: %v", err)
+		}
+		policies = append(policies, p)
+	}
+
+	aggType := defaultResultsAggregation
+	if req.Aggregation != "" {
+		parsed, err := aggregation.ParseType(req.Aggregation)
+		if err != nil {
+			return ingest.WriteOptions{}, err
+		}
+		aggType = parsed
+	}
+
+	return ingest.WriteOptions{
+		DownsampleOverride: true,
+		DownsampleMappingRules: []downsample.AutoMappingRule{
+			{
+				Aggregations: []aggregation.Type{aggType},
+				Policies:     policies,
+			},
+		},
+		// Override with no storage policies so nothing is written unaggregated.
+		WriteOverride: true,
+	}, nil
+}
+
+func parseWriteResultsRequest(r *http.Request) (*WriteResultsRequest, error) {
+	if r.Body == nil {
+		return nil, xerrors.NewInvalidParamsError(fmt.Errorf("empty request body"))
+	}
+	defer r.Body.Close()
+
+	js, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var req WriteResultsRequest
+	if err := json.Unmarshal(js, &req); err != nil {
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+	if len(req.Series) == 0 {
+		return nil, xerrors.NewInvalidParamsError(errNoResultsSeries)
+	}
+	return &req, nil
+}
+
+type writeResultsIter struct {
+	values    []ingest.IterValue
+	metadatas []ts.Metadata
+	idx       int
+}
+
+func newWriteResultsIter(
+	series []WriteResultsSeries,
+	tagOpts models.TagOptions,
+) (*writeResultsIter, error) {
+	values := make([]ingest.IterValue, 0, len(series))
+	for _, s := range series {
+		if len(s.Tags) == 0 {
+			return nil, errNoResultsSeriesTags
+		}
+		if len(s.Datapoints) == 0 {
+			return nil, errNoResultsSeriesPoints
+		}
+
+		tags := models.NewTags(len(s.Tags), tagOpts)
+		for n, v := range s.Tags {
+			tags = tags.AddTag(models.Tag{Name: []byte(n), Value: []byte(v)})
+		}
+
+		datapoints := make(ts.Datapoints, 0, len(s.Datapoints))
+		for _, dp := range s.Datapoints {
+			t, err := util.ParseTimeString(dp.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+			datapoints = append(datapoints, ts.Datapoint{
+				Timestamp: xtime.ToUnixNano(t),
+				Value:     dp.Value,
+			})
+		}
+
+		values = append(values, ingest.IterValue{
+			Tags:       tags,
+			Datapoints: datapoints,
+			Attributes: ts.SeriesAttributes{M3Type: ts.M3MetricTypeGauge},
+			Unit:       xtime.Millisecond,
+		})
+	}
+	return &writeResultsIter{
+		values:    values,
+		metadatas: make([]ts.Metadata, len(values)),
+		idx:       -1,
+	}, nil
+}
+
+func (i *writeResultsIter) Next() bool {
+	i.idx++
+	return i.idx < len(i.values)
+}
+
+func (i *writeResultsIter) Current() ingest.IterValue {
+	if i.idx < 0 || i.idx >= len(i.values) {
+		return ingest.IterValue{}
+	}
+	value := i.values[i.idx]
+	value.Metadata = i.metadatas[i.idx]
+	return value
+}
+
+func (i *writeResultsIter) Reset() error {
+	i.idx = -1
+	return nil
+}
+
+func (i *writeResultsIter) Error() error {
+	return nil
+}
+
+func (i *writeResultsIter) SetCurrentMetadata(metadata ts.Metadata) {
+	if i.idx < 0 || i.idx >= len(i.values) {
+		return
+	}
+	i.metadatas[i.idx] = metadata
+}
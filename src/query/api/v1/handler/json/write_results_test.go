@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWriteResultsHandler(
+	ctrl *gomock.Controller,
+) (http.Handler, *ingest.MockDownsamplerAndWriter) {
+	downsampler := downsample.NewMockDownsampler(ctrl)
+	downsampler.EXPECT().Enabled().Return(true).AnyTimes()
+	downsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	downsamplerAndWriter.EXPECT().Downsampler().Return(downsampler).AnyTimes()
+
+	opts := options.EmptyHandlerOptions().
+		SetTagOptions(models.NewTagOptions()).
+		SetDownsamplerAndWriter(downsamplerAndWriter)
+	return NewWriteResultsJSONHandler(opts), downsamplerAndWriter
+}
+
+func TestWriteResultsJSONHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, downsamplerAndWriter := newTestWriteResultsHandler(ctrl)
+
+	var (
+		values    []ingest.IterValue
+		writeOpts ingest.WriteOptions
+	)
+	downsamplerAndWriter.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			opts ingest.WriteOptions,
+		) ingest.BatchError {
+			for iter.Next() {
+				values = append(values, iter.Current())
+			}
+			writeOpts = opts
+			return nil
+		})
+
+	body := `{
+		"storagePolicies": ["1m:40d"],
+		"series": [
+			{
+				"tags": {"__name__": "job:requests:rate5m", "job": "api"},
+				"datapoints": [
+					{"timestamp": "1534952005", "value": 10.0},
+					{"timestamp": "1534952065", "value": 12.0}
+				]
+			}
+		]
+	}`
+	req := httptest.NewRequest(WriteResultsJSONHTTPMethod, WriteResultsJSONURL,
+		strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.Equal(t, ingest.WriteOptions{
+		DownsampleOverride: true,
+		DownsampleMappingRules: []downsample.AutoMappingRule{
+			{
+				Aggregations: []aggregation.Type{aggregation.Last},
+				Policies:     policy.StoragePolicies{policy.MustParseStoragePolicy("1m:40d")},
+			},
+		},
+		WriteOverride: true,
+	}, writeOpts)
+
+	require.Len(t, values, 1)
+	name, ok := values[0].Tags.Name()
+	require.True(t, ok)
+	require.Equal(t, "job:requests:rate5m", string(name))
+	require.Equal(t, ts.M3MetricTypeGauge, values[0].Attributes.M3Type)
+	require.Equal(t, ts.Datapoints{
+		{Timestamp: xtime.FromSeconds(1534952005), Value: 10},
+		{Timestamp: xtime.FromSeconds(1534952065), Value: 12},
+	}, values[0].Datapoints)
+}
+
+func TestWriteResultsJSONHandlerInvalidRequest(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, _ := newTestWriteResultsHandler(ctrl)
+
+	for _, body := range []string{
+		`{`,
+		`{"storagePolicies": ["1m:40d"]}`,
+		`{"series": [{"tags": {"a": "b"}, "datapoints": [{"timestamp": "1534952005", "value": 1}]}]}`,
+		`{"storagePolicies": ["foo"], "series": [{"tags": {"a": "b"}, "datapoints": [{"timestamp": "1534952005", "value": 1}]}]}`,
+		`{"storagePolicies": ["1m:40d"], "aggregation": "foo", "series": [{"tags": {"a": "b"}, "datapoints": [{"timestamp": "1534952005", "value": 1}]}]}`,
+		`{"storagePolicies": ["1m:40d"], "series": [{"tags": {}, "datapoints": [{"timestamp": "1534952005", "value": 1}]}]}`,
+		`{"storagePolicies": ["1m:40d"], "series": [{"tags": {"a": "b"}, "datapoints": []}]}`,
+	} {
+		req := httptest.NewRequest(WriteResultsJSONHTTPMethod, WriteResultsJSONURL,
+			strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, body)
+	}
+}
+
+func TestWriteResultsJSONHandlerNoDownsampler(t *testing.T) {
+	h := NewWriteResultsJSONHandler(options.EmptyHandlerOptions())
+
+	req := httptest.NewRequest(WriteResultsJSONHTTPMethod, WriteResultsJSONURL,
+		strings.NewReader(`{}`))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
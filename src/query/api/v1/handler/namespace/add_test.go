@@ -103,7 +103,7 @@ func TestNamespaceAddHandler(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.JSONEq(t,
-		`{"status":"error","error":"bad namespace metadata: retention options must be set"}`,
+		`{"status":"error","error":"bad namespace metadata: retention options must be set","code":"invalid_params","retryable":false}`,
 		string(body))
 
 	// Test good case. Note: there is no way to tell the difference between a boolean
@@ -148,10 +148,11 @@ func TestNamespaceAddHandler(t *testing.T) {
 							"enabled":        true,
 							"blockSizeNanos": "7200000000000",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
+						"runtimeOptions":                 nil,
+						"schemaOptions":                  nil,
+						"coldWritesEnabled":              false,
+						"futureWriteClampToleranceNanos": "0",
+						"extendedOptions":                xtest.NewTestExtendedOptionsJSON("foo"),
 					},
 				},
 			},
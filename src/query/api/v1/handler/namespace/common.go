@@ -193,6 +193,15 @@ func RegisterRoutes(
 		return err
 	}
 
+	// Truncate M3DB namespace data across all replicas.
+	if err := r.Register(queryhttp.RegisterOptions{
+		Path:    M3DBTruncateURL,
+		Handler: applyMiddleware(NewTruncateHandler(client, clusters, instrumentOpts).ServeHTTP, defaults),
+		Methods: []string{TruncateHTTPMethod},
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -57,7 +57,7 @@ func TestNamespaceDeleteHandlerNotFound(t *testing.T) {
 	body, _ := ioutil.ReadAll(resp.Body)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	assert.JSONEq(t,
-		`{"status":"error","error":"unable to find a namespace with specified name"}`,
+		`{"status":"error","error":"unable to find a namespace with specified name","code":"not_found","retryable":false}`,
 		string(body))
 }
 
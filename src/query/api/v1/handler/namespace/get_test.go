@@ -122,14 +122,15 @@ func TestNamespaceGetHandler(t *testing.T) {
 			"registry": xjson.Map{
 				"namespaces": xjson.Map{
 					"test": xjson.Map{
-						"aggregationOptions":    nil,
-						"bootstrapEnabled":      true,
-						"cacheBlocksOnRetrieve": nil,
-						"cleanupEnabled":        false,
-						"coldWritesEnabled":     false,
-						"flushEnabled":          true,
-						"indexOptions":          nil,
-						"repairEnabled":         false,
+						"aggregationOptions":             nil,
+						"bootstrapEnabled":               true,
+						"cacheBlocksOnRetrieve":          nil,
+						"cleanupEnabled":                 false,
+						"coldWritesEnabled":              false,
+						"futureWriteClampToleranceNanos": nil,
+						"flushEnabled":                   true,
+						"indexOptions":                   nil,
+						"repairEnabled":                  false,
 						"retentionOptions": xjson.Map{
 							"blockDataExpiry":                          true,
 							"blockDataExpiryAfterNotAccessPeriodNanos": "3600000000000",
@@ -207,14 +208,15 @@ func TestNamespaceGetHandlerWithDebug(t *testing.T) {
 			"registry": xjson.Map{
 				"namespaces": xjson.Map{
 					"test": xjson.Map{
-						"aggregationOptions":    nil,
-						"bootstrapEnabled":      true,
-						"cacheBlocksOnRetrieve": nil,
-						"cleanupEnabled":        false,
-						"coldWritesEnabled":     false,
-						"flushEnabled":          true,
-						"indexOptions":          nil,
-						"repairEnabled":         false,
+						"aggregationOptions":                nil,
+						"bootstrapEnabled":                  true,
+						"cacheBlocksOnRetrieve":             nil,
+						"cleanupEnabled":                    false,
+						"coldWritesEnabled":                 false,
+						"futureWriteClampToleranceDuration": nil,
+						"flushEnabled":                      true,
+						"indexOptions":                      nil,
+						"repairEnabled":                     false,
 						"retentionOptions": xjson.Map{
 							"blockDataExpiry": true,
 							"blockDataExpiryAfterNotAccessPeriodDuration": "1h0m0s",
@@ -264,11 +264,12 @@ func (t *testClusterNamespace) Session() client.Session {
 
 type testClusters struct {
 	configType         m3.ClusterConfigType
+	namespaces         m3.ClusterNamespaces
 	nonReadyNamespaces m3.ClusterNamespaces
 }
 
 func (t *testClusters) ClusterNamespaces() m3.ClusterNamespaces {
-	panic("implement me")
+	return t.namespaces
 }
 
 func (t *testClusters) NonReadyClusterNamespaces() m3.ClusterNamespaces {
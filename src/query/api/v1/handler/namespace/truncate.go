@@ -0,0 +1,492 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
+	"github.com/m3db/m3/src/dbnode/client"
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/clock"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// TruncateHTTPMethod is the HTTP method used with this resource.
+	TruncateHTTPMethod = http.MethodPost
+
+	// M3DBNodeNamespacesTruncatePendingKey is the KV key that holds the
+	// namespace truncations waiting for confirmation.
+	M3DBNodeNamespacesTruncatePendingKey = "m3db.node.namespaces.truncate.pending"
+
+	defaultTruncateConfirmationTimeout  = 5 * time.Minute
+	defaultTruncateWritesBlockedTimeout = time.Minute
+	defaultTruncateWritesBlockedPoll    = 100 * time.Millisecond
+)
+
+var (
+	// M3DBTruncateURL is the url for the M3DB namespace truncate handler.
+	M3DBTruncateURL = path.Join(route.Prefix, M3DBServiceNamespacePathName, "truncate")
+
+	errTruncateNoName = xerrors.NewInvalidParamsError(
+		errors.New("must specify the name of the namespace to truncate"))
+	errTruncateStaticNamespaces = xerrors.NewInvalidParamsError(
+		errors.New("cannot block writes to statically configured namespaces, cannot truncate them"))
+	errTruncateInvalidConfirmation = xerrors.NewInvalidParamsError(
+		errors.New("confirmation token is invalid or expired, request a new one"))
+	errTruncateTopologyChanged = xhttp.NewError(
+		errors.New("topology changed since the truncation was requested, request a new confirmation token"),
+		http.StatusConflict)
+	errTruncateWritesNotBlocked = xhttp.NewError(
+		errors.New("writes to the namespace were not blocked in time, request a new confirmation token"),
+		http.StatusServiceUnavailable)
+)
+
+// TruncateRequest is a request to truncate a namespace. Truncating a
+// namespace takes two requests: the first one, without a confirmation token,
+// returns the hosts that would be truncated along with a confirmation token,
+// and the second one, with the confirmation token, blocks writes to the
+// namespace, truncates it and unblocks writes.
+type TruncateRequest struct {
+	Name              string `json:"name"`
+	ConfirmationToken string `json:"confirmationToken"`
+}
+
+// TruncateHost is a host whose replicas of a namespace are truncated.
+type TruncateHost struct {
+	ID        string `json:"id"`
+	Address   string `json:"address"`
+	NumShards int    `json:"numShards"`
+}
+
+// TruncateResponse is the response to a truncate request.
+type TruncateResponse struct {
+	Name                  string         `json:"name"`
+	NumShards             int            `json:"numShards"`
+	Replicas              int            `json:"replicas"`
+	Hosts                 []TruncateHost `json:"hosts"`
+	ConfirmationToken     string         `json:"confirmationToken,omitempty"`
+	ConfirmationExpiresAt *time.Time     `json:"confirmationExpiresAt,omitempty"`
+	Truncated             bool           `json:"truncated"`
+	NumSeries             int64          `json:"numSeries"`
+}
+
+// TruncateHandler is the handler for truncating the data of a namespace on
+// every replica of every shard. Pending truncations are kept in KV so that
+// they can be confirmed through any coordinator.
+type TruncateHandler struct {
+	client               clusterclient.Client
+	clusters             m3.Clusters
+	instrumentOpts       instrument.Options
+	nowFn                clock.NowFn
+	confirmationTimeout  time.Duration
+	writesBlockedTimeout time.Duration
+	writesBlockedPoll    time.Duration
+}
+
+// NewTruncateHandler returns a new instance of TruncateHandler.
+func NewTruncateHandler(
+	client clusterclient.Client,
+	clusters m3.Clusters,
+	instrumentOpts instrument.Options,
+) *TruncateHandler {
+	return &TruncateHandler{
+		client:               client,
+		clusters:             clusters,
+		instrumentOpts:       instrumentOpts,
+		nowFn:                time.Now,
+		confirmationTimeout:  defaultTruncateConfirmationTimeout,
+		writesBlockedTimeout: defaultTruncateWritesBlockedTimeout,
+		writesBlockedPoll:    defaultTruncateWritesBlockedPoll,
+	}
+}
+
+func (h *TruncateHandler) ServeHTTP(
+	svc handleroptions.ServiceNameAndDefaults,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	var req TruncateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		xhttp.WriteError(w, errTruncateNoName)
+		return
+	}
+
+	opts := handleroptions.NewServiceOptions(svc, r.Header, nil)
+	store, err := h.client.Store(opts.KVOverrideOptions())
+	if err != nil {
+		logger.Error("unable to get kv store", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	resp, err := h.truncate(ctx, store, req, logger)
+	if err != nil {
+		logger.Error("unable to truncate namespace",
+			zap.String("namespace", req.Name), zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+	if resp.Truncated {
+		logger.Info("truncated namespace",
+			zap.String("namespace", req.Name),
+			zap.Int("hosts", len(resp.Hosts)),
+			zap.Int64("numSeries", resp.NumSeries))
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+func (h *TruncateHandler) truncate(
+	ctx context.Context,
+	store kv.Store,
+	req TruncateRequest,
+	logger *zap.Logger,
+) (TruncateResponse, error) {
+	session, err := h.adminSession(req.Name)
+	if err != nil {
+		return TruncateResponse{}, err
+	}
+	topoMap, err := session.TopologyMap()
+	if err != nil {
+		return TruncateResponse{}, err
+	}
+
+	resp := TruncateResponse{
+		Name:      req.Name,
+		NumShards: len(topoMap.ShardSet().AllIDs()),
+		Replicas:  topoMap.Replicas(),
+		Hosts:     truncateHosts(topoMap),
+	}
+	topology := truncateTopology(resp.Hosts)
+
+	if req.ConfirmationToken == "" {
+		token, expiresAt, err := h.requestTruncate(store, req.Name, topology)
+		if err != nil {
+			return TruncateResponse{}, err
+		}
+		resp.ConfirmationToken = token
+		resp.ConfirmationExpiresAt = &expiresAt
+		return resp, nil
+	}
+
+	pendingSet, version, err := pendingTruncates(store)
+	if err != nil {
+		return TruncateResponse{}, err
+	}
+	now := h.nowFn()
+	pending, ok := pendingSet.Pending[req.ConfirmationToken]
+	if !ok || pending.Name != req.Name {
+		return TruncateResponse{}, errTruncateInvalidConfirmation
+	}
+	if !now.Before(time.Unix(0, pending.ExpiresAtNanos)) {
+		return TruncateResponse{}, errTruncateInvalidConfirmation
+	}
+	// The hosts truncated must be the ones that were confirmed, otherwise
+	// replicas that moved in the meantime would be missed.
+	if pending.Topology != topology {
+		return TruncateResponse{}, errTruncateTopologyChanged
+	}
+
+	// Confirmation tokens can only be used once, whether or not the
+	// truncation succeeds.
+	delete(pendingSet.Pending, req.ConfirmationToken)
+	if err := storePendingTruncates(store, pendingSet, version, now); err != nil {
+		return TruncateResponse{}, err
+	}
+
+	// NB: namespaces that are not ready are not written to by coordinators,
+	// the namespace is moved back to its previous staging status once the
+	// truncation completes or fails, so writes are only blocked while the
+	// confirmed truncation is in progress.
+	previousStatus, err := setStagingStatus(store, req.Name, nsproto.StagingStatus_INITIALIZING)
+	if err != nil {
+		return TruncateResponse{}, err
+	}
+	defer h.restoreStagingStatus(store, req.Name, previousStatus, logger)
+
+	if err := h.waitForWritesBlocked(ctx, req.Name); err != nil {
+		return TruncateResponse{}, err
+	}
+
+	numSeries, err := session.Truncate(ident.StringID(req.Name))
+	if err != nil {
+		return TruncateResponse{}, fmt.Errorf(
+			"namespace %s may be partially truncated, retry the truncation: %w", req.Name, err)
+	}
+	resp.Truncated = true
+	resp.NumSeries = numSeries
+	return resp, nil
+}
+
+// requestTruncate stores a pending truncation of the namespace, returning its
+// confirmation token. Writes to the namespace are not blocked until the
+// truncation is confirmed, so a truncation that is never confirmed has no
+// effect.
+func (h *TruncateHandler) requestTruncate(
+	store kv.Store,
+	name string,
+	topology string,
+) (string, time.Time, error) {
+	if h.clusters.ConfigType() == m3.ClusterConfigTypeStatic {
+		return "", time.Time{}, errTruncateStaticNamespaces
+	}
+
+	token, err := newTruncateConfirmationToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := h.nowFn()
+	expiresAt := now.Add(h.confirmationTimeout)
+	pendingSet, version, err := pendingTruncates(store)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	// Only the latest token of a namespace is valid.
+	for token, existing := range pendingSet.Pending {
+		if existing.Name == name {
+			delete(pendingSet.Pending, token)
+		}
+	}
+	pendingSet.Pending[token] = &admin.NamespaceTruncatePending{
+		Name:           name,
+		Topology:       topology,
+		ExpiresAtNanos: expiresAt.UnixNano(),
+	}
+	if err := storePendingTruncates(store, pendingSet, version, now); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// storePendingTruncates prunes the expired truncations from the pending set
+// and stores it.
+func storePendingTruncates(
+	store kv.Store,
+	pendingSet *admin.NamespaceTruncatePendingSet,
+	version int,
+	now time.Time,
+) error {
+	for token, pending := range pendingSet.Pending {
+		if !now.Before(time.Unix(0, pending.ExpiresAtNanos)) {
+			delete(pendingSet.Pending, token)
+		}
+	}
+	_, err := store.CheckAndSet(M3DBNodeNamespacesTruncatePendingKey, version, pendingSet)
+	return err
+}
+
+// waitForWritesBlocked waits until the coordinator no longer sees the
+// namespace as ready, which takes a namespace watch update after its staging
+// status changed.
+func (h *TruncateHandler) waitForWritesBlocked(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, h.writesBlockedTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(h.writesBlockedPoll)
+	defer ticker.Stop()
+	for h.namespaceReady(name) {
+		select {
+		case <-ctx.Done():
+			return errTruncateWritesNotBlocked
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (h *TruncateHandler) restoreStagingStatus(
+	store kv.Store,
+	name string,
+	status nsproto.StagingStatus,
+	logger *zap.Logger,
+) {
+	if _, err := setStagingStatus(store, name, status); err != nil {
+		logger.Error("unable to unblock writes to namespace, update its staging status manually",
+			zap.String("namespace", name),
+			zap.String("status", status.String()),
+			zap.Error(err))
+	}
+}
+
+func (h *TruncateHandler) namespaceReady(name string) bool {
+	for _, ns := range h.clusters.ClusterNamespaces() {
+		if ns.NamespaceID().String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *TruncateHandler) adminSession(name string) (client.AdminSession, error) {
+	if h.clusters == nil {
+		return nil, xerrors.NewInvalidParamsError(
+			errors.New("coordinator is not connected to dbnodes, cannot truncate namespaces"))
+	}
+
+	var session client.Session
+	for _, namespaces := range []m3.ClusterNamespaces{
+		h.clusters.ClusterNamespaces(),
+		h.clusters.NonReadyClusterNamespaces(),
+	} {
+		for _, ns := range namespaces {
+			if ns.NamespaceID().String() == name {
+				session = ns.Session()
+				break
+			}
+		}
+		if session != nil {
+			break
+		}
+	}
+	if session == nil {
+		return nil, errNamespaceNotFound
+	}
+
+	adminSession, ok := session.(client.AdminSession)
+	if !ok {
+		return nil, fmt.Errorf("session of namespace %s does not support truncation", name)
+	}
+	return adminSession, nil
+}
+
+func pendingTruncates(store kv.Store) (*admin.NamespaceTruncatePendingSet, int, error) {
+	pendingSet := &admin.NamespaceTruncatePendingSet{}
+	value, err := store.Get(M3DBNodeNamespacesTruncatePendingKey)
+	if err == kv.ErrNotFound {
+		pendingSet.Pending = make(map[string]*admin.NamespaceTruncatePending)
+		return pendingSet, 0, nil
+	}
+	if err != nil {
+		return nil, -1, err
+	}
+	if err := value.Unmarshal(pendingSet); err != nil {
+		return nil, -1, fmt.Errorf("unable to parse pending truncations: %w", err)
+	}
+	if pendingSet.Pending == nil {
+		pendingSet.Pending = make(map[string]*admin.NamespaceTruncatePending)
+	}
+	return pendingSet, value.Version(), nil
+}
+
+// setStagingStatus sets the staging status of a namespace and returns its
+// previous staging status.
+func setStagingStatus(
+	store kv.Store,
+	name string,
+	status nsproto.StagingStatus,
+) (nsproto.StagingStatus, error) {
+	value, err := store.Get(M3DBNodeNamespacesKey)
+	if err == kv.ErrNotFound {
+		return nsproto.StagingStatus_UNKNOWN, errNamespaceNotFound
+	}
+	if err != nil {
+		return nsproto.StagingStatus_UNKNOWN, err
+	}
+
+	var registry nsproto.Registry
+	if err := value.Unmarshal(&registry); err != nil {
+		return nsproto.StagingStatus_UNKNOWN, fmt.Errorf("unable to parse value, err: %v", err)
+	}
+	opts, ok := registry.Namespaces[name]
+	if !ok {
+		return nsproto.StagingStatus_UNKNOWN, errNamespaceNotFound
+	}
+
+	previous := nsproto.StagingStatus_UNKNOWN
+	if opts.StagingState != nil {
+		previous = opts.StagingState.Status
+	}
+	if previous == status {
+		return previous, nil
+	}
+
+	opts.StagingState = &nsproto.StagingState{Status: status}
+	if _, err := store.CheckAndSet(M3DBNodeNamespacesKey, value.Version(), &registry); err != nil {
+		return nsproto.StagingStatus_UNKNOWN, err
+	}
+	return previous, nil
+}
+
+func truncateHosts(topoMap topology.Map) []TruncateHost {
+	hostShardSets := topoMap.HostShardSets()
+	hosts := make([]TruncateHost, 0, len(hostShardSets))
+	for _, hss := range hostShardSets {
+		hosts = append(hosts, TruncateHost{
+			ID:        hss.Host().ID(),
+			Address:   hss.Host().Address(),
+			NumShards: len(hss.ShardSet().AllIDs()),
+		})
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].ID < hosts[j].ID
+	})
+	return hosts
+}
+
+func truncateTopology(hosts []TruncateHost) string {
+	var b strings.Builder
+	for _, host := range hosts {
+		fmt.Fprintf(&b, "%s@%s:%d;", host.ID, host.Address, host.NumShards)
+	}
+	return b.String()
+}
+
+func newTruncateConfirmationToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
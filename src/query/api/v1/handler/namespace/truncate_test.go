@@ -0,0 +1,374 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/client"
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xjson "github.com/m3db/m3/src/x/json"
+	xtest "github.com/m3db/m3/src/x/test"
+)
+
+func newTestTruncateTopologyMap(hostIDs ...string) topology.Map {
+	shardSet, _ := sharding.NewShardSet(sharding.NewShards([]uint32{0, 1, 2, 3},
+		shard.Available), sharding.DefaultHashFn(4))
+	hostShardSets := make([]topology.HostShardSet, 0, len(hostIDs))
+	for _, id := range hostIDs {
+		hostShardSets = append(hostShardSets, topology.NewHostShardSet(
+			topology.NewHost(id, id+":9000"), shardSet))
+	}
+	return topology.NewStaticMap(topology.NewStaticOptions().
+		SetShardSet(shardSet).
+		SetReplicas(len(hostIDs)).
+		SetHostShardSets(hostShardSets))
+}
+
+type testTruncateSetup struct {
+	store    kv.Store
+	client   *clusterclient.MockClient
+	clusters *testClusters
+	sessions map[string]*client.MockAdminSession
+}
+
+// newTestTruncateSetup returns a setup with the given namespaces ready in KV.
+// The coordinator already sees them as not ready, as if the namespace watch
+// had observed writes being blocked.
+func newTestTruncateSetup(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	names ...string,
+) testTruncateSetup {
+	store := mem.NewStore()
+	registry := &nsproto.Registry{Namespaces: make(map[string]*nsproto.NamespaceOptions)}
+	setup := testTruncateSetup{
+		store:    store,
+		client:   clusterclient.NewMockClient(ctrl),
+		clusters: &testClusters{configType: m3.ClusterConfigTypeDynamic},
+		sessions: make(map[string]*client.MockAdminSession),
+	}
+	for _, name := range names {
+		registry.Namespaces[name] = &nsproto.NamespaceOptions{
+			RetentionOptions: &nsproto.RetentionOptions{
+				RetentionPeriodNanos: int64(48 * time.Hour),
+				BlockSizeNanos:       int64(2 * time.Hour),
+				BufferFutureNanos:    int64(10 * time.Minute),
+				BufferPastNanos:      int64(10 * time.Minute),
+			},
+			StagingState: &nsproto.StagingState{Status: nsproto.StagingStatus_READY},
+		}
+		session := client.NewMockAdminSession(ctrl)
+		setup.sessions[name] = session
+		setup.clusters.nonReadyNamespaces = append(setup.clusters.nonReadyNamespaces,
+			&testClusterNamespace{session: session, id: ident.StringID(name)})
+	}
+	_, err := store.Set(M3DBNodeNamespacesKey, registry)
+	require.NoError(t, err)
+	setup.client.EXPECT().Store(gomock.Any()).Return(store, nil).AnyTimes()
+	return setup
+}
+
+func (s testTruncateSetup) newHandler() *TruncateHandler {
+	return NewTruncateHandler(s.client, s.clusters, instrument.NewOptions())
+}
+
+func (s testTruncateSetup) requireStagingStatus(
+	t *testing.T,
+	name string,
+	expected nsproto.StagingStatus,
+) {
+	value, err := s.store.Get(M3DBNodeNamespacesKey)
+	require.NoError(t, err)
+	var registry nsproto.Registry
+	require.NoError(t, value.Unmarshal(&registry))
+	require.Equal(t, expected, registry.Namespaces[name].StagingState.Status)
+}
+
+func (s testTruncateSetup) pendingTokens(t *testing.T) []string {
+	value, err := s.store.Get(M3DBNodeNamespacesTruncatePendingKey)
+	require.NoError(t, err)
+	var pendingSet admin.NamespaceTruncatePendingSet
+	require.NoError(t, value.Unmarshal(&pendingSet))
+	tokens := make([]string, 0, len(pendingSet.Pending))
+	for token := range pendingSet.Pending {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func serveTruncate(
+	t *testing.T,
+	h *TruncateHandler,
+	body xjson.Map,
+) (int, TruncateResponse) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(TruncateHTTPMethod, M3DBTruncateURL,
+		xjson.MustNewTestReader(t, body))
+	h.ServeHTTP(svcDefaults, w, req)
+
+	resp := w.Result()
+	var truncateResp TruncateResponse
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&truncateResp))
+	}
+	return resp.StatusCode, truncateResp
+}
+
+func TestNamespaceTruncateHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	session := setup.sessions["testNamespace"]
+	session.EXPECT().TopologyMap().
+		Return(newTestTruncateTopologyMap("host2", "host1", "host3"), nil).Times(2)
+
+	code, resp := serveTruncate(t, setup.newHandler(), xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, resp.Truncated)
+	require.NotEmpty(t, resp.ConfirmationToken)
+	require.NotNil(t, resp.ConfirmationExpiresAt)
+	require.Equal(t, 4, resp.NumShards)
+	require.Equal(t, 3, resp.Replicas)
+	require.Equal(t, []TruncateHost{
+		{ID: "host1", Address: "host1:9000", NumShards: 4},
+		{ID: "host2", Address: "host2:9000", NumShards: 4},
+		{ID: "host3", Address: "host3:9000", NumShards: 4},
+	}, resp.Hosts)
+	// Writes are not blocked until the truncation is confirmed.
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+	require.Equal(t, []string{resp.ConfirmationToken}, setup.pendingTokens(t))
+
+	session.EXPECT().Truncate(ident.NewIDMatcher("testNamespace")).DoAndReturn(
+		func(ident.ID) (int64, error) {
+			setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_INITIALIZING)
+			return 42, nil
+		})
+
+	// The truncation can be confirmed through another coordinator.
+	h := setup.newHandler()
+	token := resp.ConfirmationToken
+	code, resp = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": token,
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Truncated)
+	require.Equal(t, int64(42), resp.NumSeries)
+	require.Empty(t, resp.ConfirmationToken)
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+	require.Empty(t, setup.pendingTokens(t))
+
+	// Tokens can only be used once.
+	session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1"), nil)
+	code, _ = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": token,
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestNamespaceTruncateHandlerInvalidConfirmation(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	h := setup.newHandler()
+	now := time.Now()
+	h.nowFn = func() time.Time { return now }
+	setup.sessions["testNamespace"].EXPECT().TopologyMap().
+		Return(newTestTruncateTopologyMap("host1"), nil).AnyTimes()
+
+	code, _ := serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": "unknown",
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, resp := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+
+	// Expired tokens are rejected without ever having blocked writes.
+	now = now.Add(defaultTruncateConfirmationTimeout)
+	code, _ = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": resp.ConfirmationToken,
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+}
+
+func TestNamespaceTruncateHandlerPrunesExpiredOnInsert(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace", "otherNamespace")
+	h := setup.newHandler()
+	now := time.Now()
+	h.nowFn = func() time.Time { return now }
+	for _, session := range setup.sessions {
+		session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1"), nil).AnyTimes()
+	}
+
+	code, _ := serveTruncate(t, h, xjson.Map{"name": "otherNamespace"})
+	require.Equal(t, http.StatusOK, code)
+
+	// Requesting a new token for the same namespace replaces the previous one.
+	code, first := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+	code, second := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+	tokens := setup.pendingTokens(t)
+	require.Len(t, tokens, 2)
+	require.Contains(t, tokens, second.ConfirmationToken)
+	require.NotContains(t, tokens, first.ConfirmationToken)
+
+	now = now.Add(defaultTruncateConfirmationTimeout)
+	code, third := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{third.ConfirmationToken}, setup.pendingTokens(t))
+
+	setup.sessions["testNamespace"].EXPECT().Truncate(gomock.Any()).Return(int64(1), nil)
+	code, _ = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": third.ConfirmationToken,
+	})
+	require.Equal(t, http.StatusOK, code)
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+}
+
+func TestNamespaceTruncateHandlerTopologyChanged(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	session := setup.sessions["testNamespace"]
+	gomock.InOrder(
+		session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1", "host2"), nil),
+		session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1", "host3"), nil),
+	)
+
+	h := setup.newHandler()
+	code, resp := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": resp.ConfirmationToken,
+	})
+	require.Equal(t, http.StatusConflict, code)
+}
+
+func TestNamespaceTruncateHandlerWritesNotBlocked(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	session := setup.sessions["testNamespace"]
+	session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1"), nil).Times(3)
+
+	h := setup.newHandler()
+	h.writesBlockedTimeout = 50 * time.Millisecond
+	h.writesBlockedPoll = time.Millisecond
+	code, resp := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+
+	// The coordinator does not observe the namespace becoming non-ready.
+	setup.clusters.namespaces = setup.clusters.nonReadyNamespaces
+	setup.clusters.nonReadyNamespaces = nil
+	body := xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": resp.ConfirmationToken,
+	}
+	code, _ = serveTruncate(t, h, body)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	// Writes are unblocked and the token can not be used again.
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+	code, _ = serveTruncate(t, h, body)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestNamespaceTruncateHandlerTruncateError(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	session := setup.sessions["testNamespace"]
+	session.EXPECT().TopologyMap().Return(newTestTruncateTopologyMap("host1"), nil).Times(2)
+	session.EXPECT().Truncate(gomock.Any()).Return(int64(0), errors.New("host unavailable"))
+
+	h := setup.newHandler()
+	code, resp := serveTruncate(t, h, xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = serveTruncate(t, h, xjson.Map{
+		"name":              "testNamespace",
+		"confirmationToken": resp.ConfirmationToken,
+	})
+	require.Equal(t, http.StatusInternalServerError, code)
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+}
+
+func TestNamespaceTruncateHandlerStaticNamespaces(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	setup := newTestTruncateSetup(t, ctrl, "testNamespace")
+	setup.clusters.configType = m3.ClusterConfigTypeStatic
+	setup.sessions["testNamespace"].EXPECT().TopologyMap().
+		Return(newTestTruncateTopologyMap("host1"), nil)
+
+	code, _ := serveTruncate(t, setup.newHandler(), xjson.Map{"name": "testNamespace"})
+	require.Equal(t, http.StatusBadRequest, code)
+	setup.requireStagingStatus(t, "testNamespace", nsproto.StagingStatus_READY)
+}
+
+func TestNamespaceTruncateHandlerNamespaceNotFound(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestTruncateSetup(t, ctrl, "testNamespace").newHandler()
+	code, _ := serveTruncate(t, h, xjson.Map{"name": "missing"})
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, _ = serveTruncate(t, h, xjson.Map{})
+	require.Equal(t, http.StatusBadRequest, code)
+}
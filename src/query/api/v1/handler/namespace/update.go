@@ -56,6 +56,8 @@ var (
 	fieldNameAggregationOptions = "AggregationOptions"
 	fieldNameExtendedOptions    = "ExtendedOptions"
 
+	fieldNameFutureWriteClampTolerance = "FutureWriteClampToleranceNanos"
+
 	errEmptyNamespaceName      = errors.New("must specify namespace name")
 	errEmptyNamespaceOptions   = errors.New("update options cannot be empty")
 	errNamespaceFieldImmutable = errors.New("namespace option field is immutable")
@@ -65,6 +67,8 @@ var (
 		fieldNameRuntimeOptions:     {},
 		fieldNameAggregationOptions: {},
 		fieldNameExtendedOptions:    {},
+
+		fieldNameFutureWriteClampTolerance: {},
 	}
 )
 
@@ -242,6 +246,17 @@ func (h *UpdateHandler) Update(
 		}
 	}
 
+	// Update the future write clamp tolerance, which may be reset to zero.
+	if newNanos := updateReq.Options.FutureWriteClampToleranceNanos; newNanos != nil {
+		opts := ns.Options().
+			SetFutureWriteClampTolerance(namespace.FromNanos(newNanos.Value))
+		ns, err = namespace.NewMetadata(ns.ID(), opts)
+		if err != nil {
+			return emptyReg, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"error constructing new metadata: %w", err))
+		}
+	}
+
 	// Update extended options.
 	if newExtendedOptions := updateReq.Options.ExtendedOptions; newExtendedOptions != nil {
 		newExtOpts, err := namespace.ToExtendedOptions(newExtendedOptions)
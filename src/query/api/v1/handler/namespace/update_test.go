@@ -30,6 +30,7 @@ import (
 	"testing"
 
 	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/query/generated/proto/admin"
 	"github.com/m3db/m3/src/x/instrument"
@@ -110,7 +111,7 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.JSONEq(t,
-		`{"status":"error","error":"unable to validate update request: update options cannot be empty"}`,
+		`{"status":"error","error":"unable to validate update request: update options cannot be empty","code":"invalid_params","retryable":false}`,
 		string(body))
 
 	// Test good case. Note: there is no way to tell the difference between a boolean
@@ -171,6 +172,7 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 										"downsampleOptions": xjson.Map{
 											"all": true,
 										},
+										"fanoutDisabled": false,
 									},
 								},
 							},
@@ -199,10 +201,11 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 							"flushIndexingPerCPUConcurrency": nil,
 							"writeIndexingPerCPUConcurrency": 16,
 						},
-						"schemaOptions":     nil,
-						"stagingState":      xjson.Map{"status": "UNKNOWN"},
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("bar"),
+						"schemaOptions":                  nil,
+						"stagingState":                   xjson.Map{"status": "UNKNOWN"},
+						"coldWritesEnabled":              false,
+						"futureWriteClampToleranceNanos": "0",
+						"extendedOptions":                xtest.NewTestExtendedOptionsJSON("bar"),
 					},
 				},
 			},
@@ -256,11 +259,12 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 							"enabled":        false,
 							"blockSizeNanos": "7200000000000",
 						},
-						"runtimeOptions":    nil,
-						"schemaOptions":     nil,
-						"stagingState":      xjson.Map{"status": "UNKNOWN"},
-						"coldWritesEnabled": false,
-						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
+						"runtimeOptions":                 nil,
+						"schemaOptions":                  nil,
+						"stagingState":                   xjson.Map{"status": "UNKNOWN"},
+						"coldWritesEnabled":              false,
+						"futureWriteClampToleranceNanos": "0",
+						"extendedOptions":                xtest.NewTestExtendedOptionsJSON("foo"),
 					},
 				},
 			},
@@ -272,6 +276,43 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 		xtest.Diff(expected, actual))
 }
 
+func TestNamespaceUpdateResetFutureWriteClampTolerance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockKV := setupNamespaceTest(t, ctrl)
+	updateHandler := NewUpdateHandler(mockClient, instrument.NewOptions())
+	mockClient.EXPECT().Store(gomock.Any()).Return(mockKV, nil)
+
+	registry := nsproto.Registry{
+		Namespaces: map[string]*nsproto.NamespaceOptions{
+			"testNamespace": {
+				RetentionOptions: &nsproto.RetentionOptions{
+					RetentionPeriodNanos: 172800000000000,
+					BlockSizeNanos:       7200000000000,
+				},
+				FutureWriteClampToleranceNanos: &types.Int64Value{Value: 5000000000},
+			},
+		},
+	}
+	mockValue := kv.NewMockValue(ctrl)
+	mockValue.EXPECT().Unmarshal(gomock.Any()).Return(nil).SetArg(0, registry)
+	mockValue.EXPECT().Version().Return(0)
+	mockKV.EXPECT().Get(M3DBNodeNamespacesKey).Return(mockValue, nil)
+	mockKV.EXPECT().CheckAndSet(M3DBNodeNamespacesKey, gomock.Any(), gomock.Not(nil)).Return(1, nil)
+
+	req := httptest.NewRequest("PUT", "/namespace", strings.NewReader(
+		`{"name": "testNamespace", "options": {"futureWriteClampToleranceDuration": "0s"}}`))
+	updateReq, err := updateHandler.parseRequest(req)
+	require.NoError(t, err)
+
+	updated, err := updateHandler.Update(updateReq,
+		handleroptions.NewServiceOptions(svcDefaults, nil, nil))
+	require.NoError(t, err)
+	require.Equal(t, &types.Int64Value{Value: 0},
+		updated.Namespaces["testNamespace"].FutureWriteClampToleranceNanos)
+}
+
 func TestValidateUpdateRequest(t *testing.T) {
 	var (
 		reqEmptyName = &admin.NamespaceUpdateRequest{
@@ -316,6 +357,14 @@ func TestValidateUpdateRequest(t *testing.T) {
 				},
 			},
 		}
+
+		reqValidFutureWriteClampTolerance = &admin.NamespaceUpdateRequest{
+			Name: "foo",
+			Options: &nsproto.NamespaceOptions{
+				// Resetting the tolerance to zero is a valid update.
+				FutureWriteClampToleranceNanos: &types.Int64Value{},
+			},
+		}
 	)
 
 	for _, test := range []struct {
@@ -353,6 +402,11 @@ func TestValidateUpdateRequest(t *testing.T) {
 			request: reqValid,
 			expErr:  nil,
 		},
+		{
+			name:    "validFutureWriteClampTolerance",
+			request: reqValidFutureWriteClampTolerance,
+			expErr:  nil,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			err := validateUpdateRequest(test.request)
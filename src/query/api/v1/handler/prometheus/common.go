@@ -44,6 +44,7 @@ import (
 
 const (
 	queryParam   = "query"
+	matchParam   = "match[]"
 	endParam     = "end"
 	startParam   = "start"
 	nowTimeValue = "now"
@@ -55,6 +56,10 @@ const (
 	tolerance           = 0.0000001
 )
 
+// ErrUncompressedBodyTooLarge is returned when the uncompressed size of a
+// request body exceeds the limit it is parsed with.
+var ErrUncompressedBodyTooLarge = goerrors.New("uncompressed body too large")
+
 // ParsePromCompressedRequestResult is the result of a
 // ParsePromCompressedRequest call.
 type ParsePromCompressedRequestResult struct {
@@ -65,6 +70,17 @@ type ParsePromCompressedRequestResult struct {
 // ParsePromCompressedRequest parses a snappy compressed request from Prometheus.
 func ParsePromCompressedRequest(
 	r *http.Request,
+) (ParsePromCompressedRequestResult, error) {
+	return ParsePromCompressedRequestWithLimit(r, 0)
+}
+
+// ParsePromCompressedRequestWithLimit parses a snappy compressed request from
+// Prometheus, returning ErrUncompressedBodyTooLarge without decompressing the
+// body if its uncompressed size exceeds the limit. A zero or negative limit
+// implies no limit.
+func ParsePromCompressedRequestWithLimit(
+	r *http.Request,
+	maxUncompressedBodyBytes int,
 ) (ParsePromCompressedRequestResult, error) {
 	body := r.Body
 	if r.Body == nil {
@@ -80,6 +96,19 @@ func ParsePromCompressedRequest(
 		return ParsePromCompressedRequestResult{}, err
 	}
 
+	if maxUncompressedBodyBytes > 0 {
+		n, err := snappy.DecodedLen(compressed)
+		if err != nil {
+			return ParsePromCompressedRequestResult{},
+				xerrors.NewInvalidParamsError(err)
+		}
+		if n > maxUncompressedBodyBytes {
+			return ParsePromCompressedRequestResult{},
+				fmt.Errorf("%w: %d bytes exceeds limit of %d bytes",
+					ErrUncompressedBodyTooLarge, n, maxUncompressedBodyBytes)
+		}
+	}
+
 	reqBuf, err := snappy.Decode(nil, compressed)
 	if err != nil {
 		return ParsePromCompressedRequestResult{},
@@ -107,16 +136,16 @@ func ParseTagCompletionParamsToQueries(
 	r *http.Request,
 ) (TagCompletionQueries, error) {
 	tagCompletionQueries := TagCompletionQueries{}
-	start, err := util.ParseTimeStringWithDefault(r.FormValue("start"),
+	start, err := util.ParseTimeStringWithDefault(r.FormValue(startParam),
 		time.Unix(0, 0))
 	if err != nil {
-		return tagCompletionQueries, xerrors.NewInvalidParamsError(err)
+		return tagCompletionQueries, xerrors.NewInvalidParamError(startParam, err)
 	}
 
-	end, err := util.ParseTimeStringWithDefault(r.FormValue("end"),
+	end, err := util.ParseTimeStringWithDefault(r.FormValue(endParam),
 		time.Now())
 	if err != nil {
-		return tagCompletionQueries, xerrors.NewInvalidParamsError(err)
+		return tagCompletionQueries, xerrors.NewInvalidParamError(endParam, err)
 	}
 
 	// If there is a result type field present, parse it and set
@@ -139,7 +168,7 @@ func ParseTagCompletionParamsToQueries(
 	queries, err := parseTagCompletionQueries(r)
 	if err != nil {
 		err = fmt.Errorf(errFormatStr, queryParam, err)
-		return tagCompletionQueries, xerrors.NewInvalidParamsError(err)
+		return tagCompletionQueries, xerrors.NewInvalidParamError(queryParam, err)
 	}
 
 	tagQueries := make([]*storage.CompleteTagsQuery, 0, len(queries))
@@ -188,20 +217,20 @@ func ParseStartAndEnd(
 	}
 
 	defaultTime := time.Unix(0, 0)
-	start, err := util.ParseTimeStringWithDefault(r.FormValue("start"), defaultTime)
+	start, err := util.ParseTimeStringWithDefault(r.FormValue(startParam), defaultTime)
 	if err != nil {
-		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(err)
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamError(startParam, err)
 	}
 
 	if parseOpts.RequireStartEndTime() && start.Equal(defaultTime) {
-		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamError(startParam,
 			goerrors.New("invalid start time. start time must be set"))
 	}
 
-	end, err := util.ParseTimeStringWithDefault(r.FormValue("end"),
+	end, err := util.ParseTimeStringWithDefault(r.FormValue(endParam),
 		parseOpts.NowFn()())
 	if err != nil {
-		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(err)
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamError(endParam, err)
 	}
 
 	if start.After(end) {
@@ -222,9 +251,9 @@ func ParseSeriesMatchQuery(
 		return nil, xerrors.NewInvalidParamsError(err)
 	}
 
-	matcherValues := r.Form["match[]"]
+	matcherValues := r.Form[matchParam]
 	if len(matcherValues) == 0 {
-		return nil, xerrors.NewInvalidParamsError(errors.ErrInvalidMatchers)
+		return nil, xerrors.NewInvalidParamError(matchParam, errors.ErrInvalidMatchers)
 	}
 
 	start, end, err := ParseStartAndEnd(r, parseOpts)
@@ -271,7 +300,7 @@ func ParseMatch(
 		return nil, false, xerrors.NewInvalidParamsError(err)
 	}
 
-	matcherValues := r.Form["match[]"]
+	matcherValues := r.Form[matchParam]
 	if len(matcherValues) == 0 {
 		return nil, false, nil
 	}
@@ -300,12 +329,12 @@ func parseMatch(
 
 	promMatchers, err := fn(matcher)
 	if err != nil {
-		return nil, xerrors.NewInvalidParamsError(err)
+		return nil, xerrors.NewInvalidParamError(matchParam, err)
 	}
 
 	matchers, err := xpromql.LabelMatchersToModelMatcher(promMatchers, tagOptions)
 	if err != nil {
-		return nil, xerrors.NewInvalidParamsError(err)
+		return nil, xerrors.NewInvalidParamError(matchParam, err)
 	}
 
 	return matchers, nil
@@ -555,20 +584,17 @@ func RenderSeriesMatchResultsJSON(
 
 	for _, result := range results {
 		for _, tags := range result {
-			total += len(tags.Tags.Tags)
+			// NB: the limit applies to series, series are never rendered
+			// with only some of their tags.
+			total++
 			if opts.ReturnedSeriesMetadataLimit > 0 && rendered >= opts.ReturnedSeriesMetadataLimit {
 				limited = true
 				continue
 			}
+			rendered++
 
 			jw.BeginObject()
 			for _, tag := range tags.Tags.Tags {
-				if opts.ReturnedSeriesMetadataLimit > 0 && rendered >= opts.ReturnedSeriesMetadataLimit {
-					limited = true
-					break
-				}
-				rendered++
-
 				jw.BeginObjectBytesField(tag.Name)
 				jw.WriteBytesString(tag.Value)
 			}
@@ -626,31 +652,37 @@ type TimeParams struct {
 
 // ParseTimeParams parses the time params (now, start, end) from a request.
 func ParseTimeParams(r *http.Request) (TimeParams, error) {
+	return ParseTimeParamsAt(r, time.Now())
+}
+
+// ParseTimeParamsAt parses the time params (now, start, end) from a request
+// evaluated as of the given time.
+func ParseTimeParamsAt(r *http.Request, now time.Time) (TimeParams, error) {
 	var (
 		params TimeParams
 		err    error
 	)
 
-	params.Now = time.Now()
+	params.Now = now
 	if v := r.FormValue(timeParam); v != "" {
 		var err error
 		params.Now, err = ParseTime(r, timeParam, params.Now)
 		if err != nil {
 			err = fmt.Errorf(formatErrStr, timeParam, err)
-			return params, xerrors.NewInvalidParamsError(err)
+			return params, xerrors.NewInvalidParamError(timeParam, err)
 		}
 	}
 
 	params.Start, err = ParseTime(r, startParam, params.Now)
 	if err != nil {
 		err = fmt.Errorf(formatErrStr, startParam, err)
-		return params, xerrors.NewInvalidParamsError(err)
+		return params, xerrors.NewInvalidParamError(startParam, err)
 	}
 
 	params.End, err = ParseTime(r, endParam, params.Now)
 	if err != nil {
 		err = fmt.Errorf(formatErrStr, endParam, err)
-		return params, xerrors.NewInvalidParamsError(err)
+		return params, xerrors.NewInvalidParamError(endParam, err)
 	}
 	if params.Start.After(params.End) {
 		err = fmt.Errorf("start (%s) must be before end (%s)", params.Start, params.End)
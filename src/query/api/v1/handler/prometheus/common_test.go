@@ -23,6 +23,7 @@ package prometheus
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -66,6 +67,17 @@ func TestPromCompressedReadInvalidEncoding(t *testing.T) {
 	assert.True(t, xerrors.IsInvalidParams(err))
 }
 
+func TestPromCompressedReadUncompressedBodyTooLarge(t *testing.T) {
+	req := httptest.NewRequest("POST", "/dummy", test.GeneratePromReadBody(t))
+	_, err := ParsePromCompressedRequestWithLimit(req, 1)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUncompressedBodyTooLarge))
+
+	req = httptest.NewRequest("POST", "/dummy", test.GeneratePromReadBody(t))
+	_, err = ParsePromCompressedRequestWithLimit(req, 1<<20)
+	assert.NoError(t, err)
+}
+
 type writer struct {
 	value string
 }
@@ -52,9 +52,14 @@ const (
 	TimeoutParam           = "timeout"
 	requireExhaustiveParam = "requireExhaustive"
 	requireNoWaitParam     = "requireNoWait"
+	snapshotTimeParam      = "snapshotTime"
 	maxInt64               = float64(math.MaxInt64)
 	minInt64               = float64(math.MinInt64)
 	maxTimeout             = 10 * time.Minute
+
+	// defaultSnapshotCompletenessDelay matches the default buffer past of
+	// M3DB namespaces, which bounds how late writes for past data are accepted.
+	defaultSnapshotCompletenessDelay = 10 * time.Minute
 )
 
 // FetchOptionsBuilder builds fetch options based on a request and default
@@ -73,6 +78,9 @@ type FetchOptionsBuilderOptions struct {
 	Limits        FetchOptionsBuilderLimitsOptions
 	RestrictByTag *storage.RestrictByTag
 	Timeout       time.Duration
+	// SnapshotCompletenessDelay is how long after a snapshot time writes are
+	// still expected to arrive for data at or before it.
+	SnapshotCompletenessDelay time.Duration
 }
 
 // Validate validates the fetch options builder options.
@@ -80,9 +88,20 @@ func (o FetchOptionsBuilderOptions) Validate() error {
 	if o.Limits.InstanceMultiple < 0 || (o.Limits.InstanceMultiple > 0 && o.Limits.InstanceMultiple < 1) {
 		return fmt.Errorf("InstanceMultiple must be 0 or >= 1: %v", o.Limits.InstanceMultiple)
 	}
+	if o.SnapshotCompletenessDelay < 0 {
+		return fmt.Errorf("SnapshotCompletenessDelay must not be negative: %v",
+			o.SnapshotCompletenessDelay)
+	}
 	return validateTimeout(o.Timeout)
 }
 
+func (o FetchOptionsBuilderOptions) snapshotCompletenessDelay() time.Duration {
+	if o.SnapshotCompletenessDelay > 0 {
+		return o.SnapshotCompletenessDelay
+	}
+	return defaultSnapshotCompletenessDelay
+}
+
 // FetchOptionsBuilderLimitsOptions provides limits options to use when
 // creating a fetch options builder.
 type FetchOptionsBuilderLimitsOptions struct {
@@ -231,6 +250,33 @@ func ParseRequireNoWait(req *http.Request) (bool, error) {
 	return false, nil
 }
 
+// ParseSnapshotTime parses the time a query is pinned to from either header
+// or query string, given in seconds since the epoch or in RFC3339 format.
+func ParseSnapshotTime(req *http.Request, now time.Time) (time.Time, bool, error) {
+	str := req.Header.Get(headers.SnapshotTimeHeader)
+	if str == "" {
+		str = req.FormValue(snapshotTimeParam)
+	}
+	if str == "" {
+		return time.Time{}, false, nil
+	}
+
+	var snapshotTime time.Time
+	if seconds, err := strconv.ParseFloat(str, 64); err == nil {
+		s, ns := math.Modf(seconds)
+		ns = math.Round(ns*1000) / 1000
+		snapshotTime = time.Unix(int64(s), int64(ns*float64(time.Second)))
+	} else if snapshotTime, err = time.Parse(time.RFC3339Nano, str); err != nil {
+		return time.Time{}, false, fmt.Errorf(
+			"could not parse snapshot time: input=%s, err=%w", str, err)
+	}
+	if snapshotTime.After(now) {
+		return time.Time{}, false, fmt.Errorf(
+			"snapshot time %s is in the future", snapshotTime.Format(time.RFC3339Nano))
+	}
+	return snapshotTime, true, nil
+}
+
 // NewFetchOptions parses an http request into fetch options.
 func (b fetchOptionsBuilder) NewFetchOptions(
 	ctx context.Context,
@@ -254,6 +300,16 @@ func (b fetchOptionsBuilder) newFetchOptions(
 		fetchOpts.Source = []byte(source)
 	}
 
+	now := time.Now()
+	snapshotTime, ok, err := ParseSnapshotTime(req, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		fetchOpts.SnapshotTime = snapshotTime
+		fetchOpts.SnapshotComplete = now.Sub(snapshotTime) >= b.opts.snapshotCompletenessDelay()
+	}
+
 	seriesLimit, err := ParseLimit(req, headers.LimitMaxSeriesHeader,
 		"limit", b.opts.Limits.SeriesLimit)
 	if err != nil {
@@ -587,7 +643,7 @@ func ParseRequestTimeout(
 
 	duration, err := time.ParseDuration(timeout)
 	if err != nil {
-		return 0, xerrors.NewInvalidParamsError(
+		return 0, xerrors.NewInvalidParamError(TimeoutParam,
 			fmt.Errorf("invalid 'timeout': %v", err))
 	}
 
@@ -600,11 +656,11 @@ func ParseRequestTimeout(
 
 func validateTimeout(v time.Duration) error {
 	if v <= 0 {
-		return xerrors.NewInvalidParamsError(
+		return xerrors.NewInvalidParamError(TimeoutParam,
 			fmt.Errorf("invalid 'timeout': less than or equal to zero %v", v))
 	}
 	if v > maxTimeout {
-		return xerrors.NewInvalidParamsError(
+		return xerrors.NewInvalidParamError(TimeoutParam,
 			fmt.Errorf("invalid 'timeout': %v greater than max %v", v, maxTimeout))
 	}
 	return nil
@@ -503,6 +503,64 @@ func stripSpace(str string) string {
 	return regexp.MustCompile(`\s+`).ReplaceAllString(str, "")
 }
 
+func TestParseSnapshotTime(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	req := httptest.NewRequest("GET", "/read", nil)
+	_, ok, err := ParseSnapshotTime(req, now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	req = httptest.NewRequest("GET", "/read?snapshotTime=1599999000.5", nil)
+	snapshotTime, ok, err := ParseSnapshotTime(req, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, time.Unix(1599999000, int64(500*time.Millisecond)).Equal(snapshotTime))
+
+	// The header takes precedence over the query string.
+	req.Header.Set(headers.SnapshotTimeHeader, "2020-09-13T12:00:00Z")
+	snapshotTime, ok, err = ParseSnapshotTime(req, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC).Equal(snapshotTime))
+
+	req.Header.Set(headers.SnapshotTimeHeader, "yesterday")
+	_, _, err = ParseSnapshotTime(req, now)
+	assert.Error(t, err)
+
+	req.Header.Set(headers.SnapshotTimeHeader, "1600000001")
+	_, _, err = ParseSnapshotTime(req, now)
+	assert.Error(t, err)
+}
+
+func TestFetchOptionsBuilderSnapshotTime(t *testing.T) {
+	builder, err := NewFetchOptionsBuilder(FetchOptionsBuilderOptions{
+		Timeout:                   10 * time.Second,
+		SnapshotCompletenessDelay: time.Hour,
+	})
+	require.NoError(t, err)
+
+	complete := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	req := httptest.NewRequest("GET", "/read", nil)
+	req.Header.Set(headers.SnapshotTimeHeader, complete.Format(time.RFC3339))
+	_, opts, err := builder.NewFetchOptions(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, complete.Equal(opts.SnapshotTime))
+	assert.True(t, opts.SnapshotComplete)
+
+	incomplete := time.Now().Add(-time.Minute).Truncate(time.Second)
+	req.Header.Set(headers.SnapshotTimeHeader, incomplete.Format(time.RFC3339))
+	_, opts, err = builder.NewFetchOptions(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, incomplete.Equal(opts.SnapshotTime))
+	assert.False(t, opts.SnapshotComplete)
+
+	req.Header.Set(headers.SnapshotTimeHeader, time.Now().Add(time.Hour).Format(time.RFC3339))
+	_, _, err = builder.NewFetchOptions(context.Background(), req)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
 func TestParseRequestTimeout(t *testing.T) {
 	req := httptest.NewRequest("GET", "/read?timeout=2m", nil)
 	dur, err := ParseRequestTimeout(req, time.Second)
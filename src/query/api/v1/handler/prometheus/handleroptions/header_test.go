@@ -69,6 +69,16 @@ func TestAddDBResultResponseHeaders(t *testing.T) {
 	assert.Equal(t, 1, len(recorder.Header()))
 	assert.Equal(t, "5s", recorder.Header().Get(headers.TimeoutHeader))
 
+	recorder = httptest.NewRecorder()
+	require.NoError(t, AddDBResultResponseHeaders(recorder, meta, &storage.FetchOptions{
+		Timeout:          5 * time.Second,
+		SnapshotTime:     time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC),
+		SnapshotComplete: true,
+	}))
+	assert.Equal(t, 3, len(recorder.Header()))
+	assert.Equal(t, "2020-09-13T12:00:00Z", recorder.Header().Get(headers.SnapshotTimeHeader))
+	assert.Equal(t, "true", recorder.Header().Get(headers.SnapshotCompleteHeader))
+
 	recorder = httptest.NewRecorder()
 	meta = block.NewResultMetadata()
 	meta.WaitedIndex = 3
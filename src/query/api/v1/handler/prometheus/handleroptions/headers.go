@@ -24,7 +24,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/storage"
@@ -79,6 +81,12 @@ func AddDBResultResponseHeaders(
 ) error {
 	if fetchOpts != nil {
 		w.Header().Set(headers.TimeoutHeader, fetchOpts.Timeout.String())
+		if !fetchOpts.SnapshotTime.IsZero() {
+			w.Header().Set(headers.SnapshotTimeHeader,
+				fetchOpts.SnapshotTime.UTC().Format(time.RFC3339Nano))
+			w.Header().Set(headers.SnapshotCompleteHeader,
+				strconv.FormatBool(fetchOpts.SnapshotComplete))
+		}
 	}
 
 	waiting := Waiting{
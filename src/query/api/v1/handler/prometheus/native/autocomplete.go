@@ -0,0 +1,270 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/errors"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// AutocompleteURL is the url for autocompleting label values by prefix.
+	AutocompleteURL = route.Prefix + "/label/{" + route.NameReplace + "}/autocomplete"
+
+	prefixParam  = "prefix"
+	limitParam   = "limit"
+	recencyParam = "recency"
+
+	defaultAutocompleteLimit = 100
+)
+
+// AutocompleteHTTPMethods are the HTTP methods for this handler.
+var AutocompleteHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+// AutocompleteHandler returns the values of a label that start with a
+// prefix. The prefix is pushed down to the index as an anchored regexp with
+// a literal prefix, which the index resolves with a range scan over its
+// sorted terms rather than evaluating the regexp against every value, and
+// the limit is pushed down as the aggregate query limit. When a recency
+// window is set, values seen within the window are returned ahead of
+// values only seen earlier in the queried range.
+type AutocompleteHandler struct {
+	storage             storage.Storage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	parseOpts           promql.ParseOptions
+	instrumentOpts      instrument.Options
+	tagOpts             models.TagOptions
+}
+
+// NewAutocompleteHandler returns a new instance of handler.
+func NewAutocompleteHandler(opts options.HandlerOptions) http.Handler {
+	return &AutocompleteHandler{
+		storage:             opts.Storage(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		parseOpts: promql.NewParseOptions().
+			SetRequireStartEndTime(opts.Config().Query.RequireLabelsEndpointStartEndTime).
+			SetNowFn(opts.NowFn()),
+		instrumentOpts: opts.InstrumentOpts(),
+		tagOpts:        opts.TagOptions(),
+	}
+}
+
+type autocompleteResponse struct {
+	Status string   `json:"status"`
+	Data   []string `json:"data"`
+}
+
+type autocompleteParams struct {
+	name     []byte
+	matchers models.Matchers
+	start    time.Time
+	end      time.Time
+	limit    int
+	recency  time.Duration
+}
+
+func (h *AutocompleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	ctx, opts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if rErr != nil {
+		xhttp.WriteError(w, rErr)
+		return
+	}
+
+	params, err := h.parseParams(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	values, meta, err := h.autocomplete(ctx, params, opts)
+	if err != nil {
+		logger.Error("unable to autocomplete label values", zap.Error(err))
+		if errors.IsTimeout(err) {
+			err = errors.NewErrQueryTimeout(err)
+		}
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if err := handleroptions.AddDBResultResponseHeaders(w, meta, opts); err != nil {
+		logger.Error("error writing database limit headers", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, autocompleteResponse{
+		Status: "success",
+		Data:   values,
+	}, logger)
+}
+
+func (h *AutocompleteHandler) parseParams(r *http.Request) (autocompleteParams, error) {
+	params := autocompleteParams{limit: defaultAutocompleteLimit}
+
+	name, ok := mux.Vars(r)[route.NameReplace]
+	if !ok || len(name) == 0 {
+		return params, xhttp.NewError(errors.ErrNoName, http.StatusBadRequest)
+	}
+	params.name = []byte(name)
+
+	start, end, err := prometheus.ParseStartAndEnd(r, h.parseOpts)
+	if err != nil {
+		return params, err
+	}
+	params.start, params.end = start, end
+
+	// An empty prefix matches every value of the label.
+	valueMatcher, err := models.NewMatcher(models.MatchField, params.name, nil)
+	if prefix := r.FormValue(prefixParam); prefix != "" {
+		valueMatcher, err = models.NewMatcher(models.MatchRegexp, params.name,
+			[]byte(regexp.QuoteMeta(prefix)+".*"))
+	}
+	if err != nil {
+		return params, xerrors.NewInvalidParamsError(err)
+	}
+	params.matchers = models.Matchers{valueMatcher}
+
+	matches, ok, err := prometheus.ParseMatch(r, h.parseOpts, h.tagOpts)
+	if err != nil {
+		return params, xerrors.NewInvalidParamsError(err)
+	}
+	if ok {
+		if n := len(matches); n != 1 {
+			return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"only single tag matcher allowed: actual=%d", n))
+		}
+		params.matchers = append(params.matchers, matches[0].Matchers...)
+	}
+
+	if str := r.FormValue(limitParam); str != "" {
+		v, err := strconv.Atoi(str)
+		if err != nil || v <= 0 {
+			return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"invalid %s, must be a positive integer: %s", limitParam, str))
+		}
+		params.limit = v
+	}
+
+	if r.FormValue(recencyParam) != "" {
+		recency, err := handleroptions.ParseDuration(r, recencyParam)
+		if err != nil || recency <= 0 {
+			return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"invalid %s, must be a positive duration: %s",
+				recencyParam, r.FormValue(recencyParam)))
+		}
+		params.recency = recency
+	}
+
+	return params, nil
+}
+
+func (h *AutocompleteHandler) autocomplete(
+	ctx context.Context,
+	params autocompleteParams,
+	opts *storage.FetchOptions,
+) ([]string, block.ResultMetadata, error) {
+	// Only up to the limit of values are needed, and hitting the limit is
+	// expected rather than an error for autocomplete.
+	opts = opts.Clone()
+	if opts.SeriesLimit <= 0 || opts.SeriesLimit > params.limit {
+		opts.SeriesLimit = params.limit
+	}
+	opts.RequireExhaustive = false
+
+	var (
+		values = make([]string, 0, params.limit)
+		seen   = make(map[string]struct{}, params.limit)
+		meta   = block.NewResultMetadata()
+		ranges = []time.Time{params.start}
+	)
+	if recentStart := params.end.Add(-params.recency); params.recency > 0 &&
+		recentStart.After(params.start) {
+		ranges = []time.Time{recentStart, params.start}
+	}
+
+	for i, start := range ranges {
+		result, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+			CompleteNameOnly: false,
+			FilterNameTags:   [][]byte{params.name},
+			TagMatchers:      params.matchers,
+			Start:            xtime.ToUnixNano(start),
+			End:              xtime.ToUnixNano(params.end),
+		}, opts)
+		if err != nil {
+			return nil, block.ResultMetadata{}, err
+		}
+		meta = meta.CombineMetadata(result.Metadata)
+
+		// Values from a more recent range rank ahead of values from the
+		// full range, values within each range are in lexical order.
+		var found []string
+		for _, tag := range result.CompletedTags {
+			for _, value := range tag.Values {
+				if _, ok := seen[string(value)]; ok {
+					continue
+				}
+				seen[string(value)] = struct{}{}
+				found = append(found, string(value))
+			}
+		}
+		sort.Strings(found)
+		values = append(values, found...)
+		if len(values) > params.limit {
+			values = values[:params.limit]
+			meta.Exhaustive = false
+		}
+		if len(values) == params.limit {
+			if i < len(ranges)-1 {
+				// Older values are not considered once the limit is reached.
+				meta.Exhaustive = false
+			}
+			break
+		}
+	}
+
+	return values, meta, nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func newTestAutocompleteHandler(
+	t *testing.T,
+	store storage.Storage,
+	now time.Time,
+) http.Handler {
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{Timeout: 15 * time.Second})
+	require.NoError(t, err)
+	opts := options.EmptyHandlerOptions().
+		SetStorage(store).
+		SetFetchOptionsBuilder(fb).
+		SetTagOptions(models.NewTagOptions()).
+		SetNowFn(func() time.Time { return now })
+	return NewAutocompleteHandler(opts)
+}
+
+func serveTestAutocomplete(
+	t *testing.T,
+	h http.Handler,
+	name, query string,
+) (*httptest.ResponseRecorder, autocompleteResponse) {
+	req := httptest.NewRequest(http.MethodGet, "/label/"+name+"/autocomplete?"+query, nil)
+	req = mux.SetURLVars(req, map[string]string{"name": name})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp autocompleteResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w, resp
+}
+
+func autocompleteResult(values ...string) *consolidators.CompleteTagsResult {
+	tag := consolidators.CompletedTag{Name: b("job")}
+	for _, v := range values {
+		tag.Values = append(tag.Values, b(v))
+	}
+	return &consolidators.CompleteTagsResult{
+		CompletedTags: []consolidators.CompletedTag{tag},
+		Metadata:      block.NewResultMetadata(),
+	}
+}
+
+func TestAutocompletePrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ interface{},
+			q *storage.CompleteTagsQuery,
+			opts *storage.FetchOptions,
+		) (*consolidators.CompleteTagsResult, error) {
+			require.False(t, q.CompleteNameOnly)
+			require.Equal(t, [][]byte{b("job")}, q.FilterNameTags)
+			require.Equal(t, 2, len(q.TagMatchers))
+			require.Equal(t, models.MatchRegexp, q.TagMatchers[0].Type)
+			require.Equal(t, "job", string(q.TagMatchers[0].Name))
+			require.Equal(t, `api\.v1.*`, string(q.TagMatchers[0].Value))
+			require.Equal(t, "__name__", string(q.TagMatchers[1].Name))
+			require.Equal(t, 5, opts.SeriesLimit)
+			require.False(t, opts.RequireExhaustive)
+			return autocompleteResult("api.v1.b", "api.v1.a"), nil
+		})
+
+	h := newTestAutocompleteHandler(t, store, time.Now())
+	w, resp := serveTestAutocomplete(t, h, "job",
+		"prefix=api.v1&limit=5&match[]=up")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []string{"api.v1.a", "api.v1.b"}, resp.Data)
+}
+
+func TestAutocompleteRecency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now   = time.Now().Truncate(time.Second)
+		start = now.Add(-24 * time.Hour)
+	)
+
+	store := storage.NewMockStorage(ctrl)
+	gomock.InOrder(
+		store.EXPECT().
+			CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ interface{},
+				q *storage.CompleteTagsQuery,
+				_ *storage.FetchOptions,
+			) (*consolidators.CompleteTagsResult, error) {
+				require.Equal(t, xtime.ToUnixNano(now.Add(-time.Hour)), q.Start)
+				require.Equal(t, xtime.ToUnixNano(now), q.End)
+				return autocompleteResult("web"), nil
+			}),
+		store.EXPECT().
+			CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ interface{},
+				q *storage.CompleteTagsQuery,
+				_ *storage.FetchOptions,
+			) (*consolidators.CompleteTagsResult, error) {
+				require.Equal(t, xtime.ToUnixNano(start), q.Start)
+				require.Equal(t, models.MatchField, q.TagMatchers[0].Type)
+				return autocompleteResult("api", "db", "web"), nil
+			}),
+	)
+
+	h := newTestAutocompleteHandler(t, store, now)
+	query := "recency=1h&limit=2" +
+		"&start=" + now.Add(-24*time.Hour).Format(time.RFC3339) +
+		"&end=" + now.Format(time.RFC3339)
+	w, resp := serveTestAutocomplete(t, h, "job", query)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// Recently seen values rank first regardless of lexical order.
+	require.Equal(t, []string{"web", "api"}, resp.Data)
+}
+
+func TestAutocompleteInvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestAutocompleteHandler(t, storage.NewMockStorage(ctrl), time.Now())
+	for _, query := range []string{
+		"limit=0",
+		"limit=abc",
+		"recency=-1h",
+		"match[]=up&match[]=down",
+	} {
+		w, _ := serveTestAutocomplete(t, h, "job", query)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/errors"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CardinalityURL is the url for exploring the cardinality of series.
+	CardinalityURL = route.Prefix + "/cardinality"
+
+	metricParam      = "metric"
+	labelsLimitParam = "labelsLimit"
+	valuesLimitParam = "valuesLimit"
+
+	defaultCardinalityLabelsLimit = 10
+	defaultCardinalityValuesLimit = 10
+	defaultCardinalitySeriesLimit = 10000
+	defaultCardinalityDocsLimit   = 100000
+)
+
+// CardinalityHTTPMethods are the HTTP methods for this handler.
+var CardinalityHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+// CardinalityHandler returns the label keys with the most distinct values
+// and the values of those keys with the most series for series matching a
+// metric name or matcher, to help debug high cardinality. Distinct values and
+// series counts are computed over the matching series and index documents
+// bounded by the series and docs limits.
+type CardinalityHandler struct {
+	storage             storage.Storage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	parseOpts           promql.ParseOptions
+	instrumentOpts      instrument.Options
+	tagOpts             models.TagOptions
+}
+
+// NewCardinalityHandler returns a new instance of handler.
+func NewCardinalityHandler(opts options.HandlerOptions) http.Handler {
+	return &CardinalityHandler{
+		storage:             opts.Storage(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		parseOpts:           promql.NewParseOptions().SetNowFn(opts.NowFn()),
+		instrumentOpts:      opts.InstrumentOpts(),
+		tagOpts:             opts.TagOptions(),
+	}
+}
+
+type cardinalityResponse struct {
+	Status string            `json:"status"`
+	Data   CardinalityResult `json:"data"`
+}
+
+// CardinalityResult is the result of a cardinality query.
+type CardinalityResult struct {
+	// SeriesSampled is the number of series the top values were counted over.
+	SeriesSampled int `json:"seriesSampled"`
+	// Exhaustive is false if the results were computed over a subset of
+	// the matching series or label values due to limits.
+	Exhaustive bool `json:"exhaustive"`
+	// Labels are the label keys with the most distinct values.
+	Labels []CardinalityLabel `json:"labels"`
+}
+
+// CardinalityLabel is the cardinality of a label key.
+type CardinalityLabel struct {
+	Name           string             `json:"name"`
+	DistinctValues int                `json:"distinctValues"`
+	TopValues      []CardinalityValue `json:"topValues"`
+}
+
+// CardinalityValue is the number of series with a label value.
+type CardinalityValue struct {
+	Value       string `json:"value"`
+	SeriesCount int    `json:"seriesCount"`
+}
+
+type cardinalityParams struct {
+	matchers    models.Matchers
+	start       time.Time
+	end         time.Time
+	labelsLimit int
+	valuesLimit int
+}
+
+func (h *CardinalityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	ctx, opts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if rErr != nil {
+		xhttp.WriteError(w, rErr)
+		return
+	}
+
+	params, err := h.parseParams(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	result, meta, err := h.cardinality(ctx, params, opts)
+	if err != nil {
+		logger.Error("unable to compute cardinality", zap.Error(err))
+		if errors.IsTimeout(err) {
+			err = errors.NewErrQueryTimeout(err)
+		}
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if err := handleroptions.AddDBResultResponseHeaders(w, meta, opts); err != nil {
+		logger.Error("error writing database limit headers", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, cardinalityResponse{
+		Status: "success",
+		Data:   result,
+	}, logger)
+}
+
+func (h *CardinalityHandler) parseParams(r *http.Request) (cardinalityParams, error) {
+	params := cardinalityParams{
+		labelsLimit: defaultCardinalityLabelsLimit,
+		valuesLimit: defaultCardinalityValuesLimit,
+	}
+
+	start, end, err := prometheus.ParseStartAndEnd(r, h.parseOpts)
+	if err != nil {
+		return params, err
+	}
+	params.start, params.end = start, end
+
+	matches, ok, err := prometheus.ParseMatch(r, h.parseOpts, h.tagOpts)
+	if err != nil {
+		return params, xerrors.NewInvalidParamsError(err)
+	}
+	metric := r.FormValue(metricParam)
+	switch {
+	case ok && metric != "":
+		return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+			"only one of %s or match[] may be set", metricParam))
+	case ok:
+		if n := len(matches); n != 1 {
+			return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"only single tag matcher allowed: actual=%d", n))
+		}
+		params.matchers = matches[0].Matchers
+	case metric != "":
+		matcher, err := models.NewMatcher(models.MatchEqual,
+			h.tagOpts.MetricName(), []byte(metric))
+		if err != nil {
+			return params, xerrors.NewInvalidParamsError(err)
+		}
+		params.matchers = models.Matchers{matcher}
+	default:
+		return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+			"one of %s or match[] must be set", metricParam))
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{
+		{name: labelsLimitParam, value: &params.labelsLimit},
+		{name: valuesLimitParam, value: &params.valuesLimit},
+	} {
+		str := r.FormValue(p.name)
+		if str == "" {
+			continue
+		}
+		v, err := strconv.Atoi(str)
+		if err != nil || v <= 0 {
+			return params, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"invalid %s, must be a positive integer: %s", p.name, str))
+		}
+		*p.value = v
+	}
+
+	return params, nil
+}
+
+func (h *CardinalityHandler) cardinality(
+	ctx context.Context,
+	params cardinalityParams,
+	opts *storage.FetchOptions,
+) (CardinalityResult, block.ResultMetadata, error) {
+	// Both queries are bounded by the series and docs limits of the request,
+	// and hitting the limits is expected rather than an error since the
+	// results are reported as not exhaustive.
+	opts = opts.Clone()
+	if opts.SeriesLimit <= 0 {
+		opts.SeriesLimit = defaultCardinalitySeriesLimit
+	}
+	if opts.DocsLimit <= 0 {
+		opts.DocsLimit = defaultCardinalityDocsLimit
+	}
+	opts.RequireExhaustive = false
+
+	// Distinct values per label key come from an index aggregate query,
+	// which does not need to read every matching series.
+	tagsResult, err := h.storage.CompleteTags(ctx, &storage.CompleteTagsQuery{
+		TagMatchers: params.matchers,
+		Start:       xtime.ToUnixNano(params.start),
+		End:         xtime.ToUnixNano(params.end),
+	}, opts)
+	if err != nil {
+		return CardinalityResult{}, block.ResultMetadata{}, err
+	}
+
+	// Series counts per value are computed over a sample of the matching series.
+	searchResult, err := h.storage.SearchSeries(ctx, &storage.FetchQuery{
+		TagMatchers: params.matchers,
+		Start:       params.start,
+		End:         params.end,
+	}, opts)
+	if err != nil {
+		return CardinalityResult{}, block.ResultMetadata{}, err
+	}
+
+	seriesCounts := make(map[string]map[string]int, len(tagsResult.CompletedTags))
+	for _, metric := range searchResult.Metrics {
+		for _, tag := range metric.Tags.Tags {
+			counts, ok := seriesCounts[string(tag.Name)]
+			if !ok {
+				counts = make(map[string]int)
+				seriesCounts[string(tag.Name)] = counts
+			}
+			counts[string(tag.Value)]++
+		}
+	}
+
+	labels := make([]CardinalityLabel, 0, len(tagsResult.CompletedTags))
+	for _, tag := range tagsResult.CompletedTags {
+		labels = append(labels, CardinalityLabel{
+			Name:           string(tag.Name),
+			DistinctValues: len(tag.Values),
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].DistinctValues != labels[j].DistinctValues {
+			return labels[i].DistinctValues > labels[j].DistinctValues
+		}
+		return labels[i].Name < labels[j].Name
+	})
+	if len(labels) > params.labelsLimit {
+		labels = labels[:params.labelsLimit]
+	}
+
+	for i := range labels {
+		counts := seriesCounts[labels[i].Name]
+		values := make([]CardinalityValue, 0, len(counts))
+		for value, count := range counts {
+			values = append(values, CardinalityValue{Value: value, SeriesCount: count})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].SeriesCount != values[j].SeriesCount {
+				return values[i].SeriesCount > values[j].SeriesCount
+			}
+			return values[i].Value < values[j].Value
+		})
+		if len(values) > params.valuesLimit {
+			values = values[:params.valuesLimit]
+		}
+		labels[i].TopValues = values
+	}
+
+	meta := tagsResult.Metadata.CombineMetadata(searchResult.Metadata)
+	return CardinalityResult{
+		SeriesSampled: len(searchResult.Metrics),
+		Exhaustive:    meta.Exhaustive,
+		Labels:        labels,
+	}, meta, nil
+}
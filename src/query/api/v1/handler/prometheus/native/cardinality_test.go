@@ -0,0 +1,161 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+)
+
+func newTestCardinalityHandler(t *testing.T, store storage.Storage) http.Handler {
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{Timeout: 15 * time.Second})
+	require.NoError(t, err)
+	opts := options.EmptyHandlerOptions().
+		SetStorage(store).
+		SetFetchOptionsBuilder(fb).
+		SetTagOptions(models.NewTagOptions()).
+		SetNowFn(time.Now)
+	return NewCardinalityHandler(opts)
+}
+
+func testCardinalityMetric(tags ...string) models.Metric {
+	t := models.NewTags(len(tags)/2, nil)
+	for i := 0; i < len(tags); i += 2 {
+		t = t.AddTag(models.Tag{Name: b(tags[i]), Value: b(tags[i+1])})
+	}
+	return models.Metric{ID: t.ID(), Tags: t}
+}
+
+func TestCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ interface{},
+			q *storage.CompleteTagsQuery,
+			opts *storage.FetchOptions,
+		) (*consolidators.CompleteTagsResult, error) {
+			require.Equal(t, 3, opts.SeriesLimit)
+			require.Equal(t, defaultCardinalityDocsLimit, opts.DocsLimit)
+			require.False(t, opts.RequireExhaustive)
+			require.False(t, q.CompleteNameOnly)
+			require.Equal(t, 1, len(q.TagMatchers))
+			require.Equal(t, "__name__", string(q.TagMatchers[0].Name))
+			require.Equal(t, "http_requests", string(q.TagMatchers[0].Value))
+			return &consolidators.CompleteTagsResult{
+				CompletedTags: []consolidators.CompletedTag{
+					{Name: b("__name__"), Values: [][]byte{b("http_requests")}},
+					{Name: b("instance"), Values: [][]byte{b("a"), b("b"), b("c")}},
+					{Name: b("path"), Values: [][]byte{b("/a"), b("/b")}},
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+	store.EXPECT().
+		SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ interface{},
+			_ *storage.FetchQuery,
+			opts *storage.FetchOptions,
+		) (*storage.SearchResults, error) {
+			require.Equal(t, 3, opts.SeriesLimit)
+			require.Equal(t, defaultCardinalityDocsLimit, opts.DocsLimit)
+			meta := block.NewResultMetadata()
+			meta.Exhaustive = false
+			return &storage.SearchResults{
+				Metrics: models.Metrics{
+					testCardinalityMetric("__name__", "http_requests", "instance", "a", "path", "/a"),
+					testCardinalityMetric("__name__", "http_requests", "instance", "a", "path", "/b"),
+					testCardinalityMetric("__name__", "http_requests", "instance", "b", "path", "/a"),
+				},
+				Metadata: meta,
+			}, nil
+		})
+
+	params := url.Values{}
+	params.Set("metric", "http_requests")
+	params.Set("labelsLimit", "2")
+	params.Set("valuesLimit", "1")
+	params.Set("limit", "3")
+	req := httptest.NewRequest(http.MethodGet, CardinalityURL+"?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+	newTestCardinalityHandler(t, store).ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp cardinalityResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, cardinalityResponse{
+		Status: "success",
+		Data: CardinalityResult{
+			SeriesSampled: 3,
+			Exhaustive:    false,
+			Labels: []CardinalityLabel{
+				{
+					Name:           "instance",
+					DistinctValues: 3,
+					TopValues:      []CardinalityValue{{Value: "a", SeriesCount: 2}},
+				},
+				{
+					Name:           "path",
+					DistinctValues: 2,
+					TopValues:      []CardinalityValue{{Value: "/a", SeriesCount: 2}},
+				},
+			},
+		},
+	}, resp)
+}
+
+func TestCardinalityInvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestCardinalityHandler(t, storage.NewMockStorage(ctrl))
+	for _, query := range []string{
+		"",
+		"metric=foo&match[]=bar",
+		"match[]=foo&match[]=bar",
+		"metric=foo&labelsLimit=0",
+		"metric=foo&valuesLimit=abc",
+	} {
+		req := httptest.NewRequest(http.MethodGet, CardinalityURL+"?"+query, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -68,10 +68,19 @@ func parseParams(
 		return params, xerrors.NewInvalidParamsError(err)
 	}
 
-	timeParams, err := prometheus.ParseTimeParams(r)
+	now := time.Now()
+	if snapshotTime := fetchOpts.SnapshotTime; !snapshotTime.IsZero() {
+		now = snapshotTime
+	}
+	timeParams, err := prometheus.ParseTimeParamsAt(r, now)
 	if err != nil {
 		return params, err
 	}
+	if !fetchOpts.SnapshotTime.IsZero() && timeParams.End.After(fetchOpts.SnapshotTime) {
+		err := fmt.Errorf("end (%s) must not be after the snapshot time (%s)",
+			timeParams.End, fetchOpts.SnapshotTime)
+		return params, xerrors.NewInvalidParamsError(err)
+	}
 
 	params.Now = timeParams.Now
 	params.Start = xtime.ToUnixNano(timeParams.Start)
@@ -80,7 +89,7 @@ func parseParams(
 	timeout := fetchOpts.Timeout
 	if timeout <= 0 {
 		err := fmt.Errorf("expected positive timeout, instead got: %d", timeout)
-		return params, xerrors.NewInvalidParamsError(
+		return params, xerrors.NewInvalidParamError(handleroptions.TimeoutParam,
 			fmt.Errorf(formatErrStr, handleroptions.TimeoutParam, err))
 	}
 	params.Timeout = timeout
@@ -88,14 +97,14 @@ func parseParams(
 	step := fetchOpts.Step
 	if step <= 0 {
 		err := fmt.Errorf("expected positive step size, instead got: %d", step)
-		return params, xerrors.NewInvalidParamsError(
+		return params, xerrors.NewInvalidParamError(handleroptions.StepParam,
 			fmt.Errorf(formatErrStr, handleroptions.StepParam, err))
 	}
 	params.Step = fetchOpts.Step
 
 	query, err := ParseQuery(r)
 	if err != nil {
-		return params, xerrors.NewInvalidParamsError(
+		return params, xerrors.NewInvalidParamError(QueryParam,
 			fmt.Errorf(formatErrStr, QueryParam, err))
 	}
 	params.Query = query
@@ -103,7 +112,7 @@ func parseParams(
 	if debugVal := r.FormValue(debugParam); debugVal != "" {
 		params.Debug, err = strconv.ParseBool(debugVal)
 		if err != nil {
-			return params, xerrors.NewInvalidParamsError(
+			return params, xerrors.NewInvalidParamError(debugParam,
 				fmt.Errorf(formatErrStr, debugParam, err))
 		}
 	}
@@ -112,7 +121,7 @@ func parseParams(
 	if blockType := r.FormValue(blockTypeParam); blockType != "" {
 		intVal, err := strconv.ParseInt(blockType, 10, 8)
 		if err != nil {
-			return params, xerrors.NewInvalidParamsError(
+			return params, xerrors.NewInvalidParamError(blockTypeParam,
 				fmt.Errorf(formatErrStr, blockTypeParam, err))
 		}
 
@@ -120,7 +129,7 @@ func parseParams(
 
 		// Ignore error from receiving an invalid block type, and return default.
 		if err := blockType.Validate(); err != nil {
-			return params, xerrors.NewInvalidParamsError(
+			return params, xerrors.NewInvalidParamError(blockTypeParam,
 				fmt.Errorf(formatErrStr, blockTypeParam, err))
 		}
 
@@ -133,7 +142,7 @@ func parseParams(
 	if endExclusiveVal != "" {
 		excludeEnd, err := strconv.ParseBool(endExclusiveVal)
 		if err != nil {
-			return params, xerrors.NewInvalidParamsError(
+			return params, xerrors.NewInvalidParamError(endExclusiveParam,
 				fmt.Errorf(formatErrStr, endExclusiveParam, err))
 		}
 
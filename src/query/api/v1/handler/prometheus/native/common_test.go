@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/json"
 	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/headers"
 	xjson "github.com/m3db/m3/src/x/json"
 	xhttp "github.com/m3db/m3/src/x/net/http"
 	xtest "github.com/m3db/m3/src/x/test"
@@ -119,6 +120,36 @@ func TestInstantaneousParamParsing(t *testing.T) {
 	require.Equal(t, promQuery, r.Query)
 }
 
+func TestInstantaneousParamParsingSnapshotTime(t *testing.T) {
+	snapshotTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	req := httptest.NewRequest("GET", PromReadURL, nil)
+	req.Header.Set(headers.SnapshotTimeHeader, snapshotTime.Format(time.RFC3339))
+	params := url.Values{}
+	params.Add(QueryParam, promQuery)
+	req.URL.RawQuery = params.Encode()
+	fetchOptsBuilder, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{
+			Timeout: 10 * time.Second,
+		})
+	require.NoError(t, err)
+	_, fetchOpts, err := fetchOptsBuilder.NewFetchOptions(req.Context(), req)
+	require.NoError(t, err)
+
+	// Queries without a time are evaluated as of the snapshot time.
+	r, err := parseInstantaneousParams(req, executor.NewEngineOptions(), fetchOpts)
+	require.NoError(t, err)
+	require.True(t, snapshotTime.Equal(r.Now))
+	require.True(t, snapshotTime.Equal(r.End.ToTime()))
+
+	// Queries can not be evaluated after the snapshot time.
+	params.Set(timeParam, time.Now().Format(time.RFC3339))
+	req.URL.RawQuery = params.Encode()
+	req.Form = nil
+	_, err = parseInstantaneousParams(req, executor.NewEngineOptions(), fetchOpts)
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+}
+
 func TestInvalidStart(t *testing.T) {
 	req := httptest.NewRequest("GET", PromReadURL, nil)
 	vals := defaultParams()
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// PromExplainURL is the url for the native explain handler, this takes the
+	// same parameters as the query range endpoint and returns the logical and
+	// physical plans the M3 query engine builds for the query without
+	// executing it.
+	PromExplainURL = route.Prefix + "/explain"
+)
+
+// PromExplainHTTPMethods are the HTTP methods for this handler.
+var PromExplainHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+type promExplainHandler struct {
+	opts options.HandlerOptions
+}
+
+type explainResponse struct {
+	Status string               `json:"status"`
+	Data   executor.Explanation `json:"data"`
+}
+
+// NewPromExplainHandler returns a new instance of handler.
+func NewPromExplainHandler(opts options.HandlerOptions) http.Handler {
+	return &promExplainHandler{
+		opts: opts,
+	}
+}
+
+func (h *promExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	logger := logging.WithContext(r.Context(), h.opts.InstrumentOpts())
+	ctx, parsed, err := ParseRequest(r.Context(), r, false, h.opts)
+	if err != nil {
+		logger.Error("could not parse request", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	var (
+		params    = parsed.Params
+		engine    = h.opts.Engine()
+		parseOpts = engine.Options().ParseOptions()
+	)
+	parser, err := promql.Parse(params.Query, params.Step,
+		h.opts.TagOptions(), parseOpts)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	explanation, err := engine.Explain(ctx, parser, parsed.FetchOpts, params)
+	if err != nil {
+		logger.Error("unable to explain query",
+			zap.String("query", params.Query), zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, explainResponse{
+		Status: "success",
+		Data:   explanation,
+	}, logger)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromExplainHandler(t *testing.T) {
+	setup := newTestSetup(t, nil)
+	attrs := []storagemetadata.Attributes{
+		{
+			MetricsType: storagemetadata.UnaggregatedMetricsType,
+			Retention:   48 * time.Hour,
+		},
+	}
+	setup.Storage.SetQueryStorageMetadataAttributesResult(attrs, nil)
+
+	h := NewPromExplainHandler(setup.options)
+	vals := defaultParams()
+	vals.Set(QueryParam, "sum(rate(foo[1m]))")
+	req := httptest.NewRequest(http.MethodGet,
+		PromExplainURL+"?"+vals.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Status string               `json:"status"`
+		Data   executor.Explanation `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, "sum(rate(foo[1m]))", resp.Data.Query)
+	require.Len(t, resp.Data.LogicalPlan, 3)
+	require.Len(t, resp.Data.PhysicalPlan.Steps, 3)
+	require.Len(t, resp.Data.PhysicalPlan.Fetches, 1)
+
+	fetch := resp.Data.PhysicalPlan.Fetches[0]
+	assert.Equal(t, "1m0s", fetch.Range)
+	assert.Equal(t, []string{attrs[0].String()}, fetch.Namespaces)
+}
+
+func TestPromExplainHandlerInvalidQuery(t *testing.T) {
+	setup := newTestSetup(t, nil)
+	h := NewPromExplainHandler(setup.options)
+	vals := defaultParams()
+	vals.Set(QueryParam, "sum(")
+	req := httptest.NewRequest(http.MethodGet,
+		PromExplainURL+"?"+vals.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// IngestAccountingURL is the url for the top sources of ingested traffic.
+	IngestAccountingURL = route.Prefix + "/ingest/accounting"
+
+	sortByParam = "sortBy"
+	windowParam = "window"
+
+	currentWindow  = "current"
+	previousWindow = "previous"
+)
+
+// IngestAccountingHTTPMethods are the HTTP methods for this handler.
+var IngestAccountingHTTPMethods = []string{http.MethodGet}
+
+var errIngestAccountingDisabled = xhttp.NewError(
+	errors.New("ingest accounting is not enabled"), http.StatusNotFound)
+
+// IngestAccountingHandler returns the top sources of the series and
+// datapoints ingested through Prometheus remote write.
+type IngestAccountingHandler struct {
+	accountant     ingestaccounting.Accountant
+	instrumentOpts instrument.Options
+}
+
+// NewIngestAccountingHandler returns a new instance of handler.
+func NewIngestAccountingHandler(opts options.HandlerOptions) http.Handler {
+	return &IngestAccountingHandler{
+		accountant:     opts.IngestAccountant(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+type ingestAccountingResponse struct {
+	Status string                  `json:"status"`
+	Data   ingestaccounting.Report `json:"data"`
+}
+
+func (h *IngestAccountingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if h.accountant == nil {
+		xhttp.WriteError(w, errIngestAccountingDisabled)
+		return
+	}
+
+	q, err := parseIngestAccountingQuery(r)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+	xhttp.WriteJSONResponse(w, ingestAccountingResponse{
+		Status: "success",
+		Data:   h.accountant.TopSources(q),
+	}, logger)
+}
+
+func parseIngestAccountingQuery(r *http.Request) (ingestaccounting.Query, error) {
+	q := ingestaccounting.Query{SortBy: ingestaccounting.SortByDatapoints}
+	if str := r.FormValue(sortByParam); str != "" {
+		sortBy, err := ingestaccounting.ParseSortBy(str)
+		if err != nil {
+			return q, err
+		}
+		q.SortBy = sortBy
+	}
+
+	if str := r.FormValue(limitParam); str != "" {
+		v, err := strconv.Atoi(str)
+		if err != nil {
+			return q, fmt.Errorf("invalid %s, must be an integer: %s", limitParam, str)
+		}
+		q.Limit = v
+	}
+
+	switch str := r.FormValue(windowParam); str {
+	case "", currentWindow:
+	case previousWindow:
+		q.Previous = true
+	default:
+		return q, fmt.Errorf("invalid %s, must be one of: %s, %s",
+			windowParam, currentWindow, previousWindow)
+	}
+	return q, nil
+}
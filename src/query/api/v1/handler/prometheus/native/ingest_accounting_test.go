@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
+)
+
+func serveIngestAccounting(t *testing.T, handler http.Handler, url string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body, err := ioutil.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return w.Result().StatusCode, string(body)
+}
+
+func TestIngestAccountingHandler(t *testing.T) {
+	accountingOpts, err := ingestaccounting.NewOptions(
+		&config.IngestAccountingConfiguration{Enabled: true}, tally.NoopScope)
+	require.NoError(t, err)
+	accountant := ingestaccounting.NewAccountant(accountingOpts)
+
+	for _, addr := range []string{"10.0.0.1:1234", "10.0.0.1:1234", "10.0.0.2:1234"} {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/prom/remote/write", nil)
+		r.RemoteAddr = addr
+		accountant.Record(r, []prompb.TimeSeries{{
+			Labels:  []prompb.Label{{Name: []byte("__name__"), Value: []byte(addr)}},
+			Samples: []prompb.Sample{{Value: 1}},
+		}})
+	}
+
+	handler := NewIngestAccountingHandler(
+		options.EmptyHandlerOptions().SetIngestAccountant(accountant))
+
+	code, body := serveIngestAccounting(t, handler,
+		IngestAccountingURL+"?sortBy=requests&limit=1")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"remoteAddr":"10.0.0.1"`)
+	assert.NotContains(t, body, `"remoteAddr":"10.0.0.2"`)
+
+	code, body = serveIngestAccounting(t, handler, IngestAccountingURL+"?window=previous")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"sources":[]`)
+
+	for _, query := range []string{"?sortBy=bytes", "?limit=foo", "?window=last"} {
+		code, _ = serveIngestAccounting(t, handler, IngestAccountingURL+query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
+
+func TestIngestAccountingHandlerDisabled(t *testing.T) {
+	handler := NewIngestAccountingHandler(options.EmptyHandlerOptions())
+	code, _ := serveIngestAccounting(t, handler, IngestAccountingURL)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -209,7 +209,7 @@ func TestListErrorTags(t *testing.T) {
 		r, err := ioutil.ReadAll(body)
 		require.NoError(t, err)
 
-		require.JSONEq(t, `{"status":"error","error":"err"}`, string(r))
+		require.JSONEq(t, `{"status":"error","error":"err","code":"internal","retryable":false}`, string(r))
 	}
 }
 
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// MetadataURL is the url for metric metadata.
+	MetadataURL = route.Prefix + "/metadata"
+)
+
+// MetadataHTTPMethods are the HTTP methods for this handler.
+var MetadataHTTPMethods = []string{http.MethodGet}
+
+// MetadataHandler returns the HELP, TYPE and UNIT metadata of metric families
+// received with Prometheus remote write requests, in the format of the
+// Prometheus metadata API.
+type MetadataHandler struct {
+	store          metricmetadata.Store
+	instrumentOpts instrument.Options
+}
+
+// NewMetadataHandler returns a new instance of handler.
+func NewMetadataHandler(opts options.HandlerOptions) http.Handler {
+	return &MetadataHandler{
+		store:          opts.MetricMetadataStore(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+type metadataResponse struct {
+	Status string                      `json:"status"`
+	Data   map[string][]MetricMetadata `json:"data"`
+}
+
+// MetricMetadata is the metadata of a metric family.
+type MetricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	var limit int
+	if str := r.FormValue(limitParam); str != "" {
+		v, err := strconv.Atoi(str)
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"invalid %s, must be an integer: %s", limitParam, str)))
+			return
+		}
+		limit = v
+	}
+
+	data := make(map[string][]MetricMetadata)
+	if h.store != nil {
+		for name, m := range h.store.Metadata(r.FormValue(metricParam), limit) {
+			data[name] = []MetricMetadata{{
+				Type: metricTypeString(m.Type),
+				Help: m.Help,
+				Unit: m.Unit,
+			}}
+		}
+	}
+
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+	xhttp.WriteJSONResponse(w, metadataResponse{
+		Status: "success",
+		Data:   data,
+	}, logger)
+}
+
+// metricTypeString returns the metric type as named by the Prometheus
+// exposition format.
+func metricTypeString(t prompb.MetricType) string {
+	if t == prompb.MetricType_GAUGE_HISTOGRAM {
+		return "gaugehistogram"
+	}
+	return strings.ToLower(t.String())
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	xclock "github.com/m3db/m3/src/x/clock"
+)
+
+func newTestMetadataHandler(t *testing.T) (http.Handler, func()) {
+	metadataOpts, err := metricmetadata.NewOptions(
+		&config.MetricMetadataConfiguration{
+			Enabled:         true,
+			PersistInterval: 10 * time.Millisecond,
+		},
+		func() (kv.Store, error) { return mem.NewStore(), nil },
+		tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	store := metricmetadata.NewStore(metadataOpts)
+	require.NoError(t, store.Update([]prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total number of HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricType_GAUGE_HISTOGRAM,
+			MetricFamilyName: "queue_size",
+			Help:             "Size of the queue.",
+			Unit:             "items",
+		},
+	}))
+	// Metadata is only served once persisted in the background.
+	require.True(t, xclock.WaitUntil(func() bool {
+		return len(store.Metadata("", 0)) == 2
+	}, time.Minute))
+
+	opts := options.EmptyHandlerOptions().SetMetricMetadataStore(store)
+	return NewMetadataHandler(opts), store.Close
+}
+
+func serveMetadata(t *testing.T, handler http.Handler, url string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body, err := ioutil.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return w.Result().StatusCode, string(body)
+}
+
+func TestMetadataHandler(t *testing.T) {
+	handler, closeFn := newTestMetadataHandler(t)
+	defer closeFn()
+
+	code, body := serveMetadata(t, handler, MetadataURL)
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{
+		"status": "success",
+		"data": {
+			"http_requests_total": [
+				{"type": "counter", "help": "Total number of HTTP requests.", "unit": ""}
+			],
+			"queue_size": [
+				{"type": "gaugehistogram", "help": "Size of the queue.", "unit": "items"}
+			]
+		}
+	}`, body)
+
+	code, body = serveMetadata(t, handler, MetadataURL+"?metric=queue_size")
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{
+		"status": "success",
+		"data": {
+			"queue_size": [
+				{"type": "gaugehistogram", "help": "Size of the queue.", "unit": "items"}
+			]
+		}
+	}`, body)
+
+	code, body = serveMetadata(t, handler, MetadataURL+"?limit=1")
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{
+		"status": "success",
+		"data": {
+			"http_requests_total": [
+				{"type": "counter", "help": "Total number of HTTP requests.", "unit": ""}
+			]
+		}
+	}`, body)
+
+	code, _ = serveMetadata(t, handler, MetadataURL+"?limit=foo")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestMetadataHandlerDisabled(t *testing.T) {
+	handler := NewMetadataHandler(options.EmptyHandlerOptions())
+	code, body := serveMetadata(t, handler, MetadataURL)
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"status": "success", "data": {}}`, body)
+}
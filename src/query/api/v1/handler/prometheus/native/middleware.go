@@ -51,24 +51,31 @@ var WithQueryParams middleware.OverrideOptions = func(opts middleware.Options) m
 }
 
 // WithRangeQueryParamsAndRangeRewriting adds the range query request parameters to the
-// middleware options and enables range rewriting
+// middleware options and enables range rewriting, the query firewall and
+// sticky query routing.
 var WithRangeQueryParamsAndRangeRewriting middleware.OverrideOptions = func(
 	opts middleware.Options,
 ) middleware.Options {
 	opts = WithQueryParams(opts)
 	opts.PrometheusRangeRewrite.Enabled = true
+	opts.QueryFirewall.Enabled = true
+	opts.StickyQueryRouting.Enabled = true
 
 	return opts
 }
 
 // WithInstantQueryParamsAndRangeRewriting adds the instant query request parameters to the
-// middleware options and enables range rewriting
+// middleware options and enables range rewriting, the query firewall and
+// sticky query routing.
 var WithInstantQueryParamsAndRangeRewriting middleware.OverrideOptions = func(
 	opts middleware.Options,
 ) middleware.Options {
 	opts = WithQueryParams(opts)
 	opts.PrometheusRangeRewrite.Enabled = true
 	opts.PrometheusRangeRewrite.Instant = true
+	opts.QueryFirewall.Enabled = true
+	opts.QueryFirewall.Instant = true
+	opts.StickyQueryRouting.Enabled = true
 
 	return opts
 }
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+const (
+	maxDataPointsParam = "maxDataPoints"
+	consolidateByParam = "consolidateBy"
+
+	// consolidateByLTTB selects the largest triangle three buckets algorithm
+	// which keeps the visual shape of the series.
+	consolidateByLTTB = "lttb"
+)
+
+// stepConsolidationFns are the functions available to consolidate the
+// samples of each step-aligned bucket.
+var stepConsolidationFns = map[string]func(values []float64) float64{
+	"average": consolidateAverage,
+	"min":     consolidateMin,
+	"max":     consolidateMax,
+	"sum":     consolidateSum,
+	"first":   consolidateFirst,
+	"last":    consolidateLast,
+}
+
+// consolidateOptions are the options to consolidate raw query results to a
+// maximum number of datapoints per series.
+type consolidateOptions struct {
+	maxDataPoints int
+	consolidateBy string
+}
+
+func (o consolidateOptions) enabled() bool {
+	return o.maxDataPoints > 0
+}
+
+// parseConsolidateOptions parses the optional maxDataPoints and consolidateBy
+// params, consolidating with LTTB by default.
+func parseConsolidateOptions(r *http.Request) (consolidateOptions, error) {
+	var opts consolidateOptions
+	if str := strings.TrimSpace(r.FormValue(maxDataPointsParam)); str != "" {
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %v", maxDataPointsParam, err)
+		}
+		if n <= 0 {
+			return opts, fmt.Errorf("invalid %s: must be positive", maxDataPointsParam)
+		}
+		opts.maxDataPoints = n
+	}
+
+	opts.consolidateBy = consolidateByLTTB
+	if str := strings.TrimSpace(r.FormValue(consolidateByParam)); str != "" {
+		if _, ok := stepConsolidationFns[str]; !ok && str != consolidateByLTTB {
+			return opts, fmt.Errorf("invalid %s: %s", consolidateByParam, str)
+		}
+		opts.consolidateBy = str
+	}
+	return opts, nil
+}
+
+// consolidateResults consolidates the samples of every series of the results
+// exceeding the maximum number of datapoints, returning the number of series
+// consolidated.
+func consolidateResults(
+	req *prompb.ReadRequest,
+	results []*prompb.QueryResult,
+	opts consolidateOptions,
+) int {
+	if !opts.enabled() {
+		return 0
+	}
+
+	consolidated := 0
+	for i, result := range results {
+		if result == nil || i >= len(req.Queries) {
+			continue
+		}
+		query := req.Queries[i]
+		for _, series := range result.Timeseries {
+			if len(series.Samples) <= opts.maxDataPoints {
+				continue
+			}
+			series.Samples = consolidateSamples(series.Samples,
+				query.StartTimestampMs, query.EndTimestampMs, opts)
+			consolidated++
+		}
+	}
+	return consolidated
+}
+
+func consolidateSamples(
+	samples []prompb.Sample,
+	startMs, endMs int64,
+	opts consolidateOptions,
+) []prompb.Sample {
+	if len(samples) <= opts.maxDataPoints {
+		return samples
+	}
+	if fn, ok := stepConsolidationFns[opts.consolidateBy]; ok {
+		return consolidateSteps(samples, startMs, endMs, opts.maxDataPoints, fn)
+	}
+	return consolidateLTTB(samples, opts.maxDataPoints)
+}
+
+// consolidateSteps consolidates the samples into at most maxDataPoints buckets
+// of equal width aligned to the query start, each timestamped at its start.
+func consolidateSteps(
+	samples []prompb.Sample,
+	startMs, endMs int64,
+	maxDataPoints int,
+	fn func(values []float64) float64,
+) []prompb.Sample {
+	rangeMs := endMs - startMs
+	if rangeMs <= 0 {
+		startMs = samples[0].Timestamp
+		rangeMs = samples[len(samples)-1].Timestamp - startMs + 1
+	}
+	stepMs := (rangeMs + int64(maxDataPoints) - 1) / int64(maxDataPoints)
+	if stepMs <= 0 {
+		stepMs = 1
+	}
+
+	var (
+		result = make([]prompb.Sample, 0, maxDataPoints)
+		values = make([]float64, 0, len(samples)/maxDataPoints+1)
+		bucket = -1
+	)
+	flush := func() {
+		if len(values) == 0 {
+			return
+		}
+		result = append(result, prompb.Sample{
+			Timestamp: startMs + int64(bucket)*stepMs,
+			Value:     fn(values),
+		})
+		values = values[:0]
+	}
+	for _, s := range samples {
+		// Samples outside of the query range, e.g. fetched for a range
+		// selector, are consolidated into the first and last buckets.
+		idx := int((s.Timestamp - startMs) / stepMs)
+		if s.Timestamp < startMs {
+			idx = 0
+		}
+		if idx >= maxDataPoints {
+			idx = maxDataPoints - 1
+		}
+		if idx != bucket {
+			flush()
+			bucket = idx
+		}
+		values = append(values, s.Value)
+	}
+	flush()
+	return result
+}
+
+// consolidateLTTB downsamples the samples to maxDataPoints samples using the
+// largest triangle three buckets algorithm, which always keeps the first and
+// last samples and picks the most significant sample of each bucket between.
+func consolidateLTTB(samples []prompb.Sample, maxDataPoints int) []prompb.Sample {
+	n := len(samples)
+	switch {
+	case maxDataPoints >= n:
+		return samples
+	case maxDataPoints == 1:
+		return []prompb.Sample{samples[n-1]}
+	case maxDataPoints == 2:
+		return []prompb.Sample{samples[0], samples[n-1]}
+	}
+
+	var (
+		result = make([]prompb.Sample, 0, maxDataPoints)
+		every  = float64(n-2) / float64(maxDataPoints-2)
+		a      = 0
+	)
+	result = append(result, samples[0])
+	for i := 0; i < maxDataPoints-2; i++ {
+		// Average of the next bucket, the third point of the triangle.
+		avgStart := int(float64(i+1)*every) + 1
+		avgEnd := int(float64(i+2)*every) + 1
+		if avgEnd > n {
+			avgEnd = n
+		}
+		var avgX, avgY float64
+		for j := avgStart; j < avgEnd; j++ {
+			avgX += float64(samples[j].Timestamp)
+			avgY += samples[j].Value
+		}
+		count := float64(avgEnd - avgStart)
+		avgX /= count
+		avgY /= count
+
+		var (
+			rangeStart = int(float64(i)*every) + 1
+			rangeEnd   = int(float64(i+1)*every) + 1
+			ax         = float64(samples[a].Timestamp)
+			ay         = samples[a].Value
+			maxArea    = -1.0
+			next       = rangeStart
+		)
+		for j := rangeStart; j < rangeEnd; j++ {
+			area := math.Abs((ax-avgX)*(samples[j].Value-ay) -
+				(ax-float64(samples[j].Timestamp))*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+		result = append(result, samples[next])
+		a = next
+	}
+	return append(result, samples[n-1])
+}
+
+func consolidateAverage(values []float64) float64 {
+	sum, count := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum / float64(count)
+}
+
+func consolidateMin(values []float64) float64 {
+	min := math.NaN()
+	for _, v := range values {
+		if math.IsNaN(min) || v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func consolidateMax(values []float64) float64 {
+	max := math.NaN()
+	for _, v := range values {
+		if math.IsNaN(max) || v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func consolidateSum(values []float64) float64 {
+	sum, count := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum
+}
+
+func consolidateFirst(values []float64) float64 {
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			return v
+		}
+	}
+	return math.NaN()
+}
+
+func consolidateLast(values []float64) float64 {
+	for i := len(values) - 1; i >= 0; i-- {
+		if !math.IsNaN(values[i]) {
+			return values[i]
+		}
+	}
+	return math.NaN()
+}
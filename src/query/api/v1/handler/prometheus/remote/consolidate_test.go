@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConsolidateOptions(t *testing.T) {
+	req := httptest.NewRequest("GET", PromReadURL, nil)
+	opts, err := parseConsolidateOptions(req)
+	require.NoError(t, err)
+	require.False(t, opts.enabled())
+
+	req = httptest.NewRequest("GET", PromReadURL+"?maxDataPoints=100", nil)
+	opts, err = parseConsolidateOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, consolidateOptions{maxDataPoints: 100, consolidateBy: "lttb"}, opts)
+
+	req = httptest.NewRequest("GET", PromReadURL+"?maxDataPoints=100&consolidateBy=max", nil)
+	opts, err = parseConsolidateOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, consolidateOptions{maxDataPoints: 100, consolidateBy: "max"}, opts)
+
+	for _, query := range []string{
+		"?maxDataPoints=0",
+		"?maxDataPoints=foo",
+		"?maxDataPoints=100&consolidateBy=median",
+	} {
+		req = httptest.NewRequest("GET", PromReadURL+query, nil)
+		_, err = parseConsolidateOptions(req)
+		require.Error(t, err, query)
+	}
+}
+
+func TestConsolidateSteps(t *testing.T) {
+	samples := make([]prompb.Sample, 0, 10)
+	for i := 0; i < 10; i++ {
+		samples = append(samples, prompb.Sample{Timestamp: int64(i) * 1000, Value: float64(i)})
+	}
+	samples[3].Value = math.NaN()
+
+	opts := consolidateOptions{maxDataPoints: 5, consolidateBy: "average"}
+	require.Equal(t, []prompb.Sample{
+		{Timestamp: 0, Value: 0.5},
+		{Timestamp: 2000, Value: 2},
+		{Timestamp: 4000, Value: 4.5},
+		{Timestamp: 6000, Value: 6.5},
+		{Timestamp: 8000, Value: 8.5},
+	}, consolidateSamples(samples, 0, 10000, opts))
+
+	opts.consolidateBy = "last"
+	require.Equal(t, []prompb.Sample{
+		{Timestamp: 0, Value: 1},
+		{Timestamp: 2000, Value: 2},
+		{Timestamp: 4000, Value: 5},
+		{Timestamp: 6000, Value: 7},
+		{Timestamp: 8000, Value: 9},
+	}, consolidateSamples(samples, 0, 10000, opts))
+}
+
+func TestConsolidateLTTB(t *testing.T) {
+	samples := make([]prompb.Sample, 0, 100)
+	for i := 0; i < 100; i++ {
+		samples = append(samples, prompb.Sample{Timestamp: int64(i) * 1000})
+	}
+	// A single spike must be kept.
+	samples[42].Value = 100
+
+	opts := consolidateOptions{maxDataPoints: 10, consolidateBy: consolidateByLTTB}
+	result := consolidateSamples(samples, 0, 100000, opts)
+	require.Len(t, result, 10)
+	require.Equal(t, samples[0], result[0])
+	require.Equal(t, samples[99], result[9])
+	require.Contains(t, result, samples[42])
+
+	// Series within the limit are left untouched.
+	opts.maxDataPoints = 100
+	require.Equal(t, samples, consolidateSamples(samples, 0, 100000, opts))
+}
+
+func TestConsolidateResults(t *testing.T) {
+	req := &prompb.ReadRequest{
+		Queries: []*prompb.Query{{StartTimestampMs: 0, EndTimestampMs: 4000}},
+	}
+	results := []*prompb.QueryResult{
+		{
+			Timeseries: []*prompb.TimeSeries{
+				{
+					Samples: []prompb.Sample{
+						{Timestamp: 0, Value: 1},
+						{Timestamp: 1000, Value: 2},
+						{Timestamp: 2000, Value: 3},
+						{Timestamp: 3000, Value: 4},
+					},
+				},
+				{
+					Samples: []prompb.Sample{{Timestamp: 0, Value: 1}},
+				},
+			},
+		},
+	}
+
+	opts := consolidateOptions{maxDataPoints: 2, consolidateBy: "sum"}
+	require.Equal(t, 1, consolidateResults(req, results, opts))
+	require.Equal(t, []prompb.Sample{
+		{Timestamp: 0, Value: 3},
+		{Timestamp: 2000, Value: 7},
+	}, results[0].Timeseries[0].Samples)
+	require.Equal(t, []prompb.Sample{{Timestamp: 0, Value: 1}},
+		results[0].Timeseries[1].Samples)
+}
@@ -21,6 +21,7 @@
 package remote
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/query/parser/promql"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/instrument"
 	xhttp "github.com/m3db/m3/src/x/net/http"
 
@@ -47,8 +49,10 @@ const (
 // PromSeriesMatchHTTPMethods are the HTTP methods for this handler.
 var PromSeriesMatchHTTPMethods = []string{http.MethodGet, http.MethodPost}
 
-// PromSeriesMatchHandler represents a handler for
-// the prometheus series matcher endpoint.
+// PromSeriesMatchHandler represents a handler for the prometheus series
+// matcher endpoint. Series are searched in the index only, with the time
+// bounds and limits of the request pushed down to the index queries, so no
+// series data is fetched.
 type PromSeriesMatchHandler struct {
 	storage             storage.Storage
 	tagOptions          models.TagOptions
@@ -58,8 +62,6 @@ type PromSeriesMatchHandler struct {
 }
 
 // NewPromSeriesMatchHandler returns a new instance of handler.
-// TODO: Remove series match handler, not part of Prometheus HTTP API
-// and not used anywhere or documented.
 func NewPromSeriesMatchHandler(opts options.HandlerOptions) http.Handler {
 	return &PromSeriesMatchHandler{
 		tagOptions:          opts.TagOptions(),
@@ -90,18 +92,58 @@ func (h *PromSeriesMatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	results := make([]models.Metrics, len(queries))
-	meta := block.NewResultMetadata()
+	var (
+		results   = make([]models.Metrics, 0, len(queries))
+		meta      = block.NewResultMetadata()
+		seen      = make(map[string]struct{})
+		numSeries int
+	)
 	for i, query := range queries {
-		result, err := h.storage.SearchSeries(ctx, query, opts)
+		queryOpts := opts
+		if opts.SeriesLimit > 0 {
+			// Push down only the remaining series limit so that the limit
+			// applies to the union of the matchers rather than to each of them.
+			queryOpts = opts.Clone()
+			queryOpts.SeriesLimit = opts.SeriesLimit - numSeries
+		}
+
+		result, err := h.storage.SearchSeries(ctx, query, queryOpts)
 		if err != nil {
 			logger.Error("unable to get matched series", zap.Error(err))
 			xhttp.WriteError(w, err)
 			return
 		}
 
-		results[i] = result.Metrics
+		// Series matching several of the matchers are returned once.
+		metrics := make(models.Metrics, 0, len(result.Metrics))
+		for _, metric := range result.Metrics {
+			id := metric.ID
+			if len(id) == 0 {
+				id = metric.Tags.ID()
+			}
+			if _, ok := seen[string(id)]; ok {
+				continue
+			}
+			seen[string(id)] = struct{}{}
+			metrics = append(metrics, metric)
+		}
+
+		numSeries += len(metrics)
+		results = append(results, metrics)
 		meta = meta.CombineMetadata(result.Metadata)
+
+		if opts.SeriesLimit > 0 && numSeries >= opts.SeriesLimit && i < len(queries)-1 {
+			// The limit was reached before every matcher was searched.
+			meta.Exhaustive = false
+			if opts.RequireExhaustive {
+				err := xerrors.NewInvalidParamsError(
+					fmt.Errorf("series limit of %d exceeded", opts.SeriesLimit))
+				logger.Error("series limit exceeded", zap.Error(err))
+				xhttp.WriteError(w, err)
+				return
+			}
+			break
+		}
 	}
 
 	err = handleroptions.AddDBResultResponseHeaders(w, meta, opts)
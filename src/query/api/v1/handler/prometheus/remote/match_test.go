@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/headers"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+)
+
+func testSeriesMatchMetric(name, value string) models.Metric {
+	tags := models.NewTags(2, models.NewTagOptions()).
+		AddTag(models.Tag{Name: []byte("__name__"), Value: []byte(name)}).
+		AddTag(models.Tag{Name: []byte("foo"), Value: []byte(value)})
+	return models.Metric{ID: tags.ID(), Tags: tags}
+}
+
+func newTestSeriesMatchHandler(t *testing.T, store storage.Storage) http.Handler {
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{
+			Limits: handleroptions.FetchOptionsBuilderLimitsOptions{
+				SeriesLimit: 3,
+			},
+			Timeout: 15 * time.Second,
+		})
+	require.NoError(t, err)
+	iOpts := instrument.NewOptions()
+	opts := options.EmptyHandlerOptions().
+		SetStorage(store).
+		SetEngine(newEngine(store, defaultLookbackDuration, iOpts)).
+		SetInstrumentOpts(iOpts).
+		SetTagOptions(models.NewTagOptions()).
+		SetFetchOptionsBuilder(fb)
+	return NewPromSeriesMatchHandler(opts)
+}
+
+func TestPromSeriesMatchHandlerDedupesAndSharesSeriesLimit(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	var limits []int
+	searchSeries := func(metrics ...models.Metric) func(
+		interface{}, *storage.FetchQuery, *storage.FetchOptions,
+	) (*storage.SearchResults, error) {
+		return func(
+			_ interface{},
+			query *storage.FetchQuery,
+			opts *storage.FetchOptions,
+		) (*storage.SearchResults, error) {
+			require.Equal(t, time.Unix(100, 0), query.Start)
+			require.Equal(t, time.Unix(200, 0), query.End)
+			limits = append(limits, opts.SeriesLimit)
+			return &storage.SearchResults{
+				Metrics:  metrics,
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		}
+	}
+	gomock.InOrder(
+		store.EXPECT().SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(searchSeries(
+				testSeriesMatchMetric("a", "1"),
+				testSeriesMatchMetric("a", "2"),
+			)),
+		store.EXPECT().SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(searchSeries(
+				testSeriesMatchMetric("a", "2"),
+				testSeriesMatchMetric("b", "1"),
+			)),
+	)
+
+	handler := newTestSeriesMatchHandler(t, store)
+	params := url.Values{
+		"match[]": []string{"a", `{__name__=~"a|b", foo="1"}`},
+		"start":   []string{"100"},
+		"end":     []string{"200"},
+	}
+	req := httptest.NewRequest(http.MethodGet, PromSeriesMatchURL+"?"+params.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// The second matcher is only allowed the remainder of the series limit,
+	// and series matching both matchers are returned once.
+	require.Equal(t, []int{3, 1}, limits)
+	var resp struct {
+		Status string              `json:"status"`
+		Data   []map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, "success", resp.Status)
+	require.Equal(t, []map[string]string{
+		{"__name__": "a", "foo": "1"},
+		{"__name__": "a", "foo": "2"},
+		{"__name__": "b", "foo": "1"},
+	}, resp.Data)
+	require.Empty(t, rr.Header().Get(headers.LimitHeader))
+}
+
+func TestPromSeriesMatchHandlerSeriesLimitReached(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&storage.SearchResults{
+			Metrics: models.Metrics{
+				testSeriesMatchMetric("a", "1"),
+				testSeriesMatchMetric("a", "2"),
+				testSeriesMatchMetric("a", "3"),
+			},
+			Metadata: block.NewResultMetadata(),
+		}, nil).
+		Times(2)
+
+	handler := newTestSeriesMatchHandler(t, store)
+	params := url.Values{"match[]": []string{"a", "b"}}
+
+	// The remaining matchers are not searched once the limit is reached.
+	req := httptest.NewRequest(http.MethodGet, PromSeriesMatchURL+"?"+params.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, headers.LimitHeaderSeriesLimitApplied, rr.Header().Get(headers.LimitHeader))
+
+	// Requests requiring exhaustive results fail instead.
+	req = httptest.NewRequest(http.MethodGet, PromSeriesMatchURL+"?"+params.Encode(), nil)
+	req.Header.Set(headers.LimitRequireExhaustiveHeader, "true")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+}
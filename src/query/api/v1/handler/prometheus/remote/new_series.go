@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"sync"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// seriesSeparator separates the names and values of the labels of a series
+// when hashing them, it can not occur in valid UTF-8 label names and values.
+var seriesSeparator = []byte{0xff}
+
+// seenSeries remembers the hashes of the series recently written so that the
+// series of a request that were not seen before can be counted. Hashes are
+// kept in two generations, the current generation replacing the previous one
+// once full, which bounds the memory used to twice the capacity of a
+// generation while remembering at least that many of the most recent series.
+type seenSeries struct {
+	sync.Mutex
+
+	generationSize int
+	current        map[uint64]struct{}
+	previous       map[uint64]struct{}
+}
+
+func newSeenSeries(size int) *seenSeries {
+	generationSize := size / 2
+	if generationSize < 1 {
+		generationSize = 1
+	}
+	return &seenSeries{
+		generationSize: generationSize,
+		current:        make(map[uint64]struct{}),
+		previous:       make(map[uint64]struct{}),
+	}
+}
+
+// hashSeries returns the hashes of the series of a request.
+func hashSeries(series []prompb.TimeSeries) []uint64 {
+	var (
+		digest = xxhash.New()
+		hashes = make([]uint64, 0, len(series))
+	)
+	for _, s := range series {
+		digest.Reset()
+		for _, label := range s.Labels {
+			_, _ = digest.Write(label.Name)
+			_, _ = digest.Write(seriesSeparator)
+			_, _ = digest.Write(label.Value)
+			_, _ = digest.Write(seriesSeparator)
+		}
+		hashes = append(hashes, digest.Sum64())
+	}
+	return hashes
+}
+
+// CountNew returns the number of series not seen before among the hashes.
+func (s *seenSeries) CountNew(hashes []uint64) int {
+	s.Lock()
+	defer s.Unlock()
+
+	numNew := 0
+	for _, hash := range hashes {
+		if _, ok := s.current[hash]; ok {
+			continue
+		}
+		if _, ok := s.previous[hash]; ok {
+			continue
+		}
+		numNew++
+	}
+	return numNew
+}
+
+// Add remembers the series with the given hashes as seen.
+func (s *seenSeries) Add(hashes []uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, hash := range hashes {
+		if _, ok := s.current[hash]; ok {
+			continue
+		}
+		if len(s.current) >= s.generationSize {
+			s.previous = s.current
+			s.current = make(map[uint64]struct{}, s.generationSize)
+		}
+		s.current[hash] = struct{}{}
+	}
+}
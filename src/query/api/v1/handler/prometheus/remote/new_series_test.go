@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSeries(values ...string) []prompb.TimeSeries {
+	series := make([]prompb.TimeSeries, 0, len(values))
+	for _, value := range values {
+		series = append(series, prompb.TimeSeries{
+			Labels: []prompb.Label{
+				{Name: []byte("__name__"), Value: []byte("foo")},
+				{Name: []byte("bar"), Value: []byte(value)},
+			},
+		})
+	}
+	return series
+}
+
+func TestSeenSeries(t *testing.T) {
+	seen := newSeenSeries(4)
+
+	hashes := hashSeries(testSeries("a", "b"))
+	require.Equal(t, 2, seen.CountNew(hashes))
+	seen.Add(hashes)
+	require.Equal(t, 0, seen.CountNew(hashes))
+	require.Equal(t, 1, seen.CountNew(hashSeries(testSeries("a", "c"))))
+
+	// Labels are hashed with separators so their boundaries matter.
+	require.NotEqual(t,
+		hashSeries([]prompb.TimeSeries{{Labels: []prompb.Label{{Name: []byte("ab"), Value: []byte("c")}}}}),
+		hashSeries([]prompb.TimeSeries{{Labels: []prompb.Label{{Name: []byte("a"), Value: []byte("bc")}}}}))
+
+	// The series seen before the previous generation are forgotten.
+	seen.Add(hashSeries(testSeries("c", "d", "e")))
+	require.Equal(t, 0, seen.CountNew(hashSeries(testSeries("c", "d", "e"))))
+	require.Equal(t, 2, seen.CountNew(hashes))
+}
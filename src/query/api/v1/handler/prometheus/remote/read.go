@@ -86,6 +86,9 @@ type promReadMetrics struct {
 	fetchErrorsServer tally.Counter
 	fetchErrorsClient tally.Counter
 	fetchTimerSuccess tally.Timer
+	// consolidatedSeries counts the series consolidated to the requested
+	// maximum number of datapoints.
+	consolidatedSeries tally.Counter
 }
 
 func newPromReadMetrics(scope tally.Scope) promReadMetrics {
@@ -96,7 +99,8 @@ func newPromReadMetrics(scope tally.Scope) promReadMetrics {
 			Counter("fetch.errors"),
 		fetchErrorsClient: scope.Tagged(map[string]string{"code": "4XX"}).
 			Counter("fetch.errors"),
-		fetchTimerSuccess: scope.Timer("fetch.success.latency"),
+		fetchTimerSuccess:  scope.Timer("fetch.success.latency"),
+		consolidatedSeries: scope.Counter("fetch.consolidated-series"),
 	}
 }
 
@@ -124,6 +128,15 @@ func (h *promReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	consolidateOpts, err := parseConsolidateOptions(r)
+	if err != nil {
+		err = xerrors.NewInvalidParamsError(err)
+		h.promReadMetrics.incError(err)
+		logger.Error("remote read consolidation parse error", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
 	readResult, err := Read(ctx, req, fetchOpts, h.opts)
 	if err != nil {
 		h.promReadMetrics.incError(err)
@@ -135,6 +148,10 @@ func (h *promReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if n := consolidateResults(req, readResult.Result, consolidateOpts); n > 0 {
+		h.promReadMetrics.consolidatedSeries.Inc(int64(n))
+	}
+
 	// Write headers before response.
 	err = handleroptions.AddDBResultResponseHeaders(w, readResult.Meta, fetchOpts)
 	if err != nil {
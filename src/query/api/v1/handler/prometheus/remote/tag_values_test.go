@@ -243,7 +243,7 @@ func TestTagValueErrors(t *testing.T) {
 	read, err := ioutil.ReadAll(rr.Body)
 	require.NoError(t, err)
 
-	ex := `{"status":"error","error":"invalid path with no name present"}`
+	ex := `{"status":"error","error":"invalid path with no name present","code":"invalid_params","retryable":false}`
 	assert.JSONEq(t, ex, string(read))
 }
 
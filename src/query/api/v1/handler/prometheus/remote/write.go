@@ -32,7 +32,9 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
@@ -41,7 +43,9 @@ import (
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/clock"
@@ -116,6 +120,10 @@ type PromWriteHandler struct {
 	forwardingBoundWorkers xsync.WorkerPool
 	forwardContext         context.Context
 	forwardRetrier         retry.Retrier
+	metricMetadataStore    metricmetadata.Store
+	ingestAccountant       ingestaccounting.Accountant
+	limits                 config.PerRemoteWriteLimitsConfiguration
+	seenSeries             *seenSeries
 	nowFn                  clock.NowFn
 	instrumentOpts         instrument.Options
 	metrics                promWriteMetrics
@@ -176,6 +184,12 @@ func NewPromWriteHandler(options options.HandlerOptions) (http.Handler, error) {
 		scope.SubScope("forwarding-retry"),
 	)
 
+	limits := options.Config().Limits.PerRemoteWrite
+	var seen *seenSeries
+	if limits.MaxNewSeries > 0 {
+		seen = newSeenSeries(limits.SeenSeriesCacheSizeOrDefault())
+	}
+
 	return &PromWriteHandler{
 		downsamplerAndWriter:   downsamplerAndWriter,
 		tagOptions:             tagOptions,
@@ -186,6 +200,10 @@ func NewPromWriteHandler(options options.HandlerOptions) (http.Handler, error) {
 		forwardingBoundWorkers: forwardingBoundWorkers,
 		forwardContext:         context.Background(),
 		forwardRetrier:         retry.NewRetrier(forwardRetryOpts),
+		metricMetadataStore:    options.MetricMetadataStore(),
+		ingestAccountant:       options.IngestAccountant(),
+		limits:                 limits,
+		seenSeries:             seen,
 		nowFn:                  nowFn,
 		metrics:                metrics,
 		instrumentOpts:         instrumentOpts,
@@ -204,6 +222,10 @@ type promWriteMetrics struct {
 	forwardErrors            tally.Counter
 	forwardDropped           tally.Counter
 	forwardLatency           tally.Histogram
+	metadataErrors           tally.Counter
+	rejectedBodyTooLarge     tally.Counter
+	rejectedTooManySamples   tally.Counter
+	rejectedTooManyNewSeries tally.Counter
 }
 
 func (m *promWriteMetrics) incError(err error) {
@@ -231,9 +253,19 @@ func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
 		forwardErrors:            scope.SubScope("forward").Counter("errors"),
 		forwardDropped:           scope.SubScope("forward").Counter("dropped"),
 		forwardLatency:           scope.SubScope("forward").Histogram("latency", buckets.WriteLatencyBuckets),
+		metadataErrors:           scope.SubScope("metadata").Counter("errors"),
+		rejectedBodyTooLarge:     rejectedCounter(scope, "body-too-large"),
+		rejectedTooManySamples:   rejectedCounter(scope, "too-many-samples"),
+		rejectedTooManyNewSeries: rejectedCounter(scope, "too-many-new-series"),
 	}, nil
 }
 
+func rejectedCounter(scope tally.Scope, reason string) tally.Counter {
+	return scope.SubScope("write").
+		Tagged(map[string]string{"reason": reason}).
+		Counter("rejected")
+}
+
 func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	batchRequestStopwatch := h.metrics.writeBatchLatency.Start()
 	defer batchRequestStopwatch.Stop()
@@ -301,6 +333,20 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	batchErr := h.write(r.Context(), req, opts)
 
+	if h.ingestAccountant != nil {
+		h.ingestAccountant.Record(r, req.Timeseries)
+	}
+
+	if h.metricMetadataStore != nil && len(req.Metadata) > 0 {
+		// NB: metadata is best effort, failing to store it must not fail
+		// the write of the samples.
+		if err := h.metricMetadataStore.Update(req.Metadata); err != nil {
+			h.metrics.metadataErrors.Inc(1)
+			logger := logging.WithContext(r.Context(), h.instrumentOpts)
+			logger.Warn("could not store metric metadata", zap.Error(err))
+		}
+	}
+
 	// Record ingestion delay latency
 	now := h.nowFn()
 	for _, series := range req.Timeseries {
@@ -387,6 +433,10 @@ func (h *PromWriteHandler) checkedParseRequest(
 ) (parseRequestResult, error) {
 	result, err := h.parseRequest(r)
 	if err != nil {
+		if _, ok := err.(xhttp.Error); ok { //nolint:errorlint
+			// Requests exceeding limits already carry their status code.
+			return parseRequestResult{}, err
+		}
 		// Always invalid request if parsing fails params.
 		return parseRequestResult{}, xerrors.NewInvalidParamsError(err)
 	}
@@ -450,8 +500,13 @@ func (h *PromWriteHandler) parseRequest(
 		}
 	}
 
-	result, err := prometheus.ParsePromCompressedRequest(r)
+	result, err := prometheus.ParsePromCompressedRequestWithLimit(r,
+		h.limits.MaxUncompressedBodyBytes)
 	if err != nil {
+		if errors.Is(err, prometheus.ErrUncompressedBodyTooLarge) {
+			h.metrics.rejectedBodyTooLarge.Inc(1)
+			return parseRequestResult{}, xhttp.NewError(err, http.StatusRequestEntityTooLarge)
+		}
 		return parseRequestResult{}, err
 	}
 
@@ -460,6 +515,10 @@ func (h *PromWriteHandler) parseRequest(
 		return parseRequestResult{}, err
 	}
 
+	if err := h.checkLimits(&req); err != nil {
+		return parseRequestResult{}, err
+	}
+
 	if mapStr := r.Header.Get(headers.MapTagsByJSONHeader); mapStr != "" {
 		var opts handleroptions.MapTagsOptions
 		if err := json.Unmarshal([]byte(mapStr), &opts); err != nil {
@@ -488,6 +547,40 @@ func (h *PromWriteHandler) parseRequest(
 	}, nil
 }
 
+// checkLimits returns an error with a 413 status code if the write request
+// exceeds the configured number of samples or of series not seen before.
+func (h *PromWriteHandler) checkLimits(req *prompb.WriteRequest) error {
+	if limit := h.limits.MaxSamples; limit > 0 {
+		numSamples := 0
+		for _, series := range req.Timeseries {
+			numSamples += len(series.Samples)
+		}
+		if numSamples > limit {
+			h.metrics.rejectedTooManySamples.Inc(1)
+			err := fmt.Errorf("too many samples: %d exceeds limit of %d",
+				numSamples, limit)
+			return xhttp.NewError(err, http.StatusRequestEntityTooLarge)
+		}
+	}
+
+	if limit := h.limits.MaxNewSeries; limit > 0 {
+		// NB: only requests with more series than the limit can have too many
+		// new series, the series of every accepted request are remembered.
+		hashes := hashSeries(req.Timeseries)
+		if len(hashes) > limit {
+			if numNew := h.seenSeries.CountNew(hashes); numNew > limit {
+				h.metrics.rejectedTooManyNewSeries.Inc(1)
+				err := fmt.Errorf("too many new series: %d exceeds limit of %d",
+					numNew, limit)
+				return xhttp.NewError(err, http.StatusRequestEntityTooLarge)
+			}
+		}
+		h.seenSeries.Add(hashes)
+	}
+
+	return nil
+}
+
 func (h *PromWriteHandler) write(
 	ctx context.Context,
 	r *prompb.WriteRequest,
@@ -566,6 +659,7 @@ func newPromTSIter(
 		tags             = make([]models.Tags, 0, len(timeseries))
 		datapoints       = make([]ts.Datapoints, 0, len(timeseries))
 		seriesAttributes = make([]ts.SeriesAttributes, 0, len(timeseries))
+		exemplars        [][]metric.Exemplar
 	)
 
 	graphiteTagOpts := tagOpts.SetIDSchemeType(models.TypeGraphite)
@@ -584,6 +678,12 @@ func newPromTSIter(
 		seriesAttributes = append(seriesAttributes, attributes)
 		tags = append(tags, storage.PromLabelsToM3Tags(promTS.Labels, opts))
 		datapoints = append(datapoints, storage.PromSamplesToM3Datapoints(promTS.Samples))
+		if len(promTS.Exemplars) > 0 {
+			if exemplars == nil {
+				exemplars = make([][]metric.Exemplar, len(timeseries))
+			}
+			exemplars[len(tags)-1] = storage.PromExemplarsToM3Exemplars(promTS.Exemplars)
+		}
 	}
 
 	return &promTSIter{
@@ -591,6 +691,7 @@ func newPromTSIter(
 		idx:              -1,
 		tags:             tags,
 		datapoints:       datapoints,
+		exemplars:        exemplars,
 		storeMetricsType: storeMetricsType,
 	}, nil
 }
@@ -601,6 +702,7 @@ type promTSIter struct {
 	attributes []ts.SeriesAttributes
 	tags       []models.Tags
 	datapoints []ts.Datapoints
+	exemplars  [][]metric.Exemplar
 	metadatas  []ts.Metadata
 	annotation []byte
 
@@ -655,6 +757,9 @@ func (i *promTSIter) Current() ingest.IterValue {
 	if i.idx < len(i.metadatas) {
 		value.Metadata = i.metadatas[i.idx]
 	}
+	if i.idx < len(i.exemplars) {
+		value.Exemplars = i.exemplars[i.idx]
+	}
 	return value
 }
 
@@ -32,16 +32,21 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	xclock "github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/headers"
@@ -52,6 +57,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/tally"
+	"go.uber.org/zap"
 )
 
 func makeOptions(ds ingest.DownsamplerAndWriter) options.HandlerOptions {
@@ -164,6 +170,58 @@ func TestWriteErrorMetricCount(t *testing.T) {
 	require.True(t, foundMetric)
 }
 
+func TestPromWriteLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits config.PerRemoteWriteLimitsConfiguration
+		metric string
+	}{
+		{
+			name:   "body too large",
+			limits: config.PerRemoteWriteLimitsConfiguration{MaxUncompressedBodyBytes: 16},
+			metric: "write.rejected+handler=remote-write,reason=body-too-large",
+		},
+		{
+			name:   "too many new series",
+			limits: config.PerRemoteWriteLimitsConfiguration{MaxNewSeries: 1},
+			metric: "write.rejected+handler=remote-write,reason=too-many-new-series",
+		},
+		{
+			name:   "too many samples",
+			limits: config.PerRemoteWriteLimitsConfiguration{MaxNewSeries: 2, MaxSamples: 3},
+			metric: "write.rejected+handler=remote-write,reason=too-many-samples",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := xtest.NewController(t)
+			defer ctrl.Finish()
+
+			scope := tally.NewTestScope("", nil)
+			cfg := config.Configuration{}
+			cfg.Limits.PerRemoteWrite = tt.limits
+			opts := makeOptions(ingest.NewMockDownsamplerAndWriter(ctrl)).
+				SetConfig(cfg).
+				SetInstrumentOpts(instrument.NewOptions().SetMetricsScope(scope))
+			handler, err := NewPromWriteHandler(opts)
+			require.NoError(t, err)
+
+			promReqBody := test.GeneratePromWriteRequestBody(t, test.GeneratePromWriteRequest())
+			req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, req)
+			require.Equal(t, http.StatusRequestEntityTooLarge, writer.Result().StatusCode)
+
+			counters := scope.Snapshot().Counters()
+			require.Equal(t, int64(1), counters[tt.metric].Value())
+			require.Equal(t, int64(1),
+				counters["write.errors+code=4XX,handler=remote-write"].Value())
+		})
+	}
+}
+
 func TestWriteDatapointDelayMetric(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -418,6 +476,118 @@ func TestPromWriteDisabledMetricsTypes(t *testing.T) {
 	require.NoError(t, capturedIter.Error())
 }
 
+func TestPromWriteExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var capturedIter ingest.DownsampleAndWriteIter
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, iter ingest.DownsampleAndWriteIter, _ ingest.WriteOptions) ingest.BatchError {
+			capturedIter = iter
+			return nil
+		})
+
+	opts := makeOptions(mockDownsamplerAndWriter)
+
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{},
+			{
+				Exemplars: []prompb.Exemplar{
+					{
+						Labels:    []prompb.Label{{Name: []byte("trace_id"), Value: []byte("0102")}},
+						Value:     42,
+						Timestamp: 1,
+					},
+				},
+			},
+		},
+	}
+
+	executeWriteRequest(t, opts, promReq)
+
+	require.True(t, capturedIter.Next())
+	assert.Nil(t, capturedIter.Current().Exemplars)
+	require.True(t, capturedIter.Next())
+	assert.Equal(t, []metric.Exemplar{
+		{TraceID: []byte{0x01, 0x02}, Value: 42, TimeNanos: int64(time.Millisecond)},
+	}, capturedIter.Current().Exemplars)
+
+	require.False(t, capturedIter.Next())
+	require.NoError(t, capturedIter.Error())
+}
+
+func TestPromWriteStoresMetricMetadata(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	metadataOpts, err := metricmetadata.NewOptions(
+		&config.MetricMetadataConfiguration{
+			Enabled:         true,
+			PersistInterval: 10 * time.Millisecond,
+		},
+		func() (kv.Store, error) { return mem.NewStore(), nil },
+		tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	store := metricmetadata.NewStore(metadataOpts)
+	defer store.Close()
+
+	opts := makeOptions(mockDownsamplerAndWriter).SetMetricMetadataStore(store)
+
+	metadata := prompb.MetricMetadata{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+		Help:             "Total number of HTTP requests.",
+	}
+	executeWriteRequest(t, opts, &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{metadata},
+	})
+
+	// Metadata is persisted in the background.
+	expected := map[string]prompb.MetricMetadata{
+		metadata.MetricFamilyName: metadata,
+	}
+	for start := time.Now(); time.Since(start) < 5*time.Second; {
+		if len(store.Metadata("", 0)) == len(expected) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, expected, store.Metadata("", 0))
+}
+
+func TestPromWriteRecordsIngestAccounting(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	accountingOpts, err := ingestaccounting.NewOptions(
+		&config.IngestAccountingConfiguration{Enabled: true}, tally.NoopScope)
+	require.NoError(t, err)
+	accountant := ingestaccounting.NewAccountant(accountingOpts)
+
+	opts := makeOptions(mockDownsamplerAndWriter).SetIngestAccountant(accountant)
+	executeWriteRequest(t, opts, test.GeneratePromWriteRequest())
+
+	report := accountant.TopSources(ingestaccounting.Query{})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, "192.0.2.1", report.Sources[0].RemoteAddr)
+	assert.Equal(t, int64(1), report.Sources[0].Requests)
+	assert.True(t, report.Sources[0].Datapoints > 0)
+}
+
 func BenchmarkWriteDatapoints(b *testing.B) {
 	ctrl := xtest.NewController(b)
 	defer ctrl.Finish()
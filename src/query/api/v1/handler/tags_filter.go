@@ -0,0 +1,163 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TagsFilterValidateURL is the url to validate a rule tags filter against
+	// a sample of recently seen metric IDs.
+	TagsFilterValidateURL = "/api/v1/rules/tags_filter/validate"
+
+	// TagsFilterValidateHTTPMethod is the HTTP method used with this resource.
+	TagsFilterValidateHTTPMethod = http.MethodPost
+
+	defaultTagsFilterSampleSize   = 10000
+	defaultTagsFilterExampleLimit = 10
+)
+
+var (
+	errNoDownsampler         = errors.New("downsampler is not configured")
+	errTagsFilterEmpty       = errors.New("filter must be set")
+	errTagsFilterNegativeArg = errors.New("sampleSize and exampleLimit must not be negative")
+)
+
+// TagsFilterValidateHandler validates rule tags filters against the metric
+// IDs most recently matched by the downsampler, so rule authors can gauge
+// the effect of a filter before saving a rule.
+type TagsFilterValidateHandler struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	instrumentOpts       instrument.Options
+}
+
+// NewTagsFilterValidateHandler returns a new instance of handler.
+func NewTagsFilterValidateHandler(opts options.HandlerOptions) http.Handler {
+	return &TagsFilterValidateHandler{
+		downsamplerAndWriter: opts.DownsamplerAndWriter(),
+		instrumentOpts:       opts.InstrumentOpts(),
+	}
+}
+
+type tagsFilterValidateRequest struct {
+	Filter       string `json:"filter"`
+	SampleSize   *int   `json:"sampleSize"`
+	ExampleLimit *int   `json:"exampleLimit"`
+}
+
+type tagsFilterValidateResult struct {
+	Filter   string              `json:"filter"`
+	Sampled  int                 `json:"sampled"`
+	Matched  int                 `json:"matched"`
+	Examples []map[string]string `json:"examples"`
+}
+
+func (h *TagsFilterValidateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	req, err := h.parseRequest(r)
+	if err != nil {
+		logger.Error("unable to parse request", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if h.downsamplerAndWriter == nil || h.downsamplerAndWriter.Downsampler() == nil {
+		xhttp.WriteError(w, errNoDownsampler)
+		return
+	}
+
+	sampleSize := defaultTagsFilterSampleSize
+	if req.SampleSize != nil {
+		sampleSize = *req.SampleSize
+	}
+	exampleLimit := defaultTagsFilterExampleLimit
+	if req.ExampleLimit != nil {
+		exampleLimit = *req.ExampleLimit
+	}
+
+	eval, err := h.downsamplerAndWriter.Downsampler().
+		EvaluateTagsFilter(req.Filter, sampleSize, exampleLimit)
+	if err != nil {
+		logger.Error("unable to evaluate tags filter",
+			zap.String("filter", req.Filter), zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	result := tagsFilterValidateResult{
+		Filter:   req.Filter,
+		Sampled:  eval.Sampled,
+		Matched:  eval.Matched,
+		Examples: make([]map[string]string, 0, len(eval.Examples)),
+	}
+	for _, tags := range eval.Examples {
+		example := make(map[string]string, tags.Len())
+		for _, tag := range tags.Tags {
+			example[string(tag.Name)] = string(tag.Value)
+		}
+		result.Examples = append(result.Examples, example)
+	}
+
+	xhttp.WriteJSONResponse(w, result, logger)
+}
+
+func (h *TagsFilterValidateHandler) parseRequest(
+	r *http.Request,
+) (tagsFilterValidateRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return tagsFilterValidateRequest{}, xerrors.NewInvalidParamsError(err)
+	}
+	defer r.Body.Close()
+
+	var req tagsFilterValidateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return tagsFilterValidateRequest{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	if req.Filter == "" {
+		return tagsFilterValidateRequest{}, xerrors.NewInvalidParamsError(errTagsFilterEmpty)
+	}
+	if (req.SampleSize != nil && *req.SampleSize < 0) ||
+		(req.ExampleLimit != nil && *req.ExampleLimit < 0) {
+		return tagsFilterValidateRequest{}, xerrors.NewInvalidParamsError(errTagsFilterNegativeArg)
+	}
+	if _, err := filters.ValidateTagsFilter(req.Filter); err != nil {
+		return tagsFilterValidateRequest{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTagsFilterValidateHandler(
+	ctrl *gomock.Controller,
+) (http.Handler, *downsample.MockDownsampler) {
+	downsampler := downsample.NewMockDownsampler(ctrl)
+	downsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	downsamplerAndWriter.EXPECT().Downsampler().Return(downsampler).AnyTimes()
+
+	opts := options.EmptyHandlerOptions().
+		SetDownsamplerAndWriter(downsamplerAndWriter)
+	return NewTagsFilterValidateHandler(opts), downsampler
+}
+
+func TestTagsFilterValidateHandler(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, downsampler := newTestTagsFilterValidateHandler(ctrl)
+
+	example := models.NewTags(2, nil).
+		AddTag(models.Tag{Name: []byte("__name__"), Value: []byte("requests")}).
+		AddTag(models.Tag{Name: []byte("app"), Value: []byte("foo")})
+	downsampler.EXPECT().
+		EvaluateTagsFilter("app:foo*", 100, defaultTagsFilterExampleLimit).
+		Return(downsample.TagsFilterEvaluation{
+			Sampled:  100,
+			Matched:  3,
+			Examples: []models.Tags{example},
+		}, nil)
+
+	body := `{"filter": "app:foo*", "sampleSize": 100}`
+	req := httptest.NewRequest(TagsFilterValidateHTTPMethod, TagsFilterValidateURL,
+		strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result tagsFilterValidateResult
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, tagsFilterValidateResult{
+		Filter:  "app:foo*",
+		Sampled: 100,
+		Matched: 3,
+		Examples: []map[string]string{
+			{"__name__": "requests", "app": "foo"},
+		},
+	}, result)
+}
+
+func TestTagsFilterValidateHandlerInvalidRequest(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, _ := newTestTagsFilterValidateHandler(ctrl)
+
+	for _, body := range []string{
+		`{`,
+		`{}`,
+		`{"filter": "app:foo", "sampleSize": -1}`,
+		`{"filter": "app:[foo"}`,
+	} {
+		req := httptest.NewRequest(TagsFilterValidateHTTPMethod, TagsFilterValidateURL,
+			strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, body)
+	}
+}
+
+func TestTagsFilterValidateHandlerNoDownsampler(t *testing.T) {
+	h := NewTagsFilterValidateHandler(options.EmptyHandlerOptions())
+
+	req := httptest.NewRequest(TagsFilterValidateHTTPMethod, TagsFilterValidateURL,
+		strings.NewReader(`{"filter": "app:foo"}`))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -51,7 +51,9 @@ import (
 	"github.com/m3db/m3/src/query/util/queryhttp"
 	xdebug "github.com/m3db/m3/src/x/debug"
 	extdebug "github.com/m3db/m3/src/x/debug/ext"
+	xlog "github.com/m3db/m3/src/x/log"
 	xhttp "github.com/m3db/m3/src/x/net/http"
+	"github.com/m3db/m3/src/x/retry"
 )
 
 const (
@@ -267,6 +269,13 @@ func (h *Handler) RegisterRoutes() error {
 	}); err != nil {
 		return err
 	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    m3json.WriteResultsJSONURL,
+		Handler: m3json.NewWriteResultsJSONHandler(h.options),
+		Methods: methods(m3json.WriteResultsJSONHTTPMethod),
+	}); err != nil {
+		return err
+	}
 
 	// Readiness endpoint.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
@@ -277,6 +286,24 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// Rule tags filter validation endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    handler.TagsFilterValidateURL,
+		Handler: handler.NewTagsFilterValidateHandler(h.options),
+		Methods: methods(handler.TagsFilterValidateHTTPMethod),
+	}); err != nil {
+		return err
+	}
+
+	// Series ID conversion endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    handler.ConvertIDURL,
+		Handler: handler.NewConvertIDHandler(h.options),
+		Methods: methods(handler.ConvertIDHTTPMethod),
+	}); err != nil {
+		return err
+	}
+
 	// Tag completion endpoints.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:               native.CompleteTagsURL,
@@ -294,6 +321,14 @@ func (h *Handler) RegisterRoutes() error {
 	}); err != nil {
 		return err
 	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:               native.AutocompleteURL,
+		Handler:            native.NewAutocompleteHandler(h.options),
+		Methods:            native.AutocompleteHTTPMethods,
+		MiddlewareOverride: native.WithQueryParams,
+	}); err != nil {
+		return err
+	}
 
 	// List tag endpoints.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
@@ -305,6 +340,34 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// Cardinality explorer endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:               native.CardinalityURL,
+		Handler:            native.NewCardinalityHandler(h.options),
+		Methods:            native.CardinalityHTTPMethods,
+		MiddlewareOverride: native.WithQueryParams,
+	}); err != nil {
+		return err
+	}
+
+	// Metric metadata endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.MetadataURL,
+		Handler: native.NewMetadataHandler(h.options),
+		Methods: native.MetadataHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
+	// Ingest accounting endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.IngestAccountingURL,
+		Handler: native.NewIngestAccountingHandler(h.options),
+		Methods: native.IngestAccountingHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
 	// Query parse endpoints.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    native.PromParseURL,
@@ -313,6 +376,14 @@ func (h *Handler) RegisterRoutes() error {
 	}); err != nil {
 		return err
 	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:               native.PromExplainURL,
+		Handler:            native.NewPromExplainHandler(h.options),
+		Methods:            native.PromExplainHTTPMethods,
+		MiddlewareOverride: native.WithQueryParams,
+	}); err != nil {
+		return err
+	}
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    native.PromThresholdURL,
 		Handler: native.NewPromThresholdHandler(h.options),
@@ -435,6 +506,9 @@ func (h *Handler) RegisterRoutes() error {
 	if err := h.registerRoutesEndpoint(); err != nil {
 		return err
 	}
+	if err := h.registerLogLevelEndpoint(); err != nil {
+		return err
+	}
 
 	customMiddle := make(map[*mux.Route]middleware.OverrideOptions)
 	// Register custom endpoints last to have these conflict with
@@ -473,6 +547,25 @@ func (h *Handler) RegisterRoutes() error {
 	middleIOpts := instrumentOpts.SetMetricsScope(
 		h.options.InstrumentOpts().MetricsScope().SubScope("http_handler_http_handler"))
 
+	var queryFirewallRules *middleware.QueryFirewallRules
+	if cfg := h.middlewareConfig.QueryFirewall; cfg.Enabled {
+		queryFirewallRules, err = middleware.NewQueryFirewallRules(cfg, middleIOpts)
+		if err != nil {
+			return err
+		}
+		if cfg.KVKey != "" {
+			h.watchQueryFirewallRules(queryFirewallRules)
+		}
+	}
+
+	var stickyQueryRouter *middleware.StickyQueryRouter
+	if cfg := h.middlewareConfig.StickyQueryRouting; cfg.Enabled {
+		stickyQueryRouter, err = middleware.NewStickyQueryRouter(cfg, middleIOpts)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply middleware after the custom handlers have overridden the previous handlers so the middleware functions
 	// are dispatched before the custom handler.
 	// req -> middleware fns -> custom handler -> previous handler.
@@ -494,6 +587,12 @@ func (h *Handler) RegisterRoutes() error {
 				ResolutionMultiplier: h.middlewareConfig.Prometheus.ResolutionMultiplier,
 				Storage:              h.options.Storage(),
 			},
+			QueryFirewall: middleware.QueryFirewallOptions{
+				Rules: queryFirewallRules,
+			},
+			StickyQueryRouting: middleware.StickyQueryRoutingOptions{
+				Router: stickyQueryRouter,
+			},
 		}
 		override := h.registry.MiddlewareOpts(route)
 		if override != nil {
@@ -521,6 +620,30 @@ func (h *Handler) RegisterRoutes() error {
 	return nil
 }
 
+func (h *Handler) watchQueryFirewallRules(rules *middleware.QueryFirewallRules) {
+	clusterClient := h.options.ClusterClient()
+	if clusterClient == nil {
+		h.logger.Warn("query firewall kv key set but no cluster client configured")
+		return
+	}
+
+	// NB: the cluster client may be initialized asynchronously so retry
+	// resolving the KV store in the background.
+	go func() {
+		retrier := retry.NewRetrier(retry.NewOptions().SetForever(true))
+		err := retrier.Attempt(func() error {
+			store, err := clusterClient.KV()
+			if err != nil {
+				return err
+			}
+			return rules.Watch(store)
+		})
+		if err != nil {
+			h.logger.Error("could not watch query firewall rules", zap.Error(err))
+		}
+	}()
+}
+
 func (h *Handler) placementOpts() (placementhandler.HandlerOptions, error) {
 	return placementhandler.NewHandlerOptions(
 		h.options.ClusterClient(),
@@ -581,6 +704,20 @@ func (h *Handler) registerProfileEndpoints() error {
 	})
 }
 
+// Endpoint to change log levels at runtime.
+func (h *Handler) registerLogLevelEndpoint() error {
+	levels, ok := xlog.LevelsFromLogger(h.logger)
+	if !ok {
+		return nil
+	}
+
+	return h.registry.Register(queryhttp.RegisterOptions{
+		Path:    xlog.LevelURL,
+		Handler: xlog.NewLevelHandler(levels),
+		Methods: methods(http.MethodGet, http.MethodPut, http.MethodPost),
+	})
+}
+
 // Endpoints useful for viewing routes directory.
 func (h *Handler) registerRoutesEndpoint() error {
 	return h.registry.Register(queryhttp.RegisterOptions{
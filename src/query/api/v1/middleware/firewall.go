@@ -0,0 +1,358 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+var (
+	errQueryFirewallRuleNoName       = errors.New("query firewall rule must have a name")
+	errQueryFirewallRuleNoConditions = errors.New("query firewall rule must have at least one condition")
+	errQueryFirewallRewriteNoRegexp  = errors.New("query firewall rewrite rule must set a query regexp")
+)
+
+// QueryFirewallOptions are the options for the query firewall middleware.
+type QueryFirewallOptions struct {
+	Enabled bool
+	Instant bool
+	Rules   *QueryFirewallRules
+}
+
+// QueryFirewall is middleware that, when enabled, checks the query parameter
+// on the request against the query firewall rules and either rejects the
+// request with a helpful error or rewrites the query before it is executed.
+func QueryFirewall(opts Options) mux.MiddlewareFunc {
+	return func(base http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mwOpts := opts.QueryFirewall
+			if !mwOpts.Enabled || mwOpts.Rules == nil {
+				base.ServeHTTP(w, r)
+				return
+			}
+
+			if err := mwOpts.Rules.apply(r, mwOpts.Instant); err != nil {
+				xhttp.WriteError(w, err)
+				return
+			}
+			base.ServeHTTP(w, r)
+		})
+	}
+}
+
+// QueryFirewallRules is the set of active query firewall rules, which may be
+// dynamically updated from KV.
+type QueryFirewallRules struct {
+	rules       atomic.Value // []queryFirewallRule
+	staticRules []queryFirewallRule
+	kvKey       string
+	scope       tally.Scope
+	logger      *zap.Logger
+}
+
+type queryFirewallRule struct {
+	config.QueryFirewallRuleConfiguration
+
+	queryRegexp *regexp.Regexp
+	hits        tally.Counter
+}
+
+// NewQueryFirewallRules returns the query firewall rules for the given configuration.
+func NewQueryFirewallRules(
+	cfg config.QueryFirewallConfiguration,
+	iOpts instrument.Options,
+) (*QueryFirewallRules, error) {
+	f := &QueryFirewallRules{
+		kvKey:  cfg.KVKey,
+		scope:  iOpts.MetricsScope().SubScope("query-firewall"),
+		logger: iOpts.Logger(),
+	}
+
+	rules, err := f.compile(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	f.staticRules = rules
+	f.rules.Store(rules)
+	return f, nil
+}
+
+// Watch watches the configured KV key for rules which, when set, replace the
+// static rules. It is a no-op if no KV key is configured.
+func (f *QueryFirewallRules) Watch(store kv.Store) error {
+	if f.kvKey == "" {
+		return nil
+	}
+
+	watch, err := store.Watch(f.kvKey)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for range watch.C() {
+			rules := f.staticRules
+			if newValue := watch.Get(); newValue != nil {
+				protoValue := &commonpb.StringProto{}
+				if err := newValue.Unmarshal(protoValue); err != nil {
+					f.logger.Warn("unable to parse query firewall rules", zap.Error(err))
+					continue
+				}
+
+				var cfgs []config.QueryFirewallRuleConfiguration
+				if err := yaml.Unmarshal([]byte(protoValue.Value), &cfgs); err != nil {
+					f.logger.Warn("unable to unmarshal query firewall rules", zap.Error(err))
+					continue
+				}
+
+				rules, err = f.compile(cfgs)
+				if err != nil {
+					f.logger.Warn("invalid query firewall rules", zap.Error(err))
+					continue
+				}
+			}
+
+			f.rules.Store(rules)
+		}
+	}()
+
+	return nil
+}
+
+func (f *QueryFirewallRules) compile(
+	cfgs []config.QueryFirewallRuleConfiguration,
+) ([]queryFirewallRule, error) {
+	rules := make([]queryFirewallRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, errQueryFirewallRuleNoName
+		}
+
+		switch cfg.Action {
+		case config.QueryFirewallBlock:
+		case config.QueryFirewallRewrite:
+			if cfg.QueryRegexp == "" {
+				return nil, fmt.Errorf("rule %s: %w", cfg.Name, errQueryFirewallRewriteNoRegexp)
+			}
+		default:
+			return nil, fmt.Errorf("rule %s: unknown query firewall action: %s", cfg.Name, cfg.Action)
+		}
+
+		if cfg.QueryRegexp == "" && !cfg.UnboundedSelector && cfg.MinRange == 0 && cfg.MaxStep == 0 {
+			return nil, fmt.Errorf("rule %s: %w", cfg.Name, errQueryFirewallRuleNoConditions)
+		}
+
+		rule := queryFirewallRule{
+			QueryFirewallRuleConfiguration: cfg,
+			hits: f.scope.Tagged(map[string]string{
+				"rule":   cfg.Name,
+				"action": string(cfg.Action),
+			}).Counter("hits"),
+		}
+		if cfg.QueryRegexp != "" {
+			re, err := regexp.Compile(cfg.QueryRegexp)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: invalid query regexp: %w", cfg.Name, err)
+			}
+			rule.queryRegexp = re
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// queryFirewallRequest lazily resolves the properties of a request that
+// rules are matched against.
+type queryFirewallRequest struct {
+	r       *http.Request
+	instant bool
+	query   string
+
+	expr       parser.Expr
+	exprParsed bool
+
+	queryRange  time.Duration
+	step        time.Duration
+	rangeParsed bool
+}
+
+func (q *queryFirewallRequest) setQuery(query string) {
+	q.query = query
+	q.expr = nil
+	q.exprParsed = false
+}
+
+func (q *queryFirewallRequest) hasUnboundedSelector() bool {
+	if !q.exprParsed {
+		q.exprParsed = true
+		// NB: queries that fail to parse are left for the handler to reject.
+		q.expr, _ = parser.ParseExpr(q.query)
+	}
+	if q.expr == nil {
+		return false
+	}
+
+	found := false
+	parser.Inspect(q.expr, func(node parser.Node, _ []parser.Node) error {
+		if vs, ok := node.(*parser.VectorSelector); ok && isUnboundedSelector(vs.LabelMatchers) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+func (q *queryFirewallRequest) rangeAndStep() (time.Duration, time.Duration, bool) {
+	if q.instant {
+		return 0, 0, false
+	}
+	if !q.rangeParsed {
+		q.rangeParsed = true
+		// NB: params that fail to parse are left for the handler to reject.
+		if params, err := prometheus.ParseTimeParams(q.r); err == nil {
+			q.queryRange = params.End.Sub(params.Start)
+		}
+		if step, ok, err := handleroptions.ParseStep(q.r); err == nil && ok {
+			q.step = step
+		}
+	}
+	return q.queryRange, q.step, q.step > 0
+}
+
+func (r queryFirewallRule) matches(q *queryFirewallRequest) bool {
+	if r.queryRegexp != nil && !r.queryRegexp.MatchString(q.query) {
+		return false
+	}
+	if r.UnboundedSelector && !q.hasUnboundedSelector() {
+		return false
+	}
+	if r.MinRange > 0 || r.MaxStep > 0 {
+		queryRange, step, ok := q.rangeAndStep()
+		if !ok {
+			return false
+		}
+		if r.MinRange > 0 && queryRange < r.MinRange {
+			return false
+		}
+		if r.MaxStep > 0 && step > r.MaxStep {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *QueryFirewallRules) apply(r *http.Request, instant bool) error {
+	rules, _ := f.rules.Load().([]queryFirewallRule)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return xerrors.NewInvalidParamsError(err)
+	}
+	defer func() {
+		// Reset the body on the request for any handlers that may want access to the raw body.
+		if r.Method == http.MethodGet {
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewBufferString(r.Form.Encode()))
+	}()
+
+	originalQuery := r.FormValue(queryParam)
+	if originalQuery == "" {
+		return nil
+	}
+
+	q := &queryFirewallRequest{r: r, instant: instant, query: originalQuery}
+	for _, rule := range rules {
+		if !rule.matches(q) {
+			continue
+		}
+
+		rule.hits.Inc(1)
+		if rule.Action == config.QueryFirewallBlock {
+			msg := rule.Message
+			if msg == "" {
+				msg = "query matches a known expensive pattern"
+			}
+			return xerrors.NewInvalidParamsError(fmt.Errorf(
+				"query blocked by firewall rule %s: %s", rule.Name, msg))
+		}
+
+		q.setQuery(rule.queryRegexp.ReplaceAllString(q.query, rule.Rewrite))
+	}
+
+	if q.query == originalQuery {
+		return nil
+	}
+
+	if err := setQueryParam(r, q.query); err != nil {
+		return err
+	}
+
+	f.logger.Debug("query firewall rewrote query",
+		zap.String("originalQuery", originalQuery),
+		zap.String("updatedQuery", q.query))
+	return nil
+}
+
+// isUnboundedSelector returns true if every matcher of the selector matches
+// any non-empty value.
+func isUnboundedSelector(matchers []*labels.Matcher) bool {
+	if len(matchers) == 0 {
+		return false
+	}
+	for _, m := range matchers {
+		switch {
+		case m.Type == labels.MatchRegexp && (m.Value == ".*" || m.Value == ".+"):
+		case m.Type == labels.MatchNotEqual && m.Value == "":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	xclock "github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+var testQueryFirewallRules = []config.QueryFirewallRuleConfiguration{
+	{
+		Name:              "unbounded-selector",
+		Action:            config.QueryFirewallBlock,
+		Message:           "add a metric name to the selector",
+		UnboundedSelector: true,
+	},
+	{
+		Name:     "long-high-res-range",
+		Action:   config.QueryFirewallBlock,
+		MinRange: 30 * 24 * time.Hour,
+		MaxStep:  time.Minute,
+	},
+	{
+		Name:        "short-rate",
+		Action:      config.QueryFirewallRewrite,
+		QueryRegexp: `rate\((\w+)\[\d+s\]\)`,
+		Rewrite:     "rate(${1}[1m])",
+	},
+}
+
+func TestQueryFirewall(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		start     string
+		end       string
+		step      string
+		instant   bool
+		expected  string
+		blockedBy string
+		disabled  bool
+		noRules   bool
+		method    string
+	}{
+		{
+			name:     "no match",
+			query:    `foo{bar="baz"}`,
+			start:    "1614882294",
+			end:      "1614885894",
+			step:     "15s",
+			expected: `foo{bar="baz"}`,
+		},
+		{
+			name:      "unbounded selector",
+			query:     `sum({__name__=~".+"})`,
+			start:     "1614882294",
+			end:       "1614885894",
+			step:      "15s",
+			blockedBy: "unbounded-selector",
+		},
+		{
+			name:      "unbounded selector instant",
+			query:     `{__name__=~".*", job!=""}`,
+			instant:   true,
+			blockedBy: "unbounded-selector",
+		},
+		{
+			name:     "bounded selector with regexp",
+			query:    `{__name__=~"foo.+"}`,
+			start:    "1614882294",
+			end:      "1614885894",
+			step:     "15s",
+			expected: `{__name__=~"foo.+"}`,
+		},
+		{
+			name:      "long range at high resolution",
+			query:     "foo",
+			start:     "1614882294",
+			end:       "1625250298",
+			step:      "15s",
+			blockedBy: "long-high-res-range",
+		},
+		{
+			name:     "long range at low resolution",
+			query:    "foo",
+			start:    "1614882294",
+			end:      "1625250298",
+			step:     "1h",
+			expected: "foo",
+		},
+		{
+			name:     "rewrite",
+			query:    "sum(rate(foo[15s]))",
+			start:    "1614882294",
+			end:      "1614885894",
+			step:     "15s",
+			expected: "sum(rate(foo[1m]))",
+		},
+		{
+			name:     "rewrite with post",
+			query:    "sum(rate(foo[15s]))",
+			start:    "1614882294",
+			end:      "1614885894",
+			step:     "15s",
+			method:   http.MethodPost,
+			expected: "sum(rate(foo[1m]))",
+		},
+		{
+			name:     "disabled",
+			query:    `sum({__name__=~".+"})`,
+			instant:  true,
+			disabled: true,
+			expected: `sum({__name__=~".+"})`,
+		},
+		{
+			name:     "no rules",
+			query:    `sum({__name__=~".+"})`,
+			instant:  true,
+			noRules:  true,
+			expected: `sum({__name__=~".+"})`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := tally.NewTestScope("", nil)
+			iOpts := instrument.NewOptions().SetMetricsScope(scope)
+
+			var rules []config.QueryFirewallRuleConfiguration
+			if !tt.noRules {
+				rules = testQueryFirewallRules
+			}
+			firewallRules, err := NewQueryFirewallRules(config.QueryFirewallConfiguration{
+				Enabled: true,
+				Rules:   rules,
+			}, iOpts)
+			require.NoError(t, err)
+
+			r := mux.NewRouter()
+			var query string
+			r.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+				query = r.FormValue(queryParam)
+			})
+			r.Use(QueryFirewall(Options{
+				InstrumentOpts: iOpts,
+				QueryFirewall: QueryFirewallOptions{
+					Enabled: !tt.disabled,
+					Instant: tt.instant,
+					Rules:   firewallRules,
+				},
+			}))
+
+			params := url.Values{}
+			params.Add(queryParam, tt.query)
+			if !tt.instant {
+				params.Add(startParam, tt.start)
+				params.Add(endParam, tt.end)
+				params.Add("step", tt.step)
+			}
+
+			var req *http.Request
+			if tt.method == http.MethodPost {
+				req = httptest.NewRequest(http.MethodPost, "/query", nil)
+				req.Form = params
+			} else {
+				req = httptest.NewRequest(http.MethodGet, "/query?"+params.Encode(), nil)
+			}
+
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+
+			if tt.blockedBy != "" {
+				require.Equal(t, http.StatusBadRequest, resp.Code)
+				require.Contains(t, resp.Body.String(), "firewall rule "+tt.blockedBy)
+				counter, ok := scope.Snapshot().Counters()["query-firewall.hits+action=block,rule="+tt.blockedBy]
+				require.True(t, ok)
+				require.Equal(t, int64(1), counter.Value())
+				return
+			}
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			require.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestQueryFirewallRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.QueryFirewallRuleConfiguration
+	}{
+		{
+			name: "no name",
+			rule: config.QueryFirewallRuleConfiguration{
+				Action:            config.QueryFirewallBlock,
+				UnboundedSelector: true,
+			},
+		},
+		{
+			name: "unknown action",
+			rule: config.QueryFirewallRuleConfiguration{
+				Name:              "foo",
+				Action:            "drop",
+				UnboundedSelector: true,
+			},
+		},
+		{
+			name: "no conditions",
+			rule: config.QueryFirewallRuleConfiguration{
+				Name:   "foo",
+				Action: config.QueryFirewallBlock,
+			},
+		},
+		{
+			name: "rewrite without regexp",
+			rule: config.QueryFirewallRuleConfiguration{
+				Name:     "foo",
+				Action:   config.QueryFirewallRewrite,
+				MinRange: time.Hour,
+			},
+		},
+		{
+			name: "invalid regexp",
+			rule: config.QueryFirewallRuleConfiguration{
+				Name:        "foo",
+				Action:      config.QueryFirewallBlock,
+				QueryRegexp: "(",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewQueryFirewallRules(config.QueryFirewallConfiguration{
+				Rules: []config.QueryFirewallRuleConfiguration{tt.rule},
+			}, instrument.NewOptions())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestQueryFirewallRulesWatch(t *testing.T) {
+	key := "query-firewall-rules"
+	rules, err := NewQueryFirewallRules(config.QueryFirewallConfiguration{
+		Enabled: true,
+		Rules:   testQueryFirewallRules,
+		KVKey:   key,
+	}, instrument.NewOptions())
+	require.NoError(t, err)
+
+	store := mem.NewStore()
+	require.NoError(t, rules.Watch(store))
+
+	loaded := func() []queryFirewallRule {
+		v, _ := rules.rules.Load().([]queryFirewallRule)
+		return v
+	}
+	require.Equal(t, len(testQueryFirewallRules), len(loaded()))
+
+	_, err = store.Set(key, &commonpb.StringProto{Value: `
+- name: kv-rule
+  action: block
+  minRange: 720h
+`})
+	require.NoError(t, err)
+	require.True(t, xclock.WaitUntil(func() bool {
+		current := loaded()
+		return len(current) == 1 && current[0].Name == "kv-rule" &&
+			current[0].MinRange == 720*time.Hour
+	}, 5*time.Second))
+
+	// Invalid rules are ignored.
+	_, err = store.Set(key, &commonpb.StringProto{Value: `- name: invalid`})
+	require.NoError(t, err)
+
+	// Deleting the key restores the static rules.
+	_, err = store.Delete(key)
+	require.NoError(t, err)
+	require.True(t, xclock.WaitUntil(func() bool {
+		return len(loaded()) == len(testQueryFirewallRules)
+	}, 5*time.Second))
+}
@@ -134,7 +134,7 @@ func TestPanicErrorResponder(t *testing.T) {
 
 	assert.Equal(t, 500, writer.status)
 	require.Equal(t, 1, len(writer.written))
-	assert.JSONEq(t, `{"status":"error","error":"caught panic: beef"}`, writer.written[0])
+	assert.JSONEq(t, `{"status":"error","error":"caught panic: beef","code":"internal","retryable":false}`, writer.written[0])
 
 	assertNoErrorLogs(t, stderr)
 	b, err := ioutil.ReadAll(stdout)
@@ -296,7 +296,7 @@ func TestWithResponseTimeAndPanicErrorLoggingFunc(t *testing.T) {
 
 	assert.Equal(t, 500, writer.status)
 	require.Equal(t, 1, len(writer.written))
-	assert.JSONEq(t, `{"status":"error","error":"caught panic: err"}`, writer.written[0])
+	assert.JSONEq(t, `{"status":"error","error":"caught panic: err","code":"internal","retryable":false}`, writer.written[0])
 
 	assertNoErrorLogs(t, stderr)
 
@@ -48,6 +48,8 @@ type Options struct {
 	Metrics                MetricsOptions
 	Source                 SourceOptions
 	PrometheusRangeRewrite PrometheusRangeRewriteOptions
+	QueryFirewall          QueryFirewallOptions
+	StickyQueryRouting     StickyQueryRoutingOptions
 }
 
 // OverrideOptions is a function that returns new Options from the provided Options.
@@ -64,6 +66,10 @@ func Default(opts Options) []mux.MiddlewareFunc {
 		// install source before logging so the source is available for response logging.
 		Source(opts),
 		RequestID(opts.InstrumentOpts),
+		// install sticky routing before the query firewall and range rewriting so peers receive the query as issued.
+		StickyQueryRouting(opts),
+		// install the query firewall before range rewriting so rules match the query as issued.
+		QueryFirewall(opts),
 		PrometheusRangeRewrite(opts),
 		ResponseLogging(opts),
 		ResponseMetrics(opts),
@@ -147,14 +147,27 @@ func rewriteRangeDuration(
 
 	// Add updated query to the request where necessary
 	updatedQuery := expr.String()
+	if err := setQueryParam(r, updatedQuery); err != nil {
+		return err
+	}
 
+	logger.Debug("rewrote range duration value within query",
+		zap.String("originalQuery", query),
+		zap.String("updatedQuery", updatedQuery))
+
+	return nil
+}
+
+// setQueryParam updates the query param in the URL and the parsed form of the
+// request, if present.
+func setQueryParam(r *http.Request, query string) error {
 	// Update query param in URL, if present
 	urlQueryValues, err := url.ParseQuery(r.URL.RawQuery)
 	if err != nil {
 		return err
 	}
 	if urlQueryValues.Get(queryParam) != "" {
-		urlQueryValues.Set(queryParam, updatedQuery)
+		urlQueryValues.Set(queryParam, query)
 	}
 	updatedURL, err := url.Parse(r.URL.String())
 	if err != nil {
@@ -165,13 +178,9 @@ func rewriteRangeDuration(
 
 	// Update query param in the request body, if present
 	if r.Form.Get(queryParam) != "" {
-		r.Form.Set(queryParam, updatedQuery)
+		r.Form.Set(queryParam, query)
 	}
 
-	logger.Debug("rewrote range duration value within query",
-		zap.String("originalQuery", query),
-		zap.String("updatedQuery", updatedQuery))
-
 	return nil
 }
 
@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/gorilla/mux"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/hash/jump"
+	"github.com/m3db/m3/src/x/headers"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+var (
+	errStickyQueryRoutingNoPeers = errors.New("sticky query routing requires at least one peer")
+	errStickyQueryRoutingNoSelf  = errors.New("sticky query routing self must be one of the peers")
+)
+
+// StickyQueryRoutingOptions are the options for the sticky query routing middleware.
+type StickyQueryRoutingOptions struct {
+	Enabled bool
+	Router  *StickyQueryRouter
+}
+
+// StickyQueryRouting is middleware that, when enabled, forwards queries to the
+// peer coordinator that owns the query's routing key so that the same tenant
+// or query is always served by the same coordinator. Queries that this
+// coordinator owns, that were already forwarded by a peer, or whose owner is
+// unreachable are served locally.
+func StickyQueryRouting(opts Options) mux.MiddlewareFunc {
+	return func(base http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mwOpts := opts.StickyQueryRouting
+			if !mwOpts.Enabled || mwOpts.Router == nil {
+				base.ServeHTTP(w, r)
+				return
+			}
+
+			mwOpts.Router.serve(w, r, base)
+		})
+	}
+}
+
+// StickyQueryRouter consistently maps query routing keys to peer coordinators.
+type StickyQueryRouter struct {
+	self         string
+	peers        []string
+	proxies      []*httputil.ReverseProxy
+	tenantHeader string
+	metrics      stickyQueryRoutingMetrics
+	logger       *zap.Logger
+}
+
+type stickyQueryRoutingMetrics struct {
+	local         tally.Counter
+	forwarded     tally.Counter
+	received      tally.Counter
+	forwardErrors tally.Counter
+}
+
+func newStickyQueryRoutingMetrics(scope tally.Scope) stickyQueryRoutingMetrics {
+	return stickyQueryRoutingMetrics{
+		local:         scope.Counter("local"),
+		forwarded:     scope.Counter("forwarded"),
+		received:      scope.Counter("received"),
+		forwardErrors: scope.Counter("forward-errors"),
+	}
+}
+
+type stickyQueryRoutingForwardKey struct{}
+
+// stickyQueryRoutingForward is attached to forwarded requests so that a
+// failed forward can fall back to serving the query locally.
+type stickyQueryRoutingForward struct {
+	peer     string
+	fallback func()
+}
+
+// NewStickyQueryRouter returns a sticky query router for the given configuration.
+func NewStickyQueryRouter(
+	cfg config.StickyQueryRoutingConfiguration,
+	iOpts instrument.Options,
+) (*StickyQueryRouter, error) {
+	if len(cfg.Peers) == 0 {
+		return nil, errStickyQueryRoutingNoPeers
+	}
+
+	r := &StickyQueryRouter{
+		self:         cfg.Self,
+		peers:        cfg.Peers,
+		proxies:      make([]*httputil.ReverseProxy, 0, len(cfg.Peers)),
+		tenantHeader: cfg.TenantHeader,
+		metrics: newStickyQueryRoutingMetrics(
+			iOpts.MetricsScope().SubScope("sticky-query-routing")),
+		logger: iOpts.Logger(),
+	}
+
+	foundSelf := false
+	for _, peer := range cfg.Peers {
+		if peer == cfg.Self {
+			foundSelf = true
+		}
+		peerURL, err := url.Parse("http://" + peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sticky query routing peer %s: %w", peer, err)
+		}
+		proxy := httputil.NewSingleHostReverseProxy(peerURL)
+		proxy.ErrorHandler = r.forwardError
+		r.proxies = append(r.proxies, proxy)
+	}
+	if !foundSelf {
+		return nil, errStickyQueryRoutingNoSelf
+	}
+
+	return r, nil
+}
+
+// Peer returns the address of the peer coordinator that owns the routing key.
+func (r *StickyQueryRouter) Peer(key string) string {
+	return r.peers[r.peerIndex(key)]
+}
+
+func (r *StickyQueryRouter) peerIndex(key string) int {
+	return int(jump.Hash(xxhash.Sum64String(key), int64(len(r.peers))))
+}
+
+func (r *StickyQueryRouter) serve(w http.ResponseWriter, req *http.Request, base http.Handler) {
+	if req.Header.Get(headers.StickyRoutedHeader) != "" {
+		r.metrics.received.Inc(1)
+		base.ServeHTTP(w, req)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	// NB: the form is re-encoded as the body so that it can be read again
+	// either by the peer the query is forwarded to or by the local handler.
+	var body []byte
+	if req.Method != http.MethodGet {
+		body = []byte(req.PostForm.Encode())
+	}
+	resetBody := func(req *http.Request) {
+		if req.Method == http.MethodGet {
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+	resetBody(req)
+
+	key := ""
+	if r.tenantHeader != "" {
+		key = req.Header.Get(r.tenantHeader)
+	}
+	if key == "" {
+		key = req.FormValue(queryParam)
+	}
+	if key == "" {
+		base.ServeHTTP(w, req)
+		return
+	}
+
+	idx := r.peerIndex(key)
+	if r.peers[idx] == r.self {
+		r.metrics.local.Inc(1)
+		base.ServeHTTP(w, req)
+		return
+	}
+
+	r.metrics.forwarded.Inc(1)
+	fallback := func() {
+		resetBody(req)
+		base.ServeHTTP(w, req)
+	}
+	forward := stickyQueryRoutingForward{peer: r.peers[idx], fallback: fallback}
+	forwardReq := req.WithContext(context.WithValue(req.Context(),
+		stickyQueryRoutingForwardKey{}, forward))
+	forwardReq.Header = req.Header.Clone()
+	forwardReq.Header.Set(headers.StickyRoutedHeader, r.self)
+	r.proxies[idx].ServeHTTP(w, forwardReq)
+}
+
+// forwardError serves a query locally when it could not be forwarded to the
+// peer that owns it.
+func (r *StickyQueryRouter) forwardError(w http.ResponseWriter, req *http.Request, err error) {
+	r.metrics.forwardErrors.Inc(1)
+
+	forward, ok := req.Context().Value(stickyQueryRoutingForwardKey{}).(stickyQueryRoutingForward)
+	if !ok {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	r.logger.Warn("could not forward query to peer, serving locally",
+		zap.String("peer", forward.peer), zap.Error(err))
+	forward.fallback()
+}
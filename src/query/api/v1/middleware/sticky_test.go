@@ -0,0 +1,200 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package middleware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/x/headers"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const testStickySelf = "127.0.0.1:1"
+
+type testStickyPeer struct {
+	server   *httptest.Server
+	query    string
+	routedBy string
+}
+
+func newTestStickyPeer() *testStickyPeer {
+	p := &testStickyPeer{}
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.query = r.FormValue(queryParam)
+		p.routedBy = r.Header.Get(headers.StickyRoutedHeader)
+		_, _ = w.Write([]byte("peer"))
+	}))
+	return p
+}
+
+func (p *testStickyPeer) addr() string {
+	return p.server.Listener.Addr().String()
+}
+
+func newTestStickyQueryRouter(
+	t *testing.T,
+	peer *testStickyPeer,
+	tenantHeader string,
+) *StickyQueryRouter {
+	router, err := NewStickyQueryRouter(config.StickyQueryRoutingConfiguration{
+		Enabled:      true,
+		Self:         testStickySelf,
+		Peers:        []string{testStickySelf, peer.addr()},
+		TenantHeader: tenantHeader,
+	}, instrument.NewOptions())
+	require.NoError(t, err)
+	return router
+}
+
+// testStickyKey returns a routing key owned by the given peer.
+func testStickyKey(t *testing.T, router *StickyQueryRouter, owner string) string {
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("sum(foo_%d)", i)
+		if router.Peer(key) == owner {
+			return key
+		}
+	}
+	require.FailNow(t, "no key found for peer", owner)
+	return ""
+}
+
+func serveTestStickyRequest(
+	router *StickyQueryRouter,
+	enabled bool,
+	r *http.Request,
+) (string, string) {
+	var localQuery string
+	h := StickyQueryRouting(Options{
+		StickyQueryRouting: StickyQueryRoutingOptions{
+			Enabled: enabled,
+			Router:  router,
+		},
+	}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		localQuery = r.FormValue(queryParam)
+		_, _ = w.Write([]byte("local"))
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	return string(body), localQuery
+}
+
+func newTestStickyRequest(method, query string) *http.Request {
+	values := url.Values{queryParam: []string{query}}
+	if method == http.MethodGet {
+		return httptest.NewRequest(method, "/api/v1/query?"+values.Encode(), nil)
+	}
+	r := httptest.NewRequest(method, "/api/v1/query", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestStickyQueryRouting(t *testing.T) {
+	peer := newTestStickyPeer()
+	defer peer.server.Close()
+
+	router := newTestStickyQueryRouter(t, peer, "")
+	localKey := testStickyKey(t, router, testStickySelf)
+	peerKey := testStickyKey(t, router, peer.addr())
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			served, query := serveTestStickyRequest(router, true,
+				newTestStickyRequest(method, localKey))
+			require.Equal(t, "local", served)
+			require.Equal(t, localKey, query)
+
+			served, _ = serveTestStickyRequest(router, true,
+				newTestStickyRequest(method, peerKey))
+			require.Equal(t, "peer", served)
+			require.Equal(t, peerKey, peer.query)
+			require.Equal(t, testStickySelf, peer.routedBy)
+
+			// Queries already forwarded by a peer are always served locally.
+			r := newTestStickyRequest(method, peerKey)
+			r.Header.Set(headers.StickyRoutedHeader, "other")
+			served, query = serveTestStickyRequest(router, true, r)
+			require.Equal(t, "local", served)
+			require.Equal(t, peerKey, query)
+
+			// Routing is skipped when disabled for the route.
+			served, _ = serveTestStickyRequest(router, false,
+				newTestStickyRequest(method, peerKey))
+			require.Equal(t, "local", served)
+		})
+	}
+}
+
+func TestStickyQueryRoutingTenantHeader(t *testing.T) {
+	peer := newTestStickyPeer()
+	defer peer.server.Close()
+
+	router := newTestStickyQueryRouter(t, peer, "Tenant")
+	localKey := testStickyKey(t, router, testStickySelf)
+	peerKey := testStickyKey(t, router, peer.addr())
+
+	r := newTestStickyRequest(http.MethodGet, localKey)
+	r.Header.Set("Tenant", peerKey)
+	served, _ := serveTestStickyRequest(router, true, r)
+	require.Equal(t, "peer", served)
+
+	r = newTestStickyRequest(http.MethodGet, peerKey)
+	r.Header.Set("Tenant", localKey)
+	served, _ = serveTestStickyRequest(router, true, r)
+	require.Equal(t, "local", served)
+}
+
+func TestStickyQueryRoutingPeerUnavailable(t *testing.T) {
+	peer := newTestStickyPeer()
+	router := newTestStickyQueryRouter(t, peer, "")
+	peerKey := testStickyKey(t, router, peer.addr())
+	peer.server.Close()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		served, query := serveTestStickyRequest(router, true,
+			newTestStickyRequest(method, peerKey))
+		require.Equal(t, "local", served)
+		require.Equal(t, peerKey, query)
+	}
+}
+
+func TestNewStickyQueryRouterErrors(t *testing.T) {
+	_, err := NewStickyQueryRouter(config.StickyQueryRoutingConfiguration{
+		Self: testStickySelf,
+	}, instrument.NewOptions())
+	require.Equal(t, errStickyQueryRoutingNoPeers, err)
+
+	_, err = NewStickyQueryRouter(config.StickyQueryRoutingConfiguration{
+		Self:  testStickySelf,
+		Peers: []string{"127.0.0.1:2"},
+	}, instrument.NewOptions())
+	require.Equal(t, errStickyQueryRoutingNoSelf, err)
+}
@@ -43,7 +43,9 @@ import (
 	graphite "github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/instrument"
@@ -274,6 +276,18 @@ type HandlerOptions interface {
 	SetRegisterMiddleware(value middleware.Register) HandlerOptions
 	// RegisterMiddleware returns the function to construct the set of Middleware functions to run.
 	RegisterMiddleware() middleware.Register
+
+	// SetMetricMetadataStore sets the store for Prometheus metric metadata.
+	SetMetricMetadataStore(value metricmetadata.Store) HandlerOptions
+	// MetricMetadataStore returns the store for Prometheus metric metadata,
+	// nil if storing metric metadata is disabled.
+	MetricMetadataStore() metricmetadata.Store
+
+	// SetIngestAccountant sets the accountant of ingested traffic by source.
+	SetIngestAccountant(value ingestaccounting.Accountant) HandlerOptions
+	// IngestAccountant returns the accountant of ingested traffic by source,
+	// nil if ingest accounting is disabled.
+	IngestAccountant() ingestaccounting.Accountant
 }
 
 // HandlerOptions represents handler options.
@@ -308,6 +322,8 @@ type handlerOptions struct {
 	registerMiddleware                middleware.Register
 	graphiteRenderRouter              GraphiteRenderRouter
 	graphiteFindRouter                GraphiteFindRouter
+	metricMetadataStore               metricmetadata.Store
+	ingestAccountant                  ingestaccounting.Accountant
 }
 
 // EmptyHandlerOptions returns  default handler options.
@@ -706,5 +722,25 @@ func (o *handlerOptions) SetRegisterMiddleware(value middleware.Register) Handle
 	return &opts
 }
 
+func (o *handlerOptions) SetMetricMetadataStore(value metricmetadata.Store) HandlerOptions {
+	opts := *o
+	opts.metricMetadataStore = value
+	return &opts
+}
+
+func (o *handlerOptions) MetricMetadataStore() metricmetadata.Store {
+	return o.metricMetadataStore
+}
+
+func (o *handlerOptions) SetIngestAccountant(value ingestaccounting.Accountant) HandlerOptions {
+	opts := *o
+	opts.ingestAccountant = value
+	return &opts
+}
+
+func (o *handlerOptions) IngestAccountant() ingestaccounting.Accountant {
+	return o.ingestAccountant
+}
+
 // KVStoreProtoParser parses protobuf messages based off specific keys.
 type KVStoreProtoParser func(key string) (protoiface.MessageV1, error)
@@ -0,0 +1,244 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/plan"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+// Explanation describes the logical and physical plans the engine builds
+// for a query, without executing it.
+type Explanation struct {
+	// Query is the explained query.
+	Query string `json:"query"`
+	// LogicalPlan are the steps of the logical plan built from the query DAG.
+	LogicalPlan []ExplainedStep `json:"logicalPlan"`
+	// PhysicalPlan is the physical plan the engine executes.
+	PhysicalPlan ExplainedPhysicalPlan `json:"physicalPlan"`
+}
+
+// ExplainedStep is a single step of a plan.
+type ExplainedStep struct {
+	// ID is the ID of the step.
+	ID string `json:"id"`
+	// Op is the type of the operation performed by the step.
+	Op string `json:"op"`
+	// Params are the parameters of the operation.
+	Params string `json:"params"`
+	// Parents are the IDs of the steps this step consumes the output of.
+	Parents []string `json:"parents,omitempty"`
+	// Children are the IDs of the steps consuming the output of this step.
+	Children []string `json:"children,omitempty"`
+}
+
+// ExplainedPhysicalPlan is the physical plan of a query.
+type ExplainedPhysicalPlan struct {
+	// Steps are the steps of the plan in the order they are performed.
+	Steps []ExplainedStep `json:"steps"`
+	// ResultStep is the ID of the step whose output is returned.
+	ResultStep string `json:"resultStep"`
+	// Bounds are the boundaries of the blocks the steps operate on, with
+	// the start shifted to include the largest range or lookback.
+	Bounds ExplainedBounds `json:"bounds"`
+	// LookbackDuration is the lookback duration of the query.
+	LookbackDuration string `json:"lookbackDuration"`
+	// Fetches are the fetches from storage the plan fans out to.
+	Fetches []ExplainedFetch `json:"fetches"`
+}
+
+// ExplainedBounds are the boundaries of the blocks of a plan.
+type ExplainedBounds struct {
+	// Start is the inclusive start of the blocks.
+	Start time.Time `json:"start"`
+	// End is the exclusive end of the blocks.
+	End time.Time `json:"end"`
+	// Step is the step size of the blocks.
+	Step string `json:"step"`
+	// Steps is the number of steps in the blocks.
+	Steps int `json:"steps"`
+}
+
+// ExplainedFetch is a fetch from storage of a plan.
+type ExplainedFetch struct {
+	// StepID is the ID of the step performing the fetch.
+	StepID string `json:"stepID"`
+	// Matchers are the matchers of the fetch, including restrictions
+	// applied by the fetch options.
+	Matchers []string `json:"matchers"`
+	// Start is the start of the fetched range.
+	Start time.Time `json:"start"`
+	// End is the end of the fetched range.
+	End time.Time `json:"end"`
+	// Range is the range of the fetch pushed down from range functions.
+	Range string `json:"range,omitempty"`
+	// Offset is the offset of the fetch pushed down from the offset modifier.
+	Offset string `json:"offset,omitempty"`
+	// Namespaces are the storage attributes of the namespaces the fetch
+	// fans out to.
+	Namespaces []string `json:"namespaces"`
+}
+
+func (e *engine) Explain(
+	ctx context.Context,
+	parser parser.Parser,
+	fetchOpts *storage.FetchOptions,
+	params models.RequestParams,
+) (Explanation, error) {
+	req := newRequest(e, params, fetchOpts, e.opts.InstrumentOptions())
+	nodes, edges, err := req.compile(ctx, parser)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	lp, err := plan.NewLogicalPlan(nodes, edges)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	pp, err := req.plan(ctx, nodes, edges)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	logicalSteps := make([]plan.LogicalStep, 0, len(lp.Pipeline))
+	for _, ID := range lp.Pipeline {
+		logicalSteps = append(logicalSteps, lp.Steps[ID])
+	}
+
+	physicalSteps := pp.Steps()
+	fetches, err := e.explainFetches(ctx, physicalSteps, pp.TimeSpec, fetchOpts)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	timeSpec := pp.TimeSpec
+	return Explanation{
+		Query:       params.Query,
+		LogicalPlan: explainSteps(logicalSteps),
+		PhysicalPlan: ExplainedPhysicalPlan{
+			Steps:      explainSteps(physicalSteps),
+			ResultStep: string(pp.ResultStep.Parent),
+			Bounds: ExplainedBounds{
+				Start: timeSpec.Start.ToTime(),
+				End:   timeSpec.End.ToTime(),
+				Step:  timeSpec.Step.String(),
+				Steps: timeSpec.Bounds().Steps(),
+			},
+			LookbackDuration: pp.LookbackDuration.String(),
+			Fetches:          fetches,
+		},
+	}, nil
+}
+
+func (e *engine) explainFetches(
+	ctx context.Context,
+	steps []plan.LogicalStep,
+	timeSpec transform.TimeSpec,
+	fetchOpts *storage.FetchOptions,
+) ([]ExplainedFetch, error) {
+	var fetches []ExplainedFetch
+	for _, step := range steps {
+		op, ok := step.Transform.Op.(functions.FetchOp)
+		if !ok {
+			continue
+		}
+
+		// NB: mirror the range fetched by fetch nodes, the physical plan
+		// already considers the range of the query.
+		query := &storage.FetchQuery{
+			Start:       timeSpec.Start.Add(-1 * op.Offset).ToTime(),
+			End:         timeSpec.End.Add(-1 * op.Offset).ToTime(),
+			TagMatchers: op.Matchers,
+			Interval:    timeSpec.Step,
+		}
+		query = query.WithAppliedOptions(fetchOpts)
+
+		attrs, err := e.opts.Store().QueryStorageMetadataAttributes(ctx,
+			query.Start, query.End, fetchOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		matchers := make([]string, 0, len(query.TagMatchers))
+		for _, matcher := range query.TagMatchers {
+			matchers = append(matchers, matcher.String())
+		}
+
+		namespaces := make([]string, 0, len(attrs))
+		for _, attr := range attrs {
+			namespaces = append(namespaces, attr.String())
+		}
+
+		fetch := ExplainedFetch{
+			StepID:     string(step.ID()),
+			Matchers:   matchers,
+			Start:      query.Start,
+			End:        query.End,
+			Namespaces: namespaces,
+		}
+		if op.Range > 0 {
+			fetch.Range = op.Range.String()
+		}
+		if op.Offset != 0 {
+			fetch.Offset = op.Offset.String()
+		}
+
+		fetches = append(fetches, fetch)
+	}
+
+	return fetches, nil
+}
+
+func explainSteps(steps []plan.LogicalStep) []ExplainedStep {
+	explained := make([]ExplainedStep, 0, len(steps))
+	for _, step := range steps {
+		explained = append(explained, ExplainedStep{
+			ID:       string(step.ID()),
+			Op:       step.Transform.Op.OpType(),
+			Params:   step.Transform.Op.String(),
+			Parents:  nodeIDStrings(step.Parents),
+			Children: nodeIDStrings(step.Children),
+		})
+	}
+
+	return explained
+}
+
+func nodeIDStrings(IDs []parser.NodeID) []string {
+	if len(IDs) == 0 {
+		return nil
+	}
+
+	strs := make([]string, 0, len(IDs))
+	for _, ID := range IDs {
+		strs = append(strs, string(ID))
+	}
+
+	return strs
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	query := "sum(rate(foo[5m] offset 1m))"
+	parser, err := promql.Parse(query, time.Minute,
+		models.NewTagOptions(), promql.NewParseOptions())
+	require.NoError(t, err)
+
+	attrs := []storagemetadata.Attributes{
+		{
+			MetricsType: storagemetadata.AggregatedMetricsType,
+			Retention:   24 * time.Hour,
+			Resolution:  time.Minute,
+		},
+	}
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		QueryStorageMetadataAttributes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(attrs, nil)
+
+	var (
+		engine = newEngine(store, defaultLookbackDuration, instrument.NewOptions())
+		end    = xtime.ToUnixNano(time.Unix(3600, 0))
+		start  = end.Add(-10 * time.Minute)
+	)
+	explanation, err := engine.Explain(context.TODO(), parser,
+		storage.NewFetchOptions(), models.RequestParams{
+			Query: query,
+			Start: start,
+			End:   end,
+			Step:  time.Minute,
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, query, explanation.Query)
+	ops := make([]string, 0, len(explanation.LogicalPlan))
+	for _, step := range explanation.LogicalPlan {
+		ops = append(ops, step.Op)
+	}
+	assert.Equal(t, []string{"fetch", "offset", "rate", "sum"}, ops)
+
+	physical := explanation.PhysicalPlan
+	require.Len(t, physical.Steps, 4)
+	assert.Equal(t, physical.Steps[3].ID, physical.ResultStep)
+
+	// The start of the blocks is shifted by the range of the rate.
+	assert.Equal(t, start.Add(-5*time.Minute).ToTime(), physical.Bounds.Start)
+	assert.Equal(t, end.ToTime(), physical.Bounds.End)
+	assert.Equal(t, "1m0s", physical.Bounds.Step)
+	assert.Equal(t, 15, physical.Bounds.Steps)
+
+	require.Len(t, physical.Fetches, 1)
+	fetch := physical.Fetches[0]
+	assert.Equal(t, physical.Steps[0].ID, fetch.StepID)
+	assert.Equal(t, start.Add(-6*time.Minute).ToTime(), fetch.Start)
+	assert.Equal(t, end.Add(-1*time.Minute).ToTime(), fetch.End)
+	assert.Equal(t, "5m0s", fetch.Range)
+	assert.Equal(t, "1m0s", fetch.Offset)
+	assert.Equal(t, []string{attrs[0].String()}, fetch.Namespaces)
+	require.Len(t, fetch.Matchers, 1)
+}
@@ -52,6 +52,15 @@ type Engine interface {
 		params models.RequestParams,
 	) (block.Block, error)
 
+	// Explain compiles and plans the query without executing it, returning
+	// the plans the engine builds for it.
+	Explain(
+		ctx context.Context,
+		parser parser.Parser,
+		fetchOpts *storage.FetchOptions,
+		params models.RequestParams,
+	) (Explanation, error)
+
 	// Options returns the currently configured options.
 	Options() EngineOptions
 
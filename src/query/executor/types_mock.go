@@ -103,6 +103,21 @@ func (mr *MockEngineMockRecorder) ExecuteProm(arg0, arg1, arg2, arg3 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteProm", reflect.TypeOf((*MockEngine)(nil).ExecuteProm), arg0, arg1, arg2, arg3)
 }
 
+// Explain mocks base method.
+func (m *MockEngine) Explain(arg0 context.Context, arg1 parser.Parser, arg2 *storage.FetchOptions, arg3 models.RequestParams) (Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockEngineMockRecorder) Explain(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockEngine)(nil).Explain), arg0, arg1, arg2, arg3)
+}
+
 // Options mocks base method.
 func (m *MockEngine) Options() EngineOptions {
 	m.ctrl.T.Helper()
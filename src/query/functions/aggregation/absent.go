@@ -41,8 +41,21 @@ func NewAbsentOp() parser.Params {
 	return newAbsentOp()
 }
 
+// NewAbsentOpWithTags creates a new absent operation whose output series has
+// exactly the given tags, which by Prometheus convention are derived from the
+// equality matchers of the selector of the absent expression.
+func NewAbsentOpWithTags(tags []models.Tag) parser.Params {
+	return absentOp{
+		tags:     tags,
+		withTags: true,
+	}
+}
+
 // absentOp stores required properties for absent ops.
-type absentOp struct{}
+type absentOp struct {
+	tags     []models.Tag
+	withTags bool
+}
 
 // OpType for the operator.
 func (o absentOp) OpType() string {
@@ -72,7 +85,7 @@ func newAbsentOp() absentOp {
 // absentNode is different from base node as it uses no grouping and has
 // special handling for the 0-series case.
 type absentNode struct {
-	op         parser.Params
+	op         absentOp
 	controller *transform.Controller
 }
 
@@ -101,14 +114,19 @@ func (n *absentNode) ProcessBlock(
 		tagOpts     = meta.Tags.Opts
 	)
 
-	// If no series in the input, return a scalar block with value 1.
-	if len(seriesMetas) == 0 {
-		return block.NewScalar(1, meta), nil
-	}
+	if n.op.withTags {
+		meta.Tags = models.NewTags(len(n.op.tags), tagOpts).
+			AddTags(n.op.tags)
+	} else {
+		// If no series in the input, return a scalar block with value 1.
+		if len(seriesMetas) == 0 {
+			return block.NewScalar(1, meta), nil
+		}
 
-	// NB: pull any common tags out into the created series.
-	dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
-	meta.Tags = meta.Tags.Add(dupeTags).Normalize()
+		// NB: pull any common tags out into the created series.
+		dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
+		meta.Tags = meta.Tags.Add(dupeTags).Normalize()
+	}
 	emptySeriesMeta := []block.SeriesMeta{
 		block.SeriesMeta{
 			Tags: models.NewTags(0, tagOpts),
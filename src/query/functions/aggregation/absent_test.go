@@ -147,3 +147,35 @@ func TestAbsent(t *testing.T) {
 		})
 	}
 }
+
+func TestAbsentWithTags(t *testing.T) {
+	tags := []models.Tag{{Name: []byte("job"), Value: []byte("api")}}
+	expectedMeta := test.MustMakeMeta(testBound, "job", "api")
+
+	for _, tt := range absentTests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := test.NewBlockFromValuesWithMetaAndSeriesMeta(
+				tt.meta,
+				tt.seriesMetas,
+				tt.vals,
+			)
+
+			c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+			op, ok := NewAbsentOpWithTags(tags).(transform.Params)
+			require.True(t, ok)
+
+			node := op.Node(c, transform.Options{})
+			err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), block)
+			require.NoError(t, err)
+
+			// NB: the tags of the input series are never carried over.
+			if tt.expectedVals == nil {
+				require.Equal(t, 0, len(sink.Values))
+			} else {
+				require.Equal(t, 1, len(sink.Values))
+				compare.EqualsWithNans(t, tt.expectedVals, sink.Values[0])
+				assert.True(t, expectedMeta.Equals(sink.Meta))
+			}
+		})
+	}
+}
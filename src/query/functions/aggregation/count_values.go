@@ -211,7 +211,8 @@ func (n *countValuesNode) ProcessBlock(
 			// Add the metas of this bucketBlock right after the previous block
 			blockMetas[v+previousBucketBlockIndex] = block.SeriesMeta{
 				Name: []byte(n.op.opType),
-				Tags: metas[bucketIndex].Tags.Clone().AddTag(models.Tag{
+				// NB: the label replaces any grouping tag of the same name.
+				Tags: metas[bucketIndex].Tags.Clone().AddOrUpdateTag(models.Tag{
 					Name:  []byte(labelName),
 					Value: utils.FormatFloatToBytes(k),
 				}),
@@ -224,8 +225,12 @@ func (n *countValuesNode) ProcessBlock(
 		previousBucketBlockIndex += bucketBlock.columnLength
 	}
 
+	// NB: series of different buckets collide when the label replaces a
+	// grouping tag, merge these by summing their counts.
+	mergedIndices, mergedMetas := mergeSeriesMetas(blockMetas)
+
 	// Dedupe common metadatas
-	metaTags, flattenedMeta := utils.DedupeMetadata(blockMetas, meta.Tags.Opts)
+	metaTags, flattenedMeta := utils.DedupeMetadata(mergedMetas, meta.Tags.Opts)
 	meta.Tags = metaTags
 
 	builder, err := n.controller.BlockBuilder(queryCtx, meta, flattenedMeta)
@@ -237,21 +242,59 @@ func (n *countValuesNode) ProcessBlock(
 		return nil, err
 	}
 
+	valsToAdd := make([]float64, len(mergedMetas))
 	for columnIndex := 0; columnIndex < stepCount; columnIndex++ {
+		util.Memset(valsToAdd, math.NaN())
+		seriesIndex := 0
 		for _, bucketBlock := range intermediateBlock {
-			valsToAdd := padValuesWithNaNs(
+			bucketVals := padValuesWithNaNs(
 				bucketBlock.columns[columnIndex],
 				len(bucketBlock.indexMapping),
 			)
-			if err := builder.AppendValues(columnIndex, valsToAdd); err != nil {
-				return nil, err
+			for _, v := range bucketVals {
+				mergedIndex := mergedIndices[seriesIndex]
+				seriesIndex++
+				if math.IsNaN(v) {
+					continue
+				}
+				if math.IsNaN(valsToAdd[mergedIndex]) {
+					valsToAdd[mergedIndex] = v
+				} else {
+					valsToAdd[mergedIndex] += v
+				}
 			}
 		}
+
+		if err := builder.AppendValues(columnIndex, valsToAdd); err != nil {
+			return nil, err
+		}
 	}
 
 	return builder.Build(), nil
 }
 
+// mergeSeriesMetas merges series metas with the same tags, returning the
+// index of the merged series meta of each series meta.
+func mergeSeriesMetas(metas []block.SeriesMeta) ([]int, []block.SeriesMeta) {
+	var (
+		indices = make([]int, 0, len(metas))
+		merged  = make([]block.SeriesMeta, 0, len(metas))
+		seen    = make(map[string]int, len(metas))
+	)
+	for _, meta := range metas {
+		id := string(meta.Tags.ID())
+		idx, ok := seen[id]
+		if !ok {
+			idx = len(merged)
+			seen[id] = idx
+			merged = append(merged, meta)
+		}
+		indices = append(indices, idx)
+	}
+
+	return indices, merged
+}
+
 // pads vals with enough NaNs to match size
 func padValuesWithNaNs(vals bucketColumn, size int) bucketColumn {
 	numToPad := size - len(vals)
@@ -199,6 +199,25 @@ func TestCustomProcessCountValuesFunctionFilteringWithoutA(t *testing.T) {
 	compare.CompareValuesInOrder(t, sink.Metas, tagsToSeriesMeta(expectedTags), sink.Values, expected)
 }
 
+func TestProcessCountValuesFunctionOverwritesGroupingTag(t *testing.T) {
+	tagName := "b"
+	op, err := NewCountValuesOp(CountValuesType, NodeParams{
+		MatchingTags: [][]byte{[]byte("b")}, StringParameter: tagName,
+	})
+	require.NoError(t, err)
+	sink := processCountValuesOp(t, op, simpleMetas, simpleVals)
+
+	// NB: both groups collide once the label replaces the grouping tag.
+	expected := [][]float64{{2, 2, 4, 4, 4}}
+	expectedTags := []models.Tags{models.EmptyTags()}
+
+	require.Equal(t, len(expectedTags), len(expected))
+	assert.Equal(t, bounds, sink.Meta.Bounds)
+	ex := test.TagSliceToTags([]models.Tag{{Name: []byte(tagName), Value: []byte("0")}})
+	assert.Equal(t, ex.Tags, sink.Meta.Tags.Tags)
+	compare.CompareValuesInOrder(t, sink.Metas, tagsToSeriesMeta(expectedTags), sink.Values, expected)
+}
+
 func TestSimpleProcessCountValuesFunctionFilteringWithA(t *testing.T) {
 	tagName := "tag_name_0"
 	op, err := NewCountValuesOp(CountValuesType, NodeParams{
@@ -41,6 +41,8 @@ const (
 	StandardVarianceType = "var"
 	// CountType counts all non nan elements in a list of series.
 	CountType = "count"
+	// GroupType returns 1 if there are any non nan elements in a list of series.
+	GroupType = "group"
 )
 
 func absentFn(values []float64, bucket []int) float64 {
@@ -54,6 +56,16 @@ func absentFn(values []float64, bucket []int) float64 {
 	return 1
 }
 
+func groupFn(values []float64, bucket []int) float64 {
+	for _, idx := range bucket {
+		if !math.IsNaN(values[idx]) {
+			return 1
+		}
+	}
+
+	return math.NaN()
+}
+
 func sumAndCount(values []float64, bucket []int) (float64, float64) {
 	sum := 0.0
 	count := 0.0
@@ -54,19 +54,27 @@ const (
 
 	// QuantileType calculates the φ-quantile (0 ≤ φ ≤ 1) of the values in the specified interval.
 	QuantileType = "quantile_over_time"
+
+	// PresentType returns 1 if there are any values in the specified interval.
+	PresentType = "present_over_time"
+
+	// AbsentType returns 1 if there are no values in the specified interval for
+	// any series, it is evaluated as absent applied to present_over_time.
+	AbsentType = "absent_over_time"
 )
 
 type aggFunc func([]float64) float64
 
 var (
 	aggFuncs = map[string]aggFunc{
-		AvgType:    avgOverTime,
-		CountType:  countOverTime,
-		MinType:    minOverTime,
-		MaxType:    maxOverTime,
-		SumType:    sumOverTime,
-		StdDevType: stddevOverTime,
-		StdVarType: stdvarOverTime,
+		AvgType:     avgOverTime,
+		CountType:   countOverTime,
+		MinType:     minOverTime,
+		MaxType:     maxOverTime,
+		SumType:     sumOverTime,
+		StdDevType:  stddevOverTime,
+		StdVarType:  stdvarOverTime,
+		PresentType: presentOverTime,
 	}
 )
 
@@ -158,6 +166,16 @@ func countOverTime(values []float64) float64 {
 	return count
 }
 
+func presentOverTime(values []float64) float64 {
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			return 1
+		}
+	}
+
+	return math.NaN()
+}
+
 func minOverTime(values []float64) float64 {
 	var seenNotNaN bool
 	min := math.Inf(1)
@@ -45,6 +45,8 @@
 		NamespaceSchemaResetResponse
 		NamespaceReadyRequest
 		NamespaceReadyResponse
+		NamespaceTruncatePending
+		NamespaceTruncatePendingSet
 		PlacementInitRequest
 		PlacementGetResponse
 		PlacementAddRequest
@@ -1617,41 +1619,41 @@ func init() {
 }
 
 var fileDescriptorDatabase = []byte{
-	// 572 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xc5, 0x49, 0xd3, 0xe2, 0xa9, 0x5a, 0xca, 0x16, 0x2a, 0xab, 0x88, 0x10, 0x82, 0x10, 0xb9,
-	0x10, 0x4b, 0xcd, 0x89, 0x63, 0x43, 0x45, 0x7b, 0x80, 0xaa, 0x72, 0xb8, 0x5b, 0x6b, 0x7b, 0x70,
-	0x56, 0x64, 0xbd, 0xdb, 0xdd, 0xb5, 0xa0, 0xf9, 0x08, 0xc4, 0x95, 0x3f, 0xe2, 0xc8, 0x27, 0xa0,
-	0x70, 0xe3, 0x2b, 0xd0, 0x6e, 0x6c, 0x27, 0x40, 0x4e, 0xbd, 0x8d, 0xdf, 0xbc, 0x99, 0x9d, 0xf7,
-	0x66, 0x0c, 0xa7, 0x39, 0x33, 0xd3, 0x32, 0x19, 0xa6, 0x82, 0x87, 0x7c, 0x94, 0x25, 0x21, 0x1f,
-	0x85, 0x5a, 0xa5, 0xe1, 0x75, 0x89, 0xea, 0x26, 0xcc, 0xb1, 0x40, 0x45, 0x0d, 0x66, 0xa1, 0x54,
-	0xc2, 0x88, 0x90, 0x66, 0x9c, 0x15, 0x61, 0x46, 0x0d, 0x4d, 0xa8, 0xc6, 0xa1, 0x03, 0x49, 0xc7,
-	0xa1, 0xc7, 0xe3, 0x5b, 0x74, 0x2a, 0x28, 0x47, 0x2d, 0x69, 0x5a, 0xb5, 0xba, 0x55, 0x0f, 0x39,
-	0xa3, 0x29, 0x72, 0x2c, 0xcc, 0xb2, 0x47, 0xff, 0x77, 0x0b, 0x1e, 0x9e, 0x55, 0x13, 0xbe, 0x56,
-	0x48, 0x0d, 0x46, 0x78, 0x5d, 0xa2, 0x36, 0xe4, 0x39, 0xec, 0x37, 0x0f, 0xc6, 0x36, 0x0a, 0xbc,
-	0x9e, 0x37, 0xf0, 0xa3, 0xbd, 0x06, 0xbd, 0xa4, 0x1c, 0x09, 0x81, 0x2d, 0x73, 0x23, 0x31, 0x68,
-	0xb9, 0xa4, 0x8b, 0xc9, 0x63, 0x80, 0xa2, 0xe4, 0xb1, 0x9e, 0x52, 0x95, 0xe9, 0xa0, 0xdd, 0xf3,
-	0x06, 0x9d, 0xc8, 0x2f, 0x4a, 0x3e, 0x71, 0x00, 0x79, 0x09, 0x44, 0xa1, 0x9c, 0xb1, 0x94, 0x1a,
-	0x26, 0x8a, 0xf8, 0x03, 0x4d, 0x8d, 0x50, 0xc1, 0x96, 0xa3, 0xdd, 0x5f, 0xcb, 0xbc, 0x71, 0x09,
-	0x3b, 0x88, 0x42, 0x83, 0x85, 0x23, 0x1b, 0xc6, 0x31, 0xe8, 0x2c, 0x07, 0x69, 0xd0, 0xf7, 0x8c,
-	0x23, 0x09, 0x01, 0x92, 0x99, 0x48, 0x3f, 0xc6, 0x9a, 0xcd, 0x31, 0xd8, 0xee, 0x79, 0x83, 0xdd,
-	0x93, 0x83, 0xa1, 0x53, 0x3d, 0x1c, 0xdb, 0xc4, 0x84, 0xcd, 0x31, 0xf2, 0x93, 0x3a, 0x24, 0x4f,
-	0xa1, 0x33, 0x15, 0xda, 0xe8, 0x60, 0xa7, 0xd7, 0x1e, 0xec, 0x9e, 0xec, 0x56, 0xdc, 0x0b, 0xa1,
-	0x4d, 0xb4, 0xcc, 0x90, 0x77, 0xf0, 0x80, 0xe6, 0xb9, 0xc2, 0xdc, 0xfa, 0x18, 0x37, 0xc2, 0x83,
-	0xbb, 0xae, 0xfb, 0x71, 0x55, 0x71, 0xda, 0x50, 0x2e, 0x6b, 0x46, 0x74, 0x48, 0xff, 0x07, 0xfb,
-	0x12, 0x0e, 0x37, 0x70, 0xad, 0x85, 0x6b, 0xfe, 0xba, 0x98, 0x74, 0x01, 0x14, 0x6a, 0x31, 0x2b,
-	0xad, 0xbe, 0xca, 0xdc, 0x35, 0x64, 0x83, 0x29, 0xed, 0x0d, 0xa6, 0xf4, 0x39, 0xf8, 0x8d, 0x76,
-	0xb7, 0x2a, 0xb6, 0x7a, 0xc7, 0xc6, 0xe4, 0x2d, 0x3c, 0xc3, 0xcf, 0x12, 0x53, 0xab, 0x4f, 0xa3,
-	0x62, 0xa8, 0x63, 0x7b, 0xb0, 0x52, 0xb0, 0xc2, 0xe8, 0x58, 0xa2, 0x8a, 0xa7, 0xa2, 0x54, 0x6e,
-	0x80, 0x76, 0xf4, 0xa4, 0xa6, 0x4e, 0x1c, 0xf3, 0xac, 0x21, 0x5e, 0xa1, 0xba, 0x10, 0xa5, 0xea,
-	0x7f, 0xf3, 0x60, 0xcb, 0xfa, 0x47, 0xf6, 0xa1, 0xc5, 0xb2, 0xea, 0xa1, 0x16, 0xcb, 0x48, 0x00,
-	0x3b, 0x34, 0xcb, 0x14, 0x6a, 0x5d, 0x69, 0xa9, 0x3f, 0xed, 0x50, 0x52, 0x28, 0xe3, 0xc6, 0xdf,
-	0x8b, 0x5c, 0x4c, 0x5e, 0xc0, 0x3d, 0xa6, 0xc5, 0x6c, 0x79, 0x1e, 0xb9, 0x12, 0xa5, 0x74, 0xd7,
-	0xe1, 0x47, 0xfb, 0x0d, 0x7c, 0x6e, 0x51, 0x5b, 0x3c, 0x17, 0x45, 0x7d, 0x10, 0x2e, 0x26, 0x47,
-	0xb0, 0xfd, 0x09, 0x59, 0x3e, 0x35, 0xee, 0x06, 0xf6, 0xa2, 0xea, 0xab, 0xff, 0xc5, 0x83, 0xa3,
-	0x7f, 0x2f, 0x5d, 0x4b, 0x51, 0x68, 0x24, 0xaf, 0xc0, 0x5f, 0xed, 0xd6, 0x73, 0xbb, 0x7d, 0x54,
-	0xed, 0xb6, 0xd9, 0xd2, 0x39, 0x9a, 0x9a, 0x1f, 0xad, 0xd8, 0xb6, 0xb4, 0xf9, 0xa5, 0x9c, 0xb4,
-	0x55, 0xe9, 0x55, 0x8d, 0xff, 0x55, 0xda, 0xb0, 0xc7, 0x07, 0xdf, 0x17, 0x5d, 0xef, 0xc7, 0xa2,
-	0xeb, 0xfd, 0x5c, 0x74, 0xbd, 0xaf, 0xbf, 0xba, 0x77, 0x92, 0x6d, 0xf7, 0x4f, 0x8e, 0xfe, 0x04,
-	0x00, 0x00, 0xff, 0xff, 0xce, 0xeb, 0x60, 0x31, 0x67, 0x04, 0x00, 0x00,
+	// 571 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x10, 0xfe, 0x9d, 0x34, 0xed, 0xef, 0xa9, 0x5a, 0xca, 0x16, 0x2a, 0xab, 0x88, 0x10, 0x82, 0x10,
+	0xb9, 0x10, 0x4b, 0xcd, 0x89, 0x63, 0x43, 0x45, 0x7b, 0x80, 0xaa, 0x72, 0xb8, 0x5b, 0x6b, 0x7b,
+	0x70, 0x56, 0x64, 0xbd, 0xdb, 0xdd, 0xb5, 0xa0, 0x79, 0x08, 0xc4, 0x95, 0x37, 0xe2, 0xc8, 0x23,
+	0xa0, 0x70, 0xe3, 0x29, 0xd0, 0x6e, 0x6c, 0x27, 0x85, 0x9c, 0x7a, 0xfb, 0xfc, 0xcd, 0x37, 0xb3,
+	0x33, 0xdf, 0x8c, 0xe1, 0x34, 0x67, 0x66, 0x5a, 0x26, 0xc3, 0x54, 0xf0, 0x90, 0x8f, 0xb2, 0x24,
+	0xe4, 0xa3, 0x50, 0xab, 0x34, 0xbc, 0x2e, 0x51, 0xdd, 0x84, 0x39, 0x16, 0xa8, 0xa8, 0xc1, 0x2c,
+	0x94, 0x4a, 0x18, 0x11, 0xd2, 0x8c, 0xb3, 0x22, 0xcc, 0xa8, 0xa1, 0x09, 0xd5, 0x38, 0x74, 0x24,
+	0xe9, 0x38, 0xf6, 0x78, 0x7c, 0x87, 0x4a, 0x05, 0xe5, 0xa8, 0x25, 0x4d, 0xab, 0x52, 0x77, 0xaa,
+	0x21, 0x67, 0x34, 0x45, 0x8e, 0x85, 0x59, 0xd6, 0xe8, 0xff, 0x6e, 0xc1, 0xc3, 0xb3, 0xaa, 0xc3,
+	0xd7, 0x0a, 0xa9, 0xc1, 0x08, 0xaf, 0x4b, 0xd4, 0x86, 0x3c, 0x87, 0xfd, 0xe6, 0xc1, 0xd8, 0xa2,
+	0xc0, 0xeb, 0x79, 0x03, 0x3f, 0xda, 0x6b, 0xd8, 0x4b, 0xca, 0x91, 0x10, 0xd8, 0x32, 0x37, 0x12,
+	0x83, 0x96, 0x0b, 0x3a, 0x4c, 0x1e, 0x03, 0x14, 0x25, 0x8f, 0xf5, 0x94, 0xaa, 0x4c, 0x07, 0xed,
+	0x9e, 0x37, 0xe8, 0x44, 0x7e, 0x51, 0xf2, 0x89, 0x23, 0xc8, 0x4b, 0x20, 0x0a, 0xe5, 0x8c, 0xa5,
+	0xd4, 0x30, 0x51, 0xc4, 0x1f, 0x68, 0x6a, 0x84, 0x0a, 0xb6, 0x9c, 0xec, 0xfe, 0x5a, 0xe4, 0x8d,
+	0x0b, 0xd8, 0x46, 0x14, 0x1a, 0x2c, 0x9c, 0xd8, 0x30, 0x8e, 0x41, 0x67, 0xd9, 0x48, 0xc3, 0xbe,
+	0x67, 0x1c, 0x49, 0x08, 0x90, 0xcc, 0x44, 0xfa, 0x31, 0xd6, 0x6c, 0x8e, 0xc1, 0x76, 0xcf, 0x1b,
+	0xec, 0x9e, 0x1c, 0x0c, 0xdd, 0xd4, 0xc3, 0xb1, 0x0d, 0x4c, 0xd8, 0x1c, 0x23, 0x3f, 0xa9, 0x21,
+	0x79, 0x0a, 0x9d, 0xa9, 0xd0, 0x46, 0x07, 0x3b, 0xbd, 0xf6, 0x60, 0xf7, 0x64, 0xb7, 0xd2, 0x5e,
+	0x08, 0x6d, 0xa2, 0x65, 0x84, 0xbc, 0x83, 0x07, 0x34, 0xcf, 0x15, 0xe6, 0xd6, 0xc7, 0xb8, 0x19,
+	0x3c, 0xf8, 0xdf, 0x55, 0x3f, 0xae, 0x32, 0x4e, 0x1b, 0xc9, 0x65, 0xad, 0x88, 0x0e, 0xe9, 0xbf,
+	0x64, 0x5f, 0xc2, 0xe1, 0x06, 0xad, 0xb5, 0x70, 0xcd, 0x5f, 0x87, 0x49, 0x17, 0x40, 0xa1, 0x16,
+	0xb3, 0xd2, 0xce, 0x57, 0x99, 0xbb, 0xc6, 0x6c, 0x30, 0xa5, 0xbd, 0xc1, 0x94, 0x3e, 0x07, 0xbf,
+	0x99, 0xdd, 0xad, 0x8a, 0xad, 0xde, 0xb1, 0x98, 0xbc, 0x85, 0x67, 0xf8, 0x59, 0x62, 0x6a, 0xe7,
+	0xd3, 0xa8, 0x18, 0xea, 0xd8, 0x1e, 0xac, 0x14, 0xac, 0x30, 0x3a, 0x96, 0xa8, 0xe2, 0xa9, 0x28,
+	0x95, 0x6b, 0xa0, 0x1d, 0x3d, 0xa9, 0xa5, 0x13, 0xa7, 0x3c, 0x6b, 0x84, 0x57, 0xa8, 0x2e, 0x44,
+	0xa9, 0xfa, 0xdf, 0x3c, 0xd8, 0xb2, 0xfe, 0x91, 0x7d, 0x68, 0xb1, 0xac, 0x7a, 0xa8, 0xc5, 0x32,
+	0x12, 0xc0, 0x0e, 0xcd, 0x32, 0x85, 0x5a, 0x57, 0xb3, 0xd4, 0x9f, 0xb6, 0x29, 0x29, 0x94, 0x71,
+	0xed, 0xef, 0x45, 0x0e, 0x93, 0x17, 0x70, 0x8f, 0x69, 0x31, 0x5b, 0x9e, 0x47, 0xae, 0x44, 0x29,
+	0xdd, 0x75, 0xf8, 0xd1, 0x7e, 0x43, 0x9f, 0x5b, 0xd6, 0x26, 0xcf, 0x45, 0x51, 0x1f, 0x84, 0xc3,
+	0xe4, 0x08, 0xb6, 0x3f, 0x21, 0xcb, 0xa7, 0xc6, 0xdd, 0xc0, 0x5e, 0x54, 0x7d, 0xf5, 0xbf, 0x78,
+	0x70, 0xf4, 0xf7, 0xa5, 0x6b, 0x29, 0x0a, 0x8d, 0xe4, 0x15, 0xf8, 0xab, 0xdd, 0x7a, 0x6e, 0xb7,
+	0x8f, 0xaa, 0xdd, 0x36, 0x5b, 0x3a, 0x47, 0x53, 0xeb, 0xa3, 0x95, 0xda, 0xa6, 0x36, 0xbf, 0x54,
+	0xd0, 0xba, 0x95, 0x7a, 0x55, 0xf3, 0xb7, 0x52, 0x1b, 0xf5, 0xf8, 0xe0, 0xfb, 0xa2, 0xeb, 0xfd,
+	0x58, 0x74, 0xbd, 0x9f, 0x8b, 0xae, 0xf7, 0xf5, 0x57, 0xf7, 0xbf, 0x64, 0xdb, 0xfd, 0x93, 0xa3,
+	0x3f, 0x03, 0x00, 0xce, 0xeb, 0x60, 0x31, 0x67, 0x04, 0x00, 0x00,
 }
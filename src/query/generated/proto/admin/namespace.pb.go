@@ -250,6 +250,65 @@ func (m *NamespaceReadyResponse) GetReady() bool {
 	return false
 }
 
+// NamespaceTruncatePending is a namespace truncation that has been requested
+// but not confirmed yet.
+type NamespaceTruncatePending struct {
+	// Name is the namespace name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Topology is the set of hosts that were confirmed to be truncated.
+	Topology string `protobuf:"bytes,2,opt,name=topology,proto3" json:"topology,omitempty"`
+	// ExpiresAtNanos is when the confirmation token expires.
+	ExpiresAtNanos int64 `protobuf:"varint,3,opt,name=expiresAtNanos,proto3" json:"expiresAtNanos,omitempty"`
+}
+
+func (m *NamespaceTruncatePending) Reset()         { *m = NamespaceTruncatePending{} }
+func (m *NamespaceTruncatePending) String() string { return proto.CompactTextString(m) }
+func (*NamespaceTruncatePending) ProtoMessage()    {}
+func (*NamespaceTruncatePending) Descriptor() ([]byte, []int) {
+	return fileDescriptorNamespace, []int{9}
+}
+
+func (m *NamespaceTruncatePending) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NamespaceTruncatePending) GetTopology() string {
+	if m != nil {
+		return m.Topology
+	}
+	return ""
+}
+
+func (m *NamespaceTruncatePending) GetExpiresAtNanos() int64 {
+	if m != nil {
+		return m.ExpiresAtNanos
+	}
+	return 0
+}
+
+// NamespaceTruncatePendingSet is the set of pending namespace truncations
+// keyed by confirmation token.
+type NamespaceTruncatePendingSet struct {
+	Pending map[string]*NamespaceTruncatePending `protobuf:"bytes,1,rep,name=pending" json:"pending,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *NamespaceTruncatePendingSet) Reset()         { *m = NamespaceTruncatePendingSet{} }
+func (m *NamespaceTruncatePendingSet) String() string { return proto.CompactTextString(m) }
+func (*NamespaceTruncatePendingSet) ProtoMessage()    {}
+func (*NamespaceTruncatePendingSet) Descriptor() ([]byte, []int) {
+	return fileDescriptorNamespace, []int{10}
+}
+
+func (m *NamespaceTruncatePendingSet) GetPending() map[string]*NamespaceTruncatePending {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*NamespaceGetResponse)(nil), "admin.NamespaceGetResponse")
 	proto.RegisterType((*NamespaceAddRequest)(nil), "admin.NamespaceAddRequest")
@@ -260,6 +319,8 @@ func init() {
 	proto.RegisterType((*NamespaceSchemaResetResponse)(nil), "admin.NamespaceSchemaResetResponse")
 	proto.RegisterType((*NamespaceReadyRequest)(nil), "admin.NamespaceReadyRequest")
 	proto.RegisterType((*NamespaceReadyResponse)(nil), "admin.NamespaceReadyResponse")
+	proto.RegisterType((*NamespaceTruncatePending)(nil), "admin.NamespaceTruncatePending")
+	proto.RegisterType((*NamespaceTruncatePendingSet)(nil), "admin.NamespaceTruncatePendingSet")
 }
 func (m *NamespaceGetResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -538,6 +599,87 @@ func (m *NamespaceReadyResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *NamespaceTruncatePending) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamespaceTruncatePending) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Topology) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Topology)))
+		i += copy(dAtA[i:], m.Topology)
+	}
+	if m.ExpiresAtNanos != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ExpiresAtNanos))
+	}
+	return i, nil
+}
+
+func (m *NamespaceTruncatePendingSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamespaceTruncatePendingSet) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Pending) > 0 {
+		for k, _ := range m.Pending {
+			dAtA[i] = 0xa
+			i++
+			v := m.Pending[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovNamespace(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovNamespace(uint64(len(k))) + msgSize
+			i = encodeVarintNamespace(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintNamespace(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintNamespace(dAtA, i, uint64(v.Size()))
+				n4, err := v.MarshalTo(dAtA[i:])
+				if err != nil {
+					return 0, err
+				}
+				i += n4
+			}
+		}
+	}
+	return i, nil
+}
+
 func encodeVarintNamespace(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -659,6 +801,42 @@ func (m *NamespaceReadyResponse) Size() (n int) {
 	return n
 }
 
+func (m *NamespaceTruncatePending) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	l = len(m.Topology)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.ExpiresAtNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.ExpiresAtNanos))
+	}
+	return n
+}
+
+func (m *NamespaceTruncatePendingSet) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Pending) > 0 {
+		for k, v := range m.Pending {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovNamespace(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovNamespace(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovNamespace(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
 func sovNamespace(x uint64) (n int) {
 	for {
 		n++
@@ -1611,6 +1789,306 @@ func (m *NamespaceReadyResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *NamespaceTruncatePending) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceTruncatePending: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceTruncatePending: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Topology", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Topology = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresAtNanos", wireType)
+			}
+			m.ExpiresAtNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpiresAtNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NamespaceTruncatePendingSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceTruncatePendingSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceTruncatePendingSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pending", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pending == nil {
+				m.Pending = make(map[string]*NamespaceTruncatePending)
+			}
+			var mapkey string
+			var mapvalue *NamespaceTruncatePending
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowNamespace
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowNamespace
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthNamespace
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowNamespace
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= (int(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthNamespace
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if mapmsglen < 0 {
+						return ErrInvalidLengthNamespace
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &NamespaceTruncatePending{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipNamespace(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthNamespace
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Pending[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipNamespace(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1721,33 +2199,39 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 433 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x92, 0xdf, 0x6e, 0xd3, 0x30,
-	0x14, 0xc6, 0xc9, 0xba, 0xb2, 0xf4, 0x4c, 0x48, 0x93, 0x57, 0x50, 0xe9, 0xa6, 0x68, 0xf2, 0xd5,
-	0xae, 0x6c, 0xb1, 0x0a, 0x69, 0x82, 0xab, 0x4d, 0xa0, 0x0a, 0x24, 0xfe, 0xc8, 0x88, 0x7b, 0xdc,
-	0xf8, 0xd0, 0x45, 0x2c, 0x71, 0x66, 0x3b, 0x48, 0x79, 0x0b, 0x1e, 0x8b, 0x4b, 0x1e, 0x01, 0x95,
-	0x17, 0xe0, 0x11, 0x50, 0x9c, 0xc4, 0x1b, 0xa3, 0x85, 0x2b, 0xee, 0xf2, 0x9d, 0xcf, 0xdf, 0xef,
-	0xd8, 0x27, 0x07, 0xce, 0x97, 0x99, 0xbb, 0xa8, 0x16, 0x2c, 0xd5, 0x39, 0xcf, 0x67, 0x6a, 0xc1,
-	0xf3, 0x19, 0xb7, 0x26, 0xe5, 0x57, 0x15, 0x9a, 0x9a, 0x2f, 0xb1, 0x40, 0x23, 0x1d, 0x2a, 0x5e,
-	0x1a, 0xed, 0x34, 0x97, 0x2a, 0xcf, 0x0a, 0x5e, 0xc8, 0x1c, 0x6d, 0x29, 0x53, 0x64, 0xbe, 0x4a,
-	0x86, 0xbe, 0x3c, 0x9d, 0x6f, 0x40, 0xa9, 0x45, 0xa1, 0x15, 0xfe, 0xc1, 0x0a, 0x94, 0xdb, 0x3c,
-	0x3a, 0x87, 0xf1, 0xeb, 0xbe, 0x34, 0x47, 0x27, 0xd0, 0x96, 0xba, 0xb0, 0x48, 0x38, 0xc4, 0x06,
-	0x97, 0x99, 0x75, 0xa6, 0x9e, 0x44, 0x47, 0xd1, 0xf1, 0xee, 0xc9, 0x3e, 0xbb, 0xce, 0x8a, 0xce,
-	0x12, 0xe1, 0x10, 0xfd, 0x00, 0xfb, 0x01, 0x74, 0xa6, 0x94, 0xc0, 0xab, 0x0a, 0xad, 0x23, 0x04,
-	0xb6, 0x9b, 0x98, 0x67, 0x8c, 0x84, 0xff, 0x26, 0x8f, 0x61, 0x47, 0x97, 0x2e, 0xd3, 0x85, 0x9d,
-	0x6c, 0x79, 0xf4, 0xc1, 0x0d, 0x74, 0x80, 0xbc, 0x69, 0x8f, 0x88, 0xfe, 0x2c, 0x4d, 0xe1, 0x41,
-	0x30, 0xdf, 0x97, 0x4a, 0x3a, 0xfc, 0x0f, 0x4d, 0x7e, 0x46, 0xf0, 0x30, 0xb8, 0xef, 0xd2, 0x0b,
-	0xcc, 0xe5, 0x3f, 0x5e, 0x33, 0x81, 0x9d, 0xdc, 0x2e, 0x9b, 0x8c, 0x6f, 0x34, 0x12, 0xbd, 0x24,
-	0x87, 0x30, 0xf2, 0x43, 0xf6, 0xde, 0xc0, 0x7b, 0xd7, 0x05, 0xf2, 0x12, 0x62, 0x2f, 0x5e, 0xc9,
-	0x72, 0xb2, 0x7d, 0x34, 0x38, 0xde, 0x3d, 0x61, 0xcc, 0xff, 0x5c, 0xb6, 0xb1, 0x3f, 0x7b, 0xdb,
-	0x05, 0x9e, 0x17, 0x7e, 0xf8, 0x7d, 0x7e, 0xfa, 0x14, 0xee, 0xfd, 0x66, 0x91, 0x3d, 0x18, 0x7c,
-	0xc2, 0xba, 0xbb, 0x67, 0xf3, 0x49, 0xc6, 0x30, 0xfc, 0x2c, 0x2f, 0xab, 0xfe, 0x92, 0xad, 0x78,
-	0xb2, 0x75, 0x1a, 0xd1, 0x53, 0x98, 0xae, 0xeb, 0xd8, 0x2d, 0xc2, 0x14, 0x62, 0x85, 0xe5, 0xa5,
-	0xae, 0x5f, 0x3c, 0xeb, 0x70, 0x41, 0xd3, 0x47, 0x70, 0x70, 0x2b, 0x29, 0xd0, 0x36, 0x4b, 0xb4,
-	0x71, 0x5a, 0x34, 0x81, 0xc3, 0xf5, 0x91, 0xb6, 0x1d, 0x3d, 0x83, 0xfb, 0xc1, 0x17, 0x28, 0x55,
-	0xfd, 0xb7, 0xd1, 0x8f, 0x61, 0xf8, 0x51, 0x9b, 0xb4, 0x7d, 0x53, 0x2c, 0x5a, 0x41, 0xd9, 0x8d,
-	0x3d, 0xe9, 0x10, 0xdd, 0x5b, 0xc6, 0x30, 0x34, 0x4d, 0xc1, 0x43, 0x62, 0xd1, 0x8a, 0xf3, 0xbd,
-	0xaf, 0xab, 0x24, 0xfa, 0xb6, 0x4a, 0xa2, 0xef, 0xab, 0x24, 0xfa, 0xf2, 0x23, 0xb9, 0xb3, 0xb8,
-	0xeb, 0x07, 0x3b, 0xfb, 0x15, 0x00, 0x00, 0xff, 0xff, 0xcd, 0xb9, 0x78, 0x5f, 0xb1, 0x03, 0x00,
+	// 529 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x52, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0x4d, 0x43, 0x9c, 0x09, 0xa0, 0xca, 0x0d, 0x95, 0x71, 0xaa, 0x50, 0xf9, 0x80, 0x7a,
+	0xb2, 0x45, 0xa2, 0x4a, 0x15, 0x9c, 0x52, 0x81, 0xa2, 0x22, 0x51, 0xaa, 0x2d, 0x9c, 0xb8, 0xb0,
+	0xf1, 0x0e, 0xae, 0x45, 0xbc, 0xeb, 0xee, 0x6e, 0x10, 0x7e, 0x0b, 0x5e, 0x0a, 0x89, 0x23, 0x8f,
+	0x80, 0xc2, 0x0b, 0xf0, 0x08, 0x28, 0xeb, 0x9f, 0x86, 0x34, 0xa1, 0x27, 0x6e, 0x3b, 0xf3, 0xcd,
+	0xf7, 0x7d, 0xbb, 0xb3, 0x1f, 0x9c, 0xc4, 0x89, 0xbe, 0x9c, 0x4d, 0x82, 0x48, 0xa4, 0x61, 0x3a,
+	0x64, 0x93, 0x30, 0x1d, 0x86, 0x4a, 0x46, 0xe1, 0xd5, 0x0c, 0x65, 0x1e, 0xc6, 0xc8, 0x51, 0x52,
+	0x8d, 0x2c, 0xcc, 0xa4, 0xd0, 0x22, 0xa4, 0x2c, 0x4d, 0x78, 0xc8, 0x69, 0x8a, 0x2a, 0xa3, 0x11,
+	0x06, 0xa6, 0xeb, 0x34, 0x4d, 0xdb, 0x1b, 0x6f, 0x90, 0x62, 0x13, 0x2e, 0x18, 0xde, 0xd0, 0xaa,
+	0x55, 0x56, 0xf5, 0xfc, 0x31, 0x74, 0xcf, 0xaa, 0xd6, 0x18, 0x35, 0x41, 0x95, 0x09, 0xae, 0xd0,
+	0x09, 0xc1, 0x96, 0x18, 0x27, 0x4a, 0xcb, 0xdc, 0xb5, 0x0e, 0xac, 0xc3, 0xce, 0x60, 0x37, 0xb8,
+	0xe6, 0x92, 0x12, 0x22, 0xf5, 0x90, 0xff, 0x01, 0x76, 0x6b, 0xa1, 0x11, 0x63, 0x04, 0xaf, 0x66,
+	0xa8, 0xb4, 0xe3, 0xc0, 0xf6, 0x82, 0x66, 0x34, 0xda, 0xc4, 0x9c, 0x9d, 0x23, 0x68, 0x89, 0x4c,
+	0x27, 0x82, 0x2b, 0x77, 0xcb, 0x48, 0xf7, 0x96, 0xa4, 0x6b, 0x91, 0x37, 0xc5, 0x08, 0xa9, 0x66,
+	0xfd, 0x08, 0xf6, 0x6a, 0xf0, 0x5d, 0xc6, 0xa8, 0xc6, 0xff, 0x60, 0xf2, 0xdb, 0x82, 0x47, 0x35,
+	0x7a, 0x11, 0x5d, 0x62, 0x4a, 0x6f, 0x79, 0x8d, 0x0b, 0xad, 0x54, 0xc5, 0x0b, 0x8e, 0x31, 0x6a,
+	0x93, 0xaa, 0x74, 0xf6, 0xa1, 0x6d, 0x96, 0x6c, 0xb0, 0x86, 0xc1, 0xae, 0x1b, 0xce, 0x2b, 0xb0,
+	0x4d, 0xf1, 0x9a, 0x66, 0xee, 0xf6, 0x41, 0xe3, 0xb0, 0x33, 0x08, 0x02, 0xf3, 0xb9, 0xc1, 0x46,
+	0xff, 0xe0, 0xbc, 0x24, 0xbc, 0xe4, 0x66, 0xf9, 0x15, 0xdf, 0x7b, 0x0e, 0xf7, 0xff, 0x82, 0x9c,
+	0x1d, 0x68, 0x7c, 0xc2, 0xbc, 0xbc, 0xe7, 0xe2, 0xe8, 0x74, 0xa1, 0xf9, 0x99, 0x4e, 0x67, 0xd5,
+	0x25, 0x8b, 0xe2, 0xd9, 0xd6, 0xb1, 0xe5, 0x1f, 0x83, 0xb7, 0xce, 0xb1, 0x0c, 0x82, 0x07, 0x36,
+	0xc3, 0x6c, 0x2a, 0xf2, 0xd3, 0x17, 0xa5, 0x5c, 0x5d, 0xfb, 0x4f, 0xa1, 0xb7, 0xc2, 0x24, 0xa8,
+	0x50, 0x97, 0xb7, 0x5d, 0xb7, 0x2d, 0xbf, 0x0f, 0xfb, 0xeb, 0x29, 0x85, 0x9d, 0x3f, 0x82, 0x87,
+	0x35, 0x4e, 0x90, 0xb2, 0xfc, 0x5f, 0xab, 0xef, 0x42, 0xf3, 0xa3, 0x90, 0x51, 0xf1, 0x26, 0x9b,
+	0x14, 0x85, 0x1f, 0xc0, 0xde, 0xaa, 0x44, 0xf9, 0x96, 0x2e, 0x34, 0xe5, 0xa2, 0x61, 0x44, 0x6c,
+	0x52, 0x14, 0xbe, 0x04, 0xb7, 0x9e, 0x7f, 0x2b, 0x67, 0x3c, 0xa2, 0x1a, 0xcf, 0x91, 0xb3, 0x84,
+	0xc7, 0x6b, 0x5d, 0x3d, 0xb0, 0xb5, 0xc8, 0xc4, 0x54, 0xc4, 0x79, 0xb9, 0xcc, 0xba, 0x76, 0x9e,
+	0xc0, 0x03, 0xfc, 0x92, 0x25, 0x12, 0xd5, 0x48, 0x9f, 0x51, 0x2e, 0x94, 0xf9, 0xf7, 0x06, 0x59,
+	0xe9, 0xfa, 0xdf, 0x2c, 0xe8, 0x6d, 0x32, 0xbd, 0x40, 0xed, 0x9c, 0x42, 0x2b, 0x2b, 0x2a, 0xd7,
+	0x32, 0xd9, 0x08, 0x57, 0xb3, 0x71, 0x93, 0x14, 0x94, 0xc7, 0x22, 0x1c, 0x15, 0xdf, 0x7b, 0x0f,
+	0xf7, 0x96, 0x81, 0x35, 0xd1, 0x38, 0x5a, 0x8e, 0x46, 0x67, 0xf0, 0xf8, 0x16, 0xab, 0xa5, 0xec,
+	0x9c, 0xec, 0x7c, 0x9f, 0xf7, 0xad, 0x1f, 0xf3, 0xbe, 0xf5, 0x73, 0xde, 0xb7, 0xbe, 0xfe, 0xea,
+	0xdf, 0x99, 0xdc, 0x35, 0xa1, 0x1c, 0xfe, 0x19, 0x00, 0x6c, 0x8d, 0x2e, 0xf4, 0xed, 0x04, 0x00,
 	0x00,
 }
@@ -146,7 +146,7 @@ var LabelMatcher_Type_value = map[string]int32{
 func (x LabelMatcher_Type) String() string {
 	return proto.EnumName(LabelMatcher_Type_name, int32(x))
 }
-func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4, 0} }
+func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorTypes, []int{5, 0} }
 
 type Sample struct {
 	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -182,6 +182,9 @@ type TimeSeries struct {
 	// should never clash with prometheus fields.
 	M3Type M3Type `protobuf:"varint,101,opt,name=m3_type,json=m3Type,proto3,enum=m3prometheus.M3Type" json:"m3_type,omitempty"`
 	Source Source `protobuf:"varint,102,opt,name=source,proto3,enum=m3prometheus.Source" json:"source,omitempty"`
+	// Exemplars are not read from field 3 as Prometheus sends them since that
+	// field is the metric type above.
+	Exemplars []Exemplar `protobuf:"bytes,103,rep,name=exemplars" json:"exemplars"`
 }
 
 func (m *TimeSeries) Reset()                    { *m = TimeSeries{} }
@@ -238,6 +241,47 @@ func (m *TimeSeries) GetSource() Source {
 	return Source_PROMETHEUS
 }
 
+func (m *TimeSeries) GetExemplars() []Exemplar {
+	if m != nil {
+		return m.Exemplars
+	}
+	return nil
+}
+
+// Exemplar is a sample annotated with the trace it was recorded in, as sent
+// by Prometheus in remote write requests.
+type Exemplar struct {
+	Labels    []Label `protobuf:"bytes,1,rep,name=labels" json:"labels"`
+	Value     float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64   `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Exemplar) Reset()                    { *m = Exemplar{} }
+func (m *Exemplar) String() string            { return proto.CompactTextString(m) }
+func (*Exemplar) ProtoMessage()               {}
+func (*Exemplar) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2} }
+
+func (m *Exemplar) GetLabels() []Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Exemplar) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Exemplar) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 type Label struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -246,7 +290,7 @@ type Label struct {
 func (m *Label) Reset()                    { *m = Label{} }
 func (m *Label) String() string            { return proto.CompactTextString(m) }
 func (*Label) ProtoMessage()               {}
-func (*Label) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{2} }
+func (*Label) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{3} }
 
 func (m *Label) GetName() []byte {
 	if m != nil {
@@ -269,7 +313,7 @@ type Labels struct {
 func (m *Labels) Reset()                    { *m = Labels{} }
 func (m *Labels) String() string            { return proto.CompactTextString(m) }
 func (*Labels) ProtoMessage()               {}
-func (*Labels) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{3} }
+func (*Labels) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4} }
 
 func (m *Labels) GetLabels() []Label {
 	if m != nil {
@@ -288,7 +332,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()                    { *m = LabelMatcher{} }
 func (m *LabelMatcher) String() string            { return proto.CompactTextString(m) }
 func (*LabelMatcher) ProtoMessage()               {}
-func (*LabelMatcher) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{4} }
+func (*LabelMatcher) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{5} }
 
 func (m *LabelMatcher) GetType() LabelMatcher_Type {
 	if m != nil {
@@ -311,12 +355,56 @@ func (m *LabelMatcher) GetValue() []byte {
 	return nil
 }
 
+// MetricMetadata is the metadata of a metric family, wire compatible with the
+// metadata sent by Prometheus in remote write requests.
+type MetricMetadata struct {
+	Type             MetricType `protobuf:"varint,1,opt,name=type,proto3,enum=m3prometheus.MetricType" json:"type,omitempty"`
+	MetricFamilyName string     `protobuf:"bytes,2,opt,name=metric_family_name,json=metricFamilyName,proto3" json:"metric_family_name,omitempty"`
+	Help             string     `protobuf:"bytes,4,opt,name=help,proto3" json:"help,omitempty"`
+	Unit             string     `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (m *MetricMetadata) Reset()                    { *m = MetricMetadata{} }
+func (m *MetricMetadata) String() string            { return proto.CompactTextString(m) }
+func (*MetricMetadata) ProtoMessage()               {}
+func (*MetricMetadata) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{6} }
+
+func (m *MetricMetadata) GetType() MetricType {
+	if m != nil {
+		return m.Type
+	}
+	return MetricType_UNKNOWN
+}
+
+func (m *MetricMetadata) GetMetricFamilyName() string {
+	if m != nil {
+		return m.MetricFamilyName
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetHelp() string {
+	if m != nil {
+		return m.Help
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Sample)(nil), "m3prometheus.Sample")
 	proto.RegisterType((*TimeSeries)(nil), "m3prometheus.TimeSeries")
+	proto.RegisterType((*Exemplar)(nil), "m3prometheus.Exemplar")
 	proto.RegisterType((*Label)(nil), "m3prometheus.Label")
 	proto.RegisterType((*Labels)(nil), "m3prometheus.Labels")
 	proto.RegisterType((*LabelMatcher)(nil), "m3prometheus.LabelMatcher")
+	proto.RegisterType((*MetricMetadata)(nil), "m3prometheus.MetricMetadata")
 	proto.RegisterEnum("m3prometheus.MetricType", MetricType_name, MetricType_value)
 	proto.RegisterEnum("m3prometheus.M3Type", M3Type_name, M3Type_value)
 	proto.RegisterEnum("m3prometheus.Source", Source_name, Source_value)
@@ -421,6 +509,61 @@ func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintTypes(dAtA, i, uint64(m.Source))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, msg := range m.Exemplars {
+			dAtA[i] = 0xba
+			i++
+			dAtA[i] = 0x6
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Exemplar) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Exemplar) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, msg := range m.Labels {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Value != 0 {
+		dAtA[i] = 0x11
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Timestamp))
+	}
 	return i, nil
 }
 
@@ -519,6 +662,47 @@ func (m *LabelMatcher) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *MetricMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Type != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+	}
+	if len(m.MetricFamilyName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.MetricFamilyName)))
+		i += copy(dAtA[i:], m.MetricFamilyName)
+	}
+	if len(m.Help) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Help)))
+		i += copy(dAtA[i:], m.Help)
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	return i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -572,6 +756,30 @@ func (m *TimeSeries) Size() (n int) {
 	if m.Source != 0 {
 		n += 2 + sovTypes(uint64(m.Source))
 	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 2 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Exemplar) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
 	return n
 }
 
@@ -618,6 +826,27 @@ func (m *LabelMatcher) Size() (n int) {
 	return n
 }
 
+func (m *MetricMetadata) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = len(m.MetricFamilyName)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Help)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	for {
 		n++
@@ -917,6 +1146,148 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 103:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Exemplar) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Exemplar: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Exemplar: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, Label{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1262,6 +1633,162 @@ func (m *LabelMatcher) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *MetricMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricFamilyName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricFamilyName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Help", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Help = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1372,42 +1899,48 @@ func init() {
 }
 
 var fileDescriptorTypes = []byte{
-	// 590 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0xcd, 0xd8, 0x8e, 0xd3, 0xdc, 0x86, 0x32, 0x9a, 0x76, 0x61, 0x21, 0x94, 0x46, 0x59, 0xa0,
-	0xa8, 0x6a, 0x63, 0xb5, 0xee, 0x0e, 0x24, 0x94, 0x22, 0x93, 0x46, 0xd4, 0x4e, 0x3b, 0x76, 0x84,
-	0x60, 0x13, 0xd9, 0xe9, 0x34, 0xb1, 0x94, 0xa9, 0x8d, 0x1f, 0x48, 0xe5, 0x2b, 0xd8, 0xf1, 0x0b,
-	0x7c, 0x4a, 0x97, 0x7c, 0x01, 0x42, 0xe5, 0x47, 0xd0, 0xcc, 0x04, 0xa5, 0x45, 0xdd, 0xb0, 0x49,
-	0xe6, 0x9e, 0x7b, 0xce, 0xbd, 0xc7, 0x67, 0x34, 0xf0, 0x7a, 0x9e, 0x94, 0x8b, 0x2a, 0xee, 0xcf,
-	0x52, 0x6e, 0x73, 0xe7, 0x32, 0xb6, 0xb9, 0x63, 0x17, 0xf9, 0xcc, 0xfe, 0x54, 0xb1, 0xfc, 0xc6,
-	0x9e, 0xb3, 0x6b, 0x96, 0x47, 0x25, 0xbb, 0xb4, 0xb3, 0x3c, 0x2d, 0x53, 0xf1, 0xcb, 0xb3, 0xd8,
-	0x2e, 0x6f, 0x32, 0x56, 0xf4, 0x25, 0x44, 0x5a, 0xdc, 0x11, 0x28, 0x2b, 0x17, 0xac, 0x2a, 0x9e,
-	0x1d, 0xdc, 0x1b, 0x37, 0x4f, 0xe7, 0xa9, 0xd2, 0xc5, 0xd5, 0x95, 0xac, 0xd4, 0x10, 0x71, 0x52,
-	0xe2, 0xee, 0x2b, 0x30, 0x83, 0x88, 0x67, 0x4b, 0x46, 0x76, 0xa0, 0xfe, 0x39, 0x5a, 0x56, 0xcc,
-	0x42, 0x1d, 0xd4, 0x43, 0x54, 0x15, 0xe4, 0x39, 0x34, 0xcb, 0x84, 0xb3, 0xa2, 0x8c, 0x78, 0x66,
-	0x69, 0x1d, 0xd4, 0xd3, 0xe9, 0x1a, 0xe8, 0x7e, 0xd7, 0x00, 0xc2, 0x84, 0xb3, 0x80, 0xe5, 0x09,
-	0x2b, 0xc8, 0x21, 0x98, 0xcb, 0x28, 0x66, 0xcb, 0xc2, 0x42, 0x1d, 0xbd, 0xb7, 0x79, 0xb4, 0xdd,
-	0xbf, 0x6f, 0xad, 0x7f, 0x26, 0x7a, 0x27, 0xc6, 0xed, 0xcf, 0xdd, 0x1a, 0x5d, 0x11, 0xc9, 0x31,
-	0x34, 0x0a, 0xb9, 0xbf, 0xb0, 0x34, 0xa9, 0xd9, 0x79, 0xa8, 0x51, 0xe6, 0x56, 0xa2, 0xbf, 0x54,
-	0xb2, 0x0f, 0x86, 0x48, 0xc0, 0xd2, 0x3b, 0xa8, 0xb7, 0x75, 0x64, 0x3d, 0x94, 0x78, 0xac, 0xcc,
-	0x93, 0x59, 0x78, 0x93, 0x31, 0x2a, 0x59, 0x84, 0x80, 0x51, 0x5d, 0x27, 0xa5, 0x65, 0x74, 0x50,
-	0xaf, 0x49, 0xe5, 0x59, 0x60, 0x0b, 0xb6, 0xcc, 0xac, 0xba, 0xc2, 0xc4, 0x99, 0x1c, 0x40, 0x83,
-	0x3b, 0x53, 0x39, 0x98, 0xc9, 0xc1, 0xff, 0x78, 0xf1, 0x1c, 0x39, 0xd4, 0xe4, 0xf2, 0x9f, 0xec,
-	0x83, 0x59, 0xa4, 0x55, 0x3e, 0x63, 0xd6, 0xd5, 0x63, 0xec, 0x40, 0xf6, 0xe8, 0x8a, 0xd3, 0x3d,
-	0x84, 0xba, 0xfc, 0x7e, 0xb1, 0xf9, 0x3a, 0xe2, 0x2a, 0xe6, 0x16, 0x95, 0xe7, 0x75, 0xf6, 0x9a,
-	0x04, 0x55, 0xd1, 0x7d, 0x09, 0xe6, 0x99, 0x4a, 0xe9, 0xff, 0x83, 0xed, 0x7e, 0x43, 0xd0, 0x92,
-	0xb8, 0x17, 0x95, 0xb3, 0x05, 0xcb, 0x89, 0xb3, 0xca, 0x0c, 0x49, 0xb3, 0xbb, 0x8f, 0x4c, 0x58,
-	0x31, 0xfb, 0x0f, 0xa3, 0x93, 0x66, 0xb5, 0xc7, 0xcc, 0xea, 0xf7, 0xcd, 0xf6, 0xc0, 0x90, 0xa9,
-	0x98, 0xa0, 0xb9, 0x17, 0xb8, 0x46, 0x1a, 0xa0, 0xfb, 0xee, 0x05, 0x46, 0x02, 0xa0, 0x2e, 0xd6,
-	0x24, 0x40, 0x5d, 0xac, 0xef, 0x7d, 0x01, 0x58, 0x5f, 0x11, 0xd9, 0x84, 0xc6, 0xc4, 0x7f, 0xe7,
-	0x8f, 0xdf, 0xfb, 0xb8, 0x26, 0x8a, 0x37, 0xe3, 0x89, 0x1f, 0xba, 0x14, 0x23, 0xd2, 0x84, 0xfa,
-	0x70, 0x30, 0x19, 0x0a, 0xed, 0x13, 0x68, 0x9e, 0x8e, 0x82, 0x70, 0x3c, 0xa4, 0x03, 0x0f, 0xeb,
-	0x64, 0x1b, 0x9e, 0xca, 0xce, 0x74, 0x0d, 0x1a, 0x42, 0x1b, 0x4c, 0x3c, 0x6f, 0x40, 0x3f, 0xe0,
-	0x3a, 0xd9, 0x00, 0x63, 0xe4, 0xbf, 0x1d, 0x63, 0x93, 0xb4, 0x60, 0x23, 0x08, 0x07, 0xa1, 0x1b,
-	0xb8, 0x21, 0x6e, 0xec, 0x1d, 0x83, 0xa9, 0x6e, 0x51, 0xe0, 0x9e, 0x33, 0x55, 0x0b, 0x6a, 0x64,
-	0x0b, 0xc0, 0x73, 0xa6, 0xeb, 0xdd, 0xaa, 0x1b, 0x8e, 0x3c, 0x97, 0x62, 0x6d, 0xef, 0x05, 0x98,
-	0xea, 0x36, 0x05, 0xef, 0x9c, 0x8e, 0x3d, 0x37, 0x3c, 0x75, 0x27, 0x01, 0xae, 0x09, 0xde, 0x90,
-	0x0e, 0xce, 0x4f, 0x47, 0xa1, 0x8b, 0xd1, 0x89, 0x75, 0x7b, 0xd7, 0x46, 0x3f, 0xee, 0xda, 0xe8,
-	0xd7, 0x5d, 0x1b, 0x7d, 0xfd, 0xdd, 0xae, 0x7d, 0x34, 0xd5, 0x7b, 0x8d, 0x4d, 0xf9, 0xda, 0x9c,
-	0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xcf, 0x9f, 0x2c, 0x6d, 0xed, 0x03, 0x00, 0x00,
+	// 685 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x51, 0x6f, 0xda, 0x3a,
+	0x14, 0xc6, 0x49, 0x08, 0xe5, 0x94, 0xdb, 0x6b, 0xb9, 0xd5, 0x55, 0x74, 0x75, 0x45, 0x11, 0x0f,
+	0x57, 0xa8, 0xa2, 0xa0, 0x36, 0x7d, 0xda, 0x26, 0x4d, 0x74, 0x4a, 0x29, 0x5a, 0x03, 0xad, 0x13,
+	0x34, 0x6d, 0x2f, 0x28, 0x50, 0x17, 0x90, 0x70, 0x49, 0x93, 0x30, 0x8d, 0xfd, 0x8a, 0x3d, 0x4c,
+	0xda, 0x5f, 0xea, 0xe3, 0x7e, 0xc1, 0x34, 0x75, 0xfb, 0x21, 0x93, 0xed, 0x50, 0xa0, 0xea, 0x34,
+	0x6d, 0x2f, 0x60, 0x7f, 0xe7, 0x3b, 0xdf, 0xf9, 0xec, 0x73, 0x62, 0x78, 0x3e, 0x1c, 0x27, 0xa3,
+	0x59, 0xbf, 0x36, 0x98, 0xf2, 0x3a, 0xb7, 0x2f, 0xfb, 0x75, 0x6e, 0xd7, 0xe3, 0x68, 0x50, 0xbf,
+	0x99, 0xb1, 0x68, 0x5e, 0x1f, 0xb2, 0x6b, 0x16, 0x05, 0x09, 0xbb, 0xac, 0x87, 0xd1, 0x34, 0x99,
+	0x8a, 0x5f, 0x1e, 0xf6, 0xeb, 0xc9, 0x3c, 0x64, 0x71, 0x4d, 0x42, 0xa4, 0xc0, 0x6d, 0x81, 0xb2,
+	0x64, 0xc4, 0x66, 0xf1, 0xbf, 0xfb, 0x2b, 0x72, 0xc3, 0xe9, 0x70, 0xaa, 0xf2, 0xfa, 0xb3, 0x2b,
+	0xb9, 0x53, 0x22, 0x62, 0xa5, 0x92, 0xcb, 0xcf, 0xc0, 0xf4, 0x02, 0x1e, 0x4e, 0x18, 0xd9, 0x81,
+	0xec, 0xdb, 0x60, 0x32, 0x63, 0x16, 0x2a, 0xa1, 0x0a, 0xa2, 0x6a, 0x43, 0xfe, 0x83, 0x7c, 0x32,
+	0xe6, 0x2c, 0x4e, 0x02, 0x1e, 0x5a, 0x5a, 0x09, 0x55, 0x74, 0xba, 0x04, 0xca, 0xdf, 0x35, 0x00,
+	0x7f, 0xcc, 0x99, 0xc7, 0xa2, 0x31, 0x8b, 0xc9, 0x01, 0x98, 0x93, 0xa0, 0xcf, 0x26, 0xb1, 0x85,
+	0x4a, 0x7a, 0x65, 0xf3, 0x70, 0xbb, 0xb6, 0x6a, 0xad, 0x76, 0x26, 0x62, 0xc7, 0xc6, 0xed, 0x97,
+	0xdd, 0x0c, 0x4d, 0x89, 0xe4, 0x08, 0x72, 0xb1, 0xac, 0x1f, 0x5b, 0x9a, 0xcc, 0xd9, 0x59, 0xcf,
+	0x51, 0xe6, 0xd2, 0xa4, 0x05, 0x95, 0x54, 0xc1, 0x10, 0x37, 0x60, 0xe9, 0x25, 0x54, 0xd9, 0x3a,
+	0xb4, 0xd6, 0x53, 0x5c, 0x96, 0x44, 0xe3, 0x81, 0x3f, 0x0f, 0x19, 0x95, 0x2c, 0x42, 0xc0, 0x98,
+	0x5d, 0x8f, 0x13, 0xcb, 0x28, 0xa1, 0x4a, 0x9e, 0xca, 0xb5, 0xc0, 0x46, 0x6c, 0x12, 0x5a, 0x59,
+	0x85, 0x89, 0x35, 0xd9, 0x87, 0x1c, 0xb7, 0x7b, 0x52, 0x98, 0x49, 0xe1, 0x07, 0x5e, 0x5c, 0x5b,
+	0x8a, 0x9a, 0x5c, 0xfe, 0x93, 0x2a, 0x98, 0xf1, 0x74, 0x16, 0x0d, 0x98, 0x75, 0xf5, 0x18, 0xdb,
+	0x93, 0x31, 0x9a, 0x72, 0xc8, 0x13, 0xc8, 0xb3, 0x77, 0x8c, 0x87, 0x93, 0x20, 0x8a, 0xad, 0xa1,
+	0x3c, 0xea, 0x3f, 0xeb, 0x09, 0x4e, 0x1a, 0x4e, 0x0f, 0xbb, 0xa4, 0x97, 0x6f, 0x60, 0x63, 0x11,
+	0xfc, 0x93, 0x3b, 0xbe, 0xef, 0xac, 0xf6, 0xd3, 0xce, 0xea, 0x0f, 0x3b, 0x7b, 0x00, 0x59, 0x29,
+	0x25, 0x2e, 0xea, 0x3a, 0xe0, 0x6a, 0x2a, 0x0a, 0x54, 0xae, 0xd7, 0x05, 0x0b, 0xa9, 0x60, 0xf9,
+	0x29, 0x98, 0x67, 0xaa, 0xe0, 0xef, 0x7b, 0x2c, 0x7f, 0x42, 0x50, 0x90, 0xb8, 0x1b, 0x24, 0x83,
+	0x11, 0x8b, 0x88, 0x9d, 0xb6, 0x18, 0xc9, 0xbb, 0xdd, 0x7d, 0x44, 0x21, 0x65, 0xd6, 0xd6, 0x3b,
+	0x2d, 0xcd, 0x6a, 0x8f, 0x99, 0xd5, 0x57, 0xcd, 0x56, 0xc0, 0x90, 0x4d, 0x34, 0x41, 0x73, 0x2e,
+	0x70, 0x86, 0xe4, 0x40, 0x6f, 0x3b, 0x17, 0x18, 0x09, 0x80, 0x3a, 0x58, 0x93, 0x00, 0x75, 0xb0,
+	0x5e, 0xfe, 0x88, 0x60, 0x4b, 0x8d, 0x94, 0xcb, 0x92, 0xe0, 0x32, 0x48, 0x02, 0x52, 0x5d, 0xf3,
+	0xf6, 0xab, 0xf1, 0xab, 0x02, 0xe1, 0x12, 0xeb, 0x5d, 0x05, 0x7c, 0x3c, 0x99, 0xf7, 0xee, 0x2d,
+	0xe6, 0x29, 0x56, 0x91, 0x13, 0x19, 0x68, 0x0b, 0xbb, 0x8b, 0xc1, 0x34, 0x56, 0x06, 0x73, 0x31,
+	0xc0, 0xd9, 0xe5, 0x00, 0xef, 0xbd, 0x07, 0x58, 0x56, 0x22, 0x9b, 0x90, 0xeb, 0xb6, 0x5f, 0xb6,
+	0x3b, 0xaf, 0xda, 0x38, 0x23, 0x36, 0x2f, 0x3a, 0xdd, 0xb6, 0xef, 0x50, 0x8c, 0x48, 0x1e, 0xb2,
+	0xcd, 0x46, 0xb7, 0x29, 0x8e, 0xf4, 0x17, 0xe4, 0x4f, 0x5b, 0x9e, 0xdf, 0x69, 0xd2, 0x86, 0x8b,
+	0x75, 0xb2, 0x0d, 0x7f, 0xcb, 0x48, 0x6f, 0x09, 0x1a, 0x22, 0xd7, 0xeb, 0xba, 0x6e, 0x83, 0xbe,
+	0xc6, 0x59, 0xb2, 0x01, 0x46, 0xab, 0x7d, 0xd2, 0xc1, 0x26, 0x29, 0xc0, 0x86, 0xe7, 0x37, 0x7c,
+	0xc7, 0x73, 0x7c, 0x9c, 0xdb, 0x3b, 0x02, 0x53, 0x7d, 0x0b, 0x02, 0x77, 0xed, 0x9e, 0x2a, 0x90,
+	0x21, 0x5b, 0x00, 0xae, 0xdd, 0x5b, 0xd6, 0x56, 0x51, 0xbf, 0xe5, 0x3a, 0x14, 0x6b, 0x7b, 0xff,
+	0x83, 0xa9, 0xbe, 0x09, 0xc1, 0x3b, 0xa7, 0x1d, 0xd7, 0xf1, 0x4f, 0x9d, 0xae, 0x87, 0x33, 0x82,
+	0xd7, 0xa4, 0x8d, 0xf3, 0xd3, 0x96, 0xef, 0x60, 0x74, 0x6c, 0xdd, 0xde, 0x15, 0xd1, 0xe7, 0xbb,
+	0x22, 0xfa, 0x7a, 0x57, 0x44, 0x1f, 0xbe, 0x15, 0x33, 0x6f, 0x4c, 0xf5, 0xea, 0xf5, 0x4d, 0xf9,
+	0x66, 0xd9, 0x3f, 0x06, 0x00, 0x3b, 0x9b, 0xef, 0x2c, 0x33, 0x05, 0x00, 0x00,
 }
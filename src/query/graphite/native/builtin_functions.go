@@ -35,6 +35,7 @@ import (
 
 	"github.com/m3db/m3/src/query/graphite/common"
 	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/graphite/ts"
 	"github.com/m3db/m3/src/query/util"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -303,6 +304,45 @@ func constantLine(ctx *common.Context, value float64) (ts.SeriesList, error) {
 	return ts.NewSeriesListWithSeries(newSeries), nil
 }
 
+// seriesByTag returns the series that match all of the given tag expressions,
+// the tag expressions are evaluated by the index rather than filtered client side.
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	if len(tagExpressions) == 0 {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(
+			errors.New("seriesByTag requires at least one tag expression"))
+	}
+
+	// Validate upfront so that invalid expressions are returned as an error
+	// rather than an empty result.
+	if _, err := storage.TranslateTagExpressionsToMatchers(tagExpressions); err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	query := storage.SeriesByTagQuery(tagExpressions)
+	opts := storage.FetchOptions{
+		StartTime: ctx.StartTime,
+		EndTime:   ctx.EndTime,
+		DataOptions: storage.DataOptions{
+			Timeout: ctx.Timeout,
+		},
+		QueryFetchOpts: ctx.FetchOpts,
+	}
+
+	result, err := ctx.Engine.FetchByQuery(ctx, query, opts)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	for _, r := range result.SeriesList {
+		r.Specification = query
+	}
+
+	return ts.SeriesList{
+		Values:   result.SeriesList,
+		Metadata: result.Metadata,
+	}, nil
+}
+
 // identity returns datapoints where the value equals the timestamp of the datapoint.
 func identity(ctx *common.Context, name string) (ts.SeriesList, error) {
 	return common.Identity(ctx, name)
@@ -2985,6 +3025,7 @@ func init() {
 		2: 0, // precision
 	})
 	MustRegisterFunction(scale)
+	MustRegisterFunction(seriesByTag)
 	MustRegisterFunction(scaleToSeconds)
 	MustRegisterFunction(sortBy).WithDefaultParams(map[uint8]interface{}{
 		2: "average", // fn
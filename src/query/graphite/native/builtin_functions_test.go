@@ -453,6 +453,41 @@ func TestUseSeriesAbove(t *testing.T) {
 	}
 }
 
+func TestSeriesByTag(t *testing.T) {
+	var (
+		ctrl      = xgomock.NewController(t)
+		store     = storage.NewMockStorage(ctrl)
+		now       = time.Now().Truncate(time.Hour)
+		engine    = NewEngine(store, CompileOptions{})
+		startTime = now.Add(-3 * time.Minute)
+		endTime   = now.Add(-time.Minute)
+		ctx       = common.NewContext(common.ContextOptions{Start: startTime, End: endTime, Engine: engine})
+		stepSize  = 60000
+		query     = "seriesByTag('name=foo.bar','dc=~east.*')"
+	)
+
+	defer ctrl.Finish()
+	defer func() { _ = ctx.Close() }()
+
+	store.EXPECT().FetchByQuery(gomock.Any(), query, gomock.Any()).DoAndReturn(
+		buildTestSeriesFn(stepSize, "foo.bar;dc=east1", "foo.bar;dc=east2"))
+
+	expr, err := engine.Compile("seriesByTag('name=foo.bar', 'dc=~east.*')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res.Values))
+	for _, series := range res.Values {
+		require.Equal(t, query, series.Specification)
+	}
+
+	// All negative expressions are not supported.
+	expr, err = engine.Compile("seriesByTag('dc!=east')")
+	require.NoError(t, err)
+	_, err = expr.Execute(ctx)
+	require.Error(t, err)
+}
+
 func TestPercentileOfSeriesErrors(t *testing.T) {
 	ctx := common.NewTestContext()
 
@@ -4667,6 +4702,7 @@ func TestFunctionsRegistered(t *testing.T) {
 		"removeEmptySeries",
 		"scale",
 		"scaleToSeconds",
+		"seriesByTag",
 		"smartSummarize",
 		"sortByMaxima",
 		"sortByMinima",
@@ -173,7 +173,15 @@ func translateQuery(
 	fetchOpts FetchOptions,
 	opts M3WrappedStorageOptions,
 ) (*storage.FetchQuery, error) {
-	matchers, _, err := TranslateQueryToMatchersWithTerminator(query)
+	var (
+		matchers models.Matchers
+		err      error
+	)
+	if IsSeriesByTagQuery(query) {
+		matchers, err = TranslateTagExpressionsToMatchers(seriesByTagExpressions(query))
+	} else {
+		matchers, _, err = TranslateQueryToMatchersWithTerminator(query)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+const (
+	seriesByTagPrefix    = "seriesByTag('"
+	seriesByTagSuffix    = "')"
+	seriesByTagSeparator = "','"
+
+	// nameTag is the special tag that refers to the path of a tagged series.
+	nameTag = "name"
+)
+
+var errSeriesByTagNoPositiveExpression = xerrors.NewInvalidParamsError(errors.New(
+	"seriesByTag requires at least one tag expression that matches a non-empty value"))
+
+// SeriesByTagQuery returns the storage query used to fetch the series that
+// match all of the given seriesByTag tag expressions, i.e. "dc=east".
+func SeriesByTagQuery(tagExpressions []string) string {
+	return seriesByTagPrefix +
+		strings.Join(tagExpressions, seriesByTagSeparator) +
+		seriesByTagSuffix
+}
+
+// IsSeriesByTagQuery returns whether the query is a seriesByTag query.
+func IsSeriesByTagQuery(query string) bool {
+	return strings.HasPrefix(query, seriesByTagPrefix) &&
+		strings.HasSuffix(query, seriesByTagSuffix)
+}
+
+func seriesByTagExpressions(query string) []string {
+	query = strings.TrimPrefix(query, seriesByTagPrefix)
+	query = strings.TrimSuffix(query, seriesByTagSuffix)
+	return strings.Split(query, seriesByTagSeparator)
+}
+
+// TranslateTagExpressionsToMatchers converts seriesByTag tag expressions to
+// tag matchers so that they can be evaluated by the index. Supported tag
+// expressions are:
+//   tag=value   tag value equals value (or tag does not exist if value is empty)
+//   tag!=value  tag value does not equal value
+//   tag=~value  tag value matches the regular expression (anchored at start)
+//   tag!=~value tag value does not match the regular expression
+// The special tag "name" refers to the path of the series.
+func TranslateTagExpressionsToMatchers(
+	tagExpressions []string,
+) (models.Matchers, error) {
+	var (
+		matchers    = make(models.Matchers, 0, len(tagExpressions)+1)
+		hasPath     bool
+		hasPositive bool
+	)
+	for _, expr := range tagExpressions {
+		tag, value, matchType, err := parseTagExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if tag == nameTag {
+			if matchType == models.MatchEqual {
+				pathMatchers, _, err := TranslateQueryToMatchersWithTerminator(value)
+				if err != nil {
+					return nil, err
+				}
+
+				matchers = append(matchers, pathMatchers...)
+				hasPath = true
+				hasPositive = true
+				continue
+			}
+
+			// The ID of a tagged series is the path followed by its tags,
+			// i.e. "foo.bar;dc=east" so match on the ID for the path.
+			pattern := regexp.QuoteMeta(value) + "(;.*)?"
+			if matchType == models.MatchRegexp || matchType == models.MatchNotRegexp {
+				pattern = "(" + value + ").*"
+			}
+			if matchType == models.MatchNotEqual {
+				matchType = models.MatchNotRegexp
+			}
+
+			if matchType == models.MatchRegexp {
+				hasPositive = true
+			}
+
+			matchers = append(matchers, models.Matcher{
+				Type:  matchType,
+				Name:  doc.IDReservedFieldName,
+				Value: []byte(pattern),
+			})
+			continue
+		}
+
+		switch matchType {
+		case models.MatchEqual:
+			if value == "" {
+				matchType = models.MatchNotField
+			} else {
+				hasPositive = true
+			}
+		case models.MatchNotEqual:
+			if value == "" {
+				matchType = models.MatchField
+			}
+		case models.MatchRegexp:
+			value = "(" + value + ").*"
+			hasPositive = true
+		case models.MatchNotRegexp:
+			value = "(" + value + ").*"
+		}
+
+		m := models.Matcher{
+			Type: matchType,
+			Name: []byte(tag),
+		}
+		if matchType != models.MatchField && matchType != models.MatchNotField {
+			m.Value = []byte(value)
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	if !hasPositive {
+		return nil, errSeriesByTagNoPositiveExpression
+	}
+
+	if !hasPath {
+		// Ensure that only graphite series are matched.
+		hasFirstPathMatcher, err := convertMetricPartToMatcher(0, wildcard)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, hasFirstPathMatcher)
+	}
+
+	return matchers, nil
+}
+
+func parseTagExpression(expr string) (string, string, models.MatchType, error) {
+	idx := strings.IndexByte(expr, '=')
+	if idx < 0 {
+		return "", "", 0, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid seriesByTag tag expression: %s", expr))
+	}
+
+	var (
+		tag       = expr[:idx]
+		value     = expr[idx+1:]
+		not       = strings.HasSuffix(tag, "!")
+		regex     = strings.HasPrefix(value, "~")
+		matchType models.MatchType
+	)
+	if not {
+		tag = tag[:len(tag)-1]
+	}
+	if regex {
+		value = value[1:]
+	}
+	if tag == "" {
+		return "", "", 0, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid seriesByTag tag expression, empty tag: %s", expr))
+	}
+
+	switch {
+	case not && regex:
+		matchType = models.MatchNotRegexp
+	case not:
+		matchType = models.MatchNotEqual
+	case regex:
+		matchType = models.MatchRegexp
+	default:
+		matchType = models.MatchEqual
+	}
+
+	return tag, value, matchType, nil
+}
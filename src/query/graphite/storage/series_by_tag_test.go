@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+func TestTranslateQuerySeriesByTag(t *testing.T) {
+	query := SeriesByTagQuery([]string{
+		"name=foo.bar",
+		"dc=east",
+		"env!=",
+		"host=~web.*",
+		"role!=~db",
+		"zone=",
+	})
+	require.True(t, IsSeriesByTagQuery(query))
+
+	end := time.Now()
+	start := end.Add(time.Hour * -2)
+	opts := FetchOptions{
+		StartTime: start,
+		EndTime:   end,
+		DataOptions: DataOptions{
+			Timeout: time.Minute,
+		},
+	}
+
+	translated, err := translateQuery(query, opts, M3WrappedStorageOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, query, translated.Raw)
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("foo")},
+		{Type: models.MatchEqual, Name: graphite.TagName(1), Value: []byte("bar")},
+		{Type: models.MatchNotField, Name: graphite.TagName(2)},
+		{Type: models.MatchEqual, Name: []byte("dc"), Value: []byte("east")},
+		{Type: models.MatchField, Name: []byte("env")},
+		{Type: models.MatchRegexp, Name: []byte("host"), Value: []byte("(web.*).*")},
+		{Type: models.MatchNotRegexp, Name: []byte("role"), Value: []byte("(db).*")},
+		{Type: models.MatchNotField, Name: []byte("zone")},
+	}
+	assert.Equal(t, expected, translated.TagMatchers)
+}
+
+func TestTranslateTagExpressionsToMatchersNameRegexp(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{"name=~foo\\.ba[rz]"})
+	require.NoError(t, err)
+	expected := models.Matchers{
+		{Type: models.MatchRegexp, Name: doc.IDReservedFieldName, Value: []byte(`(foo\.ba[rz]).*`)},
+		{Type: models.MatchRegexp, Name: graphite.TagName(0), Value: []byte(".*")},
+	}
+	assert.Equal(t, expected, matchers)
+}
+
+func TestTranslateTagExpressionsToMatchersErrors(t *testing.T) {
+	for _, exprs := range [][]string{
+		{"dc"},
+		{"=east"},
+		{"dc!=east"},
+		{"dc=", "env!=~prod"},
+	} {
+		_, err := TranslateTagExpressionsToMatchers(exprs)
+		require.Error(t, err)
+		require.True(t, xerrors.IsInvalidParams(err))
+	}
+}
@@ -37,7 +37,7 @@ func (t IDSchemeType) Validate() error {
 		return errors.New("id scheme type not set")
 	}
 
-	if t >= TypeQuoted && t <= TypeGraphite {
+	if t >= TypeQuoted && t <= TypeGraphiteTagged {
 		return nil
 	}
 
@@ -55,6 +55,8 @@ func (t IDSchemeType) String() string {
 		return "prepend_meta"
 	case TypeGraphite:
 		return "graphite"
+	case TypeGraphiteTagged:
+		return "graphite_tagged"
 	default:
 		// Should never get here.
 		return "unknown"
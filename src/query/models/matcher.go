@@ -24,7 +24,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -62,7 +61,7 @@ func NewMatcher(t MatchType, n, v []byte) (Matcher, error) {
 	}
 
 	if t == MatchRegexp || t == MatchNotRegexp {
-		re, err := regexp.Compile("^(?:" + string(v) + ")$")
+		re, err := compileMatcherRegexp(v)
 		if err != nil {
 			return Matcher{}, err
 		}
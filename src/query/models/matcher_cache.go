@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package models
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/m3db/m3/src/x/cache"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	// The compiled regexps are shared across queries, as per Go std lib:
+	// A Regexp is safe for concurrent use by multiple goroutines, except for
+	// configuration methods, such as Longest.
+	regexpCacheLock    sync.RWMutex
+	regexpCache        *cache.LRU
+	regexpCacheMetrics *MatcherCacheMetrics
+)
+
+// MatcherCacheOptions is a set of options for caches of compiled matchers
+// shared across queries.
+type MatcherCacheOptions struct {
+	Size  int
+	Scope tally.Scope
+}
+
+// MatcherCacheMetrics are the hit and miss metrics of a matcher cache.
+type MatcherCacheMetrics struct {
+	Hit  tally.Counter
+	Miss tally.Counter
+}
+
+// NewMatcherCacheMetrics returns new matcher cache metrics.
+func NewMatcherCacheMetrics(scope tally.Scope) *MatcherCacheMetrics {
+	return &MatcherCacheMetrics{
+		Hit:  scope.Counter("hit"),
+		Miss: scope.Counter("miss"),
+	}
+}
+
+// SetRegexpCacheOptions sets the options of the cache of compiled matcher
+// regexps, size zero disables the cache.
+func SetRegexpCacheOptions(opts MatcherCacheOptions) {
+	regexpCacheLock.Lock()
+	defer regexpCacheLock.Unlock()
+
+	if opts.Size < 1 {
+		regexpCache = nil
+		regexpCacheMetrics = nil
+		return
+	}
+
+	scope := tally.NoopScope
+	if opts.Scope != nil {
+		scope = opts.Scope
+	}
+
+	scope = scope.SubScope("matcher").SubScope("regexp-cache")
+	regexpCache = cache.NewLRU(&cache.LRUOptions{
+		MaxEntries: opts.Size,
+		Metrics:    scope.SubScope("lru"),
+	})
+	regexpCacheMetrics = NewMatcherCacheMetrics(scope)
+}
+
+// compileMatcherRegexp compiles the anchored regexp of a matcher value,
+// reusing a previously compiled regexp for the same value if cached.
+func compileMatcherRegexp(v []byte) (*regexp.Regexp, error) {
+	reString := string(v)
+
+	regexpCacheLock.RLock()
+	cacheLRU := regexpCache
+	cacheLRUMetrics := regexpCacheMetrics
+	regexpCacheLock.RUnlock()
+
+	if cacheLRU != nil {
+		if cached, ok := cacheLRU.TryGet(reString); ok {
+			if re, ok := cached.(*regexp.Regexp); ok {
+				cacheLRUMetrics.Hit.Inc(1)
+				return re, nil
+			}
+		}
+		cacheLRUMetrics.Miss.Inc(1)
+	}
+
+	re, err := regexp.Compile("^(?:" + reString + ")$")
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheLRU != nil {
+		cacheLRU.Put(reString, re)
+	}
+
+	return re, nil
+}
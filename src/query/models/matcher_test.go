@@ -23,8 +23,11 @@ package models
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/x/tallytest"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 func TestMatcherString(t *testing.T) {
@@ -78,3 +81,23 @@ func TestValidMatchersFromString(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, m)
 }
+
+func TestMatcherRegexpCache(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+
+	SetRegexpCacheOptions(MatcherCacheOptions{Size: 1, Scope: scope})
+	defer SetRegexpCacheOptions(MatcherCacheOptions{Size: 0})
+
+	m1, err := NewMatcher(MatchRegexp, []byte("foo"), []byte("b.*r"))
+	require.NoError(t, err)
+
+	tallytest.AssertCounterValue(t, 1, scope.Snapshot(), "matcher.regexp-cache.miss", nil)
+
+	m2, err := NewMatcher(MatchNotRegexp, []byte("baz"), []byte("b.*r"))
+	require.NoError(t, err)
+
+	tallytest.AssertCounterValue(t, 1, scope.Snapshot(), "matcher.regexp-cache.hit", nil)
+	require.True(t, m1.re == m2.re)
+	require.True(t, m2.re.MatchString("bar"))
+	require.False(t, m2.re.MatchString("barn"))
+}
@@ -261,7 +261,7 @@ func (t sortableTagsNumericallyAsc) Less(i, j int) bool {
 // Normalize normalizes the tags by sorting them in place.
 // In the future, it might also ensure other things like uniqueness.
 func (t Tags) Normalize() Tags {
-	if t.Opts.IDSchemeType() == TypeGraphite {
+	if t.Opts.IDSchemeType().IsGraphite() {
 		// Graphite tags are sorted numerically rather than lexically.
 		sort.Sort(sortableTagsNumericallyAsc(t))
 	} else {
@@ -289,7 +289,7 @@ func (t Tags) validate() error {
 		return errNoTags
 	}
 
-	if t.Opts.IDSchemeType() == TypeGraphite {
+	if t.Opts.IDSchemeType().IsGraphite() {
 		// Graphite tags are sorted numerically rather than lexically.
 		tags := sortableTagsNumericallyAsc(t)
 		for i, tag := range tags.Tags {
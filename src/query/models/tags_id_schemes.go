@@ -21,6 +21,8 @@
 package models
 
 import (
+	"bytes"
+
 	"github.com/m3db/m3/src/query/models/strconv"
 	"github.com/m3db/m3/src/query/util/writer"
 )
@@ -42,6 +44,8 @@ func id(t Tags) []byte {
 		return prependMetaID(t)
 	case TypeGraphite:
 		return graphiteID(t)
+	case TypeGraphiteTagged:
+		return graphiteTaggedID(t)
 	default:
 		// Default to quoted meta
 		// NB: realistically, schema defaults should be set by here.
@@ -231,3 +235,97 @@ func graphiteID(t Tags) []byte {
 	copy(id[idx:], t.Tags[lastIndex].Value)
 	return id
 }
+
+func idLenGraphiteTagged(t Tags) (int, bool) {
+	var (
+		idLen       int
+		numPathTags int
+	)
+	for _, tag := range t.Tags {
+		if IsGraphitePathTag(tag.Name) {
+			idLen += len(tag.Value)
+			numPathTags++
+			continue
+		}
+
+		// Account for the tag separator and the value separator.
+		idLen += len(tag.Name) + len(tag.Value) + 2
+	}
+
+	if numPathTags == 0 {
+		return 0, false
+	}
+
+	return idLen + numPathTags - 1, true
+}
+
+func graphiteTaggedID(t Tags) []byte {
+	idLen, hasPath := idLenGraphiteTagged(t)
+	if !hasPath {
+		// Not a graphite path, fallback to joining all values.
+		return graphiteID(t)
+	}
+
+	// TODO: pool these bytes.
+	// NB: tags are ordered numerically so write out the path tags first and
+	// then append the other tags of the series, i.e. path;t1=v1;t2=v2.
+	id := make([]byte, idLen)
+	idx := 0
+	first := true
+	for _, tag := range t.Tags {
+		if !IsGraphitePathTag(tag.Name) {
+			continue
+		}
+
+		if !first {
+			id[idx] = graphiteSep
+			idx++
+		}
+
+		first = false
+		idx += copy(id[idx:], tag.Value)
+	}
+
+	for _, tag := range t.Tags {
+		if IsGraphitePathTag(tag.Name) {
+			continue
+		}
+
+		id[idx] = GraphiteTaggedSeparator
+		idx++
+		idx += copy(id[idx:], tag.Name)
+		id[idx] = GraphiteTaggedValueSeparator
+		idx++
+		idx += copy(id[idx:], tag.Value)
+	}
+
+	return id
+}
+
+var (
+	graphitePathTagPrefix = []byte("__g")
+	graphitePathTagSuffix = []byte("__")
+)
+
+// IsGraphitePathTag returns whether the tag name is a graphite path tag, i.e.
+// one of the tags generated from the dot separated path of a graphite metric
+// such as __g0__, rather than a tag of a tagged graphite series.
+func IsGraphitePathTag(name []byte) bool {
+	if !bytes.HasPrefix(name, graphitePathTagPrefix) ||
+		!bytes.HasSuffix(name, graphitePathTagSuffix) {
+		return false
+	}
+
+	index := name[len(graphitePathTagPrefix) : len(name)-len(graphitePathTagSuffix)]
+	if len(index) == 0 {
+		return false
+	}
+
+	for _, c := range index {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -76,6 +76,38 @@ func TestLongTagNewIDOutOfOrderGraphite(t *testing.T) {
 	assert.Equal(t, []byte("v0.v1.v2.v3.v4.v5.v6.v7.v8.v9.v10.v11.v12"), actual)
 }
 
+func TestTaggedGraphiteID(t *testing.T) {
+	testTags := []Tag{
+		{Name: []byte("__g0__"), Value: []byte("foo")},
+		{Name: []byte("env"), Value: []byte("prod")},
+		{Name: []byte("__g1__"), Value: []byte("bar")},
+		{Name: []byte("dc"), Value: []byte("east")},
+	}
+
+	opts := NewTagOptions().SetIDSchemeType(TypeGraphiteTagged)
+	tags := NewTags(4, opts).AddTags(testTags)
+	assert.Equal(t, []byte("foo.bar;dc=east;env=prod"), tags.ID())
+
+	// The graphite scheme keeps joining the values of all tags.
+	opts = NewTagOptions().SetIDSchemeType(TypeGraphite)
+	tags = NewTags(4, opts).AddTags(testTags)
+	assert.Equal(t, []byte("east.prod.foo.bar"), tags.ID())
+
+	// Series without path tags join the values of all tags.
+	opts = NewTagOptions().SetIDSchemeType(TypeGraphiteTagged)
+	tags = NewTags(2, opts).AddTags([]Tag{testTags[1], testTags[3]})
+	assert.Equal(t, []byte("east.prod"), tags.ID())
+}
+
+func TestIsGraphitePathTag(t *testing.T) {
+	for _, name := range []string{"__g0__", "__g12__"} {
+		assert.True(t, IsGraphitePathTag([]byte(name)), name)
+	}
+	for _, name := range []string{"", "__g__", "__gx__", "__g1_", "g1", "dc"} {
+		assert.False(t, IsGraphitePathTag([]byte(name)), name)
+	}
+}
+
 func TestLongTagNewIDOutOfOrderQuotedWithEscape(t *testing.T) {
 	tags := testLongTagIDOutOfOrder(t, TypeQuoted)
 	tags = tags.AddTag(Tag{Name: []byte(`t5""`), Value: []byte(`v"5`)})
@@ -35,6 +35,16 @@ const (
 	rightBracket = byte('}')
 )
 
+const (
+	// GraphiteTaggedSeparator is the separator between the path of a tagged
+	// graphite series and each of its tags, i.e. "foo.bar;dc=east;env=prod".
+	GraphiteTaggedSeparator = byte(';')
+
+	// GraphiteTaggedValueSeparator is the separator between a tag name and its
+	// value in the name of a tagged graphite series.
+	GraphiteTaggedValueSeparator = byte('=')
+)
+
 // IDSchemeType determines the scheme for generating
 // series IDs based on their tags.
 type IDSchemeType uint16
@@ -70,8 +80,22 @@ const (
 	// a general ID scheme; instead, it is set on any metric coming through the
 	// graphite ingestion path.
 	TypeGraphite
+	// TypeGraphiteTagged describes a scheme where IDs are generated to match the
+	// representation of tagged graphite series, with the path tags followed by
+	// the name and value of any other tags. It is otherwise the same as the
+	// graphite scheme, and is set on tagged series coming through the graphite
+	// ingestion path rather than being available to choose.
+	// {__g0__:v1},{__g1__:v2} -> v1.v2
+	// {__g0__:v1},{__g1__:v2},{t1:v3} -> v1.v2;t1=v3
+	TypeGraphiteTagged
 )
 
+// IsGraphite returns whether the scheme is one of the graphite schemes, for
+// which tags are ordered numerically rather than lexically.
+func (t IDSchemeType) IsGraphite() bool {
+	return t == TypeGraphite || t == TypeGraphiteTagged
+}
+
 // TagOptions describes additional options for tags.
 type TagOptions interface {
 	// Validate validates these tag options.
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	promqlengine "github.com/prometheus/prometheus/promql"
+	pql "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/m3db/m3/src/query/functions/temporal"
+)
+
+func init() {
+	// NB: the vendored Prometheus parser predates present_over_time, register
+	// it with both the parser and the Prometheus engine so that queries using
+	// it parse and evaluate regardless of the engine used.
+	if _, ok := pql.Functions[temporal.PresentType]; !ok {
+		pql.Functions[temporal.PresentType] = &pql.Function{
+			Name:       temporal.PresentType,
+			ArgTypes:   []pql.ValueType{pql.ValueTypeMatrix},
+			ReturnType: pql.ValueTypeVector,
+		}
+	}
+	if _, ok := promqlengine.FunctionCalls[temporal.PresentType]; !ok {
+		promqlengine.FunctionCalls[temporal.PresentType] = presentOverTime
+	}
+}
+
+func presentOverTime(
+	_ []pql.Value,
+	_ pql.Expressions,
+	enh *promqlengine.EvalNodeHelper,
+) promqlengine.Vector {
+	return append(enh.Out, promqlengine.Sample{
+		Point: promqlengine.Point{V: 1},
+	})
+}
@@ -124,6 +124,40 @@ func NewAggregationOperator(expr *promql.AggregateExpr) (parser.Params, error) {
 	return aggregation.NewAggregationOp(op, nodeInformation)
 }
 
+// absentTags returns the tags of the series returned by absent and
+// absent_over_time for the given argument: by Prometheus convention the
+// names and values of the equality matchers of a selector argument, except
+// for names matched more than once.
+func absentTags(expr promql.Expr) []models.Tag {
+	var lMatchers []*labels.Matcher
+	switch e := unwrapParenExpr(expr).(type) {
+	case *promql.VectorSelector:
+		lMatchers = e.LabelMatchers
+	case *promql.MatrixSelector:
+		if vs, ok := e.VectorSelector.(*promql.VectorSelector); ok {
+			lMatchers = vs.LabelMatchers
+		}
+	}
+
+	var (
+		tags    = make([]models.Tag, 0, len(lMatchers))
+		matched = make(map[string]int, len(lMatchers))
+	)
+	for _, m := range lMatchers {
+		if m.Name != model.MetricNameLabel {
+			matched[m.Name]++
+		}
+	}
+	for _, m := range lMatchers {
+		if m.Type != labels.MatchEqual || matched[m.Name] != 1 {
+			continue
+		}
+		tags = append(tags, models.Tag{Name: []byte(m.Name), Value: []byte(m.Value)})
+	}
+
+	return tags
+}
+
 func unwrapParenExpr(expr promql.Expr) promql.Expr {
 	for {
 		if paren, ok := expr.(*promql.ParenExpr); ok {
@@ -162,6 +196,8 @@ func getAggOpType(opType promql.ItemType) string {
 		return aggregation.StandardVarianceType
 	case promql.COUNT:
 		return aggregation.CountType
+	case promql.GROUP:
+		return aggregation.GroupType
 
 	case promql.TOPK:
 		return aggregation.TopKType
@@ -269,6 +305,15 @@ func NewFunctionExpr(
 		p, err = temporal.NewQuantileOp(argValues, name)
 		return p, true, err
 
+	case temporal.PresentType:
+		p, err = temporal.NewAggOp(argValues, name)
+		return p, true, err
+
+	case temporal.AbsentType:
+		// NB: the parser chains an absent op after present_over_time.
+		p, err = temporal.NewAggOp(argValues, temporal.PresentType)
+		return p, true, err
+
 	case temporal.HoltWintersType:
 		p, err = temporal.NewHoltWintersOp(argValues)
 		return p, true, err
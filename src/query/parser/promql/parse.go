@@ -27,9 +27,11 @@ import (
 	pql "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions/aggregation"
 	"github.com/m3db/m3/src/query/functions/binary"
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/scalar"
+	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -338,6 +340,10 @@ func (p *parseState) walk(node pql.Node) error {
 			return nil
 		}
 
+		if n.Func.Name == aggregation.AbsentType && op.OpType() == aggregation.AbsentType {
+			op = aggregation.NewAbsentOpWithTags(absentTags(n.Args[0]))
+		}
+
 		opTransform := parser.NewTransformFromOperation(op, p.transformLen())
 		if op.OpType() != scalar.TimeType {
 			p.edges = append(p.edges, parser.Edge{
@@ -347,6 +353,16 @@ func (p *parseState) walk(node pql.Node) error {
 		}
 
 		p.transforms = append(p.transforms, opTransform)
+		if n.Func.Name == temporal.AbsentType && op.OpType() == temporal.PresentType {
+			// NB: absent_over_time is evaluated as absent applied to present_over_time.
+			absentTransform := parser.NewTransformFromOperation(
+				aggregation.NewAbsentOpWithTags(absentTags(n.Args[0])), p.transformLen())
+			p.edges = append(p.edges, parser.Edge{
+				ParentID: opTransform.ID,
+				ChildID:  absentTransform.ID,
+			})
+			p.transforms = append(p.transforms, absentTransform)
+		}
 		return nil
 
 	case *pql.BinaryExpr:
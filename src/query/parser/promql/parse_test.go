@@ -149,6 +149,7 @@ var aggregateParseTests = []struct {
 	{"stddev(up)", aggregation.StandardDeviationType},
 	{"stdvar(up)", aggregation.StandardVarianceType},
 	{"count(up)", aggregation.CountType},
+	{"group(up)", aggregation.GroupType},
 
 	{"topk(3, up)", aggregation.TopKType},
 	{"bottomk(3, up)", aggregation.BottomKType},
@@ -470,6 +471,7 @@ var temporalParseTests = []struct {
 	{"stddev_over_time(up[5m])", temporal.StdDevType},
 	{"stdvar_over_time(up[5m])", temporal.StdVarType},
 	{"quantile_over_time(0.2, up[5m])", temporal.QuantileType},
+	{"present_over_time(up[5m])", temporal.PresentType},
 	{"irate(up[5m])", temporal.IRateType},
 	{"idelta(up[5m])", temporal.IDeltaType},
 	{"rate(up[5m])", temporal.RateType},
@@ -502,6 +504,26 @@ func TestTemporalParses(t *testing.T) {
 	}
 }
 
+func TestAbsentOverTimeParses(t *testing.T) {
+	q := `absent_over_time(up{a="b"}[5m])`
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	assert.Len(t, transforms, 3)
+	assert.Equal(t, transforms[0].Op.OpType(), functions.FetchType)
+	assert.Equal(t, transforms[0].ID, parser.NodeID("0"))
+	assert.Equal(t, transforms[1].Op.OpType(), temporal.PresentType)
+	assert.Equal(t, transforms[1].ID, parser.NodeID("1"))
+	assert.Equal(t, transforms[2].Op.OpType(), aggregation.AbsentType)
+	assert.Equal(t, transforms[2].ID, parser.NodeID("2"))
+	assert.Len(t, edges, 2)
+	assert.Equal(t, edges[0].ParentID, parser.NodeID("0"))
+	assert.Equal(t, edges[0].ChildID, parser.NodeID("1"))
+	assert.Equal(t, edges[1].ParentID, parser.NodeID("1"))
+	assert.Equal(t, edges[1].ChildID, parser.NodeID("2"))
+}
+
 var tagParseTests = []struct {
 	q            string
 	expectedType string
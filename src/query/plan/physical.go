@@ -160,6 +160,18 @@ func (p PhysicalPlan) Step(ID parser.NodeID) (LogicalStep, bool) {
 	return step, ok
 }
 
+// Steps returns the logical steps in the order they are performed.
+func (p PhysicalPlan) Steps() []LogicalStep {
+	steps := make([]LogicalStep, 0, len(p.pipeline))
+	for _, ID := range p.pipeline {
+		if step, ok := p.steps[ID]; ok {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
+
 // String representation of the physical plan.
 func (p PhysicalPlan) String() string {
 	return fmt.Sprintf("StepCount: %s, Pipeline: %s, Result: %s, TimeSpec: %v",
@@ -64,6 +64,30 @@ func TestResultNode(t *testing.T) {
 	assert.Equal(t, p.ResultStep.Parent, countTransform.ID)
 }
 
+func TestPhysicalPlanSteps(t *testing.T) {
+	fetchTransform := parser.NewTransformFromOperation(functions.FetchOp{}, 1)
+	agg, err := aggregation.NewAggregationOp(aggregation.CountType, aggregation.NodeParams{})
+	require.NoError(t, err)
+	countTransform := parser.NewTransformFromOperation(agg, 2)
+	transforms := parser.Nodes{fetchTransform, countTransform}
+	edges := parser.Edges{
+		parser.Edge{
+			ParentID: fetchTransform.ID,
+			ChildID:  countTransform.ID,
+		},
+	}
+
+	lp, err := NewLogicalPlan(transforms, edges)
+	require.NoError(t, err)
+	p, err := NewPhysicalPlan(lp, testRequestParams())
+	require.NoError(t, err)
+	steps := p.Steps()
+	require.Len(t, steps, 2)
+	assert.Equal(t, fetchTransform.ID, steps[0].ID())
+	assert.Equal(t, countTransform.ID, steps[1].ID())
+	assert.Equal(t, []parser.NodeID{fetchTransform.ID}, steps[1].Parents)
+}
+
 func TestShiftTime(t *testing.T) {
 	tests := []struct {
 		name             string
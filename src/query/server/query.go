@@ -55,8 +55,11 @@ import (
 	tsdbremote "github.com/m3db/m3/src/query/remote"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/fanout"
+	"github.com/m3db/m3/src/query/storage/ingestaccounting"
+	"github.com/m3db/m3/src/query/storage/inprogress"
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/storage/promremote"
 	"github.com/m3db/m3/src/query/storage/remote"
 	"github.com/m3db/m3/src/query/stores/m3db"
@@ -324,11 +327,30 @@ func Run(runOpts RunOptions) RunResult {
 
 	defer buildReporter.Stop()
 
+	continuousProfiler, err := cfg.Debug.NewContinuousProfiler(instrumentOptions)
+	if err != nil {
+		logger.Fatal("could not create continuous profiler", zap.Error(err))
+	}
+	if continuousProfiler != nil {
+		if err := continuousProfiler.Start(); err != nil {
+			logger.Fatal("could not start continuous profiler", zap.Error(err))
+		}
+		defer continuousProfiler.Stop()
+	}
+
 	storageRestrictByTags, _, err := cfg.Query.RestrictTagsAsStorageRestrictByTag()
 	if err != nil {
 		logger.Fatal("could not parse query restrict tags config", zap.Error(err))
 	}
 
+	// Setup caches of compiled regexp matchers and their index queries.
+	matcherCacheOpts := models.MatcherCacheOptions{
+		Size:  cfg.Query.MatcherCache.SizeOrDefault(),
+		Scope: instrumentOptions.MetricsScope(),
+	}
+	models.SetRegexpCacheOptions(matcherCacheOpts)
+	storage.SetRegexpQueryCacheOptions(matcherCacheOpts)
+
 	timeout := cfg.Query.TimeoutOrDefault()
 	if runOpts.DBConfig != nil &&
 		runOpts.DBConfig.Client.FetchTimeout != nil &&
@@ -339,9 +361,10 @@ func Run(runOpts RunOptions) RunResult {
 	fetchOptsBuilderLimitsOpts := cfg.Limits.PerQuery.AsFetchOptionsBuilderLimitsOptions()
 	fetchOptsBuilder, err := handleroptions.NewFetchOptionsBuilder(
 		handleroptions.FetchOptionsBuilderOptions{
-			Limits:        fetchOptsBuilderLimitsOpts,
-			RestrictByTag: storageRestrictByTags,
-			Timeout:       timeout,
+			Limits:                    fetchOptsBuilderLimitsOpts,
+			RestrictByTag:             storageRestrictByTags,
+			Timeout:                   timeout,
+			SnapshotCompletenessDelay: cfg.Query.SnapshotCompletenessDelay,
 		})
 	if err != nil {
 		logger.Fatal("could not set fetch options parser", zap.Error(err))
@@ -615,9 +638,10 @@ func Run(runOpts RunOptions) RunResult {
 			fetchOptsBuilderLimitsOpts := limits.PerQuery.AsFetchOptionsBuilderLimitsOptions()
 			graphiteFindFetchOptsBuilder, err = handleroptions.NewFetchOptionsBuilder(
 				handleroptions.FetchOptionsBuilderOptions{
-					Limits:        fetchOptsBuilderLimitsOpts,
-					RestrictByTag: storageRestrictByTags,
-					Timeout:       timeout,
+					Limits:                    fetchOptsBuilderLimitsOpts,
+					RestrictByTag:             storageRestrictByTags,
+					Timeout:                   timeout,
+					SnapshotCompletenessDelay: cfg.Query.SnapshotCompletenessDelay,
 				})
 			if err != nil {
 				logger.Fatal("could not set graphite find fetch options parser", zap.Error(err))
@@ -627,9 +651,10 @@ func Run(runOpts RunOptions) RunResult {
 			fetchOptsBuilderLimitsOpts := limits.PerQuery.AsFetchOptionsBuilderLimitsOptions()
 			graphiteRenderFetchOptsBuilder, err = handleroptions.NewFetchOptionsBuilder(
 				handleroptions.FetchOptionsBuilderOptions{
-					Limits:        fetchOptsBuilderLimitsOpts,
-					RestrictByTag: storageRestrictByTags,
-					Timeout:       timeout,
+					Limits:                    fetchOptsBuilderLimitsOpts,
+					RestrictByTag:             storageRestrictByTags,
+					Timeout:                   timeout,
+					SnapshotCompletenessDelay: cfg.Query.SnapshotCompletenessDelay,
 				})
 			if err != nil {
 				logger.Fatal("could not set graphite find fetch options parser", zap.Error(err))
@@ -653,6 +678,30 @@ func Run(runOpts RunOptions) RunResult {
 		logger.Fatal("unable to set up handler options", zap.Error(err))
 	}
 
+	if metadataCfg := cfg.MetricMetadata; metadataCfg != nil && metadataCfg.Enabled {
+		if clusterClient == nil {
+			logger.Fatal("metric metadata requires a cluster client to store metadata in KV")
+		}
+		metadataOpts, err := metricmetadata.NewOptions(metadataCfg, clusterClient.KV,
+			instrumentOptions.MetricsScope(), logger)
+		if err != nil {
+			logger.Fatal("unable to create metric metadata options", zap.Error(err))
+		}
+		metadataStore := metricmetadata.NewStore(metadataOpts)
+		defer metadataStore.Close()
+		handlerOptions = handlerOptions.SetMetricMetadataStore(metadataStore)
+	}
+
+	if accountingCfg := cfg.IngestAccounting; accountingCfg != nil && accountingCfg.Enabled {
+		accountingOpts, err := ingestaccounting.NewOptions(accountingCfg,
+			instrumentOptions.MetricsScope())
+		if err != nil {
+			logger.Fatal("unable to create ingest accounting options", zap.Error(err))
+		}
+		handlerOptions = handlerOptions.SetIngestAccountant(
+			ingestaccounting.NewAccountant(accountingOpts))
+	}
+
 	var customHandlerOpts options.CustomHandlerOptions
 	if runOpts.CustomHandlerOptions != nil {
 		customHandlerOpts, err = runOpts.CustomHandlerOptions(instrumentOptions)
@@ -1145,6 +1194,29 @@ func newStorages(
 
 	fanoutStorage := fanout.NewStorage(stores, readFilter, writeFilter,
 		completeTagsFilter, opts.TagOptions(), opts, instrumentOpts)
+
+	if remoteReadCfg := cfg.Query.RemoteRead; remoteReadCfg != nil && remoteReadCfg.Enabled {
+		logger.Info("remote read enabled",
+			zap.Strings("endpoints", remoteReadCfg.Endpoints))
+		remoteReadOpts, err := promremote.NewReadOptions(remoteReadCfg,
+			instrumentOpts.MetricsScope(), logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		fanoutStorage = promremote.NewReadMergeStorage(fanoutStorage, remoteReadOpts)
+	}
+
+	if rywCfg := cfg.Query.ReadYourWrites; rywCfg != nil && rywCfg.Enabled {
+		logger.Info("read your writes enabled",
+			zap.Strings("aggregatorEndpoints", rywCfg.Endpoints))
+		inProgressOpts, err := inprogress.NewOptions(rywCfg,
+			instrumentOpts.MetricsScope(), logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		fanoutStorage = inprogress.NewStorage(fanoutStorage, inProgressOpts)
+	}
+
 	return fanoutStorage, cleanup, nil
 }
 
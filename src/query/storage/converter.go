@@ -22,11 +22,13 @@ package storage
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"sort"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -45,6 +47,12 @@ var (
 	promDefaultCountSuffix = []byte("_count")
 )
 
+const (
+	// The labels of Prometheus exemplars holding the trace and span IDs.
+	promExemplarTraceIDLabel = "trace_id"
+	promExemplarSpanIDLabel  = "span_id"
+)
+
 // PromLabelsToM3Tags converts Prometheus labels to M3 tags
 func PromLabelsToM3Tags(
 	labels []prompb.Label,
@@ -200,6 +208,74 @@ func PromSamplesToM3Datapoints(samples []prompb.Sample) ts.Datapoints {
 	return datapoints
 }
 
+// PromExemplarsToM3Exemplars converts Prometheus exemplars to M3 exemplars,
+// skipping the exemplars without a trace ID.
+func PromExemplarsToM3Exemplars(exemplars []prompb.Exemplar) []metric.Exemplar {
+	var result []metric.Exemplar
+	for _, exemplar := range exemplars {
+		var traceID, spanID []byte
+		for _, label := range exemplar.Labels {
+			switch string(label.Name) {
+			case promExemplarTraceIDLabel:
+				traceID = promExemplarID(label.Value)
+			case promExemplarSpanIDLabel:
+				spanID = promExemplarID(label.Value)
+			}
+		}
+		if len(traceID) == 0 {
+			continue
+		}
+		result = append(result, metric.Exemplar{
+			TraceID:   traceID,
+			SpanID:    spanID,
+			Value:     exemplar.Value,
+			TimeNanos: int64(promTimestampToUnixNanos(exemplar.Timestamp)),
+		})
+	}
+	return result
+}
+
+// promExemplarID returns the ID of an exemplar label, which is hex encoded
+// by the tracing libraries but kept as is otherwise.
+func promExemplarID(value []byte) []byte {
+	id := make([]byte, hex.DecodedLen(len(value)))
+	if _, err := hex.Decode(id, value); err != nil {
+		return append([]byte(nil), value...)
+	}
+	return id
+}
+
+// AnnotationWithExemplars returns the annotation payload with the exemplars
+// added so they are stored alongside the datapoints. The annotation is
+// returned unchanged if there are no exemplars or it is not a payload.
+func AnnotationWithExemplars(
+	annotationBytes []byte,
+	exemplars []metric.Exemplar,
+) []byte {
+	if len(exemplars) == 0 {
+		return annotationBytes
+	}
+
+	var payload annotation.Payload
+	if err := payload.Unmarshal(annotationBytes); err != nil {
+		return annotationBytes
+	}
+	payload.Exemplars = make([]*annotation.Exemplar, 0, len(exemplars))
+	for _, exemplar := range exemplars {
+		payload.Exemplars = append(payload.Exemplars, &annotation.Exemplar{
+			TraceId:   exemplar.TraceID,
+			SpanId:    exemplar.SpanID,
+			Value:     exemplar.Value,
+			TimeNanos: exemplar.TimeNanos,
+		})
+	}
+	result, err := payload.Marshal()
+	if err != nil {
+		return annotationBytes
+	}
+	return result
+}
+
 // PromReadQueryToM3 converts a prometheus read query to m3 read query
 func PromReadQueryToM3(query *prompb.Query) (*FetchQuery, error) {
 	tagMatchers, err := PromMatchersToM3(query.Matchers)
@@ -262,6 +338,59 @@ func PromTypeToM3(labelType prompb.LabelMatcher_Type) (models.MatchType, error)
 	}
 }
 
+// M3MatchersToProm converts m3 matchers to prometheus label matchers.
+func M3MatchersToProm(matchers models.Matchers) ([]*prompb.LabelMatcher, error) {
+	labelMatchers := make([]*prompb.LabelMatcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		if matcher.Type == models.MatchAll {
+			// Matches all series, nothing to restrict by.
+			continue
+		}
+
+		labelMatcher, err := M3MatcherToProm(matcher)
+		if err != nil {
+			return nil, err
+		}
+		labelMatchers = append(labelMatchers, labelMatcher)
+	}
+
+	return labelMatchers, nil
+}
+
+// M3MatcherToProm converts an m3 matcher to prometheus label matcher.
+func M3MatcherToProm(matcher models.Matcher) (*prompb.LabelMatcher, error) {
+	var (
+		labelType prompb.LabelMatcher_Type
+		value     = matcher.Value
+	)
+	switch matcher.Type {
+	case models.MatchEqual:
+		labelType = prompb.LabelMatcher_EQ
+	case models.MatchNotEqual:
+		labelType = prompb.LabelMatcher_NEQ
+	case models.MatchRegexp:
+		labelType = prompb.LabelMatcher_RE
+	case models.MatchNotRegexp:
+		labelType = prompb.LabelMatcher_NRE
+	case models.MatchField:
+		// Prometheus has no notion of labels being set, match any non empty value.
+		labelType = prompb.LabelMatcher_RE
+		value = []byte(".+")
+	case models.MatchNotField:
+		labelType = prompb.LabelMatcher_EQ
+		value = nil
+
+	default:
+		return nil, fmt.Errorf("unknown match type: %v", matcher.Type)
+	}
+
+	return &prompb.LabelMatcher{
+		Type:  labelType,
+		Name:  matcher.Name,
+		Value: value,
+	}, nil
+}
+
 // PromTimestampToTime converts a prometheus timestamp to time.Time.
 func PromTimestampToTime(timestampMS int64) time.Time {
 	return promTimestampToUnixNanos(timestampMS).ToTime()
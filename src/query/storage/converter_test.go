@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -176,6 +177,34 @@ func TestPromReadQueryToM3(t *testing.T) {
 	}
 }
 
+func TestM3MatchersToProm(t *testing.T) {
+	matchers := models.Matchers{
+		{Type: models.MatchEqual, Name: name, Value: value},
+		{Type: models.MatchNotEqual, Name: name, Value: value},
+		{Type: models.MatchRegexp, Name: name, Value: value},
+		{Type: models.MatchNotRegexp, Name: name, Value: value},
+		{Type: models.MatchField, Name: name},
+		{Type: models.MatchNotField, Name: name},
+		{Type: models.MatchAll},
+	}
+
+	result, err := M3MatchersToProm(matchers)
+	require.NoError(t, err)
+	assert.Equal(t, []*prompb.LabelMatcher{
+		{Type: prompb.LabelMatcher_EQ, Name: name, Value: value},
+		{Type: prompb.LabelMatcher_NEQ, Name: name, Value: value},
+		{Type: prompb.LabelMatcher_RE, Name: name, Value: value},
+		{Type: prompb.LabelMatcher_NRE, Name: name, Value: value},
+		{Type: prompb.LabelMatcher_RE, Name: name, Value: []byte(".+")},
+		{Type: prompb.LabelMatcher_EQ, Name: name},
+	}, result)
+
+	_, err = M3MatchersToProm(models.Matchers{
+		{Type: models.MatchType(math.MaxInt32), Name: name, Value: value},
+	})
+	assert.Error(t, err)
+}
+
 var (
 	benchResult *prompb.QueryResult
 )
@@ -403,6 +432,56 @@ func TestSeriesAttributesToAnnotationPayload(t *testing.T) {
 	assert.False(t, payload.HandleValueResets)
 }
 
+func TestPromExemplarsToM3Exemplars(t *testing.T) {
+	exemplars := PromExemplarsToM3Exemplars([]prompb.Exemplar{
+		{
+			Labels: []prompb.Label{
+				{Name: []byte("trace_id"), Value: []byte("0a0b")},
+				{Name: []byte("span_id"), Value: []byte("not-hex")},
+				{Name: []byte("foo"), Value: []byte("bar")},
+			},
+			Value:     1.5,
+			Timestamp: 1000,
+		},
+		{
+			Labels: []prompb.Label{{Name: []byte("span_id"), Value: []byte("0c")}},
+			Value:  2,
+		},
+	})
+	assert.Equal(t, []metric.Exemplar{
+		{
+			TraceID:   []byte{0x0a, 0x0b},
+			SpanID:    []byte("not-hex"),
+			Value:     1.5,
+			TimeNanos: int64(time.Second),
+		},
+	}, exemplars)
+
+	assert.Nil(t, PromExemplarsToM3Exemplars(nil))
+}
+
+func TestAnnotationWithExemplars(t *testing.T) {
+	payload := annotation.Payload{MetricType: annotation.MetricType_COUNTER}
+	annotationBytes, err := payload.Marshal()
+	require.NoError(t, err)
+
+	exemplars := []metric.Exemplar{
+		{TraceID: []byte("trace"), SpanID: []byte("span"), Value: 1, TimeNanos: 2},
+	}
+	var result annotation.Payload
+	require.NoError(t, result.Unmarshal(AnnotationWithExemplars(annotationBytes, exemplars)))
+	assert.Equal(t, annotation.Payload{
+		MetricType: annotation.MetricType_COUNTER,
+		Exemplars: []*annotation.Exemplar{
+			{TraceId: []byte("trace"), SpanId: []byte("span"), Value: 1, TimeNanos: 2},
+		},
+	}, result)
+
+	assert.Equal(t, annotationBytes, AnnotationWithExemplars(annotationBytes, nil))
+	notPayload := []byte{0xff}
+	assert.Equal(t, notPayload, AnnotationWithExemplars(notPayload, exemplars))
+}
+
 func TestPromTimestampToTime(t *testing.T) {
 	var (
 		now  = time.Now()
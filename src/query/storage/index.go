@@ -290,7 +290,7 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 			err   error
 		)
 
-		query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
+		query, err = newRegexpQuery(matcher.Name, matcher.Value)
 		if err != nil {
 			return idx.Query{}, err
 		}
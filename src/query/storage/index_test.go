@@ -27,10 +27,12 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/tallytest"
 	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
 )
 
 var (
@@ -255,6 +257,49 @@ func TestFetchQueryToM3Query(t *testing.T) {
 	}
 }
 
+func TestFetchQueryToM3QueryRegexpQueryCache(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+
+	SetRegexpQueryCacheOptions(models.MatcherCacheOptions{Size: 2, Scope: scope})
+	defer SetRegexpQueryCacheOptions(models.MatcherCacheOptions{Size: 0})
+
+	fetchQuery := &FetchQuery{
+		Raw: "up",
+		TagMatchers: models.Matchers{
+			{
+				Type:  models.MatchRegexp,
+				Name:  []byte("t1"),
+				Value: []byte("v.*"),
+			},
+			{
+				Type:  models.MatchNotRegexp,
+				Name:  []byte("t2"),
+				Value: []byte("v.*"),
+			},
+		},
+		Start:    now.Add(-5 * time.Minute),
+		End:      now,
+		Interval: 15 * time.Second,
+	}
+
+	m3Query, err := FetchQueryToM3Query(fetchQuery, nil)
+	require.NoError(t, err)
+	expected := "conjunction(regexp(t1,v.*),negation(regexp(t2,v.*)))"
+	assert.Equal(t, expected, m3Query.String())
+	tallytest.AssertCounterValue(t, 2, scope.Snapshot(),
+		"matcher.regexp-query-cache.miss", nil)
+
+	// Mutating the matchers must not affect the cached queries.
+	fetchQuery.TagMatchers[0].Value[0] = 'x'
+	fetchQuery.TagMatchers[0].Value = []byte("v.*")
+
+	m3Query, err = FetchQueryToM3Query(fetchQuery, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, m3Query.String())
+	tallytest.AssertCounterValue(t, 2, scope.Snapshot(),
+		"matcher.regexp-query-cache.hit", nil)
+}
+
 func TestFetchOptionsToAggregateOptions(t *testing.T) {
 	now := time.Now()
 
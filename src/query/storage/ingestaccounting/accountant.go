@@ -0,0 +1,334 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ingestaccounting
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+// Source identifies where ingested traffic comes from.
+type Source struct {
+	RemoteAddr string `json:"remoteAddr"`
+	UserAgent  string `json:"userAgent"`
+	Tenant     string `json:"tenant"`
+}
+
+// OtherSource is the source the traffic of sources beyond the max number of
+// sources per window is accounted to.
+var OtherSource = Source{RemoteAddr: "other", UserAgent: "other", Tenant: "other"}
+
+// SourceStats is the traffic ingested from a source over a window. Requests,
+// series and datapoints are estimated from the sampled requests. New series
+// are series not written by any source in the current or previous window, and
+// are not scaled since a series written repeatedly is eventually sampled.
+type SourceStats struct {
+	Source
+	Requests   int64 `json:"requests"`
+	Series     int64 `json:"series"`
+	Datapoints int64 `json:"datapoints"`
+	NewSeries  int64 `json:"newSeries"`
+}
+
+// SortBy is the stat sources are ranked by.
+type SortBy string
+
+// A list of supported stats to rank sources by.
+const (
+	SortByDatapoints SortBy = "datapoints"
+	SortByNewSeries  SortBy = "newSeries"
+	SortBySeries     SortBy = "series"
+	SortByRequests   SortBy = "requests"
+)
+
+// ParseSortBy parses the stat to rank sources by.
+func ParseSortBy(str string) (SortBy, error) {
+	for _, valid := range []SortBy{SortByDatapoints, SortByNewSeries, SortBySeries, SortByRequests} {
+		if str == string(valid) {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("invalid sort by %q, must be one of: %s, %s, %s, %s",
+		str, SortByDatapoints, SortByNewSeries, SortBySeries, SortByRequests)
+}
+
+// Query selects the top sources of a window.
+type Query struct {
+	// Previous selects the previous, complete, window instead of the current one.
+	Previous bool
+	// SortBy is the stat sources are ranked by.
+	SortBy SortBy
+	// Limit is the max number of sources returned, all sources are returned
+	// if it is not positive.
+	Limit int
+}
+
+// Report is the traffic ingested by source over a window.
+type Report struct {
+	WindowStart time.Time     `json:"windowStart"`
+	WindowEnd   time.Time     `json:"windowEnd"`
+	SampleRate  float64       `json:"sampleRate"`
+	Sources     []SourceStats `json:"sources"`
+}
+
+// Accountant accounts for the traffic ingested by source.
+type Accountant interface {
+	// Record accounts for a write request of the given series, unless the
+	// request is not sampled.
+	Record(r *http.Request, series []prompb.TimeSeries)
+
+	// TopSources returns the top sources of a window.
+	TopSources(q Query) Report
+}
+
+type accountantMetrics struct {
+	sampled     tally.Counter
+	otherSource tally.Counter
+	seriesFull  tally.Counter
+}
+
+func newAccountantMetrics(scope tally.Scope) accountantMetrics {
+	scope = scope.SubScope("ingest-accounting")
+	return accountantMetrics{
+		sampled:     scope.Counter("sampled"),
+		otherSource: scope.Counter("other-source"),
+		seriesFull:  scope.Counter("tracked-series-full"),
+	}
+}
+
+type window struct {
+	start   time.Time
+	sources map[Source]*SourceStats
+}
+
+func newWindow(start time.Time) window {
+	return window{start: start, sources: make(map[Source]*SourceStats)}
+}
+
+type accountant struct {
+	sync.Mutex
+
+	opts    Options
+	metrics accountantMetrics
+
+	curr       window
+	prev       window
+	currSeries map[uint64]struct{}
+	prevSeries map[uint64]struct{}
+}
+
+// NewAccountant returns a new accountant.
+func NewAccountant(opts Options) Accountant {
+	return &accountant{
+		opts:       opts,
+		metrics:    newAccountantMetrics(opts.scope),
+		curr:       newWindow(opts.nowFn().Truncate(opts.window)),
+		currSeries: make(map[uint64]struct{}),
+		prevSeries: make(map[uint64]struct{}),
+	}
+}
+
+func (a *accountant) Record(r *http.Request, series []prompb.TimeSeries) {
+	if a.opts.sampleRate < 1 && a.opts.randFn() >= a.opts.sampleRate {
+		return
+	}
+	a.metrics.sampled.Inc(1)
+
+	var (
+		source     = a.source(r)
+		datapoints int64
+		hashes     = make([]uint64, 0, len(series))
+	)
+	for _, s := range series {
+		datapoints += int64(len(s.Samples))
+		hashes = append(hashes, seriesHash(s.Labels))
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.rotateWithLock()
+	stats, ok := a.curr.sources[source]
+	if !ok {
+		if len(a.curr.sources) >= a.opts.maxSources {
+			a.metrics.otherSource.Inc(1)
+			source = OtherSource
+			stats, ok = a.curr.sources[source]
+		}
+		if !ok {
+			stats = &SourceStats{Source: source}
+			a.curr.sources[source] = stats
+		}
+	}
+	stats.Requests++
+	stats.Series += int64(len(series))
+	stats.Datapoints += datapoints
+
+	full := false
+	for _, h := range hashes {
+		if _, ok := a.currSeries[h]; ok {
+			continue
+		}
+		_, seen := a.prevSeries[h]
+		if len(a.currSeries) >= a.opts.maxTrackedSeries {
+			full = true
+			continue
+		}
+		a.currSeries[h] = struct{}{}
+		if !seen {
+			stats.NewSeries++
+		}
+	}
+	if full {
+		a.metrics.seriesFull.Inc(1)
+	}
+}
+
+func (a *accountant) TopSources(q Query) Report {
+	a.Lock()
+	a.rotateWithLock()
+	w := a.curr
+	windowEnd := a.opts.nowFn()
+	if q.Previous {
+		w = a.prev
+		windowEnd = a.curr.start
+	}
+	scale := 1 / a.opts.sampleRate
+	sources := make([]SourceStats, 0, len(w.sources))
+	for _, stats := range w.sources {
+		sources = append(sources, SourceStats{
+			Source:     stats.Source,
+			Requests:   scaled(stats.Requests, scale),
+			Series:     scaled(stats.Series, scale),
+			Datapoints: scaled(stats.Datapoints, scale),
+			NewSeries:  stats.NewSeries,
+		})
+	}
+	a.Unlock()
+
+	sort.Slice(sources, func(i, j int) bool {
+		vi, vj := sortValue(sources[i], q.SortBy), sortValue(sources[j], q.SortBy)
+		if vi != vj {
+			return vi > vj
+		}
+		return sourceLess(sources[i].Source, sources[j].Source)
+	})
+	if q.Limit > 0 && len(sources) > q.Limit {
+		sources = sources[:q.Limit]
+	}
+
+	report := Report{
+		WindowEnd:  windowEnd,
+		SampleRate: a.opts.sampleRate,
+		Sources:    sources,
+	}
+	if !w.start.IsZero() {
+		report.WindowStart = w.start
+	} else {
+		report.WindowStart = windowEnd.Add(-a.opts.window)
+	}
+	return report
+}
+
+// rotateWithLock starts a new window once the current one is over. The series
+// written in the previous window are remembered so they are not counted as
+// new series again right after the rotation.
+func (a *accountant) rotateWithLock() {
+	start := a.opts.nowFn().Truncate(a.opts.window)
+	if !start.After(a.curr.start) {
+		return
+	}
+	if start.Sub(a.curr.start) == a.opts.window {
+		a.prev = a.curr
+		a.prevSeries = a.currSeries
+	} else {
+		// NB: no traffic was sampled during the previous window.
+		a.prev = newWindow(start.Add(-a.opts.window))
+		a.prevSeries = make(map[uint64]struct{})
+	}
+	a.curr = newWindow(start)
+	a.currSeries = make(map[uint64]struct{}, len(a.prevSeries))
+}
+
+func (a *accountant) source(r *http.Request) Source {
+	addr := r.RemoteAddr
+	if header := a.opts.remoteAddrHeader; header != "" {
+		if value := r.Header.Get(header); value != "" {
+			addr = strings.TrimSpace(strings.SplitN(value, ",", 2)[0])
+		}
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	source := Source{RemoteAddr: addr, UserAgent: r.UserAgent()}
+	if header := a.opts.tenantHeader; header != "" {
+		source.Tenant = r.Header.Get(header)
+	}
+	return source
+}
+
+func seriesHash(labels []prompb.Label) uint64 {
+	d := xxhash.New()
+	for _, l := range labels {
+		_, _ = d.Write(l.Name)
+		_, _ = d.Write([]byte{0})
+		_, _ = d.Write(l.Value)
+		_, _ = d.Write([]byte{0})
+	}
+	return d.Sum64()
+}
+
+func scaled(v int64, scale float64) int64 {
+	return int64(math.Round(float64(v) * scale))
+}
+
+func sortValue(s SourceStats, sortBy SortBy) int64 {
+	switch sortBy {
+	case SortByNewSeries:
+		return s.NewSeries
+	case SortBySeries:
+		return s.Series
+	case SortByRequests:
+		return s.Requests
+	default:
+		return s.Datapoints
+	}
+}
+
+func sourceLess(a, b Source) bool {
+	if a.RemoteAddr != b.RemoteAddr {
+		return a.RemoteAddr < b.RemoteAddr
+	}
+	if a.UserAgent != b.UserAgent {
+		return a.UserAgent < b.UserAgent
+	}
+	return a.Tenant < b.Tenant
+}
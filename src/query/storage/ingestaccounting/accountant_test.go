@@ -0,0 +1,244 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package ingestaccounting
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestAccountant(
+	t *testing.T,
+	cfg config.IngestAccountingConfiguration,
+) (*accountant, *testClock) {
+	opts, err := NewOptions(&cfg, tally.NoopScope)
+	require.NoError(t, err)
+	clock := &testClock{now: time.Unix(3600*100, 0)}
+	opts.nowFn = clock.Now
+	return NewAccountant(opts).(*accountant), clock
+}
+
+func newTestRequest(remoteAddr, userAgent string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/prom/remote/write", nil)
+	r.RemoteAddr = remoteAddr
+	r.Header.Set("User-Agent", userAgent)
+	return r
+}
+
+func newTestSeries(names ...string) []prompb.TimeSeries {
+	series := make([]prompb.TimeSeries, 0, len(names))
+	for _, name := range names {
+		series = append(series, prompb.TimeSeries{
+			Labels: []prompb.Label{
+				{Name: []byte("__name__"), Value: []byte(name)},
+			},
+			Samples: []prompb.Sample{{Value: 1}, {Value: 2}},
+		})
+	}
+	return series
+}
+
+func TestNewOptionsValidatesConfiguration(t *testing.T) {
+	var (
+		zeroRate   = 0.0
+		zeroWindow = time.Duration(0)
+		zero       = 0
+		negative   = -1
+	)
+	for _, cfg := range []*config.IngestAccountingConfiguration{
+		nil,
+		{SampleRate: &zeroRate},
+		{Window: &zeroWindow},
+		{MaxSources: &zero},
+		{MaxTrackedSeries: &negative},
+	} {
+		_, err := NewOptions(cfg, tally.NoopScope)
+		assert.Error(t, err)
+	}
+}
+
+func TestAccountantTopSources(t *testing.T) {
+	a, _ := newTestAccountant(t, config.IngestAccountingConfiguration{
+		TenantHeader: "M3-Tenant",
+	})
+
+	r := newTestRequest("10.0.0.1:1234", "prometheus/2.26")
+	r.Header.Set("M3-Tenant", "team-a")
+	a.Record(r, newTestSeries("a", "b", "c"))
+	a.Record(r, newTestSeries("a", "b", "c"))
+	a.Record(newTestRequest("10.0.0.2:1234", "vmagent"), newTestSeries("a", "d"))
+
+	report := a.TopSources(Query{SortBy: SortByDatapoints})
+	require.Len(t, report.Sources, 2)
+	assert.Equal(t, SourceStats{
+		Source: Source{
+			RemoteAddr: "10.0.0.1",
+			UserAgent:  "prometheus/2.26",
+			Tenant:     "team-a",
+		},
+		Requests:   2,
+		Series:     6,
+		Datapoints: 12,
+		NewSeries:  3,
+	}, report.Sources[0])
+	assert.Equal(t, SourceStats{
+		Source: Source{
+			RemoteAddr: "10.0.0.2",
+			UserAgent:  "vmagent",
+		},
+		Requests:   1,
+		Series:     2,
+		Datapoints: 4,
+		NewSeries:  1,
+	}, report.Sources[1])
+
+	report = a.TopSources(Query{SortBy: SortByDatapoints, Limit: 1})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, "10.0.0.1", report.Sources[0].RemoteAddr)
+}
+
+func TestAccountantRemoteAddrHeader(t *testing.T) {
+	a, _ := newTestAccountant(t, config.IngestAccountingConfiguration{
+		RemoteAddrHeader: "X-Forwarded-For",
+	})
+
+	r := newTestRequest("10.0.0.1:1234", "prometheus")
+	r.Header.Set("X-Forwarded-For", "192.168.0.1, 10.0.0.1")
+	a.Record(r, newTestSeries("a"))
+	a.Record(newTestRequest("10.0.0.1:1234", "prometheus"), newTestSeries("a"))
+
+	report := a.TopSources(Query{SortBy: SortByRequests})
+	require.Len(t, report.Sources, 2)
+	assert.Equal(t, "10.0.0.1", report.Sources[0].RemoteAddr)
+	assert.Equal(t, "192.168.0.1", report.Sources[1].RemoteAddr)
+}
+
+func TestAccountantWindowRotation(t *testing.T) {
+	a, clock := newTestAccountant(t, config.IngestAccountingConfiguration{})
+	r := newTestRequest("10.0.0.1:1234", "prometheus")
+
+	a.Record(r, newTestSeries("a", "b"))
+	clock.now = clock.now.Add(time.Hour)
+	a.Record(r, newTestSeries("a", "b", "c"))
+
+	report := a.TopSources(Query{SortBy: SortByNewSeries})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, clock.now, report.WindowStart)
+	// Series written in the previous window are not new.
+	assert.Equal(t, int64(1), report.Sources[0].NewSeries)
+	assert.Equal(t, int64(3), report.Sources[0].Series)
+
+	report = a.TopSources(Query{SortBy: SortByNewSeries, Previous: true})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, clock.now.Add(-time.Hour), report.WindowStart)
+	assert.Equal(t, clock.now, report.WindowEnd)
+	assert.Equal(t, int64(2), report.Sources[0].NewSeries)
+
+	// Skipping a window drops both the previous stats and the seen series.
+	clock.now = clock.now.Add(2 * time.Hour)
+	assert.Empty(t, a.TopSources(Query{Previous: true}).Sources)
+	a.Record(r, newTestSeries("a"))
+	assert.Equal(t, int64(1), a.TopSources(Query{}).Sources[0].NewSeries)
+}
+
+func TestAccountantMaxSources(t *testing.T) {
+	maxSources := 2
+	a, _ := newTestAccountant(t, config.IngestAccountingConfiguration{
+		MaxSources: &maxSources,
+	})
+
+	for i := 0; i < 4; i++ {
+		r := newTestRequest(fmt.Sprintf("10.0.0.%d:1234", i), "prometheus")
+		a.Record(r, newTestSeries(fmt.Sprintf("series-%d", i)))
+	}
+
+	report := a.TopSources(Query{SortBy: SortByRequests})
+	require.Len(t, report.Sources, 3)
+	assert.Equal(t, OtherSource, report.Sources[0].Source)
+	assert.Equal(t, int64(2), report.Sources[0].Requests)
+}
+
+func TestAccountantMaxTrackedSeries(t *testing.T) {
+	maxTrackedSeries := 2
+	a, _ := newTestAccountant(t, config.IngestAccountingConfiguration{
+		MaxTrackedSeries: &maxTrackedSeries,
+	})
+	r := newTestRequest("10.0.0.1:1234", "prometheus")
+
+	a.Record(r, newTestSeries("a", "b", "c"))
+	a.Record(r, newTestSeries("c"))
+
+	report := a.TopSources(Query{})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, int64(2), report.Sources[0].NewSeries)
+	assert.Equal(t, int64(4), report.Sources[0].Series)
+}
+
+func TestAccountantSampling(t *testing.T) {
+	sampleRate := 0.5
+	a, _ := newTestAccountant(t, config.IngestAccountingConfiguration{
+		SampleRate: &sampleRate,
+	})
+	rands := []float64{0.1, 0.7, 0.3, 0.9}
+	a.opts.randFn = func() float64 {
+		v := rands[0]
+		rands = rands[1:]
+		return v
+	}
+	r := newTestRequest("10.0.0.1:1234", "prometheus")
+
+	for i := 0; i < 4; i++ {
+		a.Record(r, newTestSeries("a"))
+	}
+
+	report := a.TopSources(Query{})
+	require.Len(t, report.Sources, 1)
+	assert.Equal(t, 0.5, report.SampleRate)
+	assert.Equal(t, int64(4), report.Sources[0].Requests)
+	assert.Equal(t, int64(8), report.Sources[0].Datapoints)
+	assert.Equal(t, int64(1), report.Sources[0].NewSeries)
+}
+
+func TestParseSortBy(t *testing.T) {
+	sortBy, err := ParseSortBy("newSeries")
+	require.NoError(t, err)
+	assert.Equal(t, SortByNewSeries, sortBy)
+
+	_, err = ParseSortBy("bytes")
+	assert.Error(t, err)
+}
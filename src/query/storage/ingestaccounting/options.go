@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package ingestaccounting accounts for the series and datapoints ingested
+// through Prometheus remote write by source, for capacity chargeback and to
+// find the sources of abusive traffic.
+package ingestaccounting
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/x/clock"
+)
+
+const (
+	defaultSampleRate       = 1.0
+	defaultWindow           = time.Hour
+	defaultMaxSources       = 1000
+	defaultMaxTrackedSeries = 1000000
+)
+
+// Options for the accountant.
+type Options struct {
+	sampleRate       float64
+	window           time.Duration
+	maxSources       int
+	maxTrackedSeries int
+	tenantHeader     string
+	remoteAddrHeader string
+	nowFn            clock.NowFn
+	randFn           func() float64
+	scope            tally.Scope
+}
+
+// NewOptions constructs Options based on the given config.
+func NewOptions(
+	cfg *config.IngestAccountingConfiguration,
+	scope tally.Scope,
+) (Options, error) {
+	if err := validateConfiguration(cfg); err != nil {
+		return Options{}, err
+	}
+
+	opts := Options{
+		sampleRate:       defaultSampleRate,
+		window:           defaultWindow,
+		maxSources:       defaultMaxSources,
+		maxTrackedSeries: defaultMaxTrackedSeries,
+		tenantHeader:     cfg.TenantHeader,
+		remoteAddrHeader: cfg.RemoteAddrHeader,
+		nowFn:            time.Now,
+		randFn:           rand.Float64,
+		scope:            scope,
+	}
+	if cfg.SampleRate != nil {
+		opts.sampleRate = *cfg.SampleRate
+	}
+	if cfg.Window != nil {
+		opts.window = *cfg.Window
+	}
+	if cfg.MaxSources != nil {
+		opts.maxSources = *cfg.MaxSources
+	}
+	if cfg.MaxTrackedSeries != nil {
+		opts.maxTrackedSeries = *cfg.MaxTrackedSeries
+	}
+	return opts, nil
+}
+
+func validateConfiguration(cfg *config.IngestAccountingConfiguration) error {
+	if cfg == nil {
+		return errors.New("ingestAccounting configuration is required")
+	}
+	if cfg.SampleRate != nil && (*cfg.SampleRate <= 0 || *cfg.SampleRate > 1) {
+		return errors.New("sampleRate must be in (0, 1]")
+	}
+	if cfg.Window != nil && *cfg.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+	if cfg.MaxSources != nil && *cfg.MaxSources <= 0 {
+		return errors.New("maxSources must be positive")
+	}
+	if cfg.MaxTrackedSeries != nil && *cfg.MaxTrackedSeries < 0 {
+		return errors.New("maxTrackedSeries must not be negative")
+	}
+	return nil
+}
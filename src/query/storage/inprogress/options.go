@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package inprogress implements a storage that merges the values aggregators have
+// aggregated so far into the results of queries for recent data.
+package inprogress
+
+import (
+	"errors"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/x/clock"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	defaultWindow         = 5 * time.Minute
+	defaultRequestTimeout = time.Second
+)
+
+// Options for storage.
+type Options struct {
+	endpoints   []string
+	window      time.Duration
+	httpOptions xhttp.HTTPClientOptions
+	nowFn       clock.NowFn
+	scope       tally.Scope
+	logger      *zap.Logger
+}
+
+// NewOptions constructs Options based on the given config.
+func NewOptions(
+	cfg *config.ReadYourWritesConfiguration,
+	scope tally.Scope,
+	logger *zap.Logger,
+) (Options, error) {
+	if err := validateConfiguration(cfg); err != nil {
+		return Options{}, err
+	}
+
+	window := defaultWindow
+	if cfg.Window != nil {
+		window = *cfg.Window
+	}
+	clientOpts := xhttp.DefaultHTTPClientOptions()
+	clientOpts.RequestTimeout = defaultRequestTimeout
+	if cfg.RequestTimeout != nil {
+		clientOpts.RequestTimeout = *cfg.RequestTimeout
+	}
+
+	return Options{
+		endpoints:   cfg.Endpoints,
+		window:      window,
+		httpOptions: clientOpts,
+		nowFn:       time.Now,
+		scope:       scope,
+		logger:      logger,
+	}, nil
+}
+
+func validateConfiguration(cfg *config.ReadYourWritesConfiguration) error {
+	if cfg == nil {
+		return errors.New("readYourWrites configuration is required")
+	}
+	if len(cfg.Endpoints) == 0 {
+		return errors.New("at least one aggregator endpoint must be configured for readYourWrites")
+	}
+	if cfg.Window != nil && *cfg.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+	if cfg.RequestTimeout != nil && *cfg.RequestTimeout < 0 {
+		return errors.New("requestTimeout can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package inprogress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+)
+
+func TestNewOptions(t *testing.T) {
+	logger := zap.NewNop()
+	opts, err := NewOptions(&config.ReadYourWritesConfiguration{
+		Enabled:        true,
+		Endpoints:      []string{"http://m3aggregator01:6001"},
+		Window:         ptrDuration(time.Minute),
+		RequestTimeout: ptrDuration(time.Millisecond),
+	}, tally.NoopScope, logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"http://m3aggregator01:6001"}, opts.endpoints)
+	assert.Equal(t, time.Minute, opts.window)
+	assert.Equal(t, time.Millisecond, opts.httpOptions.RequestTimeout)
+	assert.Equal(t, tally.NoopScope, opts.scope)
+	assert.Equal(t, logger, opts.logger)
+
+	opts, err = NewOptions(&config.ReadYourWritesConfiguration{
+		Endpoints: []string{"http://m3aggregator01:6001"},
+	}, tally.NoopScope, logger)
+	require.NoError(t, err)
+	assert.Equal(t, defaultWindow, opts.window)
+	assert.Equal(t, defaultRequestTimeout, opts.httpOptions.RequestTimeout)
+}
+
+func TestNewOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.ReadYourWritesConfiguration
+	}{
+		{
+			name: "nil configuration",
+		},
+		{
+			name: "no endpoints",
+			cfg:  &config.ReadYourWritesConfiguration{},
+		},
+		{
+			name: "zero window",
+			cfg: &config.ReadYourWritesConfiguration{
+				Endpoints: []string{"http://m3aggregator01:6001"},
+				Window:    ptrDuration(0),
+			},
+		},
+		{
+			name: "negative request timeout",
+			cfg: &config.ReadYourWritesConfiguration{
+				Endpoints:      []string{"http://m3aggregator01:6001"},
+				RequestTimeout: ptrDuration(-time.Second),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOptions(tt.cfg, tally.NoopScope, zap.NewNop())
+			require.Error(t, err)
+		})
+	}
+}
+
+func ptrDuration(d time.Duration) *time.Duration {
+	return &d
+}
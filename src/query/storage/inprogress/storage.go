@@ -0,0 +1,330 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package inprogress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	aggserver "github.com/m3db/m3/src/aggregator/server/http"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+)
+
+const (
+	metricsScope = "in_progress_storage"
+
+	fetchInProgressWarningName  = "aggregators"
+	fetchInProgressWarningError = "fetch_in_progress_error"
+)
+
+type inProgressStorage struct {
+	storage.Storage
+
+	opts           Options
+	client         *http.Client
+	tagEncoderPool serialize.TagEncoderPool
+	metrics        inProgressMetrics
+	logger         *zap.Logger
+}
+
+type inProgressMetrics struct {
+	fetches       tally.Counter
+	fetchErrors   tally.Counter
+	mergedSamples tally.Counter
+	fetchLatency  tally.Timer
+}
+
+func newInProgressMetrics(scope tally.Scope) inProgressMetrics {
+	return inProgressMetrics{
+		fetches:       scope.Counter("fetches"),
+		fetchErrors:   scope.Counter("fetch_errors"),
+		mergedSamples: scope.Counter("merged_samples"),
+		fetchLatency:  scope.Timer("fetch_latency"),
+	}
+}
+
+// NewStorage returns a storage that merges the values the aggregators have
+// aggregated so far into the results of Prometheus queries ending within the
+// configured window of now, so that such queries do not show a trailing gap
+// equal to the aggregation window. All other operations are served by the
+// given storage as is.
+func NewStorage(store storage.Storage, opts Options) storage.Storage {
+	tagEncoderPool := serialize.NewTagEncoderPool(serialize.NewTagEncoderOptions(),
+		pool.NewObjectPoolOptions())
+	tagEncoderPool.Init()
+	return &inProgressStorage{
+		Storage:        store,
+		opts:           opts,
+		client:         xhttp.NewHTTPClient(opts.httpOptions),
+		tagEncoderPool: tagEncoderPool,
+		metrics:        newInProgressMetrics(opts.scope.SubScope(metricsScope)),
+		logger:         opts.logger,
+	}
+}
+
+func (s *inProgressStorage) FetchProm(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (storage.PromResult, error) {
+	result, err := s.Storage.FetchProm(ctx, query, options)
+	if err != nil || result.PromResult == nil || len(result.PromResult.Timeseries) == 0 {
+		return result, err
+	}
+	if query.End.Before(s.opts.nowFn().Add(-s.opts.window)) {
+		return result, nil
+	}
+
+	s.metrics.fetches.Inc(1)
+	if err := s.mergeInProgress(ctx, query, result.PromResult.Timeseries); err != nil {
+		// In-progress values are best effort, the results from storage are still valid.
+		s.metrics.fetchErrors.Inc(1)
+		s.logger.Warn("partial results: unable to fetch in-progress values from aggregators",
+			zap.Error(err))
+		result.Metadata.AddWarning(fetchInProgressWarningName, fetchInProgressWarningError)
+	}
+	return result, nil
+}
+
+func (s *inProgressStorage) Close() error {
+	s.client.CloseIdleConnections()
+	return s.Storage.Close()
+}
+
+func (s *inProgressStorage) mergeInProgress(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	series []*prompb.TimeSeries,
+) error {
+	ids, err := s.seriesIDs(series)
+	if err != nil {
+		return err
+	}
+
+	// Values from the aggregators that could be reached are merged regardless.
+	values, fetchErr := s.fetchInProgress(ctx, ids)
+	for i, ts := range series {
+		datapoints, ok := values[string(ids[i])]
+		if !ok {
+			continue
+		}
+		merged := appendInProgress(ts, datapoints, query.Start, query.End)
+		s.metrics.mergedSamples.Inc(int64(merged))
+	}
+	return fetchErr
+}
+
+// seriesIDs returns the IDs the aggregators know the series by, which are the
+// series tags sorted by name and encoded.
+func (s *inProgressStorage) seriesIDs(series []*prompb.TimeSeries) ([][]byte, error) {
+	encoder := s.tagEncoderPool.Get()
+	defer encoder.Finalize()
+
+	ids := make([][]byte, 0, len(series))
+	for _, ts := range series {
+		labels := make([]prompb.Label, len(ts.Labels))
+		copy(labels, ts.Labels)
+		sort.Slice(labels, func(i, j int) bool {
+			return bytes.Compare(labels[i].Name, labels[j].Name) < 0
+		})
+
+		tags := make([]ident.Tag, 0, len(labels))
+		for _, label := range labels {
+			tags = append(tags, ident.Tag{
+				Name:  ident.BytesID(label.Name),
+				Value: ident.BytesID(label.Value),
+			})
+		}
+
+		encoder.Reset()
+		if err := encoder.Encode(ident.NewTagsIterator(ident.NewTags(tags...))); err != nil {
+			return nil, err
+		}
+		data, ok := encoder.Data()
+		if !ok {
+			return nil, fmt.Errorf("unable to encode tags: %v", tags)
+		}
+		ids = append(ids, append([]byte(nil), data.Bytes()...))
+	}
+	return ids, nil
+}
+
+// fetchInProgress queries all aggregators for the in-progress values of the given
+// series. Each aggregator only returns values for the series it owns, and replicas
+// of the same shard return the same values.
+func (s *inProgressStorage) fetchInProgress(
+	ctx context.Context,
+	ids [][]byte,
+) (map[string][]aggregator.InProgressDatapoint, error) {
+	body, err := json.Marshal(aggserver.InProgressRequest{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		start    = s.opts.nowFn()
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		multiErr xerrors.MultiError
+		results  = make(map[string][]aggregator.InProgressDatapoint, len(ids))
+	)
+	for _, endpoint := range s.opts.endpoints {
+		endpoint := endpoint
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			series, err := s.fetchSingle(ctx, endpoint, body)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				return
+			}
+			for _, elem := range series {
+				id := string(elem.ID)
+				results[id] = append(results[id], elem.Datapoints...)
+			}
+		}()
+	}
+	wg.Wait()
+	s.metrics.fetchLatency.Record(s.opts.nowFn().Sub(start))
+
+	return results, multiErr.FinalError()
+}
+
+func (s *inProgressStorage) fetchSingle(
+	ctx context.Context,
+	endpoint string,
+	body []byte,
+) ([]aggserver.InProgressSeries, error) {
+	address := strings.TrimSuffix(endpoint, "/") + aggserver.InProgressPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		response, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("expected status code 2XX: actual=%v, address=%v, resp=%s",
+			resp.StatusCode, address, response)
+	}
+
+	var response aggserver.InProgressResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response.Series, nil
+}
+
+// appendInProgress appends the in-progress values of the finest resolution, and
+// of the longest retention among those, to the samples of the series that are
+// more recent than the samples from storage, and returns the number of samples
+// appended.
+func appendInProgress(
+	ts *prompb.TimeSeries,
+	datapoints []aggregator.InProgressDatapoint,
+	start, end time.Time,
+) int {
+	// NB: values of policies with the same resolution but different retentions
+	// would share timestamps, so only the values of a single policy are used.
+	storagePolicy := datapoints[0].StoragePolicy
+	for _, dp := range datapoints[1:] {
+		candidates := policy.ByResolutionAscRetentionDesc{dp.StoragePolicy, storagePolicy}
+		if candidates.Less(0, 1) {
+			storagePolicy = dp.StoragePolicy
+		}
+	}
+
+	var (
+		startNanos = start.UnixNano()
+		endNanos   = end.UnixNano()
+		lastMillis = int64(-1)
+		resolution = storagePolicy.Resolution().Window
+		byTime     = make(map[int64]float64, len(datapoints))
+	)
+	if n := len(ts.Samples); n > 0 {
+		lastMillis = ts.Samples[n-1].Timestamp
+	}
+	for _, dp := range datapoints {
+		if !dp.StoragePolicy.Equivalent(storagePolicy) {
+			continue
+		}
+		timeNanos := dp.TimeNanos
+		// The aggregation window that is still open when the query ends is only
+		// flushed after the end of the query, report its value so far at the end.
+		if timeNanos > endNanos && timeNanos-resolution.Nanoseconds() < endNanos {
+			timeNanos = endNanos
+		}
+		if timeNanos < startNanos || timeNanos > endNanos {
+			continue
+		}
+		// Replicas of the same shard report the same values.
+		byTime[timeNanos] = dp.Value
+	}
+
+	times := make([]int64, 0, len(byTime))
+	for timeNanos := range byTime {
+		times = append(times, timeNanos)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	appended := 0
+	for _, timeNanos := range times {
+		timestamp := timeNanos / int64(time.Millisecond)
+		if timestamp <= lastMillis {
+			continue
+		}
+		ts.Samples = append(ts.Samples, prompb.Sample{
+			Value:     byTime[timeNanos],
+			Timestamp: timestamp,
+		})
+		lastMillis = timestamp
+		appended++
+	}
+	return appended
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package inprogress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+	aggserver "github.com/m3db/m3/src/aggregator/server/http"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/pool"
+	"github.com/m3db/m3/src/x/serialize"
+)
+
+var (
+	testStoragePolicy = policy.MustParseStoragePolicy("1m:2d")
+	testCoarserPolicy = policy.MustParseStoragePolicy("10m:30d")
+)
+
+func TestFetchPromMergesInProgressValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		windowEnd = time.Unix(0, 0).Add(100 * time.Hour)
+		now       = windowEnd.Add(30 * time.Second)
+		query     = &storage.FetchQuery{Start: now.Add(-10 * time.Minute), End: now}
+	)
+
+	// Both replicas of the shard own the series and report the same values,
+	// the coarser resolution is ignored.
+	datapoints := []aggregator.InProgressDatapoint{
+		{TimeNanos: windowEnd.UnixNano(), Value: 1, StoragePolicy: testStoragePolicy},
+		{TimeNanos: windowEnd.Add(time.Minute).UnixNano(), Value: 2, StoragePolicy: testStoragePolicy},
+		{TimeNanos: windowEnd.Add(10 * time.Minute).UnixNano(), Value: 3, StoragePolicy: testCoarserPolicy},
+	}
+	leader := newTestAggregatorServer(t, map[string]string{"__name__": "foo", "bar": "baz"}, datapoints)
+	defer leader.Close()
+	follower := newTestAggregatorServer(t, map[string]string{"__name__": "foo", "bar": "baz"}, datapoints)
+	defer follower.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer unavailable.Close()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchProm(gomock.Any(), query, gomock.Any()).Return(storage.PromResult{
+		PromResult: &prompb.QueryResult{
+			Timeseries: []*prompb.TimeSeries{
+				{
+					Labels: []prompb.Label{
+						{Name: []byte("bar"), Value: []byte("baz")},
+						{Name: []byte("__name__"), Value: []byte("foo")},
+					},
+					Samples: []prompb.Sample{
+						{Value: 0, Timestamp: toMillis(windowEnd.Add(-time.Minute))},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	s := newTestStorage(t, store, now, leader.URL, follower.URL, unavailable.URL)
+	result, err := s.FetchProm(context.Background(), query, storage.NewFetchOptions())
+	require.NoError(t, err)
+
+	// The value of the window still open is reported at the end of the query.
+	require.Equal(t, []prompb.Sample{
+		{Value: 0, Timestamp: toMillis(windowEnd.Add(-time.Minute))},
+		{Value: 1, Timestamp: toMillis(windowEnd)},
+		{Value: 2, Timestamp: toMillis(now)},
+	}, result.PromResult.Timeseries[0].Samples)
+	require.Equal(t, block.Warnings{{
+		Name:    fetchInProgressWarningName,
+		Message: fetchInProgressWarningError,
+	}}, result.Metadata.Warnings)
+}
+
+func TestAppendInProgressUsesSinglePolicy(t *testing.T) {
+	var (
+		start         = time.Unix(0, 0).Add(100 * time.Hour)
+		end           = start.Add(10 * time.Minute)
+		shortRetained = policy.MustParseStoragePolicy("1m:2d")
+		longRetained  = policy.MustParseStoragePolicy("1m:30d")
+		ts            = &prompb.TimeSeries{}
+	)
+
+	// Policies with the same resolution but different retentions report values
+	// at the same times, only those of the longest retention are used.
+	datapoints := []aggregator.InProgressDatapoint{
+		{TimeNanos: start.Add(time.Minute).UnixNano(), Value: 1, StoragePolicy: shortRetained},
+		{TimeNanos: start.Add(time.Minute).UnixNano(), Value: 2, StoragePolicy: longRetained},
+		{TimeNanos: start.Add(2 * time.Minute).UnixNano(), Value: 3, StoragePolicy: longRetained},
+		{TimeNanos: start.Add(2 * time.Minute).UnixNano(), Value: 4, StoragePolicy: shortRetained},
+	}
+	require.Equal(t, 2, appendInProgress(ts, datapoints, start, end))
+	require.Equal(t, []prompb.Sample{
+		{Value: 2, Timestamp: toMillis(start.Add(time.Minute))},
+		{Value: 3, Timestamp: toMillis(start.Add(2 * time.Minute))},
+	}, ts.Samples)
+}
+
+func TestFetchPromSkipsOldQueries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		now   = time.Unix(0, 0).Add(100 * time.Hour)
+		query = &storage.FetchQuery{Start: now.Add(-time.Hour), End: now.Add(-30 * time.Minute)}
+		calls atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Inc()
+	}))
+	defer server.Close()
+
+	expected := storage.PromResult{
+		PromResult: &prompb.QueryResult{
+			Timeseries: []*prompb.TimeSeries{
+				{
+					Labels:  []prompb.Label{{Name: []byte("__name__"), Value: []byte("foo")}},
+					Samples: []prompb.Sample{{Value: 1, Timestamp: toMillis(query.End)}},
+				},
+			},
+		},
+	}
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchProm(gomock.Any(), query, gomock.Any()).Return(expected, nil)
+
+	s := newTestStorage(t, store, now, server.URL)
+	result, err := s.FetchProm(context.Background(), query, storage.NewFetchOptions())
+	require.NoError(t, err)
+	require.Equal(t, expected, result)
+	require.Equal(t, int32(0), calls.Load())
+}
+
+func newTestStorage(
+	t *testing.T,
+	store storage.Storage,
+	now time.Time,
+	endpoints ...string,
+) storage.Storage {
+	opts, err := NewOptions(&config.ReadYourWritesConfiguration{
+		Enabled:   true,
+		Endpoints: endpoints,
+	}, tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	opts.nowFn = func() time.Time { return now }
+	return NewStorage(store, opts)
+}
+
+// newTestAggregatorServer returns an aggregator server that owns the series with
+// the given tags and reports the given datapoints for it.
+func newTestAggregatorServer(
+	t *testing.T,
+	tags map[string]string,
+	datapoints []aggregator.InProgressDatapoint,
+) *httptest.Server {
+	decoderPool := serialize.NewTagDecoderPool(
+		serialize.NewTagDecoderOptions(serialize.TagDecoderOptionsConfig{}),
+		pool.NewObjectPoolOptions().SetSize(1))
+	decoderPool.Init()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, aggserver.InProgressPath, r.URL.Path)
+
+		var req aggserver.InProgressRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		response := aggserver.NewInProgressResponse()
+		for _, id := range req.IDs {
+			it := serialize.NewMetricTagsIterator(decoderPool.Get(), nil)
+			it.Reset(id)
+			var (
+				decoded  = make(map[string]string)
+				prevName string
+			)
+			for it.Next() {
+				name, value := it.Current()
+				// Tags are encoded sorted by name like the coordinator does when
+				// sending metrics to the aggregators.
+				require.True(t, prevName < string(name))
+				prevName = string(name)
+				decoded[string(name)] = string(value)
+			}
+			require.NoError(t, it.Err())
+			require.Equal(t, tags, decoded)
+			response.Series = append(response.Series, aggserver.InProgressSeries{
+				ID:         id,
+				Datapoints: datapoints,
+			})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -99,8 +99,9 @@ type ClusterNamespace interface {
 type ClusterNamespaceOptions struct {
 	// Note: Don't allow direct access, as we want to provide defaults
 	// and/or error if call to access a field is not relevant/correct.
-	attributes storagemetadata.Attributes
-	downsample *ClusterNamespaceDownsampleOptions
+	attributes     storagemetadata.Attributes
+	downsample     *ClusterNamespaceDownsampleOptions
+	fanoutDisabled bool
 }
 
 // NewClusterNamespaceOptions creates new cluster namespace options.
@@ -134,6 +135,13 @@ func (o ClusterNamespaceOptions) DownsampleOptions() (
 	return *o.downsample, nil
 }
 
+// FanoutDisabled returns whether the cluster namespace is excluded from
+// automatic query fanout, it can still be queried by explicitly restricting
+// a query to it.
+func (o ClusterNamespaceOptions) FanoutDisabled() bool {
+	return o.fanoutDisabled
+}
+
 // ClusterNamespaceDownsampleOptions is the downsample options for
 // a cluster namespace.
 type ClusterNamespaceDownsampleOptions struct {
@@ -206,6 +214,9 @@ type AggregatedClusterNamespaceDefinition struct {
 	Retention   time.Duration
 	Resolution  time.Duration
 	Downsample  *ClusterNamespaceDownsampleOptions
+	// FanoutDisabled excludes the namespace from automatic query fanout
+	// without removing it, e.g. while the namespace is being backfilled.
+	FanoutDisabled bool
 }
 
 // Validate validates the cluster namespace definition.
@@ -385,7 +396,8 @@ func newAggregatedClusterNamespace(
 				Retention:   def.Retention,
 				Resolution:  def.Resolution,
 			},
-			downsample: def.Downsample,
+			downsample:     def.Downsample,
+			fanoutDisabled: def.FanoutDisabled,
 		},
 		session: def.Session,
 	}, nil
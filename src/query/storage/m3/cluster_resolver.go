@@ -295,6 +295,9 @@ func (slices reusedAggregatedNamespaceSlices) reset(
 // optimization is disabled, or if none of the aggregated namespaces are
 // guaranteed to have a complete set of all metrics, they are added to the
 // partialAggregated list.
+//
+// NB: Namespaces with fanout disabled are never considered, they can only be
+// queried by explicitly restricting the query to them.
 func aggregatedNamespaces(
 	all ClusterNamespaces,
 	slices reusedAggregatedNamespaceSlices,
@@ -314,6 +317,11 @@ func aggregatedNamespaces(
 			continue
 		}
 
+		if nsOpts.FanoutDisabled() {
+			// Excluded from automatic fanout, e.g. while being backfilled.
+			continue
+		}
+
 		if filter != nil && !filter(namespace) {
 			// Fails to satisfy filter.
 			continue
@@ -580,6 +580,54 @@ func TestExampleCase(t *testing.T) {
 	}
 }
 
+func TestFanoutDisabledNamespacesExcluded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := client.NewMockSession(ctrl)
+	ns, err := NewClusters(UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Retention:   24 * time.Hour,
+		Session:     session,
+	}, AggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("aggregated_1m_360h"),
+		Retention:   360 * time.Hour,
+		Resolution:  time.Minute,
+		Downsample:  &ClusterNamespaceDownsampleOptions{All: true},
+		Session:     session,
+	}, AggregatedClusterNamespaceDefinition{
+		NamespaceID:    ident.StringID("backfilling_1m_720h"),
+		Retention:      720 * time.Hour,
+		Resolution:     time.Minute,
+		Downsample:     &ClusterNamespaceDownsampleOptions{All: true},
+		FanoutDisabled: true,
+		Session:        session,
+	})
+	require.NoError(t, err)
+
+	now := xtime.Now()
+	start := now.Add(-48 * time.Hour)
+	fanoutType, clusters, err := resolveClusterNamespacesForQuery(now,
+		start, now, ns, &storage.FanoutOptions{}, nil)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, "aggregated_1m_360h", clusters[0].NamespaceID().String())
+	assert.Equal(t, consolidators.NamespaceCoversAllQueryRange, fanoutType)
+
+	// Fanout disabled namespaces can still be queried explicitly.
+	restrict := &storage.RestrictQueryOptions{
+		RestrictByType: &storage.RestrictByType{
+			MetricsType:   storagemetadata.AggregatedMetricsType,
+			StoragePolicy: policy.MustParseStoragePolicy("1m:720h"),
+		},
+	}
+	_, clusters, err = resolveClusterNamespacesForQuery(now,
+		start, now, ns, &storage.FanoutOptions{}, restrict)
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, "backfilling_1m_720h", clusters[0].NamespaceID().String())
+}
+
 func TestDeduplicatePartialAggregateNamespaces(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -86,6 +86,10 @@ type ClusterStaticNamespaceConfiguration struct {
 	// Downsample is the configuration for downsampling options to use with
 	// the namespace.
 	Downsample *DownsampleClusterStaticNamespaceConfiguration `yaml:"downsample"`
+
+	// FanoutDisabled excludes the namespace from automatic query fanout
+	// without removing it, e.g. while the namespace is being backfilled.
+	FanoutDisabled bool `yaml:"fanoutDisabled"`
 }
 
 func (c ClusterStaticNamespaceConfiguration) metricsType() (storagemetadata.MetricsType, error) {
@@ -288,11 +292,12 @@ func (c ClustersStaticConfiguration) NewStaticClusters(
 			}
 
 			def := AggregatedClusterNamespaceDefinition{
-				NamespaceID: ident.StringID(n.Namespace),
-				Session:     cfg.result.session,
-				Retention:   n.Retention,
-				Resolution:  n.Resolution,
-				Downsample:  &downsampleOpts,
+				NamespaceID:    ident.StringID(n.Namespace),
+				Session:        cfg.result.session,
+				Retention:      n.Retention,
+				Resolution:     n.Resolution,
+				Downsample:     &downsampleOpts,
+				FanoutDisabled: n.FanoutDisabled,
 			}
 			aggregatedClusterNamespaces = append(aggregatedClusterNamespaces, def)
 		}
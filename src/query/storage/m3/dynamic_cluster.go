@@ -286,6 +286,7 @@ func toClusterNamespaces(clusterCfg DynamicClusterNamespaceConfiguration, md nam
 				Downsample: &ClusterNamespaceDownsampleOptions{
 					All: agg.Attributes.DownsampleOptions.All,
 				},
+				FanoutDisabled: agg.Attributes.FanoutDisabled,
 			})
 			if err != nil {
 				return nil, err
@@ -89,7 +89,7 @@ func (s *m3storage) QueryStorageMetadataAttributes(
 	queryStart, queryEnd time.Time,
 	opts *storage.FetchOptions,
 ) ([]storagemetadata.Attributes, error) {
-	now := xtime.ToUnixNano(s.nowFn())
+	now := xtime.ToUnixNano(s.queryNow(opts))
 	_, namespaces, err := resolveClusterNamespacesForQuery(now,
 		xtime.ToUnixNano(queryStart),
 		xtime.ToUnixNano(queryEnd),
@@ -107,6 +107,15 @@ func (s *m3storage) QueryStorageMetadataAttributes(
 	return results, nil
 }
 
+// queryNow returns the time a query is evaluated as of, which is the snapshot
+// time queries pinned to a snapshot are evaluated as of or the current time.
+func (s *m3storage) queryNow(opts *storage.FetchOptions) time.Time {
+	if opts != nil && !opts.SnapshotTime.IsZero() {
+		return opts.SnapshotTime
+	}
+	return s.nowFn()
+}
+
 func (s *m3storage) ErrorBehavior() storage.ErrorBehavior {
 	return storage.BehaviorFail
 }
@@ -309,7 +318,7 @@ func (s *m3storage) fetchCompressed(
 	// highest resolution (most fine grained) results.
 	// This needs to be optimized, however this is a start.
 	fanout, namespaces, err := resolveClusterNamespacesForQuery(
-		xtime.ToUnixNano(s.nowFn()),
+		xtime.ToUnixNano(s.queryNow(options)),
 		queryStart,
 		queryEnd,
 		s.clusters,
@@ -509,7 +518,7 @@ func (s *m3storage) CompleteTags(
 	// highest resolution (most fine grained) results.
 	// This needs to be optimized, however this is a start.
 	_, namespaces, err := resolveClusterNamespacesForQuery(
-		xtime.ToUnixNano(s.nowFn()),
+		xtime.ToUnixNano(s.queryNow(options)),
 		queryStart,
 		queryEnd,
 		s.clusters,
@@ -651,7 +660,7 @@ func (s *m3storage) SearchCompressed(
 	// highest resolution (most fine grained) results.
 	// This needs to be optimized, however this is a start.
 	_, namespaces, err := resolveClusterNamespacesForQuery(
-		xtime.ToUnixNano(s.nowFn()),
+		xtime.ToUnixNano(s.queryNow(options)),
 		queryStart,
 		queryEnd,
 		s.clusters,
@@ -762,12 +771,13 @@ func (s *m3storage) Write(
 		return err
 	}
 	tagIterator := storage.TagsToIdentTagIterator(tags)
+	annotation := storage.AnnotationWithExemplars(query.Annotation(), query.Exemplars())
 
 	if len(datapoints) == 1 {
 		// Special case single datapoint because it is common and we
 		// can avoid the overhead of a waitgroup, goroutine, multierr,
 		// iterator duplication etc.
-		return s.writeSingle(query, datapoints[0], id, tagIterator, namespace)
+		return s.writeSingle(query, datapoints[0], annotation, id, tagIterator, namespace)
 	}
 
 	var (
@@ -792,7 +802,7 @@ func (s *m3storage) Write(
 			}
 		}
 		spawned := s.opts.WriteWorkerPool().GoWithTimeout(func() {
-			if err := s.writeSingle(query, datapoint, id, tagIter, namespace); err != nil {
+			if err := s.writeSingle(query, datapoint, annotation, id, tagIter, namespace); err != nil {
 				multiErr.add(err)
 			}
 
@@ -819,6 +829,7 @@ func (s *m3storage) Close() error {
 func (s *m3storage) writeSingle(
 	query *storage.WriteQuery,
 	datapoint ts.Datapoint,
+	annotation []byte,
 	identID ident.ID,
 	iterator ident.TagIterator,
 	namespace ClusterNamespace,
@@ -826,5 +837,5 @@ func (s *m3storage) writeSingle(
 	namespaceID := namespace.NamespaceID()
 	session := namespace.Session()
 	return session.WriteTagged(namespaceID, identID, iterator,
-		datapoint.Timestamp, datapoint.Value, query.Unit(), query.Annotation())
+		datapoint.Timestamp, datapoint.Value, query.Unit(), annotation)
 }
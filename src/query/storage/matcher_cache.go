@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/cache"
+
+	"github.com/uber-go/tally"
+)
+
+var (
+	// Regexp index queries are immutable once created, so they can be shared
+	// across queries along with their compiled regexps.
+	regexpQueryCacheLock    sync.RWMutex
+	regexpQueryCache        *cache.LRU
+	regexpQueryCacheMetrics *models.MatcherCacheMetrics
+)
+
+// SetRegexpQueryCacheOptions sets the options of the cache of index queries
+// for regexp matchers, size zero disables the cache.
+func SetRegexpQueryCacheOptions(opts models.MatcherCacheOptions) {
+	regexpQueryCacheLock.Lock()
+	defer regexpQueryCacheLock.Unlock()
+
+	if opts.Size < 1 {
+		regexpQueryCache = nil
+		regexpQueryCacheMetrics = nil
+		return
+	}
+
+	scope := tally.NoopScope
+	if opts.Scope != nil {
+		scope = opts.Scope
+	}
+
+	scope = scope.SubScope("matcher").SubScope("regexp-query-cache")
+	regexpQueryCache = cache.NewLRU(&cache.LRUOptions{
+		MaxEntries: opts.Size,
+		Metrics:    scope.SubScope("lru"),
+	})
+	regexpQueryCacheMetrics = models.NewMatcherCacheMetrics(scope)
+}
+
+// newRegexpQuery returns the index query for a regexp matcher, reusing a
+// previously created query for the same name and pattern if cached.
+func newRegexpQuery(name, value []byte) (idx.Query, error) {
+	regexpQueryCacheLock.RLock()
+	cacheLRU := regexpQueryCache
+	cacheLRUMetrics := regexpQueryCacheMetrics
+	regexpQueryCacheLock.RUnlock()
+
+	if cacheLRU == nil {
+		return idx.NewRegexpQuery(name, value)
+	}
+
+	// NB: prefix the name with its length so that names and patterns that
+	// concatenate to the same string do not collide.
+	key := strconv.Itoa(len(name)) + ":" + string(name) + string(value)
+	if cached, ok := cacheLRU.TryGet(key); ok {
+		if query, ok := cached.(idx.Query); ok {
+			cacheLRUMetrics.Hit.Inc(1)
+			return query, nil
+		}
+	}
+	cacheLRUMetrics.Miss.Inc(1)
+
+	// NB: copy the name and pattern since the cached query outlives the
+	// matcher they belong to.
+	query, err := idx.NewRegexpQuery(
+		append([]byte(nil), name...),
+		append([]byte(nil), value...),
+	)
+	if err != nil {
+		return idx.Query{}, err
+	}
+
+	cacheLRU.Put(key, query)
+	return query, nil
+}
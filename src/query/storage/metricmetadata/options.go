@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package metricmetadata implements a store for the metadata of metric families
+// sent with Prometheus remote write requests, backed by the cluster KV store.
+package metricmetadata
+
+import (
+	"errors"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+)
+
+const (
+	defaultKVKey           = "_metric_metadata"
+	defaultNumShards       = 16
+	defaultMaxMetrics      = 100000
+	defaultPersistInterval = 10 * time.Second
+)
+
+// KVStoreFn returns the KV store to persist metadata to, it is resolved
+// lazily since the cluster client may still be initializing at startup.
+type KVStoreFn func() (kv.Store, error)
+
+// Options for the store.
+type Options struct {
+	kvStoreFn       KVStoreFn
+	kvKey           string
+	numShards       int
+	maxMetrics      int
+	persistInterval time.Duration
+	scope           tally.Scope
+	logger          *zap.Logger
+}
+
+// NewOptions constructs Options based on the given config.
+func NewOptions(
+	cfg *config.MetricMetadataConfiguration,
+	kvStoreFn KVStoreFn,
+	scope tally.Scope,
+	logger *zap.Logger,
+) (Options, error) {
+	if err := validateConfiguration(cfg); err != nil {
+		return Options{}, err
+	}
+	if kvStoreFn == nil {
+		return Options{}, errors.New("a KV store is required for metricMetadata")
+	}
+
+	kvKey := defaultKVKey
+	if cfg.KVKey != "" {
+		kvKey = cfg.KVKey
+	}
+	numShards := defaultNumShards
+	if cfg.NumShards != nil {
+		numShards = *cfg.NumShards
+	}
+	maxMetrics := defaultMaxMetrics
+	if cfg.MaxMetrics != nil {
+		maxMetrics = *cfg.MaxMetrics
+	}
+	persistInterval := defaultPersistInterval
+	if cfg.PersistInterval != 0 {
+		persistInterval = cfg.PersistInterval
+	}
+
+	return Options{
+		kvStoreFn:       kvStoreFn,
+		kvKey:           kvKey,
+		numShards:       numShards,
+		maxMetrics:      maxMetrics,
+		persistInterval: persistInterval,
+		scope:           scope,
+		logger:          logger,
+	}, nil
+}
+
+func validateConfiguration(cfg *config.MetricMetadataConfiguration) error {
+	if cfg == nil {
+		return errors.New("metricMetadata configuration is required")
+	}
+	if cfg.MaxMetrics != nil && *cfg.MaxMetrics <= 0 {
+		return errors.New("maxMetrics must be positive")
+	}
+	if cfg.NumShards != nil && *cfg.NumShards <= 0 {
+		return errors.New("numShards must be positive")
+	}
+	if cfg.PersistInterval < 0 {
+		return errors.New("persistInterval must not be negative")
+	}
+	return nil
+}
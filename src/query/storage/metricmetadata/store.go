@@ -0,0 +1,518 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+const maxPersistAttempts = 5
+
+var (
+	errStoreClosed     = errors.New("metric metadata store is closed")
+	errPersistConflict = errors.New("metric metadata was concurrently updated too many times")
+)
+
+// Store stores the metadata of metric families.
+type Store interface {
+	// Update queues the given metadata to be merged into the store, it is
+	// persisted in the background only if it differs from the metadata
+	// already stored.
+	Update(metadata []prompb.MetricMetadata) error
+
+	// Metadata returns the stored metadata keyed by metric family name,
+	// restricted to the given metric if non-empty and to at most limit
+	// metric families if limit is positive.
+	Metadata(metric string, limit int) map[string]prompb.MetricMetadata
+
+	// Close persists the queued metadata and stops watching for metadata
+	// updates.
+	Close()
+}
+
+type storeMetrics struct {
+	queued        tally.Counter
+	updated       tally.Counter
+	persistErrors tally.Counter
+	dropped       tally.Counter
+	watchErrors   tally.Counter
+}
+
+func newStoreMetrics(scope tally.Scope) storeMetrics {
+	scope = scope.SubScope("metric-metadata")
+	return storeMetrics{
+		queued:        scope.Counter("queued"),
+		updated:       scope.Counter("updated"),
+		persistErrors: scope.Counter("persist-errors"),
+		dropped:       scope.Counter("dropped"),
+		watchErrors:   scope.Counter("watch-errors"),
+	}
+}
+
+// storeShard is the metadata of the metric families stored under a single
+// KV key.
+type storeShard struct {
+	key      string
+	watch    kv.ValueWatch
+	metadata map[string]prompb.MetricMetadata
+	version  int
+	// pending is the metadata received since the last persist.
+	pending map[string]prompb.MetricMetadata
+}
+
+type store struct {
+	sync.RWMutex
+
+	opts       Options
+	kvStore    kv.Store
+	shards     []*storeShard
+	numPending int
+	closing    bool
+	closed     bool
+	closeCh    chan struct{}
+	doneCh     chan struct{}
+	metrics    storeMetrics
+}
+
+// NewStore returns a new metric metadata store that keeps an in-memory copy
+// of the metadata persisted in the KV store, kept up to date by watching the
+// KV keys so that updates received by other coordinators are served as well.
+// The metadata is sharded by metric family name across KV keys and persisted
+// periodically in the background so that updates never wait on the KV store.
+func NewStore(opts Options) Store {
+	shards := make([]*storeShard, 0, opts.numShards)
+	for i := 0; i < opts.numShards; i++ {
+		shards = append(shards, &storeShard{
+			key:      fmt.Sprintf("%s/%d", opts.kvKey, i),
+			metadata: make(map[string]prompb.MetricMetadata),
+			version:  kv.UninitializedVersion,
+		})
+	}
+	s := &store{
+		opts:    opts,
+		shards:  shards,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+		metrics: newStoreMetrics(opts.scope),
+	}
+	go s.persistLoop()
+	return s
+}
+
+func (s *store) shard(name string) *storeShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+func (s *store) Update(metadata []prompb.MetricMetadata) error {
+	var queued, dropped int64
+
+	s.Lock()
+	defer s.Unlock()
+	if s.closing {
+		return errStoreClosed
+	}
+	for _, m := range metadata {
+		if m.MetricFamilyName == "" {
+			continue
+		}
+		shard := s.shard(m.MetricFamilyName)
+		if existing, ok := shard.pending[m.MetricFamilyName]; ok {
+			if existing != m {
+				shard.pending[m.MetricFamilyName] = m
+				queued++
+			}
+			continue
+		}
+		if existing, ok := shard.metadata[m.MetricFamilyName]; ok && existing == m {
+			continue
+		}
+		if s.numPending >= s.opts.maxMetrics {
+			// Bound the memory used by metadata that could not be persisted
+			// yet, e.g. while the KV store is unavailable.
+			dropped++
+			continue
+		}
+		if shard.pending == nil {
+			shard.pending = make(map[string]prompb.MetricMetadata)
+		}
+		shard.pending[m.MetricFamilyName] = m
+		s.numPending++
+		queued++
+	}
+	s.metrics.queued.Inc(queued)
+	s.metrics.dropped.Inc(dropped)
+	return nil
+}
+
+func (s *store) persistLoop() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.opts.persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			// Persist what was received before closing on a best effort basis.
+			s.persistPending()
+			return
+		}
+		s.persistPending()
+	}
+}
+
+// persistPending persists the pending metadata of every shard in a single
+// batch per shard, the pending metadata of shards that fail to persist is
+// retried on the next persist.
+func (s *store) persistPending() {
+	if err := s.persist(); err != nil {
+		s.opts.logger.Warn("could not persist metric metadata", zap.Error(err))
+	}
+}
+
+func (s *store) persist() error {
+	kvStore, err := s.kv()
+	if err != nil {
+		s.metrics.persistErrors.Inc(1)
+		return err
+	}
+
+	var multiErr error
+	for _, shard := range s.shards {
+		s.Lock()
+		pending := shard.pending
+		shard.pending = nil
+		s.numPending -= len(pending)
+		s.Unlock()
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err := s.persistShard(kvStore, shard, pending); err != nil {
+			s.metrics.persistErrors.Inc(1)
+			s.requeue(shard, pending)
+			if multiErr == nil {
+				multiErr = err
+			}
+		}
+	}
+	return multiErr
+}
+
+// requeue queues metadata that failed to persist again unless more recent
+// metadata of the same metric family has been received meanwhile.
+func (s *store) requeue(shard *storeShard, pending map[string]prompb.MetricMetadata) {
+	s.Lock()
+	defer s.Unlock()
+	if shard.pending == nil {
+		shard.pending = make(map[string]prompb.MetricMetadata, len(pending))
+	}
+	for name, m := range pending {
+		if _, ok := shard.pending[name]; ok {
+			continue
+		}
+		shard.pending[name] = m
+		s.numPending++
+	}
+}
+
+func (s *store) persistShard(
+	kvStore kv.Store,
+	shard *storeShard,
+	changed map[string]prompb.MetricMetadata,
+) error {
+	for attempt := 0; attempt < maxPersistAttempts; attempt++ {
+		current, version, err := load(kvStore, shard.key)
+		if err != nil {
+			return err
+		}
+
+		merged, modified, dropped := s.merge(shard, current, changed)
+		s.metrics.dropped.Inc(dropped)
+		if !modified {
+			s.setMetadata(shard, current, version)
+			return nil
+		}
+
+		value := &prompb.WriteRequest{Metadata: toSlice(merged)}
+		if version == kv.UninitializedVersion {
+			version, err = kvStore.SetIfNotExists(shard.key, value)
+		} else {
+			version, err = kvStore.CheckAndSet(shard.key, version, value)
+		}
+		if err == kv.ErrVersionMismatch || err == kv.ErrAlreadyExists {
+			// Another coordinator updated the metadata concurrently, retry
+			// against its version.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.setMetadata(shard, merged, version)
+		s.metrics.updated.Inc(1)
+		return nil
+	}
+
+	return errPersistConflict
+}
+
+// merge merges the changed metadata into the current metadata of the shard,
+// dropping metadata for new metric families once the max number of metrics
+// across all shards is reached. The other shards are counted as last seen, so
+// the max number of metrics may be exceeded slightly under concurrent updates
+// of different shards.
+func (s *store) merge(
+	shard *storeShard,
+	current map[string]prompb.MetricMetadata,
+	changed map[string]prompb.MetricMetadata,
+) (map[string]prompb.MetricMetadata, bool, int64) {
+	var (
+		maxMetrics = s.opts.maxMetrics - s.numMetricsExcept(shard)
+		merged     = make(map[string]prompb.MetricMetadata, len(current)+len(changed))
+		modified   bool
+		dropped    int64
+	)
+	for name, m := range current {
+		merged[name] = m
+	}
+	for name, m := range changed {
+		existing, ok := merged[name]
+		if ok && existing == m {
+			continue
+		}
+		if !ok && len(merged) >= maxMetrics {
+			dropped++
+			continue
+		}
+		merged[name] = m
+		modified = true
+	}
+	return merged, modified, dropped
+}
+
+func (s *store) numMetricsExcept(shard *storeShard) int {
+	s.RLock()
+	defer s.RUnlock()
+	n := 0
+	for _, other := range s.shards {
+		if other != shard {
+			n += len(other.metadata)
+		}
+	}
+	return n
+}
+
+func load(
+	kvStore kv.Store,
+	key string,
+) (map[string]prompb.MetricMetadata, int, error) {
+	value, err := kvStore.Get(key)
+	if err == kv.ErrNotFound {
+		return map[string]prompb.MetricMetadata{}, kv.UninitializedVersion, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	metadata, err := decode(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return metadata, value.Version(), nil
+}
+
+// kv returns the KV store, resolving it and starting to watch the metadata
+// KV keys on first use.
+func (s *store) kv() (kv.Store, error) {
+	s.RLock()
+	kvStore, closed := s.kvStore, s.closed
+	s.RUnlock()
+	if closed {
+		return nil, errStoreClosed
+	}
+	if kvStore != nil {
+		return kvStore, nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return nil, errStoreClosed
+	}
+	if s.kvStore != nil {
+		return s.kvStore, nil
+	}
+
+	kvStore, err := s.opts.kvStoreFn()
+	if err != nil {
+		return nil, err
+	}
+	watches := make([]kv.ValueWatch, 0, len(s.shards))
+	for _, shard := range s.shards {
+		watch, err := kvStore.Watch(shard.key)
+		if err != nil {
+			for _, w := range watches {
+				w.Close()
+			}
+			return nil, err
+		}
+		watches = append(watches, watch)
+	}
+
+	s.kvStore = kvStore
+	for i, shard := range s.shards {
+		shard.watch = watches[i]
+		go s.watchUpdates(shard, watches[i])
+	}
+	return kvStore, nil
+}
+
+func (s *store) watchUpdates(shard *storeShard, watch kv.ValueWatch) {
+	for range watch.C() {
+		value := watch.Get()
+		if value == nil {
+			continue
+		}
+
+		metadata, err := decode(value)
+		if err != nil {
+			s.metrics.watchErrors.Inc(1)
+			s.opts.logger.Error("could not decode metric metadata update",
+				zap.String("key", shard.key), zap.Int("version", value.Version()),
+				zap.Error(err))
+			continue
+		}
+		s.setMetadata(shard, metadata, value.Version())
+	}
+}
+
+// setMetadata replaces the cached metadata of the shard unless it is already
+// more recent.
+func (s *store) setMetadata(
+	shard *storeShard,
+	metadata map[string]prompb.MetricMetadata,
+	version int,
+) {
+	s.Lock()
+	defer s.Unlock()
+	if version < shard.version {
+		return
+	}
+	shard.metadata = metadata
+	shard.version = version
+}
+
+func (s *store) Metadata(metric string, limit int) map[string]prompb.MetricMetadata {
+	if _, err := s.kv(); err != nil {
+		// Serve whatever has been cached so far, the KV store is retried on
+		// the next request.
+		s.opts.logger.Debug("could not watch metric metadata", zap.Error(err))
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	if metric != "" {
+		result := make(map[string]prompb.MetricMetadata, 1)
+		if m, ok := s.shard(metric).metadata[metric]; ok {
+			result[metric] = m
+		}
+		return result
+	}
+
+	var names []string
+	for _, shard := range s.shards {
+		for name := range shard.metadata {
+			names = append(names, name)
+		}
+	}
+	if limit > 0 && len(names) > limit {
+		sort.Strings(names)
+		names = names[:limit]
+	}
+
+	result := make(map[string]prompb.MetricMetadata, len(names))
+	for _, name := range names {
+		result[name] = s.shard(name).metadata[name]
+	}
+	return result
+}
+
+func (s *store) Close() {
+	s.Lock()
+	if s.closing {
+		s.Unlock()
+		return
+	}
+	s.closing = true
+	s.Unlock()
+
+	// Stop the persist loop before closing so that its final persist can
+	// still use the KV store.
+	close(s.closeCh)
+	<-s.doneCh
+
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	for _, shard := range s.shards {
+		if shard.watch != nil {
+			shard.watch.Close()
+		}
+	}
+}
+
+func decode(value kv.Value) (map[string]prompb.MetricMetadata, error) {
+	var req prompb.WriteRequest
+	if err := value.Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]prompb.MetricMetadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		metadata[m.MetricFamilyName] = m
+	}
+	return metadata, nil
+}
+
+func toSlice(metadata map[string]prompb.MetricMetadata) []prompb.MetricMetadata {
+	result := make([]prompb.MetricMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].MetricFamilyName < result[j].MetricFamilyName
+	})
+	return result
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+var (
+	requestsMetadata = prompb.MetricMetadata{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "http_requests_total",
+		Help:             "Total number of HTTP requests.",
+	}
+	latencyMetadata = prompb.MetricMetadata{
+		Type:             prompb.MetricType_HISTOGRAM,
+		MetricFamilyName: "http_request_duration_seconds",
+		Help:             "HTTP request latency.",
+		Unit:             "seconds",
+	}
+)
+
+func newTestStore(t *testing.T, kvStore kv.Store, maxMetrics int) *store {
+	opts, err := NewOptions(&config.MetricMetadataConfiguration{
+		Enabled:    true,
+		MaxMetrics: &maxMetrics,
+		// Persist explicitly in tests.
+		PersistInterval: time.Hour,
+	}, func() (kv.Store, error) {
+		return kvStore, nil
+	}, tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	return NewStore(opts).(*store)
+}
+
+func TestStoreUpdatePersistsMetadata(t *testing.T) {
+	kvStore := mem.NewStore()
+	s := newTestStore(t, kvStore, 10)
+	defer s.Close()
+
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata, latencyMetadata}))
+	// Metadata is only served once persisted.
+	assert.Equal(t, map[string]prompb.MetricMetadata{}, s.Metadata("", 0))
+	require.NoError(t, s.persist())
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: requestsMetadata,
+		latencyMetadata.MetricFamilyName:  latencyMetadata,
+	}, s.Metadata("", 0))
+
+	key := s.shard(requestsMetadata.MetricFamilyName).key
+	value, err := kvStore.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value.Version())
+
+	// Unchanged metadata is not persisted again.
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata}))
+	require.NoError(t, s.persist())
+	value, err = kvStore.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value.Version())
+
+	updated := requestsMetadata
+	updated.Help = "Total number of HTTP requests served."
+	require.NoError(t, s.Update([]prompb.MetricMetadata{updated}))
+	require.NoError(t, s.persist())
+	value, err = kvStore.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value.Version())
+
+	metadata, err := decode(value)
+	require.NoError(t, err)
+	assert.Equal(t, updated, metadata[requestsMetadata.MetricFamilyName])
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: updated,
+		latencyMetadata.MetricFamilyName:  latencyMetadata,
+	}, s.Metadata("", 0))
+}
+
+func TestStoreUpdateShardsAndBatchesMetadata(t *testing.T) {
+	kvStore := mem.NewStore()
+	s := newTestStore(t, kvStore, 1000)
+	defer s.Close()
+
+	var metadata []prompb.MetricMetadata
+	for i := 0; i < 100; i++ {
+		m := requestsMetadata
+		m.MetricFamilyName = fmt.Sprintf("metric_%d", i)
+		metadata = append(metadata, m)
+		// Every update is queued rather than persisted on its own.
+		require.NoError(t, s.Update([]prompb.MetricMetadata{m}))
+	}
+	require.NoError(t, s.persist())
+	assert.Equal(t, len(metadata), len(s.Metadata("", 0)))
+
+	var total int
+	for _, shard := range s.shards {
+		value, err := kvStore.Get(shard.key)
+		require.NoError(t, err)
+		// A single write per shard.
+		assert.Equal(t, 1, value.Version())
+
+		persisted, err := decode(value)
+		require.NoError(t, err)
+		for name := range persisted {
+			assert.Equal(t, shard, s.shard(name))
+		}
+		total += len(persisted)
+	}
+	assert.Equal(t, len(metadata), total)
+}
+
+func TestStoreMetadataFilters(t *testing.T) {
+	s := newTestStore(t, mem.NewStore(), 10)
+	defer s.Close()
+
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata, latencyMetadata}))
+	require.NoError(t, s.persist())
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		latencyMetadata.MetricFamilyName: latencyMetadata,
+	}, s.Metadata("", 1))
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: requestsMetadata,
+	}, s.Metadata(requestsMetadata.MetricFamilyName, 0))
+	assert.Equal(t, map[string]prompb.MetricMetadata{}, s.Metadata("unknown", 0))
+}
+
+func TestStoreUpdateMaxMetrics(t *testing.T) {
+	s := newTestStore(t, mem.NewStore(), 1)
+	defer s.Close()
+
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata}))
+	require.NoError(t, s.persist())
+	require.NoError(t, s.Update([]prompb.MetricMetadata{latencyMetadata}))
+	require.NoError(t, s.persist())
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: requestsMetadata,
+	}, s.Metadata("", 0))
+
+	// Metadata waiting to be persisted is bounded as well.
+	s = newTestStore(t, mem.NewStore(), 1)
+	defer s.Close()
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata, latencyMetadata}))
+	assert.Equal(t, 1, s.numPending)
+}
+
+func TestStoreWatchesOtherUpdates(t *testing.T) {
+	kvStore := mem.NewStore()
+	s := newTestStore(t, kvStore, 10)
+	defer s.Close()
+	other := newTestStore(t, kvStore, 10)
+	defer other.Close()
+
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata}))
+	require.NoError(t, s.persist())
+	require.NoError(t, other.Update([]prompb.MetricMetadata{latencyMetadata}))
+	require.NoError(t, other.persist())
+
+	expected := map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: requestsMetadata,
+		latencyMetadata.MetricFamilyName:  latencyMetadata,
+	}
+	for _, st := range []*store{s, other} {
+		for start := time.Now(); time.Since(start) < 5*time.Second; {
+			if len(st.Metadata("", 0)) == len(expected) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, expected, st.Metadata("", 0))
+	}
+}
+
+func TestStoreUpdateKVError(t *testing.T) {
+	opts, err := NewOptions(&config.MetricMetadataConfiguration{
+		Enabled:         true,
+		PersistInterval: time.Hour,
+	}, func() (kv.Store, error) {
+		return nil, errors.New("kv unavailable")
+	}, tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	s := NewStore(opts).(*store)
+	defer s.Close()
+
+	// Updates never wait on the KV store, the metadata stays queued until it
+	// can be persisted.
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata}))
+	require.Error(t, s.persist())
+	assert.Equal(t, 1, s.numPending)
+	assert.Equal(t, map[string]prompb.MetricMetadata{}, s.Metadata("", 0))
+}
+
+func TestStoreClosePersistsPending(t *testing.T) {
+	kvStore := mem.NewStore()
+	s := newTestStore(t, kvStore, 10)
+
+	require.NoError(t, s.Update([]prompb.MetricMetadata{requestsMetadata}))
+	s.Close()
+	require.Error(t, s.Update([]prompb.MetricMetadata{latencyMetadata}))
+
+	value, err := kvStore.Get(s.shard(requestsMetadata.MetricFamilyName).key)
+	require.NoError(t, err)
+	metadata, err := decode(value)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]prompb.MetricMetadata{
+		requestsMetadata.MetricFamilyName: requestsMetadata,
+	}, metadata)
+}
+
+func TestNewOptionsValidation(t *testing.T) {
+	kvStoreFn := func() (kv.Store, error) { return mem.NewStore(), nil }
+	zero := 0
+
+	_, err := NewOptions(nil, kvStoreFn, tally.NoopScope, zap.NewNop())
+	require.Error(t, err)
+	_, err = NewOptions(&config.MetricMetadataConfiguration{MaxMetrics: &zero},
+		kvStoreFn, tally.NoopScope, zap.NewNop())
+	require.Error(t, err)
+	_, err = NewOptions(&config.MetricMetadataConfiguration{NumShards: &zero},
+		kvStoreFn, tally.NoopScope, zap.NewNop())
+	require.Error(t, err)
+	_, err = NewOptions(&config.MetricMetadataConfiguration{PersistInterval: -time.Second},
+		kvStoreFn, tally.NoopScope, zap.NewNop())
+	require.Error(t, err)
+	_, err = NewOptions(&config.MetricMetadataConfiguration{},
+		nil, tally.NoopScope, zap.NewNop())
+	require.Error(t, err)
+
+	opts, err := NewOptions(&config.MetricMetadataConfiguration{KVKey: "metadata"},
+		kvStoreFn, tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "metadata", opts.kvKey)
+	assert.Equal(t, defaultNumShards, opts.numShards)
+	assert.Equal(t, defaultMaxMetrics, opts.maxMetrics)
+	assert.Equal(t, defaultPersistInterval, opts.persistInterval)
+}
@@ -67,6 +67,7 @@ func NewOptions(
 		endpoints = append(endpoints, EndpointOptions{
 			name:              endpoint.Name,
 			address:           endpoint.Address,
+			readAddress:       endpoint.ReadAddress,
 			attributes:        attr,
 			downsampleOptions: downsampleOptions,
 		})
@@ -98,6 +99,30 @@ func NewOptions(
 	}, nil
 }
 
+// NewReadOptions constructs ReadOptions based on the given config.
+func NewReadOptions(
+	cfg *config.RemoteReadConfiguration,
+	scope tally.Scope,
+	logger *zap.Logger,
+) (ReadOptions, error) {
+	if err := validateReadConfiguration(cfg); err != nil {
+		return ReadOptions{}, err
+	}
+
+	clientOpts := xhttp.DefaultHTTPClientOptions()
+	if cfg.RequestTimeout != nil {
+		clientOpts.RequestTimeout = *cfg.RequestTimeout
+	}
+	clientOpts.DisableCompression = true // Already snappy compressed.
+
+	return ReadOptions{
+		endpoints:   cfg.Endpoints,
+		httpOptions: clientOpts,
+		scope:       scope,
+		logger:      logger,
+	}, nil
+}
+
 func validateBackendConfiguration(cfg *config.PrometheusRemoteBackendConfiguration) error {
 	if cfg == nil {
 		return fmt.Errorf("prometheusRemoteBackend configuration is required")
@@ -137,6 +162,24 @@ func validateBackendConfiguration(cfg *config.PrometheusRemoteBackendConfigurati
 	return nil
 }
 
+func validateReadConfiguration(cfg *config.RemoteReadConfiguration) error {
+	if cfg == nil {
+		return errors.New("remoteRead configuration is required")
+	}
+	if len(cfg.Endpoints) == 0 {
+		return errors.New("at least one endpoint must be configured for remoteRead")
+	}
+	for _, endpoint := range cfg.Endpoints {
+		if strings.TrimSpace(endpoint) == "" {
+			return errors.New("remoteRead endpoint address must be set")
+		}
+	}
+	if cfg.RequestTimeout != nil && *cfg.RequestTimeout < 0 {
+		return errors.New("requestTimeout can't be negative")
+	}
+	return nil
+}
+
 func validateEndpointConfiguration(endpoint config.PrometheusRemoteBackendEndpointConfiguration) error {
 	if endpoint.StoragePolicy != nil {
 		if endpoint.StoragePolicy.Resolution <= 0 {
@@ -38,8 +38,9 @@ func TestNewFromConfiguration(t *testing.T) {
 	logger := zap.NewNop()
 	opts, err := NewOptions(&config.PrometheusRemoteBackendConfiguration{
 		Endpoints: []config.PrometheusRemoteBackendEndpointConfiguration{{
-			Name:    "testEndpoint",
-			Address: "testAddress",
+			Name:        "testEndpoint",
+			Address:     "testAddress",
+			ReadAddress: "testReadAddress",
 			StoragePolicy: &config.PrometheusRemoteBackendStoragePolicyConfiguration{
 				Resolution: time.Second,
 				Retention:  time.Millisecond,
@@ -57,8 +58,9 @@ func TestNewFromConfiguration(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, []EndpointOptions{{
-		name:    "testEndpoint",
-		address: "testAddress",
+		name:        "testEndpoint",
+		address:     "testAddress",
+		readAddress: "testReadAddress",
 		attributes: storagemetadata.Attributes{
 			MetricsType: storagemetadata.AggregatedMetricsType,
 			Resolution:  time.Second,
@@ -225,6 +227,57 @@ func TestValidateEndpoint(t *testing.T) {
 	})
 }
 
+func TestNewReadOptions(t *testing.T) {
+	opts, err := NewReadOptions(&config.RemoteReadConfiguration{
+		Enabled:        true,
+		Endpoints:      []string{"http://prometheus:9090/api/v1/read"},
+		RequestTimeout: ptrDuration(10 * time.Second),
+	}, tally.NoopScope, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://prometheus:9090/api/v1/read"}, opts.endpoints)
+	assert.Equal(t, 10*time.Second, opts.httpOptions.RequestTimeout)
+	assert.Equal(t, true, opts.httpOptions.DisableCompression)
+}
+
+func TestReadValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *config.RemoteReadConfiguration
+		expectedMsg string
+	}{
+		{
+			name:        "can't be nil",
+			expectedMsg: "remoteRead configuration is required",
+		},
+		{
+			name:        "at least one endpoint",
+			cfg:         &config.RemoteReadConfiguration{},
+			expectedMsg: "at least one endpoint must be configured for remoteRead",
+		},
+		{
+			name:        "endpoint address must be set",
+			cfg:         &config.RemoteReadConfiguration{Endpoints: []string{" "}},
+			expectedMsg: "remoteRead endpoint address must be set",
+		},
+		{
+			name: "requestTimeout can't be negative",
+			cfg: &config.RemoteReadConfiguration{
+				Endpoints:      []string{"http://prometheus:9090/api/v1/read"},
+				RequestTimeout: ptrDuration(-1),
+			},
+			expectedMsg: "requestTimeout can't be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewReadOptions(test.cfg, tally.NoopScope, zap.NewNop())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedMsg)
+		})
+	}
+}
+
 func assertValidationError(t *testing.T, cfg *config.PrometheusRemoteBackendConfiguration, expectedMsg string) {
 	_, err := NewOptions(cfg, tally.NoopScope, zap.NewNop())
 	require.Error(t, err)
@@ -33,10 +33,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// TestPromServer is a fake http server handling prometheus remote write. Intended for test usage.
+// TestPromServer is a fake http server handling prometheus remote write and read. Intended for test usage.
 type TestPromServer struct {
 	mu               sync.Mutex
 	lastWriteRequest *prompb.WriteRequest
+	lastReadRequest  *prompb.ReadRequest
+	readResponse     *prompb.ReadResponse
 	respErr          error
 	t                *testing.T
 	svr              *httptest.Server
@@ -48,6 +50,7 @@ func NewServer(t *testing.T) *TestPromServer {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/write", testPromServer.handleWrite)
+	mux.HandleFunc("/read", testPromServer.handleRead)
 
 	testPromServer.svr = httptest.NewServer(mux)
 
@@ -72,6 +75,32 @@ func (s *TestPromServer) handleWrite(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *TestPromServer) handleRead(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(s.t, r.Header.Get("content-encoding"), "snappy")
+	assert.Equal(s.t, r.Header.Get("content-type"), "application/x-protobuf")
+
+	req, err := remote.DecodeReadRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.lastReadRequest = req
+	if s.respErr != nil {
+		http.Error(w, s.respErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := s.readResponse
+	if resp == nil {
+		resp = &prompb.ReadResponse{Results: []*prompb.QueryResult{{}}}
+	}
+	if err := remote.EncodeReadResponse(resp, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // GetLastWriteRequest returns the last recorded write request.
 func (s *TestPromServer) GetLastWriteRequest() *prompb.WriteRequest {
 	s.mu.Lock()
@@ -84,6 +113,25 @@ func (s *TestPromServer) WriteAddr() string {
 	return fmt.Sprintf("%s/write", s.svr.URL)
 }
 
+// GetLastReadRequest returns the last recorded read request.
+func (s *TestPromServer) GetLastReadRequest() *prompb.ReadRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastReadRequest
+}
+
+// ReadAddr returns http address of a read endpoint.
+func (s *TestPromServer) ReadAddr() string {
+	return fmt.Sprintf("%s/read", s.svr.URL)
+}
+
+// SetReadResponse sets the response returned for all incoming read requests.
+func (s *TestPromServer) SetReadResponse(resp *prompb.ReadResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readResponse = resp
+}
+
 // SetError sets error that will be returned for all incoming requests.
 func (s *TestPromServer) SetError(err error) {
 	s.mu.Lock()
@@ -97,6 +145,8 @@ func (s *TestPromServer) Reset() {
 	defer s.mu.Unlock()
 	s.respErr = nil
 	s.lastWriteRequest = nil
+	s.lastReadRequest = nil
+	s.readResponse = nil
 }
 
 // Close stops underlying http server.
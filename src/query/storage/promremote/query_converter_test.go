@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/ts"
@@ -88,6 +89,49 @@ func TestWriteQueryConverter(t *testing.T) {
 				Samples: []prompb.Sample{covertedToSample, covertedToSample},
 			}),
 		},
+		{
+			name: "exemplars",
+			input: storage.WriteQueryOptions{
+				Tags: models.Tags{
+					Opts: models.NewTagOptions(),
+					Tags: []models.Tag{tag},
+				},
+				Datapoints: ts.Datapoints{dp},
+				Unit:       xtime.Millisecond,
+				Exemplars: []metric.Exemplar{
+					{
+						TraceID:   []byte{0xab, 0xcd},
+						SpanID:    []byte{0x12},
+						Value:     41,
+						TimeNanos: int64(now),
+					},
+					{
+						TraceID:   []byte{0xef},
+						Value:     40,
+						TimeNanos: int64(now),
+					},
+				},
+			},
+			expected: promWriteRequest(prompb.TimeSeries{
+				Labels:  []prompb.Label{convertedToLabel},
+				Samples: []prompb.Sample{covertedToSample},
+				Exemplars: []prompb.Exemplar{
+					{
+						Labels: []prompb.Label{
+							{Name: "trace_id", Value: "abcd"},
+							{Name: "span_id", Value: "12"},
+						},
+						Value:     41,
+						Timestamp: now.ToNormalizedTime(time.Millisecond),
+					},
+					{
+						Labels:    []prompb.Label{{Name: "trace_id", Value: "ef"}},
+						Value:     40,
+						Timestamp: now.ToNormalizedTime(time.Millisecond),
+					},
+				},
+			}),
+		},
 		{
 			name: "overrides metric name tag",
 			input: storage.WriteQueryOptions{
@@ -21,6 +21,7 @@
 package promremote
 
 import (
+	"encoding/hex"
 	"time"
 
 	"github.com/golang/snappy"
@@ -28,6 +29,12 @@ import (
 	"github.com/prometheus/prometheus/prompb"
 
 	"github.com/m3db/m3/src/query/storage"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	exemplarTraceIDLabel = "trace_id"
+	exemplarSpanIDLabel  = "span_id"
 )
 
 var errNilQuery = errors.New("received nil query")
@@ -66,11 +73,37 @@ func convertWriteQuery(query *storage.WriteQuery) *prompb.WriteRequest {
 		})
 	}
 
+	var exemplars []prompb.Exemplar
+	if queryExemplars := query.Exemplars(); len(queryExemplars) > 0 {
+		exemplars = make([]prompb.Exemplar, 0, len(queryExemplars))
+		for _, e := range queryExemplars {
+			var exemplarLabels []prompb.Label
+			if len(e.TraceID) > 0 {
+				exemplarLabels = append(exemplarLabels, prompb.Label{
+					Name:  exemplarTraceIDLabel,
+					Value: hex.EncodeToString(e.TraceID),
+				})
+			}
+			if len(e.SpanID) > 0 {
+				exemplarLabels = append(exemplarLabels, prompb.Label{
+					Name:  exemplarSpanIDLabel,
+					Value: hex.EncodeToString(e.SpanID),
+				})
+			}
+			exemplars = append(exemplars, prompb.Exemplar{
+				Labels:    exemplarLabels,
+				Value:     e.Value,
+				Timestamp: xtime.UnixNano(e.TimeNanos).ToNormalizedTime(time.Millisecond),
+			})
+		}
+	}
+
 	return &prompb.WriteRequest{
 		Timeseries: []prompb.TimeSeries{
 			{
-				Labels:  labels,
-				Samples: samples,
+				Labels:    labels,
+				Samples:   samples,
+				Exemplars: exemplars,
 			},
 		},
 	}
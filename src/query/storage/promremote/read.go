@@ -0,0 +1,359 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promremote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	readMetricsScope = "prom_remote_read_storage"
+
+	remoteReadVersionHeader = "X-Prometheus-Remote-Read-Version"
+	remoteReadVersion       = "0.1.0"
+
+	fetchRemoteReadWarningName  = "remote_read"
+	fetchRemoteReadWarningError = "fetch_remote_read_error"
+)
+
+type readEndpoint struct {
+	address string
+	metrics instrument.MethodMetrics
+}
+
+// remoteReader queries Prometheus remote read compatible endpoints and merges
+// the series they return.
+type remoteReader struct {
+	client    *http.Client
+	endpoints []readEndpoint
+	logger    *zap.Logger
+}
+
+func (r *remoteReader) fetch(
+	ctx context.Context,
+	query *storage.FetchQuery,
+) ([]*prompb.TimeSeries, error) {
+	encoded, err := encodeReadQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		multiErr xerrors.MultiError
+		result   []*prompb.TimeSeries
+	)
+	for _, endpoint := range r.endpoints {
+		endpoint := endpoint
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			series, err := r.readSingle(ctx, endpoint, encoded)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				multiErr = multiErr.Add(err)
+				return
+			}
+			result = mergeTimeseries(result, series)
+		}()
+	}
+	wg.Wait()
+
+	// Series from the endpoints that could be reached are returned regardless.
+	return result, multiErr.FinalError()
+}
+
+func (r *remoteReader) readSingle(
+	ctx context.Context,
+	endpoint readEndpoint,
+	encoded []byte,
+) ([]*prompb.TimeSeries, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		endpoint.address, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-encoding", "snappy")
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeProtobuf)
+	req.Header.Set(remoteReadVersionHeader, remoteReadVersion)
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		endpoint.metrics.ReportError(time.Since(start))
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	methodDuration := time.Since(start)
+	if err != nil {
+		endpoint.metrics.ReportError(methodDuration)
+		r.logger.Error("error reading body", zap.Error(err))
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		endpoint.metrics.ReportError(methodDuration)
+		return nil, fmt.Errorf("expected status code 2XX: actual=%v, address=%v, resp=%s",
+			resp.StatusCode, endpoint.address, body)
+	}
+
+	series, err := decodeReadResponse(body)
+	if err != nil {
+		endpoint.metrics.ReportError(methodDuration)
+		return nil, fmt.Errorf("unable to decode remote read response: address=%v, err=%v",
+			endpoint.address, err)
+	}
+	endpoint.metrics.ReportSuccess(methodDuration)
+	return series, nil
+}
+
+func encodeReadQuery(query *storage.FetchQuery) ([]byte, error) {
+	if query == nil {
+		return nil, errNilQuery
+	}
+	matchers, err := storage.M3MatchersToProm(query.TagMatchers)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &prompb.ReadRequest{
+		Queries: []*prompb.Query{{
+			StartTimestampMs: storage.TimeToPromTimestamp(xtime.ToUnixNano(query.Start)),
+			EndTimestampMs:   storage.TimeToPromTimestamp(xtime.ToUnixNano(query.End)),
+			Matchers:         matchers,
+		}},
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+func decodeReadResponse(body []byte) ([]*prompb.TimeSeries, error) {
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp prompb.ReadResponse
+	if err := resp.Unmarshal(data); err != nil {
+		return nil, err
+	}
+
+	var series []*prompb.TimeSeries
+	for _, result := range resp.Results {
+		series = mergeTimeseries(series, result.Timeseries)
+	}
+	return series, nil
+}
+
+// mergeTimeseries merges the series from other into the given series, series
+// with the same labels are merged into a single series. Where both series have
+// a sample at the same timestamp the sample of the given series is kept.
+func mergeTimeseries(series, other []*prompb.TimeSeries) []*prompb.TimeSeries {
+	if len(other) == 0 {
+		return series
+	}
+
+	byLabels := make(map[string]*prompb.TimeSeries, len(series))
+	for _, ts := range series {
+		byLabels[labelsKey(ts.Labels)] = ts
+	}
+	for _, ts := range other {
+		key := labelsKey(ts.Labels)
+		existing, ok := byLabels[key]
+		if !ok {
+			byLabels[key] = ts
+			series = append(series, ts)
+			continue
+		}
+		existing.Samples = mergeSamples(existing.Samples, ts.Samples)
+	}
+	return series
+}
+
+// mergeSamples merges two lists of samples sorted by timestamp, keeping the
+// sample of the first list where both have a sample at the same timestamp.
+func mergeSamples(samples, other []prompb.Sample) []prompb.Sample {
+	if len(other) == 0 {
+		return samples
+	}
+
+	merged := make([]prompb.Sample, 0, len(samples)+len(other))
+	i, j := 0, 0
+	for i < len(samples) && j < len(other) {
+		switch {
+		case samples[i].Timestamp < other[j].Timestamp:
+			merged = append(merged, samples[i])
+			i++
+		case samples[i].Timestamp > other[j].Timestamp:
+			merged = append(merged, other[j])
+			j++
+		default:
+			merged = append(merged, samples[i])
+			i++
+			j++
+		}
+	}
+	merged = append(merged, samples[i:]...)
+	return append(merged, other[j:]...)
+}
+
+func labelsKey(labels []prompb.Label) string {
+	sorted := make([]prompb.Label, len(labels))
+	copy(sorted, labels)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Name, sorted[j].Name) < 0
+	})
+
+	var b strings.Builder
+	for _, label := range sorted {
+		b.Write(label.Name)
+		b.WriteByte(0xff)
+		b.Write(label.Value)
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
+
+type readMergeMetrics struct {
+	fetches     tally.Counter
+	fetchErrors tally.Counter
+}
+
+func newReadMergeMetrics(scope tally.Scope) readMergeMetrics {
+	return readMergeMetrics{
+		fetches:     scope.Counter("fetches"),
+		fetchErrors: scope.Counter("fetch_errors"),
+	}
+}
+
+type readMergeStorage struct {
+	storage.Storage
+
+	reader  *remoteReader
+	metrics readMergeMetrics
+	logger  *zap.Logger
+}
+
+// NewReadMergeStorage returns a storage that queries Prometheus remote read
+// compatible endpoints alongside the given storage and merges their series into
+// the results of Prometheus queries, e.g. to serve queries spanning data from
+// both M3 and an external store while migrating from the latter. All other
+// operations are served by the given storage as is.
+func NewReadMergeStorage(store storage.Storage, opts ReadOptions) storage.Storage {
+	scope := opts.scope.SubScope(readMetricsScope)
+	endpoints := make([]readEndpoint, 0, len(opts.endpoints))
+	for _, address := range opts.endpoints {
+		endpoints = append(endpoints, readEndpoint{
+			address: address,
+			metrics: newReadEndpointMetrics(scope, address),
+		})
+	}
+	return &readMergeStorage{
+		Storage: store,
+		reader: &remoteReader{
+			client:    xhttp.NewHTTPClient(opts.httpOptions),
+			endpoints: endpoints,
+			logger:    opts.logger,
+		},
+		metrics: newReadMergeMetrics(scope),
+		logger:  opts.logger,
+	}
+}
+
+func (s *readMergeStorage) FetchProm(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (storage.PromResult, error) {
+	var (
+		wg           sync.WaitGroup
+		remoteSeries []*prompb.TimeSeries
+		remoteErr    error
+	)
+	s.metrics.fetches.Inc(1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		remoteSeries, remoteErr = s.reader.fetch(ctx, query)
+	}()
+
+	result, err := s.Storage.FetchProm(ctx, query, options)
+	wg.Wait()
+	if err != nil {
+		return result, err
+	}
+
+	if remoteErr != nil {
+		// Remote series are best effort, the results from storage are still valid.
+		s.metrics.fetchErrors.Inc(1)
+		s.logger.Warn("partial results: unable to fetch series from remote read endpoints",
+			zap.Error(remoteErr))
+		result.Metadata.AddWarning(fetchRemoteReadWarningName, fetchRemoteReadWarningError)
+	}
+	if len(remoteSeries) == 0 {
+		return result, nil
+	}
+
+	if result.PromResult == nil {
+		result.PromResult = &prompb.QueryResult{}
+	}
+	result.PromResult.Timeseries = mergeTimeseries(result.PromResult.Timeseries, remoteSeries)
+	return result, nil
+}
+
+func (s *readMergeStorage) Close() error {
+	s.reader.client.CloseIdleConnections()
+	return s.Storage.Close()
+}
+
+func newReadEndpointMetrics(scope tally.Scope, name string) instrument.MethodMetrics {
+	endpointScope := scope.Tagged(map[string]string{"endpoint_name": name})
+	return instrument.NewMethodMetrics(endpointScope, "readSingle", instrument.TimerOptions{
+		Type:             instrument.HistogramTimerType,
+		HistogramBuckets: tally.DefaultBuckets,
+	})
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promremote
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/m3db/m3/src/query/block"
+	m3prompb "github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/promremote/promremotetest"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	"github.com/m3db/m3/src/x/tallytest"
+)
+
+func newTestFetchQuery(t *testing.T) *storage.FetchQuery {
+	matcher, err := models.NewMatcher(models.MatchEqual, []byte("__name__"), []byte("up"))
+	require.NoError(t, err)
+	return &storage.FetchQuery{
+		TagMatchers: models.Matchers{matcher},
+		Start:       time.Unix(100, 0),
+		End:         time.Unix(200, 0),
+	}
+}
+
+func newTestReadResponse(name string, timestamps ...int64) *prompb.ReadResponse {
+	samples := make([]prompb.Sample, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		samples = append(samples, prompb.Sample{Timestamp: timestamp, Value: float64(timestamp)})
+	}
+	return &prompb.ReadResponse{
+		Results: []*prompb.QueryResult{{
+			Timeseries: []*prompb.TimeSeries{{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "instance", Value: name},
+				},
+				Samples: samples,
+			}},
+		}},
+	}
+}
+
+func TestFetchProm(t *testing.T) {
+	fakeProm := promremotetest.NewServer(t)
+	defer fakeProm.Close()
+	fakeProm2 := promremotetest.NewServer(t)
+	defer fakeProm2.Close()
+	fakeProm.SetReadResponse(newTestReadResponse("a", 100000, 110000))
+	fakeProm2.SetReadResponse(newTestReadResponse("a", 110000, 120000))
+
+	scope := tally.NewTestScope("test_scope", map[string]string{})
+	promStorage, err := NewStorage(Options{
+		endpoints: []EndpointOptions{
+			{name: "testEndpoint", address: fakeProm.WriteAddr(), readAddress: fakeProm.ReadAddr()},
+			{name: "testEndpoint2", address: fakeProm2.WriteAddr(), readAddress: fakeProm2.ReadAddr()},
+			{name: "writeOnly", address: fakeProm.WriteAddr()},
+		},
+		scope:  scope,
+		logger: logger,
+	})
+	require.NoError(t, err)
+	defer closeWithCheck(t, promStorage)
+
+	result, err := promStorage.FetchProm(context.TODO(), newTestFetchQuery(t), storage.NewFetchOptions())
+	require.NoError(t, err)
+
+	readRequest := fakeProm.GetLastReadRequest()
+	require.NotNil(t, readRequest)
+	require.Len(t, readRequest.Queries, 1)
+	assert.Equal(t, int64(100000), readRequest.Queries[0].StartTimestampMs)
+	assert.Equal(t, int64(200000), readRequest.Queries[0].EndTimestampMs)
+	assert.Equal(t, []*prompb.LabelMatcher{
+		{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+	}, readRequest.Queries[0].Matchers)
+
+	require.Len(t, result.PromResult.Timeseries, 1)
+	series := result.PromResult.Timeseries[0]
+	assert.Equal(t, []m3prompb.Label{
+		{Name: []byte("__name__"), Value: []byte("up")},
+		{Name: []byte("instance"), Value: []byte("a")},
+	}, series.Labels)
+	assert.Equal(t, []m3prompb.Sample{
+		{Timestamp: 100000, Value: 100000},
+		{Timestamp: 110000, Value: 110000},
+		{Timestamp: 120000, Value: 120000},
+	}, series.Samples)
+
+	tallytest.AssertCounterValue(
+		t, 1, scope.Snapshot(), "test_scope.prom_remote_storage.readSingle.success",
+		map[string]string{"endpoint_name": "testEndpoint"},
+	)
+
+	t.Run("error is returned", func(t *testing.T) {
+		fakeProm2.SetError(errors.New("test err"))
+		_, err := promStorage.FetchProm(context.TODO(), newTestFetchQuery(t), storage.NewFetchOptions())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "test err")
+	})
+}
+
+func TestFetchPromWithoutReadEndpoints(t *testing.T) {
+	promStorage, err := NewStorage(Options{
+		endpoints: []EndpointOptions{{name: "testEndpoint", address: "http://localhost/write"}},
+		scope:     tally.NoopScope,
+		logger:    logger,
+	})
+	require.NoError(t, err)
+	defer closeWithCheck(t, promStorage)
+
+	_, err = promStorage.FetchProm(context.TODO(), newTestFetchQuery(t), storage.NewFetchOptions())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FetchProm method is not supported")
+}
+
+func TestReadMergeStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeProm := promremotetest.NewServer(t)
+	defer fakeProm.Close()
+	fakeProm.SetReadResponse(newTestReadResponse("a", 100000, 110000))
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().Close().Return(nil)
+
+	scope := tally.NewTestScope("test_scope", map[string]string{})
+	mergeStorage := NewReadMergeStorage(store, ReadOptions{
+		endpoints:   []string{fakeProm.ReadAddr()},
+		httpOptions: xhttp.DefaultHTTPClientOptions(),
+		scope:       scope,
+		logger:      logger,
+	})
+	defer closeWithCheck(t, mergeStorage)
+
+	fetch := func(series ...*m3prompb.TimeSeries) storage.PromResult {
+		store.EXPECT().FetchProm(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(storage.PromResult{
+				PromResult: &m3prompb.QueryResult{Timeseries: series},
+				Metadata:   block.NewResultMetadata(),
+			}, nil)
+		result, err := mergeStorage.FetchProm(context.TODO(), newTestFetchQuery(t),
+			storage.NewFetchOptions())
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("merges series with the same labels", func(t *testing.T) {
+		result := fetch(&m3prompb.TimeSeries{
+			Labels: []m3prompb.Label{
+				{Name: []byte("instance"), Value: []byte("a")},
+				{Name: []byte("__name__"), Value: []byte("up")},
+			},
+			Samples: []m3prompb.Sample{
+				{Timestamp: 110000, Value: 1},
+				{Timestamp: 120000, Value: 2},
+			},
+		})
+		require.Len(t, result.PromResult.Timeseries, 1)
+		assert.Equal(t, []m3prompb.Sample{
+			{Timestamp: 100000, Value: 100000},
+			{Timestamp: 110000, Value: 1},
+			{Timestamp: 120000, Value: 2},
+		}, result.PromResult.Timeseries[0].Samples)
+		assert.Empty(t, result.Metadata.Warnings)
+	})
+
+	t.Run("adds series only found remotely", func(t *testing.T) {
+		result := fetch(&m3prompb.TimeSeries{
+			Labels: []m3prompb.Label{
+				{Name: []byte("__name__"), Value: []byte("up")},
+				{Name: []byte("instance"), Value: []byte("b")},
+			},
+			Samples: []m3prompb.Sample{{Timestamp: 120000, Value: 2}},
+		})
+		require.Len(t, result.PromResult.Timeseries, 2)
+		assert.Equal(t, []byte("b"), result.PromResult.Timeseries[0].Labels[1].Value)
+		assert.Equal(t, []byte("a"), result.PromResult.Timeseries[1].Labels[1].Value)
+	})
+
+	t.Run("remote errors are returned as warnings", func(t *testing.T) {
+		fakeProm.SetError(errors.New("test err"))
+		defer fakeProm.SetError(nil)
+
+		result := fetch()
+		assert.Empty(t, result.PromResult.Timeseries)
+		require.Len(t, result.Metadata.Warnings, 1)
+		assert.Equal(t, fetchRemoteReadWarningName, result.Metadata.Warnings[0].Name)
+
+		tallytest.AssertCounterValue(
+			t, 1, scope.Snapshot(), "test_scope.prom_remote_read_storage.fetch_errors",
+			map[string]string{},
+		)
+	})
+
+	t.Run("storage errors are returned", func(t *testing.T) {
+		store.EXPECT().FetchProm(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(storage.PromResult{}, errors.New("storage err"))
+		_, err := mergeStorage.FetchProm(context.TODO(), newTestFetchQuery(t),
+			storage.NewFetchOptions())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "storage err")
+	})
+}
+
+func TestMergeSamples(t *testing.T) {
+	samples := []m3prompb.Sample{
+		{Timestamp: 1, Value: 1},
+		{Timestamp: 3, Value: 3},
+		{Timestamp: 5, Value: 5},
+	}
+	other := []m3prompb.Sample{
+		{Timestamp: 2, Value: 20},
+		{Timestamp: 3, Value: 30},
+		{Timestamp: 6, Value: 60},
+	}
+	assert.Equal(t, []m3prompb.Sample{
+		{Timestamp: 1, Value: 1},
+		{Timestamp: 2, Value: 20},
+		{Timestamp: 3, Value: 3},
+		{Timestamp: 5, Value: 5},
+		{Timestamp: 6, Value: 60},
+	}, mergeSamples(samples, other))
+	assert.Equal(t, samples, mergeSamples(samples, nil))
+	assert.Equal(t, other, mergeSamples(nil, other))
+}
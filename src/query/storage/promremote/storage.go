@@ -35,6 +35,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
@@ -57,8 +58,13 @@ func NewStorage(opts Options) (storage.Storage, error) {
 		opts:            opts,
 		client:          client,
 		endpointMetrics: initEndpointMetrics(opts.endpoints, scope),
-		droppedWrites:   scope.Counter("dropped_writes"),
-		logger:          opts.logger,
+		reader: &remoteReader{
+			client:    client,
+			endpoints: initReadEndpoints(opts.endpoints, scope),
+			logger:    opts.logger,
+		},
+		droppedWrites: scope.Counter("dropped_writes"),
+		logger:        opts.logger,
 	}
 	return s, nil
 }
@@ -68,6 +74,7 @@ type promStorage struct {
 	opts            Options
 	client          *http.Client
 	endpointMetrics map[string]instrument.MethodMetrics
+	reader          *remoteReader
 	droppedWrites   tally.Counter
 	logger          *zap.Logger
 }
@@ -119,6 +126,25 @@ func (p *promStorage) Write(ctx context.Context, query *storage.WriteQuery) erro
 	return multiErr.FinalError()
 }
 
+func (p *promStorage) FetchProm(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	_ *storage.FetchOptions,
+) (storage.PromResult, error) {
+	if len(p.reader.endpoints) == 0 {
+		return storage.PromResult{}, unimplementedError("FetchProm")
+	}
+
+	series, err := p.reader.fetch(ctx, query)
+	if err != nil {
+		return storage.PromResult{}, err
+	}
+	return storage.PromResult{
+		PromResult: &prompb.QueryResult{Timeseries: series},
+		Metadata:   block.NewResultMetadata(),
+	}, nil
+}
+
 func (p *promStorage) Type() storage.Type {
 	return storage.TypeRemoteDC
 }
@@ -184,18 +210,24 @@ func initEndpointMetrics(endpoints []EndpointOptions, scope tally.Scope) map[str
 	return metrics
 }
 
+func initReadEndpoints(endpoints []EndpointOptions, scope tally.Scope) []readEndpoint {
+	var readEndpoints []readEndpoint
+	for _, endpoint := range endpoints {
+		if endpoint.readAddress == "" {
+			continue
+		}
+		readEndpoints = append(readEndpoints, readEndpoint{
+			address: endpoint.readAddress,
+			metrics: newReadEndpointMetrics(scope, endpoint.name),
+		})
+	}
+	return readEndpoints
+}
+
 var _ storage.Storage = &promStorage{}
 
 type unimplementedPromStorageMethods struct{}
 
-func (p *unimplementedPromStorageMethods) FetchProm(
-	_ context.Context,
-	_ *storage.FetchQuery,
-	_ *storage.FetchOptions,
-) (storage.PromResult, error) {
-	return storage.PromResult{}, unimplementedError("FetchProm")
-}
-
 func (p *unimplementedPromStorageMethods) FetchBlocks(
 	_ context.Context,
 	_ *storage.FetchQuery,
@@ -40,6 +40,14 @@ type Options struct {
 	logger      *zap.Logger
 }
 
+// ReadOptions for the storage merging remote read results.
+type ReadOptions struct {
+	endpoints   []string
+	httpOptions xhttp.HTTPClientOptions
+	scope       tally.Scope
+	logger      *zap.Logger
+}
+
 // Namespaces returns M3 namespaces from endpoint opts.
 func (o Options) Namespaces() m3.ClusterNamespaces {
 	namespaces := make(m3.ClusterNamespaces, 0, len(o.endpoints))
@@ -53,6 +61,7 @@ func (o Options) Namespaces() m3.ClusterNamespaces {
 type EndpointOptions struct {
 	name              string
 	address           string
+	readAddress       string
 	attributes        storagemetadata.Attributes
 	downsampleOptions *m3.ClusterNamespaceDownsampleOptions
 }
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
@@ -147,6 +148,12 @@ type FetchOptions struct {
 	Timeout time.Duration
 	// Source is the source for the query.
 	Source []byte
+	// SnapshotTime if set pins the time the query is evaluated as of, which
+	// is used instead of the current time to select the namespaces to fetch from.
+	SnapshotTime time.Time
+	// SnapshotComplete is whether writes are no longer expected to arrive for
+	// data at or before the snapshot time.
+	SnapshotComplete bool
 }
 
 // FanoutOptions describes which namespaces should be fanned out to for
@@ -274,6 +281,7 @@ type WriteQueryOptions struct {
 	Datapoints ts.Datapoints
 	Unit       xtime.Unit
 	Annotation []byte
+	Exemplars  []metric.Exemplar
 	Attributes storagemetadata.Attributes
 }
 
@@ -21,6 +21,7 @@
 package storage
 
 import (
+	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
 	"github.com/m3db/m3/src/query/ts"
@@ -90,6 +91,11 @@ func (q WriteQuery) Annotation() []byte {
 	return q.opts.Annotation
 }
 
+// Exemplars returns the exemplars.
+func (q WriteQuery) Exemplars() []metric.Exemplar {
+	return q.opts.Exemplars
+}
+
 // Attributes returns the attributes.
 func (q WriteQuery) Attributes() storagemetadata.Attributes {
 	return q.opts.Attributes
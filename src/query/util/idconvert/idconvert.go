@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package idconvert converts series IDs between Graphite paths, Prometheus
+// label sets and the M3 ID schemes, so that series stored once can be
+// referred to from any of the query languages.
+package idconvert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	ingestcarbon "github.com/m3db/m3/src/cmd/services/m3coordinator/ingest/carbon"
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/prometheus/common/model"
+	promparser "github.com/prometheus/prometheus/promql/parser"
+)
+
+// Format is a series ID format.
+type Format string
+
+// A list of supported formats.
+const (
+	// Graphite is a Graphite path, optionally with the tags of a tagged
+	// series, i.e. "foo.bar;dc=east".
+	Graphite Format = "graphite"
+	// Prometheus is a Prometheus label set, i.e. `foo{dc="east"}`.
+	Prometheus Format = "prometheus"
+	// Quoted is the M3 quoted ID scheme, i.e. `{__name__="foo",dc="east"}`.
+	Quoted Format = "quoted"
+	// PrependMeta is the M3 prepend meta ID scheme, i.e. "8,3,2,4!__name__foodceast".
+	PrependMeta Format = "prepend_meta"
+)
+
+var (
+	// Formats is the list of supported formats.
+	Formats = []Format{Graphite, Prometheus, Quoted, PrependMeta}
+
+	errEmptyID = errors.New("id must not be empty")
+
+	errNotGraphiteSeries = errors.New(
+		"series has no graphite path tags and cannot be represented as a graphite path")
+	errInvalidPrependMetaID = errors.New("invalid prepend meta id")
+)
+
+// ParseFormat parses a format.
+func ParseFormat(str string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == str {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format %q: should be one of %v", str, Formats)
+}
+
+// ToTags converts a series ID of the given format to its tags.
+func ToTags(id []byte, from Format, opts models.TagOptions) (models.Tags, error) {
+	if len(id) == 0 {
+		return models.EmptyTags(), errEmptyID
+	}
+
+	switch from {
+	case Graphite:
+		return ingestcarbon.GenerateTagsFromName(id, opts)
+	case Prometheus, Quoted:
+		// NB: the quoted ID scheme matches the syntax of a Prometheus label set
+		// without the metric name.
+		return labelSetToTags(id, opts)
+	case PrependMeta:
+		return prependMetaToTags(id, opts)
+	default:
+		return models.EmptyTags(), fmt.Errorf("invalid format %q: should be one of %v", from, Formats)
+	}
+}
+
+// FromTags converts tags to a series ID of the given format. Not every set of
+// tags can be represented in every format, i.e. only series with Graphite path
+// tags can be represented as a Graphite path.
+func FromTags(tags models.Tags, to Format) ([]byte, error) {
+	if tags.Len() == 0 {
+		return nil, errEmptyID
+	}
+
+	// NB: copy the tags as they are sorted differently depending on the format.
+	tags = tags.Clone()
+	switch to {
+	case Graphite:
+		for _, tag := range tags.Tags {
+			if models.IsGraphitePathTag(tag.Name) {
+				tags.Opts = tags.Opts.SetIDSchemeType(models.TypeGraphiteTagged)
+				return tags.Normalize().ID(), nil
+			}
+		}
+		return nil, errNotGraphiteSeries
+	case Prometheus:
+		tags.Opts = tags.Opts.SetIDSchemeType(models.TypeQuoted)
+		return tagsToLabelSet(tags.Normalize()), nil
+	case Quoted:
+		tags.Opts = tags.Opts.SetIDSchemeType(models.TypeQuoted)
+		return tags.Normalize().ID(), nil
+	case PrependMeta:
+		tags.Opts = tags.Opts.SetIDSchemeType(models.TypePrependMeta)
+		return tags.Normalize().ID(), nil
+	default:
+		return nil, fmt.Errorf("invalid format %q: should be one of %v", to, Formats)
+	}
+}
+
+// Convert converts a series ID from one format to another.
+func Convert(id []byte, from, to Format, opts models.TagOptions) ([]byte, error) {
+	tags, err := ToTags(id, from, opts)
+	if err != nil {
+		return nil, err
+	}
+	return FromTags(tags, to)
+}
+
+func labelSetToTags(id []byte, opts models.TagOptions) (models.Tags, error) {
+	labels, err := promparser.ParseMetric(string(id))
+	if err != nil {
+		return models.EmptyTags(), err
+	}
+
+	tags := models.NewTags(len(labels), opts)
+	for _, l := range labels {
+		name := []byte(l.Name)
+		if l.Name == model.MetricNameLabel {
+			name = opts.MetricName()
+		}
+		tags = tags.AddTagWithoutNormalizing(models.Tag{Name: name, Value: []byte(l.Value)})
+	}
+	return tags.Normalize(), nil
+}
+
+// tagsToLabelSet writes the tags as a Prometheus label set, with the metric
+// name outside of the braces where it is a valid metric name.
+func tagsToLabelSet(tags models.Tags) []byte {
+	var (
+		buf       bytes.Buffer
+		name, ok  = tags.Name()
+		nameFirst = ok && model.IsValidMetricName(model.LabelValue(name))
+		first     = true
+	)
+	if nameFirst {
+		buf.Write(name)
+		tags = tags.WithoutName()
+	}
+
+	buf.WriteByte('{')
+	for _, tag := range tags.Tags {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		tagName := tag.Name
+		if bytes.Equal(tagName, tags.Opts.MetricName()) {
+			tagName = []byte(model.MetricNameLabel)
+		}
+		buf.Write(tagName)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.Quote(string(tag.Value)))
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// prependMetaToTags parses an ID of the form "l1,l2,...!n1v1n2v2...", where
+// the prefix lists the lengths of each tag name and value.
+func prependMetaToTags(id []byte, opts models.TagOptions) (models.Tags, error) {
+	idx := bytes.IndexByte(id, '!')
+	if idx <= 0 {
+		return models.EmptyTags(), errInvalidPrependMetaID
+	}
+
+	lengths := bytes.Split(id[:idx], []byte{','})
+	if len(lengths)%2 != 0 {
+		return models.EmptyTags(), errInvalidPrependMetaID
+	}
+
+	var (
+		data = id[idx+1:]
+		tags = models.NewTags(len(lengths)/2, opts)
+	)
+	for i := 0; i < len(lengths); i += 2 {
+		nameLen, err := strconv.Atoi(string(lengths[i]))
+		if err != nil || nameLen < 0 {
+			return models.EmptyTags(), errInvalidPrependMetaID
+		}
+		valueLen, err := strconv.Atoi(string(lengths[i+1]))
+		if err != nil || valueLen < 0 || nameLen+valueLen > len(data) {
+			return models.EmptyTags(), errInvalidPrependMetaID
+		}
+
+		tags = tags.AddTagWithoutNormalizing(models.Tag{
+			Name:  data[:nameLen],
+			Value: data[nameLen : nameLen+valueLen],
+		})
+		data = data[nameLen+valueLen:]
+	}
+	if len(data) != 0 {
+		return models.EmptyTags(), errInvalidPrependMetaID
+	}
+	return tags.Normalize(), nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idconvert
+
+import (
+	"testing"
+
+	"github.com/m3db/m3/src/query/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		id       string
+		from     Format
+		expected map[Format]string
+	}{
+		{
+			id:   "foo.bar;dc=east",
+			from: Graphite,
+			expected: map[Format]string{
+				Graphite:    "foo.bar;dc=east",
+				Prometheus:  `{__g0__="foo",__g1__="bar",dc="east"}`,
+				Quoted:      `{__g0__="foo",__g1__="bar",dc="east"}`,
+				PrependMeta: "6,3,6,3,2,4!__g0__foo__g1__bardceast",
+			},
+		},
+		{
+			id:   "a.b.c.d.e.f.g.h.i.j.k",
+			from: Graphite,
+			expected: map[Format]string{
+				Graphite: "a.b.c.d.e.f.g.h.i.j.k",
+			},
+		},
+		{
+			id:   `http_requests{job="api",code="200"}`,
+			from: Prometheus,
+			expected: map[Format]string{
+				Prometheus:  `http_requests{code="200",job="api"}`,
+				Quoted:      `{__name__="http_requests",code="200",job="api"}`,
+				PrependMeta: "8,13,4,3,3,3!__name__http_requestscode200jobapi",
+			},
+		},
+		{
+			id:   `{__name__="http_requests",code="200",job="api"}`,
+			from: Quoted,
+			expected: map[Format]string{
+				Prometheus: `http_requests{code="200",job="api"}`,
+			},
+		},
+		{
+			id:   "8,13,4,3,3,3!__name__http_requestscode200jobapi",
+			from: PrependMeta,
+			expected: map[Format]string{
+				Prometheus: `http_requests{code="200",job="api"}`,
+			},
+		},
+		{
+			id:   `{__g0__="foo",__g1__="bar"}`,
+			from: Prometheus,
+			expected: map[Format]string{
+				Graphite: "foo.bar",
+			},
+		},
+	}
+
+	opts := models.NewTagOptions()
+	for _, test := range tests {
+		for to, expected := range test.expected {
+			actual, err := Convert([]byte(test.id), test.from, to, opts)
+			require.NoError(t, err, test.id)
+			require.Equal(t, expected, string(actual), test.id)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	opts := models.NewTagOptions()
+	for _, test := range []struct {
+		id   string
+		from Format
+		to   Format
+	}{
+		{id: "", from: Graphite, to: Quoted},
+		{id: "foo..bar", from: Graphite, to: Quoted},
+		{id: `foo{bar=}`, from: Prometheus, to: Quoted},
+		{id: "2,3!foo", from: PrependMeta, to: Quoted},
+		{id: "2,3!foobar1", from: PrependMeta, to: Quoted},
+		{id: "foo", from: PrependMeta, to: Quoted},
+		{id: `foo{bar="baz"}`, from: Prometheus, to: Graphite},
+		{id: "foo.bar", from: Graphite, to: Format("invalid")},
+	} {
+		_, err := Convert([]byte(test.id), test.from, test.to, opts)
+		require.Error(t, err, test.id)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		parsed, err := ParseFormat(string(f))
+		require.NoError(t, err)
+		require.Equal(t, f, parsed)
+	}
+
+	_, err := ParseFormat("invalid")
+	require.Error(t, err)
+}
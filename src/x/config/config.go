@@ -92,6 +92,13 @@ func LoadFiles(dst interface{}, files []string, opts Options) error {
 	}
 
 	if err := provider.Get(config.Root).Populate(dst); err != nil {
+		if !opts.DisableUnmarshalStrict {
+			// Report the paths of any unknown fields rather than the line
+			// numbers of the merged config the strict decoder refers to.
+			if unknown := unknownFieldsInFiles(dst, files); len(unknown) > 0 {
+				return unknown
+			}
+		}
 		return err
 	}
 
@@ -21,6 +21,7 @@
 package configflag
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -42,6 +43,11 @@ type Options struct {
 	// and then exit.
 	ShouldDumpConfigAndExit bool
 
+	// ShouldValidateConfigAndExit (-validate) causes MainLoad to validate the
+	// config, print any errors to stdout as JSON and exit with a non-zero exit
+	// code if the config is invalid.
+	ShouldValidateConfigAndExit bool
+
 	// for Usage()
 	cmd *flag.FlagSet
 
@@ -60,10 +66,19 @@ func (opts *Options) RegisterFlagSet(cmd *flag.FlagSet) {
 
 	cmd.Var(&opts.ConfigFiles, "f", "Configuration files to load")
 	cmd.BoolVar(&opts.ShouldDumpConfigAndExit, "d", false, "Dump configuration and exit")
+	cmd.BoolVar(&opts.ShouldValidateConfigAndExit, "validate", false,
+		"Validate configuration, rejecting unknown fields, and exit")
+}
+
+// ValidationResult is the machine readable result of validating config.
+type ValidationResult struct {
+	Valid  bool                `json:"valid"`
+	Errors []config.FieldError `json:"errors"`
 }
 
 // MainLoad is a convenience method, intended for use in main(), which handles all
 // config commandline options. It:
+//  - Validates config and exits if -validate was passed.
 //  - Dumps config and exits if -d was passed.
 //  - Loads configuration otherwise.
 // Users who want a subset of this behavior should call individual methods.
@@ -78,6 +93,10 @@ func (opts *Options) MainLoad(target interface{}, loadOpts config.Options) error
 		return errors.New("-f is required (no config files provided)")
 	}
 
+	if opts.ShouldValidateConfigAndExit {
+		return opts.validateAndExit(target, loadOpts, osFns)
+	}
+
 	if err := config.LoadFiles(target, opts.ConfigFiles.Value, loadOpts); err != nil {
 		return fmt.Errorf("unable to load config from %s: %v", opts.ConfigFiles.Value, err)
 	}
@@ -92,6 +111,34 @@ func (opts *Options) MainLoad(target interface{}, loadOpts config.Options) error
 	return nil
 }
 
+func (opts *Options) validateAndExit(
+	target interface{},
+	loadOpts config.Options,
+	osFns osIface,
+) error {
+	errs := config.ValidateFiles(target, opts.ConfigFiles.Value, loadOpts)
+	result := ValidationResult{
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	}
+	if result.Errors == nil {
+		result.Errors = []config.FieldError{}
+	}
+
+	enc := json.NewEncoder(osFns.Stdout())
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return fmt.Errorf("failed to write config validation result: %v", err)
+	}
+
+	if !result.Valid {
+		osFns.Exit(1)
+		return errs
+	}
+	osFns.Exit(0)
+	return nil
+}
+
 // FlagStringSlice represents a slice of strings. When used as a flag variable,
 // it allows for multiple string values. For example, it can be used like this:
 // 	var configFiles FlagStringSlice
@@ -22,6 +22,7 @@ package configflag
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"testing"
 
@@ -98,6 +99,54 @@ func TestCommandLineOptions(t *testing.T) {
 		assert.Equal(t, expectedConfig, actual)
 	})
 
+	t.Run("validates config and exits on validate", func(t *testing.T) {
+		tctx, teardown := setup(t)
+		defer teardown()
+
+		stdout := &bytes.Buffer{}
+
+		tctx.MockOS.EXPECT().Exit(0).Times(1)
+		tctx.MockOS.EXPECT().Stdout().Return(stdout)
+
+		args := append([]string{"-validate"}, configFileOpts...)
+		require.NoError(t, tctx.Flags.Parse(args))
+
+		var cfg testConfig
+		require.NoError(t, tctx.Opts.MainLoad(&cfg, config.Options{}))
+
+		var result ValidationResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("reports unknown fields and exits non-zero on validate", func(t *testing.T) {
+		tctx, teardown := setup(t)
+		defer teardown()
+
+		stdout := &bytes.Buffer{}
+
+		tctx.MockOS.EXPECT().Exit(1).Times(1)
+		tctx.MockOS.EXPECT().Stdout().Return(stdout)
+
+		args := []string{"-validate", "-f", "./testdata/config_unknown.yaml"}
+		require.NoError(t, tctx.Flags.Parse(args))
+
+		var cfg testConfig
+		require.Error(t, tctx.Opts.MainLoad(&cfg, config.Options{}))
+
+		var result ValidationResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+		assert.False(t, result.Valid)
+		assert.Equal(t, []config.FieldError{
+			{
+				File:    "./testdata/config_unknown.yaml",
+				Path:    "baz",
+				Message: "unknown field",
+			},
+		}, result.Errors)
+	})
+
 	t.Run("errors on no configs", func(t *testing.T) {
 		tctx, teardown := setup(t)
 		defer teardown()
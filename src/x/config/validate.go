@@ -0,0 +1,336 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	validator "gopkg.in/validator.v2"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Validator is implemented by configurations that perform validation beyond
+// the field level validation done when loading config.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is a machine readable configuration error.
+type FieldError struct {
+	// File is the config file the error was found in, if known.
+	File string `json:"file,omitempty"`
+	// Path is the YAML path of the offending field, e.g. "db.client.config",
+	// empty if the error does not apply to a single field.
+	Path string `json:"path,omitempty"`
+	// Message describes the error.
+	Message string `json:"message"`
+}
+
+// Error returns the field error as a string.
+func (e FieldError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File)
+		b.WriteString(": ")
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// FieldErrors is a list of configuration errors.
+type FieldErrors []FieldError
+
+// Error returns the field errors as a string.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateFiles loads the config from the list of files into dst and returns
+// all errors found, with unknown fields always rejected regardless of
+// whether strict unmarshalling is disabled. If dst implements Validator it is
+// also validated once loaded.
+func ValidateFiles(dst interface{}, files []string, opts Options) FieldErrors {
+	var errs FieldErrors
+	for _, file := range files {
+		unknown, err := UnknownFields(dst, file)
+		if err != nil {
+			errs = append(errs, FieldError{File: file, Message: err.Error()})
+			continue
+		}
+		errs = append(errs, unknown...)
+	}
+
+	// Unknown fields have already been reported with their paths so load
+	// permissively to surface the remaining errors in the same pass.
+	loadOpts := opts
+	loadOpts.DisableUnmarshalStrict = true
+	loadOpts.DisableValidate = true
+	if err := LoadFiles(dst, files, loadOpts); err != nil {
+		return append(errs, FieldError{Message: err.Error()})
+	}
+
+	if !opts.DisableValidate {
+		errs = append(errs, validationFieldErrors(dst, validator.Validate(dst))...)
+	}
+	if v, ok := dst.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, FieldError{Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+// UnknownFields returns the fields set in the given YAML file that do not
+// exist in the configuration type of dst.
+func UnknownFields(dst interface{}, file string) (FieldErrors, error) {
+	data, err := ioutil.ReadFile(file) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	var root interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var errs FieldErrors
+	walkUnknownFields(reflect.TypeOf(dst), root, "", func(path string) {
+		errs = append(errs, FieldError{
+			File:    file,
+			Path:    path,
+			Message: "unknown field",
+		})
+	})
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+	return errs, nil
+}
+
+func unknownFieldsInFiles(dst interface{}, files []string) FieldErrors {
+	var errs FieldErrors
+	for _, file := range files {
+		unknown, err := UnknownFields(dst, file)
+		if err != nil {
+			// The load error is returned instead.
+			return nil
+		}
+		errs = append(errs, unknown...)
+	}
+	return errs
+}
+
+func walkUnknownFields(t reflect.Type, value interface{}, path string, unknown func(string)) {
+	for t.Kind() == reflect.Ptr {
+		if customUnmarshaler(t) {
+			return
+		}
+		t = t.Elem()
+	}
+	if customUnmarshaler(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		fields := yamlFields(t)
+		for k, v := range m {
+			key := fmt.Sprintf("%v", k)
+			childPath := joinPath(path, key)
+			ft, ok := fields[key]
+			if !ok {
+				unknown(childPath)
+				continue
+			}
+			walkUnknownFields(ft, v, childPath, unknown)
+		}
+	case reflect.Map:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		for k, v := range m {
+			walkUnknownFields(t.Elem(), v, joinPath(path, fmt.Sprintf("%v", k)), unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		s, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, v := range s {
+			walkUnknownFields(t.Elem(), v, path+"["+strconv.Itoa(i)+"]", unknown)
+		}
+	}
+}
+
+func customUnmarshaler(t reflect.Type) bool {
+	pt := t
+	if t.Kind() != reflect.Ptr {
+		pt = reflect.PtrTo(t)
+	}
+	return pt.Implements(yamlUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// yamlFields returns the types of the fields of a struct keyed by their YAML
+// key, including the fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			// Unexported field.
+			continue
+		}
+		key, inline, skip := yamlKey(f)
+		if skip {
+			continue
+		}
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range yamlFields(ft) {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		fields[key] = f.Type
+	}
+	return fields
+}
+
+func yamlKey(f reflect.StructField) (key string, inline bool, skip bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			return "", true, false
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], false, false
+	}
+	return strings.ToLower(f.Name), false, false
+}
+
+// validationFieldErrors converts the errors returned by field validation to
+// field errors keyed by the YAML path of each invalid field.
+func validationFieldErrors(dst interface{}, err error) FieldErrors {
+	if err == nil {
+		return nil
+	}
+	errMap, ok := err.(validator.ErrorMap)
+	if !ok {
+		return FieldErrors{{Message: err.Error()}}
+	}
+
+	var errs FieldErrors
+	for fieldPath, fieldErrs := range errMap {
+		path := yamlPath(reflect.TypeOf(dst), fieldPath)
+		for _, fieldErr := range fieldErrs {
+			errs = append(errs, FieldError{Path: path, Message: fieldErr.Error()})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Path != errs[j].Path {
+			return errs[i].Path < errs[j].Path
+		}
+		return errs[i].Message < errs[j].Message
+	})
+	return errs
+}
+
+// yamlPath converts a Go field path such as "Client.Servers[0]" to the
+// equivalent YAML path, falling back to the Go field names where the path
+// cannot be resolved.
+func yamlPath(t reflect.Type, fieldPath string) string {
+	var path string
+	for _, segment := range strings.Split(fieldPath, ".") {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		var f reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			f, found = t.FieldByName(name)
+		}
+		if !found {
+			path = joinPath(path, segment)
+			t = nil
+			continue
+		}
+
+		if key, inline, _ := yamlKey(f); !inline {
+			path = joinPath(path, key)
+		}
+		path += index
+		t = f.Type
+		if index != "" {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+	}
+	return path
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
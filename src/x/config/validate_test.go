@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const nestedConfig = `
+listenAddress: localhost:4385
+client:
+  timeout: 10s
+  writeConsistencyLevl: majority
+  servers:
+    - address: server1:8090
+    - address: server2:8090
+      weigth: 2
+labels:
+  foo:
+    value: bar
+    extra: baz
+inlined: true
+`
+
+type nestedServerConfiguration struct {
+	Address string `yaml:"address"`
+	Weight  int    `yaml:"weight"`
+}
+
+type nestedClientConfiguration struct {
+	Timeout time.Duration               `yaml:"timeout"`
+	Servers []nestedServerConfiguration `yaml:"servers"`
+	MinHost int                         `yaml:"minHost" validate:"min=1"`
+}
+
+type nestedLabelConfiguration struct {
+	Value string `yaml:"value"`
+}
+
+type NestedInlineConfiguration struct {
+	Inlined bool `yaml:"inlined"`
+}
+
+type nestedConfiguration struct {
+	NestedInlineConfiguration `yaml:",inline"`
+
+	ListenAddress string                              `yaml:"listenAddress" validate:"nonzero"`
+	Client        nestedClientConfiguration           `yaml:"client"`
+	Labels        map[string]nestedLabelConfiguration `yaml:"labels"`
+}
+
+type validatedConfiguration struct {
+	ListenAddress string `yaml:"listenAddress"`
+}
+
+func (c *validatedConfiguration) Validate() error {
+	return errors.New("listen address is not allowed")
+}
+
+func TestUnknownFields(t *testing.T) {
+	fname := writeFile(t, nestedConfig)
+	defer func() {
+		require.NoError(t, os.Remove(fname))
+	}()
+
+	var cfg nestedConfiguration
+	errs, err := UnknownFields(&cfg, fname)
+	require.NoError(t, err)
+	require.Equal(t, FieldErrors{
+		{File: fname, Path: "client.servers[1].weigth", Message: "unknown field"},
+		{File: fname, Path: "client.writeConsistencyLevl", Message: "unknown field"},
+		{File: fname, Path: "labels.foo.extra", Message: "unknown field"},
+	}, errs)
+}
+
+func TestLoadFileUnknownFieldPaths(t *testing.T) {
+	fname := writeFile(t, nestedConfig)
+	defer func() {
+		require.NoError(t, os.Remove(fname))
+	}()
+
+	var cfg nestedConfiguration
+	err := LoadFile(&cfg, fname, Options{DisableValidate: true})
+	require.Error(t, err)
+
+	var errs FieldErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 3)
+}
+
+func TestValidateFiles(t *testing.T) {
+	fname := writeFile(t, nestedConfig)
+	defer func() {
+		require.NoError(t, os.Remove(fname))
+	}()
+
+	var cfg nestedConfiguration
+	errs := ValidateFiles(&cfg, []string{fname}, Options{})
+	require.Equal(t, 4, len(errs))
+	require.Equal(t, "client.minHost", errs[3].Path)
+	require.Equal(t, "", errs[3].File)
+
+	// Fields are still populated so that all errors are reported in one pass.
+	require.Equal(t, "localhost:4385", cfg.ListenAddress)
+	require.True(t, cfg.Inlined)
+}
+
+func TestValidateFilesValidator(t *testing.T) {
+	fname := writeFile(t, "listenAddress: localhost:4385\n")
+	defer func() {
+		require.NoError(t, os.Remove(fname))
+	}()
+
+	var cfg validatedConfiguration
+	errs := ValidateFiles(&cfg, []string{fname}, Options{})
+	require.Equal(t, FieldErrors{
+		{Message: "listen address is not allowed"},
+	}, errs)
+}
@@ -21,12 +21,20 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
+	"time"
+
+	xdebug "github.com/m3db/m3/src/x/debug"
+	"github.com/m3db/m3/src/x/instrument"
 
 	"go.uber.org/zap"
 )
 
+var errContinuousProfilingSink = errors.New(
+	"continuous profiling requires exactly one of file or http to be set")
+
 // DebugConfiguration for the debug package.
 type DebugConfiguration struct {
 
@@ -37,6 +45,10 @@ type DebugConfiguration struct {
 	// BlockProfileRate is used to set the runtime.BlockProfileRate to report blocking events
 	// See https://golang.org/pkg/runtime/#SetBlockProfileRate for more details about the value.
 	BlockProfileRate int `yaml:"blockProfileRate"`
+
+	// ContinuousProfiling configures periodically capturing profiles and
+	// shipping them to a profile sink.
+	ContinuousProfiling *ContinuousProfilingConfiguration `yaml:"continuousProfiling"`
 }
 
 // SetRuntimeValues sets the configured pprof runtime values.
@@ -46,3 +58,99 @@ func (c DebugConfiguration) SetRuntimeValues(logger *zap.Logger) {
 	logger.Info(fmt.Sprintf("setting BlockProfileRate: %v", c.BlockProfileRate))
 	runtime.SetBlockProfileRate(c.BlockProfileRate)
 }
+
+// NewContinuousProfiler returns the continuous profiler, or nil if continuous
+// profiling is not enabled.
+func (c DebugConfiguration) NewContinuousProfiler(
+	iOpts instrument.Options,
+) (*xdebug.ContinuousProfiler, error) {
+	if c.ContinuousProfiling == nil || !c.ContinuousProfiling.Enabled {
+		return nil, nil
+	}
+	return c.ContinuousProfiling.NewContinuousProfiler(iOpts)
+}
+
+// ContinuousProfilingConfiguration configures continuous profiling.
+type ContinuousProfilingConfiguration struct {
+	// Enabled enables continuous profiling.
+	Enabled bool `yaml:"enabled"`
+
+	// Profiles are the profiles to capture, e.g. cpu, heap, goroutine or mutex,
+	// defaults to cpu and heap.
+	Profiles []string `yaml:"profiles"`
+
+	// Interval is how often profiles are captured.
+	Interval time.Duration `yaml:"interval"`
+
+	// CPUProfileDuration is how long the CPU is profiled for each time.
+	CPUProfileDuration time.Duration `yaml:"cpuProfileDuration"`
+
+	// File writes profiles to a directory.
+	File *ContinuousProfilingFileConfiguration `yaml:"file"`
+
+	// HTTP sends profiles to a pprof-compatible endpoint or object storage.
+	HTTP *ContinuousProfilingHTTPConfiguration `yaml:"http"`
+}
+
+// ContinuousProfilingFileConfiguration configures writing profiles to a directory.
+type ContinuousProfilingFileConfiguration struct {
+	// Dir is the directory profiles are written to.
+	Dir string `yaml:"dir" validate:"nonzero"`
+
+	// Retention is how long profiles are kept for, forever if not set.
+	Retention time.Duration `yaml:"retention"`
+}
+
+// ContinuousProfilingHTTPConfiguration configures sending profiles over HTTP.
+type ContinuousProfilingHTTPConfiguration struct {
+	// URL is the URL template profiles are sent to, which may reference
+	// {{.ProfileName}} and {{.UnixTime}}.
+	URL string `yaml:"url" validate:"nonzero"`
+
+	// Method is the HTTP method, POST if not set.
+	Method string `yaml:"method"`
+
+	// Headers are added to each request.
+	Headers map[string]string `yaml:"headers"`
+
+	// Timeout is the timeout for each request.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// NewContinuousProfiler returns a new continuous profiler.
+func (c ContinuousProfilingConfiguration) NewContinuousProfiler(
+	iOpts instrument.Options,
+) (*xdebug.ContinuousProfiler, error) {
+	if (c.File == nil) == (c.HTTP == nil) {
+		return nil, errContinuousProfilingSink
+	}
+
+	var (
+		sink xdebug.ProfileSink
+		err  error
+	)
+	if c.File != nil {
+		sink, err = xdebug.NewFileProfileSink(xdebug.FileProfileSinkOptions{
+			Dir:       c.File.Dir,
+			Retention: c.File.Retention,
+		})
+	} else {
+		sink, err = xdebug.NewHTTPProfileSink(xdebug.HTTPProfileSinkOptions{
+			URLTemplate: c.HTTP.URL,
+			Method:      c.HTTP.Method,
+			Headers:     c.HTTP.Headers,
+			Timeout:     c.HTTP.Timeout,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return xdebug.NewContinuousProfiler(xdebug.ContinuousProfilerOptions{
+		Profiles:           c.Profiles,
+		Interval:           c.Interval,
+		CPUProfileDuration: c.CPUProfileDuration,
+		Sink:               sink,
+		InstrumentOptions:  iOpts,
+	})
+}
@@ -0,0 +1,400 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// ContinuousHeapProfileName is the name of continuous heap profile.
+	ContinuousHeapProfileName = "heap"
+
+	defaultContinuousProfilerInterval = time.Minute
+	defaultCPUProfileDuration         = 10 * time.Second
+	defaultHTTPProfileSinkMethod      = http.MethodPost
+	defaultHTTPProfileSinkTimeout     = 30 * time.Second
+	profileFileSuffix                 = ".pb.gz"
+)
+
+var (
+	// DefaultContinuousProfiles are the profiles captured by the continuous
+	// profiler when none are specified.
+	DefaultContinuousProfiles = []string{ContinuousCPUProfileName, ContinuousHeapProfileName}
+
+	errNoProfileSink   = errors.New("no profile sink")
+	errNoProfileDir    = errors.New("no profile directory")
+	errNoURLTemplate   = errors.New("no url template")
+	errProfilerStopped = errors.New("profiler stopped")
+)
+
+// CapturedProfile is a profile captured by the continuous profiler, encoded
+// in the gzipped protobuf pprof format.
+type CapturedProfile struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	Data  []byte
+}
+
+// ProfileSink ships captured profiles to where they are stored.
+type ProfileSink interface {
+	// Write writes the captured profile.
+	Write(profile CapturedProfile) error
+}
+
+// ContinuousProfilerOptions is a set of continuous profiler options.
+type ContinuousProfilerOptions struct {
+	// Profiles are the names of the profiles to capture, either cpu or
+	// the name of any runtime/pprof profile.
+	Profiles           []string
+	Interval           time.Duration
+	CPUProfileDuration time.Duration
+	Sink               ProfileSink
+	InstrumentOptions  instrument.Options
+}
+
+type continuousProfilerMetrics struct {
+	captured tally.Counter
+	errors   tally.Counter
+}
+
+// ContinuousProfiler periodically captures profiles of the process and
+// writes them to a profile sink.
+type ContinuousProfiler struct {
+	sync.Mutex
+
+	profiles           []string
+	interval           time.Duration
+	cpuProfileDuration time.Duration
+	sink               ProfileSink
+	logger             *zap.Logger
+	metrics            map[string]continuousProfilerMetrics
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewContinuousProfiler returns a new continuous profiler.
+func NewContinuousProfiler(
+	opts ContinuousProfilerOptions,
+) (*ContinuousProfiler, error) {
+	if opts.Sink == nil {
+		return nil, errNoProfileSink
+	}
+	if opts.InstrumentOptions == nil {
+		return nil, errNoInstrumentOptions
+	}
+	if len(opts.Profiles) == 0 {
+		opts.Profiles = DefaultContinuousProfiles
+	}
+	if opts.Interval == 0 {
+		opts.Interval = defaultContinuousProfilerInterval
+	}
+	if opts.CPUProfileDuration == 0 {
+		opts.CPUProfileDuration = defaultCPUProfileDuration
+	}
+
+	scope := opts.InstrumentOptions.MetricsScope().SubScope("continuous-profiler")
+	metrics := make(map[string]continuousProfilerMetrics, len(opts.Profiles))
+	for _, name := range opts.Profiles {
+		if name != ContinuousCPUProfileName && pprof.Lookup(name) == nil {
+			return nil, fmt.Errorf("unknown profile: %s", name)
+		}
+		profileScope := scope.Tagged(map[string]string{"profile": name})
+		metrics[name] = continuousProfilerMetrics{
+			captured: profileScope.Counter("captured"),
+			errors:   profileScope.Counter("errors"),
+		}
+	}
+
+	return &ContinuousProfiler{
+		profiles:           opts.Profiles,
+		interval:           opts.Interval,
+		cpuProfileDuration: opts.CPUProfileDuration,
+		sink:               opts.Sink,
+		logger:             opts.InstrumentOptions.Logger(),
+		metrics:            metrics,
+	}, nil
+}
+
+// Start will start the continuous profiler.
+func (p *ContinuousProfiler) Start() error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closeCh != nil {
+		return errAlreadyOpen
+	}
+
+	p.closeCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	go p.run(p.closeCh, p.doneCh)
+	return nil
+}
+
+// Stop will stop the continuous profiler, interrupting any CPU profile
+// being captured, and wait for it to finish.
+func (p *ContinuousProfiler) Stop() error {
+	p.Lock()
+	if p.closeCh == nil {
+		p.Unlock()
+		return errNotOpen
+	}
+
+	close(p.closeCh)
+	doneCh := p.doneCh
+	p.closeCh = nil
+	p.doneCh = nil
+	p.Unlock()
+
+	<-doneCh
+	return nil
+}
+
+func (p *ContinuousProfiler) run(closeCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		for _, name := range p.profiles {
+			err := p.captureAndWrite(name, closeCh)
+			if err == errProfilerStopped {
+				return
+			}
+			if err != nil {
+				p.metrics[name].errors.Inc(1)
+				p.logger.Error("continuous profiler error",
+					zap.String("name", name),
+					zap.Duration("interval", p.interval),
+					zap.Error(err))
+				continue
+			}
+			p.metrics[name].captured.Inc(1)
+		}
+	}
+}
+
+func (p *ContinuousProfiler) captureAndWrite(name string, closeCh chan struct{}) error {
+	var (
+		buf   bytes.Buffer
+		start = time.Now()
+	)
+	switch name {
+	case ContinuousCPUProfileName:
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return err
+		}
+		timer := time.NewTimer(p.cpuProfileDuration)
+		select {
+		case <-closeCh:
+			timer.Stop()
+			pprof.StopCPUProfile()
+			return errProfilerStopped
+		case <-timer.C:
+		}
+		pprof.StopCPUProfile()
+	default:
+		profile := pprof.Lookup(name)
+		if profile == nil {
+			return fmt.Errorf("unknown profile: %s", name)
+		}
+		// NB: debug level zero writes the gzipped protobuf format.
+		if err := profile.WriteTo(&buf, 0); err != nil {
+			return err
+		}
+	}
+
+	return p.sink.Write(CapturedProfile{
+		Name:  name,
+		Start: start,
+		End:   time.Now(),
+		Data:  buf.Bytes(),
+	})
+}
+
+// FileProfileSinkOptions is a set of file profile sink options.
+type FileProfileSinkOptions struct {
+	// Dir is the directory profiles are written to.
+	Dir string
+	// Retention is how long profiles are kept for, profiles are kept
+	// forever if zero.
+	Retention time.Duration
+}
+
+type fileProfileSink struct {
+	dir       string
+	retention time.Duration
+}
+
+// NewFileProfileSink returns a profile sink that writes profiles to files
+// in a directory, which may be backed by object storage, and removes the
+// profiles that are older than the retention.
+func NewFileProfileSink(opts FileProfileSinkOptions) (ProfileSink, error) {
+	if opts.Dir == "" {
+		return nil, errNoProfileDir
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileProfileSink{
+		dir:       opts.Dir,
+		retention: opts.Retention,
+	}, nil
+}
+
+func (s *fileProfileSink) Write(profile CapturedProfile) error {
+	fileName := fmt.Sprintf("%s-%d%s", profile.Name, profile.Start.UnixNano(), profileFileSuffix)
+	if err := ioutil.WriteFile(filepath.Join(s.dir, fileName), profile.Data, 0644); err != nil {
+		return err
+	}
+	if s.retention <= 0 {
+		return nil
+	}
+	return s.removeExpired(profile.Name, profile.Start.Add(-s.retention))
+}
+
+func (s *fileProfileSink) removeExpired(name string, cutoff time.Time) error {
+	files, err := filepath.Glob(filepath.Join(s.dir, name+"-*"+profileFileSuffix))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		unixNanos := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), name+"-"), profileFileSuffix)
+		nanos, err := strconv.ParseInt(unixNanos, 10, 64)
+		if err != nil {
+			// Not a profile written by this sink.
+			continue
+		}
+		if !time.Unix(0, nanos).Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// HTTPProfileSinkOptions is a set of HTTP profile sink options.
+type HTTPProfileSinkOptions struct {
+	// URLTemplate is the template of the URL each profile is sent to, which
+	// is executed with ContinuousFileProfilePathParams.
+	URLTemplate string
+	// Method is the HTTP method, defaults to POST.
+	Method string
+	// Headers are added to each request, e.g. for authentication.
+	Headers map[string]string
+	// Timeout is the timeout for each request.
+	Timeout time.Duration
+}
+
+type httpProfileSink struct {
+	urlTemplate *template.Template
+	method      string
+	headers     map[string]string
+	client      *http.Client
+}
+
+// NewHTTPProfileSink returns a profile sink that sends profiles to a
+// pprof-compatible ingestion endpoint, or uploads them to object storage
+// using PUT, with retention left to the receiver.
+func NewHTTPProfileSink(opts HTTPProfileSinkOptions) (ProfileSink, error) {
+	if opts.URLTemplate == "" {
+		return nil, errNoURLTemplate
+	}
+	if opts.Method == "" {
+		opts.Method = defaultHTTPProfileSinkMethod
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultHTTPProfileSinkTimeout
+	}
+
+	tmpl, err := template.New("url").Parse(opts.URLTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpProfileSink{
+		urlTemplate: tmpl,
+		method:      opts.Method,
+		headers:     opts.Headers,
+		client:      &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+func (s *httpProfileSink) Write(profile CapturedProfile) error {
+	var url bytes.Buffer
+	err := s.urlTemplate.Execute(&url, ContinuousFileProfilePathParams{
+		ProfileName: profile.Name,
+		UnixTime:    profile.Start.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(s.method, url.String(), bytes.NewReader(profile.Data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// NB: drain the body so the connection can be reused.
+	_, _ = ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("profile upload failed: status=%d", resp.StatusCode)
+	}
+	return nil
+}
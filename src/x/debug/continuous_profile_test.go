@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package debug
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testProfileSink struct {
+	sync.Mutex
+	profiles []CapturedProfile
+}
+
+func (s *testProfileSink) Write(profile CapturedProfile) error {
+	s.Lock()
+	defer s.Unlock()
+	s.profiles = append(s.profiles, profile)
+	return nil
+}
+
+func (s *testProfileSink) names() map[string]int {
+	s.Lock()
+	defer s.Unlock()
+	names := make(map[string]int)
+	for _, p := range s.profiles {
+		names[p.Name]++
+	}
+	return names
+}
+
+func TestContinuousProfiler(t *testing.T) {
+	sink := &testProfileSink{}
+	profiler, err := NewContinuousProfiler(ContinuousProfilerOptions{
+		Interval:           10 * time.Millisecond,
+		CPUProfileDuration: 10 * time.Millisecond,
+		Sink:               sink,
+		InstrumentOptions:  instrument.NewTestOptions(t),
+	})
+	require.NoError(t, err)
+	require.NoError(t, profiler.Start())
+	require.Equal(t, errAlreadyOpen, profiler.Start())
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; {
+		names := sink.names()
+		if names[ContinuousCPUProfileName] > 1 && names[ContinuousHeapProfileName] > 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, profiler.Stop())
+	require.Equal(t, errNotOpen, profiler.Stop())
+
+	names := sink.names()
+	require.True(t, names[ContinuousCPUProfileName] > 1)
+	require.True(t, names[ContinuousHeapProfileName] > 1)
+	sink.Lock()
+	for _, p := range sink.profiles {
+		require.True(t, len(p.Data) > 0)
+	}
+	sink.Unlock()
+}
+
+func TestContinuousProfilerStopInterruptsCPUProfile(t *testing.T) {
+	sink := &testProfileSink{}
+	profiler, err := NewContinuousProfiler(ContinuousProfilerOptions{
+		Profiles:           []string{ContinuousCPUProfileName},
+		Interval:           time.Millisecond,
+		CPUProfileDuration: time.Hour,
+		Sink:               sink,
+		InstrumentOptions:  instrument.NewTestOptions(t),
+	})
+	require.NoError(t, err)
+	require.NoError(t, profiler.Start())
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, profiler.Stop())
+	require.Equal(t, 0, len(sink.names()))
+}
+
+func TestContinuousProfilerUnknownProfile(t *testing.T) {
+	_, err := NewContinuousProfiler(ContinuousProfilerOptions{
+		Profiles:          []string{"unknown"},
+		Sink:              &testProfileSink{},
+		InstrumentOptions: instrument.NewTestOptions(t),
+	})
+	require.Error(t, err)
+}
+
+func TestFileProfileSinkRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileProfileSink(FileProfileSinkOptions{
+		Dir:       dir,
+		Retention: time.Hour,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, p := range []CapturedProfile{
+		{Name: "heap", Start: now.Add(-2 * time.Hour), Data: []byte("a")},
+		{Name: "cpu", Start: now.Add(-2 * time.Hour), Data: []byte("b")},
+		{Name: "heap", Start: now.Add(-30 * time.Minute), Data: []byte("c")},
+		{Name: "heap", Start: now, Data: []byte("d")},
+	} {
+		require.NoError(t, sink.Write(p))
+	}
+
+	heapFiles, err := filepath.Glob(filepath.Join(dir, "heap-*"))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(heapFiles))
+
+	// Profiles are only expired when a profile of the same name is written.
+	cpuFiles, err := filepath.Glob(filepath.Join(dir, "cpu-*"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(cpuFiles))
+}
+
+func TestHTTPProfileSink(t *testing.T) {
+	var (
+		method, path, auth string
+		body               []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		body, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPProfileSink(HTTPProfileSinkOptions{
+		URLTemplate: server.URL + "/profiles/{{.ProfileName}}/{{.UnixTime}}",
+		Method:      http.MethodPut,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(CapturedProfile{
+		Name:  "heap",
+		Start: time.Unix(1000, 0),
+		Data:  []byte("profile"),
+	}))
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "/profiles/heap/1000", path)
+	require.Equal(t, "Bearer token", auth)
+	require.Equal(t, []byte("profile"), body)
+
+	sink, err = NewHTTPProfileSink(HTTPProfileSinkOptions{
+		URLTemplate: server.URL + "/?fail=true",
+	})
+	require.NoError(t, err)
+	require.Error(t, sink.Write(CapturedProfile{Name: "heap"}))
+}
@@ -138,6 +138,37 @@ func GetInnerInvalidParamsError(err error) error {
 	return nil
 }
 
+type invalidParamError struct {
+	containedError
+	param string
+}
+
+// NewInvalidParamError creates a new invalid params error caused by the
+// value of the given request parameter.
+func NewInvalidParamError(param string, inner error) error {
+	return NewInvalidParamsError(invalidParamError{containedError{inner}, param})
+}
+
+func (e invalidParamError) Error() string {
+	return e.inner.Error()
+}
+
+func (e invalidParamError) InnerError() error {
+	return e.inner
+}
+
+// InvalidParam returns the request parameter that caused the error if it
+// contains an invalid param error, empty otherwise.
+func InvalidParam(err error) string {
+	for err != nil {
+		if paramErr, ok := err.(invalidParamError); ok { // nolint:errorlint
+			return paramErr.param
+		}
+		err = InnerError(err)
+	}
+	return ""
+}
+
 // Is checks if the error is or contains the corresponding target error.
 // It's intended to mimic the errors.Is functionality, but also consider xerrors' MultiError / InnerError
 // wrapping functionality.
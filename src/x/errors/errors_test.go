@@ -71,6 +71,21 @@ func TestWrapf(t *testing.T) {
 	assert.True(t, IsNonRetryableError(wrappedErr))
 }
 
+func TestInvalidParam(t *testing.T) {
+	inner := errors.New("detailed error message")
+	err := NewInvalidParamError("start", inner)
+	assert.Equal(t, "detailed error message", err.Error())
+	assert.True(t, IsInvalidParams(err))
+	assert.Equal(t, "start", InvalidParam(err))
+
+	wrappedErr := Wrap(err, "context about params error")
+	assert.True(t, IsInvalidParams(wrappedErr))
+	assert.Equal(t, "start", InvalidParam(wrappedErr))
+
+	assert.Equal(t, "", InvalidParam(NewInvalidParamsError(inner)))
+	assert.Equal(t, "", InvalidParam(inner))
+}
+
 func TestMultiErrorNoError(t *testing.T) {
 	err := NewMultiError()
 	require.Nil(t, err.FinalError())
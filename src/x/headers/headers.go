@@ -183,6 +183,21 @@ const (
 	// using the fields it knows about.
 	JSONDisableDisallowUnknownFields = M3HeaderPrefix + "JSON-Disable-Disallow-Unknown-Fields"
 
+	// StickyRoutedHeader is set on queries forwarded by sticky query routing
+	// so that the receiving coordinator serves them rather than forwarding
+	// them again.
+	StickyRoutedHeader = M3HeaderPrefix + "Sticky-Routed"
+
+	// SnapshotTimeHeader pins the time a query is evaluated as of, given in
+	// seconds since the epoch or in RFC3339 format, so that queries return the
+	// same results whenever they are run.
+	SnapshotTimeHeader = M3HeaderPrefix + "Snapshot-Time"
+
+	// SnapshotCompleteHeader is the header added to the responses of queries
+	// pinned to a snapshot time indicating whether writes are still expected
+	// to arrive for data at or before the snapshot time.
+	SnapshotCompleteHeader = M3HeaderPrefix + "Snapshot-Complete"
+
 	// CustomResponseMetricsType is a header that, if set, will override the `type` tag
 	// on the request's response metrics.
 	CustomResponseMetricsType = M3HeaderPrefix + "Custom-Response-Metrics-Type"
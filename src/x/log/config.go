@@ -27,10 +27,12 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// Configuration defines configuration for logging.
+// Configuration defines configuration for logging. ModuleLevels overrides the
+// level of the loggers with the given names and the loggers named after them.
 type Configuration struct {
 	File          string                 `json:"file" yaml:"file"`
 	Level         string                 `json:"level" yaml:"level"`
+	ModuleLevels  map[string]string      `json:"moduleLevels" yaml:"moduleLevels"`
 	Fields        map[string]interface{} `json:"fields" yaml:"fields"`
 	EncoderConfig encoderConfig          `json:"encoderConfig,omitempty" yaml:"encoderConfig,omitempty"`
 }
@@ -51,10 +53,19 @@ type encoderConfig struct {
 	EncodeName     string `json:"nameEncoder" yaml:"nameEncoder,omitempty"`
 }
 
-// BuildLogger builds a new Logger based on the configuration.
+// BuildLogger builds a new Logger based on the configuration. The levels of
+// the logger can be changed at runtime with the levels returned by
+// LevelsFromLogger.
 func (cfg Configuration) BuildLogger() (*zap.Logger, error) {
+	levels, err := cfg.newLevels()
+	if err != nil {
+		return nil, err
+	}
+
 	zc := zap.Config{
-		Level:             zap.NewAtomicLevelAt(zap.InfoLevel),
+		// Entries are filtered by the levels, so that the level of the logger
+		// can be lowered at runtime.
+		Level:             zap.NewAtomicLevelAt(zap.DebugLevel),
 		Development:       false,
 		DisableCaller:     true,
 		DisableStacktrace: true,
@@ -74,15 +85,30 @@ func (cfg Configuration) BuildLogger() (*zap.Logger, error) {
 		zc.ErrorOutputPaths = append(zc.ErrorOutputPaths, cfg.File)
 	}
 
+	return zc.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newLevelsCore(core, levels)
+	}))
+}
+
+func (cfg Configuration) newLevels() (*Levels, error) {
+	global := zap.InfoLevel
 	if len(cfg.Level) != 0 {
-		var parsedLevel zap.AtomicLevel
-		if err := parsedLevel.UnmarshalText([]byte(cfg.Level)); err != nil {
-			return nil, fmt.Errorf("unable to parse log level %s: %v", cfg.Level, err)
+		level, err := parseLevel(cfg.Level)
+		if err != nil {
+			return nil, err
 		}
-		zc.Level = parsedLevel
+		global = level
 	}
 
-	return zc.Build()
+	levels := NewLevels(global)
+	for _, module := range sortedModules(cfg.ModuleLevels) {
+		level, err := parseLevel(cfg.ModuleLevels[module])
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of module %s: %v", module, err)
+		}
+		levels.SetModuleLevel(module, level)
+	}
+	return levels, nil
 }
 
 func (cfg Configuration) newEncoderConfig() zapcore.EncoderConfig {
@@ -29,6 +29,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -120,3 +121,52 @@ func TestLoggerEncoderConfiguraion(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggerModuleLevels(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "logtest")
+	require.NoError(t, err)
+
+	defer tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	cfg := Configuration{
+		Level: "warn",
+		ModuleLevels: map[string]string{
+			"storage":       "debug",
+			"storage.flush": "error",
+		},
+		File: tmpfile.Name(),
+	}
+
+	log, err := cfg.BuildLogger()
+	require.NoError(t, err)
+
+	log.Info("should not appear")
+	log.Named("storage").Debug("storage debug")
+	log.Named("storage").Named("index").With(zap.String("foo", "bar")).Info("storage index info")
+	log.Named("storage").Named("flush").Warn("should not appear")
+	log.Named("storageother").Info("should not appear")
+
+	levels, ok := LevelsFromLogger(log)
+	require.True(t, ok)
+	levels.SetLevel(zapcore.InfoLevel)
+	levels.ClearModuleLevel("storage.flush")
+	log.Info("global info")
+	log.Named("storage").Named("flush").Debug("flush debug")
+
+	b, err := ioutil.ReadAll(tmpfile)
+	require.NoError(t, err)
+
+	data := string(b)
+	require.Equal(t, 4, strings.Count(data, "\n"), data)
+	require.False(t, strings.Contains(data, "should not appear"), data)
+	for _, msg := range []string{"storage debug", "storage index info", "global info", "flush debug"} {
+		require.True(t, strings.Contains(data, `"msg":"`+msg+`"`), data)
+	}
+
+	_, err = Configuration{ModuleLevels: map[string]string{"storage": "loud"}}.BuildLogger()
+	require.Error(t, err)
+
+	_, ok = LevelsFromLogger(zap.NewNop())
+	require.False(t, ok)
+}
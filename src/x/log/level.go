@@ -0,0 +1,282 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelURL is the url of the endpoint to change log levels at runtime.
+const LevelURL = "/debug/log/level"
+
+// Levels are the levels of a logger and all loggers derived from it, which
+// can be changed at runtime. Entries of named loggers are logged at the level
+// of the longest module that is the name of the logger or one of its dot
+// separated prefixes, or at the global level if no such module has a level.
+type Levels struct {
+	sync.RWMutex
+
+	global  zapcore.Level
+	modules map[string]zapcore.Level
+	// min is the lowest level of the global and module levels, entries below
+	// it are discarded without looking up the level of their logger.
+	min zap.AtomicLevel
+}
+
+// NewLevels returns new levels with the given global level.
+func NewLevels(global zapcore.Level) *Levels {
+	return &Levels{
+		global:  global,
+		modules: make(map[string]zapcore.Level),
+		min:     zap.NewAtomicLevelAt(global),
+	}
+}
+
+// Level returns the global level.
+func (l *Levels) Level() zapcore.Level {
+	l.RLock()
+	defer l.RUnlock()
+	return l.global
+}
+
+// SetLevel sets the global level.
+func (l *Levels) SetLevel(level zapcore.Level) {
+	l.Lock()
+	l.global = level
+	l.updateMinWithLock()
+	l.Unlock()
+}
+
+// ModuleLevels returns the levels of the modules that have a level set.
+func (l *Levels) ModuleLevels() map[string]zapcore.Level {
+	l.RLock()
+	defer l.RUnlock()
+	modules := make(map[string]zapcore.Level, len(l.modules))
+	for module, level := range l.modules {
+		modules[module] = level
+	}
+	return modules
+}
+
+// SetModuleLevel sets the level of a module.
+func (l *Levels) SetModuleLevel(module string, level zapcore.Level) {
+	l.Lock()
+	l.modules[module] = level
+	l.updateMinWithLock()
+	l.Unlock()
+}
+
+// ClearModuleLevel clears the level of a module, so that its entries are
+// logged at the level of its parent module or the global level again.
+func (l *Levels) ClearModuleLevel(module string) {
+	l.Lock()
+	delete(l.modules, module)
+	l.updateMinWithLock()
+	l.Unlock()
+}
+
+// Enabled returns whether entries at the given level of the named logger
+// are logged.
+func (l *Levels) Enabled(name string, level zapcore.Level) bool {
+	if !l.min.Enabled(level) {
+		return false
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+	for module := name; len(l.modules) > 0; {
+		if moduleLevel, ok := l.modules[module]; ok {
+			return moduleLevel.Enabled(level)
+		}
+		idx := strings.LastIndexByte(module, '.')
+		if idx < 0 {
+			break
+		}
+		module = module[:idx]
+	}
+	return l.global.Enabled(level)
+}
+
+func (l *Levels) updateMinWithLock() {
+	min := l.global
+	for _, level := range l.modules {
+		if level < min {
+			min = level
+		}
+	}
+	l.min.SetLevel(min)
+}
+
+// levelsCore filters the entries written to a core by the levels of the
+// loggers they are logged by.
+type levelsCore struct {
+	zapcore.Core
+
+	levels *Levels
+}
+
+func newLevelsCore(core zapcore.Core, levels *Levels) zapcore.Core {
+	return &levelsCore{Core: core, levels: levels}
+}
+
+func (c *levelsCore) Enabled(level zapcore.Level) bool {
+	return c.levels.min.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelsCore) With(fields []zapcore.Field) zapcore.Core {
+	return newLevelsCore(c.Core.With(fields), c.levels)
+}
+
+func (c *levelsCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.Enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// LevelsFromLogger returns the levels of a logger built from a configuration,
+// and false if the levels of the logger cannot be changed at runtime.
+func LevelsFromLogger(logger *zap.Logger) (*Levels, bool) {
+	core, ok := logger.Core().(*levelsCore)
+	if !ok {
+		return nil, false
+	}
+	return core.levels, true
+}
+
+// RegisterLevelHandler registers the endpoint to change log levels at runtime
+// on the ServeMux provided, if the levels of the logger can be changed.
+func RegisterLevelHandler(mux *http.ServeMux, logger *zap.Logger) {
+	if levels, ok := LevelsFromLogger(logger); ok {
+		mux.Handle(LevelURL, NewLevelHandler(levels))
+	}
+}
+
+type levelsJSON struct {
+	Level        string            `json:"level"`
+	ModuleLevels map[string]string `json:"moduleLevels"`
+}
+
+type setLevelRequest struct {
+	// Module is the module to set the level of, the global level is set if
+	// not specified.
+	Module string `json:"module"`
+	// Level is the level to set, the level of the module is cleared if not
+	// specified.
+	Level string `json:"level"`
+}
+
+type levelHandler struct {
+	levels *Levels
+}
+
+// NewLevelHandler returns a handler that returns the levels on GET requests,
+// and sets the global level or the level of a module on PUT and POST requests
+// with a JSON body such as {"level":"debug"} or {"module":"m","level":"debug"}.
+// The level of a module is cleared when set without a level.
+func NewLevelHandler(levels *Levels) http.Handler {
+	return &levelHandler{levels: levels}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req setLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeLevelError(w, http.StatusBadRequest, fmt.Errorf("unable to parse request: %v", err))
+			return
+		}
+		if err := h.set(req); err != nil {
+			writeLevelError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		writeLevelError(w, http.StatusMethodNotAllowed,
+			fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	resp := levelsJSON{
+		Level:        h.levels.Level().String(),
+		ModuleLevels: make(map[string]string),
+	}
+	for module, level := range h.levels.ModuleLevels() {
+		resp.ModuleLevels[module] = level.String()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *levelHandler) set(req setLevelRequest) error {
+	if req.Module != "" && req.Level == "" {
+		h.levels.ClearModuleLevel(req.Module)
+		return nil
+	}
+
+	level, err := parseLevel(req.Level)
+	if err != nil {
+		return err
+	}
+	if req.Module == "" {
+		h.levels.SetLevel(level)
+	} else {
+		h.levels.SetModuleLevel(req.Module, level)
+	}
+	return nil
+}
+
+func writeLevelError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{
+		Error: err.Error(),
+	})
+}
+
+func parseLevel(value string) (zapcore.Level, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("unable to parse log level %s: %v", value, err)
+	}
+	return level, nil
+}
+
+// sortedModules returns the modules of module levels in order, so that
+// configuration errors are reported deterministically.
+func sortedModules(moduleLevels map[string]string) []string {
+	modules := make([]string, 0, len(moduleLevels))
+	for module := range moduleLevels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
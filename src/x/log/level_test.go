@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevelsEnabled(t *testing.T) {
+	levels := NewLevels(zapcore.InfoLevel)
+	require.False(t, levels.Enabled("", zapcore.DebugLevel))
+	require.True(t, levels.Enabled("", zapcore.InfoLevel))
+
+	levels.SetModuleLevel("a", zapcore.DebugLevel)
+	levels.SetModuleLevel("a.b", zapcore.ErrorLevel)
+	require.True(t, levels.Enabled("a", zapcore.DebugLevel))
+	require.True(t, levels.Enabled("a.c.d", zapcore.DebugLevel))
+	require.False(t, levels.Enabled("a.b", zapcore.WarnLevel))
+	require.False(t, levels.Enabled("a.b.c", zapcore.WarnLevel))
+	require.False(t, levels.Enabled("ab", zapcore.DebugLevel))
+	require.False(t, levels.Enabled("", zapcore.DebugLevel))
+
+	levels.ClearModuleLevel("a")
+	require.False(t, levels.Enabled("a", zapcore.DebugLevel))
+	require.Equal(t, map[string]zapcore.Level{"a.b": zapcore.ErrorLevel}, levels.ModuleLevels())
+}
+
+func TestLevelHandler(t *testing.T) {
+	levels := NewLevels(zapcore.InfoLevel)
+	handler := NewLevelHandler(levels)
+
+	serve := func(method, body string) (int, levelsJSON) {
+		req := httptest.NewRequest(method, LevelURL, strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		var resp levelsJSON
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		}
+		return recorder.Code, resp
+	}
+
+	code, resp := serve(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, levelsJSON{Level: "info", ModuleLevels: map[string]string{}}, resp)
+
+	code, resp = serve(http.MethodPut, `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "debug", resp.Level)
+	require.Equal(t, zapcore.DebugLevel, levels.Level())
+
+	code, resp = serve(http.MethodPost, `{"module":"storage","level":"error"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"storage": "error"}, resp.ModuleLevels)
+
+	code, resp = serve(http.MethodPut, `{"module":"storage"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, resp.ModuleLevels)
+
+	code, _ = serve(http.MethodPut, `{"level":"loud"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = serve(http.MethodDelete, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
@@ -88,6 +88,9 @@ func NanosToDurationMap(input map[string]interface{}) (map[string]interface{}, e
 				// Assume given number is in nanos
 				dur := time.Duration(int64(vv)) * time.Nanosecond
 				dictTranslated[newKey] = dur.String()
+			case nil:
+				// Optional durations that are not set.
+				dictTranslated[newKey] = nil
 			default:
 				// Has Nano suffix, but is not string or number.
 				return nil, errDurationType
@@ -72,6 +72,7 @@ func TestNanoToDurationBytes(t *testing.T) {
 		`{"field":"value"}`:                                         ret{map[string]interface{}{"field": "value"}, false},
 		`{"fieldNanos":1000000000}`:                                 ret{map[string]interface{}{"fieldDuration": "1s"}, false},
 		`{"fieldNanos":0}`:                                          ret{map[string]interface{}{"fieldDuration": "0s"}, false},
+		`{"fieldNanos":null}`:                                       ret{map[string]interface{}{"fieldDuration": nil}, false},
 		`{"field":"value","fieldNanos":1000000000}`:                 ret{map[string]interface{}{"field": "value", "fieldDuration": "1s"}, false},
 		`{"realNanos":50,"nanoNanos":100,"normalDuration":"200ns"}`: ret{map[string]interface{}{"nanoDuration": "100ns", "normalDuration": "200ns", "realDuration": "50ns"}, false},
 		`{"field":"value","moreFields":{"innerNanos":2000000,"innerField":"innerValue"}}`: ret{map[string]interface{}{"field": "value", "moreFields": map[string]interface{}{"innerField": "innerValue", "innerDuration": "2ms"}}, false},
@@ -78,10 +78,54 @@ func (e errorWithCode) Code() int {
 	return e.status
 }
 
+// ErrorCode is a machine readable code classifying an HTTP error, so that
+// clients can branch on the code rather than parsing the error message.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidParams is returned when the request is invalid.
+	ErrorCodeInvalidParams ErrorCode = "invalid_params"
+	// ErrorCodeUnauthorized is returned when the request is not authorized.
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	// ErrorCodeNotFound is returned when the requested resource does not exist.
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeConflict is returned when the request conflicts with the
+	// current state of the resource, e.g. the resource already exists.
+	ErrorCodeConflict ErrorCode = "conflict"
+	// ErrorCodeResourceExhausted is returned when a limit was exceeded.
+	ErrorCodeResourceExhausted ErrorCode = "resource_exhausted"
+	// ErrorCodeCanceled is returned when the request was canceled.
+	ErrorCodeCanceled ErrorCode = "canceled"
+	// ErrorCodeTimeout is returned when the request timed out.
+	ErrorCodeTimeout ErrorCode = "timeout"
+	// ErrorCodeUnavailable is returned when a dependency is unavailable.
+	ErrorCodeUnavailable ErrorCode = "unavailable"
+	// ErrorCodeInternal is returned for any other error.
+	ErrorCodeInternal ErrorCode = "internal"
+)
+
 // ErrorResponse is a generic response for an HTTP error.
 type ErrorResponse struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
+	// Code classifies the error.
+	Code ErrorCode `json:"code,omitempty"`
+	// Retryable is true if the request may succeed when retried as is.
+	Retryable bool `json:"retryable"`
+	// Param is the request parameter that caused the error, if known.
+	Param string `json:"param,omitempty"`
+}
+
+// NewErrorResponse returns the error response for an error.
+func NewErrorResponse(err error) ErrorResponse {
+	code := errorCode(getStatusCode(err))
+	return ErrorResponse{
+		Status:    "error",
+		Error:     err.Error(),
+		Code:      code,
+		Retryable: isRetryable(err, code),
+		Param:     xerrors.InvalidParam(err),
+	}
 }
 
 type options struct {
@@ -113,7 +157,7 @@ func WriteError(w http.ResponseWriter, err error, opts ...WriteErrorOption) {
 	if o.response == nil {
 		w.Header().Set(HeaderContentType, ContentTypeJSON)
 		w.WriteHeader(statusCode)
-		json.NewEncoder(w).Encode(ErrorResponse{Status: "error", Error: err.Error()}) //nolint:errcheck
+		json.NewEncoder(w).Encode(NewErrorResponse(err)) //nolint:errcheck
 	} else {
 		w.WriteHeader(statusCode)
 		w.Write(o.response)
@@ -151,6 +195,46 @@ func getStatusCode(err error) int {
 	return http.StatusInternalServerError
 }
 
+func errorCode(statusCode int) ErrorCode {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrorCodeInvalidParams
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorCodeUnauthorized
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusTooManyRequests:
+		return ErrorCodeResourceExhausted
+	case 499:
+		return ErrorCodeCanceled
+	case http.StatusGatewayTimeout:
+		return ErrorCodeTimeout
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return ErrorCodeUnavailable
+	}
+	if statusCode >= 400 && statusCode < 500 {
+		return ErrorCodeInvalidParams
+	}
+	return ErrorCodeInternal
+}
+
+// isRetryable returns whether the request may succeed when retried as is,
+// client errors other than exceeded limits are never retryable.
+func isRetryable(err error, code ErrorCode) bool {
+	if xerrors.IsNonRetryableError(err) {
+		return false
+	}
+	switch code {
+	case ErrorCodeResourceExhausted, ErrorCodeTimeout, ErrorCodeUnavailable:
+		return true
+	case ErrorCodeInternal:
+		return xerrors.IsRetryableError(err)
+	}
+	return false
+}
+
 // IsClientError returns true if this error would result in 4xx status code.
 func IsClientError(err error) bool {
 	code := getStatusCode(err)
@@ -22,8 +22,10 @@ package xhttp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -106,13 +108,13 @@ func TestErrorRewrite(t *testing.T) {
 		{
 			name:           "error that should not be rewritten",
 			err:            errors.New("random error"),
-			expectedBody:   `{"status":"error","error":"random error"}`,
+			expectedBody:   `{"status":"error","error":"random error","code":"internal","retryable":false}`,
 			expectedStatus: 500,
 		},
 		{
 			name:           "error that should be rewritten",
 			err:            xerrors.NewInvalidParamsError(errors.New("to be rewritten")),
-			expectedBody:   `{"status":"error","error":"rewritten error"}`,
+			expectedBody:   `{"status":"error","error":"rewritten error","code":"internal","retryable":false}`,
 			expectedStatus: 500,
 		},
 	}
@@ -124,7 +126,8 @@ func TestErrorRewrite(t *testing.T) {
 		return err
 	}
 
-	SetErrorRewriteFn(invalidParamsRewriteFn)
+	prevFn := SetErrorRewriteFn(invalidParamsRewriteFn)
+	defer SetErrorRewriteFn(prevFn)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -136,6 +139,85 @@ func TestErrorRewrite(t *testing.T) {
 	}
 }
 
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorResponse
+	}{
+		{
+			name:     "generic error",
+			err:      errors.New("generic error"),
+			expected: ErrorResponse{Code: ErrorCodeInternal},
+		},
+		{
+			name:     "retryable error",
+			err:      xerrors.NewRetryableError(errors.New("retryable error")),
+			expected: ErrorResponse{Code: ErrorCodeInternal, Retryable: true},
+		},
+		{
+			name:     "invalid params",
+			err:      xerrors.NewInvalidParamsError(errors.New("bad param")),
+			expected: ErrorResponse{Code: ErrorCodeInvalidParams},
+		},
+		{
+			name:     "invalid param",
+			err:      xerrors.NewInvalidParamError("start", errors.New("bad start")),
+			expected: ErrorResponse{Code: ErrorCodeInvalidParams, Param: "start"},
+		},
+		{
+			name:     "retryable invalid params",
+			err:      xerrors.NewRetryableError(xerrors.NewInvalidParamsError(errors.New("bad param"))),
+			expected: ErrorResponse{Code: ErrorCodeInvalidParams},
+		},
+		{
+			name:     "not found",
+			err:      NewError(errors.New("not found"), http.StatusNotFound),
+			expected: ErrorResponse{Code: ErrorCodeNotFound},
+		},
+		{
+			name:     "conflict",
+			err:      NewError(errors.New("already exists"), http.StatusConflict),
+			expected: ErrorResponse{Code: ErrorCodeConflict},
+		},
+		{
+			name:     "too many requests",
+			err:      NewError(errors.New("limit exceeded"), http.StatusTooManyRequests),
+			expected: ErrorResponse{Code: ErrorCodeResourceExhausted, Retryable: true},
+		},
+		{
+			name:     "canceled",
+			err:      context.Canceled,
+			expected: ErrorResponse{Code: ErrorCodeCanceled},
+		},
+		{
+			name:     "deadline exceeded",
+			err:      context.DeadlineExceeded,
+			expected: ErrorResponse{Code: ErrorCodeTimeout, Retryable: true},
+		},
+		{
+			name: "non retryable unavailable",
+			err: NewError(xerrors.NewNonRetryableError(errors.New("unavailable")),
+				http.StatusServiceUnavailable),
+			expected: ErrorResponse{Code: ErrorCodeUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			WriteError(recorder, tt.err)
+
+			var actual ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+			expected := tt.expected
+			expected.Status = "error"
+			expected.Error = tt.err.Error()
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
 func TestIsClientError(t *testing.T) {
 	tests := []struct {
 		err      error
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentracing
+
+import (
+	"net/url"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// InjectBytes serializes a span context into bytes so that it can be
+// propagated across process boundaries inside a payload, such as a
+// message header, rather than in transport headers. The span context is
+// injected using the text map format so any tracer may be used.
+func InjectBytes(
+	tracer opentracing.Tracer,
+	spanCtx opentracing.SpanContext,
+) ([]byte, error) {
+	carrier := opentracing.TextMapCarrier{}
+	if err := tracer.Inject(spanCtx, opentracing.TextMap, carrier); err != nil {
+		return nil, err
+	}
+
+	values := make(url.Values, len(carrier))
+	for k, v := range carrier {
+		values.Set(k, v)
+	}
+	return []byte(values.Encode()), nil
+}
+
+// ExtractBytes deserializes a span context previously serialized with
+// InjectBytes, returning opentracing.ErrSpanContextNotFound if the bytes
+// are empty.
+func ExtractBytes(
+	tracer opentracing.Tracer,
+	data []byte,
+) (opentracing.SpanContext, error) {
+	if len(data) == 0 {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	carrier := make(opentracing.TextMapCarrier, len(values))
+	for k := range values {
+		carrier[k] = values.Get(k)
+	}
+	return tracer.Extract(opentracing.TextMap, carrier)
+}
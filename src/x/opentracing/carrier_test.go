@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package opentracing
+
+import (
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectExtractBytes(t *testing.T) {
+	mtr := mocktracer.New()
+	sp := mtr.StartSpan("root")
+	sp.SetBaggageItem("key", "a value&with=chars")
+	defer sp.Finish()
+
+	data, err := InjectBytes(mtr, sp.Context())
+	require.NoError(t, err)
+	require.NotEmpty(t, data)
+
+	spanCtx, err := ExtractBytes(mtr, data)
+	require.NoError(t, err)
+
+	expected := sp.Context().(mocktracer.MockSpanContext)
+	actual := spanCtx.(mocktracer.MockSpanContext)
+	require.Equal(t, expected.TraceID, actual.TraceID)
+	require.Equal(t, expected.SpanID, actual.SpanID)
+	require.Equal(t, "a value&with=chars", actual.Baggage["key"])
+}
+
+func TestExtractBytesEmpty(t *testing.T) {
+	_, err := ExtractBytes(mocktracer.New(), nil)
+	require.Equal(t, opentracing.ErrSpanContextNotFound, err)
+}
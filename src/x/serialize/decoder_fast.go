@@ -55,6 +55,11 @@ func TagValueFromEncodedTagsFast(
 			return nil, false, ErrEmptyTagNameLiteral
 		}
 		encodedTags = encodedTags[2:]
+		if len(encodedTags) < numBytesName {
+			return nil, false, fmt.Errorf(
+				"tag name too short: index=%d, size=%d, need=%d",
+				i, len(encodedTags), numBytesName)
+		}
 
 		bytesName := encodedTags[:numBytesName]
 		encodedTags = encodedTags[numBytesName:]
@@ -65,6 +70,11 @@ func TagValueFromEncodedTagsFast(
 
 		numBytesValue := int(ByteOrder.Uint16(encodedTags[:2]))
 		encodedTags = encodedTags[2:]
+		if len(encodedTags) < numBytesValue {
+			return nil, false, fmt.Errorf(
+				"tag value too short: index=%d, size=%d, need=%d",
+				i, len(encodedTags), numBytesValue)
+		}
 
 		bytesValue := encodedTags[:numBytesValue]
 		encodedTags = encodedTags[numBytesValue:]
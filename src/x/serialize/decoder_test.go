@@ -193,6 +193,22 @@ func TestDecodeMissingTags(t *testing.T) {
 	require.Error(t, d.Err())
 }
 
+func TestDecodeTruncatedLiterals(t *testing.T) {
+	var b []byte
+	b = append(b, headerMagicBytes...)
+	b = append(b, encodeUInt16(1, make([]byte, 2))...) /* num tags */
+	b = append(b, encodeUInt16(3, make([]byte, 2))...) /* len abc */
+	b = append(b, []byte("abc")...)
+	b = append(b, encodeUInt16(4, make([]byte, 2))...) /* len defg */
+	b = append(b, []byte("de")...)
+
+	_, _, err := TagValueFromEncodedTagsFast(b, []byte("abc"))
+	require.Error(t, err)
+
+	_, _, err = TagValueFromEncodedTagsFast(b[:7], []byte("abc"))
+	require.Error(t, err)
+}
+
 func TestDecodeOwnershipFinalize(t *testing.T) {
 	var b []byte
 	b = append(b, headerMagicBytes...)
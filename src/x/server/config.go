@@ -21,10 +21,12 @@
 package server
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/retry"
+	xtls "github.com/m3db/m3/src/x/tls"
 )
 
 // Configuration configs a server.
@@ -40,10 +42,14 @@ type Configuration struct {
 
 	// KeepAlive period.
 	KeepAlivePeriod *time.Duration `yaml:"keepAlivePeriod"`
+
+	// TLS configures TLS for connections, connections are not encrypted if
+	// not set.
+	TLS *xtls.ServerConfiguration `yaml:"tls"`
 }
 
 // NewOptions creates server options.
-func (c Configuration) NewOptions(iOpts instrument.Options) Options {
+func (c Configuration) NewOptions(iOpts instrument.Options) (Options, error) {
 	opts := NewOptions().
 		SetRetryOptions(c.Retry.NewOptions(iOpts.MetricsScope())).
 		SetInstrumentOptions(iOpts)
@@ -53,10 +59,21 @@ func (c Configuration) NewOptions(iOpts instrument.Options) Options {
 	if c.KeepAlivePeriod != nil {
 		opts = opts.SetTCPConnectionKeepAlivePeriod(*c.KeepAlivePeriod)
 	}
-	return opts
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS config: %w", err)
+		}
+		opts = opts.SetTLSConfig(tlsConfig)
+	}
+	return opts, nil
 }
 
 // NewServer creates a new server.
-func (c Configuration) NewServer(handler Handler, iOpts instrument.Options) Server {
-	return NewServer(c.ListenAddress, handler, c.NewOptions(iOpts))
+func (c Configuration) NewServer(handler Handler, iOpts instrument.Options) (Server, error) {
+	opts, err := c.NewOptions(iOpts)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(c.ListenAddress, handler, opts), nil
 }
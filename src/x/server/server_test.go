@@ -30,6 +30,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/x/retry"
+	xtls "github.com/m3db/m3/src/x/tls"
+	"github.com/m3db/m3/src/x/tls/tlstest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +119,55 @@ func TestServe(t *testing.T) {
 	s.Close()
 }
 
+func TestServerTLS(t *testing.T) {
+	var (
+		ca          = tlstest.NewCA(t, t.TempDir())
+		serverFiles = ca.IssueServer("server")
+		clientFiles = ca.IssueClient("client")
+	)
+	serverTLS, err := xtls.ServerConfiguration{
+		CertFile:          serverFiles.CertFile,
+		KeyFile:           serverFiles.KeyFile,
+		ClientCAFile:      ca.CertFile,
+		RequireClientCert: true,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+
+	h := newMockHandler()
+	s := NewServer(testListenAddress, h, NewOptions().SetTLSConfig(serverTLS)).(*server)
+	require.NoError(t, s.ListenAndServe())
+	defer s.Close()
+	listenAddr := s.listener.Addr().String()
+
+	clientTLS, err := xtls.ClientConfiguration{
+		CertFile: clientFiles.CertFile,
+		KeyFile:  clientFiles.KeyFile,
+		CAFile:   ca.CertFile,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	conn, err := net.Dial("tcp", listenAddr)
+	require.NoError(t, err)
+	tlsConn, err := xtls.Client(conn, listenAddr, clientTLS, time.Second)
+	require.NoError(t, err)
+	_, err = tlsConn.Write([]byte("msg"))
+	require.NoError(t, err)
+
+	// Clients without a certificate are rejected, depending on the TLS version
+	// either during the handshake or on the first read of the server.
+	noCertTLS, err := xtls.ClientConfiguration{CAFile: ca.CertFile}.NewTLSConfig()
+	require.NoError(t, err)
+	conn, err = net.Dial("tcp", listenAddr)
+	require.NoError(t, err)
+	if tlsConn, err = xtls.Client(conn, listenAddr, noCertTLS, time.Second); err == nil {
+		tlsConn.Write([]byte("rejected")) // nolint: errcheck
+	}
+
+	for h.called() < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, []string{"", "msg"}, h.res())
+}
+
 type mockHandler struct {
 	sync.Mutex
 
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package tls provides configuration for TLS servers and clients and helpers
+// to identify the peers of TLS connections.
+package tls
+
+import (
+	stdtls "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	errCertAndKeyRequired  = errors.New("both certFile and keyFile are required")
+	errClientCAFileMissing = errors.New("clientCAFile is required to require client certificates")
+)
+
+// ServerConfiguration is the configuration of a TLS server.
+type ServerConfiguration struct {
+	// CertFile is the path to the server certificate.
+	CertFile string `yaml:"certFile"`
+
+	// KeyFile is the path to the server private key.
+	KeyFile string `yaml:"keyFile"`
+
+	// ClientCAFile is the path to the CA certificates used to verify client
+	// certificates, if set client certificates are verified when presented.
+	ClientCAFile string `yaml:"clientCAFile"`
+
+	// RequireClientCert rejects connections that do not present a client
+	// certificate signed by one of the client CAs.
+	RequireClientCert bool `yaml:"requireClientCert"`
+}
+
+// NewTLSConfig returns the TLS config for the server.
+func (c ServerConfiguration) NewTLSConfig() (*stdtls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errCertAndKeyRequired
+	}
+	cert, err := stdtls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load server certificate: %w", err)
+	}
+
+	cfg := &stdtls.Config{
+		Certificates: []stdtls.Certificate{cert},
+		MinVersion:   stdtls.VersionTLS12,
+		ClientAuth:   stdtls.NoClientCert,
+	}
+	switch {
+	case c.ClientCAFile != "":
+		pool, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = stdtls.VerifyClientCertIfGiven
+		if c.RequireClientCert {
+			cfg.ClientAuth = stdtls.RequireAndVerifyClientCert
+		}
+	case c.RequireClientCert:
+		return nil, errClientCAFileMissing
+	}
+	return cfg, nil
+}
+
+// ClientConfiguration is the configuration of a TLS client.
+type ClientConfiguration struct {
+	// CertFile is the path to the client certificate presented to servers,
+	// the certificate identifies the client to servers that verify it.
+	CertFile string `yaml:"certFile"`
+
+	// KeyFile is the path to the client private key.
+	KeyFile string `yaml:"keyFile"`
+
+	// CAFile is the path to the CA certificates used to verify server
+	// certificates, the system CAs are used if not set.
+	CAFile string `yaml:"caFile"`
+
+	// ServerName overrides the name used to verify server certificates,
+	// which otherwise is the host dialed.
+	ServerName string `yaml:"serverName"`
+
+	// InsecureSkipVerify disables verifying server certificates.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+}
+
+// NewTLSConfig returns the TLS config for the client.
+func (c ClientConfiguration) NewTLSConfig() (*stdtls.Config, error) {
+	cfg := &stdtls.Config{
+		MinVersion:         stdtls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errCertAndKeyRequired
+		}
+		cert, err := stdtls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		cfg.Certificates = []stdtls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tls
+
+import (
+	stdtls "crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/x/tls/tlstest"
+)
+
+func handshake(
+	t *testing.T,
+	serverCfg *stdtls.Config,
+	clientCfg *stdtls.Config,
+) (stdtls.ConnectionState, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := stdtls.Server(serverConn, serverCfg)
+	client := stdtls.Client(clientConn, clientCfg)
+	clientErrCh := make(chan error, 1)
+	go func() {
+		err := client.Handshake()
+		if err == nil {
+			// With TLS 1.3 the client completes its handshake before the
+			// server verifies its certificate, so keep reading to drain
+			// any alert the server sends until the server closes the pipe.
+			_, _ = io.Copy(ioutil.Discard, client)
+		}
+		clientErrCh <- err
+	}()
+
+	serverErr := server.Handshake()
+	state := server.ConnectionState()
+	// Unblock the client waiting for the server to respond.
+	serverConn.Close()
+	clientErr := <-clientErrCh
+	if serverErr != nil {
+		return stdtls.ConnectionState{}, serverErr
+	}
+	return state, clientErr
+}
+
+func TestServerAndClientConfigurations(t *testing.T) {
+	var (
+		ca         = tlstest.NewCA(t, t.TempDir())
+		serverCert = ca.IssueServer("server")
+		clientCert = ca.IssueClient("team-a")
+	)
+
+	serverCfg, err := ServerConfiguration{
+		CertFile:          serverCert.CertFile,
+		KeyFile:           serverCert.KeyFile,
+		ClientCAFile:      ca.CertFile,
+		RequireClientCert: true,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, stdtls.RequireAndVerifyClientCert, serverCfg.ClientAuth)
+
+	clientCfg, err := ClientConfiguration{
+		CertFile:   clientCert.CertFile,
+		KeyFile:    clientCert.KeyFile,
+		CAFile:     ca.CertFile,
+		ServerName: "localhost",
+	}.NewTLSConfig()
+	require.NoError(t, err)
+
+	state, err := handshake(t, serverCfg, clientCfg)
+	require.NoError(t, err)
+	identity, ok := PeerIdentity(state)
+	require.True(t, ok)
+	assert.Equal(t, "team-a", identity)
+
+	// Clients without a certificate are rejected when one is required.
+	anonymousCfg, err := ClientConfiguration{
+		CAFile:     ca.CertFile,
+		ServerName: "localhost",
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	_, err = handshake(t, serverCfg, anonymousCfg)
+	require.Error(t, err)
+
+	// And have no identity when a certificate is optional.
+	serverCfg, err = ServerConfiguration{
+		CertFile:     serverCert.CertFile,
+		KeyFile:      serverCert.KeyFile,
+		ClientCAFile: ca.CertFile,
+	}.NewTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, stdtls.VerifyClientCertIfGiven, serverCfg.ClientAuth)
+	state, err = handshake(t, serverCfg, anonymousCfg)
+	require.NoError(t, err)
+	_, ok = PeerIdentity(state)
+	assert.False(t, ok)
+}
+
+func TestServerConfigurationValidation(t *testing.T) {
+	_, err := ServerConfiguration{}.NewTLSConfig()
+	require.Error(t, err)
+
+	serverCert := tlstest.NewCA(t, t.TempDir()).IssueServer("server")
+	_, err = ServerConfiguration{
+		CertFile:          serverCert.CertFile,
+		KeyFile:           serverCert.KeyFile,
+		RequireClientCert: true,
+	}.NewTLSConfig()
+	require.Error(t, err)
+
+	_, err = ClientConfiguration{CertFile: serverCert.CertFile}.NewTLSConfig()
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tls
+
+import (
+	stdtls "crypto/tls"
+	"crypto/x509"
+)
+
+// PeerIdentity returns the identity of the peer of a TLS connection from its
+// verified certificate: the subject common name, or if empty the first URI or
+// DNS subject alternative name. It returns false if the peer did not present
+// a verified certificate or the certificate has no usable name.
+func PeerIdentity(state stdtls.ConnectionState) (string, bool) {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return CertificateIdentity(state.VerifiedChains[0][0])
+}
+
+// CertificateIdentity returns the identity of a certificate, see PeerIdentity.
+func CertificateIdentity(cert *x509.Certificate) (string, bool) {
+	if cert == nil {
+		return "", false
+	}
+	if cn := cert.Subject.CommonName; cn != "" {
+		return cn, true
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String(), true
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0], true
+	}
+	return "", false
+}